@@ -328,7 +328,7 @@ func BuildBackupRangeAndSchema(
 			}
 			tableInfo.AutoIncID = globalAutoID
 
-			if tableInfo.PKIsHandle && tableInfo.ContainsAutoRandomBits() {
+			if tableInfo.ContainsAutoRandomBits() {
 				// this table has auto_random id, we need backup and rebase in restoration
 				var globalAutoRandID int64
 				globalAutoRandID, err = randAlloc.NextGlobalAutoID()
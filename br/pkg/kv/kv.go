@@ -316,15 +316,12 @@ func (kvcodec *tableKVEncoder) AddRecord(
 		record = make([]types.Datum, 0, len(cols)+1)
 	}
 
-	isAutoRandom := false
-	if kvcodec.tbl.Meta().PKIsHandle && kvcodec.tbl.Meta().ContainsAutoRandomBits() {
-		isAutoRandom = true
-	}
+	autoRandomCol := kvcodec.tbl.Meta().GetAutoRandomColInfo()
 
 	for i, col := range cols {
 		j := columnPermutation[i]
 		isAutoIncCol := mysql.HasAutoIncrementFlag(col.Flag)
-		isPk := mysql.HasPriKeyFlag(col.Flag)
+		isAutoRandomCol := autoRandomCol != nil && col.Offset == autoRandomCol.Offset
 		switch {
 		case j >= 0 && j < len(row):
 			value, err = table.CastValue(kvcodec.se, row[j], col.ToInfo(), false, false)
@@ -343,7 +340,7 @@ func (kvcodec *tableKVEncoder) AddRecord(
 
 		record = append(record, value)
 
-		if isAutoRandom && isPk {
+		if isAutoRandomCol {
 			typeBitsLength := uint64(mysql.DefaultLengthOfMysqlTypes[col.Tp] * 8)
 			incrementalBits := typeBitsLength - kvcodec.tbl.Meta().AutoRandomBits
 			hasSignBit := !mysql.HasUnsignedFlag(col.Flag)
@@ -75,9 +75,9 @@ func NewTableKVEncoder(tbl table.Table, options *SessionOptions) (Encoder, error
 	tables.SetAddRecordCtx(se, recordCtx)
 
 	autoIDFn := func(id int64) int64 { return id }
-	if meta.PKIsHandle && meta.ContainsAutoRandomBits() {
+	if autoRandomCol := meta.GetAutoRandomColInfo(); autoRandomCol != nil {
 		for _, col := range cols {
-			if mysql.HasPriKeyFlag(col.Flag) {
+			if col.Offset == autoRandomCol.Offset {
 				incrementalBits := autoRandomIncrementBits(col, int(meta.AutoRandomBits))
 				autoRandomBits := rand.New(rand.NewSource(options.AutoRandomSeed)).Int63n(1<<meta.AutoRandomBits) << incrementalBits
 				autoIDFn = func(id int64) int64 {
@@ -356,11 +356,11 @@ func (kvcodec *tableKVEncoder) Encode(
 	}
 
 	meta := kvcodec.tbl.Meta()
-	isAutoRandom := meta.PKIsHandle && meta.ContainsAutoRandomBits()
+	autoRandomCol := meta.GetAutoRandomColInfo()
 	for i, col := range cols {
 		j := columnPermutation[i]
 		isAutoIncCol := mysql.HasAutoIncrementFlag(col.Flag)
-		isPk := mysql.HasPriKeyFlag(col.Flag)
+		isAutoRandomCol := autoRandomCol != nil && col.Offset == autoRandomCol.Offset
 		switch {
 		case j >= 0 && j < len(row):
 			value, err = table.CastValue(kvcodec.se, row[j], col.ToInfo(), false, false)
@@ -370,7 +370,7 @@ func (kvcodec *tableKVEncoder) Encode(
 		case isAutoIncCol:
 			// we still need a conversion, e.g. to catch overflow with a TINYINT column.
 			value, err = table.CastValue(kvcodec.se, types.NewIntDatum(rowID), col.ToInfo(), false, false)
-		case isAutoRandom && isPk:
+		case isAutoRandomCol:
 			var val types.Datum
 			realRowID := kvcodec.autoIDFn(rowID)
 			if mysql.HasUnsignedFlag(col.Flag) {
@@ -392,7 +392,7 @@ func (kvcodec *tableKVEncoder) Encode(
 
 		record = append(record, value)
 
-		if isAutoRandom && isPk {
+		if isAutoRandomCol {
 			incrementalBits := autoRandomIncrementBits(col, int(meta.AutoRandomBits))
 			alloc := kvcodec.tbl.Allocators(kvcodec.se).Get(autoid.AutoRandomType)
 			if err := alloc.Rebase(context.Background(), value.GetInt64()&((1<<incrementalBits)-1), false); err != nil {
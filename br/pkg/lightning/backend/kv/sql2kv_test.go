@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/mock"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -393,6 +394,46 @@ func (s *kvSuite) TestDefaultAutoRandoms(c *C) {
 	c.Assert(tbl.Allocators(encoder.(*tableKVEncoder).se).Get(autoid.AutoRandomType).Base(), Equals, int64(71))
 }
 
+func (s *kvSuite) TestDefaultAutoRandomsOfCommonHandle(c *C) {
+	tblInfo := mockTableInfo(c, "create table t (id bigint NOT NULL auto_random, a varchar(100), primary key (id, a) clustered);")
+	tblInfo.AutoRandomBits = 5
+	c.Assert(tblInfo.IsCommonHandle, IsTrue)
+	tbl, err := tables.TableFromMeta(NewPanickingAllocators(0), tblInfo)
+	c.Assert(err, IsNil)
+	encoder, err := NewTableKVEncoder(tbl, &SessionOptions{
+		SQLMode:        mysql.ModeStrictAllTables,
+		Timestamp:      1234567893,
+		SysVars:        map[string]string{"tidb_row_format_version": "2"},
+		AutoRandomSeed: 456,
+	})
+	c.Assert(err, IsNil)
+	logger := log.Logger{Logger: zap.NewNop()}
+	decodeID := func(pairs Row) int64 {
+		kvs := pairs.(*KvPairs)
+		c.Assert(kvs.pairs, HasLen, 1)
+		_, h, err := tablecodec.DecodeRecordKey(kvs.pairs[0].Key)
+		c.Assert(err, IsNil)
+		c.Assert(h.IsInt(), IsFalse)
+		_, d, err := codec.DecodeOne(h.EncodedCol(0))
+		c.Assert(err, IsNil)
+		return d.GetInt64()
+	}
+
+	// The first column of the primary key is generated from the row ID with the random shard bits.
+	pairs, err := encoder.Encode(logger, []types.Datum{types.NewStringDatum("a")}, 70, []int{-1, 0}, "1.csv", 1234)
+	c.Assert(err, IsNil)
+	id := decodeID(pairs)
+	c.Assert(id&(1<<58-1), Equals, int64(70))
+	c.Assert(id>>58, Not(Equals), int64(0))
+	c.Assert(tbl.Allocators(encoder.(*tableKVEncoder).se).Get(autoid.AutoRandomType).Base(), Equals, int64(70))
+
+	// The explicit value rebases the allocator by its incremental bits.
+	pairs, err = encoder.Encode(logger, []types.Datum{types.NewIntDatum(1<<58 | 100), types.NewStringDatum("b")}, 71, []int{0, 1}, "1.csv", 1234)
+	c.Assert(err, IsNil)
+	c.Assert(decodeID(pairs), Equals, int64(1<<58|100))
+	c.Assert(tbl.Allocators(encoder.(*tableKVEncoder).se).Get(autoid.AutoRandomType).Base(), Equals, int64(100))
+}
+
 func (s *kvSuite) TestShardRowId(c *C) {
 	tblInfo := mockTableInfo(c, "create table t (s varchar(16)) shard_row_id_bits = 3;")
 	tbl, err := tables.TableFromMeta(NewPanickingAllocators(0), tblInfo)
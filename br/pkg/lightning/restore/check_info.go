@@ -657,8 +657,9 @@ func (rc *Controller) SchemaIsValid(ctx context.Context, tableInfo *mydump.MDTab
 	colCountFromTiDB := len(info.Core.Columns)
 	core := info.Core
 	defaultCols := make(map[string]struct{})
+	autoRandomCol := core.GetAutoRandomColInfo()
 	for _, col := range core.Columns {
-		if hasDefault(col) || (info.Core.ContainsAutoRandomBits() && mysql.HasPriKeyFlag(col.Flag)) {
+		if hasDefault(col) || (autoRandomCol != nil && col.Offset == autoRandomCol.Offset) {
 			// this column has default value or it's auto random id, so we can ignore it
 			defaultCols[col.Name.L] = struct{}{}
 		}
@@ -244,11 +244,12 @@ func (m *dbTableMetaMgr) AllocTableRowIDs(ctx context.Context, rawRowIDMax int64
 				// NOTE: currently, if a table contains auto_incremental unique key and _tidb_rowid,
 				// the `show table next_row_id` will returns the unique key field only.
 				var autoIDField string
+				autoRandomCol := m.tr.tableInfo.Core.GetAutoRandomColInfo()
 				for _, col := range m.tr.tableInfo.Core.Columns {
 					if mysql.HasAutoIncrementFlag(col.Flag) {
 						autoIDField = col.Name.L
 						break
-					} else if mysql.HasPriKeyFlag(col.Flag) && m.tr.tableInfo.Core.AutoRandomBits > 0 {
+					} else if autoRandomCol != nil && col.Offset == autoRandomCol.Offset {
 						autoIDField = col.Name.L
 						break
 					}
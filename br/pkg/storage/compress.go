@@ -37,7 +37,10 @@ func (w *withCompression) Create(ctx context.Context, name string) (ExternalFile
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	compressedWriter := newBufferedWriter(writer, hardcodedS3ChunkSize, w.compressType)
+	compressedWriter, err := newBufferedWriter(writer, hardcodedS3ChunkSize, w.compressType)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return compressedWriter, nil
 }
 
@@ -55,8 +58,11 @@ func (w *withCompression) Open(ctx context.Context, path string) (ExternalFileRe
 
 func (w *withCompression) WriteFile(ctx context.Context, name string, data []byte) error {
 	bf := bytes.NewBuffer(make([]byte, 0, len(data)))
-	compressBf := newCompressWriter(w.compressType, bf)
-	_, err := compressBf.Write(data)
+	compressBf, err := newCompressWriter(w.compressType, bf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = compressBf.Write(data)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -735,8 +735,7 @@ func (rs *S3Storage) Create(ctx context.Context, name string) (ExternalFileWrite
 	if err != nil {
 		return nil, err
 	}
-	uploaderWriter := newBufferedWriter(uploader, hardcodedS3ChunkSize, NoCompression)
-	return uploaderWriter, nil
+	return newBufferedWriter(uploader, hardcodedS3ChunkSize, NoCompression)
 }
 
 // retryerWithLog wrappes the client.DefaultRetryer, and logging when retry triggered.
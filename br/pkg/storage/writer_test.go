@@ -101,7 +101,7 @@ func (r *testStorageSuite) TestCompressReaderWriter(c *C) {
 		ctx := context.Background()
 		storage, err := Create(ctx, backend, true)
 		c.Assert(err, IsNil)
-		storage = WithCompression(storage, Gzip)
+		storage = WithCompression(storage, test.compressType)
 		fileName := strings.ReplaceAll(test.name, " ", "-") + ".txt.gz"
 		writer, err := storage.Create(ctx, fileName)
 		c.Assert(err, IsNil)
@@ -134,7 +134,7 @@ func (r *testStorageSuite) TestCompressReaderWriter(c *C) {
 
 		c.Assert(file.Close(), IsNil)
 	}
-	compressTypeArr := []CompressType{Gzip}
+	compressTypeArr := []CompressType{Gzip, Zstd}
 	tests := []testcase{
 		{
 			name: "long text medium chunks",
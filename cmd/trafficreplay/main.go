@@ -0,0 +1,82 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// trafficreplay replays the traffic captured by the status API `/api/v2/traffic/capture` of TiDB against another cluster,
+// for example, a test cluster of the new version before upgrading.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/traffic"
+	"go.uber.org/zap"
+)
+
+var (
+	dir      = flag.String("dir", "", "the directory of the captured traffic")
+	dsn      = flag.String("dsn", "root:@tcp(127.0.0.1:4000)/?multiStatements=true", "the DSN of the cluster to replay against")
+	speed    = flag.Float64("speed", 1, "the multiple of the captured speed, replay as fast as possible if it's 0")
+	logLevel = flag.String("L", "info", "log level")
+)
+
+func main() {
+	flag.Parse()
+	err := logutil.InitLogger(logutil.NewLogConfig(*logLevel, logutil.DefaultLogFormat, "", logutil.EmptyFileLogConfig, false))
+	terror.MustNil(err)
+	if *dir == "" {
+		fmt.Fprintln(os.Stderr, "-dir is required")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	reader, err := traffic.NewReader(*dir)
+	terror.MustNil(err)
+	defer func() {
+		terror.Log(reader.Close())
+	}()
+	db, err := sql.Open("mysql", *dsn)
+	terror.MustNil(err)
+	defer func() {
+		terror.Log(db.Close())
+	}()
+
+	replayer := traffic.NewReplayer(db, *speed, func(r *traffic.Record, err error) {
+		// Only the records whose results differ from the capture are reported.
+		if (err != nil) == (r.Err != "") {
+			return
+		}
+		var replayErr string
+		if err != nil {
+			replayErr = err.Error()
+		}
+		log.Warn("the replay result differs from the capture", zap.Uint64("conn_id", r.ConnID),
+			zap.String("sql", r.SQL), zap.String("captured error", r.Err), zap.String("replayed error", replayErr))
+	})
+	stats, err := replayer.Replay(context.Background(), reader)
+	if err != nil {
+		log.Error("replay failed", zap.Error(err))
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	terror.MustNil(err)
+	fmt.Println(string(data))
+}
@@ -95,10 +95,13 @@ type Config struct {
 	RunDDL           bool   `toml:"run-ddl" json:"run-ddl"`
 	SplitTable       bool   `toml:"split-table" json:"split-table"`
 	TokenLimit       uint   `toml:"token-limit" json:"token-limit"`
-	OOMUseTmpStorage bool   `toml:"oom-use-tmp-storage" json:"oom-use-tmp-storage"`
-	TempStoragePath  string `toml:"tmp-storage-path" json:"tmp-storage-path"`
-	OOMAction        string `toml:"oom-action" json:"oom-action"`
-	MemQuotaQuery    int64  `toml:"mem-quota-query" json:"mem-quota-query"`
+	TokenLimitAction string `toml:"token-limit-action" json:"token-limit-action"`
+	// TokenLimitQueueTimeout is the max seconds a statement waits with token-limit-action = "queue", 0 means no limit.
+	TokenLimitQueueTimeout uint   `toml:"token-limit-queue-timeout" json:"token-limit-queue-timeout"`
+	OOMUseTmpStorage       bool   `toml:"oom-use-tmp-storage" json:"oom-use-tmp-storage"`
+	TempStoragePath        string `toml:"tmp-storage-path" json:"tmp-storage-path"`
+	OOMAction              string `toml:"oom-action" json:"oom-action"`
+	MemQuotaQuery          int64  `toml:"mem-quota-query" json:"mem-quota-query"`
 	// TempStorageQuota describe the temporary storage Quota during query exector when OOMUseTmpStorage is enabled
 	// If the quota exceed the capacity of the TempStoragePath, the tidb-server would exit with fatal error
 	TempStorageQuota int64 `toml:"tmp-storage-quota" json:"tmp-storage-quota"` // Bytes
@@ -117,6 +120,7 @@ type Config struct {
 	PreparedPlanCache          PreparedPlanCache  `toml:"prepared-plan-cache" json:"prepared-plan-cache"`
 	OpenTracing                OpenTracing        `toml:"opentracing" json:"opentracing"`
 	ProxyProtocol              ProxyProtocol      `toml:"proxy-protocol" json:"proxy-protocol"`
+	XProtocol                  XProtocol          `toml:"x-protocol" json:"x-protocol"`
 	PDClient                   tikvcfg.PDClient   `toml:"pd-client" json:"pd-client"`
 	TiKVClient                 tikvcfg.TiKVClient `toml:"tikv-client" json:"tikv-client"`
 	Binlog                     Binlog             `toml:"binlog" json:"binlog"`
@@ -140,6 +144,8 @@ type Config struct {
 	SplitRegionMaxNum   uint64      `toml:"split-region-max-num" json:"split-region-max-num"`
 	StmtSummary         StmtSummary `toml:"stmt-summary" json:"stmt-summary"`
 	TopSQL              TopSQL      `toml:"top-sql" json:"top-sql"`
+	TrxSummary          TrxSummary  `toml:"transaction-summary" json:"transaction-summary"`
+	Audit               Audit       `toml:"audit" json:"audit"`
 	// RepairMode indicates that the TiDB is in the repair mode for table meta.
 	RepairMode      bool     `toml:"repair-mode" json:"repair-mode"`
 	RepairTableList []string `toml:"repair-table-list" json:"repair-table-list"`
@@ -166,6 +172,7 @@ type Config struct {
 	// key will be the default value of the session variable `txn_scope` for this tidb-server.
 	Labels map[string]string `toml:"labels" json:"labels"`
 	// EnableGlobalIndex enables creating global index.
+	// TRUNCATE PARTITION and EXCHANGE PARTITION are not supported on the tables with global indexes.
 	EnableGlobalIndex bool `toml:"enable-global-index" json:"enable-global-index"`
 	// DeprecateIntegerDisplayWidth indicates whether deprecating the max display length for integer.
 	DeprecateIntegerDisplayWidth bool `toml:"deprecate-integer-display-length" json:"deprecate-integer-display-length"`
@@ -414,6 +421,16 @@ type Security struct {
 	MinTLSVersion   string `toml:"tls-version" json:"tls-version"`
 	RSAKeySize      int    `toml:"rsa-key-size" json:"rsa-key-size"`
 	SecureBootstrap bool   `toml:"secure-bootstrap" json:"secure-bootstrap"`
+	// TLSCipherSuites are the cipher suites used by TLSv1.2 and earlier versions, empty means all the secure ones.
+	TLSCipherSuites []string `toml:"tls-cipher-suites" json:"tls-cipher-suites"`
+	// TLSReloadInterval is the interval in seconds to check whether the TLS certificate files are changed, 0 disables it.
+	TLSReloadInterval uint `toml:"tls-reload-interval" json:"tls-reload-interval"`
+	// The certificate and key to sign the session tokens, which are used to migrate the sessions.
+	SessionTokenSigningCert string `toml:"session-token-signing-cert" json:"session-token-signing-cert"`
+	SessionTokenSigningKey  string `toml:"session-token-signing-key" json:"session-token-signing-key"`
+	// DisconnectOnExpiredPassword rejects the clients that can't handle the expired passwords when their passwords
+	// are expired, otherwise they are logged in the sandbox mode where only the password can be changed.
+	DisconnectOnExpiredPassword bool `toml:"disconnect-on-expired-password" json:"disconnect-on-expired-password"`
 }
 
 // The ErrConfigValidationFailed error is used so that external callers can do a type assertion
@@ -445,6 +462,14 @@ type Status struct {
 	MetricsInterval uint   `toml:"metrics-interval" json:"metrics-interval"`
 	ReportStatus    bool   `toml:"report-status" json:"report-status"`
 	RecordQPSbyDB   bool   `toml:"record-db-qps" json:"record-db-qps"`
+	// RecordStmtMetricsByGroup indicates whether to record the metrics of the statements by resource group and database.
+	RecordStmtMetricsByGroup bool `toml:"record-stmt-metrics-by-group" json:"record-stmt-metrics-by-group"`
+	// StmtMetricsLabelLimit limits the number of distinct (resource group, database) label pairs of the metrics above,
+	// the statements beyond it are recorded with the database label `other`.
+	StmtMetricsLabelLimit int `toml:"stmt-metrics-label-limit" json:"stmt-metrics-label-limit"`
+	// TrafficCaptureDir is the root directory of the traffic captured by the status API, the directories specified by
+	// the API are relative to it. Capturing the traffic is disabled if it's empty.
+	TrafficCaptureDir string `toml:"traffic-capture-dir" json:"traffic-capture-dir"`
 	// After a duration of this time in seconds if the server doesn't see any activity it pings
 	// the client to see if the transport is still alive.
 	GRPCKeepAliveTime uint `toml:"grpc-keepalive-time" json:"grpc-keepalive-time"`
@@ -529,6 +554,9 @@ type OpenTracingReporter struct {
 	BufferFlushInterval time.Duration `toml:"buffer-flush-interval" json:"buffer-flush-interval"`
 	LogSpans            bool          `toml:"log-spans" json:"log-spans"`
 	LocalAgentHostPort  string        `toml:"local-agent-host-port" json:"local-agent-host-port"`
+	// OTLPEndpoint is the OTLP/HTTP endpoint receiving the spans, e.g. `http://127.0.0.1:4318/v1/traces`.
+	// The spans are exported in OTLP instead of to the jaeger agent if it's set.
+	OTLPEndpoint string `toml:"otlp-endpoint" json:"otlp-endpoint"`
 }
 
 // ProxyProtocol is the PROXY protocol section of the config.
@@ -541,6 +569,14 @@ type ProxyProtocol struct {
 	HeaderTimeout uint `toml:"header-timeout" json:"header-timeout"`
 }
 
+// XProtocol is the MySQL X Protocol section of the config.
+type XProtocol struct {
+	// Enable the X Protocol, which is used by the connectors of XDevAPI.
+	Enable bool `toml:"enable" json:"enable"`
+	// Port is the port of the X Protocol.
+	Port uint `toml:"port" json:"port"`
+}
+
 // Binlog is the config for binlog.
 type Binlog struct {
 	Enable bool `toml:"enable" json:"enable"`
@@ -593,12 +629,63 @@ type StmtSummary struct {
 	RefreshInterval int `toml:"refresh-interval" json:"refresh-interval"`
 	// The maximum history size of statement summary.
 	HistorySize int `toml:"history-size" json:"history-size"`
+	// EnablePersistent writes the history summaries into File, so that they survive the restarts.
+	EnablePersistent bool `toml:"enable-persistent" json:"enable-persistent"`
+	// File is the rotated file of the persisted summaries, its max-days and max-backups are the retention.
+	File logutil.FileLogConfig `toml:"file" json:"file"`
+}
+
+// TrxSummary is the config for the transaction summary.
+type TrxSummary struct {
+	// The max count of the transaction summaries kept in the information_schema.trx_summary table.
+	TransactionSummaryCapacity uint `toml:"transaction-summary-capacity" json:"transaction-summary-capacity"`
+	// The transactions shorter than it (in milliseconds) are not recorded in the information_schema.trx_summary table.
+	TransactionSummaryMinDuration uint `toml:"transaction-summary-min-duration" json:"transaction-summary-min-duration"`
+}
+
+// The sinks of the audit log.
+const (
+	AuditSinkFile   = "file"
+	AuditSinkSyslog = "syslog"
+	AuditSinkGRPC   = "grpc"
+)
+
+// Audit is the config for the audit log, which records the connections, the privilege changes, the DDLs and
+// optionally the other statements of the clients.
+type Audit struct {
+	Enable bool `toml:"enable" json:"enable"`
+	// Sink is where the events are written, one of "file", "syslog" and "grpc".
+	Sink string `toml:"sink" json:"sink"`
+	// File is the rotated file of the "file" sink.
+	File logutil.FileLogConfig `toml:"file" json:"file"`
+	// SyslogNetwork and SyslogAddress are the server of the "syslog" sink, the local syslog is used if they are empty.
+	SyslogNetwork string `toml:"syslog-network" json:"syslog-network"`
+	SyslogAddress string `toml:"syslog-address" json:"syslog-address"`
+	// GRPCAddress is the receiver of the "grpc" sink, it's connected with the cluster TLS config.
+	GRPCAddress string `toml:"grpc-address" json:"grpc-address"`
+	// Redact replaces the literals in the statements with "?" and omits the error messages.
+	Redact bool `toml:"redact" json:"redact"`
+	// Rules select the events to be recorded, the connections, the privilege changes and the DDLs are recorded
+	// if there is no rule.
+	Rules []AuditRule `toml:"rules" json:"rules"`
+}
+
+// AuditRule selects the audit events which match all of its non-empty fields.
+type AuditRule struct {
+	// Users are the user names, "%" matches all the users.
+	Users []string `toml:"users" json:"users"`
+	// DBs are the databases, they're matched with the current database and the databases of the accessed tables.
+	DBs []string `toml:"dbs" json:"dbs"`
+	// Classes are the event classes, which are "connection", "privilege", "ddl", "dml" and "other".
+	Classes []string `toml:"classes" json:"classes"`
 }
 
 // TopSQL is the config for TopSQL.
 type TopSQL struct {
 	// The TopSQL's data receiver address.
 	ReceiverAddress string `toml:"receiver-address" json:"receiver-address"`
+	// The HTTP URL receiving the TopSQL's data in JSON, which also contains the CPU time of the statements on each TiKV.
+	HTTPReceiverURL string `toml:"http-receiver-url" json:"http-receiver-url"`
 }
 
 // IsolationRead is the config for isolation read.
@@ -631,6 +718,8 @@ var defaultConf = Config{
 	SplitTable:                   true,
 	Lease:                        "45s",
 	TokenLimit:                   1000,
+	TokenLimitAction:             TokenLimitActionQueue,
+	TokenLimitQueueTimeout:       0,
 	OOMUseTmpStorage:             true,
 	TempStorageQuota:             -1,
 	TempStoragePath:              tempStorageDirName,
@@ -675,6 +764,7 @@ var defaultConf = Config{
 		StatusPort:            DefStatusPort,
 		MetricsInterval:       15,
 		RecordQPSbyDB:         false,
+		StmtMetricsLabelLimit: 1000,
 		GRPCKeepAliveTime:     10,
 		GRPCKeepAliveTimeout:  3,
 		GRPCConcurrentStreams: 1024,
@@ -711,6 +801,10 @@ var defaultConf = Config{
 		Networks:      "",
 		HeaderTimeout: 5,
 	},
+	XProtocol: XProtocol{
+		Enable: false,
+		Port:   33060,
+	},
 	PreparedPlanCache: PreparedPlanCache{
 		Enabled:          false,
 		Capacity:         1000,
@@ -742,6 +836,18 @@ var defaultConf = Config{
 		MaxSQLLength:        4096,
 		RefreshInterval:     1800,
 		HistorySize:         24,
+		EnablePersistent:    false,
+		File:                logutil.NewFileLogConfig(logutil.DefaultLogMaxSize),
+	},
+	TrxSummary: TrxSummary{
+		TransactionSummaryCapacity:    500,
+		TransactionSummaryMinDuration: 0,
+	},
+	Audit: Audit{
+		Enable: false,
+		Sink:   AuditSinkFile,
+		File:   logutil.NewFileLogConfig(logutil.DefaultLogMaxSize),
+		Redact: true,
 	},
 	IsolationRead: IsolationRead{
 		Engines: []string{"tikv", "tiflash", "tidb"},
@@ -759,6 +865,7 @@ var defaultConf = Config{
 		EnableSEM:                   false,
 		AutoTLS:                     false,
 		RSAKeySize:                  4096,
+		DisconnectOnExpiredPassword: true,
 	},
 	DeprecateIntegerDisplayWidth: false,
 	EnableEnumLengthLimit:        true,
@@ -940,6 +1047,10 @@ func (c *Config) Valid() error {
 	if c.OOMAction != OOMActionLog && c.OOMAction != OOMActionCancel {
 		return fmt.Errorf("unsupported OOMAction %v, TiDB only supports [%v, %v]", c.OOMAction, OOMActionLog, OOMActionCancel)
 	}
+	c.TokenLimitAction = strings.ToLower(c.TokenLimitAction)
+	if c.TokenLimitAction != TokenLimitActionQueue && c.TokenLimitAction != TokenLimitActionReject {
+		return fmt.Errorf("unsupported TokenLimitAction %v, TiDB only supports [%v, %v]", c.TokenLimitAction, TokenLimitActionQueue, TokenLimitActionReject)
+	}
 	if c.TableColumnCountLimit < DefTableColumnCountLimit || c.TableColumnCountLimit > DefMaxOfTableColumnCountLimit {
 		return fmt.Errorf("table-column-limit should be [%d, %d]", DefIndexLimit, DefMaxOfTableColumnCountLimit)
 	}
@@ -1001,6 +1112,28 @@ func (c *Config) Valid() error {
 			c.Security.SpilledFileEncryptionMethod, SpilledFileEncryptionMethodPlaintext, SpilledFileEncryptionMethodAES128CTR)
 	}
 
+	if c.StmtSummary.EnablePersistent && c.StmtSummary.File.Filename == "" {
+		return fmt.Errorf("[stmt-summary]file.filename should be specified for the persistence")
+	}
+
+	// test audit
+	if c.Audit.Enable {
+		switch c.Audit.Sink {
+		case AuditSinkFile:
+			if c.Audit.File.Filename == "" {
+				return fmt.Errorf("[audit]file.filename should be specified for the file sink")
+			}
+		case AuditSinkSyslog:
+		case AuditSinkGRPC:
+			if c.Audit.GRPCAddress == "" {
+				return fmt.Errorf("[audit]grpc-address should be specified for the grpc sink")
+			}
+		default:
+			return fmt.Errorf("unsupported [audit]sink %v, TiDB only supports [%v, %v, %v]",
+				c.Audit.Sink, AuditSinkFile, AuditSinkSyslog, AuditSinkGRPC)
+		}
+	}
+
 	// test log level
 	l := zap.NewAtomicLevel()
 	return l.UnmarshalText([]byte(c.Log.Level))
@@ -1086,6 +1219,16 @@ const (
 	OOMActionLog    = "log"
 )
 
+// The following constants represents the valid action configurations for TokenLimitAction.
+// The action is taken on a new statement when all the tokens are in use, or when the memory
+// tracked by the executors of the whole server exceeds performance.server-memory-quota.
+const (
+	// TokenLimitActionQueue makes the statement wait until the resources are available.
+	TokenLimitActionQueue = "queue"
+	// TokenLimitActionReject makes the statement fail immediately.
+	TokenLimitActionReject = "reject"
+)
+
 // hideConfig is used to filter a single line of config for hiding.
 var hideConfig = []string{
 	"index-usage-sync-lease",
@@ -209,6 +209,8 @@ unrecognized-option-test = true
 
 	_, err = f.WriteString(`
 token-limit = 0
+token-limit-action = "reject"
+token-limit-queue-timeout = 5
 enable-table-lock = true
 alter-primary-key = true
 delay-clean-table-lock = 5
@@ -246,6 +248,10 @@ max-stmt-count=1000
 max-sql-length=1024
 refresh-interval=100
 history-size=100
+enable-persistent=true
+[stmt-summary.file]
+filename="tidb-statements.log"
+max-days=7
 [experimental]
 allow-expression-index = true
 [isolation-read]
@@ -261,6 +267,9 @@ deadlock-history-capacity = 123
 deadlock-history-collect-retryable = true
 [top-sql]
 receiver-address = "127.0.0.1:10100"
+http-receiver-url = "http://127.0.0.1:10101/topsql"
+[opentracing.reporter]
+otlp-endpoint = "http://127.0.0.1:4318/v1/traces"
 [status]
 grpc-keepalive-time = 20
 grpc-keepalive-timeout = 10
@@ -291,6 +300,8 @@ grpc-max-send-msg-size = 40960
 	require.Equal(t, int64(0), conf.TiKVClient.StoreLimit)
 	require.Equal(t, int64(8192), conf.TiKVClient.TTLRefreshedTxnSize)
 	require.Equal(t, uint(1000), conf.TokenLimit)
+	require.Equal(t, TokenLimitActionReject, conf.TokenLimitAction)
+	require.Equal(t, uint(5), conf.TokenLimitQueueTimeout)
 	require.True(t, conf.EnableTableLock)
 	require.Equal(t, uint64(5), conf.DelayCleanTableLock)
 	require.Equal(t, uint64(10000), conf.SplitRegionMaxNum)
@@ -300,6 +311,9 @@ grpc-max-send-msg-size = 40960
 	require.Equal(t, uint(1024), conf.StmtSummary.MaxSQLLength)
 	require.Equal(t, 100, conf.StmtSummary.RefreshInterval)
 	require.Equal(t, 100, conf.StmtSummary.HistorySize)
+	require.True(t, conf.StmtSummary.EnablePersistent)
+	require.Equal(t, "tidb-statements.log", conf.StmtSummary.File.Filename)
+	require.Equal(t, 7, conf.StmtSummary.File.MaxDays)
 	require.True(t, conf.EnableBatchDML)
 	require.True(t, conf.RepairMode)
 	require.Equal(t, uint64(16), conf.TiKVClient.ResolveLockLiteThreshold)
@@ -323,6 +337,8 @@ grpc-max-send-msg-size = 40960
 	require.True(t, conf.PessimisticTxn.DeadlockHistoryCollectRetryable)
 	require.False(t, conf.Experimental.EnableNewCharset)
 	require.Equal(t, "127.0.0.1:10100", conf.TopSQL.ReceiverAddress)
+	require.Equal(t, "http://127.0.0.1:10101/topsql", conf.TopSQL.HTTPReceiverURL)
+	require.Equal(t, "http://127.0.0.1:4318/v1/traces", conf.OpenTracing.Reporter.OTLPEndpoint)
 	require.True(t, conf.Experimental.AllowsExpressionIndex)
 	require.Equal(t, uint(20), conf.Status.GRPCKeepAliveTime)
 	require.Equal(t, uint(10), conf.Status.GRPCKeepAliveTimeout)
@@ -505,6 +521,32 @@ func TestOOMActionValid(t *testing.T) {
 	}
 }
 
+func TestTokenLimitActionValid(t *testing.T) {
+	c1 := NewConfig()
+	tests := []struct {
+		action string
+		valid  bool
+	}{
+		{"queue", true},
+		{"Queue", true},
+		{"REJECT", true},
+		{"cancel", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		c1.TokenLimitAction = tt.action
+		require.Equal(t, tt.valid, c1.Valid() == nil)
+	}
+}
+
+func TestTokenLimitDefault(t *testing.T) {
+	// The statements keep queueing without limit by default, rejecting them is opt-in.
+	c1 := NewConfig()
+	require.Equal(t, uint(1000), c1.TokenLimit)
+	require.Equal(t, TokenLimitActionQueue, c1.TokenLimitAction)
+	require.Equal(t, uint(0), c1.TokenLimitQueueTimeout)
+}
+
 func TestTxnTotalSizeLimitValid(t *testing.T) {
 	conf := NewConfig()
 	tests := []struct {
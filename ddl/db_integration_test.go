@@ -3748,3 +3748,35 @@ func (s *testIntegrationSuite3) TestIssue29326(c *C) {
 	err = tk.ExecToErr("create definer=`root`@`127.0.0.1` view v1 as select t1.id, t2.id from t1,t2 where t1.id=t2.id")
 	c.Assert(infoschema.ErrColumnExists.Equal(err), IsTrue)
 }
+
+func (s *testIntegrationSuite3) TestTableGCLifeTime(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	defer tk.MustExec("drop table if exists t, t1")
+
+	tk.MustExec("create table t (a int) gc_life_time = '120m'")
+	tblInfo, err := s.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(tblInfo.Meta().GCLifeTime, Equals, "2h0m0s")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T![gc_life_time] GC_LIFE_TIME='2h0m0s' */"))
+
+	tk.MustExec("alter table t gc_life_time = '48h'")
+	tblInfo, err = s.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(tblInfo.Meta().GCLifeTime, Equals, "48h0m0s")
+	tk.MustExec("alter table t gc_life_time = ''")
+	tblInfo, err = s.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(tblInfo.Meta().GCLifeTime, Equals, "")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	tk.MustGetErrCode("alter table t gc_life_time = 'abc'", errno.ErrWrongValue)
+	tk.MustGetErrCode("alter table t gc_life_time = '5m'", errno.ErrWrongValue)
+	tk.MustGetErrCode("create table t1 (a int) gc_life_time = '-1h'", errno.ErrWrongValue)
+	tk.MustGetErrCode("create global temporary table t1 (a int) gc_life_time = '1h' on commit delete rows", errno.ErrOptOnTemporaryTable)
+}
@@ -1653,6 +1653,38 @@ func (s *testSerialDBSuite1) TestDropPartitionWithGlobalIndex(c *C) {
 	})
 }
 
+func (s *testSerialDBSuite1) TestPartitionManagementWithGlobalIndex(c *C) {
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.EnableGlobalIndex = true
+	})
+	defer config.UpdateGlobal(func(conf *config.Config) {
+		conf.EnableGlobalIndex = false
+	})
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists test_global, test_global_nt")
+	tk.MustExec(`create table test_global (a int, b int, unique index idx_b (b))
+	partition by range( a ) (
+		partition p1 values less than (10),
+		partition p2 values less than (20)
+	);`)
+	tk.MustExec("insert into test_global values (1, 1), (11, 2)")
+
+	tk.MustGetErrCode("alter table test_global truncate partition p2", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("create table test_global_nt (a int, b int, unique index idx_b (b))")
+	tk.MustExec("set @@tidb_enable_exchange_partition = 1")
+	tk.MustGetErrCode("alter table test_global exchange partition p1 with table test_global_nt", errno.ErrUnsupportedDDLOperation)
+
+	tk.MustExec("alter table test_global add partition (partition p3 values less than (30))")
+	tk.MustExec("insert into test_global values (21, 3)")
+	tk.MustGetErrCode("insert into test_global values (22, 1)", errno.ErrDupEntry)
+	// The entries of the dropped partitions are cleaned up, so their unique values can be reused.
+	tk.MustExec("alter table test_global drop partition p2")
+	tk.MustExec("insert into test_global values (23, 2)")
+	tk.MustQuery("select * from test_global where b = 2").Check(testkit.Rows("23 2"))
+	tk.MustQuery("select * from test_global use index (idx_b) order by b").Check(testkit.Rows("1 1", "23 2", "21 3"))
+}
+
 func (s *testSerialDBSuite1) TestAlterTableExchangePartition(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -597,17 +597,17 @@ func buildColumnAndConstraint(
 
 // checkColumnDefaultValue checks the default value of the column.
 // In non-strict SQL mode, if the default value of the column is an empty string, the default value can be ignored.
-// In strict SQL mode, TEXT/BLOB/JSON can't have not null default values.
+// In strict SQL mode, TEXT/BLOB/JSON/VECTOR can't have not null default values.
 // In NO_ZERO_DATE SQL mode, TIMESTAMP/DATE/DATETIME type can't have zero date like '0000-00-00' or '0000-00-00 00:00:00'.
 func checkColumnDefaultValue(ctx sessionctx.Context, col *table.Column, value interface{}) (bool, interface{}, error) {
 	hasDefaultValue := true
-	if value != nil && (col.Tp == mysql.TypeJSON ||
+	if value != nil && (col.Tp == mysql.TypeJSON || col.Tp == mysql.TypeTiDBVectorFloat32 ||
 		col.Tp == mysql.TypeTinyBlob || col.Tp == mysql.TypeMediumBlob ||
 		col.Tp == mysql.TypeLongBlob || col.Tp == mysql.TypeBlob) {
 		// In non-strict SQL mode.
 		if !ctx.GetSessionVars().SQLMode.HasStrictMode() && value == "" {
-			if col.Tp == mysql.TypeBlob || col.Tp == mysql.TypeLongBlob {
-				// The TEXT/BLOB default value can be ignored.
+			if col.Tp == mysql.TypeBlob || col.Tp == mysql.TypeLongBlob || col.Tp == mysql.TypeTiDBVectorFloat32 {
+				// The TEXT/BLOB/VECTOR default value can be ignored.
 				hasDefaultValue = false
 			}
 			// In non-strict SQL mode, if the column type is json and the default value is null, it is initialized to an empty array.
@@ -1186,7 +1186,8 @@ func containsColumnOption(colDef *ast.ColumnDef, opTp ast.ColumnOptionType) bool
 
 // IsAutoRandomColumnID returns true if the given column ID belongs to an auto_random column.
 func IsAutoRandomColumnID(tblInfo *model.TableInfo, colID int64) bool {
-	return tblInfo.PKIsHandle && tblInfo.ContainsAutoRandomBits() && tblInfo.GetPkColInfo().ID == colID
+	col := tblInfo.GetAutoRandomColInfo()
+	return col != nil && col.ID == colID
 }
 
 func checkGeneratedColumn(ctx sessionctx.Context, colDefs []*ast.ColumnDef) error {
@@ -1453,14 +1454,21 @@ func getPrimaryKey(tblInfo *model.TableInfo) *model.IndexInfo {
 }
 
 func setTableAutoRandomBits(ctx sessionctx.Context, tbInfo *model.TableInfo, colDefs []*ast.ColumnDef) error {
-	pkColName := tbInfo.GetPkName()
+	var pkColName model.CIStr
+	if tbInfo.PKIsHandle {
+		pkColName = tbInfo.GetPkName()
+	} else if tbInfo.IsCommonHandle {
+		// The auto_random column can be the first column of the clustered composite primary key.
+		pkIdx := tables.FindPrimaryIndex(tbInfo)
+		pkColName = pkIdx.Columns[0].Name
+	}
 	for _, col := range colDefs {
 		if containsColumnOption(col, ast.ColumnOptionAutoRandom) {
 			if col.Tp.Tp != mysql.TypeLonglong {
 				return ErrInvalidAutoRandom.GenWithStackByArgs(
 					fmt.Sprintf(autoid.AutoRandomOnNonBigIntColumn, types.TypeStr(col.Tp.Tp)))
 			}
-			if !tbInfo.PKIsHandle || col.Name.Name.L != pkColName.L {
+			if col.Name.Name.L != pkColName.L {
 				errMsg := fmt.Sprintf(autoid.AutoRandomPKisNotHandleErrMsg, col.Name.Name.O)
 				return ErrInvalidAutoRandom.GenWithStackByArgs(errMsg)
 			}
@@ -1613,6 +1621,9 @@ func buildTableInfo(
 		case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
 			idxInfo.Unique = true
 		}
+		if idxInfo.Unique && idxInfo.MVIndex {
+			return nil, errUnsupportedMVIndex.GenWithStackByArgs("unique key")
+		}
 		// set index type.
 		if constr.Option != nil {
 			idxInfo.Comment, err = validateCommentLength(ctx.GetSessionVars(), idxInfo.Name.String(), constr.Option)
@@ -1899,6 +1910,9 @@ func buildTableInfoWithStmt(ctx sessionctx.Context, s *ast.CreateTableStmt, dbCh
 	if err = setTemporaryType(ctx, tbInfo, s); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err = setExternalTable(tbInfo, s); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	if err = setTableAutoRandomBits(ctx, tbInfo, colDefs); err != nil {
 		return nil, errors.Trace(err)
@@ -1908,7 +1922,7 @@ func buildTableInfoWithStmt(ctx sessionctx.Context, s *ast.CreateTableStmt, dbCh
 		return nil, errors.Trace(err)
 	}
 
-	if tbInfo.TempTableType == model.TempTableNone && tbInfo.PlacementPolicyRef == nil && tbInfo.DirectPlacementOpts == nil {
+	if tbInfo.TempTableType == model.TempTableNone && tbInfo.ExternalTable == nil && tbInfo.PlacementPolicyRef == nil && tbInfo.DirectPlacementOpts == nil {
 		// Set the defaults from Schema. Note: they are mutual exclusive!
 		if placementPolicyRef != nil {
 			tbInfo.PlacementPolicyRef = placementPolicyRef
@@ -2019,6 +2033,31 @@ func setTemporaryType(ctx sessionctx.Context, tbInfo *model.TableInfo, s *ast.Cr
 	return nil
 }
 
+// setExternalTable records the location and format of an external table. The
+// rows of an external table are only read from the files, so the features which
+// need to maintain data in TiKV are rejected.
+func setExternalTable(tbInfo *model.TableInfo, s *ast.CreateTableStmt) error {
+	if s.External == nil {
+		return nil
+	}
+	if s.External.Format != model.ExternalTableFormatCSV {
+		return ErrOptOnExternalTable.GenWithStackByArgs("FORMAT " + s.External.Format)
+	}
+	if len(tbInfo.Indices) > 0 || tbInfo.PKIsHandle {
+		return ErrOptOnExternalTable.GenWithStackByArgs("index")
+	}
+	for _, col := range tbInfo.Columns {
+		if col.IsGenerated() {
+			return ErrOptOnExternalTable.GenWithStackByArgs("generated column")
+		}
+	}
+	tbInfo.ExternalTable = &model.ExternalTableInfo{
+		Location: s.External.Location,
+		Format:   s.External.Format,
+	}
+	return nil
+}
+
 func (d *ddl) CreateTableWithInfo(
 	ctx sessionctx.Context,
 	dbName model.CIStr,
@@ -2183,6 +2222,27 @@ func (d *ddl) RecoverTable(ctx sessionctx.Context, recoverInfo *RecoverInfo) (er
 	return errors.Trace(err)
 }
 
+func (d *ddl) RecoverSchema(ctx sessionctx.Context, recoverSchemaInfo *RecoverSchemaInfo) error {
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	dbInfo := recoverSchemaInfo.DBInfo
+	// Check not exist schema with same name.
+	if is.SchemaExists(dbInfo.Name) {
+		return infoschema.ErrDatabaseExists.GenWithStackByArgs(dbInfo.Name)
+	}
+
+	dbInfo.State = model.StateNone
+	job := &model.Job{
+		SchemaID:   dbInfo.ID,
+		SchemaName: dbInfo.Name.L,
+		Type:       model.ActionRecoverSchema,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{recoverSchemaInfo, recoverTableCheckFlagNone},
+	}
+	err := d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) CreateView(ctx sessionctx.Context, s *ast.CreateViewStmt) (err error) {
 	viewInfo, err := buildViewInfo(ctx, s)
 	if err != nil {
@@ -2451,6 +2511,15 @@ func handleTableOptions(options []*ast.TableOption, tbInfo *model.TableInfo) err
 				return errors.Trace(ErrOptOnTemporaryTable.GenWithStackByArgs("pre split regions"))
 			}
 			tbInfo.PreSplitRegions = op.UintValue
+		case ast.TableOptionGCLifeTime:
+			if tbInfo.TempTableType != model.TempTableNone {
+				return errors.Trace(ErrOptOnTemporaryTable.GenWithStackByArgs("gc life time"))
+			}
+			lifeTime, err := normalizeGCLifeTime(op.StrValue)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			tbInfo.GCLifeTime = lifeTime
 		case ast.TableOptionCharset, ast.TableOptionCollate:
 			// We don't handle charset and collate here since they're handled in `getCharsetAndCollateInTableOption`.
 		case ast.TableOptionPlacementPolicy:
@@ -2644,6 +2713,14 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 	if is.TableIsView(ident.Schema, ident.Name) || is.TableIsSequence(ident.Schema, ident.Name) {
 		return ErrWrongObject.GenWithStackByArgs(ident.Schema, ident.Name, "BASE TABLE")
 	}
+	if tb, err := is.TableByName(ident.Schema, ident.Name); err == nil && tb.Meta().ExternalTable != nil {
+		// The columns of an external table are bound to the fields of the files, only renaming is allowed.
+		for _, spec := range validSpecs {
+			if spec.Tp != ast.AlterTableRenameTable {
+				return ErrOptOnExternalTable.GenWithStackByArgs("alter table")
+			}
+		}
+	}
 
 	err = checkMultiSpecs(sctx, validSpecs)
 	if err != nil {
@@ -2787,6 +2864,8 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 				case ast.TableOptionComment:
 					spec.Comment = opt.StrValue
 					err = d.AlterTableComment(sctx, ident, spec)
+				case ast.TableOptionGCLifeTime:
+					err = d.AlterTableGCLifeTime(sctx, ident, opt.StrValue)
 				case ast.TableOptionCharset, ast.TableOptionCollate:
 					// getCharsetAndCollateInTableOption will get the last charset and collate in the options,
 					// so it should be handled only once.
@@ -3319,6 +3398,10 @@ func (d *ddl) TruncateTablePartition(ctx sessionctx.Context, ident ast.Ident, sp
 	if meta.GetPartitionInfo() == nil {
 		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
 	}
+	// The entries of the truncated partitions in the global indexes can't be cleaned up by truncating.
+	if meta.HasGlobalIndex() {
+		return errors.Trace(ErrUnsupportedPartitionWithGlobalIndex.GenWithStackByArgs("truncate partition"))
+	}
 
 	pids := make([]int64, len(spec.PartitionNames))
 	if spec.OnAllPartitions {
@@ -3561,6 +3644,10 @@ func (d *ddl) ExchangeTablePartition(ctx sessionctx.Context, ident ast.Ident, sp
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// The global indexes can't follow the exchanged rows.
+	if ptMeta.HasGlobalIndex() {
+		return errors.Trace(ErrUnsupportedPartitionWithGlobalIndex.GenWithStackByArgs("exchange partition"))
+	}
 
 	partName := spec.PartitionNames[0].L
 
@@ -4338,7 +4425,7 @@ func checkIndexInModifiableColumns(columns []*model.ColumnInfo, idxColumns []*mo
 
 func checkAutoRandom(tableInfo *model.TableInfo, originCol *table.Column, specNewColumn *ast.ColumnDef) (uint64, error) {
 	var oldRandBits uint64
-	if originCol.IsPKHandleColumn(tableInfo) {
+	if IsAutoRandomColumnID(tableInfo, originCol.ID) {
 		oldRandBits = tableInfo.AutoRandomBits
 	}
 	newRandBits, err := extractAutoRandomBitsFromColDef(specNewColumn)
@@ -4607,6 +4694,47 @@ func (d *ddl) AlterTableComment(ctx sessionctx.Context, ident ast.Ident, spec *a
 	return errors.Trace(err)
 }
 
+// normalizeGCLifeTime checks the GC life time of a table and formats it in Go duration format. Empty means no
+// override of the global GC life time.
+func normalizeGCLifeTime(val string) (string, error) {
+	if val == "" {
+		return "", nil
+	}
+	lifeTime, err := time.ParseDuration(val)
+	if err != nil || lifeTime < minGCLifeTime {
+		return "", errInvalidGCLifeTime.GenWithStackByArgs("GC_LIFE_TIME", val)
+	}
+	return lifeTime.String(), nil
+}
+
+// AlterTableGCLifeTime updates the GC life time of the table.
+func (d *ddl) AlterTableGCLifeTime(ctx sessionctx.Context, ident ast.Ident, val string) error {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if tb.Meta().TempTableType != model.TempTableNone {
+		return errors.Trace(ErrOptOnTemporaryTable.GenWithStackByArgs("gc life time"))
+	}
+	lifeTime, err := normalizeGCLifeTime(val)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tb.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterTableGCLifeTime,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{lifeTime},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 // AlterTableAutoIDCache updates the table comment information.
 func (d *ddl) AlterTableAutoIDCache(ctx sessionctx.Context, ident ast.Ident, newCache int64) error {
 	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
@@ -5404,6 +5532,9 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	if t.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 		return errors.Trace(ErrOptOnCacheTable.GenWithStackByArgs("Create Index"))
 	}
+	if t.Meta().ExternalTable != nil {
+		return errors.Trace(ErrOptOnExternalTable.GenWithStackByArgs("Create Index"))
+	}
 	// Deal with anonymous index.
 	if len(indexName.L) == 0 {
 		colName := model.NewCIStr("expression_index")
@@ -5457,6 +5588,9 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if unique && isMVIndexColumn(model.FindColumnInfo(finalColumns, indexColumns[0].Name.L)) {
+		return errUnsupportedMVIndex.GenWithStackByArgs("unique key")
+	}
 
 	global := false
 	if unique && tblInfo.GetPartitionInfo() != nil {
@@ -6566,6 +6700,89 @@ func (d *ddl) AlterPlacementPolicy(ctx sessionctx.Context, stmt *ast.AlterPlacem
 	return errors.Trace(err)
 }
 
+func (d *ddl) CreateResourceGroup(ctx sessionctx.Context, stmt *ast.CreateResourceGroupStmt) (err error) {
+	groupName := stmt.ResourceGroupName
+	if groupName.L == DefaultResourceGroupName {
+		return errors.Trace(infoschema.ErrReservedSyntax.GenWithStackByArgs(groupName))
+	}
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	// Check resource group existence.
+	if _, ok := is.ResourceGroupByName(groupName); ok {
+		err = infoschema.ErrResourceGroupExists.GenWithStackByArgs(groupName)
+		if stmt.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	// Auto fill the resource group ID when it is inserted.
+	groupInfo := buildResourceGroupInfo(groupName, stmt.ResourceGroupOptionList)
+
+	job := &model.Job{
+		SchemaName: groupInfo.Name.L,
+		Type:       model.ActionCreateResourceGroup,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{groupInfo},
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+func (d *ddl) AlterResourceGroup(ctx sessionctx.Context, stmt *ast.AlterResourceGroupStmt) (err error) {
+	groupName := stmt.ResourceGroupName
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	// Check resource group existence.
+	group, ok := is.ResourceGroupByName(groupName)
+	if !ok {
+		err = infoschema.ErrResourceGroupNotExists.GenWithStackByArgs(groupName)
+		if stmt.IfExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	newGroupInfo := buildResourceGroupInfo(group.Name, stmt.ResourceGroupOptionList)
+
+	job := &model.Job{
+		SchemaID:   group.ID,
+		SchemaName: group.Name.L,
+		Type:       model.ActionAlterResourceGroup,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{newGroupInfo},
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+func (d *ddl) DropResourceGroup(ctx sessionctx.Context, stmt *ast.DropResourceGroupStmt) (err error) {
+	groupName := stmt.ResourceGroupName
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	// Check resource group existence.
+	group, ok := is.ResourceGroupByName(groupName)
+	if !ok {
+		err = infoschema.ErrResourceGroupNotExists.GenWithStackByArgs(groupName)
+		if stmt.IfExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	job := &model.Job{
+		SchemaID:   group.ID,
+		SchemaName: group.Name.L,
+		Type:       model.ActionDropResourceGroup,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{groupName},
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) AlterTableCache(ctx sessionctx.Context, ti ast.Ident) (err error) {
 	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
 	if err != nil {
@@ -411,8 +411,11 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 			err = w.deleteRange(w.ddlJobCtx, job)
 		}
 	}
-	if job.Type == model.ActionRecoverTable {
+	switch job.Type {
+	case model.ActionRecoverTable:
 		err = finishRecoverTable(w, job)
+	case model.ActionRecoverSchema:
+		err = finishRecoverSchema(w, job)
 	}
 	if err != nil {
 		return errors.Trace(err)
@@ -452,6 +455,22 @@ func finishRecoverTable(w *worker, job *model.Job) error {
 	return nil
 }
 
+func finishRecoverSchema(w *worker, job *model.Job) error {
+	var recoverSchemaInfo *RecoverSchemaInfo
+	var recoverSchemaCheckFlag int64
+	err := job.DecodeArgs(&recoverSchemaInfo, &recoverSchemaCheckFlag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if recoverSchemaCheckFlag == recoverTableCheckFlagEnableGC {
+		err = enableGC(w)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 func isDependencyJobDone(t *meta.Meta, job *model.Job) (bool, error) {
 	if job.DependencyID == noneDependencyJob {
 		return true, nil
@@ -812,6 +831,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onModifyTableCharsetAndCollate(t, job)
 	case model.ActionRecoverTable:
 		ver, err = w.onRecoverTable(d, t, job)
+	case model.ActionRecoverSchema:
+		ver, err = w.onRecoverSchema(d, t, job)
 	case model.ActionLockTable:
 		ver, err = onLockTables(t, job)
 	case model.ActionUnlockTable:
@@ -838,6 +859,12 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onDropPlacementPolicy(d, t, job)
 	case model.ActionAlterPlacementPolicy:
 		ver, err = onAlterPlacementPolicy(d, t, job)
+	case model.ActionCreateResourceGroup:
+		ver, err = onCreateResourceGroup(d, t, job)
+	case model.ActionAlterResourceGroup:
+		ver, err = onAlterResourceGroup(t, job)
+	case model.ActionDropResourceGroup:
+		ver, err = onDropResourceGroup(t, job)
 	case model.ActionAlterTablePartitionPlacement:
 		ver, err = onAlterTablePartitionPlacement(t, job)
 	case model.ActionAlterTablePlacement:
@@ -846,6 +873,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onAlterCacheTable(t, job)
 	case model.ActionAlterNoCacheTable:
 		ver, err = onAlterNoCacheTable(t, job)
+	case model.ActionAlterTableGCLifeTime:
+		ver, err = onAlterTableGCLifeTime(t, job)
 	default:
 		// Invalid job, cancel it.
 		job.State = model.JobStateCancelled
@@ -65,6 +65,9 @@ func buildIndexColumns(columns []*model.ColumnInfo, indexPartSpecifications []*a
 		if col == nil {
 			return nil, errKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", ip.Column.Name)
 		}
+		if isMVIndexColumn(col) && len(indexPartSpecifications) > 1 {
+			return nil, errUnsupportedMVIndex.GenWithStackByArgs("more than one key part")
+		}
 
 		if err := checkIndexColumn(col, ip.Length); err != nil {
 			return nil, err
@@ -129,14 +132,19 @@ func checkIndexColumn(col *model.ColumnInfo, indexColumnLen int) error {
 		return errors.Trace(errWrongKeyColumn.GenWithStackByArgs(col.Name))
 	}
 
-	// JSON column cannot index.
-	if col.FieldType.Tp == mysql.TypeJSON {
+	// JSON column cannot index, except the array of a multi-valued index.
+	if col.FieldType.Tp == mysql.TypeJSON && !isMVIndexColumn(col) {
 		if col.Hidden {
 			return errFunctionalIndexOnJSONOrGeometryFunction
 		}
 		return errors.Trace(errJSONUsedAsKey.GenWithStackByArgs(col.Name.O))
 	}
 
+	// VECTOR column cannot index.
+	if col.FieldType.Tp == mysql.TypeTiDBVectorFloat32 {
+		return errors.Trace(errVectorUsedAsKey.GenWithStackByArgs(col.Name.O))
+	}
+
 	// Length must be specified and non-zero for BLOB and TEXT column indexes.
 	if types.IsTypeBlob(col.FieldType.Tp) {
 		if indexColumnLen == types.UnspecifiedLength {
@@ -236,10 +244,17 @@ func buildIndexInfo(tblInfo *model.TableInfo, indexName model.CIStr, indexPartSp
 		Name:    indexName,
 		Columns: idxColumns,
 		State:   state,
+		MVIndex: isMVIndexColumn(tblInfo.Columns[idxColumns[0].Offset]),
 	}
 	return idxInfo, nil
 }
 
+// isMVIndexColumn checks whether the column is the hidden `CAST(... AS ... ARRAY)`
+// column of a multi-valued index.
+func isMVIndexColumn(col *model.ColumnInfo) bool {
+	return col.Hidden && col.FieldType.Tp == mysql.TypeJSON && mysql.HasArrayFlag(col.Flag)
+}
+
 func addIndexColumnFlag(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) {
 	if indexInfo.Primary {
 		for _, col := range indexInfo.Columns {
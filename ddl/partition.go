@@ -995,15 +995,6 @@ func getPartitionIDsFromDefinitions(defs []model.PartitionDefinition) []int64 {
 	return pids
 }
 
-func hasGlobalIndex(tblInfo *model.TableInfo) bool {
-	for _, idxInfo := range tblInfo.Indices {
-		if idxInfo.Global {
-			return true
-		}
-	}
-	return false
-}
-
 // getTableInfoWithDroppingPartitions builds oldTableInfo including dropping partitions, only used by onDropTablePartition.
 func getTableInfoWithDroppingPartitions(t *model.TableInfo) *model.TableInfo {
 	p := t.Partition
@@ -1099,7 +1090,7 @@ func (w *worker) onDropTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (
 			return ver, errors.Trace(err)
 		}
 		// If table has global indexes, we need reorg to clean up them.
-		if pt, ok := tbl.(table.PartitionedTable); ok && hasGlobalIndex(tblInfo) {
+		if pt, ok := tbl.(table.PartitionedTable); ok && tblInfo.HasGlobalIndex() {
 			// Build elements for compatible with modify column type. elements will not be used when reorganizing.
 			elements := make([]*meta.Element, 0, len(tblInfo.Indices))
 			for _, idxInfo := range tblInfo.Indices {
@@ -1688,6 +1679,7 @@ func getPartitionRuleIDs(dbName string, table *model.TableInfo) []string {
 }
 
 // checkPartitioningKeysConstraints checks that the range partitioning key is included in the table constraint.
+// If global index is enabled, the unique indexes which don't include the partitioning key are built as global indexes.
 func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTableStmt, tblInfo *model.TableInfo) error {
 	// Returns directly if there are no unique keys in the table.
 	if len(tblInfo.Indices) == 0 && !tblInfo.PKIsHandle {
@@ -1721,6 +1713,8 @@ func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTabl
 			if !config.GetGlobalConfig().EnableGlobalIndex {
 				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
 			}
+			// The uniqueness across the partitions can only be kept by a global index.
+			index.Global = true
 		}
 	}
 	// when PKIsHandle, tblInfo.Indices will not contain the primary key.
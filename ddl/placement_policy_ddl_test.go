@@ -123,6 +123,7 @@ func (s *testDDLSuite) TestPlacementPolicyInUse(c *C) {
 		[]*model.DBInfo{db1, db2, dbP},
 		nil,
 		[]*model.PolicyInfo{p1, p2, p3, p4, p5},
+		nil,
 		1,
 	)
 	c.Assert(err, IsNil)
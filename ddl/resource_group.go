@@ -0,0 +1,165 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+)
+
+// DefaultResourceGroupName is the name of the resource group which the sessions belong to by default. It can't be
+// created, altered or dropped, and its requests are never throttled.
+const DefaultResourceGroupName = "default"
+
+func buildResourceGroupInfo(name model.CIStr, options []*ast.ResourceGroupOption) *model.ResourceGroupInfo {
+	groupInfo := &model.ResourceGroupInfo{
+		ResourceGroupSettings: model.NewResourceGroupSettings(),
+		Name:                  name,
+	}
+	for _, opt := range options {
+		switch opt.Tp {
+		case ast.ResourceRURate:
+			groupInfo.RURate = opt.UintValue
+		case ast.ResourcePriority:
+			groupInfo.Priority = opt.UintValue
+		case ast.ResourceBurstable:
+			groupInfo.Burstable = opt.BoolValue
+		case ast.ResourceQueryLimit:
+			groupInfo.ExecElapsedTimeMs = opt.UintValue
+			groupInfo.RunawayAction = opt.RunawayAction
+		case ast.ResourceEgressRate:
+			groupInfo.EgressBytesPerSec = opt.UintValue
+		}
+	}
+	return groupInfo
+}
+
+func onCreateResourceGroup(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	groupInfo := &model.ResourceGroupInfo{}
+	if err := job.DecodeArgs(groupInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	err := checkResourceGroupNotExistAndCancelExistJob(d, t, job, groupInfo)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	groupInfo.State = model.StatePublic
+	if err = t.CreateResourceGroup(groupInfo); err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.SchemaID = groupInfo.ID
+
+	ver, err = updateSchemaVersion(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// Finish this job.
+	job.FinishDBJob(model.JobStateDone, model.StatePublic, ver, nil)
+	return ver, nil
+}
+
+func onAlterResourceGroup(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	alterGroup := &model.ResourceGroupInfo{}
+	if err := job.DecodeArgs(alterGroup); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	oldGroup, err := checkResourceGroupExistAndCancelNonExistJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	newGroup := *oldGroup
+	newGroup.ResourceGroupSettings = alterGroup.ResourceGroupSettings
+	if err = t.UpdateResourceGroup(&newGroup); err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	ver, err = updateSchemaVersion(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// Finish this job.
+	job.FinishDBJob(model.JobStateDone, model.StatePublic, ver, nil)
+	return ver, nil
+}
+
+func onDropResourceGroup(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	groupInfo, err := checkResourceGroupExistAndCancelNonExistJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	// The sessions bound to the dropped group fall back to the default group, so it can be dropped at once.
+	if err = t.DropResourceGroup(groupInfo.ID); err != nil {
+		return ver, errors.Trace(err)
+	}
+	ver, err = updateSchemaVersion(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// Finish this job.
+	job.FinishDBJob(model.JobStateDone, model.StateNone, ver, nil)
+	return ver, nil
+}
+
+func checkResourceGroupNotExistAndCancelExistJob(d *ddlCtx, t *meta.Meta, job *model.Job, info *model.ResourceGroupInfo) error {
+	currVer, err := t.GetSchemaVersion()
+	if err != nil {
+		return err
+	}
+	is := d.infoCache.GetLatest()
+	if is.SchemaMetaVersion() == currVer {
+		// Use cached resource groups.
+		if _, ok := is.ResourceGroupByName(info.Name); ok {
+			job.State = model.JobStateCancelled
+			return infoschema.ErrResourceGroupExists.GenWithStackByArgs(info.Name)
+		}
+		return nil
+	}
+	// Check in meta directly.
+	groups, err := t.ListResourceGroups()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, group := range groups {
+		if group.Name.L == info.Name.L {
+			job.State = model.JobStateCancelled
+			return infoschema.ErrResourceGroupExists.GenWithStackByArgs(info.Name)
+		}
+	}
+	return nil
+}
+
+func checkResourceGroupExistAndCancelNonExistJob(t *meta.Meta, job *model.Job, groupID int64) (*model.ResourceGroupInfo, error) {
+	group, err := t.GetResourceGroup(groupID)
+	if err == nil {
+		return group, nil
+	}
+	if meta.ErrResourceGroupNotExists.Equal(err) {
+		job.State = model.JobStateCancelled
+		return nil, infoschema.ErrResourceGroupNotExists.GenWithStackByArgs(fmt.Sprintf("(Resource Group ID %d)", groupID))
+	}
+	return nil, errors.Trace(err)
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/util/resourcegroup"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceGroupBasic(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("drop resource group if exists rg1")
+	tk.MustExec("create resource group rg1 ru_per_sec = 1000 priority = high")
+	tk.MustGetErrCode("create resource group rg1 ru_per_sec = 1000", errno.ErrResourceGroupExists)
+	tk.MustExec("create resource group if not exists rg1 ru_per_sec = 1000")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 8257 Resource group 'rg1' already exists"))
+	tk.MustGetErrCode("create resource group `default` ru_per_sec = 1000", errno.ErrReservedSyntax)
+
+	group, ok := dom.InfoSchema().ResourceGroupByName(model.NewCIStr("rg1"))
+	require.True(t, ok)
+	require.Equal(t, uint64(1000), group.RURate)
+	require.Equal(t, model.ResourceGroupPriorityHigh, group.Priority)
+	require.False(t, group.Burstable)
+	require.Equal(t, model.ResourceGroupPriorityHigh, resourcegroup.GlobalController.Priority("rg1"))
+
+	tk.MustExec("alter resource group rg1 ru_per_sec = 2000, burstable")
+	group, ok = dom.InfoSchema().ResourceGroupByName(model.NewCIStr("rg1"))
+	require.True(t, ok)
+	require.Equal(t, uint64(2000), group.RURate)
+	require.Equal(t, model.ResourceGroupPriorityMedium, group.Priority)
+	require.True(t, group.Burstable)
+	tk.MustGetErrCode("alter resource group rg2 ru_per_sec = 1000", errno.ErrResourceGroupNotExists)
+
+	// Bind the sessions and the users to the resource groups.
+	tk.MustQuery("select @@tidb_resource_group").Check(testkit.Rows("default"))
+	tk.MustExec("set resource group rg1")
+	tk.MustQuery("select @@tidb_resource_group").Check(testkit.Rows("rg1"))
+	tk.MustGetErrCode("set resource group rg2", errno.ErrResourceGroupNotExists)
+	tk.MustGetErrCode("set @@tidb_resource_group = 'rg1'", errno.ErrIncorrectGlobalLocalVar)
+	tk.MustExec("set resource group default")
+	tk.MustQuery("select @@tidb_resource_group").Check(testkit.Rows("default"))
+
+	tk.MustExec("drop user if exists rg_user1, rg_user2")
+	tk.MustExec("create user rg_user1 resource group rg1")
+	tk.MustGetErrCode("create user rg_user2 resource group rg2", errno.ErrResourceGroupNotExists)
+	tk.MustExec("create user rg_user2")
+	tk.MustQuery("select user, resource_group from mysql.user where user like 'rg_user%' order by user").Check(testkit.Rows("rg_user1 rg1", "rg_user2 "))
+	tk.MustExec("alter user rg_user2 resource group rg1")
+	tk.MustExec("alter user rg_user1 resource group default")
+	tk.MustQuery("select user, resource_group from mysql.user where user like 'rg_user%' order by user").Check(testkit.Rows("rg_user1 ", "rg_user2 rg1"))
+	tk.MustExec("drop user rg_user1, rg_user2")
+
+	tk.MustExec("drop resource group rg1")
+	_, ok = dom.InfoSchema().ResourceGroupByName(model.NewCIStr("rg1"))
+	require.False(t, ok)
+	tk.MustGetErrCode("drop resource group rg1", errno.ErrResourceGroupNotExists)
+	tk.MustExec("drop resource group if exists rg1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 8258 Unknown resource group 'rg1'"))
+}
+
+func TestResourceGroupRunawayWatch(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create resource group rg1 ru_per_sec = 1000 query_limit = (exec_elapsed = '1.5s' action = kill)")
+	group, ok := dom.InfoSchema().ResourceGroupByName(model.NewCIStr("rg1"))
+	require.True(t, ok)
+	require.Equal(t, uint64(1500), group.ExecElapsedTimeMs)
+	require.Equal(t, model.RunawayActionKill, group.RunawayAction)
+	execElapsed, action := resourcegroup.GlobalController.QueryLimit("rg1")
+	require.Equal(t, 1500*time.Millisecond, execElapsed)
+	require.Equal(t, model.RunawayActionKill, action)
+
+	tk.MustExec("alter resource group rg1 query_limit = (exec_elapsed = '10s' action = cooldown)")
+	execElapsed, action = resourcegroup.GlobalController.QueryLimit("rg1")
+	require.Equal(t, 10*time.Second, execElapsed)
+	require.Equal(t, model.RunawayActionCooldown, action)
+	// Altering the group without QUERY_LIMIT removes it.
+	tk.MustExec("alter resource group rg1 ru_per_sec = 1000")
+	execElapsed, _ = resourcegroup.GlobalController.QueryLimit("rg1")
+	require.Equal(t, time.Duration(0), execElapsed)
+
+	// The runaway queries are persisted into mysql.runaway_watch.
+	startTime := time.Date(2022, 7, 1, 10, 0, 0, 0, time.Local)
+	resourcegroup.GlobalController.RecordRunaway(&resourcegroup.RunawayRecord{
+		ResourceGroupName: "rg1",
+		User:              "root",
+		Host:              "localhost",
+		SQLDigest:         "digest",
+		SQLText:           "select sleep(20)",
+		ExecElapsed:       10 * time.Second,
+		Action:            model.RunawayActionCooldown,
+		StartTime:         startTime,
+		WatchTime:         startTime.Add(10 * time.Second),
+	})
+	require.Eventually(t, func() bool {
+		return len(tk.MustQuery("select * from mysql.runaway_watch").Rows()) > 0
+	}, 5*time.Second, 50*time.Millisecond)
+	tk.MustQuery("select * from mysql.runaway_watch").Check(testkit.Rows(
+		"rg1 root localhost digest select sleep(20) 10000 COOLDOWN 2022-07-01 10:00:00.000000 2022-07-01 10:00:10.000000"))
+	tk.MustExec("drop resource group rg1")
+}
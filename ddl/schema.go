@@ -20,6 +20,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/ddl/label"
+	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta"
@@ -257,6 +258,125 @@ func onDropSchema(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error)
 	return ver, errors.Trace(err)
 }
 
+func (w *worker) onRecoverSchema(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var (
+		recoverSchemaInfo      *RecoverSchemaInfo
+		recoverSchemaCheckFlag int64
+	)
+	const checkFlagIndexInJobArgs = 1 // The index of `recoverSchemaCheckFlag` in job arg list.
+	if err := job.DecodeArgs(&recoverSchemaInfo, &recoverSchemaCheckFlag); err != nil {
+		// Invalid arguments, cancel this job.
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	schemaInfo := recoverSchemaInfo.DBInfo
+
+	// check GC and safe point
+	gcEnable, err := checkGCEnable(w)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	err = checkSchemaNotExists(d, t, schemaInfo.ID, schemaInfo)
+	if err == nil {
+		var oldInfo *model.DBInfo
+		oldInfo, err = t.GetDatabase(schemaInfo.ID)
+		if err == nil && oldInfo != nil {
+			err = infoschema.ErrDatabaseExists.GenWithStackByArgs(oldInfo.Name)
+		}
+	}
+	if err != nil {
+		if infoschema.ErrDatabaseExists.Equal(err) {
+			job.State = model.JobStateCancelled
+		}
+		return ver, errors.Trace(err)
+	}
+
+	// Recover schema is divided into 2 steps like recover table, see the comments in `onRecoverTable`.
+	switch schemaInfo.State {
+	case model.StateNone:
+		// none -> write only
+		// check GC enable and update flag.
+		if gcEnable {
+			job.Args[checkFlagIndexInJobArgs] = recoverTableCheckFlagEnableGC
+		} else {
+			job.Args[checkFlagIndexInJobArgs] = recoverTableCheckFlagDisableGC
+		}
+
+		var bundles []*placement.Bundle
+		for _, recoverInfo := range recoverSchemaInfo.RecoverTabsInfo {
+			tblBundles, err := placement.NewFullTableBundles(t, recoverInfo.TableInfo)
+			if err != nil {
+				job.State = model.JobStateCancelled
+				return ver, errors.Trace(err)
+			}
+			bundles = append(bundles, tblBundles...)
+		}
+
+		// Send the placement bundles to PD.
+		err = infosync.PutRuleBundlesWithDefaultRetry(context.TODO(), bundles)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+
+		schemaInfo.State = model.StateWriteOnly
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> public
+		// do recover schema and tables.
+		if gcEnable {
+			err = disableGC(w)
+			if err != nil {
+				job.State = model.JobStateCancelled
+				return ver, errors.Errorf("disable gc failed, try again later. err: %v", err)
+			}
+		}
+		// check GC safe point
+		err = checkSafePoint(w, recoverSchemaInfo.SnapshotTS)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+
+		tblInfos := make([]*model.TableInfo, 0, len(recoverSchemaInfo.RecoverTabsInfo))
+		for _, recoverInfo := range recoverSchemaInfo.RecoverTabsInfo {
+			tblInfos = append(tblInfos, recoverInfo.TableInfo)
+		}
+		// Remove dropped schema DDL job from gc_delete_range table.
+		err = w.delRangeManager.removeFromGCDeleteRange(w.ddlJobCtx, recoverSchemaInfo.DropJobID, getIDs(tblInfos))
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+
+		schemaInfo.State = model.StatePublic
+		err = t.CreateDatabase(schemaInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		for _, recoverInfo := range recoverSchemaInfo.RecoverTabsInfo {
+			tblInfo := recoverInfo.TableInfo
+			tblInfo.State = model.StatePublic
+			tblInfo.UpdateTS = t.StartTS
+			err = t.CreateTableAndSetAutoID(schemaInfo.ID, tblInfo, recoverInfo.AutoIDs.RowID, recoverInfo.AutoIDs.RandomID)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+		}
+
+		ver, err = updateSchemaVersion(t, job)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Finish this job.
+		job.FinishDBJob(model.JobStateDone, model.StatePublic, ver, schemaInfo)
+	default:
+		return ver, ErrInvalidDDLState.GenWithStackByArgs("schema", schemaInfo.State)
+	}
+	return ver, nil
+}
+
 func checkSchemaExistAndCancelNotExistJob(t *meta.Meta, job *model.Job) (*model.DBInfo, error) {
 	dbInfo, err := t.GetDatabase(job.SchemaID)
 	if err != nil {
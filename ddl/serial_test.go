@@ -1215,6 +1215,11 @@ func (s *testSerialDBSuite) TestAutoRandom(c *C) {
 	assertPKIsNotHandle("create table t (a bigint auto_random(3), b bigint, primary key (a, b))", "a")
 	assertPKIsNotHandle("create table t (a bigint auto_random(3), b int, c char, primary key (a, c))", "a")
 
+	// PKIsNotHandle: auto_random is not the first column of the clustered composite primary key.
+	assertPKIsNotHandle("create table t (a bigint, b bigint auto_random(3), primary key (a, b) clustered)", "b")
+	assertPKIsNotHandle("create table t (a bigint auto_random(3), b bigint, primary key (b, a) clustered)", "a")
+	assertPKIsNotHandle("create table t (a bigint auto_random(3), b bigint, primary key (a, b) nonclustered)", "a")
+
 	// PKIsNotHandle: nonclustered integer primary key.
 	assertPKIsNotHandle("create table t (a bigint auto_random(3) primary key nonclustered, b int)", "a")
 	assertPKIsNotHandle("create table t (a bigint auto_random(3) primary key nonclustered, b int)", "a")
@@ -1252,6 +1257,7 @@ func (s *testSerialDBSuite) TestAutoRandom(c *C) {
 	mustExecAndDrop("create table t (a bigint auto_random(15) primary key)")
 	mustExecAndDrop("create table t (a bigint primary key auto_random(4))")
 	mustExecAndDrop("create table t (a bigint auto_random(4), primary key (a))")
+	mustExecAndDrop("create table t (a bigint auto_random(4), b varchar(255), primary key (a, b) clustered)")
 
 	// Increase auto_random bits.
 	mustExecAndDrop("create table t (a bigint auto_random(5) primary key)", func() {
@@ -1259,6 +1265,11 @@ func (s *testSerialDBSuite) TestAutoRandom(c *C) {
 		tk.MustExec("alter table t modify a bigint auto_random(10)")
 		tk.MustExec("alter table t modify a bigint auto_random(12)")
 	})
+	mustExecAndDrop("create table t (a bigint auto_random(5), b int, primary key (a, b) clustered)", func() {
+		tk.MustExec("alter table t modify a bigint auto_random(8)")
+		assertDecreaseBitErr("alter table t modify a bigint auto_random(6)")
+		assertAlterValue("alter table t modify a bigint")
+	})
 
 	// Auto_random can occur multiple times like other column attributes.
 	mustExecAndDrop("create table t (a bigint auto_random(3) auto_random(2) primary key)")
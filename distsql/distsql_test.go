@@ -62,7 +62,9 @@ func TestSelectNormal(t *testing.T) {
 }
 
 func TestSelectMemTracker(t *testing.T) {
-	response, colTypes := createSelectNormal(t, 2, 6, nil, nil)
+	sctx := newMockSessionContext()
+	sctx.GetSessionVars().InitChunkSize = 32
+	response, colTypes := createSelectNormal(t, 2, 6, nil, sctx)
 
 	// Test Next.
 	chk := chunk.New(colTypes, 3, 3)
@@ -82,6 +84,31 @@ func TestSelectNormalChunkSize(t *testing.T) {
 	require.Equal(t, int64(0), response.memTracker.BytesConsumed())
 }
 
+func TestSelectChunkRPCReferenceResponse(t *testing.T) {
+	sctx := newMockSessionContext()
+	sctx.GetSessionVars().InitChunkSize = 32
+	sctx.GetSessionVars().MaxChunkSize = 1024
+
+	// The response chunks are big enough, they are referenced without being spliced together.
+	response, colTypes := createSelectNormal(t, 40, 400, nil, sctx)
+	chk := chunk.New(colTypes, 1024, 1024)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, response.Next(context.TODO(), chk))
+		require.Equal(t, 40, chk.NumRows())
+		require.Equal(t, int64(123), chk.GetRow(39).GetInt64(3))
+	}
+	require.NoError(t, response.Next(context.TODO(), chk))
+	require.Equal(t, 0, chk.NumRows())
+	require.NoError(t, response.Close())
+
+	// The small response chunks are spliced into one chunk.
+	response, colTypes = createSelectNormal(t, 8, 400, nil, sctx)
+	chk = chunk.New(colTypes, 1024, 1024)
+	require.NoError(t, response.Next(context.TODO(), chk))
+	require.Equal(t, 400, chk.NumRows())
+	require.NoError(t, response.Close())
+}
+
 func TestSelectWithRuntimeStats(t *testing.T) {
 	planIDs := []int{1, 2, 3}
 	response, colTypes := createSelectNormal(t, 1, 2, planIDs, nil)
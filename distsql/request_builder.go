@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -36,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/ranger"
+	"github.com/pingcap/tidb/util/resourcegroup"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
@@ -216,10 +218,14 @@ func (builder *RequestBuilder) SetAllowBatchCop(batchCop bool) *RequestBuilder {
 	return builder
 }
 
-func (builder *RequestBuilder) getIsolationLevel() kv.IsoLevel {
+func (builder *RequestBuilder) getIsolationLevel(sv *variable.SessionVars) kv.IsoLevel {
 	switch builder.Tp {
 	case kv.ReqTypeAnalyze:
 		return kv.RC
+	case kv.ReqTypeDAG:
+		if sv.StmtCtx.RCCheckTS {
+			return kv.RCCheckTS
+		}
 	}
 	return kv.SI
 }
@@ -227,6 +233,13 @@ func (builder *RequestBuilder) getIsolationLevel() kv.IsoLevel {
 func (builder *RequestBuilder) getKVPriority(sv *variable.SessionVars) int {
 	switch sv.StmtCtx.Priority {
 	case mysql.NoPriority, mysql.DelayedPriority:
+		// The statements without priority hints use the priority of their resource groups.
+		switch resourcegroup.GlobalController.Priority(sv.ResourceGroupName) {
+		case model.ResourceGroupPriorityLow:
+			return kv.PriorityLow
+		case model.ResourceGroupPriorityHigh:
+			return kv.PriorityHigh
+		}
 		return kv.PriorityNormal
 	case mysql.LowPriority:
 		return kv.PriorityLow
@@ -237,18 +250,24 @@ func (builder *RequestBuilder) getKVPriority(sv *variable.SessionVars) int {
 }
 
 // SetFromSessionVars sets the following fields for "kv.Request" from session variables:
-// "Concurrency", "IsolationLevel", "NotFillCache", "TaskID", "Priority", "ReplicaRead", "ResourceGroupTagger".
+// "Concurrency", "IsolationLevel", "NotFillCache", "TaskID", "Priority", "ReplicaRead", "ResourceGroupTagger",
+// "ResourceGroupName", "RunawayCooldown".
 func (builder *RequestBuilder) SetFromSessionVars(sv *variable.SessionVars) *RequestBuilder {
 	if builder.Request.Concurrency == 0 {
 		// Concurrency may be set to 1 by SetDAGRequest
 		builder.Request.Concurrency = sv.DistSQLScanConcurrency()
 	}
-	builder.Request.IsolationLevel = builder.getIsolationLevel()
+	builder.Request.IsolationLevel = builder.getIsolationLevel(sv)
 	builder.Request.NotFillCache = sv.StmtCtx.NotFillCache
 	builder.Request.TaskID = sv.StmtCtx.TaskID
 	builder.Request.Priority = builder.getKVPriority(sv)
 	builder.Request.ReplicaRead = sv.GetReplicaRead()
 	builder.SetResourceGroupTagger(sv.StmtCtx)
+	builder.Request.ResourceGroupName = sv.ResourceGroupName
+	if len(sv.ResourceGroupName) > 0 {
+		// Only the queries of the non-default resource groups may be runaway queries.
+		builder.Request.RunawayCooldown = &sv.StmtCtx.RunawayCooldown
+	}
 	return builder
 }
 
@@ -332,9 +332,9 @@ func (r *selectResult) readFromChunk(ctx context.Context, chk *chunk.Chunk) erro
 		if r.respChunkDecoder.IsFinished() {
 			r.respChunkDecoder.Reset(r.selectResp.Chunks[r.respChkIdx].RowsData)
 		}
-		// If the next chunk size is greater than required rows * 0.8, reuse the memory of the next chunk and return
-		// immediately. Otherwise, splice the data to one chunk and wait the next chunk.
-		if r.respChunkDecoder.RemainedRows() > int(float64(chk.RequiredRows())*0.8) {
+		// If the next chunk is big enough, reference its memory directly and return immediately, the data is not
+		// copied in this case. Otherwise, splice the data to one chunk and wait the next chunk.
+		if r.respChunkDecoder.RemainedRows() > r.minReferencedRows(chk) {
 			if chk.NumRows() > 0 {
 				return nil
 			}
@@ -350,6 +350,17 @@ func (r *selectResult) readFromChunk(ctx context.Context, chk *chunk.Chunk) erro
 	return nil
 }
 
+// minReferencedRows returns the minimal number of rows which makes a response chunk be referenced by chk without
+// copying. Small response chunks, which are common for the first batches of a scan and for paging requests, are still
+// spliced together to avoid passing too many small chunks to the parent executors.
+func (r *selectResult) minReferencedRows(chk *chunk.Chunk) int {
+	minRows := int(float64(chk.RequiredRows()) * 0.8)
+	if initChunkSize := r.ctx.GetSessionVars().InitChunkSize; minRows >= initChunkSize {
+		minRows = initChunkSize - 1
+	}
+	return minRows
+}
+
 func (r *selectResult) updateCopRuntimeStats(ctx context.Context, copStats *copr.CopRuntimeStats, respTime time.Duration) {
 	callee := copStats.CalleeAddress
 	if r.rootPlanID <= 0 || r.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl == nil || callee == "" {
@@ -55,6 +55,7 @@ import (
 	"github.com/pingcap/tidb/util/domainutil"
 	"github.com/pingcap/tidb/util/expensivequery"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/resourcegroup"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/tikv/client-go/v2/txnkv/transaction"
 	"go.etcd.io/etcd/clientv3"
@@ -82,6 +83,7 @@ type Domain struct {
 	exit                 chan struct{}
 	etcdClient           *clientv3.Client
 	sysVarCache          sysVarCache // replaces GlobalVariableCache
+	userFunctions        userFunctionCache
 	slowQuery            *topNSlowQueries
 	expensiveQueryHandle *expensivequery.Handle
 	wg                   sync.WaitGroup
@@ -89,6 +91,8 @@ type Domain struct {
 	cancel               context.CancelFunc
 	indexUsageSyncLease  time.Duration
 	planReplayer         *planReplayer
+	planReplayerCapture  *planReplayerCapture
+	storeVersion         storeVersionChecker
 
 	serverID             uint64
 	serverIDSession      *concurrency.Session
@@ -160,7 +164,12 @@ func (do *Domain) loadInfoSchema(startTS uint64) (infoschema.InfoSchema, bool, i
 		return nil, false, currentSchemaVersion, nil, err
 	}
 
-	newISBuilder, err := infoschema.NewBuilder(do.Store(), do.renewLeaseCh, do.sysFacHack).InitWithDBInfos(schemas, bundles, policies, neededSchemaVersion)
+	resourceGroups, err := m.ListResourceGroups()
+	if err != nil {
+		return nil, false, currentSchemaVersion, nil, err
+	}
+
+	newISBuilder, err := infoschema.NewBuilder(do.Store(), do.renewLeaseCh, do.sysFacHack).InitWithDBInfos(schemas, bundles, policies, resourceGroups, neededSchemaVersion)
 	if err != nil {
 		return nil, false, currentSchemaVersion, nil, err
 	}
@@ -416,6 +425,8 @@ func (do *Domain) Reload() error {
 		}
 	}
 
+	resourcegroup.GlobalController.Update(is.AllResourceGroups())
+
 	// lease renew, so it must be executed despite it is cache or not
 	do.SchemaValidator.Update(ver.Ver, oldSchemaVersion, is.SchemaMetaVersion(), changes)
 	lease := do.DDL().GetLease()
@@ -689,6 +700,7 @@ func (do *Domain) Close() {
 	do.cancel()
 	do.wg.Wait()
 	do.sysSessionPool.Close()
+	do.userFunctions.close()
 	variable.UnregisterStatistics(do.bindHandle)
 	if do.onClose != nil {
 		do.onClose()
@@ -710,6 +722,7 @@ func NewDomain(store kv.Storage, ddlLease time.Duration, statsLease time.Duratio
 		slowQuery:           newTopNSlowQueries(30, time.Hour*24*7, 500),
 		indexUsageSyncLease: idxUsageSyncLease,
 		planReplayer:        &planReplayer{planReplayerGCLease: planReplayerGCLease},
+		planReplayerCapture: newPlanReplayerCapture(),
 		onClose:             onClose,
 		renewLeaseCh:        make(chan func(), 10),
 	}
@@ -1039,6 +1052,54 @@ func (do *Domain) LoadSysVarCacheLoop(ctx sessionctx.Context) error {
 	return nil
 }
 
+// LoadUserFunctionsLoop create a goroutine loads the user-defined functions in a loop,
+// it should be called only once in BootstrapSession.
+func (do *Domain) LoadUserFunctionsLoop(ctx sessionctx.Context) error {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	err := do.rebuildUserFunctionCache(ctx)
+	if err != nil {
+		return err
+	}
+	var watchCh clientv3.WatchChan
+	duration := 30 * time.Second
+	if do.etcdClient != nil {
+		watchCh = do.etcdClient.Watch(context.Background(), userFunctionsKey)
+	}
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("LoadUserFunctionsLoop exited.")
+			util.Recover(metrics.LabelDomain, "LoadUserFunctionsLoop", nil, false)
+		}()
+		var count int
+		for {
+			ok := true
+			select {
+			case <-do.exit:
+				return
+			case _, ok = <-watchCh:
+			case <-time.After(duration):
+			}
+			if !ok {
+				logutil.BgLogger().Error("LoadUserFunctionsLoop loop watch channel closed")
+				watchCh = do.etcdClient.Watch(context.Background(), userFunctionsKey)
+				count++
+				if count > 10 {
+					time.Sleep(time.Duration(count) * time.Second)
+				}
+				continue
+			}
+			count = 0
+			err := do.rebuildUserFunctionCache(ctx)
+			if err != nil {
+				logutil.BgLogger().Error("LoadUserFunctionsLoop failed", zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
 // PrivilegeHandle returns the MySQLPrivilege.
 func (do *Domain) PrivilegeHandle() *privileges.Handle {
 	return do.privHandle
@@ -1211,6 +1272,44 @@ func (do *Domain) PlanReplayerLoop() {
 	}()
 }
 
+// RunawayRecordLoop creates a goroutine that persists the runaway queries into mysql.runaway_watch.
+func (do *Domain) RunawayRecordLoop() {
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("RunawayRecordLoop exited.")
+			util.Recover(metrics.LabelDomain, "RunawayRecordLoop", nil, false)
+		}()
+		recordCh := resourcegroup.GlobalController.RunawayRecords()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case record := <-recordCh:
+				if err := do.persistRunawayRecord(record); err != nil {
+					logutil.BgLogger().Warn("persist runaway record failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+func (do *Domain) persistRunawayRecord(record *resourcegroup.RunawayRecord) error {
+	sysSessionPool := do.SysSessionPool()
+	ctx, err := sysSessionPool.Get()
+	if err != nil {
+		return err
+	}
+	defer sysSessionPool.Put(ctx)
+	_, err = ctx.(sqlexec.SQLExecutor).ExecuteInternal(context.Background(),
+		`INSERT INTO mysql.runaway_watch (resource_group_name, user, host, sql_digest, sql_text, exec_elapsed_ms, action, start_time, watch_time)
+		VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?)`,
+		record.ResourceGroupName, record.User, record.Host, record.SQLDigest, record.SQLText,
+		record.ExecElapsed.Milliseconds(), record.Action.String(), record.StartTime, record.WatchTime)
+	return err
+}
+
 // StatsHandle returns the statistic handle.
 func (do *Domain) StatsHandle() *handle.Handle {
 	return (*handle.Handle)(atomic.LoadPointer(&do.statsHandle))
@@ -1399,6 +1498,12 @@ func (do *Domain) updateStatsWorker(ctx sessionctx.Context, owner owner.Manager)
 				logutil.BgLogger().Debug("dump stats delta failed", zap.Error(err))
 			}
 			statsHandle.UpdateErrorRate(do.InfoSchema())
+			if err := statsHandle.DumpColStatsUsageToKV(); err != nil {
+				logutil.BgLogger().Debug("dump column stats usage failed", zap.Error(err))
+			}
+			if err := statsHandle.DumpAnalyzeJobProgress(); err != nil {
+				logutil.BgLogger().Debug("dump analyze job progress failed", zap.Error(err))
+			}
 		case <-loadFeedbackTicker.C:
 			statsHandle.UpdateStatsByLocalFeedback(do.InfoSchema())
 			if !owner.IsOwner() {
@@ -1421,6 +1526,9 @@ func (do *Domain) updateStatsWorker(ctx sessionctx.Context, owner owner.Manager)
 			if err != nil {
 				logutil.BgLogger().Debug("GC stats failed", zap.Error(err))
 			}
+			if err := statsHandle.DeleteAnalyzeJobs(time.Now().Add(-handle.AnalyzeJobsRetention)); err != nil {
+				logutil.BgLogger().Debug("delete analyze jobs failed", zap.Error(err))
+			}
 		}
 	}
 }
@@ -1452,8 +1560,9 @@ func (do *Domain) ExpensiveQueryHandle() *expensivequery.Handle {
 }
 
 const (
-	privilegeKey   = "/tidb/privilege"
-	sysVarCacheKey = "/tidb/sysvars"
+	privilegeKey     = "/tidb/privilege"
+	sysVarCacheKey   = "/tidb/sysvars"
+	userFunctionsKey = "/tidb/user_functions"
 )
 
 // NotifyUpdatePrivilege updates privilege key in etcd, TiDB client that watches
@@ -1504,6 +1613,20 @@ func (do *Domain) NotifyUpdateSysVarCache() {
 	}
 }
 
+// NotifyUpdateUserFunctions updates the user functions key in etcd, so that other TiDB clients reload
+// the user-defined functions. For the caller, the functions are reloaded synchronously.
+func (do *Domain) NotifyUpdateUserFunctions() error {
+	if do.etcdClient != nil {
+		row := do.etcdClient.KV
+		_, err := row.Put(context.Background(), userFunctionsKey, "")
+		if err != nil {
+			logutil.BgLogger().Warn("notify update user functions failed", zap.Error(err))
+		}
+	}
+	// update locally
+	return do.rebuildUserFunctionCache(nil)
+}
+
 // ServerID gets serverID.
 func (do *Domain) ServerID() uint64 {
 	return atomic.LoadUint64(&do.serverID)
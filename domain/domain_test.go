@@ -358,6 +358,15 @@ func SubTestDomain(t *testing.T) {
 	infoSyncer.ReportMinStartTS(dom.Store())
 	require.Equal(t, validTS, infoSyncer.GetMinStartTS())
 
+	// The session which sets tidb_session_gc_life_time holds the min start timestamp back.
+	sm.PS = append(sm.PS, &util.ProcessInfo{GCLifeTime: 48 * time.Hour})
+	infoSyncer.SetSessionManager(sm)
+	beforeTS = oracle.GoTimeToTS(time.Now().Add(-48 * time.Hour))
+	infoSyncer.ReportMinStartTS(dom.Store())
+	afterTS = oracle.GoTimeToTS(time.Now().Add(-48 * time.Hour))
+	require.GreaterOrEqual(t, infoSyncer.GetMinStartTS(), beforeTS)
+	require.LessOrEqual(t, infoSyncer.GetMinStartTS(), afterTS)
+
 	err = store.Close()
 	require.NoError(t, err)
 
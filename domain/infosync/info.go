@@ -611,6 +611,13 @@ func (is *InfoSyncer) ReportMinStartTS(store kv.Storage) {
 		if info.CurTxnStartTS > startTSLowerLimit && info.CurTxnStartTS < minStartTS {
 			minStartTS = info.CurTxnStartTS
 		}
+		// The sessions which set tidb_session_gc_life_time keep the versions within it, which may be older
+		// than the lower limit.
+		if info.GCLifeTime > 0 {
+			if ts := oracle.GoTimeToTS(now.Add(-info.GCLifeTime)); ts < minStartTS {
+				minStartTS = ts
+			}
+		}
 	}
 
 	is.minStartTS = minStartTS
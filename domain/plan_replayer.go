@@ -25,6 +25,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
 )
@@ -85,3 +90,196 @@ func (p *planReplayer) planReplayerGC(t time.Duration) {
 		}
 	}
 }
+
+const (
+	// PlanReplayerCaptureReasonSlow means the statement is captured because its latency exceeds the threshold.
+	PlanReplayerCaptureReasonSlow = "slow"
+	// PlanReplayerCaptureReasonPlanChange means the statement is captured because its plan deviates from the
+	// baseline plan, which is the first plan seen of the statement.
+	PlanReplayerCaptureReasonPlanChange = "plan_change"
+
+	// planReplayerCaptureMaxStatements is the max number of the statements whose baseline plans are kept, all the
+	// baselines are reset once it's exceeded.
+	planReplayerCaptureMaxStatements = 10000
+	// planReplayerCaptureQueueSize is the size of the capture queue, the tasks are dropped when the queue is full.
+	planReplayerCaptureQueueSize = 16
+)
+
+// PlanReplayerCaptureTask is a statement captured to be dumped into a plan replayer zip in the background.
+// The session related information is collected when the statement finishes, since the session may run other
+// statements or be closed when the task is handled.
+type PlanReplayerCaptureTask struct {
+	SQLDigest   string
+	PlanDigest  string
+	Reason      string
+	Latency     time.Duration
+	CaptureTime time.Time
+	DB          string
+	Stmt        ast.StmtNode
+	// Variables is the values of the system variables of the session.
+	Variables map[string]string
+	// SessionBindings is the rows of `show bindings` of the session.
+	SessionBindings [][]string
+	// Plan is the plan executed by the statement.
+	Plan string
+}
+
+// PlanReplayerCaptureRecord is a statement captured into a plan replayer zip.
+type PlanReplayerCaptureRecord struct {
+	SQLDigest   string
+	PlanDigest  string
+	Reason      string
+	Latency     time.Duration
+	CaptureTime time.Time
+	DB          string
+	SQL         string
+	// FileName is the name of the zip in the plan replayer directory, it's empty if the dump fails.
+	FileName string
+	Err      error
+}
+
+// PlanReplayerCaptureDumpFunc dumps the captured statement into a plan replayer zip and returns the file name.
+type PlanReplayerCaptureDumpFunc func(sctx sessionctx.Context, task *PlanReplayerCaptureTask) (string, error)
+
+type planReplayerCapture struct {
+	sync.Mutex
+	// baselines is the first plan digest seen of every sql digest.
+	baselines map[string]string
+	// captured is the (sql digest, plan digest) pairs captured, every plan of a statement is captured only once.
+	captured map[[2]string]struct{}
+	records  []*PlanReplayerCaptureRecord
+	taskCh   chan *PlanReplayerCaptureTask
+}
+
+func newPlanReplayerCapture() *planReplayerCapture {
+	return &planReplayerCapture{
+		baselines: make(map[string]string),
+		captured:  make(map[[2]string]struct{}),
+		taskCh:    make(chan *PlanReplayerCaptureTask, planReplayerCaptureQueueSize),
+	}
+}
+
+// check returns the reason to capture the statement, or an empty string if it needn't be captured.
+func (c *planReplayerCapture) check(sqlDigest, planDigest string, latency, threshold time.Duration) string {
+	c.Lock()
+	defer c.Unlock()
+	key := [2]string{sqlDigest, planDigest}
+	if _, ok := c.captured[key]; ok {
+		return ""
+	}
+	reason := ""
+	baseline, ok := c.baselines[sqlDigest]
+	if !ok {
+		if len(c.baselines) >= planReplayerCaptureMaxStatements {
+			c.baselines = make(map[string]string)
+			c.captured = make(map[[2]string]struct{})
+		}
+		c.baselines[sqlDigest] = planDigest
+	} else if baseline != planDigest {
+		reason = PlanReplayerCaptureReasonPlanChange
+	}
+	if reason == "" && threshold > 0 && latency >= threshold {
+		reason = PlanReplayerCaptureReasonSlow
+	}
+	if reason != "" {
+		c.captured[key] = struct{}{}
+	}
+	return reason
+}
+
+// addRecord adds the record of a captured statement and removes the oldest zips beyond the limit.
+func (c *planReplayerCapture) addRecord(record *PlanReplayerCaptureRecord, maxFiles int) {
+	c.Lock()
+	defer c.Unlock()
+	c.records = append(c.records, record)
+	for len(c.records) > maxFiles {
+		if name := c.records[0].FileName; name != "" {
+			err := os.Remove(filepath.Join(GetPlanReplayerDirName(), name))
+			if err != nil && !os.IsNotExist(err) {
+				logutil.BgLogger().Warn("[PlanReplayer] remove captured file failed", zap.Error(err))
+			}
+		}
+		c.records = c.records[1:]
+	}
+}
+
+// CheckPlanReplayerCapture returns the reason to capture the finished statement into a plan replayer zip, or an
+// empty string if it needn't be captured.
+func (do *Domain) CheckPlanReplayerCapture(sqlDigest, planDigest string, latency time.Duration) string {
+	threshold := time.Duration(variable.PlanReplayerCaptureThreshold.Load()) * time.Millisecond
+	return do.planReplayerCapture.check(sqlDigest, planDigest, latency, threshold)
+}
+
+// SendPlanReplayerCaptureTask sends the task to the capture loop, the task is dropped if the loop is busy.
+func (do *Domain) SendPlanReplayerCaptureTask(task *PlanReplayerCaptureTask) {
+	select {
+	case do.planReplayerCapture.taskCh <- task:
+	default:
+		logutil.BgLogger().Info("[PlanReplayer] drop the capture task since the queue is full",
+			zap.String("sqlDigest", task.SQLDigest), zap.String("planDigest", task.PlanDigest))
+	}
+}
+
+// GetPlanReplayerCaptureRecords returns the records of the captured statements whose zips are still kept.
+func (do *Domain) GetPlanReplayerCaptureRecords() []*PlanReplayerCaptureRecord {
+	do.planReplayerCapture.Lock()
+	defer do.planReplayerCapture.Unlock()
+	records := make([]*PlanReplayerCaptureRecord, 0, len(do.planReplayerCapture.records))
+	for _, record := range do.planReplayerCapture.records {
+		// The zip may be removed by the GC or downloaded.
+		if record.FileName != "" {
+			if _, err := os.Stat(filepath.Join(GetPlanReplayerDirName(), record.FileName)); err != nil {
+				continue
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// PlanReplayerCaptureLoop creates a goroutine that dumps the captured statements by dumpFunc.
+func (do *Domain) PlanReplayerCaptureLoop(dumpFunc PlanReplayerCaptureDumpFunc) {
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("PlanReplayerCaptureLoop exited.")
+			util.Recover(metrics.LabelDomain, "PlanReplayerCaptureLoop", nil, false)
+		}()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case task := <-do.planReplayerCapture.taskCh:
+				do.handlePlanReplayerCaptureTask(task, dumpFunc)
+			}
+		}
+	}()
+}
+
+func (do *Domain) handlePlanReplayerCaptureTask(task *PlanReplayerCaptureTask, dumpFunc PlanReplayerCaptureDumpFunc) {
+	record := &PlanReplayerCaptureRecord{
+		SQLDigest:   task.SQLDigest,
+		PlanDigest:  task.PlanDigest,
+		Reason:      task.Reason,
+		Latency:     task.Latency,
+		CaptureTime: task.CaptureTime,
+		DB:          task.DB,
+		SQL:         task.Stmt.Text(),
+	}
+	se, err := do.sysSessionPool.Get()
+	if err == nil {
+		record.FileName, record.Err = dumpFunc(se.(sessionctx.Context), task)
+		do.sysSessionPool.Put(se)
+	} else {
+		record.Err = err
+	}
+	if record.Err != nil {
+		logutil.BgLogger().Warn("[PlanReplayer] capture the statement failed", zap.String("sqlDigest", task.SQLDigest),
+			zap.String("planDigest", task.PlanDigest), zap.Error(record.Err))
+	} else {
+		logutil.BgLogger().Info("[PlanReplayer] capture the statement", zap.String("sqlDigest", task.SQLDigest),
+			zap.String("planDigest", task.PlanDigest), zap.String("reason", task.Reason), zap.String("file", record.FileName))
+	}
+	do.planReplayerCapture.addRecord(record, int(variable.PlanReplayerCaptureMaxFiles.Load()))
+}
@@ -58,3 +58,42 @@ func TestPlanReplayerParseTime(t *testing.T) {
 	_, err = parseTime(name3)
 	require.NotNil(t, err)
 }
+
+func TestPlanReplayerCaptureCheck(t *testing.T) {
+	c := newPlanReplayerCapture()
+	// The first plan is the baseline, it's captured only when it's slow.
+	require.Equal(t, "", c.check("s1", "p1", time.Second, 2*time.Second))
+	require.Equal(t, PlanReplayerCaptureReasonSlow, c.check("s1", "p1", 3*time.Second, 2*time.Second))
+	// Every plan is captured only once.
+	require.Equal(t, "", c.check("s1", "p1", 3*time.Second, 2*time.Second))
+	require.Equal(t, PlanReplayerCaptureReasonPlanChange, c.check("s1", "p2", time.Second, 2*time.Second))
+	require.Equal(t, "", c.check("s1", "p2", 3*time.Second, 2*time.Second))
+	// The latency isn't checked if the threshold is 0.
+	require.Equal(t, "", c.check("s2", "p1", time.Hour, 0))
+	require.Equal(t, PlanReplayerCaptureReasonPlanChange, c.check("s2", "p2", time.Second, 0))
+}
+
+func TestPlanReplayerCaptureRetention(t *testing.T) {
+	err := os.MkdirAll(GetPlanReplayerDirName(), os.ModePerm)
+	require.NoError(t, err)
+	c := newPlanReplayerCapture()
+	var paths []string
+	for i := 0; i < 3; i++ {
+		fileName := fmt.Sprintf("capture_replayer_xxxxxx%d_%v.zip", i, time.Now().UnixNano())
+		path := filepath.Join(GetPlanReplayerDirName(), fileName)
+		zf, err := os.Create(path)
+		require.NoError(t, err)
+		require.NoError(t, zf.Close())
+		paths = append(paths, path)
+		c.addRecord(&PlanReplayerCaptureRecord{FileName: fileName}, 2)
+	}
+	// The oldest zip is removed.
+	require.Len(t, c.records, 2)
+	_, err = os.Stat(paths[0])
+	require.True(t, os.IsNotExist(err))
+	for _, path := range paths[1:] {
+		_, err = os.Stat(path)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(path))
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb/ddl/placement"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/tikv/client-go/v2/tikv"
+	"go.uber.org/zap"
+)
+
+// storeVersionCheckInterval is the min interval between two checks of the store versions.
+var storeVersionCheckInterval = time.Minute
+
+// minAsyncCommitStoreVersion is the min TiKV version which supports async commit and one-phase commit.
+var minAsyncCommitStoreVersion = semver.Version{Major: 5}
+
+// storeVersionChecker caches whether all the TiKV stores support async commit and one-phase commit, the stores are
+// fetched from PD at most once per storeVersionCheckInterval.
+type storeVersionChecker struct {
+	sync.Mutex
+	lastCheck   time.Time
+	unsupported bool
+}
+
+// AsyncCommitSupported returns false if any TiKV store is older than the version which supports async commit and
+// one-phase commit, e.g. during a rolling upgrade, in which case the transactions should fall back to the normal 2PC.
+// It returns true if the stores can't be fetched.
+func (do *Domain) AsyncCommitSupported() bool {
+	c := &do.storeVersion
+	c.Lock()
+	defer c.Unlock()
+	if time.Since(c.lastCheck) < storeVersionCheckInterval {
+		return !c.unsupported
+	}
+	c.lastCheck = time.Now()
+	tikvStore, ok := do.store.(tikv.Storage)
+	if !ok || tikvStore.GetRegionCache() == nil || tikvStore.GetRegionCache().PDClient() == nil {
+		return !c.unsupported
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	stores, err := tikvStore.GetRegionCache().PDClient().GetAllStores(ctx)
+	if err != nil {
+		logutil.BgLogger().Warn("get stores failed when checking the store versions", zap.Error(err))
+		return !c.unsupported
+	}
+	store := findStoreWithoutAsyncCommit(stores)
+	if store != nil && !c.unsupported {
+		logutil.BgLogger().Warn("async commit and one-phase commit are disabled because the store doesn't support them",
+			zap.Uint64("storeID", store.GetId()), zap.String("address", store.GetAddress()), zap.String("version", store.GetVersion()))
+	} else if store == nil && c.unsupported {
+		logutil.BgLogger().Info("async commit and one-phase commit are enabled again because all the stores support them")
+	}
+	c.unsupported = store != nil
+	return !c.unsupported
+}
+
+// findStoreWithoutAsyncCommit returns the first TiKV store which is older than minAsyncCommitStoreVersion. The stores
+// whose versions can't be parsed are regarded as supported.
+func findStoreWithoutAsyncCommit(stores []*metapb.Store) *metapb.Store {
+	for _, store := range stores {
+		if store.GetState() == metapb.StoreState_Tombstone || isTiFlashStore(store) {
+			continue
+		}
+		version, err := semver.NewVersion(strings.TrimPrefix(store.GetVersion(), "v"))
+		if err != nil {
+			continue
+		}
+		// The pre-release versions like 5.0.0-rc support async commit too.
+		version.PreRelease = ""
+		if version.LessThan(minAsyncCommitStoreVersion) {
+			return store
+		}
+	}
+	return nil
+}
+
+func isTiFlashStore(store *metapb.Store) bool {
+	for _, label := range store.GetLabels() {
+		if label.GetKey() == placement.EngineLabelKey && label.GetValue() == placement.EngineLabelTiFlash {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb/ddl/placement"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindStoreWithoutAsyncCommit(t *testing.T) {
+	tiflash := []*metapb.StoreLabel{{Key: placement.EngineLabelKey, Value: placement.EngineLabelTiFlash}}
+	stores := []*metapb.Store{
+		{Id: 1, Version: "5.0.0"},
+		{Id: 2, Version: "v5.4.0-alpha"},
+		{Id: 3, Version: "5.0.0-rc"},
+		{Id: 4, Version: ""},
+		{Id: 5, Version: "4.0.9", Labels: tiflash},
+		{Id: 6, Version: "4.0.9", State: metapb.StoreState_Tombstone},
+	}
+	require.Nil(t, findStoreWithoutAsyncCommit(stores))
+
+	stores = append(stores, &metapb.Store{Id: 7, Version: "v4.0.16"})
+	store := findStoreWithoutAsyncCommit(stores)
+	require.NotNil(t, store)
+	require.Equal(t, uint64(7), store.GetId())
+}
@@ -240,6 +240,67 @@ func (do *Domain) checkEnableServerGlobalVar(name, sVal string) {
 			break
 		}
 		storekv.StoreLimit.Store(val)
+	case variable.TiDBServerMemoryLimit:
+		var val uint64
+		val, err = strconv.ParseUint(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		variable.ServerMemoryLimit.Store(val)
+	case variable.MandatoryRoles:
+		variable.MandatoryRolesValue.Store(sVal)
+	case variable.ActivateAllRolesOnLogin:
+		variable.ActivateAllRolesOnLoginValue.Store(variable.TiDBOptOn(sVal))
+	case variable.AuthenticationLDAPSimpleServerHost, variable.AuthenticationLDAPSimpleServerPort, variable.AuthenticationLDAPSimpleTLS,
+		variable.AuthenticationLDAPSimpleCAPath, variable.AuthenticationLDAPSimpleBindBaseDN, variable.AuthenticationLDAPSimpleBindRootDN,
+		variable.AuthenticationLDAPSimpleBindRootPWD, variable.AuthenticationLDAPSimpleUserSearchAttr, variable.AuthenticationLDAPSimpleGroupSearchAttr,
+		variable.AuthenticationLDAPSimpleGroupRoleMapping, variable.AuthenticationLDAPSimpleInitPoolSize, variable.AuthenticationLDAPSimpleMaxPoolSize,
+		variable.AuthenticationLDAPSimpleBindCacheTTL, variable.AuthenticationLDAPSASLServerHost, variable.AuthenticationLDAPSASLServerPort,
+		variable.AuthenticationLDAPSASLTLS, variable.AuthenticationLDAPSASLCAPath, variable.AuthenticationLDAPSASLBindBaseDN,
+		variable.AuthenticationLDAPSASLBindRootDN, variable.AuthenticationLDAPSASLBindRootPWD, variable.AuthenticationLDAPSASLUserSearchAttr,
+		variable.AuthenticationLDAPSASLGroupSearchAttr, variable.AuthenticationLDAPSASLGroupRoleMapping, variable.AuthenticationLDAPSASLInitPoolSize,
+		variable.AuthenticationLDAPSASLMaxPoolSize, variable.AuthenticationLDAPSASLAuthMethodName:
+		// The settings of the LDAP authentication are applied to all the servers.
+		err = variable.GetSysVar(name).SetGlobal(nil, sVal)
+	case variable.MaxUserConnections:
+		var val uint64
+		val, err = strconv.ParseUint(sVal, 10, 32)
+		if err != nil {
+			break
+		}
+		variable.MaxUserConnectionsValue.Store(uint32(val))
+	case variable.DefaultPasswordLifetime:
+		var val uint64
+		val, err = strconv.ParseUint(sVal, 10, 16)
+		if err != nil {
+			break
+		}
+		variable.DefaultPasswordLifetimeValue.Store(uint32(val))
+	case variable.TiDBConnEgressBytesPerSec:
+		var val uint64
+		val, err = strconv.ParseUint(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		variable.ConnEgressBytesPerSec.Store(val)
+	case variable.TiDBSlowLogFormat:
+		variable.SlowLogFormatValue.Store(sVal)
+	case variable.TiDBEnablePlanReplayerCapture:
+		variable.EnablePlanReplayerCapture.Store(variable.TiDBOptOn(sVal))
+	case variable.TiDBPlanReplayerCaptureThreshold:
+		var val int64
+		val, err = strconv.ParseInt(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		variable.PlanReplayerCaptureThreshold.Store(val)
+	case variable.TiDBPlanReplayerCaptureMaxFiles:
+		var val int64
+		val, err = strconv.ParseInt(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		variable.PlanReplayerCaptureMaxFiles.Store(val)
 	}
 	if err != nil {
 		logutil.BgLogger().Error(fmt.Sprintf("load global variable %s error", name), zap.Error(err))
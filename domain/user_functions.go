@@ -0,0 +1,164 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// userFunctionCache caches the compiled user-defined functions in mysql.user_functions.
+// Like the sysvar cache, it's rebuilt periodically and on the etcd notification.
+type userFunctionCache struct {
+	sync.RWMutex // protects funcs
+	funcs        map[string]*cachedUserFunction
+	rebuildLock  sync.Mutex // protects concurrent rebuild
+}
+
+type cachedUserFunction struct {
+	udf *expression.UserDefinedFunction
+	// definition is used to check whether the function is changed, the unchanged functions are not
+	// compiled again when the cache is rebuilt.
+	definition string
+}
+
+// GetUserDefinedFunction implements the expression.UserDefinedFunctions interface.
+func (c *userFunctionCache) GetUserDefinedFunction(name string) (*expression.UserDefinedFunction, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	f, ok := c.funcs[name]
+	if !ok {
+		return nil, false
+	}
+	return f.udf, true
+}
+
+func (c *userFunctionCache) close() {
+	c.Lock()
+	defer c.Unlock()
+	for _, f := range c.funcs {
+		f.udf.Close()
+	}
+	c.funcs = nil
+}
+
+// UserDefinedFunctions returns the user-defined functions created by CREATE FUNCTION.
+func (do *Domain) UserDefinedFunctions() expression.UserDefinedFunctions {
+	return &do.userFunctions
+}
+
+// EncodeUserFunctionTypes encodes the types of the user-defined function stored in mysql.user_functions.
+func EncodeUserFunctionTypes(paramTypes []*types.FieldType, retType *types.FieldType) (params string, ret string, err error) {
+	if paramTypes == nil {
+		paramTypes = []*types.FieldType{}
+	}
+	paramBytes, err := json.Marshal(paramTypes)
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	retBytes, err := json.Marshal(retType)
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	return string(paramBytes), string(retBytes), nil
+}
+
+func decodeUserFunctionTypes(params, ret string) ([]*types.FieldType, *types.FieldType, error) {
+	var paramTypes []*types.FieldType
+	if err := json.Unmarshal([]byte(params), &paramTypes); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	retType := &types.FieldType{}
+	if err := json.Unmarshal([]byte(ret), retType); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return paramTypes, retType, nil
+}
+
+// rebuildUserFunctionCache loads the user-defined functions from mysql.user_functions. The functions
+// which can't be loaded are skipped, so that a broken function doesn't make the others unavailable.
+func (do *Domain) rebuildUserFunctionCache(ctx sessionctx.Context) error {
+	if ctx == nil {
+		sysSessionPool := do.SysSessionPool()
+		res, err := sysSessionPool.Get()
+		if err != nil {
+			return err
+		}
+		defer sysSessionPool.Put(res)
+		ctx = res.(sessionctx.Context)
+	}
+	cache := &do.userFunctions
+	cache.rebuildLock.Lock()
+	defer cache.rebuildLock.Unlock()
+
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParamsInternal(context.Background(), `SELECT name, language, param_types, return_type, body FROM mysql.user_functions`)
+	if err != nil {
+		return err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(context.TODO(), stmt)
+	if err != nil {
+		return err
+	}
+
+	cache.RLock()
+	oldFuncs := cache.funcs
+	cache.RUnlock()
+	newFuncs := make(map[string]*cachedUserFunction, len(rows))
+	for _, row := range rows {
+		name, language := row.GetString(0), row.GetString(1)
+		params, ret, body := row.GetString(2), row.GetString(3), row.GetBytes(4)
+		definition := language + "\x00" + params + "\x00" + ret + "\x00" + string(body)
+		if old, ok := oldFuncs[name]; ok && old.definition == definition {
+			newFuncs[name] = old
+			continue
+		}
+		if language != expression.UDFLanguageWasm {
+			logutil.BgLogger().Warn("skip the user-defined function in unsupported language",
+				zap.String("name", name), zap.String("language", language))
+			continue
+		}
+		paramTypes, retType, err := decodeUserFunctionTypes(params, ret)
+		if err == nil {
+			var udf *expression.UserDefinedFunction
+			udf, err = expression.NewWasmUserDefinedFunction(name, paramTypes, retType, body)
+			if err == nil {
+				newFuncs[name] = &cachedUserFunction{udf: udf, definition: definition}
+				continue
+			}
+		}
+		logutil.BgLogger().Warn("load the user-defined function failed", zap.String("name", name), zap.Error(err))
+	}
+
+	cache.Lock()
+	cache.funcs = newFuncs
+	cache.Unlock()
+	// The dropped and replaced functions are released, the statements still using them will fail.
+	for name, old := range oldFuncs {
+		if f, ok := newFuncs[name]; !ok || f != old {
+			old.udf.Close()
+		}
+	}
+	return nil
+}
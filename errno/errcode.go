@@ -882,9 +882,14 @@ const (
 	ErrWindowNoGroupOrderUnused                              = 3597
 	ErrWindowExplainJSON                                     = 3598
 	ErrWindowFunctionIgnoresFrame                            = 3599
+	ErrFieldInGroupingNotGroupBy                             = 3601
 	ErrIllegalPrivilegeLevel                                 = 3619
 	ErrCTEMaxRecursionDepth                                  = 3636
 	ErrNotHintUpdatable                                      = 3637
+	ErrCredentialsContradictToHistory                        = 3638
+	ErrMissingJSONTableValue                                 = 3665
+	ErrWrongJSONTableValue                                   = 3666
+	ErrRegexpIndexOutOfBounds                                = 3686
 	ErrDataTruncatedFunctionalIndex                          = 3751
 	ErrDataOutOfRangeFunctionalIndex                         = 3752
 	ErrFunctionalIndexOnJSONOrGeometryFunction               = 3753
@@ -1056,6 +1061,22 @@ const (
 	ErrPlacementPolicyInUse               = 8241
 	ErrOptOnCacheTable                    = 8242
 	ErrHTTPServiceError                   = 8243
+	ErrAdmissionRejected                  = 8244
+	ErrOptOnExternalTable                 = 8245
+	ErrNonTransactionalDMLUnsupported     = 8246
+	ErrNonTransactionalJobFailure         = 8247
+	ErrTmpStorageQuotaExceeded            = 8248
+	ErrInvalidUserDefinedFunction         = 8249
+	ErrUserDefinedFunctionFailed          = 8250
+	ErrVectorDimensionMismatch            = 8251
+	ErrVectorUsedAsKey                    = 8252
+	ErrVectorDimensionOutOfRange          = 8253
+	ErrAnalyzeMemoryExceed                = 8254
+	ErrAnalyzeJobKilled                   = 8255
+	ErrKillAnalyzeJobFailed               = 8256
+	ErrResourceGroupExists                = 8257
+	ErrResourceGroupNotExists             = 8258
+	ErrCannotMigrateSession               = 8259
 	// TiKV/PD/TiFlash errors.
 	ErrPDServerTimeout           = 9001
 	ErrTiKVServerTimeout         = 9002
@@ -877,10 +877,15 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrWindowNoGroupOrderUnused:                              mysql.Message("ASC or DESC with GROUP BY isn't allowed with window functions; put ASC or DESC in ORDER BY", nil),
 	ErrWindowExplainJSON:                                     mysql.Message("To get information about window functions use EXPLAIN FORMAT=JSON", nil),
 	ErrWindowFunctionIgnoresFrame:                            mysql.Message("Window function '%s' ignores the frame clause of window '%s' and aggregates over the whole partition", nil),
+	ErrFieldInGroupingNotGroupBy:                             mysql.Message("Argument #%d of GROUPING function is not in GROUP BY", nil),
 	ErrRoleNotGranted:                                        mysql.Message("%s is not granted to %s", nil),
 	ErrMaxExecTimeExceeded:                                   mysql.Message("Query execution was interrupted, max_execution_time exceeded.", nil),
 	ErrLockAcquireFailAndNoWaitSet:                           mysql.Message("Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set.", nil),
 	ErrNotHintUpdatable:                                      mysql.Message("Variable '%s' cannot be set using SET_VAR hint.", nil),
+	ErrCredentialsContradictToHistory:                        mysql.Message("Cannot use these credentials for '%s@%s' because they contradict the password history policy", nil),
+	ErrMissingJSONTableValue:                                 mysql.Message("Missing value for JSON_TABLE column '%-.192s'", nil),
+	ErrWrongJSONTableValue:                                   mysql.Message("Can't store an array or an object in the scalar JSON_TABLE column '%-.192s'", nil),
+	ErrRegexpIndexOutOfBounds:                                mysql.Message("Index out of bounds in regular expression search.", nil),
 	ErrDataTruncatedFunctionalIndex:                          mysql.Message("Data truncated for expression index '%s' at row %d", nil),
 	ErrDataOutOfRangeFunctionalIndex:                         mysql.Message("Value is out of range for expression index '%s' at row %d", nil),
 	ErrFunctionalIndexOnJSONOrGeometryFunction:               mysql.Message("Cannot create an expression index on a function that returns a JSON or GEOMETRY value", nil),
@@ -1062,6 +1067,22 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrPlacementPolicyWithDirectOption: mysql.Message("Placement policy '%s' can't co-exist with direct placement options", nil),
 	ErrPlacementPolicyInUse:            mysql.Message("Placement policy '%-.192s' is still in use", nil),
 	ErrOptOnCacheTable:                 mysql.Message("'%s' is unsupported on cache tables.", nil),
+	ErrAdmissionRejected:               mysql.Message("Statement is rejected by the server, reason: %s", nil),
+	ErrOptOnExternalTable:              mysql.Message("'%s' is unsupported on external tables.", nil),
+	ErrNonTransactionalDMLUnsupported:  mysql.Message("Non-transactional DML is unsupported: %s", nil),
+	ErrNonTransactionalJobFailure:      mysql.Message("Non-transactional DML job %d/%d failed, range: [%s, %s], error: %s", nil),
+	ErrTmpStorageQuotaExceeded:         mysql.Message("Out Of Temporary Storage Quota! The query holds %dB temporary storage, exceeds the quota %dB", nil),
+	ErrInvalidUserDefinedFunction:      mysql.Message("Invalid user-defined function '%s': %s", nil),
+	ErrUserDefinedFunctionFailed:       mysql.Message("Failed to execute the user-defined function '%s': %s", nil),
+	ErrVectorDimensionMismatch:         mysql.Message("Vector dimension mismatch, expect %d, got %d", nil),
+	ErrVectorUsedAsKey:                 mysql.Message("Vector column '%-.192s' cannot be used in key specification", nil),
+	ErrVectorDimensionOutOfRange:       mysql.Message("Vector dimension %d is out of range, expect 1 to %d", nil),
+	ErrAnalyzeMemoryExceed:             mysql.Message("Out Of Analyze Memory Quota! The samples hold %dB memory, exceeds the quota %dB, please decrease the sample rate or increase tidb_mem_quota_analyze", nil),
+	ErrAnalyzeJobKilled:                mysql.Message("Analyze job %d is killed", nil),
+	ErrKillAnalyzeJobFailed:            mysql.Message("Failed to kill analyze job %d: %s", nil),
+	ErrResourceGroupExists:             mysql.Message("Resource group '%-.192s' already exists", nil),
+	ErrResourceGroupNotExists:          mysql.Message("Unknown resource group '%-.192s'", nil),
+	ErrCannotMigrateSession:            mysql.Message("Cannot migrate the current session: %s", nil),
 	// TiKV/PD errors.
 	ErrPDServerTimeout:           mysql.Message("PD server timeout", nil),
 	ErrTiKVServerTimeout:         mysql.Message("TiKV server timeout", nil),
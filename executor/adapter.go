@@ -48,6 +48,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/sessiontxn"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/audit"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/hint"
@@ -87,6 +88,8 @@ type recordSet struct {
 	stmt       *ExecStmt
 	lastErr    error
 	txnStartTS uint64
+	// firstChunk is the chunk fetched in advance by ExecStmt.Exec, it's returned by the first Next.
+	firstChunk *chunk.Chunk
 }
 
 func (a *recordSet) Fields() []*ast.ResultField {
@@ -145,10 +148,15 @@ func (a *recordSet) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		logutil.Logger(ctx).Error("execute sql panic", zap.String("sql", a.stmt.GetTextToLog()), zap.Stack("stack"))
 	}()
 
-	err = Next(ctx, a.executor, req)
-	if err != nil {
-		a.lastErr = err
-		return err
+	if a.firstChunk != nil {
+		req.SwapColumns(a.firstChunk)
+		a.firstChunk = nil
+	} else {
+		err = Next(ctx, a.executor, req)
+		if err != nil {
+			a.lastErr = err
+			return err
+		}
 	}
 	numRows := req.NumRows()
 	if numRows == 0 {
@@ -218,6 +226,8 @@ type ExecStmt struct {
 	isSelectForUpdate bool
 	retryCount        uint
 	retryStartTime    time.Time
+	// maxExecTimer kills the statement when it exceeds max_execution_time, it's stopped when the statement finishes.
+	maxExecTimer *time.Timer
 
 	// OutputNames will be set if using cached plan
 	OutputNames []*types.FieldName
@@ -393,6 +403,8 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 
 	if sctx.GetSessionVars().StmtCtx.HasMemQuotaHint {
 		sctx.GetSessionVars().StmtCtx.MemTracker.SetBytesLimit(sctx.GetSessionVars().StmtCtx.MemQuotaQuery)
+	} else if _, ok := sctx.GetSessionVars().StmtCtx.SetVars[variable.TiDBMemQuotaQuery]; ok {
+		sctx.GetSessionVars().StmtCtx.MemTracker.SetBytesLimit(sctx.GetSessionVars().MemQuotaQuery)
 	}
 
 	e, err := a.buildExecutor()
@@ -423,6 +435,7 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 		maxExecutionTime := getMaxExecutionTime(sctx)
 		// Update processinfo, ShowProcess() will use it.
 		pi.SetProcessInfo(sql, time.Now(), cmd, maxExecutionTime)
+		a.startMaxExecTimer(maxExecutionTime)
 		if a.Ctx.GetSessionVars().StmtCtx.StmtType == "" {
 			a.Ctx.GetSessionVars().StmtCtx.StmtType = GetStmtLabel(a.StmtNode)
 		}
@@ -439,6 +452,14 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 		return result, err
 	}
 
+	var firstChunk *chunk.Chunk
+	if sctx.GetSessionVars().StmtCtx.RCCheckTS {
+		e, firstChunk, err = a.handleRCCheckTSRead(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var txnStartTS uint64
 	txn, err := sctx.Txn(false)
 	if err != nil {
@@ -452,9 +473,51 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 		executor:   e,
 		stmt:       a,
 		txnStartTS: txnStartTS,
+		firstChunk: firstChunk,
 	}, nil
 }
 
+// handleRCCheckTSRead fetches the first chunk of the statement which reads data with the last timestamp of the RC
+// transaction. If a newer version is met, the storage returns a write conflict error, then the statement is retried
+// with a new timestamp. Once the first chunk is returned, the statement can't be retried any more, so the following
+// conflicts are returned to the client.
+func (a *ExecStmt) handleRCCheckTSRead(ctx context.Context, e Executor) (Executor, *chunk.Chunk, error) {
+	req := newFirstChunk(e)
+	err := Next(ctx, e, req)
+	if err == nil {
+		return e, req, nil
+	}
+	terror.Call(e.Close)
+	if !terror.ErrorEqual(kv.ErrWriteConflict, err) {
+		return nil, nil, err
+	}
+	sessVars := a.Ctx.GetSessionVars()
+	logutil.Logger(ctx).Debug("RC read meets a newer version, retry statement with a new timestamp",
+		zap.Uint64("txn", sessVars.TxnCtx.StartTS),
+		zap.Uint64("forUpdateTS", sessVars.TxnCtx.GetForUpdateTS()),
+		zap.Error(err))
+	sessVars.StmtCtx.RCCheckTS = false
+	if err = UpdateForUpdateTS(a.Ctx, 0); err != nil {
+		return nil, nil, err
+	}
+	a.retryCount++
+	a.retryStartTime = time.Now()
+	sessVars.StmtCtx.ResetForRetry()
+	if e, err = a.buildExecutor(); err != nil {
+		return nil, nil, err
+	}
+	if err = e.Open(ctx); err != nil {
+		terror.Call(e.Close)
+		return nil, nil, err
+	}
+	req = newFirstChunk(e)
+	if err = Next(ctx, e, req); err != nil {
+		terror.Call(e.Close)
+		return nil, nil, err
+	}
+	return e, req, nil
+}
+
 func (a *ExecStmt) handleNoDelay(ctx context.Context, e Executor, isPessimistic bool) (handled bool, rs sqlexec.RecordSet, err error) {
 	sc := a.Ctx.GetSessionVars().StmtCtx
 	defer func() {
@@ -499,12 +562,37 @@ func (a *ExecStmt) handleNoDelay(ctx context.Context, e Executor, isPessimistic
 	return false, nil, nil
 }
 
-// getMaxExecutionTime get the max execution timeout value.
+// getMaxExecutionTime get the max execution timeout value. It's 0 for the statements which aren't limited, only the
+// SELECT statements are limited unless tidb_enable_dml_max_execution_time is on.
 func getMaxExecutionTime(sctx sessionctx.Context) uint64 {
-	if sctx.GetSessionVars().StmtCtx.HasMaxExecutionTime {
-		return sctx.GetSessionVars().StmtCtx.MaxExecutionTime
+	sessVars := sctx.GetSessionVars()
+	sc := sessVars.StmtCtx
+	if !sc.InSelectStmt && !(sessVars.EnableDMLMaxExecutionTime && (sc.InInsertStmt || sc.InUpdateStmt || sc.InDeleteStmt)) {
+		return 0
 	}
-	return sctx.GetSessionVars().MaxExecutionTime
+	if sc.HasMaxExecutionTime {
+		return sc.MaxExecutionTime
+	}
+	return sessVars.MaxExecutionTime
+}
+
+// startMaxExecTimer starts the timer which kills the statement when it runs longer than maxExecutionTime
+// milliseconds. The statement is killed as KILL QUERY does, so the coprocessor and the transaction requests are
+// cancelled as well, and the error is reported as ErrMaxExecTimeExceeded by the server.
+func (a *ExecStmt) startMaxExecTimer(maxExecutionTime uint64) {
+	if maxExecutionTime == 0 || a.maxExecTimer != nil {
+		return
+	}
+	sm := a.Ctx.GetSessionManager()
+	if sm == nil {
+		return
+	}
+	sessVars := a.Ctx.GetSessionVars()
+	sc, connID := sessVars.StmtCtx, sessVars.ConnectionID
+	a.maxExecTimer = time.AfterFunc(time.Duration(maxExecutionTime)*time.Millisecond, func() {
+		sc.MaxExecTimeExceeded.Store(true)
+		sm.Kill(connID, true)
+	})
 }
 
 type chunkRowRecordSet struct {
@@ -877,6 +965,49 @@ func (a *ExecStmt) logAudit() {
 	}
 }
 
+// LogAuditEvent records the statement of the client into the audit log if it's enabled. The statements are recorded
+// when they finish, and the statements which fail to compile are recorded by the caller of the compiler.
+func LogAuditEvent(sctx sessionctx.Context, stmtNode ast.StmtNode, err error) {
+	logger := audit.GetGlobalLogger()
+	sessVars := sctx.GetSessionVars()
+	if logger == nil || sessVars.InRestrictedSQL || stmtNode == nil {
+		return
+	}
+	// The prepared statements are recorded as the statements they execute.
+	stmt := stmtNode
+	if execStmt, ok := stmtNode.(*ast.ExecuteStmt); ok {
+		if prepared, err := plannercore.GetPreparedStmt(execStmt, sessVars); err == nil {
+			stmt = prepared.PreparedAst.Stmt
+		}
+	}
+	e := &audit.Event{
+		ConnID: sessVars.ConnectionID,
+		Class:  audit.ClassOfStmt(stmt),
+		Event:  GetStmtLabel(stmt),
+		DB:     sessVars.CurrentDB,
+	}
+	if sessVars.User != nil {
+		e.User, e.Host = sessVars.User.Username, sessVars.User.Hostname
+	}
+	for _, table := range sessVars.StmtCtx.Tables {
+		e.Tables = append(e.Tables, table.DB+"."+table.Table)
+	}
+	// The passwords are never recorded.
+	if sensitiveStmt, ok := stmt.(ast.SensitiveStmtNode); ok {
+		e.SQL = sensitiveStmt.SecureText()
+	} else {
+		e.SQL = sessVars.StmtCtx.OriginalSQL + sessVars.PreparedParams.String()
+	}
+	if err != nil {
+		e.ErrCode = mysql.ErrUnknown
+		if te, ok := errors.Cause(err).(*terror.Error); ok {
+			e.ErrCode = terror.ToSQLError(te).Code
+		}
+		e.Err = err.Error()
+	}
+	logger.Log(e)
+}
+
 // FormatSQL is used to format the original SQL, e.g. truncating long SQL, appending prepared arguments.
 func FormatSQL(sql string) stringutil.StringerFunc {
 	return func() string {
@@ -902,6 +1033,9 @@ var (
 // 4. update the `PrevStmt` in session variable.
 // 5. reset `DurationParse` in session variable.
 func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults bool) {
+	if a.maxExecTimer != nil {
+		a.maxExecTimer.Stop()
+	}
 	sessVars := a.Ctx.GetSessionVars()
 	execDetail := sessVars.StmtCtx.GetExecDetails()
 	// Attach commit/lockKeys runtime stats to executor runtime stats.
@@ -924,7 +1058,12 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults boo
 	// `LowSlowQuery` and `SummaryStmt` must be called before recording `PrevStmt`.
 	a.LogSlowQuery(txnTS, succ, hasMoreResults)
 	a.SummaryStmt(succ)
+	a.capturePlanReplayer(succ)
+	a.observeStmtMetricsByGroup(succ)
 	a.observeStmtFinishedForTopSQL()
+	if succ {
+		plannercore.CollectEstimateCorrections(a.Ctx, a.Plan)
+	}
 	if sessVars.StmtCtx.IsTiFlash.Load() {
 		if succ {
 			totalTiFlashQuerySuccCounter.Inc()
@@ -933,6 +1072,7 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults boo
 		}
 	}
 	sessVars.PrevStmt = FormatSQL(a.GetTextToLog())
+	LogAuditEvent(a.Ctx, a.StmtNode, err)
 
 	executeDuration := time.Since(sessVars.StartTime) - sessVars.DurationCompile
 	if sessVars.InRestrictedSQL {
@@ -1044,6 +1184,7 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 		ExecRetryCount:    a.retryCount,
 		IsExplicitTxn:     sessVars.TxnCtx.IsExplicit,
 		IsWriteCacheTable: sessVars.StmtCtx.WaitLockLeaseTime > 0,
+		RUConsumed:        execDetail.RUConsumed,
 	}
 	if a.retryCount > 0 {
 		slowItems.ExecRetryTime = costTime - sessVars.DurationParse - sessVars.DurationCompile - time.Since(a.retryStartTime)
@@ -1131,6 +1272,47 @@ func getPlanDigest(sctx sessionctx.Context, p plannercore.Plan) (string, *parser
 	return normalized, planDigest
 }
 
+// capturePlanReplayer captures the statement into a plan replayer zip in the background if it's slow or its plan
+// deviates from the baseline.
+func (a *ExecStmt) capturePlanReplayer(succ bool) {
+	sessVars := a.Ctx.GetSessionVars()
+	if !variable.EnablePlanReplayerCapture.Load() || !succ || sessVars.InRestrictedSQL || a.Plan == nil {
+		return
+	}
+	switch a.StmtNode.(type) {
+	case *ast.SelectStmt, *ast.SetOprStmt, *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+	default:
+		return
+	}
+	dom := domain.GetDomain(a.Ctx)
+	if dom == nil {
+		return
+	}
+	_, sqlDigest := sessVars.StmtCtx.SQLDigest()
+	_, planDigest := getPlanDigest(a.Ctx, a.Plan)
+	latency := time.Since(sessVars.StartTime) + sessVars.DurationParse
+	reason := dom.CheckPlanReplayerCapture(sqlDigest.String(), planDigest.String(), latency)
+	if reason == "" {
+		return
+	}
+	task := &domain.PlanReplayerCaptureTask{
+		SQLDigest:       sqlDigest.String(),
+		PlanDigest:      planDigest.String(),
+		Reason:          reason,
+		Latency:         latency,
+		CaptureTime:     time.Now(),
+		DB:              sessVars.CurrentDB,
+		Stmt:            a.StmtNode,
+		Variables:       getPlanReplayerVariables(sessVars),
+		SessionBindings: getPlanReplayerSessionBindings(a.Ctx),
+	}
+	encodedPlan, _ := getEncodedPlan(a.Ctx, a.Plan, false)
+	if plan, err := plancodec.DecodePlan(encodedPlan); err == nil {
+		task.Plan = plan
+	}
+	dom.SendPlanReplayerCaptureTask(task)
+}
+
 // getEncodedPlan gets the encoded plan, and generates the hint string if indicated.
 func getEncodedPlan(sctx sessionctx.Context, p plannercore.Plan, genHint bool) (encodedPlan, hintStr string) {
 	var hintSet bool
@@ -1267,7 +1449,7 @@ func (a *ExecStmt) SummaryStmt(succ bool) {
 func (a *ExecStmt) GetTextToLog() string {
 	var sql string
 	sessVars := a.Ctx.GetSessionVars()
-	if sessVars.EnableRedactLog {
+	if sessVars.ShouldRedactLog() {
 		sql, _ = sessVars.StmtCtx.SQLDigest()
 	} else if sensitiveStmt, ok := a.StmtNode.(ast.SensitiveStmtNode); ok {
 		sql = sensitiveStmt.SecureText()
@@ -18,7 +18,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/testkit"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
 )
 
@@ -40,3 +43,51 @@ func TestQueryTime(t *testing.T) {
 	costTime = time.Since(tk.Session().GetSessionVars().StartTime)
 	require.Less(t, costTime, time.Second)
 }
+
+func TestStmtMetricsByGroup(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Status.RecordStmtMetricsByGroup = true
+	})
+	metrics.GroupQueryTotalCounter.Reset()
+	defer metrics.GroupQueryTotalCounter.Reset()
+	getCount := func(group, db string) float64 {
+		pb := &dto.Metric{}
+		require.NoError(t, metrics.GroupQueryTotalCounter.WithLabelValues("Select", group, db, metrics.LblOK).Write(pb))
+		return pb.GetCounter().GetValue()
+	}
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int)")
+	tk.MustQuery("select * from t")
+	require.Equal(t, float64(1), getCount("default", "test"))
+
+	tk.Session().GetSessionVars().ResourceGroupName = "rg1"
+	tk.MustQuery("select * from t")
+	tk.MustQuery("select * from t")
+	require.Equal(t, float64(2), getCount("rg1", "test"))
+	require.Equal(t, float64(1), getCount("default", "test"))
+
+	// The new pairs are recorded as `other` once the limit is reached, while the known pairs aren't affected.
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Status.StmtMetricsLabelLimit = 0
+	})
+	tk.MustExec("use mysql")
+	tk.MustQuery("select * from test.t")
+	require.Equal(t, float64(1), getCount("rg1", "other"))
+	require.Equal(t, float64(0), getCount("rg1", "mysql"))
+	tk.MustExec("use test")
+	tk.MustQuery("select * from t")
+	require.Equal(t, float64(3), getCount("rg1", "test"))
+
+	// Nothing is recorded if it's disabled.
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Status.RecordStmtMetricsByGroup = false
+	})
+	tk.MustQuery("select * from t")
+	require.Equal(t, float64(3), getCount("rg1", "test"))
+}
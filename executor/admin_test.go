@@ -17,6 +17,7 @@ package executor_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -1258,3 +1259,82 @@ func TestAdminCheckTableFailed(t *testing.T) {
 	require.NoError(t, err)
 	tk.MustExec("admin check table admin_test")
 }
+
+func TestFastAdminCheckTable(t *testing.T) {
+	store, domain, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_fast_table_check = on")
+	tk.MustExec("create table admin_test (c1 int, c2 int, c3 varchar(255), primary key(c1), key(c3), unique key(c2), key(c2, c3))")
+	values := make([]string, 0, 500)
+	for i := 0; i < 500; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d, 'v%d')", i, i+1000, i))
+	}
+	tk.MustExec("insert admin_test values " + strings.Join(values, ", "))
+	tk.MustExec("insert admin_test values (-1, -10, null)")
+	tk.MustExec("admin check table admin_test")
+
+	ctx := mock.NewContext()
+	ctx.Store = store
+	tbl, err := domain.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("admin_test"))
+	require.NoError(t, err)
+	tblInfo := tbl.Meta()
+	indexOpr := tables.NewIndex(tblInfo.ID, tblInfo, tblInfo.Indices[1])
+	sc := ctx.GetSessionVars().StmtCtx
+
+	// The index misses a row.
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	require.NoError(t, indexOpr.Delete(sc, txn, types.MakeDatums(1100), kv.IntHandle(100)))
+	require.NoError(t, txn.Commit(context.Background()))
+	err = tk.ExecToErr("admin check table admin_test")
+	require.True(t, executor.ErrAdminCheckTable.Equal(err))
+	require.EqualError(t, err, "[executor:8003]admin_test err:[executor:8133]handle \"100\", index:<nil> != record:\"1100\"")
+
+	// The index has a wrong value.
+	txn, err = store.Begin()
+	require.NoError(t, err)
+	_, err = indexOpr.Create(ctx, txn, types.MakeDatums(2100), kv.IntHandle(100), nil)
+	require.NoError(t, err)
+	require.NoError(t, txn.Commit(context.Background()))
+	err = tk.ExecToErr("admin check table admin_test")
+	require.EqualError(t, err, "[executor:8003]admin_test err:[executor:8133]handle \"100\", index:\"2100\" != record:\"1100\"")
+
+	// The index has an extra row.
+	txn, err = store.Begin()
+	require.NoError(t, err)
+	require.NoError(t, indexOpr.Delete(sc, txn, types.MakeDatums(2100), kv.IntHandle(100)))
+	_, err = indexOpr.Create(ctx, txn, types.MakeDatums(1100), kv.IntHandle(100), nil)
+	require.NoError(t, err)
+	_, err = indexOpr.Create(ctx, txn, types.MakeDatums(0), kv.IntHandle(1000), nil)
+	require.NoError(t, err)
+	require.NoError(t, txn.Commit(context.Background()))
+	err = tk.ExecToErr("admin check table admin_test")
+	require.EqualError(t, err, "[executor:8003]admin_test err:[executor:8133]handle \"1000\", index:\"0\" != record:<nil>")
+	txn, err = store.Begin()
+	require.NoError(t, err)
+	require.NoError(t, indexOpr.Delete(sc, txn, types.MakeDatums(0), kv.IntHandle(1000)))
+	require.NoError(t, txn.Commit(context.Background()))
+	tk.MustExec("admin check table admin_test")
+
+	// The NULL values are compared.
+	indexOpr = tables.NewIndex(tblInfo.ID, tblInfo, tblInfo.Indices[0])
+	txn, err = store.Begin()
+	require.NoError(t, err)
+	require.NoError(t, indexOpr.Delete(sc, txn, types.MakeDatums(nil), kv.IntHandle(-1)))
+	_, err = indexOpr.Create(ctx, txn, types.MakeDatums(""), kv.IntHandle(-1), nil)
+	require.NoError(t, err)
+	require.NoError(t, txn.Commit(context.Background()))
+	err = tk.ExecToErr("admin check table admin_test")
+	require.EqualError(t, err, "[executor:8003]admin_test err:[executor:8133]handle \"-1\", index:\"\" != record:\"NULL\"")
+
+	// The tables without an integer handle.
+	tk.MustExec("create table t_rowid (a int, b varchar(10), key(a), key(b))")
+	tk.MustExec("create table t_clustered (a varchar(10), b int, c int, primary key(a, b) clustered, key(c))")
+	tk.MustExec("insert t_rowid values (1, 'a'), (2, 'b'), (null, null)")
+	tk.MustExec("insert t_clustered values ('a', 1, 1), ('b', 2, null)")
+	tk.MustExec("admin check table t_rowid")
+	tk.MustExec("admin check table t_clustered")
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
@@ -1432,6 +1433,49 @@ func TestIssue20658(t *testing.T) {
 	}
 }
 
+func TestParallelStreamAggOnSortedInput(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, key(b, a))")
+	tk.MustExec("set tidb_init_chunk_size=1")
+	tk.MustExec("set tidb_max_chunk_size=32")
+	var insertSQL strings.Builder
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			insertSQL.WriteString(",")
+		}
+		insertSQL.WriteString(fmt.Sprintf("(%d,%d)", i, i%37))
+	}
+	tk.MustExec("insert into t values " + insertSQL.String())
+
+	sqls := []string{
+		"select /*+ stream_agg() */ b, count(a), sum(a) from t use index(b) group by b",
+		"select b, sum(a) over (partition by b), row_number() over (partition by b order by a) from t use index(b)",
+	}
+	for _, sql := range sqls {
+		tk.MustExec("set @@tidb_executor_concurrency = 1")
+		tk.MustExec("set @@tidb_streamagg_concurrency = 1")
+		expected := tk.MustQuery(sql).Sort().Rows()
+		tk.MustExec("set @@tidb_executor_concurrency = 4")
+		tk.MustExec("set @@tidb_streamagg_concurrency = 4")
+		for _, enabled := range []bool{false, true} {
+			tk.MustExec(fmt.Sprintf("set @@tidb_enable_sorted_shuffle = %v", enabled))
+			hasShuffle := false
+			for _, row := range tk.MustQuery("explain format = 'brief' " + sql).Rows() {
+				if strings.Contains(fmt.Sprintf("%v", row), "Shuffle") {
+					hasShuffle = true
+					break
+				}
+			}
+			require.Equal(t, enabled, hasShuffle, sql)
+			tk.MustQuery(sql).Sort().Check(expected)
+		}
+	}
+}
+
 func TestIssue23277(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -1597,3 +1641,59 @@ func TestRandomPanicAggConsume(t *testing.T) {
 		require.EqualError(t, err, "failpoint panic: ERROR 1105 (HY000): Out Of Memory Quota![conn_id=1]")
 	}
 }
+
+func TestAggregationWithRollup(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 10), (1, 2, 20), (2, 1, 30), (2, 2, 40), (null, 1, 50)")
+
+	tk.MustQuery("select a, b, sum(c), grouping(a), grouping(b), grouping(a, b) from t group by a, b with rollup").Sort().Check(testkit.Rows(
+		"1 1 10 0 0 0",
+		"1 2 20 0 0 0",
+		"1 <nil> 30 0 1 1",
+		"2 1 30 0 0 0",
+		"2 2 40 0 0 0",
+		"2 <nil> 70 0 1 1",
+		"<nil> 1 50 0 0 0",
+		"<nil> <nil> 150 1 1 3",
+		"<nil> <nil> 50 0 1 1",
+	))
+	tk.MustQuery("select count(*), count(a), max(b) from t group by a with rollup").Sort().Check(testkit.Rows("1 0 1", "2 2 2", "2 2 2", "5 4 2"))
+	tk.MustQuery("select a+1, sum(c), grouping(a+1) from t group by a+1 with rollup").Sort().Check(testkit.Rows(
+		"2 30 0", "3 70 0", "<nil> 150 1", "<nil> 50 0"))
+	tk.MustQuery("select a, sum(c) from t group by a with rollup having grouping(a) = 1").Check(testkit.Rows("<nil> 150"))
+	tk.MustQuery("select a, b, sum(c) from t group by a, b with rollup order by grouping(a), a, grouping(b), b").Check(testkit.Rows(
+		"<nil> 1 50",
+		"<nil> <nil> 50",
+		"1 1 10",
+		"1 2 20",
+		"1 <nil> 30",
+		"2 1 30",
+		"2 2 40",
+		"2 <nil> 70",
+		"<nil> <nil> 150",
+	))
+	tk.MustQuery("select a, sum(c) from t where a > 1 group by a with rollup").Sort().Check(testkit.Rows("2 70", "<nil> 70"))
+	tk.MustQuery("select a, sum(c) from t where a > 10 group by a with rollup").Check(testkit.Rows())
+	// The CTE named `rollup` is not ROLLUP.
+	tk.MustQuery("with rollup as (select 1 as x) select x from rollup").Check(testkit.Rows("1"))
+
+	tk.MustQuery("explain format = 'brief' select a, sum(c) from t group by a with rollup").Check(testkit.Rows(
+		"Projection 8000.00 root  test.t.a, Column#7",
+		"└─HashAgg 8000.00 root  group by:Column#10, Column#11, funcs:sum(Column#8)->Column#7, funcs:firstrow(Column#9)->test.t.a",
+		"  └─Projection 20000.00 root  cast(test.t.c, decimal(10,0) BINARY)->Column#8, Column#5, Column#5, Column#6",
+		"    └─Expand 20000.00 root  level-projections:[test.t.a->Column#5, 0->Column#6], [<nil>->Column#5, 1->Column#6]",
+		"      └─TableReader 10000.00 root  data:TableFullScan",
+		"        └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo",
+	))
+
+	tk.MustGetErrCode("select grouping(a) from t group by a", mysql.ErrInvalidGroupFuncUse)
+	tk.MustGetErrCode("select a from t where grouping(a) = 0 group by a with rollup", mysql.ErrInvalidGroupFuncUse)
+	tk.MustGetErrCode("select sum(grouping(a)) from t group by a with rollup", mysql.ErrInvalidGroupFuncUse)
+	tk.MustGetErrMsg("select a, grouping(b) from t group by a with rollup", "[planner:3601]Argument #1 of GROUPING function is not in GROUP BY")
+	tk.MustGetErrMsg("select a, grouping(a, c) from t group by a with rollup", "[planner:3601]Argument #2 of GROUPING function is not in GROUP BY")
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net"
 	"runtime"
 	"sort"
 	"strconv"
@@ -33,6 +34,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
@@ -54,6 +56,7 @@ import (
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/timeutil"
@@ -95,14 +98,14 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	for i := 0; i < concurrency; i++ {
 		go e.analyzeWorker(taskCh, resultsCh, i == 0)
 	}
+	statsHandle := domain.GetDomain(e.ctx).StatsHandle()
 	for _, task := range e.tasks {
-		statistics.AddNewAnalyzeJob(task.job)
+		registerAnalyzeJob(e.ctx, task.job)
 	}
 	for _, task := range e.tasks {
 		taskCh <- task
 	}
 	close(taskCh)
-	statsHandle := domain.GetDomain(e.ctx).StatsHandle()
 	panicCnt := 0
 
 	pruneMode := variable.PartitionPruneMode(e.ctx.GetSessionVars().PartitionPruneMode.Load())
@@ -123,8 +126,8 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	// The meaning of key in map is the structure that used to store the tableID and indexID.
 	// The meaning of value in map is some additional information needed to build global-level stats.
 	globalStatsMap := make(map[globalStatsKey]globalStatsInfo)
-	finishJobWithLogFn := func(ctx context.Context, job *statistics.AnalyzeJob, meetError bool) {
-		job.Finish(meetError)
+	finishJobWithLogFn := func(ctx context.Context, job *statistics.AnalyzeJob, err error) {
+		finishAnalyzeJob(e.ctx, job, err)
 		if job != nil {
 			logutil.Logger(ctx).Info(fmt.Sprintf("analyze table `%s`.`%s` has %s", job.DBName, job.TableName, job.State),
 				zap.String("partition", job.PartitionName),
@@ -146,9 +149,15 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			} else {
 				logutil.Logger(ctx).Error("analyze failed", zap.Error(err))
 			}
-			finishJobWithLogFn(ctx, results.Job, true)
+			finishJobWithLogFn(ctx, results.Job, err)
+			continue
+		}
+		if err1 := checkAnalyzeJobKilled(results.Job); err1 != nil {
+			err = err1
+			finishJobWithLogFn(ctx, results.Job, err)
 			continue
 		}
+		results.Job.SetPhase(statistics.AnalyzePhaseSaving)
 		if results.TableID.IsPartitionTable() && needGlobalStats {
 			for _, result := range results.Ars {
 				if result.IsIndex == 0 {
@@ -174,20 +183,28 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		if err1 := statsHandle.SaveTableStatsToStorage(results, results.TableID.IsPartitionTable() && needGlobalStats); err1 != nil {
 			err = err1
 			logutil.Logger(ctx).Error("save table stats to storage failed", zap.Error(err))
-			finishJobWithLogFn(ctx, results.Job, true)
+			finishJobWithLogFn(ctx, results.Job, err)
 		} else {
-			finishJobWithLogFn(ctx, results.Job, false)
+			finishJobWithLogFn(ctx, results.Job, nil)
 		}
 	}
 	for _, task := range e.tasks {
-		statistics.MoveToHistory(task.job)
+		statistics.RemoveAnalyzeJob(task.job)
 	}
 	if err != nil {
 		return err
 	}
 	if needGlobalStats {
 		for globalStatsID, info := range globalStatsMap {
-			globalStats, err := statsHandle.MergePartitionStats2GlobalStatsByTableID(e.ctx, e.opts, e.ctx.GetInfoSchema().(infoschema.InfoSchema), globalStatsID.tableID, info.isIndex, info.histIDs)
+			opts := e.opts
+			if info.isIndex == 1 {
+				opts = make(map[ast.AnalyzeOptionType]uint64, len(e.opts))
+				for key, val := range e.opts {
+					opts[key] = val
+				}
+				opts[ast.AnalyzeOptNumTopN] = indexTopNNum(e.ctx, e.opts)
+			}
+			globalStats, err := statsHandle.MergePartitionStats2GlobalStatsByTableID(e.ctx, opts, e.ctx.GetInfoSchema().(infoschema.InfoSchema), globalStatsID.tableID, info.isIndex, info.histIDs)
 			if err != nil {
 				if types.ErrPartitionStatsMissing.Equal(err) {
 					// When we find some partition-level stats are missing, we need to report warning.
@@ -267,7 +284,14 @@ func (e *AnalyzeExec) analyzeWorker(taskCh <-chan *analyzeTask, resultsCh chan<-
 		if !ok {
 			break
 		}
-		task.job.Start()
+		if err := checkAnalyzeJobKilled(task.job); err != nil {
+			resultsCh <- &statistics.AnalyzeResults{
+				Err: err,
+				Job: task.job,
+			}
+			continue
+		}
+		startAnalyzeJob(e.ctx, task.job)
 		switch task.taskType {
 		case colTask:
 			resultsCh <- analyzeColumnsPushdown(task.colExec)
@@ -283,6 +307,57 @@ func (e *AnalyzeExec) analyzeWorker(taskCh <-chan *analyzeTask, resultsCh chan<-
 	}
 }
 
+// analyzeJobInstance returns the address of the current TiDB instance, which is recorded in mysql.analyze_jobs to find
+// the instance running the job.
+func analyzeJobInstance() string {
+	serverInfo, err := infosync.GetServerInfo()
+	if err != nil {
+		return ""
+	}
+	return net.JoinHostPort(serverInfo.IP, strconv.Itoa(int(serverInfo.Port)))
+}
+
+// registerAnalyzeJob persists the analyze job into mysql.analyze_jobs and registers it as a running job of the current
+// instance, so that its progress can be dumped and it can be killed by KILL ANALYZE.
+func registerAnalyzeJob(sctx sessionctx.Context, job *statistics.AnalyzeJob) {
+	if job == nil {
+		return
+	}
+	statsHandle := domain.GetDomain(sctx).StatsHandle()
+	if err := statsHandle.InsertAnalyzeJob(job, analyzeJobInstance(), sctx.GetSessionVars().ConnectionID); err != nil {
+		logutil.BgLogger().Warn("[stats] insert analyze job failed", zap.Error(err))
+	}
+	statistics.AddNewAnalyzeJob(job)
+}
+
+func startAnalyzeJob(sctx sessionctx.Context, job *statistics.AnalyzeJob) {
+	if job == nil {
+		return
+	}
+	job.Start()
+	if err := domain.GetDomain(sctx).StatsHandle().StartAnalyzeJob(job); err != nil {
+		logutil.BgLogger().Warn("[stats] start analyze job failed", zap.Uint64("job id", job.ID), zap.Error(err))
+	}
+}
+
+func finishAnalyzeJob(sctx sessionctx.Context, job *statistics.AnalyzeJob, analyzeErr error) {
+	if job == nil {
+		return
+	}
+	job.Finish(analyzeErr)
+	if err := domain.GetDomain(sctx).StatsHandle().FinishAnalyzeJob(job); err != nil {
+		logutil.BgLogger().Warn("[stats] finish analyze job failed", zap.Uint64("job id", job.ID), zap.Error(err))
+	}
+}
+
+// checkAnalyzeJobKilled returns an error if the analyze job is killed by KILL ANALYZE.
+func checkAnalyzeJobKilled(job *statistics.AnalyzeJob) error {
+	if job.Killed() {
+		return ErrAnalyzeJobKilled.GenWithStackByArgs(job.ID)
+	}
+	return nil
+}
+
 func analyzeIndexPushdown(idxExec *AnalyzeIndexExec) *statistics.AnalyzeResults {
 	ranges := ranger.FullRange()
 	// For single-column index, we do not load null rows from TiKV, so the built histogram would not include
@@ -335,6 +410,14 @@ type baseAnalyzeExec struct {
 	snapshot    uint64
 }
 
+// indexTopNNum returns the TopN size of the index statistics, which can be enlarged by tidb_analyze_index_topn_num.
+func indexTopNNum(sctx sessionctx.Context, opts map[ast.AnalyzeOptionType]uint64) uint64 {
+	if num := uint64(sctx.GetSessionVars().AnalyzeIndexTopNNum); num > opts[ast.AnalyzeOptNumTopN] {
+		return num
+	}
+	return opts[ast.AnalyzeOptNumTopN]
+}
+
 func analyzeIndexNDVPushDown(idxExec *AnalyzeIndexExec) *statistics.AnalyzeResults {
 	ranges := ranger.FullRange()
 	// For single-column index, we do not load null rows from TiKV, so the built histogram would not include
@@ -484,7 +567,7 @@ func (e *AnalyzeIndexExec) buildStatsFromResult(result distsql.SelectResult, nee
 	var topn *statistics.TopN
 	if needCMS {
 		cms = statistics.NewCMSketch(int32(e.opts[ast.AnalyzeOptCMSketchDepth]), int32(e.opts[ast.AnalyzeOptCMSketchWidth]))
-		topn = statistics.NewTopN(int(e.opts[ast.AnalyzeOptNumTopN]))
+		topn = statistics.NewTopN(int(indexTopNNum(e.ctx, e.opts)))
 	}
 	fms := statistics.NewFMSketch(maxSketchSize)
 	statsVer := statistics.Version1
@@ -492,6 +575,9 @@ func (e *AnalyzeIndexExec) buildStatsFromResult(result distsql.SelectResult, nee
 		statsVer = int(*e.analyzePB.IdxReq.Version)
 	}
 	for {
+		if err := checkAnalyzeJobKilled(e.job); err != nil {
+			return nil, nil, nil, nil, err
+		}
 		data, err := result.NextRaw(context.TODO())
 		if err != nil {
 			return nil, nil, nil, nil, err
@@ -505,7 +591,7 @@ func (e *AnalyzeIndexExec) buildStatsFromResult(result distsql.SelectResult, nee
 			return nil, nil, nil, nil, err
 		}
 		hist, cms, fms, topn, err = updateIndexResult(e.ctx.GetSessionVars().StmtCtx, resp, e.job, hist, cms, fms, topn,
-			e.idxInfo, int(e.opts[ast.AnalyzeOptNumBuckets]), int(e.opts[ast.AnalyzeOptNumTopN]), statsVer)
+			e.idxInfo, int(e.opts[ast.AnalyzeOptNumBuckets]), int(indexTopNNum(e.ctx, e.opts)), statsVer)
 		if err != nil {
 			return nil, nil, nil, nil, err
 		}
@@ -724,6 +810,9 @@ type AnalyzeColumnsExec struct {
 	schemaForVirtualColEval *expression.Schema
 	baseCount               int64
 	baseModifyCnt           int64
+
+	// memTracker tracks the memory of the samples held by the merge workers, it's limited by tidb_mem_quota_analyze.
+	memTracker *memory.Tracker
 }
 
 func (e *AnalyzeColumnsExec) open(ranges []*ranger.Range) error {
@@ -807,9 +896,12 @@ func (e AnalyzeColumnsExec) decodeSampleDataWithVirtualColumn(
 	return nil
 }
 
-func readDataAndSendTask(handler *tableResultHandler, mergeTaskCh chan []byte) error {
+func readDataAndSendTask(handler *tableResultHandler, mergeTaskCh chan []byte, job *statistics.AnalyzeJob) error {
 	defer close(mergeTaskCh)
 	for {
+		if err := checkAnalyzeJobKilled(job); err != nil {
+			return err
+		}
 		data, err := handler.nextRaw(context.TODO())
 		if err != nil {
 			return errors.Trace(err)
@@ -844,12 +936,17 @@ func (e *AnalyzeColumnsExec) buildSamplingStats(
 		}
 	}()
 
+	sc := e.ctx.GetSessionVars().StmtCtx
+	e.memTracker = memory.NewTracker(memory.LabelForAnalyzeMemory, e.ctx.GetSessionVars().MemQuotaAnalyze)
+	if sc.MemTracker != nil {
+		e.memTracker.AttachTo(sc.MemTracker)
+		defer e.memTracker.Detach()
+	}
 	l := len(e.analyzePB.ColReq.ColumnsInfo) + len(e.analyzePB.ColReq.ColumnGroups)
 	rootRowCollector := statistics.NewRowSampleCollector(int(e.analyzePB.ColReq.SampleSize), e.analyzePB.ColReq.GetSampleRate(), l)
 	for i := 0; i < l; i++ {
 		rootRowCollector.Base().FMSketches = append(rootRowCollector.Base().FMSketches, statistics.NewFMSketch(maxSketchSize))
 	}
-	sc := e.ctx.GetSessionVars().StmtCtx
 	statsConcurrency, err := getBuildStatsConcurrency(e.ctx)
 	if err != nil {
 		return 0, nil, nil, nil, nil, err
@@ -861,7 +958,7 @@ func (e *AnalyzeColumnsExec) buildSamplingStats(
 	for i := 0; i < statsConcurrency; i++ {
 		go e.subMergeWorker(mergeResultCh, mergeTaskCh, l, i == 0)
 	}
-	if err = readDataAndSendTask(e.resultHandler, mergeTaskCh); err != nil {
+	if err = readDataAndSendTask(e.resultHandler, mergeTaskCh, e.job); err != nil {
 		return 0, nil, nil, nil, nil, err
 	}
 
@@ -878,11 +975,15 @@ func (e *AnalyzeColumnsExec) buildSamplingStats(
 			}
 			continue
 		}
+		oldMemSize := rootRowCollector.Base().MemSize
 		rootRowCollector.MergeCollector(mergeResult.collector)
+		// The samples that are not kept by the root collector are released.
+		e.memTracker.Consume(rootRowCollector.Base().MemSize - oldMemSize - mergeResult.collector.Base().MemSize)
 	}
 	if err != nil {
 		return 0, nil, nil, nil, nil, err
 	}
+	e.job.SetPhase(statistics.AnalyzePhaseBuilding)
 
 	// handling virtual columns
 	virtualColIdx := buildVirtualColumnIndex(e.schemaForVirtualColEval, e.colsInfo)
@@ -1020,7 +1121,7 @@ func (e *AnalyzeColumnsExec) handleNDVForSpecialIndexes(indexInfos []*model.Inde
 	statsConcurrncy, err := getBuildStatsConcurrency(e.ctx)
 	taskCh := make(chan *analyzeTask, len(tasks))
 	for _, task := range tasks {
-		statistics.AddNewAnalyzeJob(task.job)
+		registerAnalyzeJob(e.ctx, task.job)
 	}
 	resultsCh := make(chan *statistics.AnalyzeResults, len(tasks))
 	e.subIndexWorkerWg.Add(statsConcurrncy)
@@ -1041,17 +1142,19 @@ func (e *AnalyzeColumnsExec) handleNDVForSpecialIndexes(indexInfos []*model.Inde
 			break
 		}
 		if results.Err != nil {
-			results.Job.Finish(true)
+			finishAnalyzeJob(e.ctx, results.Job, results.Err)
 			err = results.Err
 			if err == errAnalyzeWorkerPanic {
 				panicCnt++
 			}
 			continue
 		}
-		results.Job.Finish(false)
-		statistics.MoveToHistory(results.Job)
+		finishAnalyzeJob(e.ctx, results.Job, nil)
 		totalResult.results[results.Ars[0].Hist[0].ID] = results
 	}
+	for _, task := range tasks {
+		statistics.RemoveAnalyzeJob(task.job)
+	}
 	if err != nil {
 		totalResult.err = err
 	}
@@ -1085,7 +1188,14 @@ func (e *AnalyzeColumnsExec) subIndexWorkerForNDV(taskCh chan *analyzeTask, resu
 		if !ok {
 			break
 		}
-		task.job.Start()
+		if err := checkAnalyzeJobKilled(e.job); err != nil {
+			resultsCh <- &statistics.AnalyzeResults{
+				Err: err,
+				Job: task.job,
+			}
+			continue
+		}
+		startAnalyzeJob(e.ctx, task.job)
 		if task.taskType != idxTask {
 			resultsCh <- &statistics.AnalyzeResults{
 				Err: errors.Errorf("incorrect analyze type"),
@@ -1211,7 +1321,13 @@ func (e *AnalyzeColumnsExec) subMergeWorker(resultCh chan<- *samplingMergeResult
 		subCollector := statistics.NewRowSampleCollector(int(e.analyzePB.ColReq.SampleSize), e.analyzePB.ColReq.GetSampleRate(), l)
 		subCollector.Base().FromProto(colResp.RowCollector)
 		e.job.Update(subCollector.Base().Count)
+		oldMemSize := retCollector.Base().MemSize
 		retCollector.MergeCollector(subCollector)
+		e.memTracker.Consume(retCollector.Base().MemSize - oldMemSize)
+		if e.memTracker.CheckExceed() {
+			resultCh <- &samplingMergeResult{err: ErrAnalyzeMemoryExceed.GenWithStackByArgs(e.memTracker.BytesConsumed(), e.memTracker.GetBytesLimit())}
+			return
+		}
 	}
 	resultCh <- &samplingMergeResult{collector: retCollector}
 }
@@ -1325,7 +1441,11 @@ workLoop:
 		if task.isColumn {
 			collectors[task.slicePos] = collector
 		}
-		hist, topn, err := statistics.BuildHistAndTopN(e.ctx, int(e.opts[ast.AnalyzeOptNumBuckets]), int(e.opts[ast.AnalyzeOptNumTopN]), task.id, collector, task.tp, task.isColumn)
+		numTopN := e.opts[ast.AnalyzeOptNumTopN]
+		if !task.isColumn {
+			numTopN = indexTopNNum(e.ctx, e.opts)
+		}
+		hist, topn, err := statistics.BuildHistAndTopN(e.ctx, int(e.opts[ast.AnalyzeOptNumBuckets]), int(numTopN), task.id, collector, task.tp, task.isColumn)
 		if err != nil {
 			resultCh <- err
 			continue
@@ -1356,7 +1476,7 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats boo
 	if e.analyzePB.Tp == tipb.AnalyzeType_TypeMixed {
 		handleHist = &statistics.Histogram{}
 		handleCms = statistics.NewCMSketch(int32(e.opts[ast.AnalyzeOptCMSketchDepth]), int32(e.opts[ast.AnalyzeOptCMSketchWidth]))
-		handleTopn = statistics.NewTopN(int(e.opts[ast.AnalyzeOptNumTopN]))
+		handleTopn = statistics.NewTopN(int(indexTopNNum(e.ctx, e.opts)))
 		handleFms = statistics.NewFMSketch(maxSketchSize)
 		if e.analyzePB.IdxReq.Version != nil {
 			statsVer = int(*e.analyzePB.IdxReq.Version)
@@ -1373,6 +1493,9 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats boo
 		}
 	}
 	for {
+		if err1 := checkAnalyzeJobKilled(e.job); err1 != nil {
+			return nil, nil, nil, nil, nil, err1
+		}
 		data, err1 := e.resultHandler.nextRaw(context.TODO())
 		if err1 != nil {
 			return nil, nil, nil, nil, nil, err1
@@ -1391,7 +1514,7 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats boo
 			colResp = resp.ColumnsResp
 			handleHist, handleCms, handleFms, handleTopn, err = updateIndexResult(sc, resp.IndexResp, nil, handleHist,
 				handleCms, handleFms, handleTopn, e.commonHandle, int(e.opts[ast.AnalyzeOptNumBuckets]),
-				int(e.opts[ast.AnalyzeOptNumTopN]), statsVer)
+				int(indexTopNNum(e.ctx, e.opts)), statsVer)
 
 			if err != nil {
 				return nil, nil, nil, nil, nil, err
@@ -1416,6 +1539,7 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats boo
 		}
 		e.job.Update(rowCount)
 	}
+	e.job.SetPhase(statistics.AnalyzePhaseBuilding)
 	timeZone := e.ctx.GetSessionVars().Location()
 	if hasPkHist(e.handleCols) {
 		pkInfo := e.handleCols.GetCol(0)
@@ -2129,14 +2253,14 @@ func analyzeIndexIncremental(idxExec *analyzeIndexIncrementalExec) *statistics.A
 		return &statistics.AnalyzeResults{Err: err, Job: idxExec.job}
 	}
 	if idxExec.oldCMS != nil && cms != nil {
-		err = cms.MergeCMSketch4IncrementalAnalyze(idxExec.oldCMS, uint32(idxExec.opts[ast.AnalyzeOptNumTopN]))
+		err = cms.MergeCMSketch4IncrementalAnalyze(idxExec.oldCMS, uint32(indexTopNNum(idxExec.ctx, idxExec.opts)))
 		if err != nil {
 			return &statistics.AnalyzeResults{Err: err, Job: idxExec.job}
 		}
 		cms.CalcDefaultValForAnalyze(uint64(hist.NDV))
 	}
 	if statsVer >= statistics.Version2 {
-		poped := statistics.MergeTopNAndUpdateCMSketch(topN, idxExec.oldTopN, cms, uint32(idxExec.opts[ast.AnalyzeOptNumTopN]))
+		poped := statistics.MergeTopNAndUpdateCMSketch(topN, idxExec.oldTopN, cms, uint32(indexTopNNum(idxExec.ctx, idxExec.opts)))
 		hist.AddIdxVals(poped)
 	}
 	result := &statistics.AnalyzeResult{
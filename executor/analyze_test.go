@@ -696,26 +696,26 @@ func TestExtractTopN(t *testing.T) {
 	idxItem := idxStats.TopN.TopN[0]
 	require.Equal(t, uint64(11), idxItem.Count)
 	// The columns are: DBName, table name, column name, is index, value, count.
-	tk.MustQuery("show stats_topn where column_name in ('b', 'index_b')").Sort().Check(testkit.Rows("test_extract_topn test_extract_topn  b 0 0 11",
-		"test_extract_topn test_extract_topn  b 0 1 1",
-		"test_extract_topn test_extract_topn  b 0 2 1",
-		"test_extract_topn test_extract_topn  b 0 3 1",
-		"test_extract_topn test_extract_topn  b 0 4 1",
-		"test_extract_topn test_extract_topn  b 0 5 1",
-		"test_extract_topn test_extract_topn  b 0 6 1",
-		"test_extract_topn test_extract_topn  b 0 7 1",
-		"test_extract_topn test_extract_topn  b 0 8 1",
-		"test_extract_topn test_extract_topn  b 0 9 1",
-		"test_extract_topn test_extract_topn  index_b 1 0 11",
-		"test_extract_topn test_extract_topn  index_b 1 1 1",
-		"test_extract_topn test_extract_topn  index_b 1 2 1",
-		"test_extract_topn test_extract_topn  index_b 1 3 1",
-		"test_extract_topn test_extract_topn  index_b 1 4 1",
-		"test_extract_topn test_extract_topn  index_b 1 5 1",
-		"test_extract_topn test_extract_topn  index_b 1 6 1",
-		"test_extract_topn test_extract_topn  index_b 1 7 1",
-		"test_extract_topn test_extract_topn  index_b 1 8 1",
-		"test_extract_topn test_extract_topn  index_b 1 9 1",
+	tk.MustQuery("show stats_topn where column_name in ('b', 'index_b')").Sort().Check(testkit.Rows("test_extract_topn test_extract_topn  b 0 0 11 1 5.5",
+		"test_extract_topn test_extract_topn  b 0 1 1 1 5.5",
+		"test_extract_topn test_extract_topn  b 0 2 1 1 5.5",
+		"test_extract_topn test_extract_topn  b 0 3 1 1 5.5",
+		"test_extract_topn test_extract_topn  b 0 4 1 1 5.5",
+		"test_extract_topn test_extract_topn  b 0 5 1 1 5.5",
+		"test_extract_topn test_extract_topn  b 0 6 1 1 5.5",
+		"test_extract_topn test_extract_topn  b 0 7 1 1 5.5",
+		"test_extract_topn test_extract_topn  b 0 8 1 1 5.5",
+		"test_extract_topn test_extract_topn  b 0 9 1 1 5.5",
+		"test_extract_topn test_extract_topn  index_b 1 0 11 1 5.5",
+		"test_extract_topn test_extract_topn  index_b 1 1 1 1 5.5",
+		"test_extract_topn test_extract_topn  index_b 1 2 1 1 5.5",
+		"test_extract_topn test_extract_topn  index_b 1 3 1 1 5.5",
+		"test_extract_topn test_extract_topn  index_b 1 4 1 1 5.5",
+		"test_extract_topn test_extract_topn  index_b 1 5 1 1 5.5",
+		"test_extract_topn test_extract_topn  index_b 1 6 1 1 5.5",
+		"test_extract_topn test_extract_topn  index_b 1 7 1 1 5.5",
+		"test_extract_topn test_extract_topn  index_b 1 8 1 1 5.5",
+		"test_extract_topn test_extract_topn  index_b 1 9 1 1 5.5",
 	))
 }
 
@@ -1166,16 +1166,16 @@ func TestIssue20874(t *testing.T) {
 	tk.MustExec("set @@tidb_analyze_version=2")
 	tk.MustExec("analyze table t")
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(testkit.Rows(
-		"test t  a 0 \x02\xd2 1",
-		"test t  a 0 \x0e\x0f 1",
-		"test t  a 0 \x0e3 1",
-		"test t  b 0 \x00A 1",
-		"test t  b 0 \x00C 2",
-		"test t  idxa 1 \x02\xd2 1",
-		"test t  idxa 1 \x0e\x0f 1",
-		"test t  idxa 1 \x0e3 1",
-		"test t  idxb 1 \x00A 1",
-		"test t  idxb 1 \x00C 2",
+		"test t  a 0 \x02\xd2 1 1 1",
+		"test t  a 0 \x0e\x0f 1 1 1",
+		"test t  a 0 \x0e3 1 1 1",
+		"test t  b 0 \x00A 1 1 1.3333333333333333",
+		"test t  b 0 \x00C 2 1 1.3333333333333333",
+		"test t  idxa 1 \x02\xd2 1 1 1",
+		"test t  idxa 1 \x0e\x0f 1 1 1",
+		"test t  idxa 1 \x0e3 1 1 1",
+		"test t  idxb 1 \x00A 1 1 1.3333333333333333",
+		"test t  idxb 1 \x00C 2 1 1.3333333333333333",
 	))
 	tk.MustQuery("select is_index, hist_id, distinct_count, null_count, tot_col_size, stats_ver, correlation from mysql.stats_histograms").Sort().Check(testkit.Rows(
 		"0 1 3 0 6 2 1",
@@ -1207,10 +1207,36 @@ func TestAnalyzeClusteredIndexPrimary(t *testing.T) {
 	tk.MustExec("analyze table t0")
 	tk.MustExec("analyze table t1")
 	tk.MustQuery("show stats_topn").Sort().Check(testkit.Rows(""+
-		"test t0  PRIMARY 1 1111 1",
-		"test t0  a 0 1111 1",
-		"test t1  PRIMARY 1 1111 1",
-		"test t1  a 0 1111 1"))
+		"test t0  PRIMARY 1 1111 1 1 1",
+		"test t0  a 0 1111 1 1 1",
+		"test t1  PRIMARY 1 1111 1 1 1",
+		"test t1  a 0 1111 1 1 1"))
+}
+
+func TestAnalyzeIndexTopNNum(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("create table t(a int, b int, index idx(b))")
+	tk.MustExec("insert into t values(1, 1), (2, 1), (3, 1), (4, 1), (5, 2), (6, 2), (7, 2), (8, 3), (9, 3), (10, 4)")
+	tk.MustExec("analyze table t with 1 topn")
+	require.Len(t, tk.MustQuery("show stats_topn where table_name = 't' and column_name = 'b'").Rows(), 1)
+	require.Len(t, tk.MustQuery("show stats_topn where table_name = 't' and column_name = 'idx'").Rows(), 1)
+
+	// Only the TopN of the indexes is enlarged.
+	tk.MustExec("set @@session.tidb_analyze_index_topn_num = 3")
+	tk.MustExec("analyze table t with 1 topn")
+	tk.MustQuery("show stats_topn where table_name = 't' and column_name = 'b'").Check(testkit.Rows("test t  b 0 1 4 0.4 1.6"))
+	tk.MustQuery("show stats_topn where table_name = 't' and column_name = 'idx'").Sort().Check(testkit.Rows(
+		"test t  idx 1 1 4 0.9 1.6",
+		"test t  idx 1 2 3 0.9 1.6",
+		"test t  idx 1 3 2 0.9 1.6"))
+	// A larger TopN size of the ANALYZE statement is kept.
+	tk.MustExec("analyze table t with 4 topn")
+	require.Len(t, tk.MustQuery("show stats_topn where table_name = 't' and column_name = 'idx'").Rows(), 4)
 }
 
 func TestAnalyzeSamplingWorkPanic(t *testing.T) {
@@ -1233,3 +1259,79 @@ func TestAnalyzeSamplingWorkPanic(t *testing.T) {
 	require.NotNil(t, err)
 	require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/mockAnalyzeSamplingMergeWorkerPanic"))
 }
+
+func TestAnalyzeMemoryQuota(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("create table t(a int, b varchar(64))")
+	tk.MustExec("insert into t values(1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e'), (6, 'f'), (7, 'g'), (8, 'h')")
+	tk.MustExec("split table t between (-9223372036854775808) and (9223372036854775807) regions 4")
+
+	tk.MustExec("set @@session.tidb_mem_quota_analyze = 1")
+	err := tk.ExecToErr("analyze table t with 1 samplerate")
+	require.True(t, executor.ErrAnalyzeMemoryExceed.Equal(err))
+	err = tk.ExecToErr("analyze table t with 16 samples")
+	require.True(t, executor.ErrAnalyzeMemoryExceed.Equal(err))
+
+	tk.MustExec("set @@session.tidb_mem_quota_analyze = -1")
+	tk.MustExec("analyze table t with 1 samplerate")
+	tk.MustQuery("select count(*) from mysql.stats_histograms where table_id = (select tidb_table_id from information_schema.tables where table_name = 't')").Check(testkit.Rows("2"))
+	tk.MustExec("set @@session.tidb_mem_quota_analyze = 1048576")
+	tk.MustExec("analyze table t with 0.5 samplerate")
+}
+
+func TestLockStats(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("create table t1(a int, b int, index idx(b))")
+	tk.MustExec("create table t2(a int) partition by hash(a) partitions 2")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1), (2), (3)")
+	histCount := func(table string) string {
+		return fmt.Sprintf("select count(*) from mysql.stats_histograms where table_id in "+
+			"(select tidb_table_id from information_schema.tables where table_name = '%[1]s' "+
+			"union select tidb_partition_id from information_schema.partitions where table_name = '%[1]s')", table)
+	}
+
+	tk.MustExec("lock stats t1, t2")
+	tk.MustQuery("select count(*) from mysql.stats_table_locked").Check(testkit.Rows("2"))
+	tk.MustExec("lock stats t1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip locking locked table: test.t1"))
+
+	// ANALYZE skips the locked tables.
+	tk.MustExec("analyze table t1, t2")
+	tk.MustQuery("show warnings").Sort().Check(testkit.Rows(
+		"Warning 1105 skip analyze locked table: test.t1",
+		"Warning 1105 skip analyze locked table: test.t2",
+	))
+	tk.MustQuery(histCount("t1")).Check(testkit.Rows("0"))
+	tk.MustQuery(histCount("t2")).Check(testkit.Rows("0"))
+	tk.MustExec("analyze table t2 partition p0")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip analyze locked table: test.t2"))
+	tk.MustQuery(histCount("t2")).Check(testkit.Rows("0"))
+
+	tk.MustExec("unlock stats t1")
+	tk.MustExec("unlock stats t1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip unlocking unlocked table: test.t1"))
+	tk.MustExec("analyze table t1, t2")
+	tk.MustQuery("show warnings where level = 'Warning'").Check(testkit.Rows("Warning 1105 skip analyze locked table: test.t2"))
+	tk.MustQuery(histCount("t1")).Check(testkit.Rows("3"))
+	tk.MustQuery(histCount("t2")).Check(testkit.Rows("0"))
+
+	tk.MustExec("unlock stats t2")
+	tk.MustQuery("select count(*) from mysql.stats_table_locked").Check(testkit.Rows("0"))
+	tk.MustExec("analyze table t2")
+	tk.MustQuery(histCount("t2")).Check(testkit.Rows("3"))
+
+	tk.MustExec("create view v as select * from t1")
+	tk.MustGetErrCode("lock stats v", mysql.ErrWrongObject)
+	tk.MustGetErrCode("lock stats t3", mysql.ErrNoSuchTable)
+}
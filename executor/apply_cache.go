@@ -31,6 +31,9 @@ type applyCache struct {
 	cache       *kvcache.SimpleLRUCache // cache.Get/Put are not thread-safe, so it's protected by the lock above
 	memCapacity int64
 	memTracker  *memory.Tracker // track memory usage.
+	// loading records the keys whose values are being fetched by the workers of the
+	// parallel apply, the other workers wait for them instead of fetching the same values again.
+	loading map[string]chan struct{}
 }
 
 type applyCacheKey []byte
@@ -51,6 +54,7 @@ func newApplyCache(ctx sessionctx.Context) (*applyCache, error) {
 		cache:       cache,
 		memCapacity: ctx.GetSessionVars().MemQuotaApplyCache,
 		memTracker:  memory.NewTracker(memory.LabelForApplyCache, -1),
+		loading:     make(map[string]chan struct{}),
 	}
 	return &c, nil
 }
@@ -61,12 +65,6 @@ func (c *applyCache) get(key applyCacheKey) (value kvcache.Value, ok bool) {
 	return c.cache.Get(key)
 }
 
-func (c *applyCache) put(key applyCacheKey, val kvcache.Value) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	c.cache.Put(key, val)
-}
-
 // Get gets a cache item according to cache key. It's thread-safe.
 func (c *applyCache) Get(key applyCacheKey) (*chunk.List, error) {
 	value, hit := c.get(key)
@@ -77,12 +75,47 @@ func (c *applyCache) Get(key applyCacheKey) (*chunk.List, error) {
 	return typedValue, nil
 }
 
+// GetOrReserve gets a cache item according to cache key. If the item is being fetched by
+// another caller, it waits until that caller releases the key. If the item is neither in the
+// cache nor being fetched, the key is reserved and the caller should fetch the item, insert
+// it by Set and then Release the key. It's thread-safe.
+func (c *applyCache) GetOrReserve(key applyCacheKey) (value *chunk.List, reserved bool) {
+	for {
+		c.lock.Lock()
+		if value, hit := c.cache.Get(key); hit {
+			c.lock.Unlock()
+			return value.(*chunk.List), false
+		}
+		wait, ok := c.loading[string(key)]
+		if !ok {
+			c.loading[string(key)] = make(chan struct{})
+			c.lock.Unlock()
+			return nil, true
+		}
+		c.lock.Unlock()
+		// The item may be not inserted since it's too large, so look up the cache again.
+		<-wait
+	}
+}
+
+// Release releases the key reserved by GetOrReserve and wakes up the callers waiting for it.
+func (c *applyCache) Release(key applyCacheKey) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if wait, ok := c.loading[string(key)]; ok {
+		close(wait)
+		delete(c.loading, string(key))
+	}
+}
+
 // Set inserts an item to the cache. It's thread-safe.
 func (c *applyCache) Set(key applyCacheKey, value *chunk.List) (bool, error) {
 	mem := applyCacheKVMem(key, value)
 	if mem > c.memCapacity { // ignore this kv pair if its size is too large
 		return false, nil
 	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
 	for mem+c.memTracker.BytesConsumed() > c.memCapacity {
 		evictedKey, evictedValue, evicted := c.cache.RemoveOldest()
 		if !evicted {
@@ -91,7 +124,7 @@ func (c *applyCache) Set(key applyCacheKey, value *chunk.List) (bool, error) {
 		c.memTracker.Consume(-applyCacheKVMem(evictedKey.(applyCacheKey), evictedValue.(*chunk.List)))
 	}
 	c.memTracker.Consume(mem)
-	c.put(key, value)
+	c.cache.Put(key, value)
 	return true, nil
 }
 
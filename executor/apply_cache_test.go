@@ -77,3 +77,53 @@ func TestApplyCache(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, result)
 }
+
+func TestApplyCacheGetOrReserve(t *testing.T) {
+	ctx := mock.NewContext()
+	ctx.GetSessionVars().MemQuotaApplyCache = 100
+	applyCache, err := newApplyCache(ctx)
+	require.NoError(t, err)
+
+	fields := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}
+	value := chunk.NewList(fields, 1, 1)
+	srcChunk := chunk.NewChunkWithCapacity(fields, 1)
+	srcChunk.AppendInt64(0, 1)
+	value.AppendRow(srcChunk.GetRow(0))
+	key := applyCacheKey(strings.Repeat("1", 100))
+
+	result, reserved := applyCache.GetOrReserve(key)
+	require.True(t, reserved)
+	require.Nil(t, result)
+
+	// The other callers wait for the reserved key.
+	resultCh := make(chan *chunk.List, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			result, _ := applyCache.GetOrReserve(key)
+			resultCh <- result
+		}()
+	}
+	ok, err := applyCache.Set(key, value)
+	require.NoError(t, err)
+	require.True(t, ok)
+	applyCache.Release(key)
+	for i := 0; i < 2; i++ {
+		require.Equal(t, value, <-resultCh)
+	}
+
+	// The key is reserved by the next caller if the item isn't inserted.
+	key = applyCacheKey(strings.Repeat("2", 200))
+	_, reserved = applyCache.GetOrReserve(key)
+	require.True(t, reserved)
+	reservedCh := make(chan bool)
+	go func() {
+		_, reserved := applyCache.GetOrReserve(key)
+		reservedCh <- reserved
+	}()
+	ok, err = applyCache.Set(key, value)
+	require.NoError(t, err)
+	require.False(t, ok)
+	applyCache.Release(key)
+	require.True(t, <-reservedCh)
+	applyCache.Release(key)
+}
@@ -100,7 +100,7 @@ func getKeysNeedCheckOneRow(ctx sessionctx.Context, t table.Table, row []types.D
 		t, err = p.GetPartitionByRow(ctx, row)
 		if err != nil {
 			if terr, ok := errors.Cause(err).(*terror.Error); ctx.GetSessionVars().StmtCtx.IgnoreNoPartition && ok && (terr.Code() == errno.ErrNoPartitionForGivenValue || terr.Code() == errno.ErrRowDoesNotMatchGivenPartitionSet) {
-				ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+				appendDMLError(ctx, row, err)
 				result = append(result, toBeCheckedRow{ignored: true})
 				return result, nil
 			}
@@ -214,6 +214,9 @@ func (e *BatchPointGetExec) Close() error {
 	if e.runtimeStats != nil && e.snapshot != nil {
 		e.snapshot.SetOption(kv.CollectRuntimeStats, nil)
 	}
+	if e.idxInfo != nil && atomic.LoadUint32(&e.inited) == 1 {
+		e.storeIndexUsage(e.tblInfo, e.idxInfo)
+	}
 	e.inited = 0
 	e.index = 0
 	return nil
@@ -275,14 +278,21 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 				continue
 			}
 
-			physID, err := getPhysID(e.tblInfo, e.partExpr, idxVals[e.partPos].GetInt64())
-			if err != nil {
-				continue
-			}
+			var physID int64
+			if e.idxInfo.Global {
+				// The global index is encoded with the ID of the partitioned table, the partitions of the rows are
+				// decided by the index values.
+				physID = e.tblInfo.ID
+			} else {
+				physID, err = getPhysID(e.tblInfo, e.partExpr, idxVals[e.partPos].GetInt64())
+				if err != nil {
+					continue
+				}
 
-			// If this BatchPointGetExec is built only for the specific table partition, skip those filters not matching this partition.
-			if e.singlePart && e.partTblID != physID {
-				continue
+				// If this BatchPointGetExec is built only for the specific table partition, skip those filters not matching this partition.
+				if e.singlePart && e.partTblID != physID {
+					continue
+				}
 			}
 			idxKey, err1 := EncodeUniqueIndexKey(e.ctx, e.tblInfo, e.idxInfo, idxVals, physID)
 			if err1 != nil && !kv.ErrNotExist.Equal(err1) {
@@ -345,7 +355,15 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 				indexKeys = append(indexKeys, key)
 			}
 			if e.tblInfo.Partition != nil {
-				pid := tablecodec.DecodeTableID(key)
+				var pid int64
+				if e.idxInfo.Global {
+					pid, err1 = tablecodec.DecodePartitionIDInGlobalIndexValue(handleVal)
+					if err1 != nil {
+						return err1
+					}
+				} else {
+					pid = tablecodec.DecodeTableID(key)
+				}
 				e.physIDs = append(e.physIDs, pid)
 				if e.lock {
 					e.updateDeltaForTableID(pid)
@@ -245,10 +245,14 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildStreamAgg(v)
 	case *plannercore.PhysicalProjection:
 		return b.buildProjection(v)
+	case *plannercore.PhysicalExpand:
+		return b.buildExpand(v)
 	case *plannercore.PhysicalMemTable:
 		return b.buildMemTable(v)
 	case *plannercore.PhysicalTableDual:
 		return b.buildTableDual(v)
+	case *plannercore.PhysicalJSONTable:
+		return b.buildJSONTable(v)
 	case *plannercore.PhysicalApply:
 		return b.buildApply(v)
 	case *plannercore.PhysicalMaxOneRow:
@@ -425,6 +429,14 @@ func buildIndexLookUpChecker(b *executorBuilder, p *plannercore.PhysicalIndexLoo
 }
 
 func (b *executorBuilder) buildCheckTable(v *plannercore.CheckTable) Executor {
+	if b.ctx.GetSessionVars().FastCheckTable && !v.CheckIndex && canFastCheckTable(v.Table, v.IndexInfos) {
+		return &FastCheckTableExec{
+			baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+			dbName:       v.DBName,
+			table:        v.Table,
+			indexInfos:   v.IndexInfos,
+		}
+	}
 	readerExecs := make([]*IndexLookUpExecutor, 0, len(v.IndexLookUpReaders))
 	for _, readerPlan := range v.IndexLookUpReaders {
 		readerExec, err := buildNoRangeIndexLookUpReader(b, readerPlan)
@@ -910,6 +922,7 @@ func (b *executorBuilder) buildLoadStats(v *plannercore.LoadStats) Executor {
 	e := &LoadStatsExec{
 		baseExecutor: newBaseExecutor(b.ctx, nil, v.ID()),
 		info:         &LoadStatsInfo{v.Path, b.ctx},
+		json:         v.JSON,
 	}
 	return e
 }
@@ -1433,6 +1446,21 @@ func (b *executorBuilder) buildSelection(v *plannercore.PhysicalSelection) Execu
 	return e
 }
 
+func (b *executorBuilder) buildExpand(v *plannercore.PhysicalExpand) Executor {
+	childExec := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	e := &ExpandExec{
+		baseExecutor:    newBaseExecutor(b.ctx, v.Schema(), v.ID(), childExec),
+		levelEvaluators: make([]*expression.EvaluatorSuite, 0, len(v.LevelExprs)),
+	}
+	for _, exprs := range v.LevelExprs {
+		e.levelEvaluators = append(e.levelEvaluators, expression.NewEvaluatorSuite(exprs, true))
+	}
+	return e
+}
+
 func (b *executorBuilder) buildProjection(v *plannercore.PhysicalProjection) Executor {
 	childExec := b.build(v.Children()[0])
 	if b.err != nil {
@@ -1474,6 +1502,14 @@ func (b *executorBuilder) buildTableDual(v *plannercore.PhysicalTableDual) Execu
 	return e
 }
 
+func (b *executorBuilder) buildJSONTable(v *plannercore.PhysicalJSONTable) Executor {
+	return &JSONTableExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		expr:         v.Expr,
+		rowPath:      v.RowPath,
+	}
+}
+
 // `getSnapshotTS` returns the timestamp of the snapshot that a reader should read.
 func (b *executorBuilder) getSnapshotTS() (uint64, error) {
 	// `refreshForUpdateTSForRC` should always be invoked before returning the cached value to
@@ -1514,6 +1550,19 @@ func (b *executorBuilder) getSnapshotTS() (uint64, error) {
 }
 
 func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executor {
+	if v.Table.ExternalTable != nil {
+		e := &MemTableReaderExec{
+			baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+			table:        v.Table,
+		}
+		e.retriever = &externalTableRetriever{
+			table:      v.Table,
+			outputCols: v.Columns,
+			conditions: v.Extractor.(*plannercore.ExternalTableExtractor).Conditions,
+			retTypes:   retTypes(e),
+		}
+		return e
+	}
 	switch v.DBName.L {
 	case util.MetricSchemaName.L:
 		return &MemTableReaderExec{
@@ -1534,6 +1583,14 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 					extractor: v.Extractor.(*plannercore.ClusterTableExtractor),
 				},
 			}
+		case strings.ToLower(infoschema.TableClusterConfigDiff):
+			return &MemTableReaderExec{
+				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+				table:        v.Table,
+				retriever: &clusterConfigDiffRetriever{
+					extractor: v.Extractor.(*plannercore.ClusterTableExtractor),
+				},
+			}
 		case strings.ToLower(infoschema.TableClusterLoad):
 			return &MemTableReaderExec{
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
@@ -1586,6 +1643,16 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 					timeRange: v.QueryTimeRange,
 				},
 			}
+		case strings.ToLower(infoschema.TableInspectionReport):
+			return &MemTableReaderExec{
+				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+				table:        v.Table,
+				retriever: &inspectionResultRetriever{
+					extractor:      v.Extractor.(*plannercore.InspectionResultTableExtractor),
+					timeRange:      v.QueryTimeRange,
+					withSuggestion: true,
+				},
+			}
 		case strings.ToLower(infoschema.TableInspectionSummary):
 			return &MemTableReaderExec{
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
@@ -1625,10 +1692,10 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 				},
 			}
 		case strings.ToLower(infoschema.TableSchemata),
-			strings.ToLower(infoschema.TableStatistics),
 			strings.ToLower(infoschema.TableTiDBIndexes),
+			strings.ToLower(infoschema.TableTiDBIndexUsage),
+			strings.ToLower(infoschema.TableTiDBAutoRandomIDSpace),
 			strings.ToLower(infoschema.TableViews),
-			strings.ToLower(infoschema.TableTables),
 			strings.ToLower(infoschema.TableReferConst),
 			strings.ToLower(infoschema.TableSequences),
 			strings.ToLower(infoschema.TablePartitions),
@@ -1654,11 +1721,19 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TableTiKVStoreStatus),
 			strings.ToLower(infoschema.TableStatementsSummaryEvicted),
 			strings.ToLower(infoschema.ClusterTableStatementsSummaryEvicted),
+			strings.ToLower(infoschema.TableTrxSummary),
+			strings.ToLower(infoschema.ClusterTableTrxSummary),
 			strings.ToLower(infoschema.TableClientErrorsSummaryGlobal),
 			strings.ToLower(infoschema.TableClientErrorsSummaryByUser),
 			strings.ToLower(infoschema.TableClientErrorsSummaryByHost),
 			strings.ToLower(infoschema.TableAttributes),
-			strings.ToLower(infoschema.TablePlacementRules):
+			strings.ToLower(infoschema.TablePlacementRules),
+			strings.ToLower(infoschema.TableTiDBSpillFiles),
+			strings.ToLower(infoschema.TableSessionConnectAttrs),
+			strings.ToLower(infoschema.TableTiKVEncryptionStatus),
+			strings.ToLower(infoschema.TableMemoryUsage),
+			strings.ToLower(infoschema.TableMemoryUsageOpsHistory),
+			strings.ToLower(infoschema.TablePlanReplayerCaptures):
 			return &MemTableReaderExec{
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 				table:        v.Table,
@@ -1667,6 +1742,17 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 					columns: v.Columns,
 				},
 			}
+		case strings.ToLower(infoschema.TableTables),
+			strings.ToLower(infoschema.TableStatistics):
+			return &MemTableReaderExec{
+				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+				table:        v.Table,
+				retriever: &memtableRetriever{
+					table:     v.Table,
+					columns:   v.Columns,
+					extractor: v.Extractor.(*plannercore.InfoSchemaTablesExtractor),
+				},
+			}
 		case strings.ToLower(infoschema.TableTiDBTrx),
 			strings.ToLower(infoschema.ClusterTableTiDBTrx):
 			return &MemTableReaderExec{
@@ -1714,8 +1800,9 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 				table:        v.Table,
 				retriever: &hugeMemTableRetriever{
-					table:   v.Table,
-					columns: v.Columns,
+					table:     v.Table,
+					columns:   v.Columns,
+					extractor: v.Extractor.(*plannercore.InfoSchemaTablesExtractor),
 				},
 			}
 
@@ -2153,7 +2240,7 @@ func (b *executorBuilder) buildAnalyzeIndexPushdown(task plannercore.AnalyzeInde
 		idxInfo:         task.IndexInfo,
 	}
 	topNSize := new(int32)
-	*topNSize = int32(opts[ast.AnalyzeOptNumTopN])
+	*topNSize = int32(indexTopNNum(b.ctx, opts))
 	statsVersion := new(int32)
 	*statsVersion = int32(task.StatsVersion)
 	e.analyzePB.IdxReq = &tipb.AnalyzeIndexReq{
@@ -2438,7 +2525,7 @@ func (b *executorBuilder) buildAnalyzeColumnsPushdown(task plannercore.AnalyzeCo
 	}
 	if task.CommonHandleInfo != nil {
 		topNSize := new(int32)
-		*topNSize = int32(opts[ast.AnalyzeOptNumTopN])
+		*topNSize = int32(indexTopNNum(b.ctx, opts))
 		statsVersion := new(int32)
 		*statsVersion = int32(task.StatsVersion)
 		e.analyzePB.IdxReq = &tipb.AnalyzeIndexReq{
@@ -2606,7 +2693,29 @@ func (b *executorBuilder) buildAnalyze(v *plannercore.Analyze) Executor {
 	if b.ctx.GetSessionVars().InRestrictedSQL {
 		autoAnalyze = "auto "
 	}
+	lockedTables, err := domain.GetDomain(b.ctx).StatsHandle().GetLockedTables()
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	skippedTables := make(map[int64]struct{})
+	// isLocked checks whether the stats of the table are locked, the locked tables are skipped with a warning.
+	isLocked := func(info plannercore.AnalyzeInfo) bool {
+		tableID := info.TableID.TableID
+		if _, ok := lockedTables[tableID]; !ok {
+			return false
+		}
+		if _, ok := skippedTables[tableID]; !ok {
+			skippedTables[tableID] = struct{}{}
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("skip analyze locked table: %s.%s", info.DBName, info.TableName))
+		}
+		return true
+	}
 	for _, task := range v.ColTasks {
+		if isLocked(task.AnalyzeInfo) {
+			continue
+		}
+		numTasks := len(e.tasks)
 		if task.Incremental {
 			e.tasks = append(e.tasks, b.buildAnalyzePKIncremental(task, v.Opts))
 		} else {
@@ -2625,8 +2734,13 @@ func (b *executorBuilder) buildAnalyze(v *plannercore.Analyze) Executor {
 		if b.err != nil {
 			return nil
 		}
+		b.setAnalyzeJobsEstimatedRows(e.tasks[numTasks:], task.TblInfo, task.TableID)
 	}
 	for _, task := range v.IdxTasks {
+		if isLocked(task.AnalyzeInfo) {
+			continue
+		}
+		numTasks := len(e.tasks)
 		if task.Incremental {
 			e.tasks = append(e.tasks, b.buildAnalyzeIndexIncremental(task, v.Opts))
 		} else {
@@ -2639,10 +2753,26 @@ func (b *executorBuilder) buildAnalyze(v *plannercore.Analyze) Executor {
 		if b.err != nil {
 			return nil
 		}
+		b.setAnalyzeJobsEstimatedRows(e.tasks[numTasks:], task.TblInfo, task.TableID)
 	}
 	return e
 }
 
+// setAnalyzeJobsEstimatedRows sets the estimated row count of the analyze jobs by the cached statistics, which is used
+// to calculate the remaining time of the jobs.
+func (b *executorBuilder) setAnalyzeJobsEstimatedRows(tasks []*analyzeTask, tblInfo *model.TableInfo, tableID statistics.AnalyzeTableID) {
+	if len(tasks) == 0 {
+		return
+	}
+	statsTbl := domain.GetDomain(b.ctx).StatsHandle().GetPartitionStats(tblInfo, tableID.GetStatisticsID())
+	if statsTbl.Pseudo {
+		return
+	}
+	for _, task := range tasks {
+		task.job.EstimatedRows = statsTbl.Count
+	}
+}
+
 func constructDistExec(sctx sessionctx.Context, plans []plannercore.PhysicalPlan) ([]*tipb.Executor, bool, error) {
 	streaming := true
 	executors := make([]*tipb.Executor, 0, len(plans))
@@ -2979,6 +3109,9 @@ func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableRea
 	if err != nil {
 		return nil, err
 	}
+	if v.Paging {
+		streaming = false
+	}
 	ts := v.GetTableScan()
 	if err = b.validCanReadTemporaryOrCacheTable(ts.Table); err != nil {
 		return nil, err
@@ -3005,6 +3138,7 @@ func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableRea
 		desc:             ts.Desc,
 		columns:          ts.Columns,
 		streaming:        streaming,
+		paging:           v.Paging,
 		corColInFilter:   b.corColInDistPlan(v.TablePlans),
 		corColInAccess:   b.corColInAccess(v.TablePlans[0]),
 		plans:            v.TablePlans,
@@ -3256,6 +3390,9 @@ func buildNoRangeIndexReader(b *executorBuilder, v *plannercore.PhysicalIndexRea
 	if err != nil {
 		return nil, err
 	}
+	if v.Paging {
+		streaming = false
+	}
 	is := v.IndexPlans[0].(*plannercore.PhysicalIndexScan)
 	tbl, _ := b.is.TableByID(is.Table.ID)
 	isPartition, physicalTableID := is.IsPartition()
@@ -3282,6 +3419,7 @@ func buildNoRangeIndexReader(b *executorBuilder, v *plannercore.PhysicalIndexRea
 		desc:             is.Desc,
 		columns:          is.Columns,
 		streaming:        streaming,
+		paging:           v.Paging,
 		corColInFilter:   b.corColInDistPlan(v.IndexPlans),
 		corColInAccess:   b.corColInAccess(v.IndexPlans[0]),
 		idxCols:          is.IdxCols,
@@ -4166,7 +4304,7 @@ func buildKvRangesForIndexJoin(ctx sessionctx.Context, tableID, indexID int64, l
 		sort.Slice(kvRanges, func(i, j int) bool {
 			return bytes.Compare(kvRanges[i].StartKey, kvRanges[j].StartKey) < 0
 		})
-		return kvRanges, nil
+		return mergeSortedKVRanges(kvRanges), nil
 	}
 
 	tmpDatumRanges, err = ranger.UnionRanges(ctx, tmpDatumRanges, true)
@@ -4180,6 +4318,26 @@ func buildKvRangesForIndexJoin(ctx sessionctx.Context, tableID, indexID int64, l
 	return distsql.IndexRangesToKVRangesWithInterruptSignal(ctx.GetSessionVars().StmtCtx, tableID, indexID, tmpDatumRanges, nil, memTracker, interruptSignal)
 }
 
+// mergeSortedKVRanges merges the overlapped or adjacent ranges in place, so the inner side of index join sends
+// fewer ranges to the coprocessor. The ranges must be sorted by the start key.
+func mergeSortedKVRanges(kvRanges []kv.KeyRange) []kv.KeyRange {
+	if len(kvRanges) < 2 {
+		return kvRanges
+	}
+	merged := kvRanges[:1]
+	for _, ran := range kvRanges[1:] {
+		last := &merged[len(merged)-1]
+		if bytes.Compare(ran.StartKey, last.EndKey) > 0 {
+			merged = append(merged, ran)
+			continue
+		}
+		if bytes.Compare(ran.EndKey, last.EndKey) > 0 {
+			last.EndKey = ran.EndKey
+		}
+	}
+	return merged
+}
+
 func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 	childExec := b.build(v.Children()[0])
 	if b.err != nil {
@@ -4210,7 +4368,8 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 		resultColIdx++
 	}
 
-	if b.ctx.GetSessionVars().EnablePipelinedWindowExec {
+	// The pipelined window executor doesn't support excluding rows from the frames.
+	if b.ctx.GetSessionVars().EnablePipelinedWindowExec && (v.Frame == nil || v.Frame.Exclusion == ast.ExcludeNoOthers) {
 		exec := &PipelinedWindowExec{
 			baseExecutor:   base,
 			groupChecker:   newVecGroupChecker(b.ctx, groupByItems),
@@ -4244,6 +4403,10 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 		return exec
 	}
 	var processor windowProcessor
+	var excluder *frameExcluder
+	if v.Frame != nil && v.Frame.Exclusion != ast.ExcludeNoOthers {
+		excluder = newFrameExcluder(v.Frame.Exclusion, orderByCols)
+	}
 	if v.Frame == nil {
 		processor = &aggWindowProcessor{
 			windowFuncs:    windowFuncs,
@@ -4255,6 +4418,7 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 			partialResults: partialResults,
 			start:          v.Frame.Start,
 			end:            v.Frame.End,
+			excluder:       excluder,
 		}
 	} else {
 		cmpResult := int64(-1)
@@ -4268,6 +4432,7 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 			end:               v.Frame.End,
 			orderByCols:       orderByCols,
 			expectedCmpResult: cmpResult,
+			excluder:          excluder,
 		}
 	}
 	return &WindowExec{baseExecutor: base,
@@ -353,12 +353,16 @@ func GetStmtLabel(stmtNode ast.StmtNode) string {
 		return "CreateView"
 	case *ast.CreateUserStmt:
 		return "CreateUser"
+	case *ast.CreateFunctionStmt:
+		return "CreateFunction"
 	case *ast.DeleteStmt:
 		return "Delete"
 	case *ast.DropDatabaseStmt:
 		return "DropDatabase"
 	case *ast.DropIndexStmt:
 		return "DropIndex"
+	case *ast.DropFunctionStmt:
+		return "DropFunction"
 	case *ast.DropTableStmt:
 		if x.IsView {
 			return "DropView"
@@ -375,6 +379,10 @@ func GetStmtLabel(stmtNode ast.StmtNode) string {
 		return "LoadData"
 	case *ast.RollbackStmt:
 		return "RollBack"
+	case *ast.SavepointStmt:
+		return "Savepoint"
+	case *ast.ReleaseSavepointStmt:
+		return "ReleaseSavepoint"
 	case *ast.SelectStmt:
 		return "Select"
 	case *ast.SetStmt, *ast.SetPwdStmt:
@@ -407,6 +415,8 @@ func GetStmtLabel(stmtNode ast.StmtNode) string {
 		return "Trace"
 	case *ast.ShutdownStmt:
 		return "Shutdown"
+	case *ast.NonTransactionalDMLStmt:
+		return "NonTransactionalDML"
 	}
 	return "other"
 }
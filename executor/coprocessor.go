@@ -148,7 +148,7 @@ func (h *CoprocessorDAGHandler) buildDAGExecutor(req *coprocessor.Request) (Exec
 			if success && pm.GetAuthWithoutVerification(authName, authHost) {
 				h.sctx.GetSessionVars().User.AuthUsername = authName
 				h.sctx.GetSessionVars().User.AuthHostname = authHost
-				h.sctx.GetSessionVars().ActiveRoles = pm.GetDefaultRoles(authName, authHost)
+				h.sctx.GetSessionVars().ActiveRoles = pm.GetLoginRoles(authName, authHost)
 			}
 		}
 	}
@@ -141,6 +141,23 @@ func TestCTEMaxRecursionDepth(t *testing.T) {
 	rows.Check(testkit.Rows("1", "2"))
 	rows = tk.MustQuery("with cte1(c1) as (select 1 union select 2) select * from cte1 order by c1;")
 	rows.Check(testkit.Rows("1", "2"))
+
+	// The depth can be limited by the statement with the SET_VAR hint.
+	rows = tk.MustQuery("with recursive cte1(c1) as (select 1 union select c1 + 1 c1 from cte1 where c1 < 5) select /*+ SET_VAR(cte_max_recursion_depth=10) */ * from cte1;")
+	rows.Check(testkit.Rows("1", "2", "3", "4", "5"))
+	err = tk.QueryToErr("with recursive cte1(c1) as (select 1 union select c1 + 1 c1 from cte1 where c1 < 5) select /*+ SET_VAR(cte_max_recursion_depth=2) */ * from cte1;")
+	require.EqualError(t, err, "[executor:3636]Recursive query aborted after 3 iterations. Try increasing @@cte_max_recursion_depth to a larger value")
+	tk.MustQuery("select @@cte_max_recursion_depth").Check(testkit.Rows("1"))
+
+	// UNION DISTINCT stops the recursion on the cycles.
+	tk.MustExec("drop table if exists edges")
+	tk.MustExec("create table edges(src int, dst int)")
+	tk.MustExec("insert into edges values (1, 2), (2, 3), (3, 1)")
+	tk.MustExec("set @@cte_max_recursion_depth = 10;")
+	rows = tk.MustQuery("with recursive cte1(c1) as (select 1 union select dst from cte1 join edges on c1 = src) select * from cte1 order by c1;")
+	rows.Check(testkit.Rows("1", "2", "3"))
+	err = tk.QueryToErr("with recursive cte1(c1) as (select 1 union all select dst from cte1 join edges on c1 = src) select * from cte1;")
+	require.EqualError(t, err, "[executor:3636]Recursive query aborted after 11 iterations. Try increasing @@cte_max_recursion_depth to a larger value")
 }
 
 func TestCTEWithLimit(t *testing.T) {
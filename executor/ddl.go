@@ -166,6 +166,8 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		err = e.executeRecoverTable(x)
 	case *ast.FlashBackTableStmt:
 		err = e.executeFlashbackTable(x)
+	case *ast.FlashBackDatabaseStmt:
+		err = e.executeFlashbackDatabase(x)
 	case *ast.RenameTableStmt:
 		err = e.executeRenameTable(x)
 	case *ast.TruncateTableStmt:
@@ -201,6 +203,12 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		err = e.executeDropPlacementPolicy(x)
 	case *ast.AlterPlacementPolicyStmt:
 		err = e.executeAlterPlacementPolicy(x)
+	case *ast.CreateResourceGroupStmt:
+		err = domain.GetDomain(e.ctx).DDL().CreateResourceGroup(e.ctx, x)
+	case *ast.AlterResourceGroupStmt:
+		err = domain.GetDomain(e.ctx).DDL().AlterResourceGroup(e.ctx, x)
+	case *ast.DropResourceGroupStmt:
+		err = domain.GetDomain(e.ctx).DDL().DropResourceGroup(e.ctx, x)
 	}
 	if err != nil {
 		// If the owner return ErrTableNotExists error when running this DDL, it may be caused by schema changed,
@@ -346,6 +354,9 @@ func (e *DDLExec) executeAlterDatabase(s *ast.AlterDatabaseStmt) error {
 }
 
 func (e *DDLExec) executeCreateTable(s *ast.CreateTableStmt) error {
+	if err := checkExternalTableLocation(s); err != nil {
+		return err
+	}
 	err := domain.GetDomain(e.ctx).DDL().CreateTable(e.ctx, s)
 	return err
 }
@@ -817,6 +828,121 @@ func (e *DDLExec) executeFlashbackTable(s *ast.FlashBackTableStmt) error {
 	return err
 }
 
+// executeFlashbackDatabase represents a restore schema executor.
+// It is built from "flashback schema" statement,
+// is used to recover the schema that deleted by mistake.
+func (e *DDLExec) executeFlashbackDatabase(s *ast.FlashBackDatabaseStmt) error {
+	dbName := s.DBName
+	if len(s.NewName) > 0 {
+		dbName = model.NewCIStr(s.NewName)
+	}
+	// Check the Schema Name was not exists.
+	is := domain.GetDomain(e.ctx).InfoSchema()
+	if is.SchemaExists(dbName) {
+		return infoschema.ErrDatabaseExists.GenWithStackByArgs(dbName)
+	}
+	recoverSchemaInfo, err := e.getRecoverDBByName(s.DBName)
+	if err != nil {
+		return err
+	}
+	// Check the Schema ID was not exists.
+	if schema, ok := is.SchemaByID(recoverSchemaInfo.DBInfo.ID); ok {
+		return infoschema.ErrDatabaseExists.GenWithStack("Schema '%-.192s' already been recover to '%-.192s', can't be recover repeatedly", s.DBName, schema.Name.O)
+	}
+	recoverSchemaInfo.DBInfo.Name = dbName
+	// Call DDL RecoverSchema.
+	err = domain.GetDomain(e.ctx).DDL().RecoverSchema(e.ctx, recoverSchemaInfo)
+	return err
+}
+
+func (e *DDLExec) getRecoverDBByName(schemaName model.CIStr) (*ddl.RecoverSchemaInfo, error) {
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return nil, err
+	}
+	gcSafePoint, err := gcutil.GetGCSafePoint(e.ctx)
+	if err != nil {
+		return nil, err
+	}
+	dom := domain.GetDomain(e.ctx)
+	var recoverSchemaInfo *ddl.RecoverSchemaInfo
+	fn := func(jobs []*model.Job) (bool, error) {
+		for _, job := range jobs {
+			if job.Type != model.ActionDropSchema || job.SchemaName != schemaName.L {
+				continue
+			}
+			// Check GC safe point for getting snapshot infoSchema.
+			err := gcutil.ValidateSnapshotWithGCSafePoint(job.StartTS, gcSafePoint)
+			if err != nil {
+				return true, err
+			}
+			snapMeta, err := dom.GetSnapshotMeta(job.StartTS)
+			if err != nil {
+				return false, err
+			}
+			schemaInfo, err := snapMeta.GetDatabase(job.SchemaID)
+			if err != nil {
+				return false, err
+			}
+			if schemaInfo == nil {
+				// The drop schema job maybe failed, then the schema can't be found in the snapshot, just skip it.
+				continue
+			}
+			if ref := schemaInfo.PlacementPolicyRef; ref != nil {
+				policy, err := snapMeta.GetPolicy(ref.ID)
+				if err != nil {
+					return false, err
+				}
+				schemaInfo.PlacementPolicyRef = nil
+				schemaInfo.DirectPlacementOpts = policy.PlacementSettings
+			}
+			tables, err := snapMeta.ListTables(job.SchemaID)
+			if err != nil {
+				return false, err
+			}
+			recoverTabsInfo := make([]*ddl.RecoverInfo, 0, len(tables))
+			for _, tblInfo := range tables {
+				autoIDs, err := snapMeta.GetAutoIDAccessors(job.SchemaID, tblInfo.ID).Get()
+				if err != nil {
+					return false, err
+				}
+				if tblInfo, err = recoverTablePlacement(snapMeta, tblInfo); err != nil {
+					return false, err
+				}
+				recoverTabsInfo = append(recoverTabsInfo, &ddl.RecoverInfo{
+					SchemaID:      job.SchemaID,
+					TableInfo:     tblInfo,
+					DropJobID:     job.ID,
+					SnapshotTS:    job.StartTS,
+					AutoIDs:       autoIDs,
+					OldSchemaName: schemaName.L,
+					OldTableName:  tblInfo.Name.L,
+				})
+			}
+			recoverSchemaInfo = &ddl.RecoverSchemaInfo{
+				DBInfo:          schemaInfo,
+				RecoverTabsInfo: recoverTabsInfo,
+				DropJobID:       job.ID,
+				SnapshotTS:      job.StartTS,
+				OldSchemaName:   schemaName,
+			}
+			return true, nil
+		}
+		return false, nil
+	}
+	err = admin.IterHistoryDDLJobs(txn, fn)
+	if err != nil {
+		if terror.ErrorEqual(variable.ErrSnapshotTooOld, err) {
+			return nil, errors.Errorf("Can't find dropped database '%s' in GC safe point %s", schemaName.O, model.TSConvert2Time(gcSafePoint).String())
+		}
+		return nil, err
+	}
+	if recoverSchemaInfo == nil {
+		return nil, errors.Errorf("Can't find dropped database: %v in DDL history jobs", schemaName.O)
+	}
+	return recoverSchemaInfo, nil
+}
+
 func (e *DDLExec) executeLockTables(s *ast.LockTablesStmt) error {
 	if !config.TableLockEnabled() {
 		e.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrFuncNotEnabled.GenWithStackByArgs("LOCK TABLES", "enable-table-lock"))
@@ -1069,6 +1069,42 @@ func (s *testAutoRandomSuite) TestAutoRandomBitsData(c *C) {
 	tk.MustExec("drop table t;")
 }
 
+func (s *testAutoRandomSuite) TestAutoRandomOnCompositePrimaryKey(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("set @@allow_auto_random_explicit_insert = true")
+
+	tk.MustExec("create table t (a bigint auto_random(5), b varchar(10), primary key (a, b) clustered)")
+	tk.MustExec("insert into t(b) values ('a'), ('b'), ('c')")
+	// The incremental bits are continuous, the shard bits are the same in a transaction.
+	tk.MustQuery("select a & ((1 << 58) - 1), b from t order by b").Check(testkit.Rows("1 a", "2 b", "3 c"))
+	tk.MustQuery("select count(distinct a >> 58) from t").Check(testkit.Rows("1"))
+	tk.MustQuery("select count(*) from t where a = last_insert_id()").Check(testkit.Rows("1"))
+
+	// Explicit insert and update rebase the allocator.
+	tk.MustExec("insert into t values (1000, 'd')")
+	tk.MustExec("insert into t(b) values ('e')")
+	tk.MustQuery("select a & ((1 << 58) - 1) from t where b = 'e'").Check(testkit.Rows("1001"))
+	tk.MustExec("update t set a = 5000 where b = 'd'")
+	tk.MustExec("insert into t(b) values ('f')")
+	tk.MustQuery("select a & ((1 << 58) - 1) from t where b = 'f'").Check(testkit.Rows("5001"))
+	tk.MustExec("admin check table t")
+
+	rows := tk.MustQuery("show table t next_row_id").Rows()
+	c.Assert(rows, HasLen, 1)
+	c.Assert(rows[0][2], Equals, "a")
+	c.Assert(rows[0][4], Equals, "AUTO_RANDOM")
+	tk.MustQuery("select tidb_row_id_sharding_info from information_schema.tables where table_schema = 'test' and table_name = 't'").Check(
+		testkit.Rows("PK_AUTO_RANDOM_BITS=5"))
+
+	// The shard bits can be increased.
+	tk.MustExec("alter table t modify column a bigint auto_random(8)")
+	tk.MustExec("insert into t(b) values ('g')")
+	tk.MustQuery("select a & ((1 << 55) - 1) > 5001 from t where b = 'g'").Check(testkit.Rows("1"))
+	tk.MustExec("drop table t")
+}
+
 func (s *testAutoRandomSuite) TestAutoRandomTableOption(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
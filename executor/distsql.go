@@ -181,6 +181,7 @@ type IndexReaderExecutor struct {
 
 	feedback  *statistics.QueryFeedback
 	streaming bool
+	paging    bool
 
 	keepOrder bool
 	desc      bool
@@ -204,6 +205,9 @@ func (e *IndexReaderExecutor) Close() (err error) {
 
 	if e.result != nil {
 		err = e.result.Close()
+		if e.table != nil {
+			e.storeIndexUsage(e.table.Meta(), e.index)
+		}
 	}
 	e.result = nil
 	e.ctx.StoreQueryFeedback(e.feedback)
@@ -295,6 +299,7 @@ func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange)
 		SetDesc(e.desc).
 		SetKeepOrder(e.keepOrder).
 		SetStreaming(e.streaming).
+		SetPaging(e.paging).
 		SetReadReplicaScope(e.readReplicaScope).
 		SetIsStaleness(e.isStaleness).
 		SetFromSessionVars(e.ctx.GetSessionVars()).
@@ -696,6 +701,7 @@ func (e *IndexLookUpExecutor) Close() error {
 	}
 	e.idxWorkerWg.Wait()
 	e.tblWorkerWg.Wait()
+	e.storeIndexUsage(e.table.Meta(), e.index)
 	e.finished = nil
 	e.workerStarted = false
 	e.memTracker = nil
@@ -1265,7 +1271,7 @@ func (w *tableWorker) executeTask(ctx context.Context, task *lookupTableTask) er
 				obtainedHandlesMap.Set(handle, true)
 			}
 
-			if w.idxLookup.ctx.GetSessionVars().EnableRedactLog {
+			if w.idxLookup.ctx.GetSessionVars().ShouldRedactLog() {
 				logutil.Logger(ctx).Error("inconsistent index handles",
 					zap.String("table_name", w.idxLookup.index.Table.O),
 					zap.String("index", w.idxLookup.index.Name.O),
@@ -384,3 +384,40 @@ func (s *testSuite3) TestPartitionTableIndexJoinIndexLookUp(c *C) {
 		tk.MustQuery("select /*+ TIDB_INLJ(t1, t2) */ t1.* from t t1, t t2 use index(a) where t1.a=t2.b and " + cond).Sort().Check(result)
 	}
 }
+
+func (s *testSuite3) TestCoprocessorPaging(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, c int, v int, key c(c))")
+	vals := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		vals = append(vals, fmt.Sprintf("(%d, %d, %d)", i, i%500, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(vals, ","))
+	tk.MustQuery("split table t by (250), (500), (750)").Check(testkit.Rows("3 1"))
+
+	sqls := []string{
+		"select * from t use index() where id >= 100 order by id limit 300",
+		"select * from t use index() where id > 10 order by id desc limit 700",
+		"select * from t use index() where id < 20 or id between 400 and 900 order by id limit 600",
+		"select id from t use index() where v % 3 = 0 order by id limit 100",
+		"select c, id from t use index(c) where c > 10 order by c, id limit 500",
+		"select c, id from t use index(c) where c >= 100 order by c desc, id desc limit 400",
+		"select * from t use index(c) where c > 10 order by c, id limit 10",
+	}
+	results := make([][][]interface{}, 0, len(sqls))
+	for _, sql := range sqls {
+		results = append(results, tk.MustQuery(sql).Rows())
+	}
+
+	tk.MustExec("set @@tidb_enable_paging = on")
+	for i, sql := range sqls {
+		plan := fmt.Sprintf("%v", tk.MustQuery("explain format = 'brief' "+sql).Rows())
+		c.Assert(strings.Contains(plan, "paging:true"), IsTrue, Commentf("sql: %s, plan: %s", sql, plan))
+		tk.MustQuery(sql).Check(results[i])
+	}
+	// The pushed down aggregation and the upper task which requires all rows don't go paging.
+	c.Assert(fmt.Sprintf("%v", tk.MustQuery("explain format = 'brief' select count(*) from t use index() where id > 10").Rows()), Not(Matches), ".*paging:true.*")
+	c.Assert(fmt.Sprintf("%v", tk.MustQuery("explain format = 'brief' select * from t use index() limit 961").Rows()), Not(Matches), ".*paging:true.*")
+}
@@ -50,6 +50,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	storeerr "github.com/pingcap/tidb/store/driver/error"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
@@ -220,6 +221,15 @@ func (e *baseExecutor) updateDeltaForTableID(id int64) {
 	txnCtx.UpdateDeltaForTable(id, 0, 0, map[int64]int64{})
 }
 
+// storeIndexUsage stores the usage of the index read by the executor, the rows selected are the rows it returns.
+func (e *baseExecutor) storeIndexUsage(tblInfo *model.TableInfo, idxInfo *model.IndexInfo) {
+	actRows := int64(0)
+	if e.runtimeStats != nil {
+		actRows = e.runtimeStats.GetActRows()
+	}
+	e.ctx.StoreIndexUsage(tblInfo.ID, idxInfo.ID, actRows)
+}
+
 func newBaseExecutor(ctx sessionctx.Context, schema *expression.Schema, id int, children ...Executor) baseExecutor {
 	e := baseExecutor{
 		children:     children,
@@ -360,7 +370,7 @@ func (e *ShowNextRowIDExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			}
 		case autoid.AutoRandomType:
 			idType = "AUTO_RANDOM"
-			colName = tblMeta.GetPkName().O
+			colName = tblMeta.GetAutoRandomColInfo().Name.O
 		case autoid.SequenceType:
 			idType = "SEQUENCE"
 			colName = ""
@@ -900,15 +910,35 @@ type SelectLockExec struct {
 	// tblID2PIDColumnIndex stores the column index in the chunk row. The children may be join
 	// of multiple tables, so the map struct is used.
 	tblID2PIDColumnIndex map[int64]int
+
+	// For "SELECT .. FOR UPDATE SKIP LOCKED" in pessimistic transactions, every row is locked without waiting
+	// when it's read, and the rows which can't be locked are skipped.
+	skipLocked bool
+	childChk   *chunk.Chunk
+	childIdx   int
+	childDone  bool
+	// skippedKeys are the keys which failed to be locked. They are not locked again in the statement, because the
+	// asynchronous pessimistic rollback of the failed lock request may remove the lock acquired later.
+	skippedKeys map[string]struct{}
 }
 
 // Open implements the Executor Open interface.
 func (e *SelectLockExec) Open(ctx context.Context) error {
+	e.skipLocked = e.Lock.LockType == ast.SelectLockForUpdateSkipLocked && e.ctx.GetSessionVars().TxnCtx.IsPessimistic
+	if e.skipLocked {
+		e.childChk = newFirstChunk(e.children[0])
+		e.childIdx, e.childDone = 0, false
+		e.skippedKeys = make(map[string]struct{})
+	}
 	return e.baseExecutor.Open(ctx)
 }
 
 // Next implements the Executor Next interface.
 func (e *SelectLockExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	if e.skipLocked && len(e.tblID2Handle) > 0 {
+		req.Reset()
+		return e.nextSkipLocked(ctx, req)
+	}
 	req.GrowAndReset(e.maxChunkSize)
 	err := Next(ctx, e.children[0], req)
 	if err != nil {
@@ -922,24 +952,9 @@ func (e *SelectLockExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	if req.NumRows() > 0 {
 		iter := chunk.NewIterator4Chunk(req)
 		for row := iter.Begin(); row != iter.End(); row = iter.Next() {
-
-			for id, cols := range e.tblID2Handle {
-				physicalID := id
-				if len(e.partitionedTable) > 0 {
-					// Replace the table ID with partition ID.
-					// The partition ID is returned as an extra column from the table reader.
-					if offset, ok := e.tblID2PIDColumnIndex[id]; ok {
-						physicalID = row.GetInt64(offset)
-					}
-				}
-
-				for _, col := range cols {
-					handle, err := col.BuildHandle(row)
-					if err != nil {
-						return err
-					}
-					e.keys = append(e.keys, tablecodec.EncodeRowKeyWithHandle(physicalID, handle))
-				}
+			e.keys, err = e.appendRowKeys(e.keys, row)
+			if err != nil {
+				return err
 			}
 		}
 		return nil
@@ -951,19 +966,100 @@ func (e *SelectLockExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		lockWaitTime = int64(e.Lock.WaitSec) * 1000
 	}
 
-	if len(e.tblID2Handle) > 0 {
-		for id := range e.tblID2Handle {
-			e.updateDeltaForTableID(id)
+	e.updateDeltaForTables()
+	return doLockKeys(ctx, e.ctx, newLockCtx(e.ctx.GetSessionVars(), lockWaitTime), e.keys...)
+}
+
+// nextSkipLocked locks the rows read from the child one by one without waiting, and only returns the locked rows.
+// It stops locking once the required rows are returned, so that a limit above doesn't lock more rows than needed.
+func (e *SelectLockExec) nextSkipLocked(ctx context.Context, req *chunk.Chunk) error {
+	for !req.IsFull() && !e.childDone {
+		if e.childIdx >= e.childChk.NumRows() {
+			err := Next(ctx, e.children[0], e.childChk)
+			if err != nil {
+				return err
+			}
+			e.childIdx = 0
+			if e.childChk.NumRows() == 0 {
+				e.childDone = true
+				break
+			}
+		}
+		row := e.childChk.GetRow(e.childIdx)
+		e.childIdx++
+		locked, err := e.lockRowNoWait(ctx, row)
+		if err != nil {
+			return err
+		}
+		if locked {
+			req.AppendRow(row)
+		}
+	}
+	if req.NumRows() > 0 {
+		return nil
+	}
+	e.updateDeltaForTables()
+	if len(e.skippedKeys) > 0 {
+		// Like the NOWAIT lock error, the for update ts must be updated so that the following statements won't be
+		// affected by the asynchronous pessimistic rollback.
+		return UpdateForUpdateTS(e.ctx, 0)
+	}
+	return nil
+}
+
+func (e *SelectLockExec) lockRowNoWait(ctx context.Context, row chunk.Row) (bool, error) {
+	keys, err := e.appendRowKeys(nil, row)
+	if err != nil {
+		return false, err
+	}
+	for _, key := range keys {
+		if _, ok := e.skippedKeys[string(key)]; ok {
+			return false, nil
 		}
 	}
-	if len(e.partitionedTable) > 0 {
-		for _, p := range e.partitionedTable {
-			pid := p.Meta().ID
-			e.updateDeltaForTableID(pid)
+	err = doLockKeys(ctx, e.ctx, newLockCtx(e.ctx.GetSessionVars(), tikvstore.LockNoWait), keys...)
+	if err == nil {
+		return true, nil
+	}
+	if !storeerr.ErrLockAcquireFailAndNoWaitSet.Equal(err) {
+		return false, err
+	}
+	for _, key := range keys {
+		e.skippedKeys[string(key)] = struct{}{}
+	}
+	return false, nil
+}
+
+// appendRowKeys appends the row keys to be locked of the row.
+func (e *SelectLockExec) appendRowKeys(keys []kv.Key, row chunk.Row) ([]kv.Key, error) {
+	for id, cols := range e.tblID2Handle {
+		physicalID := id
+		if len(e.partitionedTable) > 0 {
+			// Replace the table ID with partition ID.
+			// The partition ID is returned as an extra column from the table reader.
+			if offset, ok := e.tblID2PIDColumnIndex[id]; ok {
+				physicalID = row.GetInt64(offset)
+			}
+		}
+
+		for _, col := range cols {
+			handle, err := col.BuildHandle(row)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, tablecodec.EncodeRowKeyWithHandle(physicalID, handle))
 		}
 	}
+	return keys, nil
+}
 
-	return doLockKeys(ctx, e.ctx, newLockCtx(e.ctx.GetSessionVars(), lockWaitTime), e.keys...)
+func (e *SelectLockExec) updateDeltaForTables() {
+	for id := range e.tblID2Handle {
+		e.updateDeltaForTableID(id)
+	}
+	for _, p := range e.partitionedTable {
+		e.updateDeltaForTableID(p.Meta().ID)
+	}
 }
 
 func newLockCtx(seVars *variable.SessionVars, lockWaitTime int64) *tikvstore.LockCtx {
@@ -1680,6 +1776,8 @@ func (e *UnionExec) Close() error {
 // Before every execution, we must clear statement context.
 func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	vars := ctx.GetSessionVars()
+	// Restore the variables set by SET_VAR hint of the last statement before they are used.
+	vars.ClearStmtVars()
 	var sc *stmtctx.StatementContext
 	if vars.TxnCtx.CouldRetry {
 		// Must construct new statement context object, the retry history need context for every statement.
@@ -1698,7 +1796,7 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	sc.OptimizerCETrace = nil
 
 	sc.InitMemTracker(memory.LabelForSQLText, vars.MemQuotaQuery)
-	sc.InitDiskTracker(memory.LabelForSQLText, -1)
+	sc.InitDiskTracker(memory.LabelForSQLText, vars.TmpStorageQuotaQuery)
 	sc.MemTracker.AttachToGlobalTracker(GlobalMemoryUsageTracker)
 	globalConfig := config.GetGlobalConfig()
 	if globalConfig.OOMUseTmpStorage && GlobalDiskUsageTracker != nil {
@@ -1774,6 +1872,8 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 		sc.AllowInvalidDate = vars.SQLMode.HasAllowInvalidDatesMode()
 		sc.IgnoreZeroInDate = !vars.SQLMode.HasNoZeroInDateMode() || !vars.SQLMode.HasNoZeroDateMode() || !vars.StrictSQLMode || stmt.IgnoreErr || sc.AllowInvalidDate
 		sc.Priority = stmt.Priority
+		sc.CollectDMLErrors = vars.CollectDMLErrors
+		sc.DMLErrorsMaxSize = vars.CollectDMLErrorsMaxSize
 	case *ast.CreateTableStmt, *ast.AlterTableStmt:
 		sc.InCreateOrAlterStmt = true
 		sc.AllowInvalidDate = vars.SQLMode.HasAllowInvalidDatesMode()
@@ -1788,6 +1888,8 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 		sc.InLoadDataStmt = true
 		// return warning instead of error when load data meet no partition for value
 		sc.IgnoreNoPartition = true
+		sc.CollectDMLErrors = vars.CollectDMLErrors
+		sc.DMLErrorsMaxSize = vars.CollectDMLErrorsMaxSize
 	case *ast.SelectStmt:
 		sc.InSelectStmt = true
 
@@ -1854,13 +1956,15 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	}
 
 	sc.TblInfo2UnionScan = make(map[*model.TableInfo]bool)
+	if vars.StmtCtx.CollectDMLErrors {
+		vars.LastDMLErrors = vars.StmtCtx.GetDMLErrors()
+	}
 	errCount, warnCount := vars.StmtCtx.NumErrorWarnings()
 	vars.SysErrorCount = errCount
 	vars.SysWarningCount = warnCount
 	vars.StmtCtx = sc
 	vars.PrevFoundInPlanCache = vars.FoundInPlanCache
 	vars.FoundInPlanCache = false
-	vars.ClearStmtVars()
 	vars.PrevFoundInBinding = vars.FoundInBinding
 	vars.FoundInBinding = false
 	return
@@ -1877,6 +1981,8 @@ func ResetUpdateStmtCtx(sc *stmtctx.StatementContext, stmt *ast.UpdateStmt, vars
 	sc.IgnoreZeroInDate = !vars.SQLMode.HasNoZeroInDateMode() || !vars.SQLMode.HasNoZeroDateMode() || !vars.StrictSQLMode || stmt.IgnoreErr || sc.AllowInvalidDate
 	sc.Priority = stmt.Priority
 	sc.IgnoreNoPartition = stmt.IgnoreErr
+	sc.CollectDMLErrors = vars.CollectDMLErrors
+	sc.DMLErrorsMaxSize = vars.CollectDMLErrorsMaxSize
 }
 
 // FillVirtualColumnValue will calculate the virtual column value by evaluating generated
@@ -101,6 +101,7 @@ func (msm *mockSessionManager) SetServerID(serverID uint64) {
 func TestExecutorPkg(t *testing.T) {
 	t.Run("ShowProcessList", SubTestShowProcessList)
 	t.Run("BuildKvRangesForIndexJoinWithoutCwc", SubTestBuildKvRangesForIndexJoinWithoutCwc)
+	t.Run("MergeSortedKVRanges", SubTestMergeSortedKVRanges)
 	t.Run("GetFieldsFromLine", SubTestGetFieldsFromLine)
 	t.Run("SlowQueryRuntimeStats", SubTestSlowQueryRuntimeStats)
 	t.Run("AggPartialResultMapperB", SubTestAggPartialResultMapperB)
@@ -209,6 +210,25 @@ func SubTestBuildKvRangesForIndexJoinWithoutCwc(t *testing.T) {
 	}
 }
 
+func SubTestMergeSortedKVRanges(t *testing.T) {
+	newRange := func(start, end string) kv.KeyRange {
+		return kv.KeyRange{StartKey: kv.Key(start), EndKey: kv.Key(end)}
+	}
+	kvRanges := []kv.KeyRange{
+		newRange("a", "b"),
+		newRange("a", "b"),
+		newRange("b", "c"),
+		newRange("d", "f"),
+		newRange("e", "ee"),
+		newRange("g", "h"),
+	}
+	merged := mergeSortedKVRanges(kvRanges)
+	require.Equal(t, []kv.KeyRange{newRange("a", "c"), newRange("d", "f"), newRange("g", "h")}, merged)
+
+	require.Len(t, mergeSortedKVRanges(nil), 0)
+	require.Equal(t, []kv.KeyRange{newRange("a", "b")}, mergeSortedKVRanges([]kv.KeyRange{newRange("a", "b")}))
+}
+
 func generateIndexRange(vals ...int64) *ranger.Range {
 	lowDatums := generateDatumSlice(vals...)
 	highDatums := make([]types.Datum, len(vals))
@@ -19,6 +19,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
 	"net"
@@ -373,6 +374,14 @@ func (s *testSuiteP1) TestShow(c *C) {
 		"ROLE_ADMIN Server Admin ",
 		"CONNECTION_ADMIN Server Admin ",
 		"PLACEMENT_ADMIN Server Admin ",
+		"RESOURCE_GROUP_ADMIN Server Admin ",
+		"RESOURCE_GROUP_USER Server Admin ",
+		"DDL_ADMIN Server Admin ",
+		"TABLE_CHECK_ADMIN Server Admin ",
+		"OPTIMIZER_ADMIN Server Admin ",
+		"PLUGIN_ADMIN Server Admin ",
+		"DIAGNOSTICS_ADMIN Server Admin ",
+		"ENCRYPTION_KEY_ADMIN Server Admin ",
 		"DASHBOARD_CLIENT Server Admin ",
 		"RESTRICTED_TABLES_ADMIN Server Admin ",
 		"RESTRICTED_STATUS_ADMIN Server Admin ",
@@ -5741,6 +5750,7 @@ func (s *testSuiteWithCliBaseCharset) TestCharsetFeature(c *C) {
 	tk.MustQuery("show charset").Check(testkit.Rows(
 		"ascii US ASCII ascii_bin 1",
 		"binary binary binary 1",
+		"gb18030 China National Standard GB18030 gb18030_chinese_ci 4",
 		"gbk Chinese Internal Code Specification gbk_chinese_ci 2",
 		"latin1 Latin1 latin1_bin 1",
 		"utf8 UTF-8 Unicode utf8_bin 3",
@@ -5749,6 +5759,8 @@ func (s *testSuiteWithCliBaseCharset) TestCharsetFeature(c *C) {
 	tk.MustQuery("show collation").Check(testkit.Rows(
 		"ascii_bin ascii 65 Yes Yes 1",
 		"binary binary 63 Yes Yes 1",
+		"gb18030_bin gb18030 249  Yes 1",
+		"gb18030_chinese_ci gb18030 248 Yes Yes 1",
 		"gbk_bin gbk 87  Yes 1",
 		"gbk_chinese_ci gbk 28 Yes Yes 1",
 		"latin1_bin latin1 47 Yes Yes 1",
@@ -5799,9 +5811,11 @@ func (s *testSuiteWithCliBaseCharset) TestCharsetFeature(c *C) {
 	))
 
 	collate.SetNewCollationEnabledForTest(false)
+	defer collate.SetNewCollationEnabledForTest(true)
 	tk.MustQuery("show charset").Check(testkit.Rows(
 		"ascii US ASCII ascii_bin 1",
 		"binary binary binary 1",
+		"gb18030 China National Standard GB18030 gb18030_chinese_ci 4",
 		"gbk Chinese Internal Code Specification gbk_chinese_ci 2",
 		"latin1 Latin1 latin1_bin 1",
 		"utf8 UTF-8 Unicode utf8_bin 3",
@@ -5841,6 +5855,48 @@ func (s *testSuiteWithCliBaseCharset) TestCharsetFeatureCollation(c *C) {
 	tk.MustGetErrCode("select collation(concat(ascii_char collate ascii_bin, gbk_char)) from t;", mysql.ErrCantAggregate2collations)
 }
 
+func (s *testSuiteWithCliBaseCharset) TestCharsetFeatureGB18030(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set names gb18030;")
+	tk.MustQuery("select @@character_set_connection, @@collation_connection;").Check(testkit.Rows("gb18030 gb18030_chinese_ci"))
+	tk.MustExec("set names utf8mb4;")
+
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec("create table t(a char(10) charset gb18030, b varchar(10) charset gb18030 collate gb18030_bin, c varchar(10));")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` char(10) CHARACTER SET gb18030 COLLATE gb18030_chinese_ci DEFAULT NULL,\n" +
+		"  `b` varchar(10) CHARACTER SET gb18030 COLLATE gb18030_bin DEFAULT NULL,\n" +
+		"  `c` varchar(10) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+	))
+	// Unlike gbk, all the unicode characters can be stored in gb18030.
+	tk.MustExec("insert into t values ('a', 'a', 'a'), ('中文', '中文', '中文'), ('À😃', 'À😃', 'À😃'), ('A', 'A', 'A');")
+	tk.MustQuery("select a, hex(a), length(a), char_length(a) from t where c = 'À😃';").Check(testkit.Rows("À😃 813086389439FC39 8 2"))
+	tk.MustQuery("select hex(convert('一二三' using gb18030)), convert(0xD2BBB6FEC8FD using gb18030);").Check(testkit.Rows("D2BBB6FEC8FD 一二三"))
+	tk.MustQuery("select upper(a), lower(a), substr(a, 2), reverse(a) from t where c = 'À😃';").Check(testkit.Rows("À😃 à😃 😃 😃À"))
+
+	// gb18030_chinese_ci is case insensitive and sorts the Chinese characters by pinyin.
+	tk.MustQuery("select c from t where a = 'à😃';").Check(testkit.Rows("À😃"))
+	tk.MustQuery("select count(*) from t where a = 'a';").Check(testkit.Rows("2"))
+	tk.MustQuery("select count(*) from t where b = 'a';").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where a like '中%';").Check(testkit.Rows("中文"))
+	tk.MustQuery("select collation(a), collation(b), collation(concat(a, 'x')) from t limit 1;").Check(testkit.Rows("gb18030_chinese_ci gb18030_bin gb18030_chinese_ci"))
+	tk.MustQuery("select b from t order by b, c;").Check(testkit.Rows("A", "a", "À😃", "中文"))
+	tk.MustQuery("select a from t order by a, c;").Check(testkit.Rows("A", "a", "À😃", "中文"))
+
+	// The gb18030 strings are not pushed down to the storage layer, the results are the same with an index.
+	tk.MustQuery("explain format = 'brief' select * from t where a = 'a';").Check(testkit.Rows(
+		"Selection 8000.00 root  eq(test.t.a, \"a\")",
+		"└─TableReader 10000.00 root  data:TableFullScan",
+		"  └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustExec("alter table t add index idx(a);")
+	tk.MustQuery("select c from t use index(idx) where a = 'a' order by c;").Check(testkit.Rows("A", "a"))
+	tk.MustQuery("select c from t use index(idx) where a > 'z' order by a, c;").Check(testkit.Rows("À😃", "中文"))
+	tk.MustQuery("select count(distinct a), count(distinct b) from t;").Check(testkit.Rows("3 4"))
+	tk.MustGetErrCode("alter table t set tiflash replica 1;", errno.ErrUnsupportedDDLOperation)
+}
+
 func (s *testSerialSuite2) TestIssue23567(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	oriProbability := statistics.FeedbackProbability.Load()
@@ -6190,6 +6246,62 @@ func (s *testRecoverTable) TestFlashbackTable(c *C) {
 	tk.MustQuery("select a from t order by a").Check(testkit.Rows("1", "2", "3"))
 }
 
+func (s *testRecoverTable) TestFlashbackDatabase(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("drop database if exists db_flashback")
+	tk.MustExec("create database db_flashback")
+	tk.MustExec("use db_flashback")
+	tk.MustExec("create table t1 (a int);")
+	tk.MustExec("create table t2 (a int) partition by hash(a) partitions 4;")
+	tk.MustExec("create view v as select * from t1;")
+
+	timeBeforeDrop, _, safePointSQL, resetGC := testkit.MockGC(tk)
+	defer resetGC()
+
+	// Set GC safe point
+	tk.MustExec(fmt.Sprintf(safePointSQL, timeBeforeDrop))
+	// Set GC enable.
+	c.Assert(gcutil.EnableGC(tk.Se), IsNil)
+
+	tk.MustExec("insert into t1 values (1),(2),(3)")
+	tk.MustExec("insert into t2 values (1),(2),(3)")
+	tk.MustExec("drop database db_flashback")
+
+	// Test flashback database with not_exist_db_name name.
+	tk.MustGetErrMsg("flashback database db_not_exists", "Can't find dropped database: db_not_exists in DDL history jobs")
+
+	// Test flashback database failed by there is already a database with the target name.
+	tk.MustExec("create database db_flashback_tmp")
+	_, err := tk.Exec("flashback database db_flashback to db_flashback_tmp")
+	c.Assert(infoschema.ErrDatabaseExists.Equal(err), IsTrue)
+	tk.MustExec("drop database db_flashback_tmp")
+
+	tk.MustExec("flashback database db_flashback")
+	// Check flashback tables meta and data record.
+	tk.MustQuery("select * from db_flashback.t1").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select * from db_flashback.t2 order by a").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("show tables in db_flashback").Check(testkit.Rows("t1", "t2", "v"))
+	// Check rebase auto id.
+	tk.MustExec("insert into db_flashback.t1 values (4)")
+	tk.MustQuery("select _tidb_rowid > 3 from db_flashback.t1 where a = 4").Check(testkit.Rows("1"))
+	// GC is enabled again after the flashback.
+	gcEnable, err := gcutil.CheckGCEnable(tk.Se)
+	c.Assert(err, IsNil)
+	c.Assert(gcEnable, IsTrue)
+
+	// Test flashback database to a new name.
+	tk.MustExec("drop database db_flashback")
+	tk.MustGetErrCode("flashback database db_flashback to `db_flashback `", errno.ErrWrongDBName)
+	tk.MustExec("flashback database db_flashback to db_flashback2")
+	tk.MustQuery("select * from db_flashback2.t1").Check(testkit.Rows("1", "2", "3", "4"))
+	tk.MustQuery("show tables in db_flashback2").Check(testkit.Rows("t1", "t2", "v"))
+
+	// Test for flashback one database multiple time.
+	_, err = tk.Exec("flashback database db_flashback to db_flashback3")
+	c.Assert(infoschema.ErrDatabaseExists.Equal(err), IsTrue)
+	tk.MustExec("drop database db_flashback2")
+}
+
 func (s *testRecoverTable) TestRecoverTempTable(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("create database if not exists test_recover")
@@ -7429,6 +7541,45 @@ func (s *testSlowQuery) TestSlowQueryPrepared(c *C) {
 		))
 }
 
+func (s *testSlowQuery) TestSlowQueryDesensitization(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	originCfg := config.GetGlobalConfig()
+	newCfg := *originCfg
+
+	f, err := os.CreateTemp("", "tidb-slow-*.log")
+	c.Assert(err, IsNil)
+	f.Close()
+	newCfg.Log.SlowQueryFile = f.Name()
+	config.StoreGlobalConfig(&newCfg)
+	defer func() {
+		tk.MustExec("set tidb_slow_log_threshold=300;")
+		tk.MustExec("set global tidb_log_desensitization=0;")
+		config.StoreGlobalConfig(originCfg)
+		os.Remove(newCfg.Log.SlowQueryFile)
+	}()
+	err = logutil.InitLogger(newCfg.Log.ToLogConfig())
+	c.Assert(err, IsNil)
+
+	tk.MustQuery("select @@global.tidb_log_desensitization").Check(testkit.Rows("0"))
+	c.Assert(tk.ExecToErr("set session tidb_log_desensitization=1"), NotNil)
+	tk.MustExec("set global tidb_log_desensitization=1;")
+	tk.MustQuery("select @@global.tidb_log_desensitization").Check(testkit.Rows("1"))
+	// It can't be turned off by the session.
+	tk.MustExec("set tidb_redact_log=0;")
+	tk.MustExec("set tidb_slow_log_threshold=0;")
+	tk.MustExec("select sleep(0.01), 'secret_3a1f';")
+	tk.MustExec(`prepare mystmt from 'select sleep(?), 1';`)
+	tk.MustExec("SET @num = 0.01;")
+	tk.MustExec("execute mystmt using @num;")
+	tk.MustExec("set tidb_slow_log_threshold=300;")
+	tk.MustQuery("SELECT Query FROM `information_schema`.`slow_query` " +
+		"where query like 'select%sleep%' order by time desc limit 2").
+		Check(testkit.Rows(
+			"select `sleep` ( ? ) , ?;",
+			"select `sleep` ( ? ) , ? ;",
+		))
+}
+
 func (s *testSlowQuery) TestLogSlowLogIndex(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	f, err := os.CreateTemp("", "tidb-slow-*.log")
@@ -7652,6 +7803,11 @@ func (s *testSuite) TestCollectDMLRuntimeStats(c *C) {
 	c.Assert(getRootStats(), Matches, "time:.*, loops:.*, prepare:.*, check_insert: {total_time:.*, mem_insert_time:.*, prefetch:.*, rpc:{BatchGet:{num_rpc:.*, total_time:.*}.*")
 	tk.MustExec("rollback")
 
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("insert into t1 values (5,1),(7,1),(11,1) on duplicate key update b=b+1")
+	c.Assert(getRootStats(), Matches, "time:.*, loops:.*, prepare:.*, check_insert: {total_time:.*, mem_insert_time:.*, prefetch:.*, conflicts: 2, rpc:{BatchGet:{num_rpc:.*, total_time:.*}.*")
+	tk.MustExec("rollback")
+
 	tk.MustExec("begin pessimistic")
 	tk.MustExec("insert into t1 values (1,2)")
 	c.Assert(getRootStats(), Matches, "time:.*, loops:.*, prepare:.*, insert:.*")
@@ -9426,6 +9582,61 @@ func (s *testSuiteWithData) TestPlanReplayerDumpSingle(c *C) {
 	}
 }
 
+func (s *testSerialSuite) TestPlanReplayerCapture(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_capture")
+	tk.MustExec("create table t_capture(a int, b int)")
+	tk.MustExec("set @@global.tidb_plan_replayer_capture_threshold = 0")
+	tk.MustExec("set @@global.tidb_enable_plan_replayer_capture = on")
+	defer func() {
+		tk.MustExec("set @@global.tidb_enable_plan_replayer_capture = default")
+		tk.MustExec("set @@global.tidb_plan_replayer_capture_threshold = default")
+	}()
+	tk.MustQuery("select * from t_capture where a = 1").Check(testkit.Rows())
+	tk.MustQuery("select * from t_capture where a = 2").Check(testkit.Rows())
+	// The plan deviates from the baseline after the index is created.
+	tk.MustExec("create index idx_a on t_capture(a)")
+	tk.MustQuery("select * from t_capture where a = 3").Check(testkit.Rows())
+
+	querySQL := "select reason, db, query_sample_text, error, file_name from information_schema.plan_replayer_captures where query_sample_text like '%t_capture%'"
+	var rows [][]interface{}
+	for i := 0; i < 100; i++ {
+		if rows = tk.MustQuery(querySQL).Rows(); len(rows) > 0 {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	c.Assert(rows, HasLen, 1)
+	c.Assert(fmt.Sprint(rows[0][:4]), Equals, "[plan_change test select * from t_capture where a = 3 <nil>]")
+	path := filepath.Join(domain.GetPlanReplayerDirName(), rows[0][4].(string))
+	defer os.Remove(path)
+	reader, err := zip.OpenReader(path)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		r, err := file.Open()
+		c.Assert(err, IsNil)
+		content, err := io.ReadAll(r)
+		c.Assert(err, IsNil)
+		c.Assert(r.Close(), IsNil)
+		files[file.Name] = string(content)
+	}
+	c.Assert(files["sqls.sql"], Equals, "select * from t_capture where a = 3")
+	c.Assert(files["explain.txt"], Matches, "(?s).*idx_a.*")
+	c.Assert(files["variables.toml"], Matches, "(?s).*tidb_enable_plan_replayer_capture = \"ON\".*")
+	c.Assert(files["schema/test.t_capture.schema.txt"], Matches, "(?s).*CREATE TABLE `t_capture`.*")
+	c.Assert(files, HasKey, "stats/test.t_capture.json")
+
+	// The privilege PROCESS is required to list the captures.
+	tk.MustExec("create user capture_tester")
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "capture_tester", Hostname: "%"}, nil, nil), IsTrue)
+	err = tk1.QueryToErr("select * from information_schema.plan_replayer_captures")
+	c.Assert(err.Error(), Equals, "[planner:1227]Access denied; you need (at least one of) the PROCESS privilege(s) for this operation")
+}
+
 func (s *testSuiteP1) TestIssue28935(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("set @@tidb_enable_vectorized_expression=true")
@@ -125,11 +125,12 @@ func (s *testSuite1) TestExplainAnalyzeMemory(c *C) {
 	s.checkMemoryInfo(c, tk, "explain analyze select k from t use index(k)")
 	s.checkMemoryInfo(c, tk, "explain analyze select * from t use index(k)")
 	s.checkMemoryInfo(c, tk, "explain analyze select v+k from t")
+	s.checkMemoryInfo(c, tk, "explain analyze select sum(v) over (order by v rows between 1 preceding and current row) from t")
 }
 
 func (s *testSuite1) checkMemoryInfo(c *C, tk *testkit.TestKit, sql string) {
 	memCol := 6
-	ops := []string{"Join", "Reader", "Top", "Sort", "LookUp", "Projection", "Selection", "Agg"}
+	ops := []string{"Join", "Reader", "Top", "Sort", "LookUp", "Projection", "Selection", "Agg", "Window"}
 	rows := tk.MustQuery(sql).Rows()
 	for _, row := range rows {
 		strs := make([]string, len(row))
@@ -182,6 +183,7 @@ func (s *testSuite1) TestMemoryAndDiskUsageAfterClose(c *C) {
 		"select v from t order by v",
 		"select count(v) from t",            // StreamAgg
 		"select count(v) from t group by v", // HashAgg
+		"select sum(v) over (order by v rows between 1 preceding and current row) from t", // PipelinedWindow
 	}
 	for _, sql := range SQLs {
 		tk.MustQuery(sql)
@@ -0,0 +1,235 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/execdetails"
+)
+
+// externalTableNullValue is the field value which is read as NULL, the same as the default of LOAD DATA.
+const externalTableNullValue = `\N`
+
+// checkExternalTableLocation checks the LOCATION of CREATE EXTERNAL TABLE. It's checked here instead of by the ddl
+// package, which doesn't depend on the SDKs of the external storages.
+func checkExternalTableLocation(s *ast.CreateTableStmt) error {
+	if s.External == nil {
+		return nil
+	}
+	_, err := storage.ParseBackend(s.External.Location, nil)
+	return errors.Trace(err)
+}
+
+// redactExternalTableLocation hides the credentials in the query parameters of the LOCATION, the same as BR.
+func redactExternalTableLocation(location string) string {
+	u, err := url.Parse(location)
+	if err != nil || u.RawQuery == "" {
+		return location
+	}
+	query := u.Query()
+	for key := range query {
+		switch strings.ToLower(strings.ReplaceAll(key, "_", "-")) {
+		case "access-key", "secret-access-key":
+			query[key] = []string{"xxxxxx"}
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// externalTableRetriever reads the rows of an external table from the CSV files in an external storage.
+// The i-th field of a record is the value of the i-th column of the table. The files are read lazily,
+// one batch each time, and the rows which don't satisfy the pushed down conditions are skipped.
+type externalTableRetriever struct {
+	table      *model.TableInfo
+	outputCols []*model.ColumnInfo
+	// conditions are resolved against the output columns.
+	conditions []expression.Expression
+	retTypes   []*types.FieldType
+
+	initialized bool
+	store       storage.ExternalStorage
+	files       []string
+	fileIdx     int
+	file        storage.ExternalFileReader
+	reader      *csv.Reader
+}
+
+// retrieve implements the memTableRetriever interface
+func (e *externalTableRetriever) retrieve(ctx context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
+	if !e.initialized {
+		if err := e.initialize(ctx); err != nil {
+			return nil, err
+		}
+		e.initialized = true
+	}
+	batchSize := sctx.GetSessionVars().MaxChunkSize
+	rows := make([][]types.Datum, 0, batchSize)
+	var mutableRow chunk.MutRow
+	if len(e.conditions) > 0 {
+		mutableRow = chunk.MutRowFromTypes(e.retTypes)
+	}
+	for len(rows) < batchSize {
+		if e.reader == nil {
+			if e.fileIdx >= len(e.files) {
+				break
+			}
+			if err := e.openFile(ctx); err != nil {
+				return nil, err
+			}
+		}
+		record, err := e.reader.Read()
+		if err == io.EOF {
+			if err = e.closeFile(); err != nil {
+				return nil, err
+			}
+			e.fileIdx++
+			continue
+		}
+		if err != nil {
+			return nil, errors.Annotatef(err, "read external file %s", e.files[e.fileIdx])
+		}
+		row, err := e.convertRecord(sctx, record)
+		if err != nil {
+			return nil, err
+		}
+		if len(e.conditions) > 0 {
+			mutableRow.SetDatums(row...)
+			matched, _, err := expression.EvalBool(sctx, e.conditions, mutableRow.ToRow())
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (e *externalTableRetriever) initialize(ctx context.Context) error {
+	dir, file, err := splitExternalLocation(e.table.ExternalTable.Location)
+	if err != nil {
+		return err
+	}
+	backend, err := storage.ParseBackend(dir, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	e.store, err = storage.New(ctx, backend, &storage.ExternalStorageOptions{})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(file) > 0 {
+		e.files = []string{file}
+		return nil
+	}
+	err = e.store.WalkDir(ctx, &storage.WalkOption{}, func(path string, size int64) error {
+		if size > 0 {
+			e.files = append(e.files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sort.Strings(e.files)
+	return nil
+}
+
+// splitExternalLocation splits the location of an external table into the URL of the
+// directory and the file name. The file name is empty if the location is a directory.
+func splitExternalLocation(location string) (dir string, file string, err error) {
+	u, err := storage.ParseRawURL(location)
+	if err != nil {
+		return "", "", err
+	}
+	if !strings.HasSuffix(strings.ToLower(u.Path), ".csv") {
+		return location, "", nil
+	}
+	u.Path, file = path.Split(u.Path)
+	if len(u.Path) == 0 {
+		u.Path = "."
+	}
+	return u.String(), file, nil
+}
+
+func (e *externalTableRetriever) openFile(ctx context.Context) error {
+	file, err := e.store.Open(ctx, e.files[e.fileIdx])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	e.file = file
+	e.reader = csv.NewReader(file)
+	e.reader.FieldsPerRecord = -1
+	e.reader.ReuseRecord = true
+	return nil
+}
+
+func (e *externalTableRetriever) closeFile() error {
+	if e.file == nil {
+		return nil
+	}
+	err := e.file.Close()
+	e.file, e.reader = nil, nil
+	return errors.Trace(err)
+}
+
+// convertRecord casts the fields of a CSV record to the types of the output columns.
+// The missing fields are read as NULL.
+func (e *externalTableRetriever) convertRecord(sctx sessionctx.Context, record []string) ([]types.Datum, error) {
+	row := make([]types.Datum, len(e.outputCols))
+	for i, col := range e.outputCols {
+		if col.Offset >= len(record) || record[col.Offset] == externalTableNullValue {
+			row[i].SetNull()
+			continue
+		}
+		casted, err := table.CastValue(sctx, types.NewStringDatum(record[col.Offset]), col, false, false)
+		if err != nil {
+			return nil, err
+		}
+		row[i] = casted
+	}
+	return row, nil
+}
+
+// close implements the memTableRetriever interface. The retriever is reset, so the
+// files are read again if the executor is reopened, e.g. by Apply.
+func (e *externalTableRetriever) close() error {
+	err := e.closeFile()
+	e.initialized, e.store, e.files, e.fileIdx = false, nil, nil, 0
+	return err
+}
+
+func (e *externalTableRetriever) getRuntimeStats() execdetails.RuntimeStats {
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalTable(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.csv"), []byte("1,foo,1.5\n2,bar,\\N\n3,\"b,z\",3.5\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.csv"), []byte("4,qux,4.5\n5\n"), 0644))
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	tk.MustExec(fmt.Sprintf("create external table t (a int, b varchar(10), c double) location '%s' format csv", dir))
+	tk.MustExec(fmt.Sprintf("create external table t1 (a int, b varchar(10)) location '%s' format csv", filepath.Join(dir, "b.csv")))
+
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 foo 1.5", "2 bar <nil>", "3 b,z 3.5", "4 qux 4.5", "5 <nil> <nil>"))
+	tk.MustQuery("select * from t1").Check(testkit.Rows("4 qux", "5 <nil>"))
+	tk.MustQuery("select b from t where a > 1 and c is not null").Check(testkit.Rows("b,z", "qux"))
+	tk.MustQuery("select count(*), sum(c) from t where b like 'b%'").Check(testkit.Rows("2 3.5"))
+
+	// The conditions are evaluated by the reader and the unused columns are pruned.
+	rows := tk.MustQuery("explain format = 'brief' select b from t where a > 1").Rows()
+	require.Len(t, rows, 2)
+	require.Equal(t, "└─MemTableScan", rows[1][0])
+	require.Equal(t, "table:t", rows[1][3])
+	require.Equal(t, "conditions:[gt(test.t.a, 1)]", rows[1][4])
+
+	// The files are read again when the reader is reopened by Apply.
+	tk.MustExec("create table n (x int)")
+	tk.MustExec("insert into n values (1), (3)")
+	tk.MustQuery("select x, (select count(*) from t where t.a <= n.x) from n order by x").Check(testkit.Rows("1 1", "3 3"))
+	tk.MustQuery("select n.x, t.b from n join t on n.x = t.a order by n.x").Check(testkit.Rows("1 foo", "3 b,z"))
+
+	// The rows of an external table can't be modified.
+	tk.MustGetErrMsg("insert into t values (6, 'a', 1)", "[planner:1288]The target table t of the INSERT is not updatable")
+	tk.MustGetErrMsg("replace into t values (6, 'a', 1)", "[planner:1288]The target table t of the REPLACE is not updatable")
+	tk.MustGetErrMsg("update t set a = 1", "[planner:1288]The target table t of the UPDATE is not updatable")
+	tk.MustGetErrMsg("delete from t where a = 1", "[planner:1288]The target table t of the DELETE is not updatable")
+	tk.MustExec("update n, t set n.x = t.a * 10 where n.x = t.a + 2")
+	tk.MustQuery("select x from n order by x").Check(testkit.Rows("1", "10"))
+	tk.MustGetErrMsg("create index idx on t (a)", "[ddl:8245]'Create Index' is unsupported on external tables.")
+	tk.MustGetErrMsg("alter table t add column d int", "[ddl:8245]'alter table' is unsupported on external tables.")
+	tk.MustExec("alter table t1 rename to t2")
+	tk.MustQuery("select a from t2").Check(testkit.Rows("4", "5"))
+
+	tk.MustQuery("show create table t2").Check(testkit.Rows("t2 CREATE EXTERNAL TABLE `t2` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` varchar(10) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin LOCATION '" + filepath.Join(dir, "b.csv") + "' FORMAT CSV"))
+
+	// The credentials in the location are hidden.
+	tk.MustExec("create external table t3 (a int) location 's3://bucket/prefix?access-key=ak&secret_access_key=sk&region=us-west-2' format csv")
+	tk.MustQuery("show create table t3").Check(testkit.Rows("t3 CREATE EXTERNAL TABLE `t3` (\n" +
+		"  `a` int(11) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin LOCATION 's3://bucket/prefix?access-key=xxxxxx&region=us-west-2&secret_access_key=xxxxxx' FORMAT CSV"))
+	tk.MustExec("drop table t3")
+
+	err := tk.ExecToErr("create external table t3 (a int) location 'foo://bar' format csv")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "storage foo not support yet")
+	tk.MustGetErrMsg("create external table t3 (a int) location '/tmp' format json", "[ddl:8245]'FORMAT JSON' is unsupported on external tables.")
+	tk.MustGetErrMsg("create external table t3 (a int primary key) location '/tmp' format csv", "[ddl:8245]'index' is unsupported on external tables.")
+	tk.MustGetErrMsg("create external table t3 (a int, key(a)) location '/tmp' format csv", "[ddl:8245]'index' is unsupported on external tables.")
+	tk.MustGetErrMsg("create external table t3 (a int, b int as (a + 1)) location '/tmp' format csv", "[ddl:8245]'generated column' is unsupported on external tables.")
+}
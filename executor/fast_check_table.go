@@ -0,0 +1,364 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+var _ Executor = &FastCheckTableExec{}
+
+const (
+	// fastCheckBucketCount is the number of buckets the rows of a mismatched bucket are split into.
+	fastCheckBucketCount = 1024
+	// fastCheckLookupThreshold is the max number of rows in a mismatched bucket which are compared one by one.
+	fastCheckLookupThreshold = 100
+	// fastCheckMaxModulus is the number of different handle hash values, the rows in a bucket whose modulus
+	// reaches it have the same handle hash and can't be split any more.
+	fastCheckMaxModulus = 1 << 32
+)
+
+// FastCheckTableExec checks the consistency of a table and its indices by comparing checksums. It is built from the
+// 'admin check table' statement when tidb_enable_fast_table_check is on.
+//
+// The rows are split into buckets by the hash of their handles, and the checksum and the row count of every bucket
+// are computed by the coprocessor from the table data and from the index data. Only the mismatched buckets are split
+// further, and the rows of a small enough mismatched bucket are compared one by one to report the inconsistent row.
+type FastCheckTableExec struct {
+	baseExecutor
+
+	dbName     string
+	table      table.Table
+	indexInfos []*model.IndexInfo
+	done       bool
+}
+
+// canFastCheckTable reports whether the indices of the table can be checked by FastCheckTableExec. The handles of
+// different partitions can be the same, and the values of prefix and expression index columns can't be read from
+// both the table and the index, so they're checked row by row.
+func canFastCheckTable(tbl table.Table, indexInfos []*model.IndexInfo) bool {
+	tblInfo := tbl.Meta()
+	if tblInfo.GetPartitionInfo() != nil {
+		return false
+	}
+	for _, idxInfo := range indexInfos {
+		for _, idxCol := range idxInfo.Columns {
+			if idxCol.Length != types.UnspecifiedLength || tblInfo.Columns[idxCol.Offset].Hidden {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Next implements the Executor Next interface.
+func (e *FastCheckTableExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	snapshot := txn.StartTS()
+	if e.ctx.GetSessionVars().SnapshotTS != 0 {
+		snapshot = e.ctx.GetSessionVars().SnapshotTS
+	}
+	// Here we need check all indexes, includes invisible index.
+	e.ctx.GetSessionVars().OptimizerUseInvisibleIndexes = true
+	defer func() {
+		e.ctx.GetSessionVars().OptimizerUseInvisibleIndexes = false
+	}()
+
+	handleCols := fastCheckHandleCols(e.table.Meta())
+	for _, idxInfo := range e.indexInfos {
+		checker := &fastIndexChecker{
+			exec:       e.ctx.(sqlexec.RestrictedSQLExecutor),
+			snapshot:   snapshot,
+			dbName:     e.dbName,
+			tblName:    e.table.Meta().Name.O,
+			idxName:    idxInfo.Name.O,
+			handleCols: handleCols,
+		}
+		checker.idxCols = make([]string, 0, len(idxInfo.Columns))
+		for _, idxCol := range idxInfo.Columns {
+			checker.idxCols = append(checker.idxCols, idxCol.Name.O)
+		}
+		if err := checker.checkBucket(ctx, 1, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fastCheckHandleCols(tblInfo *model.TableInfo) []string {
+	if tblInfo.PKIsHandle {
+		return []string{tblInfo.GetPkColInfo().Name.O}
+	}
+	if tblInfo.IsCommonHandle {
+		pkIdx := tables.FindPrimaryIndex(tblInfo)
+		cols := make([]string, 0, len(pkIdx.Columns))
+		for _, idxCol := range pkIdx.Columns {
+			cols = append(cols, idxCol.Name.O)
+		}
+		return cols
+	}
+	return []string{model.ExtraHandleName.O}
+}
+
+// fastIndexChecker compares the table data with the data of one index.
+type fastIndexChecker struct {
+	exec     sqlexec.RestrictedSQLExecutor
+	snapshot uint64
+
+	dbName     string
+	tblName    string
+	idxName    string
+	handleCols []string
+	idxCols    []string
+}
+
+// bucketChecksum is the checksum and the row count of a bucket.
+type bucketChecksum struct {
+	checksum uint64
+	count    int64
+}
+
+// checkBucket checks the rows whose handle hash modulo `modulus` equals to `remainder`, these rows are split into
+// fastCheckBucketCount buckets by the handle hash divided by `modulus`.
+func (c *fastIndexChecker) checkBucket(ctx context.Context, modulus, remainder uint64) error {
+	tblBuckets, err := c.checksumBuckets(ctx, false, modulus, remainder)
+	if err != nil {
+		return err
+	}
+	idxBuckets, err := c.checksumBuckets(ctx, true, modulus, remainder)
+	if err != nil {
+		return err
+	}
+	for _, bucket := range mergeBucketIDs(tblBuckets, idxBuckets) {
+		tblChecksum, idxChecksum := tblBuckets[bucket], idxBuckets[bucket]
+		if tblChecksum == idxChecksum {
+			continue
+		}
+		subModulus, subRemainder := modulus*fastCheckBucketCount, remainder+bucket*modulus
+		logutil.Logger(ctx).Info("fast check table found a mismatched bucket",
+			zap.String("table", c.tblName), zap.String("index", c.idxName),
+			zap.Uint64("modulus", subModulus), zap.Uint64("remainder", subRemainder),
+			zap.Int64("table count", tblChecksum.count), zap.Int64("index count", idxChecksum.count))
+		if tblChecksum.count <= fastCheckLookupThreshold && idxChecksum.count <= fastCheckLookupThreshold || subModulus >= fastCheckMaxModulus {
+			return c.checkRows(ctx, subModulus, subRemainder)
+		}
+		return c.checkBucket(ctx, subModulus, subRemainder)
+	}
+	return nil
+}
+
+func mergeBucketIDs(tblBuckets, idxBuckets map[uint64]bucketChecksum) []uint64 {
+	buckets := make([]uint64, 0, len(tblBuckets))
+	for bucket := range tblBuckets {
+		buckets = append(buckets, bucket)
+	}
+	for bucket := range idxBuckets {
+		if _, ok := tblBuckets[bucket]; !ok {
+			buckets = append(buckets, bucket)
+		}
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+	return buckets
+}
+
+func (c *fastIndexChecker) checksumBuckets(ctx context.Context, useIndex bool, modulus, remainder uint64) (map[uint64]bucketChecksum, error) {
+	var sql strings.Builder
+	var args []interface{}
+	sql.WriteString("SELECT BIT_XOR(")
+	args = c.writeRowChecksum(&sql, args)
+	sql.WriteString("), COUNT(*), ")
+	args = c.writeHandleHash(&sql, args)
+	sql.WriteString(" DIV %? MOD %? AS bucket")
+	args = append(args, modulus, fastCheckBucketCount)
+	args = c.writeFromWhere(&sql, args, useIndex, modulus, remainder)
+	sql.WriteString(" GROUP BY bucket")
+
+	rows, _, err := c.execute(ctx, sql.String(), args)
+	if err != nil {
+		return nil, err
+	}
+	buckets := make(map[uint64]bucketChecksum, len(rows))
+	for _, row := range rows {
+		buckets[row.GetUint64(2)] = bucketChecksum{checksum: row.GetUint64(0), count: row.GetInt64(1)}
+	}
+	return buckets, nil
+}
+
+// fastCheckRow is a row read in the row-level diagnosis, the handle and the index column values are formatted as
+// strings to be reported.
+type fastCheckRow struct {
+	values   string
+	checksum uint64
+}
+
+// checkRows compares the rows of a mismatched bucket one by one and reports the first inconsistent row.
+func (c *fastIndexChecker) checkRows(ctx context.Context, modulus, remainder uint64) error {
+	tblRows, err := c.bucketRows(ctx, false, modulus, remainder)
+	if err != nil {
+		return err
+	}
+	idxRows, err := c.bucketRows(ctx, true, modulus, remainder)
+	if err != nil {
+		return err
+	}
+	handles := make([]string, 0, len(tblRows))
+	for handle := range tblRows {
+		handles = append(handles, handle)
+	}
+	for handle := range idxRows {
+		if _, ok := tblRows[handle]; !ok {
+			handles = append(handles, handle)
+		}
+	}
+	sort.Strings(handles)
+	for _, handle := range handles {
+		tblRow, inTable := tblRows[handle]
+		idxRow, inIndex := idxRows[handle]
+		if inTable && inIndex && tblRow == idxRow {
+			continue
+		}
+		var idxVal, recordVal interface{}
+		if inIndex {
+			idxVal = idxRow.values
+		}
+		if inTable {
+			recordVal = tblRow.values
+		}
+		return ErrAdminCheckTable.GenWithStack("%v err:%v", c.tblName, ErrDataInConsistentExtraIndex.GenWithStackByArgs(handle, idxVal, recordVal))
+	}
+	return ErrAdminCheckTable.GenWithStack("%v err:checksum of index %s mismatches, but no inconsistent row is found", c.tblName, c.idxName)
+}
+
+func (c *fastIndexChecker) bucketRows(ctx context.Context, useIndex bool, modulus, remainder uint64) (map[string]fastCheckRow, error) {
+	var sql strings.Builder
+	var args []interface{}
+	sql.WriteString("SELECT ")
+	for _, col := range c.handleCols {
+		sql.WriteString("%n, ")
+		args = append(args, col)
+	}
+	for _, col := range c.idxCols {
+		sql.WriteString("%n, ")
+		args = append(args, col)
+	}
+	args = c.writeRowChecksum(&sql, args)
+	args = c.writeFromWhere(&sql, args, useIndex, modulus, remainder)
+
+	rows, fields, err := c.execute(ctx, sql.String(), args)
+	if err != nil {
+		return nil, err
+	}
+	numHandleCols := len(c.handleCols)
+	checksumIdx := numHandleCols + len(c.idxCols)
+	result := make(map[string]fastCheckRow, len(rows))
+	for _, row := range rows {
+		handle, err := formatFastCheckValues(row, fields, 0, numHandleCols)
+		if err != nil {
+			return nil, err
+		}
+		values, err := formatFastCheckValues(row, fields, numHandleCols, checksumIdx)
+		if err != nil {
+			return nil, err
+		}
+		result[handle] = fastCheckRow{values: values, checksum: row.GetUint64(checksumIdx)}
+	}
+	return result, nil
+}
+
+func formatFastCheckValues(row chunk.Row, fields []*ast.ResultField, begin, end int) (string, error) {
+	values := make([]string, 0, end-begin)
+	for i := begin; i < end; i++ {
+		d := row.GetDatum(i, &fields[i].Column.FieldType)
+		if d.IsNull() {
+			values = append(values, "NULL")
+			continue
+		}
+		str, err := d.ToString()
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		values = append(values, str)
+	}
+	return strings.Join(values, ", "), nil
+}
+
+// writeRowChecksum writes the checksum of the index columns and the handle columns of a row. The nullness of the
+// columns is included because CONCAT_WS skips the NULL values.
+func (c *fastIndexChecker) writeRowChecksum(sql *strings.Builder, args []interface{}) []interface{} {
+	sql.WriteString("CRC32(MD5(CONCAT_WS(0x2")
+	for _, cols := range [][]string{c.idxCols, c.handleCols} {
+		for _, col := range cols {
+			sql.WriteString(", %n, ISNULL(%n)")
+			args = append(args, col, col)
+		}
+	}
+	sql.WriteString(")))")
+	return args
+}
+
+func (c *fastIndexChecker) writeHandleHash(sql *strings.Builder, args []interface{}) []interface{} {
+	sql.WriteString("CRC32(MD5(CONCAT_WS(0x2")
+	for _, col := range c.handleCols {
+		sql.WriteString(", %n")
+		args = append(args, col)
+	}
+	sql.WriteString(")))")
+	return args
+}
+
+func (c *fastIndexChecker) writeFromWhere(sql *strings.Builder, args []interface{}, useIndex bool, modulus, remainder uint64) []interface{} {
+	sql.WriteString(" FROM %n.%n")
+	args = append(args, c.dbName, c.tblName)
+	if useIndex {
+		sql.WriteString(" USE INDEX(%n)")
+		args = append(args, c.idxName)
+	} else {
+		sql.WriteString(" USE INDEX()")
+	}
+	sql.WriteString(" WHERE ")
+	args = c.writeHandleHash(sql, args)
+	sql.WriteString(" MOD %? = %?")
+	return append(args, modulus, remainder)
+}
+
+func (c *fastIndexChecker) execute(ctx context.Context, sql string, args []interface{}) ([]chunk.Row, []*ast.ResultField, error) {
+	stmt, err := c.exec.ParseWithParamsInternal(ctx, sql, args...)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	rows, fields, err := c.exec.ExecRestrictedStmt(ctx, stmt, sqlexec.ExecOptionWithSnapshot(c.snapshot))
+	return rows, fields, errors.Trace(err)
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/metrics"
+)
+
+const (
+	// defaultResourceGroupLabel is the resource group label of the sessions which aren't bound to any resource group.
+	defaultResourceGroupLabel = "default"
+	// otherDBLabel is the database label of the statements whose (resource group, database) pair exceeds the limit.
+	otherDBLabel = "other"
+)
+
+// groupLabelGuard bounds the cardinality of the metrics labelled by resource group and database. Once the number of
+// distinct pairs reaches the limit, the new pairs share the database label `other` of their resource group.
+type groupLabelGuard struct {
+	sync.Mutex
+	pairs map[groupLabelPair]struct{}
+}
+
+type groupLabelPair struct {
+	group string
+	db    string
+}
+
+var stmtGroupLabelGuard = &groupLabelGuard{pairs: make(map[groupLabelPair]struct{})}
+
+// dbLabel returns the database label used for the pair.
+func (g *groupLabelGuard) dbLabel(group, db string, limit int) string {
+	pair := groupLabelPair{group: group, db: db}
+	g.Lock()
+	defer g.Unlock()
+	if _, ok := g.pairs[pair]; ok {
+		return db
+	}
+	if len(g.pairs) >= limit {
+		return otherDBLabel
+	}
+	g.pairs[pair] = struct{}{}
+	return db
+}
+
+// observeStmtMetricsByGroup records the qps, latency, memory and RU metrics of the statement by its resource group
+// and database if `status.record-stmt-metrics-by-group` is enabled.
+func (a *ExecStmt) observeStmtMetricsByGroup(succ bool) {
+	cfg := config.GetGlobalConfig()
+	if !cfg.Status.RecordStmtMetricsByGroup {
+		return
+	}
+	sessVars := a.Ctx.GetSessionVars()
+	if sessVars.InRestrictedSQL {
+		return
+	}
+	group := sessVars.ResourceGroupName
+	if group == "" {
+		group = defaultResourceGroupLabel
+	}
+	db := stmtGroupLabelGuard.dbLabel(group, sessVars.CurrentDB, cfg.Status.StmtMetricsLabelLimit)
+	sqlType := GetStmtLabel(a.StmtNode)
+	result := metrics.LblOK
+	if !succ {
+		result = metrics.LblError
+	}
+	metrics.GroupQueryTotalCounter.WithLabelValues(sqlType, group, db, result).Inc()
+	latency := time.Since(sessVars.StartTime) + sessVars.DurationParse
+	metrics.GroupQueryDurationHistogram.WithLabelValues(sqlType, group, db).Observe(latency.Seconds())
+	if memTracker := sessVars.StmtCtx.MemTracker; memTracker != nil {
+		metrics.GroupQueryMemoryHistogram.WithLabelValues(sqlType, group, db).Observe(float64(memTracker.MaxConsumed()))
+	}
+	if ru := sessVars.StmtCtx.GetExecDetails().RUConsumed; ru > 0 {
+		metrics.GroupQueryRUCounter.WithLabelValues(sqlType, group, db).Add(ru)
+	}
+}
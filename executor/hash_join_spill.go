@@ -0,0 +1,530 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
+)
+
+const (
+	// hashJoinSpillPartitionBits is the number of the hash value bits used to choose the partition of a row
+	// in one spill round, the next bits are used if a partition is split again.
+	hashJoinSpillPartitionBits = 4
+	hashJoinSpillPartitionNum  = 1 << hashJoinSpillPartitionBits
+	// hashJoinSpillMaxRounds is the max number of the spill rounds. The partitions of the last round aren't
+	// split any more, their build side rows are spilled by the row container if they still exceed the quota.
+	hashJoinSpillMaxRounds = 3
+)
+
+// hashJoinSpillContext keeps the states of the grace hash join. If the build side exceeds the memory quota,
+// both sides are partitioned into disk by the hash values of the join keys, then the partitions are joined
+// one by one. A partition whose build side still exceeds the quota is partitioned again by the next bits of
+// the hash values, until hashJoinSpillMaxRounds is reached.
+type hashJoinSpillContext struct {
+	action *hashJoinSpillAction
+
+	buildTypes []*types.FieldType
+	probeTypes []*types.FieldType
+	buildHCtx  *hashContext
+	probeHCtx  *hashContext
+	selected   []bool
+
+	// partitions are the partitions of the first round, they are written by the build side fetcher
+	// and read by the probe side fetcher after the build side finishes.
+	partitions []*hashJoinPartition
+	// pending are the partitions to be joined.
+	pending []*hashJoinPartition
+
+	rounds int
+	bytes  int64
+}
+
+func newHashJoinSpillContext(e *HashJoinExec) *hashJoinSpillContext {
+	buildKeyColIdx := make([]int, len(e.buildKeys))
+	for i := range e.buildKeys {
+		buildKeyColIdx[i] = e.buildKeys[i].Index
+	}
+	probeKeyColIdx := make([]int, len(e.probeKeys))
+	for i := range e.probeKeys {
+		probeKeyColIdx[i] = e.probeKeys[i].Index
+	}
+	return &hashJoinSpillContext{
+		action:     &hashJoinSpillAction{splittable: true},
+		buildTypes: retTypes(e.buildSideExec),
+		probeTypes: retTypes(e.probeSideExec),
+		buildHCtx:  &hashContext{allTypes: e.buildTypes, keyColIdx: buildKeyColIdx},
+		probeHCtx:  &hashContext{allTypes: e.probeTypes, keyColIdx: probeKeyColIdx},
+	}
+}
+
+func (s *hashJoinSpillContext) closePartition(p *hashJoinPartition) {
+	s.bytes += p.build.GetDiskTracker().BytesConsumed() + p.probe.GetDiskTracker().BytesConsumed()
+	terror.Call(p.build.Close)
+	terror.Call(p.probe.Close)
+}
+
+// close releases the partitions which are not joined, e.g. the query is cancelled.
+func (s *hashJoinSpillContext) close() {
+	for _, p := range s.pending {
+		s.closePartition(p)
+	}
+	s.partitions, s.pending = nil, nil
+	s.action.setRowContainer(nil)
+}
+
+// hashJoinPartition is a partition of the grace hash join, the rows in it have the same hash bits of the join keys.
+type hashJoinPartition struct {
+	round int
+	// splittable indicates whether the partition can be split again by the hash values.
+	splittable bool
+	build      *hashJoinSpillList
+	probe      *hashJoinSpillList
+}
+
+// hashJoinSpillList buffers the rows of one side of a partition and writes them into disk chunk by chunk.
+type hashJoinSpillList struct {
+	*chunk.ListInDisk
+	buf          *chunk.Chunk
+	fieldTypes   []*types.FieldType
+	maxChunkSize int
+}
+
+func newHashJoinSpillList(fieldTypes []*types.FieldType, maxChunkSize int, diskTracker *disk.Tracker) *hashJoinSpillList {
+	l := &hashJoinSpillList{
+		ListInDisk:   chunk.NewListInDisk(fieldTypes),
+		fieldTypes:   fieldTypes,
+		maxChunkSize: maxChunkSize,
+	}
+	l.GetDiskTracker().AttachTo(diskTracker)
+	return l
+}
+
+func (l *hashJoinSpillList) appendRow(row chunk.Row) error {
+	if l.buf == nil {
+		l.buf = chunk.NewChunkWithCapacity(l.fieldTypes, l.maxChunkSize)
+	}
+	l.buf.AppendRow(row)
+	if l.buf.NumRows() < l.maxChunkSize {
+		return nil
+	}
+	err := l.Add(l.buf)
+	l.buf.Reset()
+	return err
+}
+
+// finish writes the buffered rows into disk, the list can only be read after it finishes.
+func (l *hashJoinSpillList) finish() (err error) {
+	if l.buf != nil && l.buf.NumRows() > 0 {
+		err = l.Add(l.buf)
+	}
+	l.buf = nil
+	return err
+}
+
+// hashJoinSpillAction implements memory.ActionOnExceed for the hash join. When the memory quota is exceeded
+// during building a hash table which can be split, it requests the builder to partition the build side into
+// disk. Otherwise, the rows of the current row container are spilled as before.
+type hashJoinSpillAction struct {
+	memory.BaseOOMAction
+	mu sync.Mutex
+	// splittable is true when the hash table being built can be partitioned.
+	splittable bool
+	requested  bool
+	// rowContainerSpill is the spill action of the row container being built or probed.
+	rowContainerSpill memory.ActionOnExceed
+}
+
+// Action implements the memory.ActionOnExceed interface.
+func (a *hashJoinSpillAction) Action(t *memory.Tracker) {
+	a.mu.Lock()
+	if a.splittable {
+		if !a.requested {
+			logutil.BgLogger().Info("memory exceeds quota, partition the hash join into disk now.",
+				zap.Int64("consumed", t.BytesConsumed()), zap.Int64("quota", t.GetBytesLimit()))
+		}
+		a.requested = true
+		a.mu.Unlock()
+		return
+	}
+	rowContainerSpill := a.rowContainerSpill
+	a.mu.Unlock()
+	if rowContainerSpill != nil {
+		rowContainerSpill.Action(t)
+		return
+	}
+	if !t.CheckExceed() {
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+// SetLogHook implements the memory.ActionOnExceed interface.
+func (a *hashJoinSpillAction) SetLogHook(hook func(uint64)) {}
+
+// GetPriority implements the memory.ActionOnExceed interface.
+func (a *hashJoinSpillAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
+
+// startBuild is called before building a hash table which can be split.
+func (a *hashJoinSpillAction) startBuild() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.splittable, a.requested, a.rowContainerSpill = true, false, nil
+}
+
+func (a *hashJoinSpillAction) spillRequested() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.requested
+}
+
+// setRowContainer sets the row container which is spilled when the memory quota is exceeded,
+// nil means there is nothing to spill.
+func (a *hashJoinSpillAction) setRowContainer(rowContainer *hashRowContainer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.splittable, a.requested, a.rowContainerSpill = false, false, nil
+	if rowContainer != nil {
+		a.rowContainerSpill = rowContainer.ActionSpill()
+		a.rowContainerSpill.SetFallback(a.GetFallback())
+	}
+}
+
+func (e *HashJoinExec) newSpillPartitions(round int) []*hashJoinPartition {
+	parts := make([]*hashJoinPartition, hashJoinSpillPartitionNum)
+	for i := range parts {
+		parts[i] = &hashJoinPartition{
+			round:      round,
+			splittable: round+1 < hashJoinSpillMaxRounds,
+			build:      newHashJoinSpillList(e.spill.buildTypes, e.maxChunkSize, e.diskTracker),
+			probe:      newHashJoinSpillList(e.spill.probeTypes, e.maxChunkSize, e.diskTracker),
+		}
+	}
+	if round+1 > e.spill.rounds {
+		e.spill.rounds = round + 1
+	}
+	return parts
+}
+
+// spillChunk appends the rows of chk to the partitions chosen by the hash values of the join keys.
+// The rows which can't match any row are put into the first partition, because they still need to be
+// handled by the joiner, e.g. the outer rows of an outer join. They are dropped if they are inner rows.
+func (e *HashJoinExec) spillChunk(parts []*hashJoinPartition, chk *chunk.Chunk, buildSide bool) (err error) {
+	s := e.spill
+	hCtx, ignoreNulls := s.probeHCtx, e.isNullEQ
+	var selected []bool
+	keepUnmatched := true
+	switch {
+	case buildSide:
+		hCtx = s.buildHCtx
+		keepUnmatched = e.useOuterToBuild
+		if e.useOuterToBuild && len(e.outerFilter) > 0 {
+			s.selected, err = expression.VectorizedFilter(e.ctx, e.outerFilter, chunk.NewIterator4Chunk(chk), s.selected)
+			selected = s.selected
+		}
+	case e.useOuterToBuild:
+		// The same as join2ChunkForOuterHashJoin, all the inner rows are looked up.
+		ignoreNulls = nil
+	default:
+		s.selected, err = expression.VectorizedFilter(e.ctx, e.outerFilter, chunk.NewIterator4Chunk(chk), s.selected)
+		selected = s.selected
+	}
+	if err != nil {
+		return err
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	hCtx.initHash(chk.NumRows())
+	for keyIdx, colIdx := range hCtx.keyColIdx {
+		ignoreNull := len(ignoreNulls) > keyIdx && ignoreNulls[keyIdx]
+		err = codec.HashChunkSelected(sc, hCtx.hashVals, chk, hCtx.allTypes[keyIdx], colIdx, hCtx.buf, hCtx.hasNull, selected, ignoreNull)
+		if err != nil {
+			return err
+		}
+	}
+	shift := uint(parts[0].round * hashJoinSpillPartitionBits)
+	for i := 0; i < chk.NumRows(); i++ {
+		partIdx := 0
+		if (selected != nil && !selected[i]) || (hCtx.hasNull[i] && (buildSide || !e.useOuterToBuild)) {
+			if !keepUnmatched {
+				continue
+			}
+		} else {
+			partIdx = int(hCtx.hashVals[i].Sum64()>>shift) & (hashJoinSpillPartitionNum - 1)
+		}
+		list := parts[partIdx].probe
+		if buildSide {
+			list = parts[partIdx].build
+		}
+		if err = list.appendRow(chk.GetRow(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func finishSpillPartitions(parts []*hashJoinPartition, buildSide bool) error {
+	for _, p := range parts {
+		list := p.probe
+		if buildSide {
+			list = p.build
+		}
+		if err := list.finish(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spillRowContainer moves the rows of the row container into the partitions and releases its memory.
+func (e *HashJoinExec) spillRowContainer(parts []*hashJoinPartition, rowContainer *hashRowContainer) error {
+	for i := 0; i < rowContainer.NumChunks(); i++ {
+		chk, err := rowContainer.GetChunk(i)
+		if err != nil {
+			return err
+		}
+		if err = e.spillChunk(parts, chk, true); err != nil {
+			return err
+		}
+	}
+	e.releaseOuterMatchedStatus()
+	rowContainer.hashTable = newConcurrentMapHashTable()
+	return rowContainer.Close()
+}
+
+// spillBuildSide partitions the build side into disk when the first hash table exceeds the memory quota.
+// The remaining build side rows are partitioned directly.
+func (e *HashJoinExec) spillBuildSide() error {
+	e.spill.action.setRowContainer(nil)
+	e.spill.partitions = e.newSpillPartitions(0)
+	e.spill.pending = append([]*hashJoinPartition(nil), e.spill.partitions...)
+	if err := e.spillRowContainer(e.spill.partitions, e.rowContainer); err != nil {
+		return err
+	}
+	// The shallow copies for the join workers share nothing with the released hash table.
+	for _, rowContainer := range e.rowContainerForProbe {
+		rowContainer.hashTable = e.rowContainer.hashTable
+	}
+	return nil
+}
+
+// finishBuildSide is called after all the build side rows are fetched.
+func (e *HashJoinExec) finishBuildSide() error {
+	if e.spill.partitions == nil {
+		e.spill.action.setRowContainer(e.rowContainer)
+		return nil
+	}
+	return finishSpillPartitions(e.spill.partitions, true)
+}
+
+// spillProbeSide partitions all the probe side rows into disk, the first chunk has been fetched.
+func (e *HashJoinExec) spillProbeSide(ctx context.Context, chk *chunk.Chunk) error {
+	for chk.NumRows() > 0 {
+		if e.finished.Load().(bool) {
+			return nil
+		}
+		if err := e.spillChunk(e.spill.partitions, chk, false); err != nil {
+			return err
+		}
+		if err := Next(ctx, e.probeSideExec, chk); err != nil {
+			return err
+		}
+	}
+	if err := finishSpillPartitions(e.spill.partitions, false); err != nil {
+		return err
+	}
+	e.probeSideSpilled = true
+	return nil
+}
+
+// joinSpilledPartitions joins the spilled partitions one by one. It runs after all the join workers exit.
+func (e *HashJoinExec) joinSpilledPartitions() {
+	ok, joinResult := e.getNewJoinResult(0)
+	if !ok {
+		return
+	}
+	s := e.spill
+	for len(s.pending) > 0 && ok {
+		if e.finished.Load().(bool) {
+			break
+		}
+		p := s.pending[len(s.pending)-1]
+		s.pending = s.pending[:len(s.pending)-1]
+		ok, joinResult = e.joinPartition(p, joinResult)
+		s.closePartition(p)
+	}
+	if joinResult == nil {
+		return
+	} else if joinResult.err != nil || (joinResult.chk != nil && joinResult.chk.NumRows() > 0) {
+		e.joinResultCh <- joinResult
+	} else if joinResult.chk != nil && joinResult.chk.NumRows() == 0 {
+		e.joinChkResourceCh[0] <- joinResult.chk
+	}
+}
+
+// joinPartition joins the rows of a partition. If its build side exceeds the memory quota, it's partitioned again
+// and the new partitions are joined later.
+func (e *HashJoinExec) joinPartition(p *hashJoinPartition, joinResult *hashjoinWorkerResult) (ok bool, _ *hashjoinWorkerResult) {
+	// Skip the partitions which can't produce any row.
+	if (e.useOuterToBuild && p.build.Len() == 0) || (!e.useOuterToBuild && p.probe.Len() == 0) {
+		return true, joinResult
+	}
+	rowContainer, err := e.buildPartition(p)
+	if err != nil {
+		joinResult.err = err
+		return false, joinResult
+	}
+	if rowContainer == nil {
+		return true, joinResult
+	}
+	defer func() {
+		e.spill.action.setRowContainer(nil)
+		e.releaseOuterMatchedStatus()
+		terror.Call(rowContainer.Close)
+	}()
+
+	selected := make([]bool, 0, chunk.InitialCapacity)
+	for i := 0; i < p.probe.NumChunks(); i++ {
+		chk, err := p.probe.GetChunk(i)
+		if err != nil {
+			joinResult.err = err
+			return false, joinResult
+		}
+		if e.useOuterToBuild {
+			ok, joinResult = e.join2ChunkForOuterHashJoin(0, chk, e.spill.probeHCtx, rowContainer, joinResult)
+		} else {
+			ok, joinResult = e.join2Chunk(0, chk, e.spill.probeHCtx, rowContainer, joinResult, selected)
+		}
+		if !ok {
+			return false, joinResult
+		}
+	}
+	if e.useOuterToBuild {
+		for i := 0; i < rowContainer.NumChunks(); i++ {
+			if ok, joinResult = e.joinUnmatchedBuildSideRows(0, rowContainer, i, joinResult); !ok {
+				return false, joinResult
+			}
+		}
+	}
+	return true, joinResult
+}
+
+// buildPartition builds the hash table of a partition. It returns nil if the partition is split again.
+func (e *HashJoinExec) buildPartition(p *hashJoinPartition) (rowContainer *hashRowContainer, err error) {
+	hCtx := &hashContext{allTypes: e.buildTypes, keyColIdx: e.spill.buildHCtx.keyColIdx}
+	rowContainer = newHashRowContainer(e.ctx, p.build.Len(), hCtx, e.spill.buildTypes)
+	rowContainer.stat = e.rowContainer.stat
+	rowContainer.GetMemTracker().AttachTo(e.memTracker)
+	rowContainer.GetMemTracker().SetLabel(memory.LabelForBuildSideResult)
+	rowContainer.GetDiskTracker().AttachTo(e.diskTracker)
+	rowContainer.GetDiskTracker().SetLabel(memory.LabelForBuildSideResult)
+	defer func() {
+		if err != nil {
+			e.spill.action.setRowContainer(nil)
+			e.releaseOuterMatchedStatus()
+			terror.Call(rowContainer.Close)
+		}
+	}()
+
+	splittable := p.splittable && p.build.NumChunks() > 1
+	if splittable {
+		e.spill.action.startBuild()
+	} else {
+		e.spill.action.setRowContainer(rowContainer)
+	}
+	var selected []bool
+	for i := 0; i < p.build.NumChunks(); i++ {
+		chk, err := p.build.GetChunk(i)
+		if err != nil {
+			return rowContainer, err
+		}
+		if selected, err = e.putBuildSideChunk(rowContainer, chk, selected); err != nil {
+			return rowContainer, err
+		}
+		if splittable && e.spill.action.spillRequested() {
+			return nil, e.splitPartition(p, rowContainer, i+1)
+		}
+	}
+	e.spill.action.setRowContainer(rowContainer)
+	return rowContainer, nil
+}
+
+// splitPartition partitions the rows of p again by the next bits of the hash values, the build side rows
+// before nextBuildChk have been put into the row container.
+func (e *HashJoinExec) splitPartition(p *hashJoinPartition, rowContainer *hashRowContainer, nextBuildChk int) error {
+	e.spill.action.setRowContainer(nil)
+	parts := e.newSpillPartitions(p.round + 1)
+	e.spill.pending = append(e.spill.pending, parts...)
+	if err := e.spillRowContainer(parts, rowContainer); err != nil {
+		return err
+	}
+	for i := nextBuildChk; i < p.build.NumChunks(); i++ {
+		chk, err := p.build.GetChunk(i)
+		if err != nil {
+			return err
+		}
+		if err = e.spillChunk(parts, chk, true); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < p.probe.NumChunks(); i++ {
+		chk, err := p.probe.GetChunk(i)
+		if err != nil {
+			return err
+		}
+		if err = e.spillChunk(parts, chk, false); err != nil {
+			return err
+		}
+	}
+	if err := finishSpillPartitions(parts, true); err != nil {
+		return err
+	}
+	if err := finishSpillPartitions(parts, false); err != nil {
+		return err
+	}
+	// All the rows may have the same join key, splitting the partition again is useless.
+	for _, part := range parts {
+		if part.build.Len() == p.build.Len() {
+			part.splittable = false
+		}
+	}
+	return nil
+}
+
+func (e *HashJoinExec) releaseOuterMatchedStatus() {
+	for _, bitMap := range e.outerMatchedStatus {
+		e.memTracker.Consume(-bitMap.BytesConsumed())
+	}
+	e.outerMatchedStatus = e.outerMatchedStatus[:0]
+}
+
+// alreadySpilledSafeForTest indicates that the build side rows have been spilled into disk.
+// It should be called after the executor finishes.
+func (e *HashJoinExec) alreadySpilledSafeForTest() bool {
+	return (e.spill != nil && e.spill.rounds > 0) || e.rowContainer.alreadySpilledSafeForTest()
+}
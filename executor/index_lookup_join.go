@@ -603,7 +603,12 @@ func (iw *innerWorker) constructLookupContent(task *lookUpJoinTask) ([]*indexJoi
 	for i := range task.encodedLookUpKeys {
 		task.memTracker.Consume(task.encodedLookUpKeys[i].MemoryUsage())
 	}
+	lookUpKeys := len(lookUpContents)
 	lookUpContents = iw.sortAndDedupLookUpContents(lookUpContents)
+	if iw.stats != nil {
+		atomic.AddInt64(&iw.stats.lookUpKeys, int64(lookUpKeys))
+		atomic.AddInt64(&iw.stats.uniqueKeys, int64(len(lookUpContents)))
+	}
 	return lookUpContents, nil
 }
 
@@ -794,6 +799,9 @@ type innerWorkerRuntimeStats struct {
 	fetch     int64
 	build     int64
 	join      int64
+	// lookUpKeys and uniqueKeys are the number of inner lookup keys before and after deduplication.
+	lookUpKeys int64
+	uniqueKeys int64
 }
 
 func (e *indexLookUpJoinRuntimeStats) String() string {
@@ -809,6 +817,12 @@ func (e *indexLookUpJoinRuntimeStats) String() string {
 		}
 		buf.WriteString(", task:")
 		buf.WriteString(strconv.FormatInt(e.innerWorker.task, 10))
+		if e.innerWorker.lookUpKeys > 0 {
+			buf.WriteString(", keys:")
+			buf.WriteString(strconv.FormatInt(e.innerWorker.lookUpKeys, 10))
+			buf.WriteString(", unique keys:")
+			buf.WriteString(strconv.FormatInt(e.innerWorker.uniqueKeys, 10))
+		}
 		buf.WriteString(", construct:")
 		buf.WriteString(execdetails.FormatDuration(time.Duration(e.innerWorker.construct)))
 		buf.WriteString(", fetch:")
@@ -848,6 +862,8 @@ func (e *indexLookUpJoinRuntimeStats) Merge(rs execdetails.RuntimeStats) {
 	e.innerWorker.fetch += tmp.innerWorker.fetch
 	e.innerWorker.build += tmp.innerWorker.build
 	e.innerWorker.join += tmp.innerWorker.join
+	e.innerWorker.lookUpKeys += tmp.innerWorker.lookUpKeys
+	e.innerWorker.uniqueKeys += tmp.innerWorker.uniqueKeys
 }
 
 // Tp implements the RuntimeStats interface.
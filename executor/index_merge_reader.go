@@ -636,6 +636,12 @@ func (e *IndexMergeReaderExecutor) Close() error {
 	e.processWokerWg.Wait()
 	e.tblWorkerWg.Wait()
 	e.idxWorkerWg.Wait()
+	for _, idxInfo := range e.indexes {
+		// The partial path is a table scan if its index is nil.
+		if idxInfo != nil {
+			e.storeIndexUsage(e.table.Meta(), idxInfo)
+		}
+	}
 	e.finished = nil
 	e.workerStarted = false
 	// TODO: how to store e.feedbacks
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/israce"
 	"github.com/pingcap/tidb/util/testkit"
@@ -454,3 +455,49 @@ func (test *testSerialSuite2) TestIndexMergeReaderMemTracker(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(bytes, Greater, 0.0)
 }
+
+func (s *testSuite1) TestIndexMergeOnMVIndex(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Experimental.AllowsExpressionIndex = true
+	})
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("set @@tidb_enable_index_merge = 1")
+	tk.MustExec("create table t(a int primary key, j json, key idx((cast(j->'$.tags' as unsigned array))))")
+	tk.MustExec(`insert into t values (1, '{"tags": [1, 2, 3]}'), (2, '{"tags": [3, 4]}'), (3, '{"tags": 5}'), (4, '{"tags": []}'), (5, null)`)
+	tk.MustQuery("explain format = 'brief' select * from t where 3 member of (j->'$.tags')").Check(testkit.Rows(
+		"Selection 8000.00 root  json_memberof(cast(3, json BINARY), json_extract(test.t.j, \"$.tags\"))",
+		"└─IndexMerge 10.00 root  ",
+		"  ├─IndexRangeScan(Build) 10.00 cop[tikv] table:t, index:idx(cast(json_extract(`j`, _utf8mb4'$.tags') as unsigned array)) range:[3,3], keep order:false, stats:pseudo",
+		"  └─TableRowIDScan(Probe) 10.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("select a from t where 3 member of (j->'$.tags') order by a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t where json_contains(j->'$.tags', '5') order by a").Check(testkit.Rows("3"))
+	tk.MustQuery("select a from t where json_contains(j->'$.tags', '[1, 3]') order by a").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where 1 member of (j->'$.tags') or 4 member of (j->'$.tags') order by a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t where 6 member of (j->'$.tags')").Check(testkit.Rows())
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("5"))
+
+	tk.MustExec("update t set j = '{\"tags\": [6]}' where a = 2")
+	tk.MustExec("delete from t where a = 1")
+	tk.MustQuery("select a from t where 3 member of (j->'$.tags')").Check(testkit.Rows())
+	tk.MustQuery("select a from t where 6 member of (j->'$.tags')").Check(testkit.Rows("2"))
+	tk.MustExec("begin")
+	tk.MustExec(`insert into t values (6, '{"tags": [6, 7]}')`)
+	tk.MustQuery("select a from t where 6 member of (j->'$.tags') order by a").Check(testkit.Rows("2", "6"))
+	tk.MustExec("rollback")
+	tk.MustExec("admin check table t")
+	tk.MustGetErrMsg(`insert into t values (7, '{"tags": [-1]}')`, "[expression:3904]Out of range JSON value for CAST for expression index 'unsigned bigint'")
+	tk.MustGetErrMsg(`insert into t values (7, '{"tags": ["a"]}')`, "[expression:3903]Invalid JSON value for CAST for expression index 'unsigned bigint'")
+
+	// Build the multi-valued index on existing data.
+	tk.MustExec("drop table t")
+	tk.MustExec("create table t(a int, j json)")
+	tk.MustExec(`insert into t values (1, '["a", "b"]'), (2, '["b", "c"]'), (3, '"a"')`)
+	tk.MustExec("alter table t add index idx((cast(j as char(10) array)))")
+	tk.MustQuery("select /*+ use_index_merge(t, idx) */ a from t where 'a' member of (j) order by a").Check(testkit.Rows("1", "3"))
+	tk.MustQuery("select /*+ use_index_merge(t, idx) */ a from t where json_contains(j, '\"b\"') order by a").Check(testkit.Rows("1", "2"))
+	tk.MustGetErrMsg("alter table t add unique index uk((cast(j as char(10) array)))", "[ddl:8200]Unsupported unique key in multi-valued index")
+	tk.MustGetErrMsg("alter table t add index idx2(a, (cast(j as char(10) array)))", "[ddl:8200]Unsupported more than one key part in multi-valued index")
+	tk.MustGetErrMsg("create table t2(a int, j json, unique key uk((cast(j as signed array))))", "[ddl:8200]Unsupported unique key in multi-valued index")
+}
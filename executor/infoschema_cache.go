@@ -0,0 +1,147 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/model"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/types"
+)
+
+// metaRowsCache caches the rows of `information_schema.tables`, `columns` and `statistics` which are only decided by
+// the table meta, so a query on a huge number of tables doesn't need to rebuild them every time.
+// The cache follows the latest schema version incrementally: a table meta is immutable in the info schema, so the rows
+// of a table are rebuilt only if its meta is changed, and the rows of the dropped tables are evicted once the schema
+// version changes.
+type metaRowsCache struct {
+	mu            sync.Mutex
+	schemaVersion int64
+	entries       map[int64]*metaRowsEntry
+}
+
+type metaRowsEntry struct {
+	schema model.CIStr
+	table  *model.TableInfo
+	// tables is the row of `information_schema.tables` without the statistics and auto id.
+	tables []types.Datum
+	// columns is the rows of `information_schema.columns` without the privileges.
+	columns [][]types.Datum
+	// statistics is the rows of `information_schema.statistics`.
+	statistics [][]types.Datum
+}
+
+var tableMetaRowsCache = &metaRowsCache{entries: make(map[int64]*metaRowsEntry)}
+
+// getEntry returns the cached entry of the table, the caller must hold the lock.
+func (c *metaRowsCache) getEntry(is infoschema.InfoSchema, schema *model.DBInfo, tbl *model.TableInfo) *metaRowsEntry {
+	version := is.SchemaMetaVersion()
+	if version < c.schemaVersion {
+		// Don't pollute the cache with the stale info schema, e.g. reading with `tidb_snapshot`.
+		return &metaRowsEntry{schema: schema.Name, table: tbl}
+	}
+	if version > c.schemaVersion {
+		for id, entry := range c.entries {
+			if t, ok := is.TableByID(id); !ok || t.Meta() != entry.table {
+				delete(c.entries, id)
+			}
+		}
+		c.schemaVersion = version
+	}
+	entry, ok := c.entries[tbl.ID]
+	if !ok || entry.table != tbl || entry.schema.O != schema.Name.O {
+		entry = &metaRowsEntry{schema: schema.Name, table: tbl}
+		c.entries[tbl.ID] = entry
+	}
+	return entry
+}
+
+func (c *metaRowsCache) tablesRow(is infoschema.InfoSchema, schema *model.DBInfo, tbl *model.TableInfo) []types.Datum {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := c.getEntry(is, schema, tbl)
+	if entry.tables == nil {
+		entry.tables = buildTablesRow(schema, tbl)
+	}
+	return entry.tables
+}
+
+func (c *metaRowsCache) columnsRows(is infoschema.InfoSchema, schema *model.DBInfo, tbl *model.TableInfo) [][]types.Datum {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := c.getEntry(is, schema, tbl)
+	if entry.columns == nil {
+		entry.columns = buildColumnsRows(schema, tbl)
+	}
+	return entry.columns
+}
+
+func (c *metaRowsCache) statisticsRows(is infoschema.InfoSchema, schema *model.DBInfo, tbl *model.TableInfo) [][]types.Datum {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := c.getEntry(is, schema, tbl)
+	if entry.statistics == nil {
+		entry.statistics = buildStatisticsRows(schema, tbl)
+	}
+	return entry.statistics
+}
+
+// filterSchemasByExtractor returns the schemas matching the `table_schema` predicates.
+func filterSchemasByExtractor(is infoschema.InfoSchema, extractor *plannercore.InfoSchemaTablesExtractor, dbs []*model.DBInfo) []*model.DBInfo {
+	if extractor == nil {
+		return dbs
+	}
+	if extractor.SkipRequest {
+		return nil
+	}
+	if len(extractor.TableSchema) == 0 {
+		return dbs
+	}
+	filtered := make([]*model.DBInfo, 0, len(extractor.TableSchema))
+	for name := range extractor.TableSchema {
+		if db, ok := is.SchemaByName(model.NewCIStr(name)); ok {
+			filtered = append(filtered, db)
+		}
+	}
+	sort.Sort(infoschema.SchemasSorter(filtered))
+	return filtered
+}
+
+// filterTablesByExtractor returns the tables of the schema matching the `table_name` predicates.
+func filterTablesByExtractor(is infoschema.InfoSchema, extractor *plannercore.InfoSchemaTablesExtractor, schema *model.DBInfo) []*model.TableInfo {
+	if extractor == nil {
+		return schema.Tables
+	}
+	if extractor.SkipRequest {
+		return nil
+	}
+	if len(extractor.TableName) == 0 {
+		return schema.Tables
+	}
+	tables := make([]*model.TableInfo, 0, len(extractor.TableName))
+	for name := range extractor.TableName {
+		// The local temporary tables are invisible in the metadata tables.
+		if tbl, err := is.TableByName(schema.Name, model.NewCIStr(name)); err == nil && tbl.Meta().TempTableType != model.TempTableLocal {
+			tables = append(tables, tbl.Meta())
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name.L < tables[j].Name.L
+	})
+	return tables
+}
@@ -40,7 +40,9 @@ import (
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -52,6 +54,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/store/helper"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
@@ -60,6 +63,8 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/deadlockhistory"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/expensivequery"
 	"github.com/pingcap/tidb/util/keydecoder"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/pdapi"
@@ -77,6 +82,7 @@ type memtableRetriever struct {
 	dummyCloser
 	table       *model.TableInfo
 	columns     []*model.ColumnInfo
+	extractor   *plannercore.InfoSchemaTablesExtractor
 	rows        [][]types.Datum
 	rowIdx      int
 	retrieved   bool
@@ -102,9 +108,9 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 		case infoschema.TableSchemata:
 			e.setDataFromSchemata(sctx, dbs)
 		case infoschema.TableStatistics:
-			e.setDataForStatistics(sctx, dbs)
+			e.setDataForStatistics(sctx, filterSchemasByExtractor(is, e.extractor, dbs))
 		case infoschema.TableTables:
-			err = e.setDataFromTables(ctx, sctx, dbs)
+			err = e.setDataFromTables(ctx, sctx, filterSchemasByExtractor(is, e.extractor, dbs))
 		case infoschema.TableReferConst:
 			err = e.setDataFromReferConst(ctx, sctx, dbs)
 		case infoschema.TableSequences:
@@ -114,9 +120,13 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 		case infoschema.TableClusterInfo:
 			err = e.dataForTiDBClusterInfo(sctx)
 		case infoschema.TableAnalyzeStatus:
-			e.setDataForAnalyzeStatus(sctx)
+			err = e.setDataForAnalyzeStatus(ctx, sctx)
 		case infoschema.TableTiDBIndexes:
 			e.setDataFromIndexes(sctx, dbs)
+		case infoschema.TableTiDBIndexUsage:
+			err = e.setDataForIndexUsage(ctx, sctx, dbs)
+		case infoschema.TableTiDBAutoRandomIDSpace:
+			err = e.setDataForAutoRandomIDSpace(sctx, dbs)
 		case infoschema.TableViews:
 			e.setDataFromViews(sctx, dbs)
 		case infoschema.TableEngines:
@@ -158,6 +168,9 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 		case infoschema.TableStatementsSummaryEvicted,
 			infoschema.ClusterTableStatementsSummaryEvicted:
 			err = e.setDataForStatementsSummaryEvicted(sctx)
+		case infoschema.TableTrxSummary,
+			infoschema.ClusterTableTrxSummary:
+			err = e.setDataForTrxSummary(sctx)
 		case infoschema.TableClientErrorsSummaryGlobal,
 			infoschema.TableClientErrorsSummaryByUser,
 			infoschema.TableClientErrorsSummaryByHost:
@@ -166,6 +179,18 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataForAttributes(sctx)
 		case infoschema.TablePlacementRules:
 			err = e.setDataFromPlacementRules(ctx, sctx, dbs)
+		case infoschema.TableTiDBSpillFiles:
+			e.setDataForSpillFiles(sctx)
+		case infoschema.TableSessionConnectAttrs:
+			e.setDataForSessionConnectAttrs(sctx)
+		case infoschema.TableTiKVEncryptionStatus:
+			err = e.setDataForTiKVEncryptionStatus(sctx)
+		case infoschema.TableMemoryUsage:
+			err = e.setDataForMemoryUsage(sctx)
+		case infoschema.TableMemoryUsageOpsHistory:
+			err = e.setDataForMemoryUsageOpsHistory(sctx)
+		case infoschema.TablePlanReplayerCaptures:
+			err = e.setDataForPlanReplayerCaptures(sctx)
 		}
 		if err != nil {
 			return nil, err
@@ -380,19 +405,20 @@ func (e *memtableRetriever) setDataFromSchemata(ctx sessionctx.Context, schemas
 }
 
 func (e *memtableRetriever) setDataForStatistics(ctx sessionctx.Context, schemas []*model.DBInfo) {
+	is := ctx.GetInfoSchema().(infoschema.InfoSchema)
 	checker := privilege.GetPrivilegeManager(ctx)
 	for _, schema := range schemas {
-		for _, table := range schema.Tables {
+		for _, table := range filterTablesByExtractor(is, e.extractor, schema) {
 			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.AllPrivMask) {
 				continue
 			}
-			e.setDataForStatisticsInTable(schema, table)
+			e.rows = append(e.rows, tableMetaRowsCache.statisticsRows(is, schema, table)...)
 		}
 	}
 }
 
-func (e *memtableRetriever) setDataForStatisticsInTable(schema *model.DBInfo, table *model.TableInfo) {
-	var rows [][]types.Datum
+func buildStatisticsRows(schema *model.DBInfo, table *model.TableInfo) [][]types.Datum {
+	rows := make([][]types.Datum, 0, len(table.Indices))
 	if table.PKIsHandle {
 		for _, col := range table.Columns {
 			if mysql.HasPriKeyFlag(col.Flag) {
@@ -473,7 +499,7 @@ func (e *memtableRetriever) setDataForStatisticsInTable(schema *model.DBInfo, ta
 			rows = append(rows, record)
 		}
 	}
-	e.rows = append(e.rows, rows...)
+	return rows
 }
 
 func (e *memtableRetriever) setDataFromReferConst(ctx context.Context, sctx sessionctx.Context, schemas []*model.DBInfo) error {
@@ -522,150 +548,165 @@ func (e *memtableRetriever) setDataFromTables(ctx context.Context, sctx sessionc
 		return err
 	}
 
+	is := sctx.GetInfoSchema().(infoschema.InfoSchema)
 	checker := privilege.GetPrivilegeManager(sctx)
 
 	var rows [][]types.Datum
-	createTimeTp := mysql.TypeDatetime
 	for _, schema := range schemas {
-		for _, table := range schema.Tables {
-			collation := table.Collate
-			if collation == "" {
-				collation = mysql.DefaultCollationName
-			}
-			createTime := types.NewTime(types.FromGoTime(table.GetUpdateTime()), createTimeTp, types.DefaultFsp)
-
-			createOptions := ""
-
+		for _, table := range filterTablesByExtractor(is, e.extractor, schema) {
 			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.AllPrivMask) {
 				continue
 			}
-			pkType := "NONCLUSTERED"
-			if !table.IsView() {
-				if table.GetPartitionInfo() != nil {
-					createOptions = "partitioned"
-				} else if table.TableCacheStatusType == model.TableCacheStatusEnable {
-					createOptions = "cached=on"
-				}
-				var autoIncID interface{}
-				hasAutoIncID, _ := infoschema.HasAutoIncrementColumn(table)
-				if hasAutoIncID {
-					autoIncID, err = getAutoIncrementID(sctx, schema, table)
-					if err != nil {
-						return err
-					}
+			record := tableMetaRowsCache.tablesRow(is, schema, table)
+			if table.IsView() {
+				rows = append(rows, record)
+				continue
+			}
+			var autoIncID interface{}
+			hasAutoIncID, _ := infoschema.HasAutoIncrementColumn(table)
+			if hasAutoIncID {
+				autoIncID, err = getAutoIncrementID(sctx, schema, table)
+				if err != nil {
+					return err
 				}
+			}
 
-				var rowCount, dataLength, indexLength uint64
-				if table.GetPartitionInfo() == nil {
-					rowCount = tableRowsMap[table.ID]
-					dataLength, indexLength = getDataAndIndexLength(table, table.ID, rowCount, colLengthMap)
-				} else {
-					for _, pi := range table.GetPartitionInfo().Definitions {
-						rowCount += tableRowsMap[pi.ID]
-						parDataLen, parIndexLen := getDataAndIndexLength(table, pi.ID, tableRowsMap[pi.ID], colLengthMap)
-						dataLength += parDataLen
-						indexLength += parIndexLen
-					}
-				}
-				avgRowLength := uint64(0)
-				if rowCount != 0 {
-					avgRowLength = dataLength / rowCount
-				}
-				tableType := "BASE TABLE"
-				if util.IsSystemView(schema.Name.L) {
-					tableType = "SYSTEM VIEW"
-				}
-				if table.IsSequence() {
-					tableType = "SEQUENCE"
-					if rowCount == 0 {
-						rowCount = 1
-					}
-				}
-				if table.HasClusteredIndex() {
-					pkType = "CLUSTERED"
-				}
-				shardingInfo := infoschema.GetShardingInfo(schema, table)
-				var policyName, directPlacement interface{}
-				if table.PlacementPolicyRef != nil {
-					policyName = table.PlacementPolicyRef.Name.O
-				}
-				if table.DirectPlacementOpts != nil {
-					directPlacement = table.DirectPlacementOpts.String()
-				}
-				record := types.MakeDatums(
-					infoschema.CatalogVal, // TABLE_CATALOG
-					schema.Name.O,         // TABLE_SCHEMA
-					table.Name.O,          // TABLE_NAME
-					tableType,             // TABLE_TYPE
-					"InnoDB",              // ENGINE
-					uint64(10),            // VERSION
-					"Compact",             // ROW_FORMAT
-					rowCount,              // TABLE_ROWS
-					avgRowLength,          // AVG_ROW_LENGTH
-					dataLength,            // DATA_LENGTH
-					uint64(0),             // MAX_DATA_LENGTH
-					indexLength,           // INDEX_LENGTH
-					uint64(0),             // DATA_FREE
-					autoIncID,             // AUTO_INCREMENT
-					createTime,            // CREATE_TIME
-					nil,                   // UPDATE_TIME
-					nil,                   // CHECK_TIME
-					collation,             // TABLE_COLLATION
-					nil,                   // CHECKSUM
-					createOptions,         // CREATE_OPTIONS
-					table.Comment,         // TABLE_COMMENT
-					table.ID,              // TIDB_TABLE_ID
-					shardingInfo,          // TIDB_ROW_ID_SHARDING_INFO
-					pkType,                // TIDB_PK_TYPE
-					policyName,            // TIDB_PLACEMENT_POLICY_NAME
-					directPlacement,       // TIDB_DIRECT_PLACEMENT
-				)
-				rows = append(rows, record)
+			var rowCount, dataLength, indexLength uint64
+			if table.GetPartitionInfo() == nil {
+				rowCount = tableRowsMap[table.ID]
+				dataLength, indexLength = getDataAndIndexLength(table, table.ID, rowCount, colLengthMap)
 			} else {
-				record := types.MakeDatums(
-					infoschema.CatalogVal, // TABLE_CATALOG
-					schema.Name.O,         // TABLE_SCHEMA
-					table.Name.O,          // TABLE_NAME
-					"VIEW",                // TABLE_TYPE
-					nil,                   // ENGINE
-					nil,                   // VERSION
-					nil,                   // ROW_FORMAT
-					nil,                   // TABLE_ROWS
-					nil,                   // AVG_ROW_LENGTH
-					nil,                   // DATA_LENGTH
-					nil,                   // MAX_DATA_LENGTH
-					nil,                   // INDEX_LENGTH
-					nil,                   // DATA_FREE
-					nil,                   // AUTO_INCREMENT
-					createTime,            // CREATE_TIME
-					nil,                   // UPDATE_TIME
-					nil,                   // CHECK_TIME
-					nil,                   // TABLE_COLLATION
-					nil,                   // CHECKSUM
-					nil,                   // CREATE_OPTIONS
-					"VIEW",                // TABLE_COMMENT
-					table.ID,              // TIDB_TABLE_ID
-					nil,                   // TIDB_ROW_ID_SHARDING_INFO
-					pkType,                // TIDB_PK_TYPE
-					nil,                   // TIDB_PLACEMENT_POLICY_NAME
-					nil,                   // TIDB_DIRECT_PLACEMENT
-				)
-				rows = append(rows, record)
+				for _, pi := range table.GetPartitionInfo().Definitions {
+					rowCount += tableRowsMap[pi.ID]
+					parDataLen, parIndexLen := getDataAndIndexLength(table, pi.ID, tableRowsMap[pi.ID], colLengthMap)
+					dataLength += parDataLen
+					indexLength += parIndexLen
+				}
+			}
+			avgRowLength := uint64(0)
+			if rowCount != 0 {
+				avgRowLength = dataLength / rowCount
+			}
+			if table.IsSequence() && rowCount == 0 {
+				rowCount = 1
 			}
+			// The cached row is shared, fill the statistics and auto id into a copy of it.
+			record = append([]types.Datum(nil), record...)
+			record[7].SetUint64(rowCount)          // TABLE_ROWS
+			record[8].SetUint64(avgRowLength)      // AVG_ROW_LENGTH
+			record[9].SetUint64(dataLength)        // DATA_LENGTH
+			record[11].SetUint64(indexLength)      // INDEX_LENGTH
+			record[13] = types.NewDatum(autoIncID) // AUTO_INCREMENT
+			rows = append(rows, record)
 		}
 	}
 	e.rows = rows
 	return nil
 }
 
+// buildTablesRow builds the row of `information_schema.tables` which is only decided by the table meta, the statistics
+// and the auto id of a base table are left as zero.
+func buildTablesRow(schema *model.DBInfo, table *model.TableInfo) []types.Datum {
+	collation := table.Collate
+	if collation == "" {
+		collation = mysql.DefaultCollationName
+	}
+	createTime := types.NewTime(types.FromGoTime(table.GetUpdateTime()), mysql.TypeDatetime, types.DefaultFsp)
+	pkType := "NONCLUSTERED"
+	if table.IsView() {
+		return types.MakeDatums(
+			infoschema.CatalogVal, // TABLE_CATALOG
+			schema.Name.O,         // TABLE_SCHEMA
+			table.Name.O,          // TABLE_NAME
+			"VIEW",                // TABLE_TYPE
+			nil,                   // ENGINE
+			nil,                   // VERSION
+			nil,                   // ROW_FORMAT
+			nil,                   // TABLE_ROWS
+			nil,                   // AVG_ROW_LENGTH
+			nil,                   // DATA_LENGTH
+			nil,                   // MAX_DATA_LENGTH
+			nil,                   // INDEX_LENGTH
+			nil,                   // DATA_FREE
+			nil,                   // AUTO_INCREMENT
+			createTime,            // CREATE_TIME
+			nil,                   // UPDATE_TIME
+			nil,                   // CHECK_TIME
+			nil,                   // TABLE_COLLATION
+			nil,                   // CHECKSUM
+			nil,                   // CREATE_OPTIONS
+			"VIEW",                // TABLE_COMMENT
+			table.ID,              // TIDB_TABLE_ID
+			nil,                   // TIDB_ROW_ID_SHARDING_INFO
+			pkType,                // TIDB_PK_TYPE
+			nil,                   // TIDB_PLACEMENT_POLICY_NAME
+			nil,                   // TIDB_DIRECT_PLACEMENT
+		)
+	}
+	createOptions := ""
+	if table.GetPartitionInfo() != nil {
+		createOptions = "partitioned"
+	} else if table.TableCacheStatusType == model.TableCacheStatusEnable {
+		createOptions = "cached=on"
+	}
+	tableType := "BASE TABLE"
+	if util.IsSystemView(schema.Name.L) {
+		tableType = "SYSTEM VIEW"
+	}
+	if table.IsSequence() {
+		tableType = "SEQUENCE"
+	}
+	if table.HasClusteredIndex() {
+		pkType = "CLUSTERED"
+	}
+	shardingInfo := infoschema.GetShardingInfo(schema, table)
+	var policyName, directPlacement interface{}
+	if table.PlacementPolicyRef != nil {
+		policyName = table.PlacementPolicyRef.Name.O
+	}
+	if table.DirectPlacementOpts != nil {
+		directPlacement = table.DirectPlacementOpts.String()
+	}
+	return types.MakeDatums(
+		infoschema.CatalogVal, // TABLE_CATALOG
+		schema.Name.O,         // TABLE_SCHEMA
+		table.Name.O,          // TABLE_NAME
+		tableType,             // TABLE_TYPE
+		"InnoDB",              // ENGINE
+		uint64(10),            // VERSION
+		"Compact",             // ROW_FORMAT
+		uint64(0),             // TABLE_ROWS
+		uint64(0),             // AVG_ROW_LENGTH
+		uint64(0),             // DATA_LENGTH
+		uint64(0),             // MAX_DATA_LENGTH
+		uint64(0),             // INDEX_LENGTH
+		uint64(0),             // DATA_FREE
+		nil,                   // AUTO_INCREMENT
+		createTime,            // CREATE_TIME
+		nil,                   // UPDATE_TIME
+		nil,                   // CHECK_TIME
+		collation,             // TABLE_COLLATION
+		nil,                   // CHECKSUM
+		createOptions,         // CREATE_OPTIONS
+		table.Comment,         // TABLE_COMMENT
+		table.ID,              // TIDB_TABLE_ID
+		shardingInfo,          // TIDB_ROW_ID_SHARDING_INFO
+		pkType,                // TIDB_PK_TYPE
+		policyName,            // TIDB_PLACEMENT_POLICY_NAME
+		directPlacement,       // TIDB_DIRECT_PLACEMENT
+	)
+}
+
 func (e *hugeMemTableRetriever) setDataForColumns(ctx context.Context, sctx sessionctx.Context) error {
 	checker := privilege.GetPrivilegeManager(sctx)
 	e.rows = e.rows[:0]
 	batch := 1024
+	is := sctx.GetInfoSchema().(infoschema.InfoSchema)
 	for ; e.dbsIdx < len(e.dbs); e.dbsIdx++ {
 		schema := e.dbs[e.dbsIdx]
-		for e.tblIdx < len(schema.Tables) {
-			table := schema.Tables[e.tblIdx]
+		tables := filterTablesByExtractor(is, e.extractor, schema)
+		for e.tblIdx < len(tables) {
+			table := tables[e.tblIdx]
 			e.tblIdx++
 			hasPrivs := false
 			var priv mysql.PrivilegeType
@@ -692,10 +733,30 @@ func (e *hugeMemTableRetriever) setDataForColumns(ctx context.Context, sctx sess
 }
 
 func (e *hugeMemTableRetriever) dataForColumnsInTable(ctx context.Context, sctx sessionctx.Context, schema *model.DBInfo, tbl *model.TableInfo, priv mysql.PrivilegeType) {
-	if err := tryFillViewColumnType(ctx, sctx, sctx.GetInfoSchema().(infoschema.InfoSchema), schema.Name, tbl); err != nil {
-		sctx.GetSessionVars().StmtCtx.AppendWarning(err)
-		return
+	is := sctx.GetInfoSchema().(infoschema.InfoSchema)
+	var rows [][]types.Datum
+	if tbl.IsView() {
+		// The column types of a view are decided by the session, so they can't be cached.
+		if err := tryFillViewColumnType(ctx, sctx, is, schema.Name, tbl); err != nil {
+			sctx.GetSessionVars().StmtCtx.AppendWarning(err)
+			return
+		}
+		rows = buildColumnsRows(schema, tbl)
+	} else {
+		rows = tableMetaRowsCache.columnsRows(is, schema, tbl)
+	}
+	privs := strings.ToLower(privileges.PrivToString(priv, mysql.AllColumnPrivs, mysql.Priv2Str))
+	for _, row := range rows {
+		// The cached rows are shared, fill the privileges into a copy of them.
+		record := append([]types.Datum(nil), row...)
+		record[18] = types.NewStringDatum(privs) // PRIVILEGES
+		e.rows = append(e.rows, record)
 	}
+}
+
+// buildColumnsRows builds the rows of `information_schema.columns` of the table, the privileges are left as null.
+func buildColumnsRows(schema *model.DBInfo, tbl *model.TableInfo) [][]types.Datum {
+	rows := make([][]types.Datum, 0, len(tbl.Columns))
 	for i, col := range tbl.Columns {
 		if col.Hidden {
 			continue
@@ -772,12 +833,13 @@ func (e *hugeMemTableRetriever) dataForColumnsInTable(ctx context.Context, sctx
 			columnType,                           // COLUMN_TYPE
 			columnDesc.Key,                       // COLUMN_KEY
 			columnDesc.Extra,                     // EXTRA
-			strings.ToLower(privileges.PrivToString(priv, mysql.AllColumnPrivs, mysql.Priv2Str)), // PRIVILEGES
-			columnDesc.Comment,      // COLUMN_COMMENT
-			col.GeneratedExprString, // GENERATION_EXPRESSION
+			nil,                                  // PRIVILEGES
+			columnDesc.Comment,                   // COLUMN_COMMENT
+			col.GeneratedExprString,              // GENERATION_EXPRESSION
 		)
-		e.rows = append(e.rows, record)
+		rows = append(rows, record)
 	}
+	return rows
 }
 
 func calcCharOctLength(lenInChar int, cs string) int {
@@ -1124,6 +1186,115 @@ func (e *memtableRetriever) dataForTiKVStoreStatus(ctx sessionctx.Context) (err
 	return nil
 }
 
+func (e *memtableRetriever) setDataForTiKVEncryptionStatus(ctx sessionctx.Context) error {
+	tikvStore, ok := ctx.GetStore().(helper.Storage)
+	if !ok {
+		return errors.New("Information about TiKV encryption status can be gotten only when the storage is TiKV")
+	}
+	tikvHelper := &helper.Helper{
+		Store:       tikvStore,
+		RegionCache: tikvStore.GetRegionCache(),
+	}
+	storesStat, err := tikvHelper.GetStoresStat()
+	if err != nil {
+		return err
+	}
+	hideAddress := false
+	if sem.IsEnabled() {
+		checker := privilege.GetPrivilegeManager(ctx)
+		hideAddress = checker == nil || !checker.RequestDynamicVerification(ctx.GetSessionVars().ActiveRoles, "RESTRICTED_TABLES_ADMIN", false)
+	}
+	for _, storeStat := range storesStat.Stores {
+		if storeStat.Store.StateName == helper.StoreStateTombstone {
+			continue
+		}
+		row := make([]types.Datum, len(infoschema.TableTiKVEncryptionStatusCols))
+		row[0].SetInt64(storeStat.Store.ID)
+		if !hideAddress {
+			row[1].SetString(storeStat.Store.Address, mysql.DefaultCollationName)
+		}
+		// An unavailable store is reported with the error instead of failing the query.
+		status, err := helper.GetStoreEncryptionStatus(storeStat.Store.StatusAddress)
+		if err != nil {
+			row[7].SetString(err.Error(), mysql.DefaultCollationName)
+			e.rows = append(e.rows, row)
+			continue
+		}
+		row[2].SetString(status.Method, mysql.DefaultCollationName)
+		row[3].SetString(status.MasterKeyType, mysql.DefaultCollationName)
+		row[4].SetString(status.MasterKeyID, mysql.DefaultCollationName)
+		row[5].SetString(status.RotationState, mysql.DefaultCollationName)
+		if status.LastRotationTimestamp > 0 {
+			lastRotationTime := time.Unix(status.LastRotationTimestamp, 0)
+			row[6].SetMysqlTime(types.NewTime(types.FromGoTime(lastRotationTime), mysql.TypeDatetime, types.DefaultFsp))
+		}
+		if status.Error != "" {
+			row[7].SetString(status.Error, mysql.DefaultCollationName)
+		}
+		e.rows = append(e.rows, row)
+	}
+	return nil
+}
+
+func (e *memtableRetriever) setDataForMemoryUsage(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	for _, usage := range GetMemoryUsage(domain.GetDomain(ctx), ctx.GetSessionManager()) {
+		e.rows = append(e.rows, types.MakeDatums(usage.Category, usage.Bytes, usage.Description))
+	}
+	return nil
+}
+
+func (e *memtableRetriever) setDataForMemoryUsageOpsHistory(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	for _, ops := range expensivequery.GetMemoryOpsHistory() {
+		row := make([]types.Datum, len(e.table.Columns))
+		row[0].SetMysqlTime(types.NewTime(types.FromGoTime(ops.Time), mysql.TypeDatetime, types.DefaultFsp))
+		row[1].SetString(ops.Ops, mysql.DefaultCollationName)
+		row[2].SetInt64(int64(ops.MemoryLimit))
+		row[3].SetInt64(int64(ops.MemoryCurrent))
+		// The columns of the killed statement are NULL if no statement is killed.
+		if ops.ConnID != 0 {
+			row[4].SetUint64(ops.ConnID)
+			row[5].SetInt64(ops.Mem)
+			row[6].SetString(ops.User, mysql.DefaultCollationName)
+			row[7].SetString(ops.Host, mysql.DefaultCollationName)
+			row[8].SetString(ops.DB, mysql.DefaultCollationName)
+			row[9].SetString(ops.SQLDigest, mysql.DefaultCollationName)
+			row[10].SetString(ops.SQLText, mysql.DefaultCollationName)
+		}
+		e.rows = append(e.rows, row)
+	}
+	return nil
+}
+
+func (e *memtableRetriever) setDataForPlanReplayerCaptures(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	for _, record := range domain.GetDomain(ctx).GetPlanReplayerCaptureRecords() {
+		row := make([]types.Datum, len(e.table.Columns))
+		row[0].SetString(record.SQLDigest, mysql.DefaultCollationName)
+		row[1].SetString(record.PlanDigest, mysql.DefaultCollationName)
+		row[2].SetString(record.Reason, mysql.DefaultCollationName)
+		row[3].SetFloat64(record.Latency.Seconds())
+		row[4].SetString(record.DB, mysql.DefaultCollationName)
+		row[5].SetString(record.SQL, mysql.DefaultCollationName)
+		row[6].SetMysqlTime(types.NewTime(types.FromGoTime(record.CaptureTime), mysql.TypeDatetime, types.DefaultFsp))
+		if record.FileName != "" {
+			row[7].SetString(record.FileName, mysql.DefaultCollationName)
+		}
+		if record.Err != nil {
+			row[8].SetString(record.Err.Error(), mysql.DefaultCollationName)
+		}
+		e.rows = append(e.rows, row)
+	}
+	return nil
+}
+
 // DDLJobsReaderExec executes DDLJobs information retrieving.
 type DDLJobsReaderExec struct {
 	baseExecutor
@@ -1319,13 +1490,98 @@ func (e *memtableRetriever) setDataForProcessList(ctx sessionctx.Context) {
 			continue
 		}
 
-		rows := pi.ToRow(ctx.GetSessionVars().StmtCtx.TimeZone)
+		rows := desensitizeProcessInfo(pi, loginUser).ToRow(ctx.GetSessionVars().StmtCtx.TimeZone)
 		record := types.MakeDatums(rows...)
 		records = append(records, record)
 	}
 	e.rows = records
 }
 
+// desensitizeProcessInfo replaces the literals in the query of the other users with placeholders if
+// tidb_log_desensitization is on, so that their data isn't exposed by the processlist.
+func desensitizeProcessInfo(pi *util.ProcessInfo, loginUser *auth.UserIdentity) *util.ProcessInfo {
+	if !variable.LogDesensitization.Load() || pi.Info == "" || (loginUser != nil && pi.User == loginUser.Username) {
+		return pi
+	}
+	desensitized := *pi
+	desensitized.Info = parser.Normalize(pi.Info)
+	return &desensitized
+}
+
+func (e *memtableRetriever) setDataForSessionConnectAttrs(ctx sessionctx.Context) {
+	sm := ctx.GetSessionManager()
+	if sm == nil {
+		return
+	}
+
+	loginUser := ctx.GetSessionVars().User
+	hasProcessPriv := hasPriv(ctx, mysql.ProcessPriv)
+	pl := sm.ShowProcessList()
+
+	var records [][]types.Datum
+	for _, pi := range pl {
+		// Like the processlist, you can see only the attributes of your own connections without the PROCESS privilege.
+		if !hasProcessPriv && loginUser != nil && pi.User != loginUser.Username {
+			continue
+		}
+		names := make([]string, 0, len(pi.ConnectionAttrs))
+		for name := range pi.ConnectionAttrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			records = append(records, types.MakeDatums(
+				pi.ID,                    // PROCESSLIST_ID
+				name,                     // ATTR_NAME
+				pi.ConnectionAttrs[name], // ATTR_VALUE
+				i,                        // ORDINAL_POSITION
+			))
+		}
+	}
+	e.rows = records
+}
+
+func (e *memtableRetriever) setDataForSpillFiles(ctx sessionctx.Context) {
+	var pl map[uint64]*util.ProcessInfo
+	if sm := ctx.GetSessionManager(); sm != nil {
+		pl = sm.ShowProcessList()
+	}
+	loginUser := ctx.GetSessionVars().User
+	hasProcessPriv := hasPriv(ctx, mysql.ProcessPriv)
+
+	files := disk.GetSpillFiles()
+	records := make([][]types.Datum, 0, len(files))
+	for _, file := range files {
+		// Find the statement owning the file by its disk tracker.
+		var owner *util.ProcessInfo
+		for _, pi := range pl {
+			if pi.StmtCtx != nil && pi.StmtCtx.DiskTracker != nil && file.Tracker.HasAncestor(pi.StmtCtx.DiskTracker) {
+				owner = pi
+				break
+			}
+		}
+		// Like the processlist, you can see only the files of your own statements without the PROCESS privilege.
+		if !hasProcessPriv && (owner == nil || loginUser != nil && owner.User != loginUser.Username) {
+			continue
+		}
+		record := types.MakeDatums(
+			nil,                          // ID
+			nil,                          // DIGEST
+			file.Path,                    // FILE_PATH
+			file.Tracker.BytesConsumed(), // SIZE
+			types.NewTime(types.FromGoTime(file.CreateTime), mysql.TypeDatetime, types.DefaultFsp), // CREATE_TIME
+		)
+		if owner != nil {
+			record[0].SetUint64(owner.ID)
+			if len(owner.Digest) > 0 {
+				record[1].SetString(owner.Digest, mysql.DefaultCollationName)
+			}
+		}
+		records = append(records, record)
+	}
+	e.rows = records
+}
+
 func (e *memtableRetriever) setDataFromUserPrivileges(ctx sessionctx.Context) {
 	pm := privilege.GetPrivilegeManager(ctx)
 	// The results depend on the user querying the information.
@@ -1823,42 +2079,134 @@ func (e *memtableRetriever) setDataFromSessionVar(ctx sessionctx.Context) error
 	return nil
 }
 
-// dataForAnalyzeStatusHelper is a helper function which can be used in show_stats.go
-func dataForAnalyzeStatusHelper(sctx sessionctx.Context) (rows [][]types.Datum) {
+func (e *memtableRetriever) setDataForIndexUsage(ctx context.Context, sctx sessionctx.Context, schemas []*model.DBInfo) error {
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParamsInternal(ctx, "select table_id, index_id, query_count, rows_selected, rows_written, last_used_at from mysql.schema_index_usage")
+	if err != nil {
+		return err
+	}
+	chunkRows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	usages := make(map[handle.GlobalIndexID]chunk.Row, len(chunkRows))
+	for _, row := range chunkRows {
+		usages[handle.GlobalIndexID{TableID: row.GetInt64(0), IndexID: row.GetInt64(1)}] = row
+	}
 	checker := privilege.GetPrivilegeManager(sctx)
-	for _, job := range statistics.GetAllAnalyzeJobs() {
-		job.Lock()
-		var startTime, endTime interface{}
-		if job.StartTime.IsZero() {
-			startTime = nil
-		} else {
-			startTime = types.NewTime(types.FromGoTime(job.StartTime), mysql.TypeDatetime, 0)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, tb := range schema.Tables {
+			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.Name.L, tb.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			for _, idxInfo := range tb.Indices {
+				// The clustered primary key is read and written along with the rows, its usage isn't collected.
+				if idxInfo.State != model.StatePublic || (tb.IsCommonHandle && idxInfo.Primary) {
+					continue
+				}
+				// The indexes which have never been used have no usage information.
+				var queryCount, rowsSelected, rowsWritten int64
+				var lastUsedAt interface{}
+				if row, ok := usages[handle.GlobalIndexID{TableID: tb.ID, IndexID: idxInfo.ID}]; ok {
+					queryCount, rowsSelected, rowsWritten = row.GetInt64(2), row.GetInt64(3), row.GetInt64(4)
+					if !row.IsNull(5) {
+						lastUsedAt = row.GetTime(5)
+					}
+				}
+				rows = append(rows, types.MakeDatums(
+					schema.Name.O,  // TABLE_SCHEMA
+					tb.Name.O,      // TABLE_NAME
+					idxInfo.Name.O, // INDEX_NAME
+					tb.ID,          // TABLE_ID
+					idxInfo.ID,     // INDEX_ID
+					queryCount,     // QUERY_COUNT
+					rowsSelected,   // ROWS_SELECTED
+					rowsWritten,    // ROWS_WRITTEN
+					lastUsedAt,     // LAST_USED_AT
+				))
+			}
 		}
-		if job.EndTime.IsZero() {
-			endTime = nil
-		} else {
-			endTime = types.NewTime(types.FromGoTime(job.EndTime), mysql.TypeDatetime, 0)
+	}
+	e.rows = rows
+	return nil
+}
+
+// dataForAnalyzeStatusHelper is a helper function which can be used in show_stats.go
+func dataForAnalyzeStatusHelper(ctx context.Context, sctx sessionctx.Context) (rows [][]types.Datum, err error) {
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParamsInternal(ctx, "select id, table_schema, table_name, partition_name, job_info, processed_rows, total_rows, phase, start_time, end_time, state, fail_reason, instance, process_id from mysql.analyze_jobs order by id")
+	if err != nil {
+		return nil, err
+	}
+	chunkRows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	checker := privilege.GetPrivilegeManager(sctx)
+	localInstance := analyzeJobInstance()
+	now := time.Now()
+	for _, chunkRow := range chunkRows {
+		dbName, tableName := chunkRow.GetString(1), chunkRow.GetString(2)
+		if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, dbName, tableName, "", mysql.AllPrivMask) {
+			continue
 		}
-		if checker == nil || checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, job.DBName, job.TableName, "", mysql.AllPrivMask) {
-			rows = append(rows, types.MakeDatums(
-				job.DBName,        // TABLE_SCHEMA
-				job.TableName,     // TABLE_NAME
-				job.PartitionName, // PARTITION_NAME
-				job.JobInfo,       // JOB_INFO
-				job.RowCount,      // ROW_COUNT
-				startTime,         // START_TIME
-				endTime,           // END_TIME
-				job.State,         // STATE
-			))
+		id := chunkRow.GetUint64(0)
+		processedRows, totalRows, phase := int64(chunkRow.GetUint64(5)), int64(chunkRow.GetUint64(6)), chunkRow.GetString(7)
+		state, instance := chunkRow.GetEnum(10).String(), chunkRow.GetString(12)
+		// The progress of the running jobs is dumped periodically, use the latest one if the job runs on this instance.
+		if instance == localInstance && state == statistics.AnalyzeRunning {
+			if job := statistics.GetAnalyzeJob(id); job != nil {
+				job.Lock()
+				processedRows, phase = job.RowCount, job.Phase
+				job.Unlock()
+			}
+		}
+		var startTime, endTime, remainingSeconds, failReason, processID interface{}
+		if !chunkRow.IsNull(8) {
+			startTime = chunkRow.GetTime(8)
+			if state == statistics.AnalyzeRunning && processedRows > 0 && totalRows > processedRows {
+				goStartTime, err := chunkRow.GetTime(8).GoTime(time.Local)
+				if err == nil && now.After(goStartTime) {
+					elapsed := now.Sub(goStartTime).Seconds()
+					remainingSeconds = int64(elapsed * float64(totalRows-processedRows) / float64(processedRows))
+				}
+			}
 		}
-		job.Unlock()
+		if !chunkRow.IsNull(9) {
+			endTime = chunkRow.GetTime(9)
+		}
+		if !chunkRow.IsNull(11) {
+			failReason = chunkRow.GetString(11)
+		}
+		if !chunkRow.IsNull(13) {
+			processID = chunkRow.GetUint64(13)
+		}
+		rows = append(rows, types.MakeDatums(
+			dbName,                // TABLE_SCHEMA
+			tableName,             // TABLE_NAME
+			chunkRow.GetString(3), // PARTITION_NAME
+			chunkRow.GetString(4), // JOB_INFO
+			processedRows,         // PROCESSED_ROWS
+			startTime,             // START_TIME
+			endTime,               // END_TIME
+			state,                 // STATE
+			phase,                 // PHASE
+			totalRows,             // ESTIMATED_TOTAL_ROWS
+			remainingSeconds,      // REMAINING_SECONDS
+			failReason,            // FAIL_REASON
+			instance,              // INSTANCE
+			processID,             // PROCESS_ID
+			id,                    // JOB_ID
+		))
 	}
-	return
+	return rows, nil
 }
 
 // setDataForAnalyzeStatus gets all the analyze jobs.
-func (e *memtableRetriever) setDataForAnalyzeStatus(sctx sessionctx.Context) {
-	e.rows = dataForAnalyzeStatusHelper(sctx)
+func (e *memtableRetriever) setDataForAnalyzeStatus(ctx context.Context, sctx sessionctx.Context) (err error) {
+	e.rows, err = dataForAnalyzeStatusHelper(ctx, sctx)
+	return
 }
 
 // setDataForPseudoProfiling returns pseudo data for table profiling when system variable `profiling` is set to `ON`.
@@ -1918,6 +2266,55 @@ func (e *memtableRetriever) setDataForServersInfo(ctx sessionctx.Context) error
 	return nil
 }
 
+func (e *memtableRetriever) setDataForAutoRandomIDSpace(sctx sessionctx.Context, schemas []*model.DBInfo) error {
+	is := sctx.GetInfoSchema().(infoschema.InfoSchema)
+	checker := privilege.GetPrivilegeManager(sctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, tblInfo := range schema.Tables {
+			col := tblInfo.GetAutoRandomColInfo()
+			if col == nil {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.Name.L, tblInfo.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			tbl, err := is.TableByName(schema.Name, tblInfo.Name)
+			if err != nil {
+				return err
+			}
+			alloc := tbl.Allocators(sctx).Get(autoid.AutoRandomType)
+			if alloc == nil {
+				continue
+			}
+			nextID, err := alloc.NextGlobalAutoID()
+			if err != nil {
+				return err
+			}
+			layout := autoid.NewShardIDLayout(&col.FieldType, tblInfo.AutoRandomBits)
+			maxID := layout.IncrementalBitsCapacity()
+			// The IDs below nextID have been allocated, cached by the TiDB servers or skipped by rebase.
+			var remaining uint64
+			if used := uint64(nextID - 1); used < maxID {
+				remaining = maxID - used
+			}
+			rows = append(rows, types.MakeDatums(
+				schema.Name.O,          // TABLE_SCHEMA
+				tblInfo.Name.O,         // TABLE_NAME
+				col.Name.O,             // COLUMN_NAME
+				tblInfo.ID,             // TABLE_ID
+				layout.ShardBits,       // SHARD_BITS
+				layout.IncrementalBits, // INCREMENTAL_BITS
+				nextID,                 // NEXT_ID
+				maxID,                  // MAX_ID
+				remaining,              // REMAINING_IDS
+			))
+		}
+	}
+	e.rows = rows
+	return nil
+}
+
 func (e *memtableRetriever) setDataFromSequences(ctx sessionctx.Context, schemas []*model.DBInfo) {
 	checker := privilege.GetPrivilegeManager(ctx)
 	var rows [][]types.Datum
@@ -2007,6 +2404,22 @@ func (e *memtableRetriever) setDataForStatementsSummaryEvicted(ctx sessionctx.Co
 	return nil
 }
 
+func (e *memtableRetriever) setDataForTrxSummary(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	e.rows = txninfo.Recorder.DumpTrxSummary()
+	switch e.table.Name.O {
+	case infoschema.ClusterTableTrxSummary:
+		rows, err := infoschema.AppendHostInfoToRows(ctx, e.rows)
+		if err != nil {
+			return err
+		}
+		e.rows = rows
+	}
+	return nil
+}
+
 func (e *memtableRetriever) setDataForClientErrorsSummary(ctx sessionctx.Context, tableName string) error {
 	// Seeing client errors should require the PROCESS privilege, with the exception of errors for your own user.
 	// This is similar to information_schema.processlist, which is the closest comparison.
@@ -2236,6 +2649,27 @@ type dataLockWaitsTableRetriever struct {
 	columns     []*model.ColumnInfo
 	lockWaits   []*deadlock.WaitForEntry
 	initialized bool
+	// txnDigests maps the start ts of the transactions running on the current TiDB instance to the digests of the
+	// SQLs they are executing or last executed.
+	txnDigests map[uint64]string
+}
+
+func (r *dataLockWaitsTableRetriever) collectTxnDigests(sctx sessionctx.Context) map[uint64]string {
+	txnDigests := make(map[uint64]string)
+	sm := sctx.GetSessionManager()
+	if sm == nil {
+		return txnDigests
+	}
+	for _, info := range sm.ShowTxnList() {
+		digest := info.CurrentSQLDigest
+		if len(digest) == 0 && len(info.AllSQLDigests) > 0 {
+			digest = info.AllSQLDigests[len(info.AllSQLDigests)-1]
+		}
+		if len(digest) > 0 {
+			txnDigests[info.StartTS] = digest
+		}
+	}
+	return txnDigests
 }
 
 func (r *dataLockWaitsTableRetriever) retrieve(ctx context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
@@ -2266,18 +2700,25 @@ func (r *dataLockWaitsTableRetriever) retrieve(ctx context.Context, sctx session
 		// Before getting rows, collect the SQL digests that needs to be retrieved first.
 		var needDigest bool
 		var needSQLText bool
+		var needHoldingDigest bool
+		var needHoldingSQLText bool
 		for _, c := range r.columns {
-			if c.Name.O == infoschema.DataLockWaitsColumnSQLDigestText {
+			switch c.Name.O {
+			case infoschema.DataLockWaitsColumnSQLDigestText:
 				needSQLText = true
-			} else if c.Name.O == infoschema.DataLockWaitsColumnSQLDigest {
+			case infoschema.DataLockWaitsColumnSQLDigest:
 				needDigest = true
+			case infoschema.DataLockWaitsColumnCurrentHoldingSQLDigestText:
+				needHoldingSQLText = true
+			case infoschema.DataLockWaitsColumnCurrentHoldingSQLDigest:
+				needHoldingDigest = true
 			}
 		}
 
 		var digests []string
 		if needDigest || needSQLText {
 			digests = make([]string, end-start)
-			for i, lockWait := range r.lockWaits {
+			for i, lockWait := range r.lockWaits[start:end] {
 				digest, err := resourcegrouptag.DecodeResourceGroupTag(lockWait.ResourceGroupTag)
 				if err != nil {
 					// Ignore the error if failed to decode the digest from resource_group_tag. We still want to show
@@ -2289,13 +2730,35 @@ func (r *dataLockWaitsTableRetriever) retrieve(ctx context.Context, sctx session
 			}
 		}
 
+		// The digests of the SQLs executed by the transactions holding the locks can only be found if the transactions
+		// are running on the current TiDB instance.
+		var holdingDigests []string
+		if needHoldingDigest || needHoldingSQLText {
+			if r.txnDigests == nil {
+				r.txnDigests = r.collectTxnDigests(sctx)
+			}
+			holdingDigests = make([]string, end-start)
+			for i, lockWait := range r.lockWaits[start:end] {
+				holdingDigests[i] = r.txnDigests[lockWait.WaitForTxn]
+			}
+		}
+
 		// Fetch the SQL Texts of the digests above if necessary.
 		var sqlRetriever *expression.SQLDigestTextRetriever
-		if needSQLText {
+		if needSQLText || needHoldingSQLText {
 			sqlRetriever = expression.NewSQLDigestTextRetriever()
-			for _, digest := range digests {
-				if len(digest) > 0 {
-					sqlRetriever.SQLDigestsMap[digest] = ""
+			if needSQLText {
+				for _, digest := range digests {
+					if len(digest) > 0 {
+						sqlRetriever.SQLDigestsMap[digest] = ""
+					}
+				}
+			}
+			if needHoldingSQLText {
+				for _, digest := range holdingDigests {
+					if len(digest) > 0 {
+						sqlRetriever.SQLDigestsMap[digest] = ""
+					}
 				}
 			}
 			err := sqlRetriever.RetrieveGlobal(ctx, sctx)
@@ -2346,6 +2809,22 @@ func (r *dataLockWaitsTableRetriever) retrieve(ctx context.Context, sctx session
 					} else {
 						row = append(row, types.NewDatum(nil))
 					}
+				case infoschema.DataLockWaitsColumnWaitTime:
+					row = append(row, types.NewDatum(lockWait.WaitTime))
+				case infoschema.DataLockWaitsColumnCurrentHoldingSQLDigest:
+					digest := holdingDigests[rowIdx]
+					if len(digest) == 0 {
+						row = append(row, types.NewDatum(nil))
+					} else {
+						row = append(row, types.NewDatum(digest))
+					}
+				case infoschema.DataLockWaitsColumnCurrentHoldingSQLDigestText:
+					text := sqlRetriever.SQLDigestsMap[holdingDigests[rowIdx]]
+					if len(text) > 0 {
+						row = append(row, types.NewDatum(text))
+					} else {
+						row = append(row, types.NewDatum(nil))
+					}
 				default:
 					row = append(row, types.NewDatum(nil))
 				}
@@ -2517,6 +2996,7 @@ type hugeMemTableRetriever struct {
 	dummyCloser
 	table       *model.TableInfo
 	columns     []*model.ColumnInfo
+	extractor   *plannercore.InfoSchemaTablesExtractor
 	retrieved   bool
 	initialized bool
 	rows        [][]types.Datum
@@ -2535,7 +3015,7 @@ func (e *hugeMemTableRetriever) retrieve(ctx context.Context, sctx sessionctx.Co
 		is := sctx.GetInfoSchema().(infoschema.InfoSchema)
 		dbs := is.AllSchemas()
 		sort.Sort(infoschema.SchemasSorter(dbs))
-		e.dbs = dbs
+		e.dbs = filterSchemasByExtractor(is, e.extractor, dbs)
 		e.initialized = true
 		e.rows = make([][]types.Datum, 0, 1024)
 	}
@@ -21,6 +21,7 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -39,7 +40,6 @@ import (
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx/variable"
-	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/store/helper"
 	"github.com/pingcap/tidb/store/mockstore"
@@ -543,7 +543,6 @@ func (s *testInfoschemaTableSerialSuite) TestPartitionsTable(c *C) {
 
 func (s *testInfoschemaTableSuite) TestMetricTables(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
-	statistics.ClearHistoryJobs()
 	tk.MustExec("use information_schema")
 	tk.MustQuery("select count(*) > 0 from `METRICS_TABLES`").Check(testkit.Rows("1"))
 	tk.MustQuery("select * from `METRICS_TABLES` where table_name='tidb_qps'").
@@ -562,7 +561,7 @@ func (s *testInfoschemaTableSuite) TestTableSessionVar(c *C) {
 
 func (s *testInfoschemaTableSuite) TestForAnalyzeStatus(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
-	statistics.ClearHistoryJobs()
+	tk.MustExec("delete from mysql.analyze_jobs")
 	tk.MustExec("use test")
 	tk.MustExec("drop table if exists analyze_test")
 	tk.MustExec("create table analyze_test (a int, b int, index idx(a))")
@@ -596,8 +595,8 @@ func (s *testInfoschemaTableSuite) TestForAnalyzeStatus(c *C) {
 	resultT1 := tk.MustQuery("select * from information_schema.analyze_status where TABLE_NAME='t1'").Sort()
 	c.Assert(len(resultT1.Rows()), Greater, 0)
 	for _, row := range resultT1.Rows() {
-		c.Assert(len(row), Equals, 8) // test length of row
-		c.Assert(row[6], NotNil)      // test `End_time` field
+		c.Assert(len(row), Equals, 15) // test length of row
+		c.Assert(row[6], NotNil)       // test `End_time` field
 	}
 }
 
@@ -628,7 +627,6 @@ func (s *testInfoschemaTableSerialSuite) TestForTableTiFlashReplica(c *C) {
 	}()
 
 	tk := testkit.NewTestKit(c, s.store)
-	statistics.ClearHistoryJobs()
 	tk.MustExec("use test")
 	tk.MustExec("drop table if exists t")
 	tk.MustExec("create table t (a int, b int, index idx(a))")
@@ -744,6 +742,29 @@ func (s *testInfoschemaClusterTableSuite) setUpMockPDHTTPServer() (*httptest.Ser
 	router.Handle(pdapi.Config, fn.Wrap(mockConfig))
 	// TiDB/TiKV config.
 	router.Handle("/config", fn.Wrap(mockConfig))
+	// The encryption at rest of PD and TiKV, the master key ID changes after each rotation.
+	var rotations int32
+	router.Handle(pdapi.RotateMasterKey, fn.Wrap(func() (interface{}, error) {
+		return nil, nil
+	}))
+	router.Handle("/encryption/master-key/rotate", fn.Wrap(func() (interface{}, error) {
+		atomic.AddInt32(&rotations, 1)
+		return nil, nil
+	}))
+	router.Handle("/encryption/status", fn.Wrap(func() (*helper.EncryptionStatus, error) {
+		status := &helper.EncryptionStatus{
+			Method:        "aes256-ctr",
+			MasterKeyType: "kms",
+			MasterKeyID:   "key-0",
+			RotationState: "none",
+		}
+		if n := atomic.LoadInt32(&rotations); n > 0 {
+			status.MasterKeyID = fmt.Sprintf("key-%d", n)
+			status.RotationState = "done"
+			status.LastRotationTimestamp = s.startTime.Unix()
+		}
+		return status, nil
+	}))
 	// PD region.
 	router.Handle("/pd/api/v1/stats/region", fn.Wrap(func() (*helper.PDRegionStats, error) {
 		return &helper.PDRegionStats{
@@ -882,6 +903,28 @@ func (s *testInfoschemaClusterTableSuite) TestTiDBClusterInfo(c *C) {
 	))
 }
 
+func (s *testInfoschemaClusterTableSuite) TestTiKVEncryptionStatus(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	err := tk.QueryToErr("select * from information_schema.TIKV_ENCRYPTION_STATUS")
+	c.Assert(err.Error(), Equals, "pd unavailable")
+	_, err = tk.Exec("alter instance rotate master key")
+	c.Assert(err.Error(), Equals, "pd unavailable")
+
+	store := &mockStore{
+		s.store.(helper.Storage),
+		s.mockAddr,
+	}
+	tk = testkit.NewTestKit(c, store)
+	tk.MustQuery("select * from information_schema.TIKV_ENCRYPTION_STATUS").Check(testkit.Rows(
+		"1 127.0.0.1:20160 aes256-ctr kms key-0 none <nil> <nil>",
+	))
+	tk.MustExec("alter instance rotate master key")
+	lastRotationTime := time.Unix(s.startTime.Unix(), 0).Format("2006-01-02 15:04:05")
+	tk.MustQuery("select store_id, master_key_id, rotation_state, last_rotation_time from information_schema.TIKV_ENCRYPTION_STATUS").Check(testkit.Rows(
+		"1 key-1 done " + lastRotationTime,
+	))
+}
+
 func (s *testInfoschemaClusterTableSuite) TestTableStorageStats(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	err := tk.QueryToErr("select * from information_schema.TABLE_STORAGE_STATS where TABLE_SCHEMA = 'test'")
@@ -966,6 +1009,27 @@ func (s *testInfoschemaTableSuite) TestSequences(c *C) {
 	tk.MustQuery("SELECT TABLE_CATALOG, TABLE_SCHEMA, TABLE_NAME , TABLE_TYPE, ENGINE, TABLE_ROWS FROM information_schema.tables WHERE TABLE_TYPE='SEQUENCE' AND TABLE_NAME='seq2'").Check(testkit.Rows("def test seq2 SEQUENCE InnoDB 1"))
 }
 
+func (s *testInfoschemaTableSuite) TestAutoRandomIDSpace(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create database auto_random_id_space")
+	defer tk.MustExec("drop database auto_random_id_space")
+	tk.MustExec("use auto_random_id_space")
+	tk.MustExec("create table t1 (a bigint auto_random(5) primary key, b int) auto_random_base = 1000")
+	tk.MustExec("create table t2 (a bigint unsigned auto_random(3), b varchar(10), primary key (a, b) clustered)")
+	tk.MustExec("create table t3 (a bigint primary key, b int)")
+	tk.MustQuery("select table_schema, table_name, column_name, shard_bits, incremental_bits, next_id, max_id, remaining_ids " +
+		"from information_schema.tidb_auto_random_id_space where table_schema = 'auto_random_id_space' order by table_name").Check(testkit.Rows(
+		"auto_random_id_space t1 a 5 58 1000 288230376151711743 288230376151710744",
+		"auto_random_id_space t2 a 3 61 1 2305843009213693951 2305843009213693951",
+	))
+	tk.MustQuery("select count(*) from information_schema.tidb_auto_random_id_space s join information_schema.tables t " +
+		"on s.table_schema = t.table_schema and s.table_name = t.table_name and s.table_id = t.tidb_table_id " +
+		"where s.table_schema = 'auto_random_id_space'").Check(testkit.Rows("2"))
+	tk.MustExec("alter table t1 modify column a bigint auto_random(10)")
+	tk.MustQuery("select shard_bits, incremental_bits, max_id from information_schema.tidb_auto_random_id_space where table_name = 't1'").Check(testkit.Rows(
+		"10 53 9007199254740991"))
+}
+
 func (s *testInfoschemaTableSuite) TestTiFlashSystemTables(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	err := tk.QueryToErr("select * from information_schema.TIFLASH_TABLES;")
@@ -986,3 +1050,70 @@ func (s *testInfoschemaTableSuite) TestTablesPKType(c *C) {
 	tk.MustQuery("SELECT TIDB_PK_TYPE FROM information_schema.tables where table_schema = 'test' and table_name = 't_common'").Check(testkit.Rows("CLUSTERED"))
 	tk.MustQuery("SELECT TIDB_PK_TYPE FROM information_schema.tables where table_schema = 'INFORMATION_SCHEMA' and table_name = 'TABLES'").Check(testkit.Rows("NONCLUSTERED"))
 }
+
+func (s *testInfoschemaTableSuite) TestMetaTablesCacheAndPushDown(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create database meta_cache")
+	defer tk.MustExec("drop database meta_cache")
+	tk.MustExec("use meta_cache")
+	tk.MustExec("create table t1 (a int primary key, b int, key idx_b(b))")
+	tk.MustExec("create table t2 (id int auto_increment primary key)")
+
+	rows := tk.MustQuery("explain format = 'brief' select * from information_schema.columns where table_schema = 'meta_cache' and table_name = 't1'").Rows()
+	c.Assert(rows[len(rows)-1][4], Equals, `schema:["meta_cache"], table:["t1"]`)
+	tk.MustQuery("select column_name, privileges from information_schema.columns where table_schema = 'meta_cache' and table_name = 't1'").Check(testkit.Rows(
+		"a select,insert,update,references", "b select,insert,update,references"))
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_schema = 'meta_cache' and table_name = 't1'").Check(testkit.Rows(
+		"PRIMARY a", "idx_b b"))
+	tk.MustQuery("select table_name, auto_increment from information_schema.tables where table_schema = 'meta_cache'").Check(testkit.Rows(
+		"t1 <nil>", "t2 1"))
+	tk.MustQuery("select count(*) from information_schema.tables where table_schema = 'meta_cache' and table_schema = 'test'").Check(testkit.Rows("0"))
+
+	// The cached rows are rebuilt after the tables are changed.
+	tk.MustExec("alter table t1 add column c int")
+	tk.MustExec("alter table t1 add index idx_c(c)")
+	tk.MustExec("insert into t2 values ()")
+	tk.MustQuery("select column_name from information_schema.columns where table_schema = 'meta_cache' and table_name = 't1'").Check(testkit.Rows("a", "b", "c"))
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_schema = 'meta_cache' and table_name = 't1'").Check(testkit.Rows(
+		"PRIMARY a", "idx_b b", "idx_c c"))
+	tk.MustQuery("select table_name, auto_increment > 1 from information_schema.tables where table_schema = 'meta_cache' and table_name = 't2'").Check(testkit.Rows("t2 1"))
+	tk.MustExec("rename table t2 to t3")
+	tk.MustQuery("select table_name from information_schema.tables where table_schema = 'meta_cache'").Check(testkit.Rows("t1", "t3"))
+	tk.MustExec("drop table t3")
+	tk.MustQuery("select table_name from information_schema.tables where table_schema = 'meta_cache'").Check(testkit.Rows("t1"))
+
+	// The privileges are filled by the session.
+	tk.MustExec("create user meta_cache_tester")
+	tk.MustExec("grant select on meta_cache.t1 to meta_cache_tester")
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{
+		Username: "meta_cache_tester",
+		Hostname: "127.0.0.1",
+	}, nil, nil), IsTrue)
+	tk1.MustQuery("select column_name, privileges from information_schema.columns where table_schema = 'meta_cache'").Check(testkit.Rows(
+		"a select", "b select", "c select"))
+}
+
+func (s *testInfoschemaTableSuite) TestMemoryUsage(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustQuery("select category from information_schema.memory_usage").Check(testkit.Rows(
+		"sessions", "internal", "plan_cache", "stats_cache", "untracked",
+		"runtime.heap_inuse", "runtime.heap_idle", "runtime.stack_inuse", "runtime.sys", "runtime.next_gc"))
+	tk.MustQuery("select count(*) from information_schema.memory_usage where bytes < 0").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) from information_schema.memory_usage where category = 'runtime.heap_inuse' and bytes > 0").Check(testkit.Rows("1"))
+	tk.MustQuery("select count(*) from information_schema.memory_usage_ops_history where ops not in ('record', 'kill')").Check(testkit.Rows("0"))
+
+	// The memory usage requires the PROCESS privilege.
+	tk.MustExec("create user memory_usage_tester")
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{
+		Username: "memory_usage_tester",
+		Hostname: "127.0.0.1",
+	}, nil, nil), IsTrue)
+	err := tk1.QueryToErr("select * from information_schema.memory_usage")
+	c.Assert(err.Error(), Equals, "[planner:1227]Access denied; you need (at least one of) the PROCESS privilege(s) for this operation")
+	err = tk1.QueryToErr("select * from information_schema.memory_usage_ops_history")
+	c.Assert(err.Error(), Equals, "[planner:1227]Access denied; you need (at least one of) the PROCESS privilege(s) for this operation")
+	tk.MustExec("grant process on *.* to memory_usage_tester")
+	c.Assert(len(tk1.MustQuery("select * from information_schema.memory_usage").Rows()), Equals, 10)
+}
@@ -202,7 +202,7 @@ func (e *InsertExec) updateDupRow(ctx context.Context, idxInBatch int, txn kv.Tr
 
 	err = e.doDupRowUpdate(ctx, handle, oldRow, row.row, e.OnDuplicate)
 	if e.ctx.GetSessionVars().StmtCtx.DupKeyAsWarning && kv.ErrKeyExists.Equal(err) {
-		e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		appendDMLError(e.ctx, row.row, err)
 		return nil
 	}
 	return err
@@ -247,6 +247,9 @@ func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.D
 
 			err = e.updateDupRow(ctx, i, txn, r, handle, e.OnDuplicate)
 			if err == nil {
+				if e.stats != nil {
+					e.stats.Conflicts++
+				}
 				continue
 			}
 			if !kv.IsErrNotFound(err) {
@@ -279,6 +282,9 @@ func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.D
 				}
 				return err
 			}
+			if e.stats != nil {
+				e.stats.Conflicts++
+			}
 
 			newRows[i] = nil
 			break
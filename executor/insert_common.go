@@ -1022,6 +1022,16 @@ func (e *InsertValues) handleWarning(err error) {
 	sc.AppendWarning(err)
 }
 
+// appendDMLError appends the reason why the row is rejected as a warning, the row is also
+// collected with the reason if tidb_collect_dml_errors is enabled.
+func appendDMLError(sctx sessionctx.Context, row []types.Datum, err error) {
+	sc := sctx.GetSessionVars().StmtCtx
+	sc.AppendWarning(err)
+	if sc.CollectDMLErrors {
+		sc.AppendDMLError(types.DatumsToStrNoErr(row), err)
+	}
+}
+
 func (e *InsertValues) collectRuntimeStatsEnabled() bool {
 	if e.runtimeStats != nil {
 		if e.stats == nil {
@@ -1087,7 +1097,7 @@ func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.D
 		if r.handleKey != nil {
 			_, err := txn.Get(ctx, r.handleKey.newKey)
 			if err == nil {
-				e.ctx.GetSessionVars().StmtCtx.AppendWarning(r.handleKey.dupErr)
+				appendDMLError(e.ctx, rows[i], r.handleKey.dupErr)
 				continue
 			}
 			if !kv.IsErrNotFound(err) {
@@ -1098,7 +1108,7 @@ func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.D
 			_, err := txn.Get(ctx, uk.newKey)
 			if err == nil {
 				// If duplicate keys were found in BatchGet, mark row = nil.
-				e.ctx.GetSessionVars().StmtCtx.AppendWarning(uk.dupErr)
+				appendDMLError(e.ctx, rows[i], uk.dupErr)
 				skip = true
 				break
 			}
@@ -1156,6 +1166,9 @@ type InsertRuntimeStat struct {
 	*autoid.AllocatorRuntimeStats
 	CheckInsertTime time.Duration
 	Prefetch        time.Duration
+	// Conflicts is the number of rows that conflict with the existing rows and are updated
+	// by the ON DUPLICATE KEY UPDATE clause.
+	Conflicts int64
 }
 
 func (e *InsertRuntimeStat) String() string {
@@ -1198,6 +1211,9 @@ func (e *InsertRuntimeStat) String() string {
 			execdetails.FormatDuration(e.CheckInsertTime),
 			execdetails.FormatDuration(e.CheckInsertTime-e.Prefetch),
 			execdetails.FormatDuration(e.Prefetch)))
+		if e.Conflicts > 0 {
+			buf.WriteString(fmt.Sprintf(", conflicts: %d", e.Conflicts))
+		}
 		if e.SnapshotRuntimeStats != nil {
 			if rpc := e.SnapshotRuntimeStats.String(); len(rpc) > 0 {
 				buf.WriteString(fmt.Sprintf(", rpc:{%s}", rpc))
@@ -1215,6 +1231,7 @@ func (e *InsertRuntimeStat) Clone() execdetails.RuntimeStats {
 	newRs := &InsertRuntimeStat{
 		CheckInsertTime: e.CheckInsertTime,
 		Prefetch:        e.Prefetch,
+		Conflicts:       e.Conflicts,
 	}
 	if e.SnapshotRuntimeStats != nil {
 		snapshotStats := e.SnapshotRuntimeStats.Clone()
@@ -1261,6 +1278,7 @@ func (e *InsertRuntimeStat) Merge(other execdetails.RuntimeStats) {
 	}
 	e.Prefetch += tmp.Prefetch
 	e.CheckInsertTime += tmp.CheckInsertTime
+	e.Conflicts += tmp.Conflicts
 }
 
 // Tp implements the RuntimeStats interface.
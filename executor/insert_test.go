@@ -1464,6 +1464,12 @@ func (s *testSuite10) TestInsertRuntimeStat(c *C) {
 	c.Assert(stats.String(), Equals, stats.Clone().String())
 	stats.Merge(stats.Clone())
 	c.Assert(stats.String(), Equals, "prepare: 6s, check_insert: {total_time: 4s, mem_insert_time: 2s, prefetch: 2s}")
+
+	stats.Conflicts = 3
+	c.Assert(stats.String(), Equals, "prepare: 6s, check_insert: {total_time: 4s, mem_insert_time: 2s, prefetch: 2s, conflicts: 3}")
+	c.Assert(stats.String(), Equals, stats.Clone().String())
+	stats.Merge(stats.Clone())
+	c.Assert(stats.String(), Equals, "prepare: 12s, check_insert: {total_time: 8s, mem_insert_time: 4s, prefetch: 4s, conflicts: 6}")
 }
 
 func (s *testSerialSuite) TestDuplicateEntryMessage(c *C) {
@@ -1835,3 +1841,62 @@ func (s *testSuite13) TestReplaceAllocatingAutoID(c *C) {
 	// Note that this error is different from MySQL's duplicated primary key error.
 	tk.MustGetErrCode("REPLACE INTO t1 VALUES (0,'newmaxvalue');", errno.ErrAutoincReadFailed)
 }
+
+func (s *testSuite3) TestCollectDMLErrors(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, pt, src")
+	tk.MustExec("create table t (a int primary key, b varchar(10), unique key (b))")
+	tk.MustExec("insert into t values (1, 'a'), (2, 'b')")
+
+	// The rejected rows aren't collected by default.
+	tk.MustExec("insert ignore into t values (1, 'x')")
+	tk.MustQuery("show last dml errors").Check(testkit.Rows())
+
+	tk.MustExec("set @@tidb_collect_dml_errors = 1")
+	tk.MustExec("insert ignore into t values (1, 'x'), (3, 'c'), (4, 'b')")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 a", "2 b", "3 c"))
+	tk.MustQuery("show last dml errors").Check(testkit.Rows(
+		"1062 Duplicate entry '1' for key 'PRIMARY' (1, \"x\")",
+		"1062 Duplicate entry 'b' for key 'b' (4, \"b\")"))
+	tk.MustQuery("show last dml errors where `Row` like '%x%'").Check(testkit.Rows(
+		"1062 Duplicate entry '1' for key 'PRIMARY' (1, \"x\")"))
+	tk.MustExec("update ignore t set b = 'a' where a = 3")
+	tk.MustQuery("show last dml errors").Check(testkit.Rows("1062 Duplicate entry 'a' for key 'b' (3, \"a\")"))
+
+	tk.MustExec("create table pt (a int) partition by range (a) (partition p0 values less than (10))")
+	tk.MustExec("insert ignore into pt values (1), (20)")
+	tk.MustQuery("show last dml errors").Check(testkit.Rows("1526 Table has no partition for value 20 20"))
+
+	// All the rejected rows are collected.
+	tk.MustExec("create table src (a int)")
+	values := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("(%d)", i+100))
+	}
+	tk.MustExec("insert into src values " + strings.Join(values, ","))
+	tk.MustExec("insert into t select a, a from src")
+	tk.MustExec("insert ignore into t select a, concat('x', a) from src")
+	c.Assert(tk.MustQuery("show last dml errors").Rows(), HasLen, 100)
+
+	// The rows rejected by the last DML statement are kept until the next DML statement.
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("103"))
+	c.Assert(tk.MustQuery("show last dml errors").Rows(), HasLen, 100)
+	tk.MustExec("insert into t values (10, 'z')")
+	tk.MustQuery("show last dml errors").Check(testkit.Rows())
+
+	// The collected rows are limited by tidb_collect_dml_errors_max_size.
+	tk.MustExec("set @@tidb_collect_dml_errors_max_size = 1024")
+	tk.MustExec("insert ignore into t select a, concat('y', a) from src")
+	warnings := tk.MustQuery("show warnings").Rows()
+	rows := tk.MustQuery("show last dml errors").Rows()
+	c.Assert(len(rows), Greater, 0)
+	c.Assert(len(rows), Less, 100)
+	size := 0
+	for _, row := range rows {
+		size += len(row[1].(string)) + len(row[2].(string))
+	}
+	c.Assert(size, LessEqual, 1024)
+	c.Assert(warnings, HasLen, 101)
+	c.Assert(warnings[len(rows)+1][2], Equals, "the rejected rows exceed 1024 bytes, the rest of them are not collected")
+}
@@ -0,0 +1,276 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/statistics/handle"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+type inspectionThresholdKey struct {
+	rule string
+	item string
+}
+
+// inspectionThresholds are the thresholds configured in mysql.inspection_thresholds, which override
+// the default thresholds of the inspection items.
+type inspectionThresholds map[inspectionThresholdKey]float64
+
+func loadInspectionThresholds(ctx context.Context, sctx sessionctx.Context) inspectionThresholds {
+	var rows []chunk.Row
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParamsInternal(ctx, "select rule, item, threshold from mysql.inspection_thresholds")
+	if err == nil {
+		rows, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+	}
+	if err != nil {
+		sctx.GetSessionVars().StmtCtx.AppendWarning(fmt.Errorf("load inspection thresholds failed: %v", err))
+		return nil
+	}
+	thresholds := make(inspectionThresholds, len(rows))
+	for _, row := range rows {
+		if row.IsNull(2) {
+			continue
+		}
+		thresholds[inspectionThresholdKey{rule: row.GetString(0), item: row.GetString(1)}] = row.GetFloat64(2)
+	}
+	return thresholds
+}
+
+// inspectionSuggestions are the default suggestions of the rules shown in `inspection_report`.
+var inspectionSuggestions = map[string]string{
+	"config":               "make the configuration consistent among the instances of the same component",
+	"version":              "upgrade the instances of the same component to the same version",
+	"node-load":            "scale out the cluster or move the workload away from the node",
+	"critical-error":       "check the logs of the instance around the time to find the cause",
+	"threshold-check":      "check the load of the component, scale it out or tune the related configuration",
+	"stats-staleness":      "analyze the table, and check whether the auto analyze is enabled and works",
+	"hot-region-imbalance": "find the hot tables in information_schema.tidb_hot_regions, split or scatter their regions",
+	"plan-cache-thrash":    "increase the capacity of the prepared plan cache, and check the warnings of the EXECUTE statements for the plans which can't be cached",
+	"lock-contention":      "find the blocking transactions in information_schema.data_lock_waits and information_schema.deadlocks, and avoid updating the same rows concurrently",
+}
+
+type (
+	// statsStalenessInspection is used to check whether the statistics of the tables are too stale,
+	// which may lead to bad execution plans.
+	statsStalenessInspection struct{ inspectionName }
+
+	// hotRegionInspection is used to check whether the hot regions are concentrated on some TiKV stores.
+	hotRegionInspection struct{ inspectionName }
+
+	// planCacheInspection is used to check whether the plans of the prepared statements are hardly
+	// hit in the plan cache.
+	planCacheInspection struct{ inspectionName }
+
+	// lockContentionInspection is used to check whether the transactions are blocked by the locks of
+	// others too often.
+	lockContentionInspection struct{ inspectionName }
+)
+
+func (c statsStalenessInspection) inspect(ctx context.Context, sctx sessionctx.Context, filter inspectionFilter) []inspectionResult {
+	const item = "stats-healthy"
+	if !filter.enable(item) {
+		return nil
+	}
+	// The healthy is the same as `SHOW STATS_HEALTHY`, which is the percentage of the unmodified rows.
+	threshold := filter.threshold(c.name(), item, 60)
+	sql := fmt.Sprintf(`select t.table_schema, t.table_name, m.modify_count, m.count
+		from mysql.stats_meta m join information_schema.tables t on m.table_id = t.tidb_table_id
+		where t.table_schema != 'mysql' and m.count >= %d and m.modify_count > m.count * %f`, handle.AutoAnalyzeMinCnt, 1-threshold/100)
+	var rows []chunk.Row
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParamsInternal(ctx, sql)
+	if err == nil {
+		rows, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+	}
+	if err != nil {
+		sctx.GetSessionVars().StmtCtx.AppendWarning(fmt.Errorf("execute '%s' failed: %v", sql, err))
+		return nil
+	}
+	var results []inspectionResult
+	for _, row := range rows {
+		modifyCount, count := row.GetInt64(2), row.GetInt64(3)
+		healthy := 100 * (1 - math.Min(float64(modifyCount)/float64(count), 1))
+		table := fmt.Sprintf("`%s`.`%s`", row.GetString(0), row.GetString(1))
+		results = append(results, inspectionResult{
+			tp:         "tidb",
+			item:       item,
+			actual:     fmt.Sprintf("%.0f", healthy),
+			expected:   fmt.Sprintf(">= %.0f", threshold),
+			severity:   "warning",
+			detail:     fmt.Sprintf("the stats of %s are stale, %d of %d rows are modified after the last analyze", table, modifyCount, count),
+			suggestion: fmt.Sprintf("ANALYZE TABLE %s", table),
+			degree:     (threshold - healthy) / math.Max(threshold, 1),
+		})
+	}
+	return results
+}
+
+// checkHotRegionBalance checks whether a store has much more hot regions than the average of all the stores.
+type checkHotRegionBalance struct {
+	item string
+	tp   string
+	// threshold is the ratio of the hot regions of a store to the average.
+	threshold float64
+}
+
+func (c checkHotRegionBalance) genSQL(timeRange plannercore.QueryTimeRange) string {
+	condition := timeRange.Condition()
+	return fmt.Sprintf(`select t1.address, t1.value, t2.value from
+		(select address, avg(value) as value from metrics_schema.pd_hotspot_status %[1]s and type='%[2]s' group by address) as t1 join
+		(select avg(value) as value from (select address, avg(value) as value from metrics_schema.pd_hotspot_status %[1]s and type='%[2]s' group by address) as tmp) as t2
+		where t1.value > t2.value * %[3]f and t2.value > 0`, condition, c.tp, c.threshold)
+}
+
+func (c checkHotRegionBalance) genResult(_ string, row chunk.Row) inspectionResult {
+	value, avg := row.GetFloat64(1), row.GetFloat64(2)
+	return inspectionResult{
+		tp:       "tikv",
+		instance: row.GetString(0),
+		item:     c.item,
+		actual:   fmt.Sprintf("%.2f", value),
+		expected: fmt.Sprintf("<= %.2f", avg*c.threshold),
+		severity: "warning",
+		detail:   fmt.Sprintf("%s tikv has %.2f %s on average, more than %.2f times of the average %.2f of all the stores", row.GetString(0), value, c.tp, c.threshold, avg),
+		degree:   value / (avg * c.threshold),
+	}
+}
+
+func (c checkHotRegionBalance) getItem() string {
+	return c.item
+}
+
+func (c hotRegionInspection) inspect(ctx context.Context, sctx sessionctx.Context, filter inspectionFilter) []inspectionResult {
+	rules := []ruleChecker{
+		checkHotRegionBalance{
+			item:      "hot-write-region-balance",
+			tp:        "hot_write_region_as_leader",
+			threshold: filter.threshold(c.name(), "hot-write-region-balance", 1.5),
+		},
+		checkHotRegionBalance{
+			item:      "hot-read-region-balance",
+			tp:        "hot_read_region_as_leader",
+			threshold: filter.threshold(c.name(), "hot-read-region-balance", 1.5),
+		},
+	}
+	return checkRules(ctx, sctx, filter, rules)
+}
+
+func (c planCacheInspection) inspect(ctx context.Context, sctx sessionctx.Context, filter inspectionFilter) []inspectionResult {
+	const item = "plan-cache-hit-ratio"
+	if !filter.enable(item) {
+		return nil
+	}
+	threshold := filter.threshold(c.name(), item, 0.5)
+	// The statements executed by the binary protocol are counted as `StmtExecute`.
+	condition := filter.timeRange.Condition()
+	sql := fmt.Sprintf(`select t1.instance, ifnull(t2.hit, 0) / t1.execute as ratio from
+		(select instance, sum(value) as execute from metrics_schema.tidb_qps %[1]s and type='StmtExecute' group by instance) as t1 left join
+		(select instance, sum(value) as hit from metrics_schema.tidb_query_using_plan_cache_ops %[1]s group by instance) as t2
+		on t1.instance = t2.instance where t1.execute > 0 and ifnull(t2.hit, 0) / t1.execute < %[2]f`, condition, threshold)
+	var rows []chunk.Row
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParamsInternal(ctx, sql)
+	if err == nil {
+		rows, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+	}
+	if err != nil {
+		sctx.GetSessionVars().StmtCtx.AppendWarning(fmt.Errorf("execute '%s' failed: %v", sql, err))
+		return nil
+	}
+	var results []inspectionResult
+	for _, row := range rows {
+		ratio := row.GetFloat64(1)
+		results = append(results, inspectionResult{
+			tp:            "tidb",
+			statusAddress: row.GetString(0),
+			item:          item,
+			actual:        fmt.Sprintf("%.2f", ratio),
+			expected:      fmt.Sprintf(">= %.2f", threshold),
+			severity:      "warning",
+			detail:        fmt.Sprintf("the plan cache hit ratio of the prepared statements of %s tidb is too low, the plans are evicted frequently or can't be cached", row.GetString(0)),
+			degree:        math.Abs(threshold-ratio) / math.Max(threshold, ratio),
+		})
+	}
+	return results
+}
+
+func (c lockContentionInspection) inspect(ctx context.Context, sctx sessionctx.Context, filter inspectionFilter) []inspectionResult {
+	var rules = []struct {
+		tp        string
+		item      string
+		sql       string
+		threshold float64
+		detail    string
+	}{
+		{
+			tp:   "tidb",
+			item: "txn-lock-backoff",
+			sql: `select instance, max(value) as max_value from
+				(select instance, sum(value) as value from metrics_schema.tidb_kv_backoff_ops %s and type in ('txnLock','txnLockFast') group by instance, time) as tmp
+				group by instance having max_value > %f`,
+			threshold: 10,
+			detail:    "the transactions of %s tidb backoff on the locks of others too often",
+		},
+		{
+			tp:        "tikv",
+			item:      "lock-wait-duration",
+			sql:       `select instance, max(value) as max_value from metrics_schema.tikv_lock_manager_waiter_lifetime_duration %s and quantile=0.99 group by instance having max_value > %f`,
+			threshold: 1,
+			detail:    "the pessimistic locks wait too long on %s tikv",
+		},
+	}
+
+	condition := filter.timeRange.Condition()
+	var results []inspectionResult
+	var rows []chunk.Row
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	for _, rule := range rules {
+		if !filter.enable(rule.item) {
+			continue
+		}
+		threshold := filter.threshold(c.name(), rule.item, rule.threshold)
+		sql := fmt.Sprintf(rule.sql, condition, threshold)
+		stmt, err := exec.ParseWithParamsInternal(ctx, sql)
+		if err == nil {
+			rows, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+		}
+		if err != nil {
+			sctx.GetSessionVars().StmtCtx.AppendWarning(fmt.Errorf("execute '%s' failed: %v", sql, err))
+			continue
+		}
+		for _, row := range rows {
+			value := row.GetFloat64(1)
+			results = append(results, inspectionResult{
+				tp:            rule.tp,
+				statusAddress: row.GetString(0),
+				item:          rule.item,
+				actual:        fmt.Sprintf("%.3f", value),
+				expected:      fmt.Sprintf("<= %.3f", threshold),
+				severity:      "warning",
+				detail:        fmt.Sprintf(rule.detail, row.GetString(0)),
+				degree:        math.Abs(value-threshold) / math.Max(value, threshold),
+			})
+		}
+	}
+	return results
+}
@@ -48,6 +48,8 @@ type (
 		expected string
 		severity string
 		detail   string
+		// suggestion is the action to solve the problem, the default suggestion of the rule is used if it's empty.
+		suggestion string
 		// degree only used for sort.
 		degree float64
 	}
@@ -55,8 +57,9 @@ type (
 	inspectionName string
 
 	inspectionFilter struct {
-		set       set.StringSet
-		timeRange plannercore.QueryTimeRange
+		set        set.StringSet
+		timeRange  plannercore.QueryTimeRange
+		thresholds inspectionThresholds
 	}
 
 	inspectionRule interface {
@@ -73,6 +76,14 @@ func (f inspectionFilter) enable(name string) bool {
 	return len(f.set) == 0 || f.set.Exist(name)
 }
 
+// threshold returns the threshold of the item configured in mysql.inspection_thresholds, or the default one.
+func (f inspectionFilter) threshold(rule, item string, def float64) float64 {
+	if v, ok := f.thresholds[inspectionThresholdKey{rule: rule, item: item}]; ok {
+		return v
+	}
+	return def
+}
+
 type (
 	// configInspection is used to check whether a same configuration item has a
 	// different value between different instance in the cluster
@@ -100,6 +111,10 @@ var inspectionRules = []inspectionRule{
 	&nodeLoadInspection{inspectionName: "node-load"},
 	&criticalErrorInspection{inspectionName: "critical-error"},
 	&thresholdCheckInspection{inspectionName: "threshold-check"},
+	&statsStalenessInspection{inspectionName: "stats-staleness"},
+	&hotRegionInspection{inspectionName: "hot-region-imbalance"},
+	&planCacheInspection{inspectionName: "plan-cache-thrash"},
+	&lockContentionInspection{inspectionName: "lock-contention"},
 }
 
 type inspectionResultRetriever struct {
@@ -109,6 +124,8 @@ type inspectionResultRetriever struct {
 	timeRange               plannercore.QueryTimeRange
 	instanceToStatusAddress map[string]string
 	statusToInstanceAddress map[string]string
+	// withSuggestion is true for `inspection_report`, which has a SUGGESTION column.
+	withSuggestion bool
 }
 
 func (e *inspectionResultRetriever) retrieve(ctx context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
@@ -157,7 +174,11 @@ func (e *inspectionResultRetriever) retrieve(ctx context.Context, sctx sessionct
 	}
 
 	rules := inspectionFilter{set: e.extractor.Rules}
-	items := inspectionFilter{set: e.extractor.Items, timeRange: e.timeRange}
+	items := inspectionFilter{
+		set:        e.extractor.Items,
+		timeRange:  e.timeRange,
+		thresholds: loadInspectionThresholds(ctx, sctx),
+	}
 	var finalRows [][]types.Datum
 	for _, r := range inspectionRules {
 		name := r.name()
@@ -191,7 +212,7 @@ func (e *inspectionResultRetriever) retrieve(ctx context.Context, sctx sessionct
 			if len(result.statusAddress) == 0 {
 				result.statusAddress = e.instanceToStatusAddress[result.instance]
 			}
-			finalRows = append(finalRows, types.MakeDatums(
+			row := types.MakeDatums(
 				name,
 				result.item,
 				result.tp,
@@ -201,7 +222,14 @@ func (e *inspectionResultRetriever) retrieve(ctx context.Context, sctx sessionct
 				result.expected,
 				result.severity,
 				result.detail,
-			))
+			)
+			if e.withSuggestion {
+				if len(result.suggestion) == 0 {
+					result.suggestion = inspectionSuggestions[name]
+				}
+				row = append(row, types.NewStringDatum(result.suggestion))
+			}
+			finalRows = append(finalRows, row)
 		}
 	}
 	return finalRows, nil
@@ -850,6 +878,7 @@ func (thresholdCheckInspection) inspectThreshold1(ctx context.Context, sctx sess
 		if !filter.enable(rule.item) {
 			continue
 		}
+		rule.threshold = filter.threshold("threshold-check", rule.item, rule.threshold)
 
 		sql.Reset()
 		if len(rule.configKey) > 0 {
@@ -1023,6 +1052,7 @@ func (thresholdCheckInspection) inspectThreshold2(ctx context.Context, sctx sess
 		if !filter.enable(rule.item) {
 			continue
 		}
+		rule.threshold = filter.threshold("threshold-check", rule.item, rule.threshold)
 		cond := condition
 		if len(rule.condition) > 0 {
 			cond = fmt.Sprintf("%s and %s", cond, rule.condition)
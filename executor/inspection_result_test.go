@@ -703,3 +703,85 @@ func (s *inspectionResultSuite) TestConfigCheckOfStorageBlockCacheSize(c *C) {
 		"config storage.block-cache.capacity tikv 192.168.3.33  32212254720 < 24159191040 warning There are 2 TiKV server in 192.168.3.33 node, the total 'storage.block-cache.capacity' of TiKV is more than (0.45 * total node memory)",
 	))
 }
+
+func (s *inspectionResultSuite) TestInspectionReport(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	datetime := func(s string) types.Time {
+		t, err := types.ParseTime(tk.Se.GetSessionVars().StmtCtx, s, mysql.TypeDatetime, types.MaxFsp)
+		c.Assert(err, IsNil)
+		return t
+	}
+
+	// mock the stale stats
+	tk.MustExec("drop table if exists t_stale")
+	tk.MustExec("create table t_stale (a int)")
+	tableID := tk.MustQuery("select tidb_table_id from information_schema.tables where table_schema='test' and table_name='t_stale'").Rows()[0][0]
+	tk.MustExec(fmt.Sprintf("insert into mysql.stats_meta (version, table_id, modify_count, count) values (1, %v, 1500, 2000) on duplicate key update modify_count=1500, count=2000", tableID))
+	defer tk.MustExec("drop table if exists t_stale")
+
+	// construct some mock abnormal data
+	mockData := map[string][][]types.Datum{
+		"pd_hotspot_status": {
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "pd-0", "tikv-0", "1", "hot_write_region_as_leader", 10.0),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "pd-0", "tikv-1", "2", "hot_write_region_as_leader", 2.0),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "pd-0", "tikv-2", "3", "hot_write_region_as_leader", 0.0),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "pd-0", "tikv-0", "1", "hot_read_region_as_leader", 3.0),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "pd-0", "tikv-1", "2", "hot_read_region_as_leader", 3.0),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "pd-0", "tikv-2", "3", "hot_read_region_as_leader", 3.0),
+		},
+		"tidb_qps": {
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "tidb-0s", "StmtExecute", "OK", 100.0),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "tidb-0s", "Query", "OK", 100.0),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "tidb-1s", "StmtExecute", "OK", 100.0),
+		},
+		"tidb_query_using_plan_cache_ops": {
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "tidb-0s", "hit", 90.0),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "tidb-1s", "hit", 10.0),
+		},
+		"tidb_kv_backoff_ops": {
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "tidb-0s", "txnLock", 8.0),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "tidb-0s", "txnLockFast", 5.0),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "tidb-0s", "regionMiss", 100.0),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "tidb-1s", "txnLock", 5.0),
+		},
+		"tikv_lock_manager_waiter_lifetime_duration": {
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "tikv-0s", 0.99, 2.5),
+			types.MakeDatums(datetime("2020-02-14 05:20:00"), "tikv-1s", 0.99, 0.5),
+		},
+	}
+
+	ctx := s.setupForInspection(c, mockData, nil)
+	defer s.tearDownForInspection(c)
+
+	check := func(sql string, rows ...string) {
+		rs, err := tk.Se.Execute(ctx, sql)
+		c.Assert(err, IsNil)
+		result := tk.ResultSetToResultWithCtx(ctx, rs[0], Commentf("SQL: %v", sql))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0), Commentf("unexpected warnings: %+v", tk.Se.GetSessionVars().StmtCtx.GetWarnings()))
+		result.Check(testkit.Rows(rows...))
+	}
+	check(`select /*+ time_range('2020-02-14 04:20:00','2020-02-14 05:23:00') */ rule, item, type, instance, status_address, value, reference, details
+		from information_schema.inspection_result where rule in ('hot-region-imbalance', 'plan-cache-thrash', 'lock-contention') order by rule, item`,
+		"hot-region-imbalance hot-write-region-balance tikv tikv-0 tikv-0s 10.00 <= 6.00 tikv-0 tikv has 10.00 hot_write_region_as_leader on average, more than 1.50 times of the average 4.00 of all the stores",
+		"lock-contention lock-wait-duration tikv tikv-0 tikv-0s 2.500 <= 1.000 the pessimistic locks wait too long on tikv-0s tikv",
+		"lock-contention txn-lock-backoff tidb tidb-0 tidb-0s 13.000 <= 10.000 the transactions of tidb-0s tidb backoff on the locks of others too often",
+		"plan-cache-thrash plan-cache-hit-ratio tidb tidb-1 tidb-1s 0.10 >= 0.50 the plan cache hit ratio of the prepared statements of tidb-1s tidb is too low, the plans are evicted frequently or can't be cached",
+	)
+
+	// The report has the suggestions.
+	check(`select rule, item, value, reference, details, suggestion from information_schema.inspection_report where rule='stats-staleness' and details like '%t_stale%'`,
+		"stats-staleness stats-healthy 25 >= 60 the stats of `test`.`t_stale` are stale, 1500 of 2000 rows are modified after the last analyze ANALYZE TABLE `test`.`t_stale`",
+	)
+	check(`select /*+ time_range('2020-02-14 04:20:00','2020-02-14 05:23:00') */ item, instance, suggestion from information_schema.inspection_report where rule='lock-contention' and item='txn-lock-backoff'`,
+		"txn-lock-backoff tidb-0 find the blocking transactions in information_schema.data_lock_waits and information_schema.deadlocks, and avoid updating the same rows concurrently",
+	)
+
+	// The thresholds can be configured in mysql.inspection_thresholds.
+	tk.MustExec("insert into mysql.inspection_thresholds values ('lock-contention', 'txn-lock-backoff', 4), ('stats-staleness', 'stats-healthy', 20)")
+	defer tk.MustExec("delete from mysql.inspection_thresholds")
+	check(`select /*+ time_range('2020-02-14 04:20:00','2020-02-14 05:23:00') */ item, instance, value, reference from information_schema.inspection_result where rule='lock-contention' and item='txn-lock-backoff'`,
+		"txn-lock-backoff tidb-0 13.000 <= 4.000",
+		"txn-lock-backoff tidb-1 5.000 <= 4.000",
+	)
+	check(`select item from information_schema.inspection_report where rule='stats-staleness' and details like '%t_stale%'`)
+}
@@ -86,6 +86,12 @@ type HashJoinExec struct {
 	outerMatchedStatus []*bitmap.ConcurrentBitmap
 	useOuterToBuild    bool
 
+	// spill is set if the build side can be partitioned into disk when it exceeds the memory quota.
+	spill *hashJoinSpillContext
+	// probeSideSpilled indicates that the probe side is partitioned into disk too,
+	// the spilled partitions are joined after the join workers exit.
+	probeSideSpilled bool
+
 	prepared    bool
 	isOuterJoin bool
 
@@ -146,12 +152,18 @@ func (e *HashJoinExec) Close() error {
 		e.probeChkResourceCh = nil
 		e.joinChkResourceCh = nil
 		terror.Call(e.rowContainer.Close)
+		if e.spill != nil {
+			e.spill.close()
+		}
 	}
 	e.outerMatchedStatus = e.outerMatchedStatus[:0]
 
 	if e.stats != nil && e.rowContainer != nil {
 		e.stats.hashStat = *e.rowContainer.stat
 	}
+	if e.stats != nil && e.spill != nil {
+		e.stats.spillRounds, e.stats.spillBytes = e.spill.rounds, e.spill.bytes
+	}
 	err := e.baseExecutor.Close()
 	return err
 }
@@ -171,6 +183,7 @@ func (e *HashJoinExec) Open(ctx context.Context) error {
 	e.closeCh = make(chan struct{})
 	e.finished.Store(false)
 	e.joinWorkerWaitGroup = sync.WaitGroup{}
+	e.spill, e.probeSideSpilled = nil, false
 
 	if e.probeTypes == nil {
 		e.probeTypes = retTypes(e.probeSideExec)
@@ -237,6 +250,14 @@ func (e *HashJoinExec) fetchProbeSideChunks(ctx context.Context) {
 				return
 			}
 			hasWaitedForBuild = true
+			if e.spill != nil && e.spill.partitions != nil {
+				if err := e.spillProbeSide(ctx, probeSideResult); err != nil {
+					e.joinResultCh <- &hashjoinWorkerResult{
+						err: err,
+					}
+				}
+				return
+			}
 		}
 
 		if probeSideResult.NumRows() == 0 {
@@ -256,7 +277,8 @@ func (e *HashJoinExec) wait4BuildSide() (emptyBuild bool, err error) {
 			return false, err
 		}
 	}
-	if e.rowContainer.Len() == uint64(0) && (e.joinType == plannercore.InnerJoin || e.joinType == plannercore.SemiJoin) {
+	buildSpilled := e.spill != nil && e.spill.partitions != nil
+	if !buildSpilled && e.rowContainer.Len() == uint64(0) && (e.joinType == plannercore.InnerJoin || e.joinType == plannercore.SemiJoin) {
 		return true, nil
 	}
 	return false, nil
@@ -379,24 +401,8 @@ func (e *HashJoinExec) handleUnmatchedRowsFromHashTable(workerID uint) {
 	}
 	numChks := e.rowContainer.NumChunks()
 	for i := int(workerID); i < numChks; i += int(e.concurrency) {
-		chk, err := e.rowContainer.GetChunk(i)
-		if err != nil {
-			// Catching the error and send it
-			joinResult.err = err
-			e.joinResultCh <- joinResult
-			return
-		}
-		for j := 0; j < chk.NumRows(); j++ {
-			if !e.outerMatchedStatus[i].UnsafeIsSet(j) { // process unmatched outer rows
-				e.joiners[workerID].onMissMatch(false, chk.GetRow(j), joinResult.chk)
-			}
-			if joinResult.chk.IsFull() {
-				e.joinResultCh <- joinResult
-				ok, joinResult = e.getNewJoinResult(workerID)
-				if !ok {
-					return
-				}
-			}
+		if ok, joinResult = e.joinUnmatchedBuildSideRows(workerID, e.rowContainer, i, joinResult); !ok {
+			break
 		}
 	}
 
@@ -407,9 +413,37 @@ func (e *HashJoinExec) handleUnmatchedRowsFromHashTable(workerID uint) {
 	}
 }
 
+// joinUnmatchedBuildSideRows handles the unmatched outer rows in the chkIdx-th chunk of the row container.
+func (e *HashJoinExec) joinUnmatchedBuildSideRows(workerID uint, rowContainer *hashRowContainer, chkIdx int, joinResult *hashjoinWorkerResult) (ok bool, _ *hashjoinWorkerResult) {
+	chk, err := rowContainer.GetChunk(chkIdx)
+	if err != nil {
+		// Catching the error and send it
+		joinResult.err = err
+		return false, joinResult
+	}
+	for j := 0; j < chk.NumRows(); j++ {
+		if !e.outerMatchedStatus[chkIdx].UnsafeIsSet(j) { // process unmatched outer rows
+			e.joiners[workerID].onMissMatch(false, chk.GetRow(j), joinResult.chk)
+		}
+		if joinResult.chk.IsFull() {
+			e.joinResultCh <- joinResult
+			ok, joinResult = e.getNewJoinResult(workerID)
+			if !ok {
+				return false, joinResult
+			}
+		}
+	}
+	return true, joinResult
+}
+
 func (e *HashJoinExec) waitJoinWorkersAndCloseResultChan() {
 	e.joinWorkerWaitGroup.Wait()
-	if e.useOuterToBuild {
+	if e.probeSideSpilled {
+		// Both sides are partitioned into disk, join the partitions one by one.
+		e.joinWorkerWaitGroup.Add(1)
+		go util.WithRecovery(e.joinSpilledPartitions, e.handleJoinWorkerPanic)
+		e.joinWorkerWaitGroup.Wait()
+	} else if e.useOuterToBuild {
 		// Concurrently handling unmatched rows from the hash table at the tail
 		for i := uint(0); i < e.concurrency; i++ {
 			var workerID = i
@@ -758,41 +792,33 @@ func (e *HashJoinExec) fetchAndBuildHashTable(ctx context.Context) {
 }
 
 // buildHashTableForList builds hash table from `list`.
-func (e *HashJoinExec) buildHashTableForList(buildSideResultCh <-chan *chunk.Chunk) error {
-	var err error
+func (e *HashJoinExec) buildHashTableForList(buildSideResultCh <-chan *chunk.Chunk) (err error) {
 	var selected []bool
 	e.rowContainer.GetMemTracker().AttachTo(e.memTracker)
 	e.rowContainer.GetMemTracker().SetLabel(memory.LabelForBuildSideResult)
 	e.rowContainer.GetDiskTracker().AttachTo(e.diskTracker)
 	e.rowContainer.GetDiskTracker().SetLabel(memory.LabelForBuildSideResult)
 	if config.GetGlobalConfig().OOMUseTmpStorage {
-		actionSpill := e.rowContainer.ActionSpill()
-		failpoint.Inject("testRowContainerSpill", func(val failpoint.Value) {
-			if val.(bool) {
-				actionSpill = e.rowContainer.rowContainer.ActionSpillForTest()
-				defer actionSpill.(*chunk.SpillDiskAction).WaitForTest()
+		e.spill = newHashJoinSpillContext(e)
+		e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.spill.action)
+		defer func() {
+			if err == nil {
+				err = e.finishBuildSide()
+			} else {
+				e.spill.action.setRowContainer(nil)
 			}
-		})
-		e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(actionSpill)
+		}()
 	}
 	for chk := range buildSideResultCh {
 		if e.finished.Load().(bool) {
 			return nil
 		}
-		if !e.useOuterToBuild {
-			err = e.rowContainer.PutChunk(chk, e.isNullEQ)
+		if e.spill != nil && e.spill.partitions != nil {
+			err = e.spillChunk(e.spill.partitions, chk, true)
 		} else {
-			var bitMap = bitmap.NewConcurrentBitmap(chk.NumRows())
-			e.outerMatchedStatus = append(e.outerMatchedStatus, bitMap)
-			e.memTracker.Consume(bitMap.BytesConsumed())
-			if len(e.outerFilter) == 0 {
-				err = e.rowContainer.PutChunk(chk, e.isNullEQ)
-			} else {
-				selected, err = expression.VectorizedFilter(e.ctx, e.outerFilter, chunk.NewIterator4Chunk(chk), selected)
-				if err != nil {
-					return err
-				}
-				err = e.rowContainer.PutChunkSelected(chk, selected, e.isNullEQ)
+			selected, err = e.putBuildSideChunk(e.rowContainer, chk, selected)
+			if err == nil && e.spill != nil && e.spill.action.spillRequested() {
+				err = e.spillBuildSide()
 			}
 		}
 		if err != nil {
@@ -802,6 +828,24 @@ func (e *HashJoinExec) buildHashTableForList(buildSideResultCh <-chan *chunk.Chu
 	return nil
 }
 
+// putBuildSideChunk puts a build side chunk into the row container and builds the hash table.
+func (e *HashJoinExec) putBuildSideChunk(rowContainer *hashRowContainer, chk *chunk.Chunk, selected []bool) ([]bool, error) {
+	if !e.useOuterToBuild {
+		return selected, rowContainer.PutChunk(chk, e.isNullEQ)
+	}
+	var bitMap = bitmap.NewConcurrentBitmap(chk.NumRows())
+	e.outerMatchedStatus = append(e.outerMatchedStatus, bitMap)
+	e.memTracker.Consume(bitMap.BytesConsumed())
+	if len(e.outerFilter) == 0 {
+		return selected, rowContainer.PutChunk(chk, e.isNullEQ)
+	}
+	selected, err := expression.VectorizedFilter(e.ctx, e.outerFilter, chunk.NewIterator4Chunk(chk), selected)
+	if err != nil {
+		return selected, err
+	}
+	return selected, rowContainer.PutChunkSelected(chk, selected, e.isNullEQ)
+}
+
 // NestedLoopApplyExec is the executor for apply.
 type NestedLoopApplyExec struct {
 	baseExecutor
@@ -1119,6 +1163,8 @@ type hashJoinRuntimeStats struct {
 	probe                  int64
 	concurrent             int
 	maxFetchAndProbe       int64
+	spillRounds            int
+	spillBytes             int64
 }
 
 func (e *hashJoinRuntimeStats) setMaxFetchAndProbeTime(t int64) {
@@ -1166,6 +1212,13 @@ func (e *hashJoinRuntimeStats) String() string {
 		}
 		buf.WriteString("}")
 	}
+	if e.spillRounds > 0 {
+		buf.WriteString(", spill:{round:")
+		buf.WriteString(strconv.Itoa(e.spillRounds))
+		buf.WriteString(", bytes:")
+		buf.WriteString(memory.FormatBytes(e.spillBytes))
+		buf.WriteString("}")
+	}
 	return buf.String()
 }
 
@@ -1177,6 +1230,8 @@ func (e *hashJoinRuntimeStats) Clone() execdetails.RuntimeStats {
 		probe:                  e.probe,
 		concurrent:             e.concurrent,
 		maxFetchAndProbe:       e.maxFetchAndProbe,
+		spillRounds:            e.spillRounds,
+		spillBytes:             e.spillBytes,
 	}
 }
 
@@ -1193,4 +1248,8 @@ func (e *hashJoinRuntimeStats) Merge(rs execdetails.RuntimeStats) {
 	if e.maxFetchAndProbe < tmp.maxFetchAndProbe {
 		e.maxFetchAndProbe = tmp.maxFetchAndProbe
 	}
+	if e.spillRounds < tmp.spillRounds {
+		e.spillRounds = tmp.spillRounds
+	}
+	e.spillBytes += tmp.spillBytes
 }
@@ -19,15 +19,12 @@ import (
 	"time"
 
 	. "github.com/pingcap/check"
-	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 )
 
 func (s *pkgTestSerialSuite) TestJoinExec(c *C) {
-	c.Assert(failpoint.Enable("github.com/pingcap/tidb/executor/testRowContainerSpill", "return(true)"), IsNil)
-	defer func() { c.Assert(failpoint.Disable("github.com/pingcap/tidb/executor/testRowContainerSpill"), IsNil) }()
 	colTypes := []*types.FieldType{
 		types.NewFieldType(mysql.TypeLonglong),
 		types.NewFieldType(mysql.TypeDouble),
@@ -72,7 +69,7 @@ func (s *pkgTestSerialSuite) TestJoinExec(c *C) {
 				}
 				result.Append(chk, 0, chk.NumRows())
 			}
-			c.Assert(exec.rowContainer.alreadySpilledSafeForTest(), Equals, casTest.disk)
+			c.Assert(exec.alreadySpilledSafeForTest(), Equals, casTest.disk)
 			err = exec.Close()
 			c.Assert(err, IsNil)
 		}
@@ -142,4 +139,10 @@ func (s *pkgTestSuite) TestIndexJoinRuntimeStats(c *C) {
 	c.Assert(stats.String(), Equals, stats.Clone().String())
 	stats.Merge(stats.Clone())
 	c.Assert(stats.String(), Equals, "inner:{total:10s, concurrency:5, task:32, construct:200ms, fetch:600ms, build:500ms, join:300ms}, probe:2s")
+
+	stats.innerWorker.lookUpKeys = 100
+	stats.innerWorker.uniqueKeys = 30
+	c.Assert(stats.String(), Equals, "inner:{total:10s, concurrency:5, task:32, keys:100, unique keys:30, construct:200ms, fetch:600ms, build:500ms, join:300ms}, probe:2s")
+	stats.Merge(stats.Clone())
+	c.Assert(stats.String(), Equals, "inner:{total:20s, concurrency:5, task:64, keys:200, unique keys:60, construct:400ms, fetch:1.2s, build:1s, join:600ms}, probe:4s")
 }
@@ -2679,3 +2679,76 @@ func (s *testSuiteJoinSerial) TestIssue30211(c *C) {
 	err = tk.QueryToErr("select /*+ inl_hash_join(t1) */ * from t1 join t2 on t1.a = t2.a;").Error()
 	c.Assert(strings.Contains(err, "Out Of Memory Quota"), IsTrue)
 }
+
+func (s *testSuiteJoinSerial) TestHashJoinSpillPartitions(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+	})
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	values := make([]string, 0, 3000)
+	for i := 0; i < 3000; i++ {
+		if i%100 == 0 {
+			values = append(values, fmt.Sprintf("(null, %d)", i))
+			continue
+		}
+		values = append(values, fmt.Sprintf("(%d, %d)", i%1000, i))
+	}
+	tk.MustExec("insert into t1 values " + strings.Join(values, ","))
+	tk.MustExec("insert into t2 select a + 500, b from t1 where b < 2000")
+
+	sqls := []string{
+		"select /*+ HASH_JOIN(t1, t2) */ * from t1 join t2 on t1.a = t2.a",
+		"select /*+ HASH_JOIN(t1, t2) */ * from t1 left join t2 on t1.a = t2.a and t1.b > t2.b",
+		"select /*+ HASH_JOIN(t1, t2) */ * from t1 right join t2 on t1.a = t2.a where t2.b > 100",
+		"select /*+ HASH_JOIN(t1, t2) */ * from t1 where not exists (select 1 from t2 where t1.a = t2.a and t2.b > 200)",
+		"select /*+ HASH_JOIN(t1, t2) */ * from t1 where exists (select 1 from t2 where t1.a = t2.a)",
+	}
+	check := func() {
+		for _, sql := range sqls {
+			tk.MustExec("set @@tidb_mem_quota_query = default")
+			expected := tk.MustQuery(sql).Sort().Rows()
+			tk.MustExec("set @@tidb_mem_quota_query = 10000")
+			tk.MustQuery(sql).Sort().Check(expected)
+			rows := tk.MustQuery("explain analyze " + sql).Rows()
+			c.Assert(rows[0][5], Matches, ".*spill:\\{round:[1-3], bytes:.*", Commentf("sql: %s", sql))
+		}
+	}
+	check()
+	plannercore.ForceUseOuterBuild4Test = true
+	defer func() { plannercore.ForceUseOuterBuild4Test = false }()
+	check()
+}
+
+func (s *testSuiteJoinSerial) TestHashJoinTmpStorageQuota(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+	})
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	values := make([]string, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into t1 values " + strings.Join(values, ","))
+	tk.MustExec("insert into t2 select * from t1")
+	sql := "select /*+ HASH_JOIN(t1, t2) */ count(*) from t1 join t2 on t1.a = t2.a"
+	tk.MustExec("set @@tidb_mem_quota_query = 10000")
+
+	tk.MustExec("set @@tidb_tmp_storage_quota_query = 1024")
+	err := tk.QueryToErr(sql)
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Matches, ".*Out Of Temporary Storage Quota!.*")
+	tk.MustQuery("select count(*) from information_schema.tidb_spill_files").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@tidb_tmp_storage_quota_query = default")
+	tk.MustQuery(sql).Check(testkit.Rows("2000"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Greater, int64(1024))
+	tk.MustQuery("select count(*) from information_schema.tidb_spill_files").Check(testkit.Rows("0"))
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// JSONTableExec extracts the rows of JSON_TABLE from the JSON document.
+// When the document references the columns of the outer tables, it's the inner side of an Apply
+// and it's opened again for every outer row.
+type JSONTableExec struct {
+	baseExecutor
+
+	expr    expression.Expression
+	rowPath *plannercore.JSONTablePath
+
+	// sc is used to convert the extracted values. Unlike the statement, it never ignores the
+	// conversion errors, which are handled by the ON ERROR clauses.
+	sc     *stmtctx.StatementContext
+	rows   [][]types.Datum
+	cursor int
+}
+
+// Open implements the Executor Open interface.
+func (e *JSONTableExec) Open(ctx context.Context) error {
+	e.rows, e.cursor = e.rows[:0], 0
+	e.sc = &stmtctx.StatementContext{TimeZone: e.ctx.GetSessionVars().Location()}
+	doc, isNull, err := e.expr.EvalJSON(e.ctx, chunk.Row{})
+	if isNull || err != nil {
+		return err
+	}
+	_, err = e.fillPath(e.rowPath, doc, make([]types.Datum, e.schema.Len()))
+	return err
+}
+
+// Next implements the Executor Next interface.
+func (e *JSONTableExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for ; e.cursor < len(e.rows) && !req.IsFull(); e.cursor++ {
+		for i, d := range e.rows[e.cursor] {
+			req.AppendDatum(i, &d)
+		}
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *JSONTableExec) Close() error {
+	e.rows = e.rows[:0]
+	return e.baseExecutor.Close()
+}
+
+// fillPath fills the columns of the path for every value matched in the document. A row is produced
+// when none of the NESTED PATH of the value matches anything, otherwise the rows are produced by the
+// NESTED PATH one after another, and the columns of the sibling NESTED PATH are NULL.
+func (e *JSONTableExec) fillPath(path *plannercore.JSONTablePath, doc json.BinaryJSON, row []types.Datum) (matched bool, err error) {
+	values := doc.ExtractAll(path.Path)
+	for i, value := range values {
+		for _, col := range path.Columns {
+			if row[col.Offset], err = e.evalColumn(col, value, i+1); err != nil {
+				return false, err
+			}
+		}
+		nestedMatched := false
+		for _, nested := range path.Nested {
+			ok, err := e.fillPath(nested, value, row)
+			if err != nil {
+				return false, err
+			}
+			nestedMatched = nestedMatched || ok
+			setJSONTablePathNull(nested, row)
+		}
+		if !nestedMatched {
+			e.rows = append(e.rows, append(make([]types.Datum, 0, len(row)), row...))
+		}
+	}
+	return len(values) > 0, nil
+}
+
+func setJSONTablePathNull(path *plannercore.JSONTablePath, row []types.Datum) {
+	for _, col := range path.Columns {
+		row[col.Offset].SetNull()
+	}
+	for _, nested := range path.Nested {
+		setJSONTablePathNull(nested, row)
+	}
+}
+
+func (e *JSONTableExec) evalColumn(col *plannercore.JSONTableColumn, value json.BinaryJSON, ordinality int) (types.Datum, error) {
+	switch col.Tp {
+	case ast.JSONTableColumnOrdinality:
+		return types.NewUintDatum(uint64(ordinality)), nil
+	case ast.JSONTableColumnExistsPath:
+		exists := int64(0)
+		if _, found := value.Extract([]json.PathExpression{col.Path}); found {
+			exists = 1
+		}
+		d := types.NewIntDatum(exists)
+		return d.ConvertTo(e.sc, col.RetType)
+	}
+	// Multiple matched values are wrapped as an array.
+	v, found := value.Extract([]json.PathExpression{col.Path})
+	if !found {
+		return e.onResponse(col, col.OnEmpty, ErrMissingJSONTableValue.GenWithStackByArgs(col.Name))
+	}
+	d, err := e.convertValue(col, v)
+	if err != nil {
+		return e.onResponse(col, col.OnError, err)
+	}
+	return d, nil
+}
+
+func (e *JSONTableExec) onResponse(col *plannercore.JSONTableColumn, response *plannercore.JSONTableOnResponse, err error) (types.Datum, error) {
+	if response == nil || response.Tp == ast.JSONTableOnResponseNull {
+		return types.Datum{}, nil
+	}
+	if response.Tp == ast.JSONTableOnResponseError {
+		return types.Datum{}, err
+	}
+	return e.convertValue(col, response.Default)
+}
+
+func (e *JSONTableExec) convertValue(col *plannercore.JSONTableColumn, v json.BinaryJSON) (types.Datum, error) {
+	var d types.Datum
+	switch {
+	case col.RetType.Tp == mysql.TypeJSON:
+		return types.NewJSONDatum(v), nil
+	case v.TypeCode == json.TypeCodeObject || v.TypeCode == json.TypeCodeArray:
+		return d, ErrWrongJSONTableValue.GenWithStackByArgs(col.Name)
+	case v.TypeCode == json.TypeCodeLiteral && v.Value[0] == json.LiteralNil:
+		return d, nil
+	case v.TypeCode == json.TypeCodeString:
+		d.SetString(string(v.GetString()), col.RetType.Collate)
+	default:
+		d.SetMysqlJSON(v)
+	}
+	return d.ConvertTo(e.sc, col.RetType)
+}
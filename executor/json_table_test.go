@@ -0,0 +1,82 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/testkit"
+)
+
+func TestJSONTable(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustQuery(`select * from json_table('[{"a": 1, "b": "x"}, {"a": 2}, {"b": "z"}]', '$[*]' columns(
+		id for ordinality, a int path '$.a', b varchar(10) path '$.b', has_a int exists path '$.a')) as jt`).Check(testkit.Rows(
+		"1 1 x 1",
+		"2 2 <nil> 1",
+		"3 <nil> z 0",
+	))
+
+	// ON EMPTY and ON ERROR.
+	tk.MustQuery(`select * from json_table('[{"a": "1"}, {"a": "x"}, {"a": [1, 2]}, {}]', '$[*]' columns(
+		a int path '$.a' default '-1' on empty default '-2' on error,
+		b int path '$.a' null on error,
+		c json path '$.a')) as jt`).Check(testkit.Rows(
+		"1 1 \"1\"",
+		"-2 <nil> \"x\"",
+		"-2 <nil> [1, 2]",
+		"-1 <nil> <nil>",
+	))
+	tk.MustGetErrMsg(`select * from json_table('[{}]', '$[*]' columns(a int path '$.a' error on empty)) as jt`,
+		"[executor:3665]Missing value for JSON_TABLE column 'a'")
+	tk.MustGetErrMsg(`select * from json_table('[{"a": {}}]', '$[*]' columns(a int path '$.a' error on error)) as jt`,
+		"[executor:3666]Can't store an array or an object in the scalar JSON_TABLE column 'a'")
+	tk.MustGetErrMsg(`select * from json_table('[{"a": "x"}]', '$[*]' columns(a int path '$.a' error on error)) as jt`,
+		"[types:1292]Truncated incorrect DOUBLE value: 'x'")
+
+	// NESTED PATH.
+	tk.MustQuery(`select * from json_table('[{"a": 1, "b": [11, 12], "c": [true]}, {"a": 2, "b": []}]', '$[*]' columns(
+		a int path '$.a',
+		nested path '$.b[*]' columns(id for ordinality, b int path '$'),
+		nested path '$.c[*]' columns(c json path '$'))) as jt`).Check(testkit.Rows(
+		"1 1 11 <nil>",
+		"1 2 12 <nil>",
+		"1 <nil> <nil> true",
+		"2 <nil> <nil> <nil>",
+	))
+
+	// The JSON document references the columns of the tables on the left side.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, doc json)")
+	tk.MustExec(`insert into t values (1, '{"tags": ["a", "b"]}'), (2, '{"tags": []}'), (3, null)`)
+	tk.MustQuery(`select t.id, jt.tag from t, json_table(t.doc, '$.tags[*]' columns(tag varchar(10) path '$')) as jt order by t.id, jt.tag`).
+		Check(testkit.Rows("1 a", "1 b"))
+	tk.MustQuery(`select t.id, jt.tag from t left join json_table(t.doc, '$.tags[*]' columns(tag varchar(10) path '$')) as jt on true order by t.id, jt.tag`).
+		Check(testkit.Rows("1 a", "1 b", "2 <nil>", "3 <nil>"))
+	tk.MustQuery(`select t.id, jt.* from t join json_table(t.doc, '$.tags[*]' columns(tag varchar(10) path '$')) as jt where jt.tag = 'b'`).
+		Check(testkit.Rows("1 b"))
+	tk.MustQuery(`select id, (select count(*) from json_table(t.doc, '$.tags[*]' columns(tag text path '$')) as jt) from t order by id`).
+		Check(testkit.Rows("1 2", "2 0", "3 0"))
+	tk.MustGetErrCode(`select * from json_table(t.doc, '$[*]' columns(a int path '$')) as jt right join t on true`, 1054)
+
+	tk.MustGetErrCode(`select * from json_table('{}', 'a' columns(a int path '$')) as jt`, 3143)
+	tk.MustGetErrCode(`select * from json_table('{}', '$' columns(a int path '$', a int path '$')) as jt`, 1060)
+	tk.MustGetErrCode(`select * from json_table('[1', '$' columns(a int path '$')) as jt`, 3140)
+}
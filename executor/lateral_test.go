@@ -0,0 +1,74 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/testkit"
+)
+
+func TestLateralDerivedTable(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("create table t2 (a int, c int)")
+	tk.MustExec("insert into t1 values (1, 10), (2, 20), (3, 30)")
+	tk.MustExec("insert into t2 values (1, 100), (1, 101), (2, 200)")
+
+	tk.MustQuery("select t1.a, dt.m from t1, lateral (select max(c) as m from t2 where t2.a = t1.a) as dt order by t1.a").
+		Check(testkit.Rows("1 101", "2 200", "3 <nil>"))
+	tk.MustQuery("select t1.a, dt.c from t1 left join lateral (select c from t2 where t2.a = t1.a order by c desc limit 1) dt on true order by t1.a").
+		Check(testkit.Rows("1 101", "2 200", "3 <nil>"))
+	tk.MustQuery("select t1.a, dt.c from t1 join lateral (select c from t2 where t2.a = t1.a order by c limit 1) dt order by t1.a").
+		Check(testkit.Rows("1 100", "2 200"))
+	tk.MustQuery("select t1.a, dt.c from t1 left join lateral (select c from t2 where t2.a = t1.a) dt on dt.c > 100 order by t1.a, dt.c").
+		Check(testkit.Rows("1 101", "2 200", "3 <nil>"))
+	tk.MustQuery("select t1.a, dt.x from t1, lateral (select t1.b + 1 as x) dt order by t1.a").Check(testkit.Rows("1 11", "2 21", "3 31"))
+	tk.MustQuery("select t1.a, dt.x from t1, lateral (select 1 as x) dt order by t1.a").Check(testkit.Rows("1 1", "2 1", "3 1"))
+	// The LATERAL derived table without preceding tables can reference the outer query.
+	tk.MustQuery("select (select x from lateral (select t1.a as x) dt) from t1 order by t1.a").Check(testkit.Rows("1", "2", "3"))
+
+	// The LATERAL derived table is evaluated for every row by an Apply, unless it can be decorrelated.
+	tk.MustQuery("explain format = 'brief' select t1.a, dt.c from t1, lateral (select c from t2 where t2.a = t1.a) dt").Check(testkit.Rows(
+		"HashJoin 12487.50 root  inner join, equal:[eq(test.t1.a, test.t2.a)]",
+		"├─TableReader(Build) 9990.00 root  data:Selection",
+		"│ └─Selection 9990.00 cop[tikv]  not(isnull(test.t2.a))",
+		"│   └─TableFullScan 10000.00 cop[tikv] table:t2 keep order:false, stats:pseudo",
+		"└─TableReader(Probe) 9990.00 root  data:Selection",
+		"  └─Selection 9990.00 cop[tikv]  not(isnull(test.t1.a))",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t1 keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("explain format = 'brief' select t1.a, dt.c from t1 left join lateral (select c from t2 where t2.a = t1.a order by c desc limit 1) dt on true").Check(testkit.Rows(
+		"Projection 10000.00 root  test.t1.a, test.t2.c",
+		"└─Apply 10000.00 root  CARTESIAN left outer join",
+		"  ├─TableReader(Build) 10000.00 root  data:TableFullScan",
+		"  │ └─TableFullScan 10000.00 cop[tikv] table:t1 keep order:false, stats:pseudo",
+		"  └─TopN(Probe) 1.00 root  test.t2.c:desc, offset:0, count:1",
+		"    └─TableReader 1.00 root  data:TopN",
+		"      └─TopN 1.00 cop[tikv]  test.t2.c:desc, offset:0, count:1",
+		"        └─Selection 10.00 cop[tikv]  eq(test.t2.a, test.t1.a)",
+		"          └─TableFullScan 10000.00 cop[tikv] table:t2 keep order:false, stats:pseudo",
+	))
+
+	// The outer side of the join and the tables on the right side can't be referenced.
+	tk.MustGetErrCode("select * from t1 right join lateral (select t1.a) dt on true", 1054)
+	tk.MustGetErrCode("select * from lateral (select t1.a) dt, t1", 1054)
+	tk.MustGetErrCode("select * from t1, lateral (select t3.a) dt, t2 as t3", 1054)
+}
@@ -17,7 +17,9 @@ package executor
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"hash/crc64"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -25,13 +27,18 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -40,6 +47,16 @@ import (
 var (
 	null          = []byte("NULL")
 	taskQueueSize = 16 // the maximum number of pending tasks to commit in queue
+	// crcTable is used to compute the checksum of the loaded lines, which identifies the file when resuming.
+	crcTable = crc64.MakeTable(crc64.ECMA)
+)
+
+const (
+	// minLinesPerParseWorker is the minimum number of lines parsed by a worker, the lines
+	// of a small piece of data are parsed by the loading goroutine itself.
+	minLinesPerParseWorker = 32
+	// loadDataProgressTable is the system table recording the progress of the batched LOAD DATA statements.
+	loadDataProgressTable = "load_data_progress"
 )
 
 // LoadDataExec represents a load data executor.
@@ -76,6 +93,9 @@ func (e *LoadDataExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	if e.loadDataInfo.Path == "" {
 		return errors.New("Load Data: infile path is empty")
 	}
+	if err := e.loadDataInfo.initProgress(ctx); err != nil {
+		return err
+	}
 	sctx.SetValue(LoadDataVarKey, e.loadDataInfo)
 
 	return nil
@@ -100,6 +120,11 @@ func (e *LoadDataExec) Open(ctx context.Context) error {
 type CommitTask struct {
 	cnt  uint64
 	rows [][]types.Datum
+	// lines is the number of lines read from the beginning of the file when the task is made,
+	// including the ignored ones.
+	lines uint64
+	// checksum is the checksum of the lines after the ignored ones when the task is made.
+	checksum uint64
 }
 
 // LoadDataInfo saves the information of loading data operation.
@@ -115,6 +140,19 @@ type LoadDataInfo struct {
 	Ctx         sessionctx.Context
 	rows        [][]types.Datum
 	Drained     bool
+	// linesRead is the number of lines read from the beginning of the file, including the ignored ones.
+	linesRead uint64
+	// committedLines is the number of lines after the ignored ones which have been committed by a
+	// previous load, they are skipped when resuming the load.
+	committedLines uint64
+	// committedChecksum is the checksum of the lines committed by a previous load, the skipped lines
+	// must have the same checksum, otherwise the file is changed and the load can't be resumed.
+	committedChecksum uint64
+	// checksum is the checksum of the lines read after the ignored ones.
+	checksum uint64
+	// progressTable is the table recording the progress of the load, which is nil if the rows aren't
+	// committed in batches.
+	progressTable table.Table
 
 	ColumnAssignments  []*ast.Assignment
 	ColumnsAndUserVars []*ast.ColumnNameOrUserVar
@@ -249,7 +287,7 @@ func (e *LoadDataInfo) ForceQuit() {
 
 // MakeCommitTask produce commit task with data in LoadDataInfo.rows LoadDataInfo.curBatchCnt
 func (e *LoadDataInfo) MakeCommitTask() CommitTask {
-	return CommitTask{e.curBatchCnt, e.rows}
+	return CommitTask{e.curBatchCnt, e.rows, e.linesRead, e.checksum}
 }
 
 // EnqOneTask feed one batch commit task to commit work
@@ -289,6 +327,12 @@ func (e *LoadDataInfo) CommitOneTask(ctx context.Context, task CommitTask) error
 	failpoint.Inject("commitOneTaskErr", func() error {
 		return errors.New("mock commit one task error")
 	})
+	// The progress is committed together with the rows of the batch.
+	err = e.saveProgress(ctx, task)
+	if err != nil {
+		logutil.Logger(ctx).Error("commit error save progress", zap.Error(err))
+		return err
+	}
 	e.Ctx.StmtCommit()
 	// Make sure process stream routine never use invalid txn
 	e.txnInUse.Lock()
@@ -334,13 +378,24 @@ func (e *LoadDataInfo) CommitWork(ctx context.Context) error {
 					break
 				}
 				tasks++
+				stmtCtx := e.ctx.GetSessionVars().StmtCtx
 				logutil.Logger(ctx).Info("commit one task success",
 					zap.Duration("commit time usage", time.Since(start)),
 					zap.Uint64("keys processed", commitTask.cnt),
 					zap.Uint64("tasks processed", tasks),
-					zap.Int("tasks in queue", len(e.commitTaskQueue)))
+					zap.Int("tasks in queue", len(e.commitTaskQueue)),
+					zap.Uint64("lines processed", commitTask.lines),
+					zap.Uint64("records", stmtCtx.RecordRows()),
+					zap.Uint64("skipped", stmtCtx.RecordRows()-stmtCtx.CopiedRows()),
+					zap.Uint16("warnings", stmtCtx.WarningCount()))
 			} else {
 				end = true
+				// The file ends before all the committed lines are skipped.
+				if e.committedLines > 0 {
+					err = e.errFileChanged()
+					break
+				}
+				err = e.clearProgress(ctx)
 			}
 		}
 		if err != nil {
@@ -356,6 +411,122 @@ func (e *LoadDataInfo) CommitWork(ctx context.Context) error {
 	return err
 }
 
+// initProgress looks up the table recording the progress of the load. If tidb_load_data_resume is
+// enabled, the lines committed by a previous interrupted load of the same file are skipped.
+func (e *LoadDataInfo) initProgress(ctx context.Context) error {
+	if e.Table.Meta().TempTableType != model.TempTableNone {
+		return nil
+	}
+	is := e.ctx.GetInfoSchema().(infoschema.InfoSchema)
+	tbl, err := is.TableByName(model.NewCIStr(mysql.SystemDB), model.NewCIStr(loadDataProgressTable))
+	if err != nil {
+		// The system table doesn't exist before the cluster is upgraded.
+		return nil
+	}
+	e.progressTable = tbl
+	if !e.ctx.GetSessionVars().LoadDataResume {
+		return nil
+	}
+	h, err := e.progressHandle()
+	if err != nil {
+		return err
+	}
+	row, err := tables.RowWithCols(tbl, e.ctx, h, tbl.Cols())
+	if kv.ErrNotExist.Equal(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lines := row[4].GetUint64(); lines > e.IgnoreLines {
+		logutil.Logger(ctx).Info("resume load data from the recorded progress",
+			zap.String("path", e.Path), zap.Uint64("committed lines", lines))
+		e.committedLines = lines - e.IgnoreLines
+		e.committedChecksum = row[3].GetUint64()
+	}
+	return nil
+}
+
+// progressHandle returns the handle of the progress record, which is identified by the table and
+// the digest of the file path, so the loads of different files into the same table don't conflict.
+func (e *LoadDataInfo) progressHandle() (kv.Handle, error) {
+	digest := sha256.Sum256([]byte(e.Path))
+	encoded, err := codec.EncodeKey(e.ctx.GetSessionVars().StmtCtx, nil,
+		types.NewIntDatum(e.Table.Meta().ID), types.NewBytesDatum(digest[:]))
+	if err != nil {
+		return nil, err
+	}
+	return kv.NewCommonHandle(encoded)
+}
+
+// updateChecksum returns the checksum updated with the line, the line terminator is included so
+// the lines split in different ways have different checksums.
+func (e *LoadDataInfo) updateChecksum(line []byte) uint64 {
+	checksum := crc64.Update(e.checksum, crcTable, line)
+	return crc64.Update(checksum, crcTable, []byte(e.LinesInfo.Terminated))
+}
+
+func (e *LoadDataInfo) errFileChanged() error {
+	return errors.Errorf("Load Data: the file %s doesn't match the recorded progress, set tidb_load_data_resume to OFF to load it from the beginning", e.Path)
+}
+
+// saveProgress records the number of lines committed by the task and their checksum, along with the number
+// of records, skipped records and warnings so far, so other sessions can see how much of the file is loaded.
+func (e *LoadDataInfo) saveProgress(ctx context.Context, task CommitTask) error {
+	if e.progressTable == nil || e.maxRowsInBatch == 0 {
+		return nil
+	}
+	stmtCtx := e.ctx.GetSessionVars().StmtCtx
+	records := stmtCtx.RecordRows()
+	now := types.NewTime(types.FromGoTime(time.Now().In(stmtCtx.TimeZone)), mysql.TypeTimestamp, 0)
+	digest := sha256.Sum256([]byte(e.Path))
+	row := types.MakeDatums(e.Table.Meta().ID, digest[:], e.Path, task.checksum, task.lines, records,
+		records-stmtCtx.CopiedRows(), uint64(stmtCtx.WarningCount()), now)
+	h, err := e.progressHandle()
+	if err != nil {
+		return err
+	}
+	oldRow, err := tables.RowWithCols(e.progressTable, e.ctx, h, e.progressTable.Cols())
+	if kv.ErrNotExist.Equal(err) {
+		_, err = e.progressTable.AddRecord(e.ctx, row, table.WithCtx(ctx))
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	touched := make([]bool, len(row))
+	for i := range touched {
+		touched[i] = true
+	}
+	return e.progressTable.UpdateRecord(ctx, e.ctx, h, oldRow, row, touched)
+}
+
+// clearProgress removes the progress record after all the rows are committed.
+func (e *LoadDataInfo) clearProgress(ctx context.Context) error {
+	if e.progressTable == nil || e.maxRowsInBatch == 0 {
+		return nil
+	}
+	h, err := e.progressHandle()
+	if err != nil {
+		return err
+	}
+	oldRow, err := tables.RowWithCols(e.progressTable, e.ctx, h, e.progressTable.Cols())
+	if kv.ErrNotExist.Equal(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = e.progressTable.RemoveRecord(e.ctx, h, oldRow); err != nil {
+		e.Ctx.StmtRollback()
+		return err
+	}
+	e.Ctx.StmtCommit()
+	e.txnInUse.Lock()
+	defer e.txnInUse.Unlock()
+	return e.Ctx.RefreshTxnCtx(ctx)
+}
+
 // SetMaxRowsInBatch sets the max number of rows to insert in a batch.
 func (e *LoadDataInfo) SetMaxRowsInBatch(limit uint64) {
 	e.maxRowsInBatch = limit
@@ -577,6 +748,7 @@ func (e *LoadDataInfo) InsertData(ctx context.Context, prevData, curData []byte)
 		return nil, false, nil
 	}
 	var line []byte
+	var lines [][]byte
 	var isEOF, hasStarting, reachLimit bool
 	if len(prevData) > 0 && len(curData) == 0 {
 		isEOF = true
@@ -602,29 +774,92 @@ func (e *LoadDataInfo) InsertData(ctx context.Context, prevData, curData []byte)
 			curData = nil
 		}
 
+		e.linesRead++
 		if e.IgnoreLines > 0 {
 			e.IgnoreLines--
 			continue
 		}
-		cols, err := e.getFieldsFromLine(line)
-		if err != nil {
-			return nil, false, err
+		e.checksum = e.updateChecksum(line)
+		// Unlike the ignored lines, the committed lines are split with the enclosed character
+		// taken into account, the same as when they were loaded.
+		if e.committedLines > 0 {
+			e.committedLines--
+			if e.committedLines == 0 && e.checksum != e.committedChecksum {
+				return nil, false, e.errFileChanged()
+			}
+			continue
+		}
+		lines = append(lines, line)
+		if e.maxRowsInBatch != 0 && (e.rowCount+uint64(len(lines)))%e.maxRowsInBatch == 0 {
+			reachLimit = true
+			logutil.Logger(ctx).Info("batch limit hit when inserting rows", zap.Int("maxBatchRows", e.maxChunkSize),
+				zap.Uint64("totalRows", e.rowCount+uint64(len(lines))))
+			break
 		}
+	}
+	// The lines are parsed before return, because they may refer to prevData and curData.
+	fields, err := e.parseLines(lines)
+	if err != nil {
+		return nil, false, err
+	}
+	// The fields are converted to rows sequentially, since the conversion evaluates
+	// expressions and sets user variables in the session.
+	for _, cols := range fields {
 		// rowCount will be used in fillRow(), last insert ID will be assigned according to the rowCount = 1.
 		// So should add first here.
 		e.rowCount++
 		e.rows = append(e.rows, e.colsToRow(ctx, cols))
 		e.curBatchCnt++
-		if e.maxRowsInBatch != 0 && e.rowCount%e.maxRowsInBatch == 0 {
-			reachLimit = true
-			logutil.Logger(ctx).Info("batch limit hit when inserting rows", zap.Int("maxBatchRows", e.maxChunkSize),
-				zap.Uint64("totalRows", e.rowCount))
-			break
-		}
 	}
 	return curData, reachLimit, nil
 }
 
+// parseLines splits the lines into fields. The lines are divided into ranges parsed by a
+// pool of workers when there are enough of them, the result keeps the order of the lines.
+func (e *LoadDataInfo) parseLines(lines [][]byte) ([][]field, error) {
+	fields := make([][]field, len(lines))
+	concurrency := e.ctx.GetSessionVars().ExecutorConcurrency
+	if maxWorkers := len(lines) / minLinesPerParseWorker; maxWorkers < concurrency {
+		concurrency = maxWorkers
+	}
+	if concurrency <= 1 {
+		for i, line := range lines {
+			cols, err := e.getFieldsFromLine(line)
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = cols
+		}
+		return fields, nil
+	}
+	var wg util.WaitGroupWrapper
+	errs := make([]error, concurrency)
+	linesPerWorker := (len(lines) + concurrency - 1) / concurrency
+	for i := 0; i < concurrency; i++ {
+		workerID, start, end := i, i*linesPerWorker, (i+1)*linesPerWorker
+		if end > len(lines) {
+			end = len(lines)
+		}
+		wg.Run(func() {
+			for j := start; j < end; j++ {
+				cols, err := e.getFieldsFromLine(lines[j])
+				if err != nil {
+					errs[workerID] = err
+					return
+				}
+				fields[j] = cols
+			}
+		})
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
 // CheckAndInsertOneBatch is used to commit one transaction batch full filled data
 func (e *LoadDataInfo) CheckAndInsertOneBatch(ctx context.Context, rows [][]types.Datum, cnt uint64) error {
 	if e.stats != nil && e.stats.BasicRuntimeStats != nil {
@@ -699,7 +934,7 @@ func (e *LoadDataInfo) colsToRow(ctx context.Context, cols []field) []types.Datu
 		// eval expression of `SET` clause
 		d, err := expression.EvalAstExpr(e.Ctx, e.ColumnAssignments[i].Expr)
 		if err != nil {
-			e.handleWarning(err)
+			appendDMLError(e.ctx, row, err)
 			return nil
 		}
 		row = append(row, d)
@@ -708,7 +943,7 @@ func (e *LoadDataInfo) colsToRow(ctx context.Context, cols []field) []types.Datu
 	// a new row buffer will be allocated in getRow
 	newRow, err := e.getRow(ctx, row)
 	if err != nil {
-		e.handleWarning(err)
+		appendDMLError(e.ctx, row, err)
 		return nil
 	}
 
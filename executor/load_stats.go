@@ -32,6 +32,8 @@ var _ Executor = &LoadStatsExec{}
 type LoadStatsExec struct {
 	baseExecutor
 	info *LoadStatsInfo
+	// json is the statistics of `LOAD STATS FROM 'json'`, which is loaded without reading the file.
+	json string
 }
 
 // LoadStatsInfo saves the information of loading statistic operation.
@@ -54,6 +56,9 @@ const LoadStatsVarKey loadStatsVarKeyType = 0
 // Next implements the Executor Next interface.
 func (e *LoadStatsExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.GrowAndReset(e.maxChunkSize)
+	if len(e.json) > 0 {
+		return e.info.Update([]byte(e.json))
+	}
 	if len(e.info.Path) == 0 {
 		return errors.New("Load Stats: file path is empty")
 	}
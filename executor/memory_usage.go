@@ -0,0 +1,69 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"runtime"
+
+	"github.com/cznic/mathutil"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/kvcache"
+)
+
+// MemoryUsage is the memory usage of a category in the tidb-server.
+type MemoryUsage struct {
+	Category    string `json:"category"`
+	Bytes       int64  `json:"bytes"`
+	Description string `json:"description"`
+}
+
+// GetMemoryUsage returns the memory usage of the tidb-server by category. The categories of TiDB are sourced from
+// the memory trackers, and the others are sourced from the Go runtime.
+func GetMemoryUsage(dom *domain.Domain, sm util.SessionManager) []MemoryUsage {
+	var sessions int64
+	if sm != nil {
+		for _, info := range sm.ShowProcessList() {
+			// The tracker of the idle connection is the one of its last statement, which has been released.
+			if len(info.Info) == 0 || info.StmtCtx == nil || info.StmtCtx.MemTracker == nil {
+				continue
+			}
+			sessions += info.StmtCtx.MemTracker.BytesConsumed()
+		}
+	}
+	planCache := kvcache.GlobalLRUMemUsageTracker.BytesConsumed()
+	// The trackers of all the running statements and the LRU caches are attached to the global tracker, the rest
+	// belongs to the statements of the internal sessions.
+	internal := mathutil.MaxInt64(GlobalMemoryUsageTracker.BytesConsumed()-sessions-planCache, 0)
+	var statsCache int64
+	if dom != nil && dom.StatsHandle() != nil {
+		statsCache = dom.StatsHandle().GetMemConsumed()
+	}
+	stats := &runtime.MemStats{}
+	runtime.ReadMemStats(stats)
+	untracked := mathutil.MaxInt64(int64(stats.HeapInuse)-sessions-internal-planCache-statsCache, 0)
+	return []MemoryUsage{
+		{Category: "sessions", Bytes: sessions, Description: "the running statements of the connections"},
+		{Category: "internal", Bytes: internal, Description: "the running statements of the internal sessions"},
+		{Category: "plan_cache", Bytes: planCache, Description: "the plan cache and the other LRU caches"},
+		{Category: "stats_cache", Bytes: statsCache, Description: "the statistics loaded in the stats cache"},
+		{Category: "untracked", Bytes: untracked, Description: "the heap memory in use which isn't tracked by TiDB"},
+		{Category: "runtime.heap_inuse", Bytes: int64(stats.HeapInuse), Description: "the heap memory in use"},
+		{Category: "runtime.heap_idle", Bytes: int64(stats.HeapIdle - stats.HeapReleased), Description: "the heap memory which is idle and not released to the OS"},
+		{Category: "runtime.stack_inuse", Bytes: int64(stats.StackInuse), Description: "the memory of the goroutine stacks"},
+		{Category: "runtime.sys", Bytes: int64(stats.Sys), Description: "the memory obtained from the OS"},
+		{Category: "runtime.next_gc", Bytes: int64(stats.NextGC), Description: "the heap size target of the next GC"},
+	}
+}
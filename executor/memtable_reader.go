@@ -229,31 +229,11 @@ func fetchClusterConfig(sctx sessionctx.Context, nodeTypes, nodeAddrs set.String
 					ch <- result{err: errors.Trace(err)}
 					return
 				}
-				data := config.FlattenConfigItems(nested)
-				type item struct {
-					key string
-					val string
-				}
-				var items []item
-				for key, val := range data {
-					if config.ContainHiddenConfig(key) {
-						continue
-					}
-					var str string
-					switch val := val.(type) {
-					case string: // remove quotes
-						str = val
-					default:
-						tmp, err := json.Marshal(val)
-						if err != nil {
-							ch <- result{err: errors.Trace(err)}
-							return
-						}
-						str = string(tmp)
-					}
-					items = append(items, item{key: key, val: str})
+				items, err := flattenConfigItems(nested)
+				if err != nil {
+					ch <- result{err: err}
+					return
 				}
-				sort.Slice(items, func(i, j int) bool { return items[i].key < items[j].key })
 				var rows [][]types.Datum
 				for _, item := range items {
 					rows = append(rows, types.MakeDatums(
@@ -287,6 +267,157 @@ func fetchClusterConfig(sctx sessionctx.Context, nodeTypes, nodeAddrs set.String
 	return finalRows, nil
 }
 
+type configItem struct {
+	key string
+	val string
+}
+
+// flattenConfigItems flattens the nested config into the items sorted by key, the hidden items are skipped.
+func flattenConfigItems(nested map[string]interface{}) ([]configItem, error) {
+	data := config.FlattenConfigItems(nested)
+	items := make([]configItem, 0, len(data))
+	for key, val := range data {
+		if config.ContainHiddenConfig(key) {
+			continue
+		}
+		var str string
+		switch val := val.(type) {
+		case string: // remove quotes
+			str = val
+		default:
+			tmp, err := json.Marshal(val)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			str = string(tmp)
+		}
+		items = append(items, configItem{key: key, val: str})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].key < items[j].key })
+	return items, nil
+}
+
+const (
+	configDiffReasonMajority = "majority"
+	configDiffReasonDefault  = "default"
+)
+
+type clusterConfigDiffRetriever struct {
+	dummyCloser
+	retrieved bool
+	extractor *plannercore.ClusterTableExtractor
+}
+
+// retrieve implements the memTableRetriever interface
+func (e *clusterConfigDiffRetriever) retrieve(_ context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
+	if e.extractor.SkipRequest || e.retrieved {
+		return nil, nil
+	}
+	e.retrieved = true
+	// The configs of all instances of the type are needed to decide the majority, so the instances are filtered later.
+	rows, err := fetchClusterConfig(sctx, e.extractor.NodeTypes, set.NewStringSet())
+	if err != nil {
+		return nil, err
+	}
+	tidbDefaults, err := tidbDefaultConfigItems()
+	if err != nil {
+		return nil, err
+	}
+	return diffClusterConfig(rows, tidbDefaults, e.extractor.Instances), nil
+}
+
+// tidbDefaultConfigItems returns the default config items of TiDB, in the same format as the `/config` API.
+func tidbDefaultConfigItems() (map[string]string, error) {
+	data, err := json.Marshal(config.NewConfig())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var nested map[string]interface{}
+	if err = json.Unmarshal(data, &nested); err != nil {
+		return nil, errors.Trace(err)
+	}
+	items, err := flattenConfigItems(nested)
+	if err != nil {
+		return nil, err
+	}
+	defaults := make(map[string]string, len(items))
+	for _, item := range items {
+		defaults[item.key] = item.val
+	}
+	return defaults, nil
+}
+
+// diffClusterConfig picks the rows of `cluster_config` whose value diverges from the majority value of the same type
+// and key, or from the default value of TiDB.
+func diffClusterConfig(configRows [][]types.Datum, tidbDefaults map[string]string, instances set.StringSet) [][]types.Datum {
+	type typeKey struct {
+		typ string
+		key string
+	}
+	// The count of every value of every item.
+	valueCounts := make(map[typeKey]map[string]int)
+	for _, row := range configRows {
+		item := typeKey{typ: row[0].GetString(), key: row[2].GetString()}
+		if valueCounts[item] == nil {
+			valueCounts[item] = make(map[string]int)
+		}
+		valueCounts[item][row[3].GetString()]++
+	}
+	majorities := make(map[typeKey]string, len(valueCounts))
+	for item, counts := range valueCounts {
+		// There is no majority if the most common values are tied.
+		var majority string
+		maxCount, tied := 0, false
+		for val, count := range counts {
+			if count > maxCount {
+				majority, maxCount, tied = val, count, false
+			} else if count == maxCount {
+				tied = true
+			}
+		}
+		if !tied {
+			majorities[item] = majority
+		}
+	}
+
+	var rows [][]types.Datum
+	for _, row := range configRows {
+		typ, instance, key, val := row[0].GetString(), row[1].GetString(), row[2].GetString(), row[3].GetString()
+		if len(instances) > 0 && !instances.Exist(instance) {
+			continue
+		}
+		var reasons []string
+		var majorityVal, defaultVal interface{}
+		if majority, ok := majorities[typeKey{typ: typ, key: key}]; ok {
+			majorityVal = majority
+			if majority != val {
+				reasons = append(reasons, configDiffReasonMajority)
+			}
+		}
+		if typ == "tidb" {
+			if def, ok := tidbDefaults[key]; ok {
+				defaultVal = def
+				if def != val {
+					reasons = append(reasons, configDiffReasonDefault)
+				}
+			}
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+		rows = append(rows, types.MakeDatums(
+			typ,
+			instance,
+			key,
+			val,
+			majorityVal,
+			defaultVal,
+			strings.Join(reasons, ","),
+		))
+	}
+	return rows
+}
+
 type clusterServerInfoRetriever struct {
 	dummyCloser
 	extractor      *plannercore.ClusterTableExtractor
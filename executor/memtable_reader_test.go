@@ -451,6 +451,66 @@ func (s *testMemTableReaderSuite) TestTiDBClusterConfig(c *C) {
 	}
 }
 
+func (s *testMemTableReaderSuite) TestTiDBClusterConfigDiff(c *C) {
+	const testServerCount = 3
+	var servers []*httptest.Server
+	for i := 0; i < testServerCount; i++ {
+		configuration := map[string]interface{}{
+			"key1":        "value1",
+			"key2":        fmt.Sprintf("value%d", i),
+			"split-table": true,
+		}
+		if i == testServerCount-1 {
+			configuration["key1"] = "value2"
+			configuration["split-table"] = false
+		}
+		router := mux.NewRouter()
+		router.Handle(pdapi.Config, fn.Wrap(func() (map[string]interface{}, error) {
+			return configuration, nil
+		}))
+		router.Handle("/config", fn.Wrap(func() (map[string]interface{}, error) {
+			return configuration, nil
+		}))
+		servers = append(servers, httptest.NewServer(router))
+	}
+	defer func() {
+		for _, server := range servers {
+			server.Close()
+		}
+	}()
+
+	var serversInfo []string
+	for _, typ := range []string{"tidb", "tikv"} {
+		for _, server := range servers {
+			address := strings.TrimPrefix(server.URL, "http://")
+			serversInfo = append(serversInfo, strings.Join([]string{typ, address, address}, ","))
+		}
+	}
+	fpName := "github.com/pingcap/tidb/executor/mockClusterConfigServerInfo"
+	c.Assert(failpoint.Enable(fpName, fmt.Sprintf(`return("%s")`, strings.Join(serversInfo, ";"))), IsNil)
+	defer func() { c.Assert(failpoint.Disable(fpName), IsNil) }()
+
+	// The value of `key2` is different on every instance, so there is no majority of it.
+	lastAddr := strings.TrimPrefix(servers[testServerCount-1].URL, "http://")
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustQuery("select * from information_schema.cluster_config_diff").Check(testkit.Rows(
+		fmt.Sprintf("tidb %s key1 value2 value1 <nil> majority", lastAddr),
+		fmt.Sprintf("tidb %s split-table false true true majority,default", lastAddr),
+		fmt.Sprintf("tikv %s key1 value2 value1 <nil> majority", lastAddr),
+		fmt.Sprintf("tikv %s split-table false true <nil> majority", lastAddr),
+	))
+	tk.MustQuery("select `key`, diff_reason from information_schema.cluster_config_diff where type = 'tikv'").Check(testkit.Rows(
+		"key1 majority",
+		"split-table majority",
+	))
+	// The majority is decided by all instances even if the instance is specified.
+	tk.MustQuery(fmt.Sprintf("select `key` from information_schema.cluster_config_diff where instance = '%s'", lastAddr)).Check(testkit.Rows(
+		"key1", "split-table", "key1", "split-table",
+	))
+	firstAddr := strings.TrimPrefix(servers[0].URL, "http://")
+	tk.MustQuery(fmt.Sprintf("select * from information_schema.cluster_config_diff where instance = '%s'", firstAddr)).Check(testkit.Rows())
+}
+
 func (s *testClusterTableBase) writeTmpFile(c *C, dir, filename string, lines []string) {
 	err := os.WriteFile(filepath.Join(dir, filename), []byte(strings.Join(lines, "\n")), os.ModePerm)
 	c.Assert(err, IsNil, Commentf("write tmp file %s failed", filename))
@@ -1237,6 +1297,17 @@ func (s *testHotRegionsHistoryTableSuite) TestTiDBHotRegionsHistory(c *C) {
 				fullHotRegions[0], fullHotRegions[1], fullHotRegions[6], fullHotRegions[7],
 			},
 		},
+		{
+			conditions: []string{
+				"update_time>='2019-10-10 10:10:10'",
+				"update_time<='2019-10-11 10:10:10'",
+				"type='READ'",
+			}, // the type is case-insensitive
+			expected: [][]string{
+				fullHotRegions[0], fullHotRegions[2],
+				fullHotRegions[6], fullHotRegions[8], fullHotRegions[10],
+			},
+		},
 		{
 			conditions: []string{
 				"update_time>='2019-10-10 10:10:10'",
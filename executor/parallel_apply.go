@@ -288,15 +288,15 @@ func (e *ParallelNestedLoopApplyExec) fetchAllInners(ctx context.Context, id int
 	if e.useCache { // look up the cache
 		atomic.AddInt64(&e.cacheAccessCounter, 1)
 		failpoint.Inject("parallelApplyGetCachePanic", nil)
-		value, err := e.cache.Get(key)
-		if err != nil {
-			return err
-		}
-		if value != nil {
+		// The workers processing the outer rows with the same correlated values wait for
+		// the one fetching the inner rows, so the inner side is executed only once for them.
+		value, reserved := e.cache.GetOrReserve(key)
+		if !reserved {
 			e.innerList[id] = value
 			atomic.AddInt64(&e.cacheHitCounter, 1)
 			return nil
 		}
+		defer e.cache.Release(key)
 	}
 
 	err = e.innerExecs[id].Open(ctx)
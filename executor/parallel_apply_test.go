@@ -568,6 +568,13 @@ func (s *testSuite) TestApplyCacheRatio(c *C) {
 	c.Assert(checkRatio("50.000%"), IsTrue)
 	tk.MustExec("set tidb_mem_quota_apply_cache = 0")
 	c.Assert(checkRatio(""), IsFalse)
+	tk.MustExec("set tidb_mem_quota_apply_cache = 33554432")
+
+	// The workers of the parallel apply share the cache, and the inner side is executed
+	// only once for the same correlated values even if they are processed concurrently.
+	tk.MustExec("set tidb_enable_parallel_apply = true")
+	tk.MustExec("set tidb_executor_concurrency = 5")
+	c.Assert(checkRatio("50.000%"), IsTrue)
 }
 
 func (s *testSuite) TestApplyGoroutinePanic(c *C) {
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/israce"
 	"github.com/pingcap/tidb/util/testkit"
@@ -2740,6 +2741,36 @@ partition p2 values less than (10))`)
 	tk.MustQuery("select * from p use index (idx)").Check(testkit.Rows("1 3", "3 4", "5 6", "7 9"))
 }
 
+func (s *globalIndexSuite) TestGlobalIndexPointGet(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists p")
+	tk.MustExec(`create table p (id int, c int) partition by range (c) (
+partition p0 values less than (4),
+partition p1 values less than (7),
+partition p2 values less than (10))`)
+	tk.MustExec("alter table p add unique idx(id)")
+	tk.MustExec("insert into p values (1,3), (3,4), (5,6), (7,9)")
+	c.Assert(tk.HasPlan("select * from p where id = 3", "Point_Get"), IsTrue)
+	tk.MustQuery("select * from p where id = 3").Check(testkit.Rows("3 4"))
+	tk.MustQuery("select * from p where id = 4").Check(testkit.Rows())
+	tk.MustQuery("select * from p where id = 3 and c = 4").Check(testkit.Rows("3 4"))
+	tk.MustQuery("select * from p where id = 3 and c = 5").Check(testkit.Rows())
+	c.Assert(tk.HasPlan("select * from p where id in (1, 5)", "Batch_Point_Get"), IsTrue)
+	tk.MustQuery("select * from p where id in (1, 5, 8)").Sort().Check(testkit.Rows("1 3", "5 6"))
+
+	tk.MustExec("update p set c = 8 where id = 5")
+	tk.MustQuery("select * from p where id = 5").Check(testkit.Rows("5 8"))
+	tk.MustGetErrCode("insert into p values (5, 1)", mysql.ErrDupEntry)
+	tk.MustGetErrCode("update p set id = 1, c = 8 where id = 3", mysql.ErrDupEntry)
+	tk.MustQuery("select * from p where id = 3").Check(testkit.Rows("3 4"))
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from p where id = 7 for update").Check(testkit.Rows("7 9"))
+	tk.MustExec("delete from p where id = 7")
+	tk.MustQuery("select * from p where id = 7").Check(testkit.Rows())
+	tk.MustExec("commit")
+	tk.MustQuery("select * from p use index (idx)").Check(testkit.Rows("1 3", "3 4", "5 8"))
+}
+
 func (s *partitionTableSuite) TestIssue20028(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
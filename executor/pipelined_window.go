@@ -25,12 +25,15 @@ import (
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 type dataInfo struct {
 	chk         *chunk.Chunk
 	remaining   uint64
 	accumulated uint64
+	// memUsage is the memory consumed by chk when it was fetched from the child executor.
+	memUsage int64
 }
 
 // PipelinedWindowExec is the executor for window functions.
@@ -43,6 +46,9 @@ type PipelinedWindowExec struct {
 	start              *core.FrameBound
 	end                *core.FrameBound
 	groupChecker       *vecGroupChecker
+	// memTracker tracks the memory of the buffered child chunks, which is bounded by the frame size
+	// instead of the partition size.
+	memTracker *memory.Tracker
 
 	// childResult stores the child chunk. Note that even if remaining is 0, e.rows might still references rows in data[0].chk after returned it to upper executor, since there is no guarantee what the upper executor will do to the returned chunk, it might destroy the data (as in the benchmark test, it reused the chunk to pull data, and it will be chk.Reset(), causing panicking). So dataIdx, accumulated and dropped are added to ensure that chunk will only be returned if there is no row reference.
 	childResult *chunk.Chunk
@@ -78,6 +84,9 @@ type PipelinedWindowExec struct {
 
 // Close implements the Executor Close interface.
 func (e *PipelinedWindowExec) Close() error {
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	}
 	return errors.Trace(e.baseExecutor.Close())
 }
 
@@ -96,6 +105,8 @@ func (e *PipelinedWindowExec) Open(ctx context.Context) (err error) {
 		}
 	}
 	e.rows = make([]chunk.Row, 0)
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
 	return e.baseExecutor.Open(ctx)
 }
 
@@ -161,6 +172,7 @@ func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err e
 		}
 	}
 	if len(e.data) > 0 {
+		e.memTracker.Consume(-e.data[0].memUsage)
 		chk.SwapColumns(e.data[0].chk)
 		e.data = e.data[1:]
 		e.dataIdx--
@@ -223,7 +235,9 @@ func (e *PipelinedWindowExec) fetchChild(ctx context.Context) (EOF bool, err err
 		return false, err
 	}
 	e.accumulated += uint64(numRows)
-	e.data = append(e.data, dataInfo{chk: resultChk, remaining: uint64(numRows), accumulated: e.accumulated})
+	memUsage := resultChk.MemoryUsage()
+	e.memTracker.Consume(memUsage)
+	e.data = append(e.data, dataInfo{chk: resultChk, remaining: uint64(numRows), accumulated: e.accumulated, memUsage: memUsage})
 
 	e.childResult = childResult
 	return false, nil
@@ -29,6 +29,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/bindinfo"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
@@ -126,19 +127,10 @@ func (e *PlanReplayerSingleExec) dumpSingle(path string) (string, error) {
 		return "", errors.AddStack(err)
 	}
 
-	// Generate key and create zip file
-	time := time.Now().UnixNano()
-	b := make([]byte, 16)
-	_, err = rand.Read(b)
+	zf, fileName, err := createPlanReplayerFile(path, "replayer_single")
 	if err != nil {
 		return "", err
 	}
-	key := base64.URLEncoding.EncodeToString(b)
-	fileName := fmt.Sprintf("replayer_single_%v_%v.zip", key, time)
-	zf, err := os.Create(filepath.Join(path, fileName))
-	if err != nil {
-		return "", errors.AddStack(err)
-	}
 
 	// Create zip writer
 	zw := zip.NewWriter(zf)
@@ -217,6 +209,137 @@ func (e *PlanReplayerSingleExec) dumpSingle(path string) (string, error) {
 	return fileName, nil
 }
 
+// createPlanReplayerFile generates a random key and creates the zip file named by the prefix, the key and the
+// current time, the time is parsed by the GC of the plan replayer.
+func createPlanReplayerFile(path, prefix string) (*os.File, string, error) {
+	time := time.Now().UnixNano()
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, "", err
+	}
+	key := base64.URLEncoding.EncodeToString(b)
+	fileName := fmt.Sprintf("%v_%v_%v.zip", prefix, key, time)
+	zf, err := os.Create(filepath.Join(path, fileName))
+	if err != nil {
+		return nil, "", errors.AddStack(err)
+	}
+	return zf, fileName, nil
+}
+
+// DumpPlanReplayerCapture dumps the statement captured automatically into a plan replayer zip, the format is the
+// same as `plan replayer dump`, except that the variables, the session bindings and the plan are the ones collected
+// from the session when the statement finishes.
+func DumpPlanReplayerCapture(sctx sessionctx.Context, task *domain.PlanReplayerCaptureTask) (_ string, err error) {
+	path := domain.GetPlanReplayerDirName()
+	if err = os.MkdirAll(path, os.ModePerm); err != nil {
+		return "", errors.AddStack(err)
+	}
+	zf, fileName, err := createPlanReplayerFile(path, "capture_replayer")
+	if err != nil {
+		return "", err
+	}
+	zw := zip.NewWriter(zf)
+	defer func() {
+		if closeErr := zw.Close(); closeErr != nil {
+			logutil.BgLogger().Warn("Closing zip writer failed", zap.Error(closeErr))
+		}
+		if closeErr := zf.Close(); closeErr != nil {
+			logutil.BgLogger().Warn("Closing zip file failed", zap.Error(closeErr))
+		}
+		// Don't leave a broken zip.
+		if err != nil {
+			if rmErr := os.Remove(filepath.Join(path, fileName)); rmErr != nil {
+				logutil.BgLogger().Warn("Removing zip file failed", zap.Error(rmErr))
+			}
+		}
+	}()
+
+	if err = dumpConfig(zw); err != nil {
+		return "", err
+	}
+	if err = dumpMeta(zw); err != nil {
+		return "", err
+	}
+	pairs, err := extractTableNames(task.Stmt, strings.ToLower(task.DB))
+	if err != nil {
+		return "", err
+	}
+	if err = dumpSchemas(sctx, zw, pairs); err != nil {
+		return "", err
+	}
+	if err = dumpStats(zw, pairs, domain.GetDomain(sctx)); err != nil {
+		return "", err
+	}
+	vf, err := zw.Create("variables.toml")
+	if err != nil {
+		return "", errors.AddStack(err)
+	}
+	if err = toml.NewEncoder(vf).Encode(task.Variables); err != nil {
+		return "", errors.AddStack(err)
+	}
+	if err = writePlanReplayerFile(zw, "sqls.sql", task.Stmt.Text()); err != nil {
+		return "", err
+	}
+	bindings := make([]string, 0, len(task.SessionBindings))
+	for _, row := range task.SessionBindings {
+		bindings = append(bindings, strings.Join(row, "\t")+"\n")
+	}
+	if err = writePlanReplayerFile(zw, "session_bindings.sql", strings.Join(bindings, "")); err != nil {
+		return "", err
+	}
+	if err = dumpGlobalBindings(sctx, zw); err != nil {
+		return "", err
+	}
+	if err = writePlanReplayerFile(zw, "explain.txt", task.Plan); err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
+
+func writePlanReplayerFile(zw *zip.Writer, name, content string) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return errors.AddStack(err)
+	}
+	if _, err = fw.Write([]byte(content)); err != nil {
+		return errors.AddStack(err)
+	}
+	return nil
+}
+
+// getPlanReplayerVariables returns the values of the system variables of the session, like `show variables`.
+func getPlanReplayerVariables(sessVars *variable.SessionVars) map[string]string {
+	varMap := make(map[string]string)
+	for _, v := range variable.GetSysVars() {
+		if v.Hidden {
+			continue
+		}
+		value, err := variable.GetSessionOrGlobalSystemVar(sessVars, v.Name)
+		if err != nil {
+			continue
+		}
+		varMap[v.Name] = value
+	}
+	return varMap
+}
+
+// getPlanReplayerSessionBindings returns the bindings of the session, like `show bindings`.
+func getPlanReplayerSessionBindings(sctx sessionctx.Context) [][]string {
+	handle, ok := sctx.Value(bindinfo.SessionBindInfoKeyType).(*bindinfo.SessionHandle)
+	if !ok {
+		return nil
+	}
+	var rows [][]string
+	for _, bindRecord := range handle.GetAllBindRecord() {
+		for _, binding := range bindRecord.Bindings {
+			rows = append(rows, []string{bindRecord.OriginalSQL, binding.BindSQL, bindRecord.Db, binding.Status,
+				binding.CreateTime.String(), binding.UpdateTime.String(), binding.Charset, binding.Collation, binding.Source})
+		}
+	}
+	return rows
+}
+
 func dumpConfig(zw *zip.Writer) error {
 	cf, err := zw.Create("config.toml")
 	if err != nil {
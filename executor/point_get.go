@@ -201,11 +201,7 @@ func (e *PointGetExecutor) Close() error {
 		e.snapshot.SetOption(kv.CollectRuntimeStats, nil)
 	}
 	if e.idxInfo != nil && e.tblInfo != nil {
-		actRows := int64(0)
-		if e.runtimeStats != nil {
-			actRows = e.runtimeStats.GetActRows()
-		}
-		e.ctx.StoreIndexUsage(e.tblInfo.ID, e.idxInfo.ID, actRows)
+		e.storeIndexUsage(e.tblInfo, e.idxInfo)
 	}
 	e.done = false
 	return nil
@@ -226,7 +222,9 @@ func (e *PointGetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 	} else {
 		tblID = e.tblInfo.ID
 	}
-	if e.lock {
+	isGlobalIndex := e.idxInfo != nil && e.idxInfo.Global
+	// The partition of the row is decided by the global index value later.
+	if e.lock && !isGlobalIndex {
 		e.updateDeltaForTableID(tblID)
 	}
 	if e.idxInfo != nil {
@@ -243,7 +241,12 @@ func (e *PointGetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 				return err
 			}
 		} else {
-			e.idxKey, err = EncodeUniqueIndexKey(e.ctx, e.tblInfo, e.idxInfo, e.idxVals, tblID)
+			idxTblID := tblID
+			if isGlobalIndex {
+				// The global index is encoded with the ID of the partitioned table.
+				idxTblID = e.tblInfo.ID
+			}
+			e.idxKey, err = EncodeUniqueIndexKey(e.ctx, e.tblInfo, e.idxInfo, e.idxVals, idxTblID)
 			if err != nil && !kv.ErrNotExist.Equal(err) {
 				return err
 			}
@@ -284,6 +287,20 @@ func (e *PointGetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 				return err
 			}
 			e.handle = iv
+			if isGlobalIndex {
+				pid, err := tablecodec.DecodePartitionIDInGlobalIndexValue(e.handleVal)
+				if err != nil {
+					return err
+				}
+				// The row isn't in the located partition.
+				if e.partInfo != nil && e.partInfo.ID != pid {
+					return nil
+				}
+				tblID = pid
+				if e.lock {
+					e.updateDeltaForTableID(tblID)
+				}
+			}
 
 			// The injection is used to simulate following scenario:
 			// 1. Session A create a point get query but pause before second time `GET` kv from backend
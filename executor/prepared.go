@@ -108,6 +108,14 @@ func NewPrepareExec(ctx sessionctx.Context, sqlTxt string) *PrepareExec {
 	}
 }
 
+// NewRestoredPrepareExec creates a PrepareExec to restore a prepared statement with its original ID and name when the
+// session is migrated. It's executed within the `SET SESSION_STATES` statement, so the statement context isn't reset.
+func NewRestoredPrepareExec(ctx sessionctx.Context, sqlTxt string, id uint32, name string) *PrepareExec {
+	e := NewPrepareExec(ctx, sqlTxt)
+	e.ID, e.name, e.needReset = id, name, false
+	return e
+}
+
 // Next implements the Executor Next interface.
 func (e *PrepareExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	vars := e.ctx.GetSessionVars()
@@ -237,6 +245,8 @@ func (e *PrepareExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		SQLDigest:           digest,
 		ForUpdateRead:       destBuilder.GetIsForUpdateRead(),
 		SnapshotTSEvaluator: ret.SnapshotTSEvaluator,
+		StmtText:            e.sqlText,
+		StmtDB:              vars.CurrentDB,
 	}
 	return vars.AddPreparedStmt(e.ID, preparedObj)
 }
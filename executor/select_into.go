@@ -20,11 +20,15 @@ import (
 	"context"
 	"math"
 	"os"
+	"path"
 	"strconv"
+	"strings"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
@@ -40,11 +44,40 @@ type SelectIntoExec struct {
 	escapeBuf []byte
 	enclosed  bool
 	writer    *bufio.Writer
-	dstFile   *os.File
+	dstFile   *outfileWriter
 	chk       *chunk.Chunk
 	started   bool
 }
 
+// outfileWriter writes the file of SELECT INTO OUTFILE to the local disk or an external storage.
+type outfileWriter struct {
+	ctx    context.Context
+	writer storage.ExternalFileWriter
+}
+
+// Write implements the io.Writer interface.
+func (w *outfileWriter) Write(p []byte) (int, error) {
+	return w.writer.Write(w.ctx, p)
+}
+
+// Close finishes the file, the data is uploaded completely after it's closed.
+func (w *outfileWriter) Close() error {
+	return w.writer.Close(w.ctx)
+}
+
+// localFileWriter adapts a local file to storage.ExternalFileWriter.
+type localFileWriter struct {
+	file *os.File
+}
+
+func (w *localFileWriter) Write(_ context.Context, p []byte) (int, error) {
+	return w.file.Write(p)
+}
+
+func (w *localFileWriter) Close(_ context.Context) error {
+	return w.file.Close()
+}
+
 // Open implements the Executor Open interface.
 func (s *SelectIntoExec) Open(ctx context.Context) error {
 	// only 'select ... into outfile' is supported now
@@ -52,12 +85,12 @@ func (s *SelectIntoExec) Open(ctx context.Context) error {
 		return errors.New("unsupported SelectInto type")
 	}
 
-	f, err := os.OpenFile(s.intoOpt.FileName, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+	writer, err := createOutfile(ctx, s.intoOpt)
 	if err != nil {
-		return errors.Trace(err)
+		return err
 	}
 	s.started = true
-	s.dstFile = f
+	s.dstFile = &outfileWriter{ctx: ctx, writer: writer}
 	s.writer = bufio.NewWriter(s.dstFile)
 	s.chk = newFirstChunk(s.children[0])
 	s.lineBuf = make([]byte, 0, 1024)
@@ -66,6 +99,65 @@ func (s *SelectIntoExec) Open(ctx context.Context) error {
 	return s.baseExecutor.Open(ctx)
 }
 
+// createOutfile creates the file to write. The file name may be the URI of an external storage such as
+// s3://bucket/path/file.csv, then the rows are streamed to the storage without being saved on the local disk.
+func createOutfile(ctx context.Context, intoOpt *ast.SelectIntoOption) (storage.ExternalFileWriter, error) {
+	var compressType storage.CompressType
+	switch intoOpt.Compression {
+	case "", "none":
+		compressType = storage.NoCompression
+	case "gzip":
+		compressType = storage.Gzip
+	case "zstd":
+		compressType = storage.Zstd
+	default:
+		return nil, errors.Errorf("unsupported compression type %s of select into outfile", intoOpt.Compression)
+	}
+
+	if !strings.Contains(intoOpt.FileName, "://") {
+		f, err := os.OpenFile(intoOpt.FileName, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var writer storage.ExternalFileWriter = &localFileWriter{file: f}
+		if compressType != storage.NoCompression {
+			writer, err = storage.NewUploaderWriter(writer, outfileCompressChunkSize, compressType)
+			if err != nil {
+				terror.Log(f.Close())
+				return nil, errors.Trace(err)
+			}
+		}
+		return writer, nil
+	}
+
+	u, err := storage.ParseRawURL(intoOpt.FileName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var name string
+	u.Path, name = path.Split(u.Path)
+	if len(name) == 0 {
+		return nil, errors.Errorf("the file name of select into outfile %s is empty", intoOpt.FileName)
+	}
+	backend, err := storage.ParseBackend(u.String(), nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	store, err := storage.New(ctx, backend, &storage.ExternalStorageOptions{})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	exists, err := store.FileExists(ctx, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if exists {
+		return nil, ErrFileExists.GenWithStackByArgs(intoOpt.FileName)
+	}
+	writer, err := storage.WithCompression(store, compressType).Create(ctx, name)
+	return writer, errors.Trace(err)
+}
+
 // Next implements the Executor Next interface.
 func (s *SelectIntoExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	for {
@@ -178,7 +270,7 @@ func (s *SelectIntoExec) dumpToOutfile() error {
 			case mysql.TypeNewDecimal:
 				s.fieldBuf = append(s.fieldBuf, row.GetMyDecimal(j).String()...)
 			case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
-				mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+				mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeTiDBVectorFloat32:
 				s.fieldBuf = append(s.fieldBuf, row.GetBytes(j)...)
 			case mysql.TypeBit:
 				// bit value won't be escaped anyway (verified on MySQL, test case added)
@@ -233,6 +325,9 @@ func (s *SelectIntoExec) Close() error {
 const (
 	expFormatBig   = 1e15
 	expFormatSmall = 1e-15
+
+	// outfileCompressChunkSize is the size of the compressed data buffered before it's written to the local file.
+	outfileCompressChunkSize = 1024 * 1024
 )
 
 // DumpRealOutfile dumps a real number to lineBuf.
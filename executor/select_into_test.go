@@ -15,12 +15,15 @@
 package executor_test
 
 import (
+	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -54,6 +57,54 @@ func (s *testSuite1) TestSelectIntoFileExists(c *C) {
 	c.Assert(strings.Contains(err.Error(), outfile), IsTrue)
 }
 
+func (s *testSuite1) TestSelectIntoOutfileCompression(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(10))")
+	tk.MustExec("insert into t values (1, 'a'), (2, 'b'), (3, null)")
+	expected := "1\ta\n2\tb\n3\t\\N\n"
+	readCompressed := func(outfile string, newReader func(io.Reader) (io.ReadCloser, error)) string {
+		f, err := os.Open(outfile)
+		c.Assert(err, IsNil)
+		defer f.Close()
+		r, err := newReader(f)
+		c.Assert(err, IsNil)
+		defer r.Close()
+		content, err := io.ReadAll(r)
+		c.Assert(err, IsNil)
+		return string(content)
+	}
+	gzipReader := func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }
+	zstdReader := func(r io.Reader) (io.ReadCloser, error) {
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	}
+
+	outfile := randomSelectFilePath("TestSelectIntoOutfileCompression") + ".gz"
+	tk.MustExec(fmt.Sprintf("select * from t order by a into outfile %q compression 'gzip'", outfile))
+	c.Assert(readCompressed(outfile, gzipReader), Equals, expected)
+	c.Assert(os.Remove(outfile), IsNil)
+
+	// The file is written through the external storage if the file name is an URI.
+	dir := c.MkDir()
+	uri := "local://" + filepath.ToSlash(dir) + "/result.csv.zst"
+	tk.MustExec(fmt.Sprintf("select * from t order by a into outfile %q compression 'ZSTD'", uri))
+	c.Assert(readCompressed(filepath.Join(dir, "result.csv.zst"), zstdReader), Equals, expected)
+	err := tk.ExecToErr(fmt.Sprintf("select * from t into outfile %q", uri))
+	c.Assert(err, ErrorMatches, ".*File '"+uri+"' already exists")
+	tk.MustExec(fmt.Sprintf("select * from t order by a into outfile %q", "local://"+filepath.ToSlash(dir)+"/result.csv"))
+	cmpAndRm(expected, filepath.Join(dir, "result.csv"), c)
+
+	err = tk.ExecToErr(fmt.Sprintf("select * from t into outfile %q compression 'lz4'", filepath.Join(dir, "result.lz4")))
+	c.Assert(err, ErrorMatches, "unsupported compression type lz4 of select into outfile")
+	err = tk.ExecToErr(fmt.Sprintf("select * from t into outfile %q", "local://"+filepath.ToSlash(dir)+"/"))
+	c.Assert(err, ErrorMatches, "the file name of select into outfile .* is empty")
+}
+
 func (s *testSuite1) TestSelectIntoOutfileTypes(c *C) {
 	outfile := randomSelectFilePath("TestSelectIntoOutfileTypes")
 	tk := testkit.NewTestKit(c, s.store)
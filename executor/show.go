@@ -50,6 +50,7 @@ import (
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/helper"
@@ -179,6 +180,10 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowWarnings(false)
 	case ast.ShowErrors:
 		return e.fetchShowWarnings(true)
+	case ast.ShowLastDMLErrors:
+		return e.fetchShowLastDMLErrors()
+	case ast.ShowSessionStates:
+		return e.fetchShowSessionStates(ctx)
 	case ast.ShowProcessList:
 		return e.fetchShowProcessList()
 	case ast.ShowEvents:
@@ -201,6 +206,8 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return nil
 	case ast.ShowColumnStatsUsage:
 		return e.fetchShowColumnStatsUsage()
+	case ast.ShowStatsDump:
+		return e.fetchShowStatsDump()
 	case ast.ShowPlugins:
 		return e.fetchShowPlugins()
 	case ast.ShowProfiles:
@@ -212,8 +219,7 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 	case ast.ShowBindings:
 		return e.fetchShowBind()
 	case ast.ShowAnalyzeStatus:
-		e.fetchShowAnalyzeStatus()
-		return nil
+		return e.fetchShowAnalyzeStatus(ctx)
 	case ast.ShowRegions:
 		return e.fetchShowTableRegions()
 	case ast.ShowBuiltins:
@@ -406,7 +412,7 @@ func (e *ShowExec) fetchShowProcessList() error {
 		if !hasProcessPriv && pi.User != loginUser.Username {
 			continue
 		}
-		row := pi.ToRowForShow(e.Full)
+		row := desensitizeProcessInfo(pi, loginUser).ToRowForShow(e.Full)
 		e.appendRow(row)
 	}
 	return nil
@@ -830,7 +836,11 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 	case model.TempTableLocal:
 		fmt.Fprintf(buf, "CREATE TEMPORARY TABLE %s (\n", tableName)
 	default:
-		fmt.Fprintf(buf, "CREATE TABLE %s (\n", tableName)
+		if tableInfo.ExternalTable != nil {
+			fmt.Fprintf(buf, "CREATE EXTERNAL TABLE %s (\n", tableName)
+		} else {
+			fmt.Fprintf(buf, "CREATE TABLE %s (\n", tableName)
+		}
 	}
 	var pkCol *model.ColumnInfo
 	var hasAutoIncID bool
@@ -1073,6 +1083,10 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		fmt.Fprintf(buf, " ON COMMIT DELETE ROWS")
 	}
 
+	if tableInfo.GCLifeTime != "" {
+		fmt.Fprintf(buf, " /*T![gc_life_time] GC_LIFE_TIME='%s' */", tableInfo.GCLifeTime)
+	}
+
 	if tableInfo.PlacementPolicyRef != nil {
 		fmt.Fprintf(buf, " /*T![placement] PLACEMENT POLICY=%s */", stringutil.Escape(tableInfo.PlacementPolicyRef.Name.String(), sqlMode))
 	}
@@ -1087,6 +1101,10 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 	appendDirectPlacementInfo(tableInfo.DirectPlacementOpts, buf)
 	// add partition info here.
 	appendPartitionInfo(tableInfo.Partition, buf, sqlMode)
+
+	if tableInfo.ExternalTable != nil {
+		fmt.Fprintf(buf, " LOCATION '%s' FORMAT %s", format.OutputFormat(redactExternalTableLocation(tableInfo.ExternalTable.Location)), tableInfo.ExternalTable.Format)
+	}
 	return nil
 }
 
@@ -1439,7 +1457,8 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
 
-	stmt, err := exec.ParseWithParamsInternal(ctx, `SELECT plugin FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, strings.ToLower(hostName))
+	stmt, err := exec.ParseWithParamsInternal(ctx, `SELECT plugin, Account_locked, Password_expired, Password_lifetime, Password_reuse_history, Password_reuse_time, Failed_login_attempts, Password_lock_time FROM %n.%n WHERE User=%? AND Host=%?`,
+		mysql.SystemDB, mysql.UserTable, userName, strings.ToLower(hostName))
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -1458,6 +1477,7 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 	if len(rows) == 1 && rows[0].GetString(0) != "" {
 		authplugin = rows[0].GetString(0)
 	}
+	passwordOrLock := showPasswordOrLockOptions(rows[0])
 
 	stmt, err = exec.ParseWithParamsInternal(ctx, `SELECT Priv FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.GlobalPrivTable, userName, hostName)
 	if err != nil {
@@ -1481,17 +1501,54 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 
 	authData := checker.GetEncodedPassword(e.User.Username, e.User.Hostname)
 	authStr := ""
-	if !(authplugin == mysql.AuthSocket && authData == "") {
+	if !((authplugin == mysql.AuthSocket || authplugin == mysql.AuthLDAPSimple || authplugin == mysql.AuthLDAPSASL) && authData == "") {
 		authStr = fmt.Sprintf(" AS '%s'", authData)
 	}
 
 	// FIXME: the returned string is not escaped safely
-	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s'%s REQUIRE %s PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
-		e.User.Username, e.User.Hostname, authplugin, authStr, require)
+	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s'%s REQUIRE %s%s",
+		e.User.Username, e.User.Hostname, authplugin, authStr, require, passwordOrLock)
 	e.appendRow([]interface{}{showStr})
 	return nil
 }
 
+// showPasswordOrLockOptions restores the password and lock options of a mysql.user row which contains the columns
+// plugin, Account_locked, Password_expired, Password_lifetime, Password_reuse_history, Password_reuse_time,
+// Failed_login_attempts and Password_lock_time. The options which are not set are omitted except the expiration and lock.
+func showPasswordOrLockOptions(row chunk.Row) string {
+	var sb strings.Builder
+	switch {
+	case row.GetEnum(2).String() == "Y":
+		sb.WriteString(" PASSWORD EXPIRE")
+	case row.IsNull(3):
+		sb.WriteString(" PASSWORD EXPIRE DEFAULT")
+	case row.GetInt64(3) == 0:
+		sb.WriteString(" PASSWORD EXPIRE NEVER")
+	default:
+		fmt.Fprintf(&sb, " PASSWORD EXPIRE INTERVAL %d DAY", row.GetInt64(3))
+	}
+	if row.GetEnum(1).String() == "Y" {
+		sb.WriteString(" ACCOUNT LOCK")
+	} else {
+		sb.WriteString(" ACCOUNT UNLOCK")
+	}
+	if !row.IsNull(4) {
+		fmt.Fprintf(&sb, " PASSWORD HISTORY %d", row.GetInt64(4))
+	}
+	if !row.IsNull(5) {
+		fmt.Fprintf(&sb, " PASSWORD REUSE INTERVAL %d DAY", row.GetInt64(5))
+	}
+	if row.GetInt64(6) > 0 || row.GetInt64(7) != 0 {
+		fmt.Fprintf(&sb, " FAILED_LOGIN_ATTEMPTS %d", row.GetInt64(6))
+		if row.GetInt64(7) < 0 {
+			sb.WriteString(" PASSWORD_LOCK_TIME UNBOUNDED")
+		} else {
+			fmt.Fprintf(&sb, " PASSWORD_LOCK_TIME %d", row.GetInt64(7))
+		}
+	}
+	return sb.String()
+}
+
 func (e *ShowExec) fetchShowGrants() error {
 	vars := e.ctx.GetSessionVars()
 	checker := privilege.GetPrivilegeManager(e.ctx)
@@ -1610,6 +1667,59 @@ func (e *ShowExec) fetchShowWarnings(errOnly bool) error {
 	return nil
 }
 
+// fetchShowLastDMLErrors gets the rows rejected by the last DML statement which collected them.
+func (e *ShowExec) fetchShowLastDMLErrors() error {
+	for _, dmlErr := range e.ctx.GetSessionVars().LastDMLErrors {
+		err := errors.Cause(dmlErr.Err)
+		switch x := err.(type) {
+		case *terror.Error:
+			sqlErr := terror.ToSQLError(x)
+			e.appendRow([]interface{}{int64(sqlErr.Code), sqlErr.Message, dmlErr.Row})
+		default:
+			e.appendRow([]interface{}{int64(mysql.ErrUnknown), err.Error(), dmlErr.Row})
+		}
+	}
+	return nil
+}
+
+// fetchShowSessionStates gets the states of the current session and the token to authenticate on another server,
+// which are used to migrate the session.
+func (e *ShowExec) fetchShowSessionStates(ctx context.Context) error {
+	sessionStates := &sessionstates.SessionStates{}
+	if err := e.ctx.EncodeSessionStates(ctx, sessionStates); err != nil {
+		return err
+	}
+	stateBytes, err := gjson.Marshal(sessionStates)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var stateJSON json.BinaryJSON
+	if err = stateJSON.UnmarshalJSON(stateBytes); err != nil {
+		return err
+	}
+	// The token is NULL if the signing certificate isn't configured, then the user has to be authenticated by the
+	// password on the new server.
+	var tokenJSON interface{}
+	user := e.ctx.GetSessionVars().User
+	if len(config.GetGlobalConfig().Security.SessionTokenSigningCert) > 0 && user != nil {
+		token, err := sessionstates.CreateSessionToken(user.Username)
+		if err != nil {
+			return err
+		}
+		tokenBytes, err := gjson.Marshal(token)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var bj json.BinaryJSON
+		if err = bj.UnmarshalJSON(tokenBytes); err != nil {
+			return err
+		}
+		tokenJSON = bj
+	}
+	e.appendRow([]interface{}{stateJSON, tokenJSON})
+	return nil
+}
+
 // fetchShowPumpOrDrainerStatus gets status of all pumps or drainers and fill them into e.rows.
 func (e *ShowExec) fetchShowPumpOrDrainerStatus(kind string) error {
 	registry, err := createRegistry(config.GetGlobalConfig().Path)
@@ -15,6 +15,8 @@
 package executor
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -306,7 +308,7 @@ func (e *ShowExec) appendTableForStatsTopN(dbName, tblName, partitionName string
 	}
 	colNameToType := make(map[string]byte, len(statsTbl.Columns))
 	for _, col := range stableColsStats(statsTbl.Columns) {
-		err := e.topNToRows(dbName, tblName, partitionName, col.Info.Name.O, 1, 0, col.TopN, []byte{col.Histogram.Tp.Tp})
+		err := e.topNToRows(dbName, tblName, partitionName, col.Info.Name.O, 1, 0, &col.Histogram, col.TopN, col.StatsVer, []byte{col.Histogram.Tp.Tp})
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -317,7 +319,7 @@ func (e *ShowExec) appendTableForStatsTopN(dbName, tblName, partitionName string
 		for i := 0; i < len(idx.Info.Columns); i++ {
 			idxColumnTypes = append(idxColumnTypes, colNameToType[idx.Info.Columns[i].Name.O])
 		}
-		err := e.topNToRows(dbName, tblName, partitionName, idx.Info.Name.O, len(idx.Info.Columns), 1, idx.TopN, idxColumnTypes)
+		err := e.topNToRows(dbName, tblName, partitionName, idx.Info.Name.O, len(idx.Info.Columns), 1, &idx.Histogram, idx.TopN, idx.StatsVer, idxColumnTypes)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -341,10 +343,12 @@ func stableIdxsStats(idxStats map[int64]*statistics.Index) (idxs []*statistics.I
 	return
 }
 
-func (e *ShowExec) topNToRows(dbName, tblName, partitionName, colName string, numOfCols int, isIndex int, topN *statistics.TopN, columnTypes []byte) error {
+// topNToRows converts the TopN to rows, each of which also carries the skew metrics of the column or index.
+func (e *ShowExec) topNToRows(dbName, tblName, partitionName, colName string, numOfCols int, isIndex int, hist *statistics.Histogram, topN *statistics.TopN, statsVer int64, columnTypes []byte) error {
 	if topN == nil {
 		return nil
 	}
+	coverage, skewRatio := statistics.TopNSkewMetrics(hist, topN, int(statsVer))
 	var tmpDatum types.Datum
 	for i := 0; i < len(topN.TopN); i++ {
 		tmpDatum.SetBytes(topN.TopN[i].Encoded)
@@ -360,6 +364,8 @@ func (e *ShowExec) topNToRows(dbName, tblName, partitionName, colName string, nu
 			isIndex,
 			valStr,
 			topN.TopN[i].Count,
+			coverage,
+			skewRatio,
 		})
 	}
 	return nil
@@ -426,15 +432,10 @@ func (e *ShowExec) fetchShowStatsHealthy() {
 }
 
 func (e *ShowExec) appendTableForStatsHealthy(dbName, tblName, partitionName string, statsTbl *statistics.Table) {
-	if statsTbl.Pseudo {
+	healthy, ok := statsTbl.GetStatsHealthy()
+	if !ok {
 		return
 	}
-	var healthy int64
-	if statsTbl.ModifyCount < statsTbl.Count {
-		healthy = int64((1.0 - float64(statsTbl.ModifyCount)/float64(statsTbl.Count)) * 100.0)
-	} else if statsTbl.ModifyCount == 0 {
-		healthy = 100
-	}
 	e.appendRow([]interface{}{
 		dbName,
 		tblName,
@@ -447,13 +448,17 @@ func (e *ShowExec) fetchShowHistogramsInFlight() {
 	e.appendRow([]interface{}{statistics.HistogramNeededColumns.Length()})
 }
 
-func (e *ShowExec) fetchShowAnalyzeStatus() {
-	rows := dataForAnalyzeStatusHelper(e.baseExecutor.ctx)
+func (e *ShowExec) fetchShowAnalyzeStatus(ctx context.Context) error {
+	rows, err := dataForAnalyzeStatusHelper(ctx, e.baseExecutor.ctx)
+	if err != nil {
+		return err
+	}
 	for _, row := range rows {
 		for i := range row {
 			e.result.AppendDatum(i, &row[i])
 		}
 	}
+	return nil
 }
 
 func (e *ShowExec) fetchShowColumnStatsUsage() error {
@@ -512,3 +517,21 @@ func (e *ShowExec) fetchShowColumnStatsUsage() error {
 	}
 	return nil
 }
+
+func (e *ShowExec) fetchShowStatsDump() error {
+	tbl, err := e.getTable()
+	if err != nil {
+		return err
+	}
+	h := domain.GetDomain(e.ctx).StatsHandle()
+	jsonTbl, err := h.DumpStatsToJSON(e.Table.Schema.O, tbl.Meta(), nil)
+	if err != nil {
+		return err
+	}
+	js, err := json.Marshal(jsonTbl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	e.appendRow([]interface{}{e.Table.Schema.O, tbl.Meta().Name.O, string(js)})
+	return nil
+}
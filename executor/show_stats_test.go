@@ -16,11 +16,14 @@ package executor_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/testkit"
@@ -356,12 +359,52 @@ func TestShowHistogramsInFlight(t *testing.T) {
 	require.Equal(t, rows[0][0], "0")
 }
 
+func TestShowStatsDumpAndLoadStats(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(10), index idx(a))")
+	tk.MustExec("insert into t values (1, 'a'), (2, 'b'), (3, 'c'), (3, 'c')")
+	tk.MustExec("analyze table t with 1 topn")
+	meta := tk.MustQuery("show stats_meta where table_name = 't'").Rows()
+	require.Len(t, meta, 1)
+	buckets := tk.MustQuery("show stats_buckets where table_name = 't'").Sort().Rows()
+	topn := tk.MustQuery("show stats_topn where table_name = 't'").Sort().Rows()
+	require.NotEmpty(t, buckets)
+	require.NotEmpty(t, topn)
+
+	rows := tk.MustQuery("show stats_dump t").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "test", rows[0][0])
+	require.Equal(t, "t", rows[0][1])
+	js := rows[0][2].(string)
+	require.Contains(t, js, `"database_name":"test","table_name":"t"`)
+
+	// The stats are restored after the table is recreated.
+	tk.MustExec("drop table t")
+	tk.MustExec("create table t (a int, b varchar(10), index idx(a))")
+	tk.MustQuery("show stats_buckets where table_name = 't'").Check(testkit.Rows())
+	tk.MustExec(fmt.Sprintf("load stats from '%s'", strings.ReplaceAll(js, `\`, `\\`)))
+	rows = tk.MustQuery("show stats_meta where table_name = 't'").Rows()
+	require.Len(t, rows, 1)
+	// Modify_count and Row_count
+	require.Equal(t, meta[0][4:], rows[0][4:])
+	tk.MustQuery("show stats_buckets where table_name = 't'").Sort().Check(buckets)
+	tk.MustQuery("show stats_topn where table_name = 't'").Sort().Check(topn)
+
+	tk.MustGetErrCode("show stats_dump t_not_exists", mysql.ErrNoSuchTable)
+	require.Error(t, tk.ExecToErr("load stats from 'invalid json'"))
+}
+
 func TestShowAnalyzeStatus(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
 
 	tk := testkit.NewTestKit(t, store)
-	statistics.ClearHistoryJobs()
+	tk.MustExec("delete from mysql.analyze_jobs")
 	tk.MustExec("use test")
 	tk.MustExec("drop table if exists t")
 	tk.MustExec("create table t (a int, b int, primary key(a), index idx(b))")
@@ -380,7 +423,7 @@ func TestShowAnalyzeStatus(t *testing.T) {
 	require.NotNil(t, result.Rows()[0][6])
 	require.Equal(t, "finished", result.Rows()[0][7])
 
-	statistics.ClearHistoryJobs()
+	tk.MustExec("delete from mysql.analyze_jobs")
 
 	tk.MustExec("set @@tidb_analyze_version=1")
 	tk.MustExec("analyze table t")
@@ -405,3 +448,52 @@ func TestShowAnalyzeStatus(t *testing.T) {
 	require.NotNil(t, result.Rows()[1][6])
 	require.Equal(t, "finished", result.Rows()[1][7])
 }
+
+func TestKillAnalyze(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("delete from mysql.analyze_jobs")
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, index idx(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tk.MustExec("set @@tidb_analyze_version=2")
+	tk.MustExec("analyze table t")
+	rows := tk.MustQuery("select job_id, instance, process_id, state, phase, estimated_total_rows, remaining_seconds, fail_reason from information_schema.analyze_status").Rows()
+	require.Len(t, rows, 1)
+	finishedID, instance := rows[0][0].(string), rows[0][1].(string)
+	require.Equal(t, fmt.Sprintf("%d", tk.Session().GetSessionVars().ConnectionID), rows[0][2])
+	require.Equal(t, []interface{}{"finished", statistics.AnalyzePhaseSaving, "0", "<nil>", "<nil>"}, rows[0][3:])
+
+	tk.MustGetErrMsg("kill analyze "+finishedID, fmt.Sprintf("[executor:8256]Failed to kill analyze job %s: the job has been finished", finishedID))
+	tk.MustGetErrMsg("kill analyze 100000", "[executor:8256]Failed to kill analyze job 100000: unknown job")
+
+	h := dom.StatsHandle()
+	remoteJob := &statistics.AnalyzeJob{DBName: "test", TableName: "t", JobInfo: "analyze table"}
+	require.NoError(t, h.InsertAnalyzeJob(remoteJob, "127.0.0.2:4000", 1))
+	tk.MustGetErrMsg(fmt.Sprintf("kill analyze %d", remoteJob.ID),
+		fmt.Sprintf("[executor:8256]Failed to kill analyze job %d: the job is running on 127.0.0.2:4000, please execute KILL ANALYZE on that instance", remoteJob.ID))
+
+	job := &statistics.AnalyzeJob{DBName: "test", TableName: "t", JobInfo: "analyze table", EstimatedRows: 100}
+	require.NoError(t, h.InsertAnalyzeJob(job, instance, 1))
+	statistics.AddNewAnalyzeJob(job)
+	defer statistics.RemoveAnalyzeJob(job)
+	job.Start()
+	job.StartTime = job.StartTime.Add(-10 * time.Second)
+	require.NoError(t, h.StartAnalyzeJob(job))
+	job.Update(20)
+	rows = tk.MustQuery(fmt.Sprintf("select processed_rows, state, phase, estimated_total_rows, remaining_seconds > 0 from information_schema.analyze_status where job_id = %d", job.ID)).Rows()
+	require.Equal(t, []interface{}{"20", "running", statistics.AnalyzePhaseScanning, "100", "1"}, rows[0])
+
+	tk.MustExec(fmt.Sprintf("kill analyze %d", job.ID))
+	require.True(t, job.Killed())
+	job.Finish(executor.ErrAnalyzeJobKilled.GenWithStackByArgs(job.ID))
+	require.NoError(t, h.FinishAnalyzeJob(job))
+	tk.MustQuery(fmt.Sprintf("select processed_rows, state, fail_reason from mysql.analyze_jobs where id = %d", job.ID)).Check(
+		testkit.Rows(fmt.Sprintf("20 failed [executor:8255]Analyze job %d is killed", job.ID)))
+
+	tk.MustExec("delete from mysql.analyze_jobs")
+	tk.MustQuery("show analyze status").Check(testkit.Rows())
+}
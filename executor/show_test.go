@@ -478,6 +478,12 @@ func (s *testSuite5) TestShowCreateUser(c *C) {
 	rows = tk.MustQuery("SHOW CREATE USER 'sock'@'%'")
 	c.Assert(rows.Rows()[0][0].(string), check.Equals, "CREATE USER 'sock'@'%' IDENTIFIED WITH 'auth_socket' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK")
 
+	// The password and lock options are shown.
+	tk.MustExec("CREATE USER 'pwd_opts'@'%' PASSWORD EXPIRE NEVER ACCOUNT LOCK PASSWORD HISTORY 3 PASSWORD REUSE INTERVAL 10 DAY FAILED_LOGIN_ATTEMPTS 2 PASSWORD_LOCK_TIME 5")
+	tk.MustQuery("SHOW CREATE USER 'pwd_opts'@'%'").Check(testkit.Rows("CREATE USER 'pwd_opts'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE NEVER ACCOUNT LOCK PASSWORD HISTORY 3 PASSWORD REUSE INTERVAL 10 DAY FAILED_LOGIN_ATTEMPTS 2 PASSWORD_LOCK_TIME 5"))
+	tk.MustExec("ALTER USER 'pwd_opts'@'%' PASSWORD EXPIRE INTERVAL 90 DAY PASSWORD HISTORY DEFAULT PASSWORD_LOCK_TIME UNBOUNDED")
+	tk.MustQuery("SHOW CREATE USER 'pwd_opts'@'%'").Check(testkit.Rows("CREATE USER 'pwd_opts'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE INTERVAL 90 DAY ACCOUNT LOCK PASSWORD REUSE INTERVAL 10 DAY FAILED_LOGIN_ATTEMPTS 2 PASSWORD_LOCK_TIME UNBOUNDED"))
+
 	tk.MustExec("CREATE USER 'sock2'@'%' IDENTIFIED WITH 'auth_socket' AS 'sock3'")
 
 	// Compare only the start of the output as the salt changes every time.
@@ -1003,6 +1009,39 @@ func (s *testSuite5) TestShowCreateTable(c *C) {
 	))
 }
 
+func (s *testSuite5) TestShowCreateTableAsOf(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_as_of")
+
+	timeBeforeDrop, timeAfterDrop, safePointSQL, resetGC := testkit.MockGC(tk)
+	defer resetGC()
+	tk.MustExec(fmt.Sprintf(safePointSQL, timeBeforeDrop))
+
+	tk.MustExec("create table t_as_of (a int)")
+	tk.MustExec("begin")
+	ts := tk.Se.GetSessionVars().TxnCtx.StartTS
+	tk.MustExec("rollback")
+	tk.MustExec("alter table t_as_of add column b int")
+	showSQL := fmt.Sprintf("show create table t_as_of as of timestamp tidb_parse_tso(%d)", ts)
+	tk.MustQuery(showSQL).Check(testkit.Rows("t_as_of CREATE TABLE `t_as_of` (\n" +
+		"  `a` int(11) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	// The dropped table can be shown as it was.
+	tk.MustExec("drop table t_as_of")
+	tk.MustGetErrCode("show create table t_as_of", mysql.ErrNoSuchTable)
+	tk.MustQuery(showSQL).Check(testkit.Rows("t_as_of CREATE TABLE `t_as_of` (\n" +
+		"  `a` int(11) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustGetErrCode("show create table t_as_of as of timestamp now() - interval 1 day", mysql.ErrNoSuchTable)
+
+	tk.MustGetErrMsg("show create table t_as_of as of timestamp now() + interval 1 day", "cannot set read timestamp to a future time")
+	tk.MustExec(fmt.Sprintf(safePointSQL, timeAfterDrop))
+	_, err := tk.Exec(showSQL)
+	c.Assert(variable.ErrSnapshotTooOld.Equal(err), IsTrue)
+}
+
 func (s *testSuite5) TestShowCreateTablePlacement(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -1394,7 +1433,7 @@ func (s *testSuite5) TestShowBuiltin(c *C) {
 	res := tk.MustQuery("show builtins;")
 	c.Assert(res, NotNil)
 	rows := res.Rows()
-	const builtinFuncNum = 274
+	const builtinFuncNum = 291
 	c.Assert(builtinFuncNum, Equals, len(rows))
 	c.Assert("abs", Equals, rows[0][0].(string))
 	c.Assert("yearweek", Equals, rows[builtinFuncNum-1][0].(string))
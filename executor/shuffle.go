@@ -31,7 +31,7 @@ import (
 
 // ShuffleExec is the executor to run other executors in a parallel manner.
 //  1. It fetches chunks from M `DataSources` (value of M depends on the actual executor, e.g. M = 1 for WindowExec, M = 2 for MergeJoinExec).
-//  2. It splits tuples from each `DataSource` into N partitions (Only "split by hash" is implemented so far).
+//  2. It splits tuples from each `DataSource` into N partitions, by hash, or by range if the `DataSource` is sorted.
 //  3. It invokes N workers in parallel, each one has M `receiver` to receive partitions from `DataSources`
 //  4. It assigns partitions received as input to each worker and executes child executors.
 //  5. It collects outputs from each worker, then sends outputs to its parent.
@@ -443,6 +443,9 @@ type partitionRangeSplitter struct {
 	numWorkers   int
 	groupChecker *vecGroupChecker
 	idx          int
+	// prevIdx is the worker index of the last group in the previous chunk.
+	prevIdx int
+	hasPrev bool
 }
 
 func buildPartitionRangeSplitter(ctx sessionctx.Context, concurrency int, byItems []expression.Expression) *partitionRangeSplitter {
@@ -458,7 +461,7 @@ func buildPartitionRangeSplitter(ctx sessionctx.Context, concurrency int, byItem
 // the caller of this method should guarantee that `input` is grouped,
 // which means that rows with the same byItems should be continuous, the order does not matter.
 func (s *partitionRangeSplitter) split(ctx sessionctx.Context, input *chunk.Chunk, workerIndices []int) ([]int, error) {
-	_, err := s.groupChecker.splitIntoGroups(input)
+	isFirstGroupSameAsPrev, err := s.groupChecker.splitIntoGroups(input)
 	if err != nil {
 		return workerIndices, err
 	}
@@ -466,10 +469,17 @@ func (s *partitionRangeSplitter) split(ctx sessionctx.Context, input *chunk.Chun
 	workerIndices = workerIndices[:0]
 	for !s.groupChecker.isExhausted() {
 		begin, end := s.groupChecker.getNextGroup()
+		idx := s.idx
+		if begin == 0 && isFirstGroupSameAsPrev && s.hasPrev {
+			// The group continues from the previous chunk, it must be sent to the same worker.
+			idx = s.prevIdx
+		} else {
+			s.idx = (s.idx + 1) % s.numWorkers
+		}
 		for i := begin; i < end; i++ {
-			workerIndices = append(workerIndices, s.idx)
+			workerIndices = append(workerIndices, idx)
 		}
-		s.idx = (s.idx + 1) % s.numWorkers
+		s.prevIdx, s.hasPrev = idx, true
 	}
 
 	return workerIndices, nil
@@ -63,3 +63,33 @@ func TestPartitionRangeSplitter(t *testing.T) {
 		require.Equal(t, expected[i], obtained[i])
 	}
 }
+
+func TestPartitionRangeSplitterAcrossChunks(t *testing.T) {
+	ctx := mock.NewContext()
+	concurrency := 2
+
+	tp := &types.FieldType{Tp: mysql.TypeVarchar}
+	col0 := &expression.Column{
+		RetType: tp,
+		Index:   0,
+	}
+	byItems := []expression.Expression{col0}
+	splitter := buildPartitionRangeSplitter(ctx, concurrency, byItems)
+
+	input := chunk.New([]*types.FieldType{tp}, 1024, 1024)
+	for _, s := range []string{"a", "a", "b"} {
+		input.Column(0).AppendString(s)
+	}
+	obtained, err := splitter.split(ctx, input, nil)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 0, 1}, obtained)
+
+	// The group "b" continues from the previous chunk, so it is sent to the same worker.
+	input.Reset()
+	for _, s := range []string{"b", "b", "c", "d"} {
+		input.Column(0).AppendString(s)
+	}
+	obtained, err = splitter.split(ctx, input, obtained)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 1, 0, 1}, obtained)
+}
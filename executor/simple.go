@@ -16,8 +16,11 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"os"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -25,6 +28,7 @@ import (
 	"github.com/ngaut/pools"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/errno"
@@ -40,13 +44,19 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/store/helper"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/passwordvalidation"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/timeutil"
@@ -122,6 +132,10 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	switch x := e.Statement.(type) {
 	case *ast.GrantRoleStmt:
 		err = e.executeGrantRole(ctx, x)
+	case *ast.GrantProxyStmt:
+		err = e.executeGrantProxy(ctx, x)
+	case *ast.RevokeProxyStmt:
+		err = e.executeRevokeProxy(ctx, x)
 	case *ast.UseStmt:
 		err = e.executeUse(x)
 	case *ast.FlushStmt:
@@ -133,7 +147,11 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	case *ast.CommitStmt:
 		e.executeCommit(x)
 	case *ast.RollbackStmt:
-		err = e.executeRollback(x)
+		err = e.executeRollback(ctx, x)
+	case *ast.SavepointStmt:
+		err = e.executeSavepoint(x)
+	case *ast.ReleaseSavepointStmt:
+		err = e.executeReleaseSavepoint(x)
 	case *ast.CreateUserStmt:
 		err = e.executeCreateUser(ctx, x)
 	case *ast.AlterUserStmt:
@@ -142,6 +160,10 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		err = e.executeDropUser(ctx, x)
 	case *ast.RenameUserStmt:
 		err = e.executeRenameUser(x)
+	case *ast.CreateFunctionStmt:
+		err = e.executeCreateFunction(ctx, x)
+	case *ast.DropFunctionStmt:
+		err = e.executeDropFunction(ctx, x)
 	case *ast.SetPwdStmt:
 		err = e.executeSetPwd(ctx, x)
 	case *ast.KillStmt:
@@ -151,6 +173,16 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		return nil
 	case *ast.DropStatsStmt:
 		err = e.executeDropStats(x)
+	case *ast.LockStatsStmt:
+		err = e.executeLockStats(x.Tables, true)
+	case *ast.UnlockStatsStmt:
+		err = e.executeLockStats(x.Tables, false)
+	case *ast.SetResourceGroupStmt:
+		err = e.executeSetResourceGroup(x)
+	case *ast.SetSessionStatesStmt:
+		err = e.executeSetSessionStates(ctx, x)
+	case *ast.SetSessionAuthorizationStmt:
+		err = e.executeSetSessionAuthorization(ctx, x)
 	case *ast.SetRoleStmt:
 		err = e.executeSetRole(x)
 	case *ast.RevokeRoleStmt:
@@ -514,6 +546,347 @@ func (e *SimpleExec) setRoleNone(s *ast.SetRoleStmt) error {
 	return nil
 }
 
+// resourceGroupName checks the resource group exists and returns its name, the default group is stored as "".
+func (e *SimpleExec) resourceGroupName(name model.CIStr) (string, error) {
+	if name.L == ddl.DefaultResourceGroupName {
+		return "", nil
+	}
+	if _, ok := e.is.ResourceGroupByName(name); !ok {
+		return "", infoschema.ErrResourceGroupNotExists.GenWithStackByArgs(name.O)
+	}
+	return name.L, nil
+}
+
+// userResourceGroup returns the resource group which the users of CREATE USER and ALTER USER are bound to.
+func (e *SimpleExec) userResourceGroup(opt *ast.ResourceGroupNameOption) (string, error) {
+	if opt == nil {
+		return "", nil
+	}
+	return e.resourceGroupName(model.NewCIStr(opt.Value))
+}
+
+// userMaxConnections returns the MAX_USER_CONNECTIONS of CREATE USER and ALTER USER, ok is false if it's not specified.
+func userMaxConnections(opts []*ast.ResourceOption) (maxConns int64, ok bool) {
+	for _, opt := range opts {
+		if opt.Type == ast.MaxUserConnections {
+			maxConns, ok = opt.Count, true
+		}
+	}
+	return maxConns, ok
+}
+
+// userColumnValues is the values of the columns in mysql.user, in the order they are set.
+type userColumnValues struct {
+	columns []string
+	values  []interface{}
+}
+
+func (c *userColumnValues) set(column string, value interface{}) {
+	for i, col := range c.columns {
+		if col == column {
+			c.values[i] = value
+			return
+		}
+	}
+	c.columns = append(c.columns, column)
+	c.values = append(c.values, value)
+}
+
+func (c *userColumnValues) get(column string) (interface{}, bool) {
+	for i, col := range c.columns {
+		if col == column {
+			return c.values[i], true
+		}
+	}
+	return nil, false
+}
+
+// passwordOrLockColumns converts the password and lock options of CREATE USER and ALTER USER to the columns
+// in mysql.user, the later options override the former ones like MySQL. A nil value means the default.
+func passwordOrLockColumns(opts []*ast.PasswordOrLockOption) (*userColumnValues, error) {
+	checkRange := func(name string, value, min, max int64) error {
+		if value < min || value > max {
+			return types.ErrWrongValue.GenWithStackByArgs(name, strconv.FormatInt(value, 10))
+		}
+		return nil
+	}
+	c := &userColumnValues{}
+	for _, opt := range opts {
+		var err error
+		switch opt.Type {
+		case ast.Lock:
+			c.set("Account_locked", "Y")
+		case ast.Unlock:
+			c.set("Account_locked", "N")
+		case ast.PasswordExpire:
+			c.set("Password_expired", "Y")
+		case ast.PasswordExpireDefault:
+			c.set("Password_lifetime", nil)
+		case ast.PasswordExpireNever:
+			c.set("Password_lifetime", 0)
+		case ast.PasswordExpireInterval:
+			err = checkRange("PASSWORD EXPIRE INTERVAL", opt.Count, 1, math.MaxUint16)
+			c.set("Password_lifetime", opt.Count)
+		case ast.FailedLoginAttempts:
+			err = checkRange("FAILED_LOGIN_ATTEMPTS", opt.Count, 0, math.MaxInt16)
+			c.set("Failed_login_attempts", opt.Count)
+		case ast.PasswordLockTime:
+			err = checkRange("PASSWORD_LOCK_TIME", opt.Count, 0, math.MaxInt16)
+			c.set("Password_lock_time", opt.Count)
+		case ast.PasswordLockTimeUnbounded:
+			c.set("Password_lock_time", -1)
+		case ast.PasswordHistory:
+			err = checkRange("PASSWORD HISTORY", opt.Count, 0, math.MaxUint16)
+			c.set("Password_reuse_history", opt.Count)
+		case ast.PasswordHistoryDefault:
+			c.set("Password_reuse_history", nil)
+		case ast.PasswordReuseInterval:
+			err = checkRange("PASSWORD REUSE INTERVAL", opt.Count, 0, math.MaxUint16)
+			c.set("Password_reuse_time", opt.Count)
+		case ast.PasswordReuseDefault:
+			c.set("Password_reuse_time", nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// validatePassword checks the password against the rules of the validate_password component.
+func (e *SimpleExec) validatePassword(userName, pwd string) error {
+	opts, err := passwordvalidation.LoadOptions(e.ctx.GetSessionVars().GlobalVarsAccessor)
+	if err != nil || opts == nil {
+		return err
+	}
+	if err = opts.Validate(userName, pwd); err != nil {
+		return ErrNotValidPassword.GenWithStackByArgs(err.Error())
+	}
+	return nil
+}
+
+// passwordReusePolicy returns the number of the recent passwords and the days in which the passwords
+// can't be reused. The global password_history and password_reuse_interval are used for the NULL values.
+func (e *SimpleExec) passwordReusePolicy(history, interval interface{}) (int64, int64, error) {
+	policy := make([]int64, 0, 2)
+	for _, v := range []struct {
+		name  string
+		value interface{}
+	}{{variable.PasswordHistory, history}, {variable.PasswordReuseInterval, interval}} {
+		if v.value != nil {
+			policy = append(policy, v.value.(int64))
+			continue
+		}
+		val, err := e.ctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(v.name)
+		if err != nil {
+			return 0, 0, err
+		}
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return 0, 0, errors.Trace(err)
+		}
+		policy = append(policy, n)
+	}
+	return policy[0], policy[1], nil
+}
+
+// userPasswordReusePolicy returns the password reuse policy of the user.
+func (e *SimpleExec) userPasswordReusePolicy(ctx context.Context, user, host string) (int64, int64, error) {
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParamsInternal(ctx, `SELECT Password_reuse_history, Password_reuse_time FROM %n.%n WHERE User=%? AND Host=%?`,
+		mysql.SystemDB, mysql.UserTable, user, strings.ToLower(host))
+	if err != nil {
+		return 0, 0, err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return 0, 0, err
+	}
+	var history, interval interface{}
+	if len(rows) > 0 {
+		if !rows[0].IsNull(0) {
+			history = rows[0].GetInt64(0)
+		}
+		if !rows[0].IsNull(1) {
+			interval = rows[0].GetInt64(1)
+		}
+	}
+	return e.passwordReusePolicy(history, interval)
+}
+
+// passwordMatchesHash returns whether the hash in the password history is generated from the password.
+func passwordMatchesHash(pwd, hash string) bool {
+	if len(hash) == mysql.SHAPWDHashLen {
+		// The digest types of caching_sha2_password and tidb_sm3_password are different, the check fails with
+		// an error if the hash is of the other plugin.
+		if ok, err := auth.CheckShaPassword([]byte(hash), pwd); err == nil {
+			return ok
+		}
+		ok, err := auth.CheckSM3Password([]byte(hash), pwd)
+		return err == nil && ok
+	}
+	return auth.EncodePassword(pwd) == hash
+}
+
+// checkPasswordHistory returns an error if the password is one of the latest `history` passwords of the user,
+// or it's used in the last `interval` days.
+func checkPasswordHistory(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, user, host, pwd string, history, interval int64) error {
+	if history == 0 && interval == 0 {
+		return nil
+	}
+	stmt, err := exec.ParseWithParamsInternal(ctx,
+		`SELECT Password, Password_timestamp >= DATE_SUB(NOW(6), INTERVAL %? DAY) FROM %n.%n WHERE Host=%? AND User=%? ORDER BY Password_timestamp DESC`,
+		interval, mysql.SystemDB, mysql.PasswordHistoryTable, strings.ToLower(host), user)
+	if err != nil {
+		return err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	for i, row := range rows {
+		if int64(i) >= history && row.GetInt64(1) == 0 {
+			// The rows are sorted from the newest, so the rest are neither restricted.
+			break
+		}
+		if passwordMatchesHash(pwd, row.GetString(0)) {
+			return ErrPasswordContradictHistory.GenWithStackByArgs(user, host)
+		}
+	}
+	return nil
+}
+
+// recordPasswordHistory records the new password of the user, and removes the passwords which are
+// no longer restricted by the password reuse policy.
+func recordPasswordHistory(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, user, host, pwd string, history, interval int64) error {
+	host = strings.ToLower(host)
+	execSQL := func(sql string, args ...interface{}) ([]chunk.Row, error) {
+		stmt, err := exec.ParseWithParamsInternal(ctx, sql, args...)
+		if err != nil {
+			return nil, err
+		}
+		rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+		return rows, err
+	}
+	if history == 0 && interval == 0 {
+		_, err := execSQL(`DELETE FROM %n.%n WHERE Host=%? AND User=%?`, mysql.SystemDB, mysql.PasswordHistoryTable, host, user)
+		return err
+	}
+	if _, err := execSQL(`INSERT INTO %n.%n (Host, User, Password) VALUES (%?, %?, %?)`, mysql.SystemDB, mysql.PasswordHistoryTable, host, user, pwd); err != nil {
+		return err
+	}
+	if history == 0 {
+		_, err := execSQL(`DELETE FROM %n.%n WHERE Host=%? AND User=%? AND Password_timestamp < DATE_SUB(NOW(6), INTERVAL %? DAY)`,
+			mysql.SystemDB, mysql.PasswordHistoryTable, host, user, interval)
+		return err
+	}
+	// The passwords older than the latest `history` ones are removed if they are out of the interval.
+	rows, err := execSQL(`SELECT Password_timestamp FROM %n.%n WHERE Host=%? AND User=%? ORDER BY Password_timestamp DESC LIMIT %?, 1`,
+		mysql.SystemDB, mysql.PasswordHistoryTable, host, user, history-1)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	_, err = execSQL(`DELETE FROM %n.%n WHERE Host=%? AND User=%? AND Password_timestamp < %? AND Password_timestamp < DATE_SUB(NOW(6), INTERVAL %? DAY)`,
+		mysql.SystemDB, mysql.PasswordHistoryTable, host, user, rows[0].GetTime(0).String(), interval)
+	return err
+}
+
+func (e *SimpleExec) executeSetResourceGroup(s *ast.SetResourceGroupStmt) error {
+	name, err := e.resourceGroupName(s.Name)
+	if err != nil {
+		return err
+	}
+	e.ctx.GetSessionVars().ResourceGroupName = name
+	return nil
+}
+
+func (e *SimpleExec) executeSetSessionStates(ctx context.Context, s *ast.SetSessionStatesStmt) error {
+	var sessionStates sessionstates.SessionStates
+	if err := json.Unmarshal(hack.Slice(s.SessionStates), &sessionStates); err != nil {
+		return errors.Trace(err)
+	}
+	return e.ctx.DecodeSessionStates(ctx, &sessionStates)
+}
+
+// executeSetSessionAuthorization switches the session to act as another user, which requires the login user to
+// have the PROXY privilege on that user. SET SESSION AUTHORIZATION DEFAULT switches back to the login user.
+// The privileges are always checked against the login user, so a failed switch leaves the session as the login user.
+func (e *SimpleExec) executeSetSessionAuthorization(ctx context.Context, s *ast.SetSessionAuthorizationStmt) error {
+	checker := privilege.GetPrivilegeManager(e.ctx)
+	if checker == nil {
+		return errors.New("miss privilege checker")
+	}
+	sessionVars := e.ctx.GetSessionVars()
+	if sessionVars.User == nil {
+		return nil
+	}
+	loginUser := sessionVars.User
+	if sessionVars.ProxyUser != nil {
+		loginUser = sessionVars.ProxyUser
+		if !checker.GetAuthWithoutVerification(loginUser.AuthUsername, loginUser.AuthHostname) {
+			return ErrPasswordNoMatch
+		}
+		sessionVars.User, sessionVars.ProxyUser = loginUser, nil
+		sessionVars.ActiveRoles = checker.GetLoginRoles(loginUser.AuthUsername, loginUser.AuthHostname)
+		sessionVars.ResourceGroupName = checker.GetUserResourceGroup(loginUser.AuthUsername, loginUser.AuthHostname)
+	}
+
+	if s.User != nil {
+		proxiedUser := &auth.UserIdentity{Username: s.User.Username, Hostname: strings.ToLower(s.User.Hostname)}
+		if s.User.CurrentUser {
+			proxiedUser.Username, proxiedUser.Hostname = loginUser.AuthUsername, loginUser.AuthHostname
+		}
+		exists, err := userExists(ctx, e.ctx, proxiedUser.Username, proxiedUser.Hostname)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrPasswordNoMatch
+		}
+		activeRoles := sessionVars.ActiveRoles
+		if !checker.RequestProxyVerification(activeRoles, proxiedUser, false) {
+			return core.ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or PROXY")
+		}
+		// Acting as a user with SYSTEM_USER or RESTRICTED_USER_ADMIN requires the same privileges as ALTER USER.
+		if checker.RequestDynamicVerificationWithUser("SYSTEM_USER", false, proxiedUser) &&
+			!(checker.RequestDynamicVerification(activeRoles, "SYSTEM_USER", false) || checker.RequestDynamicVerification(activeRoles, "RESTRICTED_USER_ADMIN", false)) {
+			return core.ErrSpecificAccessDenied.GenWithStackByArgs("SYSTEM_USER or SUPER")
+		}
+		if sem.IsEnabled() && checker.RequestDynamicVerificationWithUser("RESTRICTED_USER_ADMIN", false, proxiedUser) &&
+			!checker.RequestDynamicVerification(activeRoles, "RESTRICTED_USER_ADMIN", false) {
+			return core.ErrSpecificAccessDenied.GenWithStackByArgs("RESTRICTED_USER_ADMIN")
+		}
+		if !checker.GetAuthWithoutVerification(proxiedUser.Username, proxiedUser.Hostname) {
+			return ErrPasswordNoMatch
+		}
+		sessionVars.User = &auth.UserIdentity{
+			Username:     proxiedUser.Username,
+			Hostname:     loginUser.Hostname,
+			AuthUsername: proxiedUser.Username,
+			AuthHostname: proxiedUser.Hostname,
+		}
+		sessionVars.ProxyUser = loginUser
+		sessionVars.ActiveRoles = checker.GetLoginRoles(proxiedUser.Username, proxiedUser.Hostname)
+		sessionVars.ResourceGroupName = checker.GetUserResourceGroup(proxiedUser.Username, proxiedUser.Hostname)
+	}
+
+	logutil.BgLogger().Info("set session authorization", zap.Uint64("conn", sessionVars.ConnectionID),
+		zap.Stringer("user", sessionVars.User), zap.Stringer("login user", loginUser))
+	return plugin.ForeachPlugin(plugin.Audit, func(p *plugin.Plugin) error {
+		auditPlugin := plugin.DeclareAuditManifest(p.Manifest)
+		if auditPlugin.OnConnectionEvent == nil || sessionVars.ConnectionInfo == nil {
+			return nil
+		}
+		connInfo := *sessionVars.ConnectionInfo
+		connInfo.User = sessionVars.User.Username
+		connInfo.ProxyUser = ""
+		if sessionVars.ProxyUser != nil {
+			connInfo.ProxyUser = sessionVars.ProxyUser.Username
+		}
+		return auditPlugin.OnConnectionEvent(context.Background(), plugin.ChangeUser, &connInfo)
+	})
+}
+
 func (e *SimpleExec) executeSetRole(s *ast.SetRoleStmt) error {
 	switch s.SetRoleOpt {
 	case ast.SetRoleRegular:
@@ -729,7 +1102,10 @@ func (e *SimpleExec) executeCommit(s *ast.CommitStmt) {
 	e.ctx.GetSessionVars().SetInTxn(false)
 }
 
-func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
+func (e *SimpleExec) executeRollback(ctx context.Context, s *ast.RollbackStmt) error {
+	if s.SavepointName != "" {
+		return e.executeRollbackToSavepoint(ctx, s)
+	}
 	sessVars := e.ctx.GetSessionVars()
 	logutil.BgLogger().Debug("execute rollback statement", zap.Uint64("conn", sessVars.ConnectionID))
 	sessVars.SetInTxn(false)
@@ -750,6 +1126,76 @@ func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
 	return nil
 }
 
+func (e *SimpleExec) executeSavepoint(s *ast.SavepointStmt) error {
+	sessVars := e.ctx.GetSessionVars()
+	// Like MySQL, the savepoint is useless if the statement is committed at once.
+	if !sessVars.InTxn() && sessVars.IsAutocommit() {
+		return nil
+	}
+	if _, err := e.ctx.Txn(true); err != nil {
+		return err
+	}
+	sessVars.TxnCtx.AddSavepoint(s.Name)
+	return nil
+}
+
+// executeRollbackToSavepoint restores the values in the memory buffer of the keys modified after the savepoint. The
+// keys which had no value in the memory buffer are written with the values read at the for update ts, and the
+// pessimistic locks acquired after the savepoint are kept until the transaction ends.
+func (e *SimpleExec) executeRollbackToSavepoint(ctx context.Context, s *ast.RollbackStmt) error {
+	txnCtx := e.ctx.GetSessionVars().TxnCtx
+	idx := txnCtx.FindSavepoint(s.SavepointName)
+	if idx < 0 {
+		return ErrSpDoesNotExist.GenWithStackByArgs("SAVEPOINT", s.SavepointName)
+	}
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	values := txnCtx.SavepointValuesSince(idx)
+	absentKeys := make([]kv.Key, 0, len(values))
+	for key, val := range values {
+		if !val.Exists {
+			absentKeys = append(absentKeys, kv.Key(key))
+		}
+	}
+	if len(absentKeys) > 0 {
+		snapshotValues, err := e.ctx.GetSnapshotWithTS(txnCtx.GetForUpdateTS()).BatchGet(ctx, absentKeys)
+		if err != nil {
+			return err
+		}
+		for _, key := range absentKeys {
+			values[string(key)] = variable.SavepointValue{Value: snapshotValues[string(key)], Exists: true}
+		}
+	}
+	memBuffer := txn.GetMemBuffer()
+	for key, val := range values {
+		if len(val.Value) == 0 {
+			err = memBuffer.Delete(kv.Key(key))
+		} else {
+			err = memBuffer.Set(kv.Key(key), val.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Flush the restored values before removing the savepoints, so they are not recorded as the changes after the
+	// savepoint.
+	e.ctx.StmtCommit()
+	txnCtx.RollbackToSavepoint(idx)
+	return nil
+}
+
+func (e *SimpleExec) executeReleaseSavepoint(s *ast.ReleaseSavepointStmt) error {
+	txnCtx := e.ctx.GetSessionVars().TxnCtx
+	idx := txnCtx.FindSavepoint(s.Name)
+	if idx < 0 {
+		return ErrSpDoesNotExist.GenWithStackByArgs("SAVEPOINT", s.Name)
+	}
+	txnCtx.ReleaseSavepoint(idx)
+	return nil
+}
+
 func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStmt) error {
 	// Check `CREATE USER` privilege.
 	if !config.GetGlobalConfig().Security.SkipGrantTable {
@@ -776,14 +1222,38 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 		return err
 	}
 
+	resourceGroup, err := e.userResourceGroup(s.ResourceGroupNameOption)
+	if err != nil {
+		return err
+	}
+
+	maxUserConnections, _ := userMaxConnections(s.ResourceOptions)
+
+	passwordOrLock, err := passwordOrLockColumns(s.PasswordOrLockOptions)
+	if err != nil {
+		return err
+	}
+	reuseHistory, _ := passwordOrLock.get("Password_reuse_history")
+	reuseInterval, _ := passwordOrLock.get("Password_reuse_time")
+	passwordHistory, passwordReuseInterval, err := e.passwordReusePolicy(reuseHistory, reuseInterval)
+	if err != nil {
+		return err
+	}
+
 	sql := new(strings.Builder)
 	if s.IsCreateRole {
 		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, Account_locked) VALUES `, mysql.SystemDB, mysql.UserTable)
 	} else {
-		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin) VALUES `, mysql.SystemDB, mysql.UserTable)
+		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, Resource_group, max_user_connections`, mysql.SystemDB, mysql.UserTable)
+		for _, column := range passwordOrLock.columns {
+			sqlexec.MustFormatSQL(sql, `, %n`, column)
+		}
+		sqlexec.MustFormatSQL(sql, `) VALUES `)
 	}
 
 	users := make([]*auth.UserIdentity, 0, len(s.Specs))
+	// passwords is the hashes of the passwords to be added to the password history.
+	passwords := make([]string, 0, len(s.Specs))
 	for _, spec := range s.Specs {
 		if len(users) > 0 {
 			sqlexec.MustFormatSQL(sql, ",")
@@ -815,18 +1285,40 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 		}
 
 		switch authPlugin {
-		case mysql.AuthNativePassword, mysql.AuthCachingSha2Password, mysql.AuthSocket:
+		case mysql.AuthNativePassword, mysql.AuthCachingSha2Password, mysql.AuthTiDBSM3Password, mysql.AuthSocket,
+			mysql.AuthLDAPSimple, mysql.AuthLDAPSASL:
 		default:
 			return ErrPluginIsNotLoaded.GenWithStackByArgs(spec.AuthOpt.AuthPlugin)
 		}
+		// Only the passwords of mysql_native_password, caching_sha2_password and tidb_sm3_password are managed by TiDB.
+		managedPwd := ""
+		if !s.IsCreateRole && (authPlugin == mysql.AuthNativePassword || authPlugin == mysql.AuthCachingSha2Password ||
+			authPlugin == mysql.AuthTiDBSM3Password) {
+			// The hash strings can't be validated, but the empty passwords can.
+			if pwd == "" || spec.AuthOpt.ByAuthString {
+				cleartext := ""
+				if spec.AuthOpt != nil {
+					cleartext = spec.AuthOpt.AuthString
+				}
+				if err := e.validatePassword(spec.User.Username, cleartext); err != nil {
+					return err
+				}
+			}
+			managedPwd = pwd
+		}
 
 		hostName := strings.ToLower(spec.User.Hostname)
 		if s.IsCreateRole {
 			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?)`, hostName, spec.User.Username, pwd, authPlugin, "Y")
 		} else {
-			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?)`, hostName, spec.User.Username, pwd, authPlugin)
+			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?, %?`, hostName, spec.User.Username, pwd, authPlugin, resourceGroup, maxUserConnections)
+			for _, value := range passwordOrLock.values {
+				sqlexec.MustFormatSQL(sql, `, %?`, value)
+			}
+			sqlexec.MustFormatSQL(sql, `)`)
 		}
 		users = append(users, spec.User)
+		passwords = append(passwords, managedPwd)
 	}
 	if len(users) == 0 {
 		return nil
@@ -869,6 +1361,17 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 	if _, err := sqlExecutor.ExecuteInternal(context.TODO(), "commit"); err != nil {
 		return errors.Trace(err)
 	}
+	if passwordHistory > 0 || passwordReuseInterval > 0 {
+		exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+		for i, user := range users {
+			if passwords[i] == "" {
+				continue
+			}
+			if err = recordPasswordHistory(ctx, exec, user.Username, user.Hostname, passwords[i], passwordHistory, passwordReuseInterval); err != nil {
+				return err
+			}
+		}
+	}
 	return domain.GetDomain(e.ctx).NotifyUpdatePrivilege()
 }
 
@@ -893,6 +1396,20 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 		return err
 	}
 
+	resourceGroup, err := e.userResourceGroup(s.ResourceGroupNameOption)
+	if err != nil {
+		return err
+	}
+
+	maxUserConnections, hasMaxUserConnections := userMaxConnections(s.ResourceOptions)
+
+	passwordOrLock, err := passwordOrLockColumns(s.PasswordOrLockOptions)
+	if err != nil {
+		return err
+	}
+	_, expirePassword := passwordOrLock.get("Password_expired")
+	accountLocked, _ := passwordOrLock.get("Account_locked")
+
 	failedUsers := make([]string, 0, len(s.Specs))
 	checker := privilege.GetPrivilegeManager(e.ctx)
 	if checker == nil {
@@ -949,6 +1466,32 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 		}
 
 		exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+		// The password and lock options are applied first, so the password reuse policy in the same statement is used.
+		if len(passwordOrLock.columns) > 0 {
+			sql := new(strings.Builder)
+			args := []interface{}{mysql.SystemDB, mysql.UserTable}
+			sql.WriteString("UPDATE %n.%n SET ")
+			for i, column := range passwordOrLock.columns {
+				if i > 0 {
+					sql.WriteString(", ")
+				}
+				sql.WriteString("%n=%?")
+				args = append(args, column, passwordOrLock.values[i])
+			}
+			sql.WriteString(" WHERE Host=%? and User=%?;")
+			args = append(args, strings.ToLower(spec.User.Hostname), spec.User.Username)
+			stmt, err := exec.ParseWithParamsInternal(ctx, sql.String(), args...)
+			if err != nil {
+				return err
+			}
+			_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+			if err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			} else if accountLocked == "N" {
+				privileges.ResetFailedLogins(spec.User.Username, strings.ToLower(spec.User.Hostname))
+			}
+		}
+
 		if spec.AuthOpt != nil {
 			if spec.AuthOpt.AuthPlugin == "" {
 				authplugin, err := e.userAuthPlugin(spec.User.Username, spec.User.Hostname)
@@ -958,7 +1501,8 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 				spec.AuthOpt.AuthPlugin = authplugin
 			}
 			switch spec.AuthOpt.AuthPlugin {
-			case mysql.AuthNativePassword, mysql.AuthCachingSha2Password, mysql.AuthSocket, "":
+			case mysql.AuthNativePassword, mysql.AuthCachingSha2Password, mysql.AuthTiDBSM3Password, mysql.AuthSocket,
+				mysql.AuthLDAPSimple, mysql.AuthLDAPSASL, "":
 			default:
 				return ErrPluginIsNotLoaded.GenWithStackByArgs(spec.AuthOpt.AuthPlugin)
 			}
@@ -966,9 +1510,33 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 			if !ok {
 				return errors.Trace(ErrPasswordFormat)
 			}
+			var passwordHistory, passwordReuseInterval int64
+			isManagedPwd := spec.AuthOpt.AuthPlugin == mysql.AuthNativePassword || spec.AuthOpt.AuthPlugin == mysql.AuthCachingSha2Password ||
+				spec.AuthOpt.AuthPlugin == mysql.AuthTiDBSM3Password
+			if isManagedPwd {
+				if pwd == "" || spec.AuthOpt.ByAuthString {
+					if err := e.validatePassword(spec.User.Username, spec.AuthOpt.AuthString); err != nil {
+						return err
+					}
+				}
+				passwordHistory, passwordReuseInterval, err = e.userPasswordReusePolicy(ctx, spec.User.Username, spec.User.Hostname)
+				if err != nil {
+					return err
+				}
+				if spec.AuthOpt.ByAuthString {
+					err = checkPasswordHistory(ctx, exec, spec.User.Username, spec.User.Hostname, spec.AuthOpt.AuthString, passwordHistory, passwordReuseInterval)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			passwordExpired := "N"
+			if expirePassword {
+				passwordExpired = "Y"
+			}
 			stmt, err := exec.ParseWithParamsInternal(ctx,
-				`UPDATE %n.%n SET authentication_string=%?, plugin=%? WHERE Host=%? and User=%?;`,
-				mysql.SystemDB, mysql.UserTable, pwd, spec.AuthOpt.AuthPlugin, strings.ToLower(spec.User.Hostname), spec.User.Username,
+				`UPDATE %n.%n SET authentication_string=%?, plugin=%?, Password_expired=%?, Password_last_changed=CURRENT_TIMESTAMP() WHERE Host=%? and User=%?;`,
+				mysql.SystemDB, mysql.UserTable, pwd, spec.AuthOpt.AuthPlugin, passwordExpired, strings.ToLower(spec.User.Hostname), spec.User.Username,
 			)
 			if err != nil {
 				return err
@@ -976,6 +1544,14 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 			_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
 			if err != nil {
 				failedUsers = append(failedUsers, spec.User.String())
+			} else if isManagedPwd && pwd != "" {
+				err = recordPasswordHistory(ctx, exec, spec.User.Username, spec.User.Hostname, pwd, passwordHistory, passwordReuseInterval)
+				if err != nil {
+					return err
+				}
+			}
+			if err == nil && !expirePassword {
+				e.leaveSandboxMode(spec.User.Username, spec.User.Hostname)
 			}
 		}
 
@@ -989,6 +1565,34 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 				failedUsers = append(failedUsers, spec.User.String())
 			}
 		}
+
+		if s.ResourceGroupNameOption != nil {
+			stmt, err := exec.ParseWithParamsInternal(ctx,
+				`UPDATE %n.%n SET Resource_group=%? WHERE Host=%? and User=%?;`,
+				mysql.SystemDB, mysql.UserTable, resourceGroup, strings.ToLower(spec.User.Hostname), spec.User.Username,
+			)
+			if err != nil {
+				return err
+			}
+			_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+			if err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
+		}
+
+		if hasMaxUserConnections {
+			stmt, err := exec.ParseWithParamsInternal(ctx,
+				`UPDATE %n.%n SET max_user_connections=%? WHERE Host=%? and User=%?;`,
+				mysql.SystemDB, mysql.UserTable, maxUserConnections, strings.ToLower(spec.User.Hostname), spec.User.Username,
+			)
+			if err != nil {
+				return err
+			}
+			_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+			if err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
+		}
 	}
 	if len(failedUsers) > 0 {
 		// Commit the transaction even if we returns error
@@ -1071,6 +1675,105 @@ func (e *SimpleExec) executeGrantRole(ctx context.Context, s *ast.GrantRoleStmt)
 	return domain.GetDomain(e.ctx).NotifyUpdatePrivilege()
 }
 
+// checkProxyGrantable checks whether the current user can grant or revoke the PROXY privilege on the proxied user,
+// and all the users in the statement exist.
+func (e *SimpleExec) checkProxyGrantable(ctx context.Context, stmtName string, proxiedUser *auth.UserIdentity, users []*auth.UserIdentity) error {
+	checker := privilege.GetPrivilegeManager(e.ctx)
+	if checker == nil {
+		return errors.New("miss privilege checker")
+	}
+	sessionVars := e.ctx.GetSessionVars()
+	for _, user := range append([]*auth.UserIdentity{proxiedUser}, users...) {
+		if user.CurrentUser {
+			user.Username = sessionVars.User.AuthUsername
+			user.Hostname = sessionVars.User.AuthHostname
+		}
+	}
+	if !checker.RequestProxyVerification(sessionVars.ActiveRoles, proxiedUser, true) {
+		return core.ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or PROXY WITH GRANT OPTION")
+	}
+	for _, user := range append([]*auth.UserIdentity{proxiedUser}, users...) {
+		exists, err := userExists(ctx, e.ctx, user.Username, user.Hostname)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrCannotUser.GenWithStackByArgs(stmtName, user.String())
+		}
+	}
+	return nil
+}
+
+func (e *SimpleExec) executeGrantProxy(ctx context.Context, s *ast.GrantProxyStmt) error {
+	if err := e.checkProxyGrantable(ctx, "GRANT PROXY", s.LocalUser, s.ExternalUsers); err != nil {
+		return err
+	}
+
+	sql := new(strings.Builder)
+	if s.WithGrant {
+		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, Proxied_host, Proxied_user, With_grant) VALUES `, mysql.SystemDB, mysql.ProxiesPrivTable)
+	} else {
+		sqlexec.MustFormatSQL(sql, `INSERT IGNORE INTO %n.%n (Host, User, Proxied_host, Proxied_user, With_grant) VALUES `, mysql.SystemDB, mysql.ProxiesPrivTable)
+	}
+	withGrant := "N"
+	if s.WithGrant {
+		withGrant = "Y"
+	}
+	for i, user := range s.ExternalUsers {
+		if i > 0 {
+			sqlexec.MustFormatSQL(sql, ",")
+		}
+		sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?)`, strings.ToLower(user.Hostname), user.Username,
+			strings.ToLower(s.LocalUser.Hostname), s.LocalUser.Username, withGrant)
+	}
+	if s.WithGrant {
+		sqlexec.MustFormatSQL(sql, ` ON DUPLICATE KEY UPDATE With_grant = 'Y'`)
+	}
+
+	restrictedCtx, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(restrictedCtx)
+	if _, err := restrictedCtx.(sqlexec.SQLExecutor).ExecuteInternal(context.TODO(), sql.String()); err != nil {
+		return err
+	}
+	return domain.GetDomain(e.ctx).NotifyUpdatePrivilege()
+}
+
+func (e *SimpleExec) executeRevokeProxy(ctx context.Context, s *ast.RevokeProxyStmt) error {
+	if err := e.checkProxyGrantable(ctx, "REVOKE PROXY", s.LocalUser, s.ExternalUsers); err != nil {
+		return err
+	}
+
+	restrictedCtx, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(restrictedCtx)
+	sqlExecutor := restrictedCtx.(sqlexec.SQLExecutor)
+	if _, err := sqlExecutor.ExecuteInternal(context.TODO(), "begin"); err != nil {
+		return err
+	}
+	sql := new(strings.Builder)
+	for _, user := range s.ExternalUsers {
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, `DELETE FROM %n.%n WHERE Host = %? AND User = %? AND Proxied_host = %? AND Proxied_user = %?`,
+			mysql.SystemDB, mysql.ProxiesPrivTable, strings.ToLower(user.Hostname), user.Username,
+			strings.ToLower(s.LocalUser.Hostname), s.LocalUser.Username)
+		if _, err := sqlExecutor.ExecuteInternal(context.TODO(), sql.String()); err != nil {
+			if _, err := sqlExecutor.ExecuteInternal(context.TODO(), "rollback"); err != nil {
+				return err
+			}
+			return ErrCannotUser.GenWithStackByArgs("REVOKE PROXY", user.String())
+		}
+	}
+	if _, err := sqlExecutor.ExecuteInternal(context.TODO(), "commit"); err != nil {
+		return err
+	}
+	return domain.GetDomain(e.ctx).NotifyUpdatePrivilege()
+}
+
 // Should cover same internal mysql.* tables as DROP USER, so this function is very similar
 func (e *SimpleExec) executeRenameUser(s *ast.RenameUserStmt) error {
 
@@ -1159,6 +1862,23 @@ func (e *SimpleExec) executeRenameUser(s *ast.RenameUserStmt) error {
 			break
 		}
 
+		// rename relationship from mysql.proxies_priv
+		if err = renameUserHostInSystemTable(sqlExecutor, mysql.ProxiesPrivTable, "User", "Host", userToUser); err != nil {
+			failedUser = oldUser.String() + " TO " + newUser.String() + " " + mysql.ProxiesPrivTable + " error"
+			break
+		}
+
+		if err = renameUserHostInSystemTable(sqlExecutor, mysql.ProxiesPrivTable, "Proxied_user", "Proxied_host", userToUser); err != nil {
+			failedUser = oldUser.String() + " TO " + newUser.String() + " " + mysql.ProxiesPrivTable + " (proxied) error"
+			break
+		}
+
+		// rename relationship from mysql.password_history
+		if err = renameUserHostInSystemTable(sqlExecutor, mysql.PasswordHistoryTable, "User", "Host", userToUser); err != nil {
+			failedUser = oldUser.String() + " TO " + newUser.String() + " " + mysql.PasswordHistoryTable + " error"
+			break
+		}
+
 		//TODO: need update columns_priv once we implement columns_priv functionality.
 		// When that is added, please refactor both executeRenameUser and executeDropUser to use an array of tables
 		// to loop over, so it is easier to maintain.
@@ -1320,6 +2040,22 @@ func (e *SimpleExec) executeDropUser(ctx context.Context, s *ast.DropUserStmt) e
 			break
 		}
 
+		// delete relationship from mysql.proxies_priv
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, `DELETE FROM %n.%n WHERE (Host = %? and User = %?) or (Proxied_host = %? and Proxied_user = %?);`, mysql.SystemDB, mysql.ProxiesPrivTable, user.Hostname, user.Username, user.Hostname, user.Username)
+		if _, err = sqlExecutor.ExecuteInternal(context.TODO(), sql.String()); err != nil {
+			failedUsers = append(failedUsers, user.String())
+			break
+		}
+
+		// delete the password history from mysql.password_history
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, `DELETE FROM %n.%n WHERE Host = %? and User = %?;`, mysql.SystemDB, mysql.PasswordHistoryTable, user.Hostname, user.Username)
+		if _, err = sqlExecutor.ExecuteInternal(context.TODO(), sql.String()); err != nil {
+			failedUsers = append(failedUsers, user.String())
+			break
+		}
+
 		// delete from activeRoles
 		if s.IsDropRole {
 			for i := 0; i < len(activeRoles); i++ {
@@ -1432,16 +2168,37 @@ func (e *SimpleExec) executeSetPwd(ctx context.Context, s *ast.SetPwdStmt) error
 	switch authplugin {
 	case mysql.AuthCachingSha2Password:
 		pwd = auth.NewSha2Password(s.Password)
+	case mysql.AuthTiDBSM3Password:
+		pwd = auth.NewSM3Password(s.Password)
 	case mysql.AuthSocket:
 		e.ctx.GetSessionVars().StmtCtx.AppendNote(ErrSetPasswordAuthPlugin.GenWithStackByArgs(u, h))
 		pwd = ""
+	case mysql.AuthLDAPSimple, mysql.AuthLDAPSASL:
+		// The password is managed by the LDAP server, and the authentication string is the DN which should be kept.
+		e.ctx.GetSessionVars().StmtCtx.AppendNote(ErrSetPasswordAuthPlugin.GenWithStackByArgs(u, h))
+		return nil
 	default:
 		pwd = auth.EncodePassword(s.Password)
 	}
 
-	// update mysql.user
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
-	stmt, err := exec.ParseWithParamsInternal(ctx, `UPDATE %n.%n SET authentication_string=%? WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, strings.ToLower(h))
+	var passwordHistory, passwordReuseInterval int64
+	isManagedPwd := authplugin != mysql.AuthSocket
+	if isManagedPwd {
+		if err := e.validatePassword(u, s.Password); err != nil {
+			return err
+		}
+		passwordHistory, passwordReuseInterval, err = e.userPasswordReusePolicy(ctx, u, h)
+		if err != nil {
+			return err
+		}
+		if err := checkPasswordHistory(ctx, exec, u, h, s.Password, passwordHistory, passwordReuseInterval); err != nil {
+			return err
+		}
+	}
+
+	// update mysql.user
+	stmt, err := exec.ParseWithParamsInternal(ctx, `UPDATE %n.%n SET authentication_string=%?, Password_expired='N', Password_last_changed=CURRENT_TIMESTAMP() WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, strings.ToLower(h))
 	if err != nil {
 		return err
 	}
@@ -1449,10 +2206,28 @@ func (e *SimpleExec) executeSetPwd(ctx context.Context, s *ast.SetPwdStmt) error
 	if err != nil {
 		return err
 	}
+	if isManagedPwd && pwd != "" {
+		if err := recordPasswordHistory(ctx, exec, u, h, pwd, passwordHistory, passwordReuseInterval); err != nil {
+			return err
+		}
+	}
+	e.leaveSandboxMode(u, h)
 	return domain.GetDomain(e.ctx).NotifyUpdatePrivilege()
 }
 
+// leaveSandboxMode lets the session execute the other statements once the expired password of current user is changed.
+func (e *SimpleExec) leaveSandboxMode(user, host string) {
+	sessionVars := e.ctx.GetSessionVars()
+	if sessionVars.InSandboxMode && sessionVars.User != nil &&
+		sessionVars.User.AuthUsername == user && strings.EqualFold(sessionVars.User.AuthHostname, host) {
+		sessionVars.InSandboxMode = false
+	}
+}
+
 func (e *SimpleExec) executeKillStmt(ctx context.Context, s *ast.KillStmt) error {
+	if s.AnalyzeJobID != 0 {
+		return e.executeKillAnalyze(ctx, s.AnalyzeJobID)
+	}
 	if !config.GetGlobalConfig().Experimental.EnableGlobalKill {
 		conf := config.GetGlobalConfig()
 		if s.TiDBExtension || conf.CompatibleKillQuery {
@@ -1507,6 +2282,48 @@ func (e *SimpleExec) executeKillStmt(ctx context.Context, s *ast.KillStmt) error
 	return nil
 }
 
+// executeKillAnalyze kills the analyze job running on the current instance. The job stops itself as soon as it finds
+// it is killed, and it is recorded as failed in mysql.analyze_jobs.
+func (e *SimpleExec) executeKillAnalyze(ctx context.Context, jobID uint64) error {
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParamsInternal(ctx, "select table_schema, table_name, state, instance from mysql.analyze_jobs where id = %?", jobID)
+	if err != nil {
+		return err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return ErrKillAnalyzeJobFailed.GenWithStackByArgs(jobID, "unknown job")
+	}
+	dbName, tableName := rows[0].GetString(0), rows[0].GetString(1)
+	state, instance := rows[0].GetEnum(2).String(), rows[0].GetString(3)
+	// Killing an analyze job requires the same privileges as running it.
+	if checker := privilege.GetPrivilegeManager(e.ctx); checker != nil {
+		activeRoles := e.ctx.GetSessionVars().ActiveRoles
+		for _, priv := range []mysql.PrivilegeType{mysql.SelectPriv, mysql.InsertPriv} {
+			if !checker.RequestVerification(activeRoles, dbName, tableName, "", priv) {
+				user := e.ctx.GetSessionVars().User
+				return ErrTableaccessDenied.GenWithStackByArgs(priv.String(), user.AuthUsername, user.AuthHostname, tableName)
+			}
+		}
+	}
+	if state == statistics.AnalyzeFinished || state == statistics.AnalyzeFailed {
+		return ErrKillAnalyzeJobFailed.GenWithStackByArgs(jobID, "the job has been "+state)
+	}
+	if instance != analyzeJobInstance() {
+		return ErrKillAnalyzeJobFailed.GenWithStackByArgs(jobID, fmt.Sprintf("the job is running on %s, please execute KILL ANALYZE on that instance", instance))
+	}
+	job := statistics.GetAnalyzeJob(jobID)
+	if job == nil {
+		return ErrKillAnalyzeJobFailed.GenWithStackByArgs(jobID, "the job is not running")
+	}
+	job.Kill()
+	logutil.Logger(ctx).Info("kill analyze job", zap.Uint64("job id", jobID), zap.String("table", dbName+"."+tableName))
+	return nil
+}
+
 func killRemoteConn(ctx context.Context, sctx sessionctx.Context, connID *util.GlobalConnID, query bool) error {
 	if connID.ServerID == 0 {
 		return errors.New("Unexpected ZERO ServerID. Please file a bug to the TiDB Team")
@@ -1592,6 +2409,46 @@ func (e *SimpleExec) executeAlterInstance(s *ast.AlterInstanceStmt) error {
 		}
 		sm.UpdateTLSConfig(tlsCfg)
 	}
+	if s.RotateMasterKey {
+		return e.executeRotateMasterKey()
+	}
+	return nil
+}
+
+// executeRotateMasterKey rotates the master key of PD at first, and then the master keys of all the stores. The
+// stores re-encrypt the data keys in the background, the progress is shown in TIKV_ENCRYPTION_STATUS.
+func (e *SimpleExec) executeRotateMasterKey() error {
+	tikvStore, ok := e.ctx.GetStore().(helper.Storage)
+	if !ok {
+		return errors.New("rotating the master key is only supported when the storage is TiKV")
+	}
+	h := helper.NewHelper(tikvStore)
+	storesStat, err := h.GetStoresStat()
+	if err != nil {
+		return err
+	}
+	if err = h.RotatePDMasterKey(); err != nil {
+		return err
+	}
+	logutil.BgLogger().Info("rotate the master key of PD")
+	var failed int
+	for _, storeStat := range storesStat.Stores {
+		store := storeStat.Store
+		if store.StateName == helper.StoreStateTombstone {
+			continue
+		}
+		// Try all the stores so that a store being down doesn't block the others, the rotation can be retried.
+		if err = helper.RotateStoreMasterKey(store.StatusAddress); err != nil {
+			failed++
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("rotate the master key of store %d failed: %v", store.ID, err))
+			logutil.BgLogger().Warn("rotate the master key of store failed", zap.Int64("store", store.ID), zap.Error(err))
+			continue
+		}
+		logutil.BgLogger().Info("rotate the master key of store", zap.Int64("store", store.ID))
+	}
+	if failed > 0 {
+		return errors.Errorf("rotate the master keys of %d stores failed, see the warnings for details", failed)
+	}
 	return nil
 }
 
@@ -1611,9 +2468,149 @@ func (e *SimpleExec) executeDropStats(s *ast.DropStatsStmt) (err error) {
 	return h.Update(e.ctx.GetInfoSchema().(infoschema.InfoSchema))
 }
 
+// executeLockStats locks or unlocks the stats of the tables. Locking the tables that are locked already
+// or unlocking the tables that are not locked is skipped with a warning.
+func (e *SimpleExec) executeLockStats(tables []*ast.TableName, lock bool) error {
+	h := domain.GetDomain(e.ctx).StatsHandle()
+	tableIDs := make([]int64, 0, len(tables))
+	tableNames := make(map[int64]string, len(tables))
+	for _, tbl := range tables {
+		if tbl.TableInfo.IsView() || tbl.TableInfo.IsSequence() {
+			return ErrWrongObject.GenWithStackByArgs(tbl.Schema.O, tbl.Name.O, "BASE TABLE")
+		}
+		tableIDs = append(tableIDs, tbl.TableInfo.ID)
+		tableNames[tbl.TableInfo.ID] = tbl.Schema.O + "." + tbl.Name.O
+	}
+	var skipped []int64
+	var err error
+	msg := "skip locking locked table: %s"
+	if lock {
+		skipped, err = h.LockTables(tableIDs)
+	} else {
+		msg = "skip unlocking unlocked table: %s"
+		skipped, err = h.UnlockTables(tableIDs)
+	}
+	if err != nil {
+		return err
+	}
+	for _, id := range skipped {
+		e.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf(msg, tableNames[id]))
+	}
+	return nil
+}
+
+// userFunctionsTable is the system table storing the user-defined functions.
+const userFunctionsTable = "user_functions"
+
+// normalizeUserFunctionType fills the default length of the parameter or return type of the user-defined function.
+func normalizeUserFunctionType(tp *types.FieldType) *types.FieldType {
+	tp = tp.Clone()
+	defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(tp.Tp)
+	if tp.Flen == types.UnspecifiedLength {
+		tp.Flen = defaultFlen
+	}
+	if tp.Decimal == types.UnspecifiedLength {
+		tp.Decimal = defaultDecimal
+	}
+	types.SetBinChsClnFlag(tp)
+	return tp
+}
+
+func userFunctionExists(ctx context.Context, sctx sessionctx.Context, name string) (bool, error) {
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParamsInternal(ctx, `SELECT name FROM %n.%n WHERE name=%?`, mysql.SystemDB, userFunctionsTable, name)
+	if err != nil {
+		return false, err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func (e *SimpleExec) executeCreateFunction(ctx context.Context, s *ast.CreateFunctionStmt) error {
+	name := s.FuncName.L
+	if expression.IsFunctionSupported(name) {
+		return ErrNativeFctNameCollision.GenWithStackByArgs(s.FuncName.O)
+	}
+	if s.Language != expression.UDFLanguageWasm {
+		return expression.ErrInvalidUserDefinedFunction.GenWithStackByArgs(name, fmt.Sprintf("language %s is not supported", s.Language))
+	}
+	paramTypes := make([]*types.FieldType, 0, len(s.Params))
+	for _, param := range s.Params {
+		paramTypes = append(paramTypes, normalizeUserFunctionType(param.Tp))
+	}
+	retType := normalizeUserFunctionType(s.ReturnType)
+	body := []byte(s.Body.(ast.ValueExpr).GetDatumString())
+	// Compile the module to check it's valid before it's stored.
+	udf, err := expression.NewWasmUserDefinedFunction(name, paramTypes, retType, body)
+	if err != nil {
+		return err
+	}
+	udf.Close()
+
+	exists, err := userFunctionExists(ctx, e.ctx, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		err := ErrSpAlreadyExists.GenWithStackByArgs("FUNCTION", name)
+		if !s.IfNotExists {
+			return err
+		}
+		e.ctx.GetSessionVars().StmtCtx.AppendNote(err)
+		return nil
+	}
+	params, ret, err := domain.EncodeUserFunctionTypes(paramTypes, retType)
+	if err != nil {
+		return err
+	}
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(sysSession)
+	sql := new(strings.Builder)
+	sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (name, language, param_types, return_type, body) VALUES (%?, %?, %?, %?, %?)`,
+		mysql.SystemDB, userFunctionsTable, name, s.Language, params, ret, body)
+	if _, err := sysSession.(sqlexec.SQLExecutor).ExecuteInternal(ctx, sql.String()); err != nil {
+		return err
+	}
+	return domain.GetDomain(e.ctx).NotifyUpdateUserFunctions()
+}
+
+func (e *SimpleExec) executeDropFunction(ctx context.Context, s *ast.DropFunctionStmt) error {
+	name := s.FuncName.L
+	exists, err := userFunctionExists(ctx, e.ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		err := ErrSpDoesNotExist.GenWithStackByArgs("FUNCTION", name)
+		if !s.IfExists {
+			return err
+		}
+		e.ctx.GetSessionVars().StmtCtx.AppendNote(err)
+		return nil
+	}
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(sysSession)
+	sql := new(strings.Builder)
+	sqlexec.MustFormatSQL(sql, `DELETE FROM %n.%n WHERE name=%?`, mysql.SystemDB, userFunctionsTable, name)
+	if _, err := sysSession.(sqlexec.SQLExecutor).ExecuteInternal(ctx, sql.String()); err != nil {
+		return err
+	}
+	return domain.GetDomain(e.ctx).NotifyUpdateUserFunctions()
+}
+
 func (e *SimpleExec) autoNewTxn() bool {
 	switch e.Statement.(type) {
-	case *ast.CreateUserStmt, *ast.AlterUserStmt, *ast.DropUserStmt, *ast.RenameUserStmt:
+	case *ast.CreateUserStmt, *ast.AlterUserStmt, *ast.DropUserStmt, *ast.RenameUserStmt,
+		*ast.CreateFunctionStmt, *ast.DropFunctionStmt:
 		return true
 	}
 	return false
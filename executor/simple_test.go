@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/util"
@@ -354,6 +355,65 @@ func (s *testSuite7) TestSetDefaultRoleAll(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *testSuite7) TestUserMaxConnections(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("CREATE USER 'mc1'@'%' WITH MAX_USER_CONNECTIONS 3")
+	tk.MustExec("CREATE USER 'mc2'@'%'")
+	tk.MustQuery("SELECT User, max_user_connections FROM mysql.user WHERE User LIKE 'mc%' ORDER BY User").Check(testkit.Rows("mc1 3", "mc2 0"))
+	tk.MustExec("ALTER USER 'mc2'@'%' WITH MAX_QUERIES_PER_HOUR 10 MAX_USER_CONNECTIONS 5")
+	tk.MustExec("ALTER USER 'mc1'@'%' IDENTIFIED BY '123'")
+	tk.MustQuery("SELECT User, max_user_connections FROM mysql.user WHERE User LIKE 'mc%' ORDER BY User").Check(testkit.Rows("mc1 3", "mc2 5"))
+	tk.MustExec("ALTER USER 'mc1'@'%' WITH MAX_USER_CONNECTIONS 0")
+	tk.MustQuery("SELECT max_user_connections FROM mysql.user WHERE User = 'mc1'").Check(testkit.Rows("0"))
+	tk.MustExec("DROP USER 'mc1'@'%', 'mc2'@'%'")
+
+	tk.MustExec("SET GLOBAL max_user_connections = 10")
+	c.Assert(variable.MaxUserConnectionsValue.Load(), Equals, uint32(10))
+	tk.MustQuery("SELECT @@global.max_user_connections").Check(testkit.Rows("10"))
+	tk.MustExec("SET GLOBAL max_user_connections = default")
+	c.Assert(variable.MaxUserConnectionsValue.Load(), Equals, uint32(0))
+}
+
+func (s *testSuite7) TestSetSessionAuthorization(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("CREATE USER 'proxy_login'@'%', 'proxy_app'@'%', 'proxy_other'@'%'")
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+	defer tk.MustExec("DROP USER IF EXISTS 'proxy_login'@'%', 'proxy_app'@'%', 'proxy_other'@'%'")
+	tk.MustExec("GRANT PROXY ON 'proxy_app'@'%' TO 'proxy_login'@'%'")
+	tk.MustQuery("SELECT Host, User, Proxied_host, Proxied_user, With_grant FROM mysql.proxies_priv").Check(testkit.Rows("% proxy_login % proxy_app N"))
+	tk.MustQuery("SHOW GRANTS FOR 'proxy_login'@'%'").Check(testkit.Rows(
+		"GRANT USAGE ON *.* TO 'proxy_login'@'%'",
+		"GRANT PROXY ON 'proxy_app'@'%' TO 'proxy_login'@'%'"))
+	tk.MustGetErrCode("GRANT PROXY ON 'proxy_none'@'%' TO 'proxy_login'@'%'", mysql.ErrCannotUser)
+
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "proxy_login", Hostname: "%"}, nil, nil), IsTrue)
+	tk1.MustQuery("SELECT CURRENT_USER()").Check(testkit.Rows("proxy_login@%"))
+	tk1.MustExec("SET SESSION AUTHORIZATION 'proxy_app'@'%'")
+	tk1.MustQuery("SELECT CURRENT_USER()").Check(testkit.Rows("proxy_app@%"))
+	// The PROXY privilege is checked against the login user, so the session can switch between the proxied users.
+	tk1.MustExec("SET SESSION AUTHORIZATION 'proxy_app'@'%'")
+	tk1.MustGetErrCode("SET SESSION AUTHORIZATION 'proxy_other'@'%'", mysql.ErrSpecificAccessDenied)
+	tk1.MustQuery("SELECT CURRENT_USER()").Check(testkit.Rows("proxy_login@%"))
+	tk1.MustGetErrCode("GRANT PROXY ON 'proxy_app'@'%' TO 'proxy_other'@'%'", mysql.ErrSpecificAccessDenied)
+	tk1.MustExec("SET SESSION AUTHORIZATION 'proxy_app'@'%'")
+	tk1.MustExec("SET SESSION AUTHORIZATION DEFAULT")
+	tk1.MustQuery("SELECT CURRENT_USER()").Check(testkit.Rows("proxy_login@%"))
+
+	tk.MustExec("GRANT PROXY ON 'proxy_app'@'%' TO 'proxy_login'@'%' WITH GRANT OPTION")
+	tk1.MustExec("GRANT PROXY ON 'proxy_app'@'%' TO 'proxy_other'@'%'")
+	tk.MustQuery("SELECT User, With_grant FROM mysql.proxies_priv ORDER BY User").Check(testkit.Rows("proxy_login Y", "proxy_other N"))
+
+	tk.MustExec("RENAME USER 'proxy_other'@'%' TO 'proxy_renamed'@'%'")
+	tk.MustExec("REVOKE PROXY ON 'proxy_app'@'%' FROM 'proxy_login'@'%'")
+	tk.MustQuery("SELECT User FROM mysql.proxies_priv").Check(testkit.Rows("proxy_renamed"))
+	tk1.MustGetErrCode("SET SESSION AUTHORIZATION 'proxy_app'@'%'", mysql.ErrSpecificAccessDenied)
+	tk.MustExec("DROP USER 'proxy_app'@'%'")
+	tk.MustQuery("SELECT User FROM mysql.proxies_priv").Check(testkit.Rows())
+	tk.MustExec("DROP USER 'proxy_renamed'@'%'")
+}
+
 func (s *testSuite7) TestUser(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	// Make sure user test not in mysql.User.
@@ -569,6 +629,60 @@ func (s *testSuite3) TestSetPwd(c *C) {
 
 }
 
+func (s *testSuite3) TestPasswordManagement(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+
+	// The password and lock options are stored into mysql.user.
+	tk.MustExec(`CREATE USER 'pwdmgr'@'localhost' IDENTIFIED BY 'pwd1' PASSWORD EXPIRE INTERVAL 30 DAY PASSWORD HISTORY 2 FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME UNBOUNDED`)
+	tk.MustQuery(`SELECT Password_lifetime, Password_reuse_history, Password_reuse_time, Failed_login_attempts, Password_lock_time FROM mysql.user WHERE User="pwdmgr"`).
+		Check(testkit.Rows("30 2 <nil> 3 -1"))
+	_, err := tk.Exec(`ALTER USER 'pwdmgr'@'localhost' FAILED_LOGIN_ATTEMPTS 40000`)
+	c.Assert(err, NotNil)
+
+	// The passwords in the history can't be reused.
+	_, err = tk.Exec(`ALTER USER 'pwdmgr'@'localhost' IDENTIFIED BY 'pwd1'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrPasswordContradictHistory), IsTrue, Commentf("err %v", err))
+	tk.MustExec(`ALTER USER 'pwdmgr'@'localhost' IDENTIFIED BY 'pwd2'`)
+	_, err = tk.Exec(`SET PASSWORD FOR 'pwdmgr'@'localhost' = 'pwd1'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrPasswordContradictHistory), IsTrue, Commentf("err %v", err))
+	tk.MustExec(`SET PASSWORD FOR 'pwdmgr'@'localhost' = 'pwd3'`)
+	tk.MustQuery(`SELECT count(*) FROM mysql.password_history WHERE User="pwdmgr"`).Check(testkit.Rows("2"))
+	// 'pwd1' is out of the latest 2 passwords.
+	tk.MustExec(`SET PASSWORD FOR 'pwdmgr'@'localhost' = 'pwd1'`)
+
+	// Disabling the policy clears the history.
+	tk.MustExec(`ALTER USER 'pwdmgr'@'localhost' PASSWORD HISTORY 0`)
+	tk.MustExec(`SET PASSWORD FOR 'pwdmgr'@'localhost' = 'pwd1'`)
+	tk.MustQuery(`SELECT count(*) FROM mysql.password_history WHERE User="pwdmgr"`).Check(testkit.Rows("0"))
+
+	// Changing the password resets the expiration.
+	tk.MustExec(`ALTER USER 'pwdmgr'@'localhost' PASSWORD EXPIRE`)
+	tk.MustQuery(`SELECT Password_expired FROM mysql.user WHERE User="pwdmgr"`).Check(testkit.Rows("Y"))
+	tk.MustExec(`ALTER USER 'pwdmgr'@'localhost' IDENTIFIED BY 'pwd4'`)
+	tk.MustQuery(`SELECT Password_expired FROM mysql.user WHERE User="pwdmgr"`).Check(testkit.Rows("N"))
+
+	// The validate_password component checks the new passwords.
+	tk.MustExec(`SET GLOBAL validate_password.enable = ON`)
+	defer tk.MustExec(`SET GLOBAL validate_password.enable = DEFAULT`)
+	_, err = tk.Exec(`CREATE USER 'pwdmgr2'@'localhost' IDENTIFIED BY 'short'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrNotValidPassword), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec(`ALTER USER 'pwdmgr'@'localhost' IDENTIFIED BY 'rgmdwp'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrNotValidPassword), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec(`SET PASSWORD FOR 'pwdmgr'@'localhost' = 'lowercase#1'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrNotValidPassword), IsTrue, Commentf("err %v", err))
+	tk.MustExec(`SET PASSWORD FOR 'pwdmgr'@'localhost' = 'Str0ng#Passwd'`)
+	// The roles have no password to validate.
+	tk.MustExec(`CREATE ROLE 'pwdmgr_role'`)
+
+	// The password history is removed with the user.
+	tk.MustExec(`ALTER USER 'pwdmgr'@'localhost' PASSWORD HISTORY 5`)
+	tk.MustExec(`SET PASSWORD FOR 'pwdmgr'@'localhost' = 'An0ther#Passwd'`)
+	tk.MustExec(`RENAME USER 'pwdmgr'@'localhost' TO 'pwdmgr3'@'localhost'`)
+	tk.MustQuery(`SELECT count(*) FROM mysql.password_history WHERE User="pwdmgr3"`).Check(testkit.Rows("1"))
+	tk.MustExec(`DROP USER 'pwdmgr3'@'localhost', 'pwdmgr_role'`)
+	tk.MustQuery(`SELECT count(*) FROM mysql.password_history WHERE User="pwdmgr3"`).Check(testkit.Rows("0"))
+}
+
 func (s *testSuite3) TestKillStmt(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -17,6 +17,7 @@ package executor
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -313,6 +314,10 @@ func (e *slowQueryRetriever) getBatchLog(ctx context.Context, reader *bufio.Read
 			}
 			line = string(hack.String(lineByte))
 			log = append(log, line)
+			// An entry in the JSON format is in one line.
+			if strings.HasPrefix(line, variable.SlowLogJSONStartPrefixStr) {
+				break
+			}
 			if strings.HasSuffix(line, variable.SlowLogSQLSuffixStr) {
 				if strings.HasPrefix(line, "use") || strings.HasPrefix(line, variable.SlowLogRowPrefixStr) {
 					continue
@@ -364,13 +369,14 @@ func (e *slowQueryRetriever) getBatchLogForReversedScan(ctx context.Context, rea
 			return nil, err
 		}
 		line = string(hack.String(lineByte))
-		if !hasStartFlag && strings.HasPrefix(line, variable.SlowLogStartPrefixStr) {
-			hasStartFlag = true
+		if !hasStartFlag {
+			_, hasStartFlag = getSlowLogEntryTime(line)
 		}
 		if hasStartFlag {
 			log = append(log, line)
-			if strings.HasSuffix(line, variable.SlowLogSQLSuffixStr) {
-				if strings.HasPrefix(line, "use") || strings.HasPrefix(line, variable.SlowLogRowPrefixStr) {
+			isJSON := strings.HasPrefix(line, variable.SlowLogJSONStartPrefixStr)
+			if isJSON || strings.HasSuffix(line, variable.SlowLogSQLSuffixStr) {
+				if !isJSON && (strings.HasPrefix(line, "use") || strings.HasPrefix(line, variable.SlowLogRowPrefixStr)) {
 					continue
 				}
 				logs = append(logs, log)
@@ -549,6 +555,13 @@ func (e *slowQueryRetriever) parseLog(ctx context.Context, sctx sessionctx.Conte
 			return nil, ctx.Err()
 		}
 		fileLine := getLineIndex(offset, index)
+		if strings.HasPrefix(line, variable.SlowLogJSONStartPrefixStr) {
+			if row := e.parseJSONLog(sctx, tz, line, fileLine); row != nil {
+				data = append(data, row)
+			}
+			startFlag = false
+			continue
+		}
 		if !startFlag && strings.HasPrefix(line, variable.SlowLogStartPrefixStr) {
 			row = make([]types.Datum, len(e.outputCols))
 			user = ""
@@ -621,6 +634,78 @@ func (e *slowQueryRetriever) parseLog(ctx context.Context, sctx sessionctx.Conte
 	return data, nil
 }
 
+// parseJSONLog parses a slow log entry in the JSON format, it returns nil if the entry is filtered out.
+func (e *slowQueryRetriever) parseJSONLog(sctx sessionctx.Context, tz *time.Location, line string, lineNum int) []types.Datum {
+	row := make([]types.Datum, len(e.outputCols))
+	user := ""
+	decoder := json.NewDecoder(strings.NewReader(line))
+	err := func() error {
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			field, _ := token.(string)
+			var raw json.RawMessage
+			if err = decoder.Decode(&raw); err != nil {
+				return err
+			}
+			// The strings are unquoted, and the other values are kept in the JSON form.
+			value := string(raw)
+			if len(raw) > 0 && raw[0] == '"' {
+				if err = json.Unmarshal(raw, &value); err != nil {
+					return err
+				}
+			}
+			valid := true
+			switch {
+			case field == variable.SlowLogUserStr:
+				user = value
+				if e.checker != nil && !e.checker.hasPrivilege(user) {
+					valid = false
+				} else {
+					valid = e.setColumnValue(sctx, row, tz, field, value, e.checker, lineNum)
+				}
+			case strings.HasPrefix(field, variable.SlowLogCopBackoffPrefix):
+				valid = e.setColumnValue(sctx, row, tz, variable.SlowLogBackoffDetail, field+variable.SlowLogSpaceMarkStr+value, e.checker, lineNum)
+			default:
+				valid = e.setColumnValue(sctx, row, tz, field, value, e.checker, lineNum)
+			}
+			if !valid {
+				row = nil
+				return nil
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		sctx.GetSessionVars().StmtCtx.AppendWarning(fmt.Errorf("Parse slow log at line %v failed, error is %v", lineNum, err))
+		return nil
+	}
+	if row == nil || (e.checker != nil && !e.checker.hasPrivilege(user)) {
+		return nil
+	}
+	e.setDefaultValue(row)
+	return row
+}
+
+// getSlowLogEntryTime returns the time of the slow log entry if the line is the start of an entry.
+func getSlowLogEntryTime(line string) (string, bool) {
+	if strings.HasPrefix(line, variable.SlowLogStartPrefixStr) {
+		return line[len(variable.SlowLogStartPrefixStr):], true
+	}
+	if strings.HasPrefix(line, variable.SlowLogJSONStartPrefixStr) {
+		line = line[len(variable.SlowLogJSONStartPrefixStr):]
+		if end := strings.IndexByte(line, '"'); end >= 0 {
+			return line[:end], true
+		}
+	}
+	return "", false
+}
+
 func (e *slowQueryRetriever) setColumnValue(sctx sessionctx.Context, row []types.Datum, tz *time.Location, field, value string, checker *slowLogChecker, lineNum int) bool {
 	factory := e.columnValueFactoryMap[field]
 	if factory == nil {
@@ -921,9 +1006,8 @@ func (e *slowQueryRetriever) getFileStartTime(ctx context.Context, file *os.File
 		if err != nil {
 			return t, err
 		}
-		line := string(lineByte)
-		if strings.HasPrefix(line, variable.SlowLogStartPrefixStr) {
-			return ParseTime(line[len(variable.SlowLogStartPrefixStr):])
+		if t, ok := getSlowLogEntryTime(string(lineByte)); ok {
+			return ParseTime(t)
 		}
 		maxNum -= 1
 		if maxNum <= 0 {
@@ -1003,8 +1087,8 @@ func (e *slowQueryRetriever) getFileEndTime(ctx context.Context, file *os.File)
 		}
 		endCursor -= int64(readBytes)
 		for i := len(lines) - 1; i >= 0; i-- {
-			if strings.HasPrefix(lines[i], variable.SlowLogStartPrefixStr) {
-				return ParseTime(lines[i][len(variable.SlowLogStartPrefixStr):])
+			if t, ok := getSlowLogEntryTime(lines[i]); ok {
+				return ParseTime(t)
 			}
 		}
 		tried += len(lines)
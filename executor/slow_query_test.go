@@ -54,7 +54,7 @@ func parseLog(retriever *slowQueryRetriever, sctx sessionctx.Context, reader *bu
 }
 
 func newSlowQueryRetriever() (*slowQueryRetriever, error) {
-	newISBuilder, err := infoschema.NewBuilder(nil, nil, nil).InitWithDBInfos(nil, nil, nil, 0)
+	newISBuilder, err := infoschema.NewBuilder(nil, nil, nil).InitWithDBInfos(nil, nil, nil, nil, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -330,6 +330,47 @@ select * from t;
 
 }
 
+func (s *testExecSuite) TestParseSlowLogJSONFormat(c *C) {
+	slowLog := bytes.NewBufferString(
+		`{"Time":"2019-04-28T15:24:04.309074+08:00","Txn_start_ts":405888132465033227,"User":"root","Host":"127.0.0.1","Query_time":0.216905,"Process_time":0.021,"Request_count":1,"Is_internal":true,"Stats":"t1:1,t2:2","Cop_backoff_rpcTiKV_total_times":200,"Cop_backoff_rpcTiKV_total_time":0.2,"Mem_max":70724,"Succ":true,"Plan_digest":"60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4","Query":"select * from t;"}
+# Time: 2019-04-28T15:24:05.309074+08:00
+# Txn_start_ts: 405888132465033228
+# Query_time: 0.1
+select * from t2;
+{"Time":"2019-04-28T15:24:06.309074+08:00","Txn_start_ts":"invalid","Query":"select 1;"}
+{"Time":"2019-04-28T15:24:07.309074+08:00","Query_time":`)
+	reader := bufio.NewReader(slowLog)
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	c.Assert(err, IsNil)
+	ctx := mock.NewContext()
+	ctx.GetSessionVars().TimeZone = loc
+	rows, err := parseSlowLog(ctx, reader, 64)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 3)
+
+	recordString := ""
+	for i, value := range rows[0] {
+		str, err := value.ToString()
+		c.Assert(err, IsNil)
+		if i > 0 {
+			recordString += ","
+		}
+		recordString += str
+	}
+	c.Assert(recordString, Matches, `2019-04-28 15:24:04.309074,405888132465033227,root,127.0.0.1,0,.*,0.216905,.*`)
+	c.Assert(recordString, Matches, `.*,t1:1,t2:2,.*,Cop_backoff_rpcTiKV_total_times: 200 Cop_backoff_rpcTiKV_total_time: 0.2,.*`)
+	c.Assert(recordString, Matches, `.*,60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4,.*,select \* from t;`)
+	t2Query, err := rows[1][len(rows[1])-1].ToString()
+	c.Assert(err, IsNil)
+	c.Assert(t2Query, Equals, "select * from t2;")
+
+	// The invalid field is reported as a warning, and the broken entry is skipped.
+	warnings := ctx.GetSessionVars().StmtCtx.GetWarnings()
+	c.Assert(warnings, HasLen, 2)
+	c.Assert(warnings[0].Err.Error(), Equals, "Parse slow log at line 6, failed field is Txn_start_ts, failed value is invalid, error is strconv.ParseUint: parsing \"invalid\": invalid syntax")
+	c.Assert(warnings[1].Err.Error(), Equals, "Parse slow log at line 7 failed, error is unexpected EOF")
+}
+
 func (s *testExecSuite) TestSlowQueryRetriever(c *C) {
 	logData0 := ""
 	logData1 := `
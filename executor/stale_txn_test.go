@@ -1245,3 +1245,37 @@ func (s *testStaleTxnSerialSuite) TestStaleReadNoExtraTSORequest(c *C) {
 	tk.MustQuery("select * from t")
 	failpoint.Disable("github.com/pingcap/tidb/session/assertTSONotRequest")
 }
+
+func (s *testStaleTxnSuite) TestExternalTSRead(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	defer tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int)")
+	tk.MustExec("insert into t values (1)")
+	tk.MustExec("begin")
+	tk.MustQuery("select * from t")
+	externalTS := tk.Se.GetSessionVars().TxnCtx.StartTS
+	tk.MustExec("commit")
+	tk.MustExec("insert into t values (2)")
+
+	tk.MustExec("set @@tidb_enable_external_ts_read = on")
+	defer tk.MustExec("set @@tidb_enable_external_ts_read = off")
+	if tk.MustQuery("select @@global.tidb_external_ts").Rows()[0][0] == "0" {
+		err := tk.ExecToErr("select * from t")
+		c.Assert(err, NotNil)
+		c.Assert(err.Error(), Matches, ".*tidb_external_ts is not set.*")
+	}
+	tk.MustExec(fmt.Sprintf("set global tidb_external_ts = %d", externalTS))
+	tk.MustQuery("select @@global.tidb_external_ts").Check(testkit.Rows(fmt.Sprint(externalTS)))
+	tk.MustQuery("select * from t").Check(testkit.Rows("1"))
+	// The external timestamp can't go backwards.
+	_, err := tk.Exec(fmt.Sprintf("set global tidb_external_ts = %d", externalTS-1))
+	c.Assert(err, NotNil)
+	// The writes are not affected.
+	tk.MustExec("insert into t values (3)")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1"))
+
+	tk.MustExec("set @@tidb_enable_external_ts_read = off")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1", "2", "3"))
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/opentracing/basictracer-go"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
@@ -41,6 +42,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/tracing"
 	"go.uber.org/zap"
 	"sourcegraph.com/sourcegraph/appdash"
 	traceImpl "sourcegraph.com/sourcegraph/appdash/opentracing"
@@ -93,6 +95,8 @@ func (e *TraceExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	switch e.format {
 	case core.TraceFormatLog:
 		return e.nextTraceLog(ctx, se, req)
+	case core.TraceFormatOTel:
+		return e.nextOTel(ctx, se, req)
 	default:
 		return e.nextRowJSON(ctx, se, req)
 	}
@@ -190,6 +194,33 @@ func (e *TraceExec) nextTraceLog(ctx context.Context, se sqlexec.SQLExecutor, re
 	return nil
 }
 
+func (e *TraceExec) nextOTel(ctx context.Context, se sqlexec.SQLExecutor, req *chunk.Chunk) error {
+	recorder := basictracer.NewInMemoryRecorder()
+	tracer := basictracer.New(recorder)
+	span := tracer.StartSpan("trace")
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	e.executeChild(ctx, se)
+	span.Finish()
+
+	data, err := tracing.EncodeOTLP("TiDB", recorder.GetSpans())
+	if err != nil {
+		return err
+	}
+	if endpoint := config.GetGlobalConfig().OpenTracing.Reporter.OTLPEndpoint; endpoint != "" {
+		exportCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err = tracing.ExportOTLP(exportCtx, endpoint, data)
+		cancel()
+		if err != nil {
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		}
+	}
+
+	appendSplitRows(data, req)
+	e.exhausted = true
+	return nil
+}
+
 func (e *TraceExec) nextRowJSON(ctx context.Context, se sqlexec.SQLExecutor, req *chunk.Chunk) error {
 	store := appdash.NewMemoryStore()
 	tracer := traceImpl.NewTracer(store)
@@ -222,15 +253,19 @@ func (e *TraceExec) nextRowJSON(ctx context.Context, se sqlexec.SQLExecutor, req
 		return errors.Trace(err)
 	}
 
-	// Split json data into rows to avoid the max packet size limitation.
+	appendSplitRows(data, req)
+	e.exhausted = true
+	return nil
+}
+
+// appendSplitRows splits json data into rows to avoid the max packet size limitation.
+func appendSplitRows(data []byte, req *chunk.Chunk) {
 	const maxRowLen = 4096
 	for len(data) > maxRowLen {
 		req.AppendString(0, string(data[:maxRowLen]))
 		data = data[maxRowLen:]
 	}
 	req.AppendString(0, string(data))
-	e.exhausted = true
-	return nil
 }
 
 func (e *TraceExec) executeChild(ctx context.Context, se sqlexec.SQLExecutor) {
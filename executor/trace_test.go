@@ -15,6 +15,9 @@
 package executor_test
 
 import (
+	"encoding/json"
+	"strings"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/util/testkit"
 )
@@ -50,6 +53,35 @@ func (s *testSuite1) TestTraceExec(c *C) {
 	tk.MustExec("trace format='log' insert into trace (c1, c2, c3) values (1, 2, 3)")
 	rows = tk.MustQuery("trace format='log' select * from trace where id = 0;").Rows()
 	c.Assert(len(rows), GreaterEqual, 1)
+
+	rows = tk.MustQuery("trace format='otel' select * from trace where c1 = 1;").Rows()
+	var data strings.Builder
+	for _, row := range rows {
+		data.WriteString(row[0].(string))
+	}
+	var traces struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					Name string `json:"name"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	c.Assert(json.Unmarshal([]byte(data.String()), &traces), IsNil)
+	c.Assert(traces.ResourceSpans, HasLen, 1)
+	c.Assert(traces.ResourceSpans[0].ScopeSpans, HasLen, 1)
+	names := make(map[string]struct{})
+	for _, span := range traces.ResourceSpans[0].ScopeSpans[0].Spans {
+		names[span.Name] = struct{}{}
+	}
+	for _, name := range []string{"trace", "session.ExecuteStmt", "planner.Optimize", "copIteratorWorker.handleTask"} {
+		_, ok := names[name]
+		c.Assert(ok, IsTrue, Commentf("span %s is not found", name))
+	}
+
+	_, err := tk.Exec("trace format='unknown' select * from trace")
+	c.Assert(err, ErrorMatches, ".*trace format should be one of 'row', 'log', 'json' or 'otel'.*")
 }
 
 func rowsOrdered(rows [][]interface{}) bool {
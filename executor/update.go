@@ -197,7 +197,7 @@ func (e *UpdateExec) exec(ctx context.Context, schema *expression.Schema, row, n
 
 		sc := e.ctx.GetSessionVars().StmtCtx
 		if kv.ErrKeyExists.Equal(err1) && sc.DupKeyAsWarning {
-			sc.AppendWarning(err1)
+			appendDMLError(e.ctx, newTableData, err1)
 			continue
 		}
 		return err1
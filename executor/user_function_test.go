@@ -0,0 +1,85 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// wasmAddModule exports `add_ints(i64, i64) i64`.
+	wasmAddModule = "x'0061736d0100000001070160027e7e017e03020100070c01086164645f696e747300000a09010700200020017c0b'"
+	// wasmTimesModule exports `times(f64, f64) f64`.
+	wasmTimesModule = "x'0061736d0100000001070160027c7c017c030201000709010574696d657300000a0901070020002001a20b'"
+)
+
+func TestUserDefinedFunction(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create function `Add_Ints`(a bigint, b bigint) returns bigint language wasm as " + wasmAddModule)
+	tk.MustExec("create function times(a double, b double) returns double language wasm as " + wasmTimesModule)
+	tk.MustQuery("select name, language from mysql.user_functions order by name").Check(testkit.Rows("add_ints WASM", "times WASM"))
+
+	tk.MustQuery("select add_ints(1, 2), ADD_INTS(-1, 1), times(1.5, 2), add_ints(null, 1)").Check(testkit.Rows("3 0 3 <nil>"))
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b double)")
+	tk.MustExec("insert into t values (1, 0.5), (2, 1.5), (null, 2.5)")
+	tk.MustQuery("select add_ints(a, 10), times(a, b) from t order by b").Check(testkit.Rows("11 0.5", "12 3", "<nil> <nil>"))
+	tk.MustQuery("select a from t where add_ints(a, a) > 2").Check(testkit.Rows("2"))
+
+	// The other sessions use the function as well.
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustQuery("select test.add_ints(1, 1)").Check(testkit.Rows("2"))
+
+	tk.MustGetErrMsg("create function add_ints(a bigint, b bigint) returns bigint language wasm as "+wasmAddModule,
+		"[executor:1304]FUNCTION add_ints already exists")
+	tk.MustExec("create function if not exists add_ints(a bigint, b bigint) returns bigint language wasm as " + wasmAddModule)
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1304 FUNCTION add_ints already exists"))
+	tk.MustGetErrMsg("create function abs(a bigint) returns bigint language wasm as "+wasmAddModule,
+		"[executor:1585]This function 'abs' has the same name as a native function")
+	tk.MustGetErrMsg("create function sub(a bigint, b bigint) returns bigint language wasm as "+wasmAddModule,
+		"[expression:8249]Invalid user-defined function 'sub': the module doesn't export the function 'sub'")
+	tk.MustGetErrMsg("create function add2(a bigint) returns bigint language lua as 'return a'",
+		"[expression:8249]Invalid user-defined function 'add2': language LUA is not supported")
+	tk.MustGetErrMsg("create function times(a double, b double) returns varchar(10) language wasm as "+wasmTimesModule,
+		"[expression:8249]Invalid user-defined function 'times': return type varchar is not supported")
+	tk.MustGetErrCode("select add_ints(1)", 1582)
+
+	tk.MustExec("drop function add_ints")
+	tk.MustGetErrMsg("select add_ints(1, 2)", "[expression:1305]FUNCTION test.add_ints does not exist")
+	tk2.MustGetErrMsg("select add_ints(1, 2)", "[planner:1046]No database selected")
+	tk.MustGetErrMsg("drop function add_ints", "[executor:1305]FUNCTION add_ints does not exist")
+	tk.MustExec("drop function if exists add_ints")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1305 FUNCTION add_ints does not exist"))
+	tk.MustQuery("select name from mysql.user_functions").Check(testkit.Rows("times"))
+
+	// Creating and dropping the functions require the privileges on the mysql schema.
+	tk.MustExec("create user 'udf_user'@'%'")
+	userTK := testkit.NewTestKit(t, store)
+	require.True(t, userTK.Session().Auth(&auth.UserIdentity{Username: "udf_user", Hostname: "%"}, nil, nil))
+	userTK.MustQuery("select times(2, 3)").Check(testkit.Rows("6"))
+	userTK.MustGetErrCode("create function add_ints(a bigint, b bigint) returns bigint language wasm as "+wasmAddModule, 1142)
+	userTK.MustGetErrCode("drop function times", 1142)
+	tk.MustExec("grant insert, delete on mysql.user_functions to 'udf_user'@'%'")
+	userTK.MustExec("create function add_ints(a bigint, b bigint) returns bigint language wasm as " + wasmAddModule)
+	userTK.MustExec("drop function add_ints")
+}
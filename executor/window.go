@@ -239,6 +239,8 @@ type rowFrameWindowProcessor struct {
 	start          *core.FrameBound
 	end            *core.FrameBound
 	curRowIdx      uint64
+	// excluder is not nil if some rows are excluded from the frames by the EXCLUDE clause.
+	excluder *frameExcluder
 }
 
 func (p *rowFrameWindowProcessor) getStartOffset(numRows uint64) uint64 {
@@ -312,6 +314,15 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 	for ; remained > 0; lastStart, lastEnd = start, end {
 		start = p.getStartOffset(numRows)
 		end = p.getEndOffset(numRows)
+		if p.excluder != nil {
+			err = p.excluder.appendResult2Chunk(ctx, p.windowFuncs, p.partialResults, rows, p.curRowIdx, start, end, chk)
+			if err != nil {
+				return nil, err
+			}
+			p.curRowIdx++
+			remained--
+			continue
+		}
 		p.curRowIdx++
 		remained--
 		shiftStart = start - lastStart
@@ -373,6 +384,9 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 
 func (p *rowFrameWindowProcessor) resetPartialResult() {
 	p.curRowIdx = 0
+	if p.excluder != nil {
+		p.excluder.reset()
+	}
 }
 
 type rangeFrameWindowProcessor struct {
@@ -386,6 +400,8 @@ type rangeFrameWindowProcessor struct {
 	orderByCols     []*expression.Column
 	// expectedCmpResult is used to decide if one value is included in the frame.
 	expectedCmpResult int64
+	// excluder is not nil if some rows are excluded from the frames by the EXCLUDE clause.
+	excluder *frameExcluder
 }
 
 func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
@@ -466,6 +482,15 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 		if err != nil {
 			return nil, err
 		}
+		if p.excluder != nil {
+			err = p.excluder.appendResult2Chunk(ctx, p.windowFuncs, p.partialResults, rows, p.curRowIdx, start, end, chk)
+			if err != nil {
+				return nil, err
+			}
+			p.curRowIdx++
+			remained--
+			continue
+		}
 		p.curRowIdx++
 		remained--
 		shiftStart = start - lastStart
@@ -530,4 +555,112 @@ func (p *rangeFrameWindowProcessor) resetPartialResult() {
 	p.curRowIdx = 0
 	p.lastStartOffset = 0
 	p.lastEndOffset = 0
+	if p.excluder != nil {
+		p.excluder.reset()
+	}
+}
+
+// frameExcluder removes the rows specified by the EXCLUDE clause from the window frames.
+type frameExcluder struct {
+	exclusion ast.FrameExclusion
+	cmpFuncs  []chunk.CompareFunc
+	colIdx    []int
+	// peerStart and peerEnd are the offsets of the peers of the current row, which are
+	// the rows having the same value of the ORDER BY items as the current row.
+	peerStart uint64
+	peerEnd   uint64
+	excluded  [2][2]uint64
+}
+
+func newFrameExcluder(exclusion ast.FrameExclusion, orderByCols []*expression.Column) *frameExcluder {
+	e := &frameExcluder{exclusion: exclusion}
+	e.cmpFuncs = make([]chunk.CompareFunc, 0, len(orderByCols))
+	e.colIdx = make([]int, 0, len(orderByCols))
+	for _, col := range orderByCols {
+		cmpFunc := chunk.GetCompareFunc(col.RetType)
+		if cmpFunc == nil {
+			continue
+		}
+		e.cmpFuncs = append(e.cmpFuncs, cmpFunc)
+		e.colIdx = append(e.colIdx, col.Index)
+	}
+	return e
+}
+
+func (e *frameExcluder) isPeer(rows []chunk.Row, i, j uint64) bool {
+	for k, idx := range e.colIdx {
+		if e.cmpFuncs[k](rows[i], idx, rows[j], idx) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// updatePeers updates the peers for the current row. The rows are sorted by the ORDER BY items,
+// and the current row only moves forward, so the peers are found incrementally.
+func (e *frameExcluder) updatePeers(rows []chunk.Row, curRowIdx uint64) {
+	if curRowIdx < e.peerEnd {
+		return
+	}
+	numRows := uint64(len(rows))
+	e.peerStart, e.peerEnd = curRowIdx, curRowIdx+1
+	for e.peerEnd < numRows && e.isPeer(rows, curRowIdx, e.peerEnd) {
+		e.peerEnd++
+	}
+}
+
+// excludedRanges returns the ranges of rows excluded from the frame of the current row.
+// The ranges are sorted and don't overlap with each other.
+func (e *frameExcluder) excludedRanges(rows []chunk.Row, curRowIdx uint64) [][2]uint64 {
+	switch e.exclusion {
+	case ast.ExcludeCurrentRow:
+		e.excluded[0] = [2]uint64{curRowIdx, curRowIdx + 1}
+		return e.excluded[:1]
+	case ast.ExcludeGroup:
+		e.updatePeers(rows, curRowIdx)
+		e.excluded[0] = [2]uint64{e.peerStart, e.peerEnd}
+		return e.excluded[:1]
+	case ast.ExcludeTies:
+		e.updatePeers(rows, curRowIdx)
+		e.excluded[0] = [2]uint64{e.peerStart, curRowIdx}
+		e.excluded[1] = [2]uint64{curRowIdx + 1, e.peerEnd}
+		return e.excluded[:2]
+	}
+	return nil
+}
+
+// appendResult2Chunk computes the window functions over the frame [start, end) of the current row
+// without the excluded rows, and appends the results to chk.
+func (e *frameExcluder) appendResult2Chunk(ctx sessionctx.Context, windowFuncs []aggfuncs.AggFunc, partialResults []aggfuncs.PartialResult,
+	rows []chunk.Row, curRowIdx, start, end uint64, chk *chunk.Chunk) error {
+	excluded := e.excludedRanges(rows, curRowIdx)
+	for i, windowFunc := range windowFuncs {
+		pos := start
+		for j := 0; j <= len(excluded); j++ {
+			segEnd, nextPos := end, end
+			if j < len(excluded) {
+				segEnd = mathutil.MaxUint64(pos, mathutil.MinUint64(excluded[j][0], end))
+				nextPos = mathutil.MaxUint64(segEnd, mathutil.MinUint64(excluded[j][1], end))
+			}
+			if pos < segEnd {
+				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
+					minMaxSlidingWindowAggFunc.SetWindowStart(pos)
+				}
+				if _, err := windowFunc.UpdatePartialResult(ctx, rows[pos:segEnd], partialResults[i]); err != nil {
+					return err
+				}
+			}
+			pos = nextPos
+		}
+		if err := windowFunc.AppendFinalResult2Chunk(ctx, partialResults[i], chk); err != nil {
+			return err
+		}
+		windowFunc.ResetPartialResult(partialResults[i])
+	}
+	return nil
+}
+
+func (e *frameExcluder) reset() {
+	e.peerStart = 0
+	e.peerEnd = 0
 }
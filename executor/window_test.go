@@ -444,6 +444,38 @@ func baseTestSlidingWindowFunctions(tk *testkit.TestKit) {
 	result.Check(testkit.Rows("<nil> 11", "<nil> 11", "M 5", "F 5", "F 4", "F 3", "M 2"))
 }
 
+func (s *testSuite7) TestWindowFrameExclusion(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (p int, a int, v int)")
+	tk.MustExec("insert into t values (1, 1, 1), (1, 2, 2), (1, 2, 3), (1, 3, 4), (2, 1, 5), (2, 1, 6)")
+	for _, concurrency := range []int{1, 4} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_window_concurrency = %d", concurrency))
+		tk.MustQuery("select p, a, v, sum(v) over w, count(v) over w from t " +
+			"window w as (partition by p order by a, v rows between 1 preceding and 1 following exclude current row) order by p, a, v").Check(testkit.Rows(
+			"1 1 1 2 1", "1 2 2 4 2", "1 2 3 6 2", "1 3 4 3 1", "2 1 5 6 1", "2 1 6 5 1"))
+		tk.MustQuery("select p, a, v, sum(v) over w, count(v) over w from t " +
+			"window w as (partition by p order by a range between unbounded preceding and unbounded following exclude group) order by p, a, v").Check(testkit.Rows(
+			"1 1 1 9 3", "1 2 2 5 2", "1 2 3 5 2", "1 3 4 6 3", "2 1 5 <nil> 0", "2 1 6 <nil> 0"))
+		tk.MustQuery("select p, a, v, sum(v) over (partition by p order by a range between unbounded preceding and current row exclude ties) from t order by p, a, v").Check(testkit.Rows(
+			"1 1 1 1", "1 2 2 3", "1 2 3 4", "1 3 4 10", "2 1 5 5", "2 1 6 6"))
+		tk.MustQuery("select p, a, v, sum(v) over (partition by p order by a range between unbounded preceding and current row exclude no others) from t order by p, a, v").Check(testkit.Rows(
+			"1 1 1 1", "1 2 2 6", "1 2 3 6", "1 3 4 10", "2 1 5 11", "2 1 6 11"))
+		tk.MustQuery("select p, v, first_value(v) over w, last_value(v) over w, nth_value(v, 2) over w, max(v) over w, min(v) over w from t " +
+			"window w as (partition by p order by a, v rows between unbounded preceding and unbounded following exclude current row) order by p, v").Check(testkit.Rows(
+			"1 1 2 4 3 4 2", "1 2 1 4 3 4 1", "1 3 1 4 2 4 1", "1 4 1 3 2 3 1", "2 5 6 6 <nil> 6 6", "2 6 5 5 <nil> 5 5"))
+		// The frame with the EXCLUDE clause can extend a named window.
+		tk.MustQuery("select p, v, sum(v) over (w rows between 1 preceding and current row exclude current row), sum(v) over w from t " +
+			"window w as (partition by p order by a, v) order by p, v").Check(testkit.Rows(
+			"1 1 <nil> 1", "1 2 1 3", "1 3 2 6", "1 4 3 10", "2 5 <nil> 5", "2 6 5 11"))
+	}
+	tk.MustExec("set @@tidb_window_concurrency = 1")
+	rows := tk.MustQuery("explain format = 'brief' select sum(v) over w, count(v) over w from t window w as (order by a rows between 1 preceding and 1 following exclude ties)").Rows()
+	c.Assert(rows[1][0], Equals, "└─Window")
+	c.Assert(rows[1][4], Matches, ".*rows between 1 preceding and 1 following exclude ties.*")
+}
+
 func (s *testSuite7) TestIssue24264(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
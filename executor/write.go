@@ -20,6 +20,7 @@ import (
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
@@ -112,16 +113,15 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 					return false, err
 				}
 			}
-			if col.IsPKHandleColumn(t.Meta()) {
+			if col.IsPKHandleColumn(t.Meta()) || col.IsCommonHandleColumn(t.Meta()) {
 				handleChanged = true
-				// Rebase auto random id if the field is changed.
+			}
+			// Rebase auto random id if the field is changed.
+			if ddl.IsAutoRandomColumnID(t.Meta(), col.ID) {
 				if err := rebaseAutoRandomValue(ctx, sctx, t, &newData[i], col); err != nil {
 					return false, err
 				}
 			}
-			if col.IsCommonHandleColumn(t.Meta()) {
-				handleChanged = true
-			}
 		} else {
 			if mysql.HasOnUpdateNowFlag(col.Flag) && modified[i] {
 				// It's for "UPDATE t SET ts = ts" and ts is a timestamp.
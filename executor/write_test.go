@@ -15,6 +15,7 @@
 package executor_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -2245,6 +2246,113 @@ func TestLoadDataIntoPartitionedTable(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLoadDataResume(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b varchar(10))")
+	tableID := tk.MustQuery("select tidb_table_id from information_schema.tables where table_schema = 'test' and table_name = 't'").Rows()[0][0]
+	ctx := tk.Session().(sessionctx.Context)
+	data := []byte("a,b\n1,a\n2,\"b\nb\"\n3,c\n4,d\n5,e\n")
+	loadSQL := "load data local infile '/tmp/nonexistence.csv' into table t fields terminated by ',' enclosed by '\"' ignore 1 lines"
+
+	// The load is interrupted after the first batch is committed.
+	tk.MustExec(loadSQL)
+	ld := ctx.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+	ld.SetMaxRowsInBatch(2)
+	require.NoError(t, ctx.NewTxn(context.Background()))
+	_, reachLimit, err := ld.InsertData(context.Background(), nil, data)
+	require.NoError(t, err)
+	require.True(t, reachLimit)
+	require.NoError(t, ld.CommitOneTask(context.Background(), ld.MakeCommitTask()))
+	ctx.SetValue(executor.LoadDataVarKey, nil)
+	tk.MustQuery("select path, line_count, record_count, skipped_count, warning_count from mysql.load_data_progress where table_id = ?", tableID).
+		Check(testkit.Rows("/tmp/nonexistence.csv 3 2 0 0"))
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 a", "2 b\nb"))
+
+	// The committed lines are skipped when resuming, and the progress is removed after the load finishes.
+	tk.MustExec("set @@tidb_load_data_resume = 1")
+	tk.MustExec(loadSQL)
+	ld = ctx.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+	ld.SetMaxRowsInBatch(2)
+	require.NoError(t, ctx.NewTxn(context.Background()))
+	for {
+		data, reachLimit, err = ld.InsertData(context.Background(), nil, data)
+		require.NoError(t, err)
+		require.NoError(t, ld.EnqOneTask(context.Background()))
+		if !reachLimit {
+			break
+		}
+	}
+	ld.CloseTaskQueue()
+	require.NoError(t, ld.CommitWork(context.Background()))
+	ctx.SetValue(executor.LoadDataVarKey, nil)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 a", "2 b\nb", "3 c", "4 d", "5 e"))
+	tk.MustQuery("select count(*) from mysql.load_data_progress").Check(testkit.Rows("0"))
+
+	// The progress of the loads of different files into the same table is recorded separately.
+	tk.MustExec("delete from t")
+	tk.MustExec("set @@tidb_load_data_resume = 0")
+	for _, path := range []string{"/tmp/a.csv", "/tmp/b.csv"} {
+		tk.MustExec(fmt.Sprintf("load data local infile '%s' into table t fields terminated by ',' enclosed by '\"' ignore 1 lines", path))
+		ld = ctx.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+		ld.SetMaxRowsInBatch(2)
+		require.NoError(t, ctx.NewTxn(context.Background()))
+		_, _, err = ld.InsertData(context.Background(), nil, []byte("a,b\n1,a\n2,b\n3,c\n"))
+		require.NoError(t, err)
+		require.NoError(t, ld.CommitOneTask(context.Background(), ld.MakeCommitTask()))
+		ctx.SetValue(executor.LoadDataVarKey, nil)
+	}
+	tk.MustQuery("select path, line_count from mysql.load_data_progress where table_id = ? order by path", tableID).
+		Check(testkit.Rows("/tmp/a.csv 3", "/tmp/b.csv 3"))
+
+	// The load isn't resumed if the committed lines of the file are changed or missing.
+	tk.MustExec("set @@tidb_load_data_resume = 1")
+	tk.MustExec("load data local infile '/tmp/a.csv' into table t fields terminated by ',' enclosed by '\"' ignore 1 lines")
+	ld = ctx.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+	ld.SetMaxRowsInBatch(2)
+	require.NoError(t, ctx.NewTxn(context.Background()))
+	_, _, err = ld.InsertData(context.Background(), nil, []byte("a,b\n1,a\n2,changed\n3,c\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "doesn't match the recorded progress")
+	ctx.SetValue(executor.LoadDataVarKey, nil)
+	tk.MustExec("load data local infile '/tmp/b.csv' into table t fields terminated by ',' enclosed by '\"' ignore 1 lines")
+	ld = ctx.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+	ld.SetMaxRowsInBatch(2)
+	require.NoError(t, ctx.NewTxn(context.Background()))
+	_, _, err = ld.InsertData(context.Background(), nil, []byte("a,b\n1,a\n"))
+	require.NoError(t, err)
+	ld.CloseTaskQueue()
+	err = ld.CommitWork(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "doesn't match the recorded progress")
+	ctx.SetValue(executor.LoadDataVarKey, nil)
+	tk.MustQuery("select count(*) from mysql.load_data_progress").Check(testkit.Rows("2"))
+	tk.MustExec("delete from mysql.load_data_progress")
+
+	// The lines of a large piece of data are parsed by several workers.
+	tk.MustExec("truncate table t")
+	tk.MustExec("set @@tidb_executor_concurrency = 4")
+	var buf bytes.Buffer
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&buf, "%d,\"%d\"\n", i, i%10)
+	}
+	tk.MustExec("load data local infile '/tmp/nonexistence.csv' into table t fields terminated by ',' enclosed by '\"'")
+	ld = ctx.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+	require.NoError(t, ctx.NewTxn(context.Background()))
+	_, _, err = ld.InsertData(context.Background(), nil, buf.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, ld.CheckAndInsertOneBatch(context.Background(), ld.GetRows(), ld.GetCurBatchCnt()))
+	ctx.StmtCommit()
+	txn, err := ctx.Txn(true)
+	require.NoError(t, err)
+	require.NoError(t, txn.Commit(context.Background()))
+	ctx.SetValue(executor.LoadDataVarKey, nil)
+	tk.MustQuery("select count(*), sum(a), sum(b), min(a), max(a) from t").Check(testkit.Rows("1000 499500 4500 0 999"))
+	tk.MustQuery("select * from t where a in (0, 517, 999)").Check(testkit.Rows("0 0", "517 7", "999 9"))
+}
+
 func TestNullDefault(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
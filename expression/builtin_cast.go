@@ -23,12 +23,15 @@
 package expression
 
 import (
+	"fmt"
 	"math"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
@@ -48,6 +51,7 @@ var (
 	_ functionClass = &castAsTimeFunctionClass{}
 	_ functionClass = &castAsDurationFunctionClass{}
 	_ functionClass = &castAsJSONFunctionClass{}
+	_ functionClass = &castJSONAsArrayFunctionClass{}
 )
 
 var (
@@ -457,6 +461,48 @@ func (c *castAsJSONFunctionClass) getFunction(ctx sessionctx.Context, args []Exp
 	return sig, nil
 }
 
+type castJSONAsArrayFunctionClass struct {
+	baseFunctionClass
+
+	// elemTp is the field type of the array elements, such as `UNSIGNED` in
+	// `CAST(j AS UNSIGNED ARRAY)`.
+	elemTp *types.FieldType
+}
+
+func (c *castJSONAsArrayFunctionClass) verifyArgs(args []Expression) error {
+	if err := c.baseFunctionClass.verifyArgs(args); err != nil {
+		return err
+	}
+	if args[0].GetType().EvalType() != types.ETJson {
+		return errNotSupportedYet.GenWithStackByArgs("CAST-ing non-JSON value to array")
+	}
+	if !isValidArrayElemType(c.elemTp) {
+		return errNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("CAST-ing JSON to %s ARRAY", types.TypeStr(c.elemTp.Tp)))
+	}
+	return nil
+}
+
+func (c *castJSONAsArrayFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFunc(ctx, c.funcName, args, types.ETJson)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp = types.NewFieldType(mysql.TypeJSON)
+	types.SetBinChsClnFlag(bf.tp)
+	bf.tp.Flag |= mysql.ArrayFlag
+	elemTp := c.elemTp.Clone()
+	if elemTp.EvalType() == types.ETString && elemTp.Charset == "" {
+		elemTp.Charset, elemTp.Collate = mysql.DefaultCharset, charset.CollationUTF8MB4
+	}
+	// The array cast is only used by the multi-valued index and is never
+	// pushed down, so no pb code is set here.
+	sig = &builtinCastJSONAsArraySig{bf, elemTp}
+	return sig, nil
+}
+
 type builtinCastIntAsIntSig struct {
 	baseBuiltinCastFunc
 }
@@ -1631,6 +1677,50 @@ func (b *builtinCastJSONAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON,
 	return b.args[0].EvalJSON(b.ctx, row)
 }
 
+type builtinCastJSONAsArraySig struct {
+	baseBuiltinFunc
+
+	elemTp *types.FieldType
+}
+
+func (b *builtinCastJSONAsArraySig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsArraySig{elemTp: b.elemTp.Clone()}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsArraySig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	return b.castToArray(val)
+}
+
+func (b *builtinCastJSONAsArraySig) castToArray(val json.BinaryJSON) (res json.BinaryJSON, isNull bool, err error) {
+	if val.TypeCode == json.TypeCodeLiteral && val.Value[0] == json.LiteralNil {
+		return res, true, nil
+	}
+	if val.TypeCode != json.TypeCodeArray {
+		// A scalar is regarded as an array with a single element.
+		d, err := ConvertJSONToArrayElem(val, b.elemTp)
+		if err != nil {
+			return res, false, err
+		}
+		return json.CreateBinary([]interface{}{d.GetValue()}), false, nil
+	}
+	elemCount := val.GetElemCount()
+	elems := make([]interface{}, 0, elemCount)
+	for i := 0; i < elemCount; i++ {
+		d, err := ConvertJSONToArrayElem(val.ArrayGetElem(i), b.elemTp)
+		if err != nil {
+			return res, false, err
+		}
+		elems = append(elems, d.GetValue())
+	}
+	return json.CreateBinary(elems), false, nil
+}
+
 type builtinCastJSONAsIntSig struct {
 	baseBuiltinCastFunc
 }
@@ -1871,6 +1961,91 @@ func BuildCastFunction(ctx sessionctx.Context, expr Expression, tp *types.FieldT
 	return res
 }
 
+// BuildCastAsArrayFunction builds a `CAST(expr AS elemTp ARRAY)` function,
+// which is only allowed in the definition of a multi-valued index.
+func BuildCastAsArrayFunction(ctx sessionctx.Context, expr Expression, elemTp *types.FieldType) (Expression, error) {
+	fc := &castJSONAsArrayFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, elemTp}
+	f, err := fc.getFunction(ctx, []Expression{expr})
+	if err != nil {
+		return nil, err
+	}
+	return &ScalarFunction{
+		FuncName: model.NewCIStr(ast.Cast),
+		RetType:  f.getRetTp(),
+		Function: f,
+	}, nil
+}
+
+// ArrayCastElemType returns the element type if `expr` is a `CAST(... AS ...
+// ARRAY)` function.
+func ArrayCastElemType(expr Expression) (*types.FieldType, bool) {
+	sf, ok := expr.(*ScalarFunction)
+	if !ok {
+		return nil, false
+	}
+	sig, ok := sf.Function.(*builtinCastJSONAsArraySig)
+	if !ok {
+		return nil, false
+	}
+	return sig.elemTp, true
+}
+
+// isValidArrayElemType checks whether the field type can be used as the
+// element type of an array cast. Only integers and strings are supported now.
+func isValidArrayElemType(tp *types.FieldType) bool {
+	switch tp.Tp {
+	case mysql.TypeLonglong:
+		return true
+	case mysql.TypeVarString, mysql.TypeString, mysql.TypeVarchar:
+		return tp.Charset != charset.CharsetBin
+	default:
+		return false
+	}
+}
+
+// ConvertJSONToArrayElem converts an element of a JSON array to the datum of
+// the given element type. The datum kind is the same one used to encode the
+// keys of a multi-valued index.
+func ConvertJSONToArrayElem(elem json.BinaryJSON, tp *types.FieldType) (types.Datum, error) {
+	unsigned := mysql.HasUnsignedFlag(tp.Flag)
+	typeStr := types.TypeStr(tp.Tp)
+	if tp.EvalType() == types.ETInt && unsigned {
+		typeStr = "unsigned " + typeStr
+	}
+	switch tp.EvalType() {
+	case types.ETInt:
+		switch elem.TypeCode {
+		case json.TypeCodeInt64:
+			v := elem.GetInt64()
+			if unsigned {
+				if v < 0 {
+					return types.Datum{}, errJSONValueOutOfRangeForFuncIndex.GenWithStackByArgs(typeStr)
+				}
+				return types.NewUintDatum(uint64(v)), nil
+			}
+			return types.NewIntDatum(v), nil
+		case json.TypeCodeUint64:
+			v := elem.GetUint64()
+			if unsigned {
+				return types.NewUintDatum(v), nil
+			}
+			if v > math.MaxInt64 {
+				return types.Datum{}, errJSONValueOutOfRangeForFuncIndex.GenWithStackByArgs(typeStr)
+			}
+			return types.NewIntDatum(int64(v)), nil
+		}
+	case types.ETString:
+		if elem.TypeCode == json.TypeCodeString {
+			v := string(elem.GetString())
+			if tp.Flen != types.UnspecifiedLength && utf8.RuneCountInString(v) > tp.Flen {
+				return types.Datum{}, errJSONValueOutOfRangeForFuncIndex.GenWithStackByArgs(typeStr)
+			}
+			return types.NewStringDatum(v), nil
+		}
+	}
+	return types.Datum{}, errInvalidJSONValueForFuncIndex.GenWithStackByArgs(typeStr)
+}
+
 // WrapWithCastAsInt wraps `expr` with `cast` if the return type of expr is not
 // type int, otherwise, returns `expr` directly.
 func WrapWithCastAsInt(ctx sessionctx.Context, expr Expression) Expression {
@@ -1161,6 +1161,68 @@ func TestCastJSONAsDecimalSig(t *testing.T) {
 	}
 }
 
+func TestCastJSONAsArraySig(t *testing.T) {
+	ctx := createContext(t)
+	col := &Column{RetType: types.NewFieldType(mysql.TypeJSON), Index: 0}
+
+	unsignedTp := types.NewFieldType(mysql.TypeLonglong)
+	unsignedTp.Flag |= mysql.UnsignedFlag
+	charTp := types.NewFieldType(mysql.TypeVarString)
+	charTp.Flen = 2
+	var tests = []struct {
+		tp  *types.FieldType
+		in  string
+		out string
+		err *terror.Error
+	}{
+		{types.NewFieldType(mysql.TypeLonglong), `[1, -2, 3]`, `[1, -2, 3]`, nil},
+		{types.NewFieldType(mysql.TypeLonglong), `1`, `[1]`, nil},
+		{types.NewFieldType(mysql.TypeLonglong), `[]`, `[]`, nil},
+		{types.NewFieldType(mysql.TypeLonglong), `[18446744073709551615]`, ``, errJSONValueOutOfRangeForFuncIndex},
+		{types.NewFieldType(mysql.TypeLonglong), `["1"]`, ``, errInvalidJSONValueForFuncIndex},
+		{unsignedTp, `[1, 18446744073709551615]`, `[1, 18446744073709551615]`, nil},
+		{unsignedTp, `[-1]`, ``, errJSONValueOutOfRangeForFuncIndex},
+		{unsignedTp, `[1.5]`, ``, errInvalidJSONValueForFuncIndex},
+		{charTp, `["a", "中文"]`, `["a", "中文"]`, nil},
+		{charTp, `["abc"]`, ``, errJSONValueOutOfRangeForFuncIndex},
+		{charTp, `[1]`, ``, errInvalidJSONValueForFuncIndex},
+	}
+	for _, tt := range tests {
+		f, err := BuildCastAsArrayFunction(ctx, col, tt.tp)
+		require.NoError(t, err)
+		require.True(t, mysql.HasArrayFlag(f.GetType().Flag))
+		elemTp, ok := ArrayCastElemType(f)
+		require.True(t, ok)
+		require.Equal(t, tt.tp.Tp, elemTp.Tp)
+
+		j, err := json.ParseBinaryFromString(tt.in)
+		require.NoError(t, err)
+		row := chunk.MutRowFromDatums([]types.Datum{types.NewDatum(j)})
+		res, isNull, err := f.EvalJSON(ctx, row.ToRow())
+		if tt.err != nil {
+			require.True(t, tt.err.Equal(err))
+			continue
+		}
+		require.NoError(t, err)
+		require.False(t, isNull)
+		require.Equal(t, tt.out, res.String())
+	}
+
+	// JSON null makes up a NULL array.
+	f, err := BuildCastAsArrayFunction(ctx, col, unsignedTp)
+	require.NoError(t, err)
+	row := chunk.MutRowFromDatums([]types.Datum{types.NewDatum(json.CreateBinary(nil))})
+	_, isNull, err := f.EvalJSON(ctx, row.ToRow())
+	require.NoError(t, err)
+	require.True(t, isNull)
+
+	// Only JSON value can be cast to the array of integer or string.
+	_, err = BuildCastAsArrayFunction(ctx, &Column{RetType: types.NewFieldType(mysql.TypeLonglong), Index: 0}, unsignedTp)
+	require.True(t, errNotSupportedYet.Equal(err))
+	_, err = BuildCastAsArrayFunction(ctx, col, types.NewFieldType(mysql.TypeDouble))
+	require.True(t, errNotSupportedYet.Equal(err))
+}
+
 // TestWrapWithCastAsTypesClasses tests WrapWithCastAsInt/Real/String/Decimal.
 func TestWrapWithCastAsTypesClasses(t *testing.T) {
 	ctx := createContext(t)
@@ -1467,16 +1467,17 @@ func (c *compareFunctionClass) refineArgs(ctx sessionctx.Context, args []Express
 	arg0Type, arg1Type := args[0].GetType(), args[1].GetType()
 	arg0IsInt := arg0Type.EvalType() == types.ETInt
 	arg1IsInt := arg1Type.EvalType() == types.ETInt
-	arg0IsString := arg0Type.EvalType() == types.ETString
-	arg1IsString := arg1Type.EvalType() == types.ETString
 	arg0, arg0IsCon := args[0].(*Constant)
 	arg1, arg1IsCon := args[1].(*Constant)
 	isExceptional, finalArg0, finalArg1 := false, args[0], args[1]
 	isPositiveInfinite, isNegativeInfinite := false, false
 	if MaybeOverOptimized4PlanCache(ctx, args) {
-		// To keep the result be compatible with MySQL, refine `int non-constant <cmp> str constant`
-		// here and skip this refine operation in all other cases for safety.
-		if (arg0IsInt && !arg0IsCon && arg1IsString && arg1IsCon) || (arg1IsInt && !arg1IsCon && arg0IsString && arg0IsCon) {
+		// The refined constant depends on the value of the parameters, so we fold the parameters into the
+		// constant and skip the plan cache to refine `int non-constant <cmp> non-int constant`. For example,
+		// `int_col = ? + 1` with a decimal or string parameter is refined to `int_col = int_const`, which can
+		// be used to build ranges, instead of `cast(int_col) = ? + 1`, which can only be a full scan.
+		// Skip this refine operation in all other cases for safety.
+		if (arg0IsInt && !arg0IsCon && !arg1IsInt && arg1IsCon) || (arg1IsInt && !arg1IsCon && !arg0IsInt && arg0IsCon) {
 			ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
 			RemoveMutableConst(ctx, args)
 		} else {
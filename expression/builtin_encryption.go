@@ -55,6 +55,7 @@ var (
 	_ functionClass = &randomBytesFunctionClass{}
 	_ functionClass = &sha1FunctionClass{}
 	_ functionClass = &sha2FunctionClass{}
+	_ functionClass = &sm3FunctionClass{}
 	_ functionClass = &uncompressFunctionClass{}
 	_ functionClass = &uncompressedLengthFunctionClass{}
 	_ functionClass = &validatePasswordStrengthFunctionClass{}
@@ -71,6 +72,7 @@ var (
 	_ builtinFunc = &builtinRandomBytesSig{}
 	_ builtinFunc = &builtinSHA1Sig{}
 	_ builtinFunc = &builtinSHA2Sig{}
+	_ builtinFunc = &builtinSM3Sig{}
 	_ builtinFunc = &builtinUncompressSig{}
 	_ builtinFunc = &builtinUncompressedLengthSig{}
 )
@@ -99,6 +101,11 @@ var aesModes = map[string]*aesModeAttr{
 	"aes-128-cfb": {"cfb", 16, true},
 	"aes-192-cfb": {"cfb", 24, true},
 	"aes-256-cfb": {"cfb", 32, true},
+	// SM4 only supports 128 bits keys.
+	"sm4-128-ecb": {"sm4-ecb", 16, false},
+	"sm4-128-cbc": {"sm4-cbc", 16, true},
+	"sm4-128-ofb": {"sm4-ofb", 16, true},
+	"sm4-128-cfb": {"sm4-cfb", 16, true},
 }
 
 type aesDecryptFunctionClass struct {
@@ -172,6 +179,8 @@ func (b *builtinAesDecryptSig) evalString(row chunk.Row) (string, bool, error) {
 	switch b.modeName {
 	case "ecb":
 		plainText, err = encrypt.AESDecryptWithECB([]byte(cryptStr), key)
+	case "sm4-ecb":
+		plainText, err = encrypt.SM4DecryptWithECB([]byte(cryptStr), key)
 	default:
 		return "", true, errors.Errorf("unsupported block encryption mode - %v", b.modeName)
 	}
@@ -226,6 +235,12 @@ func (b *builtinAesDecryptIVSig) evalString(row chunk.Row) (string, bool, error)
 		plainText, err = encrypt.AESDecryptWithOFB([]byte(cryptStr), key, []byte(iv))
 	case "cfb":
 		plainText, err = encrypt.AESDecryptWithCFB([]byte(cryptStr), key, []byte(iv))
+	case "sm4-cbc":
+		plainText, err = encrypt.SM4DecryptWithCBC([]byte(cryptStr), key, []byte(iv))
+	case "sm4-ofb":
+		plainText, err = encrypt.SM4DecryptWithOFB([]byte(cryptStr), key, []byte(iv))
+	case "sm4-cfb":
+		plainText, err = encrypt.SM4DecryptWithCFB([]byte(cryptStr), key, []byte(iv))
 	default:
 		return "", true, errors.Errorf("unsupported block encryption mode - %v", b.modeName)
 	}
@@ -306,6 +321,8 @@ func (b *builtinAesEncryptSig) evalString(row chunk.Row) (string, bool, error) {
 	switch b.modeName {
 	case "ecb":
 		cipherText, err = encrypt.AESEncryptWithECB([]byte(str), key)
+	case "sm4-ecb":
+		cipherText, err = encrypt.SM4EncryptWithECB([]byte(str), key)
 	default:
 		return "", true, errors.Errorf("unsupported block encryption mode - %v", b.modeName)
 	}
@@ -360,6 +377,12 @@ func (b *builtinAesEncryptIVSig) evalString(row chunk.Row) (string, bool, error)
 		cipherText, err = encrypt.AESEncryptWithOFB([]byte(str), key, []byte(iv))
 	case "cfb":
 		cipherText, err = encrypt.AESEncryptWithCFB([]byte(str), key, []byte(iv))
+	case "sm4-cbc":
+		cipherText, err = encrypt.SM4EncryptWithCBC([]byte(str), key, []byte(iv))
+	case "sm4-ofb":
+		cipherText, err = encrypt.SM4EncryptWithOFB([]byte(str), key, []byte(iv))
+	case "sm4-cfb":
+		cipherText, err = encrypt.SM4EncryptWithCFB([]byte(str), key, []byte(iv))
 	default:
 		return "", true, errors.Errorf("unsupported block encryption mode - %v", b.modeName)
 	}
@@ -747,6 +770,45 @@ func (b *builtinSHA2Sig) evalString(row chunk.Row) (string, bool, error) {
 	return fmt.Sprintf("%x", hasher.Sum(nil)), false, nil
 }
 
+type sm3FunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *sm3FunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Charset, bf.tp.Collate = ctx.GetSessionVars().GetCharsetInfo()
+	bf.tp.Flen = auth.SM3Size * 2
+	sig := &builtinSM3Sig{bf}
+	return sig, nil
+}
+
+type builtinSM3Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSM3Sig) Clone() builtinFunc {
+	newSig := &builtinSM3Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SM3(str).
+// The value is returned as a string of 64 hexadecimal digits, or NULL if the argument was NULL.
+func (b *builtinSM3Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	sum := auth.SM3([]byte(str))
+	return fmt.Sprintf("%x", sum), false, nil
+}
+
 // deflate compresses a string using the DEFLATE format.
 func deflate(data []byte) ([]byte, error) {
 	var buffer bytes.Buffer
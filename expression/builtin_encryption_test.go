@@ -140,6 +140,13 @@ var aesTests = []struct {
 	{"aes-256-cfb", "pingcap", []interface{}{"1234567890123456", "1234567890123456"}, "2E70FCAC0C0834"},
 	{"aes-256-cfb", "pingcap", []interface{}{"12345678901234561234567890123456", "1234567890123456"}, "83E2B30A71F011"},
 	{"aes-256-cfb", "pingcap", []interface{}{"1234567890123456", "12345678901234561234567890123456"}, "2E70FCAC0C0834"},
+	// test for sm4
+	{"sm4-128-ecb", "pingcap", []interface{}{"1234567890123456"}, "BFD68794E492543137C9F39ABA492F8B"},
+	{"sm4-128-ecb", "pingcap", []interface{}{"123"}, "D032D169B28E9CFE6E7575EB2DDFA6A4"},
+	{"sm4-128-ecb", nil, []interface{}{123}, nil},
+	{"sm4-128-cbc", "pingcap", []interface{}{"1234567890123456", "1234567890123456"}, "6FB923BE2E7A60CB7D33292FF18E596C"},
+	{"sm4-128-ofb", "pingcap", []interface{}{"1234567890123456", "1234567890123456"}, "C0EAB2A7CA973B"},
+	{"sm4-128-cfb", "pingcap", []interface{}{"1234567890123456", "1234567890123456"}, "C0EAB2A7CA973B"},
 }
 
 func TestAESEncrypt(t *testing.T) {
@@ -380,6 +387,29 @@ func TestSha1Hash(t *testing.T) {
 	require.True(t, crypt.IsNull())
 }
 
+func TestSM3Hash(t *testing.T) {
+	ctx := createContext(t)
+	sm3Tests := []struct {
+		origin interface{}
+		crypt  interface{}
+	}{
+		{"test", "55e12e91650d2fec56ec74e1d3e4ddbfce2ef3a65890c2a19ecf88a307e76a23"},
+		{"pingcap", "809a8d2e0bed324a3efc7a3024541d6d4537e14804539724edb0c62228d111db"},
+		{"", "1ab21d8355cfa17f8e61194831e81a8f22bec8c728fefb747ed035eb5082aa2b"},
+		{123.45, "44842509aa4dea2260e4ecc3d62819c1cada14329da1632035940ef39516351a"},
+		{nil, nil},
+	}
+
+	fc := funcs[ast.SM3]
+	for _, tt := range sm3Tests {
+		f, err := fc.getFunction(ctx, primitiveValsToConstants(ctx, []interface{}{tt.origin}))
+		require.NoError(t, err)
+		crypt, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, types.NewDatum(tt.crypt), crypt)
+	}
+}
+
 func TestSha2Hash(t *testing.T) {
 	ctx := createContext(t)
 	sha2Tests := []struct {
@@ -59,7 +59,13 @@ func (b *builtinAesDecryptSig) vecEvalString(input *chunk.Chunk, result *chunk.C
 		return err
 	}
 
-	if b.modeName != "ecb" {
+	var decrypt func(cryptStr, key []byte) ([]byte, error)
+	switch b.modeName {
+	case "ecb":
+		decrypt = encrypt.AESDecryptWithECB
+	case "sm4-ecb":
+		decrypt = encrypt.SM4DecryptWithECB
+	default:
 		return errors.Errorf("unsupported block encryption mode - %v", b.modeName)
 	}
 
@@ -90,7 +96,7 @@ func (b *builtinAesDecryptSig) vecEvalString(input *chunk.Chunk, result *chunk.C
 		// we can't use GetBytes here because GetBytes return raw memory in strBuf,
 		// and the memory will be modified in AESEncryptWithECB & AESDecryptWithECB
 		str := []byte(strBuf.GetString(i))
-		plainText, err := encrypt.AESDecryptWithECB(str, key)
+		plainText, err := decrypt(str, key)
 		if err != nil {
 			result.AppendNull()
 			continue
@@ -136,16 +142,20 @@ func (b *builtinAesEncryptIVSig) vecEvalString(input *chunk.Chunk, result *chunk
 		return err
 	}
 
-	isCBC := false
-	isOFB := false
-	isCFB := false
+	var encryptIV func(str, key, iv []byte) ([]byte, error)
 	switch b.modeName {
 	case "cbc":
-		isCBC = true
+		encryptIV = encrypt.AESEncryptWithCBC
 	case "ofb":
-		isOFB = true
+		encryptIV = encrypt.AESEncryptWithOFB
 	case "cfb":
-		isCFB = true
+		encryptIV = encrypt.AESEncryptWithCFB
+	case "sm4-cbc":
+		encryptIV = encrypt.SM4EncryptWithCBC
+	case "sm4-ofb":
+		encryptIV = encrypt.SM4EncryptWithOFB
+	case "sm4-cfb":
+		encryptIV = encrypt.SM4EncryptWithCFB
 	default:
 		return errors.Errorf("unsupported block encryption mode - %v", b.modeName)
 	}
@@ -173,20 +183,11 @@ func (b *builtinAesEncryptIVSig) vecEvalString(input *chunk.Chunk, result *chunk
 		if !isConst {
 			key = encrypt.DeriveKeyMySQL(keyBuf.GetBytes(i), b.keySize)
 		}
-		var cipherText []byte
 
 		// ANNOTATION:
 		// we can't use GetBytes here because GetBytes return raw memory in strBuf,
 		// and the memory will be modified in AESEncryptWithCBC & AESEncryptWithOFB & AESEncryptWithCFB
-		if isCBC {
-			cipherText, err = encrypt.AESEncryptWithCBC([]byte(strBuf.GetString(i)), key, iv)
-		}
-		if isOFB {
-			cipherText, err = encrypt.AESEncryptWithOFB([]byte(strBuf.GetString(i)), key, iv)
-		}
-		if isCFB {
-			cipherText, err = encrypt.AESEncryptWithCFB([]byte(strBuf.GetString(i)), key, iv)
-		}
+		cipherText, err := encryptIV([]byte(strBuf.GetString(i)), key, iv)
 		if err != nil {
 			result.AppendNull()
 			continue
@@ -309,16 +310,20 @@ func (b *builtinAesDecryptIVSig) vecEvalString(input *chunk.Chunk, result *chunk
 		return err
 	}
 
-	isCBC := false
-	isOFB := false
-	isCFB := false
+	var decryptIV func(cryptStr, key, iv []byte) ([]byte, error)
 	switch b.modeName {
 	case "cbc":
-		isCBC = true
+		decryptIV = encrypt.AESDecryptWithCBC
 	case "ofb":
-		isOFB = true
+		decryptIV = encrypt.AESDecryptWithOFB
 	case "cfb":
-		isCFB = true
+		decryptIV = encrypt.AESDecryptWithCFB
+	case "sm4-cbc":
+		decryptIV = encrypt.SM4DecryptWithCBC
+	case "sm4-ofb":
+		decryptIV = encrypt.SM4DecryptWithOFB
+	case "sm4-cfb":
+		decryptIV = encrypt.SM4DecryptWithCFB
 	default:
 		return errors.Errorf("unsupported block encryption mode - %v", b.modeName)
 	}
@@ -346,20 +351,11 @@ func (b *builtinAesDecryptIVSig) vecEvalString(input *chunk.Chunk, result *chunk
 		if !isConst {
 			key = encrypt.DeriveKeyMySQL(keyBuf.GetBytes(i), b.keySize)
 		}
-		var plainText []byte
 
 		// ANNOTATION:
 		// we can't use GetBytes here because GetBytes return raw memory in strBuf,
 		// and the memory will be modified in AESDecryptWithCBC & AESDecryptWithOFB & AESDecryptWithCFB
-		if isCBC {
-			plainText, err = encrypt.AESDecryptWithCBC([]byte(strBuf.GetString(i)), key, iv)
-		}
-		if isOFB {
-			plainText, err = encrypt.AESDecryptWithOFB([]byte(strBuf.GetString(i)), key, iv)
-		}
-		if isCFB {
-			plainText, err = encrypt.AESDecryptWithCFB([]byte(strBuf.GetString(i)), key, iv)
-		}
+		plainText, err := decryptIV([]byte(strBuf.GetString(i)), key, iv)
 		if err != nil {
 			result.AppendNull()
 			continue
@@ -625,8 +621,13 @@ func (b *builtinAesEncryptSig) vecEvalString(input *chunk.Chunk, result *chunk.C
 		return err
 	}
 
-	if b.modeName != "ecb" {
-		// For modes that do not require init_vector, it is ignored and a warning is generated if it is specified.
+	var encryptECB func(str, key []byte) ([]byte, error)
+	switch b.modeName {
+	case "ecb":
+		encryptECB = encrypt.AESEncryptWithECB
+	case "sm4-ecb":
+		encryptECB = encrypt.SM4EncryptWithECB
+	default:
 		return errors.Errorf("unsupported block encryption mode - %v", b.modeName)
 	}
 
@@ -655,7 +656,7 @@ func (b *builtinAesEncryptSig) vecEvalString(input *chunk.Chunk, result *chunk.C
 		// NOTE: we can't use GetBytes, because in AESEncryptWithECB padding is automatically
 		//       added to str and this will damange the data layout in chunk.Column
 		str := []byte(strBuf.GetString(i))
-		cipherText, err := encrypt.AESEncryptWithECB(str, key)
+		cipherText, err := encryptECB(str, key)
 		if err != nil {
 			result.AppendNull()
 			continue
@@ -734,6 +735,32 @@ func (b *builtinSHA1Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column)
 	return nil
 }
 
+func (b *builtinSM3Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSM3Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		sum := auth.SM3(buf.GetBytes(i))
+		result.AppendString(fmt.Sprintf("%x", sum))
+	}
+	return nil
+}
+
 func (b *builtinUncompressSig) vectorized() bool {
 	return true
 }
@@ -224,9 +224,10 @@ func (b *builtinCurrentRoleSig) Clone() builtinFunc {
 // See https://dev.mysql.com/doc/refman/8.0/en/information-functions.html#function_current-role
 func (b *builtinCurrentRoleSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
 	data := b.ctx.GetSessionVars()
-	if data == nil || data.ActiveRoles == nil {
+	if data == nil {
 		return "", true, errors.Errorf("Missing session variable when eval builtin")
 	}
+	// No role is active, or the roles are not initialized because the session is not authenticated.
 	if len(data.ActiveRoles) == 0 {
 		return "NONE", false, nil
 	}
@@ -829,19 +830,36 @@ func (b *builtinTiDBDecodeSQLDigestsSig) evalString(row chunk.Row) (string, bool
 		}
 	}
 
+	digests, ok := b.unmarshalDigests(digestsStr)
+	if !ok {
+		return "", true, nil
+	}
+
+	// Query the SQL Statements by digests.
+	retriever := NewSQLDigestTextRetriever()
+	addSQLDigestsToRetriever(retriever, digests)
+	if isNull, err := b.retrieveSQLDigests(retriever); isNull || err != nil {
+		return "", true, err
+	}
+	return b.collectDecodedSQLDigests(retriever, digests, stmtTruncateLength)
+}
+
+// unmarshalDigests parses the argument of TIDB_DECODE_SQL_DIGESTS as a JSON array, a warning is appended if it fails.
+func (b *builtinTiDBDecodeSQLDigestsSig) unmarshalDigests(digestsStr string) ([]interface{}, bool) {
 	var digests []interface{}
-	err = json.Unmarshal([]byte(digestsStr), &digests)
+	err := json.Unmarshal([]byte(digestsStr), &digests)
 	if err != nil {
 		const errMsgMaxLength = 32
 		if len(digestsStr) > errMsgMaxLength {
 			digestsStr = digestsStr[:errMsgMaxLength] + "..."
 		}
 		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errIncorrectArgs.GenWithStack("The argument can't be unmarshalled as JSON array: '%s'", digestsStr))
-		return "", true, nil
+		return nil, false
 	}
+	return digests, true
+}
 
-	// Query the SQL Statements by digests.
-	retriever := NewSQLDigestTextRetriever()
+func addSQLDigestsToRetriever(retriever *SQLDigestTextRetriever, digests []interface{}) {
 	for _, item := range digests {
 		if item != nil {
 			digest, ok := item.(string)
@@ -850,7 +868,11 @@ func (b *builtinTiDBDecodeSQLDigestsSig) evalString(row chunk.Row) (string, bool
 			}
 		}
 	}
+}
 
+// retrieveSQLDigests queries the SQL statements of the digests in the retriever. The result should be NULL if the
+// retrieving fails, and a warning is appended in this case.
+func (b *builtinTiDBDecodeSQLDigestsSig) retrieveSQLDigests(retriever *SQLDigestTextRetriever) (bool, error) {
 	// Querying may take some time and it takes a context.Context as argument, which is not available here.
 	// We simply create a context with a timeout here.
 	timeout := time.Duration(b.ctx.GetSessionVars().MaxExecutionTime) * time.Millisecond
@@ -859,16 +881,19 @@ func (b *builtinTiDBDecodeSQLDigestsSig) evalString(row chunk.Row) (string, bool
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
-	err = retriever.RetrieveGlobal(ctx, b.ctx)
+	err := retriever.RetrieveGlobal(ctx, b.ctx)
 	if err != nil {
 		if errors.Cause(err) == context.DeadlineExceeded || errors.Cause(err) == context.Canceled {
-			return "", true, errUnknown.GenWithStack("Retrieving cancelled internally with error: %v", err)
+			return true, errUnknown.GenWithStack("Retrieving cancelled internally with error: %v", err)
 		}
 
 		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errUnknown.GenWithStack("Retrieving statements information failed with error: %v", err))
-		return "", true, nil
+		return true, nil
 	}
+	return false, nil
+}
 
+func (b *builtinTiDBDecodeSQLDigestsSig) collectDecodedSQLDigests(retriever *SQLDigestTextRetriever, digests []interface{}, stmtTruncateLength int64) (string, bool, error) {
 	// Collect the result.
 	result := make([]interface{}, len(digests))
 	for i, item := range digests {
@@ -120,6 +120,12 @@ func TestCurrentRole(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "`r_1`@`%`,`r_2`@`localhost`", d.GetString())
 	require.Equal(t, f.PbCode(), f.Clone().PbCode())
+
+	// the roles are not initialized
+	sessionVars.ActiveRoles = nil
+	d, err = evalBuiltinFunc(f, chunk.Row{})
+	require.NoError(t, err)
+	require.Equal(t, "NONE", d.GetString())
 }
 
 func TestConnectionID(t *testing.T) {
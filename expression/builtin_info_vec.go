@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/plancodec"
 	"github.com/pingcap/tidb/util/printer"
 )
 
@@ -129,7 +130,7 @@ func (b *builtinCurrentRoleSig) vecEvalString(input *chunk.Chunk, result *chunk.
 	n := input.NumRows()
 
 	data := b.ctx.GetSessionVars()
-	if data == nil || data.ActiveRoles == nil {
+	if data == nil {
 		return errors.Errorf("Missing session variable when eval builtin")
 	}
 
@@ -355,3 +356,200 @@ func (b *builtinTiDBDecodeKeySig) vecEvalString(input *chunk.Chunk, result *chun
 	}
 	return nil
 }
+
+func (c *builtinCoercibilitySig) vectorized() bool {
+	return true
+}
+
+func (c *builtinCoercibilitySig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	coercibility := int64(c.args[0].Coercibility())
+	for i := 0; i < n; i++ {
+		i64s[i] = coercibility
+	}
+	return nil
+}
+
+func (b *builtinCollationSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCollationSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ReserveString(n)
+	collation := b.args[0].GetType().Collate
+	for i := 0; i < n; i++ {
+		result.AppendString(collation)
+	}
+	return nil
+}
+
+func (b *builtinFormatBytesSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFormatBytesSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalReal(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	f64s := buf.Float64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(GetFormatBytes(f64s[i]))
+	}
+	return nil
+}
+
+func (b *builtinFormatNanoTimeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFormatNanoTimeSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalReal(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	f64s := buf.Float64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(GetFormatNanoTime(f64s[i]))
+	}
+	return nil
+}
+
+func (b *builtinTiDBDecodePlanSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTiDBDecodePlanSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		planString := buf.GetString(i)
+		planTree, err := plancodec.DecodePlan(planString)
+		if err != nil {
+			result.AppendString(planString)
+			continue
+		}
+		result.AppendString(planTree)
+	}
+	return nil
+}
+
+func (b *builtinTiDBDecodeSQLDigestsSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals TIDB_DECODE_SQL_DIGESTS, the digests of all the rows are retrieved together.
+func (b *builtinTiDBDecodeSQLDigestsSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	args := b.getArgs()
+	digestsBuf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(digestsBuf)
+	if err := args[0].VecEvalString(b.ctx, input, digestsBuf); err != nil {
+		return err
+	}
+	var truncateBuf *chunk.Column
+	if len(args) > 1 {
+		if truncateBuf, err = b.bufAllocator.get(); err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(truncateBuf)
+		if err := args[1].VecEvalInt(b.ctx, input, truncateBuf); err != nil {
+			return err
+		}
+	}
+
+	retriever := NewSQLDigestTextRetriever()
+	rowDigests := make([][]interface{}, n)
+	valid := make([]bool, n)
+	numValid := 0
+	for i := 0; i < n; i++ {
+		if digestsBuf.IsNull(i) {
+			continue
+		}
+		if rowDigests[i], valid[i] = b.unmarshalDigests(digestsBuf.GetString(i)); valid[i] {
+			addSQLDigestsToRetriever(retriever, rowDigests[i])
+			numValid++
+		}
+	}
+	retrieveFailed := false
+	if numValid > 0 {
+		if retrieveFailed, err = b.retrieveSQLDigests(retriever); err != nil {
+			return err
+		}
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if !valid[i] || retrieveFailed {
+			result.AppendNull()
+			continue
+		}
+		stmtTruncateLength := int64(0)
+		if truncateBuf != nil && !truncateBuf.IsNull(i) {
+			stmtTruncateLength = truncateBuf.GetInt64(i)
+		}
+		res, isNull, err := b.collectDecodedSQLDigests(retriever, rowDigests[i], stmtTruncateLength)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+// The sequence functions modify the sequence or depend on its latest modification, they are evaluated row by row to
+// make sure they're only called for the rows which need them, for example, the rows selected by a CASE WHEN.
+
+func (b *builtinNextValSig) vectorized() bool {
+	return false
+}
+
+func (b *builtinLastValSig) vectorized() bool {
+	return false
+}
+
+func (b *builtinSetValSig) vectorized() bool {
+	return false
+}
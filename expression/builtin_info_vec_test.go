@@ -107,6 +107,23 @@ var vecBuiltinInfoCases = map[string][]vecExprBenchCase{
 		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETInt, types.ETJson},
 			constants: []*Constant{{Value: types.NewIntDatum(17), RetType: types.NewFieldType(mysql.TypeLonglong)}, nil}},
 	},
+	ast.Coercibility: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETInt}},
+	},
+	ast.Collation: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETInt}},
+	},
+	ast.FormatBytes: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETReal}, geners: []dataGenerator{newRangeRealGener(0, 1<<40, 0.1)}},
+	},
+	ast.FormatNanoTime: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETReal}, geners: []dataGenerator{newRangeRealGener(0, 1<<40, 0.1)}},
+	},
+	ast.TiDBDecodePlan: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+	},
 }
 
 func TestVectorizedBuiltinInfoFunc(t *testing.T) {
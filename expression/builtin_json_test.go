@@ -467,6 +467,43 @@ func TestJSONContains(t *testing.T) {
 	}
 }
 
+func TestJSONMemberOf(t *testing.T) {
+	ctx := createContext(t)
+	fc := funcs[ast.JSONMemberOf]
+	tbl := []struct {
+		input    []interface{}
+		expected interface{}
+		err      error
+	}{
+		{[]interface{}{`1`, nil}, nil, nil},
+		{[]interface{}{nil, `[1]`}, nil, nil},
+		{[]interface{}{1, `[1, 2]`}, 1, nil},
+		{[]interface{}{3, `[1, 2]`}, 0, nil},
+		{[]interface{}{"a", `["a", "b"]`}, 1, nil},
+		{[]interface{}{"1", `[1, 2]`}, 0, nil},
+		{[]interface{}{1, `1`}, 1, nil},
+		{[]interface{}{1, `[[1], 2]`}, 0, nil},
+		{[]interface{}{1, `{"a": 1}`}, 0, nil},
+		{[]interface{}{1, `a:1`}, nil, json.ErrInvalidJSONText},
+	}
+	for _, tt := range tbl {
+		args := types.MakeDatums(tt.input...)
+		f, err := fc.getFunction(ctx, datumsToConstants(args))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err == nil {
+			require.NoError(t, err)
+			if tt.expected == nil {
+				require.True(t, d.IsNull())
+			} else {
+				require.Equal(t, int64(tt.expected.(int)), d.GetInt64())
+			}
+		} else {
+			require.True(t, tt.err.(*terror.Error).Equal(err))
+		}
+	}
+}
+
 func TestJSONContainsPath(t *testing.T) {
 	ctx := createContext(t)
 	fc := funcs[ast.JSONContainsPath]
@@ -0,0 +1,481 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// The data masking functions are compatible with the ones of MySQL Enterprise Data Masking.
+// See https://dev.mysql.com/doc/refman/8.0/en/data-masking-plugin-functions.html
+
+var (
+	_ functionClass = &maskInnerFunctionClass{}
+	_ functionClass = &maskOuterFunctionClass{}
+	_ functionClass = &maskPANFunctionClass{}
+	_ functionClass = &maskPANRelaxedFunctionClass{}
+	_ functionClass = &maskSSNFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinMaskInnerSig{}
+	_ builtinFunc = &builtinMaskOuterSig{}
+	_ builtinFunc = &builtinMaskPANSig{}
+	_ builtinFunc = &builtinMaskPANRelaxedSig{}
+	_ builtinFunc = &builtinMaskSSNSig{}
+)
+
+const (
+	// defaultMaskChar is used to mask when the masking character isn't specified.
+	defaultMaskChar = "X"
+	// minPANLength is the min length of a payment card number, the shorter strings are not masked.
+	minPANLength = 14
+	// minSSNLength is the min length of a US social security number, the shorter strings are not masked.
+	minSSNLength = 9
+	// maskRevealedSuffixLength is the length of the suffix revealed by MASK_PAN, MASK_PAN_RELAXED and MASK_SSN.
+	maskRevealedSuffixLength = 4
+	// maskPANRevealedPrefixLength is the length of the prefix revealed by MASK_PAN_RELAXED.
+	maskPANRevealedPrefixLength = 6
+)
+
+// maskArgs are the arguments of the masking functions evaluated for a row. The margins are only used by
+// MASK_INNER and MASK_OUTER.
+type maskArgs struct {
+	str      string
+	margin1  int64
+	margin2  int64
+	maskChar string
+}
+
+// newMaskFunction creates the masking functions, which are mask_xxx(str, [margin1, margin2,] [mask_char]).
+func newMaskFunction(ctx sessionctx.Context, c *baseFunctionClass, args []Expression, hasMargins bool) (baseBuiltinMaskSig, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return baseBuiltinMaskSig{}, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETString)
+	if hasMargins {
+		argTps = append(argTps, types.ETInt, types.ETInt)
+	}
+	if len(args) > len(argTps) {
+		argTps = append(argTps, types.ETString)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return baseBuiltinMaskSig{}, err
+	}
+	argTp := args[0].GetType()
+	bf.tp.Flen = argTp.Flen
+	if types.IsBinaryStr(argTp) {
+		types.SetBinChsClnFlag(bf.tp)
+	}
+	return baseBuiltinMaskSig{bf, c.funcName, hasMargins}, nil
+}
+
+// baseBuiltinMaskSig is the shared implementation of the masking functions.
+type baseBuiltinMaskSig struct {
+	baseBuiltinFunc
+	name       string
+	hasMargins bool
+}
+
+func (b *baseBuiltinMaskSig) cloneFromMaskSig(from *baseBuiltinMaskSig) {
+	b.cloneFrom(&from.baseBuiltinFunc)
+	b.name = from.name
+	b.hasMargins = from.hasMargins
+}
+
+func (b *baseBuiltinMaskSig) maskCharIdx() int {
+	if b.hasMargins {
+		return 3
+	}
+	return 1
+}
+
+// checkMaskChar checks the masking character is a single character, or a single byte for the binary strings.
+func (b *baseBuiltinMaskSig) checkMaskChar(maskChar string) error {
+	if types.IsBinaryStr(b.tp) {
+		if len(maskChar) != 1 {
+			return errIncorrectArgs.GenWithStackByArgs(b.name)
+		}
+		return nil
+	}
+	if utf8.RuneCountInString(maskChar) != 1 {
+		return errIncorrectArgs.GenWithStackByArgs(b.name)
+	}
+	return nil
+}
+
+func (b *baseBuiltinMaskSig) evalMaskArgs(row chunk.Row) (args maskArgs, isNull bool, err error) {
+	args.str, isNull, err = b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return args, true, err
+	}
+	if b.hasMargins {
+		args.margin1, isNull, err = b.args[1].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return args, true, err
+		}
+		args.margin2, isNull, err = b.args[2].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return args, true, err
+		}
+	}
+	args.maskChar = defaultMaskChar
+	if idx := b.maskCharIdx(); len(b.args) > idx {
+		args.maskChar, isNull, err = b.args[idx].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return args, true, err
+		}
+	}
+	return args, false, nil
+}
+
+func (b *baseBuiltinMaskSig) evalMask(row chunk.Row, mask func(args maskArgs) (string, error)) (string, bool, error) {
+	args, isNull, err := b.evalMaskArgs(row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	if err := b.checkMaskChar(args.maskChar); err != nil {
+		return "", true, err
+	}
+	res, err := mask(args)
+	return res, err != nil, err
+}
+
+// vecEvalMask evaluates the arguments of the chunk and masks the strings row by row.
+func (b *baseBuiltinMaskSig) vecEvalMask(input *chunk.Chunk, result *chunk.Column, mask func(args maskArgs) (string, error)) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, 0, len(b.args))
+	defer func() {
+		for _, buf := range bufs {
+			b.bufAllocator.put(buf)
+		}
+	}()
+	for i, arg := range b.args {
+		buf, err := b.bufAllocator.get()
+		if err != nil {
+			return err
+		}
+		bufs = append(bufs, buf)
+		if b.hasMargins && (i == 1 || i == 2) {
+			err = arg.VecEvalInt(b.ctx, input, buf)
+		} else {
+			err = arg.VecEvalString(b.ctx, input, buf)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	result.ReserveString(n)
+	args := maskArgs{maskChar: defaultMaskChar}
+	maskCharIdx := b.maskCharIdx()
+	for i := 0; i < n; i++ {
+		isNull := false
+		for _, buf := range bufs {
+			if buf.IsNull(i) {
+				isNull = true
+				break
+			}
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		args.str = bufs[0].GetString(i)
+		if b.hasMargins {
+			args.margin1, args.margin2 = bufs[1].GetInt64(i), bufs[2].GetInt64(i)
+		}
+		if len(bufs) > maskCharIdx {
+			args.maskChar = bufs[maskCharIdx].GetString(i)
+		}
+		if err := b.checkMaskChar(args.maskChar); err != nil {
+			return err
+		}
+		res, err := mask(args)
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+// maskString replaces the i-th characters of str with maskChar if masked returns true, n is the number of
+// characters in str. The characters are bytes if the result is a binary string.
+func (b *baseBuiltinMaskSig) maskString(str, maskChar string, masked func(i, n int, c rune) bool) string {
+	var sb strings.Builder
+	sb.Grow(len(str))
+	if types.IsBinaryStr(b.tp) {
+		for i, n := 0, len(str); i < n; i++ {
+			if masked(i, n, rune(str[i])) {
+				sb.WriteString(maskChar)
+			} else {
+				sb.WriteByte(str[i])
+			}
+		}
+		return sb.String()
+	}
+	n := utf8.RuneCountInString(str)
+	i := 0
+	for _, c := range str {
+		if masked(i, n, c) {
+			sb.WriteString(maskChar)
+		} else {
+			sb.WriteRune(c)
+		}
+		i++
+	}
+	return sb.String()
+}
+
+// checkMargins checks the margins are not negative.
+func (b *baseBuiltinMaskSig) checkMargins(args maskArgs) error {
+	if args.margin1 < 0 || args.margin2 < 0 {
+		return errIncorrectArgs.GenWithStackByArgs(b.name)
+	}
+	return nil
+}
+
+type maskInnerFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *maskInnerFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	base, err := newMaskFunction(ctx, &c.baseFunctionClass, args, true)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinMaskInnerSig{base}, nil
+}
+
+type builtinMaskInnerSig struct {
+	baseBuiltinMaskSig
+}
+
+func (b *builtinMaskInnerSig) Clone() builtinFunc {
+	newSig := &builtinMaskInnerSig{}
+	newSig.cloneFromMaskSig(&b.baseBuiltinMaskSig)
+	return newSig
+}
+
+// mask masks the interior of the string and leaves margin1 characters at the beginning and margin2 characters
+// at the end unmasked. The string isn't masked if the sum of the margins isn't less than its length.
+func (b *builtinMaskInnerSig) mask(args maskArgs) (string, error) {
+	if err := b.checkMargins(args); err != nil {
+		return "", err
+	}
+	return b.maskString(args.str, args.maskChar, func(i, n int, _ rune) bool {
+		return int64(i) >= args.margin1 && int64(i) < int64(n)-args.margin2
+	}), nil
+}
+
+// evalString evals MASK_INNER(str, margin1, margin2[, mask_char]).
+// See https://dev.mysql.com/doc/refman/8.0/en/data-masking-plugin-functions.html#function_mask-inner-plugin
+func (b *builtinMaskInnerSig) evalString(row chunk.Row) (string, bool, error) {
+	return b.evalMask(row, b.mask)
+}
+
+func (b *builtinMaskInnerSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMaskInnerSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalMask(input, result, b.mask)
+}
+
+type maskOuterFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *maskOuterFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	base, err := newMaskFunction(ctx, &c.baseFunctionClass, args, true)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinMaskOuterSig{base}, nil
+}
+
+type builtinMaskOuterSig struct {
+	baseBuiltinMaskSig
+}
+
+func (b *builtinMaskOuterSig) Clone() builtinFunc {
+	newSig := &builtinMaskOuterSig{}
+	newSig.cloneFromMaskSig(&b.baseBuiltinMaskSig)
+	return newSig
+}
+
+// mask masks margin1 characters at the beginning and margin2 characters at the end of the string. The whole
+// string is masked if the sum of the margins isn't less than its length.
+func (b *builtinMaskOuterSig) mask(args maskArgs) (string, error) {
+	if err := b.checkMargins(args); err != nil {
+		return "", err
+	}
+	return b.maskString(args.str, args.maskChar, func(i, n int, _ rune) bool {
+		return int64(i) < args.margin1 || int64(i) >= int64(n)-args.margin2
+	}), nil
+}
+
+// evalString evals MASK_OUTER(str, margin1, margin2[, mask_char]).
+// See https://dev.mysql.com/doc/refman/8.0/en/data-masking-plugin-functions.html#function_mask-outer-plugin
+func (b *builtinMaskOuterSig) evalString(row chunk.Row) (string, bool, error) {
+	return b.evalMask(row, b.mask)
+}
+
+func (b *builtinMaskOuterSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMaskOuterSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalMask(input, result, b.mask)
+}
+
+type maskPANFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *maskPANFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	base, err := newMaskFunction(ctx, &c.baseFunctionClass, args, false)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinMaskPANSig{base}, nil
+}
+
+type builtinMaskPANSig struct {
+	baseBuiltinMaskSig
+}
+
+func (b *builtinMaskPANSig) Clone() builtinFunc {
+	newSig := &builtinMaskPANSig{}
+	newSig.cloneFromMaskSig(&b.baseBuiltinMaskSig)
+	return newSig
+}
+
+// mask masks all but the last 4 characters of the payment card number.
+func (b *builtinMaskPANSig) mask(args maskArgs) (string, error) {
+	return b.maskString(args.str, args.maskChar, func(i, n int, _ rune) bool {
+		return n >= minPANLength && i < n-maskRevealedSuffixLength
+	}), nil
+}
+
+// evalString evals MASK_PAN(str[, mask_char]).
+// See https://dev.mysql.com/doc/refman/8.0/en/data-masking-plugin-functions.html#function_mask-pan-plugin
+func (b *builtinMaskPANSig) evalString(row chunk.Row) (string, bool, error) {
+	return b.evalMask(row, b.mask)
+}
+
+func (b *builtinMaskPANSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMaskPANSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalMask(input, result, b.mask)
+}
+
+type maskPANRelaxedFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *maskPANRelaxedFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	base, err := newMaskFunction(ctx, &c.baseFunctionClass, args, false)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinMaskPANRelaxedSig{base}, nil
+}
+
+type builtinMaskPANRelaxedSig struct {
+	baseBuiltinMaskSig
+}
+
+func (b *builtinMaskPANRelaxedSig) Clone() builtinFunc {
+	newSig := &builtinMaskPANRelaxedSig{}
+	newSig.cloneFromMaskSig(&b.baseBuiltinMaskSig)
+	return newSig
+}
+
+// mask masks the payment card number but reveals the first 6 characters, which identify the issuer, and
+// the last 4 characters.
+func (b *builtinMaskPANRelaxedSig) mask(args maskArgs) (string, error) {
+	return b.maskString(args.str, args.maskChar, func(i, n int, _ rune) bool {
+		return n >= minPANLength && i >= maskPANRevealedPrefixLength && i < n-maskRevealedSuffixLength
+	}), nil
+}
+
+// evalString evals MASK_PAN_RELAXED(str[, mask_char]).
+// See https://dev.mysql.com/doc/refman/8.0/en/data-masking-plugin-functions.html#function_mask-pan-relaxed-plugin
+func (b *builtinMaskPANRelaxedSig) evalString(row chunk.Row) (string, bool, error) {
+	return b.evalMask(row, b.mask)
+}
+
+func (b *builtinMaskPANRelaxedSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMaskPANRelaxedSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalMask(input, result, b.mask)
+}
+
+type maskSSNFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *maskSSNFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	base, err := newMaskFunction(ctx, &c.baseFunctionClass, args, false)
+	if err != nil {
+		return nil, err
+	}
+	return &builtinMaskSSNSig{base}, nil
+}
+
+type builtinMaskSSNSig struct {
+	baseBuiltinMaskSig
+}
+
+func (b *builtinMaskSSNSig) Clone() builtinFunc {
+	newSig := &builtinMaskSSNSig{}
+	newSig.cloneFromMaskSig(&b.baseBuiltinMaskSig)
+	return newSig
+}
+
+// mask masks all but the last 4 characters of the social security number, the separators '-' are kept,
+// e.g. '909-63-6922' is masked as 'XXX-XX-6922'.
+func (b *builtinMaskSSNSig) mask(args maskArgs) (string, error) {
+	return b.maskString(args.str, args.maskChar, func(i, n int, c rune) bool {
+		return n >= minSSNLength && i < n-maskRevealedSuffixLength && c != '-'
+	}), nil
+}
+
+// evalString evals MASK_SSN(str[, mask_char]).
+// See https://dev.mysql.com/doc/refman/8.0/en/data-masking-plugin-functions.html#function_mask-ssn-plugin
+func (b *builtinMaskSSNSig) evalString(row chunk.Row) (string, bool, error) {
+	return b.evalMask(row, b.mask)
+}
+
+func (b *builtinMaskSSNSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMaskSSNSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalMask(input, result, b.mask)
+}
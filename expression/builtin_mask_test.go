@@ -0,0 +1,110 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/testkit/trequire"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+type maskTestCase struct {
+	args   []interface{}
+	expect interface{}
+	err    *terror.Error
+}
+
+func runMaskTestCases(t *testing.T, funcName string, tests []maskTestCase) {
+	ctx := createContext(t)
+	for _, tt := range tests {
+		comment := fmt.Sprintf("%s%v", funcName, tt.args)
+		f, err := funcs[funcName].getFunction(ctx, primitiveValsToConstants(ctx, tt.args))
+		require.NoError(t, err, comment)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			require.Truef(t, terror.ErrorEqual(err, tt.err), "%s: %v", comment, err)
+			continue
+		}
+		require.NoError(t, err, comment)
+		trequire.DatumEqual(t, types.NewDatum(tt.expect), d, comment)
+	}
+}
+
+func TestMaskInner(t *testing.T) {
+	runMaskTestCases(t, ast.MaskInner, []maskTestCase{
+		{[]interface{}{"abcdef", 1, 2}, "aXXXef", nil},
+		{[]interface{}{"abcdef", 0, 5}, "Xbcdef", nil},
+		{[]interface{}{"abcdef", 1, 2, "*"}, "a***ef", nil},
+		{[]interface{}{"abcdef", 3, 3}, "abcdef", nil},
+		{[]interface{}{"abcdef", 100, 0}, "abcdef", nil},
+		{[]interface{}{"测试数据", 1, 1, "文"}, "测文文据", nil},
+		{[]interface{}{[]byte("abcdef"), 1, 1, "#"}, []byte("a####f"), nil},
+		{[]interface{}{nil, 1, 2}, nil, nil},
+		{[]interface{}{"abcdef", nil, 2}, nil, nil},
+		{[]interface{}{"abcdef", 1, 2, nil}, nil, nil},
+		{[]interface{}{"abcdef", -1, 2}, nil, errIncorrectArgs},
+		{[]interface{}{"abcdef", 1, 2, "**"}, nil, errIncorrectArgs},
+		{[]interface{}{"abcdef", 1, 2, ""}, nil, errIncorrectArgs},
+		{[]interface{}{[]byte("abcdef"), 1, 2, "文"}, nil, errIncorrectArgs},
+	})
+}
+
+func TestMaskOuter(t *testing.T) {
+	runMaskTestCases(t, ast.MaskOuter, []maskTestCase{
+		{[]interface{}{"abcdef", 1, 2}, "XbcdXX", nil},
+		{[]interface{}{"abcdef", 0, 5}, "aXXXXX", nil},
+		{[]interface{}{"abcdef", 1, 2, "*"}, "*bcd**", nil},
+		{[]interface{}{"abcdef", 3, 3}, "XXXXXX", nil},
+		{[]interface{}{"abcdef", 0, 0}, "abcdef", nil},
+		{[]interface{}{"", 1, 1}, "", nil},
+		{[]interface{}{"测试数据", 1, 1, "文"}, "文试数文", nil},
+		{[]interface{}{nil, 1, 2}, nil, nil},
+		{[]interface{}{"abcdef", 1, -2}, nil, errIncorrectArgs},
+	})
+}
+
+func TestMaskPAN(t *testing.T) {
+	runMaskTestCases(t, ast.MaskPAN, []maskTestCase{
+		{[]interface{}{"9130837077312461"}, "XXXXXXXXXXXX2461", nil},
+		{[]interface{}{"9130837077312461", "*"}, "************2461", nil},
+		{[]interface{}{"91308370773124"}, "XXXXXXXXXX3124", nil},
+		{[]interface{}{"9130837077312"}, "9130837077312", nil},
+		{[]interface{}{nil}, nil, nil},
+		{[]interface{}{"9130837077312461", "xy"}, nil, errIncorrectArgs},
+	})
+	runMaskTestCases(t, ast.MaskPANRelaxed, []maskTestCase{
+		{[]interface{}{"9130837077312461"}, "913083XXXXXX2461", nil},
+		{[]interface{}{"9130837077312461", "*"}, "913083******2461", nil},
+		{[]interface{}{"9130837077312"}, "9130837077312", nil},
+		{[]interface{}{nil}, nil, nil},
+	})
+}
+
+func TestMaskSSN(t *testing.T) {
+	runMaskTestCases(t, ast.MaskSSN, []maskTestCase{
+		{[]interface{}{"909-63-6922"}, "XXX-XX-6922", nil},
+		{[]interface{}{"909-63-6922", "*"}, "***-**-6922", nil},
+		{[]interface{}{"909636922"}, "XXXXX6922", nil},
+		{[]interface{}{"63-6922"}, "63-6922", nil},
+		{[]interface{}{nil}, nil, nil},
+		{[]interface{}{"909-63-6922", ""}, nil, errIncorrectArgs},
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinMaskCases = map[string][]vecExprBenchCase{
+	ast.MaskInner: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt},
+			geners: []dataGenerator{nil, newRangeInt64Gener(0, 5), newRangeInt64Gener(0, 5)},
+		},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{nil, newRangeInt64Gener(0, 5), newRangeInt64Gener(0, 5), newSelectStringGener([]string{"*", "#", "文"})},
+		},
+	},
+	ast.MaskOuter: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt},
+			geners: []dataGenerator{nil, newRangeInt64Gener(0, 5), newRangeInt64Gener(0, 5)},
+		},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{nil, newRangeInt64Gener(0, 5), newRangeInt64Gener(0, 5), newSelectStringGener([]string{"*", "#", "文"})},
+		},
+	},
+	ast.MaskPAN: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{newSelectStringGener([]string{"9130837077312461", "91308370773124", "9130837077312"})},
+		},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"*", "#"})},
+		},
+	},
+	ast.MaskPANRelaxed: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{newSelectStringGener([]string{"9130837077312461", "91308370773124", "9130837077312"})},
+		},
+	},
+	ast.MaskSSN: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString},
+			geners: []dataGenerator{newSelectStringGener([]string{"909-63-6922", "909636922", "63-6922"})},
+		},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"*", "#"})},
+		},
+	},
+}
+
+func TestVectorizedBuiltinMaskFunc(t *testing.T) {
+	testVectorizedBuiltinFunc(t, vecBuiltinMaskCases)
+}
+
+func BenchmarkVectorizedBuiltinMaskFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinMaskCases)
+}
@@ -23,6 +23,7 @@ import (
 	utilMath "github.com/pingcap/tidb/util/math"
 
 	"github.com/cznic/mathutil"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -1070,8 +1071,7 @@ func (b *builtinSignSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) er
 }
 
 func (b *builtinConvSig) vectorized() bool {
-	// TODO: change the vecEval match hybrid type fixing. Then open the vectorized evaluation.
-	return false
+	return true
 }
 
 func (b *builtinConvSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
@@ -1100,15 +1100,60 @@ func (b *builtinConvSig) vecEvalString(input *chunk.Chunk, result *chunk.Column)
 	if err := b.args[2].VecEvalInt(b.ctx, input, buf3); err != nil {
 		return err
 	}
+
+	// The same as evalString, the binary literals and the hybrid types are converted from their bit strings.
+	var constBitStr string
+	var hybridBuf *chunk.Column
+	switch x := b.args[0].(type) {
+	case *Constant:
+		if x.Value.Kind() == types.KindBinaryLiteral {
+			constBitStr = x.Value.GetBinaryLiteral().ToBitLiteralString(true)
+		}
+	case *ScalarFunction:
+		if x.FuncName.L == ast.Cast {
+			arg0 := x.GetArgs()[0]
+			if arg0.GetType().Hybrid() || IsBinaryLiteral(arg0) {
+				if hybridBuf, err = b.bufAllocator.get(); err != nil {
+					return err
+				}
+				defer b.bufAllocator.put(hybridBuf)
+				if err := arg0.VecEvalString(b.ctx, input, hybridBuf); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	result.ReserveString(n)
 	fromBase := buf2.Int64s()
 	toBase := buf3.Int64s()
 	for i := 0; i < n; i++ {
-		if buf1.IsNull(i) || buf2.IsNull(i) || buf3.IsNull(i) {
+		str := constBitStr
+		if hybridBuf != nil {
+			if hybridBuf.IsNull(i) {
+				result.AppendNull()
+				continue
+			}
+			d := types.NewStringDatum(hybridBuf.GetString(i))
+			str = d.GetBinaryLiteral().ToBitLiteralString(true)
+		}
+		if buf2.IsNull(i) || buf3.IsNull(i) {
 			result.AppendNull()
 			continue
 		}
-		res, isNull, err := b.conv(buf1.GetString(i), fromBase[i], toBase[i])
+		if len(str) == 0 {
+			if buf1.IsNull(i) {
+				result.AppendNull()
+				continue
+			}
+			str = buf1.GetString(i)
+		} else {
+			str, _, err = b.conv(str[2:], 2, fromBase[i])
+			if err != nil {
+				return err
+			}
+		}
+		res, isNull, err := b.conv(str, fromBase[i], toBase[i])
 		if err != nil {
 			return err
 		}
@@ -23,14 +23,18 @@ import (
 )
 
 var vecBuiltinMathCases = map[string][]vecExprBenchCase{
-	/* TODO: Because of https://github.com/pingcap/tidb/issues/5817, we don't enable it now.
 	ast.Conv: {
 		{
 			retEvalType:   types.ETString,
 			childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt},
 		},
+		{
+			retEvalType:   types.ETString,
+			childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt},
+			geners:        []dataGenerator{nil, newRangeInt64Gener(2, 37), newRangeInt64Gener(2, 37)},
+			constants:     []*Constant{{Value: types.NewBinaryLiteralDatum([]byte{0x0a}), RetType: types.NewFieldType(mysql.TypeBit)}, nil, nil},
+		},
 	},
-	*/
 	ast.Sign: {
 		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETReal}},
 	},
@@ -39,10 +39,13 @@ var (
 	_ functionClass = &releaseLockFunctionClass{}
 	_ functionClass = &anyValueFunctionClass{}
 	_ functionClass = &defaultFunctionClass{}
+	_ functionClass = &groupingFunctionClass{}
 	_ functionClass = &inetAtonFunctionClass{}
 	_ functionClass = &inetNtoaFunctionClass{}
 	_ functionClass = &inet6AtonFunctionClass{}
 	_ functionClass = &inet6NtoaFunctionClass{}
+	_ functionClass = &inet6NormalizeFunctionClass{}
+	_ functionClass = &ipInRangeFunctionClass{}
 	_ functionClass = &isFreeLockFunctionClass{}
 	_ functionClass = &isIPv4FunctionClass{}
 	_ functionClass = &isIPv4CompatFunctionClass{}
@@ -71,10 +74,13 @@ var (
 	_ builtinFunc = &builtinRealAnyValueSig{}
 	_ builtinFunc = &builtinStringAnyValueSig{}
 	_ builtinFunc = &builtinTimeAnyValueSig{}
+	_ builtinFunc = &builtinGroupingSig{}
 	_ builtinFunc = &builtinInetAtonSig{}
 	_ builtinFunc = &builtinInetNtoaSig{}
 	_ builtinFunc = &builtinInet6AtonSig{}
 	_ builtinFunc = &builtinInet6NtoaSig{}
+	_ builtinFunc = &builtinInet6NormalizeSig{}
+	_ builtinFunc = &builtinIPInRangeSig{}
 	_ builtinFunc = &builtinIsIPv4Sig{}
 	_ builtinFunc = &builtinIsIPv4CompatSig{}
 	_ builtinFunc = &builtinIsIPv4MappedSig{}
@@ -640,6 +646,141 @@ func (b *builtinInet6NtoaSig) evalString(row chunk.Row) (string, bool, error) {
 	return ip, false, nil
 }
 
+type inet6NormalizeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *inet6NormalizeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Charset, bf.tp.Collate = ctx.GetSessionVars().GetCharsetInfo()
+	bf.tp.Flen = 39
+	sig := &builtinInet6NormalizeSig{bf}
+	return sig, nil
+}
+
+type builtinInet6NormalizeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinInet6NormalizeSig) Clone() builtinFunc {
+	newSig := &builtinInet6NormalizeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinInet6NormalizeSig.
+// It returns the canonical text form of an IPv4 or IPv6 address, the same as INET6_NTOA(INET6_ATON(ip)).
+func (b *builtinInet6NormalizeSig) evalString(row chunk.Row) (string, bool, error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if err != nil || isNull {
+		return "", true, err
+	}
+	ip, ok := normalizeIP(val)
+	return ip, !ok, nil
+}
+
+// normalizeIP returns the canonical text form of the text IP address s.
+// IPv4-mapped IPv6 addresses keep their "::ffff:" prefix.
+func normalizeIP(s string) (string, bool) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return "", false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		if strings.Contains(s, ":") {
+			return "::ffff:" + ip4.String(), true
+		}
+		return ip4.String(), true
+	}
+	return ip.String(), true
+}
+
+type ipInRangeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *ipInRangeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := &builtinIPInRangeSig{bf}
+	return sig, nil
+}
+
+type builtinIPInRangeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIPInRangeSig) Clone() builtinFunc {
+	newSig := &builtinIPInRangeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinIPInRangeSig.
+// It returns 1 if the text IP address is in the CIDR range, such as '10.0.0.0/8' or '2001:db8::/32'.
+// A range without a prefix length only contains the address itself.
+// It returns NULL if either the address or the range is invalid.
+func (b *builtinIPInRangeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	ip, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if err != nil || isNull {
+		return 0, true, err
+	}
+	ipRange, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if err != nil || isNull {
+		return 0, true, err
+	}
+	res, ok := ipInRange(ip, ipRange)
+	return res, !ok, nil
+}
+
+// parseIPRange parses a CIDR range. A single address is treated as a range with the full prefix length.
+func parseIPRange(s string) (*net.IPNet, bool) {
+	if strings.Contains(s, "/") {
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, false
+		}
+		return ipNet, true
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, false
+	}
+	if ip4 := ip.To4(); ip4 != nil && !strings.Contains(s, ":") {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(8*net.IPv4len, 8*net.IPv4len)}, true
+	}
+	return &net.IPNet{IP: ip.To16(), Mask: net.CIDRMask(8*net.IPv6len, 8*net.IPv6len)}, true
+}
+
+// ipInRange checks whether the text IP address ip is in the CIDR range ipRange.
+// IPv4 addresses never match IPv6 ranges, but IPv4-mapped IPv6 addresses match IPv4 ranges.
+func ipInRange(ip, ipRange string) (int64, bool) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return 0, false
+	}
+	ipNet, ok := parseIPRange(ipRange)
+	if !ok {
+		return 0, false
+	}
+	if ipNet.Contains(addr) {
+		return 1, true
+	}
+	return 0, true
+}
+
 type isFreeLockFunctionClass struct {
 	baseFunctionClass
 }
@@ -905,6 +1046,76 @@ func (b *builtinIsUUIDSig) evalInt(row chunk.Row) (int64, bool, error) {
 	return 1, false, nil
 }
 
+type groupingFunctionClass struct {
+	baseFunctionClass
+}
+
+// getFunction gets the builtinGroupingSig. GROUPING(expr, ...) is rewritten by the planner as
+// grouping(grouping_id, offset, ...), the offsets are the ones of the arguments in the GROUP BY items.
+func (c *groupingFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	offsets := make([]uint64, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		con, ok := arg.(*Constant)
+		if !ok {
+			return nil, ErrIncorrectParameterCount.GenWithStackByArgs(c.funcName)
+		}
+		offset, isNull, err := con.EvalInt(ctx, chunk.Row{})
+		if err != nil {
+			return nil, err
+		}
+		if isNull || offset < 0 || offset >= 64 {
+			return nil, ErrIncorrectParameterCount.GenWithStackByArgs(c.funcName)
+		}
+		offsets = append(offsets, uint64(offset))
+	}
+	argTps := make([]types.EvalType, len(args))
+	for i := range argTps {
+		argTps[i] = types.ETInt
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = mysql.MaxIntWidth
+	bf.tp.Flag |= mysql.NotNullFlag
+	sig := &builtinGroupingSig{bf, offsets}
+	return sig, nil
+}
+
+type builtinGroupingSig struct {
+	baseBuiltinFunc
+	offsets []uint64
+}
+
+func (b *builtinGroupingSig) Clone() builtinFunc {
+	newSig := &builtinGroupingSig{offsets: b.offsets}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// groupingResult returns the bits of the GROUP BY items in the grouping id, the bit is 1 if the item is NULL
+// in the super-aggregate row. The rightmost argument is the least significant bit of the result.
+func (b *builtinGroupingSig) groupingResult(groupingID uint64) int64 {
+	var res int64
+	for _, offset := range b.offsets {
+		res = res<<1 | int64((groupingID>>offset)&1)
+	}
+	return res
+}
+
+// evalInt evals a builtinGroupingSig.
+// See https://dev.mysql.com/doc/refman/8.0/en/miscellaneous-functions.html#function_grouping
+func (b *builtinGroupingSig) evalInt(row chunk.Row) (int64, bool, error) {
+	groupingID, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil || isNull {
+		return 0, isNull, err
+	}
+	return b.groupingResult(uint64(groupingID)), false, nil
+}
+
 type masterPosWaitFunctionClass struct {
 	baseFunctionClass
 }
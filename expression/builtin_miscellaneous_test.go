@@ -130,6 +130,38 @@ func TestIsUUID(t *testing.T) {
 	require.True(t, r.IsNull())
 }
 
+func TestGrouping(t *testing.T) {
+	ctx := createContext(t)
+	tests := []struct {
+		groupingID int64
+		offsets    []interface{}
+		expect     int64
+	}{
+		{0, []interface{}{0}, 0},
+		{1, []interface{}{0}, 1},
+		{1, []interface{}{1}, 0},
+		{3, []interface{}{0, 1}, 3},
+		{2, []interface{}{0, 1}, 1},
+		{2, []interface{}{1, 0}, 2},
+		{6, []interface{}{2, 0, 1}, 5},
+	}
+
+	fc := funcs[ast.Grouping]
+	for _, test := range tests {
+		args := append([]interface{}{test.groupingID}, test.offsets...)
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(args...)))
+		require.NoError(t, err)
+		result, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		require.Equal(t, test.expect, result.GetInt64())
+	}
+
+	_, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(1)))
+	require.True(t, ErrIncorrectParameterCount.Equal(err))
+	_, err = fc.getFunction(ctx, datumsToConstants(types.MakeDatums(1, 64)))
+	require.True(t, ErrIncorrectParameterCount.Equal(err))
+}
+
 func TestUUID(t *testing.T) {
 	ctx := createContext(t)
 	f, err := newFunctionForTest(ctx, ast.UUID)
@@ -207,6 +239,67 @@ func TestIsIPv6(t *testing.T) {
 	trequire.DatumEqual(t, types.NewDatum(0), r)
 }
 
+func TestInet6Normalize(t *testing.T) {
+	ctx := createContext(t)
+	tests := []struct {
+		ip     interface{}
+		expect interface{}
+	}{
+		{"192.168.1.1", "192.168.1.1"},
+		{"2001:0DB8:0000:0000:0000:0000:0000:0068", "2001:db8::68"},
+		{"::FFFF:1.2.3.4", "::ffff:1.2.3.4"},
+		{"::ffff:0102:0304", "::ffff:1.2.3.4"},
+		{"0:0:0:0:0:0:0:1", "::1"},
+		{"192.168.1", nil},
+		{"", nil},
+		{nil, nil},
+	}
+	fc := funcs[ast.Inet6Normalize]
+	for _, test := range tests {
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(test.ip)))
+		require.NoError(t, err)
+		result, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		trequire.DatumEqual(t, types.NewDatum(test.expect), result)
+	}
+}
+
+func TestIPInRange(t *testing.T) {
+	ctx := createContext(t)
+	tests := []struct {
+		ip      interface{}
+		ipRange interface{}
+		expect  interface{}
+	}{
+		{"10.1.2.3", "10.0.0.0/8", 1},
+		{"11.1.2.3", "10.0.0.0/8", 0},
+		{"10.1.2.3", "10.1.2.3/32", 1},
+		{"10.1.2.3", "10.1.2.3", 1},
+		{"10.1.2.4", "10.1.2.3", 0},
+		{"10.1.2.3", "10.255.255.255/8", 1},
+		{"1.2.3.4", "0.0.0.0/0", 1},
+		{"::ffff:10.1.2.3", "10.0.0.0/8", 1},
+		{"10.1.2.3", "::/0", 0},
+		{"2001:db8::68", "2001:db8::/32", 1},
+		{"2001:db9::68", "2001:db8::/32", 0},
+		{"2001:DB8:0:0::68", "2001:db8::68", 1},
+		{"2001:db8::68", "10.0.0.0/8", 0},
+		{"10.1.2.3", "10.0.0.0/33", nil},
+		{"10.1.2", "10.0.0.0/8", nil},
+		{"10.1.2.3", "abc", nil},
+		{nil, "10.0.0.0/8", nil},
+		{"10.1.2.3", nil, nil},
+	}
+	fc := funcs[ast.IPInRange]
+	for _, test := range tests {
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(test.ip, test.ipRange)))
+		require.NoError(t, err)
+		result, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		trequire.DatumEqual(t, types.NewDatum(test.expect), result)
+	}
+}
+
 func TestInetNtoa(t *testing.T) {
 	ctx := createContext(t)
 	tests := []struct {
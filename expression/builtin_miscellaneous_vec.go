@@ -119,6 +119,74 @@ func (b *builtinStringAnyValueSig) vecEvalString(input *chunk.Chunk, result *chu
 	return b.args[0].VecEvalString(b.ctx, input, result)
 }
 
+func (b *builtinInet6NormalizeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinInet6NormalizeSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		if ip, ok := normalizeIP(buf.GetString(i)); ok {
+			result.AppendString(ip)
+		} else {
+			result.AppendNull()
+		}
+	}
+	return nil
+}
+
+func (b *builtinIPInRangeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinIPInRangeSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	ipBuf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(ipBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, ipBuf); err != nil {
+		return err
+	}
+	rangeBuf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(rangeBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, rangeBuf); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	result.MergeNulls(ipBuf, rangeBuf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, ok := ipInRange(ipBuf.GetString(i), rangeBuf.GetString(i))
+		if !ok {
+			result.SetNull(i, true)
+			continue
+		}
+		i64s[i] = res
+	}
+	return nil
+}
+
 func (b *builtinIsIPv6Sig) vectorized() bool {
 	return true
 }
@@ -154,6 +222,24 @@ func (b *builtinIsIPv6Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column)
 	return nil
 }
 
+func (b *builtinGroupingSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGroupingSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	i64s := result.Int64s()
+	for i := range i64s {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = b.groupingResult(uint64(i64s[i]))
+	}
+	return nil
+}
+
 func (b *builtinIsUUIDSig) vectorized() bool {
 	return true
 }
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/mock"
@@ -33,6 +34,22 @@ var vecBuiltinMiscellaneousCases = map[string][]vecExprBenchCase{
 	ast.IsIPv6: {
 		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString}},
 	},
+	ast.Inet6Normalize: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{
+			newSelectStringGener([]string{"192.168.0.1", "2001:DB8:0:0::68", "::ffff:1.2.3.4", "abc"})}},
+	},
+	ast.IPInRange: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString}, geners: []dataGenerator{
+			newSelectStringGener([]string{"10.1.2.3", "192.168.0.1", "2001:db8::68", "::ffff:10.0.0.1", "abc"}),
+			newSelectStringGener([]string{"10.0.0.0/8", "2001:db8::/32", "192.168.0.1", "10.0.0.0/33"})}},
+	},
+	ast.Grouping: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETInt, types.ETInt, types.ETInt},
+			geners: []dataGenerator{newRangeInt64Gener(0, 8)},
+			constants: []*Constant{nil,
+				{Value: types.NewIntDatum(2), RetType: types.NewFieldType(mysql.TypeLonglong)},
+				{Value: types.NewIntDatum(0), RetType: types.NewFieldType(mysql.TypeLonglong)}}},
+	},
 	ast.Sleep: {
 		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETReal}, geners: []dataGenerator{
 			newSelectRealGener([]float64{0, 0.000001}),
@@ -461,3 +461,77 @@ func (b *builtinGetDecimalVarSig) vecEvalDecimal(input *chunk.Chunk, result *chu
 	}
 	return nil
 }
+
+func (b *builtinSetTimeVarSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSetTimeVarSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	buf1, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	if err := b.args[1].VecEvalTime(b.ctx, input, buf1); err != nil {
+		return handleInvalidTimeError(b.ctx, err)
+	}
+	result.ResizeTime(n, false)
+	times := result.Times()
+	sessionVars := b.ctx.GetSessionVars()
+	sessionVars.UsersLock.Lock()
+	defer sessionVars.UsersLock.Unlock()
+	for i := 0; i < n; i++ {
+		if buf0.IsNull(i) || buf1.IsNull(i) {
+			result.SetNull(i, true)
+			continue
+		}
+		varName := strings.ToLower(buf0.GetString(i))
+		res := buf1.GetTime(i)
+		sessionVars.Users[varName] = types.NewTimeDatum(res)
+		times[i] = res
+	}
+	return nil
+}
+
+func (b *builtinGetTimeVarSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGetTimeVarSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	result.ResizeTime(n, false)
+	result.MergeNulls(buf0)
+	times := result.Times()
+	sessionVars := b.ctx.GetSessionVars()
+	sessionVars.UsersLock.RLock()
+	defer sessionVars.UsersLock.RUnlock()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		varName := strings.ToLower(buf0.GetString(i))
+		if v, ok := sessionVars.Users[varName]; ok {
+			times[i] = v.GetMysqlTime()
+			continue
+		}
+		result.SetNull(i, true)
+	}
+	return nil
+}
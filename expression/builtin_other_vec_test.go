@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/mock"
@@ -40,6 +41,7 @@ var vecBuiltinOtherCases = map[string][]vecExprBenchCase{
 		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETInt}},
 		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETString, types.ETReal}},
 		{retEvalType: types.ETDecimal, childrenTypes: []types.EvalType{types.ETString, types.ETDecimal}},
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETString, types.ETDatetime}},
 	},
 	ast.GetVar: {
 		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
@@ -88,3 +90,35 @@ func TestInDecimal(t *testing.T) {
 		require.Equal(t, int64(1), result.GetInt64(0))
 	}
 }
+
+func TestGetTimeVarVectorized(t *testing.T) {
+	ctx := mock.NewContext()
+	ft := eType2FieldType(types.ETDatetime)
+	ctx.GetSessionVars().Users["a"] = types.NewTimeDatum(types.NewTime(types.FromDate(2022, 1, 2, 3, 4, 5, 0), mysql.TypeDatetime, 0))
+	ctx.GetSessionVars().Users["b"] = types.NewTimeDatum(types.NewTime(types.FromDate(2021, 12, 31, 0, 0, 0, 0), mysql.TypeDatetime, 0))
+	strTp := eType2FieldType(types.ETString)
+	f, err := BuildGetVarFunction(ctx, &Column{RetType: strTp, Index: 0}, ft)
+	require.NoError(t, err)
+	sf := f.(*ScalarFunction)
+	require.True(t, sf.Vectorized())
+
+	input := chunk.NewChunkWithCapacity([]*types.FieldType{strTp}, 4)
+	input.Column(0).AppendString("a")
+	input.Column(0).AppendNull()
+	input.Column(0).AppendString("B")
+	input.Column(0).AppendString("c")
+	result := chunk.NewColumn(ft, 4)
+	require.NoError(t, sf.VecEvalTime(ctx, input, result))
+	it := chunk.NewIterator4Chunk(input)
+	i := 0
+	for row := it.Begin(); row != it.End(); row = it.Next() {
+		val, isNull, err := sf.EvalTime(ctx, row)
+		require.NoError(t, err)
+		require.Equal(t, isNull, result.IsNull(i))
+		if !isNull {
+			require.Equal(t, val, result.GetTime(i))
+		}
+		i++
+	}
+	require.True(t, result.IsNull(3))
+}
@@ -0,0 +1,552 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &regexpLikeFunctionClass{}
+	_ functionClass = &regexpInStrFunctionClass{}
+	_ functionClass = &regexpSubstrFunctionClass{}
+	_ functionClass = &regexpReplaceFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinRegexpLikeSig{}
+	_ builtinFunc = &builtinRegexpInStrSig{}
+	_ builtinFunc = &builtinRegexpSubstrSig{}
+	_ builtinFunc = &builtinRegexpReplaceSig{}
+)
+
+// The characters of the match_type argument.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+const (
+	regexpMatchTypeCaseSensitive   = 'c'
+	regexpMatchTypeCaseInsensitive = 'i'
+	regexpMatchTypeMultiLine       = 'm'
+	regexpMatchTypeDotAll          = 'n'
+	regexpMatchTypeUnixLines       = 'u'
+)
+
+// newRegexpBaseBuiltinFunc creates the baseBuiltinFunc of the REGEXP_* functions, the optional
+// arguments which are not given are removed from argTps.
+func newRegexpBaseBuiltinFunc(ctx sessionctx.Context, funcName string, args []Expression, retType types.EvalType, argTps ...types.EvalType) (baseBuiltinFunc, error) {
+	return newBaseBuiltinFuncWithTp(ctx, funcName, args, retType, argTps[:len(args)]...)
+}
+
+// regexpBaseFuncSig is the shared part of the REGEXP_* functions. The strings are matched in
+// bytes if the collation is binary, otherwise in characters. Note that the regular expressions
+// are compiled by the Go regexp package, so the ICU-only syntax such as the back references
+// and the look-around assertions is reported as an error.
+type regexpBaseFuncSig struct {
+	baseBuiltinFunc
+
+	memorizedOnce   sync.Once
+	memorizedRegexp *regexp.Regexp
+	memorizedErr    error
+}
+
+func (b *regexpBaseFuncSig) clone(from *regexpBaseFuncSig) {
+	b.cloneFrom(&from.baseBuiltinFunc)
+}
+
+func (b *regexpBaseFuncSig) isBinaryCollation() bool {
+	return b.collation == charset.CollationBin
+}
+
+// buildRegexpFlags parses the match_type argument. The case sensitivity is inherited from the
+// collation unless it's specified, and the binary strings are always matched case-sensitively.
+func (b *regexpBaseFuncSig) buildRegexpFlags(matchType string) (string, error) {
+	caseInsensitive := collate.IsCICollation(b.collation)
+	multiLine, dotAll := false, false
+	for _, c := range matchType {
+		switch c {
+		case regexpMatchTypeCaseSensitive:
+			caseInsensitive = false
+		case regexpMatchTypeCaseInsensitive:
+			caseInsensitive = true
+		case regexpMatchTypeMultiLine:
+			multiLine = true
+		case regexpMatchTypeDotAll:
+			dotAll = true
+		case regexpMatchTypeUnixLines:
+			// Only '\n' is recognized as the line terminator by the Go regexp package.
+		default:
+			return "", errIncorrectArgs.GenWithStackByArgs("match_type")
+		}
+	}
+	var flags strings.Builder
+	if caseInsensitive && !b.isBinaryCollation() {
+		flags.WriteByte('i')
+	}
+	if multiLine {
+		flags.WriteByte('m')
+	}
+	if dotAll {
+		flags.WriteByte('s')
+	}
+	if flags.Len() == 0 {
+		return "", nil
+	}
+	return "(?" + flags.String() + ")", nil
+}
+
+func (b *regexpBaseFuncSig) compileRegexp(pat, matchType string) (*regexp.Regexp, error) {
+	flags, err := b.buildRegexpFlags(matchType)
+	if err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(flags + pat)
+	if err != nil {
+		return nil, ErrRegexp.GenWithStackByArgs(err.Error())
+	}
+	return re, nil
+}
+
+// getRegexp compiles the pattern, the result is memorized if both the pattern and the match_type
+// are constant.
+func (b *regexpBaseFuncSig) getRegexp(pat, matchType string, matchTypeIdx int) (*regexp.Regexp, error) {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	if !b.args[1].ConstItem(sc) || (matchTypeIdx < len(b.args) && !b.args[matchTypeIdx].ConstItem(sc)) {
+		return b.compileRegexp(pat, matchType)
+	}
+	b.memorizedOnce.Do(func() {
+		b.memorizedRegexp, b.memorizedErr = b.compileRegexp(pat, matchType)
+	})
+	return b.memorizedRegexp, b.memorizedErr
+}
+
+// byteOffset converts the 1-based position to the offset in bytes of expr.
+func (b *regexpBaseFuncSig) byteOffset(expr string, pos int64) (int, error) {
+	if pos < 1 {
+		return 0, errRegexpIndexOutOfBounds.GenWithStackByArgs()
+	}
+	if b.isBinaryCollation() {
+		if pos-1 > int64(len(expr)) {
+			return 0, errRegexpIndexOutOfBounds.GenWithStackByArgs()
+		}
+		return int(pos - 1), nil
+	}
+	cur := int64(1)
+	for offset := range expr {
+		if cur == pos {
+			return offset, nil
+		}
+		cur++
+	}
+	if cur == pos {
+		return len(expr), nil
+	}
+	return 0, errRegexpIndexOutOfBounds.GenWithStackByArgs()
+}
+
+// position converts the offset in bytes of expr to the 1-based position.
+func (b *regexpBaseFuncSig) position(expr string, offset int) int64 {
+	if b.isBinaryCollation() {
+		return int64(offset) + 1
+	}
+	return int64(utf8.RuneCountInString(expr[:offset])) + 1
+}
+
+// findMatch finds the occurrence-th match of re in expr from pos, nil is returned if there isn't one.
+func (b *regexpBaseFuncSig) findMatch(re *regexp.Regexp, expr string, pos, occurrence int64) (offset int, match []int, err error) {
+	offset, err = b.byteOffset(expr, pos)
+	if err != nil {
+		return 0, nil, err
+	}
+	if occurrence < 1 {
+		occurrence = 1
+	}
+	matches := re.FindAllStringIndex(expr[offset:], int(occurrence))
+	if int64(len(matches)) < occurrence {
+		return offset, nil, nil
+	}
+	return offset, matches[occurrence-1], nil
+}
+
+func (b *regexpBaseFuncSig) evalStringArg(row chunk.Row, idx int, defaultVal string) (string, bool, error) {
+	if idx >= len(b.args) {
+		return defaultVal, false, nil
+	}
+	return b.args[idx].EvalString(b.ctx, row)
+}
+
+func (b *regexpBaseFuncSig) evalIntArg(row chunk.Row, idx int, defaultVal int64) (int64, bool, error) {
+	if idx >= len(b.args) {
+		return defaultVal, false, nil
+	}
+	return b.args[idx].EvalInt(b.ctx, row)
+}
+
+type regexpLikeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpLikeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newRegexpBaseBuiltinFunc(ctx, c.funcName, args, types.ETInt, types.ETString, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := &builtinRegexpLikeSig{regexpBaseFuncSig{baseBuiltinFunc: bf}}
+	if bf.collation == charset.CollationBin {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpLikeSig struct {
+	regexpBaseFuncSig
+}
+
+func (b *builtinRegexpLikeSig) Clone() builtinFunc {
+	newSig := &builtinRegexpLikeSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalInt evals REGEXP_LIKE(expr, pat[, match_type]).
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *builtinRegexpLikeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	matchType, isNull, err := b.evalStringArg(row, 2, "")
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	res, err := b.regexpLike(expr, pat, matchType)
+	return res, err != nil, err
+}
+
+func (b *builtinRegexpLikeSig) regexpLike(expr, pat, matchType string) (int64, error) {
+	re, err := b.getRegexp(pat, matchType, 2)
+	if err != nil {
+		return 0, err
+	}
+	return boolToInt64(re.MatchString(expr)), nil
+}
+
+type regexpInStrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpInStrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newRegexpBaseBuiltinFunc(ctx, c.funcName, args, types.ETInt, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinRegexpInStrSig{regexpBaseFuncSig{baseBuiltinFunc: bf}}
+	if bf.collation == charset.CollationBin {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpInStrSig struct {
+	regexpBaseFuncSig
+}
+
+func (b *builtinRegexpInStrSig) Clone() builtinFunc {
+	newSig := &builtinRegexpInStrSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalInt evals REGEXP_INSTR(expr, pat[, pos[, occurrence[, return_option[, match_type]]]]).
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-instr
+func (b *builtinRegexpInStrSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pos, isNull, err := b.evalIntArg(row, 2, 1)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	occurrence, isNull, err := b.evalIntArg(row, 3, 1)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	returnOption, isNull, err := b.evalIntArg(row, 4, 0)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	matchType, isNull, err := b.evalStringArg(row, 5, "")
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	res, err := b.regexpInStr(expr, pat, pos, occurrence, returnOption, matchType)
+	return res, err != nil, err
+}
+
+func (b *builtinRegexpInStrSig) regexpInStr(expr, pat string, pos, occurrence, returnOption int64, matchType string) (int64, error) {
+	if returnOption != 0 && returnOption != 1 {
+		return 0, errIncorrectArgs.GenWithStackByArgs("regexp_instr: return_option must be 1 or 0.")
+	}
+	re, err := b.getRegexp(pat, matchType, 5)
+	if err != nil {
+		return 0, err
+	}
+	offset, match, err := b.findMatch(re, expr, pos, occurrence)
+	if match == nil || err != nil {
+		return 0, err
+	}
+	return b.position(expr, offset+match[returnOption]), nil
+}
+
+type regexpSubstrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpSubstrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newRegexpBaseBuiltinFunc(ctx, c.funcName, args, types.ETString, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := &builtinRegexpSubstrSig{regexpBaseFuncSig{baseBuiltinFunc: bf}}
+	if bf.collation == charset.CollationBin {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpSubstrSig struct {
+	regexpBaseFuncSig
+}
+
+func (b *builtinRegexpSubstrSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSubstrSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalString evals REGEXP_SUBSTR(expr, pat[, pos[, occurrence[, match_type]]]).
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-substr
+func (b *builtinRegexpSubstrSig) evalString(row chunk.Row) (string, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.evalIntArg(row, 2, 1)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	occurrence, isNull, err := b.evalIntArg(row, 3, 1)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	matchType, isNull, err := b.evalStringArg(row, 4, "")
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return b.regexpSubstr(expr, pat, pos, occurrence, matchType)
+}
+
+func (b *builtinRegexpSubstrSig) regexpSubstr(expr, pat string, pos, occurrence int64, matchType string) (string, bool, error) {
+	re, err := b.getRegexp(pat, matchType, 4)
+	if err != nil {
+		return "", true, err
+	}
+	offset, match, err := b.findMatch(re, expr, pos, occurrence)
+	if match == nil || err != nil {
+		return "", true, err
+	}
+	return expr[offset+match[0] : offset+match[1]], false, nil
+}
+
+type regexpReplaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpReplaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newRegexpBaseBuiltinFunc(ctx, c.funcName, args, types.ETString, types.ETString, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = mysql.MaxBlobWidth
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	SetBinFlagOrBinStr(args[2].GetType(), bf.tp)
+	sig := &builtinRegexpReplaceSig{regexpBaseFuncSig{baseBuiltinFunc: bf}}
+	if bf.collation == charset.CollationBin {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpReplaceSig struct {
+	regexpBaseFuncSig
+}
+
+func (b *builtinRegexpReplaceSig) Clone() builtinFunc {
+	newSig := &builtinRegexpReplaceSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalString evals REGEXP_REPLACE(expr, pat, repl[, pos[, occurrence[, match_type]]]).
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-replace
+func (b *builtinRegexpReplaceSig) evalString(row chunk.Row) (string, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	repl, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.evalIntArg(row, 3, 1)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	occurrence, isNull, err := b.evalIntArg(row, 4, 0)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	matchType, isNull, err := b.evalStringArg(row, 5, "")
+	if isNull || err != nil {
+		return "", true, err
+	}
+	res, err := b.regexpReplace(expr, pat, repl, pos, occurrence, matchType)
+	return res, err != nil, err
+}
+
+// regexpReplace replaces the occurrence-th match from pos, all the matches are replaced if
+// occurrence is less than 1.
+func (b *builtinRegexpReplaceSig) regexpReplace(expr, pat, repl string, pos, occurrence int64, matchType string) (string, error) {
+	re, err := b.getRegexp(pat, matchType, 5)
+	if err != nil {
+		return "", err
+	}
+	offset, err := b.byteOffset(expr, pos)
+	if err != nil {
+		return "", err
+	}
+	src := expr[offset:]
+	n := -1
+	if occurrence > 0 {
+		n = int(occurrence)
+	}
+	matches := re.FindAllStringSubmatchIndex(src, n)
+	if occurrence > 0 {
+		if int64(len(matches)) < occurrence {
+			return expr, nil
+		}
+		matches = matches[occurrence-1:]
+	}
+	if len(matches) == 0 {
+		return expr, nil
+	}
+	res := make([]byte, 0, len(expr))
+	res = append(res, expr[:offset]...)
+	last := 0
+	for _, match := range matches {
+		res = append(res, src[last:match[0]]...)
+		if res, err = expandRegexpReplacement(res, repl, src, match); err != nil {
+			return "", err
+		}
+		last = match[1]
+	}
+	res = append(res, src[last:]...)
+	return string(res), nil
+}
+
+// expandRegexpReplacement appends the replacement of the match to dst in the ICU syntax, that is,
+// "$n" refers to the n-th captured group and '\' escapes the next character.
+func expandRegexpReplacement(dst []byte, repl, src string, match []int) ([]byte, error) {
+	groups := len(match) / 2
+	for i := 0; i < len(repl); i++ {
+		c := repl[i]
+		switch {
+		case c == '\\' && i+1 < len(repl):
+			i++
+			dst = append(dst, repl[i])
+		case c == '$' && i+1 < len(repl) && '0' <= repl[i+1] && repl[i+1] <= '9':
+			// Take as many digits as they form a valid group number.
+			i++
+			group := int(repl[i] - '0')
+			for i+1 < len(repl) && '0' <= repl[i+1] && repl[i+1] <= '9' {
+				next := group*10 + int(repl[i+1]-'0')
+				if next >= groups {
+					break
+				}
+				group = next
+				i++
+			}
+			if group >= groups {
+				return nil, errRegexpIndexOutOfBounds.GenWithStackByArgs()
+			}
+			if match[2*group] >= 0 {
+				dst = append(dst, src[match[2*group]:match[2*group+1]]...)
+			}
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst, nil
+}
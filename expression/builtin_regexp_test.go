@@ -0,0 +1,120 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/testkit/trequire"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+type regexpTestCase struct {
+	args   []interface{}
+	expect interface{}
+	err    *terror.Error
+}
+
+func runRegexpTestCases(t *testing.T, funcName string, tests []regexpTestCase) {
+	ctx := createContext(t)
+	for _, tt := range tests {
+		comment := fmt.Sprintf("%s%v", funcName, tt.args)
+		f, err := funcs[funcName].getFunction(ctx, primitiveValsToConstants(ctx, tt.args))
+		require.NoError(t, err, comment)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			require.Truef(t, terror.ErrorEqual(err, tt.err), "%s: %v", comment, err)
+			continue
+		}
+		require.NoError(t, err, comment)
+		trequire.DatumEqual(t, types.NewDatum(tt.expect), d, comment)
+	}
+}
+
+func TestRegexpLike(t *testing.T) {
+	runRegexpTestCases(t, ast.RegexpLike, []regexpTestCase{
+		{[]interface{}{"abc", "^a"}, int64(1), nil},
+		{[]interface{}{"abc", "^b"}, int64(0), nil},
+		{[]interface{}{"ABC", "abc"}, int64(0), nil},
+		{[]interface{}{"ABC", "abc", "i"}, int64(1), nil},
+		{[]interface{}{"ABC", "abc", "ic"}, int64(0), nil},
+		{[]interface{}{"a\nb", "^b"}, int64(0), nil},
+		{[]interface{}{"a\nb", "^b", "m"}, int64(1), nil},
+		{[]interface{}{"a\nb", "a.b"}, int64(0), nil},
+		{[]interface{}{"a\nb", "a.b", "n"}, int64(1), nil},
+		{[]interface{}{[]byte("ABC"), []byte("abc"), "i"}, int64(0), nil},
+		{[]interface{}{nil, "a"}, nil, nil},
+		{[]interface{}{"a", nil}, nil, nil},
+		{[]interface{}{"a", "a", nil}, nil, nil},
+		{[]interface{}{"a", "(", ""}, nil, ErrRegexp},
+		{[]interface{}{"a", "a", "x"}, nil, errIncorrectArgs},
+	})
+}
+
+func TestRegexpInStr(t *testing.T) {
+	runRegexpTestCases(t, ast.RegexpInStr, []regexpTestCase{
+		{[]interface{}{"dog cat dog", "dog"}, int64(1), nil},
+		{[]interface{}{"dog cat dog", "dog", 2}, int64(9), nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 2}, int64(9), nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 3}, int64(0), nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 2, 1}, int64(12), nil},
+		{[]interface{}{"dog cat dog", "DOG", 1, 1, 0, "i"}, int64(1), nil},
+		{[]interface{}{"你好世界世界", "世界", 1, 2}, int64(5), nil},
+		{[]interface{}{"你好世界世界", "世界", 4, 1, 1}, int64(7), nil},
+		{[]interface{}{[]byte("你好世界"), []byte("世界")}, int64(7), nil},
+		{[]interface{}{"abc", "", 4}, int64(4), nil},
+		{[]interface{}{"abc", "b", 5}, nil, errRegexpIndexOutOfBounds},
+		{[]interface{}{"abc", "b", 0}, nil, errRegexpIndexOutOfBounds},
+		{[]interface{}{"abc", "b", 1, 1, 2}, nil, errIncorrectArgs},
+		{[]interface{}{"abc", "b", nil}, nil, nil},
+	})
+}
+
+func TestRegexpSubstr(t *testing.T) {
+	runRegexpTestCases(t, ast.RegexpSubstr, []regexpTestCase{
+		{[]interface{}{"abc def ghi", "[a-z]+"}, "abc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 3}, "ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 2, 2}, "def", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 4}, nil, nil},
+		{[]interface{}{"abc DEF ghi", "[a-z]+", 4, 1, "i"}, "DEF", nil},
+		{[]interface{}{"你好世界", "世.", 2}, "世界", nil},
+		{[]interface{}{"abc", "x"}, nil, nil},
+		{[]interface{}{"abc", "b", 5}, nil, errRegexpIndexOutOfBounds},
+		{[]interface{}{nil, "b"}, nil, nil},
+	})
+}
+
+func TestRegexpReplace(t *testing.T) {
+	runRegexpTestCases(t, ast.RegexpReplace, []regexpTestCase{
+		{[]interface{}{"a b c", "b", "X"}, "a X c", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X"}, "X X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 3}, "abc def X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 2}, "aX X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 5, 2}, "abc def X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 4}, "abc def ghi", nil},
+		{[]interface{}{"abc DEF", "def", "X", 1, 0, "i"}, "abc X", nil},
+		{[]interface{}{"a1 b2", "([a-z])([0-9])", "$2$1"}, "1a 2b", nil},
+		{[]interface{}{"a1 b2", "([a-z])([0-9])", "\\$1$12"}, "$1a2 $1b2", nil},
+		{[]interface{}{"a1", "([a-z])", "$2"}, nil, errRegexpIndexOutOfBounds},
+		{[]interface{}{"你好世界", "好.", "X", 2}, "你X界", nil},
+		{[]interface{}{"abc", "b", "X", 5}, nil, errRegexpIndexOutOfBounds},
+		{[]interface{}{"abc", "b", nil}, nil, nil},
+	})
+}
@@ -3999,7 +3999,10 @@ func (b *builtinWeightStringSig) evalString(row chunk.Row) (string, bool, error)
 	if isNull {
 		return "", true, nil
 	}
+	return b.weightString(str)
+}
 
+func (b *builtinWeightStringSig) weightString(str string) (string, bool, error) {
 	var ctor collate.Collator
 	// TODO: refactor padding codes after padding is implemented by all collators.
 	switch b.padding {
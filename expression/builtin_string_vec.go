@@ -3120,3 +3120,72 @@ func (b *builtinTranslateUTF8Sig) vecEvalString(input *chunk.Chunk, result *chun
 func (b *builtinTranslateUTF8Sig) vectorized() bool {
 	return true
 }
+
+func (b *builtinLoadFileSig) vectorized() bool {
+	return true
+}
+
+// vecEvalString evals LOAD_FILE(file_name), it always returns NULL because reading the files on the server is not
+// supported.
+func (b *builtinLoadFileSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		result.AppendNull()
+	}
+	return nil
+}
+
+func (b *builtinWeightStringNullSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinWeightStringNullSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		result.AppendNull()
+	}
+	return nil
+}
+
+func (b *builtinWeightStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinWeightStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull, err := b.weightString(buf.GetString(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
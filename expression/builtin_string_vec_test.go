@@ -532,6 +532,25 @@ var vecBuiltinStringCases2 = map[string][]vecExprBenchCase{
 			newNullWrappedGener(0.1, &constStrGener{"en_US"}),
 		}},
 	},
+	ast.LoadFile: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+	},
+	ast.WeightString: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETInt}},
+		{
+			retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners: []dataGenerator{newRandLenStrGener(0, 10)},
+			constants: []*Constant{nil, {Value: types.NewDatum("CHAR"), RetType: types.NewFieldType(mysql.TypeString)},
+				{Value: types.NewDatum(5), RetType: types.NewFieldType(mysql.TypeLonglong)}},
+		},
+		{
+			retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners: []dataGenerator{newRandLenStrGener(0, 10)},
+			constants: []*Constant{nil, {Value: types.NewDatum("BINARY"), RetType: types.NewFieldType(mysql.TypeString)},
+				{Value: types.NewDatum(5), RetType: types.NewFieldType(mysql.TypeLonglong)}},
+		},
+	},
 }
 
 func TestVectorizedBuiltinStringEvalOneVec(t *testing.T) {
@@ -122,6 +122,7 @@ var (
 	_ functionClass = &timestampFunctionClass{}
 	_ functionClass = &timestampLiteralFunctionClass{}
 	_ functionClass = &lastDayFunctionClass{}
+	_ functionClass = &timeBucketFunctionClass{}
 	_ functionClass = &addDateFunctionClass{}
 	_ functionClass = &subDateFunctionClass{}
 )
@@ -215,6 +216,7 @@ var (
 	_ builtinFunc = &builtinTimestamp2ArgsSig{}
 	_ builtinFunc = &builtinTimestampLiteralSig{}
 	_ builtinFunc = &builtinLastDaySig{}
+	_ builtinFunc = &builtinTimeBucketSig{}
 	_ builtinFunc = &builtinStrToDateDateSig{}
 	_ builtinFunc = &builtinStrToDateDatetimeSig{}
 	_ builtinFunc = &builtinStrToDateDurationSig{}
@@ -2663,6 +2665,10 @@ func (b *builtinExtractDatetimeFromStringSig) evalInt(row chunk.Row) (int64, boo
 	if isNull || err != nil {
 		return 0, isNull, err
 	}
+	return b.extractDatetimeFromString(unit, dtStr)
+}
+
+func (b *builtinExtractDatetimeFromStringSig) extractDatetimeFromString(unit, dtStr string) (int64, bool, error) {
 	sc := b.ctx.GetSessionVars().StmtCtx
 	switch strings.ToUpper(unit) {
 	case "DAY_MICROSECOND", "DAY_SECOND", "DAY_MINUTE", "DAY_HOUR":
@@ -7029,6 +7035,167 @@ func (b *builtinLastDaySig) evalTime(row chunk.Row) (types.Time, bool, error) {
 	return ret, false, nil
 }
 
+var (
+	// defaultTimeBucketOrigin is the origin of the buckets whose width is counted in days or shorter units.
+	// It's a Monday, so the buckets of weeks start on Mondays.
+	defaultTimeBucketOrigin = types.FromDate(2000, 1, 3, 0, 0, 0, 0)
+	// defaultMonthTimeBucketOrigin is the origin of the buckets whose width is counted in months.
+	defaultMonthTimeBucketOrigin = types.FromDate(2000, 1, 1, 0, 0, 0, 0)
+)
+
+type timeBucketFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *timeBucketFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getExpressionFsp(ctx, args[2])
+	if err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETDatetime}
+	if len(args) == 4 {
+		originFsp, err := getExpressionFsp(ctx, args[3])
+		if err != nil {
+			return nil, err
+		}
+		fsp = mathutil.Max(fsp, originFsp)
+		argTps = append(argTps, types.ETDatetime)
+	}
+	// The buckets may start at the fractional seconds if the width has a fractional part.
+	if !isIntegralTimeBucketWidth(ctx, args[0], args[1]) {
+		fsp = int(types.MaxFsp)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETDatetime, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	bf.setDecimalAndFlenForDatetime(fsp)
+	sig := &builtinTimeBucketSig{bf}
+	return sig, nil
+}
+
+// isIntegralTimeBucketWidth checks whether the width of the buckets is a constant number of seconds.
+func isIntegralTimeBucketWidth(ctx sessionctx.Context, intervalArg, unitArg Expression) bool {
+	intervalConst, ok1 := intervalArg.(*Constant)
+	unitConst, ok2 := unitArg.(*Constant)
+	if !ok1 || !ok2 {
+		return false
+	}
+	interval, isNull, err := intervalConst.EvalString(ctx, chunk.Row{})
+	if isNull || err != nil {
+		return false
+	}
+	unit, isNull, err := unitConst.EvalString(ctx, chunk.Row{})
+	if isNull || err != nil {
+		return false
+	}
+	_, _, _, nano, err := types.ParseDurationValue(unit, interval)
+	return err == nil && nano%int64(time.Second) == 0
+}
+
+type builtinTimeBucketSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTimeBucketSig) Clone() builtinFunc {
+	newSig := &builtinTimeBucketSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals TIME_BUCKET(INTERVAL expr unit, ts [, origin]), which returns the start of the bucket containing
+// ts. The buckets have the same width and are aligned to origin.
+func (b *builtinTimeBucketSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	interval, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	unit, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, err
+	}
+	ts, isNull, err := b.args[2].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+	}
+	var origin *types.Time
+	if len(b.args) == 4 {
+		o, isNull, err := b.args[3].EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return types.ZeroTime, true, handleInvalidTimeError(b.ctx, err)
+		}
+		origin = &o
+	}
+	return b.bucket(interval, unit, ts, origin)
+}
+
+// bucket calculates the start of the bucket. The width is either a number of months or a fixed duration,
+// the buckets counted in months end at the same day of the months as origin, or the last day if the months
+// are shorter.
+func (b *builtinTimeBucketSig) bucket(interval, unit string, ts types.Time, origin *types.Time) (types.Time, bool, error) {
+	year, month, day, nano, err := types.ParseDurationValue(unit, interval)
+	if err := handleInvalidTimeError(b.ctx, err); err != nil {
+		return types.ZeroTime, true, err
+	}
+	months := year*12 + month
+	// The widths longer than the range of datetime are meaningless, they are rejected to avoid overflowing.
+	const maxDays = 10000 * 366
+	if months < 0 || day < 0 || nano < 0 || (months == 0) == (day == 0 && nano == 0) || months > maxDays/28 || day > maxDays {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, types.ErrWrongValue.GenWithStackByArgs("INTERVAL", interval))
+	}
+
+	var originTime types.CoreTime
+	switch {
+	case origin != nil:
+		originTime = origin.CoreTime()
+	case months > 0:
+		originTime = defaultMonthTimeBucketOrigin
+	default:
+		originTime = defaultTimeBucketOrigin
+	}
+	tsGo, err := ts.GoTime(time.UTC)
+	if err := handleInvalidTimeError(b.ctx, err); err != nil {
+		return types.ZeroTime, true, err
+	}
+	originGo, err := originTime.GoTime(time.UTC)
+	if err := handleInvalidTimeError(b.ctx, err); err != nil {
+		return types.ZeroTime, true, err
+	}
+
+	var start time.Time
+	if months > 0 {
+		diff := int64(tsGo.Year()*12+int(tsGo.Month())) - int64(originGo.Year()*12+int(originGo.Month()))
+		n := floorDiv(diff, months)
+		start = types.AddDate(0, n*months, 0, originGo)
+		if start.After(tsGo) {
+			start = types.AddDate(0, (n-1)*months, 0, originGo)
+		}
+	} else {
+		width := day*int64(types.GoDurationDay/time.Microsecond) + nano/int64(time.Microsecond)
+		tsMicros := tsGo.Unix()*1e6 + int64(tsGo.Nanosecond()/1e3)
+		originMicros := originGo.Unix()*1e6 + int64(originGo.Nanosecond()/1e3)
+		startMicros := originMicros + floorDiv(tsMicros-originMicros, width)*width
+		secs := floorDiv(startMicros, 1e6)
+		start = time.Unix(secs, (startMicros-secs*1e6)*1e3).UTC()
+	}
+	if start.Year() < 0 || start.Year() > 9999 {
+		return types.ZeroTime, true, handleInvalidTimeError(b.ctx, types.ErrDatetimeFunctionOverflow.GenWithStackByArgs("datetime"))
+	}
+	return types.NewTime(types.FromGoTime(start), mysql.TypeDatetime, int8(b.tp.Decimal)), false, nil
+}
+
+// floorDiv returns the largest integer which is less than or equal to a/b, b must be positive.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b < 0 {
+		q--
+	}
+	return q
+}
+
 // getExpressionFsp calculates the fsp from given expression.
 // This function must by called before calling newBaseBuiltinFuncWithTp.
 func getExpressionFsp(ctx sessionctx.Context, expression Expression) (int, error) {
@@ -7127,7 +7294,7 @@ type builtinTiDBBoundedStalenessSig struct {
 }
 
 func (b *builtinTiDBBoundedStalenessSig) Clone() builtinFunc {
-	newSig := &builtinTidbParseTsoSig{}
+	newSig := &builtinTiDBBoundedStalenessSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
@@ -2877,6 +2877,64 @@ func TestLastDay(t *testing.T) {
 	}
 }
 
+func TestTimeBucket(t *testing.T) {
+	ctx := createContext(t)
+	tests := []struct {
+		interval interface{}
+		unit     string
+		ts       interface{}
+		origin   interface{}
+		expect   interface{}
+	}{
+		{5, "MINUTE", "2022-03-04 10:17:33", nil, "2022-03-04 10:15:00"},
+		{1, "WEEK", "2022-03-06 10:00:00", nil, "2022-02-28 00:00:00"},
+		{1, "DAY", "1999-12-31 23:59:59", nil, "1999-12-31 00:00:00"},
+		{3, "HOUR", "2022-03-04 10:17:33", "2022-01-01 01:00:00", "2022-03-04 10:00:00"},
+		{3, "HOUR", "2021-12-31 23:17:33", "2022-01-01 01:00:00", "2021-12-31 22:00:00"},
+		{"1:30", "HOUR_MINUTE", "2022-03-04 10:17:33", nil, "2022-03-04 09:00:00"},
+		{"1 12", "DAY_HOUR", "2000-01-05 11:59:59", nil, "2000-01-04 12:00:00"},
+		{"1.5", "SECOND", "2022-03-04 10:17:33.7", nil, "2022-03-04 10:17:33.000000"},
+		{250, "MICROSECOND", "2022-03-04 10:17:33.123456", nil, "2022-03-04 10:17:33.123250"},
+		{1, "MONTH", "2022-03-04 10:17:33", nil, "2022-03-01 00:00:00"},
+		{1, "QUARTER", "2022-05-31", nil, "2022-04-01 00:00:00"},
+		{1, "YEAR", "2022-05-31", "2000-07-01", "2021-07-01 00:00:00"},
+		{"1-6", "YEAR_MONTH", "1999-05-31", nil, "1998-07-01 00:00:00"},
+		{1, "MONTH", "2022-02-28 12:00:00", "2022-01-31", "2022-02-28 00:00:00"},
+		{1, "MONTH", "2022-02-27 12:00:00", "2022-01-31", "2022-01-31 00:00:00"},
+		{nil, "MINUTE", "2022-03-04 10:17:33", nil, nil},
+		{5, "MINUTE", nil, nil, nil},
+		{0, "MINUTE", "2022-03-04 10:17:33", nil, nil},
+		{-1, "DAY", "2022-03-04 10:17:33", nil, nil},
+		{"abc", "DAY", "2022-03-04 10:17:33", nil, nil},
+		{5, "MINUTE", "0000-00-00 00:00:00", nil, nil},
+	}
+	fc := funcs[ast.TimeBucket]
+	for i, test := range tests {
+		args := []interface{}{test.interval, test.unit, test.ts}
+		if test.origin != nil {
+			args = append(args, test.origin)
+		}
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(args...)))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		if test.expect == nil {
+			require.True(t, d.IsNull(), "case %d", i)
+			continue
+		}
+		result, err := d.ToString()
+		require.NoError(t, err)
+		require.Equal(t, test.expect, result, "case %d", i)
+	}
+
+	// The result is NULL if the origin is NULL.
+	f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(5, "MINUTE", "2022-03-04 10:17:33", nil)))
+	require.NoError(t, err)
+	d, err := evalBuiltinFunc(f, chunk.Row{})
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+}
+
 func TestWithTimeZone(t *testing.T) {
 	ctx := createContext(t)
 	sv := ctx.GetSessionVars()
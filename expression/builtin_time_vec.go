@@ -207,8 +207,7 @@ func (b *builtinSysDateWithoutFspSig) vecEvalTime(input *chunk.Chunk, result *ch
 }
 
 func (b *builtinExtractDatetimeFromStringSig) vectorized() bool {
-	// TODO: to fix https://github.com/pingcap/tidb/issues/9716 in vectorized evaluation.
-	return false
+	return true
 }
 
 func (b *builtinExtractDatetimeFromStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
@@ -227,33 +226,25 @@ func (b *builtinExtractDatetimeFromStringSig) vecEvalInt(input *chunk.Chunk, res
 		return err
 	}
 	defer b.bufAllocator.put(buf1)
-	if err := b.args[1].VecEvalTime(b.ctx, input, buf1); err != nil {
+	if err := b.args[1].VecEvalString(b.ctx, input, buf1); err != nil {
 		return err
 	}
 
 	result.ResizeInt64(n, false)
 	i64s := result.Int64s()
-	ds := buf1.Times()
 	result.MergeNulls(buf, buf1)
 	for i := 0; i < n; i++ {
 		if result.IsNull(i) {
 			continue
 		}
-		if ds[i].IsZero() {
-			i64s[i] = 0
-			if b.ctx.GetSessionVars().SQLMode.HasNoZeroDateMode() {
-				isNull, err := handleInvalidZeroTime(b.ctx, ds[i])
-				if err != nil {
-					return err
-				}
-				result.SetNull(i, isNull)
-			}
-			continue
-		}
-		res, err := types.ExtractDatetimeNum(&ds[i], buf.GetString(i))
+		res, isNull, err := b.extractDatetimeFromString(buf.GetString(i), buf1.GetString(i))
 		if err != nil {
 			return err
 		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
 		i64s[i] = res
 	}
 	return nil
@@ -728,6 +719,68 @@ func (b *builtinLastDaySig) vecEvalTime(input *chunk.Chunk, result *chunk.Column
 	return nil
 }
 
+func (b *builtinTimeBucketSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTimeBucketSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	intervalBuf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(intervalBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, intervalBuf); err != nil {
+		return err
+	}
+	unitBuf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(unitBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, unitBuf); err != nil {
+		return err
+	}
+	if err := b.args[2].VecEvalTime(b.ctx, input, result); err != nil {
+		return err
+	}
+	result.MergeNulls(intervalBuf, unitBuf)
+	var origins []types.Time
+	if len(b.args) == 4 {
+		originBuf, err := b.bufAllocator.get()
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(originBuf)
+		if err := b.args[3].VecEvalTime(b.ctx, input, originBuf); err != nil {
+			return err
+		}
+		result.MergeNulls(originBuf)
+		origins = originBuf.Times()
+	}
+
+	times := result.Times()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		var origin *types.Time
+		if origins != nil {
+			origin = &origins[i]
+		}
+		start, isNull, err := b.bucket(intervalBuf.GetString(i), unitBuf.GetString(i), times[i], origin)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		times[i] = start
+	}
+	return nil
+}
+
 func (b *builtinStrToDateDateSig) vectorized() bool {
 	return true
 }
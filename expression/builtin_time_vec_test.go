@@ -531,9 +531,30 @@ var vecBuiltinTimeCases = map[string][]vecExprBenchCase{
 	ast.LastDay: {
 		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETDatetime}},
 	},
-	/* TODO: to fix https://github.com/pingcap/tidb/issues/9716 in vectorized evaluation.
+	ast.TimeBucket: {
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETDatetime},
+			geners:    []dataGenerator{newSelectStringGener([]string{"1", "5", "15", "0", "-1"})},
+			constants: []*Constant{nil, {Value: types.NewStringDatum("MINUTE"), RetType: types.NewFieldType(mysql.TypeString)}},
+		},
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETDatetime, types.ETDatetime},
+			geners: []dataGenerator{newSelectStringGener([]string{"1", "3", "1-6"}), newSelectStringGener([]string{"MONTH", "QUARTER", "YEAR_MONTH"})},
+		},
+		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETDatetime, types.ETDatetime},
+			geners: []dataGenerator{newSelectStringGener([]string{"2", "1.5", "1:30"}), newUnitStrGener()},
+		},
+	},
 	ast.Extract: {
-		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETDatetime}, geners: []dataGenerator{newDateTimeUnitStrGener(), nil}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETDatetime},
+			constants: []*Constant{{Value: types.NewStringDatum("YEAR_MONTH"), RetType: types.NewFieldType(mysql.TypeString)}},
+		},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners:    []dataGenerator{nil, &dateTimeStrGener{randGen: newDefaultRandGen()}},
+			constants: []*Constant{{Value: types.NewStringDatum("YEAR_MONTH"), RetType: types.NewFieldType(mysql.TypeString)}},
+		},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners:    []dataGenerator{nil, &dateTimeStrGener{randGen: newDefaultRandGen()}},
+			constants: []*Constant{{Value: types.NewStringDatum("DAY_SECOND"), RetType: types.NewFieldType(mysql.TypeString)}},
+		},
 		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETDuration},
 			constants: []*Constant{{Value: types.NewStringDatum("MICROSECOND"), RetType: types.NewFieldType(mysql.TypeString)}},
 		},
@@ -565,7 +586,6 @@ var vecBuiltinTimeCases = map[string][]vecExprBenchCase{
 			constants: []*Constant{{Value: types.NewStringDatum("HOUR_MINUTE"), RetType: types.NewFieldType(mysql.TypeString)}},
 		},
 	},
-	*/
 	ast.ConvertTz: {
 		{retEvalType: types.ETDatetime, childrenTypes: []types.EvalType{types.ETDatetime, types.ETString, types.ETString},
 			geners: []dataGenerator{nil, newNullWrappedGener(0.2, &tzStrGener{}), newNullWrappedGener(0.2, &tzStrGener{})}},
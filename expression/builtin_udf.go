@@ -0,0 +1,210 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/tetratelabs/wazero/api"
+)
+
+var (
+	_ functionClass = &userDefinedFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinWasmUDFIntSig{}
+	_ builtinFunc = &builtinWasmUDFRealSig{}
+)
+
+type userDefinedFunctionClass struct {
+	baseFunctionClass
+	udf *UserDefinedFunction
+}
+
+func newUserDefinedFunctionClass(udf *UserDefinedFunction) *userDefinedFunctionClass {
+	n := len(udf.ParamTypes)
+	return &userDefinedFunctionClass{baseFunctionClass{udf.Name, n, n}, udf}
+}
+
+func (c *userDefinedFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for _, tp := range c.udf.ParamTypes {
+		argTps = append(argTps, tp.EvalType())
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, c.udf.RetType.EvalType(), argTps...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp = c.udf.RetType.Clone()
+	base := builtinWasmUDFSig{bf, c.udf}
+	if c.udf.RetType.EvalType() == types.ETReal {
+		return &builtinWasmUDFRealSig{base}, nil
+	}
+	return &builtinWasmUDFIntSig{base}, nil
+}
+
+// builtinWasmUDFSig is the shared implementation of the user-defined functions in WebAssembly.
+type builtinWasmUDFSig struct {
+	baseBuiltinFunc
+	udf *UserDefinedFunction
+}
+
+// evalParams evaluates the arguments of the row and encodes them as the WebAssembly values.
+func (b *builtinWasmUDFSig) evalParams(row chunk.Row, params []uint64) (isNull bool, err error) {
+	for i, arg := range b.args {
+		if arg.GetType().EvalType() == types.ETReal {
+			v, isNull, err := arg.EvalReal(b.ctx, row)
+			if isNull || err != nil {
+				return true, err
+			}
+			params[i] = api.EncodeF64(v)
+			continue
+		}
+		v, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return true, err
+		}
+		params[i] = api.EncodeI64(v)
+	}
+	return false, nil
+}
+
+func (b *builtinWasmUDFSig) call(row chunk.Row) (res uint64, isNull bool, err error) {
+	params := make([]uint64, len(b.args))
+	if isNull, err = b.evalParams(row, params); isNull || err != nil {
+		return 0, true, err
+	}
+	caller, err := b.udf.newCaller()
+	if err != nil {
+		return 0, true, err
+	}
+	defer caller.close()
+	res, err = caller.call(params)
+	return res, err != nil, err
+}
+
+// vecCall evaluates the arguments of the chunk and calls the function with the same instance for
+// every row, the result is NULL if any argument is NULL.
+func (b *builtinWasmUDFSig) vecCall(input *chunk.Chunk, result *chunk.Column, fn func(i int, res uint64)) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, 0, len(b.args))
+	defer func() {
+		for _, buf := range bufs {
+			b.bufAllocator.put(buf)
+		}
+	}()
+	for _, arg := range b.args {
+		buf, err := b.bufAllocator.get()
+		if err != nil {
+			return err
+		}
+		bufs = append(bufs, buf)
+		if arg.GetType().EvalType() == types.ETReal {
+			err = arg.VecEvalReal(b.ctx, input, buf)
+		} else {
+			err = arg.VecEvalInt(b.ctx, input, buf)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	result.MergeNulls(bufs...)
+
+	caller, err := b.udf.newCaller()
+	if err != nil {
+		return err
+	}
+	defer caller.close()
+	params := make([]uint64, len(b.args))
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		for j, buf := range bufs {
+			if b.args[j].GetType().EvalType() == types.ETReal {
+				params[j] = api.EncodeF64(buf.GetFloat64(i))
+			} else {
+				params[j] = api.EncodeI64(buf.GetInt64(i))
+			}
+		}
+		res, err := caller.call(params)
+		if err != nil {
+			return err
+		}
+		fn(i, res)
+	}
+	return nil
+}
+
+type builtinWasmUDFIntSig struct {
+	builtinWasmUDFSig
+}
+
+func (b *builtinWasmUDFIntSig) Clone() builtinFunc {
+	newSig := &builtinWasmUDFIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.udf = b.udf
+	return newSig
+}
+
+func (b *builtinWasmUDFIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	res, isNull, err := b.call(row)
+	return int64(res), isNull, err
+}
+
+func (b *builtinWasmUDFIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinWasmUDFIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	result.ResizeInt64(input.NumRows(), false)
+	i64s := result.Int64s()
+	return b.vecCall(input, result, func(i int, res uint64) {
+		i64s[i] = int64(res)
+	})
+}
+
+type builtinWasmUDFRealSig struct {
+	builtinWasmUDFSig
+}
+
+func (b *builtinWasmUDFRealSig) Clone() builtinFunc {
+	newSig := &builtinWasmUDFRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.udf = b.udf
+	return newSig
+}
+
+func (b *builtinWasmUDFRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	res, isNull, err := b.call(row)
+	return api.DecodeF64(res), isNull, err
+}
+
+func (b *builtinWasmUDFRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinWasmUDFRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	result.ResizeFloat64(input.NumRows(), false)
+	f64s := result.Float64s()
+	return b.vecCall(input, result, func(i int, res uint64) {
+		f64s[i] = api.DecodeF64(res)
+	})
+}
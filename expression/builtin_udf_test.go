@@ -0,0 +1,165 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// wasmAddModule exports `add(i64, i64) i64`.
+	wasmAddModule = []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+		0x01, 0x07, 0x01, 0x60, 0x02, 0x7e, 0x7e, 0x01, 0x7e,
+		0x03, 0x02, 0x01, 0x00,
+		0x07, 0x07, 0x01, 0x03, 'a', 'd', 'd', 0x00, 0x00,
+		0x0a, 0x09, 0x01, 0x07, 0x00, 0x20, 0x00, 0x20, 0x01, 0x7c, 0x0b,
+	}
+	// wasmMulModule exports `mul(f64, f64) f64`.
+	wasmMulModule = []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+		0x01, 0x07, 0x01, 0x60, 0x02, 0x7c, 0x7c, 0x01, 0x7c,
+		0x03, 0x02, 0x01, 0x00,
+		0x07, 0x07, 0x01, 0x03, 'm', 'u', 'l', 0x00, 0x00,
+		0x0a, 0x09, 0x01, 0x07, 0x00, 0x20, 0x00, 0x20, 0x01, 0xa2, 0x0b,
+	}
+	// wasmSpinModule exports `spin() i64` which never returns.
+	wasmSpinModule = []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+		0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7e,
+		0x03, 0x02, 0x01, 0x00,
+		0x07, 0x08, 0x01, 0x04, 's', 'p', 'i', 'n', 0x00, 0x00,
+		0x0a, 0x0b, 0x01, 0x09, 0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x42, 0x00, 0x0b,
+	}
+)
+
+type mockUserDefinedFunctions map[string]*UserDefinedFunction
+
+func (m mockUserDefinedFunctions) GetUserDefinedFunction(name string) (*UserDefinedFunction, bool) {
+	udf, ok := m[name]
+	return udf, ok
+}
+
+func TestNewWasmUserDefinedFunction(t *testing.T) {
+	bigint := types.NewFieldType(mysql.TypeLonglong)
+	double := types.NewFieldType(mysql.TypeDouble)
+	varchar := types.NewFieldType(mysql.TypeVarchar)
+
+	udf, err := NewWasmUserDefinedFunction("add", []*types.FieldType{bigint, bigint}, bigint, wasmAddModule)
+	require.NoError(t, err)
+	udf.Close()
+
+	_, err = NewWasmUserDefinedFunction("add", []*types.FieldType{bigint, bigint}, bigint, []byte("module"))
+	require.True(t, ErrInvalidUserDefinedFunction.Equal(err))
+	_, err = NewWasmUserDefinedFunction("sub", []*types.FieldType{bigint, bigint}, bigint, wasmAddModule)
+	require.EqualError(t, err, "[expression:8249]Invalid user-defined function 'sub': the module doesn't export the function 'sub'")
+	_, err = NewWasmUserDefinedFunction("add", []*types.FieldType{bigint, double}, bigint, wasmAddModule)
+	require.EqualError(t, err, "[expression:8249]Invalid user-defined function 'add': the signature of the exported function doesn't match the declared types")
+	_, err = NewWasmUserDefinedFunction("add", []*types.FieldType{bigint}, bigint, wasmAddModule)
+	require.EqualError(t, err, "[expression:8249]Invalid user-defined function 'add': the signature of the exported function doesn't match the declared types")
+	_, err = NewWasmUserDefinedFunction("add", []*types.FieldType{bigint, varchar}, bigint, wasmAddModule)
+	require.EqualError(t, err, "[expression:8249]Invalid user-defined function 'add': type varchar of parameter 2 is not supported")
+	_, err = NewWasmUserDefinedFunction("add", []*types.FieldType{bigint, bigint}, varchar, wasmAddModule)
+	require.EqualError(t, err, "[expression:8249]Invalid user-defined function 'add': return type varchar is not supported")
+}
+
+func TestWasmUserDefinedFunction(t *testing.T) {
+	ctx := createContext(t)
+	bigint := types.NewFieldType(mysql.TypeLonglong)
+	double := types.NewFieldType(mysql.TypeDouble)
+	add, err := NewWasmUserDefinedFunction("add", []*types.FieldType{bigint, bigint}, bigint, wasmAddModule)
+	require.NoError(t, err)
+	defer add.Close()
+	mul, err := NewWasmUserDefinedFunction("mul", []*types.FieldType{double, double}, double, wasmMulModule)
+	require.NoError(t, err)
+	defer mul.Close()
+
+	ctx.GetSessionVars().CurrentDB = "test"
+	_, err = NewFunction(ctx, "add", types.NewFieldType(mysql.TypeUnspecified), datumsToConstants(types.MakeDatums(1, 2))...)
+	require.True(t, errFunctionNotExists.Equal(err))
+	BindUserDefinedFunctions(ctx, mockUserDefinedFunctions{"add": add, "mul": mul})
+
+	cases := []struct {
+		name     string
+		args     []interface{}
+		expected interface{}
+	}{
+		{"add", []interface{}{1, 2}, int64(3)},
+		{"add", []interface{}{"-5", 2.4}, int64(-3)},
+		{"add", []interface{}{1, nil}, nil},
+		{"mul", []interface{}{1.5, 4}, float64(6)},
+		{"mul", []interface{}{nil, 4}, nil},
+	}
+	for _, c := range cases {
+		f, err := NewFunctionBase(ctx, c.name, types.NewFieldType(mysql.TypeUnspecified), datumsToConstants(types.MakeDatums(c.args...))...)
+		require.NoError(t, err)
+		d, err := f.Eval(chunk.Row{})
+		require.NoError(t, err)
+		if c.expected == nil {
+			require.True(t, d.IsNull())
+		} else {
+			require.Equal(t, c.expected, d.GetValue())
+		}
+	}
+	_, err = NewFunction(ctx, "add", types.NewFieldType(mysql.TypeUnspecified), datumsToConstants(types.MakeDatums(1))...)
+	require.True(t, ErrIncorrectParameterCount.Equal(err))
+
+	// Evaluate in a vectorized way.
+	fieldTps := []*types.FieldType{bigint, bigint}
+	input := chunk.New(fieldTps, 3, 3)
+	input.AppendInt64(0, 1)
+	input.AppendInt64(1, 10)
+	input.AppendNull(0)
+	input.AppendInt64(1, 20)
+	input.AppendInt64(0, -3)
+	input.AppendInt64(1, 30)
+	f, err := NewFunctionBase(ctx, "add", types.NewFieldType(mysql.TypeUnspecified),
+		&Column{Index: 0, RetType: bigint}, &Column{Index: 1, RetType: bigint})
+	require.NoError(t, err)
+	require.True(t, f.Vectorized())
+	result := chunk.NewColumn(bigint, 3)
+	require.NoError(t, f.VecEvalInt(ctx, input, result))
+	require.Equal(t, int64(11), result.GetInt64(0))
+	require.True(t, result.IsNull(1))
+	require.Equal(t, int64(27), result.GetInt64(2))
+}
+
+func TestWasmUserDefinedFunctionTimeout(t *testing.T) {
+	origin := UDFExecutionTimeout
+	UDFExecutionTimeout = 100 * time.Millisecond
+	defer func() {
+		UDFExecutionTimeout = origin
+	}()
+
+	ctx := createContext(t)
+	spin, err := NewWasmUserDefinedFunction("spin", nil, types.NewFieldType(mysql.TypeLonglong), wasmSpinModule)
+	require.NoError(t, err)
+	BindUserDefinedFunctions(ctx, mockUserDefinedFunctions{"spin": spin})
+	f, err := NewFunctionBase(ctx, "spin", types.NewFieldType(mysql.TypeUnspecified))
+	require.NoError(t, err)
+	_, err = f.Eval(chunk.Row{})
+	require.EqualError(t, err, "[expression:8250]Failed to execute the user-defined function 'spin': execution timed out")
+
+	// The function fails after it's dropped.
+	spin.Close()
+	_, err = f.Eval(chunk.Row{})
+	require.EqualError(t, err, "[expression:8250]Failed to execute the user-defined function 'spin': the function has been dropped")
+}
@@ -0,0 +1,193 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+var (
+	_ functionClass = &vecCosineDistanceFunctionClass{}
+	_ functionClass = &vecL2DistanceFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinVecCosineDistanceSig{}
+	_ builtinFunc = &builtinVecL2DistanceSig{}
+)
+
+// vecDistanceFunc computes the distance between two vectors, isNull is true if the distance is undefined.
+type vecDistanceFunc func(a, b types.VectorFloat32) (distance float64, isNull bool, err error)
+
+// newVecDistanceFunction creates the vector distance functions, which are vec_xxx_distance(v1, v2).
+// The arguments are vectors or strings in the text form of vectors.
+func newVecDistanceFunction(ctx sessionctx.Context, c *baseFunctionClass, args []Expression) (baseBuiltinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return baseBuiltinFunc{}, err
+	}
+	return newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString, types.ETString)
+}
+
+// evalVecDistance evaluates the two vectors of the row and computes the distance between them.
+func evalVecDistance(b *baseBuiltinFunc, row chunk.Row, distance vecDistanceFunc) (float64, bool, error) {
+	str1, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	str2, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return computeVecDistance(str1, str2, distance)
+}
+
+// vecEvalVecDistance evaluates the two vectors of the chunk and computes the distances row by row.
+func vecEvalVecDistance(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, distance vecDistanceFunc) error {
+	n := input.NumRows()
+	buf1, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf1); err != nil {
+		return err
+	}
+	buf2, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf2)
+	if err := b.args[1].VecEvalString(b.ctx, input, buf2); err != nil {
+		return err
+	}
+
+	result.ResizeFloat64(n, false)
+	result.MergeNulls(buf1, buf2)
+	f64s := result.Float64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, isNull, err := computeVecDistance(buf1.GetString(i), buf2.GetString(i), distance)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		f64s[i] = res
+	}
+	return nil
+}
+
+func computeVecDistance(str1, str2 string, distance vecDistanceFunc) (float64, bool, error) {
+	v1, err := types.ParseVectorFloat32(str1)
+	if err != nil {
+		return 0, true, err
+	}
+	v2, err := types.ParseVectorFloat32(str2)
+	if err != nil {
+		return 0, true, err
+	}
+	res, isNull, err := distance(v1, v2)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return res, false, nil
+}
+
+type vecCosineDistanceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecCosineDistanceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	bf, err := newVecDistanceFunction(ctx, &c.baseFunctionClass, args)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinVecCosineDistanceSig{bf}
+	return sig, nil
+}
+
+type builtinVecCosineDistanceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecCosineDistanceSig) Clone() builtinFunc {
+	newSig := &builtinVecCosineDistanceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func cosineDistance(a, b types.VectorFloat32) (float64, bool, error) {
+	return a.CosineDistance(b)
+}
+
+// evalReal evals VEC_COSINE_DISTANCE(v1, v2), it returns NULL if any of the vectors is a zero vector.
+func (b *builtinVecCosineDistanceSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalVecDistance(&b.baseBuiltinFunc, row, cosineDistance)
+}
+
+func (b *builtinVecCosineDistanceSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinVecCosineDistanceSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalVecDistance(&b.baseBuiltinFunc, input, result, cosineDistance)
+}
+
+type vecL2DistanceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *vecL2DistanceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	bf, err := newVecDistanceFunction(ctx, &c.baseFunctionClass, args)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinVecL2DistanceSig{bf}
+	return sig, nil
+}
+
+type builtinVecL2DistanceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVecL2DistanceSig) Clone() builtinFunc {
+	newSig := &builtinVecL2DistanceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func l2Distance(a, b types.VectorFloat32) (float64, bool, error) {
+	res, err := a.L2Distance(b)
+	return res, false, err
+}
+
+// evalReal evals VEC_L2_DISTANCE(v1, v2), which is the euclidean distance between the vectors.
+func (b *builtinVecL2DistanceSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalVecDistance(&b.baseBuiltinFunc, row, l2Distance)
+}
+
+func (b *builtinVecL2DistanceSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinVecL2DistanceSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalVecDistance(&b.baseBuiltinFunc, input, result, l2Distance)
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+type vecDistanceTestCase struct {
+	v1     interface{}
+	v2     interface{}
+	expect interface{}
+	err    *terror.Error
+}
+
+func runVecDistanceTestCases(t *testing.T, funcName string, tests []vecDistanceTestCase) {
+	ctx := createContext(t)
+	for _, tt := range tests {
+		comment := fmt.Sprintf("%s(%v, %v)", funcName, tt.v1, tt.v2)
+		f, err := funcs[funcName].getFunction(ctx, primitiveValsToConstants(ctx, []interface{}{tt.v1, tt.v2}))
+		require.NoError(t, err, comment)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			require.Truef(t, terror.ErrorEqual(err, tt.err), "%s: %v", comment, err)
+			continue
+		}
+		require.NoError(t, err, comment)
+		if tt.expect == nil {
+			require.True(t, d.IsNull(), comment)
+			continue
+		}
+		require.Equal(t, types.KindFloat64, d.Kind(), comment)
+		require.InDelta(t, tt.expect, d.GetFloat64(), 1e-6, comment)
+	}
+}
+
+func TestVecL2Distance(t *testing.T) {
+	runVecDistanceTestCases(t, ast.VecL2Distance, []vecDistanceTestCase{
+		{"[1,2,3]", "[1,2,3]", 0.0, nil},
+		{"[0,0]", "[3,4]", 5.0, nil},
+		{"[1, 2.5]", " [ -1,2.5 ] ", 2.0, nil},
+		{"[]", "[]", 0.0, nil},
+		{nil, "[1,2]", nil, nil},
+		{"[1,2]", nil, nil, nil},
+		{"[1,2]", "[1,2,3]", nil, types.ErrVectorDimensionMismatch},
+		{"[1,2]", "1,2", nil, types.ErrTruncatedWrongVal},
+		{"[1,a]", "[1,2]", nil, types.ErrTruncatedWrongVal},
+	})
+}
+
+func TestVecCosineDistance(t *testing.T) {
+	runVecDistanceTestCases(t, ast.VecCosineDistance, []vecDistanceTestCase{
+		{"[1,2,3]", "[2,4,6]", 0.0, nil},
+		{"[1,0]", "[0,1]", 1.0, nil},
+		{"[1,0]", "[-1,0]", 2.0, nil},
+		{"[1,1]", "[1,0]", 1 - 1/math.Sqrt2, nil},
+		{"[0,0]", "[1,2]", nil, nil},
+		{nil, "[1,2]", nil, nil},
+		{"[1,2]", "[1]", nil, types.ErrVectorDimensionMismatch},
+		{"[1,2", "[1,2]", nil, types.ErrTruncatedWrongVal},
+	})
+}
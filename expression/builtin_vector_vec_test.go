@@ -0,0 +1,49 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinVectorCases = map[string][]vecExprBenchCase{
+	ast.VecCosineDistance: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{
+				newSelectStringGener([]string{"[1,2,3]", "[0,0,0]", "[-1.5,2,0.25]"}),
+				newSelectStringGener([]string{"[3,2,1]", "[1,1,1]", "[0,0,0]"}),
+			},
+		},
+	},
+	ast.VecL2Distance: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{
+				newSelectStringGener([]string{"[1,2,3]", "[0,0,0]", "[-1.5,2,0.25]"}),
+				newSelectStringGener([]string{"[3,2,1]", "[1,1,1]", "[0,0,0]"}),
+			},
+		},
+	},
+}
+
+func TestVectorizedBuiltinVectorFunc(t *testing.T) {
+	testVectorizedBuiltinFunc(t, vecBuiltinVectorCases)
+}
+
+func BenchmarkVectorizedBuiltinVectorFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinVectorCases)
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rowBasedBuiltinSigs are the builtin function signatures which are evaluated row by row on purpose.
+var rowBasedBuiltinSigs = map[string]struct{}{
+	// VALUES() reads the row being inserted by INSERT ... ON DUPLICATE KEY UPDATE.
+	"builtinValuesIntSig":      {},
+	"builtinValuesRealSig":     {},
+	"builtinValuesDecimalSig":  {},
+	"builtinValuesStringSig":   {},
+	"builtinValuesTimeSig":     {},
+	"builtinValuesDurationSig": {},
+	"builtinValuesJSONSig":     {},
+	// The sequence functions must only be called for the rows which need them.
+	"builtinNextValSig": {},
+	"builtinLastValSig": {},
+	"builtinSetValSig":  {},
+}
+
+type builtinSigCoverage struct {
+	hasEval    bool
+	vectorized string
+}
+
+// TestVectorizedCoverage reports the builtin function signatures which can't be evaluated in a vectorized way.
+func TestVectorizedCoverage(t *testing.T) {
+	files, err := os.ReadDir(".")
+	require.NoError(t, err)
+	fset := token.NewFileSet()
+	sigs := make(map[string]*builtinSigCoverage)
+	getSig := func(name string) *builtinSigCoverage {
+		if sigs[name] == nil {
+			sigs[name] = &builtinSigCoverage{}
+		}
+		return sigs[name]
+	}
+	// The signatures embedded by other signatures are only shared implementations.
+	embedded := make(map[string]struct{})
+	for _, file := range files {
+		name := file.Name()
+		if !strings.HasPrefix(name, "builtin") || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		require.NoError(t, err)
+		ast.Inspect(f, func(node ast.Node) bool {
+			st, ok := node.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				if len(field.Names) != 0 {
+					continue
+				}
+				typ := field.Type
+				if star, ok := typ.(*ast.StarExpr); ok {
+					typ = star.X
+				}
+				if ident, ok := typ.(*ast.Ident); ok {
+					embedded[ident.Name] = struct{}{}
+				}
+			}
+			return true
+		})
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+				continue
+			}
+			star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			recv, ok := star.X.(*ast.Ident)
+			if !ok || !strings.HasPrefix(recv.Name, "builtin") || !strings.HasSuffix(recv.Name, "Sig") {
+				continue
+			}
+			switch method := fn.Name.Name; {
+			case method == "vectorized":
+				getSig(recv.Name).vectorized = "unknown"
+				if len(fn.Body.List) == 1 {
+					if ret, ok := fn.Body.List[0].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+						if ident, ok := ret.Results[0].(*ast.Ident); ok {
+							getSig(recv.Name).vectorized = ident.Name
+						}
+					}
+				}
+			case strings.HasPrefix(method, "eval"):
+				getSig(recv.Name).hasEval = true
+			}
+		}
+	}
+
+	var unvectorized []string
+	for name, sig := range sigs {
+		if _, ok := embedded[name]; ok || !sig.hasEval {
+			continue
+		}
+		if _, ok := rowBasedBuiltinSigs[name]; ok {
+			require.Equalf(t, "false", sig.vectorized, "%s is expected to be evaluated row by row", name)
+			continue
+		}
+		if sig.vectorized == "" || sig.vectorized == "false" {
+			unvectorized = append(unvectorized, name)
+		}
+	}
+	sort.Strings(unvectorized)
+	require.Emptyf(t, unvectorized, "the builtin function signatures are not vectorized: %s", strings.Join(unvectorized, ", "))
+}
@@ -36,6 +36,8 @@ var (
 	ErrInvalidTableSample          = dbterror.ClassExpression.NewStd(mysql.ErrInvalidTableSample)
 	ErrInternal                    = dbterror.ClassOptimizer.NewStd(mysql.ErrInternal)
 	ErrNoDB                        = dbterror.ClassOptimizer.NewStd(mysql.ErrNoDB)
+	ErrInvalidUserDefinedFunction  = dbterror.ClassExpression.NewStd(mysql.ErrInvalidUserDefinedFunction)
+	ErrUserDefinedFunctionFailed   = dbterror.ClassExpression.NewStd(mysql.ErrUserDefinedFunctionFailed)
 
 	// All the un-exported errors are defined here:
 	errFunctionNotExists             = dbterror.ClassExpression.NewStd(mysql.ErrSpDoesNotExist)
@@ -53,6 +55,12 @@ var (
 	errWrongValueForType             = dbterror.ClassExpression.NewStd(mysql.ErrWrongValueForType)
 	errUnknown                       = dbterror.ClassExpression.NewStd(mysql.ErrUnknown)
 	errSpecificAccessDenied          = dbterror.ClassExpression.NewStd(mysql.ErrSpecificAccessDenied)
+	errNotSupportedYet               = dbterror.ClassExpression.NewStd(mysql.ErrNotSupportedYet)
+	errRegexpIndexOutOfBounds        = dbterror.ClassExpression.NewStd(mysql.ErrRegexpIndexOutOfBounds)
+
+	// Multi-valued index related errors.
+	errInvalidJSONValueForFuncIndex    = dbterror.ClassExpression.NewStd(mysql.ErrInvalidJSONValueForFuncIndex)
+	errJSONValueOutOfRangeForFuncIndex = dbterror.ClassExpression.NewStd(mysql.ErrJSONValueOutOfRangeForFuncIndex)
 
 	// Sequence usage privilege check.
 	errSequenceAccessDenied      = dbterror.ClassExpression.NewStd(mysql.ErrTableaccessDenied)
@@ -1129,6 +1129,40 @@ func TestExprOnlyPushDownToTiKV(t *testing.T) {
 	require.Len(t, remained, 0)
 }
 
+func TestRegexpFuncPushDown(t *testing.T) {
+	sc := new(stmtctx.StatementContext)
+	client := new(mock.Client)
+
+	intColumn := genColumn(mysql.TypeLonglong, 1)
+	stringColumn := genColumn(mysql.TypeString, 2)
+	binaryStringColumn := genColumn(mysql.TypeString, 3)
+	binaryStringColumn.RetType.Collate = charset.CollationBin
+
+	tests := []struct {
+		funcName string
+		retTp    byte
+		args     []Expression
+		pbCode   tipb.ScalarFuncSig
+	}{
+		{ast.RegexpLike, mysql.TypeLonglong, []Expression{stringColumn, stringColumn}, tipb.ScalarFuncSig_RegexpLikeUTF8Sig},
+		{ast.RegexpLike, mysql.TypeLonglong, []Expression{binaryStringColumn, binaryStringColumn, stringColumn}, tipb.ScalarFuncSig_RegexpLikeSig},
+		{ast.RegexpInStr, mysql.TypeLonglong, []Expression{stringColumn, stringColumn, intColumn, intColumn, intColumn, stringColumn}, tipb.ScalarFuncSig_RegexpInStrUTF8Sig},
+		{ast.RegexpInStr, mysql.TypeLonglong, []Expression{binaryStringColumn, binaryStringColumn}, tipb.ScalarFuncSig_RegexpInStrSig},
+		{ast.RegexpSubstr, mysql.TypeString, []Expression{stringColumn, stringColumn, intColumn}, tipb.ScalarFuncSig_RegexpSubstrUTF8Sig},
+		{ast.RegexpSubstr, mysql.TypeString, []Expression{binaryStringColumn, binaryStringColumn}, tipb.ScalarFuncSig_RegexpSubstrSig},
+		{ast.RegexpReplace, mysql.TypeString, []Expression{stringColumn, stringColumn, stringColumn, intColumn, intColumn}, tipb.ScalarFuncSig_RegexpReplaceUTF8Sig},
+		{ast.RegexpReplace, mysql.TypeString, []Expression{binaryStringColumn, binaryStringColumn, binaryStringColumn}, tipb.ScalarFuncSig_RegexpReplaceSig},
+	}
+	for _, tt := range tests {
+		function, err := NewFunction(mock.NewContext(), tt.funcName, types.NewFieldType(tt.retTp), tt.args...)
+		require.NoError(t, err)
+		require.Equal(t, tt.pbCode, function.(*ScalarFunction).Function.PbCode())
+		exprs := []Expression{function}
+		require.True(t, CanExprsPushDown(sc, exprs, client, kv.TiKV))
+		require.True(t, CanExprsPushDown(sc, exprs, client, kv.TiFlash))
+	}
+}
+
 func TestGroupByItem2Pb(t *testing.T) {
 	sc := new(stmtctx.StatementContext)
 	client := new(mock.Client)
@@ -1190,6 +1224,51 @@ func TestPushCollationDown(t *testing.T) {
 	}
 }
 
+func TestPushCollationSensitiveFuncsDownToTiKV(t *testing.T) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+	sc := new(stmtctx.StatementContext)
+	client := new(mock.Client)
+	ctx := mock.NewContext()
+
+	for _, coll := range []string{"utf8mb4_bin", "utf8mb4_general_ci", "utf8mb4_unicode_ci", "utf8_general_ci", "utf8_unicode_ci", "utf8mb4_zh_pinyin_tidb_as_cs"} {
+		a := columnCollation(genColumn(mysql.TypeVarchar, 1), "utf8mb4", coll)
+		b := columnCollation(genColumn(mysql.TypeVarchar, 2), "utf8mb4", coll)
+		like, err := NewFunction(ctx, ast.Like, types.NewFieldType(mysql.TypeLonglong), a, b, &Constant{Value: types.NewIntDatum(int64('\\')), RetType: types.NewFieldType(mysql.TypeLonglong)})
+		require.NoError(t, err)
+		instr, err := NewFunction(ctx, ast.Instr, types.NewFieldType(mysql.TypeLonglong), a, b)
+		require.NoError(t, err)
+		locate, err := NewFunction(ctx, ast.Locate, types.NewFieldType(mysql.TypeLonglong), a, b)
+		require.NoError(t, err)
+		eq, err := NewFunction(ctx, ast.EQ, types.NewFieldType(mysql.TypeLonglong), a, b)
+		require.NoError(t, err)
+		lt, err := NewFunction(ctx, ast.LT, types.NewFieldType(mysql.TypeLonglong), a, b)
+		require.NoError(t, err)
+		exprs := []Expression{like, instr, locate, eq, lt}
+
+		// The collation isn't supported by TiKV, the results may be different from TiDB.
+		supported := coll != "utf8mb4_zh_pinyin_tidb_as_cs"
+		pushed, remained := PushDownExprs(sc, exprs, client, kv.TiKV)
+		if !supported {
+			require.Len(t, pushed, 0, coll)
+			require.Len(t, remained, len(exprs), coll)
+			continue
+		}
+		require.Len(t, pushed, len(exprs), coll)
+
+		// The collation is encoded into the field type of the functions.
+		pbExprs, err := ExpressionsToPBList(sc, exprs, client)
+		require.NoError(t, err)
+		for _, pbExpr := range pbExprs {
+			require.Equal(t, collate.CollationToProto(coll), pbExpr.FieldType.Collate, coll)
+			expr, err := PBToExpr(pbExpr, []*types.FieldType{a.RetType, a.RetType, b.RetType}, sc)
+			require.NoError(t, err)
+			_, eColl := expr.CharsetAndCollation()
+			require.Equal(t, coll, eColl)
+		}
+	}
+}
+
 func columnCollation(c *Column, chs, coll string) *Column {
 	c.RetType.Charset = chs
 	c.RetType.Collate = coll
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/opcode"
@@ -35,6 +36,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/generatedexpr"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tipb/go-tipb"
@@ -965,8 +967,9 @@ func scalarExprSupportedByTiKV(sf *ScalarFunction) bool {
 		ast.Case, ast.If, ast.Ifnull, ast.Coalesce,
 
 		// string functions.
-		ast.Length, ast.BitLength, ast.Concat, ast.ConcatWS /*ast.Locate,*/, ast.Replace, ast.ASCII, ast.Hex,
+		ast.Length, ast.BitLength, ast.Concat, ast.ConcatWS, ast.Locate, ast.Instr, ast.Replace, ast.ASCII, ast.Hex,
 		ast.Reverse, ast.LTrim, ast.RTrim /*ast.Left,*/, ast.Strcmp, ast.Space, ast.Elt, ast.Field,
+		ast.RegexpLike, ast.RegexpInStr, ast.RegexpSubstr, ast.RegexpReplace,
 		InternalFuncFromBinary, InternalFuncToBinary,
 
 		// json functions.
@@ -1008,6 +1011,17 @@ func scalarExprSupportedByTiKV(sf *ScalarFunction) bool {
 	return false
 }
 
+// collationSupportedByTiKV checks the collation of the function is supported by TiKV when the new collation
+// is enabled, so that the string functions and comparisons pushed down get the same results as in TiDB. The
+// collation is encoded in the field type of the function and sent to TiKV.
+func collationSupportedByTiKV(sf *ScalarFunction) bool {
+	if !collate.NewCollationEnabled() {
+		return true
+	}
+	_, coll := sf.CharsetAndCollation()
+	return coll == "" || collate.IsTiKVSupportedCollation(coll)
+}
+
 func isValidTiFlashDecimalType(tp *types.FieldType) bool {
 	if tp.Tp != mysql.TypeNewDecimal {
 		return false
@@ -1048,6 +1062,7 @@ func scalarExprSupportedByFlash(function *ScalarFunction) bool {
 		ast.InetNtoa, ast.InetAton, ast.Inet6Ntoa, ast.Inet6Aton,
 		ast.Coalesce, ast.ASCII, ast.Length, ast.Trim, ast.Position, ast.Format,
 		ast.LTrim, ast.RTrim,
+		ast.RegexpLike, ast.RegexpInStr, ast.RegexpSubstr, ast.RegexpReplace,
 		ast.Hour, ast.Minute, ast.Second, ast.MicroSecond:
 		switch function.Function.PbCode() {
 		case tipb.ScalarFuncSig_InDuration,
@@ -1169,7 +1184,7 @@ func canFuncBePushed(sf *ScalarFunction, storeType kv.StoreType) bool {
 	case kv.TiFlash:
 		ret = scalarExprSupportedByFlash(sf)
 	case kv.TiKV:
-		ret = scalarExprSupportedByTiKV(sf)
+		ret = scalarExprSupportedByTiKV(sf) && collationSupportedByTiKV(sf)
 	case kv.TiDB:
 		ret = scalarExprSupportedByTiDB(sf)
 	case kv.UnSpecified:
@@ -1251,6 +1266,11 @@ func canScalarFuncPushDown(scalarFunc *ScalarFunction, pc PbConverter, storeType
 }
 
 func canExprPushDown(expr Expression, pc PbConverter, storeType kv.StoreType, canEnumPush bool) bool {
+	// The storage layer doesn't support the gb18030 collations, so the strings of gb18030 are
+	// only evaluated in TiDB.
+	if ft := expr.GetType(); ft.EvalType() == types.ETString && ft.Charset == charset.CharsetGB18030 {
+		return false
+	}
 	if storeType == kv.TiFlash {
 		switch expr.GetType().Tp {
 		case mysql.TypeEnum, mysql.TypeBit, mysql.TypeSet, mysql.TypeGeometry, mysql.TypeUnspecified:
@@ -4009,6 +4009,113 @@ func TestCacheRegexpr(t *testing.T) {
 	tk.MustQuery("execute stmt1 using @a").Check(testkit.Rows("R1"))
 }
 
+func TestRegexpFunctions(t *testing.T) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, a varchar(20) collate utf8mb4_bin, b varchar(20) collate utf8mb4_general_ci, c varbinary(20))")
+	tk.MustExec("insert into t values (1, 'Abc abc', 'Abc abc', 'Abc abc'), (2, '你好 世界', '你好 世界', '你好 世界'), (3, null, null, null)")
+
+	// The case sensitivity follows the collation unless the match_type is given.
+	tk.MustQuery("select id from t where regexp_like(a, '^abc')").Check(testkit.Rows())
+	tk.MustQuery("select id from t where regexp_like(b, '^abc')").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where regexp_like(b, '^abc', 'c')").Check(testkit.Rows())
+	tk.MustQuery("select id from t where regexp_like(a, '^abc', 'i')").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where regexp_like(c, '^abc', 'i')").Check(testkit.Rows())
+
+	// The positions are counted in characters, except for the binary strings.
+	tk.MustQuery("select id, regexp_instr(a, '世界'), regexp_instr(c, '世界'), regexp_instr(b, 'abc', 1, 2, 1) from t order by id").
+		Check(testkit.Rows("1 0 0 8", "2 4 8 0", "3 <nil> <nil> <nil>"))
+	tk.MustQuery("select id, regexp_substr(a, '[a-z]+', 2), regexp_substr(b, '[a-z]+', 1, 2), regexp_substr(a, '世.') from t order by id").
+		Check(testkit.Rows("1 bc abc <nil>", "2 <nil> <nil> 世界", "3 <nil> <nil> <nil>"))
+	tk.MustQuery("select id, regexp_replace(a, 'b', 'X'), regexp_replace(b, 'A(b)', '<$1>', 1, 2), regexp_replace(a, '(.)好', '$1$1') from t order by id").
+		Check(testkit.Rows("1 AXc aXc Abc <b>c Abc abc", "2 你好 世界 你好 世界 你你 世界", "3 <nil> <nil> <nil>"))
+
+	// The functions are pushed down to the coprocessor.
+	tk.MustQuery("explain format = 'brief' select id from t where regexp_like(a, 'b+')").Check(testkit.Rows(
+		"Projection 8000.00 root  test.t.id",
+		"└─TableReader 8000.00 root  data:Selection",
+		"  └─Selection 8000.00 cop[tikv]  regexp_like(test.t.a, \"b+\")",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select id from t where regexp_instr(b, 'ABC', 1, 2) > 0 and regexp_substr(c, '^[A-Z]') = 'A'").Check(testkit.Rows("1"))
+
+	require.EqualError(t, tk.QueryToErr("select regexp_instr('abc', 'b', 5)"), "[expression:3686]Index out of bounds in regular expression search.")
+	require.EqualError(t, tk.QueryToErr("select regexp_like('abc', 'b', 'x')"), "[expression:1210]Incorrect arguments to match_type")
+	require.EqualError(t, tk.QueryToErr("select regexp_substr('abc', '(')"), "[expression:1139]Got error 'error parsing regexp: missing closing ): `(`' from regexp")
+}
+
+func TestCollationAwareStringFuncsPushDown(t *testing.T) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, a varchar(20) collate utf8mb4_general_ci, b varchar(20) collate utf8mb4_unicode_ci, c varchar(20) collate utf8mb4_zh_pinyin_tidb_as_cs)")
+	tk.MustExec("insert into t values (1, 'Hello Wörld', 'Hello Wörld', 'Hello'), (2, 'straße', 'straße', 'straße'), (3, 'ÀBC', 'ABC', 'ABC'), (4, null, null, null)")
+
+	queries := []string{
+		"select id from t where a like 'hello w%' order by id",
+		"select id from t where b like '%abc' order by id",
+		"select id from t where a = 'abc' order by id",
+		"select id from t where b > 'HELLO' order by id",
+		"select id from t where instr(a, 'HELLO') > 0 order by id",
+		"select id from t where locate('abc', b) = 1 order by id",
+		"select id, instr(a, 'ss'), instr(b, 'SSE') from t where instr(a, 'e') > 0 order by id",
+	}
+	results := make([][][]interface{}, 0, len(queries))
+	for _, query := range queries {
+		results = append(results, tk.MustQuery(query).Rows())
+	}
+	tk.MustQuery(queries[0]).Check(testkit.Rows("1"))
+	tk.MustQuery(queries[1]).Check(testkit.Rows("3"))
+	tk.MustQuery(queries[4]).Check(testkit.Rows("1"))
+	tk.MustQuery(queries[5]).Check(testkit.Rows("3"))
+
+	// The predicates are pushed down to TiKV with the collations.
+	tk.MustQuery("explain format = 'brief' select id from t where a like 'hello w%' and instr(a, 'WOR') > 0 and locate('abc', b) = 1").Check(testkit.Rows(
+		"Projection 200.00 root  test.t.id",
+		"└─TableReader 200.00 root  data:Selection",
+		"  └─Selection 200.00 cop[tikv]  eq(locate(\"abc\", test.t.b), 1), gt(instr(test.t.a, \"WOR\"), 0), like(test.t.a, \"hello w%\", 92)",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo",
+	))
+	// The collation unsupported by TiKV is evaluated in TiDB.
+	tk.MustQuery("explain format = 'brief' select id from t where instr(c, 'AB') > 0").Check(testkit.Rows(
+		"Projection 8000.00 root  test.t.id",
+		"└─Selection 8000.00 root  gt(instr(test.t.c, \"AB\"), 0)",
+		"  └─TableReader 10000.00 root  data:TableFullScan",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo",
+	))
+
+	// The results are same as the ones evaluated in TiDB.
+	tk.MustExec("insert into mysql.expr_pushdown_blacklist values('like', 'tikv', 'for test'), ('instr', 'tikv', 'for test'), " +
+		"('locate', 'tikv', 'for test'), ('eq', 'tikv', 'for test'), ('gt', 'tikv', 'for test')")
+	tk.MustExec("admin reload expr_pushdown_blacklist")
+	defer func() {
+		tk.MustExec("delete from mysql.expr_pushdown_blacklist where reason = 'for test'")
+		tk.MustExec("admin reload expr_pushdown_blacklist")
+	}()
+	tk.MustQuery("explain format = 'brief' select id from t where instr(a, 'WOR') > 0").Check(testkit.Rows(
+		"Projection 8000.00 root  test.t.id",
+		"└─Selection 8000.00 root  gt(instr(test.t.a, \"WOR\"), 0)",
+		"  └─TableReader 10000.00 root  data:TableFullScan",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo",
+	))
+	for i, query := range queries {
+		tk.MustQuery(query).Check(results[i])
+	}
+}
+
 func TestCacheRefineArgs(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -2218,6 +2218,115 @@ func TestDateBuiltin(t *testing.T) {
 	require.True(t, terror.ErrorEqual(err, types.ErrWrongValue.GenWithStackByArgs(types.DateStr, "abcdefg")), "err: %v", err)
 }
 
+func TestTimeBucket(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (ts datetime(3), ts2 timestamp, v int)")
+	tk.MustExec("insert into t values ('2022-03-04 10:01:02.500', '2022-03-04 10:01:02', 1), ('2022-03-04 10:04:59.999', '2022-03-04 10:04:59', 2), " +
+		"('2022-03-04 10:05:00.000', '2022-03-04 10:05:00', 3), ('2022-03-05 00:00:00.000', '2022-03-05 00:00:00', 4), (null, null, 5)")
+
+	tk.MustQuery("select time_bucket(interval 5 minute, ts) b, sum(v) from t group by b order by b").Check(testkit.Rows(
+		"<nil> 5", "2022-03-04 10:00:00.000 3", "2022-03-04 10:05:00.000 3", "2022-03-05 00:00:00.000 4"))
+	tk.MustQuery("select time_bucket(interval 1 day, ts2, '2022-03-01 06:00:00') b, count(*) from t group by b order by b").Check(testkit.Rows(
+		"<nil> 1", "2022-03-04 06:00:00 4"))
+	tk.MustQuery("select time_bucket(interval 2.5 second, ts), time_bucket(interval '1:30' minute_second, ts) from t where v = 1").Check(testkit.Rows(
+		"2022-03-04 10:01:02.500000 2022-03-04 10:00:00.000"))
+	tk.MustQuery("select time_bucket(interval 1 quarter, ts) from t where v = 2").Check(testkit.Rows("2022-01-01 00:00:00.000"))
+	tk.MustQuery("select time_bucket(interval 1 week, date '2022-03-06'), time_bucket(interval 2 month, '2022-03-31 10:00:00', '2021-12-31')").Check(testkit.Rows(
+		"2022-02-28 00:00:00 2022-02-28 00:00:00"))
+
+	// The invalid intervals and timestamps produce NULL with warnings.
+	tk.MustQuery("select time_bucket(interval 0 hour, ts) from t where v = 1").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1292 Incorrect INTERVAL value: '0'"))
+	tk.MustQuery("select time_bucket(interval -1 hour, '2022-03-04')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("select time_bucket(interval 1 hour, '2022-13-04')").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1292 Incorrect datetime value: '2022-13-4'"))
+	tk.MustGetErrCode("select time_bucket(interval 1 hour)", mysql.ErrParse)
+	tk.MustGetErrCode("select time_bucket(1, ts) from t", mysql.ErrParse)
+}
+
+func TestMaskFunctions(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (name varchar(20), pan varchar(20), ssn char(11), b varbinary(20))")
+	tk.MustExec("insert into t values ('Alice', '9130837077312461', '909-63-6922', 'abcdef'), ('Bo', '913083707731', '63-6922', null)")
+
+	tk.MustQuery("select mask_inner(name, 1, 1), mask_outer(name, 1, 1, '*'), mask_pan(pan), mask_pan_relaxed(pan), mask_ssn(ssn) from t order by name").Check(testkit.Rows(
+		"AXXXe *lic* XXXXXXXXXXXX2461 913083XXXXXX2461 XXX-XX-6922",
+		"Bo ** 913083707731 913083707731 63-6922"))
+	tk.MustQuery("select mask_inner(b, 2, 2, '#'), hex(mask_outer(b, 1, 0)) from t order by name").Check(testkit.Rows(
+		"ab##ef 586263646566", "<nil> <nil>"))
+	tk.MustQuery("select mask_inner('测试数据', 1, 1, '文'), mask_ssn(null), mask_outer('abc', null, 1)").Check(testkit.Rows("测文文据 <nil> <nil>"))
+	tk.MustQuery("select count(*) from t where mask_ssn(ssn) = 'XXX-XX-6922'").Check(testkit.Rows("1"))
+
+	err := tk.QueryToErr("select mask_inner(name, -1, 1) from t")
+	require.EqualError(t, err, "[expression:1210]Incorrect arguments to mask_inner")
+	err = tk.QueryToErr("select mask_pan(pan, 'ab') from t")
+	require.EqualError(t, err, "[expression:1210]Incorrect arguments to mask_pan")
+	tk.MustGetErrCode("select mask_pan()", mysql.ErrWrongParamcountToNativeFct)
+
+	// The masking functions are evaluated in TiDB.
+	tk.MustQuery("explain format = 'brief' select * from t where mask_ssn(ssn) = 'XXX-XX-6922'").Check(testkit.Rows(
+		"Selection 8000.00 root  eq(mask_ssn(test.t.ssn), \"XXX-XX-6922\")",
+		"└─TableReader 10000.00 root  data:TableFullScan",
+		"  └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+}
+
+func TestVectorType(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t (id int primary key, v vector(3))")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `v` vector(3) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("insert into t values (1, '[1, 2, 3]'), (2, ' [ 1.5,-2,0.25 ] '), (3, '[0,0,0]'), (4, null)")
+	tk.MustQuery("select id, v from t order by id").Check(testkit.Rows("1 [1,2,3]", "2 [1.5,-2,0.25]", "3 [0,0,0]", "4 <nil>"))
+	tk.MustQuery("select id from t where v = '[1,2,3]'").Check(testkit.Rows("1"))
+
+	err := tk.ExecToErr("insert into t values (5, '[1,2]')")
+	require.EqualError(t, err, "[types:8251]Vector dimension mismatch, expect 3, got 2")
+	err = tk.ExecToErr("insert into t values (5, '1,2,3')")
+	require.EqualError(t, err, "[table:1366]Incorrect vector value: '1,2,3' for column 'v' at row 1")
+	tk.MustGetErrCode("create table t1 (v vector(3), key(v))", errno.ErrVectorUsedAsKey)
+	tk.MustGetErrCode("alter table t add index idx(v)", errno.ErrVectorUsedAsKey)
+	tk.MustGetErrCode("create table t1 (v vector(16384))", errno.ErrVectorDimensionOutOfRange)
+	tk.MustGetErrCode("create table t1 (v vector default '[1]')", mysql.ErrBlobCantHaveDefault)
+
+	// A VECTOR column without a dimension accepts vectors of any dimension.
+	tk.MustExec("create table t1 (v vector)")
+	tk.MustExec("insert into t1 values ('[1]'), ('[1,2,3,4]')")
+	tk.MustQuery("select v from t1 order by v").Check(testkit.Rows("[1,2,3,4]", "[1]"))
+
+	tk.MustQuery("select id, vec_l2_distance(v, '[1,2,3]'), round(vec_cosine_distance(v, '[2,4,6]'), 6) from t order by id").Check(testkit.Rows(
+		"1 0 0", "2 4.879805323985784 1.186154", "3 3.7416573867739413 <nil>", "4 <nil> <nil>"))
+	err = tk.QueryToErr("select vec_l2_distance(v, '[1,2]') from t")
+	require.EqualError(t, err, "[types:8251]Vector dimension mismatch, expect 3, got 2")
+
+	// The nearest neighbors are found by TopN in TiDB, because the storage doesn't know vectors.
+	tk.MustQuery("explain format = 'brief' select id from t order by vec_l2_distance(v, '[1,2,3]') limit 2").Check(testkit.Rows(
+		"Projection 2.00 root  test.t.id",
+		"└─Projection 2.00 root  test.t.id, test.t.v",
+		"  └─TopN 2.00 root  Column#4, offset:0, count:2",
+		"    └─Projection 10000.00 root  test.t.id, test.t.v, vec_l2_distance(test.t.v, [1,2,3])->Column#4",
+		"      └─TableReader 10000.00 root  data:TableFullScan",
+		"        └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("select id from t where v is not null order by vec_l2_distance(v, '[1,2,3]') limit 2").Check(testkit.Rows("1", "3"))
+}
+
 func TestJSONBuiltin(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
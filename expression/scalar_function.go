@@ -189,6 +189,12 @@ func newFunctionImpl(ctx sessionctx.Context, fold int, funcName string, retType
 		return BuildToBinaryFunction(ctx, args[0]), nil
 	}
 	fc, ok := funcs[funcName]
+	if !ok {
+		var udf *UserDefinedFunction
+		if udf, ok = getUserDefinedFunction(ctx, funcName); ok {
+			fc = newUserDefinedFunctionClass(udf)
+		}
+	}
 	if !ok {
 		db := ctx.GetSessionVars().CurrentDB
 		if db == "" {
@@ -0,0 +1,256 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+const (
+	// UDFLanguageWasm is the language of the user-defined functions compiled to WebAssembly.
+	UDFLanguageWasm = "WASM"
+
+	// udfMemoryLimitPages limits the linear memory of a module instance to 16MiB.
+	udfMemoryLimitPages = 256
+	// udfMaxIdleInstances is the max number of the idle module instances kept for reusing.
+	udfMaxIdleInstances = 8
+)
+
+// UDFExecutionTimeout is the max duration of a single call of the user-defined function, the vectorized
+// evaluation calls the function for the whole chunk within it. It's a variable for testing.
+var UDFExecutionTimeout = 5 * time.Second
+
+// UserDefinedFunction is a scalar function created by CREATE FUNCTION. The function body is a WebAssembly
+// module which exports a function with the same name. The module runs in a sandbox: it can't import
+// anything from the host, its memory is limited and the calls are interrupted when they time out.
+// The function returns NULL when any argument is NULL.
+type UserDefinedFunction struct {
+	Name       string
+	ParamTypes []*types.FieldType
+	RetType    *types.FieldType
+
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+
+	mu struct {
+		sync.Mutex
+		idle   []*udfInstance
+		closed bool
+	}
+}
+
+// udfInstance is an instance of the module, it can't be used by multiple goroutines at the same time.
+type udfInstance struct {
+	mod api.Module
+	fn  api.Function
+}
+
+// wasmValueType returns the WebAssembly value type of the SQL type.
+func wasmValueType(tp *types.FieldType) (api.ValueType, bool) {
+	switch tp.EvalType() {
+	case types.ETInt:
+		return api.ValueTypeI64, true
+	case types.ETReal:
+		return api.ValueTypeF64, true
+	}
+	return 0, false
+}
+
+// NewWasmUserDefinedFunction compiles the WebAssembly module and checks the exported function matches the
+// declared parameter and return types. The integer types are passed as i64 and the floating point types
+// are passed as f64, the other types are not supported yet.
+func NewWasmUserDefinedFunction(name string, paramTypes []*types.FieldType, retType *types.FieldType, body []byte) (*UserDefinedFunction, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return ErrInvalidUserDefinedFunction.GenWithStackByArgs(name, fmt.Sprintf(format, args...))
+	}
+	expected := make([]api.ValueType, 0, len(paramTypes))
+	for i, tp := range paramTypes {
+		vt, ok := wasmValueType(tp)
+		if !ok {
+			return nil, invalid("type %s of parameter %d is not supported", types.TypeStr(tp.Tp), i+1)
+		}
+		expected = append(expected, vt)
+	}
+	retVT, ok := wasmValueType(retType)
+	if !ok {
+		return nil, invalid("return type %s is not supported", types.TypeStr(retType.Tp))
+	}
+
+	ctx := context.Background()
+	r := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfigInterpreter().
+		WithMemoryLimitPages(udfMemoryLimitPages).
+		WithCloseOnContextDone(true))
+	fail := func(err error) (*UserDefinedFunction, error) {
+		_ = r.Close(ctx)
+		return nil, err
+	}
+	compiled, err := r.CompileModule(ctx, body)
+	if err != nil {
+		return fail(invalid("%s", err.Error()))
+	}
+	if len(compiled.ImportedFunctions()) > 0 || len(compiled.ImportedMemories()) > 0 {
+		return fail(invalid("the module must not import anything"))
+	}
+	def, ok := compiled.ExportedFunctions()[name]
+	if !ok {
+		return fail(invalid("the module doesn't export the function '%s'", name))
+	}
+	if !equalWasmValueTypes(def.ParamTypes(), expected) || !equalWasmValueTypes(def.ResultTypes(), []api.ValueType{retVT}) {
+		return fail(invalid("the signature of the exported function doesn't match the declared types"))
+	}
+	return &UserDefinedFunction{
+		Name:       name,
+		ParamTypes: paramTypes,
+		RetType:    retType,
+		runtime:    r,
+		compiled:   compiled,
+	}, nil
+}
+
+func equalWasmValueTypes(a, b []api.ValueType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Close releases the runtime of the function. The calls in progress fail after it's closed.
+func (u *UserDefinedFunction) Close() {
+	u.mu.Lock()
+	u.mu.closed = true
+	u.mu.idle = nil
+	u.mu.Unlock()
+	_ = u.runtime.Close(context.Background())
+}
+
+func (u *UserDefinedFunction) getInstance(ctx context.Context) (*udfInstance, error) {
+	u.mu.Lock()
+	if u.mu.closed {
+		u.mu.Unlock()
+		return nil, ErrUserDefinedFunctionFailed.GenWithStackByArgs(u.Name, "the function has been dropped")
+	}
+	if n := len(u.mu.idle); n > 0 {
+		inst := u.mu.idle[n-1]
+		u.mu.idle = u.mu.idle[:n-1]
+		u.mu.Unlock()
+		return inst, nil
+	}
+	u.mu.Unlock()
+	// The instances are anonymous so that they don't conflict with each other in the runtime.
+	mod, err := u.runtime.InstantiateModule(ctx, u.compiled, wazero.NewModuleConfig().WithName(""))
+	if err != nil {
+		return nil, ErrUserDefinedFunctionFailed.GenWithStackByArgs(u.Name, err.Error())
+	}
+	return &udfInstance{mod: mod, fn: mod.ExportedFunction(u.Name)}, nil
+}
+
+// putInstance puts the instance back to the pool. The instance is discarded if the call failed, because
+// its state may be broken, e.g. it's closed when the call times out.
+func (u *UserDefinedFunction) putInstance(inst *udfInstance, failed bool) {
+	u.mu.Lock()
+	if !failed && !u.mu.closed && len(u.mu.idle) < udfMaxIdleInstances {
+		u.mu.idle = append(u.mu.idle, inst)
+		u.mu.Unlock()
+		return
+	}
+	u.mu.Unlock()
+	_ = inst.mod.Close(context.Background())
+}
+
+// udfCaller calls the function with the same instance until it's closed.
+type udfCaller struct {
+	udf    *UserDefinedFunction
+	inst   *udfInstance
+	ctx    context.Context
+	cancel context.CancelFunc
+	failed bool
+}
+
+func (u *UserDefinedFunction) newCaller() (*udfCaller, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), UDFExecutionTimeout)
+	inst, err := u.getInstance(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &udfCaller{udf: u, inst: inst, ctx: ctx, cancel: cancel}, nil
+}
+
+// call calls the function with the encoded arguments and returns the encoded result.
+func (c *udfCaller) call(params []uint64) (uint64, error) {
+	results, err := c.inst.fn.Call(c.ctx, params...)
+	if err != nil {
+		c.failed = true
+		if c.ctx.Err() != nil {
+			return 0, ErrUserDefinedFunctionFailed.GenWithStackByArgs(c.udf.Name, "execution timed out")
+		}
+		return 0, ErrUserDefinedFunctionFailed.GenWithStackByArgs(c.udf.Name, err.Error())
+	}
+	return results[0], nil
+}
+
+func (c *udfCaller) close() {
+	c.udf.putInstance(c.inst, c.failed)
+	c.cancel()
+}
+
+// UserDefinedFunctions provides the user-defined functions.
+type UserDefinedFunctions interface {
+	// GetUserDefinedFunction gets the user-defined function by the lower case name.
+	GetUserDefinedFunction(name string) (*UserDefinedFunction, bool)
+}
+
+type udfKeyType int
+
+func (k udfKeyType) String() string {
+	return "user-defined-functions-key"
+}
+
+const udfKey udfKeyType = 0
+
+// BindUserDefinedFunctions binds UserDefinedFunctions to context.
+func BindUserDefinedFunctions(ctx sessionctx.Context, udfs UserDefinedFunctions) {
+	ctx.SetValue(udfKey, udfs)
+}
+
+// GetUserDefinedFunctions gets UserDefinedFunctions from context.
+func GetUserDefinedFunctions(ctx sessionctx.Context) UserDefinedFunctions {
+	if v, ok := ctx.Value(udfKey).(UserDefinedFunctions); ok {
+		return v
+	}
+	return nil
+}
+
+// getUserDefinedFunction looks up the user-defined function bound to the context.
+func getUserDefinedFunction(ctx sessionctx.Context, name string) (*UserDefinedFunction, bool) {
+	udfs := GetUserDefinedFunctions(ctx)
+	if udfs == nil {
+		return nil, false
+	}
+	return udfs.GetUserDefinedFunction(name)
+}
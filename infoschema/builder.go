@@ -57,6 +57,8 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 		return nil, b.applyCreateSchema(m, diff)
 	case model.ActionDropSchema:
 		return b.applyDropSchema(diff.SchemaID), nil
+	case model.ActionRecoverSchema:
+		return b.applyRecoverSchema(m, diff)
 	case model.ActionModifySchemaCharsetAndCollate:
 		return nil, b.applyModifySchemaCharsetAndCollate(m, diff)
 	case model.ActionModifySchemaDefaultPlacement:
@@ -67,6 +69,11 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 		return b.applyDropPolicy(diff.SchemaID), nil
 	case model.ActionAlterPlacementPolicy:
 		return b.applyAlterPolicy(m, diff)
+	case model.ActionCreateResourceGroup, model.ActionAlterResourceGroup:
+		return nil, b.applyCreateOrAlterResourceGroup(m, diff)
+	case model.ActionDropResourceGroup:
+		b.applyDropResourceGroup(diff.SchemaID)
+		return nil, nil
 	}
 	roDBInfo, ok := b.is.SchemaByID(diff.SchemaID)
 	if !ok {
@@ -279,6 +286,23 @@ func (b *Builder) applyAlterPolicy(m *meta.Meta, diff *model.SchemaDiff) ([]int6
 	return []int64{}, nil
 }
 
+func (b *Builder) applyCreateOrAlterResourceGroup(m *meta.Meta, diff *model.SchemaDiff) error {
+	group, err := m.GetResourceGroup(diff.SchemaID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	b.is.setResourceGroup(group)
+	return nil
+}
+
+func (b *Builder) applyDropResourceGroup(groupID int64) {
+	group, ok := b.is.resourceGroupByID(groupID)
+	if !ok {
+		return
+	}
+	b.is.deleteResourceGroup(group.Name.L)
+}
+
 func (b *Builder) applyCreateSchema(m *meta.Meta, diff *model.SchemaDiff) error {
 	di, err := m.GetDatabase(diff.SchemaID)
 	if err != nil {
@@ -367,6 +391,52 @@ func (b *Builder) applyDropSchema(schemaID int64) []int64 {
 	return tableIDs
 }
 
+func (b *Builder) applyRecoverSchema(m *meta.Meta, diff *model.SchemaDiff) ([]int64, error) {
+	if di, ok := b.is.SchemaByID(diff.SchemaID); ok {
+		return nil, ErrDatabaseExists.GenWithStackByArgs(
+			fmt.Sprintf("(Schema ID %d)", di.ID),
+		)
+	}
+	di, err := m.GetDatabase(diff.SchemaID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if di == nil {
+		// When we apply an old schema diff, the database may has been dropped already, so we need to fall back to
+		// full load.
+		return nil, ErrDatabaseNotExists.GenWithStackByArgs(
+			fmt.Sprintf("(Schema ID %d)", diff.SchemaID),
+		)
+	}
+	di.Tables, err = m.ListTables(di.ID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Copy the sortedTables that contain the tables we are going to recover.
+	tableIDs := make([]int64, 0, len(di.Tables))
+	bucketIdxMap := make(map[int]struct{}, len(di.Tables))
+	for _, tbl := range di.Tables {
+		bucketIdxMap[tableBucketIdx(tbl.ID)] = struct{}{}
+		tableIDs = appendAffectedIDs(tableIDs, tbl)
+	}
+	for bucketIdx := range bucketIdxMap {
+		b.copySortedTablesBucket(bucketIdx)
+	}
+	if err := b.createSchemaTablesForDB(di, b.tableFromMeta); err != nil {
+		return nil, errors.Trace(err)
+	}
+	for bucketIdx := range bucketIdxMap {
+		sort.Sort(b.is.sortedTablesBuckets[bucketIdx])
+	}
+	for _, id := range tableIDs {
+		if err := b.applyPlacementUpdate(placement.GroupID(id)); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return tableIDs, nil
+}
+
 func (b *Builder) copySortedTablesBucket(bucketIdx int) {
 	oldSortedTables := b.is.sortedTablesBuckets[bucketIdx]
 	newSortedTables := make(sortedTables, len(oldSortedTables))
@@ -552,6 +622,7 @@ func (b *Builder) InitWithOldInfoSchema(oldSchema InfoSchema) *Builder {
 	b.copySchemasMap(oldIS)
 	b.copyBundlesMap(oldIS)
 	b.copyPoliciesMap(oldIS)
+	b.copyResourceGroupsMap(oldIS)
 
 	copy(b.is.sortedTablesBuckets, oldIS.sortedTablesBuckets)
 	return b
@@ -577,6 +648,13 @@ func (b *Builder) copyPoliciesMap(oldIS *infoSchema) {
 	}
 }
 
+func (b *Builder) copyResourceGroupsMap(oldIS *infoSchema) {
+	is := b.is
+	for _, v := range oldIS.AllResourceGroups() {
+		is.resourceGroupMap[v.Name.L] = v
+	}
+}
+
 // copySchemaTables creates a new schemaTables instance when a table in the database has changed.
 // It also does modifications on the new one because old schemaTables must be read-only.
 // Note: please make sure the dbName is in lowercase.
@@ -593,8 +671,8 @@ func (b *Builder) copySchemaTables(dbName string) *model.DBInfo {
 	return newSchemaTables.dbInfo
 }
 
-// InitWithDBInfos initializes an empty new InfoSchema with a slice of DBInfo, all placement rules, and schema version.
-func (b *Builder) InitWithDBInfos(dbInfos []*model.DBInfo, bundles []*placement.Bundle, policies []*model.PolicyInfo, schemaVersion int64) (*Builder, error) {
+// InitWithDBInfos initializes an empty new InfoSchema with a slice of DBInfo, all placement rules, resource groups and schema version.
+func (b *Builder) InitWithDBInfos(dbInfos []*model.DBInfo, bundles []*placement.Bundle, policies []*model.PolicyInfo, resourceGroups []*model.ResourceGroupInfo, schemaVersion int64) (*Builder, error) {
 	info := b.is
 	info.schemaMetaVersion = schemaVersion
 	for _, bundle := range bundles {
@@ -604,6 +682,9 @@ func (b *Builder) InitWithDBInfos(dbInfos []*model.DBInfo, bundles []*placement.
 	for _, policy := range policies {
 		info.setPolicy(policy)
 	}
+	for _, group := range resourceGroups {
+		info.setResourceGroup(group)
+	}
 
 	for _, di := range dbInfos {
 		err := b.createSchemaTablesForDB(di, b.tableFromMeta)
@@ -689,6 +770,7 @@ func NewBuilder(store kv.Storage, renewCh chan func(), factory func() (pools.Res
 		is: &infoSchema{
 			schemaMap:           map[string]*schemaTables{},
 			policyMap:           map[string]*model.PolicyInfo{},
+			resourceGroupMap:    map[string]*model.ResourceGroupInfo{},
 			ruleBundleMap:       map[string]*placement.Bundle{},
 			sortedTablesBuckets: make([]sortedTables, bucketCount),
 		},
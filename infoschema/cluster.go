@@ -44,6 +44,8 @@ const (
 	ClusterTableTiDBTrx = "CLUSTER_TIDB_TRX"
 	// ClusterTableDeadlocks is the string constant of cluster dead lock table.
 	ClusterTableDeadlocks = "CLUSTER_DEADLOCKS"
+	// ClusterTableTrxSummary is the string constant of cluster transaction summary table.
+	ClusterTableTrxSummary = "CLUSTER_TRX_SUMMARY"
 )
 
 // memTableToClusterTables means add memory table to cluster table.
@@ -55,6 +57,7 @@ var memTableToClusterTables = map[string]string{
 	TableStatementsSummaryEvicted: ClusterTableStatementsSummaryEvicted,
 	TableTiDBTrx:                  ClusterTableTiDBTrx,
 	TableDeadlocks:                ClusterTableDeadlocks,
+	TableTrxSummary:               ClusterTableTrxSummary,
 }
 
 func init() {
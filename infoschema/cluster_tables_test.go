@@ -35,6 +35,7 @@ import (
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/server"
+	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/store/helper"
 	"github.com/pingcap/tidb/store/mockstore/mockstorage"
 	"github.com/pingcap/tidb/testkit"
@@ -162,8 +163,8 @@ func SubTestTestDataLockWaits(s *clusterTablesSuite) func(*testing.T) {
 		_, digest1 := parser.NormalizeDigest("select * from test_data_lock_waits for update")
 		_, digest2 := parser.NormalizeDigest("update test_data_lock_waits set f1=1 where id=2")
 		s.store.(mockstorage.MockLockWaitSetter).SetMockLockWaits([]*deadlock.WaitForEntry{
-			{Txn: 1, WaitForTxn: 2, Key: []byte("key1"), ResourceGroupTag: resourcegrouptag.EncodeResourceGroupTag(digest1, nil, tipb.ResourceGroupTagLabel_ResourceGroupTagLabelUnknown)},
-			{Txn: 3, WaitForTxn: 4, Key: []byte("key2"), ResourceGroupTag: resourcegrouptag.EncodeResourceGroupTag(digest2, nil, tipb.ResourceGroupTagLabel_ResourceGroupTagLabelUnknown)},
+			{Txn: 1, WaitForTxn: 2, Key: []byte("key1"), ResourceGroupTag: resourcegrouptag.EncodeResourceGroupTag(digest1, nil, tipb.ResourceGroupTagLabel_ResourceGroupTagLabelUnknown), WaitTime: 100},
+			{Txn: 3, WaitForTxn: 4, Key: []byte("key2"), ResourceGroupTag: resourcegrouptag.EncodeResourceGroupTag(digest2, nil, tipb.ResourceGroupTagLabel_ResourceGroupTagLabelUnknown), WaitTime: 200},
 			// Invalid digests
 			{Txn: 5, WaitForTxn: 6, Key: []byte("key3"), ResourceGroupTag: resourcegrouptag.EncodeResourceGroupTag(nil, nil, tipb.ResourceGroupTagLabel_ResourceGroupTagLabelUnknown)},
 			{Txn: 7, WaitForTxn: 8, Key: []byte("key4"), ResourceGroupTag: []byte("asdfghjkl")},
@@ -176,10 +177,22 @@ func SubTestTestDataLockWaits(s *clusterTablesSuite) func(*testing.T) {
 		tk.MustExec("select * from test_data_lock_waits for update")
 
 		tk.MustQuery("select * from information_schema.DATA_LOCK_WAITS").Check(testkit.Rows(
-			"6B657931 <nil> 1 2 "+digest1.String()+" select * from `test_data_lock_waits` for update",
-			"6B657932 <nil> 3 4 "+digest2.String()+" <nil>",
-			"6B657933 <nil> 5 6 <nil> <nil>",
-			"6B657934 <nil> 7 8 <nil> <nil>"))
+			"6B657931 <nil> 1 2 "+digest1.String()+" select * from `test_data_lock_waits` for update 100 <nil> <nil>",
+			"6B657932 <nil> 3 4 "+digest2.String()+" <nil> 200 <nil> <nil>",
+			"6B657933 <nil> 5 6 <nil> <nil> 0 <nil> <nil>",
+			"6B657934 <nil> 7 8 <nil> <nil> 0 <nil> <nil>"))
+
+		// The digests of the holding transactions are available if they are running on the current instance.
+		sm := &mockSessionManager{nil, []*txninfo.TxnInfo{
+			{StartTS: 2, CurrentSQLDigest: digest2.String()},
+			{StartTS: 4, AllSQLDigests: []string{digest2.String(), digest1.String()}},
+		}}
+		tk.Session().SetSessionManager(sm)
+		tk.MustQuery("select trx_id, current_holding_trx_id, current_holding_sql_digest, current_holding_sql_digest_text from information_schema.DATA_LOCK_WAITS").Check(testkit.Rows(
+			"1 2 "+digest2.String()+" <nil>",
+			"3 4 "+digest1.String()+" select * from `test_data_lock_waits` for update",
+			"5 6 <nil> <nil>",
+			"7 8 <nil> <nil>"))
 	}
 }
 
@@ -224,7 +237,7 @@ func SubTestSelectClusterTable(s *clusterTablesSuite) func(*testing.T) {
 			tk.MustQuery("select count(*) from `CLUSTER_SLOW_QUERY`").Check(testkit.Rows("2"))
 			tk.MustQuery("select time from `CLUSTER_SLOW_QUERY` where time='2019-02-12 19:33:56.571953'").Check(testutil.RowsWithSep("|", "2019-02-12 19:33:56.571953"))
 			tk.MustQuery("select count(*) from `CLUSTER_PROCESSLIST`").Check(testkit.Rows("1"))
-			tk.MustQuery("select * from `CLUSTER_PROCESSLIST`").Check(testkit.Rows(fmt.Sprintf(":10080 1 root 127.0.0.1 <nil> Query 9223372036 %s <nil>  0 0 ", "")))
+			tk.MustQuery("select * from `CLUSTER_PROCESSLIST`").Check(testkit.Rows(fmt.Sprintf(":10080 1 root 127.0.0.1 <nil> Query 9223372036 %s <nil>  0 0  ", "")))
 			tk.MustQuery("select query_time, conn_id from `CLUSTER_SLOW_QUERY` order by time limit 1").Check(testkit.Rows("4.895492 6"))
 			tk.MustQuery("select count(*) from `CLUSTER_SLOW_QUERY` group by digest").Check(testkit.Rows("1", "1"))
 			tk.MustQuery("select digest, count(*) from `CLUSTER_SLOW_QUERY` group by digest order by digest").Check(testkit.Rows("124acb3a0bec903176baca5f9da00b4e7512a41c93b417923f26502edeb324cc 1", "42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772 1"))
@@ -277,7 +290,7 @@ select * from t3;
 		tk.MustQuery("select count(*) from `CLUSTER_SLOW_QUERY`").Check(testkit.Rows("4"))
 		tk.MustQuery("select count(*) from `SLOW_QUERY`").Check(testkit.Rows("4"))
 		tk.MustQuery("select count(*) from `CLUSTER_PROCESSLIST`").Check(testkit.Rows("1"))
-		tk.MustQuery("select * from `CLUSTER_PROCESSLIST`").Check(testkit.Rows(fmt.Sprintf(":10080 1 root 127.0.0.1 <nil> Query 9223372036 %s <nil>  0 0 ", "")))
+		tk.MustQuery("select * from `CLUSTER_PROCESSLIST`").Check(testkit.Rows(fmt.Sprintf(":10080 1 root 127.0.0.1 <nil> Query 9223372036 %s <nil>  0 0  ", "")))
 		tk.MustExec("create user user1")
 		tk.MustExec("create user user2")
 		user1 := testkit.NewTestKit(t, s.store)
@@ -178,6 +178,34 @@ const (
 	TableAttributes = "ATTRIBUTES"
 	// TablePlacementRules is the string constant of placement rules table.
 	TablePlacementRules = "PLACEMENT_RULES"
+	// TableTiDBSpillFiles is the string constant of the live spill files table.
+	TableTiDBSpillFiles = "TIDB_SPILL_FILES"
+	// TableTrxSummary is the string constant of the transaction summary table.
+	TableTrxSummary = "TRX_SUMMARY"
+	// TableSessionConnectAttrs is the string constant of the connection attributes table.
+	TableSessionConnectAttrs = "SESSION_CONNECT_ATTRS"
+	// TableTiKVEncryptionStatus is the string constant of the encryption at rest status table of the stores.
+	TableTiKVEncryptionStatus = "TIKV_ENCRYPTION_STATUS"
+	// TableInspectionReport is the string constant of inspection report table, which is the inspection result
+	// with the suggestions.
+	TableInspectionReport = "INSPECTION_REPORT"
+	// TableTiDBIndexUsage is the string constant of the index usage table, which shows the usage of all the indexes.
+	TableTiDBIndexUsage = "TIDB_INDEX_USAGE"
+	// TableMemoryUsage is the string constant of the memory usage table, which shows the memory usage of the
+	// tidb-server by category.
+	TableMemoryUsage = "MEMORY_USAGE"
+	// TableMemoryUsageOpsHistory is the string constant of the memory usage ops history table, which shows the
+	// operations taken when the memory usage of the tidb-server is too high.
+	TableMemoryUsageOpsHistory = "MEMORY_USAGE_OPS_HISTORY"
+	// TablePlanReplayerCaptures is the string constant of the plan replayer captures table, which lists the statements
+	// captured into the plan replayer zips automatically.
+	TablePlanReplayerCaptures = "PLAN_REPLAYER_CAPTURES"
+	// TableClusterConfigDiff is the string constant of the cluster config diff table, which lists the config items
+	// diverging from the majority of the same component or from the defaults.
+	TableClusterConfigDiff = "CLUSTER_CONFIG_DIFF"
+	// TableTiDBAutoRandomIDSpace is the string constant of the auto_random ID space table, which shows how many
+	// IDs can still be allocated for the auto_random columns.
+	TableTiDBAutoRandomIDSpace = "TIDB_AUTO_RANDOM_ID_SPACE"
 )
 
 const (
@@ -193,6 +221,12 @@ const (
 	DataLockWaitsColumnSQLDigest = "SQL_DIGEST"
 	// DataLockWaitsColumnSQLDigestText is the name of the SQL_DIGEST_TEXT column of the DATA_LOCK_WAITS table.
 	DataLockWaitsColumnSQLDigestText = "SQL_DIGEST_TEXT"
+	// DataLockWaitsColumnWaitTime is the name of the WAIT_TIME column of the DATA_LOCK_WAITS table.
+	DataLockWaitsColumnWaitTime = "WAIT_TIME"
+	// DataLockWaitsColumnCurrentHoldingSQLDigest is the name of the CURRENT_HOLDING_SQL_DIGEST column of the DATA_LOCK_WAITS table.
+	DataLockWaitsColumnCurrentHoldingSQLDigest = "CURRENT_HOLDING_SQL_DIGEST"
+	// DataLockWaitsColumnCurrentHoldingSQLDigestText is the name of the CURRENT_HOLDING_SQL_DIGEST_TEXT column of the DATA_LOCK_WAITS table.
+	DataLockWaitsColumnCurrentHoldingSQLDigestText = "CURRENT_HOLDING_SQL_DIGEST_TEXT"
 )
 
 var tableIDMap = map[string]int64{
@@ -276,6 +310,18 @@ var tableIDMap = map[string]int64{
 	TableAttributes:                      autoid.InformationSchemaDBID + 77,
 	TableTiDBHotRegionsHistory:           autoid.InformationSchemaDBID + 78,
 	TablePlacementRules:                  autoid.InformationSchemaDBID + 79,
+	TableTiDBSpillFiles:                  autoid.InformationSchemaDBID + 80,
+	TableTrxSummary:                      autoid.InformationSchemaDBID + 81,
+	ClusterTableTrxSummary:               autoid.InformationSchemaDBID + 82,
+	TableSessionConnectAttrs:             autoid.InformationSchemaDBID + 83,
+	TableTiKVEncryptionStatus:            autoid.InformationSchemaDBID + 84,
+	TableInspectionReport:                autoid.InformationSchemaDBID + 85,
+	TableTiDBIndexUsage:                  autoid.InformationSchemaDBID + 86,
+	TableMemoryUsage:                     autoid.InformationSchemaDBID + 87,
+	TableMemoryUsageOpsHistory:           autoid.InformationSchemaDBID + 88,
+	TablePlanReplayerCaptures:            autoid.InformationSchemaDBID + 89,
+	TableClusterConfigDiff:               autoid.InformationSchemaDBID + 90,
+	TableTiDBAutoRandomIDSpace:           autoid.InformationSchemaDBID + 91,
 }
 
 type columnInfo struct {
@@ -787,6 +833,7 @@ var tableProcesslistCols = []columnInfo{
 	{name: "MEM", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
 	{name: "DISK", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
 	{name: "TxnStart", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, deflt: ""},
+	{name: "PROGRAM_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, deflt: ""},
 }
 
 var tableTiDBIndexesCols = []columnInfo{
@@ -944,6 +991,13 @@ var tableAnalyzeStatusCols = []columnInfo{
 	{name: "START_TIME", tp: mysql.TypeDatetime},
 	{name: "END_TIME", tp: mysql.TypeDatetime},
 	{name: "STATE", tp: mysql.TypeVarchar, size: 64},
+	{name: "PHASE", tp: mysql.TypeVarchar, size: 16},
+	{name: "ESTIMATED_TOTAL_ROWS", tp: mysql.TypeLonglong, size: 20, flag: mysql.UnsignedFlag},
+	{name: "REMAINING_SECONDS", tp: mysql.TypeLonglong, size: 20, flag: mysql.UnsignedFlag},
+	{name: "FAIL_REASON", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+	{name: "INSTANCE", tp: mysql.TypeVarchar, size: 512},
+	{name: "PROCESS_ID", tp: mysql.TypeLonglong, size: 20, flag: mysql.UnsignedFlag},
+	{name: "JOB_ID", tp: mysql.TypeLonglong, size: 20, flag: mysql.UnsignedFlag},
 }
 
 // TableTiKVRegionStatusCols is TiKV region status mem table columns.
@@ -997,6 +1051,28 @@ var tableClusterConfigCols = []columnInfo{
 	{name: "VALUE", tp: mysql.TypeVarchar, size: 128},
 }
 
+var tableClusterConfigDiffCols = []columnInfo{
+	{name: "TYPE", tp: mysql.TypeVarchar, size: 64},
+	{name: "INSTANCE", tp: mysql.TypeVarchar, size: 64},
+	{name: "KEY", tp: mysql.TypeVarchar, size: 256},
+	{name: "VALUE", tp: mysql.TypeVarchar, size: 128},
+	{name: "MAJORITY_VALUE", tp: mysql.TypeVarchar, size: 128, comment: "The most common value of the item among the instances of the same type"},
+	{name: "DEFAULT_VALUE", tp: mysql.TypeVarchar, size: 128, comment: "The default value of the item, it's only known for TiDB"},
+	{name: "DIFF_REASON", tp: mysql.TypeVarchar, size: 64, comment: "Why the item is listed, `majority`, `default` or both of them"},
+}
+
+var tableTiDBAutoRandomIDSpaceCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "COLUMN_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_ID", tp: mysql.TypeLonglong, size: 21},
+	{name: "SHARD_BITS", tp: mysql.TypeLonglong, size: 21},
+	{name: "INCREMENTAL_BITS", tp: mysql.TypeLonglong, size: 21},
+	{name: "NEXT_ID", tp: mysql.TypeLonglong, size: 21, comment: "The next value of the incremental bits to be allocated"},
+	{name: "MAX_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The max value of the incremental bits"},
+	{name: "REMAINING_IDS", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The number of the IDs which can still be allocated implicitly"},
+}
+
 var tableClusterLogCols = []columnInfo{
 	{name: "TIME", tp: mysql.TypeVarchar, size: 32},
 	{name: "TYPE", tp: mysql.TypeVarchar, size: 64},
@@ -1106,6 +1182,65 @@ var tableInspectionResultCols = []columnInfo{
 	{name: "DETAILS", tp: mysql.TypeVarchar, size: 256},
 }
 
+var tableInspectionReportCols = []columnInfo{
+	{name: "RULE", tp: mysql.TypeVarchar, size: 64},
+	{name: "ITEM", tp: mysql.TypeVarchar, size: 64},
+	{name: "TYPE", tp: mysql.TypeVarchar, size: 64},
+	{name: "INSTANCE", tp: mysql.TypeVarchar, size: 64},
+	{name: "STATUS_ADDRESS", tp: mysql.TypeVarchar, size: 64},
+	{name: "VALUE", tp: mysql.TypeVarchar, size: 64},
+	{name: "REFERENCE", tp: mysql.TypeVarchar, size: 64},
+	{name: "SEVERITY", tp: mysql.TypeVarchar, size: 64},
+	{name: "DETAILS", tp: mysql.TypeVarchar, size: 256},
+	{name: "SUGGESTION", tp: mysql.TypeVarchar, size: 256},
+}
+
+// tableTiDBIndexUsageCols is the columns of the index usage table, the usage is collected by the executors and
+// dumped to mysql.schema_index_usage periodically.
+var tableTiDBIndexUsageCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "INDEX_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_ID", tp: mysql.TypeLonglong, size: 21},
+	{name: "INDEX_ID", tp: mysql.TypeLonglong, size: 21},
+	{name: "QUERY_COUNT", tp: mysql.TypeLonglong, size: 21, comment: "The number of the queries which read the index"},
+	{name: "ROWS_SELECTED", tp: mysql.TypeLonglong, size: 21, comment: "The number of the rows read from the index"},
+	{name: "ROWS_WRITTEN", tp: mysql.TypeLonglong, size: 21, comment: "The number of the index rows written by the committed transactions"},
+	{name: "LAST_USED_AT", tp: mysql.TypeDatetime, size: 19, comment: "The last time the index is read, NULL if it has never been read"},
+}
+
+var tableMemoryUsageCols = []columnInfo{
+	{name: "CATEGORY", tp: mysql.TypeVarchar, size: 64},
+	{name: "BYTES", tp: mysql.TypeLonglong, size: 21},
+	{name: "DESCRIPTION", tp: mysql.TypeVarchar, size: 256},
+}
+
+var tableMemoryUsageOpsHistoryCols = []columnInfo{
+	{name: "TIME", tp: mysql.TypeDatetime, size: 19},
+	{name: "OPS", tp: mysql.TypeVarchar, size: 20, comment: "The operation taken, `record` or `kill`"},
+	{name: "MEMORY_LIMIT", tp: mysql.TypeLonglong, size: 21, comment: "The memory usage which triggers the operation"},
+	{name: "MEMORY_CURRENT", tp: mysql.TypeLonglong, size: 21, comment: "The memory usage of the tidb-server when the operation is taken"},
+	{name: "CONN_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The connection of the killed statement"},
+	{name: "MEM", tp: mysql.TypeLonglong, size: 21, comment: "The memory usage of the killed statement"},
+	{name: "USER", tp: mysql.TypeVarchar, size: 64},
+	{name: "HOST", tp: mysql.TypeVarchar, size: 64},
+	{name: "DB", tp: mysql.TypeVarchar, size: 64},
+	{name: "SQL_DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "SQL_TEXT", tp: mysql.TypeVarchar, size: 256},
+}
+
+var tablePlanReplayerCapturesCols = []columnInfo{
+	{name: "SQL_DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "PLAN_DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "REASON", tp: mysql.TypeVarchar, size: 64, comment: "Why the statement is captured, `slow` or `plan_change`"},
+	{name: "LATENCY", tp: mysql.TypeDouble, size: 22, comment: "The latency of the statement in seconds"},
+	{name: "DB", tp: mysql.TypeVarchar, size: 64},
+	{name: "QUERY_SAMPLE_TEXT", tp: mysql.TypeBlob, size: types.UnspecifiedLength},
+	{name: "CAPTURE_TIME", tp: mysql.TypeDatetime, size: 19},
+	{name: "FILE_NAME", tp: mysql.TypeVarchar, size: 128, comment: "The zip in the plan replayer directory, which can be downloaded by /plan_replayer/dump/{file_name}"},
+	{name: "ERROR", tp: mysql.TypeVarchar, size: 256, comment: "The error if the statement fails to be dumped"},
+}
+
 var tableInspectionSummaryCols = []columnInfo{
 	{name: "RULE", tp: mysql.TypeVarchar, size: 64},
 	{name: "INSTANCE", tp: mysql.TypeVarchar, size: 64},
@@ -1417,6 +1552,11 @@ var tableTiDBTrxCols = []columnInfo{
 	{name: txninfo.AllSQLDigestsStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "A list of the digests of SQL statements that the transaction has executed"},
 }
 
+var tableTrxSummaryCols = []columnInfo{
+	{name: txninfo.SummaryDigestStr, tp: mysql.TypeVarchar, size: 16, flag: mysql.NotNullFlag, comment: "Digest of the SQL digests executed by the transactions"},
+	{name: txninfo.SummaryAllSQLDigestsStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "A list of the digests of SQL statements that the transactions have executed"},
+}
+
 var tableDeadlocksCols = []columnInfo{
 	{name: deadlockhistory.ColDeadlockIDStr, tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag, comment: "The ID to distinguish different deadlock events"},
 	{name: deadlockhistory.ColOccurTimeStr, tp: mysql.TypeTimestamp, decimal: 6, size: 26, comment: "The physical time when the deadlock occurs"},
@@ -1436,6 +1576,9 @@ var tableDataLockWaitsCols = []columnInfo{
 	{name: DataLockWaitsColumnCurrentHoldingTrxID, tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The transaction that's holding the lock and blocks the current transaction"},
 	{name: DataLockWaitsColumnSQLDigest, tp: mysql.TypeVarchar, size: 64, comment: "Digest of the SQL that's trying to acquire the lock"},
 	{name: DataLockWaitsColumnSQLDigestText, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Digest of the SQL that's trying to acquire the lock"},
+	{name: DataLockWaitsColumnWaitTime, tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "Milliseconds that the current transaction has been waiting for the lock"},
+	{name: DataLockWaitsColumnCurrentHoldingSQLDigest, tp: mysql.TypeVarchar, size: 64, comment: "Digest of the SQL that's being executed or last executed by the transaction holding the lock, only available if the transaction is running on the current TiDB instance"},
+	{name: DataLockWaitsColumnCurrentHoldingSQLDigestText, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Normalized text of the SQL that's being executed or last executed by the transaction holding the lock"},
 }
 
 var tableStatementsSummaryEvictedCols = []columnInfo{
@@ -1469,11 +1612,38 @@ var tablePlacementRulesCols = []columnInfo{
 	{name: "LEARNERS", tp: mysql.TypeLonglong, size: 64, flag: mysql.NotNullFlag},
 }
 
+var tableTiDBSpillFilesCols = []columnInfo{
+	{name: "ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The connection which runs the statement owning the file"},
+	{name: "DIGEST", tp: mysql.TypeVarchar, size: 64, comment: "Digest of the statement owning the file"},
+	{name: "FILE_PATH", tp: mysql.TypeVarchar, size: 512, flag: mysql.NotNullFlag},
+	{name: "SIZE", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag, comment: "The bytes written to the file"},
+	{name: "CREATE_TIME", tp: mysql.TypeDatetime, size: 19},
+}
+
+var tableSessionConnectAttrsCols = []columnInfo{
+	{name: "PROCESSLIST_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "ATTR_NAME", tp: mysql.TypeVarchar, size: 32, flag: mysql.NotNullFlag},
+	{name: "ATTR_VALUE", tp: mysql.TypeVarchar, size: 1024},
+	{name: "ORDINAL_POSITION", tp: mysql.TypeLong, size: 11, comment: "The position of the attribute ordered by the name"},
+}
+
+// TableTiKVEncryptionStatusCols is the columns of the encryption at rest status table.
+var TableTiKVEncryptionStatusCols = []columnInfo{
+	{name: "STORE_ID", tp: mysql.TypeLonglong, size: 21},
+	{name: "ADDRESS", tp: mysql.TypeVarchar, size: 64},
+	{name: "ENCRYPTION_METHOD", tp: mysql.TypeVarchar, size: 64},
+	{name: "MASTER_KEY_TYPE", tp: mysql.TypeVarchar, size: 64},
+	{name: "MASTER_KEY_ID", tp: mysql.TypeVarchar, size: 256},
+	{name: "ROTATION_STATE", tp: mysql.TypeVarchar, size: 64},
+	{name: "LAST_ROTATION_TIME", tp: mysql.TypeDatetime, size: 19},
+	{name: "ERROR", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The error of the last rotation or of getting the status"},
+}
+
 // GetShardingInfo returns a nil or description string for the sharding information of given TableInfo.
 // The returned description string may be:
 //  - "NOT_SHARDED": for tables that SHARD_ROW_ID_BITS is not specified.
 //  - "NOT_SHARDED(PK_IS_HANDLE)": for tables of which primary key is row id.
-//  - "PK_AUTO_RANDOM_BITS={bit_number}": for tables of which primary key is sharded row id.
+//  - "PK_AUTO_RANDOM_BITS={bit_number}": for tables of which primary key is sharded row id, or starts with an auto_random column.
 //  - "SHARD_BITS={bit_number}": for tables that with SHARD_ROW_ID_BITS.
 // The returned nil indicates that sharding information is not suitable for the table(for example, when the table is a View).
 // This function is exported for unit test.
@@ -1488,6 +1658,8 @@ func GetShardingInfo(dbInfo *model.DBInfo, tableInfo *model.TableInfo) interface
 		} else {
 			shardingInfo = "NOT_SHARDED(PK_IS_HANDLE)"
 		}
+	} else if tableInfo.IsCommonHandle && tableInfo.ContainsAutoRandomBits() {
+		shardingInfo = "PK_AUTO_RANDOM_BITS=" + strconv.Itoa(int(tableInfo.AutoRandomBits))
 	} else if tableInfo.ShardRowIDBits > 0 {
 		shardingInfo = "SHARD_BITS=" + strconv.Itoa(int(tableInfo.ShardRowIDBits))
 	}
@@ -1839,6 +2011,17 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableDataLockWaits:                      tableDataLockWaitsCols,
 	TableAttributes:                         tableAttributesCols,
 	TablePlacementRules:                     tablePlacementRulesCols,
+	TableTiDBSpillFiles:                     tableTiDBSpillFilesCols,
+	TableTrxSummary:                         tableTrxSummaryCols,
+	TableSessionConnectAttrs:                tableSessionConnectAttrsCols,
+	TableTiKVEncryptionStatus:               TableTiKVEncryptionStatusCols,
+	TableInspectionReport:                   tableInspectionReportCols,
+	TableTiDBIndexUsage:                     tableTiDBIndexUsageCols,
+	TableMemoryUsage:                        tableMemoryUsageCols,
+	TableMemoryUsageOpsHistory:              tableMemoryUsageOpsHistoryCols,
+	TablePlanReplayerCaptures:               tablePlanReplayerCapturesCols,
+	TableClusterConfigDiff:                  tableClusterConfigDiffCols,
+	TableTiDBAutoRandomIDSpace:              tableTiDBAutoRandomIDSpaceCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
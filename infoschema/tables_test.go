@@ -19,11 +19,13 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/errno"
@@ -41,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/pingcap/tidb/util/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -158,7 +161,8 @@ func TestInfoSchemaFieldValue(t *testing.T) {
 			"  `DIGEST` varchar(64) DEFAULT '',\n" +
 			"  `MEM` bigint(21) unsigned DEFAULT NULL,\n" +
 			"  `DISK` bigint(21) unsigned DEFAULT NULL,\n" +
-			"  `TxnStart` varchar(64) NOT NULL DEFAULT ''\n" +
+			"  `TxnStart` varchar(64) NOT NULL DEFAULT '',\n" +
+			"  `PROGRAM_NAME` varchar(64) NOT NULL DEFAULT ''\n" +
 			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
 	tk.MustQuery("show create table information_schema.cluster_log").Check(
 		testkit.Rows("" +
@@ -335,6 +339,10 @@ func TestSomeTables(t *testing.T) {
 		State:   1,
 		Info:    "do something",
 		StmtCtx: tk.Session().GetSessionVars().StmtCtx,
+		ConnectionAttrs: map[string]string{
+			"program_name": "svc-1",
+			"_client_name": "libmysql",
+		},
 	}
 	sm.processInfoMap[2] = &util.ProcessInfo{
 		ID:      2,
@@ -363,9 +371,9 @@ func TestSomeTables(t *testing.T) {
 	tk.Session().SetSessionManager(sm)
 	tk.MustQuery("select * from information_schema.PROCESSLIST order by ID;").Sort().Check(
 		testkit.Rows(
-			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0 ", "in transaction", "do something"),
-			fmt.Sprintf("2 user-2 localhost test Init DB 9223372036 %s %s abc2 0 0 ", "autocommit", strings.Repeat("x", 101)),
-			fmt.Sprintf("3 user-3 127.0.0.1:12345 test Init DB 9223372036 %s %s abc3 0 0 ", "in transaction", "check port"),
+			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0  svc-1", "in transaction", "do something"),
+			fmt.Sprintf("2 user-2 localhost test Init DB 9223372036 %s %s abc2 0 0  ", "autocommit", strings.Repeat("x", 101)),
+			fmt.Sprintf("3 user-3 127.0.0.1:12345 test Init DB 9223372036 %s %s abc3 0 0  ", "in transaction", "check port"),
 		))
 	tk.MustQuery("SHOW PROCESSLIST;").Sort().Check(
 		testkit.Rows(
@@ -379,6 +387,11 @@ func TestSomeTables(t *testing.T) {
 			fmt.Sprintf("2 user-2 localhost test Init DB 9223372036 %s %s", "autocommit", strings.Repeat("x", 101)),
 			fmt.Sprintf("3 user-3 127.0.0.1:12345 test Init DB 9223372036 %s %s", "in transaction", "check port"),
 		))
+	tk.MustQuery("select * from information_schema.SESSION_CONNECT_ATTRS order by PROCESSLIST_ID, ORDINAL_POSITION;").Check(
+		testkit.Rows(
+			"1 _client_name libmysql 0",
+			"1 program_name svc-1 1",
+		))
 
 	sm = &mockSessionManager{make(map[uint64]*util.ProcessInfo, 2), nil}
 	sm.processInfoMap[1] = &util.ProcessInfo{
@@ -404,8 +417,8 @@ func TestSomeTables(t *testing.T) {
 	tk.Session().GetSessionVars().TimeZone = time.UTC
 	tk.MustQuery("select * from information_schema.PROCESSLIST order by ID;").Check(
 		testkit.Rows(
-			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0 ", "in transaction", "<nil>"),
-			fmt.Sprintf("2 user-2 localhost <nil> Init DB 9223372036 %s %s abc2 0 0 07-29 03:26:05.158(410090409861578752)", "autocommit", strings.Repeat("x", 101)),
+			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0  ", "in transaction", "<nil>"),
+			fmt.Sprintf("2 user-2 localhost <nil> Init DB 9223372036 %s %s abc2 0 0 07-29 03:26:05.158(410090409861578752) ", "autocommit", strings.Repeat("x", 101)),
 		))
 	tk.MustQuery("SHOW PROCESSLIST;").Sort().Check(
 		testkit.Rows(
@@ -419,14 +432,56 @@ func TestSomeTables(t *testing.T) {
 		))
 	tk.MustQuery("select * from information_schema.PROCESSLIST where db is null;").Check(
 		testkit.Rows(
-			fmt.Sprintf("2 user-2 localhost <nil> Init DB 9223372036 %s %s abc2 0 0 07-29 03:26:05.158(410090409861578752)", "autocommit", strings.Repeat("x", 101)),
+			fmt.Sprintf("2 user-2 localhost <nil> Init DB 9223372036 %s %s abc2 0 0 07-29 03:26:05.158(410090409861578752) ", "autocommit", strings.Repeat("x", 101)),
 		))
 	tk.MustQuery("select * from information_schema.PROCESSLIST where Info is null;").Check(
 		testkit.Rows(
-			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0 ", "in transaction", "<nil>"),
+			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0  ", "in transaction", "<nil>"),
 		))
 }
 
+func TestProcessListDesensitization(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	sm := &mockSessionManager{make(map[uint64]*util.ProcessInfo, 2), nil}
+	sm.processInfoMap[1] = &util.ProcessInfo{
+		ID:      1,
+		User:    "root",
+		Host:    "localhost",
+		Command: byte(3),
+		State:   2,
+		Info:    "select * from t where a = 'secret-1'",
+		StmtCtx: tk.Session().GetSessionVars().StmtCtx,
+	}
+	sm.processInfoMap[2] = &util.ProcessInfo{
+		ID:      2,
+		User:    "user-2",
+		Host:    "localhost",
+		Command: byte(3),
+		State:   2,
+		Info:    "select * from t where a = 'secret-2'",
+		StmtCtx: tk.Session().GetSessionVars().StmtCtx,
+	}
+	tk.Session().SetSessionManager(sm)
+	tk.MustQuery("select info from information_schema.PROCESSLIST order by ID").Check(
+		testkit.Rows("select * from t where a = 'secret-1'", "select * from t where a = 'secret-2'"))
+
+	tk.MustExec("set global tidb_log_desensitization = on")
+	defer tk.MustExec("set global tidb_log_desensitization = default")
+	// The statements of the other users are desensitized.
+	tk.MustQuery("select info from information_schema.PROCESSLIST order by ID").Check(
+		testkit.Rows("select * from t where a = 'secret-1'", "select * from `t` where `a` = ?"))
+	tk.MustQuery("SHOW FULL PROCESSLIST").Sort().Check(
+		testkit.Rows(
+			"1 root localhost <nil> Query 9223372036 autocommit select * from t where a = 'secret-1'",
+			"2 user-2 localhost <nil> Query 9223372036 autocommit select * from `t` where `a` = ?",
+		))
+	require.Equal(t, "select * from t where a = 'secret-2'", sm.processInfoMap[2].Info)
+}
+
 func prepareSlowLogfile(t *testing.T, slowLogFileName string) {
 	f, err := os.OpenFile(slowLogFileName, os.O_CREATE|os.O_WRONLY, 0644)
 	require.NoError(t, err)
@@ -1283,6 +1338,44 @@ func TestStmtSummaryHistoryTableOther(t *testing.T) {
 		))
 }
 
+func TestStmtSummaryHistoryPersistence(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := newTestKitWithRoot(t, store)
+	// Clear summaries.
+	tk.MustExec("set global tidb_enable_stmt_summary = 0")
+	tk.MustExec("set global tidb_enable_stmt_summary = 1")
+
+	filename := filepath.Join(t.TempDir(), "tidb-statements.log")
+	require.NoError(t, stmtsummary.StmtSummaryByDigestMap.SetupPersistence(log.FileLogConfig{Filename: filename}))
+	defer stmtsummary.StmtSummaryByDigestMap.ClosePersistence()
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, key k(a))")
+	tk.MustExec("insert into t values(1, 1), (2, 2)")
+	tk.MustExec("select * from t where a = 1")
+	tk.MustExec("select * from t where a = 2")
+	// The end time is cut to the time of the shutdown, so it's not compared.
+	sql := "select summary_begin_time, stmt_type, schema_name, digest, digest_text, table_names, index_names, " +
+		"sample_user, exec_count, sum_latency, max_latency, avg_latency, avg_processed_keys, max_mem, backoff_types, " +
+		"first_seen, last_seen, plan_in_cache, query_sample_text, prev_sample_text, plan_digest, plan " +
+		"from information_schema.statements_summary_history where digest_text like 'select * from `t`%'"
+	rows := tk.MustQuery(sql).Rows()
+	require.Len(t, rows, 1)
+
+	// The summaries are read from the file after the restart.
+	stmtsummary.StmtSummaryByDigestMap.ClosePersistence()
+	tk.MustExec("set global tidb_enable_stmt_summary = 0")
+	tk.MustExec("set global tidb_enable_stmt_summary = 1")
+	tk.MustQuery(sql).Check(testkit.Rows())
+	require.NoError(t, stmtsummary.StmtSummaryByDigestMap.SetupPersistence(log.FileLogConfig{Filename: filename}))
+	tk.MustQuery(sql).Check(rows)
+	tk.MustQuery("select exec_count, index_names, sample_user from information_schema.statements_summary_history " +
+		"where digest_text like 'select * from `t`%'").Check(testkit.Rows("2 t:k root"))
+}
+
 func TestPerformanceSchemaforPlanCache(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -1431,6 +1524,55 @@ func TestInfoSchemaDeadlockPrivilege(t *testing.T) {
 	_ = tk.MustQuery("select * from information_schema.deadlocks")
 }
 
+func TestTrxSummary(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	txninfo.Recorder.ResizeSummaries(2)
+	defer txninfo.Recorder.ResizeSummaries(0)
+	txninfo.Recorder.Clean()
+
+	tk := newTestKitWithRoot(t, store)
+	tk.MustExec("create table t (a int)")
+	txninfo.Recorder.Clean()
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (1)")
+	tk.MustExec("update t set a = a + 1")
+	tk.MustExec("commit")
+	_, beginDigest := parser.NormalizeDigest("begin")
+	_, insertDigest := parser.NormalizeDigest("insert into t values (1)")
+	_, updateDigest := parser.NormalizeDigest("update t set a = a + 1")
+	_, commitDigest := parser.NormalizeDigest("commit")
+	_, rollbackDigest := parser.NormalizeDigest("rollback")
+	allSQLDigests := fmt.Sprintf(`["%s","%s","%s","%s"]`, beginDigest, insertDigest, updateDigest, commitDigest)
+	tk.MustQuery("select all_sql_digests from information_schema.trx_summary").Check(testkit.Rows(allSQLDigests))
+
+	// The transactions executing the same statements are merged.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (2)")
+	tk.MustExec("update t set a = a + 3")
+	tk.MustExec("rollback")
+	rows := tk.MustQuery("select digest, all_sql_digests from information_schema.trx_summary").Rows()
+	require.Len(t, rows, 2)
+	require.Len(t, rows[0][0], 16)
+	require.Equal(t, fmt.Sprintf(`["%s","%s","%s","%s"]`, beginDigest, insertDigest, updateDigest, rollbackDigest), rows[0][1])
+	require.Equal(t, allSQLDigests, rows[1][1])
+
+	// The least recently finished transactions are evicted.
+	tk.MustExec("insert into t values (3)")
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (4)")
+	tk.MustExec("update t set a = a + 1")
+	tk.MustExec("commit")
+	tk.MustQuery("select all_sql_digests from information_schema.trx_summary").Check(testkit.Rows(
+		allSQLDigests, fmt.Sprintf(`["%s"]`, insertDigest)))
+
+	tk.MustExec("create user 'testuser'@'localhost'")
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "testuser", Hostname: "localhost"}, nil, nil))
+	err := tk.QueryToErr("select * from information_schema.trx_summary")
+	require.Equal(t, "[planner:1227]Access denied; you need (at least one of) the PROCESS privilege(s) for this operation", err.Error())
+}
+
 func TestAttributes(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -31,6 +31,7 @@ import (
 	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
 	"github.com/tikv/client-go/v2/tikvrpc"
+	"go.uber.org/atomic"
 )
 
 // UnCommitIndexKVFlag uses to indicate the index key/value is no need to commit.
@@ -340,6 +341,11 @@ type Request struct {
 	ResourceGroupTagger tikvrpc.ResourceGroupTagger
 	// Paging indicates whether the request is a paging request.
 	Paging bool
+	// ResourceGroupName is the resource group which the request units of the request are charged to.
+	ResourceGroupName string
+	// RunawayCooldown is set when the statement exceeds the QUERY_LIMIT of its resource group with the COOLDOWN
+	// action, the remaining requests are sent with low priority then.
+	RunawayCooldown *atomic.Bool
 }
 
 const (
@@ -481,4 +487,7 @@ const (
 	SI IsoLevel = iota
 	// RC stands for 'read committed'.
 	RC
+	// RCCheckTS stands for 'read committed' with the check of the read timestamp, the storage returns a write
+	// conflict error instead of the data if a newer version than the read timestamp is met.
+	RCCheckTS
 )
@@ -31,8 +31,8 @@ var (
 )
 
 const (
-	// AutoRandomPKisNotHandleErrMsg indicates the auto_random column attribute is defined on a non-primary key column, or the primary key is nonclustered.
-	AutoRandomPKisNotHandleErrMsg = "column %s is not the integer primary key, or the primary key is nonclustered"
+	// AutoRandomPKisNotHandleErrMsg indicates the auto_random column attribute is defined on a column other than the integer primary key or the first column of the composite primary key, or the primary key is nonclustered.
+	AutoRandomPKisNotHandleErrMsg = "column %s is not the integer primary key or the first column of the composite primary key, or the primary key is nonclustered"
 	// AutoRandomIncompatibleWithAutoIncErrMsg is reported when auto_random and auto_increment are specified on the same column.
 	AutoRandomIncompatibleWithAutoIncErrMsg = "auto_random is incompatible with auto_increment"
 	// AutoRandomIncompatibleWithDefaultValueErrMsg is reported when auto_random and default are specified on the same column.
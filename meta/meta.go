@@ -59,23 +59,26 @@ var (
 //
 
 var (
-	mMetaPrefix       = []byte("m")
-	mNextGlobalIDKey  = []byte("NextGlobalID")
-	mSchemaVersionKey = []byte("SchemaVersionKey")
-	mDBs              = []byte("DBs")
-	mDBPrefix         = "DB"
-	mTablePrefix      = "Table"
-	mSequencePrefix   = "SID"
-	mSeqCyclePrefix   = "SequenceCycle"
-	mTableIDPrefix    = "TID"
-	mIncIDPrefix      = "IID"
-	mRandomIDPrefix   = "TARID"
-	mBootstrapKey     = []byte("BootstrapKey")
-	mSchemaDiffPrefix = "Diff"
-	mPolicies         = []byte("Policies")
-	mPolicyPrefix     = "Policy"
-	mPolicyGlobalID   = []byte("PolicyGlobalID")
-	mPolicyMagicByte  = CurrentMagicByteVer
+	mMetaPrefix            = []byte("m")
+	mNextGlobalIDKey       = []byte("NextGlobalID")
+	mSchemaVersionKey      = []byte("SchemaVersionKey")
+	mDBs                   = []byte("DBs")
+	mDBPrefix              = "DB"
+	mTablePrefix           = "Table"
+	mSequencePrefix        = "SID"
+	mSeqCyclePrefix        = "SequenceCycle"
+	mTableIDPrefix         = "TID"
+	mIncIDPrefix           = "IID"
+	mRandomIDPrefix        = "TARID"
+	mBootstrapKey          = []byte("BootstrapKey")
+	mSchemaDiffPrefix      = "Diff"
+	mPolicies              = []byte("Policies")
+	mPolicyPrefix          = "Policy"
+	mPolicyGlobalID        = []byte("PolicyGlobalID")
+	mPolicyMagicByte       = CurrentMagicByteVer
+	mResourceGroups        = []byte("ResourceGroups")
+	mResourceGroupPrefix   = "ResourceGroup"
+	mResourceGroupGlobalID = []byte("ResourceGroupGlobalID")
 )
 
 const (
@@ -102,6 +105,10 @@ var (
 	ErrPolicyExists = dbterror.ClassMeta.NewStd(errno.ErrPlacementPolicyExists)
 	// ErrPolicyNotExists is the error for policy not exists.
 	ErrPolicyNotExists = dbterror.ClassMeta.NewStd(errno.ErrPlacementPolicyNotExists)
+	// ErrResourceGroupExists is the error for resource group exists.
+	ErrResourceGroupExists = dbterror.ClassMeta.NewStd(errno.ErrResourceGroupExists)
+	// ErrResourceGroupNotExists is the error for resource group not exists.
+	ErrResourceGroupNotExists = dbterror.ClassMeta.NewStd(errno.ErrResourceGroupNotExists)
 	// ErrTableExists is the error for table exists.
 	ErrTableExists = dbterror.ClassMeta.NewStd(mysql.ErrTableExists)
 	// ErrTableNotExists is the error for table not exists.
@@ -179,6 +186,10 @@ func (m *Meta) policyKey(policyID int64) []byte {
 	return []byte(fmt.Sprintf("%s:%d", mPolicyPrefix, policyID))
 }
 
+func (m *Meta) resourceGroupKey(groupID int64) []byte {
+	return []byte(fmt.Sprintf("%s:%d", mResourceGroupPrefix, groupID))
+}
+
 func (m *Meta) dbKey(dbID int64) []byte {
 	return []byte(fmt.Sprintf("%s:%d", mDBPrefix, dbID))
 }
@@ -250,6 +261,22 @@ func (m *Meta) checkPolicyNotExists(policyKey []byte) error {
 	return errors.Trace(err)
 }
 
+func (m *Meta) checkResourceGroupExists(groupKey []byte) error {
+	v, err := m.txn.HGet(mResourceGroups, groupKey)
+	if err == nil && v == nil {
+		err = ErrResourceGroupNotExists.GenWithStack("resource group doesn't exist")
+	}
+	return errors.Trace(err)
+}
+
+func (m *Meta) checkResourceGroupNotExists(groupKey []byte) error {
+	v, err := m.txn.HGet(mResourceGroups, groupKey)
+	if err == nil && v != nil {
+		err = ErrResourceGroupExists.GenWithStack("resource group already exists")
+	}
+	return errors.Trace(err)
+}
+
 func (m *Meta) checkDBExists(dbKey []byte) error {
 	v, err := m.txn.HGet(mDBs, dbKey)
 	if err == nil && v == nil {
@@ -322,6 +349,39 @@ func (m *Meta) UpdatePolicy(policy *model.PolicyInfo) error {
 	return m.txn.HSet(mPolicies, policyKey, attachMagicByte(data))
 }
 
+// CreateResourceGroup creates a resource group.
+func (m *Meta) CreateResourceGroup(group *model.ResourceGroupInfo) error {
+	if group.ID == 0 {
+		genID, err := m.txn.Inc(mResourceGroupGlobalID, 1)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		group.ID = genID
+	}
+	groupKey := m.resourceGroupKey(group.ID)
+	if err := m.checkResourceGroupNotExists(groupKey); err != nil {
+		return errors.Trace(err)
+	}
+	data, err := json.Marshal(group)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return m.txn.HSet(mResourceGroups, groupKey, attachMagicByte(data))
+}
+
+// UpdateResourceGroup updates a resource group.
+func (m *Meta) UpdateResourceGroup(group *model.ResourceGroupInfo) error {
+	groupKey := m.resourceGroupKey(group.ID)
+	if err := m.checkResourceGroupExists(groupKey); err != nil {
+		return errors.Trace(err)
+	}
+	data, err := json.Marshal(group)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return m.txn.HSet(mResourceGroups, groupKey, attachMagicByte(data))
+}
+
 // CreateDatabase creates a database with db info.
 func (m *Meta) CreateDatabase(dbInfo *model.DBInfo) error {
 	dbKey := m.dbKey(dbInfo.ID)
@@ -435,6 +495,11 @@ func (m *Meta) DropPolicy(policyID int64) error {
 	return nil
 }
 
+// DropResourceGroup drops the specified resource group.
+func (m *Meta) DropResourceGroup(groupID int64) error {
+	return errors.Trace(m.txn.HDel(mResourceGroups, m.resourceGroupKey(groupID)))
+}
+
 // DropDatabase drops whole database.
 func (m *Meta) DropDatabase(dbID int64) error {
 	// Check if db exists.
@@ -618,6 +683,48 @@ func (m *Meta) GetPolicy(policyID int64) (*model.PolicyInfo, error) {
 	return policy, errors.Trace(err)
 }
 
+// ListResourceGroups shows all resource groups.
+func (m *Meta) ListResourceGroups() ([]*model.ResourceGroupInfo, error) {
+	res, err := m.txn.HGetAll(mResourceGroups)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	groups := make([]*model.ResourceGroupInfo, 0, len(res))
+	for _, r := range res {
+		value, err := detachMagicByte(r.Value)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		group := &model.ResourceGroupInfo{}
+		if err = json.Unmarshal(value, group); err != nil {
+			return nil, errors.Trace(err)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// GetResourceGroup gets the resource group with ID.
+func (m *Meta) GetResourceGroup(groupID int64) (*model.ResourceGroupInfo, error) {
+	value, err := m.txn.HGet(mResourceGroups, m.resourceGroupKey(groupID))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if value == nil {
+		return nil, ErrResourceGroupNotExists.GenWithStack("resource group id : %d doesn't exist", groupID)
+	}
+
+	value, err = detachMagicByte(value)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	group := &model.ResourceGroupInfo{}
+	err = json.Unmarshal(value, group)
+	return group, errors.Trace(err)
+}
+
 func attachMagicByte(data []byte) []byte {
 	data = append(data, 0)
 	copy(data[1:], data)
@@ -36,6 +36,14 @@ var (
 			Buckets:   prometheus.ExponentialBuckets(4, 4, 21), // 4Bytes ~ 4TB
 		}, []string{LblType})
 
+	CompressedPacketIOCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "compressed_packet_io_bytes",
+			Help:      "Counter of bytes transferred by the compressed protocol, before and after compression.",
+		}, []string{LblType, LblAlgorithm, LblStage})
+
 	QueryDurationHistogram = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "tidb",
@@ -94,6 +102,7 @@ var (
 
 	EventStart        = "start"
 	EventGracefulDown = "graceful_shutdown"
+	EventDrain        = "drain"
 	// Eventkill occurs when the server.Kill() function is called.
 	EventKill          = "kill"
 	EventClose         = "close"
@@ -138,6 +147,14 @@ var (
 		},
 	)
 
+	AdmissionRejectedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "admission_rejected_total",
+			Help:      "Counter of statements rejected because the server reaches token-limit or server-memory-quota.",
+		}, []string{LblType})
+
 	GetTokenDurationHistogram = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Namespace: "tidb",
@@ -238,6 +255,42 @@ var (
 			Help:      "Bucketed histogram of all pd api execution time (s)",
 			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 20), // 1ms ~ 524s
 		}, []string{LblType})
+
+	// The following metrics of the statements are broken down by the resource group and the current database of the
+	// session, they're recorded only if `status.record-stmt-metrics-by-group` is enabled.
+	GroupQueryTotalCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "group_query_total",
+			Help:      "Counter of the statements by resource group and database.",
+		}, []string{LblSQLType, LblResourceGroup, LblDb, LblResult})
+
+	GroupQueryDurationHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "group_query_duration_seconds",
+			Help:      "Bucketed histogram of processing time (s) of the statements by resource group and database.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 29), // 0.5ms ~ 1.5days
+		}, []string{LblSQLType, LblResourceGroup, LblDb})
+
+	GroupQueryMemoryHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "group_query_memory_bytes",
+			Help:      "Bucketed histogram of the max memory usage of the statements by resource group and database.",
+			Buckets:   prometheus.ExponentialBuckets(1024, 4, 14), // 1KB ~ 64GB
+		}, []string{LblSQLType, LblResourceGroup, LblDb})
+
+	GroupQueryRUCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "group_query_ru_total",
+			Help:      "Counter of the request units consumed by the statements by resource group and database.",
+		}, []string{LblSQLType, LblResourceGroup, LblDb})
 )
 
 // ExecuteErrorToLabel converts an execute error to label.
@@ -157,4 +157,8 @@ const (
 	LblVersion     = "version"
 	LblHash        = "hash"
 	LblCTEType     = "cte_type"
+	LblAlgorithm   = "algorithm"
+	LblStage       = "stage"
+
+	LblResourceGroup = "resource_group"
 )
@@ -14,6 +14,8 @@
 package ast
 
 import (
+	"time"
+
 	"github.com/pingcap/errors"
 
 	"github.com/pingcap/tidb/parser/auth"
@@ -40,6 +42,9 @@ var (
 	_ DDLNode = &DropTableStmt{}
 	_ DDLNode = &DropSequenceStmt{}
 	_ DDLNode = &DropPlacementPolicyStmt{}
+	_ DDLNode = &CreateResourceGroupStmt{}
+	_ DDLNode = &AlterResourceGroupStmt{}
+	_ DDLNode = &DropResourceGroupStmt{}
 	_ DDLNode = &RenameTableStmt{}
 	_ DDLNode = &TruncateTableStmt{}
 	_ DDLNode = &RepairTableStmt{}
@@ -991,13 +996,25 @@ type CreateTableStmt struct {
 	Partition      *PartitionOptions
 	OnDuplicate    OnDuplicateKeyHandlingType
 	Select         ResultSetNode
+	// External is not nil for CREATE EXTERNAL TABLE.
+	External *ExternalTableOption
+}
+
+// ExternalTableOption is the `LOCATION ... FORMAT ...` clause of CREATE EXTERNAL TABLE.
+type ExternalTableOption struct {
+	Location string
+	Format   string
 }
 
 // Restore implements Node interface.
 func (n *CreateTableStmt) Restore(ctx *format.RestoreCtx) error {
 	switch n.TemporaryKeyword {
 	case TemporaryNone:
-		ctx.WriteKeyWord("CREATE TABLE ")
+		if n.External != nil {
+			ctx.WriteKeyWord("CREATE EXTERNAL TABLE ")
+		} else {
+			ctx.WriteKeyWord("CREATE TABLE ")
+		}
 	case TemporaryGlobal:
 		ctx.WriteKeyWord("CREATE GLOBAL TEMPORARY TABLE ")
 	case TemporaryLocal:
@@ -1069,6 +1086,13 @@ func (n *CreateTableStmt) Restore(ctx *format.RestoreCtx) error {
 		}
 	}
 
+	if n.External != nil {
+		ctx.WriteKeyWord(" LOCATION ")
+		ctx.WriteString(n.External.Location)
+		ctx.WriteKeyWord(" FORMAT ")
+		ctx.WritePlain(n.External.Format)
+	}
+
 	if n.TemporaryKeyword == TemporaryGlobal {
 		if n.OnCommitDelete {
 			ctx.WriteKeyWord(" ON COMMIT DELETE ROWS")
@@ -2014,6 +2038,7 @@ const (
 	TableOptionTableCheckSum
 	TableOptionUnion
 	TableOptionEncryption
+	TableOptionGCLifeTime
 	TableOptionPlacementPrimaryRegion       = TableOptionType(PlacementOptionPrimaryRegion)
 	TableOptionPlacementRegions             = TableOptionType(PlacementOptionRegions)
 	TableOptionPlacementFollowerCount       = TableOptionType(PlacementOptionFollowerCount)
@@ -2297,6 +2322,12 @@ func (n *TableOption) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("ENCRYPTION ")
 		ctx.WritePlain("= ")
 		ctx.WriteString(n.StrValue)
+	case TableOptionGCLifeTime:
+		ctx.WriteWithSpecialComments(tidb.FeatureIDGCLifeTime, func() {
+			ctx.WriteKeyWord("GC_LIFE_TIME ")
+			ctx.WritePlain("= ")
+			ctx.WriteString(n.StrValue)
+		})
 	case TableOptionPlacementPrimaryRegion, TableOptionPlacementRegions, TableOptionPlacementFollowerCount, TableOptionPlacementLeaderConstraints, TableOptionPlacementLearnerCount, TableOptionPlacementVoterCount, TableOptionPlacementSchedule, TableOptionPlacementConstraints, TableOptionPlacementFollowerConstraints, TableOptionPlacementVoterConstraints, TableOptionPlacementLearnerConstraints, TableOptionPlacementPolicy:
 		placementOpt := PlacementOption{
 			Tp:        PlacementOptionType(n.Tp),
@@ -3881,6 +3912,35 @@ func (n *FlashBackTableStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// FlashBackDatabaseStmt is a statement to restore a dropped database.
+type FlashBackDatabaseStmt struct {
+	ddlNode
+
+	DBName  model.CIStr
+	NewName string
+}
+
+// Restore implements Node interface.
+func (n *FlashBackDatabaseStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("FLASHBACK DATABASE ")
+	ctx.WriteName(n.DBName.O)
+	if len(n.NewName) > 0 {
+		ctx.WriteKeyWord(" TO ")
+		ctx.WriteName(n.NewName)
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *FlashBackDatabaseStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*FlashBackDatabaseStmt)
+	return v.Leave(n)
+}
+
 type AttributesSpec struct {
 	node
 
@@ -3968,6 +4028,157 @@ func (n *AlterPlacementPolicyStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// ResourceGroupOptionType is the type for ResourceGroupOption.
+type ResourceGroupOptionType int
+
+// ResourceGroupOption types.
+const (
+	ResourceRURate ResourceGroupOptionType = 0x6000 + iota
+	ResourcePriority
+	ResourceBurstable
+	ResourceQueryLimit
+	ResourceEgressRate
+)
+
+// ResourceGroupOption is used for parsing resource group option.
+type ResourceGroupOption struct {
+	Tp        ResourceGroupOptionType
+	UintValue uint64
+	BoolValue bool
+	// RunawayAction is the ACTION of QUERY_LIMIT, whose EXEC_ELAPSED is stored in UintValue in milliseconds.
+	RunawayAction model.RunawayActionType
+}
+
+func (n *ResourceGroupOption) Restore(ctx *format.RestoreCtx) error {
+	switch n.Tp {
+	case ResourceRURate:
+		ctx.WriteKeyWord("RU_PER_SEC ")
+		ctx.WritePlain("= ")
+		ctx.WritePlainf("%d", n.UintValue)
+	case ResourcePriority:
+		ctx.WriteKeyWord("PRIORITY ")
+		ctx.WritePlain("= ")
+		ctx.WriteKeyWord(model.ResourceGroupPriorityValueToName[n.UintValue])
+	case ResourceBurstable:
+		ctx.WriteKeyWord("BURSTABLE")
+	case ResourceEgressRate:
+		ctx.WriteKeyWord("EGRESS_BYTES_PER_SEC ")
+		ctx.WritePlain("= ")
+		ctx.WritePlainf("%d", n.UintValue)
+	case ResourceQueryLimit:
+		ctx.WriteKeyWord("QUERY_LIMIT ")
+		ctx.WritePlain("= (")
+		ctx.WriteKeyWord("EXEC_ELAPSED ")
+		ctx.WritePlain("= ")
+		ctx.WriteString((time.Duration(n.UintValue) * time.Millisecond).String())
+		ctx.WriteKeyWord(" ACTION ")
+		ctx.WritePlain("= ")
+		ctx.WriteKeyWord(n.RunawayAction.String())
+		ctx.WritePlain(")")
+	default:
+		return errors.Errorf("invalid ResourceGroupOption: %d", n.Tp)
+	}
+	return nil
+}
+
+// CreateResourceGroupStmt is a statement to create a resource group.
+type CreateResourceGroupStmt struct {
+	ddlNode
+
+	IfNotExists             bool
+	ResourceGroupName       model.CIStr
+	ResourceGroupOptionList []*ResourceGroupOption
+}
+
+// Restore implements Node interface.
+func (n *CreateResourceGroupStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CREATE RESOURCE GROUP ")
+	if n.IfNotExists {
+		ctx.WriteKeyWord("IF NOT EXISTS ")
+	}
+	ctx.WriteName(n.ResourceGroupName.O)
+	for i, option := range n.ResourceGroupOptionList {
+		ctx.WritePlain(" ")
+		if err := option.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while splicing CreateResourceGroupStmt Option: [%v]", i)
+		}
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateResourceGroupStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateResourceGroupStmt)
+	return v.Leave(n)
+}
+
+// AlterResourceGroupStmt is a statement to alter resource group options.
+type AlterResourceGroupStmt struct {
+	ddlNode
+
+	IfExists                bool
+	ResourceGroupName       model.CIStr
+	ResourceGroupOptionList []*ResourceGroupOption
+}
+
+// Restore implements Node interface.
+func (n *AlterResourceGroupStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("ALTER RESOURCE GROUP ")
+	if n.IfExists {
+		ctx.WriteKeyWord("IF EXISTS ")
+	}
+	ctx.WriteName(n.ResourceGroupName.O)
+	for i, option := range n.ResourceGroupOptionList {
+		ctx.WritePlain(" ")
+		if err := option.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while splicing AlterResourceGroupStmt Option: [%v]", i)
+		}
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *AlterResourceGroupStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*AlterResourceGroupStmt)
+	return v.Leave(n)
+}
+
+// DropResourceGroupStmt is a statement to drop a resource group.
+type DropResourceGroupStmt struct {
+	ddlNode
+
+	IfExists          bool
+	ResourceGroupName model.CIStr
+}
+
+// Restore implements Node interface.
+func (n *DropResourceGroupStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DROP RESOURCE GROUP ")
+	if n.IfExists {
+		ctx.WriteKeyWord("IF EXISTS ")
+	}
+	ctx.WriteName(n.ResourceGroupName.O)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *DropResourceGroupStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*DropResourceGroupStmt)
+	return v.Leave(n)
+}
+
 // AlterSequenceStmt is a statement to alter sequence option.
 type AlterSequenceStmt struct {
 	ddlNode
@@ -466,6 +466,7 @@ func TestAlterTableSpecRestore(t *testing.T) {
 		{"ROW_FORMAT = tokudb_uncompressed", "ROW_FORMAT = TOKUDB_UNCOMPRESSED"},
 		{"shard_row_id_bits 1", "SHARD_ROW_ID_BITS = 1"},
 		{"shard_row_id_bits = 1", "SHARD_ROW_ID_BITS = 1"},
+		{"gc_life_time = '48h'", "GC_LIFE_TIME = '48h'"},
 		{"CONVERT TO CHARACTER SET utf8", "CONVERT TO CHARACTER SET UTF8"},
 		{"CONVERT TO CHARSET utf8", "CONVERT TO CHARACTER SET UTF8"},
 		{"CONVERT TO CHARACTER SET utf8 COLLATE utf8_bin", "CONVERT TO CHARACTER SET UTF8 COLLATE UTF8_BIN"},
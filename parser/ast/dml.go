@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/parser/format"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
 )
 
 var (
@@ -34,6 +35,8 @@ var (
 	_ DMLNode = &LoadDataStmt{}
 	_ DMLNode = &SplitRegionStmt{}
 
+	_ StmtNode = &NonTransactionalDMLStmt{}
+
 	_ Node = &Assignment{}
 	_ Node = &ByItem{}
 	_ Node = &FieldList{}
@@ -518,6 +521,10 @@ type TableSource struct {
 
 	// AsName is the alias name of the table source.
 	AsName model.CIStr
+
+	// Lateral indicates it's a LATERAL derived table, which can reference the columns of the preceding
+	// tables in the FROM clause.
+	Lateral bool
 }
 
 func (*TableSource) resultSet() {}
@@ -564,6 +571,9 @@ func (n *TableSource) Restore(ctx *format.RestoreCtx) error {
 			ctx.WritePlain(")")
 		}
 	} else {
+		if n.Lateral {
+			ctx.WriteKeyWord("LATERAL ")
+		}
 		if needParen {
 			ctx.WritePlain("(")
 		}
@@ -835,6 +845,8 @@ func (n *ByItem) Accept(v Visitor) (Node, bool) {
 type GroupByClause struct {
 	node
 	Items []*ByItem
+	// Rollup indicates the GROUP BY clause has the WITH ROLLUP modifier.
+	Rollup bool
 }
 
 // Restore implements Node interface.
@@ -848,6 +860,9 @@ func (n *GroupByClause) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotatef(err, "An error occurred while restore GroupByClause.Items[%d]", i)
 		}
 	}
+	if n.Rollup {
+		ctx.WriteKeyWord(" WITH ROLLUP")
+	}
 	return nil
 }
 
@@ -1020,6 +1035,162 @@ func (s *TableSample) Accept(v Visitor) (node Node, ok bool) {
 	return v.Leave(s)
 }
 
+// JSONTable represents the JSON_TABLE table function which extracts the data of
+// a JSON document into relational columns.
+// See https://dev.mysql.com/doc/refman/8.0/en/json-table-functions.html
+type JSONTable struct {
+	node
+
+	Expr    ExprNode
+	Path    string
+	Columns []*JSONTableColumn
+}
+
+func (*JSONTable) resultSet() {}
+
+// Restore implements Node interface.
+func (n *JSONTable) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("JSON_TABLE")
+	ctx.WritePlain("(")
+	if err := n.Expr.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore JSONTable.Expr")
+	}
+	ctx.WritePlain(", ")
+	ctx.WriteString(n.Path)
+	ctx.WritePlain(" ")
+	if err := restoreJSONTableColumns(ctx, n.Columns); err != nil {
+		return err
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+func restoreJSONTableColumns(ctx *format.RestoreCtx, cols []*JSONTableColumn) error {
+	ctx.WriteKeyWord("COLUMNS")
+	ctx.WritePlain("(")
+	for i, col := range cols {
+		if i != 0 {
+			ctx.WritePlain(", ")
+		}
+		if err := col.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore JSONTable.Columns[%d]", i)
+		}
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *JSONTable) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*JSONTable)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	return v.Leave(n)
+}
+
+// JSONTableColumnType is the type of the column defined in JSON_TABLE.
+type JSONTableColumnType int
+
+const (
+	// JSONTableColumnPath is the column extracted from the path, like `c INT PATH '$.a'`.
+	JSONTableColumnPath JSONTableColumnType = iota
+	// JSONTableColumnExistsPath is the column which is 1 if the path exists, like `c INT EXISTS PATH '$.a'`.
+	JSONTableColumnExistsPath
+	// JSONTableColumnOrdinality is the row counter column, like `c FOR ORDINALITY`.
+	JSONTableColumnOrdinality
+	// JSONTableColumnNested flattens the nested arrays or objects, like `NESTED PATH '$.a[*]' COLUMNS(...)`.
+	JSONTableColumnNested
+)
+
+// JSONTableColumn is a column defined in the COLUMNS clause of JSON_TABLE.
+type JSONTableColumn struct {
+	Tp   JSONTableColumnType
+	Name model.CIStr
+	// Type is nil for the ordinality and nested columns.
+	Type *types.FieldType
+	Path string
+	// OnEmpty and OnError are nil if they are not specified.
+	OnEmpty *JSONTableOnResponse
+	OnError *JSONTableOnResponse
+	// NestedColumns are the columns of the NESTED PATH.
+	NestedColumns []*JSONTableColumn
+}
+
+// Restore writes the column definition into the RestoreCtx.
+func (n *JSONTableColumn) Restore(ctx *format.RestoreCtx) error {
+	switch n.Tp {
+	case JSONTableColumnNested:
+		ctx.WriteKeyWord("NESTED PATH ")
+		ctx.WriteString(n.Path)
+		ctx.WritePlain(" ")
+		return restoreJSONTableColumns(ctx, n.NestedColumns)
+	case JSONTableColumnOrdinality:
+		ctx.WriteName(n.Name.O)
+		ctx.WriteKeyWord(" FOR ORDINALITY")
+		return nil
+	}
+	ctx.WriteName(n.Name.O)
+	ctx.WritePlain(" ")
+	if err := n.Type.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore JSONTableColumn.Type")
+	}
+	if n.Tp == JSONTableColumnExistsPath {
+		ctx.WriteKeyWord(" EXISTS")
+	}
+	ctx.WriteKeyWord(" PATH ")
+	ctx.WriteString(n.Path)
+	if n.OnEmpty != nil {
+		ctx.WritePlain(" ")
+		n.OnEmpty.Restore(ctx)
+		ctx.WriteKeyWord(" ON EMPTY")
+	}
+	if n.OnError != nil {
+		ctx.WritePlain(" ")
+		n.OnError.Restore(ctx)
+		ctx.WriteKeyWord(" ON ERROR")
+	}
+	return nil
+}
+
+// JSONTableOnResponseType is the type of the ON EMPTY and ON ERROR clauses of a JSON_TABLE column.
+type JSONTableOnResponseType int
+
+const (
+	// JSONTableOnResponseNull sets the column to NULL.
+	JSONTableOnResponseNull JSONTableOnResponseType = iota
+	// JSONTableOnResponseError reports an error.
+	JSONTableOnResponseError
+	// JSONTableOnResponseDefault sets the column to the default value.
+	JSONTableOnResponseDefault
+)
+
+// JSONTableOnResponse is the ON EMPTY or ON ERROR clause of a JSON_TABLE column.
+type JSONTableOnResponse struct {
+	Tp JSONTableOnResponseType
+	// Default is the JSON string of DEFAULT.
+	Default string
+}
+
+// Restore writes the response without the ON EMPTY or ON ERROR keywords into the RestoreCtx.
+func (n *JSONTableOnResponse) Restore(ctx *format.RestoreCtx) {
+	switch n.Tp {
+	case JSONTableOnResponseNull:
+		ctx.WriteKeyWord("NULL")
+	case JSONTableOnResponseError:
+		ctx.WriteKeyWord("ERROR")
+	case JSONTableOnResponseDefault:
+		ctx.WriteKeyWord("DEFAULT ")
+		ctx.WriteString(n.Default)
+	}
+}
+
 type SelectStmtKind uint8
 
 const (
@@ -2347,6 +2518,76 @@ func (n *DeleteStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+const (
+	// NoDryRun means the non-transactional DML is executed.
+	NoDryRun = iota
+	// DryRunQuery means the query which splits the non-transactional DML is returned.
+	DryRunQuery
+	// DryRunSplitDml means the first and the last split statements are returned.
+	DryRunSplitDml
+)
+
+// NonTransactionalDMLStmt is a statement to split a huge DELETE or UPDATE statement into many small
+// statements, each of them is executed in its own transaction.
+// e.g. BATCH ON id LIMIT 1000 DELETE FROM t WHERE v < 10
+type NonTransactionalDMLStmt struct {
+	stmtNode
+
+	DryRun int
+	// ShardColumn is the column the rows are split by. If it's nil, the handle column is chosen.
+	ShardColumn *ColumnName
+	Limit       uint64
+	// DMLStmt is a DeleteStmt or an UpdateStmt.
+	DMLStmt DMLNode
+}
+
+// Restore implements Node interface.
+func (n *NonTransactionalDMLStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("BATCH ")
+	if n.ShardColumn != nil {
+		ctx.WriteKeyWord("ON ")
+		if err := n.ShardColumn.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore NonTransactionalDMLStmt.ShardColumn")
+		}
+		ctx.WritePlain(" ")
+	}
+	ctx.WriteKeyWord("LIMIT ")
+	ctx.WritePlainf("%d ", n.Limit)
+	switch n.DryRun {
+	case DryRunSplitDml:
+		ctx.WriteKeyWord("DRY RUN ")
+	case DryRunQuery:
+		ctx.WriteKeyWord("DRY RUN QUERY ")
+	}
+	if err := n.DMLStmt.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore NonTransactionalDMLStmt.DMLStmt")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *NonTransactionalDMLStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+
+	n = newNode.(*NonTransactionalDMLStmt)
+	if n.ShardColumn != nil {
+		node, ok := n.ShardColumn.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.ShardColumn = node.(*ColumnName)
+	}
+	node, ok := n.DMLStmt.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.DMLStmt = node.(DMLNode)
+	return v.Leave(n)
+}
+
 // UpdateStmt is a statement to update columns of existing rows in tables with new values.
 // See https://dev.mysql.com/doc/refman/5.7/en/update.html
 type UpdateStmt struct {
@@ -2582,6 +2823,7 @@ const (
 	ShowStatsHealthy
 	ShowHistogramsInFlight
 	ShowColumnStatsUsage
+	ShowStatsDump
 	ShowPlugins
 	ShowProfile
 	ShowProfiles
@@ -2605,6 +2847,8 @@ const (
 	ShowPlacementForTable
 	ShowPlacementForPartition
 	ShowPlacementLabels
+	ShowLastDMLErrors
+	ShowSessionStates
 )
 
 const (
@@ -2779,6 +3023,11 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 		if err := restoreShowLikeOrWhereOpt(); err != nil {
 			return err
 		}
+	case ShowStatsDump:
+		ctx.WriteKeyWord("STATS_DUMP ")
+		if err := n.Table.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ShowStmt.Table")
+		}
 	case ShowProfiles:
 		ctx.WriteKeyWord("PROFILES")
 	case ShowProfile:
@@ -2890,6 +3139,10 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 			ctx.WriteKeyWord("WARNINGS")
 		case ShowErrors:
 			ctx.WriteKeyWord("ERRORS")
+		case ShowLastDMLErrors:
+			ctx.WriteKeyWord("LAST DML ERRORS")
+		case ShowSessionStates:
+			ctx.WriteKeyWord("SESSION_STATES")
 		case ShowVariables:
 			restoreGlobalScope()
 			ctx.WriteKeyWord("VARIABLES")
@@ -3103,6 +3356,8 @@ type SelectIntoOption struct {
 	FileName   string
 	FieldsInfo *FieldsClause
 	LinesInfo  *LinesClause
+	// Compression is the algorithm compressing the file, it's empty if the file isn't compressed.
+	Compression string
 }
 
 // Restore implements Node interface.
@@ -3124,6 +3379,10 @@ func (n *SelectIntoOption) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotate(err, "An error occurred while restore SelectInto.LinesInfo")
 		}
 	}
+	if n.Compression != "" {
+		ctx.WriteKeyWord(" COMPRESSION ")
+		ctx.WriteString(n.Compression)
+	}
 	return nil
 }
 
@@ -3185,12 +3444,24 @@ const (
 	Groups
 )
 
+// FrameExclusion is the type of window function frame exclusion.
+type FrameExclusion int
+
+// Frame exclusion types.
+const (
+	ExcludeNoOthers FrameExclusion = iota
+	ExcludeCurrentRow
+	ExcludeGroup
+	ExcludeTies
+)
+
 // FrameClause represents frame clause.
 type FrameClause struct {
 	node
 
-	Type   FrameType
-	Extent FrameExtent
+	Type      FrameType
+	Extent    FrameExtent
+	Exclusion FrameExclusion
 }
 
 // Restore implements Node interface.
@@ -3211,6 +3482,14 @@ func (n *FrameClause) Restore(ctx *format.RestoreCtx) error {
 	if err := n.Extent.End.Restore(ctx); err != nil {
 		return errors.Annotate(err, "An error occurred while restore FrameClause.Extent.End")
 	}
+	switch n.Exclusion {
+	case ExcludeCurrentRow:
+		ctx.WriteKeyWord(" EXCLUDE CURRENT ROW")
+	case ExcludeGroup:
+		ctx.WriteKeyWord(" EXCLUDE GROUP")
+	case ExcludeTies:
+		ctx.WriteKeyWord(" EXCLUDE TIES")
+	}
 
 	return nil
 }
@@ -185,6 +185,7 @@ func TestTableSourceRestore(t *testing.T) {
 		{"tbl as t", "`tbl` AS `t`"},
 		{"(select * from tbl) as t", "(SELECT * FROM `tbl`) AS `t`"},
 		{"(select * from a union select * from b) as t", "(SELECT * FROM `a` UNION SELECT * FROM `b`) AS `t`"},
+		{"lateral (select * from tbl) as t", "LATERAL (SELECT * FROM `tbl`) AS `t`"},
 	}
 	extractNodeFunc := func(node Node) Node {
 		return node.(*SelectStmt).From.TableRefs.Left
@@ -294,6 +295,7 @@ func TestGroupByClauseRestore(t *testing.T) {
 	testCases := []NodeRestoreTestCase{
 		{"GROUP BY a,b desc", "GROUP BY `a`,`b` DESC"},
 		{"GROUP BY 1 desc,b", "GROUP BY 1 DESC,`b`"},
+		{"GROUP BY a,b with rollup", "GROUP BY `a`,`b` WITH ROLLUP"},
 	}
 	extractNodeFunc := func(node Node) Node {
 		return node.(*SelectStmt).GroupBy
@@ -160,6 +160,7 @@ const (
 	TimeFormat       = "time_format"
 	TimeToSec        = "time_to_sec"
 	TimeDiff         = "timediff"
+	TimeBucket       = "time_bucket"
 	Timestamp        = "timestamp"
 	TimestampLiteral = "'tidb`.(timestampliteral"
 	TimestampAdd     = "timestampadd"
@@ -211,6 +212,10 @@ const (
 	Ord             = "ord"
 	Position        = "position"
 	Quote           = "quote"
+	RegexpInStr     = "regexp_instr"
+	RegexpLike      = "regexp_like"
+	RegexpReplace   = "regexp_replace"
+	RegexpSubstr    = "regexp_substr"
 	Repeat          = "repeat"
 	Replace         = "replace"
 	Reverse         = "reverse"
@@ -269,10 +274,13 @@ const (
 	// miscellaneous functions
 	AnyValue        = "any_value"
 	DefaultFunc     = "default_func"
+	Grouping        = "grouping"
 	InetAton        = "inet_aton"
 	InetNtoa        = "inet_ntoa"
 	Inet6Aton       = "inet6_aton"
 	Inet6Ntoa       = "inet6_ntoa"
+	Inet6Normalize  = "inet6_normalize"
+	IPInRange       = "ip_in_range"
 	IsFreeLock      = "is_free_lock"
 	IsIPv4          = "is_ipv4"
 	IsIPv4Compat    = "is_ipv4_compat"
@@ -310,10 +318,22 @@ const (
 	SHA1                     = "sha1"
 	SHA                      = "sha"
 	SHA2                     = "sha2"
+	SM3                      = "sm3"
 	Uncompress               = "uncompress"
 	UncompressedLength       = "uncompressed_length"
 	ValidatePasswordStrength = "validate_password_strength"
 
+	// data masking functions
+	MaskInner      = "mask_inner"
+	MaskOuter      = "mask_outer"
+	MaskPAN        = "mask_pan"
+	MaskPANRelaxed = "mask_pan_relaxed"
+	MaskSSN        = "mask_ssn"
+
+	// vector functions
+	VecCosineDistance = "vec_cosine_distance"
+	VecL2Distance     = "vec_l2_distance"
+
 	// json functions
 	JSONType          = "json_type"
 	JSONExtract       = "json_extract"
@@ -326,6 +346,7 @@ const (
 	JSONReplace       = "json_replace"
 	JSONRemove        = "json_remove"
 	JSONContains      = "json_contains"
+	JSONMemberOf      = "json_memberof"
 	JSONContainsPath  = "json_contains_path"
 	JSONValid         = "json_valid"
 	JSONArrayAppend   = "json_array_append"
@@ -390,6 +411,19 @@ func (n *FuncCallExpr) Restore(ctx *format.RestoreCtx) error {
 		return nil
 	}
 
+	if n.FnName.L == JSONMemberOf && len(n.Args) == 2 {
+		if err := n.Args[0].Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore FuncCallExpr.Args[0]")
+		}
+		ctx.WriteKeyWord(" MEMBER OF ")
+		ctx.WritePlain("(")
+		if err := n.Args[1].Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore FuncCallExpr.Args[1]")
+		}
+		ctx.WritePlain(")")
+		return nil
+	}
+
 	if len(n.Schema.String()) != 0 {
 		ctx.WriteName(n.Schema.O)
 		ctx.WritePlain(".")
@@ -423,6 +457,21 @@ func (n *FuncCallExpr) Restore(ctx *format.RestoreCtx) error {
 		if err := n.Args[2].Restore(ctx); err != nil {
 			return errors.Annotatef(err, "An error occurred while restore FuncCallExpr.Args[2]")
 		}
+	case TimeBucket:
+		ctx.WriteKeyWord("INTERVAL ")
+		if err := n.Args[0].Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore FuncCallExpr.Args[0]")
+		}
+		ctx.WritePlain(" ")
+		if err := n.Args[1].Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore FuncCallExpr.Args[1]")
+		}
+		for i, argv := range n.Args[2:] {
+			ctx.WritePlain(", ")
+			if err := argv.Restore(ctx); err != nil {
+				return errors.Annotatef(err, "An error occurred while restore FuncCallExpr.Args[%d]", i+2)
+			}
+		}
 	case "extract":
 		if err := n.Args[0].Restore(ctx); err != nil {
 			return errors.Annotatef(err, "An error occurred while restore FuncCallExpr.Args[0]")
@@ -512,6 +561,16 @@ func (n *FuncCallExpr) specialFormatArgs(w io.Writer) bool {
 		fmt.Fprint(w, " ")
 		n.Args[2].Format(w)
 		return true
+	case TimeBucket:
+		fmt.Fprint(w, "INTERVAL ")
+		n.Args[0].Format(w)
+		fmt.Fprint(w, " ")
+		n.Args[1].Format(w)
+		for _, arg := range n.Args[2:] {
+			fmt.Fprint(w, ", ")
+			arg.Format(w)
+		}
+		return true
 	}
 	return false
 }
@@ -555,6 +614,9 @@ type FuncCastExpr struct {
 	FunctionType CastFunctionType
 	// ExplicitCharSet is true when charset is explicit indicated.
 	ExplicitCharSet bool
+	// Array is true when the expression is converted to an array of Tp, which is
+	// only allowed in the definition of a multi-valued index.
+	Array bool
 }
 
 // Restore implements Node interface.
@@ -568,6 +630,9 @@ func (n *FuncCastExpr) Restore(ctx *format.RestoreCtx) error {
 		}
 		ctx.WriteKeyWord(" AS ")
 		n.Tp.RestoreAsCastType(ctx, n.ExplicitCharSet)
+		if n.Array {
+			ctx.WriteKeyWord(" ARRAY")
+		}
 		ctx.WritePlain(")")
 	case CastConvertFunction:
 		ctx.WriteKeyWord("CONVERT")
@@ -64,6 +64,8 @@ func TestFuncCallExprRestore(t *testing.T) {
 		{"TRIM(TRAILING 'x' FROM 'xxxyxxx')", "TRIM(TRAILING _UTF8MB4'x' FROM _UTF8MB4'xxxyxxx')"},
 		{"TRIM(BOTH col1 FROM col2)", "TRIM(BOTH `col1` FROM `col2`)"},
 		{"DATE_ADD('2008-01-02', INTERVAL INTERVAL(1, 0, 1) DAY)", "DATE_ADD(_UTF8MB4'2008-01-02', INTERVAL INTERVAL(1, 0, 1) DAY)"},
+		{"TIME_BUCKET(INTERVAL 15 MINUTE, ts)", "TIME_BUCKET(INTERVAL 15 MINUTE, `ts`)"},
+		{"TIME_BUCKET(INTERVAL '1:30' HOUR_MINUTE, ts, '2000-01-01 00:15:00')", "TIME_BUCKET(INTERVAL _UTF8MB4'1:30' HOUR_MINUTE, `ts`, _UTF8MB4'2000-01-01 00:15:00')"},
 		{"BENCHMARK(1000000, AES_ENCRYPT('text', UNHEX('F3229A0B371ED2D9441B830D21A390C3')))", "BENCHMARK(1000000, AES_ENCRYPT(_UTF8MB4'text', UNHEX(_UTF8MB4'F3229A0B371ED2D9441B830D21A390C3')))"},
 		{"SUBSTRING('Quadratically', 5)", "SUBSTRING(_UTF8MB4'Quadratically', 5)"},
 		{"SUBSTRING('Quadratically' FROM 5)", "SUBSTRING(_UTF8MB4'Quadratically', 5)"},
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/parser/format"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
 )
 
 var (
@@ -43,6 +44,9 @@ var (
 	_ StmtNode = &RollbackStmt{}
 	_ StmtNode = &SetPwdStmt{}
 	_ StmtNode = &SetRoleStmt{}
+	_ StmtNode = &SetResourceGroupStmt{}
+	_ StmtNode = &SetSessionStatesStmt{}
+	_ StmtNode = &SetSessionAuthorizationStmt{}
 	_ StmtNode = &SetDefaultRoleStmt{}
 	_ StmtNode = &SetStmt{}
 	_ StmtNode = &UseStmt{}
@@ -50,6 +54,8 @@ var (
 	_ StmtNode = &KillStmt{}
 	_ StmtNode = &CreateBindingStmt{}
 	_ StmtNode = &DropBindingStmt{}
+	_ StmtNode = &CreateFunctionStmt{}
+	_ StmtNode = &DropFunctionStmt{}
 	_ StmtNode = &ShutdownStmt{}
 	_ StmtNode = &RestartStmt{}
 	_ StmtNode = &RenameUserStmt{}
@@ -609,11 +615,18 @@ type RollbackStmt struct {
 	stmtNode
 	// CompletionType overwrites system variable `completion_type` within transaction
 	CompletionType CompletionType
+	// SavepointName is the name of the savepoint to roll back to, it's empty if the whole transaction is rolled back.
+	SavepointName string
 }
 
 // Restore implements Node interface.
 func (n *RollbackStmt) Restore(ctx *format.RestoreCtx) error {
 	ctx.WriteKeyWord("ROLLBACK")
+	if n.SavepointName != "" {
+		ctx.WriteKeyWord(" TO ")
+		ctx.WriteName(n.SavepointName)
+		return nil
+	}
 	if err := n.CompletionType.Restore(ctx); err != nil {
 		return errors.Annotate(err, "An error occurred while restore RollbackStmt.CompletionType")
 	}
@@ -630,6 +643,56 @@ func (n *RollbackStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// SavepointStmt is the statement to set a named savepoint in the current transaction.
+// See https://dev.mysql.com/doc/refman/5.7/en/savepoint.html
+type SavepointStmt struct {
+	stmtNode
+
+	Name string
+}
+
+// Restore implements Node interface.
+func (n *SavepointStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("SAVEPOINT ")
+	ctx.WriteName(n.Name)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *SavepointStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*SavepointStmt)
+	return v.Leave(n)
+}
+
+// ReleaseSavepointStmt is the statement to remove a named savepoint from the current transaction.
+// See https://dev.mysql.com/doc/refman/5.7/en/savepoint.html
+type ReleaseSavepointStmt struct {
+	stmtNode
+
+	Name string
+}
+
+// Restore implements Node interface.
+func (n *ReleaseSavepointStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("RELEASE SAVEPOINT ")
+	ctx.WriteName(n.Name)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ReleaseSavepointStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ReleaseSavepointStmt)
+	return v.Leave(n)
+}
+
 // UseStmt is a statement to use the DBName database as the current database.
 // See https://dev.mysql.com/doc/refman/5.7/en/use.html
 type UseStmt struct {
@@ -856,11 +919,18 @@ type KillStmt struct {
 	// So, "KILL TIDB" grammar is introduced, and it REQUIRES DIRECT client -> TiDB TOPOLOGY.
 	// TODO: The standard KILL grammar will be supported once we have global connectionID.
 	TiDBExtension bool
+	// AnalyzeJobID is the id of the analyze job to be killed by "KILL ANALYZE jobID", it is 0 for other KILL statements.
+	AnalyzeJobID uint64
 }
 
 // Restore implements Node interface.
 func (n *KillStmt) Restore(ctx *format.RestoreCtx) error {
 	ctx.WriteKeyWord("KILL")
+	if n.AnalyzeJobID != 0 {
+		ctx.WriteKeyWord(" ANALYZE")
+		ctx.WritePlainf(" %d", n.AnalyzeJobID)
+		return nil
+	}
 	if n.TiDBExtension {
 		ctx.WriteKeyWord(" TIDB")
 	}
@@ -1050,6 +1120,85 @@ func (n *ChangeStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// SetResourceGroupStmt is a statement to bind the current session to a resource group.
+type SetResourceGroupStmt struct {
+	stmtNode
+
+	Name model.CIStr
+}
+
+// Restore implements Node interface.
+func (n *SetResourceGroupStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("SET RESOURCE GROUP ")
+	ctx.WriteName(n.Name.O)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *SetResourceGroupStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*SetResourceGroupStmt)
+	return v.Leave(n)
+}
+
+// SetSessionStatesStmt is a statement to restore the session states shown by `SHOW SESSION_STATES`.
+type SetSessionStatesStmt struct {
+	stmtNode
+
+	SessionStates string
+}
+
+// Restore implements Node interface.
+func (n *SetSessionStatesStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("SET SESSION_STATES ")
+	ctx.WriteString(n.SessionStates)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *SetSessionStatesStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*SetSessionStatesStmt)
+	return v.Leave(n)
+}
+
+// SetSessionAuthorizationStmt is a statement to switch the privileges of the current session to another user.
+// The session goes back to the login user if User is nil, which stands for `SET SESSION AUTHORIZATION DEFAULT`.
+type SetSessionAuthorizationStmt struct {
+	stmtNode
+
+	User *auth.UserIdentity
+}
+
+// Restore implements Node interface.
+func (n *SetSessionAuthorizationStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("SET SESSION AUTHORIZATION ")
+	if n.User == nil {
+		ctx.WriteKeyWord("DEFAULT")
+		return nil
+	}
+	if err := n.User.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore SetSessionAuthorizationStmt.User")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *SetSessionAuthorizationStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*SetSessionAuthorizationStmt)
+	return v.Leave(n)
+}
+
 // SetRoleStmtType is the type for FLUSH statement.
 type SetRoleStmtType int
 
@@ -1202,7 +1351,9 @@ func (n *UserSpec) EncodedPassword() (string, bool) {
 		switch opt.AuthPlugin {
 		case mysql.AuthCachingSha2Password:
 			return auth.NewSha2Password(opt.AuthString), true
-		case mysql.AuthSocket:
+		case mysql.AuthTiDBSM3Password:
+			return auth.NewSM3Password(opt.AuthString), true
+		case mysql.AuthSocket, mysql.AuthLDAPSimple, mysql.AuthLDAPSASL:
 			return "", true
 		default:
 			return auth.EncodePassword(opt.AuthString), true
@@ -1216,7 +1367,7 @@ func (n *UserSpec) EncodedPassword() (string, bool) {
 
 	// Not a legal password string.
 	switch opt.AuthPlugin {
-	case mysql.AuthCachingSha2Password:
+	case mysql.AuthCachingSha2Password, mysql.AuthTiDBSM3Password:
 		if len(opt.HashString) != mysql.SHAPWDHashLen {
 			return "", false
 		}
@@ -1224,7 +1375,8 @@ func (n *UserSpec) EncodedPassword() (string, bool) {
 		if len(opt.HashString) != (mysql.PWDHashLen+1) || !strings.HasPrefix(opt.HashString, "*") {
 			return "", false
 		}
-	case mysql.AuthSocket:
+	case mysql.AuthSocket, mysql.AuthLDAPSimple, mysql.AuthLDAPSASL:
+		// For LDAP plugins, the `AS` string is the DN of the user in the LDAP server.
 	default:
 		return "", false
 	}
@@ -1301,6 +1453,17 @@ func (r *ResourceOption) Restore(ctx *format.RestoreCtx) error {
 	return nil
 }
 
+// ResourceGroupNameOption is the `RESOURCE GROUP name` option of CREATE USER and ALTER USER statements.
+type ResourceGroupNameOption struct {
+	Value string
+}
+
+func (r *ResourceGroupNameOption) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("RESOURCE GROUP ")
+	ctx.WriteName(r.Value)
+	return nil
+}
+
 const (
 	PasswordExpire = iota + 1
 	PasswordExpireDefault
@@ -1308,6 +1471,13 @@ const (
 	PasswordExpireInterval
 	Lock
 	Unlock
+	FailedLoginAttempts
+	PasswordLockTime
+	PasswordLockTimeUnbounded
+	PasswordHistory
+	PasswordHistoryDefault
+	PasswordReuseInterval
+	PasswordReuseDefault
 )
 
 type PasswordOrLockOption struct {
@@ -1331,6 +1501,25 @@ func (p *PasswordOrLockOption) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("ACCOUNT LOCK")
 	case Unlock:
 		ctx.WriteKeyWord("ACCOUNT UNLOCK")
+	case FailedLoginAttempts:
+		ctx.WriteKeyWord("FAILED_LOGIN_ATTEMPTS")
+		ctx.WritePlainf(" %d", p.Count)
+	case PasswordLockTime:
+		ctx.WriteKeyWord("PASSWORD_LOCK_TIME")
+		ctx.WritePlainf(" %d", p.Count)
+	case PasswordLockTimeUnbounded:
+		ctx.WriteKeyWord("PASSWORD_LOCK_TIME UNBOUNDED")
+	case PasswordHistory:
+		ctx.WriteKeyWord("PASSWORD HISTORY")
+		ctx.WritePlainf(" %d", p.Count)
+	case PasswordHistoryDefault:
+		ctx.WriteKeyWord("PASSWORD HISTORY DEFAULT")
+	case PasswordReuseInterval:
+		ctx.WriteKeyWord("PASSWORD REUSE INTERVAL")
+		ctx.WritePlainf(" %d", p.Count)
+		ctx.WriteKeyWord(" DAY")
+	case PasswordReuseDefault:
+		ctx.WriteKeyWord("PASSWORD REUSE INTERVAL DEFAULT")
 	default:
 		return errors.Errorf("Unsupported PasswordOrLockOption.Type %d", p.Type)
 	}
@@ -1348,6 +1537,8 @@ type CreateUserStmt struct {
 	TLSOptions            []*TLSOption
 	ResourceOptions       []*ResourceOption
 	PasswordOrLockOptions []*PasswordOrLockOption
+	// ResourceGroupNameOption is nil if the resource group is not specified.
+	ResourceGroupNameOption *ResourceGroupNameOption
 }
 
 // Restore implements Node interface.
@@ -1399,6 +1590,13 @@ func (n *CreateUserStmt) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotatef(err, "An error occurred while restore CreateUserStmt.PasswordOrLockOptions[%d]", i)
 		}
 	}
+
+	if n.ResourceGroupNameOption != nil {
+		ctx.WritePlain(" ")
+		if err := n.ResourceGroupNameOption.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore CreateUserStmt.ResourceGroupNameOption")
+		}
+	}
 	return nil
 }
 
@@ -1434,6 +1632,8 @@ type AlterUserStmt struct {
 	TLSOptions            []*TLSOption
 	ResourceOptions       []*ResourceOption
 	PasswordOrLockOptions []*PasswordOrLockOption
+	// ResourceGroupNameOption is nil if the resource group is not specified.
+	ResourceGroupNameOption *ResourceGroupNameOption
 }
 
 // Restore implements Node interface.
@@ -1488,6 +1688,13 @@ func (n *AlterUserStmt) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotatef(err, "An error occurred while restore AlterUserStmt.PasswordOrLockOptions[%d]", i)
 		}
 	}
+
+	if n.ResourceGroupNameOption != nil {
+		ctx.WritePlain(" ")
+		if err := n.ResourceGroupNameOption.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore AlterUserStmt.ResourceGroupNameOption")
+		}
+	}
 	return nil
 }
 
@@ -1519,6 +1726,8 @@ type AlterInstanceStmt struct {
 
 	ReloadTLS         bool
 	NoRollbackOnError bool
+	// RotateMasterKey rotates the master keys of the encryption at rest of the storage nodes.
+	RotateMasterKey bool
 }
 
 // Restore implements Node interface.
@@ -1530,6 +1739,9 @@ func (n *AlterInstanceStmt) Restore(ctx *format.RestoreCtx) error {
 	if n.NoRollbackOnError {
 		ctx.WriteKeyWord(" NO ROLLBACK ON ERROR")
 	}
+	if n.RotateMasterKey {
+		ctx.WriteKeyWord(" ROTATE MASTER KEY")
+	}
 	return nil
 }
 
@@ -1584,6 +1796,100 @@ func (n *DropUserStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// FunctionParam is a parameter of the user defined function.
+type FunctionParam struct {
+	Name model.CIStr
+	Tp   *types.FieldType
+}
+
+// CreateFunctionStmt creates a user defined scalar function.
+type CreateFunctionStmt struct {
+	stmtNode
+
+	IfNotExists bool
+	FuncName    model.CIStr
+	Params      []*FunctionParam
+	ReturnType  *types.FieldType
+	Language    string
+	// Body is the literal of the function implementation, such as the binary of a WebAssembly module.
+	Body ExprNode
+}
+
+// Restore implements Node interface.
+func (n *CreateFunctionStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CREATE FUNCTION ")
+	if n.IfNotExists {
+		ctx.WriteKeyWord("IF NOT EXISTS ")
+	}
+	ctx.WriteName(n.FuncName.O)
+	ctx.WritePlain("(")
+	for i, param := range n.Params {
+		if i != 0 {
+			ctx.WritePlain(", ")
+		}
+		ctx.WriteName(param.Name.O)
+		ctx.WritePlain(" ")
+		if err := param.Tp.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore CreateFunctionStmt.Params[%d]", i)
+		}
+	}
+	ctx.WritePlain(")")
+	ctx.WriteKeyWord(" RETURNS ")
+	if err := n.ReturnType.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateFunctionStmt.ReturnType")
+	}
+	ctx.WriteKeyWord(" LANGUAGE ")
+	ctx.WriteKeyWord(n.Language)
+	ctx.WriteKeyWord(" AS ")
+	if err := n.Body.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateFunctionStmt.Body")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateFunctionStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateFunctionStmt)
+	node, ok := n.Body.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Body = node.(ExprNode)
+	return v.Leave(n)
+}
+
+// DropFunctionStmt drops a user defined function.
+type DropFunctionStmt struct {
+	stmtNode
+
+	IfExists bool
+	FuncName model.CIStr
+}
+
+// Restore implements Node interface.
+func (n *DropFunctionStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DROP FUNCTION ")
+	if n.IfExists {
+		ctx.WriteKeyWord("IF EXISTS ")
+	}
+	ctx.WriteName(n.FuncName.O)
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *DropFunctionStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*DropFunctionStmt)
+	return v.Leave(n)
+}
+
 // CreateBindingStmt creates sql binding hint.
 type CreateBindingStmt struct {
 	stmtNode
@@ -2512,6 +2818,42 @@ func (n *GrantProxyStmt) Restore(ctx *format.RestoreCtx) error {
 	return nil
 }
 
+// RevokeProxyStmt is the struct for REVOKE PROXY statement.
+type RevokeProxyStmt struct {
+	stmtNode
+
+	LocalUser     *auth.UserIdentity
+	ExternalUsers []*auth.UserIdentity
+}
+
+// Accept implements Node Accept interface.
+func (n *RevokeProxyStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*RevokeProxyStmt)
+	return v.Leave(n)
+}
+
+// Restore implements Node interface.
+func (n *RevokeProxyStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("REVOKE PROXY ON ")
+	if err := n.LocalUser.Restore(ctx); err != nil {
+		return errors.Annotatef(err, "An error occurred while restore RevokeProxyStmt.LocalUser")
+	}
+	ctx.WriteKeyWord(" FROM ")
+	for i, v := range n.ExternalUsers {
+		if i != 0 {
+			ctx.WritePlain(", ")
+		}
+		if err := v.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore RevokeProxyStmt.ExternalUsers[%d]", i)
+		}
+	}
+	return nil
+}
+
 // GrantRoleStmt is the struct for GRANT TO statement.
 type GrantRoleStmt struct {
 	stmtNode
@@ -237,15 +237,24 @@ func (n *DropStatsStmt) Accept(v Visitor) (Node, bool) {
 }
 
 // LoadStatsStmt is the statement node for loading statistic.
+// LOAD STATS 'file_path'
+// LOAD STATS FROM 'json'
 type LoadStatsStmt struct {
 	stmtNode
 
 	Path string
+	// JSON is the statistics dumped by `SHOW STATS_DUMP`, it's loaded directly instead of reading a file.
+	JSON string
 }
 
 // Restore implements Node interface.
 func (n *LoadStatsStmt) Restore(ctx *format.RestoreCtx) error {
 	ctx.WriteKeyWord("LOAD STATS ")
+	if len(n.JSON) > 0 {
+		ctx.WriteKeyWord("FROM ")
+		ctx.WriteString(n.JSON)
+		return nil
+	}
 	ctx.WriteString(n.Path)
 	return nil
 }
@@ -259,3 +268,77 @@ func (n *LoadStatsStmt) Accept(v Visitor) (Node, bool) {
 	n = newNode.(*LoadStatsStmt)
 	return v.Leave(n)
 }
+
+// LockStatsStmt is the statement node for locking the statistics of tables.
+// LOCK STATS t1, t2
+type LockStatsStmt struct {
+	stmtNode
+
+	Tables []*TableName
+}
+
+// Restore implements Node interface.
+func (n *LockStatsStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("LOCK STATS ")
+	return restoreStatsTables(ctx, n.Tables)
+}
+
+// Accept implements Node Accept interface.
+func (n *LockStatsStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*LockStatsStmt)
+	for i, t := range n.Tables {
+		node, ok := t.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Tables[i] = node.(*TableName)
+	}
+	return v.Leave(n)
+}
+
+// UnlockStatsStmt is the statement node for unlocking the statistics of tables.
+// UNLOCK STATS t1, t2
+type UnlockStatsStmt struct {
+	stmtNode
+
+	Tables []*TableName
+}
+
+// Restore implements Node interface.
+func (n *UnlockStatsStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("UNLOCK STATS ")
+	return restoreStatsTables(ctx, n.Tables)
+}
+
+// Accept implements Node Accept interface.
+func (n *UnlockStatsStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*UnlockStatsStmt)
+	for i, t := range n.Tables {
+		node, ok := t.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Tables[i] = node.(*TableName)
+	}
+	return v.Leave(n)
+}
+
+func restoreStatsTables(ctx *format.RestoreCtx, tables []*TableName) error {
+	for i, t := range tables {
+		if i != 0 {
+			ctx.WritePlain(", ")
+		}
+		if err := t.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore LockStatsStmt.Tables[%d]", i)
+		}
+	}
+	return nil
+}
@@ -37,6 +37,7 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"hash"
 	"strconv"
 )
 
@@ -46,6 +47,12 @@ const (
 	ITERATION_MULTIPLIER = 1000
 )
 
+// The digest types of the authentication strings, the hash algorithm is the only difference between them.
+const (
+	sha256DigestType = 'A'
+	sm3DigestType    = 'B'
+)
+
 func b64From24bit(b []byte, n int) []byte {
 	b64t := []byte("./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz")
 
@@ -60,16 +67,17 @@ func b64From24bit(b []byte, n int) []byte {
 	return ret
 }
 
-func sha256crypt(plaintext string, salt []byte, iterations int) string {
+// hashCrypt implements the SHA-crypt algorithm with a hash function whose checksum is 32 bytes.
+func hashCrypt(plaintext string, salt []byte, iterations int, digestType byte, newHash func() hash.Hash) string {
 	// Numbers in the comments refer to the description of the algorithm on https://www.akkadia.org/drepper/SHA-crypt.txt
 
 	// 1, 2, 3
-	tmpA := sha256.New()
+	tmpA := newHash()
 	tmpA.Write([]byte(plaintext))
 	tmpA.Write(salt)
 
 	// 4, 5, 6, 7, 8
-	tmpB := sha256.New()
+	tmpB := newHash()
 	tmpB.Write([]byte(plaintext))
 	tmpB.Write(salt)
 	tmpB.Write([]byte(plaintext))
@@ -95,7 +103,7 @@ func sha256crypt(plaintext string, salt []byte, iterations int) string {
 	sumA := tmpA.Sum(nil)
 
 	// 13, 14, 15
-	tmpDP := sha256.New()
+	tmpDP := newHash()
 	for range []byte(plaintext) {
 		tmpDP.Write([]byte(plaintext))
 	}
@@ -112,7 +120,7 @@ func sha256crypt(plaintext string, salt []byte, iterations int) string {
 	}
 
 	// 17, 18, 19
-	tmpDS := sha256.New()
+	tmpDS := newHash()
 	for i = 0; i < 16+int(sumA[0]); i++ {
 		tmpDS.Write(salt)
 	}
@@ -129,7 +137,7 @@ func sha256crypt(plaintext string, salt []byte, iterations int) string {
 	}
 
 	// 21
-	tmpC := sha256.New()
+	tmpC := newHash()
 	var sumC []byte
 	for i = 0; i < iterations; i++ {
 		tmpC.Reset()
@@ -157,7 +165,7 @@ func sha256crypt(plaintext string, salt []byte, iterations int) string {
 	// 22
 	buf := bytes.Buffer{}
 	buf.Grow(100) // FIXME
-	buf.Write([]byte{'$', 'A', '$'})
+	buf.Write([]byte{'$', digestType, '$'})
 	rounds := fmt.Sprintf("%03d", iterations/ITERATION_MULTIPLIER)
 	buf.Write([]byte(rounds))
 	buf.Write([]byte{'$'})
@@ -180,13 +188,17 @@ func sha256crypt(plaintext string, salt []byte, iterations int) string {
 
 // Checks if a MySQL style caching_sha2 authentication string matches a password
 func CheckShaPassword(pwhash []byte, password string) (bool, error) {
+	return checkHashingPassword(pwhash, password, sha256DigestType, sha256.New)
+}
+
+func checkHashingPassword(pwhash []byte, password string, digestType byte, newHash func() hash.Hash) (bool, error) {
 	pwhash_parts := bytes.Split(pwhash, []byte("$"))
 	if len(pwhash_parts) != 4 {
 		return false, errors.New("failed to decode hash parts")
 	}
 
 	hash_type := string(pwhash_parts[1])
-	if hash_type != "A" {
+	if hash_type != string(digestType) {
 		return false, errors.New("digest type is incompatible")
 	}
 
@@ -197,12 +209,16 @@ func CheckShaPassword(pwhash []byte, password string) (bool, error) {
 	iterations = iterations * ITERATION_MULTIPLIER
 	salt := pwhash_parts[3][:SALT_LENGTH]
 
-	newHash := sha256crypt(password, salt, iterations)
+	newPwhash := hashCrypt(password, salt, iterations, digestType, newHash)
 
-	return bytes.Equal(pwhash, []byte(newHash)), nil
+	return bytes.Equal(pwhash, []byte(newPwhash)), nil
 }
 
 func NewSha2Password(pwd string) string {
+	return newHashPassword(pwd, sha256DigestType, sha256.New)
+}
+
+func newHashPassword(pwd string, digestType byte, newHash func() hash.Hash) string {
 	salt := make([]byte, SALT_LENGTH)
 	rand.Read(salt)
 
@@ -216,5 +232,5 @@ func NewSha2Password(pwd string) string {
 		}
 	}
 
-	return sha256crypt(pwd, salt, 5*ITERATION_MULTIPLIER)
+	return hashCrypt(pwd, salt, 5*ITERATION_MULTIPLIER, digestType, newHash)
 }
@@ -0,0 +1,180 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+// Resources:
+// - GB/T 32905-2016, the SM3 cryptographic hash algorithm
+// - https://datatracker.ietf.org/doc/html/draft-sca-cfrg-sm3-02
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+const (
+	// SM3Size is the size of an SM3 checksum in bytes.
+	SM3Size = 32
+	// SM3BlockSize is the block size of SM3 in bytes.
+	SM3BlockSize = 64
+)
+
+var sm3IV = [8]uint32{0x7380166f, 0x4914b2b9, 0x172442d7, 0xda8a0600, 0xa96f30bc, 0x163138aa, 0xe38dee4d, 0xb0fb0e4e}
+
+type sm3 struct {
+	h   [8]uint32
+	x   [SM3BlockSize]byte
+	nx  int
+	len uint64
+}
+
+// NewSM3 returns a new hash.Hash computing the SM3 checksum.
+func NewSM3() hash.Hash {
+	d := &sm3{}
+	d.Reset()
+	return d
+}
+
+// SM3 returns the SM3 checksum of the data.
+func SM3(data []byte) [SM3Size]byte {
+	d := &sm3{}
+	d.Reset()
+	d.Write(data)
+	var sum [SM3Size]byte
+	d.checkSum(sum[:0])
+	return sum
+}
+
+func (d *sm3) Reset() {
+	d.h = sm3IV
+	d.nx = 0
+	d.len = 0
+}
+
+func (d *sm3) Size() int { return SM3Size }
+
+func (d *sm3) BlockSize() int { return SM3BlockSize }
+
+func (d *sm3) Write(p []byte) (int, error) {
+	n := len(p)
+	d.len += uint64(n)
+	if d.nx > 0 {
+		c := copy(d.x[d.nx:], p)
+		d.nx += c
+		if d.nx == SM3BlockSize {
+			d.block(d.x[:])
+			d.nx = 0
+		}
+		p = p[c:]
+	}
+	for len(p) >= SM3BlockSize {
+		d.block(p[:SM3BlockSize])
+		p = p[SM3BlockSize:]
+	}
+	if len(p) > 0 {
+		d.nx = copy(d.x[:], p)
+	}
+	return n, nil
+}
+
+func (d *sm3) Sum(in []byte) []byte {
+	// Make a copy so that the caller can keep writing and summing.
+	d0 := *d
+	return d0.checkSum(in)
+}
+
+func (d *sm3) checkSum(in []byte) []byte {
+	// The padding is the same as SHA-256: 0x80, zeros, and the length in bits as a big-endian uint64.
+	length := d.len
+	var tmp [SM3BlockSize + 8]byte
+	tmp[0] = 0x80
+	padLen := 56 - length%SM3BlockSize
+	if length%SM3BlockSize >= 56 {
+		padLen += SM3BlockSize
+	}
+	binary.BigEndian.PutUint64(tmp[padLen:], length<<3)
+	d.Write(tmp[:padLen+8])
+
+	var digest [SM3Size]byte
+	for i, v := range d.h {
+		binary.BigEndian.PutUint32(digest[i*4:], v)
+	}
+	return append(in, digest[:]...)
+}
+
+func sm3P0(x uint32) uint32 {
+	return x ^ bits.RotateLeft32(x, 9) ^ bits.RotateLeft32(x, 17)
+}
+
+func sm3P1(x uint32) uint32 {
+	return x ^ bits.RotateLeft32(x, 15) ^ bits.RotateLeft32(x, 23)
+}
+
+// block compresses a 64 bytes block into the state.
+func (d *sm3) block(p []byte) {
+	var w [68]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(p[i*4:])
+	}
+	for j := 16; j < 68; j++ {
+		w[j] = sm3P1(w[j-16]^w[j-9]^bits.RotateLeft32(w[j-3], 15)) ^ bits.RotateLeft32(w[j-13], 7) ^ w[j-6]
+	}
+
+	a, b, c, dd, e, f, g, h := d.h[0], d.h[1], d.h[2], d.h[3], d.h[4], d.h[5], d.h[6], d.h[7]
+	for j := 0; j < 64; j++ {
+		var t, ff, gg uint32
+		if j < 16 {
+			t = 0x79cc4519
+			ff = a ^ b ^ c
+			gg = e ^ f ^ g
+		} else {
+			t = 0x7a879d8a
+			ff = (a & b) | (a & c) | (b & c)
+			gg = (e & f) | (^e & g)
+		}
+		a12 := bits.RotateLeft32(a, 12)
+		ss1 := bits.RotateLeft32(a12+e+bits.RotateLeft32(t, j%32), 7)
+		ss2 := ss1 ^ a12
+		tt1 := ff + dd + ss2 + (w[j] ^ w[j+4])
+		tt2 := gg + h + ss1 + w[j]
+		dd = c
+		c = bits.RotateLeft32(b, 9)
+		b = a
+		a = tt1
+		h = g
+		g = bits.RotateLeft32(f, 19)
+		f = e
+		e = sm3P0(tt2)
+	}
+	d.h[0] ^= a
+	d.h[1] ^= b
+	d.h[2] ^= c
+	d.h[3] ^= dd
+	d.h[4] ^= e
+	d.h[5] ^= f
+	d.h[6] ^= g
+	d.h[7] ^= h
+}
+
+// CheckSM3Password checks if a tidb_sm3_password authentication string matches a password.
+func CheckSM3Password(pwhash []byte, password string) (bool, error) {
+	return checkHashingPassword(pwhash, password, sm3DigestType, NewSM3)
+}
+
+// NewSM3Password returns the tidb_sm3_password authentication string of the password, which is in the same format
+// as caching_sha2_password but hashed with SM3.
+func NewSM3Password(pwd string) string {
+	return newHashPassword(pwd, sm3DigestType, NewSM3)
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSM3(t *testing.T) {
+	// The examples in GB/T 32905-2016.
+	sum := SM3([]byte("abc"))
+	require.Equal(t, "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0", hex.EncodeToString(sum[:]))
+	sum = SM3([]byte(strings.Repeat("abcd", 16)))
+	require.Equal(t, "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732", hex.EncodeToString(sum[:]))
+
+	// Writing in pieces gets the same checksum.
+	h := NewSM3()
+	for i := 0; i < 16; i++ {
+		_, err := h.Write([]byte("abcd"))
+		require.NoError(t, err)
+	}
+	require.Equal(t, sum[:], h.Sum(nil))
+	require.Equal(t, sum[:], h.Sum(nil))
+	h.Reset()
+	_, err := h.Write([]byte("abc"))
+	require.NoError(t, err)
+	require.Equal(t, "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0", hex.EncodeToString(h.Sum(nil)))
+}
+
+func TestNewSM3Password(t *testing.T) {
+	pwd := "testpwd"
+	pwhash := NewSM3Password(pwd)
+	require.Len(t, pwhash, 70)
+	require.True(t, strings.HasPrefix(pwhash, "$B$005$"))
+	r, err := CheckSM3Password([]byte(pwhash), pwd)
+	require.NoError(t, err)
+	require.True(t, r)
+	r, err = CheckSM3Password([]byte(pwhash), "not_testpwd")
+	require.NoError(t, err)
+	require.False(t, r)
+
+	// The authentication strings of caching_sha2_password and tidb_sm3_password can't be mixed up.
+	_, err = CheckShaPassword([]byte(pwhash), pwd)
+	require.Error(t, err)
+	_, err = CheckSM3Password([]byte(NewSha2Password(pwd)), pwd)
+	require.Error(t, err)
+}
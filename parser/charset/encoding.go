@@ -21,6 +21,7 @@ var (
 	_ Encoding = &encodingLatin1{}
 	_ Encoding = &encodingBin{}
 	_ Encoding = &encodingGBK{}
+	_ Encoding = &encodingGB18030{}
 )
 
 // IsSupportedEncoding checks if the charset is fully supported.
@@ -44,6 +45,7 @@ var encodingMap = map[string]Encoding{
 	CharsetUTF8MB4: EncodingUTF8Impl,
 	CharsetUTF8:    EncodingUTF8Impl,
 	CharsetGBK:     EncodingGBKImpl,
+	CharsetGB18030: EncodingGB18030Impl,
 	CharsetLatin1:  EncodingLatin1Impl,
 	CharsetBin:     EncodingBinImpl,
 	CharsetASCII:   EncodingASCIIImpl,
@@ -79,6 +81,7 @@ const (
 	EncodingTpLatin1
 	EncodingTpBin
 	EncodingTpGBK
+	EncodingTpGB18030
 )
 
 // Op is used by Encoding.Transform.
@@ -0,0 +1,58 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package charset
+
+import (
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// EncodingGB18030Impl is the instance of encodingGB18030
+var EncodingGB18030Impl = &encodingGB18030{encodingBase{enc: simplifiedchinese.GB18030}}
+
+func init() {
+	EncodingGB18030Impl.self = EncodingGB18030Impl
+}
+
+// encodingGB18030 is GB18030 encoding, which is a superset of GBK and covers all the unicode characters.
+type encodingGB18030 struct {
+	encodingBase
+}
+
+// Name implements Encoding interface.
+func (e *encodingGB18030) Name() string {
+	return CharsetGB18030
+}
+
+// Tp implements Encoding interface.
+func (e *encodingGB18030) Tp() EncodingTp {
+	return EncodingTpGB18030
+}
+
+// Peek implements Encoding interface.
+func (e *encodingGB18030) Peek(src []byte) []byte {
+	charLen := 2
+	if len(src) == 0 || src[0] < 0x80 {
+		// A byte in the range 00–7F is a single byte that means the same thing as it does in ASCII.
+		charLen = 1
+	} else if len(src) > 1 && src[1] >= 0x30 && src[1] <= 0x39 {
+		// A four-byte character has a digit as its second byte, while the second byte of
+		// a two-byte character is in the range 40–7E or 80–FE.
+		charLen = 4
+	}
+	if charLen < len(src) {
+		return src[:charLen]
+	}
+	return src
+}
@@ -88,6 +88,40 @@ func TestEncoding(t *testing.T) {
 	}
 }
 
+func TestEncodingGB18030(t *testing.T) {
+	enc := charset.FindEncoding(charset.CharsetGB18030)
+	require.Equal(t, charset.CharsetGB18030, enc.Name())
+
+	testCases := []struct {
+		utf8Str string
+		encoded string
+	}{
+		{"abc", "abc"},
+		{"一二三", "\xd2\xbb\xb6\xfe\xc8\xfd"},
+		{"À", "\x81\x30\x86\x38"},
+		{"😂", "\x94\x39\xfc\x38"},
+		{"a中À😂", "a\xd6\xd0\x81\x30\x86\x38\x94\x39\xfc\x38"},
+	}
+	for _, tc := range testCases {
+		cmt := fmt.Sprintf("%v", tc)
+		encoded, err := enc.Transform(nil, []byte(tc.utf8Str), charset.OpEncode)
+		require.NoError(t, err, cmt)
+		require.Equal(t, tc.encoded, string(encoded), cmt)
+		decoded, err := enc.Transform(nil, encoded, charset.OpDecode)
+		require.NoError(t, err, cmt)
+		require.Equal(t, tc.utf8Str, string(decoded), cmt)
+	}
+
+	// The four-byte characters are peeked as a whole.
+	require.Equal(t, []byte{0x81, 0x30, 0x86, 0x38}, enc.Peek([]byte{0x81, 0x30, 0x86, 0x38, 0x61}))
+	require.Equal(t, []byte{0xd6, 0xd0}, enc.Peek([]byte{0xd6, 0xd0, 0x61}))
+	require.Equal(t, []byte{0x61}, enc.Peek([]byte{0x61, 0xd6, 0xd0}))
+
+	_, err := enc.Transform(nil, []byte{0x81, 0x30, 0xff, 0xff}, charset.OpDecode)
+	require.Error(t, err)
+	require.Equal(t, "ÀÉ", enc.ToUpper("àé"))
+}
+
 func TestEncodingValidate(t *testing.T) {
 	oxfffefd := string([]byte{0xff, 0xfe, 0xfd})
 	testCases := []struct {
@@ -125,6 +159,11 @@ func TestEncodingValidate(t *testing.T) {
 		{charset.CharsetGBK, "À", "?", 0, false},
 		{charset.CharsetGBK, "中文À中文", "中文?中文", 6, false},
 		{charset.CharsetGBK, "asdfÀ", "asdf?", 4, false},
+		{charset.CharsetGB18030, "", "", 0, true},
+		{charset.CharsetGB18030, "asdf", "asdf", 4, true},
+		{charset.CharsetGB18030, "中文", "中文", 6, true},
+		{charset.CharsetGB18030, "À😂", "À😂", 6, true},
+		{charset.CharsetGB18030, "中文" + oxfffefd, "中文?", 6, false},
 	}
 	for _, tc := range testCases {
 		msg := fmt.Sprintf("%v", tc)
@@ -175,6 +175,16 @@ func (s *Scanner) getNextToken() int {
 	return tok
 }
 
+// getNextTwoTokens returns the next two tokens without consuming them.
+func (s *Scanner) getNextTwoTokens() (tok1 int, tok2 int) {
+	r := s.r
+	tok1 = s.getNextToken()
+	s.scan()
+	tok2 = s.getNextToken()
+	s.r = r
+	return tok1, tok2
+}
+
 // Lex returns a token and store the token value in v.
 // Scanner satisfies yyLexer interface.
 // 0 and invalid are special token id this function would return:
@@ -218,6 +228,19 @@ func (s *Scanner) Lex(v *yySymType) int {
 		v.offset = pos.Offset
 		return asof
 	}
+	// "WITH ROLLUP" is scanned as one token to avoid the conflict with the WITH clause after GROUP BY, e.g.
+	// `CREATE VIEW v AS SELECT a FROM t GROUP BY a WITH CASCADED CHECK OPTION`. It isn't combined when ROLLUP is
+	// the name of a common table expression.
+	if tok == with {
+		if tok1, tok2 := s.getNextTwoTokens(); tok1 == rollup && tok2 != as && tok2 != '(' {
+			_, pos, lit = s.scan()
+			v.ident = fmt.Sprintf("%s %s", v.ident, lit)
+			s.lastKeyword = withRollup
+			s.lastScanOffset = pos.Offset
+			v.offset = pos.Offset
+			return withRollup
+		}
+	}
 
 	switch tok {
 	case intLit:
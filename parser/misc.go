@@ -155,8 +155,10 @@ var tokenMap = map[string]int{
 	"APPROX_PERCENTILE":        approxPercentile,
 	"AS":                       as,
 	"ASC":                      asc,
+	"ARRAY":                    array,
 	"ASCII":                    ascii,
 	"ATTRIBUTES":               attributes,
+	"AUTHORIZATION":            authorization,
 	"STATS_OPTIONS":            statsOptions,
 	"STATS_SAMPLE_RATE":        statsSampleRate,
 	"STATS_COL_CHOICE":         statsColChoice,
@@ -170,6 +172,7 @@ var tokenMap = map[string]int{
 	"BACKEND":                  backend,
 	"BACKUP":                   backup,
 	"BACKUPS":                  backups,
+	"BATCH":                    batch,
 	"BEGIN":                    begin,
 	"BETWEEN":                  between,
 	"BERNOULLI":                bernoulli,
@@ -192,6 +195,7 @@ var tokenMap = map[string]int{
 	"BTREE":                    btree,
 	"BUCKETS":                  buckets,
 	"BUILTINS":                 builtins,
+	"BURSTABLE":                burstable,
 	"BY":                       by,
 	"BYTE":                     byteType,
 	"CACHE":                    cache,
@@ -240,6 +244,7 @@ var tokenMap = map[string]int{
 	"CONSTRAINTS":              constraints,
 	"CONTEXT":                  context,
 	"CONVERT":                  convert,
+	"COOLDOWN":                 cooldown,
 	"COPY":                     copyKwd,
 	"CORRELATION":              correlation,
 	"CPU":                      cpu,
@@ -292,6 +297,7 @@ var tokenMap = map[string]int{
 	"DISTINCT":                 distinct,
 	"DISTINCTROW":              distinct,
 	"DIV":                      div,
+	"DML":                      dml,
 	"DO":                       do,
 	"DOT":                      dotType,
 	"DOUBLE":                   doubleType,
@@ -299,9 +305,12 @@ var tokenMap = map[string]int{
 	"DROP":                     drop,
 	"DUAL":                     dual,
 	"DUMP":                     dump,
+	"DRY":                      dry,
 	"DUPLICATE":                duplicate,
 	"DYNAMIC":                  dynamic,
+	"EGRESS_BYTES_PER_SEC":     egressBytesPerSec,
 	"ELSE":                     elseKwd,
+	"EMPTY":                    emptyKwd,
 	"ENABLE":                   enable,
 	"ENCLOSED":                 enclosed,
 	"ENCRYPTION":               encryption,
@@ -320,7 +329,9 @@ var tokenMap = map[string]int{
 	"EXACT":                    exact,
 	"EXCEPT":                   except,
 	"EXCHANGE":                 exchange,
+	"EXCLUDE":                  exclude,
 	"EXCLUSIVE":                exclusive,
+	"EXEC_ELAPSED":             execElapsed,
 	"EXECUTE":                  execute,
 	"EXISTS":                   exists,
 	"EXPANSION":                expansion,
@@ -328,6 +339,8 @@ var tokenMap = map[string]int{
 	"EXPLAIN":                  explain,
 	"EXPR_PUSHDOWN_BLACKLIST":  exprPushdownBlacklist,
 	"EXTENDED":                 extended,
+	"EXTERNAL":                 external,
+	"FAILED_LOGIN_ATTEMPTS":    failedLoginAttempts,
 	"EXTRACT":                  extract,
 	"FALSE":                    falseKwd,
 	"FAULTS":                   faultsSym,
@@ -351,6 +364,7 @@ var tokenMap = map[string]int{
 	"FULL":                     full,
 	"FULLTEXT":                 fulltext,
 	"FUNCTION":                 function,
+	"GC_LIFE_TIME":             gcLifeTime,
 	"GENERAL":                  general,
 	"GENERATED":                generated,
 	"GET_FORMAT":               getFormat,
@@ -410,6 +424,7 @@ var tokenMap = map[string]int{
 	"JOIN":                     join,
 	"JSON_ARRAYAGG":            jsonArrayagg,
 	"JSON_OBJECTAGG":           jsonObjectAgg,
+	"JSON_TABLE":               jsonTable,
 	"JSON":                     jsonType,
 	"KEY_BLOCK_SIZE":           keyBlockSize,
 	"KEY":                      key,
@@ -420,6 +435,7 @@ var tokenMap = map[string]int{
 	"LAST_BACKUP":              lastBackup,
 	"LAST":                     last,
 	"LASTVAL":                  lastval,
+	"LATERAL":                  lateral,
 	"LEADER":                   leader,
 	"LEADER_CONSTRAINTS":       leaderConstraints,
 	"LEADING":                  leading,
@@ -461,6 +477,7 @@ var tokenMap = map[string]int{
 	"MEDIUMBLOB":               mediumblobType,
 	"MEDIUMINT":                mediumIntType,
 	"MEDIUMTEXT":               mediumtextType,
+	"MEMBER":                   member,
 	"MEMORY":                   memory,
 	"MERGE":                    merge,
 	"MICROSECOND":              microsecond,
@@ -478,6 +495,7 @@ var tokenMap = map[string]int{
 	"NATIONAL":                 national,
 	"NATURAL":                  natural,
 	"NCHAR":                    ncharType,
+	"NESTED":                   nested,
 	"NEVER":                    never,
 	"NEXT_ROW_ID":              next_row_id,
 	"NEXT":                     next,
@@ -513,7 +531,9 @@ var tokenMap = map[string]int{
 	"OPTIMIZE":                 optimize,
 	"OPTION":                   option,
 	"OPTIONAL":                 optional,
+	"ORDINALITY":               ordinality,
 	"OPTIONALLY":               optionally,
+	"OTHERS":                   others,
 	"OR":                       or,
 	"ORDER":                    order,
 	"OUTER":                    outer,
@@ -526,6 +546,8 @@ var tokenMap = map[string]int{
 	"PARTITIONING":             partitioning,
 	"PARTITIONS":               partitions,
 	"PASSWORD":                 password,
+	"PASSWORD_LOCK_TIME":       passwordLockTime,
+	"PATH":                     pathKwd,
 	"PERCENT":                  percent,
 	"PER_DB":                   per_db,
 	"PER_TABLE":                per_table,
@@ -544,6 +566,7 @@ var tokenMap = map[string]int{
 	"PRESERVE":                 preserve,
 	"PRIMARY":                  primary,
 	"PRIMARY_REGION":           primaryRegion,
+	"PRIORITY":                 priority,
 	"PRIVILEGES":               privileges,
 	"PROCEDURE":                procedure,
 	"PROCESS":                  process,
@@ -556,6 +579,7 @@ var tokenMap = map[string]int{
 	"QUARTER":                  quarter,
 	"QUERIES":                  queries,
 	"QUERY":                    query,
+	"QUERY_LIMIT":              queryLimit,
 	"QUICK":                    quick,
 	"RANGE":                    rangeKwd,
 	"RATE_LIMIT":               rateLimit,
@@ -585,6 +609,7 @@ var tokenMap = map[string]int{
 	"REPLICATION":              replication,
 	"REQUIRE":                  require,
 	"REQUIRED":                 required,
+	"RESOURCE":                 resource,
 	"RESET":                    reset,
 	"RESPECT":                  respect,
 	"RESTART":                  restart,
@@ -597,18 +622,25 @@ var tokenMap = map[string]int{
 	"RLIKE":                    rlike,
 	"ROLE":                     role,
 	"ROLLBACK":                 rollback,
+	"ROLLUP":                   rollup,
+	"ROTATE":                   rotate,
 	"ROUTINE":                  routine,
 	"ROW_COUNT":                rowCount,
 	"ROW_FORMAT":               rowFormat,
 	"ROW":                      row,
 	"ROWS":                     rows,
 	"RTREE":                    rtree,
+	"RUN":                      run,
 	"RESUME":                   resume,
+	"RETURNS":                  returns,
+	"REUSE":                    reuse,
+	"RU_PER_SEC":               ruPerSec,
 	"RUNNING":                  running,
 	"S3":                       s3,
 	"SAMPLES":                  samples,
 	"SAMPLERATE":               sampleRate,
 	"SAN":                      san,
+	"SAVEPOINT":                savepoint,
 	"SCHEDULE":                 schedule,
 	"SCHEMA":                   database,
 	"SCHEMAS":                  databases,
@@ -625,6 +657,7 @@ var tokenMap = map[string]int{
 	"SERIAL":                   serial,
 	"SERIALIZABLE":             serializable,
 	"SESSION":                  session,
+	"SESSION_STATES":           sessionStates,
 	"SET":                      set,
 	"SETVAL":                   setval,
 	"SHARD_ROW_ID_BITS":        shardRowIDBits,
@@ -668,6 +701,7 @@ var tokenMap = map[string]int{
 	"STATS_BUCKETS":            statsBuckets,
 	"STATS_EXTENDED":           statsExtended,
 	"STATS_HEALTHY":            statsHealthy,
+	"STATS_DUMP":               statsDump,
 	"STATS_HISTOGRAMS":         statsHistograms,
 	"STATS_TOPN":               statsTopN,
 	"STATS_META":               statsMeta,
@@ -714,6 +748,7 @@ var tokenMap = map[string]int{
 	"THAN":                     than,
 	"THEN":                     then,
 	"TIDB":                     tidb,
+	"TIES":                     ties,
 	"TIFLASH":                  tiFlash,
 	"TIKV_IMPORTER":            tikvImporter,
 	"TIME":                     timeType,
@@ -773,6 +808,7 @@ var tokenMap = map[string]int{
 	"VARIABLES":                variables,
 	"VARIANCE":                 varPop,
 	"VARYING":                  varying,
+	"VECTOR":                   vectorType,
 	"VERBOSE":                  verboseType,
 	"VOTER":                    voter,
 	"VOTER_CONSTRAINTS":        voterConstraints,
@@ -829,6 +865,7 @@ var btFuncTokenMap = map[string]int{
 	"SUM":                   builtinSum,
 	"SYSDATE":               builtinSysDate,
 	"SYSTEM_USER":           builtinUser,
+	"TIME_BUCKET":           builtinTimeBucket,
 	"TRANSLATE":             builtinTranslate,
 	"TRIM":                  builtinTrim,
 	"VARIANCE":              builtinVarPop,
@@ -94,6 +94,11 @@ const (
 	ActionAlterCacheTable               ActionType = 57
 	ActionAlterTableStatsOptions        ActionType = 58
 	ActionAlterNoCacheTable             ActionType = 59
+	ActionAlterTableGCLifeTime          ActionType = 60
+	ActionRecoverSchema                 ActionType = 61
+	ActionCreateResourceGroup           ActionType = 62
+	ActionAlterResourceGroup            ActionType = 63
+	ActionDropResourceGroup             ActionType = 64
 )
 
 var actionMap = map[ActionType]string{
@@ -155,6 +160,11 @@ var actionMap = map[ActionType]string{
 	ActionAlterCacheTable:               "alter table cache",
 	ActionAlterNoCacheTable:             "alter table nocache",
 	ActionAlterTableStatsOptions:        "alter table statistics options",
+	ActionAlterTableGCLifeTime:          "alter table gc life time",
+	ActionRecoverSchema:                 "recover schema",
+	ActionCreateResourceGroup:           "create resource group",
+	ActionAlterResourceGroup:            "alter resource group",
+	ActionDropResourceGroup:             "drop resource group",
 
 	// `ActionAlterTableAlterPartition` is removed and will never be used.
 	// Just left a tombstone here for compatibility.
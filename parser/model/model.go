@@ -345,6 +345,13 @@ type TableInfo struct {
 
 	// StatsOptions is used when do analyze/auto-analyze for each table
 	StatsOptions *StatsOptions `json:"stats_options"`
+
+	// ExternalTable is not nil when the rows of the table are read from files in an external storage.
+	ExternalTable *ExternalTableInfo `json:"external_table,omitempty"`
+
+	// GCLifeTime is the GC life time of the table in Go duration format, the GC safe point is held back to keep
+	// the versions of the table within it if it's longer than the global one. Empty means no override.
+	GCLifeTime string `json:"gc_life_time,omitempty"`
 }
 type TableCacheStatusType int
 
@@ -386,6 +393,17 @@ func (t TempTableType) String() string {
 	}
 }
 
+// ExternalTableFormatCSV is the only file format supported by external tables now.
+const ExternalTableFormatCSV = "CSV"
+
+// ExternalTableInfo provides meta data describing where and how the rows of an external table are stored.
+type ExternalTableInfo struct {
+	// Location is the external storage URL of a file or a directory, e.g. s3://bucket/prefix.
+	Location string `json:"location"`
+	// Format is the file format, e.g. CSV.
+	Format string `json:"format"`
+}
+
 // TableLockInfo provides meta data describing a table lock.
 type TableLockInfo struct {
 	Tp TableLockType
@@ -581,12 +599,33 @@ func (t *TableInfo) ContainsAutoRandomBits() bool {
 	return t.AutoRandomBits != 0
 }
 
+// GetAutoRandomColInfo gets the ColumnInfo of the auto_random column if exists.
+// It's the integer primary key, or the first column of the clustered composite primary key.
+func (t *TableInfo) GetAutoRandomColInfo() *ColumnInfo {
+	if t.AutoRandomBits == 0 {
+		return nil
+	}
+	if t.PKIsHandle {
+		return t.GetPkColInfo()
+	}
+	if !t.IsCommonHandle {
+		return nil
+	}
+	for _, idx := range t.Indices {
+		if idx.Primary {
+			return t.Columns[idx.Columns[0].Offset]
+		}
+	}
+	return nil
+}
+
 // IsAutoRandomBitColUnsigned indicates whether the auto_random column is unsigned. Make sure the table contains auto_random before calling this method.
 func (t *TableInfo) IsAutoRandomBitColUnsigned() bool {
-	if !t.PKIsHandle || t.AutoRandomBits == 0 {
+	col := t.GetAutoRandomColInfo()
+	if col == nil {
 		return false
 	}
-	return mysql.HasUnsignedFlag(t.GetPkColInfo().Flag)
+	return mysql.HasUnsignedFlag(col.Flag)
 }
 
 // Cols returns the columns of the table in public state.
@@ -615,6 +654,16 @@ func (t *TableInfo) FindIndexByName(idxName string) *IndexInfo {
 	return nil
 }
 
+// HasGlobalIndex checks whether the table has any global index.
+func (t *TableInfo) HasGlobalIndex() bool {
+	for _, idx := range t.Indices {
+		if idx.Global {
+			return true
+		}
+	}
+	return false
+}
+
 // IsLocked checks whether the table was locked.
 func (t *TableInfo) IsLocked() bool {
 	return t.Lock != nil && len(t.Lock.Sessions) > 0
@@ -1005,6 +1054,7 @@ type IndexInfo struct {
 	Primary   bool           `json:"is_primary"`   // Whether the index is primary key.
 	Invisible bool           `json:"is_invisible"` // Whether the index is invisible.
 	Global    bool           `json:"is_global"`    // Whether the index is global.
+	MVIndex   bool           `json:"mv_index"`     // Whether the index is a multi-valued index.
 }
 
 // Clone clones IndexInfo.
@@ -1185,6 +1235,114 @@ type PolicyInfo struct {
 	State SchemaState `json:"state"`
 }
 
+// Resource group priorities.
+const (
+	ResourceGroupPriorityLow    uint64 = 1
+	ResourceGroupPriorityMedium uint64 = 2
+	ResourceGroupPriorityHigh   uint64 = 3
+)
+
+// ResourceGroupPriorityValueMap maps the priority names to the priority values.
+var ResourceGroupPriorityValueMap = map[string]uint64{
+	"LOW":    ResourceGroupPriorityLow,
+	"MEDIUM": ResourceGroupPriorityMedium,
+	"HIGH":   ResourceGroupPriorityHigh,
+}
+
+// ResourceGroupPriorityValueToName maps the priority values to the priority names.
+var ResourceGroupPriorityValueToName = map[uint64]string{
+	ResourceGroupPriorityLow:    "LOW",
+	ResourceGroupPriorityMedium: "MEDIUM",
+	ResourceGroupPriorityHigh:   "HIGH",
+}
+
+// RunawayActionType is the action taken on the runaway queries, which exceed the QUERY_LIMIT of their resource groups.
+type RunawayActionType uint64
+
+// Runaway actions.
+const (
+	RunawayActionNone RunawayActionType = iota
+	// RunawayActionKill kills the runaway queries.
+	RunawayActionKill
+	// RunawayActionCooldown deprioritizes the remaining requests of the runaway queries.
+	RunawayActionCooldown
+)
+
+// RunawayActionValueMap maps the action names to the action values.
+var RunawayActionValueMap = map[string]RunawayActionType{
+	"KILL":     RunawayActionKill,
+	"COOLDOWN": RunawayActionCooldown,
+}
+
+// String implements fmt.Stringer interface.
+func (t RunawayActionType) String() string {
+	switch t {
+	case RunawayActionKill:
+		return "KILL"
+	case RunawayActionCooldown:
+		return "COOLDOWN"
+	default:
+		return "NONE"
+	}
+}
+
+// ResourceGroupSettings is the settings of the resource group.
+type ResourceGroupSettings struct {
+	// RURate is the count of the request units that can be consumed per second, 0 means unlimited.
+	RURate    uint64 `json:"ru_per_sec"`
+	Priority  uint64 `json:"priority"`
+	Burstable bool   `json:"burstable"`
+	// ExecElapsedTimeMs is the QUERY_LIMIT of the execution time, the queries running longer are runaway queries.
+	// 0 means unlimited.
+	ExecElapsedTimeMs uint64            `json:"exec_elapsed_time_ms"`
+	RunawayAction     RunawayActionType `json:"runaway_action"`
+	// EgressBytesPerSec is the size of the results that can be sent to the clients per second by all the
+	// connections of the group, 0 means unlimited.
+	EgressBytesPerSec uint64 `json:"egress_bytes_per_sec"`
+}
+
+// NewResourceGroupSettings creates a ResourceGroupSettings with the default values.
+func NewResourceGroupSettings() *ResourceGroupSettings {
+	return &ResourceGroupSettings{
+		Priority: ResourceGroupPriorityMedium,
+	}
+}
+
+func (p *ResourceGroupSettings) String() string {
+	sb := new(strings.Builder)
+	if p.RURate > 0 {
+		writeSettingItemToBuilder(sb, fmt.Sprintf("RU_PER_SEC=%d", p.RURate))
+	}
+	writeSettingItemToBuilder(sb, "PRIORITY="+ResourceGroupPriorityValueToName[p.Priority])
+	if p.Burstable {
+		writeSettingItemToBuilder(sb, "BURSTABLE")
+	}
+	if p.ExecElapsedTimeMs > 0 {
+		execElapsed := time.Duration(p.ExecElapsedTimeMs) * time.Millisecond
+		writeSettingItemToBuilder(sb, fmt.Sprintf("QUERY_LIMIT=(EXEC_ELAPSED=\"%s\" ACTION=%s)", execElapsed, p.RunawayAction))
+	}
+	if p.EgressBytesPerSec > 0 {
+		writeSettingItemToBuilder(sb, fmt.Sprintf("EGRESS_BYTES_PER_SEC=%d", p.EgressBytesPerSec))
+	}
+	return sb.String()
+}
+
+// ResourceGroupInfo is the struct to store the resource group.
+type ResourceGroupInfo struct {
+	*ResourceGroupSettings
+	ID    int64       `json:"id"`
+	Name  CIStr       `json:"name"`
+	State SchemaState `json:"state"`
+}
+
+// Clone clones the ResourceGroupInfo.
+func (p *ResourceGroupInfo) Clone() *ResourceGroupInfo {
+	cloned := *p
+	settings := *p.ResourceGroupSettings
+	cloned.ResourceGroupSettings = &settings
+	return &cloned
+}
+
 func writeSettingItemToBuilder(sb *strings.Builder, item string) {
 	if sb.Len() != 0 {
 		sb.WriteString(" ")
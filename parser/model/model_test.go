@@ -113,6 +113,10 @@ func TestModelBasic(t *testing.T) {
 	require.Equal(t, TSConvert2Time(table.UpdateTS), table.GetUpdateTime())
 	require.True(t, table2.IsSequence())
 	require.False(t, table2.IsBaseTable())
+	require.False(t, table.HasGlobalIndex())
+	index.Global = true
+	require.True(t, table.HasGlobalIndex())
+	index.Global = false
 
 	// Corner cases
 	column.Flag ^= mysql.PriKeyFlag
@@ -298,6 +302,7 @@ func TestString(t *testing.T) {
 		{ActionAlterTablePlacement, "alter table placement"},
 		{ActionAlterTablePartitionPlacement, "alter table partition placement"},
 		{ActionAlterNoCacheTable, "alter table nocache"},
+		{ActionAlterTableGCLifeTime, "alter table gc life time"},
 	}
 
 	for _, v := range acts {
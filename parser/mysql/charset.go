@@ -76,6 +76,7 @@ var CharsetIDs = map[string]uint8{
 	"geostd8":  92,
 	"cp932":    95,
 	"eucjpms":  97,
+	"gb18030":  248,
 }
 
 // Charsets maps charset name to its default collation name.
@@ -120,6 +121,7 @@ var Charsets = map[string]string{
 	"geostd8":  "geostd8_general_ci",
 	"cp932":    "cp932_japanese_ci",
 	"eucjpms":  "eucjpms_japanese_ci",
+	"gb18030":  "gb18030_chinese_ci",
 }
 
 // Collations maps MySQL collation ID to its name.
@@ -343,6 +345,8 @@ var Collations = map[uint8]string{
 	245: "utf8mb4_croatian_ci",
 	246: "utf8mb4_unicode_520_ci",
 	247: "utf8mb4_vietnamese_ci",
+	248: "gb18030_chinese_ci",
+	249: "gb18030_bin",
 	255: "utf8mb4_0900_ai_ci",
 }
 
@@ -567,6 +571,8 @@ var CollationNames = map[string]uint8{
 	"utf8mb4_croatian_ci":      245,
 	"utf8mb4_unicode_520_ci":   246,
 	"utf8mb4_vietnamese_ci":    247,
+	"gb18030_chinese_ci":       248,
+	"gb18030_bin":              249,
 	"utf8mb4_0900_ai_ci":       255,
 }
 
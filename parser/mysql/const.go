@@ -45,6 +45,9 @@ const (
 // Protocol Features
 const AuthSwitchRequest byte = 0xfe
 
+// AuthMoreData is the header of the packet carrying the extra data during the authentication.
+const AuthMoreData byte = 0x01
+
 // Server information.
 const (
 	ServerStatusInTrans            uint16 = 0x0001
@@ -155,6 +158,11 @@ const (
 	ClientPluginAuth
 	ClientConnectAtts
 	ClientPluginAuthLenencClientData
+	ClientCanHandleExpiredPasswords
+	ClientSessionTrack
+	ClientDeprecateEOF
+	ClientOptionalResultsetMetadata
+	ClientZstdCompressionAlgorithm
 )
 
 // Cache type information.
@@ -166,7 +174,12 @@ const (
 const (
 	AuthNativePassword      = "mysql_native_password"
 	AuthCachingSha2Password = "caching_sha2_password"
+	AuthTiDBSM3Password     = "tidb_sm3_password"
 	AuthSocket              = "auth_socket"
+	AuthTiDBSessionToken    = "tidb_session_token"
+	AuthLDAPSimple          = "authentication_ldap_simple"
+	AuthLDAPSASL            = "authentication_ldap_sasl"
+	AuthMySQLClearPassword  = "mysql_clear_password"
 )
 
 // MySQL database and tables.
@@ -193,6 +206,10 @@ const (
 	RoleEdgeTable = "role_edges"
 	// DefaultRoleTable is the table contain default active role info
 	DefaultRoleTable = "default_roles"
+	// ProxiesPrivTable is the table contains the PROXY privileges.
+	ProxiesPrivTable = "proxies_priv"
+	// PasswordHistoryTable is the table contains the previous passwords of the users.
+	PasswordHistoryTable = "password_history"
 )
 
 // MySQL type maximum length.
@@ -34,6 +34,8 @@ const (
 	TypeVarchar  byte = 15
 	TypeBit      byte = 16
 
+	TypeTiDBVectorFloat32 byte = 0xe1 // VECTOR, a TiDB extension for float32 vectors.
+
 	TypeJSON       byte = 0xf5
 	TypeNewDecimal byte = 0xf6
 	TypeEnum       byte = 0xf7
@@ -74,6 +76,7 @@ const (
 	PreventNullInsertFlag uint = 1 << 20 /* Prevent this Field from inserting NULL values */
 	EnumSetAsIntFlag      uint = 1 << 21 /* Internal: Used for inferring enum eval type. */
 	DropColumnIndexFlag   uint = 1 << 22 /* Internal: Used for indicate the column is being dropped with index */
+	ArrayFlag             uint = 1 << 23 /* Internal: Used for indicate the JSON value is an array casted for multi-valued index */
 )
 
 // TypeInt24 bounds.
@@ -158,6 +161,11 @@ func HasPreventNullInsertFlag(flag uint) bool {
 	return (flag & PreventNullInsertFlag) > 0
 }
 
+// HasArrayFlag checks if ArrayFlag is set.
+func HasArrayFlag(flag uint) bool {
+	return (flag & ArrayFlag) > 0
+}
+
 // HasEnumSetAsIntFlag checks if EnumSetAsIntFlag is set.
 func HasEnumSetAsIntFlag(flag uint) bool {
 	return (flag & EnumSetAsIntFlag) > 0
@@ -27,6 +27,7 @@ package parser
 
 import (
 	"strings"
+	"time"
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/ast"
@@ -52,6 +53,7 @@ import (
 	/*yy:token "%c"     */
 	identifier "identifier"
 	asof       "AS OF"
+	withRollup "WITH ROLLUP"
 
 	/*yy:token "_%c"    */
 	underscoreCS "UNDERSCORE_CHARSET"
@@ -166,11 +168,13 @@ import (
 	int4Type          "INT4"
 	int8Type          "INT8"
 	join              "JOIN"
+	jsonTable         "JSON_TABLE"
 	key               "KEY"
 	keys              "KEYS"
 	kill              "KILL"
 	lag               "LAG"
 	lastValue         "LAST_VALUE"
+	lateral           "LATERAL"
 	lead              "LEAD"
 	leading           "LEADING"
 	left              "LEFT"
@@ -296,8 +300,10 @@ import (
 	algorithm             "ALGORITHM"
 	always                "ALWAYS"
 	any                   "ANY"
+	array                 "ARRAY"
 	ascii                 "ASCII"
 	attributes            "ATTRIBUTES"
+	authorization         "AUTHORIZATION"
 	statsOptions          "STATS_OPTIONS"
 	statsSampleRate       "STATS_SAMPLE_RATE"
 	statsColChoice        "STATS_COL_CHOICE"
@@ -311,6 +317,7 @@ import (
 	backend               "BACKEND"
 	backup                "BACKUP"
 	backups               "BACKUPS"
+	batch                 "BATCH"
 	begin                 "BEGIN"
 	bernoulli             "BERNOULLI"
 	binding               "BINDING"
@@ -372,9 +379,12 @@ import (
 	disable               "DISABLE"
 	discard               "DISCARD"
 	disk                  "DISK"
+	dml                   "DML"
 	do                    "DO"
+	dry                   "DRY"
 	duplicate             "DUPLICATE"
 	dynamic               "DYNAMIC"
+	emptyKwd              "EMPTY"
 	enable                "ENABLE"
 	encryption            "ENCRYPTION"
 	end                   "END"
@@ -388,11 +398,14 @@ import (
 	events                "EVENTS"
 	evolve                "EVOLVE"
 	exchange              "EXCHANGE"
+	exclude               "EXCLUDE"
 	exclusive             "EXCLUSIVE"
 	execute               "EXECUTE"
 	expansion             "EXPANSION"
 	expire                "EXPIRE"
 	extended              "EXTENDED"
+	external              "EXTERNAL"
+	failedLoginAttempts   "FAILED_LOGIN_ATTEMPTS"
 	faultsSym             "FAULTS"
 	fields                "FIELDS"
 	file                  "FILE"
@@ -450,6 +463,7 @@ import (
 	maxUpdatesPerHour     "MAX_UPDATES_PER_HOUR"
 	maxUserConnections    "MAX_USER_CONNECTIONS"
 	mb                    "MB"
+	member                "MEMBER"
 	memory                "MEMORY"
 	merge                 "MERGE"
 	microsecond           "MICROSECOND"
@@ -462,6 +476,7 @@ import (
 	names                 "NAMES"
 	national              "NATIONAL"
 	ncharType             "NCHAR"
+	nested                "NESTED"
 	never                 "NEVER"
 	next                  "NEXT"
 	nextval               "NEXTVAL"
@@ -483,6 +498,8 @@ import (
 	only                  "ONLY"
 	open                  "OPEN"
 	optional              "OPTIONAL"
+	ordinality            "ORDINALITY"
+	others                "OTHERS"
 	packKeys              "PACK_KEYS"
 	pageSym               "PAGE"
 	parser                "PARSER"
@@ -490,6 +507,8 @@ import (
 	partitioning          "PARTITIONING"
 	partitions            "PARTITIONS"
 	password              "PASSWORD"
+	passwordLockTime      "PASSWORD_LOCK_TIME"
+	pathKwd               "PATH"
 	percent               "PERCENT"
 	per_db                "PER_DB"
 	per_table             "PER_TABLE"
@@ -524,19 +543,26 @@ import (
 	replicas              "REPLICAS"
 	replication           "REPLICATION"
 	required              "REQUIRED"
+	resource              "RESOURCE"
 	respect               "RESPECT"
 	restart               "RESTART"
 	restore               "RESTORE"
 	restores              "RESTORES"
 	resume                "RESUME"
+	returns               "RETURNS"
+	reuse                 "REUSE"
 	reverse               "REVERSE"
 	role                  "ROLE"
 	rollback              "ROLLBACK"
+	rollup                "ROLLUP"
+	rotate                "ROTATE"
 	routine               "ROUTINE"
 	rowCount              "ROW_COUNT"
 	rowFormat             "ROW_FORMAT"
 	rtree                 "RTREE"
+	run                   "RUN"
 	san                   "SAN"
+	savepoint             "SAVEPOINT"
 	second                "SECOND"
 	secondaryEngine       "SECONDARY_ENGINE"
 	secondaryLoad         "SECONDARY_LOAD"
@@ -548,6 +574,7 @@ import (
 	serial                "SERIAL"
 	serializable          "SERIALIZABLE"
 	session               "SESSION"
+	sessionStates         "SESSION_STATES"
 	setval                "SETVAL"
 	shardRowIDBits        "SHARD_ROW_ID_BITS"
 	share                 "SHARE"
@@ -595,6 +622,7 @@ import (
 	temptable             "TEMPTABLE"
 	textType              "TEXT"
 	than                  "THAN"
+	ties                  "TIES"
 	tikvImporter          "TIKV_IMPORTER"
 	timestampType         "TIMESTAMP"
 	timeType              "TIME"
@@ -613,6 +641,7 @@ import (
 	validation            "VALIDATION"
 	value                 "VALUE"
 	variables             "VARIABLES"
+	vectorType            "VECTOR"
 	view                  "VIEW"
 	visible               "VISIBLE"
 	warnings              "WARNINGS"
@@ -632,15 +661,19 @@ import (
 	bitXor                "BIT_XOR"
 	bound                 "BOUND"
 	briefType             "BRIEF"
+	burstable             "BURSTABLE"
 	cast                  "CAST"
 	copyKwd               "COPY"
 	constraints           "CONSTRAINTS"
+	cooldown              "COOLDOWN"
 	curTime               "CURTIME"
 	dateAdd               "DATE_ADD"
 	dateSub               "DATE_SUB"
 	dotType               "DOT"
 	dump                  "DUMP"
+	egressBytesPerSec     "EGRESS_BYTES_PER_SEC"
 	exact                 "EXACT"
+	execElapsed           "EXEC_ELAPSED"
 	exprPushdownBlacklist "EXPR_PUSHDOWN_BLACKLIST"
 	extract               "EXTRACT"
 	flashback             "FLASHBACK"
@@ -670,8 +703,11 @@ import (
 	position              "POSITION"
 	predicate             "PREDICATE"
 	primaryRegion         "PRIMARY_REGION"
+	priority              "PRIORITY"
+	queryLimit            "QUERY_LIMIT"
 	recent                "RECENT"
 	replayer              "REPLAYER"
+	ruPerSec              "RU_PER_SEC"
 	running               "RUNNING"
 	s3                    "S3"
 	schedule              "SCHEDULE"
@@ -721,6 +757,7 @@ import (
 	dependency                 "DEPENDENCY"
 	depth                      "DEPTH"
 	drainer                    "DRAINER"
+	gcLifeTime                 "GC_LIFE_TIME"
 	jobs                       "JOBS"
 	job                        "JOB"
 	nodeID                     "NODE_ID"
@@ -736,6 +773,7 @@ import (
 	statsHistograms            "STATS_HISTOGRAMS"
 	statsBuckets               "STATS_BUCKETS"
 	statsHealthy               "STATS_HEALTHY"
+	statsDump                  "STATS_DUMP"
 	statsTopN                  "STATS_TOPN"
 	histogramsInFlight         "HISTOGRAMS_IN_FLIGHT"
 	telemetry                  "TELEMETRY"
@@ -770,6 +808,7 @@ import (
 	builtinSubstring
 	builtinSum
 	builtinSysDate
+	builtinTimeBucket
 	builtinStddevPop
 	builtinStddevSamp
 	builtinTranslate
@@ -848,6 +887,7 @@ import (
 	AlterImportStmt            "ALTER IMPORT statement"
 	AlterInstanceStmt          "Alter instance statement"
 	AlterPolicyStmt            "Alter Placement Policy statement"
+	AlterResourceGroupStmt     "Alter Resource Group statement"
 	AlterSequenceStmt          "Alter sequence statement"
 	AnalyzeTableStmt           "Analyze table statement"
 	BeginTransactionStmt       "BEGIN TRANSACTION statement"
@@ -862,7 +902,9 @@ import (
 	CreateIndexStmt            "CREATE INDEX statement"
 	CreateImportStmt           "CREATE IMPORT statement"
 	CreateBindingStmt          "CREATE BINDING  statement"
+	CreateFunctionStmt         "CREATE FUNCTION statement"
 	CreatePolicyStmt           "CREATE PLACEMENT POLICY statement"
+	CreateResourceGroupStmt    "CREATE RESOURCE GROUP statement"
 	CreateSequenceStmt         "CREATE SEQUENCE statement"
 	CreateStatisticsStmt       "CREATE STATISTICS statement"
 	DoStmt                     "Do statement"
@@ -877,10 +919,14 @@ import (
 	DropRoleStmt               "DROP ROLE"
 	DropViewStmt               "DROP VIEW statement"
 	DropBindingStmt            "DROP BINDING  statement"
+	DropFunctionStmt           "DROP FUNCTION statement"
 	DropPolicyStmt             "DROP PLACEMENT POLICY statement"
+	DropResourceGroupStmt      "DROP RESOURCE GROUP statement"
 	DeallocateStmt             "Deallocate prepared statement"
 	DeleteFromStmt             "DELETE FROM statement"
 	DeleteWithoutUsingStmt     "Normal DELETE statement"
+	NonTransactionalDMLStmt    "Non-transactional DML statement"
+	ShardableStmt              "DML statement which can be split by non-transactional DML"
 	DeleteWithUsingStmt        "DELETE USING statement"
 	EmptyStmt                  "empty statement"
 	ExecuteStmt                "Execute statement"
@@ -888,6 +934,7 @@ import (
 	ExplainableStmt            "explainable statement"
 	FlushStmt                  "Flush statement"
 	FlashbackTableStmt         "Flashback table statement"
+	FlashbackDatabaseStmt      "Flashback database statement"
 	GrantStmt                  "Grant statement"
 	GrantProxyStmt             "Grant proxy statement"
 	GrantRoleStmt              "Grant role statement"
@@ -898,6 +945,7 @@ import (
 	LoadDataStmt               "Load data statement"
 	LoadStatsStmt              "Load statistic statement"
 	LockTablesStmt             "Lock tables statement"
+	LockStatsStmt              "Lock statistics statement"
 	PlanReplayerStmt           "Plan replayer statement"
 	PreparedStmt               "PreparedStmt"
 	PurgeImportStmt            "PURGE IMPORT statement that removes a IMPORT task record"
@@ -907,10 +955,13 @@ import (
 	RenameUserStmt             "rename user statement"
 	ReplaceIntoStmt            "REPLACE INTO statement"
 	RecoverTableStmt           "recover table statement"
+	ReleaseSavepointStmt       "RELEASE SAVEPOINT statement"
 	ResumeImportStmt           "RESUME IMPORT statement"
 	RevokeStmt                 "Revoke statement"
 	RevokeRoleStmt             "Revoke role statement"
+	RevokeProxyStmt            "Revoke proxy statement"
 	RollbackStmt               "ROLLBACK statement"
+	SavepointStmt              "SAVEPOINT statement"
 	SplitRegionStmt            "Split index region statement"
 	SetStmt                    "Set variable statement"
 	ChangeStmt                 "Change statement"
@@ -924,6 +975,7 @@ import (
 	TraceableStmt              "traceable statement"
 	TruncateTableStmt          "TRUNCATE TABLE statement"
 	UnlockTablesStmt           "Unlock tables statement"
+	UnlockStatsStmt            "Unlock statistics statement"
 	UpdateStmt                 "UPDATE statement"
 	SetOprStmt                 "Union/Except/Intersect select statement"
 	SetOprStmtWithLimitOrderBy "Union/Except/Intersect select statement with limit and order by"
@@ -956,6 +1008,8 @@ import (
 	AuthOption                             "User auth option"
 	Boolean                                "Boolean (0, 1, false, true)"
 	OptionalBraces                         "optional braces"
+	OptionalShardColumn                    "optional shard column of non-transactional DML"
+	DryRunOptions                          "dry run options of non-transactional DML"
 	CastType                               "Cast function target type"
 	ClearPasswordExpireOptions             "Clear password expire options"
 	ColumnDef                              "table column definition"
@@ -1019,6 +1073,10 @@ import (
 	FieldItem                              "Field item for load data clause"
 	FieldItemList                          "Field items for load data clause"
 	FuncDatetimePrec                       "Function datetime precision"
+	FunctionBody                           "Function body of CREATE FUNCTION"
+	FunctionParam                          "Function parameter"
+	FunctionParamList                      "Function parameter list"
+	FunctionParamListOpt                   "Function parameter list opt"
 	GetFormatSelector                      "{DATE|DATETIME|TIME|TIMESTAMP}"
 	GlobalScope                            "The scope of variable"
 	StatementScope                         "The scope of statement"
@@ -1056,6 +1114,11 @@ import (
 	InsertValues                           "Rest part of INSERT/REPLACE INTO statement"
 	JoinTable                              "join table"
 	JoinType                               "join type"
+	JSONTableColumn                        "JSON_TABLE column"
+	JSONTableColumnList                    "JSON_TABLE column list"
+	JSONTableColumnOnOpt                   "JSON_TABLE column ON EMPTY and ON ERROR clauses"
+	JSONTableColumnsClause                 "JSON_TABLE COLUMNS clause"
+	JSONTableOnResponse                    "JSON_TABLE column NULL, ERROR or DEFAULT response"
 	KillOrKillTiDB                         "Kill or Kill TiDB"
 	LocationLabelList                      "location label name list"
 	LikeTableWithOrWithoutParen            "LIKE table_name or ( LIKE table_name )"
@@ -1105,6 +1168,7 @@ import (
 	PasswordOrLockOption                   "Single password or lock option for create user statement"
 	PasswordOrLockOptionList               "Password or lock options for create user statement"
 	PasswordOrLockOptions                  "Optional password or lock options for create user statement"
+	ResourceGroupNameOption                "Optional resource group option for create user statement"
 	ColumnPosition                         "Column position [First|After ColumnName]"
 	PrepareSQL                             "Prepare statement sql string"
 	Priority                               "Statement priority"
@@ -1246,6 +1310,7 @@ import (
 	OptWild                                "Optional Wildcard"
 	OptWindowOrderByClause                 "Optional ORDER BY clause in WINDOW"
 	OptWindowFrameClause                   "Optional FRAME clause in WINDOW"
+	OptWindowFrameExclusion                "Optional EXCLUDE clause in WINDOW FRAME"
 	OptWindowingClause                     "Optional OVER clause"
 	WindowingClause                        "OVER clause"
 	WindowClauseOptional                   "Optional WINDOW clause"
@@ -1313,6 +1378,9 @@ import (
 	PlacementPolicyOption                  "Anonymous or placement policy option"
 	DirectPlacementOption                  "Subset of anonymous or direct placement option"
 	PlacementOptionList                    "Anomymous or direct placement option list"
+	DirectResourceGroupOption              "Subset of resource group option"
+	RunawayAction                          "Action taken on the runaway queries"
+	ResourceGroupOptionList                "Resource group option list"
 	AttributesOpt                          "Attributes options"
 	AllColumnsOrPredicateColumnsOpt        "all columns or predicate columns option"
 	StatsOptionsOpt                        "Stats options"
@@ -1352,6 +1420,7 @@ import (
 	logOr             "logical or operator"
 	LinearOpt         "linear or empty"
 	FieldsOrColumns   "Fields or columns"
+	NestedPathKwdOpt  "optional PATH after NESTED"
 	StorageMedia      "{DISK|MEMORY|DEFAULT}"
 	EncryptionOpt     "Encryption option 'Y' or 'N'"
 	FirstOrNext       "FIRST or NEXT"
@@ -1376,6 +1445,7 @@ import (
 	ColumnFormat                    "Column format"
 	DBName                          "Database Name"
 	PolicyName                      "Placement Policy Name"
+	ResourceGroupName               "Resource Group Name"
 	ExplainFormatType               "explain format type"
 	FieldAsName                     "Field alias name"
 	FieldAsNameOpt                  "Field alias name opt"
@@ -1386,6 +1456,7 @@ import (
 	LinesTerminated                 "Lines terminated by"
 	OptCharset                      "Optional Character setting"
 	OptCollate                      "Optional Collate setting"
+	OutfileCompressionOpt           "Optional compression of the SELECT INTO OUTFILE file"
 	PasswordOpt                     "Password option"
 	RoleNameString                  "role name string"
 	ShowDatabaseNameOpt             "Show tables/columns statement database name option"
@@ -1443,6 +1514,8 @@ import (
 %left xor
 %left andand and
 %left between
+%precedence lowerThanMember
+%precedence member
 %precedence lowerThanEq
 %left eq ge le neq neqSynonym '>' '<' is like in
 %left '|'
@@ -1515,6 +1588,62 @@ PlacementOptionList:
 		$$ = append($1.([]*ast.PlacementOption), $3.(*ast.PlacementOption))
 	}
 
+ResourceGroupOptionList:
+	DirectResourceGroupOption
+	{
+		$$ = []*ast.ResourceGroupOption{$1.(*ast.ResourceGroupOption)}
+	}
+|	ResourceGroupOptionList DirectResourceGroupOption
+	{
+		$$ = append($1.([]*ast.ResourceGroupOption), $2.(*ast.ResourceGroupOption))
+	}
+|	ResourceGroupOptionList ',' DirectResourceGroupOption
+	{
+		$$ = append($1.([]*ast.ResourceGroupOption), $3.(*ast.ResourceGroupOption))
+	}
+
+DirectResourceGroupOption:
+	"RU_PER_SEC" EqOpt LengthNum
+	{
+		$$ = &ast.ResourceGroupOption{Tp: ast.ResourceRURate, UintValue: $3.(uint64)}
+	}
+|	"PRIORITY" EqOpt Identifier
+	{
+		priority, ok := model.ResourceGroupPriorityValueMap[strings.ToUpper($3)]
+		if !ok {
+			yylex.AppendError(yylex.Errorf("PRIORITY must be LOW, MEDIUM or HIGH"))
+			return 1
+		}
+		$$ = &ast.ResourceGroupOption{Tp: ast.ResourcePriority, UintValue: priority}
+	}
+|	"BURSTABLE"
+	{
+		$$ = &ast.ResourceGroupOption{Tp: ast.ResourceBurstable, BoolValue: true}
+	}
+|	"EGRESS_BYTES_PER_SEC" EqOpt LengthNum
+	{
+		$$ = &ast.ResourceGroupOption{Tp: ast.ResourceEgressRate, UintValue: $3.(uint64)}
+	}
+|	"QUERY_LIMIT" EqOpt '(' "EXEC_ELAPSED" EqOpt stringLit CommaOpt "ACTION" EqOpt RunawayAction ')'
+	{
+		execElapsed, err := time.ParseDuration($6)
+		if err != nil || execElapsed < time.Millisecond {
+			yylex.AppendError(yylex.Errorf("EXEC_ELAPSED must be a duration of at least 1ms"))
+			return 1
+		}
+		$$ = &ast.ResourceGroupOption{Tp: ast.ResourceQueryLimit, UintValue: uint64(execElapsed.Milliseconds()), RunawayAction: $10.(model.RunawayActionType)}
+	}
+
+RunawayAction:
+	"KILL"
+	{
+		$$ = model.RunawayActionKill
+	}
+|	"COOLDOWN"
+	{
+		$$ = model.RunawayActionCooldown
+	}
+
 DirectPlacementOption:
 	"PRIMARY_REGION" EqOpt stringLit
 	{
@@ -2488,6 +2617,23 @@ FlashbackTableStmt:
 		}
 	}
 
+/*******************************************************************
+ *
+ *  Flash Back Database Statement
+ *
+ *  Example:
+ *      FLASHBACK DATABASE db TO db1
+ *
+ *******************************************************************/
+FlashbackDatabaseStmt:
+	"FLASHBACK" DatabaseSym DBName FlashbackToNewName
+	{
+		$$ = &ast.FlashBackDatabaseStmt{
+			DBName:  model.NewCIStr($3),
+			NewName: $4,
+		}
+	}
+
 FlashbackToNewName:
 	{
 		$$ = ""
@@ -3641,6 +3787,9 @@ DBName:
 PolicyName:
 	Identifier
 
+ResourceGroupName:
+	Identifier
+
 DatabaseOption:
 	DefaultKwdOpt CharsetKw EqOpt CharsetName
 	{
@@ -3745,6 +3894,18 @@ CreateTableStmt:
 		}
 		$$ = tmp
 	}
+|	"CREATE" "EXTERNAL" "TABLE" IfNotExists TableName TableElementListOpt CreateTableOptionListOpt "LOCATION" stringLit "FORMAT" Identifier
+	{
+		stmt := $6.(*ast.CreateTableStmt)
+		stmt.Table = $5.(*ast.TableName)
+		stmt.IfNotExists = $4.(bool)
+		stmt.Options = $7.([]*ast.TableOption)
+		stmt.External = &ast.ExternalTableOption{
+			Location: $9,
+			Format:   strings.ToUpper($11),
+		}
+		$$ = stmt
+	}
 
 OnCommitOpt:
 	{
@@ -4375,6 +4536,43 @@ DeleteWithUsingStmt:
 		$$ = x
 	}
 
+NonTransactionalDMLStmt:
+	"BATCH" OptionalShardColumn "LIMIT" NUM DryRunOptions ShardableStmt
+	{
+		$$ = &ast.NonTransactionalDMLStmt{
+			DryRun:      $5.(int),
+			ShardColumn: $2.(*ast.ColumnName),
+			Limit:       getUint64FromNUM($4),
+			DMLStmt:     $6.(ast.DMLNode),
+		}
+	}
+
+ShardableStmt:
+	DeleteWithoutUsingStmt
+|	UpdateStmtNoWith
+
+OptionalShardColumn:
+	{
+		$$ = (*ast.ColumnName)(nil)
+	}
+|	"ON" ColumnName
+	{
+		$$ = $2.(*ast.ColumnName)
+	}
+
+DryRunOptions:
+	{
+		$$ = ast.NoDryRun
+	}
+|	"DRY" "RUN"
+	{
+		$$ = ast.DryRunSplitDml
+	}
+|	"DRY" "RUN" "QUERY"
+	{
+		$$ = ast.DryRunQuery
+	}
+
 DeleteFromStmt:
 	DeleteWithoutUsingStmt
 |	DeleteWithUsingStmt
@@ -5426,7 +5624,11 @@ PredicateExpr:
 	{
 		$$ = &ast.PatternRegexpExpr{Expr: $1, Pattern: $3, Not: !$2.(bool)}
 	}
-|	BitExpr
+|	BitExpr "MEMBER" "OF" '(' SimpleExpr ')'
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONMemberOf), Args: []ast.ExprNode{$1, $5}}
+	}
+|	BitExpr %prec lowerThanMember
 
 RegexpSym:
 	"REGEXP"
@@ -5508,6 +5710,10 @@ GroupByClause:
 	{
 		$$ = &ast.GroupByClause{Items: $3.([]*ast.ByItem)}
 	}
+|	"GROUP" "BY" ByList withRollup
+	{
+		$$ = &ast.GroupByClause{Items: $3.([]*ast.ByItem), Rollup: true}
+	}
 
 HavingClause:
 	{
@@ -5729,6 +5935,7 @@ UnReservedKeyword:
 |	"ADVISE"
 |	"ASCII"
 |	"ATTRIBUTES"
+|	"AUTHORIZATION"
 |	"STATS_OPTIONS"
 |	"STATS_SAMPLE_RATE"
 |	"STATS_COL_CHOICE"
@@ -5752,6 +5959,7 @@ UnReservedKeyword:
 |	"COLUMNS"
 |	"CONFIG"
 |	"SAN"
+|	"SAVEPOINT"
 |	"COMMIT"
 |	"COMPACT"
 |	"COMPRESSED"
@@ -5778,6 +5986,8 @@ UnReservedKeyword:
 |	"EVOLVE"
 |	"EXECUTE"
 |	"EXTENDED"
+|	"EXTERNAL"
+|	"DML"
 |	"FIELDS"
 |	"FILE"
 |	"FIRST"
@@ -5801,6 +6011,7 @@ UnReservedKeyword:
 |	"PACK_KEYS"
 |	"PARSER"
 |	"PASSWORD" %prec lowerThanEq
+|	"PATH"
 |	"PREPARE"
 |	"PRE_SPLIT_REGIONS"
 |	"PROXY"
@@ -5811,7 +6022,9 @@ UnReservedKeyword:
 |	"RESTART"
 |	"ROLE"
 |	"ROLLBACK"
+|	"ROLLUP"
 |	"SESSION"
+|	"SESSION_STATES"
 |	"SIGNED"
 |	"SHARD_ROW_ID_BITS"
 |	"SHUTDOWN"
@@ -5870,13 +6083,19 @@ UnReservedKeyword:
 |	"SERIALIZABLE"
 |	"LEVEL"
 |	"VARIABLES"
+|	"VECTOR"
 |	"SQL_CACHE"
 |	"INDEXES"
 |	"PROCESSLIST"
 |	"SQL_NO_CACHE"
 |	"DISABLE"
 |	"ENABLE"
+|	"EMPTY"
 |	"REVERSE"
+|	"FAILED_LOGIN_ATTEMPTS"
+|	"PASSWORD_LOCK_TIME"
+|	"REUSE"
+|	"ROTATE"
 |	"PRIVILEGES"
 |	"NO"
 |	"BINLOG"
@@ -5891,6 +6110,9 @@ UnReservedKeyword:
 |	"NULLS"
 |	"SUPER"
 |	"EXCLUSIVE"
+|	"EXCLUDE"
+|	"OTHERS"
+|	"TIES"
 |	"STATS_PERSISTENT"
 |	"STATS_AUTO_RECALC"
 |	"ROW_COUNT"
@@ -5935,6 +6157,7 @@ UnReservedKeyword:
 |	"ISSUER"
 |	"X509"
 |	"NEVER"
+|	"NESTED"
 |	"EXPIRE"
 |	"ACCOUNT"
 |	"INCREMENTAL"
@@ -6021,6 +6244,9 @@ UnReservedKeyword:
 |	"AGO"
 |	"BACKUP"
 |	"BACKUPS"
+|	"BATCH"
+|	"DRY"
+|	"RUN"
 |	"CONCURRENCY"
 |	"MB"
 |	"ONLINE"
@@ -6050,15 +6276,20 @@ UnReservedKeyword:
 |	"SYSTEM"
 |	"PERCENT"
 |	"RESUME"
+|	"RETURNS"
 |	"OFF"
 |	"OPTIONAL"
+|	"ORDINALITY"
 |	"REQUIRED"
+|	"RESOURCE"
 |	"PURGE"
 |	"SKIP"
 |	"LOCKED"
 |	"CLUSTERED"
 |	"NONCLUSTERED"
 |	"PRESERVE"
+|	"MEMBER"
+|	"ARRAY"
 
 TiDBKeyword:
 	"ADMIN"
@@ -6073,6 +6304,7 @@ TiDBKeyword:
 |	"DEPENDENCY"
 |	"DEPTH"
 |	"DRAINER"
+|	"GC_LIFE_TIME"
 |	"JOBS"
 |	"JOB"
 |	"NODE_ID"
@@ -6087,6 +6319,7 @@ TiDBKeyword:
 |	"STATS_TOPN"
 |	"STATS_BUCKETS"
 |	"STATS_HEALTHY"
+|	"STATS_DUMP"
 |	"HISTOGRAMS_IN_FLIGHT"
 |	"TELEMETRY"
 |	"TELEMETRY_ID"
@@ -6185,6 +6418,13 @@ NotKeywordToken:
 |	"FOLLOWER_CONSTRAINTS"
 |	"LEARNER_CONSTRAINTS"
 |	"VOTER_CONSTRAINTS"
+|	"RU_PER_SEC"
+|	"PRIORITY"
+|	"BURSTABLE"
+|	"QUERY_LIMIT"
+|	"EXEC_ELAPSED"
+|	"COOLDOWN"
+|	"EGRESS_BYTES_PER_SEC"
 
 /************************************************************************************
  *
@@ -6838,6 +7078,27 @@ SimpleExpr:
 			ExplicitCharSet: explicitCharset,
 		}
 	}
+|	builtinCast '(' Expression "AS" CastType "ARRAY" ')'
+	{
+		/* See https://dev.mysql.com/doc/refman/8.0/en/create-index.html#create-index-multi-valued */
+		tp := $5.(*types.FieldType)
+		defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimalForCast(tp.Tp)
+		if tp.Flen == types.UnspecifiedLength {
+			tp.Flen = defaultFlen
+		}
+		if tp.Decimal == types.UnspecifiedLength {
+			tp.Decimal = defaultDecimal
+		}
+		explicitCharset := parser.explicitCharset
+		parser.explicitCharset = false
+		$$ = &ast.FuncCastExpr{
+			Expr:            $3,
+			Tp:              tp,
+			FunctionType:    ast.CastFunction,
+			ExplicitCharSet: explicitCharset,
+			Array:           true,
+		}
+	}
 |	"CASE" ExpressionOpt WhenClauseList ElseOpt "END"
 	{
 		x := &ast.CaseExpr{WhenClauses: $3.([]*ast.WhenClause)}
@@ -7218,6 +7479,20 @@ FunctionCallNonKeyword:
 			Args:   []ast.ExprNode{$3, $5, $7},
 		}
 	}
+|	builtinTimeBucket '(' "INTERVAL" Expression TimeUnit ',' Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args:   []ast.ExprNode{$4, &ast.TimeUnitExpr{Unit: $5.(ast.TimeUnitType)}, $7},
+		}
+	}
+|	builtinTimeBucket '(' "INTERVAL" Expression TimeUnit ',' Expression ',' Expression ')'
+	{
+		$$ = &ast.FuncCallExpr{
+			FnName: model.NewCIStr($1),
+			Args:   []ast.ExprNode{$4, &ast.TimeUnitExpr{Unit: $5.(ast.TimeUnitType)}, $7, $9},
+		}
+	}
 
 GetFormatSelector:
 	"DATE"
@@ -8006,6 +8281,26 @@ RollbackStmt:
 	{
 		$$ = &ast.RollbackStmt{CompletionType: $2.(ast.CompletionType)}
 	}
+|	"ROLLBACK" "TO" Identifier
+	{
+		$$ = &ast.RollbackStmt{SavepointName: $3}
+	}
+|	"ROLLBACK" "TO" "SAVEPOINT" Identifier
+	{
+		$$ = &ast.RollbackStmt{SavepointName: $4}
+	}
+
+SavepointStmt:
+	"SAVEPOINT" Identifier
+	{
+		$$ = &ast.SavepointStmt{Name: $2}
+	}
+
+ReleaseSavepointStmt:
+	"RELEASE" "SAVEPOINT" Identifier
+	{
+		$$ = &ast.ReleaseSavepointStmt{Name: $3}
+	}
 
 CompletionTypeWithinTransaction:
 	"AND" "CHAIN" "NO" "RELEASE"
@@ -8457,14 +8752,36 @@ OptWindowFrameClause:
 	{
 		$$ = nil
 	}
-|	WindowFrameUnits WindowFrameExtent
+|	WindowFrameUnits WindowFrameExtent OptWindowFrameExclusion
 	{
 		$$ = &ast.FrameClause{
-			Type:   $1.(ast.FrameType),
-			Extent: $2.(ast.FrameExtent),
+			Type:      $1.(ast.FrameType),
+			Extent:    $2.(ast.FrameExtent),
+			Exclusion: $3.(ast.FrameExclusion),
 		}
 	}
 
+OptWindowFrameExclusion:
+	{
+		$$ = ast.ExcludeNoOthers
+	}
+|	"EXCLUDE" "CURRENT" "ROW"
+	{
+		$$ = ast.ExcludeCurrentRow
+	}
+|	"EXCLUDE" "GROUP"
+	{
+		$$ = ast.ExcludeGroup
+	}
+|	"EXCLUDE" "TIES"
+	{
+		$$ = ast.ExcludeTies
+	}
+|	"EXCLUDE" "NO" "OTHERS"
+	{
+		$$ = ast.ExcludeNoOthers
+	}
+
 WindowFrameUnits:
 	"ROWS"
 	{
@@ -8725,12 +9042,99 @@ TableFactor:
 		resultNode := $1.(*ast.SubqueryExpr).Query
 		$$ = &ast.TableSource{Source: resultNode, AsName: $2.(model.CIStr)}
 	}
+|	"LATERAL" SubSelect TableAsName
+	{
+		resultNode := $2.(*ast.SubqueryExpr).Query
+		$$ = &ast.TableSource{Source: resultNode, AsName: $3.(model.CIStr), Lateral: true}
+	}
 |	'(' TableRefs ')'
 	{
 		j := $2.(*ast.Join)
 		j.ExplicitParens = true
 		$$ = $2
 	}
+|	"JSON_TABLE" '(' Expression ',' stringLit JSONTableColumnsClause ')' TableAsName
+	{
+		jt := &ast.JSONTable{Expr: $3, Path: $5, Columns: $6.([]*ast.JSONTableColumn)}
+		$$ = &ast.TableSource{Source: jt, AsName: $8.(model.CIStr)}
+	}
+
+JSONTableColumnsClause:
+	"COLUMNS" '(' JSONTableColumnList ')'
+	{
+		$$ = $3
+	}
+
+JSONTableColumnList:
+	JSONTableColumn
+	{
+		$$ = []*ast.JSONTableColumn{$1.(*ast.JSONTableColumn)}
+	}
+|	JSONTableColumnList ',' JSONTableColumn
+	{
+		$$ = append($1.([]*ast.JSONTableColumn), $3.(*ast.JSONTableColumn))
+	}
+
+JSONTableColumn:
+	Identifier "FOR" "ORDINALITY"
+	{
+		$$ = &ast.JSONTableColumn{Tp: ast.JSONTableColumnOrdinality, Name: model.NewCIStr($1)}
+	}
+|	Identifier Type "PATH" stringLit JSONTableColumnOnOpt
+	{
+		responses := $5.([]*ast.JSONTableOnResponse)
+		$$ = &ast.JSONTableColumn{
+			Tp:      ast.JSONTableColumnPath,
+			Name:    model.NewCIStr($1),
+			Type:    $2.(*types.FieldType),
+			Path:    $4,
+			OnEmpty: responses[0],
+			OnError: responses[1],
+		}
+	}
+|	Identifier Type "EXISTS" "PATH" stringLit
+	{
+		$$ = &ast.JSONTableColumn{Tp: ast.JSONTableColumnExistsPath, Name: model.NewCIStr($1), Type: $2.(*types.FieldType), Path: $5}
+	}
+|	"NESTED" NestedPathKwdOpt stringLit JSONTableColumnsClause
+	{
+		$$ = &ast.JSONTableColumn{Tp: ast.JSONTableColumnNested, Path: $3, NestedColumns: $4.([]*ast.JSONTableColumn)}
+	}
+
+NestedPathKwdOpt:
+	{}
+|	"PATH"
+
+JSONTableColumnOnOpt:
+	{
+		$$ = []*ast.JSONTableOnResponse{nil, nil}
+	}
+|	JSONTableOnResponse "ON" "EMPTY"
+	{
+		$$ = []*ast.JSONTableOnResponse{$1.(*ast.JSONTableOnResponse), nil}
+	}
+|	JSONTableOnResponse "ON" "ERROR"
+	{
+		$$ = []*ast.JSONTableOnResponse{nil, $1.(*ast.JSONTableOnResponse)}
+	}
+|	JSONTableOnResponse "ON" "EMPTY" JSONTableOnResponse "ON" "ERROR"
+	{
+		$$ = []*ast.JSONTableOnResponse{$1.(*ast.JSONTableOnResponse), $4.(*ast.JSONTableOnResponse)}
+	}
+
+JSONTableOnResponse:
+	"NULL"
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseNull}
+	}
+|	"ERROR"
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseError}
+	}
+|	"DEFAULT" stringLit
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseDefault, Default: $2}
+	}
 
 PartitionNameListOpt:
 	/* empty */
@@ -9127,11 +9531,12 @@ SelectStmtIntoOption:
 	{
 		$$ = nil
 	}
-|	"INTO" "OUTFILE" stringLit Fields Lines
+|	"INTO" "OUTFILE" stringLit Fields Lines OutfileCompressionOpt
 	{
 		x := &ast.SelectIntoOption{
-			Tp:       ast.SelectIntoOutfile,
-			FileName: $3,
+			Tp:          ast.SelectIntoOutfile,
+			FileName:    $3,
+			Compression: $6,
 		}
 		if $4 != nil {
 			x.FieldsInfo = $4.(*ast.FieldsClause)
@@ -9143,6 +9548,15 @@ SelectStmtIntoOption:
 		$$ = x
 	}
 
+OutfileCompressionOpt:
+	{
+		$$ = ""
+	}
+|	"COMPRESSION" EqOpt stringLit
+	{
+		$$ = strings.ToLower($3)
+	}
+
 // See https://dev.mysql.com/doc/refman/5.7/en/subqueries.html
 SubSelect:
 	'(' SelectStmt ')'
@@ -9548,6 +9962,26 @@ SetStmt:
 	{
 		$$ = &ast.SetStmt{Variables: $2.([]*ast.VariableAssignment)}
 	}
+|	"SET" "RESOURCE" "GROUP" ResourceGroupName
+	{
+		$$ = &ast.SetResourceGroupStmt{Name: model.NewCIStr($4)}
+	}
+|	"SET" "RESOURCE" "GROUP" "DEFAULT"
+	{
+		$$ = &ast.SetResourceGroupStmt{Name: model.NewCIStr(strings.ToLower($4))}
+	}
+|	"SET" "SESSION_STATES" stringLit
+	{
+		$$ = &ast.SetSessionStatesStmt{SessionStates: $3}
+	}
+|	"SET" "SESSION" "AUTHORIZATION" Username
+	{
+		$$ = &ast.SetSessionAuthorizationStmt{User: $4.(*auth.UserIdentity)}
+	}
+|	"SET" "SESSION" "AUTHORIZATION" "DEFAULT"
+	{
+		$$ = &ast.SetSessionAuthorizationStmt{}
+	}
 |	"SET" "PASSWORD" eq PasswordOpt
 	{
 		$$ = &ast.SetPwdStmt{Password: $4}
@@ -10237,11 +10671,22 @@ ShowStmt:
 		}
 		$$ = stmt
 	}
-|	"SHOW" "CREATE" "TABLE" TableName
+|	"SHOW" "CREATE" "TABLE" TableName AsOfClauseOpt
 	{
+		tn := $4.(*ast.TableName)
+		if $5 != nil {
+			tn.AsOf = $5.(*ast.AsOfClause)
+		}
 		$$ = &ast.ShowStmt{
 			Tp:    ast.ShowCreateTable,
-			Table: $4.(*ast.TableName),
+			Table: tn,
+		}
+	}
+|	"SHOW" "STATS_DUMP" TableName
+	{
+		$$ = &ast.ShowStmt{
+			Tp:    ast.ShowStatsDump,
+			Table: $3.(*ast.TableName),
 		}
 	}
 |	"SHOW" "CREATE" "VIEW" TableName
@@ -10583,6 +11028,14 @@ ShowTargetFilterable:
 	{
 		$$ = &ast.ShowStmt{Tp: ast.ShowErrors}
 	}
+|	"LAST" "DML" "ERRORS"
+	{
+		$$ = &ast.ShowStmt{Tp: ast.ShowLastDMLErrors}
+	}
+|	"SESSION_STATES"
+	{
+		$$ = &ast.ShowStmt{Tp: ast.ShowSessionStates}
+	}
 |	GlobalScope "VARIABLES"
 	{
 		$$ = &ast.ShowStmt{
@@ -10927,6 +11380,7 @@ Statement:
 |	AlterInstanceStmt
 |	AlterSequenceStmt
 |	AlterPolicyStmt
+|	AlterResourceGroupStmt
 |	AnalyzeTableStmt
 |	BeginTransactionStmt
 |	BinlogStmt
@@ -10945,7 +11399,9 @@ Statement:
 |	CreateUserStmt
 |	CreateRoleStmt
 |	CreateBindingStmt
+|	CreateFunctionStmt
 |	CreatePolicyStmt
+|	CreateResourceGroupStmt
 |	CreateSequenceStmt
 |	CreateStatisticsStmt
 |	DoStmt
@@ -10954,6 +11410,7 @@ Statement:
 |	DropIndexStmt
 |	DropTableStmt
 |	DropPolicyStmt
+|	DropResourceGroupStmt
 |	DropSequenceStmt
 |	DropViewStmt
 |	DropUserStmt
@@ -10961,8 +11418,10 @@ Statement:
 |	DropStatisticsStmt
 |	DropStatsStmt
 |	DropBindingStmt
+|	DropFunctionStmt
 |	FlushStmt
 |	FlashbackTableStmt
+|	FlashbackDatabaseStmt
 |	GrantStmt
 |	GrantProxyStmt
 |	GrantRoleStmt
@@ -10972,6 +11431,7 @@ Statement:
 |	KillStmt
 |	LoadDataStmt
 |	LoadStatsStmt
+|	NonTransactionalDMLStmt
 |	PlanReplayerStmt
 |	PreparedStmt
 |	PurgeImportStmt
@@ -10980,9 +11440,12 @@ Statement:
 |	RenameUserStmt
 |	ReplaceIntoStmt
 |	RecoverTableStmt
+|	ReleaseSavepointStmt
 |	ResumeImportStmt
 |	RevokeStmt
 |	RevokeRoleStmt
+|	RevokeProxyStmt
+|	SavepointStmt
 |	SetOprStmt
 |	SelectStmt
 |	SelectStmtWithClause
@@ -11012,6 +11475,8 @@ Statement:
 |	UseStmt
 |	UnlockTablesStmt
 |	LockTablesStmt
+|	LockStatsStmt
+|	UnlockStatsStmt
 |	ShutdownStmt
 |	RestartStmt
 |	HelpStmt
@@ -11279,6 +11744,10 @@ TableOption:
 	{
 		$$ = &ast.TableOption{Tp: ast.TableOptionPreSplitRegion, UintValue: $3.(uint64)}
 	}
+|	"GC_LIFE_TIME" EqOpt stringLit
+	{
+		$$ = &ast.TableOption{Tp: ast.TableOptionGCLifeTime, StrValue: $3}
+	}
 |	"PACK_KEYS" EqOpt StatsPersistentVal
 	{
 		// Parse it but will ignore it.
@@ -11772,6 +12241,14 @@ StringType:
 		x.Collate = charset.CollationBin
 		$$ = x
 	}
+|	"VECTOR" OptFieldLen
+	{
+		x := types.NewFieldType(mysql.TypeTiDBVectorFloat32)
+		x.Flen = $2.(int)
+		x.Charset = charset.CharsetBin
+		x.Collate = charset.CollationBin
+		$$ = x
+	}
 |	"LONG" Varchar OptCharsetWithOptBinary
 	{
 		x := types.NewFieldType(mysql.TypeMediumBlob)
@@ -12179,16 +12656,17 @@ CommaOpt:
  *  https://dev.mysql.com/doc/refman/5.7/en/account-management-sql.html
  ************************************************************************************/
 CreateUserStmt:
-	"CREATE" "USER" IfNotExists UserSpecList RequireClauseOpt ConnectionOptions PasswordOrLockOptions
+	"CREATE" "USER" IfNotExists UserSpecList RequireClauseOpt ConnectionOptions PasswordOrLockOptions ResourceGroupNameOption
 	{
 		// See https://dev.mysql.com/doc/refman/5.7/en/create-user.html
 		$$ = &ast.CreateUserStmt{
-			IsCreateRole:          false,
-			IfNotExists:           $3.(bool),
-			Specs:                 $4.([]*ast.UserSpec),
-			TLSOptions:            $5.([]*ast.TLSOption),
-			ResourceOptions:       $6.([]*ast.ResourceOption),
-			PasswordOrLockOptions: $7.([]*ast.PasswordOrLockOption),
+			IsCreateRole:            false,
+			IfNotExists:             $3.(bool),
+			Specs:                   $4.([]*ast.UserSpec),
+			TLSOptions:              $5.([]*ast.TLSOption),
+			ResourceOptions:         $6.([]*ast.ResourceOption),
+			PasswordOrLockOptions:   $7.([]*ast.PasswordOrLockOption),
+			ResourceGroupNameOption: $8.(*ast.ResourceGroupNameOption),
 		}
 	}
 
@@ -12205,14 +12683,15 @@ CreateRoleStmt:
 
 /* See http://dev.mysql.com/doc/refman/5.7/en/alter-user.html */
 AlterUserStmt:
-	"ALTER" "USER" IfExists UserSpecList RequireClauseOpt ConnectionOptions PasswordOrLockOptions
+	"ALTER" "USER" IfExists UserSpecList RequireClauseOpt ConnectionOptions PasswordOrLockOptions ResourceGroupNameOption
 	{
 		$$ = &ast.AlterUserStmt{
-			IfExists:              $3.(bool),
-			Specs:                 $4.([]*ast.UserSpec),
-			TLSOptions:            $5.([]*ast.TLSOption),
-			ResourceOptions:       $6.([]*ast.ResourceOption),
-			PasswordOrLockOptions: $7.([]*ast.PasswordOrLockOption),
+			IfExists:                $3.(bool),
+			Specs:                   $4.([]*ast.UserSpec),
+			TLSOptions:              $5.([]*ast.TLSOption),
+			ResourceOptions:         $6.([]*ast.ResourceOption),
+			PasswordOrLockOptions:   $7.([]*ast.PasswordOrLockOption),
+			ResourceGroupNameOption: $8.(*ast.ResourceGroupNameOption),
 		}
 	}
 |	"ALTER" "USER" IfExists "USER" '(' ')' "IDENTIFIED" "BY" AuthString
@@ -12248,6 +12727,12 @@ InstanceOption:
 			NoRollbackOnError: true,
 		}
 	}
+|	"ROTATE" "MASTER" "KEY"
+	{
+		$$ = &ast.AlterInstanceStmt{
+			RotateMasterKey: true,
+		}
+	}
 
 UserSpec:
 	Username AuthOption
@@ -12406,6 +12891,19 @@ RequireListElement:
 		}
 	}
 
+ResourceGroupNameOption:
+	{
+		$$ = (*ast.ResourceGroupNameOption)(nil)
+	}
+|	"RESOURCE" "GROUP" ResourceGroupName
+	{
+		$$ = &ast.ResourceGroupNameOption{Value: $3}
+	}
+|	"RESOURCE" "GROUP" "DEFAULT"
+	{
+		$$ = &ast.ResourceGroupNameOption{Value: strings.ToLower($3)}
+	}
+
 PasswordOrLockOptions:
 	{
 		l := []*ast.PasswordOrLockOption{}
@@ -12414,8 +12912,6 @@ PasswordOrLockOptions:
 |	PasswordOrLockOptionList
 	{
 		$$ = $1
-		yylex.AppendError(yylex.Errorf("TiDB does not support PASSWORD EXPIRE and ACCOUNT LOCK now, they would be parsed but ignored."))
-		parser.lastErrorAsWarn()
 	}
 
 PasswordOrLockOptionList:
@@ -12468,6 +12964,52 @@ PasswordOrLockOption:
 			Type: ast.PasswordExpireDefault,
 		}
 	}
+|	"FAILED_LOGIN_ATTEMPTS" Int64Num
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type:  ast.FailedLoginAttempts,
+			Count: $2.(int64),
+		}
+	}
+|	"PASSWORD_LOCK_TIME" Int64Num
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type:  ast.PasswordLockTime,
+			Count: $2.(int64),
+		}
+	}
+|	"PASSWORD_LOCK_TIME" "UNBOUNDED"
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type: ast.PasswordLockTimeUnbounded,
+		}
+	}
+|	"PASSWORD" "HISTORY" Int64Num
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type:  ast.PasswordHistory,
+			Count: $3.(int64),
+		}
+	}
+|	"PASSWORD" "HISTORY" "DEFAULT"
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type: ast.PasswordHistoryDefault,
+		}
+	}
+|	"PASSWORD" "REUSE" "INTERVAL" Int64Num "DAY"
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type:  ast.PasswordReuseInterval,
+			Count: $4.(int64),
+		}
+	}
+|	"PASSWORD" "REUSE" "INTERVAL" "DEFAULT"
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type: ast.PasswordReuseDefault,
+		}
+	}
 
 PasswordExpire:
 	"PASSWORD" "EXPIRE" ClearPasswordExpireOptions
@@ -12998,6 +13540,15 @@ RevokeStmt:
 		}
 	}
 
+RevokeProxyStmt:
+	"REVOKE" "PROXY" "ON" Username "FROM" UsernameList
+	{
+		$$ = &ast.RevokeProxyStmt{
+			LocalUser:     $4.(*auth.UserIdentity),
+			ExternalUsers: $6.([]*auth.UserIdentity),
+		}
+	}
+
 RevokeRoleStmt:
 	"REVOKE" RoleOrPrivElemList "FROM" UsernameList
 	{
@@ -13351,6 +13902,12 @@ KillStmt:
 			TiDBExtension: $1.(bool),
 		}
 	}
+|	"KILL" "ANALYZE" NUM
+	{
+		$$ = &ast.KillStmt{
+			AnalyzeJobID: getUint64FromNUM($3),
+		}
+	}
 
 KillOrKillTiDB:
 	"KILL"
@@ -13371,6 +13928,28 @@ LoadStatsStmt:
 			Path: $3,
 		}
 	}
+|	"LOAD" "STATS" "FROM" stringLit
+	{
+		$$ = &ast.LoadStatsStmt{
+			JSON: $4,
+		}
+	}
+
+LockStatsStmt:
+	"LOCK" "STATS" TableNameList
+	{
+		$$ = &ast.LockStatsStmt{
+			Tables: $3.([]*ast.TableName),
+		}
+	}
+
+UnlockStatsStmt:
+	"UNLOCK" "STATS" TableNameList
+	{
+		$$ = &ast.UnlockStatsStmt{
+			Tables: $3.([]*ast.TableName),
+		}
+	}
 
 DropPolicyStmt:
 	"DROP" "PLACEMENT" "POLICY" IfExists PolicyName
@@ -13402,6 +13981,90 @@ AlterPolicyStmt:
 		}
 	}
 
+DropResourceGroupStmt:
+	"DROP" "RESOURCE" "GROUP" IfExists ResourceGroupName
+	{
+		$$ = &ast.DropResourceGroupStmt{
+			IfExists:          $4.(bool),
+			ResourceGroupName: model.NewCIStr($5),
+		}
+	}
+
+CreateResourceGroupStmt:
+	"CREATE" "RESOURCE" "GROUP" IfNotExists ResourceGroupName ResourceGroupOptionList
+	{
+		$$ = &ast.CreateResourceGroupStmt{
+			IfNotExists:             $4.(bool),
+			ResourceGroupName:       model.NewCIStr($5),
+			ResourceGroupOptionList: $6.([]*ast.ResourceGroupOption),
+		}
+	}
+
+AlterResourceGroupStmt:
+	"ALTER" "RESOURCE" "GROUP" IfExists ResourceGroupName ResourceGroupOptionList
+	{
+		$$ = &ast.AlterResourceGroupStmt{
+			IfExists:                $4.(bool),
+			ResourceGroupName:       model.NewCIStr($5),
+			ResourceGroupOptionList: $6.([]*ast.ResourceGroupOption),
+		}
+	}
+
+/********************************************************************************************
+ *
+ *  Create Function Statement
+ *
+ *  Example:
+ *	CREATE FUNCTION [IF NOT EXISTS] function_name ([param_name type[, ...]])
+ *	RETURNS type
+ *	LANGUAGE language
+ *	AS function_body
+ ********************************************************************************************/
+CreateFunctionStmt:
+	"CREATE" "FUNCTION" IfNotExists Identifier '(' FunctionParamListOpt ')' "RETURNS" Type "LANGUAGE" Identifier "AS" FunctionBody
+	{
+		$$ = &ast.CreateFunctionStmt{
+			IfNotExists: $3.(bool),
+			FuncName:    model.NewCIStr($4),
+			Params:      $6.([]*ast.FunctionParam),
+			ReturnType:  $9.(*types.FieldType),
+			Language:    strings.ToUpper($11),
+			Body:        $13.(ast.ExprNode),
+		}
+	}
+
+FunctionParamListOpt:
+	{
+		$$ = []*ast.FunctionParam{}
+	}
+|	FunctionParamList
+
+FunctionParamList:
+	FunctionParam
+	{
+		$$ = []*ast.FunctionParam{$1.(*ast.FunctionParam)}
+	}
+|	FunctionParamList ',' FunctionParam
+	{
+		$$ = append($1.([]*ast.FunctionParam), $3.(*ast.FunctionParam))
+	}
+
+FunctionParam:
+	Identifier Type
+	{
+		$$ = &ast.FunctionParam{Name: model.NewCIStr($1), Tp: $2.(*types.FieldType)}
+	}
+
+FunctionBody:
+	stringLit
+	{
+		$$ = ast.NewValueExpr($1, "", "")
+	}
+|	hexLit
+	{
+		$$ = ast.NewValueExpr($1, "", "")
+	}
+
 /********************************************************************************************
  *
  *  Create Sequence Statement
@@ -13529,6 +14192,15 @@ SignedNum:
 		}
 	}
 
+DropFunctionStmt:
+	"DROP" "FUNCTION" IfExists Identifier
+	{
+		$$ = &ast.DropFunctionStmt{
+			IfExists: $3.(bool),
+			FuncName: model.NewCIStr($4),
+		}
+	}
+
 DropSequenceStmt:
 	"DROP" "SEQUENCE" IfExists TableNameList
 	{
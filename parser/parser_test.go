@@ -83,9 +83,9 @@ func TestSimple(t *testing.T) {
 	unreservedKws := []string{
 		"auto_increment", "after", "begin", "bit", "bool", "boolean", "charset", "columns", "commit",
 		"date", "datediff", "datetime", "deallocate", "do", "from_days", "end", "engine", "engines", "execute", "extended", "first", "file", "full",
-		"local", "names", "offset", "password", "prepare", "quick", "rollback", "session", "signed",
+		"local", "names", "offset", "password", "prepare", "quick", "rollback", "savepoint", "session", "signed",
 		"start", "global", "tables", "tablespace", "target", "text", "time", "timestamp", "tidb", "transaction", "truncate", "unknown",
-		"value", "warnings", "year", "now", "substr", "subpartition", "subpartitions", "substring", "mode", "any", "some", "user", "identified",
+		"value", "warnings", "dml", "batch", "dry", "run", "nested", "ordinality", "path", "empty", "returns", "year", "now", "substr", "subpartition", "subpartitions", "substring", "mode", "any", "some", "user", "identified",
 		"collation", "comment", "avg_row_length", "checksum", "compression", "connection", "key_block_size",
 		"max_rows", "min_rows", "national", "quarter", "escape", "grants", "status", "fields", "triggers", "language",
 		"delay_key_write", "isolation", "partitions", "repeatable", "committed", "uncommitted", "only", "serializable", "level",
@@ -98,7 +98,7 @@ func TestSimple(t *testing.T) {
 		"max_connections_per_hour", "max_queries_per_hour", "max_updates_per_hour", "max_user_connections", "event", "reload", "routine", "temporary",
 		"following", "preceding", "unbounded", "respect", "nulls", "current", "last", "against", "expansion",
 		"chain", "error", "general", "nvarchar", "pack_keys", "p", "shard_row_id_bits", "pre_split_regions",
-		"constraints", "role", "replicas", "policy", "s3", "strict", "running", "stop", "preserve", "placement",
+		"constraints", "role", "replicas", "policy", "s3", "strict", "running", "stop", "preserve", "placement", "member", "array",
 	}
 	for _, kw := range unreservedKws {
 		src := fmt.Sprintf("SELECT %s FROM tbl;", kw)
@@ -536,6 +536,17 @@ func TestDMLStmt(t *testing.T) {
 		{"ROLLBACK AND NO CHAIN RELEASE", true, "ROLLBACK RELEASE"},
 		{"ROLLBACK AND CHAIN NO RELEASE", true, "ROLLBACK AND CHAIN"},
 		{"ROLLBACK AND CHAIN RELEASE", false, ""},
+		{"SAVEPOINT s1", true, "SAVEPOINT `s1`"},
+		{"SAVEPOINT `savepoint`", true, "SAVEPOINT `savepoint`"},
+		{"SAVEPOINT", false, ""},
+		{"ROLLBACK TO s1", true, "ROLLBACK TO `s1`"},
+		{"ROLLBACK TO SAVEPOINT s1", true, "ROLLBACK TO `s1`"},
+		{"ROLLBACK TO savepoint", true, "ROLLBACK TO `savepoint`"},
+		{"ROLLBACK TO SAVEPOINT savepoint", true, "ROLLBACK TO `savepoint`"},
+		{"ROLLBACK TO", false, ""},
+		{"RELEASE SAVEPOINT s1", true, "RELEASE SAVEPOINT `s1`"},
+		{"RELEASE SAVEPOINT", false, ""},
+		{"RELEASE s1", false, ""},
 		{`BEGIN;
 			INSERT INTO foo VALUES (42, 3.14);
 			INSERT INTO foo VALUES (-1, 2.78);
@@ -692,6 +703,9 @@ func TestDMLStmt(t *testing.T) {
 		{"select a,b,a+b from t into outfile '/tmp/result.txt' fields terminated BY ',' enclosed BY '\"' lines terminated BY '\r'", true, "SELECT `a`,`b`,`a`+`b` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' ENCLOSED BY '\"' LINES TERMINATED BY '\r'"},
 		{"select a,b,a+b from t into outfile '/tmp/result.txt' fields terminated BY ',' optionally enclosed BY '\"' lines starting by 'xy' terminated BY '\r'", true, "SELECT `a`,`b`,`a`+`b` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '\"' LINES STARTING BY 'xy' TERMINATED BY '\r'"},
 		{"select a,b,a+b from t into outfile '/tmp/result.txt' fields terminated BY ',' enclosed BY '\"' lines starting by 'xy' terminated BY '\r'", true, "SELECT `a`,`b`,`a`+`b` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' ENCLOSED BY '\"' LINES STARTING BY 'xy' TERMINATED BY '\r'"},
+		{"select a from t into outfile 's3://bucket/result.csv.gz' compression 'GZIP'", true, "SELECT `a` FROM `t` INTO OUTFILE 's3://bucket/result.csv.gz' COMPRESSION 'gzip'"},
+		{"select a from t into outfile '/tmp/result.txt' fields terminated BY ',' compression = 'zstd'", true, "SELECT `a` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' COMPRESSION 'zstd'"},
+		{"select a from t into outfile '/tmp/result.txt' compression", false, ""},
 
 		// from join
 		{"SELECT * from t1, t2, t3", true, "SELECT * FROM ((`t1`) JOIN `t2`) JOIN `t3`"},
@@ -731,6 +745,35 @@ func TestDMLStmt(t *testing.T) {
 		{"DELETE FROM t1 AS w WHERE a > 0", true, "DELETE FROM `t1` AS `w` WHERE `a`>0"},
 		{"DELETE from t1 partition (p0,p1)", true, "DELETE FROM `t1` PARTITION(`p0`, `p1`)"},
 
+		// non-transactional DML
+		{"batch limit 1 delete from t", true, "BATCH LIMIT 1 DELETE FROM `t`"},
+		{"batch on c limit 10 delete from t where c = 10", true, "BATCH ON `c` LIMIT 10 DELETE FROM `t` WHERE `c`=10"},
+		{"batch on test.t.c limit 10 dry run delete from test.t where c = 10", true, "BATCH ON `test`.`t`.`c` LIMIT 10 DRY RUN DELETE FROM `test`.`t` WHERE `c`=10"},
+		{"batch on c limit 10 dry run query delete from t where c = 10", true, "BATCH ON `c` LIMIT 10 DRY RUN QUERY DELETE FROM `t` WHERE `c`=10"},
+		{"batch on c limit 10 update t set b = b + 1 where c > 10", true, "BATCH ON `c` LIMIT 10 UPDATE `t` SET `b`=`b`+1 WHERE `c`>10"},
+		{"batch limit 10 dry run query update t set b = 1", true, "BATCH LIMIT 10 DRY RUN QUERY UPDATE `t` SET `b`=1"},
+		{"batch on c limit 10 delete from t1 using t1, t2", false, ""},
+		{"batch on c delete from t", false, ""},
+		{"batch on c limit 10 dry delete from t", false, ""},
+		{"batch on c limit 10 insert into t values (1)", false, ""},
+
+		// JSON_TABLE
+		{"select * from json_table('[1, 2]', '$[*]' columns(a int path '$')) as jt", true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[1, 2]', '$[*]' COLUMNS(`a` INT PATH '$')) AS `jt`"},
+		{"select jt.* from t, json_table(t.doc, '$.items[*]' columns(id for ordinality, name varchar(10) path '$.name' default '\"x\"' on empty null on error, has_price int exists path '$.price', nested path '$.tags[*]' columns(tag text path '$'))) jt", true, "SELECT `jt`.* FROM (`t`) JOIN JSON_TABLE(`t`.`doc`, '$.items[*]' COLUMNS(`id` FOR ORDINALITY, `name` VARCHAR(10) PATH '$.name' DEFAULT '\"x\"' ON EMPTY NULL ON ERROR, `has_price` INT EXISTS PATH '$.price', NESTED PATH '$.tags[*]' COLUMNS(`tag` TEXT PATH '$'))) AS `jt`"},
+		{"select * from json_table('{}', '$' columns(a json path '$.a' error on error, nested '$.b' columns(b int path '$'))) as jt", true, "SELECT * FROM JSON_TABLE(_UTF8MB4'{}', '$' COLUMNS(`a` JSON PATH '$.a' ERROR ON ERROR, NESTED PATH '$.b' COLUMNS(`b` INT PATH '$'))) AS `jt`"},
+		{"select * from json_table('{}', '$' columns(a int path '$.a' error on error error on empty)) as jt", false, ""},
+		{"select * from json_table('{}', '$' columns(a int path '$.a'))", false, ""},
+		{"select * from json_table('{}', '$' columns()) as jt", false, ""},
+
+		// LATERAL derived tables
+		{"select * from t1, lateral (select t1.a + 1 as b) as dt", true, "SELECT * FROM (`t1`) JOIN LATERAL (SELECT `t1`.`a`+1 AS `b`) AS `dt`"},
+		{"select * from t1 left join lateral (select max(b) as m from t2 where t2.a = t1.a) dt on true", true, "SELECT * FROM `t1` LEFT JOIN LATERAL (SELECT MAX(`b`) AS `m` FROM `t2` WHERE `t2`.`a`=`t1`.`a`) AS `dt` ON TRUE"},
+		{"select * from t1 join lateral (select a from t2 union select t1.a) dt", true, "SELECT * FROM `t1` JOIN LATERAL (SELECT `a` FROM `t2` UNION SELECT `t1`.`a`) AS `dt`"},
+		{"select * from t1, lateral (select t1.a) as dt(x)", false, ""},
+		{"select * from t1, lateral (select t1.a)", false, ""},
+		{"select * from t1, lateral t2", false, ""},
+		{"select lateral from t", false, ""},
+
 		// multi table syntax: before from
 		{"delete low_priority t1, t2 from t1, t2", true, "DELETE LOW_PRIORITY `t1`,`t2` FROM (`t1`) JOIN `t2`"},
 		{"delete quick t1, t2 from t1, t2", true, "DELETE QUICK `t1`,`t2` FROM (`t1`) JOIN `t2`"},
@@ -874,6 +917,14 @@ func TestDMLStmt(t *testing.T) {
 		{"select 1 group by 1", true, "SELECT 1 GROUP BY 1"},
 		{"select 1 from dual group by 1", true, "SELECT 1 GROUP BY 1"},
 
+		// for WITH ROLLUP
+		{"select a, b, sum(c) from t group by a, b with rollup", true, "SELECT `a`,`b`,SUM(`c`) FROM `t` GROUP BY `a`,`b` WITH ROLLUP"},
+		{"select a, grouping(a) from t group by a with rollup having grouping(a) = 0 order by a", true, "SELECT `a`,GROUPING(`a`) FROM `t` GROUP BY `a` WITH ROLLUP HAVING GROUPING(`a`)=0 ORDER BY `a`"},
+		{"select a from t group by a with rollup union select 1", true, "SELECT `a` FROM `t` GROUP BY `a` WITH ROLLUP UNION SELECT 1"},
+		{"select rollup from t group by rollup with rollup", true, "SELECT `rollup` FROM `t` GROUP BY `rollup` WITH ROLLUP"},
+		{"select a from t group by a with", false, ""},
+		{"select a from t with rollup", false, ""},
+
 		// for https://github.com/pingcap/parser/issues/963
 		{"select min(b) b from (select min(t.b) b from t where t.a = '');", true, "SELECT MIN(`b`) AS `b` FROM (SELECT MIN(`t`.`b`) AS `b` FROM `t` WHERE `t`.`a`=_UTF8MB4'')"},
 		{"select min(b) b from (select min(t.b) b from t where t.a = '') as t1;", true, "SELECT MIN(`b`) AS `b` FROM (SELECT MIN(`t`.`b`) AS `b` FROM `t` WHERE `t`.`a`=_UTF8MB4'') AS `t1`"},
@@ -962,6 +1013,10 @@ AAAAAAAAAAAA5gm5Mg==
 		{"show table t1 regions", true, "SHOW TABLE `t1` REGIONS"},
 		{"show table t1 regions where a=1", true, "SHOW TABLE `t1` REGIONS WHERE `a`=1"},
 		{"show table t1", false, ""},
+		{"show last dml errors", true, "SHOW LAST DML ERRORS"},
+		{"show last dml errors where code = 1062", true, "SHOW LAST DML ERRORS WHERE `code`=1062"},
+		{"show session_states", true, "SHOW SESSION_STATES"},
+		{"show last errors", false, ""},
 		{"show table t1 index idx1 regions", true, "SHOW TABLE `t1` INDEX `idx1` REGIONS"},
 		{"show table t1 index idx1 regions where a=2", true, "SHOW TABLE `t1` INDEX `idx1` REGIONS WHERE `a`=2"},
 		{"show table t1 index idx1", false, ""},
@@ -994,6 +1049,8 @@ AAAAAAAAAAAA5gm5Mg==
 		// for alter instance.
 		{"ALTER INSTANCE RELOAD TLS", true, "ALTER INSTANCE RELOAD TLS"},
 		{"ALTER INSTANCE RELOAD TLS NO ROLLBACK ON ERROR", true, "ALTER INSTANCE RELOAD TLS NO ROLLBACK ON ERROR"},
+		{"ALTER INSTANCE ROTATE MASTER KEY", true, "ALTER INSTANCE ROTATE MASTER KEY"},
+		{"ALTER INSTANCE ROTATE KEY", false, ""},
 
 		// for create sequence with signed value especially with Two's Complement Min.
 		// for issue #17948
@@ -1099,6 +1156,8 @@ func TestDBAStmt(t *testing.T) {
 		// for show create table
 		{"show create table test.t", true, "SHOW CREATE TABLE `test`.`t`"},
 		{"show create table t", true, "SHOW CREATE TABLE `t`"},
+		{"show create table t as of timestamp '2021-04-15 00:00:00'", true, "SHOW CREATE TABLE `t` AS OF TIMESTAMP _UTF8MB4'2021-04-15 00:00:00'"},
+		{"show create table test.t as of timestamp now() - interval 5 second", true, "SHOW CREATE TABLE `test`.`t` AS OF TIMESTAMP DATE_SUB(NOW(), INTERVAL 5 SECOND)"},
 		// for show create view
 		{"show create view test.t", true, "SHOW CREATE VIEW `test`.`t`"},
 		{"show create view t", true, "SHOW CREATE VIEW `t`"},
@@ -1151,6 +1210,19 @@ func TestDBAStmt(t *testing.T) {
 
 		// for load stats
 		{"load stats '/tmp/stats.json'", true, "LOAD STATS '/tmp/stats.json'"},
+		{`load stats from '{"database_name":"test","table_name":"t"}'`, true, `LOAD STATS FROM '{"database_name":"test","table_name":"t"}'`},
+		{"load stats from", false, ""},
+		// for show stats_dump
+		{"show stats_dump t", true, "SHOW STATS_DUMP `t`"},
+		{"show stats_dump test.t", true, "SHOW STATS_DUMP `test`.`t`"},
+		{"show stats_dump", false, ""},
+		// for lock stats and unlock stats
+		{"lock stats t", true, "LOCK STATS `t`"},
+		{"lock stats t1, test.t2", true, "LOCK STATS `t1`, `test`.`t2`"},
+		{"lock stats", false, ""},
+		{"unlock stats t", true, "UNLOCK STATS `t`"},
+		{"unlock stats t1, test.t2", true, "UNLOCK STATS `t1`, `test`.`t2`"},
+		{"unlock stats", false, ""},
 		// set
 		// user defined
 		{"SET @ = 1", true, "SET @``=1"},
@@ -1222,6 +1294,15 @@ func TestDBAStmt(t *testing.T) {
 		{"set char set default", true, "SET CHARSET DEFAULT"},
 
 		{"set role `role1`", true, "SET ROLE `role1`@`%`"},
+		{"set resource group rg1", true, "SET RESOURCE GROUP `rg1`"},
+		{"set resource group `default`", true, "SET RESOURCE GROUP `default`"},
+		{"set resource group default", true, "SET RESOURCE GROUP `default`"},
+		{`set session_states '{"current-db":"test"}'`, true, `SET SESSION_STATES '{"current-db":"test"}'`},
+		{"set session authorization 'u1'@'localhost'", true, "SET SESSION AUTHORIZATION `u1`@`localhost`"},
+		{"set session authorization u1", true, "SET SESSION AUTHORIZATION `u1`@`%`"},
+		{"set session authorization default", true, "SET SESSION AUTHORIZATION DEFAULT"},
+		{"set session authorization = 1", true, "SET @@SESSION.`authorization`=1"},
+		{"set session_states", false, ""},
 		{"SET ROLE DEFAULT", true, "SET ROLE DEFAULT"},
 		{"SET ROLE ALL", true, "SET ROLE ALL"},
 		{"SET ROLE ALL EXCEPT `role1`, `role2`", true, "SET ROLE ALL EXCEPT `role1`@`%`, `role2`@`%`"},
@@ -1551,6 +1632,10 @@ func TestBuiltin(t *testing.T) {
 
 		// for cast as JSON
 		{"SELECT *, CAST(data AS JSON) FROM t;", true, "SELECT *,CAST(`data` AS JSON) FROM `t`"},
+		// for cast as array
+		{"SELECT CAST(a->'$.tags' AS UNSIGNED ARRAY) FROM t;", true, "SELECT CAST(JSON_EXTRACT(`a`, _UTF8MB4'$.tags') AS UNSIGNED ARRAY) FROM `t`"},
+		{"SELECT CAST(a AS CHAR(10) ARRAY) FROM t;", true, "SELECT CAST(`a` AS CHAR(10) ARRAY) FROM `t`"},
+		{"SELECT CONVERT(a, SIGNED ARRAY) FROM t;", false, ""},
 
 		// for cast as signed int, fix issue #3691.
 		{"select cast(1 as signed int);", true, "SELECT CAST(1 AS SIGNED)"},
@@ -1868,6 +1953,13 @@ func TestBuiltin(t *testing.T) {
 		{`select date_add("2011-11-11 10:10:10.123456", interval "10:10:10" hour_second)`, true, "SELECT DATE_ADD(_UTF8MB4'2011-11-11 10:10:10.123456', INTERVAL _UTF8MB4'10:10:10' HOUR_SECOND)"},
 		{`select date_add("2011-11-11 10:10:10.123456", interval "10:10" hour_minute)`, true, "SELECT DATE_ADD(_UTF8MB4'2011-11-11 10:10:10.123456', INTERVAL _UTF8MB4'10:10' HOUR_MINUTE)"},
 		{`select date_add("2011-11-11 10:10:10.123456", interval 10.10 hour_minute)`, true, "SELECT DATE_ADD(_UTF8MB4'2011-11-11 10:10:10.123456', INTERVAL 10.10 HOUR_MINUTE)"},
+		// for time_bucket
+		{`select time_bucket(interval 5 minute, "2011-11-11 10:10:10.123456")`, true, "SELECT TIME_BUCKET(INTERVAL 5 MINUTE, _UTF8MB4'2011-11-11 10:10:10.123456')"},
+		{`select time_bucket(interval "1 12" day_hour, ts, "2011-11-11") from t`, true, "SELECT TIME_BUCKET(INTERVAL _UTF8MB4'1 12' DAY_HOUR, `ts`, _UTF8MB4'2011-11-11') FROM `t`"},
+		{`select time_bucket(interval 1 month + 1 quarter, ts) from t`, false, ""},
+		{`select time_bucket(interval 1 + 1 month, ts) from t`, true, "SELECT TIME_BUCKET(INTERVAL 1+1 MONTH, `ts`) FROM `t`"},
+		{`select time_bucket(1, ts) from t`, false, ""},
+		{`select time_bucket from t`, true, "SELECT `time_bucket` FROM `t`"},
 		{`select date_add("2011-11-11 10:10:10.123456", interval "11 10:10:10.10" day_microsecond)`, true, "SELECT DATE_ADD(_UTF8MB4'2011-11-11 10:10:10.123456', INTERVAL _UTF8MB4'11 10:10:10.10' DAY_MICROSECOND)"},
 		{`select date_add("2011-11-11 10:10:10.123456", interval "11 10:10:10" day_second)`, true, "SELECT DATE_ADD(_UTF8MB4'2011-11-11 10:10:10.123456', INTERVAL _UTF8MB4'11 10:10:10' DAY_SECOND)"},
 		{`select date_add("2011-11-11 10:10:10.123456", interval "11 10:10" day_minute)`, true, "SELECT DATE_ADD(_UTF8MB4'2011-11-11 10:10:10.123456', INTERVAL _UTF8MB4'11 10:10' DAY_MINUTE)"},
@@ -2105,6 +2197,12 @@ func TestBuiltin(t *testing.T) {
 		// For two json grammar sugar.
 		{`SELECT a->'$.a' FROM t`, true, "SELECT JSON_EXTRACT(`a`, _UTF8MB4'$.a') FROM `t`"},
 		{`SELECT a->>'$.a' FROM t`, true, "SELECT JSON_UNQUOTE(JSON_EXTRACT(`a`, _UTF8MB4'$.a')) FROM `t`"},
+
+		// For MEMBER OF.
+		{`SELECT 1 MEMBER OF ('[1,2]')`, true, "SELECT 1 MEMBER OF (_UTF8MB4'[1,2]')"},
+		{`SELECT * FROM t WHERE 3 member of (a->'$.tags')`, true, "SELECT * FROM `t` WHERE 3 MEMBER OF (JSON_EXTRACT(`a`, _UTF8MB4'$.tags'))"},
+		{`SELECT a member of (b) AS member FROM t`, true, "SELECT `a` MEMBER OF (`b`) AS `member` FROM `t`"},
+		{`SELECT a member of b FROM t`, false, ""},
 		{`SELECT '{}'->'$.a' FROM t`, false, ""},
 		{`SELECT '{}'->>'$.a' FROM t`, false, ""},
 		{`SELECT a->3 FROM t`, false, ""},
@@ -2243,6 +2341,12 @@ func TestDDL(t *testing.T) {
 		{"create global temporary table t (a int, b varchar(255)) on commit preserve rows", true, "CREATE GLOBAL TEMPORARY TABLE `t` (`a` INT,`b` VARCHAR(255)) ON COMMIT PRESERVE ROWS"},
 		{"drop global temporary table t", true, "DROP GLOBAL TEMPORARY TABLE `t`"},
 		{"drop temporary table t", true, "DROP TEMPORARY TABLE `t`"},
+		// for external table
+		{"create external table t (a int, b varchar(255)) location 's3://bucket/prefix' format csv", true, "CREATE EXTERNAL TABLE `t` (`a` INT,`b` VARCHAR(255)) LOCATION 's3://bucket/prefix' FORMAT CSV"},
+		{"create external table if not exists t (a int) comment 'ext' location '/tmp/data.csv' format CSV", true, "CREATE EXTERNAL TABLE IF NOT EXISTS `t` (`a` INT) COMMENT = 'ext' LOCATION '/tmp/data.csv' FORMAT CSV"},
+		{"create external table t (a int) format csv", false, ""},
+		{"create external table t (a int) location 's3://bucket/prefix'", false, ""},
+		{"create table external (external int)", true, "CREATE TABLE `external` (`external` INT)"},
 		// test use key word as column name
 		{"CREATE TABLE foo (pump varchar(50), b int);", true, "CREATE TABLE `foo` (`pump` VARCHAR(50),`b` INT)"},
 		{"CREATE TABLE foo (drainer varchar(50), b int);", true, "CREATE TABLE `foo` (`drainer` VARCHAR(50),`b` INT)"},
@@ -2628,6 +2732,7 @@ func TestDDL(t *testing.T) {
 		{"CREATE TABLE t (c TEXT) default CHARACTER SET utf8, default COLLATE utf8_general_ci;", true, "CREATE TABLE `t` (`c` TEXT) DEFAULT CHARACTER SET = UTF8 DEFAULT COLLATE = UTF8_GENERAL_CI"},
 		{"CREATE TABLE t (c TEXT) shard_row_id_bits = 1;", true, "CREATE TABLE `t` (`c` TEXT) SHARD_ROW_ID_BITS = 1"},
 		{"CREATE TABLE t (c TEXT) shard_row_id_bits = 1, PRE_SPLIT_REGIONS = 1;", true, "CREATE TABLE `t` (`c` TEXT) SHARD_ROW_ID_BITS = 1 PRE_SPLIT_REGIONS = 1"},
+		{"CREATE TABLE t (c TEXT) gc_life_time = '48h';", true, "CREATE TABLE `t` (`c` TEXT) GC_LIFE_TIME = '48h'"},
 		// Create table with ON UPDATE CURRENT_TIMESTAMP(6), specify fraction part.
 		{"CREATE TABLE IF NOT EXISTS `general_log` (`event_time` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),`user_host` mediumtext NOT NULL,`thread_id` bigint(20) unsigned NOT NULL,`server_id` int(10) unsigned NOT NULL,`command_type` varchar(64) NOT NULL,`argument` mediumblob NOT NULL) ENGINE=CSV DEFAULT CHARSET=utf8 COMMENT='General log'", true, "CREATE TABLE IF NOT EXISTS `general_log` (`event_time` TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),`user_host` MEDIUMTEXT NOT NULL,`thread_id` BIGINT(20) UNSIGNED NOT NULL,`server_id` INT(10) UNSIGNED NOT NULL,`command_type` VARCHAR(64) NOT NULL,`argument` MEDIUMBLOB NOT NULL) ENGINE = CSV DEFAULT CHARACTER SET = UTF8 COMMENT = 'General log'"}, //TODO: The number yacc in parentheses has not been implemented yet.
 		// For reference_definition in column_definition.
@@ -2822,6 +2927,8 @@ func TestDDL(t *testing.T) {
 		{"ALTER TABLE t ENGINE = '', ADD COLUMN a SMALLINT", true, "ALTER TABLE `t` ENGINE = '', ADD COLUMN `a` SMALLINT"},
 		{"ALTER TABLE t default COLLATE = UTF8_GENERAL_CI, ENGINE = '', ADD COLUMN a SMALLINT", true, "ALTER TABLE `t` DEFAULT COLLATE = UTF8_GENERAL_CI, ENGINE = '', ADD COLUMN `a` SMALLINT"},
 		{"ALTER TABLE t shard_row_id_bits = 1", true, "ALTER TABLE `t` SHARD_ROW_ID_BITS = 1"},
+		{"ALTER TABLE t gc_life_time '1h30m'", true, "ALTER TABLE `t` GC_LIFE_TIME = '1h30m'"},
+		{"ALTER TABLE t gc_life_time = ''", true, "ALTER TABLE `t` GC_LIFE_TIME = ''"},
 		{"ALTER TABLE t AUTO_INCREMENT 3", true, "ALTER TABLE `t` AUTO_INCREMENT = 3"},
 		{"ALTER TABLE t AUTO_INCREMENT = 3", true, "ALTER TABLE `t` AUTO_INCREMENT = 3"},
 		{"ALTER TABLE t FORCE AUTO_INCREMENT 3", true, "ALTER TABLE `t` FORCE AUTO_INCREMENT = 3"},
@@ -3075,6 +3182,10 @@ func TestDDL(t *testing.T) {
 		// for flashback table.
 		{"flashback table t", true, "FLASHBACK TABLE `t`"},
 		{"flashback table t TO t1", true, "FLASHBACK TABLE `t` TO `t1`"},
+		// for flashback database.
+		{"flashback database db", true, "FLASHBACK DATABASE `db`"},
+		{"flashback schema db TO db1", true, "FLASHBACK DATABASE `db` TO `db1`"},
+		{"flashback database db TO", false, ""},
 
 		// for remove partitioning
 		{"alter table t remove partitioning", true, "ALTER TABLE `t` REMOVE PARTITIONING"},
@@ -3260,6 +3371,8 @@ func TestDDL(t *testing.T) {
 		{"create table a(a int, b int, key(a, (b+1)));", true, "CREATE TABLE `a` (`a` INT,`b` INT,INDEX(`a`, (`b`+1)))"},
 		{"create table a(a int, b int, key((a+1), b));", true, "CREATE TABLE `a` (`a` INT,`b` INT,INDEX((`a`+1), `b`))"},
 		{"create table a(a int, b int, key((a + 1) desc));", true, "CREATE TABLE `a` (`a` INT,`b` INT,INDEX((`a`+1)))"},
+		{"create table a(a json, key((cast(a->'$.tags' as unsigned array))));", true, "CREATE TABLE `a` (`a` JSON,INDEX((CAST(JSON_EXTRACT(`a`, _UTF8MB4'$.tags') AS UNSIGNED ARRAY))))"},
+		{"create index idx on a ((cast(a as signed array)));", true, "CREATE INDEX `idx` ON `a` ((CAST(`a` AS SIGNED ARRAY)))"},
 
 		// for create sequence
 		{"create sequence sequence", true, "CREATE SEQUENCE `sequence`"},
@@ -3373,6 +3486,27 @@ func TestDDL(t *testing.T) {
 		{"alter table t add primary key (`a`, `b`) clustered", true, "ALTER TABLE `t` ADD PRIMARY KEY(`a`, `b`) CLUSTERED"},
 		{"alter table t add primary key (`a`, `b`) nonclustered", true, "ALTER TABLE `t` ADD PRIMARY KEY(`a`, `b`) NONCLUSTERED"},
 
+		// for resource group
+		{"create resource group x ru_per_sec=1000", true, "CREATE RESOURCE GROUP `x` RU_PER_SEC = 1000"},
+		{"create resource group if not exists x ru_per_sec=1000, priority=high burstable", true, "CREATE RESOURCE GROUP IF NOT EXISTS `x` RU_PER_SEC = 1000 PRIORITY = HIGH BURSTABLE"},
+		{"create resource group x priority=low", true, "CREATE RESOURCE GROUP `x` PRIORITY = LOW"},
+		{"create resource group x priority=urgent", false, ""},
+		{"create resource group x ru_per_sec='1000'", false, ""},
+		{"create resource group x", false, ""},
+		{"alter resource group x ru_per_sec=2000 priority=medium", true, "ALTER RESOURCE GROUP `x` RU_PER_SEC = 2000 PRIORITY = MEDIUM"},
+		{"alter resource group if exists x burstable", true, "ALTER RESOURCE GROUP IF EXISTS `x` BURSTABLE"},
+		{"drop resource group x", true, "DROP RESOURCE GROUP `x`"},
+		{"drop resource group if exists x", true, "DROP RESOURCE GROUP IF EXISTS `x`"},
+		{"drop resource group x, y", false, ""},
+		{"create resource group x ru_per_sec=1000 query_limit=(exec_elapsed='10s' action=kill)", true, "CREATE RESOURCE GROUP `x` RU_PER_SEC = 1000 QUERY_LIMIT = (EXEC_ELAPSED = '10s' ACTION = KILL)"},
+		{"alter resource group x query_limit (exec_elapsed '1m30s', action cooldown)", true, "ALTER RESOURCE GROUP `x` QUERY_LIMIT = (EXEC_ELAPSED = '1m30s' ACTION = COOLDOWN)"},
+		{"create resource group x query_limit=(exec_elapsed='10' action=kill)", false, ""},
+		{"create resource group x query_limit=(exec_elapsed='10s' action=drop)", false, ""},
+		{"create resource group x query_limit=(exec_elapsed='10s')", false, ""},
+		{"create resource group x ru_per_sec=1000 egress_bytes_per_sec=1048576", true, "CREATE RESOURCE GROUP `x` RU_PER_SEC = 1000 EGRESS_BYTES_PER_SEC = 1048576"},
+		{"alter resource group x egress_bytes_per_sec 0", true, "ALTER RESOURCE GROUP `x` EGRESS_BYTES_PER_SEC = 0"},
+		{"create resource group x egress_bytes_per_sec='1m'", false, ""},
+
 		// for drop placement policy
 		{"drop placement policy x", true, "DROP PLACEMENT POLICY `x`"},
 		{"drop placement policy x, y", false, ""},
@@ -4095,6 +4229,13 @@ func TestType(t *testing.T) {
 
 		// for json type
 		{`create table t (a JSON);`, true, "CREATE TABLE `t` (`a` JSON)"},
+
+		// for vector type
+		{`create table t (a vector);`, true, "CREATE TABLE `t` (`a` VECTOR)"},
+		{`create table t (a vector(3), vector int);`, true, "CREATE TABLE `t` (`a` VECTOR(3),`vector` INT)"},
+		{`create table t (a vector(3) not null comment 'embedding');`, true, "CREATE TABLE `t` (`a` VECTOR(3) NOT NULL COMMENT 'embedding')"},
+		{`create table t (a vector());`, false, ""},
+		{`create table t (a vector(3) character set utf8mb4);`, false, ""},
 	}
 	RunTest(t, table, false)
 }
@@ -4139,6 +4280,9 @@ func TestPrivilege(t *testing.T) {
 		{"CREATE USER 'sha_test4'@'localhost' IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$%[\x03Ilf,\x10U\x12{;eJ/\x04 }\x01HRvp6DpKv02GGEdAjQfb4lXhdm2vLkQOCXZG7yVYGQO4'", true, "CREATE USER `sha_test4`@`localhost` IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$%[\x03Ilf,\x10U\x12{;eJ/\x04 }\x01HRvp6DpKv02GGEdAjQfb4lXhdm2vLkQOCXZG7yVYGQO4'"},
 		{"CREATE USER 'nopwd_native'@'localhost' IDENTIFIED WITH 'mysql_native_password'", true, "CREATE USER `nopwd_native`@`localhost` IDENTIFIED WITH 'mysql_native_password'"},
 		{"CREATE USER 'nopwd_sha'@'localhost' IDENTIFIED WITH 'caching_sha2_password'", true, "CREATE USER `nopwd_sha`@`localhost` IDENTIFIED WITH 'caching_sha2_password'"},
+		{"create user 'u1' resource group rg1", true, "CREATE USER `u1`@`%` RESOURCE GROUP `rg1`"},
+		{"alter user 'u1' resource group rg2", true, "ALTER USER `u1`@`%` RESOURCE GROUP `rg2`"},
+		{"alter user 'u1' resource group default", true, "ALTER USER `u1`@`%` RESOURCE GROUP `default`"},
 		{"CREATE ROLE `test-role`, `role1`@'localhost'", true, "CREATE ROLE `test-role`@`%`, `role1`@`localhost`"},
 		{"CREATE ROLE `test-role`", true, "CREATE ROLE `test-role`@`%`"},
 		{"CREATE ROLE role1", true, "CREATE ROLE `role1`@`%`"},
@@ -4160,6 +4304,11 @@ func TestPrivilege(t *testing.T) {
 		{"alter user 'test@localhost' password expire never;", true, "ALTER USER `test@localhost`@`%` PASSWORD EXPIRE NEVER"},
 		{"alter user 'test@localhost' password expire default;", true, "ALTER USER `test@localhost`@`%` PASSWORD EXPIRE DEFAULT"},
 		{"alter user 'test@localhost' password expire interval 3 day;", true, "ALTER USER `test@localhost`@`%` PASSWORD EXPIRE INTERVAL 3 DAY"},
+		{"alter user 'test@localhost' failed_login_attempts 3 password_lock_time 2;", true, "ALTER USER `test@localhost`@`%` FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME 2"},
+		{"alter user 'test@localhost' password_lock_time unbounded;", true, "ALTER USER `test@localhost`@`%` PASSWORD_LOCK_TIME UNBOUNDED"},
+		{"alter user 'test@localhost' password history 5 password reuse interval 365 day;", true, "ALTER USER `test@localhost`@`%` PASSWORD HISTORY 5 PASSWORD REUSE INTERVAL 365 DAY"},
+		{"alter user 'test@localhost' password history default password reuse interval default;", true, "ALTER USER `test@localhost`@`%` PASSWORD HISTORY DEFAULT PASSWORD REUSE INTERVAL DEFAULT"},
+		{"create user 'test'@'localhost' identified by 'abc' password expire interval 90 day password history 3 failed_login_attempts 5 password_lock_time 1 account lock;", true, "CREATE USER `test`@`localhost` IDENTIFIED BY 'abc' PASSWORD EXPIRE INTERVAL 90 DAY PASSWORD HISTORY 3 FAILED_LOGIN_ATTEMPTS 5 PASSWORD_LOCK_TIME 1 ACCOUNT LOCK"},
 		{"ALTER USER 'ttt' REQUIRE X509;", true, "ALTER USER `ttt`@`%` REQUIRE X509"},
 		{"ALTER USER 'ttt' REQUIRE SSL;", true, "ALTER USER `ttt`@`%` REQUIRE SSL"},
 		{"ALTER USER 'ttt' REQUIRE NONE;", true, "ALTER USER `ttt`@`%` REQUIRE NONE"},
@@ -4227,6 +4376,8 @@ func TestPrivilege(t *testing.T) {
 		{"REVOKE SELECT (col1), INSERT (col1,col2) ON mydb.mytbl FROM 'someuser'@'somehost';", true, "REVOKE SELECT (`col1`), INSERT (`col1`,`col2`) ON `mydb`.`mytbl` FROM `someuser`@`somehost`"},
 		{"REVOKE all privileges on zabbix.* FROM 'zabbix'@'localhost' identified by 'password';", true, "REVOKE ALL ON `zabbix`.* FROM `zabbix`@`localhost` IDENTIFIED BY 'password'"},
 		{"REVOKE 'role1', 'role2' FROM 'user1'@'localhost', 'user2'@'localhost';", true, "REVOKE `role1`@`%`, `role2`@`%` FROM `user1`@`localhost`, `user2`@`localhost`"},
+		{"REVOKE PROXY ON 'localuser'@'localhost' FROM 'externaluser'@'somehost'", true, "REVOKE PROXY ON `localuser`@`localhost` FROM `externaluser`@`somehost`"},
+		{"REVOKE PROXY ON 'proxied_user' FROM 'proxy_user1', 'proxy_user2'", true, "REVOKE PROXY ON `proxied_user`@`%` FROM `proxy_user1`@`%`, `proxy_user2`@`%`"},
 		{"REVOKE SHUTDOWN ON *.* FROM 'dev1'@'localhost';", true, "REVOKE SHUTDOWN ON *.* FROM `dev1`@`localhost`"},
 		{"REVOKE CONFIG ON *.* FROM 'dev1'@'localhost';", true, "REVOKE CONFIG ON *.* FROM `dev1`@`localhost`"},
 		{"REVOKE EXECUTE ON FUNCTION db.func FROM 'user'@'localhost'", true, "REVOKE EXECUTE ON FUNCTION `db`.`func` FROM `user`@`localhost`"},
@@ -4807,6 +4958,20 @@ func TestBinding(t *testing.T) {
 	require.True(t, v.GlobalScope)
 }
 
+func TestUserDefinedFunction(t *testing.T) {
+	table := []testCase{
+		{"create function f() returns bigint language wasm as x'0061736d01000000'", true, "CREATE FUNCTION `f`() RETURNS BIGINT LANGUAGE WASM AS x'0061736d01000000'"},
+		{"create function if not exists f(a int, b double) returns double language wasm as 'module'", true, "CREATE FUNCTION IF NOT EXISTS `f`(`a` INT, `b` DOUBLE) RETURNS DOUBLE LANGUAGE WASM AS 'module'"},
+		{"create function f(a bigint) returns bigint language wasm", false, ""},
+		{"create function f(a bigint) returns bigint as x'00'", false, ""},
+		{"create function f(a) returns bigint language wasm as x'00'", false, ""},
+		{"drop function f", true, "DROP FUNCTION `f`"},
+		{"drop function if exists f", true, "DROP FUNCTION IF EXISTS `f`"},
+		{"drop function db.f", false, ""},
+	}
+	RunTest(t, table, false)
+}
+
 func TestView(t *testing.T) {
 	table := []testCase{
 		{"create view v as select * from t", true, "CREATE ALGORITHM = UNDEFINED DEFINER = CURRENT_USER SQL SECURITY DEFINER VIEW `v` AS SELECT * FROM `t`"},
@@ -4818,6 +4983,8 @@ func TestView(t *testing.T) {
 		{"create or replace algorithm = merge definer = 'root' sql security definer view v as select * from t", true, "CREATE OR REPLACE ALGORITHM = MERGE DEFINER = `root`@`%` SQL SECURITY DEFINER VIEW `v` AS SELECT * FROM `t`"},
 		{"create or replace algorithm = merge definer = 'root' sql security invoker view v as select * from t", true, "CREATE OR REPLACE ALGORITHM = MERGE DEFINER = `root`@`%` SQL SECURITY INVOKER VIEW `v` AS SELECT * FROM `t`"},
 		{"create or replace algorithm = merge definer = 'root' sql security invoker view v(a,b) as select * from t", true, "CREATE OR REPLACE ALGORITHM = MERGE DEFINER = `root`@`%` SQL SECURITY INVOKER VIEW `v` (`a`,`b`) AS SELECT * FROM `t`"},
+		{"create view v as select a from t group by a with local check option", true, "CREATE ALGORITHM = UNDEFINED DEFINER = CURRENT_USER SQL SECURITY DEFINER VIEW `v` AS SELECT `a` FROM `t` GROUP BY `a` WITH LOCAL CHECK OPTION"},
+		{"create view v as select a from t group by a with rollup with local check option", true, "CREATE ALGORITHM = UNDEFINED DEFINER = CURRENT_USER SQL SECURITY DEFINER VIEW `v` AS SELECT `a` FROM `t` GROUP BY `a` WITH ROLLUP WITH LOCAL CHECK OPTION"},
 		{"create or replace algorithm = merge definer = 'root' sql security invoker view v(a,b) as select * from t with local check option", true, "CREATE OR REPLACE ALGORITHM = MERGE DEFINER = `root`@`%` SQL SECURITY INVOKER VIEW `v` (`a`,`b`) AS SELECT * FROM `t` WITH LOCAL CHECK OPTION"},
 		{"create or replace algorithm = merge definer = 'root' sql security invoker view v(a,b) as select * from t with cascaded check option", true, "CREATE OR REPLACE ALGORITHM = MERGE DEFINER = `root`@`%` SQL SECURITY INVOKER VIEW `v` (`a`,`b`) AS SELECT * FROM `t`"},
 		{"create or replace algorithm = merge definer = current_user view v as select * from t", true, "CREATE OR REPLACE ALGORITHM = MERGE DEFINER = CURRENT_USER SQL SECURITY DEFINER VIEW `v` AS SELECT * FROM `t`"},
@@ -4985,6 +5152,9 @@ func TestSessionManage(t *testing.T) {
 		{"kill tidb 23123", true, "KILL TIDB 23123"},
 		{"kill tidb connection 23123", true, "KILL TIDB 23123"},
 		{"kill tidb query 23123", true, "KILL TIDB QUERY 23123"},
+		{"kill analyze 23123", true, "KILL ANALYZE 23123"},
+		{"kill tidb analyze 23123", false, ""},
+		{"kill analyze", false, ""},
 		{"show processlist", true, "SHOW PROCESSLIST"},
 		{"show full processlist", true, "SHOW FULL PROCESSLIST"},
 		{"shutdown", true, "SHUTDOWN"},
@@ -5575,6 +5745,12 @@ func TestWindowFunctions(t *testing.T) {
 		{`SELECT AVG(val) OVER (RANGE BETWEEN INTERVAL 5 DAY PRECEDING AND INTERVAL '2:30' MINUTE_SECOND FOLLOWING) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN INTERVAL 5 DAY PRECEDING AND INTERVAL _UTF8MB4'2:30' MINUTE_SECOND FOLLOWING) FROM `t`"},
 		{`SELECT AVG(val) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM `t`"},
 		{`SELECT AVG(val) OVER (RANGE CURRENT ROW) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM `t`"},
+		{`SELECT AVG(val) OVER (ORDER BY time ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE CURRENT ROW) FROM t;`, true, "SELECT AVG(`val`) OVER (ORDER BY `time` ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE CURRENT ROW) FROM `t`"},
+		{`SELECT AVG(val) OVER (ORDER BY time RANGE UNBOUNDED PRECEDING EXCLUDE GROUP) FROM t;`, true, "SELECT AVG(`val`) OVER (ORDER BY `time` RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW EXCLUDE GROUP) FROM `t`"},
+		{`SELECT AVG(val) OVER (ORDER BY time RANGE BETWEEN 1 PRECEDING AND CURRENT ROW EXCLUDE TIES) FROM t;`, true, "SELECT AVG(`val`) OVER (ORDER BY `time` RANGE BETWEEN 1 PRECEDING AND CURRENT ROW EXCLUDE TIES) FROM `t`"},
+		{`SELECT AVG(val) OVER (ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE NO OTHERS) FROM t;`, true, "SELECT AVG(`val`) OVER (ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM `t`"},
+		{`SELECT AVG(val) OVER (ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE) FROM t;`, false, ""},
+		{`SELECT AVG(val) OVER (EXCLUDE CURRENT ROW) FROM t;`, false, ""},
 
 		// For named windows.
 		// See https://dev.mysql.com/doc/refman/8.0/en/window-functions-named-windows.html
@@ -5583,6 +5759,7 @@ func TestWindowFunctions(t *testing.T) {
 		{`SELECT FIRST_VALUE(year) OVER (w ORDER BY year) AS first FROM sales WINDOW w AS (PARTITION BY country);`, true, "SELECT FIRST_VALUE(`year`) OVER (`w` ORDER BY `year`) AS `first` FROM `sales` WINDOW `w` AS (PARTITION BY `country`)"},
 		{`SELECT RANK() OVER (w1) FROM t WINDOW w1 AS (w2), w2 AS (), w3 AS (w1);`, true, "SELECT RANK() OVER (`w1`) FROM `t` WINDOW `w1` AS (`w2`),`w2` AS (),`w3` AS (`w1`)"},
 		{`SELECT RANK() OVER w1 FROM t WINDOW w1 AS (w2), w2 AS (w3), w3 AS (w1);`, true, "SELECT RANK() OVER `w1` FROM `t` WINDOW `w1` AS (`w2`),`w2` AS (`w3`),`w3` AS (`w1`)"},
+		{`SELECT SUM(val) OVER w, COUNT(val) OVER w FROM t WINDOW w AS (ORDER BY time ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE TIES);`, true, "SELECT SUM(`val`) OVER `w`,COUNT(`val`) OVER `w` FROM `t` WINDOW `w` AS (ORDER BY `time` ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE TIES)"},
 
 		// For TSO functions
 		{`select tidb_parse_tso(1)`, true, "SELECT TIDB_PARSE_TSO(1)"},
@@ -6211,6 +6388,8 @@ func TestCTE(t *testing.T) {
 		{"with cte as (select 1) select 1 union with cte as (select 1) select * from cte;", false, ""},
 		{"with cte as (select 1) (select 1);", true, "WITH `cte` AS (SELECT 1) (SELECT 1)"},
 		{"with cte as (select 1) (select 1 union select 1)", true, "WITH `cte` AS (SELECT 1) (SELECT 1 UNION SELECT 1)"},
+		{"with rollup as (select 1) select * from rollup", true, "WITH `rollup` AS (SELECT 1) SELECT * FROM `rollup`"},
+		{"with rollup(a) as (select 1) select a from rollup group by a with rollup", true, "WITH `rollup` (`a`) AS (SELECT 1) SELECT `a` FROM `rollup` GROUP BY `a` WITH ROLLUP"},
 		{"select * from (with cte as (select 1) select 1 union select 2) qn", true, "SELECT * FROM (WITH `cte` AS (SELECT 1) SELECT 1 UNION SELECT 2) AS `qn`"},
 		{"select * from t where 1 > (with cte as (select 2) select * from cte)", true, "SELECT * FROM `t` WHERE 1>(WITH `cte` AS (SELECT 2) SELECT * FROM `cte`)"},
 		{"( with cte(n) as ( select 1 )  select n+1 from cte  union select n+2 from cte) union select 1", true, "(WITH `cte` (`n`) AS (SELECT 1) SELECT `n`+1 FROM `cte` UNION SELECT `n`+2 FROM `cte`) UNION SELECT 1"},
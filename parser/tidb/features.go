@@ -28,6 +28,8 @@ const (
 	FeatureIDForceAutoInc = "force_inc"
 	// FeatureIDPlacement is the `placement rule` feature.
 	FeatureIDPlacement = "placement"
+	// FeatureIDGCLifeTime is the `gc_life_time` feature.
+	FeatureIDGCLifeTime = "gc_life_time"
 )
 
 var featureIDs = map[string]struct{}{
@@ -37,6 +39,7 @@ var featureIDs = map[string]struct{}{
 	FeatureIDClusteredIndex: {},
 	FeatureIDForceAutoInc:   {},
 	FeatureIDPlacement:      {},
+	FeatureIDGCLifeTime:     {},
 }
 
 func CanParseFeature(fs ...string) bool {
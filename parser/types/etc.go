@@ -34,6 +34,11 @@ func IsTypeBlob(tp byte) bool {
 	}
 }
 
+// IsTypeVector returns a boolean indicating whether the tp is a vector type.
+func IsTypeVector(tp byte) bool {
+	return tp == mysql.TypeTiDBVectorFloat32
+}
+
 // IsTypeChar returns a boolean indicating
 // whether the tp is the char type like a string type or a varchar type.
 func IsTypeChar(tp byte) bool {
@@ -41,33 +46,34 @@ func IsTypeChar(tp byte) bool {
 }
 
 var type2Str = map[byte]string{
-	mysql.TypeBit:         "bit",
-	mysql.TypeBlob:        "text",
-	mysql.TypeDate:        "date",
-	mysql.TypeDatetime:    "datetime",
-	mysql.TypeUnspecified: "unspecified",
-	mysql.TypeNewDecimal:  "decimal",
-	mysql.TypeDouble:      "double",
-	mysql.TypeEnum:        "enum",
-	mysql.TypeFloat:       "float",
-	mysql.TypeGeometry:    "geometry",
-	mysql.TypeInt24:       "mediumint",
-	mysql.TypeJSON:        "json",
-	mysql.TypeLong:        "int",
-	mysql.TypeLonglong:    "bigint",
-	mysql.TypeLongBlob:    "longtext",
-	mysql.TypeMediumBlob:  "mediumtext",
-	mysql.TypeNull:        "null",
-	mysql.TypeSet:         "set",
-	mysql.TypeShort:       "smallint",
-	mysql.TypeString:      "char",
-	mysql.TypeDuration:    "time",
-	mysql.TypeTimestamp:   "timestamp",
-	mysql.TypeTiny:        "tinyint",
-	mysql.TypeTinyBlob:    "tinytext",
-	mysql.TypeVarchar:     "varchar",
-	mysql.TypeVarString:   "var_string",
-	mysql.TypeYear:        "year",
+	mysql.TypeBit:               "bit",
+	mysql.TypeBlob:              "text",
+	mysql.TypeDate:              "date",
+	mysql.TypeDatetime:          "datetime",
+	mysql.TypeUnspecified:       "unspecified",
+	mysql.TypeNewDecimal:        "decimal",
+	mysql.TypeDouble:            "double",
+	mysql.TypeEnum:              "enum",
+	mysql.TypeFloat:             "float",
+	mysql.TypeGeometry:          "geometry",
+	mysql.TypeInt24:             "mediumint",
+	mysql.TypeJSON:              "json",
+	mysql.TypeLong:              "int",
+	mysql.TypeLonglong:          "bigint",
+	mysql.TypeLongBlob:          "longtext",
+	mysql.TypeMediumBlob:        "mediumtext",
+	mysql.TypeNull:              "null",
+	mysql.TypeSet:               "set",
+	mysql.TypeShort:             "smallint",
+	mysql.TypeString:            "char",
+	mysql.TypeDuration:          "time",
+	mysql.TypeTimestamp:         "timestamp",
+	mysql.TypeTiny:              "tinyint",
+	mysql.TypeTinyBlob:          "tinytext",
+	mysql.TypeVarchar:           "varchar",
+	mysql.TypeVarString:         "var_string",
+	mysql.TypeTiDBVectorFloat32: "vector",
+	mysql.TypeYear:              "year",
 }
 
 var str2Type = map[string]byte{
@@ -97,6 +103,7 @@ var str2Type = map[string]byte{
 	"tinytext":    mysql.TypeTinyBlob,
 	"varchar":     mysql.TypeVarchar,
 	"var_string":  mysql.TypeVarString,
+	"vector":      mysql.TypeTiDBVectorFloat32,
 	"year":        mysql.TypeYear,
 }
 
@@ -109,6 +116,7 @@ func TypeStr(tp byte) (r string) {
 // It is used for converting Text to Blob,
 // or converting Char to Binary.
 // Args:
+//
 //	tp: type enum
 //	cs: charset
 func TypeToStr(tp byte, cs string) (r string) {
@@ -126,7 +134,8 @@ func TypeToStr(tp byte, cs string) (r string) {
 
 // StrToType convert a string to type enum.
 // Args:
-// 	ts: type string
+//
+//	ts: type string
 func StrToType(ts string) (tp byte) {
 	if strings.Contains(ts, "blob") {
 		ts = strings.Replace(ts, "blob", "text", 1)
@@ -180,6 +180,11 @@ func (ft *FieldType) CompactStr() string {
 		}
 	case mysql.TypeYear:
 		suffix = fmt.Sprintf("(%d)", ft.Flen)
+	case mysql.TypeTiDBVectorFloat32:
+		// The Flen of a vector is its dimension, VECTOR without a dimension accepts vectors of any dimension.
+		if ft.Flen != UnspecifiedLength {
+			suffix = fmt.Sprintf("(%d)", ft.Flen)
+		}
 	}
 	return ts + suffix
 }
@@ -193,6 +193,13 @@ func TestFieldType(t *testing.T) {
 	ft.Decimal = 0
 	require.Equal(t, "char(0)", ft.String())
 	require.True(t, HasCharset(ft))
+
+	ft = NewFieldType(mysql.TypeTiDBVectorFloat32)
+	require.Equal(t, "vector", ft.String())
+	require.False(t, HasCharset(ft))
+	ft.Flen = 3
+	require.Equal(t, "vector(3)", ft.String())
+	require.Equal(t, ETString, ft.EvalType())
 }
 
 func TestHasCharsetFromStmt(t *testing.T) {
@@ -216,6 +216,9 @@ type CachedPrepareStmt struct {
 	PlanDigest          *parser.Digest
 	ForUpdateRead       bool
 	SnapshotTSEvaluator func(sessionctx.Context) (uint64, error)
+	// StmtText and StmtDB are used to re-prepare the statement when the session is migrated.
+	StmtText string
+	StmtDB   string
 }
 
 // GetPreparedStmt extract the prepared statement from the execute statement.
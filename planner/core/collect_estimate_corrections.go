@@ -0,0 +1,157 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"time"
+
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/ranger"
+	"go.uber.org/zap"
+)
+
+// CollectEstimateCorrections compares the estimated row counts of the scans in the executed plan with their actual row
+// counts collected by the runtime stats, and records the corrections of the histogram buckets which are observed to be
+// badly estimated. It should be called after the plan is executed successfully.
+func CollectEstimateCorrections(sctx sessionctx.Context, p Plan) {
+	sessVars := sctx.GetSessionVars()
+	if !variable.EnableHistogramCorrection.Load() || sessVars.InRestrictedSQL || sessVars.StmtCtx.RuntimeStatsColl == nil {
+		return
+	}
+	switch x := p.(type) {
+	case *Explain:
+		if !x.Analyze {
+			return
+		}
+		p = x.TargetPlan
+	case *Insert:
+		p = x.SelectPlan
+	case *Update:
+		p = x.SelectPlan
+	case *Delete:
+		p = x.SelectPlan
+	}
+	physicalPlan, ok := p.(PhysicalPlan)
+	if !ok || physicalPlan == nil {
+		return
+	}
+	scans := make([]PhysicalPlan, 0, 4)
+	if !collectCorrectableScans(physicalPlan, &scans) {
+		return
+	}
+	now := time.Now()
+	for _, scan := range scans {
+		if err := observeScanRowCount(sctx, scan, now); err != nil {
+			logutil.BgLogger().Debug("[stats] observe the row count of scan failed", zap.Error(err))
+		}
+	}
+}
+
+// collectCorrectableScans collects the scans whose ranges are decided in the optimization phase. It returns false if
+// the execution may stop before all the rows are scanned, in which case the actual row counts can't be trusted.
+func collectCorrectableScans(p PhysicalPlan, scans *[]PhysicalPlan) bool {
+	switch x := p.(type) {
+	case *PhysicalLimit:
+		return false
+	case *PhysicalTableReader:
+		return appendCorrectableScan(x.TablePlans, scans)
+	case *PhysicalIndexReader:
+		return appendCorrectableScan(x.IndexPlans, scans)
+	case *PhysicalIndexLookUpReader:
+		return appendCorrectableScan(x.IndexPlans, scans) && !hasLimit(x.TablePlans)
+	case *PhysicalIndexJoin:
+		// The ranges of the inner side are built by the outer rows in the execution phase.
+		return collectCorrectableScans(x.children[1-x.InnerChildIdx], scans)
+	case *PhysicalIndexHashJoin:
+		return collectCorrectableScans(x.children[1-x.InnerChildIdx], scans)
+	case *PhysicalIndexMergeJoin:
+		return collectCorrectableScans(x.children[1-x.InnerChildIdx], scans)
+	case *PhysicalApply:
+		// The inner side may be executed for many times with different correlated values.
+		return collectCorrectableScans(x.children[1-x.InnerChildIdx], scans)
+	}
+	for _, child := range p.Children() {
+		if !collectCorrectableScans(child, scans) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasLimit(copPlans []PhysicalPlan) bool {
+	for _, p := range copPlans {
+		if _, ok := p.(*PhysicalLimit); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func appendCorrectableScan(copPlans []PhysicalPlan, scans *[]PhysicalPlan) bool {
+	if len(copPlans) == 0 || hasLimit(copPlans) {
+		return false
+	}
+	// The scan is always the first plan of the flattened cop plans.
+	switch x := copPlans[0].(type) {
+	case *PhysicalTableScan:
+		if x.StoreType == kv.TiKV && x.SampleInfo == nil {
+			*scans = append(*scans, x)
+		}
+	case *PhysicalIndexScan:
+		*scans = append(*scans, x)
+	}
+	return true
+}
+
+func observeScanRowCount(sctx sessionctx.Context, scan PhysicalPlan, now time.Time) error {
+	copStats := sctx.GetSessionVars().StmtCtx.RuntimeStatsColl.GetCopStats(scan.ID())
+	if copStats == nil {
+		return nil
+	}
+	actual := copStats.GetActRows()
+	switch x := scan.(type) {
+	case *PhysicalTableScan:
+		statsTbl := getStatsTable(sctx, x.Table, x.physicalTableID)
+		if statsTbl.Pseudo {
+			return nil
+		}
+		if x.Table.PKIsHandle {
+			pkCol := x.Table.GetPkColInfo()
+			if pkCol == nil || ranger.HasFullRange(x.Ranges, mysql.HasUnsignedFlag(pkCol.Flag)) {
+				return nil
+			}
+			return statsTbl.ObserveIntColumnRowCount(sctx, pkCol.ID, x.Ranges, actual, now)
+		}
+		if x.Table.IsCommonHandle {
+			pkIdx := tables.FindPrimaryIndex(x.Table)
+			if pkIdx == nil || ranger.HasFullRange(x.Ranges, false) {
+				return nil
+			}
+			return statsTbl.ObserveIndexRowCount(sctx, pkIdx.ID, x.Ranges, actual, now)
+		}
+	case *PhysicalIndexScan:
+		statsTbl := getStatsTable(sctx, x.Table, x.physicalTableID)
+		if statsTbl.Pseudo || ranger.HasFullRange(x.Ranges, false) {
+			return nil
+		}
+		return statsTbl.ObserveIndexRowCount(sctx, x.Index.ID, x.Ranges, actual, now)
+	}
+	return nil
+}
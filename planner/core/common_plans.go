@@ -1037,6 +1037,7 @@ type LoadStats struct {
 	baseSchemaProducer
 
 	Path string
+	JSON string
 }
 
 // PlanReplayer represents a plan replayer plan.
@@ -75,6 +75,7 @@ func (s testErrorSuite) TestError(c *C) {
 		ErrMixOfGroupFuncAndFields,
 		ErrDBaccessDenied,
 		ErrTableaccessDenied,
+		ErrColumnaccessDenied,
 		ErrSpecificAccessDenied,
 		ErrViewNoExplain,
 		ErrWrongValueCountOnRow,
@@ -2316,6 +2316,22 @@ func (p *LogicalWindow) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([
 	return []PhysicalPlan{window}, true, nil
 }
 
+func (p *LogicalExpand) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]PhysicalPlan, bool, error) {
+	p.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced(
+		"MPP mode may be blocked because operator `Expand` is not supported now.")
+	// The Expand outputs the rows of the grouping sets one after another, so it can't keep any order.
+	if prop.IsFlashProp() || !prop.IsEmpty() {
+		return nil, true, nil
+	}
+	childProperty := &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64}
+	expand := PhysicalExpand{
+		LevelExprs: p.LevelExprs,
+		GroupingID: p.GroupingID,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), p.blockOffset, childProperty)
+	expand.SetSchema(p.Schema())
+	return []PhysicalPlan{expand}, true, nil
+}
+
 // exhaustPhysicalPlans is only for implementing interface. DataSource and Dual generate task in `findBestTask` directly.
 func (p *baseLogicalPlan) exhaustPhysicalPlans(_ *property.PhysicalProperty) ([]PhysicalPlan, bool, error) {
 	panic("baseLogicalPlan.exhaustPhysicalPlans() should never be called.")
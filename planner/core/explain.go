@@ -324,6 +324,9 @@ func (p *PhysicalTableScan) isFullScan() bool {
 
 // ExplainInfo implements Plan interface.
 func (p *PhysicalTableReader) ExplainInfo() string {
+	if p.Paging {
+		return "data:" + p.tablePlan.ExplainID().String() + ", paging:true"
+	}
 	return "data:" + p.tablePlan.ExplainID().String()
 }
 
@@ -426,12 +429,15 @@ func (p *PhysicalTableReader) OperatorInfo(normalized bool) string {
 
 // ExplainInfo implements Plan interface.
 func (p *PhysicalIndexReader) ExplainInfo() string {
+	if p.Paging {
+		return "index:" + p.indexPlan.ExplainID().String() + ", paging:true"
+	}
 	return "index:" + p.indexPlan.ExplainID().String()
 }
 
 // ExplainNormalizedInfo implements Plan interface.
 func (p *PhysicalIndexReader) ExplainNormalizedInfo() string {
-	return p.ExplainInfo()
+	return "index:" + p.indexPlan.ExplainID().String()
 }
 
 func (p *PhysicalIndexReader) accessObject(sctx sessionctx.Context) string {
@@ -541,6 +547,34 @@ func (p *PhysicalProjection) ExplainNormalizedInfo() string {
 	return string(expression.SortedExplainNormalizedExpressionList(p.Exprs))
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalExpand) ExplainInfo() string {
+	var buffer strings.Builder
+	buffer.WriteString("level-projections:")
+	for i, exprs := range p.LevelExprs {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString("[")
+		first := true
+		for j, expr := range exprs {
+			// The columns passed through from the child are omitted.
+			if col, ok := expr.(*expression.Column); ok && col.Equal(nil, p.schema.Columns[j]) {
+				continue
+			}
+			if !first {
+				buffer.WriteString(", ")
+			}
+			first = false
+			buffer.WriteString(expr.String())
+			buffer.WriteString("->")
+			buffer.WriteString(p.schema.Columns[j].String())
+		}
+		buffer.WriteString("]")
+	}
+	return buffer.String()
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalTableDual) ExplainInfo() string {
 	var str strings.Builder
@@ -549,6 +583,16 @@ func (p *PhysicalTableDual) ExplainInfo() string {
 	return str.String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalJSONTable) ExplainInfo() string {
+	return fmt.Sprintf("expr:%s, path:%s", p.Expr.ExplainInfo(), p.RowPath.Path.String())
+}
+
+// ExplainNormalizedInfo implements Plan interface.
+func (p *PhysicalJSONTable) ExplainNormalizedInfo() string {
+	return fmt.Sprintf("expr:%s, path:%s", p.Expr.ExplainNormalizedInfo(), p.RowPath.Path.String())
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalSort) ExplainInfo() string {
 	buffer := bytes.NewBufferString("")
@@ -870,6 +914,14 @@ func (p *PhysicalWindow) ExplainInfo() string {
 		p.formatFrameBound(buffer, p.Frame.Start)
 		buffer.WriteString(" and ")
 		p.formatFrameBound(buffer, p.Frame.End)
+		switch p.Frame.Exclusion {
+		case ast.ExcludeCurrentRow:
+			buffer.WriteString(" exclude current row")
+		case ast.ExcludeGroup:
+			buffer.WriteString(" exclude group")
+		case ast.ExcludeTies:
+			buffer.WriteString(" exclude ties")
+		}
 	}
 	buffer.WriteString(")")
 	return buffer.String()
@@ -432,6 +432,10 @@ func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 		er.ctxStackAppend(er.schema.Columns[index], er.names[index])
 		return inNode, true
 	case *ast.FuncCallExpr:
+		if index, ok := er.groupingFieldIndex(v); ok {
+			er.ctxStackAppend(er.schema.Columns[index], er.names[index])
+			return inNode, true
+		}
 		er.asScalar = true
 		if _, ok := expression.DisableFoldFunctions[v.FnName.L]; ok {
 			er.disableFoldCounter++
@@ -1082,6 +1086,10 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 	case *ast.VariableExpr:
 		er.rewriteVariable(v)
 	case *ast.FuncCallExpr:
+		if _, ok := er.groupingFieldIndex(v); ok {
+			// It has been replaced by the auxiliary select field in Enter.
+			break
+		}
 		if _, ok := expression.TryFoldFunctions[v.FnName.L]; ok {
 			er.tryFoldCounter--
 		}
@@ -1123,6 +1131,17 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 			return retNode, false
 		}
 
+		if v.Array {
+			var castFunction expression.Expression
+			castFunction, er.err = expression.BuildCastAsArrayFunction(er.sctx, arg, v.Tp)
+			if er.err != nil {
+				return retNode, false
+			}
+			er.ctxStack[len(er.ctxStack)-1] = castFunction
+			er.ctxNameStk[len(er.ctxNameStk)-1] = types.EmptyName
+			break
+		}
+
 		castFunction := expression.BuildCastFunction(er.sctx, arg, v.Tp)
 		if v.Tp.EvalType() == types.ETString {
 			castFunction.SetCoercibility(expression.CoercibilityImplicit)
@@ -1838,11 +1857,55 @@ func (er *expressionRewriter) rewriteFuncCall(v *ast.FuncCallExpr) bool {
 		er.ctxStackPop(len(v.Args))
 		er.ctxStackAppend(funcIf, types.EmptyName)
 		return true
+	case ast.Grouping:
+		er.rewriteGrouping(v)
+		return true
 	default:
 		return false
 	}
 }
 
+// groupingFieldIndex returns the index of the auxiliary select field of the GROUPING function in HAVING and ORDER BY.
+func (er *expressionRewriter) groupingFieldIndex(v *ast.FuncCallExpr) (int, bool) {
+	if v.FnName.L != ast.Grouping || er.b.rollup == nil || (er.b.curClause != havingClause && er.b.curClause != orderByClause) {
+		return -1, false
+	}
+	index, ok := er.b.rollup.groupingMapper[v]
+	return index, ok
+}
+
+// rewriteGrouping rewrites GROUPING(expr, ...) as grouping(grouping_id, offset, ...), the offsets are the ones of
+// the arguments in the GROUP BY items.
+func (er *expressionRewriter) rewriteGrouping(v *ast.FuncCallExpr) {
+	rollup := er.b.rollup
+	if rollup == nil || rollup.groupingID == nil {
+		er.err = ErrInvalidGroupFuncUse
+		return
+	}
+	if len(v.Args) == 0 {
+		er.err = expression.ErrIncorrectParameterCount.GenWithStackByArgs(v.FnName.O)
+		return
+	}
+	stackLen := len(er.ctxStack)
+	args := make([]expression.Expression, 0, len(v.Args)+1)
+	args = append(args, rollup.groupingID)
+	for i, arg := range er.ctxStack[stackLen-len(v.Args):] {
+		offset := rollup.gbyItemOffset(er.sctx, arg)
+		if offset < 0 {
+			er.err = ErrFieldInGroupingNotGroupBy.GenWithStackByArgs(i + 1)
+			return
+		}
+		args = append(args, &expression.Constant{Value: types.NewIntDatum(int64(offset)), RetType: types.NewFieldType(mysql.TypeLonglong)})
+	}
+	f, err := er.newFunction(ast.Grouping, &v.Type, args...)
+	if err != nil {
+		er.err = err
+		return
+	}
+	er.ctxStackPop(len(v.Args))
+	er.ctxStackAppend(f, types.EmptyName)
+}
+
 func (er *expressionRewriter) funcCallToExpression(v *ast.FuncCallExpr) {
 	stackLen := len(er.ctxStack)
 	args := er.ctxStack[stackLen-len(v.Args):]
@@ -1900,6 +1963,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 			er.err = ErrUnknownColumn.GenWithStackByArgs(v.Name, clauseMsg[er.b.curClause])
 			return
 		}
+		er.b.appendColumnVisitInfo(column)
 		er.ctxStackAppend(column, er.names[idx])
 		return
 	}
@@ -1908,6 +1972,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		idx, err = expression.FindFieldName(outerName, v)
 		if idx >= 0 {
 			column := outerSchema.Columns[idx]
+			er.b.appendColumnVisitInfo(column)
 			er.ctxStackAppend(&expression.CorrelatedColumn{Column: *column, Data: new(types.Datum)}, outerName[idx])
 			return
 		}
@@ -1925,6 +1990,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		er.err = err
 		return
 	} else if col != nil {
+		er.b.appendColumnVisitInfo(col)
 		er.ctxStackAppend(col, name)
 		return
 	}
@@ -37,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/format"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -52,6 +53,7 @@ import (
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/table/temptable"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	util2 "github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
@@ -310,6 +312,146 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 	return plan4Agg, aggIndexMap, nil
 }
 
+// rollupInfo records the GROUP BY ... WITH ROLLUP of a SELECT statement.
+type rollupInfo struct {
+	// gbyItems are the GROUP BY items on the child of the Expand.
+	gbyItems []expression.Expression
+	// gbyCols are the GROUP BY items outputted by the Expand, they're NULL in the super-aggregate rows.
+	gbyCols []*expression.Column
+	// groupingID is the grouping id outputted by the Expand. It's set after the aggregation is built, because
+	// the GROUPING function can't be used before that.
+	groupingID *expression.Column
+	// groupingMapper maps the GROUPING functions in HAVING and ORDER BY to the auxiliary select fields.
+	groupingMapper map[*ast.FuncCallExpr]int
+}
+
+// maxRollupItems is the max number of the GROUP BY items with ROLLUP, every item takes a bit of the grouping id.
+const maxRollupItems = 64
+
+// buildRollupExpand builds the Expand under the aggregation of GROUP BY ... WITH ROLLUP. For n GROUP BY items,
+// every input row is expanded to n+1 rows, the last i GROUP BY items are NULL in the i-th of them. The Expand
+// outputs the columns of its child, which are used by the aggregate functions, then the GROUP BY items and the
+// grouping id, which are used as the GROUP BY items of the aggregation.
+func (b *PlanBuilder) buildRollupExpand(p LogicalPlan, gbyItems []expression.Expression) (*LogicalExpand, error) {
+	if len(gbyItems) > maxRollupItems {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("WITH ROLLUP with more than %d GROUP BY items", maxRollupItems))
+	}
+	expand := LogicalExpand{}.Init(b.ctx, b.getSelectOffset())
+	schema := p.Schema().Clone()
+	names := make(types.NameSlice, 0, schema.Len()+len(gbyItems)+1)
+	names = append(names, p.OutputNames()...)
+	gbyCols := make([]*expression.Column, 0, len(gbyItems))
+	for _, item := range gbyItems {
+		tp := item.GetType().Clone()
+		tp.Flag &= ^mysql.NotNullFlag
+		col := &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  tp,
+		}
+		schema.Append(col)
+		names = append(names, types.EmptyName)
+		gbyCols = append(gbyCols, col)
+	}
+	gidTp := types.NewFieldType(mysql.TypeLonglong)
+	gidTp.Flag |= mysql.NotNullFlag
+	expand.GroupingID = &expression.Column{
+		UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  gidTp,
+	}
+	schema.Append(expand.GroupingID)
+	names = append(names, types.EmptyName)
+
+	n := len(gbyItems)
+	expand.LevelExprs = make([][]expression.Expression, 0, n+1)
+	for level := 0; level <= n; level++ {
+		exprs := make([]expression.Expression, 0, schema.Len())
+		for _, col := range p.Schema().Columns {
+			exprs = append(exprs, col)
+		}
+		var gid uint64
+		for i, item := range gbyItems {
+			if i < n-level {
+				exprs = append(exprs, item.Clone())
+				continue
+			}
+			gid |= 1 << uint(i)
+			exprs = append(exprs, &expression.Constant{Value: types.NewDatum(nil), RetType: gbyCols[i].RetType.Clone()})
+		}
+		exprs = append(exprs, &expression.Constant{Value: types.NewIntDatum(int64(gid)), RetType: gidTp.Clone()})
+		expand.LevelExprs = append(expand.LevelExprs, exprs)
+	}
+	expand.SetSchema(schema)
+	expand.names = names
+	expand.SetChildren(p)
+	b.rollup.gbyItems = gbyItems
+	b.rollup.gbyCols = gbyCols
+	return expand, nil
+}
+
+// replaceRollupFirstRows makes the GROUP BY columns in the output of the aggregation come from the Expand, so
+// that they're NULL in the super-aggregate rows. The aggregate functions still use the columns of the child.
+func (b *PlanBuilder) replaceRollupFirstRows(agg *LogicalAggregation) error {
+	for i, aggFunc := range agg.AggFuncs {
+		if aggFunc.Name != ast.AggFuncFirstRow {
+			continue
+		}
+		col, ok := aggFunc.Args[0].(*expression.Column)
+		if !ok {
+			continue
+		}
+		for j, item := range b.rollup.gbyItems {
+			if !col.Equal(nil, item) {
+				continue
+			}
+			newFunc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{b.rollup.gbyCols[j]}, false)
+			if err != nil {
+				return err
+			}
+			agg.AggFuncs[i] = newFunc
+			agg.schema.Columns[i].RetType = newFunc.RetTp
+			break
+		}
+	}
+	return nil
+}
+
+// substituteGbyExprs replaces the GROUP BY expressions in the select fields with the ones outputted by the
+// Expand, e.g. `a+1` in `SELECT a+1 FROM t GROUP BY a+1 WITH ROLLUP`. The GROUP BY columns have been replaced
+// in replaceRollupFirstRows.
+func (r *rollupInfo) substituteGbyExprs(ctx sessionctx.Context, expr expression.Expression) expression.Expression {
+	sf, ok := expr.(*expression.ScalarFunction)
+	if !ok {
+		return expr
+	}
+	for i, item := range r.gbyItems {
+		if _, isCol := item.(*expression.Column); !isCol && item.Equal(ctx, sf) {
+			return r.gbyCols[i]
+		}
+	}
+	args := sf.GetArgs()
+	newArgs := make([]expression.Expression, 0, len(args))
+	changed := false
+	for _, arg := range args {
+		newArg := r.substituteGbyExprs(ctx, arg)
+		changed = changed || newArg != arg
+		newArgs = append(newArgs, newArg)
+	}
+	if !changed {
+		return expr
+	}
+	return expression.NewFunctionInternal(ctx, sf.FuncName.L, sf.RetType, newArgs...)
+}
+
+// gbyItemOffset returns the offset of the expression in the GROUP BY items, or -1 if it's not a GROUP BY item.
+func (r *rollupInfo) gbyItemOffset(ctx sessionctx.Context, expr expression.Expression) int {
+	for i, item := range r.gbyItems {
+		if item.Equal(ctx, expr) {
+			return i
+		}
+	}
+	return -1
+}
+
 func (b *PlanBuilder) buildTableRefs(ctx context.Context, from *ast.TableRefsClause) (p LogicalPlan, err error) {
 	if from == nil {
 		p = b.buildTableDual()
@@ -342,6 +484,10 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		case *ast.TableName:
 			p, err = b.buildDataSource(ctx, v, &x.AsName)
 			isTableName = true
+		case *ast.JSONTable:
+			p, err = b.buildJSONTable(ctx, v, x.AsName)
+			// Like `TableName`, `JSON_TABLE` is not a select block.
+			isTableName = true
 		default:
 			err = ErrUnsupportedType.GenWithStackByArgs(v)
 		}
@@ -686,7 +832,18 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		return nil, err
 	}
 
+	// JSON_TABLE and LATERAL derived tables can reference the columns of the tables on their left side unless they're
+	// the outer side of the join.
+	isLateral := isLateralSource(joinNode.Right) && joinNode.Tp != ast.RightJoin
+	if isLateral {
+		b.outerSchemas = append(b.outerSchemas, leftPlan.Schema().Clone())
+		b.outerNames = append(b.outerNames, leftPlan.OutputNames())
+	}
 	rightPlan, err := b.buildResultSetNode(ctx, joinNode.Right)
+	if isLateral {
+		b.outerSchemas = b.outerSchemas[0 : len(b.outerSchemas)-1]
+		b.outerNames = b.outerNames[0 : len(b.outerNames)-1]
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -700,7 +857,17 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 	handleMap2 := b.handleHelper.popMap()
 	b.handleHelper.mergeAndPush(handleMap1, handleMap2)
 
-	joinPlan := LogicalJoin{StraightJoin: joinNode.StraightJoin || b.inStraightJoin}.Init(b.ctx, b.getSelectOffset())
+	// The JSON_TABLE or LATERAL derived table referencing the left side is evaluated for every row of the left side
+	// by an Apply, which may be decorrelated later.
+	var joinPlan *LogicalJoin
+	var ap *LogicalApply
+	if isLateral && len(extractCorColumnsBySchema4LogicalPlan(rightPlan, leftPlan.Schema())) > 0 {
+		b.optFlag = b.optFlag | flagBuildKeyInfo | flagDecorrelate
+		ap = LogicalApply{}.Init(b.ctx, b.getSelectOffset())
+		joinPlan = &ap.LogicalJoin
+	} else {
+		joinPlan = LogicalJoin{StraightJoin: joinNode.StraightJoin || b.inStraightJoin}.Init(b.ctx, b.getSelectOffset())
+	}
 	joinPlan.SetChildren(leftPlan, rightPlan)
 	joinPlan.SetSchema(expression.MergeSchema(leftPlan.Schema(), rightPlan.Schema()))
 	joinPlan.names = make([]*types.FieldName, leftPlan.Schema().Len()+rightPlan.Schema().Len())
@@ -802,7 +969,11 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		// possible decorrelate optimizations. The ON clause is actually treated as a WHERE clause now.
 		if joinPlan.JoinType == InnerJoin {
 			sel := LogicalSelection{Conditions: onCondition}.Init(b.ctx, b.getSelectOffset())
-			sel.SetChildren(joinPlan)
+			if ap != nil {
+				sel.SetChildren(ap)
+			} else {
+				sel.SetChildren(joinPlan)
+			}
 			return sel, nil
 		}
 		joinPlan.AttachOnConds(onCondition)
@@ -812,9 +983,26 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		joinPlan.cartesianJoin = true
 	}
 
+	if ap != nil {
+		return ap, nil
+	}
 	return joinPlan, nil
 }
 
+// isLateralSource checks whether the table source can reference the columns of the preceding tables, i.e. it's a
+// JSON_TABLE or a LATERAL derived table.
+func isLateralSource(node ast.ResultSetNode) bool {
+	ts, ok := node.(*ast.TableSource)
+	if !ok {
+		return false
+	}
+	if ts.Lateral {
+		return true
+	}
+	_, ok = ts.Source.(*ast.JSONTable)
+	return ok
+}
+
 // buildUsingClause eliminate the redundant columns and ordering columns based
 // on the "USING" clause.
 //
@@ -964,6 +1152,8 @@ func (b *PlanBuilder) coalesceCommonColumns(p *LogicalJoin, leftPlan, rightPlan
 		if err != nil {
 			return err
 		}
+		b.appendColumnVisitInfo(lc)
+		b.appendColumnVisitInfo(rc)
 		conds = append(conds, cond)
 	}
 
@@ -1275,6 +1465,9 @@ func (b *PlanBuilder) buildProjection(ctx context.Context, p LogicalPlan, fields
 		if err != nil {
 			return nil, nil, 0, err
 		}
+		if !considerWindow && b.rollup != nil && b.rollup.groupingID != nil {
+			newExpr = b.rollup.substituteGbyExprs(b.ctx, newExpr)
+		}
 
 		// For window functions in the order by clause, we will append an field for it.
 		// We need rewrite the window mapper here so order by clause could find the added field.
@@ -1516,11 +1709,24 @@ func (b *PlanBuilder) buildSetOpr(ctx context.Context, setOpr *ast.SetOprStmt) (
 	return setOprPlan, nil
 }
 
+// buildSemiJoinForSetOperator builds the (anti) semi join for 'intersect' and 'except'. For the distinct variants, the
+// left side is deduplicated. For the 'all' variants, the duplicated rows of both sides are numbered by
+// buildRowNumberForSetOperator and matched one by one, so the n-th copy of a row is kept only if the right side has
+// (for 'intersect all') or hasn't (for 'except all') its n-th copy.
 func (b *PlanBuilder) buildSemiJoinForSetOperator(
 	leftOriginPlan LogicalPlan,
 	rightPlan LogicalPlan,
-	joinType JoinType) (leftPlan LogicalPlan, err error) {
-	leftPlan, err = b.buildDistinct(leftOriginPlan, leftOriginPlan.Schema().Len())
+	joinType JoinType,
+	all bool) (leftPlan LogicalPlan, err error) {
+	if all {
+		leftPlan, err = b.buildRowNumberForSetOperator(leftOriginPlan)
+		if err != nil {
+			return nil, err
+		}
+		rightPlan, err = b.buildRowNumberForSetOperator(rightPlan)
+	} else {
+		leftPlan, err = b.buildDistinct(leftOriginPlan, leftOriginPlan.Schema().Len())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1541,7 +1747,48 @@ func (b *PlanBuilder) buildSemiJoinForSetOperator(
 			joinPlan.EqualConditions = append(joinPlan.EqualConditions, eqCond.(*expression.ScalarFunction))
 		}
 	}
-	return joinPlan, nil
+	if !all {
+		return joinPlan, nil
+	}
+	// Remove the row number column.
+	outputLen := leftOriginPlan.Schema().Len()
+	proj := LogicalProjection{Exprs: expression.Column2Exprs(joinPlan.Schema().Columns[:outputLen])}.Init(b.ctx, b.getSelectOffset())
+	proj.SetChildren(joinPlan)
+	schema := expression.NewSchema(joinPlan.Schema().Clone().Columns[:outputLen]...)
+	for _, col := range schema.Columns {
+		col.UniqueID = b.ctx.GetSessionVars().AllocPlanColumnID()
+	}
+	proj.names = joinPlan.OutputNames()[:outputLen]
+	proj.SetSchema(schema)
+	return proj, nil
+}
+
+// buildRowNumberForSetOperator appends the column `row_number() over (partition by <all the columns>)` to the plan,
+// which numbers the duplicated rows for 'intersect all' and 'except all'.
+func (b *PlanBuilder) buildRowNumberForSetOperator(p LogicalPlan) (LogicalPlan, error) {
+	desc, err := aggregation.NewWindowFuncDesc(b.ctx, ast.WindowFuncRowNumber, nil)
+	if err != nil {
+		return nil, err
+	}
+	partitionBy := make([]property.SortItem, 0, p.Schema().Len())
+	for _, col := range p.Schema().Columns {
+		partitionBy = append(partitionBy, property.SortItem{Col: col})
+	}
+	window := LogicalWindow{
+		WindowFuncDescs: []*aggregation.WindowFuncDesc{desc},
+		PartitionBy:     partitionBy,
+	}.Init(b.ctx, b.getSelectOffset())
+	window.names = make([]*types.FieldName, p.Schema().Len(), p.Schema().Len()+1)
+	copy(window.names, p.OutputNames())
+	window.names = append(window.names, types.EmptyName)
+	schema := p.Schema().Clone()
+	schema.Append(&expression.Column{
+		UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  desc.RetTp,
+	})
+	window.SetChildren(p)
+	window.SetSchema(schema)
+	return window, nil
 }
 
 // buildIntersect build the set operator for 'intersect'. It is called before buildExcept and buildUnion because of its
@@ -1568,18 +1815,13 @@ func (b *PlanBuilder) buildIntersect(ctx context.Context, selects []ast.Node) (L
 	columnNums := leftPlan.Schema().Len()
 	for i := 1; i < len(selects); i++ {
 		var rightPlan LogicalPlan
+		var all bool
 		switch x := selects[i].(type) {
 		case *ast.SelectStmt:
-			if *x.AfterSetOperator == ast.IntersectAll {
-				// TODO: support intersect all
-				return nil, nil, errors.Errorf("TiDB do not support intersect all")
-			}
+			all = *x.AfterSetOperator == ast.IntersectAll
 			rightPlan, err = b.buildSelect(ctx, x)
 		case *ast.SetOprSelectList:
-			if *x.AfterSetOperator == ast.IntersectAll {
-				// TODO: support intersect all
-				return nil, nil, errors.Errorf("TiDB do not support intersect all")
-			}
+			all = *x.AfterSetOperator == ast.IntersectAll
 			rightPlan, err = b.buildSetOpr(ctx, &ast.SetOprStmt{SelectList: x})
 		}
 		if err != nil {
@@ -1588,7 +1830,7 @@ func (b *PlanBuilder) buildIntersect(ctx context.Context, selects []ast.Node) (L
 		if rightPlan.Schema().Len() != columnNums {
 			return nil, nil, ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
 		}
-		leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, SemiJoin)
+		leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, SemiJoin, all)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -1607,20 +1849,17 @@ func (b *PlanBuilder) buildExcept(ctx context.Context, selects []LogicalPlan, af
 		if rightPlan.Schema().Len() != columnNums {
 			return nil, ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
 		}
-		if *afterSetOpts[i] == ast.Except {
+		if *afterSetOpts[i] == ast.Except || *afterSetOpts[i] == ast.ExceptAll {
 			leftPlan, err := b.buildUnion(ctx, unionPlans, tmpAfterSetOpts)
 			if err != nil {
 				return nil, err
 			}
-			leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, AntiSemiJoin)
+			leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, AntiSemiJoin, *afterSetOpts[i] == ast.ExceptAll)
 			if err != nil {
 				return nil, err
 			}
 			unionPlans = []LogicalPlan{leftPlan}
 			tmpAfterSetOpts = []*ast.SetOprType{nil}
-		} else if *afterSetOpts[i] == ast.ExceptAll {
-			// TODO: support except all.
-			return nil, errors.Errorf("TiDB do not support except all")
 		} else {
 			unionPlans = append(unionPlans, rightPlan)
 			tmpAfterSetOpts = append(tmpAfterSetOpts, afterSetOpts[i])
@@ -1958,6 +2197,10 @@ type havingWindowAndOrderbyExprResolver struct {
 	outerNames   [][]*types.FieldName
 	curClause    clauseCode
 	prevClause   []clauseCode
+	// groupingMapper is not nil when the GROUP BY has ROLLUP, the GROUPING functions are extracted to the
+	// select fields like the aggregate functions.
+	groupingMapper map[*ast.FuncCallExpr]int
+	curGrouping    *ast.FuncCallExpr
 }
 
 func (a *havingWindowAndOrderbyExprResolver) pushCurClause(newClause clauseCode) {
@@ -1972,9 +2215,16 @@ func (a *havingWindowAndOrderbyExprResolver) popCurClause() {
 
 // Enter implements Visitor interface.
 func (a *havingWindowAndOrderbyExprResolver) Enter(n ast.Node) (node ast.Node, skipChildren bool) {
-	switch n.(type) {
+	switch v := n.(type) {
 	case *ast.AggregateFuncExpr:
 		a.inAggFunc = true
+	case *ast.FuncCallExpr:
+		a.inExpr = true
+		if a.groupingMapper != nil && v.FnName.L == ast.Grouping && !a.inAggFunc {
+			// The arguments of GROUPING are resolved like the ones of the aggregate functions.
+			a.inAggFunc = true
+			a.curGrouping = v
+		}
 	case *ast.WindowFuncExpr:
 		a.inWindowFunc = true
 	case *ast.WindowSpec:
@@ -2055,6 +2305,17 @@ func (a *havingWindowAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, o
 			Expr:      v,
 			AsName:    model.NewCIStr(fmt.Sprintf("sel_agg_%d", len(a.selectFields))),
 		})
+	case *ast.FuncCallExpr:
+		if v == a.curGrouping {
+			a.inAggFunc = false
+			a.curGrouping = nil
+			a.groupingMapper[v] = len(a.selectFields)
+			a.selectFields = append(a.selectFields, &ast.SelectField{
+				Auxiliary: true,
+				Expr:      v,
+				AsName:    model.NewCIStr(fmt.Sprintf("sel_grouping_%d", len(a.selectFields))),
+			})
+		}
 	case *ast.WindowFuncExpr:
 		a.inWindowFunc = false
 		if a.curClause == havingClause {
@@ -2179,6 +2440,9 @@ func (b *PlanBuilder) resolveHavingAndOrderBy(sel *ast.SelectStmt, p LogicalPlan
 	if sel.GroupBy != nil {
 		extractor.gbyItems = sel.GroupBy.Items
 	}
+	if b.rollup != nil {
+		extractor.groupingMapper = b.rollup.groupingMapper
+	}
 	// Extract agg funcs from having clause.
 	if sel.Having != nil {
 		extractor.curClause = havingClause
@@ -3090,9 +3354,14 @@ type colNameResolver struct {
 }
 
 func (c *colNameResolver) Enter(inNode ast.Node) (ast.Node, bool) {
-	switch inNode.(type) {
+	switch v := inNode.(type) {
 	case *ast.ColumnNameExpr, *ast.SubqueryExpr, *ast.AggregateFuncExpr:
 		return inNode, true
+	case *ast.FuncCallExpr:
+		// The arguments of GROUPING are checked to be the GROUP BY items when it's rewritten.
+		if v.FnName.L == ast.Grouping {
+			return inNode, true
+		}
 	}
 	return inNode, false
 }
@@ -3524,6 +3793,12 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		b.inStraightJoin = sel.SelectStmtOpts.StraightJoin
 		defer func() { b.inStraightJoin = origin }()
 	}
+	originRollup := b.rollup
+	b.rollup = nil
+	if sel.GroupBy != nil && sel.GroupBy.Rollup {
+		b.rollup = &rollupInfo{groupingMapper: make(map[*ast.FuncCallExpr]int)}
+	}
+	defer func() { b.rollup = originRollup }()
 
 	var (
 		aggFuncs                      []*ast.AggregateFuncExpr
@@ -3669,11 +3944,32 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		}
 	}
 	if needBuildAgg {
-		var aggIndexMap map[int]int
+		var (
+			aggIndexMap map[int]int
+			expand      *LogicalExpand
+		)
+		if b.rollup != nil {
+			expand, err = b.buildRollupExpand(p, gbyCols)
+			if err != nil {
+				return nil, err
+			}
+			p = expand
+			gbyCols = make([]expression.Expression, 0, len(b.rollup.gbyCols)+1)
+			for _, col := range b.rollup.gbyCols {
+				gbyCols = append(gbyCols, col)
+			}
+			gbyCols = append(gbyCols, expand.GroupingID)
+		}
 		p, aggIndexMap, err = b.buildAggregation(ctx, p, aggFuncs, gbyCols, correlatedAggMap)
 		if err != nil {
 			return nil, err
 		}
+		if b.rollup != nil {
+			if err = b.replaceRollupFirstRows(p.(*LogicalAggregation)); err != nil {
+				return nil, err
+			}
+			b.rollup.groupingID = expand.GroupingID
+		}
 		for agg, idx := range totalMap {
 			totalMap[agg] = aggIndexMap[idx]
 		}
@@ -3773,6 +4069,111 @@ func (b *PlanBuilder) buildTableDual() *LogicalTableDual {
 	return LogicalTableDual{RowCount: 1}.Init(b.ctx, b.getSelectOffset())
 }
 
+// buildJSONTable builds the JSON_TABLE table function. The JSON document can reference the columns of the
+// tables on the left side of the join, they are resolved from b.outerSchemas as correlated columns.
+func (b *PlanBuilder) buildJSONTable(ctx context.Context, jt *ast.JSONTable, asName model.CIStr) (LogicalPlan, error) {
+	dual := LogicalTableDual{RowCount: 1}.Init(b.ctx, b.getSelectOffset())
+	dual.SetSchema(expression.NewSchema())
+	expr, np, err := b.rewrite(ctx, jt.Expr, dual, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if np != dual {
+		return nil, errors.New("JSON_TABLE doesn't support subqueries yet")
+	}
+	p := LogicalJSONTable{Expr: expression.WrapWithCastAsJSON(b.ctx, expr)}.Init(b.ctx, b.getSelectOffset())
+	schema := expression.NewSchema()
+	names := make(types.NameSlice, 0, len(jt.Columns))
+	p.RowPath, err = b.buildJSONTablePath(jt.Path, jt.Columns, asName, schema, &names)
+	if err != nil {
+		return nil, err
+	}
+	p.SetSchema(schema)
+	p.SetOutputNames(names)
+	b.handleHelper.pushMap(nil)
+	return p, nil
+}
+
+// buildJSONTablePath builds the row path or a NESTED PATH of JSON_TABLE. The columns are appended to
+// the schema in the order they are defined, including the columns of the NESTED PATH.
+func (b *PlanBuilder) buildJSONTablePath(path string, cols []*ast.JSONTableColumn, asName model.CIStr, schema *expression.Schema, names *types.NameSlice) (*JSONTablePath, error) {
+	pathExpr, err := json.ParseJSONPathExpr(path)
+	if err != nil {
+		return nil, err
+	}
+	jp := &JSONTablePath{Path: pathExpr}
+	for _, col := range cols {
+		if col.Tp == ast.JSONTableColumnNested {
+			nested, err := b.buildJSONTablePath(col.Path, col.NestedColumns, asName, schema, names)
+			if err != nil {
+				return nil, err
+			}
+			jp.Nested = append(jp.Nested, nested)
+			continue
+		}
+		c := &JSONTableColumn{Tp: col.Tp, Name: col.Name.O, Offset: schema.Len()}
+		if col.Tp == ast.JSONTableColumnOrdinality {
+			c.RetType = types.NewFieldType(mysql.TypeLonglong)
+			c.RetType.Flag |= mysql.UnsignedFlag
+		} else {
+			c.RetType = col.Type.Clone()
+			if c.Path, err = json.ParseJSONPathExpr(col.Path); err != nil {
+				return nil, err
+			}
+			if c.OnEmpty, err = buildJSONTableOnResponse(col.OnEmpty); err != nil {
+				return nil, err
+			}
+			if c.OnError, err = buildJSONTableOnResponse(col.OnError); err != nil {
+				return nil, err
+			}
+		}
+		setJSONTableColumnType(c.RetType)
+		schema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  c.RetType,
+		})
+		*names = append(*names, &types.FieldName{
+			TblName:     asName,
+			OrigTblName: asName,
+			ColName:     col.Name,
+			OrigColName: col.Name,
+		})
+		jp.Columns = append(jp.Columns, c)
+	}
+	return jp, nil
+}
+
+func buildJSONTableOnResponse(response *ast.JSONTableOnResponse) (*JSONTableOnResponse, error) {
+	if response == nil {
+		return nil, nil
+	}
+	res := &JSONTableOnResponse{Tp: response.Tp}
+	if response.Tp == ast.JSONTableOnResponseDefault {
+		var err error
+		if res.Default, err = json.ParseBinaryFromString(response.Default); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// setJSONTableColumnType fills the charset, collation, flen and decimal of a JSON_TABLE column in the same
+// way as they are filled for a column of a table.
+func setJSONTableColumnType(tp *types.FieldType) {
+	if tp.EvalType() == types.ETString && tp.Charset == "" {
+		tp.Charset, tp.Collate = charset.GetDefaultCharsetAndCollate()
+	} else if tp.EvalType() != types.ETString {
+		tp.Charset, tp.Collate = charset.CharsetBin, charset.CollationBin
+	}
+	defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(tp.Tp)
+	if tp.Decimal == types.UnspecifiedLength {
+		tp.Decimal = defaultDecimal
+	}
+	if tp.Flen == types.UnspecifiedLength {
+		tp.Flen = defaultFlen
+	}
+}
+
 func (ds *DataSource) newExtraHandleSchemaCol() *expression.Column {
 	tp := types.NewFieldType(mysql.TypeLonglong)
 	tp.Flag = mysql.NotNullFlag | mysql.PriKeyFlag
@@ -3961,7 +4362,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	if sessionVars.User != nil {
 		authErr = ErrTableaccessDenied.FastGenByArgs("SELECT", sessionVars.User.AuthUsername, sessionVars.User.AuthHostname, tableInfo.Name.L)
 	}
-	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName.L, tableInfo.Name.L, "", authErr)
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName.L, tableInfo.Name.L, privilege.AnyColumn, authErr)
 
 	if tbl.Type().IsVirtualTable() {
 		if tn.TableSample != nil {
@@ -3970,6 +4371,13 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		return b.buildMemTable(ctx, dbName, tableInfo)
 	}
 
+	if tableInfo.ExternalTable != nil {
+		if tn.TableSample != nil {
+			return nil, expression.ErrInvalidTableSample.GenWithStackByArgs("Unsupported TABLESAMPLE in external tables")
+		}
+		return b.buildMemTable(ctx, dbName, tableInfo)
+	}
+
 	if tableInfo.IsView() {
 		if tn.TableSample != nil {
 			return nil, expression.ErrInvalidTableSample.GenWithStackByArgs("Unsupported TABLESAMPLE in views")
@@ -4240,6 +4648,9 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 			}
 		}
 	}
+	// The columns are registered after the generation expressions are built, reading a generated column
+	// doesn't require the privilege on the columns it depends on.
+	b.registerColumnVisitInfo(dbName, tableInfo.Name, ds.Schema().Columns, ds.names)
 
 	return result, nil
 }
@@ -4299,6 +4710,10 @@ func (b *PlanBuilder) buildMemTable(_ context.Context, dbName model.CIStr, table
 			ID:       col.ID,
 			RetType:  &col.FieldType,
 		}
+		if tableInfo.ExternalTable != nil {
+			// The conditions evaluated by the reader of an external table are shown in EXPLAIN.
+			newCol.OrigName = names[len(names)-1].String()
+		}
 		if tableInfo.PKIsHandle && mysql.HasPriKeyFlag(col.Flag) {
 			handleCols = &IntHandleCols{col: newCol}
 		}
@@ -4326,18 +4741,20 @@ func (b *PlanBuilder) buildMemTable(_ context.Context, dbName model.CIStr, table
 	}
 
 	// Some memory tables can receive some predicates
-	switch dbName.L {
-	case util2.MetricSchemaName.L:
+	switch {
+	case tableInfo.ExternalTable != nil:
+		p.Extractor = &ExternalTableExtractor{}
+	case dbName.L == util2.MetricSchemaName.L:
 		p.Extractor = newMetricTableExtractor()
-	case util2.InformationSchemaName.L:
+	case dbName.L == util2.InformationSchemaName.L:
 		switch strings.ToUpper(tableInfo.Name.O) {
-		case infoschema.TableClusterConfig, infoschema.TableClusterLoad, infoschema.TableClusterHardware, infoschema.TableClusterSystemInfo:
+		case infoschema.TableClusterConfig, infoschema.TableClusterConfigDiff, infoschema.TableClusterLoad, infoschema.TableClusterHardware, infoschema.TableClusterSystemInfo:
 			p.Extractor = &ClusterTableExtractor{}
 		case infoschema.TableClusterLog:
 			p.Extractor = &ClusterLogTableExtractor{}
 		case infoschema.TableTiDBHotRegionsHistory:
 			p.Extractor = &HotRegionsHistoryTableExtractor{}
-		case infoschema.TableInspectionResult:
+		case infoschema.TableInspectionResult, infoschema.TableInspectionReport:
 			p.Extractor = &InspectionResultTableExtractor{}
 			p.QueryTimeRange = b.timeRangeForSummaryTable()
 		case infoschema.TableInspectionSummary:
@@ -4352,6 +4769,8 @@ func (b *PlanBuilder) buildMemTable(_ context.Context, dbName model.CIStr, table
 			p.Extractor = &SlowQueryExtractor{}
 		case infoschema.TableStorageStats:
 			p.Extractor = &TableStorageStatsExtractor{}
+		case infoschema.TableTables, infoschema.TableColumns, infoschema.TableStatistics:
+			p.Extractor = &InfoSchemaTablesExtractor{}
 		case infoschema.TableTiFlashTables, infoschema.TableTiFlashSegments:
 			p.Extractor = &TiFlashSystemTableExtractor{}
 		case infoschema.TableStatementsSummary, infoschema.TableStatementsSummaryHistory:
@@ -4478,6 +4897,7 @@ func (b *PlanBuilder) buildProjUponView(ctx context.Context, dbName model.CIStr,
 	projUponView.names = projNames
 	projUponView.SetChildren(selectLogicalPlan.(LogicalPlan))
 	projUponView.SetSchema(projSchema)
+	b.registerColumnVisitInfo(dbName, tableInfo.Name, projSchema.Columns, projNames)
 	return projUponView, nil
 }
 
@@ -4693,7 +5113,7 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 		if dbName == "" {
 			dbName = b.ctx.GetSessionVars().CurrentDB
 		}
-		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName, t.Name.L, "", nil)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName, t.Name.L, privilege.AnyColumn, nil)
 	}
 
 	oldSchemaLen := p.Schema().Len()
@@ -4850,7 +5270,7 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 		foundListItem := false
 		for _, tl := range tableList {
 			if (tl.Schema.L == "" || tl.Schema.L == name.DBName.L) && (tl.Name.L == name.TblName.L) {
-				if isCTE(tl) || tl.TableInfo.IsView() || tl.TableInfo.IsSequence() {
+				if isCTE(tl) || tl.TableInfo.IsView() || tl.TableInfo.IsSequence() || tl.TableInfo.ExternalTable != nil {
 					return nil, nil, false, ErrNonUpdatableTable.GenWithStackByArgs(name.TblName.O, "UPDATE")
 				}
 				foundListItem = true
@@ -4962,10 +5382,13 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 					return expr
 				}
 			}
+			// Updating the generated column doesn't require the privilege on the columns it depends on.
+			visitInfoLen := len(b.visitInfo)
 			newExpr, np, err = b.rewriteWithPreprocess(ctx, assign.Expr, p, nil, nil, false, rewritePreprocess)
 			if err != nil {
 				return nil, nil, false, err
 			}
+			b.visitInfo = b.visitInfo[:visitInfoLen]
 			// check if the column is modified
 			dependentColumns := expression.ExtractDependentColumns(newExpr)
 			var isModified bool
@@ -4993,7 +5416,14 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 		if dbName == "" {
 			dbName = b.ctx.GetSessionVars().CurrentDB
 		}
-		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.UpdatePriv, dbName, name.OrigTblName.L, "", nil)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.UpdatePriv, dbName, name.OrigTblName.L, privilege.AnyColumn, nil)
+		if i < len(list) {
+			var authErr error
+			if user := b.ctx.GetSessionVars().User; user != nil {
+				authErr = ErrColumnaccessDenied.FastGenByArgs("UPDATE", user.AuthUsername, user.AuthHostname, name.OrigColName.L, name.OrigTblName.L)
+			}
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.UpdatePriv, dbName, name.OrigTblName.L, name.OrigColName.L, authErr)
+		}
 	}
 	return newList, p, allAssignmentsAreConstant, nil
 }
@@ -5151,6 +5581,9 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 			if tn.TableInfo.IsSequence() {
 				return nil, errors.Errorf("delete sequence %s is not supported now.", tn.Name.O)
 			}
+			if tn.TableInfo.ExternalTable != nil {
+				return nil, ErrNonUpdatableTable.GenWithStackByArgs(tn.Name.O, "DELETE")
+			}
 			if sessionVars.User != nil {
 				authErr = ErrTableaccessDenied.FastGenByArgs("DELETE", sessionVars.User.AuthUsername, sessionVars.User.AuthHostname, tb.Name.L)
 			}
@@ -5170,6 +5603,9 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 			if v.TableInfo.IsSequence() {
 				return nil, errors.Errorf("delete sequence %s is not supported now.", v.Name.O)
 			}
+			if v.TableInfo.ExternalTable != nil {
+				return nil, ErrNonUpdatableTable.GenWithStackByArgs(v.Name.O, "DELETE")
+			}
 			dbName := v.Schema.L
 			if dbName == "" {
 				dbName = b.ctx.GetSessionVars().CurrentDB
@@ -5506,7 +5942,7 @@ func (b *PlanBuilder) buildWindowFunctionFrame(ctx context.Context, spec *ast.Wi
 	if frameClause == nil {
 		return nil, nil
 	}
-	frame := &WindowFrame{Type: frameClause.Type}
+	frame := &WindowFrame{Type: frameClause.Type, Exclusion: frameClause.Exclusion}
 	var err error
 	frame.Start, err = b.buildWindowFunctionFrameBound(ctx, spec, orderByItems, &frameClause.Extent.Start)
 	if err != nil {
@@ -5779,8 +6215,9 @@ func (b *PlanBuilder) handleDefaultFrame(spec *ast.WindowSpec, windowFuncName st
 		}
 		return &newSpec, true
 	}
-	// "RANGE/ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING" is equivalent to empty frame.
-	if needFrame && spec.Frame != nil &&
+	// "RANGE/ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING" is equivalent to empty frame,
+	// unless some rows are excluded from the frame.
+	if needFrame && spec.Frame != nil && spec.Frame.Exclusion == ast.ExcludeNoOthers &&
 		spec.Frame.Extent.Start.UnBounded && spec.Frame.Extent.End.UnBounded {
 		newSpec := *spec
 		newSpec.Frame = nil
@@ -6148,6 +6585,35 @@ func appendDynamicVisitInfo(vi []visitInfo, priv string, withGrant bool, err err
 	})
 }
 
+// registerColumnVisitInfo registers the columns read from the table or view, the SELECT privilege on the column is
+// required once it is referenced by the statement.
+func (b *PlanBuilder) registerColumnVisitInfo(dbName, tblName model.CIStr, cols []*expression.Column, names types.NameSlice) {
+	if b.columnVisitInfo == nil {
+		b.columnVisitInfo = make(map[int64]visitInfo, len(cols))
+	}
+	user := b.ctx.GetSessionVars().User
+	for i, col := range cols {
+		var authErr error
+		if user != nil {
+			authErr = ErrColumnaccessDenied.FastGenByArgs("SELECT", user.AuthUsername, user.AuthHostname, names[i].ColName.L, tblName.L)
+		}
+		b.columnVisitInfo[col.UniqueID] = visitInfo{
+			privilege: mysql.SelectPriv,
+			db:        dbName.L,
+			table:     tblName.L,
+			column:    names[i].ColName.L,
+			err:       authErr,
+		}
+	}
+}
+
+// appendColumnVisitInfo appends the column-level SELECT privilege check if the column is read from a table or view.
+func (b *PlanBuilder) appendColumnVisitInfo(col *expression.Column) {
+	if v, ok := b.columnVisitInfo[col.UniqueID]; ok {
+		b.visitInfo = append(b.visitInfo, v)
+	}
+}
+
 func appendVisitInfo(vi []visitInfo, priv mysql.PrivilegeType, db, tbl, col string, err error) []visitInfo {
 	return append(vi, visitInfo{
 		privilege: priv,
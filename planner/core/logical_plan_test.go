@@ -1006,21 +1006,24 @@ func (s *testPlanSuite) TestVisitInfo(c *C) {
 		{
 			sql: "insert into t (a) values (1)",
 			ans: []visitInfo{
-				{mysql.InsertPriv, "test", "t", "", nil, false, "", false},
+				{mysql.InsertPriv, "test", "t", "*", nil, false, "", false},
+				{mysql.InsertPriv, "test", "t", "a", nil, false, "", false},
 			},
 		},
 		{
 			sql: "delete from t where a = 1",
 			ans: []visitInfo{
 				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "*", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false},
 			},
 		},
 		{
 			sql: "delete from t order by a",
 			ans: []visitInfo{
 				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "*", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false},
 			},
 		},
 		{
@@ -1041,34 +1044,54 @@ func (s *testPlanSuite) TestVisitInfo(c *C) {
 			sql: "delete from a1 using t as a1 inner join t as a2 where a1.a = a2.a",
 			ans: []visitInfo{
 				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "*", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false},
 			},
 		},
 		{
 			sql: "update t set a = 7 where a = 1",
 			ans: []visitInfo{
-				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "*", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "a", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "*", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false},
 			},
 		},
 		{
 			sql: "update t, (select * from t) a1 set t.a = a1.a;",
 			ans: []visitInfo{
-				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "*", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "a", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "*", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "b", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "c", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "d", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "e", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "c_str", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "d_str", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "e_str", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "f", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "g", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "h", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "i_date", nil, false, "", false},
 			},
 		},
 		{
 			sql: "update t a1 set a1.a = a1.a + 1",
 			ans: []visitInfo{
-				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "*", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "a", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "*", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false},
 			},
 		},
 		{
 			sql: "select a, sum(e) from t group by a",
 			ans: []visitInfo{
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "*", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "e", nil, false, "", false},
 			},
 		},
 		{
@@ -1420,20 +1443,16 @@ func (v visitInfoArray) Len() int {
 }
 
 func (v visitInfoArray) Less(i, j int) bool {
-	if v[i].privilege < v[j].privilege {
-		return true
+	if v[i].privilege != v[j].privilege {
+		return v[i].privilege < v[j].privilege
 	}
-	if v[i].db < v[j].db {
-		return true
+	if v[i].db != v[j].db {
+		return v[i].db < v[j].db
 	}
-	if v[i].table < v[j].table {
-		return true
+	if v[i].table != v[j].table {
+		return v[i].table < v[j].table
 	}
-	if v[i].column < v[j].column {
-		return true
-	}
-
-	return false
+	return v[i].column < v[j].column
 }
 
 func (v visitInfoArray) Swap(i, j int) {
@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"go.uber.org/zap"
@@ -53,6 +54,7 @@ var (
 	_ LogicalPlan = &LogicalLock{}
 	_ LogicalPlan = &LogicalLimit{}
 	_ LogicalPlan = &LogicalWindow{}
+	_ LogicalPlan = &LogicalExpand{}
 )
 
 // JoinType contains CrossJoin, InnerJoin, LeftOuterJoin, RightOuterJoin, FullOuterJoin, SemiJoin.
@@ -496,6 +498,45 @@ type LogicalTableDual struct {
 	RowCount int
 }
 
+// LogicalJSONTable represents the JSON_TABLE table function.
+type LogicalJSONTable struct {
+	logicalSchemaProducer
+
+	// Expr is the JSON document.
+	Expr    expression.Expression
+	RowPath *JSONTablePath
+}
+
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Expr)
+}
+
+// JSONTablePath is the row path or a NESTED PATH of JSON_TABLE with the columns defined in it.
+type JSONTablePath struct {
+	Path    json.PathExpression
+	Columns []*JSONTableColumn
+	Nested  []*JSONTablePath
+}
+
+// JSONTableColumn is a column defined in JSON_TABLE other than NESTED PATH.
+type JSONTableColumn struct {
+	Tp   ast.JSONTableColumnType
+	Name string
+	Path json.PathExpression
+	// Offset is the offset of the column in the schema of JSON_TABLE.
+	Offset  int
+	RetType *types.FieldType
+	OnEmpty *JSONTableOnResponse
+	OnError *JSONTableOnResponse
+}
+
+// JSONTableOnResponse is the ON EMPTY or ON ERROR clause of a JSON_TABLE column.
+type JSONTableOnResponse struct {
+	Tp      ast.JSONTableOnResponseType
+	Default json.BinaryJSON
+}
+
 // LogicalMemTable represents a memory table or virtual table
 // Some memory tables wants to take the ownership of some predications
 // e.g
@@ -1072,6 +1113,8 @@ type WindowFrame struct {
 	Type  ast.FrameType
 	Start *FrameBound
 	End   *FrameBound
+	// Exclusion indicates the rows excluded from the frame by the EXCLUDE clause.
+	Exclusion ast.FrameExclusion
 }
 
 // FrameBound is the boundary of a frame.
@@ -1138,6 +1181,7 @@ func (p *LogicalWindow) EqualFrame(ctx sessionctx.Context, newWindow *LogicalWin
 		return true
 	}
 	if p.Frame.Type != newWindow.Frame.Type ||
+		p.Frame.Exclusion != newWindow.Frame.Exclusion ||
 		p.Frame.Start.Type != newWindow.Frame.Start.Type ||
 		p.Frame.Start.UnBounded != newWindow.Frame.Start.UnBounded ||
 		p.Frame.Start.Num != newWindow.Frame.Start.Num ||
@@ -1187,6 +1231,30 @@ func (p *LogicalWindow) GetWindowResultColumns() []*expression.Column {
 	return p.schema.Columns[p.schema.Len()-len(p.WindowFuncDescs):]
 }
 
+// LogicalExpand expands every input row to a row for each grouping set, it's built under the aggregation of
+// GROUP BY ... WITH ROLLUP. The GROUP BY items which are not in a grouping set are NULL in its rows, and the
+// grouping id column tells which grouping set a row belongs to.
+type LogicalExpand struct {
+	logicalSchemaProducer
+
+	// LevelExprs are the projections of the grouping sets, each of them has an expression for every column in
+	// the schema.
+	LevelExprs [][]expression.Expression
+	// GroupingID is the column of the grouping id. Its i-th bit is set when the i-th GROUP BY item is NULL.
+	GroupingID *expression.Column
+}
+
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalExpand) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(p.LevelExprs))
+	for _, exprs := range p.LevelExprs {
+		for _, expr := range exprs {
+			corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+		}
+	}
+	return corCols
+}
+
 // ExtractCorColumnsBySchema only extracts the correlated columns that match the specified schema.
 // e.g. If the correlated columns from plan are [t1.a, t2.a, t3.a] and specified schema is [t2.a, t2.b, t2.c],
 // only [t2.a] is returned.
@@ -788,8 +788,8 @@ func (e *HotRegionsHistoryTableExtractor) Extract(
 	e.IsLearners = e.convertToBoolSlice(isLearnersUint64)
 	e.IsLeaders = e.convertToBoolSlice(isLeadersUint64)
 
-	// Extract the `type` column
-	remained, typeSkipRequest, types := e.extractCol(schema, names, remained, "type", false)
+	// Extract the `type` column, PD keys the hot regions by the lowercase type while the rows show the uppercase one.
+	remained, typeSkipRequest, types := e.extractCol(schema, names, remained, "type", true)
 	e.HotRegionTypes = types
 	e.SkipRequest = typeSkipRequest
 	if e.SkipRequest {
@@ -1295,6 +1295,57 @@ func (e *TableStorageStatsExtractor) explainInfo(p *PhysicalMemTable) string {
 	return r.String()
 }
 
+// InfoSchemaTablesExtractor is used to extract the `table_schema` and `table_name` predicates of the metadata tables,
+// e.g. `tables`, `columns` and `statistics`, so only the matched schemas and tables are visited.
+// The extracted predicates are kept in the remained conditions, the filter is only an optimization.
+type InfoSchemaTablesExtractor struct {
+	extractHelper
+	// SkipRequest means the where clause always false, we don't need to visit any table.
+	SkipRequest bool
+	// TableSchema represents the lower case schema names to visit, all schemas are visited if it's empty.
+	// e.g: SELECT * FROM information_schema.tables WHERE table_schema in ('test', 'mysql').
+	TableSchema set.StringSet
+	// TableName represents the lower case table names to visit, all tables are visited if it's empty.
+	// e.g: SELECT * FROM information_schema.columns WHERE table_name = 't'.
+	TableName set.StringSet
+}
+
+// Extract implements the MemTablePredicateExtractor Extract interface.
+func (e *InfoSchemaTablesExtractor) Extract(
+	_ sessionctx.Context,
+	schema *expression.Schema,
+	names []*types.FieldName,
+	predicates []expression.Expression,
+) []expression.Expression {
+	remained, schemaSkip, tableSchema := e.extractCol(schema, names, predicates, "table_schema", true)
+	_, tableSkip, tableName := e.extractCol(schema, names, remained, "table_name", true)
+	e.SkipRequest = schemaSkip || tableSkip
+	if e.SkipRequest {
+		return nil
+	}
+	e.TableSchema = tableSchema
+	e.TableName = tableName
+	return predicates
+}
+
+func (e *InfoSchemaTablesExtractor) explainInfo(p *PhysicalMemTable) string {
+	if e.SkipRequest {
+		return "skip_request: true"
+	}
+
+	r := new(bytes.Buffer)
+	if len(e.TableSchema) > 0 {
+		r.WriteString(fmt.Sprintf("schema:[%s]", extractStringFromStringSet(e.TableSchema)))
+	}
+	if r.Len() > 0 && len(e.TableName) > 0 {
+		r.WriteString(", ")
+	}
+	if len(e.TableName) > 0 {
+		r.WriteString(fmt.Sprintf("table:[%s]", extractStringFromStringSet(e.TableName)))
+	}
+	return r.String()
+}
+
 func (e *SlowQueryExtractor) explainInfo(p *PhysicalMemTable) string {
 	if e.SkipRequest {
 		return "skip_request: true"
@@ -1468,3 +1519,28 @@ func (e *TikvRegionPeersExtractor) explainInfo(p *PhysicalMemTable) string {
 	}
 	return s
 }
+
+// ExternalTableExtractor is used to push the predicates down to the reader of an external table.
+// The rows are filtered right after they're parsed from the files, so the
+// unmatched rows are never passed to the upper operators.
+type ExternalTableExtractor struct {
+	// Conditions are evaluated on every row read from the files.
+	Conditions []expression.Expression
+}
+
+// Extract implements the MemTablePredicateExtractor Extract interface
+func (e *ExternalTableExtractor) Extract(_ sessionctx.Context,
+	_ *expression.Schema,
+	_ []*types.FieldName,
+	predicates []expression.Expression,
+) []expression.Expression {
+	e.Conditions = append(e.Conditions, predicates...)
+	return nil
+}
+
+func (e *ExternalTableExtractor) explainInfo(_ *PhysicalMemTable) string {
+	if len(e.Conditions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("conditions:[%s]", expression.SortedExplainExpressionList(e.Conditions))
+}
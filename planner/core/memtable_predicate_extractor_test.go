@@ -1331,6 +1331,14 @@ func (s *extractorSuite) TestTiDBHotRegionsHistoryTableExtractor(c *C) {
 			sql:         "select * from information_schema.tidb_hot_regions_history where type in ('read') and type in ('write')",
 			skipRequest: true,
 		},
+		{
+			sql:            "select * from information_schema.tidb_hot_regions_history where type='READ'",
+			hotRegionTypes: set.NewStringSet(plannercore.HotRegionTypeRead),
+		},
+		{
+			sql:            "select * from information_schema.tidb_hot_regions_history where type in ('Read', 'WRITE')",
+			hotRegionTypes: set.NewStringSet(plannercore.HotRegionTypeRead, plannercore.HotRegionTypeWrite),
+		},
 		// Test `is_learner`, `is_leaeder` columns.
 		{
 			sql:            "select * from information_schema.tidb_hot_regions_history where is_learner=1",
@@ -1552,3 +1560,66 @@ func (s *extractorSuite) TestTikvRegionPeersExtractor(c *C) {
 		}
 	}
 }
+
+func (s *extractorSuite) TestInfoSchemaTablesExtractor(c *C) {
+	se, err := session.CreateSession4Test(s.store)
+	c.Assert(err, IsNil)
+
+	var cases = []struct {
+		sql         string
+		tableSchema set.StringSet
+		tableName   set.StringSet
+		skipRequest bool
+	}{
+		{
+			sql: "select * from information_schema.tables",
+		},
+		{
+			sql:         "select * from information_schema.tables where table_schema='Test'",
+			tableSchema: set.NewStringSet("test"),
+			tableName:   set.NewStringSet(),
+		},
+		{
+			sql:         "select * from information_schema.columns where table_schema='test' and table_name in ('t1', 'T2')",
+			tableSchema: set.NewStringSet("test"),
+			tableName:   set.NewStringSet("t1", "t2"),
+		},
+		{
+			sql:         "select * from information_schema.statistics where table_name='t1' or table_name='t2'",
+			tableSchema: set.NewStringSet(),
+			tableName:   set.NewStringSet("t1", "t2"),
+		},
+		{
+			sql:         "select * from information_schema.columns where table_schema in ('test', 'mysql') and table_schema='mysql'",
+			tableSchema: set.NewStringSet("mysql"),
+			tableName:   set.NewStringSet(),
+		},
+		{
+			sql:         "select * from information_schema.tables where table_schema='test' and table_schema='mysql'",
+			skipRequest: true,
+		},
+		{
+			sql:         "select * from information_schema.tables where table_name='t1' and table_name='t2'",
+			skipRequest: true,
+		},
+	}
+	parser := parser.New()
+	for _, ca := range cases {
+		logicalMemTable := s.getLogicalMemTable(c, se, parser, ca.sql)
+		c.Assert(logicalMemTable.Extractor, NotNil)
+
+		extractor := logicalMemTable.Extractor.(*plannercore.InfoSchemaTablesExtractor)
+		c.Assert(extractor.SkipRequest, Equals, ca.skipRequest, Commentf("SQL: %v", ca.sql))
+		if ca.skipRequest {
+			continue
+		}
+		c.Assert(extractor.TableSchema, HasLen, len(ca.tableSchema), Commentf("SQL: %v", ca.sql))
+		for name := range ca.tableSchema {
+			c.Assert(extractor.TableSchema.Exist(name), IsTrue, Commentf("SQL: %v", ca.sql))
+		}
+		c.Assert(extractor.TableName, HasLen, len(ca.tableName), Commentf("SQL: %v", ca.sql))
+		for name := range ca.tableName {
+			c.Assert(extractor.TableName.Exist(name), IsTrue, Commentf("SQL: %v", ca.sql))
+		}
+	}
+}
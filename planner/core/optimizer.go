@@ -271,6 +271,10 @@ func DoOptimize(ctx context.Context, sctx sessionctx.Context, flag uint64, logic
 	if err != nil {
 		return nil, 0, err
 	}
+	if !sctx.GetSessionVars().InRestrictedSQL && variable.EnableColumnTracking.Load() {
+		predicateColumns, _ := CollectColumnStatsUsage(logic)
+		sctx.UpdateColStatsUsage(predicateColumns)
+	}
 	if !AllowCartesianProduct.Load() && existsCartesianProduct(logic) {
 		return nil, 0, errors.Trace(ErrCartesianProductUnsupported)
 	}
@@ -494,6 +498,10 @@ func eliminateUnionScanAndLock(sctx sessionctx.Context, p PhysicalPlan) Physical
 		return p
 	}
 	if physLock != nil {
+		// The locked rows are skipped by the SelectLock executor, so it can't be eliminated.
+		if physLock.Lock.LockType == ast.SelectLockForUpdateSkipLocked {
+			return p
+		}
 		lock, waitTime := getLockWaitTime(sctx, physLock.Lock)
 		if !lock {
 			return p
@@ -62,6 +62,7 @@ var (
 	_ PhysicalPlan = &PhysicalMergeJoin{}
 	_ PhysicalPlan = &PhysicalUnionScan{}
 	_ PhysicalPlan = &PhysicalWindow{}
+	_ PhysicalPlan = &PhysicalExpand{}
 	_ PhysicalPlan = &PhysicalShuffle{}
 	_ PhysicalPlan = &PhysicalShuffleReceiverStub{}
 	_ PhysicalPlan = &BatchPointGetPlan{}
@@ -89,6 +90,9 @@ type PhysicalTableReader struct {
 
 	IsCommonHandle bool
 
+	// Paging indicates whether the coprocessor requests are sent in paging mode.
+	Paging bool
+
 	// Used by partition table.
 	PartitionInfo PartitionInfo
 	// Used by MPP, because MPP plan may contain join/union/union all, it is possible that a physical table reader contains more than 1 table scan
@@ -162,6 +166,7 @@ func (p *PhysicalTableReader) Clone() (PhysicalPlan, error) {
 	cloned.StoreType = p.StoreType
 	cloned.BatchCop = p.BatchCop
 	cloned.IsCommonHandle = p.IsCommonHandle
+	cloned.Paging = p.Paging
 	if cloned.tablePlan, err = p.tablePlan.Clone(); err != nil {
 		return nil, err
 	}
@@ -196,6 +201,9 @@ type PhysicalIndexReader struct {
 	// OutputColumns represents the columns that index reader should return.
 	OutputColumns []*expression.Column
 
+	// Paging indicates whether the coprocessor requests are sent in paging mode.
+	Paging bool
+
 	// Used by partition table.
 	PartitionInfo PartitionInfo
 }
@@ -215,6 +223,7 @@ func (p *PhysicalIndexReader) Clone() (PhysicalPlan, error) {
 		return nil, err
 	}
 	cloned.OutputColumns = cloneCols(p.OutputColumns)
+	cloned.Paging = p.Paging
 	return cloned, err
 }
 
@@ -308,6 +317,7 @@ func (p *PhysicalIndexLookUpReader) Clone() (PhysicalPlan, error) {
 	if p.PushedLimit != nil {
 		cloned.PushedLimit = p.PushedLimit.Clone()
 	}
+	cloned.Paging = p.Paging
 	return cloned, nil
 }
 
@@ -447,6 +457,19 @@ type PhysicalMemTable struct {
 	QueryTimeRange QueryTimeRange
 }
 
+// ExtractCorrelatedCols implements PhysicalPlan interface.
+func (p *PhysicalMemTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	extractor, ok := p.Extractor.(*ExternalTableExtractor)
+	if !ok {
+		return nil
+	}
+	corCols := make([]*expression.CorrelatedColumn, 0, len(extractor.Conditions))
+	for _, cond := range extractor.Conditions {
+		corCols = append(corCols, expression.ExtractCorColumns(cond)...)
+	}
+	return corCols
+}
+
 // PhysicalTableScan represents a table scan plan.
 type PhysicalTableScan struct {
 	physicalSchemaProducer
@@ -1299,6 +1322,25 @@ func (p *PhysicalWindow) ExtractCorrelatedCols() []*expression.CorrelatedColumn
 	return corCols
 }
 
+// PhysicalExpand is the physical operator of LogicalExpand.
+type PhysicalExpand struct {
+	physicalSchemaProducer
+
+	LevelExprs [][]expression.Expression
+	GroupingID *expression.Column
+}
+
+// ExtractCorrelatedCols implements PhysicalPlan interface.
+func (p *PhysicalExpand) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(p.LevelExprs))
+	for _, exprs := range p.LevelExprs {
+		for _, expr := range exprs {
+			corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+		}
+	}
+	return corCols
+}
+
 // PhysicalShuffle represents a shuffle plan.
 // `Tails` and `DataSources` are the last plan within and the first plan following the "shuffle", respectively,
 //  to build the child executors chain.
@@ -1362,6 +1404,19 @@ func CollectPlanStatsVersion(plan PhysicalPlan, statsInfos map[string]uint64) ma
 	return statsInfos
 }
 
+// PhysicalJSONTable is the physical plan of JSON_TABLE.
+type PhysicalJSONTable struct {
+	physicalSchemaProducer
+
+	Expr    expression.Expression
+	RowPath *JSONTablePath
+}
+
+// ExtractCorrelatedCols implements PhysicalPlan interface.
+func (p *PhysicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Expr)
+}
+
 // PhysicalShow represents a show plan.
 type PhysicalShow struct {
 	physicalSchemaProducer
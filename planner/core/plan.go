@@ -110,19 +110,33 @@ func optimizeByShuffle(tsk task, ctx sessionctx.Context) task {
 	return tsk
 }
 
+// getShuffleTailAndDataSource returns the tail and the data source of the shuffle built for `pp`, whose only child
+// is required to be sorted by the partition keys.
+// If the child is a Sort, the data source is shuffled by hash before sorting, and each worker sorts its own partitions.
+// Otherwise, the child is already sorted, so rows of a partition are continuous and the data source can be shuffled by
+// range, which keeps the order inside every partition. It returns a nil tail if the shuffle should not be built.
+func getShuffleTailAndDataSource(pp PhysicalPlan, ctx sessionctx.Context) (tail, dataSource PhysicalPlan, splitterType PartitionSplitterType) {
+	if sort, ok := pp.Children()[0].(*PhysicalSort); ok {
+		return sort, sort.Children()[0], PartitionHashSplitterType
+	}
+	if !ctx.GetSessionVars().EnableSortedShuffle {
+		// Multi-thread executing on SORTED data source is not always effective, the parallelism
+		// may not cover the cost of splitting the data source.
+		return nil, nil, PartitionRangeSplitterType
+	}
+	return pp, pp.Children()[0], PartitionRangeSplitterType
+}
+
 func optimizeByShuffle4Window(pp *PhysicalWindow, ctx sessionctx.Context) *PhysicalShuffle {
 	concurrency := ctx.GetSessionVars().WindowConcurrency()
 	if concurrency <= 1 {
 		return nil
 	}
 
-	sort, ok := pp.Children()[0].(*PhysicalSort)
-	if !ok {
-		// Multi-thread executing on SORTED data source is not effective enough by current implementation.
-		// TODO: Implement a better one.
+	tail, dataSource, splitterType := getShuffleTailAndDataSource(pp, ctx)
+	if tail == nil {
 		return nil
 	}
-	tail, dataSource := sort, sort.Children()[0]
 
 	partitionBy := make([]*expression.Column, 0, len(pp.PartitionBy))
 	for _, item := range pp.PartitionBy {
@@ -143,7 +157,7 @@ func optimizeByShuffle4Window(pp *PhysicalWindow, ctx sessionctx.Context) *Physi
 		Concurrency:  concurrency,
 		Tails:        []PhysicalPlan{tail},
 		DataSources:  []PhysicalPlan{dataSource},
-		SplitterType: PartitionHashSplitterType,
+		SplitterType: splitterType,
 		ByItemArrays: [][]expression.Expression{byItems},
 	}.Init(ctx, pp.statsInfo(), pp.SelectBlockOffset(), reqProp)
 	return shuffle
@@ -155,13 +169,10 @@ func optimizeByShuffle4StreamAgg(pp *PhysicalStreamAgg, ctx sessionctx.Context)
 		return nil
 	}
 
-	sort, ok := pp.Children()[0].(*PhysicalSort)
-	if !ok {
-		// Multi-thread executing on SORTED data source is not effective enough by current implementation.
-		// TODO: Implement a better one.
+	tail, dataSource, splitterType := getShuffleTailAndDataSource(pp, ctx)
+	if tail == nil {
 		return nil
 	}
-	tail, dataSource := sort, sort.Children()[0]
 
 	partitionBy := make([]*expression.Column, 0, len(pp.GroupByItems))
 	for _, item := range pp.GroupByItems {
@@ -180,7 +191,7 @@ func optimizeByShuffle4StreamAgg(pp *PhysicalStreamAgg, ctx sessionctx.Context)
 		Concurrency:  concurrency,
 		Tails:        []PhysicalPlan{tail},
 		DataSources:  []PhysicalPlan{dataSource},
-		SplitterType: PartitionHashSplitterType,
+		SplitterType: splitterType,
 		ByItemArrays: [][]expression.Expression{cloneExprs(pp.GroupByItems)},
 	}.Init(ctx, pp.statsInfo(), pp.SelectBlockOffset(), reqProp)
 	return shuffle
@@ -327,7 +327,13 @@ func (p *PhysicalIndexScan) ToPB(ctx sessionctx.Context, _ kv.StoreType) (*tipb.
 		} else if col.ID == model.ExtraPidColID {
 			columns = append(columns, model.NewExtraPartitionIDColInfo())
 		} else {
-			columns = append(columns, FindColumnInfoByID(tableColumns, col.ID))
+			colInfo := FindColumnInfoByID(tableColumns, col.ID)
+			// The entries of multi-valued index are encoded by the element type of the array.
+			if elemTp, ok := expression.ArrayCastElemType(col.VirtualExpr); ok && p.Index.MVIndex {
+				colInfo = colInfo.Clone()
+				colInfo.FieldType = *elemTp
+			}
+			columns = append(columns, colInfo)
 		}
 	}
 	var pkColIds []int64
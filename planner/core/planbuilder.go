@@ -20,6 +20,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 	"time"
 
@@ -454,8 +455,11 @@ type PlanBuilder struct {
 	// colMapper stores the column that must be pre-resolved.
 	colMapper map[*ast.ColumnNameExpr]int
 	// visitInfo is used for privilege check.
-	visitInfo     []visitInfo
-	tableHintInfo []tableHintInfo
+	visitInfo []visitInfo
+	// columnVisitInfo maps the unique ID of the columns read from the tables and views to the column-level
+	// privilege check, it's appended to visitInfo once the column is referenced.
+	columnVisitInfo map[int64]visitInfo
+	tableHintInfo   []tableHintInfo
 	// optFlag indicates the flags of the optimizer rules.
 	optFlag uint64
 	// capFlag indicates the capability flags.
@@ -468,7 +472,9 @@ type PlanBuilder struct {
 	rewriterPool    []*expressionRewriter
 	rewriterCounter int
 
-	windowSpecs  map[string]*ast.WindowSpec
+	windowSpecs map[string]*ast.WindowSpec
+	// rollup is the GROUP BY ... WITH ROLLUP of the SELECT statement being built.
+	rollup       *rollupInfo
 	inUpdateStmt bool
 	inDeleteStmt bool
 	// inStraightJoin represents whether the current "SELECT" statement has
@@ -727,10 +733,13 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 	case *ast.AnalyzeTableStmt:
 		return b.buildAnalyze(x)
 	case *ast.BinlogStmt, *ast.FlushStmt, *ast.UseStmt, *ast.BRIEStmt,
-		*ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.CreateUserStmt, *ast.SetPwdStmt, *ast.AlterInstanceStmt,
+		*ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.SavepointStmt, *ast.ReleaseSavepointStmt,
+		*ast.CreateUserStmt, *ast.SetPwdStmt, *ast.AlterInstanceStmt,
 		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.RevokeStmt, *ast.KillStmt, *ast.DropStatsStmt,
 		*ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt,
-		*ast.RenameUserStmt:
+		*ast.RenameUserStmt, *ast.CreateFunctionStmt, *ast.DropFunctionStmt, *ast.LockStatsStmt, *ast.UnlockStatsStmt,
+		*ast.SetResourceGroupStmt, *ast.SetSessionStatesStmt, *ast.SetSessionAuthorizationStmt,
+		*ast.GrantProxyStmt, *ast.RevokeProxyStmt:
 		return b.buildSimple(ctx, node.(ast.StmtNode))
 	case ast.DDLNode:
 		return b.buildDDL(ctx, x)
@@ -856,7 +865,7 @@ func (b *PlanBuilder) buildDropBindPlan(v *ast.DropBindingStmt) (Plan, error) {
 	if v.HintedNode != nil {
 		p.BindSQL = utilparser.RestoreWithDefaultDB(v.HintedNode, b.ctx.GetSessionVars().CurrentDB, v.HintedNode.Text())
 	}
-	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
+	b.visitInfo = appendBindingVisitInfo(b.visitInfo)
 	return p, nil
 }
 
@@ -901,10 +910,16 @@ func (b *PlanBuilder) buildCreateBindPlan(v *ast.CreateBindingStmt) (Plan, error
 		Charset:      charSet,
 		Collation:    collation,
 	}
-	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
+	b.visitInfo = appendBindingVisitInfo(b.visitInfo)
 	return p, nil
 }
 
+// appendBindingVisitInfo appends the privilege required by creating and dropping the bindings.
+func appendBindingVisitInfo(vi []visitInfo) []visitInfo {
+	err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or OPTIMIZER_ADMIN")
+	return appendDynamicVisitInfo(vi, "OPTIMIZER_ADMIN", false, err)
+}
+
 // detectSelectAgg detects an aggregate function or GROUP BY clause.
 func (b *PlanBuilder) detectSelectAgg(sel *ast.SelectStmt) bool {
 	if sel.GroupBy != nil {
@@ -997,7 +1012,8 @@ func isForUpdateReadSelectLock(lock *ast.SelectLockInfo) bool {
 	}
 	return lock.LockType == ast.SelectLockForUpdate ||
 		lock.LockType == ast.SelectLockForUpdateNoWait ||
-		lock.LockType == ast.SelectLockForUpdateWaitN
+		lock.LockType == ast.SelectLockForUpdateWaitN ||
+		lock.LockType == ast.SelectLockForUpdateSkipLocked
 }
 
 // getLatestIndexInfo gets the index info of latest schema version from given table id,
@@ -1058,6 +1074,11 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 			if tblInfo.IsCommonHandle && index.Primary {
 				continue
 			}
+			// The multi-valued index has several entries for one row, it can only
+			// be used by the IndexMerge path built from MEMBER OF and JSON_CONTAINS.
+			if index.MVIndex {
+				continue
+			}
 			if check && latestIndexes == nil {
 				latestIndexes, check, err = getLatestIndexInfo(ctx, tblInfo.ID, 0)
 				if err != nil {
@@ -1288,7 +1309,42 @@ func (b *PlanBuilder) buildPrepare(x *ast.PrepareStmt) Plan {
 	return p
 }
 
+// adminStmtPrivileges are the dynamic privileges required by the ADMIN statements, SUPER is also accepted for them.
+var adminStmtPrivileges = map[ast.AdminStmtType]string{
+	ast.AdminShowDDL:                     "DDL_ADMIN",
+	ast.AdminShowDDLJobs:                 "DDL_ADMIN",
+	ast.AdminShowDDLJobQueries:           "DDL_ADMIN",
+	ast.AdminCancelDDLJobs:               "DDL_ADMIN",
+	ast.AdminShowNextRowID:               "DDL_ADMIN",
+	ast.AdminCheckTable:                  "TABLE_CHECK_ADMIN",
+	ast.AdminCheckIndex:                  "TABLE_CHECK_ADMIN",
+	ast.AdminCheckIndexRange:             "TABLE_CHECK_ADMIN",
+	ast.AdminChecksumTable:               "TABLE_CHECK_ADMIN",
+	ast.AdminRecoverIndex:                "TABLE_CHECK_ADMIN",
+	ast.AdminCleanupIndex:                "TABLE_CHECK_ADMIN",
+	ast.AdminReloadExprPushdownBlacklist: "OPTIMIZER_ADMIN",
+	ast.AdminReloadOptRuleBlacklist:      "OPTIMIZER_ADMIN",
+	ast.AdminFlushBindings:               "OPTIMIZER_ADMIN",
+	ast.AdminCaptureBindings:             "OPTIMIZER_ADMIN",
+	ast.AdminEvolveBindings:              "OPTIMIZER_ADMIN",
+	ast.AdminReloadBindings:              "OPTIMIZER_ADMIN",
+	ast.AdminReloadStatistics:            "OPTIMIZER_ADMIN",
+	ast.AdminPluginEnable:                "PLUGIN_ADMIN",
+	ast.AdminPluginDisable:               "PLUGIN_ADMIN",
+	ast.AdminShowSlow:                    "DIAGNOSTICS_ADMIN",
+	ast.AdminShowTelemetry:               "DIAGNOSTICS_ADMIN",
+	ast.AdminResetTelemetryID:            "DIAGNOSTICS_ADMIN",
+}
+
 func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan, error) {
+	// Admin command can only be executed by administrator.
+	if priv, ok := adminStmtPrivileges[as.Tp]; ok {
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or " + priv)
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, priv, false, err)
+	} else {
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
+	}
+
 	var ret Plan
 	var err error
 	switch as.Tp {
@@ -1383,9 +1439,6 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 	default:
 		return nil, ErrUnsupportedType.GenWithStack("Unsupported ast.AdminStmt(%T) for buildAdmin", as)
 	}
-
-	// Admin command can only be executed by administrator.
-	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
 	return ret, nil
 }
 
@@ -1560,6 +1613,10 @@ func (b *PlanBuilder) buildPhysicalIndexLookUpReaders(ctx context.Context, dbNam
 			// Skip checking clustered index.
 			continue
 		}
+		if idxInfo.MVIndex {
+			// Skip checking multi-valued index, its entries can't be compared with rows one by one.
+			continue
+		}
 		if idxInfo.State != model.StatePublic {
 			logutil.Logger(ctx).Info("build physical index lookup reader, the index isn't public",
 				zap.String("index", idxInfo.Name.O),
@@ -1713,7 +1770,7 @@ func getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []
 		colsInfo = append(colsInfo, col)
 	}
 	for _, idx := range tn.TableInfo.Indices {
-		if idx.State == model.StatePublic {
+		if idx.State == model.StatePublic && !idx.MVIndex {
 			indicesInfo = append(indicesInfo, idx)
 		}
 	}
@@ -1818,16 +1875,36 @@ func GetPhysicalIDsAndPartitionNames(tblInfo *model.TableInfo, partitionNames []
 // 3. Otherwise it returns all the columns.
 func (b *PlanBuilder) getAnalyzeColumnsInfo(as *ast.AnalyzeTableStmt, tbl *ast.TableName) ([]*model.ColumnInfo, error) {
 	tblInfo := tbl.TableInfo
-	if len(as.ColumnNames) == 0 {
-		return tblInfo.Columns, nil
-	}
 	columnIDs := make(map[int64]struct{}, len(tblInfo.Columns))
-	for _, colName := range as.ColumnNames {
-		colInfo := model.FindColumnInfo(tblInfo.Columns, colName.L)
-		if colInfo == nil {
-			return nil, ErrAnalyzeMissColumn.GenWithStackByArgs(colName.O, tblInfo.Name.O)
+	switch {
+	case as.ColumnChoice == model.PredicateColumns:
+		predicateColIDs, err := domain.GetDomain(b.ctx).StatsHandle().GetPredicateColumns(tblInfo.ID)
+		if err != nil {
+			return nil, err
+		}
+		predicateCols := make(map[int64]struct{}, len(predicateColIDs))
+		for _, colID := range predicateColIDs {
+			predicateCols[colID] = struct{}{}
 		}
-		columnIDs[colInfo.ID] = struct{}{}
+		// The column may have been dropped after it was recorded as a predicate column, so we only keep the existing ones.
+		for _, col := range tblInfo.Columns {
+			if _, ok := predicateCols[col.ID]; ok {
+				columnIDs[col.ID] = struct{}{}
+			}
+		}
+		if len(columnIDs) == 0 {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("No predicate column has been collected yet for table %s.%s so only indexes and the columns composing the indexes will be analyzed.", tbl.Schema.O, tblInfo.Name.O))
+		}
+	case len(as.ColumnNames) > 0:
+		for _, colName := range as.ColumnNames {
+			colInfo := model.FindColumnInfo(tblInfo.Columns, colName.L)
+			if colInfo == nil {
+				return nil, ErrAnalyzeMissColumn.GenWithStackByArgs(colName.O, tblInfo.Name.O)
+			}
+			columnIDs[colInfo.ID] = struct{}{}
+		}
+	default:
+		return tblInfo.Columns, nil
 	}
 	missingCols := make(map[int64]struct{}, len(tblInfo.Columns)-len(columnIDs))
 	if len(tblInfo.Indices) > 0 {
@@ -1892,7 +1969,8 @@ func (b *PlanBuilder) getAnalyzeColumnsInfo(as *ast.AnalyzeTableStmt, tbl *ast.T
 			}
 		}
 	}
-	if len(missingCols) > 0 {
+	// The predicate columns are not specified by the user, so we don't warn that the columns in index/primary key/extended stats are missing.
+	if len(missingCols) > 0 && as.ColumnChoice != model.PredicateColumns {
 		missingNames := make([]string, 0, len(missingCols))
 		for _, col := range tblInfo.Columns {
 			if _, ok := missingCols[col.ID]; ok {
@@ -1928,7 +2006,7 @@ func getColOffsetForAnalyze(colsInfo []*model.ColumnInfo, colID int64) int {
 func getModifiedIndexesInfoForAnalyze(tblInfo *model.TableInfo, allColumns bool, colsInfo []*model.ColumnInfo) []*model.IndexInfo {
 	idxsInfo := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
 	for _, originIdx := range tblInfo.Indices {
-		if originIdx.State != model.StatePublic {
+		if originIdx.State != model.StatePublic || originIdx.MVIndex {
 			continue
 		}
 		if allColumns {
@@ -2031,6 +2109,9 @@ func (b *PlanBuilder) buildAnalyzeTable(as *ast.AnalyzeTableStmt, opts map[ast.A
 		if len(as.ColumnNames) > 0 {
 			return nil, errors.Errorf("Only the analyze version 2 supports analyzing the specified columns")
 		}
+		if as.ColumnChoice == model.PredicateColumns {
+			return nil, errors.Errorf("Only the analyze version 2 supports analyzing predicate columns")
+		}
 		for _, idx := range idxInfo {
 			// For prefix common handle. We don't use analyze mixed to handle it with columns. Because the full value
 			// is read by coprocessor, the prefix index would get wrong stats in this case.
@@ -2128,7 +2209,7 @@ func (b *PlanBuilder) buildAnalyzeIndex(as *ast.AnalyzeTableStmt, opts map[ast.A
 			}
 		}
 		idx := tblInfo.FindIndexByName(idxName.L)
-		if idx == nil || idx.State != model.StatePublic {
+		if idx == nil || idx.State != model.StatePublic || idx.MVIndex {
 			return nil, ErrAnalyzeMissIndex.GenWithStackByArgs(idxName.O, tblInfo.Name.O)
 		}
 		for i, id := range physicalIDs {
@@ -2172,7 +2253,7 @@ func (b *PlanBuilder) buildAnalyzeAllIndex(as *ast.AnalyzeTableStmt, opts map[as
 		return b.buildAnalyzeTable(as, opts, version)
 	}
 	for _, idx := range tblInfo.Indices {
-		if idx.State == model.StatePublic {
+		if idx.State == model.StatePublic && !idx.MVIndex {
 			for i, id := range physicalIDs {
 				if id == tblInfo.ID {
 					id = -1
@@ -2295,6 +2376,21 @@ func handleAnalyzeOptions(opts []ast.AnalyzeOpt, statsVer int) (map[ast.AnalyzeO
 	return optMap, nil
 }
 
+// appendAnalyzeTablesVisitInfo appends the privileges needed to analyze the tables, which are INSERT and SELECT.
+func appendAnalyzeTablesVisitInfo(sctx sessionctx.Context, vi []visitInfo, tables []*ast.TableName) []visitInfo {
+	user := sctx.GetSessionVars().User
+	for _, tbl := range tables {
+		var insertErr, selectErr error
+		if user != nil {
+			insertErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, tbl.Name.O)
+			selectErr = ErrTableaccessDenied.GenWithStackByArgs("SELECT", user.AuthUsername, user.AuthHostname, tbl.Name.O)
+		}
+		vi = appendVisitInfo(vi, mysql.InsertPriv, tbl.Schema.O, tbl.Name.O, "", insertErr)
+		vi = appendVisitInfo(vi, mysql.SelectPriv, tbl.Schema.O, tbl.Name.O, "", selectErr)
+	}
+	return vi
+}
+
 func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (Plan, error) {
 	// If enable fast analyze, the storage must be tikv.Storage.
 	if _, isTikvStorage := b.ctx.GetStore().(tikv.Storage); !isTikvStorage && b.ctx.GetSessionVars().EnableFastAnalyze {
@@ -2304,16 +2400,7 @@ func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (Plan, error) {
 	if b.ctx.GetSessionVars().EnableFastAnalyze && statsVersion >= statistics.Version2 {
 		return nil, errors.Errorf("Fast analyze hasn't reached General Availability and only support analyze version 1 currently.")
 	}
-	for _, tbl := range as.TableNames {
-		user := b.ctx.GetSessionVars().User
-		var insertErr, selectErr error
-		if user != nil {
-			insertErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, tbl.Name.O)
-			selectErr = ErrTableaccessDenied.GenWithStackByArgs("SELECT", user.AuthUsername, user.AuthHostname, tbl.Name.O)
-		}
-		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tbl.Schema.O, tbl.Name.O, "", insertErr)
-		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, tbl.Schema.O, tbl.Name.O, "", selectErr)
-	}
+	b.visitInfo = appendAnalyzeTablesVisitInfo(b.ctx, b.visitInfo, as.TableNames)
 	opts, err := handleAnalyzeOptions(as.AnalyzeOpts, statsVersion)
 	if err != nil {
 		return nil, err
@@ -2589,7 +2676,7 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 		p.setSchemaAndNames(buildShowNextRowID())
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, show.Table.Schema.L, show.Table.Name.L, "", ErrPrivilegeCheckFail)
 		return p, nil
-	case ast.ShowStatsBuckets, ast.ShowStatsHistograms, ast.ShowStatsMeta, ast.ShowStatsExtended, ast.ShowStatsHealthy, ast.ShowStatsTopN, ast.ShowHistogramsInFlight, ast.ShowColumnStatsUsage:
+	case ast.ShowStatsBuckets, ast.ShowStatsHistograms, ast.ShowStatsMeta, ast.ShowStatsExtended, ast.ShowStatsHealthy, ast.ShowStatsTopN, ast.ShowHistogramsInFlight, ast.ShowColumnStatsUsage, ast.ShowStatsDump:
 		user := b.ctx.GetSessionVars().User
 		var err error
 		if user != nil {
@@ -2665,11 +2752,36 @@ func (b *PlanBuilder) buildSimple(ctx context.Context, node ast.StmtNode) (Plan,
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("RELOAD")
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.ReloadPriv, "", "", "", err)
 	case *ast.AlterInstanceStmt:
-		err := ErrSpecificAccessDenied.GenWithStack("SUPER")
-		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", err)
+		if raw.RotateMasterKey {
+			// Like MySQL, rotating the master key requires ENCRYPTION_KEY_ADMIN.
+			err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or ENCRYPTION_KEY_ADMIN")
+			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "ENCRYPTION_KEY_ADMIN", false, err)
+		} else {
+			// Like MySQL, reloading the TLS context requires CONNECTION_ADMIN.
+			err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or CONNECTION_ADMIN")
+			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "CONNECTION_ADMIN", false, err)
+		}
 	case *ast.RenameUserStmt:
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("CREATE USER")
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreateUserPriv, "", "", "", err)
+	case *ast.CreateFunctionStmt:
+		// Like MySQL, creating a function requires the privilege to insert into the mysql schema.
+		var err error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			err = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, "user_functions")
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, mysql.SystemDB, "user_functions", "", err)
+	case *ast.DropFunctionStmt:
+		var err error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			err = ErrTableaccessDenied.GenWithStackByArgs("DELETE", user.AuthUsername, user.AuthHostname, "user_functions")
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DeletePriv, mysql.SystemDB, "user_functions", "", err)
+	case *ast.LockStatsStmt:
+		// Locking or unlocking stats requires the same privileges as analyzing the tables.
+		b.visitInfo = appendAnalyzeTablesVisitInfo(b.ctx, b.visitInfo, raw.Tables)
+	case *ast.UnlockStatsStmt:
+		b.visitInfo = appendAnalyzeTablesVisitInfo(b.ctx, b.visitInfo, raw.Tables)
 	case *ast.GrantStmt:
 		var err error
 		b.visitInfo, err = collectVisitInfoFromGrantStmt(b.ctx, b.visitInfo, raw)
@@ -2688,6 +2800,9 @@ func (b *PlanBuilder) buildSimple(ctx context.Context, node ast.StmtNode) (Plan,
 	case *ast.GrantRoleStmt:
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or ROLE_ADMIN")
 		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "ROLE_ADMIN", false, err)
+	case *ast.SetResourceGroupStmt:
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or RESOURCE_GROUP_USER")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "RESOURCE_GROUP_USER", false, err)
 	case *ast.RevokeRoleStmt:
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or ROLE_ADMIN")
 		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "ROLE_ADMIN", false, err)
@@ -2782,6 +2897,19 @@ func calculateTsWithReadStaleness(sctx sessionctx.Context, readStaleness time.Du
 	return oracle.GoTimeToTS(expression.CalAppropriateTime(tsVal, nowVal, minTsVal)), nil
 }
 
+// getExternalTimestamp returns the global tidb_external_ts, an error is returned if it's not set.
+func getExternalTimestamp(sctx sessionctx.Context) (uint64, error) {
+	val, err := sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBExternalTS)
+	if err != nil {
+		return 0, err
+	}
+	ts, err := strconv.ParseUint(val, 10, 64)
+	if err != nil || ts == 0 {
+		return 0, ErrAsOf.FastGenWithCause("tidb_external_ts is not set while tidb_enable_external_ts_read is on")
+	}
+	return ts, nil
+}
+
 func collectVisitInfoFromRevokeStmt(sctx sessionctx.Context, vi []visitInfo, stmt *ast.RevokeStmt) ([]visitInfo, error) {
 	// To use REVOKE, you must have the GRANT OPTION privilege,
 	// and you must have the privileges that you are granting.
@@ -2990,6 +3118,12 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		}
 		return nil, err
 	}
+	if tableInfo.ExternalTable != nil {
+		if insert.IsReplace {
+			return nil, ErrNonUpdatableTable.GenWithStackByArgs(tableInfo.Name.O, "REPLACE")
+		}
+		return nil, ErrNonUpdatableTable.GenWithStackByArgs(tableInfo.Name.O, "INSERT")
+	}
 	// Build Schema with DBName otherwise ColumnRef with DBName cannot match any Column in Schema.
 	schema, names, err := expression.TableInfo2SchemaAndNames(b.ctx, tn.Schema, tableInfo)
 	if err != nil {
@@ -3031,7 +3165,7 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	}
 
 	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tn.DBInfo.Name.L,
-		tableInfo.Name.L, "", authErr)
+		tableInfo.Name.L, privilege.AnyColumn, authErr)
 
 	// `REPLACE INTO` requires both INSERT + DELETE privilege
 	// `ON DUPLICATE KEY UPDATE` requires both INSERT + UPDATE privilege
@@ -3046,7 +3180,11 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 			cmd := strings.ToUpper(mysql.Priv2Str[extraPriv])
 			authErr = ErrTableaccessDenied.GenWithStackByArgs(cmd, user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
 		}
-		b.visitInfo = appendVisitInfo(b.visitInfo, extraPriv, tn.DBInfo.Name.L, tableInfo.Name.L, "", authErr)
+		col := ""
+		if extraPriv == mysql.UpdatePriv {
+			col = privilege.AnyColumn
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, extraPriv, tn.DBInfo.Name.L, tableInfo.Name.L, col, authErr)
 	}
 
 	mockTablePlan := LogicalTableDual{}.Init(b.ctx, b.getSelectOffset())
@@ -3084,6 +3222,8 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		}
 	}
 
+	b.appendInsertColumnVisitInfo(tn.DBInfo.Name, insert, insertPlan)
+
 	mockTablePlan.SetSchema(insertPlan.Schema4OnDuplicate)
 	mockTablePlan.names = insertPlan.names4OnDuplicate
 
@@ -3106,6 +3246,43 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	return insertPlan, err
 }
 
+// appendInsertColumnVisitInfo appends the column-level privilege checks of the columns inserted or updated on duplicate.
+func (b *PlanBuilder) appendInsertColumnVisitInfo(dbName model.CIStr, insert *ast.InsertStmt, insertPlan *Insert) {
+	tblName := insertPlan.Table.Meta().Name
+	var insertCols []model.CIStr
+	switch {
+	case len(insert.Setlist) > 0:
+		for _, assign := range insert.Setlist {
+			insertCols = append(insertCols, assign.Column.Name)
+		}
+	case len(insert.Columns) > 0:
+		for _, col := range insert.Columns {
+			insertCols = append(insertCols, col.Name)
+		}
+	default:
+		for _, col := range insertPlan.Table.VisibleCols() {
+			if !col.IsGenerated() {
+				insertCols = append(insertCols, col.Name)
+			}
+		}
+	}
+	user := b.ctx.GetSessionVars().User
+	appendColumn := func(priv mysql.PrivilegeType, col model.CIStr) {
+		var authErr error
+		if user != nil {
+			cmd := strings.ToUpper(mysql.Priv2Str[priv])
+			authErr = ErrColumnaccessDenied.GenWithStackByArgs(cmd, user.AuthUsername, user.AuthHostname, col.L, tblName.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, priv, dbName.L, tblName.L, col.L, authErr)
+	}
+	for _, col := range insertCols {
+		appendColumn(mysql.InsertPriv, col)
+	}
+	for _, assign := range insert.OnDuplicate {
+		appendColumn(mysql.UpdatePriv, assign.Column.Name)
+	}
+}
+
 func (p *Insert) resolveOnDuplicate(onDup []*ast.Assignment, tblInfo *model.TableInfo, yield func(ast.ExprNode) (expression.Expression, error)) (map[string]struct{}, error) {
 	onDupColSet := make(map[string]struct{}, len(onDup))
 	colMap := make(map[string]*table.Column, len(p.Table.Cols()))
@@ -3505,6 +3682,9 @@ func (b *PlanBuilder) buildLoadData(ctx context.Context, ld *ast.LoadDataStmt) (
 		db := b.ctx.GetSessionVars().CurrentDB
 		return nil, infoschema.ErrTableNotExists.GenWithStackByArgs(db, tableInfo.Name.O)
 	}
+	if tableInfo.ExternalTable != nil {
+		return nil, ErrNonUpdatableTable.GenWithStackByArgs(tableInfo.Name.O, "LOAD DATA")
+	}
 	schema, names, err := expression.TableInfo2SchemaAndNames(b.ctx, model.NewCIStr(""), tableInfo)
 	if err != nil {
 		return nil, err
@@ -3521,7 +3701,7 @@ func (b *PlanBuilder) buildLoadData(ctx context.Context, ld *ast.LoadDataStmt) (
 }
 
 func (b *PlanBuilder) buildLoadStats(ld *ast.LoadStatsStmt) Plan {
-	p := &LoadStats{Path: ld.Path}
+	p := &LoadStats{Path: ld.Path, JSON: ld.JSON}
 	return p
 }
 
@@ -3898,6 +4078,10 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, v.ReferTable.Schema.L,
 				v.ReferTable.Name.L, "", authErr)
 		}
+		if v.External != nil {
+			// The external table reads files as the TiDB server, just like LOAD DATA INFILE.
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.FilePriv, "", "", "", ErrSpecificAccessDenied.GenWithStackByArgs("FILE"))
+		}
 	case *ast.CreateViewStmt:
 		b.isCreateView = true
 		b.capFlag |= canExpandAST | renameView
@@ -4016,20 +4200,21 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 		}
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, v.TableToTables[0].NewTable.Schema.L,
 			v.TableToTables[0].NewTable.Name.L, "", authErr)
-	case *ast.RecoverTableStmt, *ast.FlashBackTableStmt:
+	case *ast.RecoverTableStmt, *ast.FlashBackTableStmt, *ast.FlashBackDatabaseStmt:
 		// Recover table command can only be executed by administrator.
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
 	case *ast.LockTablesStmt, *ast.UnlockTablesStmt:
 		// TODO: add Lock Table privilege check.
-	case *ast.CleanupTableLockStmt:
-		// This command can only be executed by administrator.
-		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
-	case *ast.RepairTableStmt:
-		// Repair table command can only be executed by administrator.
-		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
+	case *ast.CleanupTableLockStmt, *ast.RepairTableStmt:
+		// ADMIN CLEANUP TABLE LOCK and ADMIN REPAIR TABLE can only be executed by administrator.
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or DDL_ADMIN")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "DDL_ADMIN", false, err)
 	case *ast.DropPlacementPolicyStmt, *ast.CreatePlacementPolicyStmt, *ast.AlterPlacementPolicyStmt:
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or PLACEMENT_ADMIN")
 		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "PLACEMENT_ADMIN", false, err)
+	case *ast.CreateResourceGroupStmt, *ast.AlterResourceGroupStmt, *ast.DropResourceGroupStmt:
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or RESOURCE_GROUP_ADMIN")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "RESOURCE_GROUP_ADMIN", false, err)
 	}
 	p := &DDL{Statement: node}
 	return p, nil
@@ -4042,6 +4227,8 @@ const (
 	TraceFormatJSON = "json"
 	// TraceFormatLog indicates log tracing format.
 	TraceFormatLog = "log"
+	// TraceFormatOTel indicates the OTLP/JSON tracing format of OpenTelemetry.
+	TraceFormatOTel = "otel"
 
 	// TracePlanTargetEstimation indicates CE trace target for optimizer trace.
 	TracePlanTargetEstimation = "estimation"
@@ -4083,7 +4270,7 @@ func (b *PlanBuilder) buildTrace(trace *ast.TraceStmt) (Plan, error) {
 		schema.Append(buildColumnWithName("", "duration", mysql.TypeString, mysql.MaxBlobWidth))
 		p.SetSchema(schema.col2Schema())
 		p.names = schema.names
-	case TraceFormatJSON:
+	case TraceFormatJSON, TraceFormatOTel:
 		schema := newColumnsWithNames(1)
 		schema.Append(buildColumnWithName("", "operation", mysql.TypeString, mysql.MaxBlobWidth))
 		p.SetSchema(schema.col2Schema())
@@ -4097,7 +4284,7 @@ func (b *PlanBuilder) buildTrace(trace *ast.TraceStmt) (Plan, error) {
 		p.SetSchema(schema.col2Schema())
 		p.names = schema.names
 	default:
-		return nil, errors.New("trace format should be one of 'row', 'log' or 'json'")
+		return nil, errors.New("trace format should be one of 'row', 'log', 'json' or 'otel'")
 	}
 	return p, nil
 }
@@ -4247,6 +4434,12 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 		return buildShowEventsSchema()
 	case ast.ShowWarnings, ast.ShowErrors:
 		return buildShowWarningsSchema()
+	case ast.ShowLastDMLErrors:
+		names = []string{"Code", "Message", "Row"}
+		ftypes = []byte{mysql.TypeLong, mysql.TypeVarchar, mysql.TypeVarchar}
+	case ast.ShowSessionStates:
+		names = []string{"Session_states", "Session_token"}
+		ftypes = []byte{mysql.TypeJSON, mysql.TypeJSON}
 	case ast.ShowRegions:
 		return buildTableRegionsSchema()
 	case ast.ShowEngines:
@@ -4347,11 +4540,15 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeTiny, mysql.TypeLonglong,
 			mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong}
 	case ast.ShowStatsTopN:
-		names = []string{"Db_name", "Table_name", "Partition_name", "Column_name", "Is_index", "Value", "Count"}
-		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeTiny, mysql.TypeVarchar, mysql.TypeLonglong}
+		names = []string{"Db_name", "Table_name", "Partition_name", "Column_name", "Is_index", "Value", "Count", "Topn_coverage", "Skew_ratio"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeTiny, mysql.TypeVarchar,
+			mysql.TypeLonglong, mysql.TypeDouble, mysql.TypeDouble}
 	case ast.ShowStatsHealthy:
 		names = []string{"Db_name", "Table_name", "Partition_name", "Healthy"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong}
+	case ast.ShowStatsDump:
+		names = []string{"Db_name", "Table_name", "Stats_dump"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar}
 	case ast.ShowHistogramsInFlight:
 		names = []string{"HistogramsInFlight"}
 		ftypes = []byte{mysql.TypeLonglong}
@@ -4371,8 +4568,10 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 		names = []string{"Original_sql", "Bind_sql", "Default_db", "Status", "Create_time", "Update_time", "Charset", "Collation", "Source"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDatetime, mysql.TypeDatetime, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar}
 	case ast.ShowAnalyzeStatus:
-		names = []string{"Table_schema", "Table_name", "Partition_name", "Job_info", "Processed_rows", "Start_time", "End_time", "State"}
-		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeDatetime, mysql.TypeDatetime, mysql.TypeVarchar}
+		names = []string{"Table_schema", "Table_name", "Partition_name", "Job_info", "Processed_rows", "Start_time", "End_time", "State",
+			"Phase", "Estimated_total_rows", "Remaining_seconds", "Fail_reason", "Instance", "Process_ID", "Job_ID"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeDatetime, mysql.TypeDatetime, mysql.TypeVarchar,
+			mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeLongBlob, mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeLonglong}
 	case ast.ShowBuiltins:
 		names = []string{"Supported_builtin_functions"}
 		ftypes = []byte{mysql.TypeVarchar}
@@ -474,6 +474,10 @@ func TryFastPlan(ctx sessionctx.Context, node ast.Node) (p Plan) {
 	ctx.GetSessionVars().PlanColumnID = 0
 	switch x := node.(type) {
 	case *ast.SelectStmt:
+		// The locked rows are skipped by the SelectLock executor, which is absent in the fast plan.
+		if x.LockInfo != nil && x.LockInfo.LockType == ast.SelectLockForUpdateSkipLocked {
+			return nil
+		}
 		defer func() {
 			if ctx.GetSessionVars().SelectLimit != math2.MaxUint64 && p != nil {
 				ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New("sql_select_limit is set, so point get plan is not activated"))
@@ -524,7 +528,8 @@ func IsSelectForUpdateLockType(lockType ast.SelectLockType) bool {
 	if lockType == ast.SelectLockForUpdate ||
 		lockType == ast.SelectLockForShare ||
 		lockType == ast.SelectLockForUpdateNoWait ||
-		lockType == ast.SelectLockForUpdateWaitN {
+		lockType == ast.SelectLockForUpdateWaitN ||
+		lockType == ast.SelectLockForUpdateSkipLocked {
 		return true
 	}
 	return false
@@ -654,9 +659,14 @@ func newBatchPointGetPlan(
 		return nil
 	}
 
-	pos, err := getPartitionColumnPos(matchIdxInfo, partitionExpr, tbl)
-	if err != nil {
-		return nil
+	var pos int
+	// The partition of a global index entry is decided by the index value instead of the partition column.
+	if !matchIdxInfo.Global {
+		var err error
+		pos, err = getPartitionColumnPos(matchIdxInfo, partitionExpr, tbl)
+		if err != nil {
+			return nil
+		}
 	}
 
 	indexValues := make([][]types.Datum, len(patternInExpr.List))
@@ -877,6 +887,9 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 
 	var partitionInfo *model.PartitionDefinition
 	var pos int
+	// onlyGlobalIndex indicates the partition can't be located by the conditions, so only the global indexes, whose
+	// values carry the partition ID, can be used.
+	var onlyGlobalIndex bool
 	if pi != nil {
 		partitionInfo, pos, isTableDual = getPartitionInfo(ctx, tbl, pairs)
 		if isTableDual {
@@ -885,10 +898,12 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 			return p
 		}
 		if partitionInfo == nil {
-			return nil
-		}
-		// Take partition selection into consideration.
-		if len(tblName.PartitionNames) > 0 {
+			if !tbl.HasGlobalIndex() || len(tblName.PartitionNames) > 0 {
+				return nil
+			}
+			onlyGlobalIndex = true
+		} else if len(tblName.PartitionNames) > 0 {
+			// Take partition selection into consideration.
 			if !partitionNameInSet(partitionInfo.Name, tblName.PartitionNames) {
 				p := newPointGetPlan(ctx, tblName.Schema.O, schema, tbl, names)
 				p.IsTableDual = true
@@ -899,6 +914,9 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 
 	handlePair, fieldType := findPKHandle(tbl, pairs)
 	if handlePair.value.Kind() != types.KindNull && len(pairs) == 1 && indexIsAvailableByHints(nil, tblName.IndexHints) {
+		if onlyGlobalIndex {
+			return nil
+		}
 		if isTableDual {
 			p := newPointGetPlan(ctx, tblName.Schema.O, schema, tbl, names)
 			p.IsTableDual = true
@@ -921,7 +939,7 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 
 	for _, idxInfo := range tbl.Indices {
 		if !idxInfo.Unique || idxInfo.State != model.StatePublic || idxInfo.Invisible ||
-			!indexIsAvailableByHints(idxInfo, tblName.IndexHints) {
+			!indexIsAvailableByHints(idxInfo, tblName.IndexHints) || (onlyGlobalIndex && !idxInfo.Global) {
 			continue
 		}
 		if isTableDual {
@@ -962,7 +980,7 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 		p.IndexValues = idxValues
 		p.IndexValueParams = idxValueParams
 		p.PartitionInfo = partitionInfo
-		if p.PartitionInfo != nil {
+		if p.PartitionInfo != nil && !idxInfo.Global {
 			p.partitionColumnPos = findPartitionIdx(idxInfo, pos, pairs)
 		}
 		return p
@@ -2306,3 +2306,69 @@ func (s *testPlanSerialSuite) TestPartitionWithVariedDatasources(c *C) {
 		}
 	}
 }
+
+func (s *testPlanSerialSuite) TestPlanCacheFoldParamForIntCompare(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	tk.Se, err = session.CreateSession4TestWithOpt(store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	c.Assert(err, IsNil)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int, key(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5)")
+
+	tests := []struct {
+		sql     string
+		setVars string
+		plan    string
+		result  []string
+	}{
+		{"select * from t where a = ? + 1", "set @a = 1.0, @b = 2.5", "PointGet(Handle(t.a)2)", []string{"2 2"}},
+		{"select * from t where a = ? + 1", "set @a = '3', @b = '2'", "PointGet(Handle(t.a)4)", []string{"4 4"}},
+		{"select * from t where b = ? + 1", "set @a = 1.0, @b = 2.5", "IndexReader(Index(t.b)[[2,2]])", []string{"2 2"}},
+		{"select * from t where b > ? * 2 and b < ? + 1", "set @a = 1.0, @b = 2.5", "IndexReader(Index(t.b)[[3,3]])", []string{"3 3"}},
+		{"select * from t where b = ?", "set @a = 2.0, @b = 2.5", "IndexReader(Index(t.b)[[2,2]])", []string{"2 2"}},
+		{"select * from t where b = abs(?)", "set @a = -3.0, @b = 2.5", "IndexReader(Index(t.b)[[3,3]])", []string{"3 3"}},
+	}
+	ctx := context.TODO()
+	for _, tt := range tests {
+		tk.MustExec(fmt.Sprintf("prepare stmt from '%s'", tt.sql))
+		tk.MustExec(tt.setVars)
+		using := "using @a"
+		if strings.Count(tt.sql, "?") == 2 {
+			using = "using @a, @b"
+		}
+		for i := 0; i < 2; i++ {
+			tk.MustQuery("execute stmt " + using).Check(testkit.Rows(tt.result...))
+			// The plan can't be cached since the refined constant depends on the value of the parameters.
+			tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+		}
+
+		stmt, err := s.ParseOneStmt("execute stmt "+using, "", "")
+		c.Assert(err, IsNil)
+		is := tk.Se.GetInfoSchema().(infoschema.InfoSchema)
+		builder, _ := core.NewPlanBuilder().Init(tk.Se, is, &hint.BlockHintProcessor{})
+		p, err := builder.Build(ctx, stmt)
+		c.Assert(err, IsNil)
+		execPlan, ok := p.(*core.Execute)
+		c.Assert(ok, IsTrue)
+		err = executor.ResetContextOfStmt(tk.Se, stmt)
+		c.Assert(err, IsNil)
+		err = execPlan.OptimizePreparedPlan(ctx, tk.Se, is)
+		c.Assert(err, IsNil)
+		c.Assert(core.ToString(execPlan.Plan), Equals, tt.plan, Commentf("for %s", tt.sql))
+	}
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/pingcap/tidb/sessiontxn"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/temptable"
+	"github.com/pingcap/tidb/util/gcutil"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util"
@@ -245,6 +246,9 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		p.stmtTp = TypeShow
 		p.showTp = node.Tp
 		p.resolveShowStmt(node)
+		if node.Tp == ast.ShowCreateTable && node.Table.AsOf != nil {
+			p.handleShowAsOf(node.Table.AsOf)
+		}
 	case *ast.SetOprSelectList:
 		p.checkSetOprSelectList(node)
 	case *ast.DeleteTableList:
@@ -271,6 +275,10 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		// So skip check table name here, otherwise, recover table [table_name] syntax will return
 		// table not exists error. But recover table statement is use to recover the dropped table. So skip children here.
 		return in, true
+	case *ast.FlashBackDatabaseStmt:
+		if len(node.NewName) > 0 && isIncorrectName(node.NewName) {
+			p.err = ddl.ErrWrongDBName.GenWithStackByArgs(node.NewName)
+		}
 	case *ast.RepairTableStmt:
 		p.stmtTp = TypeRepair
 		// The RepairTable should consist of the logic for creating tables and renaming tables.
@@ -1306,6 +1314,10 @@ func checkColumn(colDef *ast.ColumnDef) error {
 		if tp.Flen > mysql.MaxBitDisplayWidth {
 			return types.ErrTooBigDisplayWidth.GenWithStackByArgs(colDef.Name.Name.O, mysql.MaxBitDisplayWidth)
 		}
+	case mysql.TypeTiDBVectorFloat32:
+		if tp.Flen != types.UnspecifiedLength && (tp.Flen <= 0 || tp.Flen > types.MaxVectorDimension) {
+			return types.ErrVectorDimensionOutOfRange.GenWithStackByArgs(tp.Flen, types.MaxVectorDimension)
+		}
 	default:
 		// TODO: Add more types.
 	}
@@ -1505,6 +1517,30 @@ func (p *preprocessor) resolveShowStmt(node *ast.ShowStmt) {
 	}
 }
 
+// handleShowAsOf handles `SHOW CREATE TABLE ... AS OF TIMESTAMP`, the table is resolved from the schema at the
+// timestamp, so the dropped or altered tables can be shown as they were.
+func (p *preprocessor) handleShowAsOf(node *ast.AsOfClause) {
+	ts, err := calculateTsExpr(p.ctx, node)
+	if err != nil {
+		p.err = err
+		return
+	}
+	if err = sessionctx.ValidateStaleReadTS(context.Background(), p.ctx, ts); err != nil {
+		p.err = errors.Trace(err)
+		return
+	}
+	if err = gcutil.ValidateSnapshot(p.ctx, ts); err != nil {
+		p.err = err
+		return
+	}
+	is, err := domain.GetDomain(p.ctx).GetSnapshotInfoSchema(ts)
+	if err != nil {
+		p.err = err
+		return
+	}
+	p.InfoSchema = temptable.AttachLocalTemporaryTableInfoSchema(p.ctx, is)
+}
+
 func (p *preprocessor) resolveExecuteStmt(node *ast.ExecuteStmt) {
 	prepared, err := GetPreparedStmt(node, p.ctx.GetSessionVars())
 	if err != nil {
@@ -1668,6 +1704,24 @@ func (p *preprocessor) handleAsOfAndReadTS(node *ast.AsOfClause) {
 			p.LastSnapshotTS = ts
 			p.IsStaleness = true
 		}
+	case readTS == 0 && node == nil && p.ctx.GetSessionVars().EnableExternalTSRead && !p.ctx.GetSessionVars().InRestrictedSQL:
+		// If tidb_enable_external_ts_read is on, the statement reads the data at the timestamp of tidb_external_ts,
+		// which is usually set by the replication tool to the timestamp the replicated data is consistent at.
+		ts, p.err = getExternalTimestamp(p.ctx)
+		if p.err != nil {
+			return
+		}
+		if err := sessionctx.ValidateStaleReadTS(context.Background(), p.ctx, ts); err != nil {
+			p.err = errors.Trace(err)
+			return
+		}
+		if !p.initedLastSnapshotTS {
+			p.SnapshotTSEvaluator = func(ctx sessionctx.Context) (uint64, error) {
+				return getExternalTimestamp(ctx)
+			}
+			p.LastSnapshotTS = ts
+			p.IsStaleness = true
+		}
 	case readTS == 0 && node == nil && readStaleness != 0:
 		// If both readTS and node is empty while the readStaleness isn't, it means we meet following situation:
 		// set @@tidb_read_staleness='-5';
@@ -266,6 +266,23 @@ func (p *PhysicalTableReader) ResolveIndices() error {
 	return p.tablePlan.ResolveIndices()
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalMemTable) ResolveIndices() (err error) {
+	err = p.physicalSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	if extractor, ok := p.Extractor.(*ExternalTableExtractor); ok {
+		for i, expr := range extractor.Conditions {
+			extractor.Conditions[i], err = expr.ResolveIndices(p.schema)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalIndexReader) ResolveIndices() (err error) {
 	err = p.physicalSchemaProducer.ResolveIndices()
@@ -425,6 +442,23 @@ func (p *PhysicalSort) ResolveIndices() (err error) {
 	return err
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalExpand) ResolveIndices() (err error) {
+	err = p.physicalSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	for _, exprs := range p.LevelExprs {
+		for i, expr := range exprs {
+			exprs[i], err = expr.ResolveIndices(p.children[0].Schema())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalWindow) ResolveIndices() (err error) {
 	err = p.physicalSchemaProducer.ResolveIndices()
@@ -130,6 +130,16 @@ func (p *LogicalTopN) BuildKeyInfo(selfSchema *expression.Schema, childSchema []
 	}
 }
 
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (p *LogicalExpand) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	// An input row is expanded to a row for each grouping set, so the keys of the child are only unique
+	// together with the grouping id.
+	p.logicalSchemaProducer.BuildKeyInfo(selfSchema, childSchema)
+	for i, key := range selfSchema.Keys {
+		selfSchema.Keys[i] = append(key[:len(key):len(key)], p.GroupingID)
+	}
+}
+
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *LogicalTableDual) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
@@ -329,7 +329,15 @@ func (p *LogicalMemTable) PruneColumns(parentUsedCols []*expression.Column) erro
 		infoschema.TableDeadlocks,
 		infoschema.ClusterTableDeadlocks:
 	default:
-		return nil
+		if p.TableInfo.ExternalTable == nil {
+			return nil
+		}
+	}
+	if extractor, ok := p.Extractor.(*ExternalTableExtractor); ok {
+		// The pushed down conditions are evaluated on the pruned rows, keep the columns they use.
+		usedCols := make([]*expression.Column, 0, len(parentUsedCols))
+		usedCols = append(usedCols, parentUsedCols...)
+		parentUsedCols = expression.ExtractColumnsFromExpressions(usedCols, extractor.Conditions, nil)
 	}
 	used := expression.GetUsedList(parentUsedCols, p.schema)
 	for i := len(used) - 1; i >= 0; i-- {
@@ -498,6 +506,25 @@ func (p *LogicalWindow) extractUsedCols(parentUsedCols []*expression.Column) []*
 	return parentUsedCols
 }
 
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalExpand) PruneColumns(parentUsedCols []*expression.Column) error {
+	used := expression.GetUsedList(parentUsedCols, p.schema)
+	for i := len(used) - 1; i >= 0; i-- {
+		// The grouping id is always kept, it distinguishes the rows expanded from the same input row.
+		if !used[i] && !p.schema.Columns[i].Equal(nil, p.GroupingID) {
+			p.schema.Columns = append(p.schema.Columns[:i], p.schema.Columns[i+1:]...)
+			for j, exprs := range p.LevelExprs {
+				p.LevelExprs[j] = append(exprs[:i], exprs[i+1:]...)
+			}
+		}
+	}
+	selfUsedCols := make([]*expression.Column, 0, p.schema.Len())
+	for _, exprs := range p.LevelExprs {
+		selfUsedCols = expression.ExtractColumnsFromExpressions(selfUsedCols, exprs, nil)
+	}
+	return p.children[0].PruneColumns(selfUsedCols)
+}
+
 // PruneColumns implements LogicalPlan interface.
 func (p *LogicalLimit) PruneColumns(parentUsedCols []*expression.Column) error {
 	if len(parentUsedCols) == 0 { // happens when LIMIT appears in UPDATE.
@@ -293,6 +293,14 @@ func (p *LogicalWindow) replaceExprColumns(replace map[string]*expression.Column
 	}
 }
 
+func (p *LogicalExpand) replaceExprColumns(replace map[string]*expression.Column) {
+	for _, exprs := range p.LevelExprs {
+		for _, expr := range exprs {
+			ResolveExprAndReplace(expr, replace)
+		}
+	}
+}
+
 func (*projectionEliminator) name() string {
 	return "projection_eliminate"
 }
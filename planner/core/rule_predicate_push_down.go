@@ -640,6 +640,13 @@ func (p *LogicalWindow) PredicatePushDown(predicates []expression.Expression, op
 	return canNotBePushed, p
 }
 
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalExpand) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
+	// The predicates can't be pushed beneath the Expand, because the GROUP BY items may be NULL in its output.
+	p.baseLogicalPlan.PredicatePushDown(nil, opt)
+	return predicates, p
+}
+
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
 func (p *LogicalMemTable) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
 	if p.Extractor != nil {
@@ -21,6 +21,7 @@ import (
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/planner/util"
 )
 
@@ -142,6 +143,10 @@ func (p *LogicalProjection) pushDownTopN(topN *LogicalTopN, opt *logicalOptimize
 }
 
 func (p *LogicalLock) pushDownTopN(topN *LogicalTopN, opt *logicalOptimizeOp) LogicalPlan {
+	// The skipped locked rows must not be counted by the TopN, so it can't be pushed down.
+	if p.Lock.LockType == ast.SelectLockForUpdateSkipLocked {
+		return p.baseLogicalPlan.pushDownTopN(topN, opt)
+	}
 	if topN != nil {
 		p.children[0] = p.children[0].pushDownTopN(topN, opt)
 	}
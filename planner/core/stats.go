@@ -31,6 +31,9 @@ import (
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"go.uber.org/zap"
@@ -85,6 +88,16 @@ func (p *LogicalShow) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 	return p.stats, nil
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalJSONTable) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
+	if p.stats != nil {
+		return p.stats, nil
+	}
+	// The number of rows is unknown before evaluating the JSON document.
+	p.stats = getFakeStats(selfSchema)
+	return p.stats, nil
+}
+
 func getFakeStats(schema *expression.Schema) *property.StatsInfo {
 	profile := &property.StatsInfo{
 		RowCount: 1,
@@ -465,6 +478,11 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		stmtCtx.AppendWarning(errors.Errorf(msg))
 		logutil.BgLogger().Debug(msg)
 	}
+
+	// The multi-valued index is not in the regular paths, it can only be accessed by IndexMerge.
+	if len(indexMergeConds) > 0 && sessionAndStmtPermission && ds.tableInfo.TempTableType != model.TempTableLocal && !readFromTableCache {
+		ds.generateMVIndexMergePaths(indexMergeConds)
+	}
 	return ds.stats, nil
 }
 
@@ -617,6 +635,151 @@ func (ds *DataSource) generateIndexMergeOrPaths(filters []expression.Expression)
 	return nil
 }
 
+// mvIndexPointSelectivity is the pseudo selectivity of a point range on a multi-valued index.
+const mvIndexPointSelectivity = 1.0 / 1000
+
+// generateMVIndexMergePaths generates IndexMerge paths on the multi-valued indexes. For
+// `const MEMBER OF (e)`, `JSON_CONTAINS(e, const)` and the DNF of them, where `e` is the
+// JSON array indexed by `CAST(e AS ... ARRAY)`, every value makes up a point range of a
+// partial index path. The index entries only tell which rows may match, so all the
+// filters are kept as the table filters of the IndexMerge path.
+func (ds *DataSource) generateMVIndexMergePaths(filters []expression.Expression) {
+	for _, idx := range ds.tableInfo.Indices {
+		if !idx.MVIndex || idx.State != model.StatePublic {
+			continue
+		}
+		if idx.Invisible && !ds.ctx.GetSessionVars().OptimizerUseInvisibleIndexes {
+			continue
+		}
+		if !ds.isInIndexMergeHints(idx.Name.L) {
+			continue
+		}
+		idxCol, arrayExpr, elemTp := ds.mvIndexColumn(idx)
+		if idxCol == nil {
+			continue
+		}
+		for _, filter := range filters {
+			values := ds.mvIndexValues(filter, arrayExpr)
+			if len(values) == 0 {
+				continue
+			}
+			partialPaths := make([]*util.AccessPath, 0, len(values))
+			for _, v := range values {
+				d, err := expression.ConvertJSONToArrayElem(v, elemTp)
+				if err != nil {
+					partialPaths = nil
+					break
+				}
+				partialPaths = append(partialPaths, &util.AccessPath{
+					Index:            idx,
+					IdxCols:          []*expression.Column{idxCol},
+					IdxColLens:       []int{types.UnspecifiedLength},
+					FullIdxCols:      []*expression.Column{idxCol},
+					FullIdxColLens:   []int{types.UnspecifiedLength},
+					Ranges:           []*ranger.Range{{LowVal: []types.Datum{d}, HighVal: []types.Datum{d}, Collators: collate.GetBinaryCollatorSlice(1)}},
+					CountAfterAccess: math.Max(ds.tableStats.RowCount*mvIndexPointSelectivity, 1),
+				})
+			}
+			if len(partialPaths) == 0 {
+				continue
+			}
+			mvIndexMergePath := &util.AccessPath{PartialIndexPaths: partialPaths}
+			mvIndexMergePath.TableFilters = append(mvIndexMergePath.TableFilters, filters...)
+			for _, p := range partialPaths {
+				mvIndexMergePath.CountAfterAccess += p.CountAfterAccess
+			}
+			mvIndexMergePath.CountAfterAccess = math.Min(mvIndexMergePath.CountAfterAccess, ds.tableStats.RowCount)
+			ds.possibleAccessPaths = append(ds.possibleAccessPaths, mvIndexMergePath)
+		}
+	}
+}
+
+// mvIndexColumn returns the hidden column of the multi-valued index, the indexed JSON
+// expression and the element type of the array.
+func (ds *DataSource) mvIndexColumn(idx *model.IndexInfo) (*expression.Column, expression.Expression, *types.FieldType) {
+	colInfo := ds.tableInfo.Columns[idx.Columns[0].Offset]
+	for _, col := range ds.TblCols {
+		if col.ID != colInfo.ID {
+			continue
+		}
+		sf, ok := col.VirtualExpr.(*expression.ScalarFunction)
+		if !ok {
+			return nil, nil, nil
+		}
+		elemTp, ok := expression.ArrayCastElemType(sf)
+		if !ok {
+			return nil, nil, nil
+		}
+		return col, sf.GetArgs()[0], elemTp
+	}
+	return nil, nil, nil
+}
+
+// mvIndexValues extracts the JSON values to look up in the multi-valued index on
+// `arrayExpr` from the filter. Nil is returned if the index can't be used.
+func (ds *DataSource) mvIndexValues(filter expression.Expression, arrayExpr expression.Expression) []json.BinaryJSON {
+	sf, ok := filter.(*expression.ScalarFunction)
+	if !ok {
+		return nil
+	}
+	switch sf.FuncName.L {
+	case ast.LogicOr:
+		var values []json.BinaryJSON
+		for _, item := range expression.FlattenDNFConditions(sf) {
+			itemValues := ds.mvIndexValues(item, arrayExpr)
+			if len(itemValues) == 0 {
+				return nil
+			}
+			values = append(values, itemValues...)
+		}
+		return values
+	case ast.JSONMemberOf:
+		args := sf.GetArgs()
+		if !args[1].Equal(ds.ctx, arrayExpr) {
+			return nil
+		}
+		v, ok := ds.evalConstJSON(args[0])
+		if !ok {
+			return nil
+		}
+		return []json.BinaryJSON{v}
+	case ast.JSONContains:
+		args := sf.GetArgs()
+		if len(args) != 2 || !args[0].Equal(ds.ctx, arrayExpr) {
+			return nil
+		}
+		v, ok := ds.evalConstJSON(args[1])
+		if !ok {
+			return nil
+		}
+		if v.TypeCode != json.TypeCodeArray {
+			return []json.BinaryJSON{v}
+		}
+		// All the elements must be in the array, looking up one of them is enough.
+		if v.GetElemCount() == 0 {
+			return nil
+		}
+		return []json.BinaryJSON{v.ArrayGetElem(0)}
+	}
+	return nil
+}
+
+// evalConstJSON evaluates the expression as JSON if it's a constant which can be
+// used to build the access path.
+func (ds *DataSource) evalConstJSON(expr expression.Expression) (json.BinaryJSON, bool) {
+	if len(expression.ExtractColumns(expr)) > 0 || len(expression.ExtractCorColumns(expr)) > 0 {
+		return json.BinaryJSON{}, false
+	}
+	if !expr.ConstItem(ds.ctx.GetSessionVars().StmtCtx) {
+		return json.BinaryJSON{}, false
+	}
+	v, isNull, err := expr.EvalJSON(ds.ctx, chunk.Row{})
+	if isNull || err != nil {
+		return json.BinaryJSON{}, false
+	}
+	return v, true
+}
+
 // isInIndexMergeHints checks whether current index or primary key is in IndexMerge hints.
 func (ds *DataSource) isInIndexMergeHints(name string) bool {
 	if len(ds.indexMergeHints) == 0 {
@@ -1253,6 +1416,32 @@ func (p *LogicalWindow) ExtractColGroups(colGroups [][]*expression.Column) [][]*
 	return extracted
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalExpand) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, colGroups [][]*expression.Column) (*property.StatsInfo, error) {
+	if p.stats != nil {
+		return p.stats, nil
+	}
+	childProfile := childStats[0]
+	levels := float64(len(p.LevelExprs))
+	p.stats = &property.StatsInfo{
+		RowCount: childProfile.RowCount * levels,
+		ColNDVs:  make(map[int64]float64, selfSchema.Len()),
+	}
+	for i, col := range selfSchema.Columns {
+		if col.Equal(nil, p.GroupingID) {
+			p.stats.ColNDVs[col.UniqueID] = levels
+			continue
+		}
+		// All the GROUP BY items are kept in the first level.
+		if childCol, ok := p.LevelExprs[0][i].(*expression.Column); ok {
+			p.stats.ColNDVs[col.UniqueID] = childProfile.ColNDVs[childCol.UniqueID]
+		} else {
+			p.stats.ColNDVs[col.UniqueID] = childProfile.RowCount
+		}
+	}
+	return p.stats, nil
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (p *LogicalCTE) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, colGroups [][]*expression.Column) (*property.StatsInfo, error) {
 	if p.stats != nil {
@@ -100,6 +100,12 @@ func (t *copTask) invalid() bool {
 	return t.tablePlan == nil && t.indexPlan == nil
 }
 
+// usePaging returns whether the coprocessor requests of the task should be sent in paging mode.
+// Paging is used if the upper task only requires a few rows, so the scan can be terminated early.
+func (t *copTask) usePaging(ctx sessionctx.Context) bool {
+	return ctx.GetSessionVars().EnablePaging && t.expectCnt > 0 && t.expectCnt <= paging.Threshold
+}
+
 func (t *rootTask) invalid() bool {
 	return t.p == nil
 }
@@ -922,7 +928,7 @@ func buildIndexLookUpTask(ctx sessionctx.Context, t *copTask) *rootTask {
 	idxCst := indexRows * sessVars.CPUFactor
 	// if the expectCnt is below the paging threshold, using paging API, recalculate idxCst.
 	// paging API reduces the count of index and table rows, however introduces more seek cost.
-	if ctx.GetSessionVars().EnablePaging && t.expectCnt > 0 && t.expectCnt <= paging.Threshold {
+	if t.usePaging(ctx) {
 		p.Paging = true
 		pagingCst := calcPagingCost(ctx, t)
 		// prevent enlarging the cost because we take paging as a better plan,
@@ -1069,7 +1075,7 @@ func (t *copTask) convertToRootTaskImpl(ctx sessionctx.Context) *rootTask {
 	if t.indexPlan != nil && t.tablePlan != nil {
 		newTask = buildIndexLookUpTask(ctx, t)
 	} else if t.indexPlan != nil {
-		p := PhysicalIndexReader{indexPlan: t.indexPlan}.Init(ctx, t.indexPlan.SelectBlockOffset())
+		p := PhysicalIndexReader{indexPlan: t.indexPlan, Paging: t.usePaging(ctx)}.Init(ctx, t.indexPlan.SelectBlockOffset())
 		p.PartitionInfo = t.partitionInfo
 		p.stats = t.indexPlan.statsInfo()
 		p.cost = newTask.cost()
@@ -1089,6 +1095,7 @@ func (t *copTask) convertToRootTaskImpl(ctx sessionctx.Context) *rootTask {
 			tablePlan:      t.tablePlan,
 			StoreType:      ts.StoreType,
 			IsCommonHandle: ts.Table.IsCommonHandle,
+			Paging:         ts.StoreType == kv.TiKV && t.usePaging(ctx),
 		}.Init(ctx, t.tablePlan.SelectBlockOffset())
 		p.PartitionInfo = t.partitionInfo
 		p.stats = t.tablePlan.statsInfo()
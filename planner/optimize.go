@@ -23,6 +23,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/bindinfo"
@@ -61,6 +62,55 @@ func IsReadOnly(node ast.Node, vars *variable.SessionVars) bool {
 	return ast.IsReadOnly(node)
 }
 
+// isRCCheckTSStmt checks whether the statement can read data with the last fetched timestamp of the RC transaction,
+// see variable.TiDBRCReadCheckTS. Only the read-only SELECT statements without subqueries are supported, because the
+// uncorrelated subqueries are executed in the optimization phase, before the point gets in the plan can be found.
+func isRCCheckTSStmt(sessVars *variable.SessionVars, node ast.Node) bool {
+	if !sessVars.RcReadCheckTS || !sessVars.IsPessimisticReadConsistency() || sessVars.InRestrictedSQL ||
+		sessVars.SnapshotTS != 0 || sessVars.StmtCtx.IsStaleness {
+		return false
+	}
+	sel, ok := node.(*ast.SelectStmt)
+	if !ok || sel.SelectIntoOpt != nil {
+		return false
+	}
+	if sel.LockInfo != nil && sel.LockInfo.LockType != ast.SelectLockNone {
+		return false
+	}
+	checker := &subqueryChecker{}
+	sel.Accept(checker)
+	return !checker.hasSubquery
+}
+
+type subqueryChecker struct {
+	hasSubquery bool
+}
+
+func (c *subqueryChecker) Enter(in ast.Node) (ast.Node, bool) {
+	if _, ok := in.(*ast.SubqueryExpr); ok {
+		c.hasSubquery = true
+	}
+	return in, c.hasSubquery
+}
+
+func (c *subqueryChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// containsPointGet checks whether the plan contains the point get executors.
+func containsPointGet(p plannercore.PhysicalPlan) bool {
+	switch p.(type) {
+	case *plannercore.PointGetPlan, *plannercore.BatchPointGetPlan:
+		return true
+	}
+	for _, child := range p.Children() {
+		if containsPointGet(child) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetExecuteForUpdateReadIS is used to check whether the statement is `execute` and target statement has a forUpdateRead flag.
 // If so, we will return the latest information schema.
 func GetExecuteForUpdateReadIS(node ast.Node, sctx sessionctx.Context) infoschema.InfoSchema {
@@ -105,16 +155,12 @@ func matchSQLBinding(sctx sessionctx.Context, stmtNode ast.StmtNode) (bindRecord
 // Optimize does optimization and creates a Plan.
 // The node must be prepared first.
 func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (plannercore.Plan, types.NameSlice, error) {
-	sessVars := sctx.GetSessionVars()
-
-	// Because for write stmt, TiFlash has a different results when lock the data in point get plan. We ban the TiFlash
-	// engine in not read only stmt.
-	if _, isolationReadContainTiFlash := sessVars.IsolationReadEngines[kv.TiFlash]; isolationReadContainTiFlash && !IsReadOnly(node, sessVars) {
-		delete(sessVars.IsolationReadEngines, kv.TiFlash)
-		defer func() {
-			sessVars.IsolationReadEngines[kv.TiFlash] = struct{}{}
-		}()
+	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
+		span1 := span.Tracer().StartSpan("planner.Optimize", opentracing.ChildOf(span.Context()))
+		defer span1.Finish()
+		ctx = opentracing.ContextWithSpan(ctx, span1)
 	}
+	sessVars := sctx.GetSessionVars()
 
 	tableHints := hint.ExtractTableHintsFromStmtNode(node, sctx)
 	originStmtHints, originStmtHintsOffs, warns := handleStmtHints(tableHints)
@@ -130,6 +176,16 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		}
 	}
 
+	// The isolation read engines may be set by SET_VAR hint, so it's checked after the hints are applied.
+	// Because for write stmt, TiFlash has a different results when lock the data in point get plan. We ban the TiFlash
+	// engine in not read only stmt.
+	if _, isolationReadContainTiFlash := sessVars.IsolationReadEngines[kv.TiFlash]; isolationReadContainTiFlash && !IsReadOnly(node, sessVars) {
+		delete(sessVars.IsolationReadEngines, kv.TiFlash)
+		defer func() {
+			sessVars.IsolationReadEngines[kv.TiFlash] = struct{}{}
+		}()
+	}
+
 	if _, isolationReadContainTiKV := sessVars.IsolationReadEngines[kv.TiKV]; isolationReadContainTiKV {
 		var fp plannercore.Plan
 		if fpv, ok := sctx.Value(plannercore.PointPlanKey).(plannercore.PointPlanVal); ok {
@@ -145,6 +201,7 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 			return fp, fp.OutputNames(), nil
 		}
 	}
+	sessVars.StmtCtx.RCCheckTS = isRCCheckTSStmt(sessVars, node)
 	sctx.PrepareTSFuture(ctx)
 
 	useBinding := sessVars.UsePlanBaselines
@@ -221,6 +278,13 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 			return nil, nil, err
 		}
 	}
+	if sessVars.StmtCtx.RCCheckTS {
+		if physicalPlan, ok := bestPlan.(plannercore.PhysicalPlan); !ok || containsPointGet(physicalPlan) {
+			// The point get reads data by the snapshot which can't check the timestamp, so a new one is required.
+			sessVars.StmtCtx.RCCheckTS = false
+			sctx.PrepareTSFuture(ctx)
+		}
+	}
 
 	// Add a baseline evolution task if:
 	// 1. the returned plan is from bindings;
@@ -286,7 +350,9 @@ func allowInReadOnlyMode(sctx sessionctx.Context, node ast.Node) (bool, error) {
 		*ast.DropBindingStmt,
 		*ast.PrepareStmt,
 		*ast.BeginStmt,
-		*ast.RollbackStmt:
+		*ast.RollbackStmt,
+		*ast.SavepointStmt,
+		*ast.ReleaseSavepointStmt:
 		return true, nil
 	case *ast.CommitStmt:
 		txn, err := sctx.Txn(true)
@@ -0,0 +1,29 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conn
+
+import "context"
+
+// AuthConn is the connection with the client during the authentication.
+// It's used by the multi-round authentication plugins, e.g. `authentication_ldap_sasl`,
+// to exchange the extra authentication data with the client.
+type AuthConn interface {
+	// ReadPacket reads a packet from the client.
+	ReadPacket() ([]byte, error)
+	// WriteAuthMoreData writes the data as an AuthMoreData packet to the client.
+	WriteAuthMoreData(data []byte) error
+	// Flush flushes the buffered data to the client.
+	Flush(ctx context.Context) error
+}
@@ -19,12 +19,16 @@ import (
 
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/privilege/conn"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 )
 
 type keyType int
 
+// AnyColumn is passed as the column to RequestVerification to check the privilege on the table or any of its columns.
+const AnyColumn = "*"
+
 func (k keyType) String() string {
 	return "privilege-key"
 }
@@ -40,6 +44,8 @@ type Manager interface {
 	// RequestVerification verifies user privilege for the request.
 	// If table is "", only check global/db scope privileges.
 	// If table is not "", check global/db/table scope privileges.
+	// If column is not "", check global/db/table/column scope privileges, pass AnyColumn to column
+	// means the privilege on any column of the table would be OK.
 	// priv should be a defined constant like CreatePriv, if pass AllPrivMask to priv,
 	// this means any privilege would be OK.
 	RequestVerification(activeRole []*auth.RoleIdentity, db, table, column string, priv mysql.PrivilegeType) bool
@@ -58,9 +64,16 @@ type Manager interface {
 	// RequestDynamicVerificationWithUser verifies a DYNAMIC privilege for a specific user.
 	RequestDynamicVerificationWithUser(privName string, grantable bool, user *auth.UserIdentity) bool
 
+	// RequestProxyVerification verifies the PROXY privilege on the proxied user, which allows
+	// the current user to act as the proxied user by SET SESSION AUTHORIZATION.
+	RequestProxyVerification(activeRoles []*auth.RoleIdentity, proxiedUser *auth.UserIdentity, grantable bool) bool
+
 	// ConnectionVerification verifies user privilege for connection.
 	// Requires exact match on user name and host name.
-	ConnectionVerification(user, host string, auth, salt []byte, tlsState *tls.ConnectionState) bool
+	// The authConn is used to exchange the extra data with the client for the multi-round authentication plugins.
+	// The passwordExpired is true if the user is verified but the password is expired, which must be changed
+	// before executing other statements.
+	ConnectionVerification(user, host string, auth, salt []byte, tlsState *tls.ConnectionState, authConn conn.AuthConn) (success bool, passwordExpired bool)
 
 	// GetAuthWithoutVerification uses to get auth name without verification.
 	// Requires exact match on user name and host name.
@@ -85,6 +98,10 @@ type Manager interface {
 	// GetDefaultRoles returns all default roles for certain user.
 	GetDefaultRoles(user, host string) []*auth.RoleIdentity
 
+	// GetLoginRoles returns the roles activated when the user logs in, which are all the granted roles if
+	// activate_all_roles_on_login is ON, or the default roles otherwise.
+	GetLoginRoles(user, host string) []*auth.RoleIdentity
+
 	// GetAllRoles return all roles of user.
 	GetAllRoles(user, host string) []*auth.RoleIdentity
 
@@ -93,6 +110,12 @@ type Manager interface {
 
 	// Get the authentication plugin for a user
 	GetAuthPlugin(user, host string) (string, error)
+
+	// GetUserResourceGroup returns the resource group which the user is bound to, empty means the default group.
+	GetUserResourceGroup(user, host string) string
+
+	// GetUserMaxConnections returns the MAX_USER_CONNECTIONS of the user, 0 means the user has no own limit.
+	GetUserMaxConnections(user, host string) int64
 }
 
 const key keyType = 0
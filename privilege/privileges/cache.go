@@ -22,6 +22,7 @@ import (
 	"net"
 	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
@@ -65,8 +67,10 @@ const (
 	References_priv,Alter_priv,Execute_priv,Index_priv,Create_view_priv,Show_view_priv,
 	Create_role_priv,Drop_role_priv,Create_tmp_table_priv,Lock_tables_priv,Create_routine_priv,
 	Alter_routine_priv,Event_priv,Shutdown_priv,Reload_priv,File_priv,Config_priv,Repl_client_priv,Repl_slave_priv,
-	account_locked,plugin FROM mysql.user`
+	account_locked,plugin,Resource_group,max_user_connections,Password_expired,Password_lifetime,
+	Failed_login_attempts,Password_lock_time,UNIX_TIMESTAMP(Password_last_changed) AS Password_last_changed FROM mysql.user`
 	sqlLoadGlobalGrantsTable = `SELECT HIGH_PRIORITY Host,User,Priv,With_Grant_Option FROM mysql.global_grants`
+	sqlLoadProxiesPrivTable  = `SELECT HIGH_PRIORITY Host,User,Proxied_host,Proxied_user,With_grant FROM mysql.proxies_priv`
 )
 
 func computePrivMask(privs []mysql.PrivilegeType) mysql.PrivilegeType {
@@ -99,6 +103,17 @@ type UserRecord struct {
 	Privileges           mysql.PrivilegeType
 	AccountLocked        bool // A role record when this field is true
 	AuthPlugin           string
+	ResourceGroup        string
+	MaxUserConnections   int64
+	PasswordExpired      bool
+	// PasswordLastChanged is the unix timestamp when the password is changed.
+	PasswordLastChanged int64
+	// PasswordLifetime is the days after which the password expires, 0 means it never expires,
+	// and -1 means the default_password_lifetime is used.
+	PasswordLifetime    int64
+	FailedLoginAttempts int64
+	// PasswordLockTime is the days the account is locked after too many failed logins, -1 means unbounded.
+	PasswordLockTime int64
 }
 
 // NewUserRecord return a UserRecord, only use for unit test.
@@ -216,6 +231,15 @@ type columnsPrivRecord struct {
 	ColumnPriv mysql.PrivilegeType
 }
 
+// proxiesPrivRecord is used to cache mysql.proxies_priv
+type proxiesPrivRecord struct {
+	baseRecord
+
+	ProxiedHost string
+	ProxiedUser string
+	WithGrant   bool
+}
+
 // defaultRoleRecord is used to cache mysql.default_roles
 type defaultRoleRecord struct {
 	baseRecord
@@ -267,6 +291,7 @@ type MySQLPrivilege struct {
 	ColumnsPriv   []columnsPrivRecord
 	DefaultRoles  []defaultRoleRecord
 	RoleGraph     map[string]roleGraphEdgesTable
+	ProxiesPriv   []proxiesPrivRecord
 }
 
 // FindAllUserEffectiveRoles is used to find all effective roles grant to this user.
@@ -307,16 +332,107 @@ func (p *MySQLPrivilege) FindAllRole(activeRoles []*auth.RoleIdentity) []*auth.R
 }
 
 // FindRole is used to detect whether there is edges between users and roles.
+// The mandatory roles are regarded as granted to all the users, and the roles mapped from
+// the LDAP groups are regarded as granted to the users authenticated by LDAP.
 func (p *MySQLPrivilege) FindRole(user string, host string, role *auth.RoleIdentity) bool {
 	rec := p.matchUser(user, host)
 	r := p.matchUser(role.Username, role.Hostname)
 	if rec != nil && r != nil {
 		key := rec.User + "@" + rec.Host
-		return p.RoleGraph[key].Find(role.Username, role.Hostname)
+		return p.RoleGraph[key].Find(role.Username, role.Hostname) || isMandatoryRole(role) || p.isLDAPGroupRole(rec.User, rec.Host, role)
+	}
+	return false
+}
+
+// mandatoryRoles caches the roles parsed from mandatory_roles.
+var mandatoryRoles struct {
+	sync.Mutex
+	value string
+	roles []*auth.RoleIdentity
+}
+
+func getMandatoryRoles() []*auth.RoleIdentity {
+	value := variable.MandatoryRolesValue.Load()
+	mandatoryRoles.Lock()
+	defer mandatoryRoles.Unlock()
+	if value != mandatoryRoles.value {
+		roles, err := variable.ParseRoleList(value)
+		if err != nil {
+			logutil.BgLogger().Warn("invalid mandatory roles", zap.String("mandatory_roles", value), zap.Error(err))
+		}
+		mandatoryRoles.value, mandatoryRoles.roles = value, roles
+	}
+	return mandatoryRoles.roles
+}
+
+func isMandatoryRole(role *auth.RoleIdentity) bool {
+	for _, r := range getMandatoryRoles() {
+		if r.Username == role.Username && strings.EqualFold(r.Hostname, role.Hostname) {
+			return true
+		}
+	}
+	return false
+}
+
+// ldapGroupRoles records the roles mapped from the LDAP groups of the users at their last
+// successful LDAP authentication, the key is user@host of the account.
+var ldapGroupRoles struct {
+	sync.RWMutex
+	roles map[string][]*auth.RoleIdentity
+}
+
+func setLDAPGroupRoles(user, host string, roles []*auth.RoleIdentity) {
+	key := user + "@" + host
+	ldapGroupRoles.Lock()
+	defer ldapGroupRoles.Unlock()
+	if len(roles) == 0 {
+		delete(ldapGroupRoles.roles, key)
+		return
+	}
+	if ldapGroupRoles.roles == nil {
+		ldapGroupRoles.roles = make(map[string][]*auth.RoleIdentity)
+	}
+	ldapGroupRoles.roles[key] = roles
+}
+
+// getLDAPGroupRoles returns the roles mapped from the LDAP groups, they are ignored if the account
+// has been changed to authenticate without LDAP.
+func (p *MySQLPrivilege) getLDAPGroupRoles(user, host string) []*auth.RoleIdentity {
+	record := p.connectionVerification(user, host)
+	if record == nil || (record.AuthPlugin != mysql.AuthLDAPSimple && record.AuthPlugin != mysql.AuthLDAPSASL) {
+		return nil
+	}
+	ldapGroupRoles.RLock()
+	defer ldapGroupRoles.RUnlock()
+	return ldapGroupRoles.roles[user+"@"+host]
+}
+
+func (p *MySQLPrivilege) isLDAPGroupRole(user, host string, role *auth.RoleIdentity) bool {
+	for _, r := range p.getLDAPGroupRoles(user, host) {
+		if r.Username == role.Username && strings.EqualFold(r.Hostname, role.Hostname) {
+			return true
+		}
 	}
 	return false
 }
 
+// appendLDAPGroupRoles appends the roles mapped from the LDAP groups which exist and aren't in the roles.
+func (p *MySQLPrivilege) appendLDAPGroupRoles(user, host string, roles []*auth.RoleIdentity) []*auth.RoleIdentity {
+	for _, r := range p.getLDAPGroupRoles(user, host) {
+		found := false
+		for _, role := range roles {
+			if r.Username == role.Username && strings.EqualFold(r.Hostname, role.Hostname) {
+				found = true
+				break
+			}
+		}
+		if !found && p.matchUser(r.Username, r.Hostname) != nil {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
 // LoadAll loads the tables from database to memory.
 func (p *MySQLPrivilege) LoadAll(ctx sessionctx.Context) error {
 	err := p.LoadUserTable(ctx)
@@ -379,6 +495,15 @@ func (p *MySQLPrivilege) LoadAll(ctx sessionctx.Context) error {
 		}
 		logutil.BgLogger().Warn("mysql.role_edges missing")
 	}
+
+	err = p.LoadProxiesPrivTable(ctx)
+	if err != nil {
+		if !noSuchTable(err) {
+			logutil.BgLogger().Warn("load mysql.proxies_priv", zap.Error(err))
+			return errLoadPrivilege.FastGen("mysql.proxies_priv")
+		}
+		logutil.BgLogger().Warn("mysql.proxies_priv missing")
+	}
 	return nil
 }
 
@@ -564,6 +689,11 @@ func (p *MySQLPrivilege) LoadDefaultRoles(ctx sessionctx.Context) error {
 	return p.loadTable(ctx, sqlLoadDefaultRoles, p.decodeDefaultRoleTableRow)
 }
 
+// LoadProxiesPrivTable loads the mysql.proxies_priv table from database.
+func (p *MySQLPrivilege) LoadProxiesPrivTable(ctx sessionctx.Context) error {
+	return p.loadTable(ctx, sqlLoadProxiesPrivTable, p.decodeProxiesPrivTableRow)
+}
+
 func (p *MySQLPrivilege) loadTable(sctx sessionctx.Context, sql string,
 	decodeTableRow func(chunk.Row, []*ast.ResultField) error) error {
 	ctx := context.Background()
@@ -653,6 +783,28 @@ func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField
 			} else {
 				value.AuthPlugin = mysql.AuthNativePassword
 			}
+		case f.ColumnAsName.L == "resource_group":
+			value.ResourceGroup = row.GetString(i)
+		case f.ColumnAsName.L == "max_user_connections":
+			value.MaxUserConnections = row.GetInt64(i)
+		case f.ColumnAsName.L == "password_expired":
+			if row.GetEnum(i).String() == "Y" {
+				value.PasswordExpired = true
+			}
+		case f.ColumnAsName.L == "password_lifetime":
+			if row.IsNull(i) {
+				value.PasswordLifetime = -1
+			} else {
+				value.PasswordLifetime = row.GetInt64(i)
+			}
+		case f.ColumnAsName.L == "failed_login_attempts":
+			value.FailedLoginAttempts = row.GetInt64(i)
+		case f.ColumnAsName.L == "password_lock_time":
+			value.PasswordLockTime = row.GetInt64(i)
+		case f.ColumnAsName.L == "password_last_changed":
+			if !row.IsNull(i) {
+				value.PasswordLastChanged = row.GetInt64(i)
+			}
 		case f.Column.Tp == mysql.TypeEnum:
 			if row.GetEnum(i).String() != "Y" {
 				continue
@@ -812,6 +964,24 @@ func (p *MySQLPrivilege) decodeDefaultRoleTableRow(row chunk.Row, fs []*ast.Resu
 	return nil
 }
 
+func (p *MySQLPrivilege) decodeProxiesPrivTableRow(row chunk.Row, fs []*ast.ResultField) error {
+	var value proxiesPrivRecord
+	for i, f := range fs {
+		switch {
+		case f.ColumnAsName.L == "proxied_host":
+			value.ProxiedHost = row.GetString(i)
+		case f.ColumnAsName.L == "proxied_user":
+			value.ProxiedUser = row.GetString(i)
+		case f.ColumnAsName.L == "with_grant":
+			value.WithGrant = row.GetEnum(i).String() == "Y"
+		default:
+			value.assignUserOrHost(row, i, f)
+		}
+	}
+	p.ProxiesPriv = append(p.ProxiesPriv, value)
+	return nil
+}
+
 func (p *MySQLPrivilege) decodeColumnsPrivTableRow(row chunk.Row, fs []*ast.ResultField) error {
 	var value columnsPrivRecord
 	for i, f := range fs {
@@ -1020,6 +1190,20 @@ func (p *MySQLPrivilege) matchColumns(user, host, db, table, column string) *col
 	return nil
 }
 
+// matchAnyColumn returns the union of the privileges granted on the columns of the table.
+func (p *MySQLPrivilege) matchAnyColumn(user, host, db, table string) mysql.PrivilegeType {
+	var priv mysql.PrivilegeType
+	for i := 0; i < len(p.ColumnsPriv); i++ {
+		record := &p.ColumnsPriv[i]
+		if record.baseRecord.match(user, host) &&
+			strings.EqualFold(record.DB, db) &&
+			strings.EqualFold(record.TableName, table) {
+			priv |= record.ColumnPriv
+		}
+	}
+	return priv
+}
+
 // HasExplicitlyGrantedDynamicPrivilege checks if a user has a DYNAMIC privilege
 // without accepting SUPER privilege as a fallback.
 func (p *MySQLPrivilege) HasExplicitlyGrantedDynamicPrivilege(activeRoles []*auth.RoleIdentity, user, host, privName string, withGrant bool) bool {
@@ -1066,6 +1250,26 @@ func (p *MySQLPrivilege) RequestDynamicVerification(activeRoles []*auth.RoleIden
 	return p.RequestVerification(activeRoles, user, host, "", "", "", mysql.SuperPriv)
 }
 
+// RequestProxyVerification checks whether the user has the PROXY privilege on the proxied user.
+func (p *MySQLPrivilege) RequestProxyVerification(activeRoles []*auth.RoleIdentity, user, host, proxiedUser, proxiedHost string, withGrant bool) bool {
+	for i := range p.ProxiesPriv {
+		record := &p.ProxiesPriv[i]
+		if record.match(user, host) && record.ProxiedUser == proxiedUser && strings.EqualFold(record.ProxiedHost, proxiedHost) {
+			if !withGrant || record.WithGrant {
+				return true
+			}
+		}
+	}
+	// Like the DYNAMIC privileges, the SUPER privilege is a replacement of PROXY unless SEM is enabled.
+	if sem.IsEnabled() {
+		return false
+	}
+	if withGrant && !p.RequestVerification(activeRoles, user, host, "", "", "", mysql.GrantPriv) {
+		return false
+	}
+	return p.RequestVerification(activeRoles, user, host, "", "", "", mysql.SuperPriv)
+}
+
 // RequestVerification checks whether the user have sufficient privileges to do the operation.
 func (p *MySQLPrivilege) RequestVerification(activeRoles []*auth.RoleIdentity, user, host, db, table, column string, priv mysql.PrivilegeType) bool {
 	if priv == mysql.UsagePriv {
@@ -1100,17 +1304,20 @@ func (p *MySQLPrivilege) RequestVerification(activeRoles []*auth.RoleIdentity, u
 		tableRecord := p.matchTables(r.Username, r.Hostname, db, table)
 		if tableRecord != nil {
 			tablePriv |= tableRecord.TablePriv
-			if column != "" {
-				columnPriv |= tableRecord.ColumnPriv
-			}
 		}
 	}
-	if tablePriv&priv > 0 || columnPriv&priv > 0 {
+	if tablePriv&priv > 0 {
 		return true
 	}
 
-	columnPriv = 0
+	if column == "" {
+		return priv == 0
+	}
 	for _, r := range roleList {
+		if column == privilege.AnyColumn {
+			columnPriv |= p.matchAnyColumn(r.Username, r.Hostname, db, table)
+			continue
+		}
 		columnRecord := p.matchColumns(r.Username, r.Hostname, db, table, column)
 		if columnRecord != nil {
 			columnPriv |= columnRecord.ColumnPriv
@@ -1395,6 +1602,17 @@ func (p *MySQLPrivilege) showGrants(user, host string, roles []*auth.RoleIdentit
 		s := fmt.Sprintf("GRANT %s ON *.* TO '%s'@'%s' WITH GRANT OPTION", strings.Join(grantableDynamicPrivs, ","), user, host)
 		gs = append(gs, s)
 	}
+
+	// Show the PROXY privileges.
+	for _, record := range p.ProxiesPriv {
+		if record.fullyMatch(user, host) {
+			s := fmt.Sprintf("GRANT PROXY ON '%s'@'%s' TO '%s'@'%s'", record.ProxiedUser, record.ProxiedHost, user, host)
+			if record.WithGrant {
+				s += " WITH GRANT OPTION"
+			}
+			gs = append(gs, s)
+		}
+	}
 	return gs
 }
 
@@ -1561,7 +1779,13 @@ func (p *MySQLPrivilege) getAllRoles(user, host string) []*auth.RoleIdentity {
 			ret = append(ret, r)
 		}
 	}
-	return ret
+	// The mandatory roles which exist are granted to all the users.
+	for _, r := range getMandatoryRoles() {
+		if !edgeTable.Find(r.Username, r.Hostname) && p.matchUser(r.Username, r.Hostname) != nil {
+			ret = append(ret, r)
+		}
+	}
+	return p.appendLDAPGroupRoles(user, host, ret)
 }
 
 // Handle wraps MySQLPrivilege providing thread safe access.
@@ -0,0 +1,435 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/ngaut/pools"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/auth"
+)
+
+const (
+	// startTLSOID is the OID of the StartTLS extended operation, see RFC 4511.
+	startTLSOID = "1.3.6.1.4.1.1466.20037"
+	// dialTimeout is the timeout of connecting to the LDAP server.
+	dialTimeout = 10 * time.Second
+)
+
+// ldapAuthImpl contains the settings and the connection pool shared by the LDAP authentication plugins.
+type ldapAuthImpl struct {
+	sync.RWMutex
+
+	bindBaseDN       string
+	bindRootDN       string
+	bindRootPWD      string
+	searchAttr       string
+	groupSearchAttr  string
+	groupRoleMapping map[string][]*auth.RoleIdentity
+	ldapServerHost   string
+	ldapServerPort   int
+	enableTLS        bool
+	caPath           string
+	caPool           *x509.CertPool
+	initCapacity     int
+	maxCapacity      int
+
+	// connPool is created lazily and re-created after the connection settings change.
+	connPool *pools.ResourcePool
+}
+
+func newLDAPAuthImpl() *ldapAuthImpl {
+	return &ldapAuthImpl{
+		searchAttr:      "uid",
+		groupSearchAttr: "cn",
+		ldapServerPort:  389,
+		initCapacity:    10,
+		maxCapacity:     1000,
+	}
+}
+
+// startTLS upgrades the connection to TLS through the StartTLS extended operation.
+func startTLS(conn net.Conn, config *tls.Config) (net.Conn, error) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Request")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, 1, "MessageID"))
+	request := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationExtendedRequest, nil, "Start TLS")
+	request.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 0, startTLSOID, "TLS Extended Command"))
+	packet.AppendChild(request)
+	if _, err := conn.Write(packet.Bytes()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	response, err := ber.ReadPacket(conn)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = ldap.GetLDAPError(response); err != nil {
+		return nil, errors.Trace(err)
+	}
+	tlsConn := tls.Client(conn, config)
+	if err = tlsConn.Handshake(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return tlsConn, nil
+}
+
+// dial connects to the LDAP server, and upgrades the connection to TLS if it's enabled.
+func (impl *ldapAuthImpl) dial() (net.Conn, error) {
+	impl.RLock()
+	host, port, enableTLS, caPool := impl.ldapServerHost, impl.ldapServerPort, impl.enableTLS, impl.caPool
+	impl.RUnlock()
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), dialTimeout)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !enableTLS {
+		return conn, nil
+	}
+	tlsConn, err := startTLS(conn, &tls.Config{
+		ServerName: host,
+		RootCAs:    caPool,
+		MinVersion: tls.VersionTLS12,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+func (impl *ldapAuthImpl) connectionFactory() (pools.Resource, error) {
+	conn, err := impl.dial()
+	if err != nil {
+		return nil, err
+	}
+	_, isTLS := conn.(*tls.Conn)
+	ldapConn := ldap.NewConn(conn, isTLS)
+	ldapConn.Start()
+	return ldapConn, nil
+}
+
+// getConnection gets a connection to the LDAP server from the pool.
+// The connection must be returned by putConnection with the same pool.
+func (impl *ldapAuthImpl) getConnection() (*pools.ResourcePool, *ldap.Conn, error) {
+	impl.Lock()
+	if impl.connPool == nil {
+		initCapacity := impl.initCapacity
+		if initCapacity < 1 {
+			initCapacity = 1
+		}
+		if initCapacity > impl.maxCapacity {
+			initCapacity = impl.maxCapacity
+		}
+		impl.connPool = pools.NewResourcePool(impl.connectionFactory, initCapacity, impl.maxCapacity, 0)
+	}
+	pool := impl.connPool
+	impl.Unlock()
+
+	for {
+		resource, err := pool.TryGet()
+		if err == nil && resource == nil {
+			// All the connections are in use, grow the pool unless it reaches the max capacity.
+			if capacity := pool.Capacity(); capacity < pool.MaxCap() {
+				// The error is ignored because the pool may be grown by others or closed, Get handles both.
+				_ = pool.SetCapacity(int(capacity) + 1)
+			}
+			resource, err = pool.Get()
+		}
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		conn := resource.(*ldap.Conn)
+		// The LDAP server may close the idle connections, skip them.
+		if conn.IsClosing() {
+			pool.Put(nil)
+			continue
+		}
+		return pool, conn, nil
+	}
+}
+
+func (impl *ldapAuthImpl) putConnection(pool *pools.ResourcePool, conn *ldap.Conn) {
+	if conn.IsClosing() {
+		pool.Put(nil)
+		return
+	}
+	pool.Put(conn)
+}
+
+// resetPool drops the connection pool, the connections in use are closed after they are returned.
+// The caller should hold the lock.
+func (impl *ldapAuthImpl) resetPool() {
+	if impl.connPool == nil {
+		return
+	}
+	pool := impl.connPool
+	impl.connPool = nil
+	go pool.Close()
+}
+
+// bindRoot binds the connection with the root DN, or binds anonymously if the root DN is not set.
+func (impl *ldapAuthImpl) bindRoot(conn *ldap.Conn) error {
+	impl.RLock()
+	rootDN, rootPWD := impl.bindRootDN, impl.bindRootPWD
+	impl.RUnlock()
+
+	if rootDN == "" {
+		return errors.Trace(conn.UnauthenticatedBind(""))
+	}
+	return errors.Trace(conn.Bind(rootDN, rootPWD))
+}
+
+// searchBindDN searches the DN of the user whose user search attribute equals to the user name.
+func (impl *ldapAuthImpl) searchBindDN(userName string) (string, error) {
+	pool, conn, err := impl.getConnection()
+	if err != nil {
+		return "", err
+	}
+	defer impl.putConnection(pool, conn)
+
+	if err = impl.bindRoot(conn); err != nil {
+		return "", errors.Annotate(err, "bind root dn to search user")
+	}
+
+	impl.RLock()
+	baseDN, searchAttr := impl.bindBaseDN, impl.searchAttr
+	impl.RUnlock()
+
+	result, err := conn.Search(ldap.NewSearchRequest(baseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf("(%s=%s)", searchAttr, ldap.EscapeFilter(userName)), []string{"dn"}, nil))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(result.Entries) == 0 {
+		return "", errors.Errorf("LDAP user %s not found", userName)
+	}
+	return result.Entries[0].DN, nil
+}
+
+// canonicalizeDN returns the DN of the user. The DN comes from the authentication string of the user,
+// a DN beginning with "+" is relative to the base DN, and an empty DN is searched by the user name.
+func (impl *ldapAuthImpl) canonicalizeDN(userName, dn string) (string, error) {
+	if dn == "" {
+		return impl.searchBindDN(userName)
+	}
+	if strings.HasPrefix(dn, "+") {
+		impl.RLock()
+		baseDN := impl.bindBaseDN
+		impl.RUnlock()
+		return dn[1:] + "," + baseDN, nil
+	}
+	return dn, nil
+}
+
+// searchGroupRoles searches the groups that the user belongs to, and maps them to the roles.
+func (impl *ldapAuthImpl) searchGroupRoles(userName, dn string) ([]*auth.RoleIdentity, error) {
+	impl.RLock()
+	baseDN, groupSearchAttr, mapping := impl.bindBaseDN, impl.groupSearchAttr, impl.groupRoleMapping
+	impl.RUnlock()
+	if len(mapping) == 0 {
+		return nil, nil
+	}
+
+	pool, conn, err := impl.getConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer impl.putConnection(pool, conn)
+
+	if err = impl.bindRoot(conn); err != nil {
+		return nil, errors.Annotate(err, "bind root dn to search groups")
+	}
+	filter := fmt.Sprintf("(|(member=%s)(uniqueMember=%s)(memberUid=%s))",
+		ldap.EscapeFilter(dn), ldap.EscapeFilter(dn), ldap.EscapeFilter(userName))
+	result, err := conn.Search(ldap.NewSearchRequest(baseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		filter, []string{groupSearchAttr}, nil))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var roles []*auth.RoleIdentity
+	for _, entry := range result.Entries {
+		for _, group := range entry.GetEqualFoldAttributeValues(groupSearchAttr) {
+			roles = append(roles, mapping[group]...)
+		}
+	}
+	return roles, nil
+}
+
+// ParseGroupRoleMapping parses the mapping from LDAP groups to roles, which is in
+// the format of `group1=role1,group2=role2@host`. The host of a role is '%' by default.
+func ParseGroupRoleMapping(s string) (map[string][]*auth.RoleIdentity, error) {
+	mapping := make(map[string][]*auth.RoleIdentity)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		idx := strings.IndexByte(item, '=')
+		if idx < 0 {
+			return nil, errors.Errorf("invalid group role mapping %s", item)
+		}
+		group := strings.TrimSpace(item[:idx])
+		role := &auth.RoleIdentity{Hostname: "%"}
+		role.Username = strings.TrimSpace(item[idx+1:])
+		if at := strings.LastIndexByte(role.Username, '@'); at >= 0 {
+			role.Username, role.Hostname = trimQuotes(role.Username[:at]), trimQuotes(role.Username[at+1:])
+		} else {
+			role.Username = trimQuotes(role.Username)
+		}
+		if group == "" || role.Username == "" {
+			return nil, errors.Errorf("invalid group role mapping %s", item)
+		}
+		mapping[group] = append(mapping[group], role)
+	}
+	return mapping, nil
+}
+
+func trimQuotes(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"' || s[0] == '`') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// SetBindBaseDN updates the base DN used to search the users and groups.
+func (impl *ldapAuthImpl) SetBindBaseDN(bindBaseDN string) {
+	impl.Lock()
+	defer impl.Unlock()
+	impl.bindBaseDN = bindBaseDN
+}
+
+// SetBindRootDN updates the DN used to bind before searching the users and groups.
+func (impl *ldapAuthImpl) SetBindRootDN(bindRootDN string) {
+	impl.Lock()
+	defer impl.Unlock()
+	impl.bindRootDN = bindRootDN
+}
+
+// SetBindRootPW updates the password of the root DN.
+func (impl *ldapAuthImpl) SetBindRootPW(bindRootPW string) {
+	impl.Lock()
+	defer impl.Unlock()
+	impl.bindRootPWD = bindRootPW
+}
+
+// SetSearchAttr updates the attribute used to search the users by the user name.
+func (impl *ldapAuthImpl) SetSearchAttr(searchAttr string) {
+	impl.Lock()
+	defer impl.Unlock()
+	impl.searchAttr = searchAttr
+}
+
+// SetGroupSearchAttr updates the attribute of the groups which is mapped to the roles.
+func (impl *ldapAuthImpl) SetGroupSearchAttr(groupSearchAttr string) {
+	impl.Lock()
+	defer impl.Unlock()
+	impl.groupSearchAttr = groupSearchAttr
+}
+
+// SetGroupRoleMapping updates the mapping from the groups to the roles, see ParseGroupRoleMapping.
+func (impl *ldapAuthImpl) SetGroupRoleMapping(groupRoleMapping string) error {
+	mapping, err := ParseGroupRoleMapping(groupRoleMapping)
+	if err != nil {
+		return err
+	}
+	impl.Lock()
+	defer impl.Unlock()
+	impl.groupRoleMapping = mapping
+	return nil
+}
+
+// SetLDAPServerHost updates the host of the LDAP server.
+func (impl *ldapAuthImpl) SetLDAPServerHost(ldapServerHost string) {
+	impl.Lock()
+	defer impl.Unlock()
+	if impl.ldapServerHost != ldapServerHost {
+		impl.ldapServerHost = ldapServerHost
+		impl.resetPool()
+	}
+}
+
+// SetLDAPServerPort updates the port of the LDAP server.
+func (impl *ldapAuthImpl) SetLDAPServerPort(ldapServerPort int) {
+	impl.Lock()
+	defer impl.Unlock()
+	if impl.ldapServerPort != ldapServerPort {
+		impl.ldapServerPort = ldapServerPort
+		impl.resetPool()
+	}
+}
+
+// SetEnableTLS updates whether to connect to the LDAP server through StartTLS.
+func (impl *ldapAuthImpl) SetEnableTLS(enableTLS bool) {
+	impl.Lock()
+	defer impl.Unlock()
+	if impl.enableTLS != enableTLS {
+		impl.enableTLS = enableTLS
+		impl.resetPool()
+	}
+}
+
+// SetCAPath updates the CA certificates used to verify the LDAP server. The system
+// root CAs are used if it's empty.
+func (impl *ldapAuthImpl) SetCAPath(caPath string) error {
+	var caPool *x509.CertPool
+	if caPath != "" {
+		data, err := os.ReadFile(caPath)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(data) {
+			return errors.Errorf("no valid certificate in %s", caPath)
+		}
+	}
+	impl.Lock()
+	defer impl.Unlock()
+	impl.caPath = caPath
+	impl.caPool = caPool
+	impl.resetPool()
+	return nil
+}
+
+// SetInitCapacity updates the initial capacity of the connection pool.
+func (impl *ldapAuthImpl) SetInitCapacity(initCapacity int) {
+	impl.Lock()
+	defer impl.Unlock()
+	if impl.initCapacity != initCapacity {
+		impl.initCapacity = initCapacity
+		impl.resetPool()
+	}
+}
+
+// SetMaxCapacity updates the max capacity of the connection pool.
+func (impl *ldapAuthImpl) SetMaxCapacity(maxCapacity int) {
+	impl.Lock()
+	defer impl.Unlock()
+	if impl.maxCapacity != maxCapacity {
+		impl.maxCapacity = maxCapacity
+		impl.resetPool()
+	}
+}
@@ -0,0 +1,449 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLDAPServer is a minimal LDAP server which supports simple bind, a fake SASL mechanism,
+// searching the users by uid and the groups by member, and StartTLS.
+type fakeLDAPServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	// users maps the DN to the password.
+	users map[string]string
+	// groups maps the cn of the group to the DN of the members.
+	groups map[string][]string
+
+	mu       sync.Mutex
+	netConns []net.Conn
+	conns    int
+	tlsConns int
+	binds    int
+	wg       sync.WaitGroup
+}
+
+func newFakeLDAPServer(t *testing.T) *fakeLDAPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeLDAPServer{
+		listener: listener,
+		users: map[string]string{
+			"uid=u1,ou=people,dc=example,dc=com": "secret1",
+			"uid=u2,ou=people,dc=example,dc=com": "secret2",
+		},
+		groups: map[string][]string{
+			"dev": {"uid=u1,ou=people,dc=example,dc=com"},
+			"ops": {"uid=u1,ou=people,dc=example,dc=com", "uid=u2,ou=people,dc=example,dc=com"},
+		},
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			c, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.netConns = append(s.netConns, c)
+			s.conns++
+			s.mu.Unlock()
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serve(c)
+			}()
+		}
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, listener.Close())
+		s.mu.Lock()
+		for _, c := range s.netConns {
+			c.Close()
+		}
+		s.mu.Unlock()
+		s.wg.Wait()
+	})
+	return s
+}
+
+func (s *fakeLDAPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeLDAPServer) stats() (conns, tlsConns, binds int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns, s.tlsConns, s.binds
+}
+
+func newResult(messageID int64, application ber.Tag, resultCode int64, extra ...*ber.Packet) *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, application, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, resultCode, "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	for _, child := range extra {
+		result.AppendChild(child)
+	}
+	packet.AppendChild(result)
+	return packet
+}
+
+func (s *fakeLDAPServer) serve(c net.Conn) {
+	defer c.Close()
+	var saslRound int
+	for {
+		request, err := ber.ReadPacket(c)
+		if err != nil {
+			return
+		}
+		messageID := request.Children[0].Value.(int64)
+		op := request.Children[1]
+		var responses []*ber.Packet
+		switch op.Tag {
+		case ldap.ApplicationUnbindRequest:
+			return
+		case ldap.ApplicationExtendedRequest:
+			response := newResult(messageID, ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess)
+			if _, err = c.Write(response.Bytes()); err != nil {
+				return
+			}
+			tlsConn := tls.Server(c, s.tlsConfig)
+			if err = tlsConn.Handshake(); err != nil {
+				return
+			}
+			c = tlsConn
+			s.mu.Lock()
+			s.tlsConns++
+			s.mu.Unlock()
+			continue
+		case ldap.ApplicationBindRequest:
+			s.mu.Lock()
+			s.binds++
+			s.mu.Unlock()
+			dn := op.Children[1].Value.(string)
+			authentication := op.Children[2]
+			code := int64(ldap.LDAPResultInvalidCredentials)
+			var serverCredentials string
+			if authentication.Tag == 0 {
+				pwd, ok := s.users[dn]
+				if dn == "" || ok && pwd == authentication.Data.String() {
+					code = ldap.LDAPResultSuccess
+				}
+			} else {
+				// The fake SASL mechanism accepts "hello" and then "world".
+				credentials := authentication.Children[1].Data.String()
+				switch {
+				case saslRound == 0 && credentials == "hello":
+					code, serverCredentials = ldap.LDAPResultSaslBindInProgress, "challenge"
+					saslRound++
+				case saslRound == 1 && credentials == "world":
+					code, serverCredentials = ldap.LDAPResultSuccess, "done"
+				}
+			}
+			var extra []*ber.Packet
+			if serverCredentials != "" {
+				extra = append(extra, ber.NewString(ber.ClassContext, ber.TypePrimitive, 7, serverCredentials, "SASL Credentials"))
+			}
+			responses = append(responses, newResult(messageID, ldap.ApplicationBindResponse, code, extra...))
+		case ldap.ApplicationSearchRequest:
+			filter, err := ldap.DecompileFilter(op.Children[6])
+			if err != nil {
+				return
+			}
+			for _, entry := range s.search(filter) {
+				packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+				packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+				result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+				result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "DN"))
+				attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+				for _, attr := range entry.Attributes {
+					attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+					attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attr.Name, "Type"))
+					values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+					for _, value := range attr.Values {
+						values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+					}
+					attribute.AppendChild(values)
+					attributes.AppendChild(attribute)
+				}
+				result.AppendChild(attributes)
+				packet.AppendChild(result)
+				responses = append(responses, packet)
+			}
+			response := newResult(messageID, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)
+			responses = append(responses, response)
+		default:
+			return
+		}
+		for _, response := range responses {
+			if _, err = c.Write(response.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *fakeLDAPServer) search(filter string) []*ldap.Entry {
+	var entries []*ldap.Entry
+	if strings.HasPrefix(filter, "(uid=") {
+		uid := strings.TrimSuffix(strings.TrimPrefix(filter, "(uid="), ")")
+		dn := "uid=" + uid + ",ou=people,dc=example,dc=com"
+		if _, ok := s.users[dn]; ok {
+			entries = append(entries, ldap.NewEntry(dn, nil))
+		}
+		return entries
+	}
+	for cn, members := range s.groups {
+		for _, member := range members {
+			if strings.Contains(filter, "(member="+member+")") {
+				entries = append(entries, ldap.NewEntry("cn="+cn+",ou=groups,dc=example,dc=com", map[string][]string{"cn": {cn}}))
+				break
+			}
+		}
+	}
+	return entries
+}
+
+func closePool(impl *ldapAuthImpl) {
+	impl.Lock()
+	pool := impl.connPool
+	impl.connPool = nil
+	impl.Unlock()
+	if pool != nil {
+		pool.Close()
+	}
+}
+
+func newTestSimpleImpl(t *testing.T, s *fakeLDAPServer) *ldapAuthImplSimple {
+	impl := &ldapAuthImplSimple{
+		ldapAuthImpl: newLDAPAuthImpl(),
+		bindCache:    make(map[string]bindCacheEntry),
+	}
+	impl.SetLDAPServerHost("127.0.0.1")
+	impl.SetLDAPServerPort(s.port())
+	impl.SetBindBaseDN("dc=example,dc=com")
+	impl.SetInitCapacity(1)
+	impl.SetMaxCapacity(2)
+	t.Cleanup(func() {
+		closePool(impl.ldapAuthImpl)
+	})
+	return impl
+}
+
+func TestAuthLDAPSimple(t *testing.T) {
+	s := newFakeLDAPServer(t)
+	impl := newTestSimpleImpl(t, s)
+
+	// The DN is specified explicitly, relative to the base DN, or searched by the uid.
+	for _, dn := range []string{"uid=u1,ou=people,dc=example,dc=com", "+uid=u1,ou=people", ""} {
+		roles, err := impl.AuthLDAPSimple("u1", dn, []byte("secret1"))
+		require.NoError(t, err)
+		require.Empty(t, roles)
+	}
+	_, err := impl.AuthLDAPSimple("u1", "", []byte("secret2"))
+	require.Error(t, err)
+	_, err = impl.AuthLDAPSimple("u1", "", nil)
+	require.Error(t, err)
+	_, err = impl.AuthLDAPSimple("u3", "", []byte("secret1"))
+	require.Error(t, err)
+
+	// The connections are reused.
+	conns, _, _ := s.stats()
+	require.Equal(t, 1, conns)
+
+	// The groups are mapped to the roles.
+	require.Error(t, impl.SetGroupRoleMapping("dev"))
+	require.NoError(t, impl.SetGroupRoleMapping("dev=r1, ops='r2'@'localhost', qa=r3"))
+	roles, err := impl.AuthLDAPSimple("u1", "", []byte("secret1"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*auth.RoleIdentity{{Username: "r1", Hostname: "%"}, {Username: "r2", Hostname: "localhost"}}, roles)
+	roles, err = impl.AuthLDAPSimple("u2", "", []byte("secret2"))
+	require.NoError(t, err)
+	require.Equal(t, []*auth.RoleIdentity{{Username: "r2", Hostname: "localhost"}}, roles)
+
+	// The successful binds are cached.
+	impl.SetBindCacheTTL(time.Minute)
+	dn := "uid=u1,ou=people,dc=example,dc=com"
+	_, err = impl.AuthLDAPSimple("u1", dn, []byte("secret1"))
+	require.NoError(t, err)
+	_, _, binds := s.stats()
+	roles, err = impl.AuthLDAPSimple("u1", dn, []byte("secret1"))
+	require.NoError(t, err)
+	require.Len(t, roles, 2)
+	_, _, newBinds := s.stats()
+	require.Equal(t, binds, newBinds)
+	// The cache doesn't apply to a different password.
+	_, err = impl.AuthLDAPSimple("u1", dn, []byte("secret2"))
+	require.Error(t, err)
+	_, _, newBinds = s.stats()
+	require.Greater(t, newBinds, binds)
+
+	// The pool is re-created after the server changes.
+	impl.SetLDAPServerPort(s.port() + 1)
+	impl.SetLDAPServerPort(s.port())
+	_, err = impl.AuthLDAPSimple("u2", "", []byte("secret2"))
+	require.NoError(t, err)
+	conns, _, _ = s.stats()
+	require.Equal(t, 2, conns)
+}
+
+type fakeAuthConn struct {
+	received [][]byte
+	toSend   [][]byte
+}
+
+func (c *fakeAuthConn) ReadPacket() ([]byte, error) {
+	data := c.toSend[0]
+	c.toSend = c.toSend[1:]
+	return data, nil
+}
+
+func (c *fakeAuthConn) WriteAuthMoreData(data []byte) error {
+	c.received = append(c.received, data)
+	return nil
+}
+
+func (c *fakeAuthConn) Flush(ctx context.Context) error {
+	return nil
+}
+
+func TestAuthLDAPSASL(t *testing.T) {
+	s := newFakeLDAPServer(t)
+	impl := &ldapAuthImplSASL{ldapAuthImpl: newLDAPAuthImpl()}
+	impl.SetLDAPServerHost("127.0.0.1")
+	impl.SetLDAPServerPort(s.port())
+	impl.SetBindBaseDN("dc=example,dc=com")
+	impl.SetSASLAuthMethod("FAKE")
+	require.Equal(t, "FAKE", impl.GetSASLAuthMethod())
+	t.Cleanup(func() {
+		closePool(impl.ldapAuthImpl)
+	})
+
+	authConn := &fakeAuthConn{toSend: [][]byte{[]byte("world")}}
+	roles, err := impl.AuthLDAPSASL("u1", "", []byte("hello"), authConn)
+	require.NoError(t, err)
+	require.Empty(t, roles)
+	require.Equal(t, [][]byte{[]byte("challenge"), []byte("done")}, authConn.received)
+
+	authConn = &fakeAuthConn{toSend: [][]byte{[]byte("wrong")}}
+	_, err = impl.AuthLDAPSASL("u1", "", []byte("hello"), authConn)
+	require.Error(t, err)
+
+	require.NoError(t, impl.SetGroupRoleMapping("ops=r2"))
+	authConn = &fakeAuthConn{toSend: [][]byte{[]byte("world")}}
+	roles, err = impl.AuthLDAPSASL("u2", "", []byte("hello"), authConn)
+	require.NoError(t, err)
+	require.Equal(t, []*auth.RoleIdentity{{Username: "r2", Hostname: "%"}}, roles)
+}
+
+func createCert(t *testing.T, dir string) (certPath, keyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ldap"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certPath = filepath.Join(dir, "cert.pem")
+	keyPath = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), 0600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0600))
+	return
+}
+
+func TestStartTLS(t *testing.T) {
+	s := newFakeLDAPServer(t)
+	certPath, keyPath := createCert(t, t.TempDir())
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	require.NoError(t, err)
+	s.tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	impl := newTestSimpleImpl(t, s)
+	impl.SetEnableTLS(true)
+	// The certificate of the server isn't trusted by the system.
+	_, err = impl.AuthLDAPSimple("u1", "uid=u1,ou=people,dc=example,dc=com", []byte("secret1"))
+	require.Error(t, err)
+
+	require.Error(t, impl.SetCAPath(filepath.Join(t.TempDir(), "not-exist.pem")))
+	require.Error(t, impl.SetCAPath(keyPath))
+	require.NoError(t, impl.SetCAPath(certPath))
+	_, err = impl.AuthLDAPSimple("u1", "uid=u1,ou=people,dc=example,dc=com", []byte("secret1"))
+	require.NoError(t, err)
+	_, tlsConns, _ := s.stats()
+	require.Equal(t, 1, tlsConns)
+
+	saslImpl := &ldapAuthImplSASL{ldapAuthImpl: newLDAPAuthImpl()}
+	saslImpl.SetLDAPServerHost("127.0.0.1")
+	saslImpl.SetLDAPServerPort(s.port())
+	saslImpl.SetEnableTLS(true)
+	require.NoError(t, saslImpl.SetCAPath(certPath))
+	_, err = saslImpl.AuthLDAPSASL("u1", "", []byte("hello"), &fakeAuthConn{toSend: [][]byte{[]byte("world")}})
+	require.NoError(t, err)
+	_, tlsConns, _ = s.stats()
+	require.Equal(t, 2, tlsConns)
+}
+
+func TestParseGroupRoleMapping(t *testing.T) {
+	mapping, err := ParseGroupRoleMapping("")
+	require.NoError(t, err)
+	require.Empty(t, mapping)
+
+	mapping, err = ParseGroupRoleMapping("g1=r1,g1=`r2`@`%`, g2 = \"r3\"@localhost,")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]*auth.RoleIdentity{
+		"g1": {{Username: "r1", Hostname: "%"}, {Username: "r2", Hostname: "%"}},
+		"g2": {{Username: "r3", Hostname: "localhost"}},
+	}, mapping)
+
+	for _, s := range []string{"g1", "=r1", "g1=", "g1=@%"} {
+		_, err = ParseGroupRoleMapping(s)
+		require.Error(t, err, s)
+	}
+}
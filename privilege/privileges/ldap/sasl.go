@@ -0,0 +1,140 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"context"
+	"net"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/privilege/conn"
+)
+
+type ldapAuthImplSASL struct {
+	*ldapAuthImpl
+
+	saslAuthMethod string
+}
+
+// LDAPSASLAuthImpl is the implementation of the `authentication_ldap_sasl` plugin.
+var LDAPSASLAuthImpl = &ldapAuthImplSASL{
+	ldapAuthImpl:   newLDAPAuthImpl(),
+	saslAuthMethod: "SCRAM-SHA-1",
+}
+
+// saslBind sends a SASL bind request and returns the result code and the credentials of the server.
+func saslBind(c net.Conn, messageID int64, mechanism string, credentials []byte) (uint16, []byte, error) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Request")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	request := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationBindRequest, nil, "Bind Request")
+	request.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, 3, "Version"))
+	// The identity is carried by the SASL credentials, so the name is empty, see RFC 4513.
+	request.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "User Name"))
+	saslAuth := ber.Encode(ber.ClassContext, ber.TypeConstructed, 3, nil, "SASL Authentication")
+	saslAuth.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, mechanism, "SASL Mechanism"))
+	saslAuth.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, string(credentials), "SASL Credentials"))
+	request.AppendChild(saslAuth)
+	packet.AppendChild(request)
+	if _, err := c.Write(packet.Bytes()); err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+
+	response, err := ber.ReadPacket(c)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	if len(response.Children) < 2 || len(response.Children[1].Children) < 3 {
+		return 0, nil, errors.New("invalid LDAP bind response")
+	}
+	bindResponse := response.Children[1]
+	resultCode, ok := bindResponse.Children[0].Value.(int64)
+	if !ok {
+		return 0, nil, errors.New("invalid LDAP bind response")
+	}
+	if resultCode != ldap.LDAPResultSuccess && resultCode != ldap.LDAPResultSaslBindInProgress {
+		return uint16(resultCode), nil, errors.Trace(ldap.GetLDAPError(response))
+	}
+	var serverCredentials []byte
+	for _, child := range bindResponse.Children[3:] {
+		// serverSaslCreds [7] OCTET STRING OPTIONAL
+		if child.ClassType == ber.ClassContext && child.Tag == 7 {
+			serverCredentials = child.Data.Bytes()
+		}
+	}
+	return uint16(resultCode), serverCredentials, nil
+}
+
+// AuthLDAPSASL authenticates the user by relaying the SASL messages between the client and the LDAP server,
+// it returns the roles mapped from the groups of the user. The DN is only used to search the groups, and
+// it's searched by the user name if it's empty.
+func (impl *ldapAuthImplSASL) AuthLDAPSASL(userName, dn string, clientCredentials []byte, authConn conn.AuthConn) ([]*auth.RoleIdentity, error) {
+	// The SASL exchange is stateful, so it uses a dedicated connection instead of the pool.
+	c, err := impl.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	mechanism := impl.GetSASLAuthMethod()
+	// The message ID 1 may be used by StartTLS.
+	for messageID := int64(2); ; messageID++ {
+		resultCode, serverCredentials, err := saslBind(c, messageID, mechanism, clientCredentials)
+		if err != nil {
+			return nil, err
+		}
+		if len(serverCredentials) > 0 {
+			if err = authConn.WriteAuthMoreData(serverCredentials); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if err = authConn.Flush(context.Background()); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if resultCode == ldap.LDAPResultSuccess {
+			break
+		}
+		if clientCredentials, err = authConn.ReadPacket(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	impl.RLock()
+	hasGroupRoleMapping := len(impl.groupRoleMapping) > 0
+	impl.RUnlock()
+	if !hasGroupRoleMapping {
+		return nil, nil
+	}
+	if dn, err = impl.canonicalizeDN(userName, dn); err != nil {
+		return nil, err
+	}
+	return impl.searchGroupRoles(userName, dn)
+}
+
+// GetSASLAuthMethod returns the SASL mechanism used to authenticate, it's sent to the client at the beginning.
+func (impl *ldapAuthImplSASL) GetSASLAuthMethod() string {
+	impl.RLock()
+	defer impl.RUnlock()
+	return impl.saslAuthMethod
+}
+
+// SetSASLAuthMethod updates the SASL mechanism used to authenticate.
+func (impl *ldapAuthImplSASL) SetSASLAuthMethod(saslAuthMethod string) {
+	impl.Lock()
+	defer impl.Unlock()
+	impl.saslAuthMethod = saslAuthMethod
+}
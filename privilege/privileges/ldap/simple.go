@@ -0,0 +1,110 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/auth"
+)
+
+// bindCacheEntry records a successful bind of the `authentication_ldap_simple` plugin.
+type bindCacheEntry struct {
+	pwdHash [sha256.Size]byte
+	roles   []*auth.RoleIdentity
+	expire  time.Time
+}
+
+type ldapAuthImplSimple struct {
+	*ldapAuthImpl
+
+	bindCacheTTL time.Duration
+	// bindCache maps the DN to the last successful bind, so that the LDAP server
+	// isn't requested again if the user logs in with the same password before it expires.
+	bindCache map[string]bindCacheEntry
+}
+
+// LDAPSimpleAuthImpl is the implementation of the `authentication_ldap_simple` plugin.
+var LDAPSimpleAuthImpl = &ldapAuthImplSimple{
+	ldapAuthImpl: newLDAPAuthImpl(),
+	bindCache:    make(map[string]bindCacheEntry),
+}
+
+func hashBindPassword(dn string, pwd []byte) [sha256.Size]byte {
+	data := make([]byte, 0, len(dn)+1+len(pwd))
+	data = append(data, dn...)
+	data = append(data, 0)
+	data = append(data, pwd...)
+	return sha256.Sum256(data)
+}
+
+// AuthLDAPSimple authenticates the user by binding the DN with the password, it returns the roles mapped
+// from the groups of the user. The DN is searched by the user name if it's empty.
+func (impl *ldapAuthImplSimple) AuthLDAPSimple(userName, dn string, pwd []byte) ([]*auth.RoleIdentity, error) {
+	// Binding with an empty password is an unauthenticated bind, which always succeeds.
+	if len(pwd) == 0 {
+		return nil, errors.New("LDAP authentication requires a non-empty password")
+	}
+	dn, err := impl.canonicalizeDN(userName, dn)
+	if err != nil {
+		return nil, err
+	}
+
+	pwdHash := hashBindPassword(dn, pwd)
+	impl.RLock()
+	entry, ok := impl.bindCache[dn]
+	impl.RUnlock()
+	if ok && entry.pwdHash == pwdHash && time.Now().Before(entry.expire) {
+		return entry.roles, nil
+	}
+
+	if err = impl.bind(dn, string(pwd)); err != nil {
+		return nil, err
+	}
+	roles, err := impl.searchGroupRoles(userName, dn)
+	if err != nil {
+		return nil, err
+	}
+
+	impl.Lock()
+	defer impl.Unlock()
+	if impl.bindCacheTTL > 0 {
+		impl.bindCache[dn] = bindCacheEntry{
+			pwdHash: pwdHash,
+			roles:   roles,
+			expire:  time.Now().Add(impl.bindCacheTTL),
+		}
+	}
+	return roles, nil
+}
+
+func (impl *ldapAuthImplSimple) bind(dn, pwd string) error {
+	pool, conn, err := impl.getConnection()
+	if err != nil {
+		return err
+	}
+	defer impl.putConnection(pool, conn)
+	return errors.Trace(conn.Bind(dn, pwd))
+}
+
+// SetBindCacheTTL updates how long a successful bind is cached, 0 means the cache is disabled.
+func (impl *ldapAuthImplSimple) SetBindCacheTTL(ttl time.Duration) {
+	impl.Lock()
+	defer impl.Unlock()
+	impl.bindCacheTTL = ttl
+	impl.bindCache = make(map[string]bindCacheEntry)
+}
@@ -21,13 +21,17 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/infoschema/perfschema"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/conn"
+	"github.com/pingcap/tidb/privilege/privileges/ldap"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
@@ -47,6 +51,14 @@ var dynamicPrivs = []string{
 	"ROLE_ADMIN",
 	"CONNECTION_ADMIN",
 	"PLACEMENT_ADMIN",                 // Can Create/Drop/Alter PLACEMENT POLICY
+	"RESOURCE_GROUP_ADMIN",            // Can Create/Drop/Alter RESOURCE GROUP
+	"RESOURCE_GROUP_USER",             // Can bind the sessions to RESOURCE GROUP
+	"DDL_ADMIN",                       // Can show/cancel DDL jobs, repair tables and clean up table locks by ADMIN statements
+	"TABLE_CHECK_ADMIN",               // Can check/checksum tables and recover/clean up indices by ADMIN statements
+	"OPTIMIZER_ADMIN",                 // Can manage global bindings and reload the optimizer blacklists and statistics
+	"PLUGIN_ADMIN",                    // Can enable/disable plugins by ADMIN statements
+	"DIAGNOSTICS_ADMIN",               // Can show the slow queries and the telemetry by ADMIN statements
+	"ENCRYPTION_KEY_ADMIN",            // Can rotate the master keys of the encryption at rest
 	"DASHBOARD_CLIENT",                // Can login to the TiDB-Dashboard.
 	"RESTRICTED_TABLES_ADMIN",         // Can see system tables when SEM is enabled
 	"RESTRICTED_STATUS_ADMIN",         // Can see all status vars when SEM is enabled.
@@ -107,6 +119,19 @@ func (p *UserPrivileges) RequestDynamicVerification(activeRoles []*auth.RoleIden
 	return mysqlPriv.RequestDynamicVerification(activeRoles, p.user, p.host, privName, grantable)
 }
 
+// RequestProxyVerification implements the Manager interface.
+func (p *UserPrivileges) RequestProxyVerification(activeRoles []*auth.RoleIdentity, proxiedUser *auth.UserIdentity, grantable bool) bool {
+	if SkipWithGrant {
+		return true
+	}
+	if p.user == "" && p.host == "" {
+		return true
+	}
+
+	mysqlPriv := p.Handle.Get()
+	return mysqlPriv.RequestProxyVerification(activeRoles, p.user, p.host, proxiedUser.Username, proxiedUser.Hostname, grantable)
+}
+
 // RequestVerification implements the Manager interface.
 func (p *UserPrivileges) RequestVerification(activeRoles []*auth.RoleIdentity, db, table, column string, priv mysql.PrivilegeType) bool {
 	if SkipWithGrant {
@@ -202,7 +227,7 @@ func (p *UserPrivileges) isValidHash(record *UserRecord) bool {
 		return false
 	}
 
-	if record.AuthPlugin == mysql.AuthCachingSha2Password {
+	if record.AuthPlugin == mysql.AuthCachingSha2Password || record.AuthPlugin == mysql.AuthTiDBSM3Password {
 		if len(pwd) == mysql.SHAPWDHashLen {
 			return true
 		}
@@ -210,7 +235,8 @@ func (p *UserPrivileges) isValidHash(record *UserRecord) bool {
 		return false
 	}
 
-	if record.AuthPlugin == mysql.AuthSocket {
+	// The authentication string is the DN of the user for LDAP plugins.
+	if record.AuthPlugin == mysql.AuthSocket || record.AuthPlugin == mysql.AuthLDAPSimple || record.AuthPlugin == mysql.AuthLDAPSASL {
 		return true
 	}
 
@@ -246,8 +272,9 @@ func (p *UserPrivileges) GetAuthPlugin(user, host string) (string, error) {
 	}
 	// zero-length auth string means no password for native and caching_sha2 auth.
 	// but for auth_socket it means there should be a 1-to-1 mapping between the TiDB user
-	// and the OS user.
-	if record.AuthenticationString == "" && record.AuthPlugin != mysql.AuthSocket {
+	// and the OS user, and for LDAP plugins it means the DN is searched by the user name.
+	if record.AuthenticationString == "" && record.AuthPlugin != mysql.AuthSocket &&
+		record.AuthPlugin != mysql.AuthLDAPSimple && record.AuthPlugin != mysql.AuthLDAPSASL {
 		return "", nil
 	}
 	if p.isValidHash(record) {
@@ -256,6 +283,32 @@ func (p *UserPrivileges) GetAuthPlugin(user, host string) (string, error) {
 	return "", errors.New("Failed to get plugin for user")
 }
 
+// GetUserResourceGroup implements the Manager interface.
+func (p *UserPrivileges) GetUserResourceGroup(user, host string) string {
+	if SkipWithGrant {
+		return ""
+	}
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.connectionVerification(user, host)
+	if record == nil {
+		return ""
+	}
+	return record.ResourceGroup
+}
+
+// GetUserMaxConnections implements the Manager interface.
+func (p *UserPrivileges) GetUserMaxConnections(user, host string) int64 {
+	if SkipWithGrant {
+		return 0
+	}
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.connectionVerification(user, host)
+	if record == nil {
+		return 0
+	}
+	return record.MaxUserConnections
+}
+
 // MatchIdentity implements the Manager interface.
 func (p *UserPrivileges) MatchIdentity(user, host string, skipNameResolve bool) (u string, h string, success bool) {
 	if SkipWithGrant {
@@ -293,7 +346,7 @@ func (p *UserPrivileges) GetAuthWithoutVerification(user, host string) (success
 }
 
 // ConnectionVerification implements the Manager interface.
-func (p *UserPrivileges) ConnectionVerification(user, host string, authentication, salt []byte, tlsState *tls.ConnectionState) (success bool) {
+func (p *UserPrivileges) ConnectionVerification(user, host string, authentication, salt []byte, tlsState *tls.ConnectionState, authConn conn.AuthConn) (success bool, passwordExpired bool) {
 	if SkipWithGrant {
 		p.user = user
 		p.host = host
@@ -328,22 +381,53 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 		return
 	}
 
-	pwd := record.AuthenticationString
 	if !p.isValidHash(record) {
 		return
 	}
 
+	if isLockedByFailedLogins(record) {
+		logutil.BgLogger().Error("try to login an account locked due to consecutive failed logins",
+			zap.String("user", user), zap.String("host", host))
+		return
+	}
+	if !p.verifyAuthentication(user, host, record, authentication, salt, authConn) {
+		trackFailedLogin(record)
+		return
+	}
+	ResetFailedLogins(record.User, record.Host)
+
+	if isPasswordExpired(record) {
+		logutil.BgLogger().Info("login with an expired password",
+			zap.String("user", user), zap.String("host", host))
+		passwordExpired = true
+	}
+
+	p.user = user
+	p.host = record.Host
+	success = true
+	return
+}
+
+// verifyAuthentication checks the authentication data of the client against the account.
+func (p *UserPrivileges) verifyAuthentication(user, host string, record *UserRecord, authentication, salt []byte, authConn conn.AuthConn) bool {
+	if record.AuthPlugin == mysql.AuthLDAPSimple || record.AuthPlugin == mysql.AuthLDAPSASL {
+		if err := p.authLDAP(user, record, authentication, authConn); err != nil {
+			logutil.BgLogger().Warn("LDAP authentication failed", zap.String("user", user),
+				zap.String("host", host), zap.String("plugin", record.AuthPlugin), zap.Error(err))
+			return false
+		}
+		return true
+	}
+
+	pwd := record.AuthenticationString
 	// empty password
 	if len(pwd) == 0 && len(authentication) == 0 {
-		p.user = user
-		p.host = record.Host
-		success = true
-		return
+		return true
 	}
 
 	if len(pwd) == 0 || len(authentication) == 0 {
 		if record.AuthPlugin != mysql.AuthSocket {
-			return
+			return false
 		}
 	}
 
@@ -351,11 +435,11 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 		hpwd, err := auth.DecodePassword(pwd)
 		if err != nil {
 			logutil.BgLogger().Error("decode password string failed", zap.Error(err))
-			return
+			return false
 		}
 
 		if !auth.CheckScrambledPassword(salt, hpwd, authentication) {
-			return
+			return false
 		}
 	} else if record.AuthPlugin == mysql.AuthCachingSha2Password {
 		authok, err := auth.CheckShaPassword([]byte(pwd), string(authentication))
@@ -364,24 +448,144 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 		}
 
 		if !authok {
-			return
+			return false
+		}
+	} else if record.AuthPlugin == mysql.AuthTiDBSM3Password {
+		authok, err := auth.CheckSM3Password([]byte(pwd), string(authentication))
+		if err != nil {
+			logutil.BgLogger().Error("Failed to check tidb_sm3_password", zap.Error(err))
+		}
+
+		if !authok {
+			return false
 		}
 	} else if record.AuthPlugin == mysql.AuthSocket {
 		if string(authentication) != user && string(authentication) != pwd {
 			logutil.BgLogger().Error("Failed socket auth", zap.String("user", user),
 				zap.String("socket_user", string(authentication)),
 				zap.String("authentication_string", pwd))
-			return
+			return false
 		}
 	} else {
 		logutil.BgLogger().Error("unknown authentication plugin", zap.String("user", user), zap.String("plugin", record.AuthPlugin))
+		return false
+	}
+	return true
+}
+
+// isPasswordExpired returns whether the password of the account is expired manually or by its lifetime.
+func isPasswordExpired(record *UserRecord) bool {
+	// The passwords of the other plugins are not managed by TiDB.
+	if record.AuthPlugin != mysql.AuthNativePassword && record.AuthPlugin != mysql.AuthCachingSha2Password &&
+		record.AuthPlugin != mysql.AuthTiDBSM3Password {
+		return false
+	}
+	if record.PasswordExpired {
+		return true
+	}
+	lifetime := record.PasswordLifetime
+	if lifetime < 0 {
+		lifetime = int64(variable.DefaultPasswordLifetimeValue.Load())
+	}
+	if lifetime == 0 || record.PasswordLastChanged == 0 {
+		return false
+	}
+	return time.Now().Unix() >= record.PasswordLastChanged+lifetime*int64(24*time.Hour/time.Second)
+}
+
+// failedLogins tracks the consecutive failed logins of the accounts with FAILED_LOGIN_ATTEMPTS and
+// PASSWORD_LOCK_TIME, the key is user@host of the account. Like MySQL, the state is kept in the memory
+// of each server, so it's reset when the server restarts.
+var failedLogins struct {
+	sync.Mutex
+	records map[string]*failedLoginRecord
+}
+
+type failedLoginRecord struct {
+	// attempts and lockTime are the settings of the account when the record is created,
+	// the record is discarded once the settings are altered.
+	attempts int64
+	lockTime int64
+	count    int64
+	// lockedAt is zero if the account isn't locked.
+	lockedAt time.Time
+}
+
+// isLockedByFailedLogins returns whether the account is locked temporarily due to the consecutive failed logins.
+func isLockedByFailedLogins(record *UserRecord) bool {
+	key := record.User + "@" + record.Host
+	failedLogins.Lock()
+	defer failedLogins.Unlock()
+	r, ok := failedLogins.records[key]
+	if !ok {
+		return false
+	}
+	if r.attempts != record.FailedLoginAttempts || r.lockTime != record.PasswordLockTime {
+		delete(failedLogins.records, key)
+		return false
+	}
+	if r.lockedAt.IsZero() {
+		return false
+	}
+	if r.lockTime < 0 || time.Since(r.lockedAt) < time.Duration(r.lockTime)*24*time.Hour {
+		return true
+	}
+	// The lock is expired, and the account can try again.
+	delete(failedLogins.records, key)
+	return false
+}
+
+// trackFailedLogin records a failed login of the account, and locks the account
+// once the consecutive failed logins reach FAILED_LOGIN_ATTEMPTS.
+func trackFailedLogin(record *UserRecord) {
+	if record.FailedLoginAttempts == 0 || record.PasswordLockTime == 0 {
 		return
 	}
+	key := record.User + "@" + record.Host
+	failedLogins.Lock()
+	defer failedLogins.Unlock()
+	r, ok := failedLogins.records[key]
+	if !ok || r.attempts != record.FailedLoginAttempts || r.lockTime != record.PasswordLockTime {
+		r = &failedLoginRecord{attempts: record.FailedLoginAttempts, lockTime: record.PasswordLockTime}
+		if failedLogins.records == nil {
+			failedLogins.records = make(map[string]*failedLoginRecord)
+		}
+		failedLogins.records[key] = r
+	}
+	r.count++
+	if r.count >= r.attempts {
+		r.lockedAt = time.Now()
+		logutil.BgLogger().Warn("the account is locked due to consecutive failed logins",
+			zap.String("user", record.User), zap.String("host", record.Host),
+			zap.Int64("failed logins", r.count), zap.Int64("lock days", r.lockTime))
+	}
+}
 
-	p.user = user
-	p.host = record.Host
-	success = true
-	return
+// ResetFailedLogins clears the consecutive failed logins of the account, it's called when
+// the account logins successfully or it's unlocked by ALTER USER ... ACCOUNT UNLOCK.
+func ResetFailedLogins(user, host string) {
+	failedLogins.Lock()
+	defer failedLogins.Unlock()
+	delete(failedLogins.records, user+"@"+host)
+}
+
+// authLDAP authenticates the user through the LDAP server, and records the roles mapped from the LDAP groups.
+func (p *UserPrivileges) authLDAP(user string, record *UserRecord, authentication []byte, authConn conn.AuthConn) error {
+	var roles []*auth.RoleIdentity
+	var err error
+	if record.AuthPlugin == mysql.AuthLDAPSimple {
+		roles, err = ldap.LDAPSimpleAuthImpl.AuthLDAPSimple(user, record.AuthenticationString, authentication)
+	} else {
+		if authConn == nil {
+			return errors.New("the connection doesn't support the SASL authentication")
+		}
+		roles, err = ldap.LDAPSASLAuthImpl.AuthLDAPSASL(user, record.AuthenticationString, authentication, authConn)
+	}
+	if err != nil {
+		return err
+	}
+	setLDAPGroupRoles(record.User, record.Host, roles)
+	return nil
 }
 
 type checkResult int
@@ -507,6 +711,8 @@ func checkCertSAN(priv *globalPrivRecord, cert *x509.Certificate, sans map[util.
 			for _, ip := range cert.IPAddresses {
 				given = append(given, ip.String())
 			}
+		case util.EmailAddress:
+			given = cert.EmailAddresses
 		default:
 			unsupported = true
 		}
@@ -525,7 +731,7 @@ func checkCertSAN(priv *globalPrivRecord, cert *x509.Certificate, sans map[util.
 			}
 		}
 		if !givenMatchOne {
-			logutil.BgLogger().Info("ssl check failure for subject", zap.String("user", priv.User), zap.String("host", priv.Host),
+			logutil.BgLogger().Info("ssl check failure for SAN", zap.String("user", priv.User), zap.String("host", priv.Host),
 				zap.String("require", priv.Priv.SAN), zap.Strings("given", given), zap.String("type", string(typ)))
 			r = false
 			return
@@ -628,6 +834,19 @@ func (p *UserPrivileges) GetDefaultRoles(user, host string) []*auth.RoleIdentity
 	return ret
 }
 
+// GetLoginRoles implements the Manager interface.
+func (p *UserPrivileges) GetLoginRoles(user, host string) []*auth.RoleIdentity {
+	if variable.ActivateAllRolesOnLoginValue.Load() {
+		return p.GetAllRoles(user, host)
+	}
+	if SkipWithGrant {
+		return p.GetDefaultRoles(user, host)
+	}
+	// The roles mapped from the LDAP groups are always activated at login.
+	mysqlPrivilege := p.Handle.Get()
+	return mysqlPrivilege.appendLDAPGroupRoles(user, host, p.GetDefaultRoles(user, host))
+}
+
 // GetAllRoles return all roles of user.
 func (p *UserPrivileges) GetAllRoles(user, host string) []*auth.RoleIdentity {
 	if SkipWithGrant {
@@ -21,11 +21,15 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
@@ -724,6 +728,8 @@ func TestCheckCertBasedAuth(t *testing.T) {
 	mustExec(t, se, "UPDATE mysql.global_priv set priv = 'abc' where `user` = 'r13_broken_user' and `host` = 'localhost'")
 	mustExec(t, se, `CREATE USER 'r14_san_only_pass'@'localhost' require san 'URI:spiffe://mesh.pingcap.com/ns/timesh/sa/me1'`)
 	mustExec(t, se, `CREATE USER 'r15_san_only_fail'@'localhost' require san 'URI:spiffe://mesh.pingcap.com/ns/timesh/sa/me2'`)
+	mustExec(t, se, `CREATE USER 'r16_san_email_pass'@'localhost' require san 'EMAIL:tester1@pingcap.com'`)
+	mustExec(t, se, `CREATE USER 'r17_san_email_fail'@'localhost' require san 'EMAIL:tester2@pingcap.com'`)
 
 	defer func() {
 		require.True(t, se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
@@ -742,6 +748,8 @@ func TestCheckCertBasedAuth(t *testing.T) {
 		mustExec(t, se, "drop user 'r13_broken_user'@'localhost'")
 		mustExec(t, se, "drop user 'r14_san_only_pass'@'localhost'")
 		mustExec(t, se, "drop user 'r15_san_only_fail'@'localhost'")
+		mustExec(t, se, "drop user 'r16_san_email_pass'@'localhost'")
+		mustExec(t, se, "drop user 'r17_san_email_fail'@'localhost'")
 	}()
 
 	// test without ssl or ca
@@ -794,6 +802,7 @@ func TestCheckCertBasedAuth(t *testing.T) {
 			err := url.UnmarshalBinary([]byte("spiffe://mesh.pingcap.com/ns/timesh/sa/me1"))
 			require.NoError(t, err)
 			cert.URIs = append(cert.URIs, &url)
+			cert.EmailAddresses = append(cert.EmailAddresses, "tester1@pingcap.com")
 		})
 	require.True(t, se.Auth(&auth.UserIdentity{Username: "r1", Hostname: "localhost"}, nil, nil))
 	require.True(t, se.Auth(&auth.UserIdentity{Username: "r2", Hostname: "localhost"}, nil, nil))
@@ -801,6 +810,8 @@ func TestCheckCertBasedAuth(t *testing.T) {
 	require.True(t, se.Auth(&auth.UserIdentity{Username: "r4", Hostname: "localhost"}, nil, nil))
 	require.True(t, se.Auth(&auth.UserIdentity{Username: "r5", Hostname: "localhost"}, nil, nil))
 	require.True(t, se.Auth(&auth.UserIdentity{Username: "r14_san_only_pass", Hostname: "localhost"}, nil, nil))
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "r16_san_email_pass", Hostname: "localhost"}, nil, nil))
+	require.False(t, se.Auth(&auth.UserIdentity{Username: "r17_san_email_fail", Hostname: "localhost"}, nil, nil))
 
 	// test require but give nothing
 	se.GetSessionVars().TLSConnectionState = nil
@@ -1413,17 +1424,57 @@ func TestAdminCommand(t *testing.T) {
 
 	require.True(t, se.Auth(&auth.UserIdentity{Username: "test_admin", Hostname: "localhost"}, nil, nil))
 	_, err := se.ExecuteInternal(context.Background(), "ADMIN SHOW DDL JOBS")
-	require.Error(t, err)
-	require.True(t, terror.ErrorEqual(err, core.ErrPrivilegeCheckFail))
+	require.EqualError(t, err, "[planner:1227]Access denied; you need (at least one of) the SUPER or DDL_ADMIN privilege(s) for this operation")
 	_, err = se.ExecuteInternal(context.Background(), "ADMIN CHECK TABLE t")
-	require.Error(t, err)
-	require.True(t, terror.ErrorEqual(err, core.ErrPrivilegeCheckFail))
+	require.EqualError(t, err, "[planner:1227]Access denied; you need (at least one of) the SUPER or TABLE_CHECK_ADMIN privilege(s) for this operation")
 
 	require.True(t, se.Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil))
 	_, err = se.ExecuteInternal(context.Background(), "ADMIN SHOW DDL JOBS")
 	require.NoError(t, err)
 }
 
+func TestAdminDynamicPrivs(t *testing.T) {
+	store, clean := newStore(t)
+	defer clean()
+
+	rootSe := newSession(t, store, dbName)
+	mustExec(t, rootSe, "CREATE USER notsuper")
+	mustExec(t, rootSe, "CREATE TABLE t(a int primary key, b int, index idx(b))")
+	mustExec(t, rootSe, "GRANT SELECT ON t TO notsuper")
+
+	se := newSession(t, store, dbName)
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "notsuper", Hostname: "%"}, nil, nil))
+	tests := []struct {
+		priv  string
+		stmts []string
+	}{
+		{"DDL_ADMIN", []string{"ADMIN SHOW DDL", "ADMIN SHOW DDL JOBS", "ADMIN SHOW DDL JOB QUERIES 1", "ADMIN SHOW t NEXT_ROW_ID", "ADMIN CLEANUP TABLE LOCK t"}},
+		{"TABLE_CHECK_ADMIN", []string{"ADMIN CHECK TABLE t", "ADMIN CHECK INDEX t idx", "ADMIN CHECKSUM TABLE t", "ADMIN RECOVER INDEX t idx", "ADMIN CLEANUP INDEX t idx"}},
+		{"OPTIMIZER_ADMIN", []string{"ADMIN RELOAD EXPR_PUSHDOWN_BLACKLIST", "ADMIN RELOAD OPT_RULE_BLACKLIST", "ADMIN FLUSH BINDINGS", "ADMIN RELOAD BINDINGS",
+			"CREATE GLOBAL BINDING FOR SELECT * FROM t USING SELECT * FROM t USE INDEX(idx)", "DROP GLOBAL BINDING FOR SELECT * FROM t"}},
+		{"DIAGNOSTICS_ADMIN", []string{"ADMIN SHOW SLOW RECENT 1", "ADMIN SHOW TELEMETRY"}},
+	}
+	for _, test := range tests {
+		for _, stmt := range test.stmts {
+			_, err := se.ExecuteInternal(context.Background(), stmt)
+			require.EqualError(t, err, fmt.Sprintf("[planner:1227]Access denied; you need (at least one of) the SUPER or %s privilege(s) for this operation", test.priv), stmt)
+		}
+		mustExec(t, rootSe, fmt.Sprintf("GRANT %s ON *.* TO notsuper", test.priv))
+		for _, stmt := range test.stmts {
+			mustExec(t, se, stmt)
+		}
+		mustExec(t, rootSe, fmt.Sprintf("REVOKE %s ON *.* FROM notsuper", test.priv))
+	}
+
+	// No plugin is loaded and no TLS is configured in the test, so only the access denied errors are checked.
+	_, err := se.ExecuteInternal(context.Background(), "ADMIN PLUGINS ENABLE audit")
+	require.EqualError(t, err, "[planner:1227]Access denied; you need (at least one of) the SUPER or PLUGIN_ADMIN privilege(s) for this operation")
+	_, err = se.ExecuteInternal(context.Background(), "ALTER INSTANCE RELOAD TLS")
+	require.EqualError(t, err, "[planner:1227]Access denied; you need (at least one of) the SUPER or CONNECTION_ADMIN privilege(s) for this operation")
+	_, err = se.ExecuteInternal(context.Background(), "ALTER INSTANCE ROTATE MASTER KEY")
+	require.EqualError(t, err, "[planner:1227]Access denied; you need (at least one of) the SUPER or ENCRYPTION_KEY_ADMIN privilege(s) for this operation")
+}
+
 func TestTableNotExistNoPermissions(t *testing.T) {
 	store, clean := newStore(t)
 	defer clean()
@@ -1518,6 +1569,19 @@ func TestSelectIntoNoPermissions(t *testing.T) {
 	require.True(t, terror.ErrorEqual(err, core.ErrSpecificAccessDenied))
 }
 
+func TestCreateExternalTableNoPermissions(t *testing.T) {
+	store, clean := newStore(t)
+	defer clean()
+
+	se := newSession(t, store, dbName)
+	mustExec(t, se, `CREATE USER 'nofile'@'localhost';`)
+	mustExec(t, se, `GRANT CREATE ON test.* TO 'nofile'@'localhost';`)
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "nofile", Hostname: "localhost"}, nil, nil))
+	_, err := se.ExecuteInternal(context.Background(), `create external table test.ext (a int) location '/tmp/doesntmatter-no-permissions' format csv`)
+	require.Error(t, err)
+	require.True(t, terror.ErrorEqual(err, core.ErrSpecificAccessDenied))
+}
+
 func TestGetEncodedPassword(t *testing.T) {
 	store, clean := newStore(t)
 	defer clean()
@@ -2970,3 +3034,442 @@ func TestIssue29823(t *testing.T) {
 	err = tk2.QueryToErr("show tables from test")
 	require.EqualError(t, err, "[executor:1044]Access denied for user 'u1'@'%' to database 'test'")
 }
+
+func TestMandatoryRoles(t *testing.T) {
+	store, clean := newStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create user u1")
+	tk.MustExec("create role r1, r2")
+	tk.MustExec("create table t1 (c1 int)")
+	tk.MustExec("grant select on t1 to r1")
+	tk.MustExec("grant insert on t1 to r2")
+	tk.MustExec("grant r2 to u1")
+	require.Error(t, tk.ExecToErr("set global mandatory_roles = 'r1@'"))
+	tk.MustExec("set global mandatory_roles = 'r1, rnotexist'")
+	defer tk.MustExec("set global mandatory_roles = ''")
+	tk.MustQuery("select @@global.mandatory_roles").Check(testkit.Rows("r1, rnotexist"))
+
+	// The mandatory roles are granted to all the users, but they are not active by default.
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk2.MustQuery("select current_role()").Check(testkit.Rows("NONE"))
+	require.Error(t, tk2.ExecToErr("select * from test.t1"))
+	tk2.MustExec("set role r1")
+	tk2.MustQuery("select * from test.t1").Check(testkit.Rows())
+	tk2.MustExec("set role all")
+	tk2.MustQuery("select current_role()").Check(testkit.Rows("`r1`@`%`,`r2`@`%`"))
+	require.Error(t, tk2.ExecToErr("set role rnotexist"))
+
+	// The mandatory roles can be the default roles.
+	tk.MustExec("set default role r1 to u1")
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk2.MustQuery("select current_role()").Check(testkit.Rows("`r1`@`%`"))
+
+	// All the granted roles including the mandatory roles are activated on login.
+	tk.MustExec("set default role none to u1")
+	tk.MustExec("set global activate_all_roles_on_login = on")
+	defer tk.MustExec("set global activate_all_roles_on_login = off")
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk2.MustQuery("select current_role()").Check(testkit.Rows("`r1`@`%`,`r2`@`%`"))
+	tk.MustExec("set global mandatory_roles = ''")
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk2.MustQuery("select current_role()").Check(testkit.Rows("`r2`@`%`"))
+}
+
+// startFakeLDAPServer starts an LDAP server which only accepts the simple bind of the user with the DN and password,
+// and regards the user as a member of the groups.
+func startFakeLDAPServer(t *testing.T, dn, pwd string, groups ...string) (port int, binds func() int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	var mu sync.Mutex
+	var conns []net.Conn
+	var bindCount int
+	var wg sync.WaitGroup
+	serve := func(c net.Conn) {
+		defer c.Close()
+		for {
+			request, err := ber.ReadPacket(c)
+			if err != nil {
+				return
+			}
+			messageID, op := request.Children[0].Value.(int64), request.Children[1]
+			newResult := func(application ber.Tag, code int64) *ber.Packet {
+				packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+				packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+				result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, application, nil, "Result")
+				result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+				result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+				result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+				packet.AppendChild(result)
+				return packet
+			}
+			var responses []*ber.Packet
+			switch op.Tag {
+			case ldap.ApplicationBindRequest:
+				mu.Lock()
+				bindCount++
+				mu.Unlock()
+				code := int64(ldap.LDAPResultInvalidCredentials)
+				if name := op.Children[1].Value.(string); name == "" || name == dn && op.Children[2].Data.String() == pwd {
+					code = ldap.LDAPResultSuccess
+				}
+				responses = append(responses, newResult(ldap.ApplicationBindResponse, code))
+			case ldap.ApplicationSearchRequest:
+				for _, group := range groups {
+					packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+					packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+					entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+					entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "cn="+group+",dc=example,dc=com", "DN"))
+					attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+					attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+					attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "cn", "Type"))
+					values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+					values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, group, "Value"))
+					attribute.AppendChild(values)
+					attributes.AppendChild(attribute)
+					entry.AppendChild(attributes)
+					packet.AppendChild(entry)
+					responses = append(responses, packet)
+				}
+				responses = append(responses, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess))
+			default:
+				return
+			}
+			for _, response := range responses {
+				if _, err = c.Write(response.Bytes()); err != nil {
+					return
+				}
+			}
+		}
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			c, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, c)
+			mu.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				serve(c)
+			}()
+		}
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, listener.Close())
+		mu.Lock()
+		for _, c := range conns {
+			c.Close()
+		}
+		mu.Unlock()
+		wg.Wait()
+	})
+	return listener.Addr().(*net.TCPAddr).Port, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return bindCount
+	}
+}
+
+func TestLDAPAuthentication(t *testing.T) {
+	store, clean := newStore(t)
+	defer clean()
+
+	dn := "uid=u1,ou=people,dc=example,dc=com"
+	port, binds := startFakeLDAPServer(t, dn, "secret", "dev", "ops")
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set global authentication_ldap_simple_server_host = '127.0.0.1'")
+	tk.MustExec(fmt.Sprintf("set global authentication_ldap_simple_server_port = %d", port))
+	defer func() {
+		tk.MustExec("set global authentication_ldap_simple_server_host = default")
+		tk.MustExec("set global authentication_ldap_simple_server_port = default")
+		tk.MustExec("set global authentication_ldap_simple_group_role_mapping = default")
+		tk.MustExec("set global authentication_ldap_simple_bind_cache_ttl = default")
+	}()
+
+	tk.MustExec(fmt.Sprintf("create user u1 identified with authentication_ldap_simple as '%s'", dn))
+	tk.MustExec("create user u2 identified with authentication_ldap_sasl")
+	tk.MustQuery("show create user u1").Check(testkit.Rows(fmt.Sprintf("CREATE USER 'u1'@'%%' IDENTIFIED WITH 'authentication_ldap_simple' AS '%s' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK", dn)))
+	tk.MustQuery("show create user u2").Check(testkit.Rows("CREATE USER 'u2'@'%' IDENTIFIED WITH 'authentication_ldap_sasl' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+	// The password is managed by the LDAP server.
+	tk.MustExec("set password for u1 = 'abc'")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1699 SET PASSWORD has no significance for user 'u1'@'%' as authentication plugin does not support it."))
+	tk.MustQuery("select authentication_string from mysql.user where user = 'u1'").Check(testkit.Rows(dn))
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, []byte("secret"), nil))
+	require.False(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, []byte("wrong"), nil))
+	// An empty password is never accepted.
+	require.False(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	// The SASL authentication requires the connection with the client.
+	require.False(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u2", Hostname: "%"}, []byte("secret"), nil))
+
+	// The LDAP groups are mapped to the roles, which are activated on login.
+	tk.MustExec("create role r1, r2")
+	tk.MustExec("create table t1 (c1 int)")
+	tk.MustExec("grant select on t1 to r1")
+	require.Error(t, tk.ExecToErr("set global authentication_ldap_simple_group_role_mapping = 'dev'"))
+	tk.MustExec("set global authentication_ldap_simple_group_role_mapping = 'dev=r1,ops=r2,qa=r3'")
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, []byte("secret"), nil))
+	tk1.MustQuery("select current_role()").Check(testkit.Rows("`r1`@`%`,`r2`@`%`"))
+	tk1.MustQuery("select * from test.t1").Check(testkit.Rows())
+	tk1.MustExec("set role none")
+	require.Error(t, tk1.ExecToErr("select * from test.t1"))
+	tk1.MustExec("set role r1")
+	tk1.MustQuery("select * from test.t1").Check(testkit.Rows())
+
+	// The successful binds are cached.
+	tk.MustExec("set global authentication_ldap_simple_bind_cache_ttl = 60")
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, []byte("secret"), nil))
+	count := binds()
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, []byte("secret"), nil))
+	require.Equal(t, count, binds())
+	require.False(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, []byte("wrong"), nil))
+}
+
+func TestCurrentRoleInPreparedStmt(t *testing.T) {
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer core.SetPreparedPlanCache(orgEnable)
+	core.SetPreparedPlanCache(true)
+	store, clean := newStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create user u1")
+	tk.MustExec("create role r1, r2")
+	tk.MustExec("grant r1, r2 to u1")
+
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk2.MustExec("prepare stmt from 'select current_role()'")
+	check := func(roles string) {
+		tk2.MustQuery("execute stmt").Check(testkit.Rows(roles))
+		// The statement is executed by the binary protocol when it has arguments.
+		tk2.MustQuery("select current_role() from dual where ? = 1", 1).Check(testkit.Rows(roles))
+	}
+	check("NONE")
+	tk2.MustExec("set role r1")
+	check("`r1`@`%`")
+	tk2.MustExec("set role all")
+	check("`r1`@`%`,`r2`@`%`")
+	tk2.MustExec("set role none")
+	check("NONE")
+}
+
+func TestColumnLevelPrivilege(t *testing.T) {
+	store, clean := newStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create user u1")
+	tk.MustExec("create table t1 (c1 int primary key, c2 int, c3 int)")
+	tk.MustExec("create table t2 (c1 int, c4 int)")
+	tk.MustExec("insert into t1 values (1, 1, 1)")
+	tk.MustExec("insert into t2 values (1, 1)")
+	tk.MustExec("create definer='root'@'%' sql security definer view v1 as select c1, c2, c3 from t1")
+	tk.MustExec("create definer='root'@'%' sql security invoker view v2 as select c1, c3 from t1")
+	tk.MustExec("grant select(c1, c2), update(c2), insert(c1, c2) on t1 to u1")
+	tk.MustExec("grant select(c1) on t2 to u1")
+	tk.MustExec("grant select(c1) on v1 to u1")
+	tk.MustExec("grant select on v2 to u1")
+
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk2.MustExec("use test")
+
+	// SELECT
+	tk2.MustQuery("select c1, c2 from t1 where c1 = 1").Check(testkit.Rows("1 1"))
+	tk2.MustQuery("select count(*) from t1").Check(testkit.Rows("1"))
+	tk2.MustQuery("select t1.c2 from t1 join t2 using (c1)").Check(testkit.Rows("1"))
+	err := tk2.ExecToErr("select c3 from t1")
+	require.EqualError(t, err, "[planner:1143]SELECT command denied to user 'u1'@'%' for column 'c3' in table 't1'")
+	err = tk2.ExecToErr("select c1 from t1 where c3 > 0")
+	require.EqualError(t, err, "[planner:1143]SELECT command denied to user 'u1'@'%' for column 'c3' in table 't1'")
+	err = tk2.ExecToErr("select * from t1")
+	require.EqualError(t, err, "[planner:1143]SELECT command denied to user 'u1'@'%' for column 'c3' in table 't1'")
+	err = tk2.ExecToErr("select c1 from t1 where exists (select 1 from t2 where t2.c4 = t1.c1)")
+	require.EqualError(t, err, "[planner:1143]SELECT command denied to user 'u1'@'%' for column 'c4' in table 't2'")
+	err = tk2.ExecToErr("select t1.c1 from t1 natural join t2")
+	require.NoError(t, err)
+	tk2.MustQuery("select a from (select c1 as a from t1) t").Check(testkit.Rows("1"))
+
+	// Views
+	tk2.MustQuery("select c1 from v1").Check(testkit.Rows("1"))
+	err = tk2.ExecToErr("select c3 from v1")
+	require.EqualError(t, err, "[planner:1143]SELECT command denied to user 'u1'@'%' for column 'c3' in table 'v1'")
+	err = tk2.ExecToErr("select c1 from v2")
+	require.EqualError(t, err, "[planner:1143]SELECT command denied to user 'u1'@'%' for column 'c3' in table 't1'")
+
+	// UPDATE
+	tk2.MustExec("update t1 set c2 = c1 + 1 where c1 = 1")
+	err = tk2.ExecToErr("update t1 set c3 = 1")
+	require.EqualError(t, err, "[planner:1143]UPDATE command denied to user 'u1'@'%' for column 'c3' in table 't1'")
+	err = tk2.ExecToErr("update t1 set c2 = c3")
+	require.EqualError(t, err, "[planner:1143]SELECT command denied to user 'u1'@'%' for column 'c3' in table 't1'")
+
+	// INSERT
+	tk2.MustExec("insert into t1 (c1, c2) values (2, 2)")
+	tk2.MustExec("insert into t1 set c1 = 3")
+	err = tk2.ExecToErr("insert into t1 (c1, c3) values (4, 4)")
+	require.EqualError(t, err, "[planner:1143]INSERT command denied to user 'u1'@'%' for column 'c3' in table 't1'")
+	err = tk2.ExecToErr("insert into t1 values (4, 4, 4)")
+	require.EqualError(t, err, "[planner:1143]INSERT command denied to user 'u1'@'%' for column 'c3' in table 't1'")
+	tk2.MustExec("insert into t1 (c1, c2) values (1, 1) on duplicate key update c2 = 3")
+	err = tk2.ExecToErr("insert into t1 (c1, c2) values (1, 1) on duplicate key update c1 = 5")
+	require.EqualError(t, err, "[planner:1143]UPDATE command denied to user 'u1'@'%' for column 'c1' in table 't1'")
+	err = tk2.ExecToErr("insert into t2 (c1) values (1)")
+	require.EqualError(t, err, "[planner:1142]INSERT command denied to user 'u1'@'%' for table 't2'")
+	tk.MustQuery("select * from t1 order by c1").Check(testkit.Rows("1 3 1", "2 2 <nil>", "3 <nil> <nil>"))
+
+	// The privilege on one column doesn't imply the privilege on the others after revoking.
+	tk.MustExec("revoke select(c2) on t1 from u1")
+	err = tk2.ExecToErr("select c2 from t1")
+	require.EqualError(t, err, "[planner:1143]SELECT command denied to user 'u1'@'%' for column 'c2' in table 't1'")
+	tk.MustExec("revoke select(c1), update(c2), insert(c1, c2) on t1 from u1")
+	err = tk2.ExecToErr("select count(*) from t1")
+	require.EqualError(t, err, "[planner:1142]SELECT command denied to user 'u1'@'%' for table 't1'")
+}
+
+func TestPasswordLockAndExpiration(t *testing.T) {
+	store, clean := newStore(t)
+	defer clean()
+
+	se := newSession(t, store, dbName)
+	login := newSession(t, store, dbName)
+	salt := []byte{85, 92, 45, 22, 58, 79, 107, 6, 122, 125, 58, 80, 12, 90, 103, 32, 90, 10, 74, 82}
+	authentication := []byte{24, 180, 183, 225, 166, 6, 81, 102, 70, 248, 199, 143, 91, 204, 169, 9, 161, 171, 203, 33}
+	user := &auth.UserIdentity{Username: "u1", Hostname: "localhost"}
+
+	// The account is locked after 2 consecutive failed logins.
+	mustExec(t, se, `CREATE USER 'u1'@'localhost' identified by 'abc' FAILED_LOGIN_ATTEMPTS 2 PASSWORD_LOCK_TIME UNBOUNDED`)
+	require.False(t, login.Auth(user, nil, nil))
+	require.True(t, login.Auth(user, authentication, salt))
+	// A successful login resets the counter.
+	require.False(t, login.Auth(user, nil, nil))
+	require.True(t, login.Auth(user, authentication, salt))
+	require.False(t, login.Auth(user, nil, nil))
+	require.False(t, login.Auth(user, nil, nil))
+	require.False(t, login.Auth(user, authentication, salt))
+	// ACCOUNT UNLOCK unlocks the account.
+	mustExec(t, se, `ALTER USER 'u1'@'localhost' ACCOUNT UNLOCK`)
+	require.True(t, login.Auth(user, authentication, salt))
+	// Altering the settings discards the failed logins.
+	require.False(t, login.Auth(user, nil, nil))
+	require.False(t, login.Auth(user, nil, nil))
+	mustExec(t, se, `ALTER USER 'u1'@'localhost' FAILED_LOGIN_ATTEMPTS 3`)
+	require.True(t, login.Auth(user, authentication, salt))
+	// The failed logins aren't tracked without the lock time.
+	mustExec(t, se, `ALTER USER 'u1'@'localhost' PASSWORD_LOCK_TIME 0`)
+	for i := 0; i < 3; i++ {
+		require.False(t, login.Auth(user, nil, nil))
+	}
+	require.True(t, login.Auth(user, authentication, salt))
+
+	// The session is in the sandbox mode once the password is expired.
+	mustExec(t, se, `ALTER USER 'u1'@'localhost' PASSWORD EXPIRE`)
+	require.True(t, login.Auth(user, authentication, salt))
+	require.True(t, login.GetSessionVars().InSandboxMode)
+	mustExec(t, se, `ALTER USER 'u1'@'localhost' identified by 'abc' PASSWORD EXPIRE INTERVAL 10 DAY`)
+	require.True(t, login.Auth(user, authentication, salt))
+	require.False(t, login.GetSessionVars().InSandboxMode)
+	mustExec(t, se, `UPDATE mysql.user SET Password_last_changed = DATE_SUB(NOW(), INTERVAL 11 DAY) WHERE User = 'u1'`)
+	mustExec(t, se, `FLUSH PRIVILEGES`)
+	require.True(t, login.Auth(user, authentication, salt))
+	require.True(t, login.GetSessionVars().InSandboxMode)
+	mustExec(t, se, `ALTER USER 'u1'@'localhost' PASSWORD EXPIRE NEVER`)
+	require.True(t, login.Auth(user, authentication, salt))
+	require.False(t, login.GetSessionVars().InSandboxMode)
+	// The accounts with PASSWORD EXPIRE DEFAULT use default_password_lifetime.
+	mustExec(t, se, `ALTER USER 'u1'@'localhost' PASSWORD EXPIRE DEFAULT`)
+	require.True(t, login.Auth(user, authentication, salt))
+	require.False(t, login.GetSessionVars().InSandboxMode)
+	mustExec(t, se, `SET GLOBAL default_password_lifetime = 10`)
+	defer mustExec(t, se, `SET GLOBAL default_password_lifetime = DEFAULT`)
+	require.True(t, login.Auth(user, authentication, salt))
+	require.True(t, login.GetSessionVars().InSandboxMode)
+}
+
+func TestPasswordExpiredSandboxMode(t *testing.T) {
+	store, clean := newStore(t)
+	defer clean()
+
+	se := newSession(t, store, dbName)
+	login := newSession(t, store, dbName)
+	salt := []byte{85, 92, 45, 22, 58, 79, 107, 6, 122, 125, 58, 80, 12, 90, 103, 32, 90, 10, 74, 82}
+	authentication := []byte{24, 180, 183, 225, 166, 6, 81, 102, 70, 248, 199, 143, 91, 204, 169, 9, 161, 171, 203, 33}
+	user := &auth.UserIdentity{Username: "u1", Hostname: "localhost"}
+
+	mustExec(t, se, `CREATE USER 'u1'@'localhost' identified by 'abc' PASSWORD EXPIRE`)
+	require.True(t, login.Auth(user, authentication, salt))
+	require.True(t, login.GetSessionVars().InSandboxMode)
+	// Only the password can be changed in the sandbox mode.
+	_, err := login.ExecuteInternal(context.Background(), "select 1")
+	require.NoError(t, err)
+	for _, sql := range []string{"select 1", "show databases", "prepare stmt from 'select 1'"} {
+		_, err = login.Execute(context.Background(), sql)
+		require.True(t, session.ErrMustChangePassword.Equal(err), sql)
+	}
+	_, _, _, err = login.PrepareStmt("select 1")
+	require.True(t, session.ErrMustChangePassword.Equal(err))
+	_, err = login.Execute(context.Background(), `SET @a = 1`)
+	require.NoError(t, err)
+	// Expiring the password again doesn't leave the sandbox mode.
+	_, err = login.Execute(context.Background(), `ALTER USER 'u1'@'localhost' identified by 'def' PASSWORD EXPIRE`)
+	require.NoError(t, err)
+	require.True(t, login.GetSessionVars().InSandboxMode)
+	_, err = login.Execute(context.Background(), `SET PASSWORD = 'abc'`)
+	require.NoError(t, err)
+	require.False(t, login.GetSessionVars().InSandboxMode)
+	_, err = login.Execute(context.Background(), `select 1`)
+	require.NoError(t, err)
+	require.True(t, login.Auth(user, authentication, salt))
+	require.False(t, login.GetSessionVars().InSandboxMode)
+
+	// Changing the password of the other users doesn't leave the sandbox mode.
+	mustExec(t, se, `CREATE USER 'u2'@'localhost'`)
+	mustExec(t, se, `GRANT CREATE USER ON *.* TO 'u1'@'localhost'`)
+	mustExec(t, se, `ALTER USER 'u1'@'localhost' PASSWORD EXPIRE`)
+	require.True(t, login.Auth(user, authentication, salt))
+	_, err = login.Execute(context.Background(), `ALTER USER 'u2'@'localhost' identified by 'abc'`)
+	require.NoError(t, err)
+	require.True(t, login.GetSessionVars().InSandboxMode)
+	_, err = login.Execute(context.Background(), `ALTER USER 'u1'@'localhost' identified by 'abc'`)
+	require.NoError(t, err)
+	require.False(t, login.GetSessionVars().InSandboxMode)
+}
+
+func TestSM3Password(t *testing.T) {
+	store, clean := newStore(t)
+	defer clean()
+
+	se := newSession(t, store, dbName)
+	login := newSession(t, store, dbName)
+	user := &auth.UserIdentity{Username: "u1", Hostname: "localhost"}
+
+	mustExec(t, se, `CREATE USER 'u1'@'localhost' IDENTIFIED WITH 'tidb_sm3_password' BY 'abc' PASSWORD HISTORY 1`)
+	pm := privilege.GetPrivilegeManager(se)
+	plugin, err := pm.GetAuthPlugin("u1", "localhost")
+	require.NoError(t, err)
+	require.Equal(t, mysql.AuthTiDBSM3Password, plugin)
+	// The clear text password is checked, the same as caching_sha2_password.
+	require.True(t, login.Auth(user, []byte("abc"), nil))
+	require.False(t, login.Auth(user, []byte("abd"), nil))
+
+	// The password history works with the SM3 hashes.
+	_, err = se.ExecuteInternal(context.Background(), `ALTER USER 'u1'@'localhost' IDENTIFIED BY 'abc'`)
+	require.Error(t, err)
+	mustExec(t, se, `SET PASSWORD FOR 'u1'@'localhost' = 'xyz'`)
+	require.True(t, login.Auth(user, []byte("xyz"), nil))
+	require.False(t, login.Auth(user, []byte("abc"), nil))
+	require.True(t, strings.HasPrefix(pm.GetEncodedPassword("u1", "localhost"), "$B$005$"))
+}
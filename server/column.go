@@ -76,7 +76,7 @@ func isStringColumnType(tp byte) bool {
 	switch tp {
 	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeBit,
 		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob,
-		mysql.TypeEnum, mysql.TypeSet, mysql.TypeJSON:
+		mysql.TypeEnum, mysql.TypeSet, mysql.TypeJSON, mysql.TypeTiDBVectorFloat32:
 		return true
 	}
 	return false
@@ -97,6 +97,9 @@ func dumpType(tp byte) byte {
 	switch tp {
 	case mysql.TypeSet, mysql.TypeEnum:
 		return mysql.TypeString
+	case mysql.TypeTiDBVectorFloat32:
+		// VECTOR is a TiDB extension unknown to MySQL clients, so it is sent as a string.
+		return mysql.TypeVarString
 	default:
 		return tp
 	}
@@ -47,6 +47,7 @@ func TestDumpColumn(t *testing.T) {
 	require.Equal(t, mysql.TypeString, dumpType(mysql.TypeSet))
 	require.Equal(t, mysql.TypeString, dumpType(mysql.TypeEnum))
 	require.Equal(t, mysql.TypeBit, dumpType(mysql.TypeBit))
+	require.Equal(t, mysql.TypeVarString, dumpType(mysql.TypeTiDBVectorFloat32))
 }
 
 func TestColumnNameLimit(t *testing.T) {
@@ -73,19 +73,24 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/conn"
+	"github.com/pingcap/tidb/privilege/privileges/ldap"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	storeerr "github.com/pingcap/tidb/store/driver/error"
 	"github.com/pingcap/tidb/tablecodec"
 	tidbutil "github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/arena"
+	"github.com/pingcap/tidb/util/audit"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/resourcegroup"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/util"
 	"go.uber.org/zap"
@@ -98,6 +103,11 @@ const (
 	connStatusWaitShutdown // Notified by server to close.
 )
 
+// authLDAPSASLClient is the client side plugin of `authentication_ldap_sasl`.
+const authLDAPSASLClient = "authentication_ldap_sasl_client"
+
+var _ conn.AuthConn = &clientConn{}
+
 var (
 	queryTotalCountOk = [...]prometheus.Counter{
 		mysql.ComSleep:            metrics.QueryTotalCounter.WithLabelValues("Sleep", "OK"),
@@ -147,6 +157,7 @@ var (
 	disconnectNormal            = metrics.DisconnectionCounter.WithLabelValues(metrics.LblOK)
 	disconnectByClientWithError = metrics.DisconnectionCounter.WithLabelValues(metrics.LblError)
 	disconnectErrorUndetermined = metrics.DisconnectionCounter.WithLabelValues("undetermined")
+	disconnectIdleTxnTimeout    = metrics.DisconnectionCounter.WithLabelValues("idle_txn_timeout")
 
 	connIdleDurationHistogramNotInTxn = metrics.ConnIdleDurationHistogram.WithLabelValues("0")
 	connIdleDurationHistogramInTxn    = metrics.ConnIdleDurationHistogram.WithLabelValues("1")
@@ -182,9 +193,11 @@ type clientConn struct {
 	chunkAlloc    chunk.Allocator
 	lastPacket    []byte            // latest sql query string, currently used for logging error.
 	ctx           *TiDBContext      // an interface to execute sql statements.
-	attrs         map[string]string // attributes parsed from client handshake response, not used for now.
+	attrs         map[string]string // attributes parsed from client handshake response.
+	zstdLevel     int               // zstd compression level in client handshake response.
 	peerHost      string            // peer host
 	peerPort      string            // peer port
+	proxyAddr     string            // address of the proxy if the connection is accepted through PROXY protocol
 	status        int32             // dispatching/reading/shutdown/waitshutdown
 	lastCode      uint16            // last error code
 	collation     uint8             // collation used by client, may be different from the collation used by database.
@@ -194,6 +207,11 @@ type clientConn struct {
 	rsEncoder     *resultEncoder    // rsEncoder is used to encode the string result to different charsets.
 	inputDecoder  *inputDecoder     // inputDecoder is used to decode the different charsets of incoming strings to utf-8.
 	socketCredUID uint32            // UID from the other end of the Unix Socket
+	secureText    string            // text of the last query whose passwords are redacted, it's captured instead of the query.
+	// egress limits the result sets sent to the connection by tidb_conn_egress_bytes_per_sec, it's recreated when
+	// the variable is changed.
+	egress     *resourcegroup.TokenBucket
+	egressRate uint64
 	// mu is used for cancelling the execution of current transaction.
 	mu struct {
 		sync.RWMutex
@@ -218,12 +236,26 @@ func (cc *clientConn) authSwitchRequest(ctx context.Context, plugin string) ([]b
 	failpoint.Inject("FakeAuthSwitch", func() {
 		failpoint.Return([]byte(plugin), nil)
 	})
-	enclen := 1 + len(plugin) + 1 + len(cc.salt) + 1
+	clientPlugin, pluginData := plugin, cc.salt
+	switch plugin {
+	case mysql.AuthTiDBSM3Password:
+		// The clients don't know tidb_sm3_password, but its exchange is the same as the full authentication
+		// of caching_sha2_password, in which the clear text password is sent over TLS.
+		clientPlugin = mysql.AuthCachingSha2Password
+	case mysql.AuthLDAPSimple:
+		// The password is sent in clear text, so that it can be used to bind to the LDAP server.
+		clientPlugin = mysql.AuthMySQLClearPassword
+	case mysql.AuthLDAPSASL:
+		// The client starts the SASL exchange with the mechanism.
+		clientPlugin = authLDAPSASLClient
+		pluginData = []byte(ldap.LDAPSASLAuthImpl.GetSASLAuthMethod())
+	}
+	enclen := 1 + len(clientPlugin) + 1 + len(pluginData) + 1
 	data := cc.alloc.AllocWithLen(4, enclen)
 	data = append(data, mysql.AuthSwitchRequest) // switch request
-	data = append(data, []byte(plugin)...)
+	data = append(data, []byte(clientPlugin)...)
 	data = append(data, byte(0x00)) // requires null
-	data = append(data, cc.salt...)
+	data = append(data, pluginData...)
 	data = append(data, 0)
 	err := cc.writePacket(data)
 	if err != nil {
@@ -246,6 +278,9 @@ func (cc *clientConn) authSwitchRequest(ctx context.Context, plugin string) ([]b
 		return nil, err
 	}
 	cc.authPlugin = plugin
+	if plugin == mysql.AuthLDAPSimple {
+		resp = bytes.TrimSuffix(resp, []byte{0})
+	}
 	return resp, nil
 }
 
@@ -253,6 +288,15 @@ func (cc *clientConn) authSwitchRequest(ctx context.Context, plugin string) ([]b
 // during handshake, client and server negotiate compatible features and do authentication.
 // After handshake, client can send sql query to server.
 func (cc *clientConn) handshake(ctx context.Context) error {
+	// Reject the connection before the handshake if the server is draining,
+	// so that the client can retry another server immediately.
+	if cc.server.isDraining() {
+		err := errServerShutdown.GenWithStackByArgs()
+		if err1 := cc.writeError(ctx, err); err1 != nil {
+			logutil.Logger(ctx).Debug("writeError failed", zap.Error(err1))
+		}
+		return err
+	}
 	if err := cc.writeInitialHandshake(ctx); err != nil {
 		if errors.Cause(err) == io.EOF {
 			logutil.Logger(ctx).Debug("Could not send handshake due to connection has be closed by client-side")
@@ -289,7 +333,7 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 	}
 
 	err := cc.writePacket(data)
-	cc.pkt.sequence = 0
+	cc.pkt.resetSequence()
 	if err != nil {
 		err = errors.SuspendStack(err)
 		logutil.Logger(ctx).Debug("write response to client failed", zap.Error(err))
@@ -302,9 +346,31 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		logutil.Logger(ctx).Debug("flush response to client failed", zap.Error(err))
 		return err
 	}
+
+	// The compressed protocol is used after the handshake is done.
+	if algorithm, level := cc.compressionAlgorithm(); algorithm != compressionNone {
+		if err = cc.pkt.setCompression(algorithm, level); err != nil {
+			logutil.Logger(ctx).Warn("enable compressed protocol failed", zap.Error(err))
+		}
+	}
 	return err
 }
 
+// compressionAlgorithm returns the algorithm and the level of the compressed protocol negotiated with the client.
+func (cc *clientConn) compressionAlgorithm() (algorithm int, level int) {
+	switch {
+	case cc.capability&mysql.ClientZstdCompressionAlgorithm > 0:
+		level = cc.zstdLevel
+		if level <= 0 {
+			level = defaultZstdCompressionLevel
+		}
+		return compressionZstd, level
+	case cc.capability&mysql.ClientCompress > 0:
+		return compressionZlib, defaultZlibCompressionLevel
+	}
+	return compressionNone, 0
+}
+
 func (cc *clientConn) Close() error {
 	cc.server.rwlock.Lock()
 	delete(cc.server.clients, cc.connectionID)
@@ -319,6 +385,9 @@ func closeConn(cc *clientConn, connections int) error {
 		err := cc.bufReadConn.Close()
 		terror.Log(err)
 	}
+	if cc.pkt != nil {
+		cc.pkt.close()
+	}
 	if cc.ctx != nil {
 		return cc.ctx.Close()
 	}
@@ -396,6 +465,25 @@ func (cc *clientConn) readPacket() ([]byte, error) {
 	return cc.pkt.readPacket()
 }
 
+// ReadPacket implements the conn.AuthConn interface.
+func (cc *clientConn) ReadPacket() ([]byte, error) {
+	return cc.readPacket()
+}
+
+// WriteAuthMoreData implements the conn.AuthConn interface.
+func (cc *clientConn) WriteAuthMoreData(data []byte) error {
+	// The first 4 bytes are reserved for the header.
+	pkt := make([]byte, 0, 4+1+len(data))
+	pkt = append(pkt, 0, 0, 0, 0, mysql.AuthMoreData)
+	pkt = append(pkt, data...)
+	return cc.writePacket(pkt)
+}
+
+// Flush implements the conn.AuthConn interface.
+func (cc *clientConn) Flush(ctx context.Context) error {
+	return cc.flush(ctx)
+}
+
 func (cc *clientConn) writePacket(data []byte) error {
 	failpoint.Inject("FakeClientConn", func() {
 		if cc.pkt == nil {
@@ -405,6 +493,15 @@ func (cc *clientConn) writePacket(data []byte) error {
 	return cc.pkt.writePacket(data)
 }
 
+// getIdleTxnTimeout returns tidb_idle_transaction_timeout if the session is in a transaction, otherwise 0.
+func (cc *clientConn) getIdleTxnTimeout() time.Duration {
+	vars := cc.ctx.GetSessionVars()
+	if !vars.InTxn() {
+		return 0
+	}
+	return time.Duration(vars.IdleTransactionTimeout) * time.Second
+}
+
 // getSessionVarsWaitTimeout get session variable wait_timeout
 func (cc *clientConn) getSessionVarsWaitTimeout(ctx context.Context) uint64 {
 	valStr, exists := cc.ctx.GetSessionVars().GetSystemVar(variable.WaitTimeout)
@@ -428,6 +525,7 @@ type handshakeResponse41 struct {
 	Auth       []byte
 	AuthPlugin string
 	Attrs      map[string]string
+	ZstdLevel  int
 }
 
 // parseOldHandshakeResponseHeader parses the old version handshake header HandshakeResponse320
@@ -580,7 +678,15 @@ func parseHandshakeResponseBody(ctx context.Context, packet *handshakeResponse41
 				return nil
 			}
 			packet.Attrs = attrs
+			offset += int(num)
+		}
+	}
+
+	if packet.Capability&mysql.ClientZstdCompressionAlgorithm > 0 {
+		if len(data[offset:]) == 0 {
+			return mysql.ErrMalformPacket
 		}
+		packet.ZstdLevel = int(data[offset])
 	}
 
 	return nil
@@ -687,6 +793,7 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 	cc.dbname = resp.DBName
 	cc.collation = resp.Collation
 	cc.attrs = resp.Attrs
+	cc.zstdLevel = resp.ZstdLevel
 
 	err = cc.handleAuthPlugin(ctx, &resp)
 	if err != nil {
@@ -694,13 +801,15 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 	}
 
 	switch resp.AuthPlugin {
-	case mysql.AuthCachingSha2Password:
+	case mysql.AuthCachingSha2Password, mysql.AuthTiDBSM3Password:
 		resp.Auth, err = cc.authSha(ctx)
 		if err != nil {
 			return err
 		}
 	case mysql.AuthNativePassword:
 	case mysql.AuthSocket:
+	case mysql.AuthTiDBSessionToken:
+	case mysql.AuthLDAPSimple, mysql.AuthLDAPSASL:
 	default:
 		return errors.New("Unknown auth plugin")
 	}
@@ -724,9 +833,11 @@ func (cc *clientConn) handleAuthPlugin(ctx context.Context, resp *handshakeRespo
 		}
 
 		switch resp.AuthPlugin {
-		case mysql.AuthCachingSha2Password:
+		case mysql.AuthCachingSha2Password, mysql.AuthTiDBSM3Password:
 		case mysql.AuthNativePassword:
 		case mysql.AuthSocket:
+		case mysql.AuthTiDBSessionToken:
+		case mysql.AuthLDAPSimple, mysql.AuthLDAPSASL:
 		default:
 			logutil.Logger(ctx).Warn("Unknown Auth Plugin", zap.String("plugin", resp.AuthPlugin))
 		}
@@ -742,7 +853,8 @@ func (cc *clientConn) handleAuthPlugin(ctx context.Context, resp *handshakeRespo
 	return nil
 }
 
-// authSha implements the caching_sha2_password specific part of the protocol.
+// authSha implements the caching_sha2_password specific part of the protocol, which is also used by
+// tidb_sm3_password.
 func (cc *clientConn) authSha(ctx context.Context) ([]byte, error) {
 
 	const (
@@ -798,6 +910,7 @@ func (cc *clientConn) openSession() error {
 	if err != nil {
 		return err
 	}
+	cc.ctx.GetSessionVars().ConnectionAttrs = cc.attrs
 
 	err = cc.server.checkConnectionCount()
 	if err != nil {
@@ -828,10 +941,37 @@ func (cc *clientConn) openSessionAndDoAuth(authData []byte, authPlugin string) e
 		return errAccessDeniedNoPassword.FastGenByArgs(cc.user, host)
 	}
 
-	if !cc.ctx.Auth(&auth.UserIdentity{Username: cc.user, Hostname: host}, authData, cc.salt) {
-		return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
+	userIdentity := &auth.UserIdentity{Username: cc.user, Hostname: host}
+	if authPlugin == mysql.AuthTiDBSessionToken {
+		// The session is migrated from another server, the user is authenticated by the token signed by that server.
+		if err = sessionstates.ValidateSessionToken(authData, cc.user); err != nil {
+			logutil.BgLogger().Warn("verify session token failed", zap.String("username", cc.user), zap.Error(err))
+			return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
+		}
+		if !cc.ctx.AuthWithoutVerification(userIdentity) {
+			return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
+		}
+	} else {
+		if authPlugin == mysql.AuthLDAPSASL {
+			// The SASL messages are exchanged with the client during the authentication.
+			cc.ctx.GetSessionVars().AuthConn = cc
+			defer func() {
+				cc.ctx.GetSessionVars().AuthConn = nil
+			}()
+		}
+		if !cc.ctx.Auth(userIdentity, authData, cc.salt) {
+			return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
+		}
+		// The clients which can't handle the expired passwords don't know they can only change the password.
+		if cc.ctx.GetSessionVars().InSandboxMode && cc.capability&mysql.ClientCanHandleExpiredPasswords == 0 &&
+			config.GetGlobalConfig().Security.DisconnectOnExpiredPassword {
+			return errMustChangePasswordLogin.GenWithStackByArgs()
+		}
 	}
 	cc.ctx.SetPort(port)
+	if err = cc.server.checkUserConnectionCount(cc); err != nil {
+		return err
+	}
 	if cc.dbname != "" {
 		err = cc.useDB(context.Background(), cc.dbname)
 		if err != nil {
@@ -875,6 +1015,10 @@ func (cc *clientConn) checkAuthPlugin(ctx context.Context, resp *handshakeRespon
 	failpoint.Inject("FakeUser", func(val failpoint.Value) {
 		userplugin = val.(string)
 	})
+	// The session token is accepted whatever the plugin of the user is, it's verified in openSessionAndDoAuth.
+	if resp.AuthPlugin == mysql.AuthTiDBSessionToken {
+		return nil, nil
+	}
 	if userplugin == mysql.AuthSocket {
 		if !cc.isUnixSocket {
 			return nil, errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
@@ -1058,12 +1202,30 @@ func (cc *clientConn) Run(ctx context.Context) {
 		cc.alloc.Reset()
 		// close connection when idle time is more than wait_timeout
 		waitTimeout := cc.getSessionVarsWaitTimeout(ctx)
-		cc.pkt.setReadTimeout(time.Duration(waitTimeout) * time.Second)
+		readTimeout := time.Duration(waitTimeout) * time.Second
+		// close connection and rollback the transaction when idle time in the transaction is more than tidb_idle_transaction_timeout
+		idleTxnTimeout := cc.getIdleTxnTimeout()
+		if idleTxnTimeout > 0 && (readTimeout == 0 || idleTxnTimeout < readTimeout) {
+			readTimeout = idleTxnTimeout
+		} else {
+			idleTxnTimeout = 0
+		}
+		cc.pkt.setReadTimeout(readTimeout)
 		start := time.Now()
 		data, err := cc.readPacket()
 		if err != nil {
 			if terror.ErrorNotEqual(err, io.EOF) {
-				if netErr, isNetErr := errors.Cause(err).(net.Error); isNetErr && netErr.Timeout() {
+				if netErr, isNetErr := errors.Cause(err).(net.Error); isNetErr && netErr.Timeout() && idleTxnTimeout > 0 {
+					vars := cc.ctx.GetSessionVars()
+					logutil.Logger(ctx).Warn("idle in transaction timeout, close this connection and rollback the transaction",
+						zap.Duration("idle", time.Since(start)),
+						zap.Duration("idleTxnTimeout", idleTxnTimeout),
+						zap.Uint64("txnStartTS", vars.TxnCtx.StartTS),
+						zap.Bool("pessimistic", vars.TxnCtx.IsPessimistic),
+					)
+					disconnectIdleTxnTimeout.Inc()
+					return
+				} else if isNetErr && netErr.Timeout() {
 					idleTime := time.Since(start)
 					logutil.Logger(ctx).Info("read packet timeout, close this connection",
 						zap.Duration("idle", idleTime),
@@ -1087,9 +1249,11 @@ func (cc *clientConn) Run(ctx context.Context) {
 		}
 
 		startTime := time.Now()
+		curDB := cc.ctx.GetSessionVars().CurrentDB
 		err = cc.dispatch(ctx, data)
 		cc.chunkAlloc.Reset()
 		if err != nil {
+			err = cc.maxExecTimeError(err)
 			cc.audit(plugin.Error) // tell the plugin API there was a dispatch error
 			if terror.ErrorEqual(err, io.EOF) {
 				cc.addMetrics(data[0], startTime, nil)
@@ -1117,14 +1281,15 @@ func (cc *clientConn) Run(ctx context.Context) {
 					zap.String("status", cc.SessionStatusToString()),
 					zap.Stringer("sql", getLastStmtInConn{cc}),
 					zap.String("txn_mode", txnMode),
-					zap.String("err", errStrForLog(err, cc.ctx.GetSessionVars().EnableRedactLog)),
+					zap.String("err", errStrForLog(err, cc.ctx.GetSessionVars().ShouldRedactLog())),
 				)
 			}
 			err1 := cc.writeError(ctx, err)
 			terror.Log(err1)
 		}
+		cc.captureTraffic(data, curDB, startTime, err)
 		cc.addMetrics(data[0], startTime, err)
-		cc.pkt.sequence = 0
+		cc.pkt.resetSequence()
 	}
 }
 
@@ -1282,7 +1447,11 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 			pprof.SetGoroutineLabels(ctx)
 		}
 	}
-	token := cc.server.getToken()
+	token, err := cc.server.getToken(ctx, cmd == mysql.ComQuery || cmd == mysql.ComStmtExecute)
+	if err != nil {
+		span.Finish()
+		return err
+	}
 	defer func() {
 		// if handleChangeUser failed, cc.ctx may be nil
 		if cc.ctx != nil {
@@ -1467,24 +1636,7 @@ func (cc *clientConn) writeOkWith(ctx context.Context, msg string, affectedRows,
 }
 
 func (cc *clientConn) writeError(ctx context.Context, e error) error {
-	var (
-		m  *mysql.SQLError
-		te *terror.Error
-		ok bool
-	)
-	originErr := errors.Cause(e)
-	if te, ok = originErr.(*terror.Error); ok {
-		m = terror.ToSQLError(te)
-	} else {
-		e := errors.Cause(originErr)
-		switch y := e.(type) {
-		case *terror.Error:
-			m = terror.ToSQLError(y)
-		default:
-			m = mysql.NewErrf(mysql.ErrUnknown, "%s", nil, e.Error())
-		}
-	}
-
+	m := toSQLError(e)
 	cc.lastCode = m.Code
 	defer errno.IncrementError(m.Code, cc.user, cc.peerHost)
 	data := cc.alloc.AllocWithLen(4, 16+len(m.Message))
@@ -1504,6 +1656,33 @@ func (cc *clientConn) writeError(ctx context.Context, e error) error {
 	return cc.flush(ctx)
 }
 
+// toSQLError converts the error to the SQL error which is sent to the client.
+func toSQLError(e error) *mysql.SQLError {
+	originErr := errors.Cause(e)
+	if te, ok := originErr.(*terror.Error); ok {
+		return terror.ToSQLError(te)
+	}
+	e = errors.Cause(originErr)
+	switch y := e.(type) {
+	case *terror.Error:
+		return terror.ToSQLError(y)
+	default:
+		return mysql.NewErrf(mysql.ErrUnknown, "%s", nil, e.Error())
+	}
+}
+
+// maxExecTimeError returns ErrMaxExecTimeExceeded instead of the interruption error if the statement is killed
+// because it exceeds max_execution_time, so the client can tell it from KILL QUERY.
+func (cc *clientConn) maxExecTimeError(err error) error {
+	if cc.ctx == nil || !cc.ctx.GetSessionVars().StmtCtx.MaxExecTimeExceeded.Load() {
+		return err
+	}
+	if errors.Cause(err) == context.Canceled || toSQLError(err).Code == mysql.ErrQueryInterrupted {
+		return executor.ErrMaxExecTimeExceeded
+	}
+	return err
+}
+
 // writeEOF writes an EOF packet.
 // Note this function won't flush the stream because maybe there are more
 // packets following it.
@@ -1790,6 +1969,7 @@ func (cc *clientConn) handleQuery(ctx context.Context, sql string) (err error) {
 	if err != nil {
 		return err
 	}
+	cc.secureText = secureTextOfStmts(stmts)
 
 	if len(stmts) == 0 {
 		return cc.writeOK(ctx)
@@ -2181,6 +2361,7 @@ func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool
 		}
 		reg := trace.StartRegion(ctx, "WriteClientConn")
 		start := time.Now()
+		egressBytes := 0
 		for i := 0; i < rowCount; i++ {
 			data = data[0:4]
 			if binary {
@@ -2196,8 +2377,12 @@ func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool
 				reg.End()
 				return false, err
 			}
+			egressBytes += len(data)
 		}
 		reg.End()
+		if err = cc.throttleEgress(ctx, egressBytes); err != nil {
+			return false, err
+		}
 		if stmtDetail != nil {
 			stmtDetail.WriteSQLRespDuration += time.Since(start)
 		}
@@ -2205,6 +2390,32 @@ func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool
 	return false, cc.writeEOF(serverStatus)
 }
 
+// throttleEgress blocks until the connection and its resource group are allowed to send n more bytes, according to
+// tidb_conn_egress_bytes_per_sec and the EGRESS_BYTES_PER_SEC of the resource group.
+func (cc *clientConn) throttleEgress(ctx context.Context, n int) error {
+	if n == 0 {
+		return nil
+	}
+	if rate := variable.ConnEgressBytesPerSec.Load(); rate != cc.egressRate {
+		cc.egressRate, cc.egress = rate, nil
+		if rate > 0 {
+			cc.egress = resourcegroup.NewTokenBucket(rate)
+		}
+	}
+	if cc.egress != nil {
+		if err := cc.egress.Wait(ctx, float64(n)); err != nil {
+			return err
+		}
+	}
+	if cc.ctx == nil {
+		return nil
+	}
+	if bucket := resourcegroup.GlobalController.EgressBucket(cc.ctx.GetSessionVars().ResourceGroupName); bucket != nil {
+		return bucket.Wait(ctx, float64(n))
+	}
+	return nil
+}
+
 // writeChunksWithFetchSize writes data from a Chunk, which filled data by a ResultSet, into a connection.
 // binary specifies the way to dump data. It throws any error while dumping data.
 // serverStatus, a flag bit represents server information.
@@ -2212,14 +2423,15 @@ func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool
 func (cc *clientConn) writeChunksWithFetchSize(ctx context.Context, rs ResultSet, serverStatus uint16, fetchSize int) error {
 	fetchedRows := rs.GetFetchedRows()
 	// if fetchedRows is not enough, getting data from recordSet.
-	req := rs.NewChunk(nil)
 	for len(fetchedRows) < fetchSize {
 		// NOTE: chunk should not be allocated from the allocator
 		// the allocator will reset every statement
 		// but it maybe stored in the result set among statements
 		// ref https://github.com/pingcap/tidb/blob/7fc6ebbda4ddf84c0ba801ca7ebb636b934168cf/server/conn_stmt.go#L233-L239
+		// The chunk can't be reused either, the rows kept in fetchedRows still refer to it,
+		// so only the chunks really needed by the client are pulled from the executor.
 		// Here server.tidbResultSet implements Next method.
-		req.Reset()
+		req := rs.NewChunk(nil)
 		if err := rs.Next(ctx, req); err != nil {
 			return err
 		}
@@ -2261,6 +2473,7 @@ func (cc *clientConn) writeChunksWithFetchSize(ctx context.Context, rs ResultSet
 	}
 	start := time.Now()
 	var err error
+	egressBytes := 0
 	for _, row := range curRows {
 		data = data[0:4]
 		data, err = dumpBinaryRow(data, rs.Columns(), row, cc.rsEncoder)
@@ -2270,6 +2483,10 @@ func (cc *clientConn) writeChunksWithFetchSize(ctx context.Context, rs ResultSet
 		if err = cc.writePacket(data); err != nil {
 			return err
 		}
+		egressBytes += len(data)
+	}
+	if err = cc.throttleEgress(ctx, egressBytes); err != nil {
+		return err
 	}
 	if stmtDetail != nil {
 		stmtDetail.WriteSQLRespDuration += time.Since(start)
@@ -2320,13 +2537,23 @@ func (cc *clientConn) handleChangeUser(ctx context.Context, data []byte) error {
 	if err := cc.ctx.Close(); err != nil {
 		logutil.Logger(ctx).Debug("close old context failed", zap.Error(err))
 	}
-	if err := cc.openSessionAndDoAuth(pass, ""); err != nil {
+	err := cc.openSessionAndDoAuth(pass, "")
+	cc.logAuditConnection(audit.EventChangeUser, err)
+	if err != nil {
 		return err
 	}
 	return cc.handleCommonConnectionReset(ctx)
 }
 
 func (cc *clientConn) handleResetConnection(ctx context.Context) error {
+	if err := cc.reopenSession(ctx); err != nil {
+		return err
+	}
+	return cc.handleCommonConnectionReset(ctx)
+}
+
+// reopenSession replaces the session with a new one of the same user and the same current database.
+func (cc *clientConn) reopenSession(ctx context.Context) error {
 	user := cc.ctx.GetSessionVars().User
 	err := cc.ctx.Close()
 	if err != nil {
@@ -2341,6 +2568,7 @@ func (cc *clientConn) handleResetConnection(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	cc.ctx.GetSessionVars().ConnectionAttrs = cc.attrs
 	if !cc.ctx.AuthWithoutVerification(user) {
 		return errors.New("Could not reset connection")
 	}
@@ -2351,8 +2579,7 @@ func (cc *clientConn) handleResetConnection(ctx context.Context) error {
 		}
 	}
 	cc.ctx.SetSessionManager(cc.server)
-
-	return cc.handleCommonConnectionReset(ctx)
+	return nil
 }
 
 func (cc *clientConn) handleCommonConnectionReset(ctx context.Context) error {
@@ -2405,7 +2632,7 @@ func (cc getLastStmtInConn) String() string {
 		return "ListFields " + string(data)
 	case mysql.ComQuery, mysql.ComStmtPrepare:
 		sql := string(hack.String(data))
-		if cc.ctx.GetSessionVars().EnableRedactLog {
+		if cc.ctx.GetSessionVars().ShouldRedactLog() {
 			sql = parser.Normalize(sql)
 		}
 		return tidbutil.QueryStrForLog(sql)
@@ -22,9 +22,11 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/parser/model"
@@ -102,6 +104,20 @@ func TestParseHandshakeResponse(t *testing.T) {
 	require.Equal(t, "pam", p.User)
 	require.Equal(t, "test", p.DBName)
 
+	// The zstd compression level follows the other fields.
+	data[3] = byte(mysql.ClientZstdCompressionAlgorithm >> 24)
+	data = append(data, 0x07)
+	p = handshakeResponse41{}
+	offset, err = parseHandshakeResponseHeader(context.Background(), &p, data)
+	require.NoError(t, err)
+	require.Equal(t, mysql.ClientZstdCompressionAlgorithm, p.Capability&mysql.ClientZstdCompressionAlgorithm)
+	err = parseHandshakeResponseBody(context.Background(), &p, data, offset)
+	require.NoError(t, err)
+	require.Equal(t, "test", p.DBName)
+	require.Equal(t, 7, p.ZstdLevel)
+	err = parseHandshakeResponseBody(context.Background(), &p, data[:len(data)-1], offset)
+	require.Equal(t, mysql.ErrMalformPacket, err)
+
 	// Test for compatibility of Protocol::HandshakeResponse320
 	data = []byte{
 		0x00, 0x80, 0x00, 0x00, 0x01, 0x72, 0x6f, 0x6f, 0x74, 0x00, 0x00,
@@ -168,6 +184,52 @@ func TestAuthSwitchRequest(t *testing.T) {
 	require.Equal(t, "caching_sha2_password", resp.AuthPlugin)
 }
 
+func TestLDAPAuthSwitchRequest(t *testing.T) {
+	salt := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11, 0x12, 0x13, 0x14}
+	for _, c := range []struct {
+		plugin       string
+		clientPlugin string
+		pluginData   []byte
+		response     []byte
+		authData     []byte
+	}{
+		// The password in clear text is terminated by NULL.
+		{mysql.AuthLDAPSimple, mysql.AuthMySQLClearPassword, salt, []byte("secret\x00"), []byte("secret")},
+		// The mechanism is sent to start the SASL exchange.
+		{mysql.AuthLDAPSASL, authLDAPSASLClient, []byte("SCRAM-SHA-1"), []byte("n,,n=u1,r=nonce"), []byte("n,,n=u1,r=nonce")},
+	} {
+		var in bytesConn
+		var out bytes.Buffer
+		// The response of the client follows the switch request, whose sequence is 0.
+		in.b.Write([]byte{byte(len(c.response)), 0, 0, 1})
+		in.b.Write(c.response)
+		cc := &clientConn{
+			salt:  salt,
+			alloc: arena.NewAllocator(512),
+			pkt:   newPacketIO(newBufferedReadConn(&in)),
+		}
+		cc.pkt.bufWriter = bufio.NewWriter(&out)
+		authData, err := cc.authSwitchRequest(context.Background(), c.plugin)
+		require.NoError(t, err)
+		require.Equal(t, c.authData, authData)
+		require.Equal(t, c.plugin, cc.authPlugin)
+
+		expected := []byte{mysql.AuthSwitchRequest}
+		expected = append(expected, c.clientPlugin...)
+		expected = append(expected, 0)
+		expected = append(expected, c.pluginData...)
+		expected = append(expected, 0)
+		require.Equal(t, expected, out.Bytes()[4:])
+	}
+
+	// The extra data of the authentication is sent as AuthMoreData.
+	var conn loopbackConn
+	cc := &clientConn{pkt: newPacketIO(newBufferedReadConn(&conn))}
+	require.NoError(t, cc.WriteAuthMoreData([]byte("challenge")))
+	require.NoError(t, cc.Flush(context.Background()))
+	require.Equal(t, append([]byte{10, 0, 0, 0, mysql.AuthMoreData}, "challenge"...), conn.b.Bytes())
+}
+
 func TestInitialHandshake(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -513,6 +575,91 @@ func testDispatch(t *testing.T, inputs []dispatchInput, capability uint32) {
 	}
 }
 
+func TestAdmissionControl(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	se, err := session.CreateSession4Test(store)
+	require.NoError(t, err)
+	tc := &TiDBContext{
+		Session: se,
+		stmts:   make(map[int]*TiDBStatement),
+	}
+
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TokenLimitAction = config.TokenLimitActionReject
+	})
+	var outBuffer bytes.Buffer
+	cfg := newTestConfig()
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	cfg.TokenLimit = 1
+	server, err := NewServer(cfg, NewTiDBDriver(store))
+	require.NoError(t, err)
+	defer server.Close()
+
+	cc := &clientConn{
+		connectionID: 1,
+		server:       server,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(&outBuffer),
+		},
+		collation:  mysql.DefaultCollationID,
+		peerHost:   "localhost",
+		alloc:      arena.NewAllocator(512),
+		chunkAlloc: chunk.NewAllocator(),
+		ctx:        tc,
+	}
+	query := append([]byte{mysql.ComQuery}, []byte("do 1")...)
+
+	// All the tokens are in use.
+	token, err := server.getToken(context.Background(), true)
+	require.NoError(t, err)
+	err = cc.dispatch(context.Background(), query)
+	require.True(t, errAdmissionRejected.Equal(err))
+	require.Contains(t, err.Error(), "token-limit(1)")
+	server.releaseToken(token)
+	require.NoError(t, cc.dispatch(context.Background(), query))
+
+	// The memory used by executors exceeds server-memory-quota.
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Performance.ServerMemoryQuota = 1
+	})
+	executor.GlobalMemoryUsageTracker.Consume(1)
+	err = cc.dispatch(context.Background(), query)
+	require.True(t, errAdmissionRejected.Equal(err))
+	require.Contains(t, err.Error(), "server-memory-quota(1)")
+
+	// The statement is queued until the memory is released.
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TokenLimitAction = config.TokenLimitActionQueue
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- cc.dispatch(context.Background(), query)
+	}()
+	select {
+	case err = <-done:
+		require.FailNow(t, "statement should be queued", "err: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	executor.GlobalMemoryUsageTracker.Consume(-1)
+	require.NoError(t, <-done)
+
+	// The statement fails after waiting for token-limit-queue-timeout.
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TokenLimitQueueTimeout = 1
+	})
+	token, err = server.getToken(context.Background(), true)
+	require.NoError(t, err)
+	err = cc.dispatch(context.Background(), query)
+	require.True(t, errAdmissionRejected.Equal(err))
+	require.Contains(t, err.Error(), "token-limit-queue-timeout(1s)")
+	server.releaseToken(token)
+	require.NoError(t, cc.dispatch(context.Background(), query))
+}
+
 func TestGetSessionVarsWaitTimeout(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -533,6 +680,37 @@ func TestGetSessionVarsWaitTimeout(t *testing.T) {
 	require.Equal(t, uint64(variable.DefWaitTimeout), cc.getSessionVarsWaitTimeout(context.Background()))
 }
 
+func TestGetIdleTxnTimeout(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	se, err := session.CreateSession4Test(store)
+	require.NoError(t, err)
+	tc := &TiDBContext{
+		Session: se,
+		stmts:   make(map[int]*TiDBStatement),
+	}
+	cc := &clientConn{
+		connectionID: 1,
+		server: &Server{
+			capability: defaultCapability,
+		},
+		ctx: tc,
+	}
+	tk := testkit.NewTestKit(t, store)
+	tk.SetSession(se)
+	tk.MustExec("set @@tidb_idle_transaction_timeout = 10")
+	require.Equal(t, time.Duration(0), cc.getIdleTxnTimeout())
+	tk.MustExec("begin")
+	require.Equal(t, 10*time.Second, cc.getIdleTxnTimeout())
+	tk.MustExec("commit")
+	require.Equal(t, time.Duration(0), cc.getIdleTxnTimeout())
+	tk.MustExec("set @@tidb_idle_transaction_timeout = 0")
+	tk.MustExec("begin")
+	require.Equal(t, time.Duration(0), cc.getIdleTxnTimeout())
+	tk.MustExec("rollback")
+}
+
 func mapIdentical(m1, m2 map[string]string) bool {
 	return mapBelong(m1, m2) && mapBelong(m2, m1)
 }
@@ -728,6 +906,120 @@ func testGetTableByName(t *testing.T, ctx sessionctx.Context, db, table string)
 	return tbl
 }
 
+func TestCursorFetchAcrossChunks(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	var outBuffer bytes.Buffer
+	cc := &clientConn{
+		alloc:      arena.NewAllocator(1024),
+		chunkAlloc: chunk.NewAllocator(),
+		capability: mysql.ClientProtocol41,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(&outBuffer),
+		},
+	}
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	cc.ctx = &TiDBContext{Session: tk.Session(), stmts: make(map[int]*TiDBStatement)}
+
+	tk.MustExec("create table t(a bigint)")
+	dml := "insert into t values"
+	for i := 0; i < 100; i++ {
+		if i != 0 {
+			dml += ","
+		}
+		dml += fmt.Sprintf("(%v)", i)
+	}
+	tk.MustExec(dml)
+	// Every COM_STMT_FETCH needs more than one chunk from the executor.
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	ctx := context.Background()
+	require.NoError(t, cc.handleStmtPrepare(ctx, "select a from t order by a"))
+	require.NoError(t, cc.handleStmtExecute(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0}))
+
+	// fetchRows sends COM_STMT_FETCH and decodes the bigint rows and the status of the EOF packet.
+	fetchRows := func(fetchSize byte) ([]int64, uint16) {
+		outBuffer.Reset()
+		require.NoError(t, cc.handleStmtFetch(ctx, []byte{0x1, 0x0, 0x0, 0x0, fetchSize, 0x0, 0x0, 0x0}))
+		require.NoError(t, cc.flush(ctx))
+		var rows []int64
+		data := outBuffer.Bytes()
+		for len(data) > 0 {
+			length := int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+			payload := data[4 : 4+length]
+			data = data[4+length:]
+			if payload[0] == mysql.EOFHeader {
+				return rows, binary.LittleEndian.Uint16(payload[3:5])
+			}
+			// Header, NULL bitmap and the 8 bytes bigint.
+			require.Len(t, payload, 10)
+			rows = append(rows, int64(binary.LittleEndian.Uint64(payload[2:])))
+		}
+		require.FailNow(t, "EOF packet is expected")
+		return nil, 0
+	}
+	expected := make([]int64, 0, 100)
+	for i := 0; i < 100; i++ {
+		expected = append(expected, int64(i))
+	}
+	rows, status := fetchRows(50)
+	require.Equal(t, expected[:50], rows)
+	require.True(t, status&mysql.ServerStatusCursorExists > 0)
+	rows, _ = fetchRows(45)
+	require.Equal(t, expected[50:95], rows)
+	rows, _ = fetchRows(45)
+	require.Equal(t, expected[95:], rows)
+	rows, status = fetchRows(45)
+	require.Len(t, rows, 0)
+	require.True(t, status&mysql.ServerStatusLastRowSend > 0)
+	require.False(t, status&mysql.ServerStatusCursorExists > 0)
+}
+
+func TestConnEgressThrottle(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	var outBuffer bytes.Buffer
+	cc := &clientConn{
+		alloc:      arena.NewAllocator(1024),
+		chunkAlloc: chunk.NewAllocator(),
+		capability: mysql.ClientProtocol41,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(&outBuffer),
+		},
+	}
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	cc.ctx = &TiDBContext{Session: tk.Session(), stmts: make(map[int]*TiDBStatement)}
+	tk.MustExec("create table t(a varchar(255))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec("insert into t values (repeat('a', 200))")
+	}
+
+	// The result set is about 20KB, which takes about 20s at 1KB per second.
+	query := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		return cc.handleQuery(ctx, "select a from t")
+	}
+	require.NoError(t, query())
+	tk.MustExec("set @@global.tidb_conn_egress_bytes_per_sec = 1024")
+	defer tk.MustExec("set @@global.tidb_conn_egress_bytes_per_sec = default")
+	require.ErrorIs(t, query(), context.DeadlineExceeded)
+	tk.MustExec("set @@global.tidb_conn_egress_bytes_per_sec = 0")
+	require.NoError(t, query())
+
+	// The connections of the resource group are throttled by its EGRESS_BYTES_PER_SEC.
+	tk.MustExec("create resource group rg1 RU_PER_SEC = 1000 EGRESS_BYTES_PER_SEC = 1024")
+	defer tk.MustExec("drop resource group rg1")
+	tk.MustExec("set resource group rg1")
+	require.ErrorIs(t, query(), context.DeadlineExceeded)
+	tk.MustExec("alter resource group rg1 RU_PER_SEC = 1000")
+	require.NoError(t, query())
+}
+
 func TestTiFlashFallback(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t,
 		mockstore.WithClusterInspector(func(c testutils.Cluster) {
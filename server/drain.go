@@ -0,0 +1,133 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// DrainStatus reports the progress of draining the server.
+type DrainStatus struct {
+	Draining bool      `json:"draining"`
+	Start    time.Time `json:"start"`
+	// Deadline is zero if the server waits for the transactions without a deadline.
+	Deadline    time.Time `json:"deadline"`
+	Connections int       `json:"connections"`
+	ActiveTxns  int       `json:"active_txns"`
+	// Finished is true if all the connections have been closed.
+	Finished bool `json:"finished"`
+}
+
+// Drain puts the server into the draining state for rolling restarts. The server reports
+// unhealthy on the status API and rejects new connections, the idle connections are closed
+// and the others are closed once their transactions finish. If timeout is positive,
+// the remaining connections are killed when it is reached.
+func (s *Server) Drain(timeout time.Duration) error {
+	if timeout < 0 {
+		return errors.Errorf("invalid drain timeout %v", timeout)
+	}
+	s.drain.Lock()
+	defer s.drain.Unlock()
+	if s.drain.cancel != nil {
+		return errors.New("the server is already draining")
+	}
+
+	var ctx context.Context
+	s.drain.start = time.Now()
+	if timeout > 0 {
+		s.drain.deadline = s.drain.start.Add(timeout)
+		ctx, s.drain.cancel = context.WithDeadline(context.Background(), s.drain.deadline)
+	} else {
+		s.drain.deadline = time.Time{}
+		ctx, s.drain.cancel = context.WithCancel(context.Background())
+	}
+	logutil.BgLogger().Info("start draining the server", zap.Duration("timeout", timeout))
+	metrics.ServerEventCounter.WithLabelValues(metrics.EventDrain).Inc()
+	go s.drainConnections(ctx)
+	return nil
+}
+
+// StopDrain quits the draining state, the server accepts new connections again.
+func (s *Server) StopDrain() {
+	s.drain.Lock()
+	defer s.drain.Unlock()
+	if s.drain.cancel == nil {
+		return
+	}
+	s.drain.cancel()
+	s.drain.cancel = nil
+	logutil.BgLogger().Info("stop draining the server")
+}
+
+// GetDrainStatus returns the progress of draining the server.
+func (s *Server) GetDrainStatus() DrainStatus {
+	s.drain.Lock()
+	st := DrainStatus{
+		Draining: s.drain.cancel != nil,
+		Start:    s.drain.start,
+		Deadline: s.drain.deadline,
+	}
+	s.drain.Unlock()
+
+	s.rwlock.RLock()
+	st.Connections = len(s.clients)
+	for _, cc := range s.clients {
+		if cc.ctx.Status()&mysql.ServerStatusInTrans > 0 {
+			st.ActiveTxns++
+		}
+	}
+	s.rwlock.RUnlock()
+	st.Finished = st.Draining && st.Connections == 0
+	return st
+}
+
+func (s *Server) isDraining() bool {
+	s.drain.Lock()
+	defer s.drain.Unlock()
+	return s.drain.cancel != nil
+}
+
+// drainConnections closes the connections once they are not in a transaction until all of them are closed.
+// It kills the remaining connections if the deadline of ctx is reached.
+func (s *Server) drainConnections(ctx context.Context) {
+	for i := 0; ; i++ {
+		s.kickIdleConnection()
+		count := s.ConnectionCount()
+		if count == 0 {
+			logutil.BgLogger().Info("all the connections are closed by draining")
+			return
+		}
+		// Print information for every 30s.
+		if i%30 == 0 {
+			logutil.BgLogger().Info("draining the server...", zap.Int("conn count", count))
+		}
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				logutil.BgLogger().Warn("draining the server timed out", zap.Int("conn count", count))
+				s.KillAllConnections()
+			}
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -196,6 +197,7 @@ func (qd *TiDBDriver) OpenCtx(connID uint64, capability uint32, collation uint8,
 		currentDB: dbname,
 		stmts:     make(map[int]*TiDBStatement),
 	}
+	se.SetSessionStatesHandler(&sessionStatesHandler{tc: tc})
 	return tc, nil
 }
 
@@ -290,6 +292,57 @@ func (tc *TiDBContext) Prepare(sql string) (statement PreparedStatement, columns
 	return
 }
 
+// sessionStatesHandler migrates the states of the statements prepared by the binary protocol, which are kept by
+// TiDBContext instead of the session.
+type sessionStatesHandler struct {
+	tc *TiDBContext
+}
+
+// EncodeSessionStates implements sessionstates.Handler EncodeSessionStates interface.
+func (h *sessionStatesHandler) EncodeSessionStates(_ context.Context, sessionStates *sessionstates.SessionStates) error {
+	for id, stmt := range h.tc.stmts {
+		// The result set of the cursor can't be migrated.
+		if stmt.rs != nil {
+			return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("prepared statement has an open cursor")
+		}
+		info, ok := sessionStates.PreparedStmts[uint32(id)]
+		if !ok {
+			return errors.Errorf("prepared statement %d is not found in the session", id)
+		}
+		info.ParamTypes = stmt.paramsType
+		info.BoundParams = stmt.boundParams
+	}
+	return nil
+}
+
+// DecodeSessionStates implements sessionstates.Handler DecodeSessionStates interface.
+func (h *sessionStatesHandler) DecodeSessionStates(_ context.Context, sessionStates *sessionstates.SessionStates) error {
+	for id, info := range sessionStates.PreparedStmts {
+		// The statements prepared by the `PREPARE` statement have names, they are kept by the session only.
+		if len(info.Name) > 0 {
+			continue
+		}
+		preparedObj, ok := h.tc.GetSessionVars().PreparedStmts[id].(*core.CachedPrepareStmt)
+		if !ok {
+			return errors.Errorf("invalid CachedPrepareStmt type")
+		}
+		numParams := len(preparedObj.PreparedAst.Params)
+		boundParams := info.BoundParams
+		if len(boundParams) != numParams {
+			boundParams = make([][]byte, numParams)
+		}
+		h.tc.stmts[int(id)] = &TiDBStatement{
+			sql:         info.StmtText,
+			id:          id,
+			numParams:   numParams,
+			boundParams: boundParams,
+			paramsType:  info.ParamTypes,
+			ctx:         h.tc,
+		}
+	}
+	return nil
+}
+
 type tidbResultSet struct {
 	recordSet    sqlexec.RecordSet
 	columns      []*ColumnInfo
@@ -0,0 +1,654 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/store/helper"
+	"github.com/pingcap/tidb/util/admin"
+	"github.com/pingcap/tidb/util/fastrand"
+	"github.com/pingcap/tidb/util/pdapi"
+)
+
+const (
+	apiV2Prefix = "/api/v2"
+
+	// defaultAPIV2Limit is the page size if the limit is not specified.
+	defaultAPIV2Limit = 100
+	maxAPIV2Limit     = 1000
+)
+
+// apiV2Page is a page of the items listed by the v2 API.
+type apiV2Page struct {
+	Total  int         `json:"total"`
+	Offset int         `json:"offset"`
+	Limit  int         `json:"limit"`
+	Items  interface{} `json:"items"`
+}
+
+// apiV2Error is the body of the responses of the v2 API when the request fails.
+type apiV2Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// apiV2Table is a table listed by the v2 API.
+type apiV2Table struct {
+	DB         string   `json:"db"`
+	Table      string   `json:"table"`
+	ID         int64    `json:"id"`
+	Partitions []string `json:"partitions,omitempty"`
+	Indices    []string `json:"indices,omitempty"`
+}
+
+// apiV2Region is a region of a table or an index listed by the v2 API.
+type apiV2Region struct {
+	TableID   int64  `json:"table_id"`
+	Partition string `json:"partition,omitempty"`
+	// Index is empty for the regions of the records.
+	Index string `json:"index,omitempty"`
+	RegionMeta
+}
+
+// apiV2StatsHealth is the stats healthy of a table or a partition, the same as SHOW STATS_HEALTHY.
+type apiV2StatsHealth struct {
+	DB        string `json:"db"`
+	Table     string `json:"table"`
+	Partition string `json:"partition,omitempty"`
+	Healthy   int64  `json:"healthy"`
+}
+
+// apiV2Handler serves the versioned status API under /api/v2. All the endpoints require the HTTP basic
+// authentication. The endpoints listing the items return apiV2Page which is paginated by offset and limit,
+// and require the PROCESS privilege. The endpoints changing the server require the CONFIG, the SUPER or the
+// SHUTDOWN privilege.
+type apiV2Handler struct {
+	*tikvHandlerTool
+}
+
+func (s *Server) registerAPIV2(router *mux.Router, tool *tikvHandlerTool) {
+	h := apiV2Handler{tool}
+	r := router.PathPrefix(apiV2Prefix).Subrouter()
+	handle := func(path string, priv mysql.PrivilegeType, f http.HandlerFunc) *mux.Route {
+		return r.Handle(path, h.authenticate(priv, f))
+	}
+	handle("/tables", mysql.ProcessPriv, h.handleTables).Methods(http.MethodGet).Name("APIV2Tables")
+	handle("/ddl/jobs", mysql.ProcessPriv, h.handleDDLJobs).Methods(http.MethodGet).Name("APIV2DDLJobs")
+	handle("/stats/health", mysql.ProcessPriv, h.handleStatsHealth).Methods(http.MethodGet).Name("APIV2StatsHealth")
+	if s.cfg.Store == "tikv" {
+		handle("/tables/{db}/{table}/regions", mysql.ProcessPriv, h.handleTableRegions).Methods(http.MethodGet).Name("APIV2TableRegions")
+		handle("/hot-tables", mysql.ProcessPriv, h.handleHotTables).Methods(http.MethodGet).Name("APIV2HotTables")
+	}
+	handle("/proxy-protocol/networks", mysql.ConfigPriv, s.handleProxyProtocolNetworks).
+		Methods(http.MethodGet, http.MethodPost).Name("APIV2ProxyProtocolNetworks")
+	handle("/tls", mysql.ConfigPriv, s.handleTLS).Methods(http.MethodGet, http.MethodPost).Name("APIV2TLS")
+	handle("/traffic/capture", mysql.SuperPriv, s.handleTrafficCapture).Methods(http.MethodGet, http.MethodPost).Name("APIV2TrafficCapture")
+	handle("/drain", mysql.ShutdownPriv, s.handleDrain).Methods(http.MethodGet, http.MethodPost).Name("APIV2Drain")
+	r.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		writeAPIV2Error(w, http.StatusNotFound, errors.Errorf("%s is not found", req.URL.Path))
+	})
+}
+
+func writeAPIV2Error(w http.ResponseWriter, code int, err error) {
+	js, marshalErr := json.Marshal(apiV2Error{Code: code, Message: err.Error()})
+	terror.Log(errors.Trace(marshalErr))
+	w.Header().Set(headerContentType, contentTypeJSON)
+	w.WriteHeader(code)
+	_, err = w.Write(js)
+	terror.Log(errors.Trace(err))
+}
+
+// authenticate checks the user of the HTTP basic authentication in the same way as the MySQL protocol,
+// and the user must have the global privilege priv and a password that has not expired.
+func (h apiV2Handler) authenticate(priv mysql.PrivilegeType, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		user, password, ok := req.BasicAuth()
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="TiDB"`)
+			writeAPIV2Error(w, http.StatusUnauthorized, errors.New("authentication is required"))
+			return
+		}
+		host, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			writeAPIV2Error(w, http.StatusBadRequest, err)
+			return
+		}
+		code, err := h.checkUser(user, host, password, priv)
+		if err != nil {
+			if code == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Basic realm="TiDB"`)
+			}
+			writeAPIV2Error(w, code, err)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (h apiV2Handler) checkUser(user, host, password string, priv mysql.PrivilegeType) (int, error) {
+	se, err := session.CreateSession(h.Store.(kv.Storage))
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	defer se.Close()
+
+	errAuth := errors.Errorf("access denied for user '%s'@'%s'", user, host)
+	identity, err := se.MatchIdentity(user, host)
+	if err != nil {
+		return http.StatusUnauthorized, errAuth
+	}
+	pm := privilege.GetPrivilegeManager(se)
+	plugin, err := pm.GetAuthPlugin(identity.Username, identity.Hostname)
+	if err != nil {
+		return http.StatusUnauthorized, errAuth
+	}
+	var authentication, salt []byte
+	switch plugin {
+	case "":
+		// The user has no password, only an empty password is accepted.
+		authentication = []byte(password)
+	case mysql.AuthNativePassword:
+		salt = fastrand.Buf(20)
+		authentication = scramblePassword(salt, password)
+	case mysql.AuthCachingSha2Password, mysql.AuthTiDBSM3Password:
+		// caching_sha2_password and tidb_sm3_password check the clear text password, the same as the MySQL protocol over TLS.
+		authentication = []byte(password)
+	default:
+		return http.StatusUnauthorized, errors.Errorf("the authentication plugin %s is not supported", plugin)
+	}
+	if !se.Auth(&auth.UserIdentity{Username: user, Hostname: host}, authentication, salt) {
+		return http.StatusUnauthorized, errAuth
+	}
+	if se.GetSessionVars().InSandboxMode {
+		// The user can only change the expired password by the MySQL protocol.
+		return http.StatusForbidden, errors.Errorf("the password of user '%s'@'%s' has expired, it must be changed before using the API", user, host)
+	}
+	if !pm.RequestVerification(se.GetSessionVars().ActiveRoles, "", "", "", priv) {
+		return http.StatusForbidden, errors.Errorf("the %s privilege is required", strings.ToUpper(priv.String()))
+	}
+	return http.StatusOK, nil
+}
+
+// scramblePassword calculates the auth response of mysql_native_password from the clear text password.
+func scramblePassword(salt []byte, password string) []byte {
+	if len(password) == 0 {
+		return nil
+	}
+	// stage1 = SHA1(password), stage2 = SHA1(stage1), response = stage1 XOR SHA1(salt + stage2)
+	stage1 := auth.Sha1Hash([]byte(password))
+	stage2 := auth.Sha1Hash(stage1)
+	scramble := auth.Sha1Hash(append(append(make([]byte, 0, len(salt)+len(stage2)), salt...), stage2...))
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+	return scramble
+}
+
+// parsePage parses the offset and the limit of the page from the request.
+func parsePage(req *http.Request) (offset, limit int, err error) {
+	limit = defaultAPIV2Limit
+	if str := req.FormValue(qOffset); str != "" {
+		if offset, err = strconv.Atoi(str); err != nil || offset < 0 {
+			return 0, 0, errors.Errorf("invalid offset %s", str)
+		}
+	}
+	if str := req.FormValue(qLimit); str != "" {
+		if limit, err = strconv.Atoi(str); err != nil || limit < 1 || limit > maxAPIV2Limit {
+			return 0, 0, errors.Errorf("invalid limit %s, it should be between 1 and %d", str, maxAPIV2Limit)
+		}
+	}
+	return offset, limit, nil
+}
+
+// pageRange returns the range of the items in the page.
+func pageRange(total, offset, limit int) (start, end int) {
+	if offset > total {
+		offset = total
+	}
+	end = offset + limit
+	if end > total {
+		end = total
+	}
+	return offset, end
+}
+
+// matchName checks whether name matches the filter of the request, the filter is case-insensitive.
+func matchName(req *http.Request, key, name string) bool {
+	filter := req.FormValue(key)
+	return filter == "" || strings.EqualFold(filter, name)
+}
+
+// handleTables lists the tables sorted by the database and the table names, it can be filtered by db and table.
+func (h apiV2Handler) handleTables(w http.ResponseWriter, req *http.Request) {
+	offset, limit, err := parsePage(req)
+	if err != nil {
+		writeAPIV2Error(w, http.StatusBadRequest, err)
+		return
+	}
+	schema, err := h.schema()
+	if err != nil {
+		writeAPIV2Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	tables := make([]apiV2Table, 0)
+	for _, db := range schema.AllSchemas() {
+		if !matchName(req, pDBName, db.Name.O) {
+			continue
+		}
+		for _, tbl := range schema.SchemaTables(db.Name) {
+			meta := tbl.Meta()
+			if !matchName(req, pTableName, meta.Name.O) {
+				continue
+			}
+			item := apiV2Table{DB: db.Name.O, Table: meta.Name.O, ID: meta.ID}
+			if pi := meta.GetPartitionInfo(); pi != nil {
+				for _, def := range pi.Definitions {
+					item.Partitions = append(item.Partitions, def.Name.O)
+				}
+			}
+			for _, idx := range meta.Indices {
+				item.Indices = append(item.Indices, idx.Name.O)
+			}
+			tables = append(tables, item)
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].DB != tables[j].DB {
+			return tables[i].DB < tables[j].DB
+		}
+		return tables[i].Table < tables[j].Table
+	})
+	start, end := pageRange(len(tables), offset, limit)
+	writeData(w, apiV2Page{Total: len(tables), Offset: offset, Limit: limit, Items: tables[start:end]})
+}
+
+// handleTableRegions lists the regions of the records and the indices of a table, it can be filtered by
+// partition and index, the regions of the records are not listed if index is specified.
+func (h apiV2Handler) handleTableRegions(w http.ResponseWriter, req *http.Request) {
+	offset, limit, err := parsePage(req)
+	if err != nil {
+		writeAPIV2Error(w, http.StatusBadRequest, err)
+		return
+	}
+	params := mux.Vars(req)
+	schema, err := h.schema()
+	if err != nil {
+		writeAPIV2Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	tbl, err := schema.TableByName(model.NewCIStr(params[pDBName]), model.NewCIStr(params[pTableName]))
+	if err != nil {
+		writeAPIV2Error(w, http.StatusNotFound, err)
+		return
+	}
+	meta := tbl.Meta()
+	physicalTables := []model.PartitionDefinition{{ID: meta.ID}}
+	if pi := meta.GetPartitionInfo(); pi != nil {
+		physicalTables = pi.Definitions
+	}
+
+	th := tableHandler{tikvHandlerTool: h.tikvHandlerTool, op: opTableRegions}
+	regions := make([]apiV2Region, 0)
+	for _, pt := range physicalTables {
+		if !matchName(req, "partition", pt.Name.O) {
+			continue
+		}
+		tableRegions, err := th.getRegionsByID(tbl, pt.ID, pt.Name.O)
+		if err != nil {
+			writeAPIV2Error(w, http.StatusInternalServerError, err)
+			return
+		}
+		if req.FormValue("index") == "" {
+			for _, region := range tableRegions.RecordRegions {
+				regions = append(regions, apiV2Region{TableID: pt.ID, Partition: pt.Name.O, RegionMeta: region})
+			}
+		}
+		for _, idx := range tableRegions.Indices {
+			if !matchName(req, "index", idx.Name) {
+				continue
+			}
+			for _, region := range idx.Regions {
+				regions = append(regions, apiV2Region{TableID: pt.ID, Partition: pt.Name.O, Index: idx.Name, RegionMeta: region})
+			}
+		}
+	}
+	start, end := pageRange(len(regions), offset, limit)
+	writeData(w, apiV2Page{Total: len(regions), Offset: offset, Limit: limit, Items: regions[start:end]})
+}
+
+// handleDDLJobs lists the DDL jobs in the queue sorted by the job ID, or the history DDL jobs from the
+// latest one with state=history. The jobs can be filtered by db.
+func (h apiV2Handler) handleDDLJobs(w http.ResponseWriter, req *http.Request) {
+	offset, limit, err := parsePage(req)
+	if err != nil {
+		writeAPIV2Error(w, http.StatusBadRequest, err)
+		return
+	}
+	schemaID := int64(-1)
+	if dbName := req.FormValue(pDBName); dbName != "" {
+		schema, err := h.schema()
+		if err != nil {
+			writeAPIV2Error(w, http.StatusInternalServerError, err)
+			return
+		}
+		db, ok := schema.SchemaByName(model.NewCIStr(dbName))
+		if !ok {
+			writeAPIV2Error(w, http.StatusNotFound, errors.Errorf("database %s is not found", dbName))
+			return
+		}
+		schemaID = db.ID
+	}
+
+	txn, err := h.Store.Begin()
+	if err != nil {
+		writeAPIV2Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer func() {
+		terror.Log(txn.Rollback())
+	}()
+	var jobs []*model.Job
+	switch state := req.FormValue("state"); state {
+	case "", "queue":
+		jobs, err = admin.GetDDLJobs(txn)
+	case "history":
+		jobs, err = meta.NewMeta(txn).GetAllHistoryDDLJobs()
+		for i, j := 0, len(jobs)-1; i < j; i, j = i+1, j-1 {
+			jobs[i], jobs[j] = jobs[j], jobs[i]
+		}
+	default:
+		err = errors.Errorf("invalid state %s, it should be queue or history", state)
+		writeAPIV2Error(w, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		writeAPIV2Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	if schemaID >= 0 {
+		filtered := jobs[:0]
+		for _, job := range jobs {
+			if job.SchemaID == schemaID {
+				filtered = append(filtered, job)
+			}
+		}
+		jobs = filtered
+	}
+	if jobs == nil {
+		jobs = make([]*model.Job, 0)
+	}
+	start, end := pageRange(len(jobs), offset, limit)
+	writeData(w, apiV2Page{Total: len(jobs), Offset: offset, Limit: limit, Items: jobs[start:end]})
+}
+
+// handleStatsHealth lists the stats healthy of the tables and the partitions which have been analyzed.
+// It can be filtered by db, table, and max_healthy to find out the tables which need to be analyzed.
+func (h apiV2Handler) handleStatsHealth(w http.ResponseWriter, req *http.Request) {
+	offset, limit, err := parsePage(req)
+	if err != nil {
+		writeAPIV2Error(w, http.StatusBadRequest, err)
+		return
+	}
+	maxHealthy := int64(100)
+	if str := req.FormValue("max_healthy"); str != "" {
+		if maxHealthy, err = strconv.ParseInt(str, 10, 64); err != nil {
+			writeAPIV2Error(w, http.StatusBadRequest, errors.Errorf("invalid max_healthy %s", str))
+			return
+		}
+	}
+	schema, err := h.schema()
+	if err != nil {
+		writeAPIV2Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	do, err := session.GetDomain(h.Store.(kv.Storage))
+	if err != nil {
+		writeAPIV2Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	statsHandle := do.StatsHandle()
+	items := make([]apiV2StatsHealth, 0)
+	appendItem := func(db, tbl, partition string, statsTbl *statistics.Table) {
+		if healthy, ok := statsTbl.GetStatsHealthy(); ok && healthy <= maxHealthy {
+			items = append(items, apiV2StatsHealth{DB: db, Table: tbl, Partition: partition, Healthy: healthy})
+		}
+	}
+	for _, db := range schema.AllSchemas() {
+		if !matchName(req, pDBName, db.Name.O) {
+			continue
+		}
+		for _, tbl := range db.Tables {
+			if !matchName(req, pTableName, tbl.Name.O) {
+				continue
+			}
+			pi := tbl.GetPartitionInfo()
+			if pi == nil {
+				appendItem(db.Name.O, tbl.Name.O, "", statsHandle.GetTableStats(tbl))
+				continue
+			}
+			appendItem(db.Name.O, tbl.Name.O, "global", statsHandle.GetTableStats(tbl))
+			for _, def := range pi.Definitions {
+				appendItem(db.Name.O, tbl.Name.O, def.Name.O, statsHandle.GetPartitionStats(tbl, def.ID))
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].DB != items[j].DB {
+			return items[i].DB < items[j].DB
+		}
+		return items[i].Table < items[j].Table
+	})
+	start, end := pageRange(len(items), offset, limit)
+	writeData(w, apiV2Page{Total: len(items), Offset: offset, Limit: limit, Items: items[start:end]})
+}
+
+// handleHotTables lists the hot regions of the tables and the indices from PD sorted by the flow bytes,
+// type=read lists the read hot regions instead of the write ones. It can be filtered by db and table.
+func (h apiV2Handler) handleHotTables(w http.ResponseWriter, req *http.Request) {
+	offset, limit, err := parsePage(req)
+	if err != nil {
+		writeAPIV2Error(w, http.StatusBadRequest, err)
+		return
+	}
+	var rw string
+	switch tp := req.FormValue("type"); tp {
+	case "", "write":
+		rw = pdapi.HotWrite
+	case "read":
+		rw = pdapi.HotRead
+	default:
+		writeAPIV2Error(w, http.StatusBadRequest, errors.Errorf("invalid type %s, it should be read or write", tp))
+		return
+	}
+	schema, err := h.schema()
+	if err != nil {
+		writeAPIV2Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	hotTables, err := h.ScrapeHotInfo(rw, schema.AllSchemas())
+	if err != nil {
+		writeAPIV2Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	items := make([]helper.HotTableIndex, 0, len(hotTables))
+	for _, hot := range hotTables {
+		if matchName(req, pDBName, hot.DbName) && matchName(req, pTableName, hot.TableName) {
+			items = append(items, hot)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].RegionMetric.FlowBytes > items[j].RegionMetric.FlowBytes
+	})
+	start, end := pageRange(len(items), offset, limit)
+	writeData(w, apiV2Page{Total: len(items), Offset: offset, Limit: limit, Items: items[start:end]})
+}
+
+// handleProxyProtocolNetworks shows the allowed networks of PROXY protocol, and changes them by POST.
+func (s *Server) handleProxyProtocolNetworks(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodPost {
+		if err := s.SetProxyProtocolNetworks(req.FormValue("networks")); err != nil {
+			writeAPIV2Error(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if s.proxyProtocolListener == nil {
+		writeAPIV2Error(w, http.StatusBadRequest, errors.New("PROXY protocol is not enabled"))
+		return
+	}
+	writeData(w, s.proxyProtocolListener.Networks().String())
+}
+
+// tlsStatus is the TLS policy and the certificate of the MySQL protocol.
+type tlsStatus struct {
+	MinTLSVersion   string    `json:"tls-version"`
+	TLSCipherSuites []string  `json:"tls-cipher-suites"`
+	SSLCert         string    `json:"ssl-cert"`
+	NotBefore       time.Time `json:"not-before"`
+	NotAfter        time.Time `json:"not-after"`
+}
+
+// handleTLS shows the TLS policy and the certificate, changes the policy by POST, and reloads the certificates by
+// POST with reload=true.
+func (s *Server) handleTLS(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodPost {
+		var err error
+		if req.FormValue("reload") == "true" {
+			err = s.ReloadTLS()
+		} else {
+			var cipherSuites []string
+			if suites := req.FormValue("tls-cipher-suites"); suites != "" {
+				cipherSuites = strings.Split(suites, ",")
+			}
+			err = s.SetTLSPolicy(req.FormValue("tls-version"), cipherSuites)
+		}
+		if err != nil {
+			writeAPIV2Error(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	cfg := config.GetGlobalConfig()
+	st := tlsStatus{
+		MinTLSVersion:   cfg.Security.MinTLSVersion,
+		TLSCipherSuites: cfg.Security.TLSCipherSuites,
+		SSLCert:         variable.GetSysVar("ssl_cert").Value,
+	}
+	if tlsConfig := s.getTLSConfig(); tlsConfig != nil && len(tlsConfig.Certificates) > 0 {
+		if cert, err := x509.ParseCertificate(tlsConfig.Certificates[0].Certificate[0]); err == nil {
+			st.NotBefore, st.NotAfter = cert.NotBefore, cert.NotAfter
+		}
+	}
+	writeData(w, st)
+}
+
+// handleTrafficCapture shows the status of capturing the traffic, starts capturing by POST with capture=true, the
+// directory relative to status.traffic-capture-dir and the optional rotation and duration, and stops capturing by
+// POST with capture=false.
+func (s *Server) handleTrafficCapture(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodPost {
+		switch req.FormValue("capture") {
+		case "true":
+			var (
+				maxSize  int64
+				maxFiles int
+				duration time.Duration
+				err      error
+			)
+			if str := req.FormValue("max-size"); str != "" {
+				if maxSize, err = strconv.ParseInt(str, 10, 64); err != nil {
+					writeAPIV2Error(w, http.StatusBadRequest, err)
+					return
+				}
+				// max-size is in MiB.
+				maxSize <<= 20
+			}
+			if str := req.FormValue("max-files"); str != "" {
+				if maxFiles, err = strconv.Atoi(str); err != nil {
+					writeAPIV2Error(w, http.StatusBadRequest, err)
+					return
+				}
+			}
+			if str := req.FormValue("duration"); str != "" {
+				if duration, err = time.ParseDuration(str); err != nil {
+					writeAPIV2Error(w, http.StatusBadRequest, err)
+					return
+				}
+			}
+			dir, err := captureDir(req.FormValue("dir"))
+			if err != nil {
+				writeAPIV2Error(w, http.StatusBadRequest, err)
+				return
+			}
+			if err = s.StartCapture(dir, maxSize, maxFiles, duration); err != nil {
+				writeAPIV2Error(w, http.StatusBadRequest, err)
+				return
+			}
+		case "false":
+			s.StopCapture()
+		default:
+			writeAPIV2Error(w, http.StatusBadRequest, errors.New("capture should be true or false"))
+			return
+		}
+	}
+	writeData(w, s.GetCaptureStatus())
+}
+
+// handleDrain shows the progress of draining the server, starts draining by POST with drain=true and
+// an optional timeout, and stops draining by POST with drain=false.
+func (s *Server) handleDrain(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodPost {
+		switch req.FormValue("drain") {
+		case "true":
+			var timeout time.Duration
+			if str := req.FormValue("timeout"); str != "" {
+				var err error
+				if timeout, err = time.ParseDuration(str); err != nil {
+					writeAPIV2Error(w, http.StatusBadRequest, err)
+					return
+				}
+			}
+			if err := s.Drain(timeout); err != nil {
+				writeAPIV2Error(w, http.StatusBadRequest, err)
+				return
+			}
+		case "false":
+			s.StopDrain()
+		default:
+			writeAPIV2Error(w, http.StatusBadRequest, errors.New("drain should be true or false"))
+			return
+		}
+	}
+	writeData(w, s.GetDrainStatus())
+}
@@ -0,0 +1,176 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func (ts *basicHTTPHandlerTestSuite) fetchAPIV2(t *testing.T, path, user, password string, code int, items interface{}) apiV2Page {
+	req, err := http.NewRequest(http.MethodGet, ts.statusURL(apiV2Prefix+path), nil)
+	require.NoError(t, err)
+	if user != "" {
+		req.SetBasicAuth(user, password)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, resp.Body.Close())
+	}()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, code, resp.StatusCode, string(body))
+	if code != http.StatusOK {
+		var apiErr apiV2Error
+		require.NoError(t, json.Unmarshal(body, &apiErr))
+		require.Equal(t, code, apiErr.Code)
+		return apiV2Page{}
+	}
+	page := apiV2Page{Items: items}
+	require.NoError(t, json.Unmarshal(body, &page))
+	return page
+}
+
+func TestScramblePassword(t *testing.T) {
+	salt := []byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8, 0x9, 0xa, 0xb, 0xc, 0xd, 0xe, 0xf, 0x10, 0x11, 0x12, 0x13, 0x14}
+	hpwd, err := auth.DecodePassword(auth.EncodePassword("123"))
+	require.NoError(t, err)
+	require.True(t, auth.CheckScrambledPassword(salt, hpwd, scramblePassword(salt, "123")))
+	require.False(t, auth.CheckScrambledPassword(salt, hpwd, scramblePassword(salt, "456")))
+	require.Nil(t, scramblePassword(salt, ""))
+}
+
+func TestAPIV2(t *testing.T) {
+	ts := createBasicHTTPHandlerTestSuite()
+	ts.startServer(t)
+	defer ts.stopServer(t)
+	ts.prepareData(t)
+
+	db, err := sql.Open("mysql", ts.getDSN())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+	dbt := testkit.NewDBTestKit(t, db)
+	dbt.MustExec("create user 'api_v2'@'%' identified by '123'")
+	dbt.MustExec("create user 'api_v2_sha2'@'%' identified with 'caching_sha2_password' by '456'")
+	dbt.MustExec("grant process on *.* to 'api_v2_sha2'@'%'")
+	dbt.MustExec("create user 'api_v2_sm3'@'%' identified with 'tidb_sm3_password' by '789'")
+	dbt.MustExec("grant process on *.* to 'api_v2_sm3'@'%'")
+	dbt.MustExec("analyze table tidb.test")
+
+	// Authentication and authorization.
+	ts.fetchAPIV2(t, "/tables", "", "", http.StatusUnauthorized, nil)
+	ts.fetchAPIV2(t, "/tables", "root", "123", http.StatusUnauthorized, nil)
+	ts.fetchAPIV2(t, "/tables", "api_v2", "456", http.StatusUnauthorized, nil)
+	ts.fetchAPIV2(t, "/tables", "api_v2", "123", http.StatusForbidden, nil)
+	dbt.MustExec("grant process on *.* to 'api_v2'@'%'")
+	ts.fetchAPIV2(t, "/tables", "api_v2", "123", http.StatusOK, nil)
+	ts.fetchAPIV2(t, "/tables", "api_v2_sha2", "456", http.StatusOK, nil)
+	ts.fetchAPIV2(t, "/tables", "api_v2_sha2", "123", http.StatusUnauthorized, nil)
+	ts.fetchAPIV2(t, "/tables", "api_v2_sm3", "789", http.StatusOK, nil)
+	ts.fetchAPIV2(t, "/tables", "api_v2_sm3", "456", http.StatusUnauthorized, nil)
+	ts.fetchAPIV2(t, "/not-found", "root", "", http.StatusNotFound, nil)
+	// The endpoints changing the server need a stronger privilege than PROCESS.
+	ts.fetchAPIV2(t, "/proxy-protocol/networks", "api_v2", "123", http.StatusForbidden, nil)
+	ts.fetchAPIV2(t, "/proxy-protocol/networks", "root", "", http.StatusBadRequest, nil)
+	ts.fetchAPIV2(t, "/tls", "api_v2", "123", http.StatusForbidden, nil)
+	ts.fetchAPIV2(t, "/tls", "root", "", http.StatusOK, nil)
+	ts.fetchAPIV2(t, "/traffic/capture", "api_v2", "123", http.StatusForbidden, nil)
+	ts.fetchAPIV2(t, "/drain", "api_v2", "123", http.StatusForbidden, nil)
+	ts.fetchAPIV2(t, "/drain", "root", "", http.StatusOK, nil)
+	// The user whose password has expired can't use the API until the password is changed.
+	dbt.MustExec("create user 'api_v2_expired'@'%' identified by '123' password expire")
+	dbt.MustExec("grant super, config, process on *.* to 'api_v2_expired'@'%'")
+	ts.fetchAPIV2(t, "/tables", "api_v2_expired", "123", http.StatusForbidden, nil)
+	ts.fetchAPIV2(t, "/tls", "api_v2_expired", "123", http.StatusForbidden, nil)
+	ts.fetchAPIV2(t, "/proxy-protocol/networks", "api_v2_expired", "123", http.StatusForbidden, nil)
+	ts.fetchAPIV2(t, "/traffic/capture", "api_v2_expired", "123", http.StatusForbidden, nil)
+	dbt.MustExec("alter user 'api_v2_expired'@'%' identified by '456'")
+	ts.fetchAPIV2(t, "/tables", "api_v2_expired", "456", http.StatusOK, nil)
+	ts.fetchAPIV2(t, "/tls", "api_v2_expired", "456", http.StatusOK, nil)
+
+	// Tables.
+	var tables []apiV2Table
+	page := ts.fetchAPIV2(t, "/tables?db=TIDB", "root", "", http.StatusOK, &tables)
+	require.Equal(t, 3, page.Total)
+	require.Equal(t, defaultAPIV2Limit, page.Limit)
+	require.Equal(t, []string{"pt", "t", "test"}, []string{tables[0].Table, tables[1].Table, tables[2].Table})
+	require.Equal(t, []string{"p0", "p1", "p2"}, tables[0].Partitions)
+	require.Equal(t, []string{"idx1", "idx2"}, tables[2].Indices)
+	tables = nil
+	page = ts.fetchAPIV2(t, "/tables?db=tidb&offset=1&limit=1", "root", "", http.StatusOK, &tables)
+	require.Equal(t, 3, page.Total)
+	require.Len(t, tables, 1)
+	require.Equal(t, "t", tables[0].Table)
+	tables = nil
+	page = ts.fetchAPIV2(t, "/tables?db=tidb&offset=10", "root", "", http.StatusOK, &tables)
+	require.Equal(t, 3, page.Total)
+	require.Len(t, tables, 0)
+	ts.fetchAPIV2(t, "/tables?limit=0", "root", "", http.StatusBadRequest, nil)
+	ts.fetchAPIV2(t, "/tables?offset=-1", "root", "", http.StatusBadRequest, nil)
+
+	// Regions.
+	var regions []apiV2Region
+	page = ts.fetchAPIV2(t, "/tables/tidb/test/regions", "root", "", http.StatusOK, &regions)
+	require.Greater(t, page.Total, 0)
+	require.Len(t, regions, page.Total)
+	tbl, err := ts.domain.InfoSchema().TableByName(model.NewCIStr("tidb"), model.NewCIStr("test"))
+	require.NoError(t, err)
+	for _, region := range regions {
+		require.Equal(t, tbl.Meta().ID, region.TableID)
+		require.True(t, ts.regionContainsTable(t, region.ID, region.TableID))
+	}
+	regions = nil
+	ts.fetchAPIV2(t, "/tables/tidb/pt/regions?partition=p1&index=idx", "root", "", http.StatusOK, &regions)
+	require.Greater(t, len(regions), 0)
+	for _, region := range regions {
+		require.Equal(t, "p1", region.Partition)
+		require.Equal(t, "idx", region.Index)
+	}
+	ts.fetchAPIV2(t, "/tables/tidb/not_exist/regions", "root", "", http.StatusNotFound, nil)
+
+	// DDL jobs.
+	var jobs []*model.Job
+	page = ts.fetchAPIV2(t, "/ddl/jobs?state=history&db=tidb&limit=2", "root", "", http.StatusOK, &jobs)
+	require.Greater(t, page.Total, 2)
+	require.Len(t, jobs, 2)
+	require.Greater(t, jobs[0].ID, jobs[1].ID)
+	require.Equal(t, model.ActionCreateTable, jobs[0].Type)
+	jobs = nil
+	page = ts.fetchAPIV2(t, "/ddl/jobs", "root", "", http.StatusOK, &jobs)
+	require.Equal(t, 0, page.Total)
+	ts.fetchAPIV2(t, "/ddl/jobs?state=foo", "root", "", http.StatusBadRequest, nil)
+	ts.fetchAPIV2(t, "/ddl/jobs?db=not_exist", "root", "", http.StatusNotFound, nil)
+
+	// Stats healthy.
+	var health []apiV2StatsHealth
+	page = ts.fetchAPIV2(t, "/stats/health?db=tidb&table=test", "root", "", http.StatusOK, &health)
+	require.Equal(t, 1, page.Total)
+	require.Equal(t, apiV2StatsHealth{DB: "tidb", Table: "test", Healthy: 100}, health[0])
+	health = nil
+	page = ts.fetchAPIV2(t, "/stats/health?db=tidb&max_healthy=50", "root", "", http.StatusOK, &health)
+	require.Equal(t, 0, page.Total)
+	ts.fetchAPIV2(t, "/stats/health?max_healthy=foo", "root", "", http.StatusBadRequest, nil)
+}
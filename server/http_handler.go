@@ -62,6 +62,7 @@ import (
 	"github.com/pingcap/tidb/util/admin"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/deadlockhistory"
+	"github.com/pingcap/tidb/util/expensivequery"
 	"github.com/pingcap/tidb/util/gcutil"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/pdapi"
@@ -92,6 +93,7 @@ const (
 const (
 	qTableID   = "table_id"
 	qLimit     = "limit"
+	qOffset    = "offset"
 	qOperation = "op"
 	qSeconds   = "seconds"
 )
@@ -372,6 +374,12 @@ type serverInfoHandler struct {
 	*tikvHandlerTool
 }
 
+// memoryUsageHandler is the handler for the memory usage of the tidb-server.
+type memoryUsageHandler struct {
+	*tikvHandlerTool
+	sm util.SessionManager
+}
+
 type allServerInfoHandler struct {
 	*tikvHandlerTool
 }
@@ -1827,6 +1835,27 @@ func (h serverInfoHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	writeData(w, info)
 }
 
+// memoryUsage is used to report the memory usage by category and the recent operations taken when the memory
+// usage is too high.
+type memoryUsage struct {
+	Usage      []executor.MemoryUsage     `json:"usage"`
+	OpsHistory []expensivequery.MemoryOps `json:"ops_history"`
+}
+
+// ServeHTTP handles request of the memory usage of the tidb-server.
+func (h memoryUsageHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	do, err := session.GetDomain(h.Store)
+	if err != nil {
+		writeError(w, errors.New("create session error"))
+		log.Error("failed to get session domain", zap.Error(err))
+		return
+	}
+	writeData(w, memoryUsage{
+		Usage:      executor.GetMemoryUsage(do, h.sm),
+		OpsHistory: expensivequery.GetMemoryOpsHistory(),
+	})
+}
+
 // clusterServerInfo is used to report cluster servers info when do http request.
 type clusterServerInfo struct {
 	ServersNum                   int                             `json:"servers_num,omitempty"`
@@ -1996,9 +2025,9 @@ func (h *testHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 }
 
 // Supported operations:
-//   * resolvelock?safepoint={uint64}&physical={bool}:
-//	   * safepoint: resolve all locks whose timestamp is less than the safepoint.
-//	   * physical: whether it uses physical(green GC) mode to scan locks. Default is true.
+//   - resolvelock?safepoint={uint64}&physical={bool}:
+//   - safepoint: resolve all locks whose timestamp is less than the safepoint.
+//   - physical: whether it uses physical(green GC) mode to scan locks. Default is true.
 func (h *testHandler) handleGC(op string, w http.ResponseWriter, req *http.Request) {
 	if !atomic.CompareAndSwapUint32(&h.gcIsRunning, 0, 1) {
 		writeError(w, errors.New("GC is running"))
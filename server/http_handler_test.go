@@ -1005,6 +1005,28 @@ func TestHotRegionInfo(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
+func TestMemoryUsage(t *testing.T) {
+	ts := createBasicHTTPHandlerTestSuite()
+	ts.startServer(t)
+	defer ts.stopServer(t)
+	resp, err := ts.fetchStatus("/memory/usage")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	decoder := json.NewDecoder(resp.Body)
+	var usage memoryUsage
+	require.NoError(t, decoder.Decode(&usage))
+	require.NoError(t, resp.Body.Close())
+	categories := make(map[string]int64, len(usage.Usage))
+	for _, item := range usage.Usage {
+		require.GreaterOrEqual(t, item.Bytes, int64(0))
+		categories[item.Category] = item.Bytes
+	}
+	for _, category := range []string{"sessions", "internal", "plan_cache", "stats_cache", "untracked"} {
+		require.Contains(t, categories, category)
+	}
+	require.Greater(t, categories["runtime.heap_inuse"], int64(0))
+}
+
 func TestDebugZip(t *testing.T) {
 	ts := createBasicHTTPHandlerTestSuite()
 	ts.startServer(t)
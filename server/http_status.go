@@ -194,6 +194,7 @@ func (s *Server) startHTTPServer() {
 	router := mux.NewRouter()
 
 	router.HandleFunc("/status", s.handleStatus).Name("Status")
+	router.HandleFunc("/drain", s.handleDrainStatus).Methods(http.MethodGet).Name("Drain")
 	// HTTP path for prometheus.
 	router.Handle("/metrics", promhttp.Handler()).Name("Metrics")
 
@@ -206,6 +207,8 @@ func (s *Server) startHTTPServer() {
 	router.Handle("/optimize_trace/dump/{filename}", s.newOptimizeTraceHandler()).Name("OptimizeTraceDump")
 
 	tikvHandlerTool := s.newTikvHandlerTool()
+	// HTTP path for the versioned API.
+	s.registerAPIV2(router, tikvHandlerTool)
 	router.Handle("/settings", settingsHandler{tikvHandlerTool}).Name("Settings")
 	router.Handle("/binlog/recover", binlogRecover{}).Name("BinlogRecover")
 
@@ -229,6 +232,8 @@ func (s *Server) startHTTPServer() {
 	// HTTP path for get server info.
 	router.Handle("/info", serverInfoHandler{tikvHandlerTool}).Name("Info")
 	router.Handle("/info/all", allServerInfoHandler{tikvHandlerTool}).Name("InfoALL")
+	// HTTP path for get the memory usage of the tidb-server by category.
+	router.Handle("/memory/usage", memoryUsageHandler{tikvHandlerTool, s}).Name("MemoryUsage")
 	// HTTP path for get db and table info that is related to the tableID.
 	router.Handle("/db-table/{tableID}", dbTableHandler{tikvHandlerTool})
 	// HTTP path for get table tiflash replica info.
@@ -500,12 +505,18 @@ type status struct {
 	GitHash     string `json:"git_hash"`
 }
 
+// handleDrainStatus shows the progress of draining the server, draining is started and stopped by the API v2.
+func (s *Server) handleDrainStatus(w http.ResponseWriter, req *http.Request) {
+	writeData(w, s.GetDrainStatus())
+}
+
 func (s *Server) handleStatus(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	// If the server is in the process of shutting down, return a non-200 status.
 	// It is important not to return status{} as acquiring the s.ConnectionCount()
 	// acquires a lock that may already be held by the shutdown process.
-	if s.inShutdownMode {
+	// A draining server also reports unhealthy so that the load balancer stops routing to it.
+	if s.inShutdownMode || s.isDraining() {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,175 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// The wire types of protobuf.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Message is a protobuf message of the X Protocol. Only the fields used by TiDB are encoded and decoded,
+// the unknown fields are skipped.
+type Message interface {
+	Marshal() []byte
+	Unmarshal(b []byte) error
+}
+
+// encoder appends the fields in the protobuf wire format.
+type encoder []byte
+
+func (e *encoder) tag(num int, wt int) {
+	e.uvarint(uint64(num)<<3 | uint64(wt))
+}
+
+func (e *encoder) uvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	*e = append(*e, buf[:n]...)
+}
+
+func (e *encoder) varintField(num int, v uint64) {
+	e.tag(num, wireVarint)
+	e.uvarint(v)
+}
+
+func (e *encoder) sintField(num int, v int64) {
+	e.varintField(num, uint64(v<<1)^uint64(v>>63))
+}
+
+func (e *encoder) boolField(num int, v bool) {
+	if v {
+		e.varintField(num, 1)
+	} else {
+		e.varintField(num, 0)
+	}
+}
+
+func (e *encoder) doubleField(num int, v float64) {
+	e.tag(num, wireFixed64)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+	*e = append(*e, buf[:]...)
+}
+
+func (e *encoder) floatField(num int, v float32) {
+	e.tag(num, wireFixed32)
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], math.Float32bits(v))
+	*e = append(*e, buf[:]...)
+}
+
+func (e *encoder) bytesField(num int, v []byte) {
+	e.tag(num, wireBytes)
+	e.uvarint(uint64(len(v)))
+	*e = append(*e, v...)
+}
+
+func (e *encoder) stringField(num int, v string) {
+	e.tag(num, wireBytes)
+	e.uvarint(uint64(len(v)))
+	*e = append(*e, v...)
+}
+
+func (e *encoder) messageField(num int, m Message) {
+	e.bytesField(num, m.Marshal())
+}
+
+// field is a decoded protobuf field.
+type field struct {
+	num int
+	wt  int
+	// u is the value of the varint and fixed fields.
+	u uint64
+	// b is the value of the length-delimited fields.
+	b []byte
+}
+
+func (f *field) bool() bool {
+	return f.u != 0
+}
+
+func (f *field) sint() int64 {
+	return int64(f.u>>1) ^ -int64(f.u&1)
+}
+
+func (f *field) double() float64 {
+	return math.Float64frombits(f.u)
+}
+
+func (f *field) float() float32 {
+	return math.Float32frombits(uint32(f.u))
+}
+
+func (f *field) string() string {
+	return string(f.b)
+}
+
+func (f *field) bytes() []byte {
+	b := make([]byte, len(f.b))
+	copy(b, f.b)
+	return b
+}
+
+// unmarshalFields decodes the fields of a message and calls fn for each of them.
+func unmarshalFields(b []byte, fn func(f *field) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errInvalidMessage
+		}
+		b = b[n:]
+		f := field{num: int(key >> 3), wt: int(key & 7)}
+		switch f.wt {
+		case wireVarint:
+			f.u, n = binary.Uvarint(b)
+			if n <= 0 {
+				return errInvalidMessage
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return errInvalidMessage
+			}
+			f.u, b = binary.LittleEndian.Uint64(b), b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return errInvalidMessage
+			}
+			f.u, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return errInvalidMessage
+			}
+			f.b, b = b[n:n+int(l)], b[n+int(l):]
+		default:
+			return errInvalidMessage
+		}
+		if err := fn(&f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var errInvalidMessage = NewError(ErrXBadMessage, "invalid protobuf message")
@@ -0,0 +1,937 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+// The types of Expr.
+const (
+	ExprIdent       = 1
+	ExprLiteral     = 2
+	ExprVariable    = 3
+	ExprFuncCall    = 4
+	ExprOperator    = 5
+	ExprPlaceholder = 6
+	ExprObject      = 7
+	ExprArray       = 8
+)
+
+// Expr is an expression of the CRUD messages.
+type Expr struct {
+	Type       uint32
+	Identifier *ColumnIdentifier
+	Variable   string
+	Literal    *Scalar
+	FuncCall   *FunctionCall
+	Operator   *Operator
+	Position   uint32
+	Object     *ObjectExpr
+	Array      *ArrayExpr
+}
+
+// Marshal implements the Message interface.
+func (m *Expr) Marshal() []byte {
+	var e encoder
+	e.varintField(1, uint64(m.Type))
+	if m.Identifier != nil {
+		e.messageField(2, m.Identifier)
+	}
+	if m.Variable != "" {
+		e.stringField(3, m.Variable)
+	}
+	if m.Literal != nil {
+		e.messageField(4, m.Literal)
+	}
+	if m.FuncCall != nil {
+		e.messageField(5, m.FuncCall)
+	}
+	if m.Operator != nil {
+		e.messageField(6, m.Operator)
+	}
+	if m.Type == ExprPlaceholder {
+		e.varintField(7, uint64(m.Position))
+	}
+	if m.Object != nil {
+		e.messageField(8, m.Object)
+	}
+	if m.Array != nil {
+		e.messageField(9, m.Array)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Expr) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Type = uint32(f.u)
+		case 2:
+			m.Identifier = &ColumnIdentifier{}
+			return m.Identifier.Unmarshal(f.b)
+		case 3:
+			m.Variable = f.string()
+		case 4:
+			m.Literal = &Scalar{}
+			return m.Literal.Unmarshal(f.b)
+		case 5:
+			m.FuncCall = &FunctionCall{}
+			return m.FuncCall.Unmarshal(f.b)
+		case 6:
+			m.Operator = &Operator{}
+			return m.Operator.Unmarshal(f.b)
+		case 7:
+			m.Position = uint32(f.u)
+		case 8:
+			m.Object = &ObjectExpr{}
+			return m.Object.Unmarshal(f.b)
+		case 9:
+			m.Array = &ArrayExpr{}
+			return m.Array.Unmarshal(f.b)
+		}
+		return nil
+	})
+}
+
+// The types of DocumentPathItem.
+const (
+	PathMember             = 1
+	PathMemberAsterisk     = 2
+	PathArrayIndex         = 3
+	PathArrayIndexAsterisk = 4
+	PathDoubleAsterisk     = 5
+)
+
+// DocumentPathItem is an item of a document path, such as ".name" and "[0]".
+type DocumentPathItem struct {
+	Type  uint32
+	Value string
+	Index uint32
+}
+
+// Marshal implements the Message interface.
+func (m *DocumentPathItem) Marshal() []byte {
+	var e encoder
+	e.varintField(1, uint64(m.Type))
+	switch m.Type {
+	case PathMember:
+		e.stringField(2, m.Value)
+	case PathArrayIndex:
+		e.varintField(3, uint64(m.Index))
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *DocumentPathItem) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Type = uint32(f.u)
+		case 2:
+			m.Value = f.string()
+		case 3:
+			m.Index = uint32(f.u)
+		}
+		return nil
+	})
+}
+
+func marshalDocumentPath(e *encoder, num int, path []*DocumentPathItem) {
+	for _, item := range path {
+		e.messageField(num, item)
+	}
+}
+
+func unmarshalDocumentPathItem(f *field, path *[]*DocumentPathItem) error {
+	item := &DocumentPathItem{}
+	*path = append(*path, item)
+	return item.Unmarshal(f.b)
+}
+
+// ColumnIdentifier identifies a column or a path in the document.
+type ColumnIdentifier struct {
+	DocumentPath []*DocumentPathItem
+	Name         string
+	TableName    string
+	SchemaName   string
+}
+
+// Marshal implements the Message interface.
+func (m *ColumnIdentifier) Marshal() []byte {
+	var e encoder
+	marshalDocumentPath(&e, 1, m.DocumentPath)
+	if m.Name != "" {
+		e.stringField(2, m.Name)
+	}
+	if m.TableName != "" {
+		e.stringField(3, m.TableName)
+	}
+	if m.SchemaName != "" {
+		e.stringField(4, m.SchemaName)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *ColumnIdentifier) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			return unmarshalDocumentPathItem(f, &m.DocumentPath)
+		case 2:
+			m.Name = f.string()
+		case 3:
+			m.TableName = f.string()
+		case 4:
+			m.SchemaName = f.string()
+		}
+		return nil
+	})
+}
+
+// Identifier is the name of a function.
+type Identifier struct {
+	Name       string
+	SchemaName string
+}
+
+// Marshal implements the Message interface.
+func (m *Identifier) Marshal() []byte {
+	var e encoder
+	e.stringField(1, m.Name)
+	if m.SchemaName != "" {
+		e.stringField(2, m.SchemaName)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Identifier) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Name = f.string()
+		case 2:
+			m.SchemaName = f.string()
+		}
+		return nil
+	})
+}
+
+// FunctionCall is a call of a SQL function.
+type FunctionCall struct {
+	Name   *Identifier
+	Params []*Expr
+}
+
+// Marshal implements the Message interface.
+func (m *FunctionCall) Marshal() []byte {
+	var e encoder
+	if m.Name != nil {
+		e.messageField(1, m.Name)
+	}
+	for _, p := range m.Params {
+		e.messageField(2, p)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *FunctionCall) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Name = &Identifier{}
+			return m.Name.Unmarshal(f.b)
+		case 2:
+			return unmarshalExpr(f, &m.Params)
+		}
+		return nil
+	})
+}
+
+// Operator is an operator, such as "==" and "&&", and its operands.
+type Operator struct {
+	Name   string
+	Params []*Expr
+}
+
+// Marshal implements the Message interface.
+func (m *Operator) Marshal() []byte {
+	var e encoder
+	e.stringField(1, m.Name)
+	for _, p := range m.Params {
+		e.messageField(2, p)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Operator) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Name = f.string()
+		case 2:
+			return unmarshalExpr(f, &m.Params)
+		}
+		return nil
+	})
+}
+
+// ObjectExprField is a field of ObjectExpr.
+type ObjectExprField struct {
+	Key   string
+	Value *Expr
+}
+
+// ObjectExpr is an object whose values are expressions.
+type ObjectExpr struct {
+	Fields []*ObjectExprField
+}
+
+// Marshal implements the Message interface.
+func (m *ObjectExpr) Marshal() []byte {
+	var e encoder
+	for _, fld := range m.Fields {
+		var fe encoder
+		fe.stringField(1, fld.Key)
+		if fld.Value != nil {
+			fe.messageField(2, fld.Value)
+		}
+		e.bytesField(1, fe)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *ObjectExpr) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		if f.num != 1 {
+			return nil
+		}
+		fld := &ObjectExprField{}
+		m.Fields = append(m.Fields, fld)
+		return unmarshalFields(f.b, func(f *field) error {
+			switch f.num {
+			case 1:
+				fld.Key = f.string()
+			case 2:
+				fld.Value = &Expr{}
+				return fld.Value.Unmarshal(f.b)
+			}
+			return nil
+		})
+	})
+}
+
+// ArrayExpr is an array whose values are expressions.
+type ArrayExpr struct {
+	Values []*Expr
+}
+
+// Marshal implements the Message interface.
+func (m *ArrayExpr) Marshal() []byte {
+	var e encoder
+	for _, v := range m.Values {
+		e.messageField(1, v)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *ArrayExpr) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		if f.num == 1 {
+			return unmarshalExpr(f, &m.Values)
+		}
+		return nil
+	})
+}
+
+func unmarshalExpr(f *field, exprs *[]*Expr) error {
+	expr := &Expr{}
+	*exprs = append(*exprs, expr)
+	return expr.Unmarshal(f.b)
+}
+
+func unmarshalScalar(f *field, scalars *[]*Scalar) error {
+	s := &Scalar{}
+	*scalars = append(*scalars, s)
+	return s.Unmarshal(f.b)
+}
+
+// The data models of the CRUD messages.
+const (
+	DataModelDocument = 1
+	DataModelTable    = 2
+)
+
+// Collection is a collection or a table in a schema.
+type Collection struct {
+	Name   string
+	Schema string
+}
+
+// Marshal implements the Message interface.
+func (m *Collection) Marshal() []byte {
+	var e encoder
+	e.stringField(1, m.Name)
+	if m.Schema != "" {
+		e.stringField(2, m.Schema)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Collection) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Name = f.string()
+		case 2:
+			m.Schema = f.string()
+		}
+		return nil
+	})
+}
+
+// Projection is a selected expression of Find.
+type Projection struct {
+	Source *Expr
+	Alias  string
+}
+
+// Marshal implements the Message interface.
+func (m *Projection) Marshal() []byte {
+	var e encoder
+	if m.Source != nil {
+		e.messageField(1, m.Source)
+	}
+	if m.Alias != "" {
+		e.stringField(2, m.Alias)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Projection) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Source = &Expr{}
+			return m.Source.Unmarshal(f.b)
+		case 2:
+			m.Alias = f.string()
+		}
+		return nil
+	})
+}
+
+// Column is an inserted column of Insert on tables.
+type Column struct {
+	Name         string
+	Alias        string
+	DocumentPath []*DocumentPathItem
+}
+
+// Marshal implements the Message interface.
+func (m *Column) Marshal() []byte {
+	var e encoder
+	if m.Name != "" {
+		e.stringField(1, m.Name)
+	}
+	if m.Alias != "" {
+		e.stringField(2, m.Alias)
+	}
+	marshalDocumentPath(&e, 3, m.DocumentPath)
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Column) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Name = f.string()
+		case 2:
+			m.Alias = f.string()
+		case 3:
+			return unmarshalDocumentPathItem(f, &m.DocumentPath)
+		}
+		return nil
+	})
+}
+
+// Limit limits the number of the rows.
+type Limit struct {
+	RowCount uint64
+	Offset   uint64
+}
+
+// Marshal implements the Message interface.
+func (m *Limit) Marshal() []byte {
+	var e encoder
+	e.varintField(1, m.RowCount)
+	if m.Offset != 0 {
+		e.varintField(2, m.Offset)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Limit) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.RowCount = f.u
+		case 2:
+			m.Offset = f.u
+		}
+		return nil
+	})
+}
+
+// LimitExpr limits the number of the rows by expressions, which are literals or placeholders.
+type LimitExpr struct {
+	RowCount *Expr
+	Offset   *Expr
+}
+
+// Marshal implements the Message interface.
+func (m *LimitExpr) Marshal() []byte {
+	var e encoder
+	if m.RowCount != nil {
+		e.messageField(1, m.RowCount)
+	}
+	if m.Offset != nil {
+		e.messageField(2, m.Offset)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *LimitExpr) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.RowCount = &Expr{}
+			return m.RowCount.Unmarshal(f.b)
+		case 2:
+			m.Offset = &Expr{}
+			return m.Offset.Unmarshal(f.b)
+		}
+		return nil
+	})
+}
+
+// The directions of Order.
+const (
+	OrderAsc  = 1
+	OrderDesc = 2
+)
+
+// Order is an item of the sort order.
+type Order struct {
+	Expr      *Expr
+	Direction uint32
+}
+
+// Marshal implements the Message interface.
+func (m *Order) Marshal() []byte {
+	var e encoder
+	if m.Expr != nil {
+		e.messageField(1, m.Expr)
+	}
+	if m.Direction != 0 {
+		e.varintField(2, uint64(m.Direction))
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Order) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Expr = &Expr{}
+			return m.Expr.Unmarshal(f.b)
+		case 2:
+			m.Direction = uint32(f.u)
+		}
+		return nil
+	})
+}
+
+func unmarshalOrder(f *field, orders *[]*Order) error {
+	o := &Order{}
+	*orders = append(*orders, o)
+	return o.Unmarshal(f.b)
+}
+
+// The operations of UpdateOperation.
+const (
+	UpdateSet         = 1
+	UpdateItemRemove  = 2
+	UpdateItemSet     = 3
+	UpdateItemReplace = 4
+	UpdateItemMerge   = 5
+	UpdateArrayInsert = 6
+	UpdateArrayAppend = 7
+	UpdateMergePatch  = 8
+)
+
+// UpdateOperation is an operation of Update.
+type UpdateOperation struct {
+	Source    *ColumnIdentifier
+	Operation uint32
+	Value     *Expr
+}
+
+// Marshal implements the Message interface.
+func (m *UpdateOperation) Marshal() []byte {
+	var e encoder
+	if m.Source != nil {
+		e.messageField(1, m.Source)
+	}
+	e.varintField(2, uint64(m.Operation))
+	if m.Value != nil {
+		e.messageField(3, m.Value)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *UpdateOperation) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Source = &ColumnIdentifier{}
+			return m.Source.Unmarshal(f.b)
+		case 2:
+			m.Operation = uint32(f.u)
+		case 3:
+			m.Value = &Expr{}
+			return m.Value.Unmarshal(f.b)
+		}
+		return nil
+	})
+}
+
+// The lockings of Find.
+const (
+	LockShared    = 1
+	LockExclusive = 2
+)
+
+// Find finds the documents or the rows.
+type Find struct {
+	Collection  *Collection
+	DataModel   uint32
+	Projection  []*Projection
+	Criteria    *Expr
+	Args        []*Scalar
+	Limit       *Limit
+	Order       []*Order
+	Grouping    []*Expr
+	GroupingCri *Expr
+	Locking     uint32
+	LimitExpr   *LimitExpr
+}
+
+// Marshal implements the Message interface.
+func (m *Find) Marshal() []byte {
+	var e encoder
+	if m.Collection != nil {
+		e.messageField(2, m.Collection)
+	}
+	if m.DataModel != 0 {
+		e.varintField(3, uint64(m.DataModel))
+	}
+	for _, p := range m.Projection {
+		e.messageField(4, p)
+	}
+	if m.Criteria != nil {
+		e.messageField(5, m.Criteria)
+	}
+	if m.Limit != nil {
+		e.messageField(6, m.Limit)
+	}
+	for _, o := range m.Order {
+		e.messageField(7, o)
+	}
+	for _, g := range m.Grouping {
+		e.messageField(8, g)
+	}
+	if m.GroupingCri != nil {
+		e.messageField(9, m.GroupingCri)
+	}
+	for _, arg := range m.Args {
+		e.messageField(11, arg)
+	}
+	if m.Locking != 0 {
+		e.varintField(12, uint64(m.Locking))
+	}
+	if m.LimitExpr != nil {
+		e.messageField(14, m.LimitExpr)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Find) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 2:
+			m.Collection = &Collection{}
+			return m.Collection.Unmarshal(f.b)
+		case 3:
+			m.DataModel = uint32(f.u)
+		case 4:
+			p := &Projection{}
+			m.Projection = append(m.Projection, p)
+			return p.Unmarshal(f.b)
+		case 5:
+			m.Criteria = &Expr{}
+			return m.Criteria.Unmarshal(f.b)
+		case 6:
+			m.Limit = &Limit{}
+			return m.Limit.Unmarshal(f.b)
+		case 7:
+			return unmarshalOrder(f, &m.Order)
+		case 8:
+			return unmarshalExpr(f, &m.Grouping)
+		case 9:
+			m.GroupingCri = &Expr{}
+			return m.GroupingCri.Unmarshal(f.b)
+		case 11:
+			return unmarshalScalar(f, &m.Args)
+		case 12:
+			m.Locking = uint32(f.u)
+		case 14:
+			m.LimitExpr = &LimitExpr{}
+			return m.LimitExpr.Unmarshal(f.b)
+		}
+		return nil
+	})
+}
+
+// Insert inserts the documents or the rows.
+type Insert struct {
+	Collection *Collection
+	DataModel  uint32
+	Projection []*Column
+	// Rows are the inserted rows, each row of a collection has one field which is the document.
+	Rows   [][]*Expr
+	Args   []*Scalar
+	Upsert bool
+}
+
+// Marshal implements the Message interface.
+func (m *Insert) Marshal() []byte {
+	var e encoder
+	if m.Collection != nil {
+		e.messageField(1, m.Collection)
+	}
+	if m.DataModel != 0 {
+		e.varintField(2, uint64(m.DataModel))
+	}
+	for _, c := range m.Projection {
+		e.messageField(3, c)
+	}
+	for _, row := range m.Rows {
+		var re encoder
+		for _, fld := range row {
+			re.messageField(1, fld)
+		}
+		e.bytesField(4, re)
+	}
+	for _, arg := range m.Args {
+		e.messageField(5, arg)
+	}
+	if m.Upsert {
+		e.boolField(6, m.Upsert)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Insert) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Collection = &Collection{}
+			return m.Collection.Unmarshal(f.b)
+		case 2:
+			m.DataModel = uint32(f.u)
+		case 3:
+			c := &Column{}
+			m.Projection = append(m.Projection, c)
+			return c.Unmarshal(f.b)
+		case 4:
+			var row []*Expr
+			err := unmarshalFields(f.b, func(f *field) error {
+				if f.num == 1 {
+					return unmarshalExpr(f, &row)
+				}
+				return nil
+			})
+			m.Rows = append(m.Rows, row)
+			return err
+		case 5:
+			return unmarshalScalar(f, &m.Args)
+		case 6:
+			m.Upsert = f.bool()
+		}
+		return nil
+	})
+}
+
+// Update updates the documents or the rows.
+type Update struct {
+	Collection *Collection
+	DataModel  uint32
+	Criteria   *Expr
+	Limit      *Limit
+	Order      []*Order
+	Operations []*UpdateOperation
+	Args       []*Scalar
+	LimitExpr  *LimitExpr
+}
+
+// Marshal implements the Message interface.
+func (m *Update) Marshal() []byte {
+	var e encoder
+	if m.Collection != nil {
+		e.messageField(2, m.Collection)
+	}
+	if m.DataModel != 0 {
+		e.varintField(3, uint64(m.DataModel))
+	}
+	if m.Criteria != nil {
+		e.messageField(4, m.Criteria)
+	}
+	if m.Limit != nil {
+		e.messageField(5, m.Limit)
+	}
+	for _, o := range m.Order {
+		e.messageField(6, o)
+	}
+	for _, op := range m.Operations {
+		e.messageField(7, op)
+	}
+	for _, arg := range m.Args {
+		e.messageField(8, arg)
+	}
+	if m.LimitExpr != nil {
+		e.messageField(9, m.LimitExpr)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Update) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 2:
+			m.Collection = &Collection{}
+			return m.Collection.Unmarshal(f.b)
+		case 3:
+			m.DataModel = uint32(f.u)
+		case 4:
+			m.Criteria = &Expr{}
+			return m.Criteria.Unmarshal(f.b)
+		case 5:
+			m.Limit = &Limit{}
+			return m.Limit.Unmarshal(f.b)
+		case 6:
+			return unmarshalOrder(f, &m.Order)
+		case 7:
+			op := &UpdateOperation{}
+			m.Operations = append(m.Operations, op)
+			return op.Unmarshal(f.b)
+		case 8:
+			return unmarshalScalar(f, &m.Args)
+		case 9:
+			m.LimitExpr = &LimitExpr{}
+			return m.LimitExpr.Unmarshal(f.b)
+		}
+		return nil
+	})
+}
+
+// Delete deletes the documents or the rows.
+type Delete struct {
+	Collection *Collection
+	DataModel  uint32
+	Criteria   *Expr
+	Limit      *Limit
+	Order      []*Order
+	Args       []*Scalar
+	LimitExpr  *LimitExpr
+}
+
+// Marshal implements the Message interface.
+func (m *Delete) Marshal() []byte {
+	var e encoder
+	if m.Collection != nil {
+		e.messageField(1, m.Collection)
+	}
+	if m.DataModel != 0 {
+		e.varintField(2, uint64(m.DataModel))
+	}
+	if m.Criteria != nil {
+		e.messageField(3, m.Criteria)
+	}
+	if m.Limit != nil {
+		e.messageField(4, m.Limit)
+	}
+	for _, o := range m.Order {
+		e.messageField(5, o)
+	}
+	for _, arg := range m.Args {
+		e.messageField(6, arg)
+	}
+	if m.LimitExpr != nil {
+		e.messageField(7, m.LimitExpr)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Delete) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Collection = &Collection{}
+			return m.Collection.Unmarshal(f.b)
+		case 2:
+			m.DataModel = uint32(f.u)
+		case 3:
+			m.Criteria = &Expr{}
+			return m.Criteria.Unmarshal(f.b)
+		case 4:
+			m.Limit = &Limit{}
+			return m.Limit.Unmarshal(f.b)
+		case 5:
+			return unmarshalOrder(f, &m.Order)
+		case 6:
+			return unmarshalScalar(f, &m.Args)
+		case 7:
+			m.LimitExpr = &LimitExpr{}
+			return m.LimitExpr.Unmarshal(f.b)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,817 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+// The types of Scalar.
+const (
+	ScalarSInt   = 1
+	ScalarUInt   = 2
+	ScalarNull   = 3
+	ScalarOctets = 4
+	ScalarDouble = 5
+	ScalarFloat  = 6
+	ScalarBool   = 7
+	ScalarString = 8
+)
+
+// Scalar is a scalar value.
+type Scalar struct {
+	Type   uint32
+	SInt   int64
+	UInt   uint64
+	Octets []byte
+	// ContentType is the content type of Octets, such as ContentTypeJSON.
+	ContentType uint32
+	Double      float64
+	Float       float32
+	Bool        bool
+	String      []byte
+	Collation   uint64
+}
+
+// Marshal implements the Message interface.
+func (m *Scalar) Marshal() []byte {
+	var e encoder
+	e.varintField(1, uint64(m.Type))
+	switch m.Type {
+	case ScalarSInt:
+		e.sintField(2, m.SInt)
+	case ScalarUInt:
+		e.varintField(3, m.UInt)
+	case ScalarOctets:
+		var o encoder
+		o.bytesField(1, m.Octets)
+		if m.ContentType != 0 {
+			o.varintField(2, uint64(m.ContentType))
+		}
+		e.bytesField(5, o)
+	case ScalarDouble:
+		e.doubleField(6, m.Double)
+	case ScalarFloat:
+		e.floatField(7, m.Float)
+	case ScalarBool:
+		e.boolField(8, m.Bool)
+	case ScalarString:
+		var s encoder
+		s.bytesField(1, m.String)
+		if m.Collation != 0 {
+			s.varintField(2, m.Collation)
+		}
+		e.bytesField(9, s)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Scalar) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Type = uint32(f.u)
+		case 2:
+			m.SInt = f.sint()
+		case 3:
+			m.UInt = f.u
+		case 5:
+			return unmarshalFields(f.b, func(f *field) error {
+				switch f.num {
+				case 1:
+					m.Octets = f.bytes()
+				case 2:
+					m.ContentType = uint32(f.u)
+				}
+				return nil
+			})
+		case 6:
+			m.Double = f.double()
+		case 7:
+			m.Float = f.float()
+		case 8:
+			m.Bool = f.bool()
+		case 9:
+			return unmarshalFields(f.b, func(f *field) error {
+				switch f.num {
+				case 1:
+					m.String = f.bytes()
+				case 2:
+					m.Collation = f.u
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+// The types of Any.
+const (
+	AnyScalar = 1
+	AnyObject = 2
+	AnyArray  = 3
+)
+
+// Any is a scalar, an object or an array.
+type Any struct {
+	Type   uint32
+	Scalar *Scalar
+	Object *Object
+	Array  *Array
+}
+
+// NewScalarAny creates an Any of the scalar.
+func NewScalarAny(s *Scalar) *Any {
+	return &Any{Type: AnyScalar, Scalar: s}
+}
+
+// Marshal implements the Message interface.
+func (m *Any) Marshal() []byte {
+	var e encoder
+	e.varintField(1, uint64(m.Type))
+	if m.Scalar != nil {
+		e.messageField(2, m.Scalar)
+	}
+	if m.Object != nil {
+		e.messageField(3, m.Object)
+	}
+	if m.Array != nil {
+		e.messageField(4, m.Array)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Any) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Type = uint32(f.u)
+		case 2:
+			m.Scalar = &Scalar{}
+			return m.Scalar.Unmarshal(f.b)
+		case 3:
+			m.Object = &Object{}
+			return m.Object.Unmarshal(f.b)
+		case 4:
+			m.Array = &Array{}
+			return m.Array.Unmarshal(f.b)
+		}
+		return nil
+	})
+}
+
+// ObjectField is a field of Object.
+type ObjectField struct {
+	Key   string
+	Value *Any
+}
+
+// Object is a list of key-value pairs.
+type Object struct {
+	Fields []*ObjectField
+}
+
+// Marshal implements the Message interface.
+func (m *Object) Marshal() []byte {
+	var e encoder
+	for _, fld := range m.Fields {
+		var fe encoder
+		fe.stringField(1, fld.Key)
+		if fld.Value != nil {
+			fe.messageField(2, fld.Value)
+		}
+		e.bytesField(1, fe)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Object) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		if f.num != 1 {
+			return nil
+		}
+		fld := &ObjectField{}
+		m.Fields = append(m.Fields, fld)
+		return unmarshalFields(f.b, func(f *field) error {
+			switch f.num {
+			case 1:
+				fld.Key = f.string()
+			case 2:
+				fld.Value = &Any{}
+				return fld.Value.Unmarshal(f.b)
+			}
+			return nil
+		})
+	})
+}
+
+// Array is a list of values.
+type Array struct {
+	Values []*Any
+}
+
+// Marshal implements the Message interface.
+func (m *Array) Marshal() []byte {
+	var e encoder
+	for _, v := range m.Values {
+		e.messageField(1, v)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Array) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		if f.num == 1 {
+			v := &Any{}
+			m.Values = append(m.Values, v)
+			return v.Unmarshal(f.b)
+		}
+		return nil
+	})
+}
+
+// Capability is a capability of the connection, such as "tls".
+type Capability struct {
+	Name  string
+	Value *Any
+}
+
+// Capabilities is the list of capabilities, it's the response of CapabilitiesGet and the content of CapabilitiesSet.
+type Capabilities struct {
+	Capabilities []*Capability
+}
+
+// Marshal implements the Message interface.
+func (m *Capabilities) Marshal() []byte {
+	var e encoder
+	for _, c := range m.Capabilities {
+		var ce encoder
+		ce.stringField(1, c.Name)
+		if c.Value != nil {
+			ce.messageField(2, c.Value)
+		}
+		e.bytesField(1, ce)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Capabilities) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		if f.num != 1 {
+			return nil
+		}
+		c := &Capability{}
+		m.Capabilities = append(m.Capabilities, c)
+		return unmarshalFields(f.b, func(f *field) error {
+			switch f.num {
+			case 1:
+				c.Name = f.string()
+			case 2:
+				c.Value = &Any{}
+				return c.Value.Unmarshal(f.b)
+			}
+			return nil
+		})
+	})
+}
+
+// CapabilitiesSet sets the capabilities of the connection.
+type CapabilitiesSet struct {
+	Capabilities *Capabilities
+}
+
+// Marshal implements the Message interface.
+func (m *CapabilitiesSet) Marshal() []byte {
+	var e encoder
+	if m.Capabilities != nil {
+		e.messageField(1, m.Capabilities)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *CapabilitiesSet) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		if f.num == 1 {
+			m.Capabilities = &Capabilities{}
+			return m.Capabilities.Unmarshal(f.b)
+		}
+		return nil
+	})
+}
+
+// Empty is a message without fields, such as CapabilitiesGet, FetchDone and StmtExecuteOk.
+type Empty struct{}
+
+// Marshal implements the Message interface.
+func (m *Empty) Marshal() []byte {
+	return nil
+}
+
+// Unmarshal implements the Message interface.
+func (m *Empty) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error { return nil })
+}
+
+// AuthenticateStart starts the authentication.
+type AuthenticateStart struct {
+	MechName        string
+	AuthData        []byte
+	InitialResponse []byte
+}
+
+// Marshal implements the Message interface.
+func (m *AuthenticateStart) Marshal() []byte {
+	var e encoder
+	e.stringField(1, m.MechName)
+	if m.AuthData != nil {
+		e.bytesField(2, m.AuthData)
+	}
+	if m.InitialResponse != nil {
+		e.bytesField(3, m.InitialResponse)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *AuthenticateStart) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.MechName = f.string()
+		case 2:
+			m.AuthData = f.bytes()
+		case 3:
+			m.InitialResponse = f.bytes()
+		}
+		return nil
+	})
+}
+
+// AuthData is the message which only carries the authentication data,
+// it's used by AuthenticateContinue and AuthenticateOk.
+type AuthData struct {
+	AuthData []byte
+}
+
+// Marshal implements the Message interface.
+func (m *AuthData) Marshal() []byte {
+	var e encoder
+	if m.AuthData != nil {
+		e.bytesField(1, m.AuthData)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *AuthData) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		if f.num == 1 {
+			m.AuthData = f.bytes()
+		}
+		return nil
+	})
+}
+
+// Reset resets the session.
+type Reset struct {
+	KeepOpen bool
+}
+
+// Marshal implements the Message interface.
+func (m *Reset) Marshal() []byte {
+	var e encoder
+	if m.KeepOpen {
+		e.boolField(1, m.KeepOpen)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Reset) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		if f.num == 1 {
+			m.KeepOpen = f.bool()
+		}
+		return nil
+	})
+}
+
+// Ok is the generic success response.
+type Ok struct {
+	Msg string
+}
+
+// Marshal implements the Message interface.
+func (m *Ok) Marshal() []byte {
+	var e encoder
+	if m.Msg != "" {
+		e.stringField(1, m.Msg)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Ok) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		if f.num == 1 {
+			m.Msg = f.string()
+		}
+		return nil
+	})
+}
+
+// The severities of ErrorMsg.
+const (
+	SeverityError = 0
+	SeverityFatal = 1
+)
+
+// ErrorMsg is the error response.
+type ErrorMsg struct {
+	Severity uint32
+	Code     uint32
+	SQLState string
+	Msg      string
+}
+
+// Marshal implements the Message interface.
+func (m *ErrorMsg) Marshal() []byte {
+	var e encoder
+	e.varintField(1, uint64(m.Severity))
+	e.varintField(2, uint64(m.Code))
+	e.stringField(3, m.Msg)
+	e.stringField(4, m.SQLState)
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *ErrorMsg) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Severity = uint32(f.u)
+		case 2:
+			m.Code = uint32(f.u)
+		case 3:
+			m.Msg = f.string()
+		case 4:
+			m.SQLState = f.string()
+		}
+		return nil
+	})
+}
+
+// The types and scopes of notices.
+const (
+	NoticeWarning             = 1
+	NoticeSessionStateChanged = 3
+	NoticeScopeGlobal         = 1
+	NoticeScopeLocal          = 2
+)
+
+// Frame is a notice.
+type Frame struct {
+	Type    uint32
+	Scope   uint32
+	Payload []byte
+}
+
+// Marshal implements the Message interface.
+func (m *Frame) Marshal() []byte {
+	var e encoder
+	e.varintField(1, uint64(m.Type))
+	if m.Scope != 0 {
+		e.varintField(2, uint64(m.Scope))
+	}
+	if m.Payload != nil {
+		e.bytesField(3, m.Payload)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Frame) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Type = uint32(f.u)
+		case 2:
+			m.Scope = uint32(f.u)
+		case 3:
+			m.Payload = f.bytes()
+		}
+		return nil
+	})
+}
+
+// The levels of warnings.
+const (
+	WarningLevelNote    = 1
+	WarningLevelWarning = 2
+	WarningLevelError   = 3
+)
+
+// Warning is the payload of the warning notice.
+type Warning struct {
+	Level uint32
+	Code  uint32
+	Msg   string
+}
+
+// Marshal implements the Message interface.
+func (m *Warning) Marshal() []byte {
+	var e encoder
+	e.varintField(1, uint64(m.Level))
+	e.varintField(2, uint64(m.Code))
+	e.stringField(3, m.Msg)
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Warning) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Level = uint32(f.u)
+		case 2:
+			m.Code = uint32(f.u)
+		case 3:
+			m.Msg = f.string()
+		}
+		return nil
+	})
+}
+
+// The parameters of SessionStateChanged.
+const (
+	StateCurrentSchema        = 1
+	StateGeneratedInsertID    = 3
+	StateRowsAffected         = 4
+	StateRowsFound            = 5
+	StateRowsMatched          = 6
+	StateProducedMessage      = 10
+	StateClientIDAssigned     = 11
+	StateGeneratedDocumentIDs = 12
+)
+
+// SessionStateChanged is the payload of the session state changed notice.
+type SessionStateChanged struct {
+	Param  uint32
+	Values []*Scalar
+}
+
+// Marshal implements the Message interface.
+func (m *SessionStateChanged) Marshal() []byte {
+	var e encoder
+	e.varintField(1, uint64(m.Param))
+	for _, v := range m.Values {
+		e.messageField(2, v)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *SessionStateChanged) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Param = uint32(f.u)
+		case 2:
+			v := &Scalar{}
+			m.Values = append(m.Values, v)
+			return v.Unmarshal(f.b)
+		}
+		return nil
+	})
+}
+
+// StmtExecute executes a statement in the namespace, such as "sql" and "mysqlx".
+type StmtExecute struct {
+	Namespace string
+	Stmt      []byte
+	Args      []*Any
+}
+
+// Marshal implements the Message interface.
+func (m *StmtExecute) Marshal() []byte {
+	var e encoder
+	e.bytesField(1, m.Stmt)
+	for _, arg := range m.Args {
+		e.messageField(2, arg)
+	}
+	if m.Namespace != "" {
+		e.stringField(3, m.Namespace)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *StmtExecute) Unmarshal(b []byte) error {
+	m.Namespace = "sql"
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Stmt = f.bytes()
+		case 2:
+			arg := &Any{}
+			m.Args = append(m.Args, arg)
+			return arg.Unmarshal(f.b)
+		case 3:
+			m.Namespace = f.string()
+		}
+		return nil
+	})
+}
+
+// The column types of ColumnMetaData.
+const (
+	ColumnSInt     = 1
+	ColumnUInt     = 2
+	ColumnDouble   = 5
+	ColumnFloat    = 6
+	ColumnBytes    = 7
+	ColumnTime     = 10
+	ColumnDatetime = 12
+	ColumnSet      = 15
+	ColumnEnum     = 16
+	ColumnBit      = 17
+	ColumnDecimal  = 18
+)
+
+// The content types of the BYTES columns and the octets scalars.
+const (
+	ContentTypeGeometry = 1
+	ContentTypeJSON     = 2
+	ContentTypeXML      = 3
+)
+
+// ColumnMetaData describes a column of the result set.
+type ColumnMetaData struct {
+	Type             uint32
+	Name             string
+	OriginalName     string
+	Table            string
+	OriginalTable    string
+	Schema           string
+	Catalog          string
+	Collation        uint64
+	FractionalDigits uint32
+	Length           uint32
+	Flags            uint32
+	ContentType      uint32
+}
+
+// Marshal implements the Message interface.
+func (m *ColumnMetaData) Marshal() []byte {
+	var e encoder
+	e.varintField(1, uint64(m.Type))
+	e.stringField(2, m.Name)
+	e.stringField(3, m.OriginalName)
+	e.stringField(4, m.Table)
+	e.stringField(5, m.OriginalTable)
+	e.stringField(6, m.Schema)
+	e.stringField(7, m.Catalog)
+	if m.Collation != 0 {
+		e.varintField(8, m.Collation)
+	}
+	e.varintField(9, uint64(m.FractionalDigits))
+	e.varintField(10, uint64(m.Length))
+	e.varintField(11, uint64(m.Flags))
+	if m.ContentType != 0 {
+		e.varintField(12, uint64(m.ContentType))
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *ColumnMetaData) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Type = uint32(f.u)
+		case 2:
+			m.Name = f.string()
+		case 3:
+			m.OriginalName = f.string()
+		case 4:
+			m.Table = f.string()
+		case 5:
+			m.OriginalTable = f.string()
+		case 6:
+			m.Schema = f.string()
+		case 7:
+			m.Catalog = f.string()
+		case 8:
+			m.Collation = f.u
+		case 9:
+			m.FractionalDigits = uint32(f.u)
+		case 10:
+			m.Length = uint32(f.u)
+		case 11:
+			m.Flags = uint32(f.u)
+		case 12:
+			m.ContentType = uint32(f.u)
+		}
+		return nil
+	})
+}
+
+// Row is a row of the result set, the fields are encoded according to the column types.
+type Row struct {
+	Fields [][]byte
+}
+
+// Marshal implements the Message interface.
+func (m *Row) Marshal() []byte {
+	var e encoder
+	for _, fld := range m.Fields {
+		e.bytesField(1, fld)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *Row) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		if f.num == 1 {
+			m.Fields = append(m.Fields, f.bytes())
+		}
+		return nil
+	})
+}
+
+// The operations of ExpectOpen.
+const (
+	ExpectCtxCopyPrev = 0
+	ExpectCtxEmpty    = 1
+)
+
+// ExpectCondition is a condition of ExpectOpen.
+type ExpectCondition struct {
+	Key   uint32
+	Value []byte
+	// Op is 0 to set the condition and 1 to unset it.
+	Op uint32
+}
+
+// ExpectOpen opens an expectation block.
+type ExpectOpen struct {
+	Op    uint32
+	Conds []*ExpectCondition
+}
+
+// Marshal implements the Message interface.
+func (m *ExpectOpen) Marshal() []byte {
+	var e encoder
+	e.varintField(1, uint64(m.Op))
+	for _, c := range m.Conds {
+		var ce encoder
+		ce.varintField(1, uint64(c.Key))
+		if c.Value != nil {
+			ce.bytesField(2, c.Value)
+		}
+		ce.varintField(3, uint64(c.Op))
+		e.bytesField(2, ce)
+	}
+	return e
+}
+
+// Unmarshal implements the Message interface.
+func (m *ExpectOpen) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(f *field) error {
+		switch f.num {
+		case 1:
+			m.Op = uint32(f.u)
+		case 2:
+			c := &ExpectCondition{}
+			m.Conds = append(m.Conds, c)
+			return unmarshalFields(f.b, func(f *field) error {
+				switch f.num {
+				case 1:
+					c.Key = uint32(f.u)
+				case 2:
+					c.Value = f.bytes()
+				case 3:
+					c.Op = uint32(f.u)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
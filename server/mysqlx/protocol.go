@@ -0,0 +1,143 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mysqlx implements the subset of the MySQL X Protocol supported by TiDB, including the framing,
+// the protobuf messages and the translation from the CRUD messages to SQL.
+// See https://dev.mysql.com/doc/dev/mysql-server/latest/page_mysqlx_protocol.html.
+package mysqlx
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/pingcap/errors"
+)
+
+// DefaultPort is the default port of the X Protocol.
+const DefaultPort = 33060
+
+// The types of the client messages.
+const (
+	ClientConCapabilitiesGet       byte = 1
+	ClientConCapabilitiesSet       byte = 2
+	ClientConClose                 byte = 3
+	ClientSessAuthenticateStart    byte = 4
+	ClientSessAuthenticateContinue byte = 5
+	ClientSessReset                byte = 6
+	ClientSessClose                byte = 7
+	ClientSQLStmtExecute           byte = 12
+	ClientCrudFind                 byte = 17
+	ClientCrudInsert               byte = 18
+	ClientCrudUpdate               byte = 19
+	ClientCrudDelete               byte = 20
+	ClientExpectOpen               byte = 24
+	ClientExpectClose              byte = 25
+)
+
+// The types of the server messages.
+const (
+	ServerOk                       byte = 0
+	ServerError                    byte = 1
+	ServerConnCapabilities         byte = 2
+	ServerSessAuthenticateContinue byte = 3
+	ServerSessAuthenticateOk       byte = 4
+	ServerNotice                   byte = 11
+	ServerResultsetColumnMetaData  byte = 12
+	ServerResultsetRow             byte = 13
+	ServerResultsetFetchDone       byte = 14
+	ServerSQLStmtExecuteOk         byte = 17
+)
+
+// The error codes of the X Protocol, they are the same as MySQL.
+const (
+	ErrXBadMessage           uint16 = 5000
+	ErrXCapabilitiesPrepare  uint16 = 5001
+	ErrXCapabilityNotFound   uint16 = 5002
+	ErrXInvalidProtocolData  uint16 = 5003
+	ErrXCmdNumArguments      uint16 = 5015
+	ErrXCmdArgumentType      uint16 = 5016
+	ErrXBadUpdateData        uint16 = 5050
+	ErrXBadMemberToUpdate    uint16 = 5053
+	ErrXBadInsertData        uint16 = 5013
+	ErrXBadProjection        uint16 = 5114
+	ErrXExprBadOperator      uint16 = 5150
+	ErrXExprBadNumArgs       uint16 = 5151
+	ErrXExprMissingArg       uint16 = 5152
+	ErrXExprBadTypeValue     uint16 = 5153
+	ErrXExprBadValue         uint16 = 5154
+	ErrXInvalidCollection    uint16 = 5156
+	ErrXInvalidAdminCommand  uint16 = 5157
+	ErrXExpectNotOpen        uint16 = 5158
+	ErrXExpectFailed         uint16 = 5159
+	ErrXExpectBadCondition   uint16 = 5160
+	ErrXExpectFieldExists    uint16 = 5168
+	ErrXInvalidNamespace     uint16 = 5162
+	ErrXBadLocking           uint16 = 5169
+	ErrUnknownComError       uint16 = 1047
+	ErrAccessDeniedError     uint16 = 1045
+	ErrXUnsupportedDataModel uint16 = 5155
+)
+
+// defaultSQLState is the SQL state of the errors which are not from SQL statements.
+const defaultSQLState = "HY000"
+
+// maxMessageSize is the maximum size of a message, the same as the default mysqlx_max_allowed_packet.
+const maxMessageSize = 64 * 1024 * 1024
+
+// Error is an error which is sent to the client with its code.
+type Error struct {
+	Code     uint16
+	SQLState string
+	Msg      string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+// NewError creates an Error with the code and the message.
+func NewError(code uint16, format string, args ...interface{}) *Error {
+	return &Error{Code: code, SQLState: defaultSQLState, Msg: errors.Errorf(format, args...).Error()}
+}
+
+// ReadMessage reads a message, which is the 4 bytes length in little endian, the 1 byte type and the payload.
+func ReadMessage(r io.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	length := binary.LittleEndian.Uint32(header[:4])
+	if length < 1 || length > maxMessageSize {
+		return 0, nil, NewError(ErrXBadMessage, "invalid message length %d", length)
+	}
+	payload := make([]byte, length-1)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	return header[4], payload, nil
+}
+
+// WriteMessage writes a message.
+func WriteMessage(w io.Writer, tp byte, msg Message) error {
+	var payload []byte
+	if msg != nil {
+		payload = msg.Marshal()
+	}
+	buf := make([]byte, 5, 5+len(payload))
+	binary.LittleEndian.PutUint32(buf, uint32(len(payload)+1))
+	buf[4] = tp
+	_, err := w.Write(append(buf, payload...))
+	return errors.Trace(err)
+}
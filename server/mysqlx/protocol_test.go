@@ -0,0 +1,129 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadWriteMessage(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteMessage(&buf, ServerOk, &Ok{Msg: "bye!"}))
+	require.NoError(t, WriteMessage(&buf, ServerResultsetFetchDone, nil))
+	require.Equal(t, []byte{7, 0, 0, 0, ServerOk, 0x0a, 4, 'b', 'y', 'e', '!', 1, 0, 0, 0, ServerResultsetFetchDone}, buf.Bytes())
+
+	tp, payload, err := ReadMessage(&buf)
+	require.NoError(t, err)
+	require.Equal(t, ServerOk, tp)
+	var ok Ok
+	require.NoError(t, ok.Unmarshal(payload))
+	require.Equal(t, "bye!", ok.Msg)
+	tp, payload, err = ReadMessage(&buf)
+	require.NoError(t, err)
+	require.Equal(t, ServerResultsetFetchDone, tp)
+	require.Len(t, payload, 0)
+
+	// Invalid length.
+	header := make([]byte, 5)
+	binary.LittleEndian.PutUint32(header, maxMessageSize+1)
+	_, _, err = ReadMessage(bytes.NewReader(header))
+	require.Equal(t, ErrXBadMessage, err.(*Error).Code)
+	_, _, err = ReadMessage(bytes.NewReader([]byte{3, 0, 0, 0, 1}))
+	require.Error(t, err)
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	str := func(s string) *Expr {
+		return &Expr{Type: ExprLiteral, Literal: &Scalar{Type: ScalarString, String: []byte(s)}}
+	}
+	messages := []Message{
+		&Capabilities{Capabilities: []*Capability{
+			{Name: "tls", Value: NewScalarAny(&Scalar{Type: ScalarBool, Bool: true})},
+			{Name: "authentication.mechanisms", Value: &Any{Type: AnyArray, Array: &Array{Values: []*Any{
+				NewScalarAny(&Scalar{Type: ScalarString, String: []byte("MYSQL41")}),
+			}}}},
+			{Name: "session_connect_attrs", Value: &Any{Type: AnyObject, Object: &Object{Fields: []*ObjectField{
+				{Key: "_client_name", Value: NewScalarAny(&Scalar{Type: ScalarOctets, Octets: []byte("test"), ContentType: ContentTypeJSON})},
+			}}}},
+		}},
+		&AuthenticateStart{MechName: "MYSQL41", AuthData: []byte("data")},
+		&ErrorMsg{Severity: SeverityFatal, Code: 1045, SQLState: "28000", Msg: "access denied"},
+		&SessionStateChanged{Param: StateRowsAffected, Values: []*Scalar{{Type: ScalarUInt, UInt: 3}, {Type: ScalarSInt, SInt: -3}}},
+		&ColumnMetaData{Type: ColumnBytes, Name: "doc", Collation: 63, Length: 10, Flags: 1, ContentType: ContentTypeJSON},
+		&Row{Fields: [][]byte{{1}, {}, []byte("abc")}},
+		&StmtExecute{Namespace: "mysqlx", Stmt: []byte("ping")},
+		&Scalar{Type: ScalarDouble, Double: 1.5},
+		&Scalar{Type: ScalarFloat, Float: -2.5},
+		&Find{
+			Collection: &Collection{Name: "c", Schema: "test"},
+			DataModel:  DataModelDocument,
+			Projection: []*Projection{{Source: &Expr{Type: ExprIdent, Identifier: &ColumnIdentifier{
+				DocumentPath: []*DocumentPathItem{{Type: PathMember, Value: "a"}, {Type: PathArrayIndex, Index: 2}},
+			}}, Alias: "x"}},
+			Criteria: &Expr{Type: ExprOperator, Operator: &Operator{Name: "==", Params: []*Expr{
+				{Type: ExprPlaceholder, Position: 0},
+				{Type: ExprFuncCall, FuncCall: &FunctionCall{Name: &Identifier{Name: "concat"}, Params: []*Expr{str("a"), str("b")}}},
+			}}},
+			Args:      []*Scalar{{Type: ScalarNull}},
+			Order:     []*Order{{Expr: str("a"), Direction: OrderDesc}},
+			LimitExpr: &LimitExpr{RowCount: &Expr{Type: ExprPlaceholder, Position: 1}},
+			Locking:   LockExclusive,
+		},
+		&Insert{
+			Collection: &Collection{Name: "c"},
+			Rows: [][]*Expr{{{Type: ExprObject, Object: &ObjectExpr{Fields: []*ObjectExprField{
+				{Key: "a", Value: &Expr{Type: ExprArray, Array: &ArrayExpr{Values: []*Expr{str("x")}}}},
+			}}}}},
+			Upsert: true,
+		},
+		&Update{
+			Collection: &Collection{Name: "c"},
+			Operations: []*UpdateOperation{{Source: &ColumnIdentifier{Name: "a"}, Operation: UpdateItemRemove}},
+			Limit:      &Limit{RowCount: 1, Offset: 2},
+		},
+		&Delete{Collection: &Collection{Name: "c"}, DataModel: DataModelTable, Criteria: str("1")},
+	}
+	for _, m := range messages {
+		decoded := reflect.New(reflect.TypeOf(m).Elem()).Interface().(Message)
+		require.NoError(t, decoded.Unmarshal(m.Marshal()))
+		if stmt, ok := m.(*StmtExecute); ok && stmt.Namespace == "" {
+			stmt.Namespace = "sql"
+		}
+		require.Equal(t, m, decoded)
+	}
+
+	// The default namespace is sql.
+	var stmt StmtExecute
+	require.NoError(t, stmt.Unmarshal((&StmtExecute{Stmt: []byte("select 1")}).Marshal()))
+	require.Equal(t, "sql", stmt.Namespace)
+
+	// The unknown fields are skipped.
+	var e encoder
+	e.stringField(1, "ok")
+	e.doubleField(100, 1)
+	e.varintField(101, 1)
+	var ok Ok
+	require.NoError(t, ok.Unmarshal(e))
+	require.Equal(t, "ok", ok.Msg)
+
+	// Truncated message.
+	require.Error(t, ok.Unmarshal(e[:len(e)-1]))
+	require.Error(t, ok.Unmarshal([]byte{0x0a, 5, 'a'}))
+}
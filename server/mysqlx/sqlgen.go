@@ -0,0 +1,1119 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// The columns of the tables which back the collections.
+const (
+	docColumn = "doc"
+	idColumn  = "_id"
+)
+
+// CreateCollectionSQL returns the statement which creates the table of a collection. The documents are stored in
+// the JSON column doc and the primary key _id is generated from the _id member of the documents.
+func CreateCollectionSQL(schema, name string, ifNotExists bool) string {
+	var b strings.Builder
+	b.WriteString("CREATE TABLE ")
+	if ifNotExists {
+		b.WriteString("IF NOT EXISTS ")
+	}
+	writeCollection(&b, &Collection{Schema: schema, Name: name})
+	b.WriteString(" (doc JSON, _id VARBINARY(32) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(doc, _utf8mb4'$._id'))) STORED NOT NULL, PRIMARY KEY (_id))")
+	return b.String()
+}
+
+// ListObjectsSQL returns the statement which lists the collections, the tables and the views in the schema.
+// A table is a collection if it only has the columns doc and _id.
+func ListObjectsSQL(schema, pattern string) string {
+	var b strings.Builder
+	b.WriteString("SELECT T.TABLE_NAME AS name, IF(T.TABLE_TYPE = 'VIEW', 'VIEW', IF(COUNT(C.COLUMN_NAME) = 2 AND " +
+		"SUM(C.COLUMN_NAME IN ('doc', '_id')) = 2, 'COLLECTION', 'TABLE')) AS type FROM INFORMATION_SCHEMA.TABLES T " +
+		"JOIN INFORMATION_SCHEMA.COLUMNS C ON T.TABLE_SCHEMA = C.TABLE_SCHEMA AND T.TABLE_NAME = C.TABLE_NAME WHERE ")
+	if schema == "" {
+		b.WriteString("T.TABLE_SCHEMA = DATABASE()")
+	} else {
+		sqlexec.MustFormatSQL(&b, "T.TABLE_SCHEMA = %?", schema)
+	}
+	if pattern != "" {
+		sqlexec.MustFormatSQL(&b, " AND T.TABLE_NAME LIKE %?", pattern)
+	}
+	b.WriteString(" GROUP BY T.TABLE_NAME, T.TABLE_TYPE ORDER BY T.TABLE_NAME")
+	return b.String()
+}
+
+func writeCollection(b *strings.Builder, c *Collection) {
+	if c.Schema != "" {
+		sqlexec.MustFormatSQL(b, "%n.", c.Schema)
+	}
+	sqlexec.MustFormatSQL(b, "%n", c.Name)
+}
+
+// IDGenerator generates the _id of the inserted documents which do not have one. Like MySQL, an id is
+// the hex of the 2 bytes prefix, the 6 bytes start time and the 8 bytes serial number, so the ids from
+// a server are increasing.
+type IDGenerator struct {
+	prefix uint16
+	start  uint64
+	serial uint64
+}
+
+// NewIDGenerator creates an IDGenerator, prefix should be different among the servers of the cluster.
+func NewIDGenerator(prefix uint16) *IDGenerator {
+	return &IDGenerator{prefix: prefix, start: uint64(time.Now().Unix())}
+}
+
+// Next returns the next id.
+func (g *IDGenerator) Next() string {
+	return fmt.Sprintf("%04x%012x%016x", g.prefix, g.start&0xffffffffffff, atomic.AddUint64(&g.serial, 1))
+}
+
+// generator translates the CRUD messages to SQL.
+type generator struct {
+	buf      strings.Builder
+	args     []*Scalar
+	docModel bool
+}
+
+func newGenerator(dataModel uint32, args []*Scalar) *generator {
+	return &generator{args: args, docModel: dataModel != DataModelTable}
+}
+
+// sub writes the output of fn to a new buffer and returns it.
+func (g *generator) sub(fn func() error) (string, error) {
+	saved := g.buf
+	g.buf = strings.Builder{}
+	err := fn()
+	s := g.buf.String()
+	g.buf = saved
+	return s, err
+}
+
+func (g *generator) collection(c *Collection) error {
+	if c == nil || c.Name == "" {
+		return NewError(ErrXInvalidCollection, "invalid collection")
+	}
+	writeCollection(&g.buf, c)
+	return nil
+}
+
+// DocumentPath returns the JSON path of the document path, such as $.a[0].
+func DocumentPath(path []*DocumentPathItem) (string, error) {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, item := range path {
+		switch item.Type {
+		case PathMember:
+			b.WriteByte('.')
+			if isPathIdentifier(item.Value) {
+				b.WriteString(item.Value)
+			} else {
+				b.WriteString(strconv.Quote(item.Value))
+			}
+		case PathMemberAsterisk:
+			b.WriteString(".*")
+		case PathArrayIndex:
+			fmt.Fprintf(&b, "[%d]", item.Index)
+		case PathArrayIndexAsterisk:
+			b.WriteString("[*]")
+		case PathDoubleAsterisk:
+			b.WriteString("**")
+		default:
+			return "", NewError(ErrXExprBadValue, "invalid document path item type %d", item.Type)
+		}
+	}
+	return b.String(), nil
+}
+
+func isPathIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		if c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func (g *generator) path(path []*DocumentPathItem) error {
+	p, err := DocumentPath(path)
+	if err != nil {
+		return err
+	}
+	sqlexec.MustFormatSQL(&g.buf, "%?", p)
+	return nil
+}
+
+// column writes the column of the identifier, it's doc for the documents.
+func (g *generator) column(id *ColumnIdentifier) error {
+	if id.Name == "" {
+		if !g.docModel {
+			return NewError(ErrXExprBadValue, "the column name is required for tables")
+		}
+		g.buf.WriteString(docColumn)
+		return nil
+	}
+	if id.SchemaName != "" {
+		sqlexec.MustFormatSQL(&g.buf, "%n.", id.SchemaName)
+	}
+	if id.TableName != "" {
+		sqlexec.MustFormatSQL(&g.buf, "%n.", id.TableName)
+	}
+	sqlexec.MustFormatSQL(&g.buf, "%n", id.Name)
+	return nil
+}
+
+func (g *generator) ident(id *ColumnIdentifier) error {
+	if id == nil {
+		return NewError(ErrXExprMissingArg, "missing identifier")
+	}
+	if len(id.DocumentPath) == 0 {
+		return g.column(id)
+	}
+	g.buf.WriteString("JSON_EXTRACT(")
+	if err := g.column(id); err != nil {
+		return err
+	}
+	g.buf.WriteString(", ")
+	if err := g.path(id.DocumentPath); err != nil {
+		return err
+	}
+	g.buf.WriteByte(')')
+	return nil
+}
+
+func (g *generator) placeholder(pos uint32) (*Scalar, error) {
+	if int(pos) >= len(g.args) {
+		return nil, NewError(ErrXExprMissingArg, "invalid placeholder position %d", pos)
+	}
+	return g.args[pos], nil
+}
+
+func (g *generator) scalar(s *Scalar) error {
+	if s == nil {
+		return NewError(ErrXExprMissingArg, "missing literal")
+	}
+	switch s.Type {
+	case ScalarSInt:
+		sqlexec.MustFormatSQL(&g.buf, "%?", s.SInt)
+	case ScalarUInt:
+		sqlexec.MustFormatSQL(&g.buf, "%?", s.UInt)
+	case ScalarNull:
+		g.buf.WriteString("NULL")
+	case ScalarOctets:
+		if s.ContentType == ContentTypeJSON {
+			sqlexec.MustFormatSQL(&g.buf, "CAST(%? AS JSON)", string(s.Octets))
+		} else {
+			sqlexec.MustFormatSQL(&g.buf, "%?", string(s.Octets))
+		}
+	case ScalarDouble:
+		sqlexec.MustFormatSQL(&g.buf, "%?", s.Double)
+	case ScalarFloat:
+		sqlexec.MustFormatSQL(&g.buf, "%?", s.Float)
+	case ScalarBool:
+		if s.Bool {
+			g.buf.WriteString("TRUE")
+		} else {
+			g.buf.WriteString("FALSE")
+		}
+	case ScalarString:
+		sqlexec.MustFormatSQL(&g.buf, "%?", string(s.String))
+	default:
+		return NewError(ErrXExprBadTypeValue, "invalid scalar type %d", s.Type)
+	}
+	return nil
+}
+
+func (g *generator) list(exprs []*Expr, fn func(e *Expr) error) error {
+	for i, e := range exprs {
+		if i > 0 {
+			g.buf.WriteString(", ")
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) expr(e *Expr) error {
+	if e == nil {
+		return NewError(ErrXExprMissingArg, "missing expression")
+	}
+	switch e.Type {
+	case ExprIdent:
+		return g.ident(e.Identifier)
+	case ExprLiteral:
+		return g.scalar(e.Literal)
+	case ExprPlaceholder:
+		s, err := g.placeholder(e.Position)
+		if err != nil {
+			return err
+		}
+		return g.scalar(s)
+	case ExprFuncCall:
+		return g.funcCall(e.FuncCall)
+	case ExprOperator:
+		return g.operator(e.Operator)
+	case ExprObject:
+		if e.Object == nil {
+			return NewError(ErrXExprMissingArg, "missing object")
+		}
+		g.buf.WriteString("JSON_OBJECT(")
+		for i, fld := range e.Object.Fields {
+			if i > 0 {
+				g.buf.WriteString(", ")
+			}
+			sqlexec.MustFormatSQL(&g.buf, "%?, ", fld.Key)
+			if err := g.expr(fld.Value); err != nil {
+				return err
+			}
+		}
+		g.buf.WriteByte(')')
+		return nil
+	case ExprArray:
+		if e.Array == nil {
+			return NewError(ErrXExprMissingArg, "missing array")
+		}
+		g.buf.WriteString("JSON_ARRAY(")
+		if err := g.list(e.Array.Values, g.expr); err != nil {
+			return err
+		}
+		g.buf.WriteByte(')')
+		return nil
+	}
+	return NewError(ErrXExprBadTypeValue, "unsupported expression type %d", e.Type)
+}
+
+// jsonValue writes the expression as a JSON value, the strings are quoted as JSON strings.
+func (g *generator) jsonValue(e *Expr) error {
+	if e == nil {
+		return NewError(ErrXExprMissingArg, "missing expression")
+	}
+	s := e.Literal
+	if e.Type == ExprPlaceholder {
+		var err error
+		if s, err = g.placeholder(e.Position); err != nil {
+			return err
+		}
+	}
+	switch {
+	case e.Type == ExprIdent && e.Identifier != nil && len(e.Identifier.DocumentPath) > 0, e.Type == ExprObject, e.Type == ExprArray:
+		return g.expr(e)
+	case s != nil && (s.Type == ScalarString || (s.Type == ScalarOctets && s.ContentType != ContentTypeJSON)):
+		g.buf.WriteString("JSON_QUOTE(")
+		if err := g.scalar(s); err != nil {
+			return err
+		}
+		g.buf.WriteByte(')')
+		return nil
+	}
+	g.buf.WriteString("CAST(")
+	if err := g.expr(e); err != nil {
+		return err
+	}
+	g.buf.WriteString(" AS JSON)")
+	return nil
+}
+
+func isSQLIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c != '_' && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *generator) funcCall(fc *FunctionCall) error {
+	if fc == nil || fc.Name == nil || !isSQLIdentifier(fc.Name.Name) {
+		return NewError(ErrXExprBadValue, "invalid function name")
+	}
+	if fc.Name.SchemaName != "" {
+		sqlexec.MustFormatSQL(&g.buf, "%n.", fc.Name.SchemaName)
+	}
+	g.buf.WriteString(fc.Name.Name)
+	g.buf.WriteByte('(')
+	if err := g.list(fc.Params, g.expr); err != nil {
+		return err
+	}
+	g.buf.WriteByte(')')
+	return nil
+}
+
+var binaryOperators = map[string]string{
+	"==":         "=",
+	"!=":         "<>",
+	"<":          "<",
+	"<=":         "<=",
+	">":          ">",
+	">=":         ">=",
+	"&&":         "AND",
+	"||":         "OR",
+	"xor":        "XOR",
+	"+":          "+",
+	"-":          "-",
+	"*":          "*",
+	"/":          "/",
+	"div":        "DIV",
+	"%":          "%",
+	"&":          "&",
+	"|":          "|",
+	"^":          "^",
+	"<<":         "<<",
+	">>":         ">>",
+	"is":         "IS",
+	"is_not":     "IS NOT",
+	"like":       "LIKE",
+	"not_like":   "NOT LIKE",
+	"regexp":     "REGEXP",
+	"not_regexp": "NOT REGEXP",
+}
+
+var unaryOperators = map[string]string{
+	"!":          "NOT ",
+	"not":        "NOT ",
+	"~":          "~",
+	"sign_plus":  "+",
+	"sign_minus": "-",
+}
+
+var intervalUnits = map[string]struct{}{
+	"MICROSECOND": {}, "SECOND": {}, "MINUTE": {}, "HOUR": {}, "DAY": {}, "WEEK": {}, "MONTH": {}, "QUARTER": {},
+	"YEAR": {}, "SECOND_MICROSECOND": {}, "MINUTE_MICROSECOND": {}, "MINUTE_SECOND": {}, "HOUR_MICROSECOND": {},
+	"HOUR_SECOND": {}, "HOUR_MINUTE": {}, "DAY_MICROSECOND": {}, "DAY_SECOND": {}, "DAY_MINUTE": {}, "DAY_HOUR": {},
+	"YEAR_MONTH": {},
+}
+
+var castTypes = map[string]struct{}{
+	"BINARY": {}, "CHAR": {}, "DATE": {}, "DATETIME": {}, "DECIMAL": {}, "JSON": {}, "SIGNED": {}, "SIGNED INTEGER": {},
+	"TIME": {}, "UNSIGNED": {}, "UNSIGNED INTEGER": {}, "DOUBLE": {},
+}
+
+// keyword returns the upper-cased string of a literal, which is the type of CAST or the unit of INTERVAL.
+func keyword(e *Expr, valid map[string]struct{}) (string, error) {
+	if e != nil && e.Type == ExprLiteral && e.Literal != nil {
+		var s string
+		switch e.Literal.Type {
+		case ScalarOctets:
+			s = string(e.Literal.Octets)
+		case ScalarString:
+			s = string(e.Literal.String)
+		}
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if _, ok := valid[strings.SplitN(s, "(", 2)[0]]; ok {
+			return s, nil
+		}
+	}
+	return "", NewError(ErrXExprBadValue, "invalid keyword argument")
+}
+
+func (g *generator) operator(op *Operator) error {
+	if op == nil {
+		return NewError(ErrXExprMissingArg, "missing operator")
+	}
+	params := op.Params
+	checkParams := func(valid bool) error {
+		if !valid {
+			return NewError(ErrXExprBadNumArgs, "invalid number of arguments for operator %s", op.Name)
+		}
+		return nil
+	}
+	name := strings.ToLower(op.Name)
+	if sqlOp, ok := binaryOperators[name]; ok {
+		if name == "*" && len(params) == 0 {
+			g.buf.WriteByte('*')
+			return nil
+		}
+		if (name == "like" || name == "not_like") && len(params) == 3 {
+			if err := g.infix(sqlOp, params[:2]); err != nil {
+				return err
+			}
+			g.buf.WriteString(" ESCAPE ")
+			return g.expr(params[2])
+		}
+		if err := checkParams(len(params) == 2); err != nil {
+			return err
+		}
+		return g.infix(sqlOp, params)
+	}
+	if sqlOp, ok := unaryOperators[name]; ok {
+		if err := checkParams(len(params) == 1); err != nil {
+			return err
+		}
+		g.buf.WriteString("(" + sqlOp)
+		if err := g.expr(params[0]); err != nil {
+			return err
+		}
+		g.buf.WriteByte(')')
+		return nil
+	}
+	switch name {
+	case "in", "not_in":
+		if err := checkParams(len(params) >= 2); err != nil {
+			return err
+		}
+		g.buf.WriteByte('(')
+		if err := g.expr(params[0]); err != nil {
+			return err
+		}
+		if name == "in" {
+			g.buf.WriteString(" IN (")
+		} else {
+			g.buf.WriteString(" NOT IN (")
+		}
+		if err := g.list(params[1:], g.expr); err != nil {
+			return err
+		}
+		g.buf.WriteString("))")
+		return nil
+	case "cont_in", "not_cont_in":
+		if err := checkParams(len(params) == 2); err != nil {
+			return err
+		}
+		if name == "not_cont_in" {
+			g.buf.WriteString("(NOT ")
+		}
+		g.buf.WriteString("JSON_CONTAINS(")
+		if err := g.jsonValue(params[1]); err != nil {
+			return err
+		}
+		g.buf.WriteString(", ")
+		if err := g.jsonValue(params[0]); err != nil {
+			return err
+		}
+		g.buf.WriteByte(')')
+		if name == "not_cont_in" {
+			g.buf.WriteByte(')')
+		}
+		return nil
+	case "between", "not_between":
+		if err := checkParams(len(params) == 3); err != nil {
+			return err
+		}
+		g.buf.WriteByte('(')
+		if err := g.expr(params[0]); err != nil {
+			return err
+		}
+		if name == "between" {
+			g.buf.WriteString(" BETWEEN ")
+		} else {
+			g.buf.WriteString(" NOT BETWEEN ")
+		}
+		if err := g.expr(params[1]); err != nil {
+			return err
+		}
+		g.buf.WriteString(" AND ")
+		if err := g.expr(params[2]); err != nil {
+			return err
+		}
+		g.buf.WriteByte(')')
+		return nil
+	case "cast":
+		if err := checkParams(len(params) == 2); err != nil {
+			return err
+		}
+		tp, err := keyword(params[1], castTypes)
+		if err != nil {
+			return err
+		}
+		g.buf.WriteString("CAST(")
+		if err := g.expr(params[0]); err != nil {
+			return err
+		}
+		g.buf.WriteString(" AS " + tp + ")")
+		return nil
+	case "date_add", "date_sub":
+		if err := checkParams(len(params) == 3); err != nil {
+			return err
+		}
+		unit, err := keyword(params[2], intervalUnits)
+		if err != nil {
+			return err
+		}
+		g.buf.WriteString(strings.ToUpper(name) + "(")
+		if err := g.expr(params[0]); err != nil {
+			return err
+		}
+		g.buf.WriteString(", INTERVAL ")
+		if err := g.expr(params[1]); err != nil {
+			return err
+		}
+		g.buf.WriteString(" " + unit + ")")
+		return nil
+	}
+	return NewError(ErrXExprBadOperator, "invalid operator %s", op.Name)
+}
+
+func (g *generator) infix(op string, params []*Expr) error {
+	g.buf.WriteByte('(')
+	if err := g.expr(params[0]); err != nil {
+		return err
+	}
+	g.buf.WriteString(" " + op + " ")
+	if err := g.expr(params[1]); err != nil {
+		return err
+	}
+	g.buf.WriteByte(')')
+	return nil
+}
+
+func (g *generator) where(criteria *Expr) error {
+	if criteria == nil {
+		return nil
+	}
+	g.buf.WriteString(" WHERE ")
+	return g.expr(criteria)
+}
+
+func (g *generator) orderBy(orders []*Order) error {
+	for i, o := range orders {
+		if i == 0 {
+			g.buf.WriteString(" ORDER BY ")
+		} else {
+			g.buf.WriteString(", ")
+		}
+		if err := g.expr(o.Expr); err != nil {
+			return err
+		}
+		if o.Direction == OrderDesc {
+			g.buf.WriteString(" DESC")
+		}
+	}
+	return nil
+}
+
+// limitValue returns the value of an expression in LimitExpr, which must be a literal or a placeholder.
+func (g *generator) limitValue(e *Expr) (uint64, error) {
+	s := e.Literal
+	if e.Type == ExprPlaceholder {
+		var err error
+		if s, err = g.placeholder(e.Position); err != nil {
+			return 0, err
+		}
+	} else if e.Type != ExprLiteral {
+		s = nil
+	}
+	if s != nil {
+		switch {
+		case s.Type == ScalarUInt:
+			return s.UInt, nil
+		case s.Type == ScalarSInt && s.SInt >= 0:
+			return uint64(s.SInt), nil
+		}
+	}
+	return 0, NewError(ErrXExprBadTypeValue, "invalid value of limit")
+}
+
+func (g *generator) limit(limit *Limit, limitExpr *LimitExpr, allowOffset bool) error {
+	var count, offset uint64
+	switch {
+	case limitExpr != nil && limitExpr.RowCount != nil:
+		var err error
+		if count, err = g.limitValue(limitExpr.RowCount); err != nil {
+			return err
+		}
+		if limitExpr.Offset != nil {
+			if offset, err = g.limitValue(limitExpr.Offset); err != nil {
+				return err
+			}
+		}
+	case limit != nil:
+		count, offset = limit.RowCount, limit.Offset
+	default:
+		return nil
+	}
+	if offset > 0 {
+		if !allowOffset {
+			return NewError(ErrXInvalidProtocolData, "invalid parameter: non-zero offset value not allowed for this operation")
+		}
+		fmt.Fprintf(&g.buf, " LIMIT %d, %d", offset, count)
+		return nil
+	}
+	fmt.Fprintf(&g.buf, " LIMIT %d", count)
+	return nil
+}
+
+// BuildFind translates Find to a SELECT statement. The result set of the documents has only one column doc.
+func BuildFind(m *Find) (string, error) {
+	g := newGenerator(m.DataModel, m.Args)
+	g.buf.WriteString("SELECT ")
+	if err := g.projection(m.Projection); err != nil {
+		return "", err
+	}
+	g.buf.WriteString(" FROM ")
+	if err := g.collection(m.Collection); err != nil {
+		return "", err
+	}
+	if err := g.where(m.Criteria); err != nil {
+		return "", err
+	}
+	for i, e := range m.Grouping {
+		if i == 0 {
+			g.buf.WriteString(" GROUP BY ")
+		} else {
+			g.buf.WriteString(", ")
+		}
+		if err := g.expr(e); err != nil {
+			return "", err
+		}
+	}
+	if m.GroupingCri != nil {
+		g.buf.WriteString(" HAVING ")
+		if err := g.expr(m.GroupingCri); err != nil {
+			return "", err
+		}
+	}
+	if err := g.orderBy(m.Order); err != nil {
+		return "", err
+	}
+	if err := g.limit(m.Limit, m.LimitExpr, true); err != nil {
+		return "", err
+	}
+	switch m.Locking {
+	case 0:
+	case LockShared:
+		g.buf.WriteString(" LOCK IN SHARE MODE")
+	case LockExclusive:
+		g.buf.WriteString(" FOR UPDATE")
+	default:
+		return "", NewError(ErrXBadLocking, "invalid row locking type %d", m.Locking)
+	}
+	return g.buf.String(), nil
+}
+
+func (g *generator) projection(projection []*Projection) error {
+	if len(projection) == 0 {
+		if g.docModel {
+			g.buf.WriteString(docColumn)
+		} else {
+			g.buf.WriteByte('*')
+		}
+		return nil
+	}
+	if g.docModel {
+		g.buf.WriteString("JSON_OBJECT(")
+	}
+	for i, p := range projection {
+		if i > 0 {
+			g.buf.WriteString(", ")
+		}
+		alias := p.Alias
+		if alias == "" && p.Source != nil && p.Source.Type == ExprIdent && p.Source.Identifier != nil {
+			if path := p.Source.Identifier.DocumentPath; len(path) > 0 && path[len(path)-1].Type == PathMember {
+				alias = path[len(path)-1].Value
+			} else if !g.docModel {
+				alias = p.Source.Identifier.Name
+			}
+		}
+		if g.docModel {
+			if alias == "" {
+				return NewError(ErrXBadProjection, "invalid projection target name")
+			}
+			sqlexec.MustFormatSQL(&g.buf, "%?, ", alias)
+		}
+		if err := g.expr(p.Source); err != nil {
+			return err
+		}
+		if !g.docModel && alias != "" {
+			sqlexec.MustFormatSQL(&g.buf, " AS %n", alias)
+		}
+	}
+	if g.docModel {
+		g.buf.WriteString(") AS doc")
+	}
+	return nil
+}
+
+// documentID returns whether the document has the _id member, ok is false if it's unknown.
+func (g *generator) documentID(e *Expr) (hasID bool, ok bool, err error) {
+	switch e.Type {
+	case ExprObject:
+		if e.Object == nil {
+			return false, false, NewError(ErrXExprMissingArg, "missing object")
+		}
+		for _, fld := range e.Object.Fields {
+			if fld.Key == idColumn {
+				return true, true, nil
+			}
+		}
+		return false, true, nil
+	case ExprLiteral, ExprPlaceholder:
+		s := e.Literal
+		if e.Type == ExprPlaceholder {
+			if s, err = g.placeholder(e.Position); err != nil {
+				return false, false, err
+			}
+		}
+		if s == nil {
+			return false, false, NewError(ErrXExprMissingArg, "missing literal")
+		}
+		var doc []byte
+		switch s.Type {
+		case ScalarOctets:
+			doc = s.Octets
+		case ScalarString:
+			doc = s.String
+		default:
+			return false, false, NewError(ErrXBadInsertData, "invalid document")
+		}
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(doc, &members); err != nil {
+			return false, false, NewError(ErrXBadInsertData, "invalid JSON document: %s", err.Error())
+		}
+		_, hasID = members[idColumn]
+		return hasID, true, nil
+	}
+	return false, false, nil
+}
+
+// document writes the inserted document, the _id member is added if it's missing.
+// It returns the generated id, which is empty if the document has the _id member or it's unknown.
+func (g *generator) document(e *Expr, ids *IDGenerator) (string, error) {
+	if e == nil {
+		return "", NewError(ErrXBadInsertData, "missing document")
+	}
+	hasID, ok, err := g.documentID(e)
+	if err != nil {
+		return "", err
+	}
+	value, err := g.sub(func() error {
+		if e.Type != ExprLiteral && e.Type != ExprPlaceholder {
+			return g.expr(e)
+		}
+		// documentID has checked that the scalar is a string or octets.
+		s := e.Literal
+		if e.Type == ExprPlaceholder {
+			s = g.args[e.Position]
+		}
+		if s.Type == ScalarOctets {
+			sqlexec.MustFormatSQL(&g.buf, "CAST(%? AS JSON)", string(s.Octets))
+		} else {
+			sqlexec.MustFormatSQL(&g.buf, "CAST(%? AS JSON)", string(s.String))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if hasID {
+		g.buf.WriteString(value)
+		return "", nil
+	}
+	id := ids.Next()
+	g.buf.WriteString("JSON_INSERT(" + value + ", ")
+	sqlexec.MustFormatSQL(&g.buf, "'$._id', %?)", id)
+	if !ok {
+		return "", nil
+	}
+	return id, nil
+}
+
+// BuildInsert translates Insert to an INSERT statement, it returns the generated ids of the documents.
+func BuildInsert(m *Insert, ids *IDGenerator) (string, []string, error) {
+	g := newGenerator(m.DataModel, m.Args)
+	if len(m.Rows) == 0 {
+		return "", nil, NewError(ErrXBadInsertData, "missing row data for insert")
+	}
+	g.buf.WriteString("INSERT INTO ")
+	if err := g.collection(m.Collection); err != nil {
+		return "", nil, err
+	}
+	var generatedIDs []string
+	if g.docModel {
+		if len(m.Projection) > 0 {
+			return "", nil, NewError(ErrXBadProjection, "invalid projection for document operation")
+		}
+		g.buf.WriteString(" (doc) VALUES ")
+		for i, row := range m.Rows {
+			if len(row) != 1 {
+				return "", nil, NewError(ErrXBadInsertData, "wrong number of fields in row %d", i)
+			}
+			if i > 0 {
+				g.buf.WriteString(", ")
+			}
+			g.buf.WriteByte('(')
+			id, err := g.document(row[0], ids)
+			if err != nil {
+				return "", nil, err
+			}
+			if id != "" {
+				generatedIDs = append(generatedIDs, id)
+			}
+			g.buf.WriteByte(')')
+		}
+		if m.Upsert {
+			g.buf.WriteString(" ON DUPLICATE KEY UPDATE doc = VALUES(doc)")
+		}
+		return g.buf.String(), generatedIDs, nil
+	}
+
+	if m.Upsert {
+		return "", nil, NewError(ErrXBadInsertData, "unable to use upsert for table insert")
+	}
+	if len(m.Projection) > 0 {
+		g.buf.WriteString(" (")
+		for i, c := range m.Projection {
+			if c.Name == "" {
+				return "", nil, NewError(ErrXBadProjection, "invalid column name")
+			}
+			if i > 0 {
+				g.buf.WriteString(", ")
+			}
+			sqlexec.MustFormatSQL(&g.buf, "%n", c.Name)
+		}
+		g.buf.WriteByte(')')
+	}
+	g.buf.WriteString(" VALUES ")
+	for i, row := range m.Rows {
+		if len(row) == 0 || (len(m.Projection) > 0 && len(row) != len(m.Projection)) {
+			return "", nil, NewError(ErrXBadInsertData, "wrong number of fields in row %d", i)
+		}
+		if i > 0 {
+			g.buf.WriteString(", ")
+		}
+		g.buf.WriteByte('(')
+		if err := g.list(row, g.expr); err != nil {
+			return "", nil, err
+		}
+		g.buf.WriteByte(')')
+	}
+	return g.buf.String(), nil, nil
+}
+
+var jsonUpdateFuncs = map[uint32]string{
+	UpdateItemSet:     "JSON_SET",
+	UpdateItemReplace: "JSON_REPLACE",
+	UpdateArrayInsert: "JSON_ARRAY_INSERT",
+	UpdateArrayAppend: "JSON_ARRAY_APPEND",
+}
+
+// updateValue returns the new value of the column after the operation.
+func (g *generator) updateValue(column string, op *UpdateOperation) (string, error) {
+	path := op.Source.DocumentPath
+	if g.docModel && len(path) > 0 && path[0].Type == PathMember && path[0].Value == idColumn {
+		return "", NewError(ErrXBadMemberToUpdate, "forbidden update operation on '$._id' member")
+	}
+	return g.sub(func() error {
+		switch op.Operation {
+		case UpdateSet:
+			if g.docModel || len(path) > 0 {
+				return NewError(ErrXBadUpdateData, "invalid update operation SET on document path")
+			}
+			return g.expr(op.Value)
+		case UpdateItemRemove:
+			if len(path) == 0 {
+				return NewError(ErrXBadUpdateData, "invalid document path for ITEM_REMOVE")
+			}
+			g.buf.WriteString("JSON_REMOVE(" + column + ", ")
+			if err := g.path(path); err != nil {
+				return err
+			}
+		case UpdateItemMerge, UpdateMergePatch:
+			if len(path) > 0 {
+				return NewError(ErrXBadUpdateData, "invalid document path for merging")
+			}
+			merged, err := g.sub(func() error {
+				if op.Operation == UpdateItemMerge {
+					g.buf.WriteString("JSON_MERGE_PRESERVE(" + column + ", ")
+				} else {
+					g.buf.WriteString("JSON_MERGE_PATCH(" + column + ", ")
+				}
+				if err := g.jsonValue(op.Value); err != nil {
+					return err
+				}
+				g.buf.WriteByte(')')
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if g.docModel {
+				// The _id member can't be changed by merging.
+				g.buf.WriteString("JSON_SET(" + merged + ", '$._id', JSON_EXTRACT(" + column + ", '$._id'))")
+			} else {
+				g.buf.WriteString(merged)
+			}
+			return nil
+		default:
+			fn, ok := jsonUpdateFuncs[op.Operation]
+			if !ok {
+				return NewError(ErrXBadUpdateData, "invalid update operation %d", op.Operation)
+			}
+			if len(path) == 0 {
+				return NewError(ErrXBadUpdateData, "invalid document path for %s", fn)
+			}
+			g.buf.WriteString(fn + "(" + column + ", ")
+			if err := g.path(path); err != nil {
+				return err
+			}
+			g.buf.WriteString(", ")
+			if err := g.expr(op.Value); err != nil {
+				return err
+			}
+		}
+		g.buf.WriteByte(')')
+		return nil
+	})
+}
+
+// BuildUpdate translates Update to an UPDATE statement. The operations on the same document are nested.
+func BuildUpdate(m *Update) (string, error) {
+	g := newGenerator(m.DataModel, m.Args)
+	if len(m.Operations) == 0 {
+		return "", NewError(ErrXBadUpdateData, "invalid update expression list")
+	}
+	g.buf.WriteString("UPDATE ")
+	if err := g.collection(m.Collection); err != nil {
+		return "", err
+	}
+	g.buf.WriteString(" SET ")
+	var doc string
+	for i, op := range m.Operations {
+		if op.Source == nil {
+			return "", NewError(ErrXBadUpdateData, "missing source of update operation")
+		}
+		if g.docModel {
+			if op.Source.Name != "" {
+				return "", NewError(ErrXBadUpdateData, "invalid column name to update")
+			}
+			if i == 0 {
+				doc = docColumn
+			}
+			var err error
+			if doc, err = g.updateValue(doc, op); err != nil {
+				return "", err
+			}
+			continue
+		}
+		column, err := g.sub(func() error { return g.column(op.Source) })
+		if err != nil {
+			return "", err
+		}
+		value, err := g.updateValue(column, op)
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			g.buf.WriteString(", ")
+		}
+		g.buf.WriteString(column + " = " + value)
+	}
+	if g.docModel {
+		g.buf.WriteString("doc = " + doc)
+	}
+	if err := g.where(m.Criteria); err != nil {
+		return "", err
+	}
+	if err := g.orderBy(m.Order); err != nil {
+		return "", err
+	}
+	if err := g.limit(m.Limit, m.LimitExpr, false); err != nil {
+		return "", err
+	}
+	return g.buf.String(), nil
+}
+
+// BuildDelete translates Delete to a DELETE statement.
+func BuildDelete(m *Delete) (string, error) {
+	g := newGenerator(m.DataModel, m.Args)
+	g.buf.WriteString("DELETE FROM ")
+	if err := g.collection(m.Collection); err != nil {
+		return "", err
+	}
+	if err := g.where(m.Criteria); err != nil {
+		return "", err
+	}
+	if err := g.orderBy(m.Order); err != nil {
+		return "", err
+	}
+	if err := g.limit(m.Limit, m.LimitExpr, false); err != nil {
+		return "", err
+	}
+	return g.buf.String(), nil
+}
+
+// BuildSQL replaces the placeholders "?" in the statement of the sql namespace by the arguments.
+func BuildSQL(stmt string, args []*Any) (string, error) {
+	var b strings.Builder
+	g := &generator{}
+	pos := 0
+	for i := 0; i < len(stmt); i++ {
+		c := stmt[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for ; j < len(stmt) && stmt[j] != c; j++ {
+				if stmt[j] == '\\' && c != '`' {
+					j++
+				}
+			}
+			if j >= len(stmt) {
+				j = len(stmt) - 1
+			}
+			b.WriteString(stmt[i : j+1])
+			i = j
+		case c == '#' || (c == '-' && strings.HasPrefix(stmt[i:], "-- ")):
+			j := strings.IndexByte(stmt[i:], '\n')
+			if j < 0 {
+				j = len(stmt) - i - 1
+			}
+			b.WriteString(stmt[i : i+j+1])
+			i += j
+		case c == '/' && strings.HasPrefix(stmt[i:], "/*"):
+			j := strings.Index(stmt[i+2:], "*/")
+			if j < 0 {
+				j = len(stmt) - i - 4
+			}
+			b.WriteString(stmt[i : i+j+4])
+			i += j + 3
+		case c == '?':
+			if pos >= len(args) {
+				return "", NewError(ErrXCmdNumArguments, "too few arguments")
+			}
+			arg := args[pos]
+			pos++
+			if arg.Type != AnyScalar {
+				return "", NewError(ErrXCmdArgumentType, "invalid argument type of placeholder %d", pos)
+			}
+			g.buf.Reset()
+			if err := g.scalar(arg.Scalar); err != nil {
+				return "", err
+			}
+			b.WriteString(g.buf.String())
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if pos != len(args) {
+		return "", NewError(ErrXCmdNumArguments, "too many arguments")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,272 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func member(names ...string) *Expr {
+	id := &ColumnIdentifier{}
+	for _, name := range names {
+		id.DocumentPath = append(id.DocumentPath, &DocumentPathItem{Type: PathMember, Value: name})
+	}
+	return &Expr{Type: ExprIdent, Identifier: id}
+}
+
+func column(name string) *Expr {
+	return &Expr{Type: ExprIdent, Identifier: &ColumnIdentifier{Name: name}}
+}
+
+func strExpr(s string) *Expr {
+	return &Expr{Type: ExprLiteral, Literal: &Scalar{Type: ScalarString, String: []byte(s)}}
+}
+
+func intExpr(v int64) *Expr {
+	return &Expr{Type: ExprLiteral, Literal: &Scalar{Type: ScalarSInt, SInt: v}}
+}
+
+func op(name string, params ...*Expr) *Expr {
+	return &Expr{Type: ExprOperator, Operator: &Operator{Name: name, Params: params}}
+}
+
+func requireXError(t *testing.T, code uint16, err error) {
+	require.Error(t, err)
+	require.Equal(t, code, err.(*Error).Code, err.Error())
+}
+
+func TestDocumentPath(t *testing.T) {
+	path, err := DocumentPath([]*DocumentPathItem{
+		{Type: PathMember, Value: "a"},
+		{Type: PathArrayIndex, Index: 1},
+		{Type: PathMember, Value: "b c"},
+		{Type: PathMemberAsterisk},
+		{Type: PathArrayIndexAsterisk},
+		{Type: PathDoubleAsterisk},
+		{Type: PathMember, Value: "_d1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `$.a[1]."b c".*[*]**._d1`, path)
+	_, err = DocumentPath([]*DocumentPathItem{{Type: 100}})
+	requireXError(t, ErrXExprBadValue, err)
+}
+
+func TestBuildFind(t *testing.T) {
+	coll := &Collection{Schema: "test", Name: "c"}
+	sql, err := BuildFind(&Find{Collection: coll})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT doc FROM `test`.`c`", sql)
+
+	sql, err = BuildFind(&Find{
+		Collection: &Collection{Name: "c"},
+		Projection: []*Projection{{Source: member("name")}, {Source: op("+", member("age"), intExpr(1)), Alias: "next_age"}},
+		Criteria: op("&&",
+			op(">", member("age"), &Expr{Type: ExprPlaceholder, Position: 0}),
+			op("like", member("name"), strExpr("A%'"))),
+		Args:      []*Scalar{{Type: ScalarUInt, UInt: 18}, {Type: ScalarUInt, UInt: 10}},
+		Order:     []*Order{{Expr: member("age"), Direction: OrderDesc}, {Expr: member("name")}},
+		LimitExpr: &LimitExpr{RowCount: &Expr{Type: ExprPlaceholder, Position: 1}, Offset: intExpr(5)},
+		Locking:   LockExclusive,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT JSON_OBJECT('name', JSON_EXTRACT(doc, '$.name'), 'next_age', (JSON_EXTRACT(doc, '$.age') + 1)) AS doc "+
+		"FROM `c` WHERE ((JSON_EXTRACT(doc, '$.age') > 18) AND (JSON_EXTRACT(doc, '$.name') LIKE 'A%\\'')) "+
+		"ORDER BY JSON_EXTRACT(doc, '$.age') DESC, JSON_EXTRACT(doc, '$.name') LIMIT 5, 10 FOR UPDATE", sql)
+
+	sql, err = BuildFind(&Find{
+		Collection:  coll,
+		DataModel:   DataModelTable,
+		Projection:  []*Projection{{Source: column("a")}, {Source: &Expr{Type: ExprFuncCall, FuncCall: &FunctionCall{Name: &Identifier{Name: "count"}, Params: []*Expr{op("*")}}}, Alias: "cnt"}},
+		Criteria:    op("not_in", column("b"), intExpr(1), strExpr("x")),
+		Grouping:    []*Expr{column("a")},
+		GroupingCri: op(">", column("cnt"), intExpr(1)),
+		Limit:       &Limit{RowCount: 3},
+		Locking:     LockShared,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT `a` AS `a`, count(*) AS `cnt` FROM `test`.`c` WHERE (`b` NOT IN (1, 'x')) "+
+		"GROUP BY `a` HAVING (`cnt` > 1) LIMIT 3 LOCK IN SHARE MODE", sql)
+
+	_, err = BuildFind(&Find{
+		Collection: coll,
+		Criteria: op("||",
+			op("cont_in", strExpr("go"), member("tags")),
+			op("between", op("cast", member("age"), strExpr("signed")), intExpr(1), intExpr(2)),
+			op("not", op("is", member("x"), &Expr{Type: ExprLiteral, Literal: &Scalar{Type: ScalarNull}})),
+		),
+	})
+	requireXError(t, ErrXExprBadNumArgs, err)
+	sql, err = BuildFind(&Find{
+		Collection: coll,
+		Criteria: op("||",
+			op("cont_in", strExpr("go"), member("tags")),
+			op("&&",
+				op("between", op("cast", member("age"), strExpr("signed")), intExpr(1), intExpr(2)),
+				op("not", op("is", member("x"), &Expr{Type: ExprLiteral, Literal: &Scalar{Type: ScalarNull}})))),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT doc FROM `test`.`c` WHERE (JSON_CONTAINS(JSON_EXTRACT(doc, '$.tags'), JSON_QUOTE('go')) OR "+
+		"((CAST(JSON_EXTRACT(doc, '$.age') AS SIGNED) BETWEEN 1 AND 2) AND (NOT (JSON_EXTRACT(doc, '$.x') IS NULL))))", sql)
+
+	// Errors.
+	_, err = BuildFind(&Find{})
+	requireXError(t, ErrXInvalidCollection, err)
+	_, err = BuildFind(&Find{Collection: coll, Criteria: &Expr{Type: ExprPlaceholder, Position: 1}})
+	requireXError(t, ErrXExprMissingArg, err)
+	_, err = BuildFind(&Find{Collection: coll, Criteria: op("foo", intExpr(1))})
+	requireXError(t, ErrXExprBadOperator, err)
+	_, err = BuildFind(&Find{Collection: coll, Projection: []*Projection{{Source: intExpr(1)}}})
+	requireXError(t, ErrXBadProjection, err)
+	_, err = BuildFind(&Find{Collection: coll, Criteria: &Expr{Type: ExprFuncCall, FuncCall: &FunctionCall{Name: &Identifier{Name: "sleep(1);"}}}})
+	requireXError(t, ErrXExprBadValue, err)
+	_, err = BuildFind(&Find{Collection: coll, Criteria: op("cast", intExpr(1), strExpr("int); drop table t;"))})
+	requireXError(t, ErrXExprBadValue, err)
+	_, err = BuildFind(&Find{Collection: coll, DataModel: DataModelTable, Criteria: member("a")})
+	requireXError(t, ErrXExprBadValue, err)
+	_, err = BuildFind(&Find{Collection: coll, Locking: 3})
+	requireXError(t, ErrXBadLocking, err)
+}
+
+func TestBuildInsert(t *testing.T) {
+	ids := NewIDGenerator(1)
+	coll := &Collection{Schema: "test", Name: "c"}
+	sql, generated, err := BuildInsert(&Insert{
+		Collection: coll,
+		Rows: [][]*Expr{
+			{{Type: ExprLiteral, Literal: &Scalar{Type: ScalarOctets, Octets: []byte(`{"_id": "1", "a": 1}`), ContentType: ContentTypeJSON}}},
+			{{Type: ExprObject, Object: &ObjectExpr{Fields: []*ObjectExprField{{Key: "a", Value: &Expr{Type: ExprArray, Array: &ArrayExpr{Values: []*Expr{intExpr(1), strExpr("x")}}}}}}}},
+			{{Type: ExprPlaceholder, Position: 0}},
+			{{Type: ExprFuncCall, FuncCall: &FunctionCall{Name: &Identifier{Name: "json_object"}}}},
+		},
+		Args:   []*Scalar{{Type: ScalarString, String: []byte(`{"b": 2}`)}},
+		Upsert: true,
+	}, ids)
+	require.NoError(t, err)
+	require.Len(t, generated, 2)
+	require.Len(t, generated[0], 32)
+	require.Less(t, generated[0], generated[1])
+	require.Equal(t, "INSERT INTO `test`.`c` (doc) VALUES (CAST('{\\\"_id\\\": \\\"1\\\", \\\"a\\\": 1}' AS JSON)), "+
+		"(JSON_INSERT(JSON_OBJECT('a', JSON_ARRAY(1, 'x')), '$._id', '"+generated[0]+"')), "+
+		"(JSON_INSERT(CAST('{\\\"b\\\": 2}' AS JSON), '$._id', '"+generated[1]+"')), "+
+		"(JSON_INSERT(json_object(), '$._id', '"+generated[0][:16]+"0000000000000003')) "+
+		"ON DUPLICATE KEY UPDATE doc = VALUES(doc)", sql)
+
+	sql, generated, err = BuildInsert(&Insert{
+		Collection: coll,
+		DataModel:  DataModelTable,
+		Projection: []*Column{{Name: "a"}, {Name: "b"}},
+		Rows:       [][]*Expr{{intExpr(1), strExpr("x")}, {intExpr(2), &Expr{Type: ExprLiteral, Literal: &Scalar{Type: ScalarNull}}}},
+	}, ids)
+	require.NoError(t, err)
+	require.Nil(t, generated)
+	require.Equal(t, "INSERT INTO `test`.`c` (`a`, `b`) VALUES (1, 'x'), (2, NULL)", sql)
+
+	// Errors.
+	_, _, err = BuildInsert(&Insert{Collection: coll}, ids)
+	requireXError(t, ErrXBadInsertData, err)
+	_, _, err = BuildInsert(&Insert{Collection: coll, Rows: [][]*Expr{{strExpr("not json")}}}, ids)
+	requireXError(t, ErrXBadInsertData, err)
+	_, _, err = BuildInsert(&Insert{Collection: coll, Rows: [][]*Expr{{strExpr("{}"), strExpr("{}")}}}, ids)
+	requireXError(t, ErrXBadInsertData, err)
+	_, _, err = BuildInsert(&Insert{Collection: coll, DataModel: DataModelTable, Projection: []*Column{{Name: "a"}}, Rows: [][]*Expr{{intExpr(1), intExpr(2)}}}, ids)
+	requireXError(t, ErrXBadInsertData, err)
+	_, _, err = BuildInsert(&Insert{Collection: coll, DataModel: DataModelTable, Rows: [][]*Expr{{intExpr(1)}}, Upsert: true}, ids)
+	requireXError(t, ErrXBadInsertData, err)
+}
+
+func TestBuildUpdateDelete(t *testing.T) {
+	coll := &Collection{Name: "c"}
+	path := func(names ...string) *ColumnIdentifier {
+		return member(names...).Identifier
+	}
+	sql, err := BuildUpdate(&Update{
+		Collection: coll,
+		Criteria:   op("==", member("_id"), strExpr("1")),
+		Operations: []*UpdateOperation{
+			{Source: path("a"), Operation: UpdateItemSet, Value: intExpr(1)},
+			{Source: path("b"), Operation: UpdateItemRemove},
+			{Source: &ColumnIdentifier{}, Operation: UpdateMergePatch, Value: &Expr{Type: ExprObject, Object: &ObjectExpr{Fields: []*ObjectExprField{{Key: "c", Value: strExpr("x")}}}}},
+			{Source: &ColumnIdentifier{DocumentPath: []*DocumentPathItem{{Type: PathMember, Value: "d"}, {Type: PathArrayIndex, Index: 0}}}, Operation: UpdateArrayInsert, Value: intExpr(2)},
+		},
+		Limit: &Limit{RowCount: 1},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `c` SET doc = JSON_ARRAY_INSERT(JSON_SET(JSON_MERGE_PATCH(JSON_REMOVE(JSON_SET(doc, '$.a', 1), '$.b'), "+
+		"JSON_OBJECT('c', 'x')), '$._id', JSON_EXTRACT(JSON_REMOVE(JSON_SET(doc, '$.a', 1), '$.b'), '$._id')), '$.d[0]', 2) "+
+		"WHERE (JSON_EXTRACT(doc, '$._id') = '1') LIMIT 1", sql)
+
+	sql, err = BuildUpdate(&Update{
+		Collection: coll,
+		DataModel:  DataModelTable,
+		Operations: []*UpdateOperation{
+			{Source: &ColumnIdentifier{Name: "a"}, Operation: UpdateSet, Value: op("+", column("a"), intExpr(1))},
+			{Source: &ColumnIdentifier{Name: "j", DocumentPath: path("x").DocumentPath}, Operation: UpdateItemReplace, Value: strExpr("y")},
+		},
+		Order: []*Order{{Expr: column("a")}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE `c` SET `a` = (`a` + 1), `j` = JSON_REPLACE(`j`, '$.x', 'y') ORDER BY `a`", sql)
+
+	_, err = BuildUpdate(&Update{Collection: coll})
+	requireXError(t, ErrXBadUpdateData, err)
+	_, err = BuildUpdate(&Update{Collection: coll, Operations: []*UpdateOperation{{Source: path("_id"), Operation: UpdateItemSet, Value: intExpr(1)}}})
+	requireXError(t, ErrXBadMemberToUpdate, err)
+	_, err = BuildUpdate(&Update{Collection: coll, Operations: []*UpdateOperation{{Source: path(), Operation: UpdateItemSet, Value: intExpr(1)}}})
+	requireXError(t, ErrXBadUpdateData, err)
+	_, err = BuildUpdate(&Update{Collection: coll, Operations: []*UpdateOperation{{Source: path("a"), Operation: UpdateSet, Value: intExpr(1)}}})
+	requireXError(t, ErrXBadUpdateData, err)
+	_, err = BuildUpdate(&Update{Collection: coll, Operations: []*UpdateOperation{{Source: path("a"), Operation: UpdateItemRemove}}, Limit: &Limit{RowCount: 1, Offset: 1}})
+	requireXError(t, ErrXInvalidProtocolData, err)
+
+	sql, err = BuildDelete(&Delete{
+		Collection: coll,
+		Criteria:   op("in", member("a"), intExpr(1), intExpr(2)),
+		Order:      []*Order{{Expr: member("a"), Direction: OrderDesc}},
+		Limit:      &Limit{RowCount: 2},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "DELETE FROM `c` WHERE (JSON_EXTRACT(doc, '$.a') IN (1, 2)) ORDER BY JSON_EXTRACT(doc, '$.a') DESC LIMIT 2", sql)
+	_, err = BuildDelete(&Delete{Collection: coll, LimitExpr: &LimitExpr{RowCount: intExpr(-1)}})
+	requireXError(t, ErrXExprBadTypeValue, err)
+}
+
+func TestBuildSQL(t *testing.T) {
+	args := []*Any{
+		NewScalarAny(&Scalar{Type: ScalarSInt, SInt: -1}),
+		NewScalarAny(&Scalar{Type: ScalarString, String: []byte("a'b")}),
+	}
+	sql, err := BuildSQL("select ?, '?', \"?\", `?` /* ? */, ? -- ?\n# ?", args)
+	require.NoError(t, err)
+	require.Equal(t, "select -1, '?', \"?\", `?` /* ? */, 'a\\'b' -- ?\n# ?", sql)
+	sql, err = BuildSQL("select 'a\\'?'", nil)
+	require.NoError(t, err)
+	require.Equal(t, "select 'a\\'?'", sql)
+
+	_, err = BuildSQL("select ?", nil)
+	requireXError(t, ErrXCmdNumArguments, err)
+	_, err = BuildSQL("select 1", args)
+	requireXError(t, ErrXCmdNumArguments, err)
+	_, err = BuildSQL("select ?", []*Any{{Type: AnyArray, Array: &Array{}}})
+	requireXError(t, ErrXCmdArgumentType, err)
+}
+
+func TestListObjectsSQL(t *testing.T) {
+	require.Equal(t, "CREATE TABLE IF NOT EXISTS `test`.`c` (doc JSON, _id VARBINARY(32) GENERATED ALWAYS AS "+
+		"(JSON_UNQUOTE(JSON_EXTRACT(doc, _utf8mb4'$._id'))) STORED NOT NULL, PRIMARY KEY (_id))", CreateCollectionSQL("test", "c", true))
+	require.Contains(t, ListObjectsSQL("te'st", "c%"), "WHERE T.TABLE_SCHEMA = 'te\\'st' AND T.TABLE_NAME LIKE 'c%' GROUP BY")
+	require.Contains(t, ListObjectsSQL("", ""), "WHERE T.TABLE_SCHEMA = DATABASE() GROUP BY")
+}
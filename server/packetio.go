@@ -37,17 +37,47 @@ package server
 
 import (
 	"bufio"
+	"bytes"
+	"compress/zlib"
 	"io"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const defaultWriterSize = 16 * 1024
 
+const (
+	// compressedHeaderLen is the length of the header of a compressed packet: 3 bytes of the compressed length,
+	// 1 byte of the sequence and 3 bytes of the uncompressed length.
+	compressedHeaderLen = 7
+	// minCompressLength is the minimum length of the payload to compress, shorter payloads are sent as is.
+	// It is the same as MIN_COMPRESS_LENGTH in MySQL.
+	minCompressLength = 50
+	// defaultZlibCompressionLevel is the zlib level, the same as the default level of MySQL.
+	defaultZlibCompressionLevel = 6
+	// defaultZstdCompressionLevel is the zstd level used if the client does not specify one.
+	defaultZstdCompressionLevel = 3
+)
+
+// Compression algorithms of the compressed protocol.
+const (
+	compressionNone = iota
+	compressionZlib
+	compressionZstd
+)
+
+var compressionAlgorithmNames = []string{
+	compressionNone: "",
+	compressionZlib: "zlib",
+	compressionZstd: "zstd",
+}
+
 var (
 	readPacketBytes  = metrics.PacketIOHistogram.WithLabelValues("read")
 	writePacketBytes = metrics.PacketIOHistogram.WithLabelValues("write")
@@ -59,6 +89,23 @@ type packetIO struct {
 	bufWriter   *bufio.Writer
 	sequence    uint8
 	readTimeout time.Duration
+
+	// reader is bufReadConn, or the compressedReader if the compressed protocol is used.
+	reader      io.Reader
+	compression int
+	compressor  *compressor
+	// compressedSequence is the sequence of the compressed packets, it is reset together with sequence.
+	compressedSequence uint8
+	compressedReader   *compressedReader
+	compressStats      compressStats
+}
+
+// compressStats is the statistics of the compressed protocol of a connection.
+type compressStats struct {
+	compressedBytesRead      uint64
+	compressedBytesWritten   uint64
+	uncompressedBytesRead    uint64
+	uncompressedBytesWritten uint64
 }
 
 func newPacketIO(bufReadConn *bufferedReadConn) *packetIO {
@@ -69,13 +116,45 @@ func newPacketIO(bufReadConn *bufferedReadConn) *packetIO {
 
 func (p *packetIO) setBufferedReadConn(bufReadConn *bufferedReadConn) {
 	p.bufReadConn = bufReadConn
+	p.reader = bufReadConn
 	p.bufWriter = bufio.NewWriterSize(bufReadConn, defaultWriterSize)
+	if p.compression != compressionNone {
+		p.compressedReader = &compressedReader{p: p}
+		p.reader = p.compressedReader
+		p.bufWriter = bufio.NewWriterSize(&compressedWriter{p: p}, defaultWriterSize)
+	}
 }
 
 func (p *packetIO) setReadTimeout(timeout time.Duration) {
 	p.readTimeout = timeout
 }
 
+// setCompression switches the connection to the compressed protocol. It must be called after the pending
+// packets are flushed.
+func (p *packetIO) setCompression(algorithm int, level int) error {
+	c, err := newCompressor(algorithm, level)
+	if err != nil {
+		return err
+	}
+	p.compression = algorithm
+	p.compressor = c
+	p.setBufferedReadConn(p.bufReadConn)
+	return nil
+}
+
+// resetSequence resets the sequences at the beginning of a command.
+func (p *packetIO) resetSequence() {
+	p.sequence = 0
+	p.compressedSequence = 0
+}
+
+// close releases the resources of the compressed protocol.
+func (p *packetIO) close() {
+	if p.compressor != nil {
+		p.compressor.close()
+	}
+}
+
 func (p *packetIO) readOnePacket() ([]byte, error) {
 	var header [4]byte
 	if p.readTimeout > 0 {
@@ -83,12 +162,15 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+	if _, err := io.ReadFull(p.reader, header[:]); err != nil {
 		return nil, errors.Trace(err)
 	}
 
 	sequence := header[3]
-	if sequence != p.sequence {
+	if p.compression != compressionNone {
+		// Like MySQL, only the sequence of the compressed packets is checked.
+		p.sequence = sequence
+	} else if sequence != p.sequence {
 		return nil, errInvalidSequence.GenWithStack("invalid sequence %d != %d", sequence, p.sequence)
 	}
 
@@ -102,7 +184,7 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+	if _, err := io.ReadFull(p.reader, data); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return data, nil
@@ -188,3 +270,205 @@ func (p *packetIO) flush() error {
 	}
 	return err
 }
+
+// compressor compresses and decompresses the payloads of the compressed packets.
+type compressor struct {
+	algorithm   int
+	level       int
+	buf         bytes.Buffer
+	zlibWriter  *zlib.Writer
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+
+	readCompressedBytes    prometheus.Counter
+	readUncompressedBytes  prometheus.Counter
+	writeCompressedBytes   prometheus.Counter
+	writeUncompressedBytes prometheus.Counter
+}
+
+func newCompressor(algorithm int, level int) (*compressor, error) {
+	c := &compressor{algorithm: algorithm, level: level}
+	name := compressionAlgorithmNames[algorithm]
+	c.readCompressedBytes = metrics.CompressedPacketIOCounter.WithLabelValues("read", name, "compressed")
+	c.readUncompressedBytes = metrics.CompressedPacketIOCounter.WithLabelValues("read", name, "uncompressed")
+	c.writeCompressedBytes = metrics.CompressedPacketIOCounter.WithLabelValues("write", name, "compressed")
+	c.writeUncompressedBytes = metrics.CompressedPacketIOCounter.WithLabelValues("write", name, "uncompressed")
+	var err error
+	switch algorithm {
+	case compressionZlib:
+		c.zlibWriter, err = zlib.NewWriterLevel(&c.buf, level)
+	case compressionZstd:
+		c.zstdEncoder, err = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)), zstd.WithEncoderConcurrency(1))
+		if err == nil {
+			c.zstdDecoder, err = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(mysql.MaxPayloadLen))
+		}
+	default:
+		err = errors.Errorf("unknown compression algorithm %d", algorithm)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return c, nil
+}
+
+// compress appends the compressed data to dst.
+func (c *compressor) compress(dst, data []byte) ([]byte, error) {
+	if c.algorithm == compressionZstd {
+		return c.zstdEncoder.EncodeAll(data, dst), nil
+	}
+	c.buf.Reset()
+	c.zlibWriter.Reset(&c.buf)
+	if _, err := c.zlibWriter.Write(data); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := c.zlibWriter.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(dst, c.buf.Bytes()...), nil
+}
+
+// decompress decompresses the data whose uncompressed length is length.
+func (c *compressor) decompress(data []byte, length int) ([]byte, error) {
+	var (
+		result []byte
+		err    error
+	)
+	if c.algorithm == compressionZstd {
+		result, err = c.zstdDecoder.DecodeAll(data, make([]byte, 0, length))
+	} else {
+		var r io.ReadCloser
+		if r, err = zlib.NewReader(bytes.NewReader(data)); err == nil {
+			result = make([]byte, length)
+			_, err = io.ReadFull(r, result)
+			terror.Log(r.Close())
+		}
+	}
+	if err != nil {
+		return nil, errors.Trace(mysql.ErrMalformPacket)
+	}
+	if len(result) != length {
+		return nil, errors.Trace(mysql.ErrMalformPacket)
+	}
+	return result, nil
+}
+
+func (c *compressor) close() {
+	if c.zstdDecoder != nil {
+		c.zstdDecoder.Close()
+	}
+}
+
+// compressedReader reads the payloads of the compressed packets from the connection.
+type compressedReader struct {
+	p    *packetIO
+	data []byte
+}
+
+func (r *compressedReader) Read(b []byte) (int, error) {
+	if len(r.data) == 0 {
+		if err := r.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func (r *compressedReader) readCompressedPacket() error {
+	p := r.p
+	var header [compressedHeaderLen]byte
+	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+		return err
+	}
+	sequence := header[3]
+	if sequence != p.compressedSequence {
+		return errInvalidSequence.GenWithStack("invalid compressed sequence %d != %d", sequence, p.compressedSequence)
+	}
+	p.compressedSequence++
+
+	compressedLength := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+	data := make([]byte, compressedLength)
+	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+		return err
+	}
+	// The payload is not compressed if the uncompressed length is 0.
+	if uncompressedLength > 0 {
+		var err error
+		if data, err = p.compressor.decompress(data, uncompressedLength); err != nil {
+			return err
+		}
+	}
+	p.compressor.readCompressedBytes.Add(float64(compressedHeaderLen + compressedLength))
+	p.compressor.readUncompressedBytes.Add(float64(len(data)))
+	p.compressStats.compressedBytesRead += uint64(compressedHeaderLen + compressedLength)
+	p.compressStats.uncompressedBytesRead += uint64(len(data))
+	r.data = data
+	return nil
+}
+
+// compressedWriter writes the data to the connection as compressed packets.
+type compressedWriter struct {
+	p   *packetIO
+	buf []byte
+}
+
+func (w *compressedWriter) Write(data []byte) (int, error) {
+	n := 0
+	for len(data) > 0 {
+		length := len(data)
+		if length > mysql.MaxPayloadLen {
+			length = mysql.MaxPayloadLen
+		}
+		if err := w.writeCompressedPacket(data[:length]); err != nil {
+			return n, err
+		}
+		n += length
+		data = data[length:]
+	}
+	return n, nil
+}
+
+func (w *compressedWriter) writeCompressedPacket(data []byte) error {
+	p := w.p
+	buf := append(w.buf[:0], make([]byte, compressedHeaderLen)...)
+	uncompressedLength := 0
+	if len(data) >= minCompressLength {
+		var err error
+		if buf, err = p.compressor.compress(buf, data); err != nil {
+			return err
+		}
+		// Send the data as is if compression does not make it shorter.
+		if len(buf)-compressedHeaderLen < len(data) {
+			uncompressedLength = len(data)
+		} else {
+			buf = buf[:compressedHeaderLen]
+		}
+	}
+	if uncompressedLength == 0 {
+		buf = append(buf, data...)
+	}
+	compressedLength := len(buf) - compressedHeaderLen
+	buf[0] = byte(compressedLength)
+	buf[1] = byte(compressedLength >> 8)
+	buf[2] = byte(compressedLength >> 16)
+	buf[3] = p.compressedSequence
+	buf[4] = byte(uncompressedLength)
+	buf[5] = byte(uncompressedLength >> 8)
+	buf[6] = byte(uncompressedLength >> 16)
+	// Do not keep the buffer of a large packet.
+	if cap(buf) <= 2*defaultWriterSize {
+		w.buf = buf
+	}
+	if _, err := p.bufReadConn.Write(buf); err != nil {
+		return err
+	}
+	p.compressedSequence++
+
+	p.compressor.writeCompressedBytes.Add(float64(len(buf)))
+	p.compressor.writeUncompressedBytes.Add(float64(len(data)))
+	p.compressStats.compressedBytesWritten += uint64(len(buf))
+	p.compressStats.uncompressedBytesWritten += uint64(len(data))
+	return nil
+}
@@ -86,6 +86,74 @@ func TestPacketIORead(t *testing.T) {
 	require.Equal(t, byte(0x0a), bytes[mysql.MaxPayloadLen])
 }
 
+func TestPacketIOCompression(t *testing.T) {
+	for _, algorithm := range []int{compressionZlib, compressionZstd} {
+		var conn loopbackConn
+		writer := newPacketIO(newBufferedReadConn(&conn))
+		require.NoError(t, writer.setCompression(algorithm, 3))
+		reader := newPacketIO(newBufferedReadConn(&conn))
+		require.NoError(t, reader.setCompression(algorithm, 3))
+		defer writer.close()
+		defer reader.close()
+
+		small := []byte{0x01, 0x02, 0x03}
+		large := bytes.Repeat([]byte("compressed"), mysql.MaxPayloadLen/5)
+		for _, payload := range [][]byte{small, large} {
+			data := append(make([]byte, 4), payload...)
+			require.NoError(t, writer.writePacket(data))
+		}
+		require.NoError(t, writer.flush())
+		// The large payload is split into several packets.
+		require.Greater(t, writer.sequence, uint8(2))
+		require.Equal(t, writer.compressStats.compressedBytesWritten, uint64(conn.b.Len()))
+		require.Less(t, writer.compressStats.compressedBytesWritten, writer.compressStats.uncompressedBytesWritten)
+
+		for _, payload := range [][]byte{small, large} {
+			data, err := reader.readPacket()
+			require.NoError(t, err)
+			require.Equal(t, payload, data)
+		}
+		require.Equal(t, writer.sequence, reader.sequence)
+		require.Equal(t, writer.compressedSequence, reader.compressedSequence)
+		require.Equal(t, writer.compressStats.compressedBytesWritten, reader.compressStats.compressedBytesRead)
+		require.Equal(t, writer.compressStats.uncompressedBytesWritten, reader.compressStats.uncompressedBytesRead)
+
+		// The sequence of the compressed packets is checked.
+		writer.resetSequence()
+		require.NoError(t, writer.writePacket([]byte{0x00, 0x00, 0x00, 0x00, 0x01}))
+		require.NoError(t, writer.flush())
+		_, err := reader.readPacket()
+		require.True(t, errInvalidSequence.Equal(err))
+	}
+}
+
+func TestCompressedPacketHeader(t *testing.T) {
+	var conn loopbackConn
+	pkt := newPacketIO(newBufferedReadConn(&conn))
+	require.NoError(t, pkt.setCompression(compressionZlib, defaultZlibCompressionLevel))
+	// Short payloads are not compressed.
+	require.NoError(t, pkt.writePacket([]byte{0x00, 0x00, 0x00, 0x00, 0x01}))
+	require.NoError(t, pkt.flush())
+	require.Equal(t, []byte{0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01}, conn.b.Bytes())
+
+	conn.b.Reset()
+	pkt.resetSequence()
+	require.NoError(t, pkt.writePacket(make([]byte, 4+100)))
+	require.NoError(t, pkt.flush())
+	header := conn.b.Bytes()[:compressedHeaderLen]
+	require.Less(t, int(header[0]), 104)
+	require.Equal(t, []byte{0x00, 0x00, 0x00, 104, 0x00, 0x00}, header[1:])
+}
+
+// loopbackConn reads the data written to it.
+type loopbackConn struct {
+	bytesConn
+}
+
+func (c *loopbackConn) Write(b []byte) (n int, err error) {
+	return c.b.Write(b)
+}
+
 type bytesConn struct {
 	b bytes.Buffer
 }
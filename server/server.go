@@ -41,29 +41,35 @@ import (
 	_ "net/http/pprof" // #nosec G108
 	"os"
 	"os/user"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 	"unsafe"
 
-	"github.com/blacktear23/go-proxyprotocol"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/plugin"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/server/mysqlx"
 	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/audit"
 	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/fastrand"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/proxyprotocol"
 	"github.com/pingcap/tidb/util/sys/linux"
 	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/pingcap/tidb/util/traffic"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 )
@@ -97,10 +103,14 @@ var (
 	errAccessDenied            = dbterror.ClassServer.NewStd(errno.ErrAccessDenied)
 	errAccessDeniedNoPassword  = dbterror.ClassServer.NewStd(errno.ErrAccessDeniedNoPassword)
 	errConCount                = dbterror.ClassServer.NewStd(errno.ErrConCount)
+	errTooManyUserConnections  = dbterror.ClassServer.NewStd(errno.ErrTooManyUserConnections)
 	errSecureTransportRequired = dbterror.ClassServer.NewStd(errno.ErrSecureTransportRequired)
 	errMultiStatementDisabled  = dbterror.ClassServer.NewStd(errno.ErrMultiStatementDisabled)
 	errNewAbortingConnection   = dbterror.ClassServer.NewStd(errno.ErrNewAbortingConnection)
 	errNotSupportedAuthMode    = dbterror.ClassServer.NewStd(errno.ErrNotSupportedAuthMode)
+	errAdmissionRejected       = dbterror.ClassServer.NewStd(errno.ErrAdmissionRejected)
+	errServerShutdown          = dbterror.ClassServer.NewStd(errno.ErrServerShutdown)
+	errMustChangePasswordLogin = dbterror.ClassServer.NewStd(errno.ErrMustChangePasswordLogin)
 )
 
 // DefaultCapability is the capability of the server when it is created using the default configuration.
@@ -109,27 +119,54 @@ const defaultCapability = mysql.ClientLongPassword | mysql.ClientLongFlag |
 	mysql.ClientConnectWithDB | mysql.ClientProtocol41 |
 	mysql.ClientTransactions | mysql.ClientSecureConnection | mysql.ClientFoundRows |
 	mysql.ClientMultiStatements | mysql.ClientMultiResults | mysql.ClientLocalFiles |
-	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive
+	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive |
+	mysql.ClientPluginAuthLenencClientData | mysql.ClientCompress | mysql.ClientZstdCompressionAlgorithm |
+	mysql.ClientCanHandleExpiredPasswords
 
 // Server is the MySQL protocol server
 type Server struct {
-	cfg               *config.Config
-	tlsConfig         unsafe.Pointer // *tls.Config
-	driver            IDriver
-	listener          net.Listener
-	socket            net.Listener
-	rwlock            sync.RWMutex
-	concurrentLimiter *TokenLimiter
-	clients           map[uint64]*clientConn
-	capability        uint32
-	dom               *domain.Domain
-	globalConnID      util.GlobalConnID
+	cfg       *config.Config
+	tlsConfig unsafe.Pointer // *tls.Config
+	driver    IDriver
+	listener  net.Listener
+	socket    net.Listener
+	// xListener accepts the connections of the MySQL X Protocol, it's nil if the X Protocol is disabled.
+	xListener net.Listener
+	// xIDs generates the IDs of the documents inserted by the X Protocol.
+	xIDs *mysqlx.IDGenerator
+	// proxyProtocolListener is not nil if PROXY protocol is enabled.
+	proxyProtocolListener *proxyprotocol.Listener
+	rwlock                sync.RWMutex
+	concurrentLimiter     *TokenLimiter
+	clients               map[uint64]*clientConn
+	capability            uint32
+	dom                   *domain.Domain
+	globalConnID          util.GlobalConnID
 
 	statusAddr     string
 	statusListener net.Listener
 	statusServer   *http.Server
 	grpcServer     *grpc.Server
 	inShutdownMode bool
+	// stopTLSWatcher stops checking whether the TLS certificate files are changed.
+	stopTLSWatcher chan struct{}
+	// drain is the state of draining the server, the server is draining if cancel is not nil.
+	drain struct {
+		sync.Mutex
+		start    time.Time
+		deadline time.Time
+		cancel   context.CancelFunc
+	}
+	// capture is the state of capturing the traffic, the server is capturing if capturer is not nil.
+	capture struct {
+		sync.RWMutex
+		// capturing is checked by the connections before taking the lock.
+		capturing int32
+		capturer  *traffic.Capturer
+		// last is the current or the last capturer, whose status is reported.
+		last  *traffic.Capturer
+		timer *time.Timer
+	}
 }
 
 // ConnectionCount gets current connection count.
@@ -140,13 +177,92 @@ func (s *Server) ConnectionCount() int {
 	return cnt
 }
 
-func (s *Server) getToken() *Token {
+// SetProxyProtocolNetworks changes the networks which are allowed to send the PROXY protocol header.
+// It takes effect on the new connections, and PROXY protocol must be enabled when the server starts.
+func (s *Server) SetProxyProtocolNetworks(networks string) error {
+	if s.proxyProtocolListener == nil {
+		return errors.New("PROXY protocol is not enabled")
+	}
+	if err := s.proxyProtocolListener.SetNetworks(networks); err != nil {
+		return errors.Trace(err)
+	}
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.ProxyProtocol.Networks = networks
+	})
+	logutil.BgLogger().Info("PROXY protocol networks changed", zap.String("networks", networks))
+	return nil
+}
+
+// admissionMemoryCheckInterval is the interval to recheck the memory usage of the server
+// when a statement is queued because the usage exceeds server-memory-quota.
+var admissionMemoryCheckInterval = 10 * time.Millisecond
+
+// getToken obtains a token for a command. If admit is true, the command is a statement, and it is
+// also held back while the memory tracked by the executors exceeds server-memory-quota. When the
+// resources are not available, the statement fails or waits at most token-limit-queue-timeout
+// according to token-limit-action.
+func (s *Server) getToken(ctx context.Context, admit bool) (*Token, error) {
 	start := time.Now()
-	tok := s.concurrentLimiter.Get()
+	cfg := config.GetGlobalConfig()
+	reject := admit && cfg.TokenLimitAction == config.TokenLimitActionReject
+	if admit && !reject && cfg.TokenLimitQueueTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(cfg.TokenLimitQueueTimeout)*time.Second)
+		defer cancel()
+	}
+	if admit {
+		if err := s.waitForMemory(ctx, cfg.Performance.ServerMemoryQuota, reject); err != nil {
+			return nil, err
+		}
+	}
+	var tok *Token
+	switch {
+	case reject:
+		var ok bool
+		if tok, ok = s.concurrentLimiter.TryGet(); !ok {
+			metrics.AdmissionRejectedCounter.WithLabelValues("token").Inc()
+			return nil, errAdmissionRejected.FastGenByArgs(fmt.Sprintf("the number of executing statements reaches token-limit(%d)", s.cfg.TokenLimit))
+		}
+	case admit:
+		var ok bool
+		if tok, ok = s.concurrentLimiter.GetWithContext(ctx); !ok {
+			if ctx.Err() != context.DeadlineExceeded {
+				return nil, ctx.Err()
+			}
+			metrics.AdmissionRejectedCounter.WithLabelValues("token").Inc()
+			return nil, errAdmissionRejected.FastGenByArgs(fmt.Sprintf("the number of executing statements reaches token-limit(%d) for more than token-limit-queue-timeout(%ds)", s.cfg.TokenLimit, cfg.TokenLimitQueueTimeout))
+		}
+	default:
+		tok = s.concurrentLimiter.Get()
+	}
 	metrics.TokenGauge.Inc()
 	// Note that data smaller than one microsecond is ignored, because that case can be viewed as non-block.
 	metrics.GetTokenDurationHistogram.Observe(float64(time.Since(start).Nanoseconds() / 1e3))
-	return tok
+	return tok, nil
+}
+
+// waitForMemory blocks until the memory tracked by the executors is below quota or ctx is done, or returns
+// an error immediately if reject is true. A zero quota means there is no limit.
+func (s *Server) waitForMemory(ctx context.Context, quota uint64, reject bool) error {
+	if quota == 0 {
+		return nil
+	}
+	for executor.GlobalMemoryUsageTracker.BytesConsumed() >= int64(quota) {
+		if reject {
+			metrics.AdmissionRejectedCounter.WithLabelValues("memory").Inc()
+			return errAdmissionRejected.FastGenByArgs(fmt.Sprintf("the memory used by executors exceeds server-memory-quota(%d)", quota))
+		}
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != context.DeadlineExceeded {
+				return ctx.Err()
+			}
+			metrics.AdmissionRejectedCounter.WithLabelValues("memory").Inc()
+			return errAdmissionRejected.FastGenByArgs(fmt.Sprintf("the memory used by executors exceeds server-memory-quota(%d) for more than token-limit-queue-timeout", quota))
+		case <-time.After(admissionMemoryCheckInterval):
+		}
+	}
+	return nil
 }
 
 func (s *Server) releaseToken(token *Token) {
@@ -233,6 +349,10 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 	if s.tlsConfig != nil {
 		s.capability |= mysql.ClientSSL
 	}
+	if tlsConfig != nil && !autoReload && s.cfg.Security.TLSReloadInterval > 0 {
+		s.stopTLSWatcher = make(chan struct{})
+		go s.watchTLSFiles(time.Duration(s.cfg.Security.TLSReloadInterval)*time.Second, tlsFilesStat(), s.stopTLSWatcher)
+	}
 
 	if s.cfg.Host != "" && (s.cfg.Port != 0 || runInGoTest) {
 		addr := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
@@ -260,6 +380,18 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 		logutil.BgLogger().Info("server is running MySQL protocol", zap.String("socket", s.cfg.Socket))
 	}
 
+	if s.cfg.XProtocol.Enable && s.cfg.Host != "" {
+		addr := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.XProtocol.Port)
+		if s.xListener, err = net.Listen("tcp", addr); err != nil {
+			return nil, errors.Trace(err)
+		}
+		s.xIDs = mysqlx.NewIDGenerator(uint16(fastrand.Uint32()))
+		logutil.BgLogger().Info("server is running MySQL X Protocol", zap.String("addr", addr))
+		if runInGoTest && s.cfg.XProtocol.Port == 0 {
+			s.cfg.XProtocol.Port = uint(s.xListener.Addr().(*net.TCPAddr).Port)
+		}
+	}
+
 	if s.socket == nil && s.listener == nil {
 		err = errors.New("Server not configured to listen on either -socket or -host and -port")
 		return nil, errors.Trace(err)
@@ -271,11 +403,12 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 			proxyTarget = s.socket
 		}
 		ppListener, err := proxyprotocol.NewListener(proxyTarget, s.cfg.ProxyProtocol.Networks,
-			int(s.cfg.ProxyProtocol.HeaderTimeout))
+			time.Duration(s.cfg.ProxyProtocol.HeaderTimeout)*time.Second)
 		if err != nil {
 			logutil.BgLogger().Error("ProxyProtocol networks parameter invalid")
 			return nil, errors.Trace(err)
 		}
+		s.proxyProtocolListener = ppListener
 		if s.listener != nil {
 			s.listener = ppListener
 			logutil.BgLogger().Info("server is running MySQL protocol (through PROXY protocol)", zap.String("host", s.cfg.Host))
@@ -365,11 +498,13 @@ func (s *Server) Run() error {
 	errChan := make(chan error)
 	go s.startNetworkListener(s.listener, false, errChan)
 	go s.startNetworkListener(s.socket, true, errChan)
-	err := <-errChan
-	if err != nil {
-		return err
+	go s.startXListener(errChan)
+	for i := 0; i < 3; i++ {
+		if err := <-errChan; err != nil {
+			return err
+		}
 	}
-	return <-errChan
+	return nil
 }
 
 func (s *Server) startNetworkListener(listener net.Listener, isUnixSocket bool, errChan chan error) {
@@ -403,8 +538,16 @@ func (s *Server) startNetworkListener(listener net.Listener, isUnixSocket bool,
 		}
 
 		clientConn := s.newConn(conn)
-		if isUnixSocket {
+		ppConn, isProxied := conn.(*proxyprotocol.Conn)
+		if isProxied {
+			clientConn.proxyAddr = ppConn.ProxyAddr().String()
+		}
+		// The client behind the proxy is not a local client even if the proxy connects by the UNIX socket.
+		if isUnixSocket && !(isProxied && ppConn.RemoteAddr().Network() == "tcp") {
 			uc, ok := conn.(*net.UnixConn)
+			if isProxied {
+				uc, ok = ppConn.Conn.(*net.UnixConn)
+			}
 			if !ok {
 				logutil.BgLogger().Error("Expected UNIX socket, but got something else")
 				return
@@ -482,6 +625,11 @@ func (s *Server) Close() {
 		terror.Log(errors.Trace(err))
 		s.socket = nil
 	}
+	if s.xListener != nil {
+		err := s.xListener.Close()
+		terror.Log(errors.Trace(err))
+		s.xListener = nil
+	}
 	if s.statusServer != nil {
 		err := s.statusServer.Close()
 		terror.Log(errors.Trace(err))
@@ -491,6 +639,17 @@ func (s *Server) Close() {
 		s.grpcServer.Stop()
 		s.grpcServer = nil
 	}
+	if s.stopTLSWatcher != nil {
+		close(s.stopTLSWatcher)
+		s.stopTLSWatcher = nil
+	}
+	s.drain.Lock()
+	if s.drain.cancel != nil {
+		// Stop the draining goroutine but keep reporting the draining state.
+		s.drain.cancel()
+	}
+	s.drain.Unlock()
+	s.StopCapture()
 	metrics.ServerEventCounter.WithLabelValues(metrics.EventClose).Inc()
 }
 
@@ -498,6 +657,10 @@ func (s *Server) Close() {
 func (s *Server) onConn(conn *clientConn) {
 	ctx := logutil.WithConnID(context.Background(), conn.connectionID)
 	if err := conn.handshake(ctx); err != nil {
+		// The probes of the keep alive services have no user, and they're not recorded.
+		if conn.user != "" {
+			conn.logAuditConnection(audit.EventReject, err)
+		}
 		if plugin.IsEnable(plugin.Audit) && conn.ctx != nil {
 			conn.ctx.GetSessionVars().ConnectionInfo = conn.connectInfo()
 			err = plugin.ForeachPlugin(plugin.Audit, func(p *plugin.Plugin) error {
@@ -518,7 +681,7 @@ func (s *Server) onConn(conn *clientConn) {
 		return
 	}
 
-	logutil.Logger(ctx).Debug("new connection", zap.String("remoteAddr", conn.bufReadConn.RemoteAddr().String()))
+	logutil.Logger(ctx).Debug("new connection", zap.String("remoteAddr", conn.bufReadConn.RemoteAddr().String()), zap.String("proxyAddr", conn.proxyAddr))
 
 	defer func() {
 		logutil.Logger(ctx).Debug("connection closed")
@@ -544,8 +707,10 @@ func (s *Server) onConn(conn *clientConn) {
 		return
 	}
 
+	conn.logAuditConnection(audit.EventConnect, nil)
 	connectedTime := time.Now()
 	conn.Run(ctx)
+	conn.logAuditConnection(audit.EventDisconnect, nil)
 
 	err = plugin.ForeachPlugin(plugin.Audit, func(p *plugin.Plugin) error {
 		// Audit plugin may be disabled before a conn is created, leading no connectionInfo in sessionVars.
@@ -580,6 +745,7 @@ func (cc *clientConn) connectInfo() *variable.ConnectionInfo {
 		Host:              cc.peerHost,
 		ClientIP:          cc.peerHost,
 		ClientPort:        cc.peerPort,
+		ProxyAddr:         cc.proxyAddr,
 		ServerID:          1,
 		ServerPort:        int(cc.server.cfg.Port),
 		User:              cc.user,
@@ -593,6 +759,27 @@ func (cc *clientConn) connectInfo() *variable.ConnectionInfo {
 	return connInfo
 }
 
+// logAuditConnection records the connection event into the audit log if it's enabled.
+func (cc *clientConn) logAuditConnection(event string, err error) {
+	logger := audit.GetGlobalLogger()
+	if logger == nil {
+		return
+	}
+	e := &audit.Event{
+		ConnID: cc.connectionID,
+		Class:  audit.ClassConnection,
+		Event:  event,
+		User:   cc.user,
+		Host:   cc.peerHost,
+		DB:     cc.dbname,
+	}
+	if err != nil {
+		e.ErrCode = toSQLError(err).Code
+		e.Err = err.Error()
+	}
+	logger.Log(e)
+}
+
 func (s *Server) checkConnectionCount() error {
 	// When the value of MaxServerConnections is 0, the number of connections is unlimited.
 	if int(s.cfg.MaxServerConnections) == 0 {
@@ -611,6 +798,44 @@ func (s *Server) checkConnectionCount() error {
 	return nil
 }
 
+// checkUserConnectionCount checks whether the account of the connection has reached its MAX_USER_CONNECTIONS,
+// the limit of the account is used if it's set, otherwise the global max_user_connections is used.
+// Only the connections on this TiDB instance are counted, so the limit is applied per instance.
+func (s *Server) checkUserConnectionCount(cc *clientConn) error {
+	user := cc.ctx.GetSessionVars().User
+	if user == nil {
+		return nil
+	}
+	limit := int64(variable.MaxUserConnectionsValue.Load())
+	if pm := privilege.GetPrivilegeManager(cc.ctx.Session); pm != nil {
+		if userLimit := pm.GetUserMaxConnections(user.AuthUsername, user.AuthHostname); userLimit > 0 {
+			limit = userLimit
+		}
+	}
+	if limit == 0 {
+		return nil
+	}
+
+	var conns int64
+	s.rwlock.RLock()
+	for _, client := range s.clients {
+		if client == cc || atomic.LoadInt32(&client.status) == connStatusWaitShutdown {
+			continue
+		}
+		if u := client.ctx.GetSessionVars().User; u != nil && u.AuthUsername == user.AuthUsername && u.AuthHostname == user.AuthHostname {
+			conns++
+		}
+	}
+	s.rwlock.RUnlock()
+
+	if conns >= limit {
+		logutil.BgLogger().Warn("too many user connections", zap.String("user", user.AuthUsername),
+			zap.String("host", user.AuthHostname), zap.Int64("max user connections", limit))
+		return errTooManyUserConnections.GenWithStackByArgs(user.AuthUsername)
+	}
+	return nil
+}
+
 // ShowProcessList implements the SessionManager interface.
 func (s *Server) ShowProcessList() map[uint64]*util.ProcessInfo {
 	s.rwlock.RLock()
@@ -683,6 +908,84 @@ func (s *Server) getTLSConfig() *tls.Config {
 	return (*tls.Config)(atomic.LoadPointer(&s.tlsConfig))
 }
 
+// ReloadTLS reloads the TLS certificates with the current minimum TLS version and cipher suites.
+// The current TLS config is kept if it fails. It takes effect on the new connections.
+func (s *Server) ReloadTLS() error {
+	cfg := config.GetGlobalConfig()
+	tlsConfig, _, err := util.LoadTLSCertificates(
+		variable.GetSysVar("ssl_ca").Value,
+		variable.GetSysVar("ssl_key").Value,
+		variable.GetSysVar("ssl_cert").Value,
+		cfg.Security.AutoTLS, cfg.Security.RSAKeySize)
+	if err != nil {
+		return err
+	}
+	if tlsConfig == nil {
+		return errors.New("TLS certificates are not configured")
+	}
+	s.UpdateTLSConfig(tlsConfig)
+	logutil.BgLogger().Info("TLS certificates reloaded",
+		zap.String("tls-version", cfg.Security.MinTLSVersion), zap.Strings("tls-cipher-suites", cfg.Security.TLSCipherSuites))
+	return nil
+}
+
+// SetTLSPolicy changes the minimum TLS version and the cipher suites, and reloads the TLS certificates to apply them.
+func (s *Server) SetTLSPolicy(minVersion string, cipherSuites []string) error {
+	if _, err := util.ParseTLSVersion(minVersion); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := util.ParseTLSCipherSuites(cipherSuites); err != nil {
+		return errors.Trace(err)
+	}
+	oldCfg := config.GetGlobalConfig()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Security.MinTLSVersion = minVersion
+		conf.Security.TLSCipherSuites = cipherSuites
+	})
+	if err := s.ReloadTLS(); err != nil {
+		config.StoreGlobalConfig(oldCfg)
+		return err
+	}
+	return nil
+}
+
+// watchTLSFiles reloads the TLS certificates when the files are changed.
+func (s *Server) watchTLSFiles(interval time.Duration, lastStat string, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		stat := tlsFilesStat()
+		if stat == lastStat {
+			continue
+		}
+		// The files may be partially written, they are reloaded again when they are changed next time.
+		lastStat = stat
+		if err := s.ReloadTLS(); err != nil {
+			logutil.BgLogger().Warn("reload TLS certificates after the files changed failed", zap.Error(err))
+		}
+	}
+}
+
+// tlsFilesStat returns the modification time and the size of the TLS certificate files.
+func tlsFilesStat() string {
+	var sb strings.Builder
+	for _, name := range []string{"ssl_ca", "ssl_key", "ssl_cert"} {
+		path := variable.GetSysVar(name).Value
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&sb, "%s:%d:%d;", path, info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	return sb.String()
+}
+
 func killConn(conn *clientConn) {
 	sessVars := conn.ctx.GetSessionVars()
 	atomic.StoreUint32(&sessVars.Killed, 1)
@@ -87,6 +87,17 @@ func (cli *testServerClient) formStatus(path string, data url.Values) (*http.Res
 	return http.PostForm(cli.statusURL(path), data)
 }
 
+// formAPIV2 posts a form request to the v2 status API as root.
+func (cli *testServerClient) formAPIV2(path string, data url.Values) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, cli.statusURL(apiV2Prefix+path), strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("root", "")
+	return http.DefaultClient.Do(req)
+}
+
 // getDSN generates a DSN string for MySQL connection.
 func (cli *testServerClient) getDSN(overriders ...configOverrider) string {
 	config := mysql.NewConfig()
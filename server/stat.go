@@ -25,15 +25,29 @@ import (
 )
 
 var (
-	serverNotAfter  = "Ssl_server_not_after"
-	serverNotBefore = "Ssl_server_not_before"
-	upTime          = "Uptime"
+	serverNotAfter            = "Ssl_server_not_after"
+	serverNotBefore           = "Ssl_server_not_before"
+	upTime                    = "Uptime"
+	compression               = "Compression"
+	compressionAlgorithm      = "Compression_algorithm"
+	compressionLevel          = "Compression_level"
+	compressedBytesReceived   = "Compressed_bytes_received"
+	compressedBytesSent       = "Compressed_bytes_sent"
+	uncompressedBytesReceived = "Uncompressed_bytes_received"
+	uncompressedBytesSent     = "Uncompressed_bytes_sent"
 )
 
 var defaultStatus = map[string]*variable.StatusVal{
-	serverNotAfter:  {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
-	serverNotBefore: {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
-	upTime:          {Scope: variable.ScopeGlobal, Value: 0},
+	serverNotAfter:            {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
+	serverNotBefore:           {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
+	upTime:                    {Scope: variable.ScopeGlobal, Value: 0},
+	compression:               {Scope: variable.ScopeSession, Value: "OFF"},
+	compressionAlgorithm:      {Scope: variable.ScopeSession, Value: ""},
+	compressionLevel:          {Scope: variable.ScopeSession, Value: 0},
+	compressedBytesReceived:   {Scope: variable.ScopeSession, Value: uint64(0)},
+	compressedBytesSent:       {Scope: variable.ScopeSession, Value: uint64(0)},
+	uncompressedBytesReceived: {Scope: variable.ScopeSession, Value: uint64(0)},
+	uncompressedBytesSent:     {Scope: variable.ScopeSession, Value: uint64(0)},
 }
 
 // GetScope gets the status variables scope.
@@ -62,6 +76,22 @@ func (s *Server) Stats(vars *variable.SessionVars) (map[string]interface{}, erro
 		}
 	}
 
+	if vars != nil {
+		s.rwlock.RLock()
+		cc := s.clients[vars.ConnectionID]
+		s.rwlock.RUnlock()
+		if cc != nil && cc.pkt != nil && cc.pkt.compression != compressionNone {
+			stats := cc.pkt.compressStats
+			m[compression] = "ON"
+			m[compressionAlgorithm] = compressionAlgorithmNames[cc.pkt.compression]
+			m[compressionLevel] = cc.pkt.compressor.level
+			m[compressedBytesReceived] = stats.compressedBytesRead
+			m[compressedBytesSent] = stats.compressedBytesWritten
+			m[uncompressedBytesReceived] = stats.uncompressedBytesRead
+			m[uncompressedBytesSent] = stats.uncompressedBytesWritten
+		}
+	}
+
 	var err error
 	info := serverInfo{}
 	info.ServerInfo, err = infosync.GetServerInfo()
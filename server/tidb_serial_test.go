@@ -18,6 +18,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"crypto/x509"
 	"os"
 	"sync/atomic"
@@ -495,3 +496,68 @@ func TestReloadTLS(t *testing.T) {
 	require.Truef(t, util.IsTLSExpiredError(err), "real error is %+v", err)
 	server.Close()
 }
+
+func TestTLSAutoReloadAndPolicy(t *testing.T) {
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+	defer config.RestoreFunc()()
+
+	caCert, caKey, err := generateCert(0, "TiDB CA", nil, nil, "/tmp/ca-key-watch.pem", "/tmp/ca-cert-watch.pem")
+	require.NoError(t, err)
+	_, _, err = generateCert(1, "tidb-server", caCert, caKey, "/tmp/server-key-watch.pem", "/tmp/server-cert-watch.pem")
+	require.NoError(t, err)
+	defer func() {
+		os.Remove("/tmp/ca-key-watch.pem")
+		os.Remove("/tmp/ca-cert-watch.pem")
+		os.Remove("/tmp/server-key-watch.pem")
+		os.Remove("/tmp/server-cert-watch.pem")
+	}()
+
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Status.ReportStatus = false
+	cfg.Security = config.Security{
+		SSLCA:             "/tmp/ca-cert-watch.pem",
+		SSLCert:           "/tmp/server-cert-watch.pem",
+		SSLKey:            "/tmp/server-key-watch.pem",
+		TLSReloadInterval: 1,
+	}
+	server, err := NewServer(cfg, ts.tidbdrv)
+	require.NoError(t, err)
+	defer server.Close()
+
+	// The certificates are reloaded after the files are changed.
+	notAfter := func() time.Time {
+		cert, err := x509.ParseCertificate(server.getTLSConfig().Certificates[0].Certificate[0])
+		require.NoError(t, err)
+		return cert.NotAfter
+	}
+	oldNotAfter := notAfter()
+	_, _, err = generateCert(1, "tidb-server", caCert, caKey, "/tmp/server-key-watch2.pem", "/tmp/server-cert-watch2.pem", func(c *x509.Certificate) {
+		c.NotBefore = time.Now().Add(-24 * time.Hour).UTC()
+		c.NotAfter = time.Now().Add(1 * time.Hour).UTC()
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.Rename("/tmp/server-key-watch2.pem", "/tmp/server-key-watch.pem"))
+	require.NoError(t, os.Rename("/tmp/server-cert-watch2.pem", "/tmp/server-cert-watch.pem"))
+	require.Eventually(t, func() bool {
+		return !notAfter().Equal(oldNotAfter)
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// The minimum TLS version and the cipher suites can be changed.
+	require.NoError(t, server.SetTLSPolicy("TLSv1.2", []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "ECDHE-RSA-AES256-GCM-SHA384"}))
+	tlsConfig := server.getTLSConfig()
+	require.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	require.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, tlsConfig.CipherSuites)
+	require.Equal(t, "TLSv1.2", config.GetGlobalConfig().Security.MinTLSVersion)
+
+	require.Error(t, server.SetTLSPolicy("TLSv1.4", nil))
+	require.Error(t, server.SetTLSPolicy("TLSv1.3", []string{"TLS_RSA_WITH_RC4_128_SHA"}))
+	require.Equal(t, tlsConfig, server.getTLSConfig())
+	require.Equal(t, "TLSv1.2", config.GetGlobalConfig().Security.MinTLSVersion)
+
+	// The policy is kept when the certificates are reloaded.
+	require.NoError(t, server.ReloadTLS())
+	require.Equal(t, uint16(tls.VersionTLS12), server.getTLSConfig().MinVersion)
+	require.Len(t, server.getTLSConfig().CipherSuites, 2)
+}
@@ -25,13 +25,19 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"database/sql"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"io"
 	"math/big"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -46,13 +52,16 @@ import (
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/audit"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/plancodec"
 	"github.com/pingcap/tidb/util/topsql/reporter"
 	mockTopSQLReporter "github.com/pingcap/tidb/util/topsql/reporter/mock"
 	"github.com/pingcap/tidb/util/topsql/tracecpu"
 	mockTopSQLTraceCPU "github.com/pingcap/tidb/util/topsql/tracecpu/mock"
+	"github.com/pingcap/tidb/util/traffic"
 	"github.com/stretchr/testify/require"
 )
 
@@ -203,6 +212,45 @@ func TestIssues(t *testing.T) {
 	ts.runTestIssue22646(t)
 }
 
+func TestExpiredPasswordLogin(t *testing.T) {
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+
+	ts.runTests(t, nil, func(dbt *testkit.DBTestKit) {
+		dbt.MustExec(`CREATE USER 'expired'@'%' IDENTIFIED BY 'expired' PASSWORD EXPIRE`)
+	})
+	db, err := sql.Open("mysql", ts.getDSN(func(config *mysql.Config) {
+		config.User = "expired"
+		config.Passwd = "expired"
+		config.DBName = ""
+	}))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+	// The driver doesn't declare that it can handle the expired passwords.
+	err = db.Ping()
+	require.EqualError(t, err, "Error 1862: Your password has expired. To log in you must change it using a client that supports expired passwords.")
+
+	// The clients are logged in the sandbox mode if disconnect-on-expired-password is false.
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Security.DisconnectOnExpiredPassword = false
+	})
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+	_, err = conn.ExecContext(ctx, "select 1")
+	require.EqualError(t, err, "Error 1820: You must SET PASSWORD before executing this statement")
+	_, err = conn.ExecContext(ctx, "set password = 'changed'")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "select 1")
+	require.NoError(t, err)
+}
+
 func TestDBNameEscape(t *testing.T) {
 	ts, cleanup := createTidbTestSuite(t)
 	defer cleanup()
@@ -613,6 +661,176 @@ func TestSocketAndIp(t *testing.T) {
 }
 
 // TestOnlySocket for server configuration without network interface for mysql clients
+func TestProxyProtocol(t *testing.T) {
+	cli := newTestServerClient()
+	cfg := newTestConfig()
+	cfg.Port = cli.port
+	cfg.Status.ReportStatus = false
+	cfg.ProxyProtocol.Networks = "127.0.0.1"
+	cfg.ProxyProtocol.HeaderTimeout = 5
+
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+
+	server, err := NewServer(cfg, ts.tidbdrv)
+	require.NoError(t, err)
+	cli.port = getPortFromTCPAddr(server.listener.Addr())
+	go func() {
+		err := server.Run()
+		require.NoError(t, err)
+	}()
+	defer server.Close()
+	originNetworks := config.GetGlobalConfig().ProxyProtocol.Networks
+	defer func() {
+		config.GetGlobalConfig().ProxyProtocol.Networks = originNetworks
+	}()
+
+	// The dialer sends a PROXY protocol v2 header with a TLV, the client is 192.168.1.10:56789.
+	mysql.RegisterDialContext("proxyv2", func(ctx context.Context, addr string) (net.Conn, error) {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		header := []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A, 0x21, 0x11, 0x00, 0x13,
+			192, 168, 1, 10, 127, 0, 0, 1, 0xDD, 0xD5, 0x0F, 0xA0,
+			0x02, 0x00, 0x04, 't', 'i', 'd', 'b'}
+		if _, err = conn.Write(header); err != nil {
+			return nil, err
+		}
+		return conn, nil
+	})
+	cli.runTests(t, func(config *mysql.Config) {
+		config.User = "root"
+		config.Net = "proxyv2"
+	},
+		func(dbt *testkit.DBTestKit) {
+			rows := dbt.MustQuery("select user()")
+			cli.checkRows(t, rows, "root@192.168.1.10")
+			rows = dbt.MustQuery("select host from information_schema.processlist where info like 'select host%'")
+			cli.checkRows(t, rows, "192.168.1.10:56789")
+			dbt.MustExec("CREATE USER 'proxied'@'192.168.1.%'")
+			dbt.MustExec("GRANT SELECT ON test.* TO 'proxied'@'192.168.1.%'")
+		})
+	// The host of the client is used to match the user.
+	cli.runTests(t, func(config *mysql.Config) {
+		config.User = "proxied"
+		config.Net = "proxyv2"
+	},
+		func(dbt *testkit.DBTestKit) {
+			rows := dbt.MustQuery("select current_user()")
+			cli.checkRows(t, rows, "proxied@192.168.1.%")
+		})
+
+	// The header is not read from the networks which are not allowed.
+	require.Error(t, server.SetProxyProtocolNetworks("127.0.0.1,abc"))
+	require.NoError(t, server.SetProxyProtocolNetworks("10.0.0.0/8"))
+	require.Equal(t, "10.0.0.0/8", config.GetGlobalConfig().ProxyProtocol.Networks)
+	cli.runTests(t, func(config *mysql.Config) {
+		config.User = "root"
+	},
+		func(dbt *testkit.DBTestKit) {
+			rows := dbt.MustQuery("select user()")
+			cli.checkRows(t, rows, "root@127.0.0.1")
+			dbt.MustExec("DROP USER 'proxied'@'192.168.1.%'")
+		})
+}
+
+func TestCompressedProtocol(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Status.ReportStatus = false
+
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+
+	server, err := NewServer(cfg, ts.tidbdrv)
+	require.NoError(t, err)
+	go func() {
+		err := server.Run()
+		require.NoError(t, err)
+	}()
+	defer server.Close()
+	time.Sleep(time.Millisecond * 100)
+
+	for _, capability := range []uint32{tmysql.ClientCompress, tmysql.ClientZstdCompressionAlgorithm} {
+		conn, err := net.Dial("tcp", server.listener.Addr().String())
+		require.NoError(t, err)
+		pkt := newPacketIO(newBufferedReadConn(conn))
+
+		// Read the initial handshake and reply a handshake response of root without password.
+		_, err = pkt.readPacket()
+		require.NoError(t, err)
+		resp := make([]byte, 4, 64)
+		resp = dumpUint32(resp, tmysql.ClientProtocol41|tmysql.ClientSecureConnection|tmysql.ClientPluginAuth|capability)
+		resp = dumpUint32(resp, tmysql.MaxPayloadLen)
+		resp = append(resp, tmysql.DefaultCollationID)
+		resp = append(resp, make([]byte, 23)...)
+		resp = append(resp, "root"...)
+		resp = append(resp, 0, 0)
+		resp = append(resp, tmysql.AuthNativePassword...)
+		resp = append(resp, 0)
+		if capability == tmysql.ClientZstdCompressionAlgorithm {
+			resp = append(resp, 5)
+		}
+		require.NoError(t, pkt.writePacket(resp))
+		require.NoError(t, pkt.flush())
+		data, err := pkt.readPacket()
+		require.NoError(t, err)
+		require.Equal(t, tmysql.OKHeader, data[0])
+
+		algorithm, level := compressionZlib, defaultZlibCompressionLevel
+		if capability == tmysql.ClientZstdCompressionAlgorithm {
+			algorithm, level = compressionZstd, 5
+		}
+		require.NoError(t, pkt.setCompression(algorithm, level))
+		query := func(sql string) [][]byte {
+			pkt.resetSequence()
+			require.NoError(t, pkt.writePacket(append([]byte{0, 0, 0, 0, tmysql.ComQuery}, sql...)))
+			require.NoError(t, pkt.flush())
+			// Skip the column count, the column definitions and the EOF.
+			data, err := pkt.readPacket()
+			require.NoError(t, err)
+			columns, _, _ := parseLengthEncodedInt(data)
+			for i := uint64(0); i <= columns; i++ {
+				_, err = pkt.readPacket()
+				require.NoError(t, err)
+			}
+			var rows [][]byte
+			for {
+				data, err = pkt.readPacket()
+				require.NoError(t, err)
+				if data[0] == tmysql.EOFHeader && len(data) < 9 {
+					return rows
+				}
+				rows = append(rows, data)
+			}
+		}
+
+		rows := query("select repeat('a', 10000)")
+		require.Len(t, rows, 1)
+		value, _, _, err := parseLengthEncodedBytes(rows[0])
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("a", 10000), string(value))
+		require.Less(t, pkt.compressStats.compressedBytesRead, pkt.compressStats.uncompressedBytesRead)
+
+		rows = query("show status like 'Compression%'")
+		status := make(map[string]string, len(rows))
+		for _, row := range rows {
+			name, _, off, err := parseLengthEncodedBytes(row)
+			require.NoError(t, err)
+			value, _, _, err := parseLengthEncodedBytes(row[off:])
+			require.NoError(t, err)
+			status[string(name)] = string(value)
+		}
+		require.Equal(t, "ON", status["Compression"])
+		require.Equal(t, compressionAlgorithmNames[algorithm], status["Compression_algorithm"])
+		require.Equal(t, strconv.Itoa(level), status["Compression_level"])
+
+		pkt.close()
+		require.NoError(t, conn.Close())
+	}
+}
+
 func TestOnlySocket(t *testing.T) {
 	osTempDir := os.TempDir()
 	tempDir, err := os.MkdirTemp(osTempDir, "tidb-test.*.socket")
@@ -994,6 +1212,48 @@ func TestShowTablesFlen(t *testing.T) {
 	require.Equal(t, 26*tmysql.MaxBytesOfCharacter, int(cols[0].ColumnLength))
 }
 
+func TestMigrateBinaryProtocolStmts(t *testing.T) {
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	qctx1, err := ts.tidbdrv.OpenCtx(uint64(0), 0, uint8(tmysql.DefaultCollationID), "test", nil)
+	require.NoError(t, err)
+	_, err = Execute(ctx, qctx1, "use test")
+	require.NoError(t, err)
+	_, err = Execute(ctx, qctx1, "create table t_migrate(a int primary key, b varchar(10))")
+	require.NoError(t, err)
+	_, err = Execute(ctx, qctx1, "insert into t_migrate values(1, 'a'), (2, 'b')")
+	require.NoError(t, err)
+	stmt1, _, _, err := qctx1.Prepare("select b from t_migrate where a = ?")
+	require.NoError(t, err)
+	stmt1.SetParamsType([]byte{tmysql.TypeLonglong, 0})
+	require.NoError(t, stmt1.AppendParam(0, []byte("long data")))
+
+	rs, err := Execute(ctx, qctx1, "show session_states")
+	require.NoError(t, err)
+	req := rs.NewChunk(nil)
+	require.NoError(t, rs.Next(ctx, req))
+	states := req.GetRow(0).GetJSON(0).String()
+	require.NoError(t, rs.Close())
+
+	qctx2, err := ts.tidbdrv.OpenCtx(uint64(0), 0, uint8(tmysql.DefaultCollationID), "", nil)
+	require.NoError(t, err)
+	_, err = Execute(ctx, qctx2, fmt.Sprintf("set session_states '%s'", states))
+	require.NoError(t, err)
+	stmt2 := qctx2.GetStatement(stmt1.ID())
+	require.NotNil(t, stmt2)
+	require.Equal(t, 1, stmt2.NumParams())
+	require.Equal(t, []byte{tmysql.TypeLonglong, 0}, stmt2.GetParamsType())
+	require.Equal(t, [][]byte{[]byte("long data")}, stmt2.BoundParams())
+	rs, err = stmt2.Execute(ctx, []types.Datum{types.NewIntDatum(2)})
+	require.NoError(t, err)
+	req = rs.NewChunk(nil)
+	require.NoError(t, rs.Next(ctx, req))
+	require.Equal(t, "b", req.GetRow(0).GetString(0))
+	require.NoError(t, rs.Close())
+}
+
 func checkColNames(t *testing.T, columns []*ColumnInfo, names ...string) {
 	for i, name := range names {
 		require.Equal(t, name, columns[i].Name)
@@ -1233,6 +1493,128 @@ func TestGracefulShutdown(t *testing.T) {
 	require.Regexp(t, "connect: connection refused$", err.Error())
 }
 
+func TestDrain(t *testing.T) {
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+
+	cli := newTestServerClient()
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Status.StatusPort = 0
+	cfg.Status.ReportStatus = true
+	server, err := NewServer(cfg, ts.tidbdrv)
+	require.NoError(t, err)
+	cli.port = getPortFromTCPAddr(server.listener.Addr())
+	cli.statusPort = getPortFromTCPAddr(server.statusListener.Addr())
+	go func() {
+		err := server.Run()
+		require.NoError(t, err)
+	}()
+	defer server.Close()
+	time.Sleep(time.Millisecond * 100)
+
+	db, err := sql.Open("mysql", cli.getDSN())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+	ctx := context.Background()
+	txnConn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	idleConn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	_, err = txnConn.ExecContext(ctx, "begin")
+	require.NoError(t, err)
+	_, err = txnConn.ExecContext(ctx, "select 1")
+	require.NoError(t, err)
+	require.NoError(t, idleConn.PingContext(ctx))
+
+	// Draining is only changed by the API v2, the status API only shows the progress.
+	resp, err := cli.formStatus("/drain", url.Values{"drain": {"true"}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	require.False(t, server.GetDrainStatus().Draining)
+	resp, err = cli.formAPIV2("/drain", url.Values{"drain": {"foo"}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	resp, err = cli.formAPIV2("/drain", url.Values{"drain": {"true"}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	require.Error(t, server.Drain(0))
+	resp, err = cli.fetchStatus("/drain")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var st DrainStatus
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&st))
+	require.NoError(t, resp.Body.Close())
+	require.True(t, st.Draining)
+
+	// The server reports unhealthy and rejects new connections.
+	resp, err = cli.fetchStatus("/status")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	newConn, err := db.Conn(ctx)
+	if err == nil {
+		err = newConn.PingContext(ctx)
+		require.NoError(t, newConn.Close())
+	}
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Server shutdown in progress")
+
+	// The idle connection is closed and the connection in the transaction is kept.
+	require.Eventually(t, func() bool {
+		return server.GetDrainStatus().Connections == 1
+	}, 5*time.Second, 100*time.Millisecond)
+	st = server.GetDrainStatus()
+	require.True(t, st.Draining)
+	require.Equal(t, 1, st.ActiveTxns)
+	require.False(t, st.Finished)
+	require.True(t, st.Deadline.IsZero())
+	_, err = txnConn.ExecContext(ctx, "commit")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return server.GetDrainStatus().Finished
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Error(t, idleConn.PingContext(ctx))
+	require.NoError(t, idleConn.Close())
+	require.NoError(t, txnConn.Close())
+
+	// The server accepts new connections after draining is stopped.
+	resp, err = cli.formAPIV2("/drain", url.Values{"drain": {"false"}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	resp, err = cli.fetchStatus("/status")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	db2, err := sql.Open("mysql", cli.getDSN())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db2.Close())
+	}()
+	txnConn, err = db2.Conn(ctx)
+	require.NoError(t, err)
+	_, err = txnConn.ExecContext(ctx, "begin")
+	require.NoError(t, err)
+	_, err = txnConn.ExecContext(ctx, "select 1")
+	require.NoError(t, err)
+
+	// The connections in the transactions are killed once the timeout is reached.
+	require.NoError(t, server.Drain(time.Second))
+	require.False(t, server.GetDrainStatus().Deadline.IsZero())
+	require.Eventually(t, func() bool {
+		return server.GetDrainStatus().Finished
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Error(t, txnConn.PingContext(ctx))
+	require.NoError(t, txnConn.Close())
+	server.StopDrain()
+}
+
 func TestPessimisticInsertSelectForUpdate(t *testing.T) {
 	ts, cleanup := createTidbTestSuite(t)
 	defer cleanup()
@@ -1258,6 +1640,75 @@ func TestPessimisticInsertSelectForUpdate(t *testing.T) {
 	require.Nil(t, rs) // should be no delay
 }
 
+func TestMaxExecutionTime(t *testing.T) {
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+
+	db, err := sql.Open("mysql", ts.getDSN())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+	ctx := context.Background()
+	conn1, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn1.Close())
+	}()
+	conn2, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn2.Close())
+	}()
+	mustExec := func(conn *sql.Conn, sql string) {
+		_, err := conn.ExecContext(ctx, sql)
+		require.NoError(t, err)
+	}
+	errCode := func(err error) int {
+		require.Error(t, err)
+		mysqlErr, ok := errors.Cause(err).(*mysql.MySQLError)
+		require.True(t, ok, err.Error())
+		return int(mysqlErr.Number)
+	}
+
+	mustExec(conn1, "create table max_exec_time (id int primary key, v int)")
+	mustExec(conn1, "insert into max_exec_time values (1, 1)")
+	mustExec(conn1, "begin pessimistic")
+	mustExec(conn1, "select * from max_exec_time where id = 1 for update")
+
+	// The statements of conn2 wait for the lock held by conn1.
+	mustExec(conn2, "set @@innodb_lock_wait_timeout = 2")
+	mustExec(conn2, "set @@max_execution_time = 200")
+	mustExec(conn2, "begin pessimistic")
+	_, err = conn2.ExecContext(ctx, "select * from max_exec_time where id = 1 for update")
+	require.Equal(t, tmysql.ErrMaxExecTimeExceeded, errCode(err))
+	// The DML statements aren't limited by default.
+	_, err = conn2.ExecContext(ctx, "update max_exec_time set v = 2 where id = 1")
+	require.Equal(t, tmysql.ErrLockWaitTimeout, errCode(err))
+	mustExec(conn2, "set @@tidb_enable_dml_max_execution_time = on")
+	_, err = conn2.ExecContext(ctx, "update max_exec_time set v = 2 where id = 1")
+	require.Equal(t, tmysql.ErrMaxExecTimeExceeded, errCode(err))
+	_, err = conn2.ExecContext(ctx, "delete /*+ MAX_EXECUTION_TIME(100) */ from max_exec_time where id = 1")
+	require.Equal(t, tmysql.ErrMaxExecTimeExceeded, errCode(err))
+
+	// KILL QUERY is still reported as ErrQueryInterrupted.
+	mustExec(conn2, "set @@max_execution_time = 0")
+	var connID uint64
+	require.NoError(t, conn2.QueryRowContext(ctx, "select connection_id()").Scan(&connID))
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := conn2.ExecContext(ctx, "update max_exec_time set v = 2 where id = 1")
+		errCh <- err
+	}()
+	time.Sleep(200 * time.Millisecond)
+	mustExec(conn1, fmt.Sprintf("kill tidb query %d", connID))
+	require.Equal(t, tmysql.ErrQueryInterrupted, errCode(<-errCh))
+
+	mustExec(conn1, "rollback")
+	mustExec(conn2, "update max_exec_time set v = 2 where id = 1")
+	mustExec(conn2, "commit")
+}
+
 type collectorWrapper struct {
 	reporter.TopSQLReporter
 }
@@ -1769,3 +2220,200 @@ func TestLocalhostClientMapping(t *testing.T) {
 	err = dbSocket.Ping()
 	require.Errorf(t, err, "Connection successful without matching host for unix domain socket!")
 }
+
+func TestCaptureAndReplayTraffic(t *testing.T) {
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+
+	db, err := sql.Open("mysql", ts.getDSN())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+	_, err = db.Exec("create table traffic (id int primary key auto_increment, v varchar(10))")
+	require.NoError(t, err)
+
+	require.Error(t, ts.server.StartCapture("", 0, 0, 0))
+	// The directory must be in traffic-capture-dir.
+	resp, err := ts.formAPIV2("/traffic/capture", url.Values{"capture": {"true"}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	root := t.TempDir()
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Status.TrafficCaptureDir = root
+	})
+	for _, dir := range []string{t.TempDir(), "../capture", "capture/../../capture"} {
+		resp, err = ts.formAPIV2("/traffic/capture", url.Values{"capture": {"true"}, "dir": {dir}})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, dir)
+		require.NoError(t, resp.Body.Close())
+	}
+	dir := filepath.Join(root, "capture")
+	resp, err = ts.formAPIV2("/traffic/capture", url.Values{"capture": {"true"}, "dir": {"capture"}, "duration": {"foo"}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	resp, err = ts.formAPIV2("/traffic/capture", url.Values{"capture": {"true"}, "dir": {"capture"}, "max-size": {"16"}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	require.Error(t, ts.server.StartCapture(dir, 0, 0, 0))
+	require.True(t, ts.server.GetCaptureStatus().Capturing)
+	_, err = db.Exec("insert into traffic (v) values ('a')")
+	require.NoError(t, err)
+	// The prepared statement is captured with its parameters.
+	_, err = db.Exec("insert into traffic (v) values (?), (?)", "b", nil)
+	require.NoError(t, err)
+	_, err = db.Exec("insert into traffic (id, v) values (1, 'a')")
+	require.Error(t, err)
+	resp, err = ts.formAPIV2("/traffic/capture", url.Values{"capture": {"false"}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	status := ts.server.GetCaptureStatus()
+	require.False(t, status.Capturing)
+	require.Empty(t, status.Err)
+	require.Equal(t, uint64(0), status.Dropped)
+
+	reader, err := traffic.NewReader(dir)
+	require.NoError(t, err)
+	var records []*traffic.Record
+	for {
+		r, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if strings.Contains(r.SQL, "traffic") {
+			records = append(records, r)
+		}
+	}
+	require.NoError(t, reader.Close())
+	require.Len(t, records, 3)
+	require.Equal(t, traffic.CmdExecute, records[1].Cmd)
+	require.Equal(t, [][]byte{[]byte("b"), nil}, records[1].Params)
+	require.Equal(t, "test", records[1].DB)
+	require.NotEmpty(t, records[2].Err)
+
+	// Replay the traffic against the same server, the duplicated key error is expected again.
+	reader, err = traffic.NewReader(dir)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, reader.Close())
+	}()
+	replayer := traffic.NewReplayer(db, 0, nil)
+	stats, err := replayer.Replay(context.Background(), reader)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), stats.Mismatched)
+	require.GreaterOrEqual(t, stats.Replayed, uint64(3))
+	var count int
+	require.NoError(t, db.QueryRow("select count(*) from traffic where v = 'b' or v is null").Scan(&count))
+	require.Equal(t, 4, count)
+
+	// The passwords are never captured.
+	dir = filepath.Join(root, "redact")
+	require.NoError(t, ts.server.StartCapture(dir, 0, 0, 0))
+	_, err = db.Exec("create user 'traffic'@'%' identified by 'traffic_secret'")
+	require.NoError(t, err)
+	_, err = db.Exec("set password for 'traffic'@'%' = 'traffic_secret'")
+	require.NoError(t, err)
+	ts.server.StopCapture()
+	redactReader, err := traffic.NewReader(dir)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, redactReader.Close())
+	}()
+	records = records[:0]
+	for {
+		r, err := redactReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if strings.Contains(r.SQL, "traffic") {
+			records = append(records, r)
+		}
+	}
+	require.Len(t, records, 2)
+	for _, r := range records {
+		require.NotContains(t, r.SQL, "traffic_secret")
+	}
+}
+
+type memoryAuditSink struct {
+	sync.Mutex
+	events []*audit.Event
+}
+
+func (s *memoryAuditSink) Write(e *audit.Event) error {
+	s.Lock()
+	defer s.Unlock()
+	s.events = append(s.events, e)
+	return nil
+}
+
+func (s *memoryAuditSink) Close() error {
+	return nil
+}
+
+func TestAuditLog(t *testing.T) {
+	ts, cleanup := createTidbTestSuite(t)
+	defer cleanup()
+
+	sink := &memoryAuditSink{}
+	rules := []audit.Rule{
+		{Classes: []string{audit.ClassConnection, audit.ClassPrivilege, audit.ClassDDL}},
+		{DBs: []string{"audit"}, Classes: []string{audit.ClassDML}},
+	}
+	logger, err := audit.NewLogger(sink, rules, true)
+	require.NoError(t, err)
+	require.Nil(t, audit.SetGlobalLogger(logger))
+
+	ts.runTests(t, nil, func(dbt *testkit.DBTestKit) {
+		dbt.MustExec("create database audit")
+		dbt.MustExec("create table audit.t (a int primary key, b varchar(10))")
+		dbt.MustExec("insert into audit.t values (1, 'secret')")
+		// The statements of the other databases are filtered out.
+		dbt.MustQuery("select 1").Close()
+		_, err := dbt.GetDB().Exec("insert into audit.t values (1, 'secret')")
+		require.Error(t, err)
+		dbt.MustExec("create user audit_user identified by 'abc'")
+	})
+	db, err := sql.Open("mysql", ts.getDSN(func(config *mysql.Config) {
+		config.User = "audit_user"
+		config.Passwd = "wrong"
+	}))
+	require.NoError(t, err)
+	require.Error(t, db.Ping())
+	require.NoError(t, db.Close())
+
+	require.Equal(t, logger, audit.SetGlobalLogger(nil))
+	require.NoError(t, logger.Close())
+
+	var events []string
+	var connID uint64
+	for i, e := range sink.events {
+		if e.Class != audit.ClassConnection {
+			require.Equal(t, connID, e.ConnID)
+			require.Equal(t, uint64(i+1), e.Seq)
+		}
+		switch e.Event {
+		case audit.EventConnect:
+			connID = e.ConnID
+			require.Equal(t, "root", e.User)
+		case audit.EventReject:
+			require.Equal(t, "audit_user", e.User)
+			require.Equal(t, uint16(tmysql.ErrAccessDenied), e.ErrCode)
+		case "Insert":
+			require.Equal(t, []string{"audit.t"}, e.Tables)
+			require.Equal(t, "insert into `audit` . `t` values ( ... )", e.SQL)
+		}
+		events = append(events, e.Event)
+	}
+	require.Equal(t, []string{audit.EventConnect, "CreateDatabase", "CreateTable", "Insert", "Insert", "CreateUser",
+		audit.EventDisconnect, audit.EventReject}, events)
+	require.Equal(t, uint16(0), sink.events[3].ErrCode)
+	require.Equal(t, uint16(tmysql.ErrDupEntry), sink.events[4].ErrCode)
+}
@@ -14,6 +14,8 @@
 
 package server
 
+import "context"
+
 // Token is used as a permission to keep on running.
 type Token struct {
 }
@@ -34,6 +36,26 @@ func (tl *TokenLimiter) Get() *Token {
 	return <-tl.ch
 }
 
+// TryGet obtains a token without blocking, it returns false if there is no token available.
+func (tl *TokenLimiter) TryGet() (*Token, bool) {
+	select {
+	case tk := <-tl.ch:
+		return tk, true
+	default:
+		return nil, false
+	}
+}
+
+// GetWithContext obtains a token, it returns false if ctx is done before a token is available.
+func (tl *TokenLimiter) GetWithContext(ctx context.Context) (*Token, bool) {
+	select {
+	case tk := <-tl.ch:
+		return tk, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
 // NewTokenLimiter creates a TokenLimiter with count tokens.
 func NewTokenLimiter(count uint) *TokenLimiter {
 	tl := &TokenLimiter{count: count, ch: make(chan *Token, count)}
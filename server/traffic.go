@@ -0,0 +1,201 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/traffic"
+	"go.uber.org/zap"
+)
+
+// captureDir resolves the capture directory specified by the status API, which must be in status.traffic-capture-dir.
+func captureDir(dir string) (string, error) {
+	root := config.GetGlobalConfig().Status.TrafficCaptureDir
+	if root == "" {
+		return "", errors.New("capturing traffic is disabled because status.traffic-capture-dir is not configured")
+	}
+	if filepath.IsAbs(dir) {
+		return "", errors.Errorf("the capture directory %s should be relative to status.traffic-capture-dir", dir)
+	}
+	path := filepath.Join(root, dir)
+	if rel, err := filepath.Rel(root, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("the capture directory %s is out of status.traffic-capture-dir", dir)
+	}
+	return path, nil
+}
+
+// StartCapture starts capturing the statements of the client connections into the files in dir, which can be
+// replayed by the trafficreplay tool. The files are rotated when they exceed maxSize bytes and only the latest
+// maxFiles rotated files are kept. If duration is positive, the capture stops after it.
+func (s *Server) StartCapture(dir string, maxSize int64, maxFiles int, duration time.Duration) error {
+	if dir == "" {
+		return errors.New("the capture directory is not specified")
+	}
+	if duration < 0 {
+		return errors.Errorf("invalid capture duration %v", duration)
+	}
+	s.capture.Lock()
+	defer s.capture.Unlock()
+	if s.capture.capturer != nil {
+		return errors.New("the server is already capturing traffic")
+	}
+	sink, err := traffic.NewFileSink(dir, maxSize, maxFiles)
+	if err != nil {
+		return err
+	}
+	var deadline time.Time
+	if duration > 0 {
+		deadline = time.Now().Add(duration)
+		s.capture.timer = time.AfterFunc(duration, s.StopCapture)
+	}
+	s.capture.capturer = traffic.NewCapturer(sink, dir, deadline)
+	s.capture.last = s.capture.capturer
+	atomic.StoreInt32(&s.capture.capturing, 1)
+	logutil.BgLogger().Info("start capturing traffic", zap.String("dir", dir), zap.Int64("max-size", maxSize),
+		zap.Int("max-files", maxFiles), zap.Duration("duration", duration))
+	return nil
+}
+
+// StopCapture stops capturing the traffic and flushes the captured statements.
+func (s *Server) StopCapture() {
+	s.capture.Lock()
+	capturer := s.capture.capturer
+	if capturer == nil {
+		s.capture.Unlock()
+		return
+	}
+	atomic.StoreInt32(&s.capture.capturing, 0)
+	s.capture.capturer = nil
+	if s.capture.timer != nil {
+		s.capture.timer.Stop()
+		s.capture.timer = nil
+	}
+	s.capture.Unlock()
+	// No connection captures into it after it's detached.
+	capturer.Stop()
+	status := capturer.Status()
+	logutil.BgLogger().Info("stop capturing traffic", zap.Uint64("captured", status.Captured),
+		zap.Uint64("dropped", status.Dropped), zap.String("err", status.Err))
+}
+
+// GetCaptureStatus reports the status of the current or the last capture.
+func (s *Server) GetCaptureStatus() traffic.CaptureStatus {
+	s.capture.RLock()
+	defer s.capture.RUnlock()
+	if s.capture.last == nil {
+		return traffic.CaptureStatus{}
+	}
+	return s.capture.last.Status()
+}
+
+// secureTextOfStmts returns the text of the statements in which the passwords are redacted, or an empty string if
+// none of the statements is sensitive.
+func secureTextOfStmts(stmts []ast.StmtNode) string {
+	sensitive := false
+	for _, stmt := range stmts {
+		if _, ok := stmt.(ast.SensitiveStmtNode); ok {
+			sensitive = true
+			break
+		}
+	}
+	if !sensitive {
+		return ""
+	}
+	texts := make([]string, 0, len(stmts))
+	for _, stmt := range stmts {
+		if ss, ok := stmt.(ast.SensitiveStmtNode); ok {
+			texts = append(texts, ss.SecureText())
+		} else {
+			texts = append(texts, stmt.Text())
+		}
+	}
+	return strings.Join(texts, "; ")
+}
+
+// captureTraffic captures the command if the server is capturing traffic. db is the current database before the
+// command is dispatched. Like the audit log, the passwords of the sensitive statements are never captured.
+func (cc *clientConn) captureTraffic(data []byte, db string, start time.Time, err error) {
+	secureText := cc.secureText
+	cc.secureText = ""
+	if atomic.LoadInt32(&cc.server.capture.capturing) == 0 || len(data) == 0 {
+		return
+	}
+	r := &traffic.Record{
+		ConnID:    cc.connectionID,
+		StartTime: start,
+		Duration:  time.Since(start),
+		User:      cc.user,
+		DB:        db,
+	}
+	payload := data[1:]
+	if len(payload) > 0 && payload[len(payload)-1] == 0 {
+		payload = payload[:len(payload)-1]
+	}
+	switch data[0] {
+	case mysql.ComQuery:
+		r.Cmd, r.SQL = traffic.CmdQuery, string(payload)
+		if secureText != "" {
+			r.SQL = secureText
+		}
+	case mysql.ComInitDB:
+		r.Cmd, r.SQL = traffic.CmdInitDB, string(payload)
+	case mysql.ComStmtExecute:
+		if len(payload) < 4 {
+			return
+		}
+		preparedObj, _ := cc.preparedStmtID2CachePreparedStmt(binary.LittleEndian.Uint32(payload))
+		if preparedObj == nil {
+			return
+		}
+		r.Cmd, r.SQL = traffic.CmdExecute, preparedObj.PreparedAst.Stmt.Text()
+		// The parameters are kept in the session until the next statement, they are dropped if they may be the
+		// passwords.
+		if ss, ok := preparedObj.PreparedAst.Stmt.(ast.SensitiveStmtNode); ok {
+			r.SQL = ss.SecureText()
+		} else if params := cc.ctx.GetSessionVars().PreparedParams; len(params) == len(preparedObj.PreparedAst.Params) {
+			r.Params = make([][]byte, len(params))
+			for i, param := range params {
+				if param.IsNull() {
+					continue
+				}
+				str, err := param.ToString()
+				if err != nil {
+					return
+				}
+				r.Params[i] = []byte(str)
+			}
+		}
+	default:
+		return
+	}
+	if err != nil {
+		r.Err = err.Error()
+	}
+	cc.server.capture.RLock()
+	if cc.server.capture.capturer != nil {
+		cc.server.capture.capturer.Capture(r)
+	}
+	cc.server.capture.RUnlock()
+}
@@ -97,6 +97,11 @@ func TestResultEncoder(t *testing.T) {
 	result = d.encodeMeta([]byte("一"))
 	require.Equal(t, []byte{0xd2, 0xbb}, result)
 
+	// Encode bytes to GB18030, the characters out of GBK are encoded in four bytes.
+	d = newResultEncoder("gb18030")
+	result = d.encodeMeta([]byte("一😃"))
+	require.Equal(t, []byte{0xd2, 0xbb, 0x94, 0x39, 0xfc, 0x39}, result)
+
 	// Encode bytes to binary.
 	d = newResultEncoder("binary")
 	result = d.encodeMeta([]byte("一"))
@@ -0,0 +1,824 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/server/mysqlx"
+	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/hack"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+const (
+	// xConnectTimeout is the timeout of reading the messages before the authentication,
+	// the same as the default mysqlx_connect_timeout.
+	xConnectTimeout = 30 * time.Second
+	// xMaxAuthAttempts is the number of the failed authentications before the connection is closed.
+	xMaxAuthAttempts = 3
+)
+
+// The authentication mechanisms of the X Protocol.
+const (
+	xAuthMySQL41 = "MYSQL41"
+	xAuthPlain   = "PLAIN"
+)
+
+// The conditions of the expectation blocks.
+const (
+	xExpectNoError     = 1
+	xExpectFieldExists = 2
+	xExpectDocIDGen    = 3
+)
+
+// xExpectFields are the protobuf fields which can be checked by the field_exists expectation.
+var xExpectFields = map[string]struct{}{
+	// Mysqlx.Session.Reset.keep_open
+	"6.1": {},
+}
+
+// startXListener accepts the connections of the X Protocol.
+func (s *Server) startXListener(errChan chan error) {
+	if s.xListener == nil {
+		errChan <- nil
+		return
+	}
+	for {
+		conn, err := s.xListener.Accept()
+		if err != nil {
+			if opErr, ok := err.(*net.OpError); ok {
+				if opErr.Err.Error() == "use of closed network connection" {
+					if s.inShutdownMode {
+						errChan <- nil
+					} else {
+						errChan <- err
+					}
+					return
+				}
+			}
+			logutil.BgLogger().Error("accept X Protocol connection failed", zap.Error(err))
+			errChan <- err
+			return
+		}
+		if s.dom != nil && s.dom.IsLostConnectionToPD() {
+			logutil.BgLogger().Warn("reject connection due to lost connection to PD")
+			terror.Log(conn.Close())
+			continue
+		}
+		xc := newXClientConn(s.newConn(conn))
+		go xc.Run(logutil.WithConnID(context.Background(), xc.connectionID))
+	}
+}
+
+// xExpectBlock is an expectation block opened by ExpectOpen.
+type xExpectBlock struct {
+	noError bool
+	// failed is true if an error occurred in the block.
+	failed bool
+}
+
+// xClientConn is a connection of the X Protocol. It shares the session, the connection ID and the states
+// with clientConn, so the connection can be shown and killed in the same way.
+type xClientConn struct {
+	*clientConn
+	w *bufio.Writer
+	// authMech is the mechanism waiting for AuthenticateContinue.
+	authMech string
+	// startTLS is true if TLS is enabled by CapabilitiesSet, the connection is upgraded after Ok is sent.
+	startTLS      bool
+	authFailures  int
+	authenticated bool
+	expects       []*xExpectBlock
+}
+
+func newXClientConn(cc *clientConn) *xClientConn {
+	return &xClientConn{clientConn: cc, w: bufio.NewWriterSize(cc.bufReadConn, defaultWriterSize)}
+}
+
+// writeMessage writes the message to the buffer, it's sent after flush is called.
+func (xc *xClientConn) writeMessage(tp byte, msg mysqlx.Message) error {
+	return mysqlx.WriteMessage(xc.w, tp, msg)
+}
+
+func (xc *xClientConn) writeOk(msg string) error {
+	return xc.writeMessage(mysqlx.ServerOk, &mysqlx.Ok{Msg: msg})
+}
+
+// writeError writes the error, the client is disconnected after a fatal error.
+func (xc *xClientConn) writeError(e error, severity uint32) error {
+	msg := &mysqlx.ErrorMsg{Severity: severity}
+	if xe, ok := errors.Cause(e).(*mysqlx.Error); ok {
+		msg.Code, msg.SQLState, msg.Msg = uint32(xe.Code), xe.SQLState, xe.Msg
+	} else {
+		m := toSQLError(e)
+		msg.Code, msg.SQLState, msg.Msg = uint32(m.Code), m.State, m.Message
+	}
+	xc.lastCode = uint16(msg.Code)
+	return xc.writeMessage(mysqlx.ServerError, msg)
+}
+
+func (xc *xClientConn) flush() error {
+	return xc.w.Flush()
+}
+
+// Run reads the messages and writes the responses in a loop until the connection is closed,
+// the states of the connection are changed in the same way as clientConn.Run.
+func (xc *xClientConn) Run(ctx context.Context) {
+	const size = 4096
+	defer func() {
+		r := recover()
+		if r != nil {
+			buf := make([]byte, size)
+			stackSize := runtime.Stack(buf, false)
+			logutil.Logger(ctx).Error("X Protocol connection running loop panic",
+				zap.Stringer("lastSQL", getLastStmtInConn{xc.clientConn}),
+				zap.String("err", fmt.Sprintf("%v", r)),
+				zap.String("stack", string(buf[:stackSize])),
+			)
+			terror.Log(xc.writeError(errors.New(fmt.Sprintf("%v", r)), mysqlx.SeverityFatal))
+			terror.Log(xc.flush())
+			metrics.PanicCounter.WithLabelValues(metrics.LabelSession).Inc()
+		}
+		if atomic.LoadInt32(&xc.status) != connStatusShutdown {
+			terror.Log(xc.Close())
+		}
+	}()
+
+	// Reject the connection if the server is draining, so that the client can retry another server immediately.
+	if xc.server.isDraining() {
+		terror.Log(xc.writeError(errServerShutdown.GenWithStackByArgs(), mysqlx.SeverityFatal))
+		terror.Log(xc.flush())
+		return
+	}
+	for {
+		if !atomic.CompareAndSwapInt32(&xc.status, connStatusDispatching, connStatusReading) {
+			return
+		}
+		readTimeout := xConnectTimeout
+		if xc.authenticated {
+			readTimeout = time.Duration(xc.getSessionVarsWaitTimeout(ctx)) * time.Second
+		}
+		var deadline time.Time
+		if readTimeout > 0 {
+			deadline = time.Now().Add(readTimeout)
+		}
+		if err := xc.bufReadConn.SetReadDeadline(deadline); err != nil {
+			logutil.Logger(ctx).Warn("set read deadline failed", zap.Error(err))
+		}
+		tp, payload, err := mysqlx.ReadMessage(xc.bufReadConn)
+		if err != nil {
+			if _, ok := err.(*mysqlx.Error); ok {
+				terror.Log(xc.writeError(err, mysqlx.SeverityFatal))
+				terror.Log(xc.flush())
+			} else if terror.ErrorNotEqual(err, io.EOF) {
+				errStack := errors.ErrorStack(err)
+				if !strings.Contains(errStack, "use of closed network connection") {
+					logutil.Logger(ctx).Info("read X Protocol message failed, close this connection",
+						zap.Error(errors.SuspendStack(err)))
+				}
+			}
+			disconnectByClientWithError.Inc()
+			return
+		}
+
+		if !atomic.CompareAndSwapInt32(&xc.status, connStatusReading, connStatusDispatching) {
+			return
+		}
+		err = xc.dispatch(ctx, tp, payload)
+		xc.chunkAlloc.Reset()
+		if err != nil {
+			err = xc.maxExecTimeError(err)
+			if terror.ErrorEqual(err, io.EOF) {
+				terror.Log(xc.flush())
+				disconnectNormal.Inc()
+				return
+			}
+			if len(xc.expects) > 0 {
+				xc.expects[len(xc.expects)-1].failed = true
+			}
+			logutil.Logger(ctx).Debug("X Protocol message dispatched failed",
+				zap.Uint8("type", tp), zap.Stringer("sql", getLastStmtInConn{xc.clientConn}), zap.Error(err))
+			terror.Log(xc.writeError(err, mysqlx.SeverityError))
+		}
+		if err = xc.flush(); err != nil {
+			logutil.Logger(ctx).Debug("flush X Protocol response failed", zap.Error(err))
+			return
+		}
+		// The TLS handshake starts after Ok of CapabilitiesSet is sent.
+		if tlsConfig := xc.pendingTLS(); tlsConfig != nil {
+			if err = xc.upgradeToTLS(tlsConfig); err != nil {
+				logutil.Logger(ctx).Info("X Protocol TLS handshake failed", zap.Error(err))
+				return
+			}
+			xc.w.Reset(xc.bufReadConn)
+		}
+	}
+}
+
+// pendingTLS returns the TLS config if the client has enabled TLS but the connection is not upgraded.
+func (xc *xClientConn) pendingTLS() *tls.Config {
+	if !xc.startTLS {
+		return nil
+	}
+	xc.startTLS = false
+	return xc.server.getTLSConfig()
+}
+
+func (xc *xClientConn) dispatch(ctx context.Context, tp byte, payload []byte) error {
+	if len(xc.expects) > 0 {
+		block := xc.expects[len(xc.expects)-1]
+		if block.noError && block.failed && tp != mysqlx.ClientExpectOpen && tp != mysqlx.ClientExpectClose {
+			return mysqlx.NewError(mysqlx.ErrXExpectFailed, "Expectation failed: no_error")
+		}
+	}
+	switch tp {
+	case mysqlx.ClientConCapabilitiesGet:
+		return xc.writeMessage(mysqlx.ServerConnCapabilities, xc.capabilities())
+	case mysqlx.ClientConClose:
+		if err := xc.writeOk("bye!"); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	if !xc.authenticated {
+		switch tp {
+		case mysqlx.ClientConCapabilitiesSet:
+			var m mysqlx.CapabilitiesSet
+			if err := m.Unmarshal(payload); err != nil {
+				return err
+			}
+			return xc.setCapabilities(&m)
+		case mysqlx.ClientSessAuthenticateStart:
+			var m mysqlx.AuthenticateStart
+			if err := m.Unmarshal(payload); err != nil {
+				return err
+			}
+			return xc.handleAuthenticateStart(ctx, &m)
+		case mysqlx.ClientSessAuthenticateContinue:
+			var m mysqlx.AuthData
+			if err := m.Unmarshal(payload); err != nil {
+				return err
+			}
+			return xc.handleAuthenticateContinue(ctx, &m)
+		}
+		return mysqlx.NewError(mysqlx.ErrUnknownComError, "Unexpected message received")
+	}
+
+	switch tp {
+	case mysqlx.ClientSessReset:
+		var m mysqlx.Reset
+		if err := m.Unmarshal(payload); err != nil {
+			return err
+		}
+		return xc.handleReset(ctx, &m)
+	case mysqlx.ClientSessClose:
+		if err := xc.writeOk("bye!"); err != nil {
+			return err
+		}
+		return io.EOF
+	case mysqlx.ClientExpectOpen:
+		var m mysqlx.ExpectOpen
+		if err := m.Unmarshal(payload); err != nil {
+			return err
+		}
+		return xc.handleExpectOpen(&m)
+	case mysqlx.ClientExpectClose:
+		return xc.handleExpectClose()
+	case mysqlx.ClientSQLStmtExecute, mysqlx.ClientCrudFind, mysqlx.ClientCrudInsert,
+		mysqlx.ClientCrudUpdate, mysqlx.ClientCrudDelete:
+		return xc.dispatchStmt(ctx, tp, payload)
+	}
+	return mysqlx.NewError(mysqlx.ErrUnknownComError, "Unexpected message received")
+}
+
+// capabilities returns the capabilities of the connection.
+func (xc *xClientConn) capabilities() *mysqlx.Capabilities {
+	str := func(s string) *mysqlx.Any {
+		return mysqlx.NewScalarAny(&mysqlx.Scalar{Type: mysqlx.ScalarString, String: []byte(s)})
+	}
+	mechanisms := &mysqlx.Array{Values: []*mysqlx.Any{str(xAuthMySQL41)}}
+	if xc.tlsConn != nil {
+		// The password is sent in plain text, so PLAIN is only supported over TLS.
+		mechanisms.Values = append(mechanisms.Values, str(xAuthPlain))
+	}
+	var caps []*mysqlx.Capability
+	if xc.tlsConn != nil || xc.server.getTLSConfig() != nil {
+		caps = append(caps, &mysqlx.Capability{Name: "tls", Value: mysqlx.NewScalarAny(&mysqlx.Scalar{
+			Type: mysqlx.ScalarBool, Bool: xc.tlsConn != nil,
+		})})
+	}
+	caps = append(caps,
+		&mysqlx.Capability{Name: "authentication.mechanisms", Value: &mysqlx.Any{Type: mysqlx.AnyArray, Array: mechanisms}},
+		&mysqlx.Capability{Name: "doc.formats", Value: str("text")},
+		&mysqlx.Capability{Name: "node_type", Value: str("mysql")},
+	)
+	return &mysqlx.Capabilities{Capabilities: caps}
+}
+
+// xScalarString returns the string of the scalar, ok is false if the scalar is not a string.
+func xScalarString(v *mysqlx.Any) (string, bool) {
+	if v == nil || v.Type != mysqlx.AnyScalar || v.Scalar == nil {
+		return "", false
+	}
+	switch v.Scalar.Type {
+	case mysqlx.ScalarString:
+		return string(v.Scalar.String), true
+	case mysqlx.ScalarOctets:
+		return string(v.Scalar.Octets), true
+	}
+	return "", false
+}
+
+// xScalarBool returns the boolean of the scalar, ok is false if the scalar is not a boolean or an integer.
+func xScalarBool(v *mysqlx.Any) (b bool, ok bool) {
+	if v == nil || v.Type != mysqlx.AnyScalar || v.Scalar == nil {
+		return false, false
+	}
+	switch v.Scalar.Type {
+	case mysqlx.ScalarBool:
+		return v.Scalar.Bool, true
+	case mysqlx.ScalarSInt:
+		return v.Scalar.SInt != 0, true
+	case mysqlx.ScalarUInt:
+		return v.Scalar.UInt != 0, true
+	}
+	return false, false
+}
+
+// setCapabilities sets the capabilities, none of them is set if any of them is invalid.
+func (xc *xClientConn) setCapabilities(m *mysqlx.CapabilitiesSet) error {
+	var enableTLS bool
+	var attrs map[string]string
+	if m.Capabilities != nil {
+		for _, c := range m.Capabilities.Capabilities {
+			switch c.Name {
+			case "tls":
+				b, ok := xScalarBool(c.Value)
+				if !ok || (!b && xc.tlsConn != nil) || (b && xc.tlsConn == nil && xc.server.getTLSConfig() == nil) {
+					return mysqlx.NewError(mysqlx.ErrXCapabilitiesPrepare, "Capability prepare failed for '%s'", c.Name)
+				}
+				enableTLS = b && xc.tlsConn == nil
+			case "client.pwd_expire_ok":
+				if _, ok := xScalarBool(c.Value); !ok {
+					return mysqlx.NewError(mysqlx.ErrXCapabilitiesPrepare, "Capability prepare failed for '%s'", c.Name)
+				}
+			case "session_connect_attrs":
+				if c.Value == nil || c.Value.Type != mysqlx.AnyObject || c.Value.Object == nil {
+					return mysqlx.NewError(mysqlx.ErrXCapabilitiesPrepare, "Capability prepare failed for '%s'", c.Name)
+				}
+				attrs = make(map[string]string, len(c.Value.Object.Fields))
+				for _, f := range c.Value.Object.Fields {
+					v, ok := xScalarString(f.Value)
+					if !ok {
+						return mysqlx.NewError(mysqlx.ErrXCapabilitiesPrepare, "Capability prepare failed for '%s'", c.Name)
+					}
+					attrs[f.Key] = v
+				}
+			default:
+				return mysqlx.NewError(mysqlx.ErrXCapabilityNotFound, "Capability '%s' doesn't exist", c.Name)
+			}
+		}
+	}
+	if attrs != nil {
+		xc.attrs = attrs
+	}
+	xc.startTLS = enableTLS
+	return xc.writeOk("")
+}
+
+func (xc *xClientConn) handleAuthenticateStart(ctx context.Context, m *mysqlx.AuthenticateStart) error {
+	switch m.MechName {
+	case xAuthMySQL41:
+		xc.authMech = m.MechName
+		return xc.writeMessage(mysqlx.ServerSessAuthenticateContinue, &mysqlx.AuthData{AuthData: xc.salt})
+	case xAuthPlain:
+		if xc.tlsConn == nil {
+			return xc.authFailed(mysqlx.NewError(mysqlx.ErrAccessDeniedError,
+				"Invalid authentication method %s, it's only supported over TLS", m.MechName))
+		}
+		return xc.authenticate(ctx, m.MechName, m.AuthData)
+	}
+	return xc.authFailed(mysqlx.NewError(mysqlx.ErrAccessDeniedError, "Invalid authentication method %s", m.MechName))
+}
+
+func (xc *xClientConn) handleAuthenticateContinue(ctx context.Context, m *mysqlx.AuthData) error {
+	if xc.authMech == "" {
+		return mysqlx.NewError(mysqlx.ErrUnknownComError, "Unexpected message received")
+	}
+	mech := xc.authMech
+	xc.authMech = ""
+	return xc.authenticate(ctx, mech, m.AuthData)
+}
+
+// authFailed writes the error of the authentication, the connection is closed after too many failures.
+func (xc *xClientConn) authFailed(err error) error {
+	xc.authFailures++
+	if xc.authFailures < xMaxAuthAttempts {
+		return err
+	}
+	if err = xc.writeError(err, mysqlx.SeverityFatal); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// authenticate authenticates the user with the data "schema\0user\0password". The password of MYSQL41 is
+// '*' followed by the hex of the scrambled password, and the password of PLAIN is in clear text.
+func (xc *xClientConn) authenticate(ctx context.Context, mech string, data []byte) error {
+	if xc.tlsConn == nil && xc.server.cfg.Security.RequireSecureTransport {
+		return xc.authFailed(errSecureTransportRequired.FastGenByArgs())
+	}
+	parts := bytes.SplitN(data, []byte{0}, 3)
+	if len(parts) != 3 {
+		return xc.authFailed(mysqlx.NewError(mysqlx.ErrAccessDeniedError, "Invalid user or password"))
+	}
+	xc.dbname, xc.user = string(parts[0]), string(parts[1])
+	if xc.ctx == nil {
+		if err := xc.openSession(); err != nil {
+			return err
+		}
+	}
+	var authData []byte
+	authPlugin := mysql.AuthNativePassword
+	switch mech {
+	case xAuthMySQL41:
+		if len(parts[2]) > 0 {
+			var err error
+			if parts[2][0] != '*' {
+				return xc.authFailed(mysqlx.NewError(mysqlx.ErrAccessDeniedError, "Invalid user or password"))
+			}
+			if authData, err = hex.DecodeString(string(parts[2][1:])); err != nil {
+				return xc.authFailed(mysqlx.NewError(mysqlx.ErrAccessDeniedError, "Invalid user or password"))
+			}
+		}
+	case xAuthPlain:
+		hasPassword := "YES"
+		if len(parts[2]) == 0 {
+			hasPassword = "NO"
+		}
+		host, _, err := xc.PeerHost(hasPassword)
+		if err != nil {
+			return err
+		}
+		identity, err := xc.ctx.MatchIdentity(xc.user, host)
+		if err != nil {
+			return xc.authFailed(errAccessDenied.FastGenByArgs(xc.user, host, hasPassword))
+		}
+		userPlugin, err := xc.ctx.AuthPluginForUser(identity)
+		if err != nil {
+			return xc.authFailed(errAccessDenied.FastGenByArgs(xc.user, host, hasPassword))
+		}
+		switch userPlugin {
+		case "", mysql.AuthCachingSha2Password, mysql.AuthTiDBSM3Password:
+			// The clear text password is checked, the same as the MySQL protocol over TLS.
+			authData = parts[2]
+		case mysql.AuthNativePassword:
+			authData = scramblePassword(xc.salt, string(parts[2]))
+		default:
+			authPlugin = userPlugin
+		}
+	}
+	if err := xc.openSessionAndDoAuth(authData, authPlugin); err != nil {
+		return xc.authFailed(err)
+	}
+
+	xc.authenticated = true
+	xc.server.rwlock.Lock()
+	xc.server.clients[xc.connectionID] = xc.clientConn
+	connections := len(xc.server.clients)
+	xc.server.rwlock.Unlock()
+	metrics.ConnGauge.Set(float64(connections))
+	logutil.Logger(ctx).Debug("new X Protocol connection", zap.String("remoteAddr", xc.bufReadConn.RemoteAddr().String()))
+
+	if err := xc.writeStateChanged(mysqlx.StateClientIDAssigned, &mysqlx.Scalar{Type: mysqlx.ScalarUInt, UInt: xc.connectionID}); err != nil {
+		return err
+	}
+	return xc.writeMessage(mysqlx.ServerSessAuthenticateOk, &mysqlx.AuthData{})
+}
+
+// handleReset resets the session. The user is kept if keep_open is set, otherwise the client must authenticate again.
+func (xc *xClientConn) handleReset(ctx context.Context, m *mysqlx.Reset) error {
+	xc.expects = nil
+	if m.KeepOpen {
+		if err := xc.reopenSession(ctx); err != nil {
+			return err
+		}
+		return xc.writeOk("")
+	}
+	xc.server.rwlock.Lock()
+	delete(xc.server.clients, xc.connectionID)
+	connections := len(xc.server.clients)
+	xc.server.rwlock.Unlock()
+	metrics.ConnGauge.Set(float64(connections))
+	err := xc.ctx.Close()
+	if err != nil {
+		logutil.Logger(ctx).Debug("close old context failed", zap.Error(err))
+	}
+	xc.ctx, xc.lastPacket = nil, nil
+	xc.user, xc.dbname = "", ""
+	xc.authenticated, xc.authFailures = false, 0
+	return xc.writeOk("")
+}
+
+func (xc *xClientConn) handleExpectOpen(m *mysqlx.ExpectOpen) error {
+	block := &xExpectBlock{}
+	if m.Op == mysqlx.ExpectCtxCopyPrev && len(xc.expects) > 0 {
+		*block = *xc.expects[len(xc.expects)-1]
+	}
+	// The block is pushed before the conditions are checked, so the failed block must also be closed.
+	xc.expects = append(xc.expects, block)
+	if block.noError && block.failed {
+		return mysqlx.NewError(mysqlx.ErrXExpectFailed, "Expectation failed: no_error")
+	}
+	for _, cond := range m.Conds {
+		set := cond.Op == 0
+		switch cond.Key {
+		case xExpectNoError:
+			block.noError = set && (len(cond.Value) == 0 || string(cond.Value) == "1")
+		case xExpectFieldExists:
+			if _, ok := xExpectFields[string(cond.Value)]; set && !ok {
+				return mysqlx.NewError(mysqlx.ErrXExpectFieldExists, "Expectation failed: field_exists = '%s'", cond.Value)
+			}
+		case xExpectDocIDGen:
+			// The IDs of the documents are always generated by the server.
+		default:
+			return mysqlx.NewError(mysqlx.ErrXExpectBadCondition, "Unknown condition key %d", cond.Key)
+		}
+	}
+	return xc.writeOk("")
+}
+
+func (xc *xClientConn) handleExpectClose() error {
+	if len(xc.expects) == 0 {
+		return mysqlx.NewError(mysqlx.ErrXExpectNotOpen, "Expect block currently not open")
+	}
+	block := xc.expects[len(xc.expects)-1]
+	xc.expects = xc.expects[:len(xc.expects)-1]
+	if block.noError && block.failed {
+		return mysqlx.NewError(mysqlx.ErrXExpectFailed, "Expectation failed: no_error")
+	}
+	return xc.writeOk("")
+}
+
+// dispatchStmt handles the messages which execute the statements, such as StmtExecute and the CRUD messages.
+func (xc *xClientConn) dispatchStmt(ctx context.Context, tp byte, payload []byte) error {
+	defer func() {
+		// reset killed for each request
+		atomic.StoreUint32(&xc.ctx.GetSessionVars().Killed, 0)
+	}()
+	var cancelFunc context.CancelFunc
+	ctx, cancelFunc = context.WithCancel(ctx)
+	defer cancelFunc()
+	xc.mu.Lock()
+	xc.mu.cancelFunc = cancelFunc
+	xc.mu.Unlock()
+
+	token, err := xc.server.getToken(ctx, true)
+	if err != nil {
+		return err
+	}
+	t := time.Now()
+	defer func() {
+		if xc.ctx != nil {
+			xc.ctx.SetProcessInfo("", t, mysql.ComSleep, 0)
+		}
+		xc.server.releaseToken(token)
+		xc.lastActive = time.Now()
+	}()
+	atomic.StoreUint32(&xc.ctx.GetSessionVars().Killed, 0)
+	xc.ctx.SetCommandValue(mysql.ComQuery)
+
+	switch tp {
+	case mysqlx.ClientSQLStmtExecute:
+		var m mysqlx.StmtExecute
+		if err := m.Unmarshal(payload); err != nil {
+			return err
+		}
+		switch m.Namespace {
+		case "sql":
+			sql, err := mysqlx.BuildSQL(string(hack.String(m.Stmt)), m.Args)
+			if err != nil {
+				return err
+			}
+			return xc.executeSQL(ctx, sql, nil)
+		case "mysqlx", "xplugin":
+			return xc.handleAdminCommand(ctx, string(hack.String(m.Stmt)), m.Args)
+		}
+		return mysqlx.NewError(mysqlx.ErrXInvalidNamespace, "Unknown namespace %s", m.Namespace)
+	case mysqlx.ClientCrudFind:
+		var m mysqlx.Find
+		if err := m.Unmarshal(payload); err != nil {
+			return err
+		}
+		sql, err := mysqlx.BuildFind(&m)
+		if err != nil {
+			return err
+		}
+		return xc.executeSQL(ctx, sql, nil)
+	case mysqlx.ClientCrudInsert:
+		var m mysqlx.Insert
+		if err := m.Unmarshal(payload); err != nil {
+			return err
+		}
+		sql, ids, err := mysqlx.BuildInsert(&m, xc.server.xIDs)
+		if err != nil {
+			return err
+		}
+		return xc.executeSQL(ctx, sql, ids)
+	case mysqlx.ClientCrudUpdate:
+		var m mysqlx.Update
+		if err := m.Unmarshal(payload); err != nil {
+			return err
+		}
+		sql, err := mysqlx.BuildUpdate(&m)
+		if err != nil {
+			return err
+		}
+		return xc.executeSQL(ctx, sql, nil)
+	default:
+		var m mysqlx.Delete
+		if err := m.Unmarshal(payload); err != nil {
+			return err
+		}
+		sql, err := mysqlx.BuildDelete(&m)
+		if err != nil {
+			return err
+		}
+		return xc.executeSQL(ctx, sql, nil)
+	}
+}
+
+// executeSQL executes the statement and writes the result set or the notices of the affected rows,
+// docIDs are the IDs of the inserted documents which are generated by the server.
+func (xc *xClientConn) executeSQL(ctx context.Context, sql string, docIDs []string) error {
+	xc.lastPacket = append([]byte{mysql.ComQuery}, sql...)
+	stmts, err := xc.ctx.Parse(ctx, sql)
+	if err != nil {
+		return err
+	}
+	if len(stmts) != 1 {
+		return mysqlx.NewError(mysqlx.ErrXInvalidProtocolData, "Only one statement can be executed at a time")
+	}
+	ctx = context.WithValue(ctx, execdetails.StmtExecDetailKey, &execdetails.StmtExecDetails{})
+	rs, err := xc.ctx.ExecuteStmt(ctx, stmts[0])
+	if rs != nil {
+		defer terror.Call(rs.Close)
+	}
+	if err != nil {
+		return err
+	}
+	if rs != nil {
+		if connStatus := atomic.LoadInt32(&xc.status); connStatus == connStatusShutdown {
+			return executor.ErrQueryInterrupted
+		}
+		if err = xc.writeResultset(ctx, rs); err != nil {
+			return err
+		}
+	} else {
+		// The statements which need the client to send or receive files are not supported.
+		unsupported := false
+		for _, key := range []fmt.Stringer{executor.LoadDataVarKey, executor.LoadStatsVarKey,
+			executor.IndexAdviseVarKey, executor.PlanReplayerLoadVarKey} {
+			if xc.ctx.Value(key) != nil {
+				xc.ctx.SetValue(key, nil)
+				unsupported = true
+			}
+		}
+		if unsupported {
+			return mysqlx.NewError(mysqlx.ErrUnknownComError, "The statement is not supported by the X Protocol")
+		}
+	}
+	if err = xc.writeWarnings(); err != nil {
+		return err
+	}
+	if rs == nil {
+		if err = xc.writeStateChanged(mysqlx.StateRowsAffected, &mysqlx.Scalar{Type: mysqlx.ScalarUInt, UInt: xc.ctx.AffectedRows()}); err != nil {
+			return err
+		}
+		if id := xc.ctx.LastInsertID(); id > 0 {
+			if err = xc.writeStateChanged(mysqlx.StateGeneratedInsertID, &mysqlx.Scalar{Type: mysqlx.ScalarUInt, UInt: id}); err != nil {
+				return err
+			}
+		}
+		if msg := xc.ctx.LastMessage(); msg != "" {
+			if err = xc.writeStateChanged(mysqlx.StateProducedMessage, &mysqlx.Scalar{Type: mysqlx.ScalarString, String: []byte(msg)}); err != nil {
+				return err
+			}
+		}
+	}
+	if len(docIDs) > 0 {
+		values := make([]*mysqlx.Scalar, 0, len(docIDs))
+		for _, id := range docIDs {
+			values = append(values, &mysqlx.Scalar{Type: mysqlx.ScalarOctets, Octets: []byte(id)})
+		}
+		if err = xc.writeStateChanged(mysqlx.StateGeneratedDocumentIDs, values...); err != nil {
+			return err
+		}
+	}
+	return xc.writeMessage(mysqlx.ServerSQLStmtExecuteOk, nil)
+}
+
+// xAdminArgs returns the string arguments of the admin command, which are either an object of the named arguments
+// or the positional arguments. The first required names must be set.
+func xAdminArgs(args []*mysqlx.Any, required int, names ...string) ([]string, error) {
+	values := make([]string, len(names))
+	set := make([]bool, len(names))
+	if len(args) == 1 && args[0] != nil && args[0].Type == mysqlx.AnyObject && args[0].Object != nil {
+		for _, f := range args[0].Object.Fields {
+			for i, name := range names {
+				if f.Key != name {
+					continue
+				}
+				v, ok := xScalarString(f.Value)
+				if !ok {
+					return nil, mysqlx.NewError(mysqlx.ErrXCmdArgumentType, "Invalid type for argument '%s'", name)
+				}
+				values[i], set[i] = v, true
+			}
+		}
+	} else {
+		if len(args) > len(names) {
+			return nil, mysqlx.NewError(mysqlx.ErrXCmdNumArguments, "Invalid number of arguments, expected %d but got %d", len(names), len(args))
+		}
+		for i, arg := range args {
+			v, ok := xScalarString(arg)
+			if !ok {
+				return nil, mysqlx.NewError(mysqlx.ErrXCmdArgumentType, "Invalid type for argument '%s'", names[i])
+			}
+			values[i], set[i] = v, true
+		}
+	}
+	for i := 0; i < required; i++ {
+		if !set[i] {
+			return nil, mysqlx.NewError(mysqlx.ErrXCmdNumArguments, "Missing value for argument '%s'", names[i])
+		}
+	}
+	return values, nil
+}
+
+// handleAdminCommand handles the admin commands of the mysqlx namespace, which manage the collections.
+func (xc *xClientConn) handleAdminCommand(ctx context.Context, cmd string, args []*mysqlx.Any) error {
+	switch cmd {
+	case "ping":
+		return xc.writeMessage(mysqlx.ServerSQLStmtExecuteOk, nil)
+	case "create_collection", "ensure_collection":
+		values, err := xAdminArgs(args, 2, "schema", "name")
+		if err != nil {
+			return err
+		}
+		if values[1] == "" {
+			return mysqlx.NewError(mysqlx.ErrXInvalidCollection, "Invalid collection name")
+		}
+		return xc.executeSQL(ctx, mysqlx.CreateCollectionSQL(values[0], values[1], cmd == "ensure_collection"), nil)
+	case "drop_collection":
+		values, err := xAdminArgs(args, 2, "schema", "name")
+		if err != nil {
+			return err
+		}
+		var b strings.Builder
+		if values[0] == "" {
+			sqlexec.MustFormatSQL(&b, "DROP TABLE %n", values[1])
+		} else {
+			sqlexec.MustFormatSQL(&b, "DROP TABLE %n.%n", values[0], values[1])
+		}
+		return xc.executeSQL(ctx, b.String(), nil)
+	case "list_objects":
+		values, err := xAdminArgs(args, 0, "schema", "pattern")
+		if err != nil {
+			return err
+		}
+		return xc.executeSQL(ctx, mysqlx.ListObjectsSQL(values[0], values[1]), nil)
+	}
+	return mysqlx.NewError(mysqlx.ErrXInvalidAdminCommand, "Invalid mysqlx command %s", cmd)
+}
@@ -0,0 +1,284 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/server/mysqlx"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/hack"
+)
+
+// The flags of the X Protocol column metadata.
+const (
+	xFlagUnsigned      = 0x0001
+	xFlagNotNull       = 0x0010
+	xFlagPrimaryKey    = 0x0020
+	xFlagUniqueKey     = 0x0040
+	xFlagMultipleKey   = 0x0080
+	xFlagAutoIncrement = 0x0100
+)
+
+// xColumnMetaData converts the column to the metadata of the X Protocol.
+func xColumnMetaData(col *ColumnInfo) *mysqlx.ColumnMetaData {
+	md := &mysqlx.ColumnMetaData{
+		Name:          col.Name,
+		OriginalName:  col.OrgName,
+		Table:         col.Table,
+		OriginalTable: col.OrgTable,
+		Schema:        col.Schema,
+		Catalog:       "def",
+		Length:        col.ColumnLength,
+	}
+	flag := uint(col.Flag)
+	for _, f := range []struct {
+		flag  uint
+		xFlag uint32
+	}{
+		{mysql.NotNullFlag, xFlagNotNull},
+		{mysql.PriKeyFlag, xFlagPrimaryKey},
+		{mysql.UniqueKeyFlag, xFlagUniqueKey},
+		{mysql.MultipleKeyFlag, xFlagMultipleKey},
+		{mysql.AutoIncrementFlag, xFlagAutoIncrement},
+	} {
+		if flag&f.flag > 0 {
+			md.Flags |= f.xFlag
+		}
+	}
+	switch col.Type {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		md.Type = mysqlx.ColumnSInt
+		if mysql.HasUnsignedFlag(flag) {
+			md.Type = mysqlx.ColumnUInt
+		}
+	case mysql.TypeYear:
+		md.Type = mysqlx.ColumnUInt
+	case mysql.TypeFloat, mysql.TypeDouble:
+		md.Type = mysqlx.ColumnDouble
+		if col.Type == mysql.TypeFloat {
+			md.Type = mysqlx.ColumnFloat
+		}
+		md.FractionalDigits = uint32(col.Decimal)
+		if mysql.HasUnsignedFlag(flag) {
+			md.Flags |= xFlagUnsigned
+		}
+	case mysql.TypeNewDecimal:
+		md.Type = mysqlx.ColumnDecimal
+		md.FractionalDigits = uint32(col.Decimal)
+		if mysql.HasUnsignedFlag(flag) {
+			md.Flags |= xFlagUnsigned
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		md.Type = mysqlx.ColumnDatetime
+		md.FractionalDigits = uint32(col.Decimal)
+	case mysql.TypeDuration:
+		md.Type = mysqlx.ColumnTime
+		md.FractionalDigits = uint32(col.Decimal)
+	case mysql.TypeEnum:
+		md.Type = mysqlx.ColumnEnum
+		md.Collation = uint64(col.Charset)
+	case mysql.TypeBit:
+		md.Type = mysqlx.ColumnBit
+	default:
+		// The strings, the sets, the JSONs and the others are sent as bytes.
+		md.Type = mysqlx.ColumnBytes
+		md.Collation = uint64(col.Charset)
+		if col.Type == mysql.TypeJSON {
+			md.ContentType = mysqlx.ContentTypeJSON
+		}
+	}
+	return md
+}
+
+// appendXUvarint appends the varint, which is used by the integers, the datetimes and the times.
+func appendXUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buf, tmp[:binary.PutUvarint(tmp[:], v)]...)
+}
+
+// appendXDecimal appends the scale and the packed BCD digits which end with the sign 0xc or 0xd.
+func appendXDecimal(buf []byte, s string) []byte {
+	sign := byte(0xc)
+	if strings.HasPrefix(s, "-") {
+		sign, s = 0xd, s[1:]
+	}
+	scale := 0
+	if pos := strings.IndexByte(s, '.'); pos >= 0 {
+		scale = len(s) - pos - 1
+		s = s[:pos] + s[pos+1:]
+	}
+	buf = append(buf, byte(scale))
+	for i := 0; i < len(s); i += 2 {
+		if i+1 < len(s) {
+			buf = append(buf, (s[i]-'0')<<4|(s[i+1]-'0'))
+		} else {
+			buf = append(buf, (s[i]-'0')<<4|sign)
+			return buf
+		}
+	}
+	return append(buf, sign<<4)
+}
+
+// xRowFields encodes the row of the result set in the X Protocol format. A NULL is an empty field.
+func xRowFields(columns []*ColumnInfo, metas []*mysqlx.ColumnMetaData, row chunk.Row) [][]byte {
+	fields := make([][]byte, len(columns))
+	for i, col := range columns {
+		if row.IsNull(i) {
+			fields[i] = []byte{}
+			continue
+		}
+		var buf []byte
+		switch metas[i].Type {
+		case mysqlx.ColumnSInt:
+			v := row.GetInt64(i)
+			buf = appendXUvarint(buf, uint64(v<<1)^uint64(v>>63))
+		case mysqlx.ColumnUInt:
+			buf = appendXUvarint(buf, row.GetUint64(i))
+		case mysqlx.ColumnFloat:
+			buf = make([]byte, 4)
+			binary.LittleEndian.PutUint32(buf, math.Float32bits(row.GetFloat32(i)))
+		case mysqlx.ColumnDouble:
+			buf = make([]byte, 8)
+			binary.LittleEndian.PutUint64(buf, math.Float64bits(row.GetFloat64(i)))
+		case mysqlx.ColumnDecimal:
+			buf = appendXDecimal(buf, row.GetMyDecimal(i).String())
+		case mysqlx.ColumnDatetime:
+			t := row.GetTime(i)
+			for _, v := range []int{t.Year(), t.Month(), t.Day()} {
+				buf = appendXUvarint(buf, uint64(v))
+			}
+			if col.Type != mysql.TypeDate {
+				for _, v := range []int{t.Hour(), t.Minute(), t.Second(), t.Microsecond()} {
+					buf = appendXUvarint(buf, uint64(v))
+				}
+			}
+		case mysqlx.ColumnTime:
+			d := row.GetDuration(i, int(col.Decimal)).Duration
+			buf = append(buf, 0)
+			if d < 0 {
+				buf[0], d = 1, -d
+			}
+			buf = appendXUvarint(buf, uint64(d/time.Hour))
+			buf = appendXUvarint(buf, uint64(d%time.Hour/time.Minute))
+			buf = appendXUvarint(buf, uint64(d%time.Minute/time.Second))
+			buf = appendXUvarint(buf, uint64(d%time.Second/time.Microsecond))
+		case mysqlx.ColumnBit:
+			var v uint64
+			for _, b := range row.GetBytes(i) {
+				v = v<<8 | uint64(b)
+			}
+			buf = appendXUvarint(buf, v)
+		default:
+			// The bytes end with 0x00, so an empty string is different from NULL.
+			var data []byte
+			switch col.Type {
+			case mysql.TypeEnum:
+				data = hack.Slice(row.GetEnum(i).String())
+			case mysql.TypeSet:
+				data = hack.Slice(row.GetSet(i).String())
+			case mysql.TypeJSON:
+				data = hack.Slice(row.GetJSON(i).String())
+			default:
+				data = row.GetBytes(i)
+			}
+			buf = append(append(make([]byte, 0, len(data)+1), data...), 0)
+		}
+		fields[i] = buf
+	}
+	return fields
+}
+
+// writeResultset writes the column metadata and the rows of the result set, which ends with FetchDone.
+func (xc *xClientConn) writeResultset(ctx context.Context, rs ResultSet) error {
+	req := rs.NewChunk(xc.chunkAlloc)
+	var columns []*ColumnInfo
+	var metas []*mysqlx.ColumnMetaData
+	for {
+		if err := rs.Next(ctx, req); err != nil {
+			return err
+		}
+		if columns == nil {
+			// The columns are correct after calling Next.
+			columns = rs.Columns()
+			metas = make([]*mysqlx.ColumnMetaData, len(columns))
+			for i, col := range columns {
+				metas[i] = xColumnMetaData(col)
+				if err := xc.writeMessage(mysqlx.ServerResultsetColumnMetaData, metas[i]); err != nil {
+					return err
+				}
+			}
+		}
+		if req.NumRows() == 0 {
+			break
+		}
+		egressBytes := 0
+		for i := 0; i < req.NumRows(); i++ {
+			row := &mysqlx.Row{Fields: xRowFields(columns, metas, req.GetRow(i))}
+			if err := xc.writeMessage(mysqlx.ServerResultsetRow, row); err != nil {
+				return err
+			}
+			for _, field := range row.Fields {
+				egressBytes += len(field)
+			}
+		}
+		if err := xc.throttleEgress(ctx, egressBytes); err != nil {
+			return err
+		}
+	}
+	return xc.writeMessage(mysqlx.ServerResultsetFetchDone, nil)
+}
+
+// writeStateChanged writes the notice of the changed session state.
+func (xc *xClientConn) writeStateChanged(param uint32, values ...*mysqlx.Scalar) error {
+	payload := (&mysqlx.SessionStateChanged{Param: param, Values: values}).Marshal()
+	return xc.writeMessage(mysqlx.ServerNotice, &mysqlx.Frame{
+		Type:    mysqlx.NoticeSessionStateChanged,
+		Scope:   mysqlx.NoticeScopeLocal,
+		Payload: payload,
+	})
+}
+
+// writeWarnings writes the warnings of the last statement as notices.
+func (xc *xClientConn) writeWarnings() error {
+	for _, w := range xc.ctx.GetWarnings() {
+		level := uint32(mysqlx.WarningLevelWarning)
+		switch w.Level {
+		case "Note":
+			level = mysqlx.WarningLevelNote
+		case "Error":
+			level = mysqlx.WarningLevelError
+		}
+		code := uint32(mysql.ErrUnknown)
+		if te, ok := errors.Cause(w.Err).(interface{ Code() errors.ErrCode }); ok {
+			code = uint32(te.Code())
+		}
+		payload := (&mysqlx.Warning{Level: level, Code: code, Msg: w.Err.Error()}).Marshal()
+		if err := xc.writeMessage(mysqlx.ServerNotice, &mysqlx.Frame{
+			Type:    mysqlx.NoticeWarning,
+			Scope:   mysqlx.NoticeScopeLocal,
+			Payload: payload,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
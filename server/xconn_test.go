@@ -0,0 +1,340 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/server/mysqlx"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+type xTestClient struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+func (c *xTestClient) send(tp byte, msg mysqlx.Message) {
+	require.NoError(c.t, mysqlx.WriteMessage(c.conn, tp, msg))
+}
+
+func (c *xTestClient) recv(expected byte, msg mysqlx.Message) {
+	tp, payload, err := mysqlx.ReadMessage(c.conn)
+	require.NoError(c.t, err)
+	if tp == mysqlx.ServerError && expected != mysqlx.ServerError {
+		var e mysqlx.ErrorMsg
+		require.NoError(c.t, e.Unmarshal(payload))
+		require.FailNow(c.t, "unexpected error", "%d: %s", e.Code, e.Msg)
+	}
+	require.Equal(c.t, expected, tp)
+	if msg != nil {
+		require.NoError(c.t, msg.Unmarshal(payload))
+	}
+}
+
+func (c *xTestClient) recvError(code uint16) {
+	var e mysqlx.ErrorMsg
+	c.recv(mysqlx.ServerError, &e)
+	require.Equal(c.t, uint32(code), e.Code, e.Msg)
+}
+
+// recvResult reads the messages until StmtExecuteOk, it returns the rows and the session state changes.
+func (c *xTestClient) recvResult() (columns []*mysqlx.ColumnMetaData, rows [][][]byte, states map[uint32][]*mysqlx.Scalar) {
+	states = make(map[uint32][]*mysqlx.Scalar)
+	for {
+		tp, payload, err := mysqlx.ReadMessage(c.conn)
+		require.NoError(c.t, err)
+		switch tp {
+		case mysqlx.ServerResultsetColumnMetaData:
+			col := &mysqlx.ColumnMetaData{}
+			require.NoError(c.t, col.Unmarshal(payload))
+			columns = append(columns, col)
+		case mysqlx.ServerResultsetRow:
+			var row mysqlx.Row
+			require.NoError(c.t, row.Unmarshal(payload))
+			rows = append(rows, row.Fields)
+		case mysqlx.ServerNotice:
+			var frame mysqlx.Frame
+			require.NoError(c.t, frame.Unmarshal(payload))
+			if frame.Type == mysqlx.NoticeSessionStateChanged {
+				var state mysqlx.SessionStateChanged
+				require.NoError(c.t, state.Unmarshal(frame.Payload))
+				states[state.Param] = state.Values
+			}
+		case mysqlx.ServerResultsetFetchDone:
+		case mysqlx.ServerSQLStmtExecuteOk:
+			return
+		default:
+			var e mysqlx.ErrorMsg
+			require.NoError(c.t, e.Unmarshal(payload))
+			require.FailNow(c.t, "unexpected message", "type %d, error %d: %s", tp, e.Code, e.Msg)
+		}
+	}
+}
+
+func (c *xTestClient) exec(sql string, args ...*mysqlx.Any) ([][][]byte, map[uint32][]*mysqlx.Scalar) {
+	c.send(mysqlx.ClientSQLStmtExecute, &mysqlx.StmtExecute{Namespace: "sql", Stmt: []byte(sql), Args: args})
+	_, rows, states := c.recvResult()
+	return rows, states
+}
+
+func (c *xTestClient) authMySQL41(schema, user, password string) {
+	c.send(mysqlx.ClientSessAuthenticateStart, &mysqlx.AuthenticateStart{MechName: "MYSQL41"})
+	var salt mysqlx.AuthData
+	c.recv(mysqlx.ServerSessAuthenticateContinue, &salt)
+	data := schema + "\x00" + user + "\x00"
+	if password != "" {
+		data += "*" + hex.EncodeToString(scramblePassword(salt.AuthData, password))
+	}
+	c.send(mysqlx.ClientSessAuthenticateContinue, &mysqlx.AuthData{AuthData: []byte(data)})
+}
+
+func xStr(s string) *mysqlx.Any {
+	return mysqlx.NewScalarAny(&mysqlx.Scalar{Type: mysqlx.ScalarString, String: []byte(s)})
+}
+
+func TestXProtocol(t *testing.T) {
+	store, err := mockstore.NewMockStore()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, store.Close())
+	}()
+	session.DisableStats4Test()
+	dom, err := session.BootstrapSession(store)
+	require.NoError(t, err)
+	defer dom.Close()
+
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Status.ReportStatus = false
+	cfg.XProtocol.Enable = true
+	cfg.XProtocol.Port = 0
+	server, err := NewServer(cfg, NewTiDBDriver(store))
+	require.NoError(t, err)
+	go func() {
+		require.NoError(t, server.Run())
+	}()
+	defer server.Close()
+
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", cfg.XProtocol.Port), 5*time.Second)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+	c := &xTestClient{t: t, conn: conn}
+
+	// Negotiate the capabilities.
+	c.send(mysqlx.ClientConCapabilitiesGet, nil)
+	var caps mysqlx.Capabilities
+	c.recv(mysqlx.ServerConnCapabilities, &caps)
+	names := make(map[string]*mysqlx.Any)
+	for _, capability := range caps.Capabilities {
+		names[capability.Name] = capability.Value
+	}
+	require.Contains(t, names, "authentication.mechanisms")
+	require.NotContains(t, names, "tls")
+	require.Equal(t, "MYSQL41", string(names["authentication.mechanisms"].Array.Values[0].Scalar.String))
+	c.send(mysqlx.ClientConCapabilitiesSet, &mysqlx.CapabilitiesSet{Capabilities: &mysqlx.Capabilities{Capabilities: []*mysqlx.Capability{
+		{Name: "foo", Value: xStr("bar")},
+	}}})
+	c.recvError(mysqlx.ErrXCapabilityNotFound)
+	c.send(mysqlx.ClientConCapabilitiesSet, &mysqlx.CapabilitiesSet{Capabilities: &mysqlx.Capabilities{Capabilities: []*mysqlx.Capability{
+		{Name: "session_connect_attrs", Value: &mysqlx.Any{Type: mysqlx.AnyObject, Object: &mysqlx.Object{Fields: []*mysqlx.ObjectField{
+			{Key: "_client_name", Value: xStr("test")},
+		}}}},
+	}}})
+	c.recv(mysqlx.ServerOk, nil)
+
+	// The statements can't be executed before the authentication.
+	c.send(mysqlx.ClientSQLStmtExecute, &mysqlx.StmtExecute{Stmt: []byte("select 1")})
+	c.recvError(mysqlx.ErrUnknownComError)
+	c.authMySQL41("", "root", "wrong")
+	c.recvError(mysqlx.ErrAccessDeniedError)
+	c.send(mysqlx.ClientSessAuthenticateStart, &mysqlx.AuthenticateStart{MechName: "PLAIN", AuthData: []byte("\x00root\x00")})
+	c.recvError(mysqlx.ErrAccessDeniedError)
+	c.authMySQL41("test", "root", "")
+	var notice mysqlx.Frame
+	c.recv(mysqlx.ServerNotice, &notice)
+	var state mysqlx.SessionStateChanged
+	require.NoError(t, state.Unmarshal(notice.Payload))
+	require.Equal(t, uint32(mysqlx.StateClientIDAssigned), state.Param)
+	connID := state.Values[0].UInt
+	c.recv(mysqlx.ServerSessAuthenticateOk, nil)
+	require.Contains(t, server.ShowProcessList(), connID)
+
+	// Execute the SQL statements.
+	rows, _ := c.exec("select ?, -2, 1.50, null, 'a', cast('2022-01-02 03:04:05' as datetime)", xStr("x"))
+	require.Len(t, rows, 1)
+	require.Equal(t, []byte("x\x00"), rows[0][0])
+	require.Equal(t, []byte{3}, rows[0][1])
+	require.Equal(t, []byte{2, 0x15, 0x0c}, rows[0][2])
+	require.Equal(t, []byte{}, rows[0][3])
+	require.Equal(t, []byte("a\x00"), rows[0][4])
+	require.Equal(t, []byte{0xe6, 0x0f, 1, 2, 3, 4, 5, 0}, rows[0][5])
+	_, states := c.exec("create table t (id int primary key auto_increment, v int)")
+	require.Equal(t, uint64(0), states[mysqlx.StateRowsAffected][0].UInt)
+	_, states = c.exec("insert into t (v) values (1), (2)")
+	require.Equal(t, uint64(2), states[mysqlx.StateRowsAffected][0].UInt)
+	require.Equal(t, uint64(1), states[mysqlx.StateGeneratedInsertID][0].UInt)
+	c.send(mysqlx.ClientSQLStmtExecute, &mysqlx.StmtExecute{Stmt: []byte("select * from no_such_table")})
+	c.recvError(mysql.ErrNoSuchTable)
+
+	// Manage the collections.
+	admin := func(cmd string, args ...*mysqlx.ObjectField) ([][][]byte, map[uint32][]*mysqlx.Scalar) {
+		c.send(mysqlx.ClientSQLStmtExecute, &mysqlx.StmtExecute{Namespace: "mysqlx", Stmt: []byte(cmd), Args: []*mysqlx.Any{
+			{Type: mysqlx.AnyObject, Object: &mysqlx.Object{Fields: args}},
+		}})
+		_, rows, states := c.recvResult()
+		return rows, states
+	}
+	admin("create_collection", &mysqlx.ObjectField{Key: "schema", Value: xStr("test")}, &mysqlx.ObjectField{Key: "name", Value: xStr("c")})
+	admin("ensure_collection", &mysqlx.ObjectField{Key: "schema", Value: xStr("test")}, &mysqlx.ObjectField{Key: "name", Value: xStr("c")})
+	c.send(mysqlx.ClientSQLStmtExecute, &mysqlx.StmtExecute{Namespace: "mysqlx", Stmt: []byte("create_collection"), Args: []*mysqlx.Any{xStr("test")}})
+	c.recvError(mysqlx.ErrXCmdNumArguments)
+	c.send(mysqlx.ClientSQLStmtExecute, &mysqlx.StmtExecute{Namespace: "mysqlx", Stmt: []byte("foo")})
+	c.recvError(mysqlx.ErrXInvalidAdminCommand)
+	rows, _ = admin("list_objects", &mysqlx.ObjectField{Key: "schema", Value: xStr("test")})
+	require.Equal(t, [][][]byte{{[]byte("c\x00"), []byte("COLLECTION\x00")}, {[]byte("t\x00"), []byte("TABLE\x00")}}, rows)
+
+	// CRUD on the collection.
+	collection := &mysqlx.Collection{Schema: "test", Name: "c"}
+	doc := func(id string, v int64) []*mysqlx.Expr {
+		fields := []*mysqlx.ObjectExprField{{Key: "v", Value: &mysqlx.Expr{Type: mysqlx.ExprLiteral, Literal: &mysqlx.Scalar{Type: mysqlx.ScalarSInt, SInt: v}}}}
+		if id != "" {
+			fields = append(fields, &mysqlx.ObjectExprField{Key: "_id", Value: &mysqlx.Expr{Type: mysqlx.ExprLiteral, Literal: &mysqlx.Scalar{Type: mysqlx.ScalarString, String: []byte(id)}}})
+		}
+		return []*mysqlx.Expr{{Type: mysqlx.ExprObject, Object: &mysqlx.ObjectExpr{Fields: fields}}}
+	}
+	c.send(mysqlx.ClientCrudInsert, &mysqlx.Insert{Collection: collection, DataModel: mysqlx.DataModelDocument, Rows: [][]*mysqlx.Expr{doc("1", 1), doc("", 2)}})
+	_, _, states = c.recvResult()
+	require.Equal(t, uint64(2), states[mysqlx.StateRowsAffected][0].UInt)
+	require.Len(t, states[mysqlx.StateGeneratedDocumentIDs], 1)
+	generatedID := string(states[mysqlx.StateGeneratedDocumentIDs][0].Octets)
+	require.Len(t, generatedID, 32)
+
+	vGreater := func(v int64) *mysqlx.Expr {
+		return &mysqlx.Expr{Type: mysqlx.ExprOperator, Operator: &mysqlx.Operator{Name: ">", Params: []*mysqlx.Expr{
+			{Type: mysqlx.ExprIdent, Identifier: &mysqlx.ColumnIdentifier{DocumentPath: []*mysqlx.DocumentPathItem{{Type: mysqlx.PathMember, Value: "v"}}}},
+			{Type: mysqlx.ExprLiteral, Literal: &mysqlx.Scalar{Type: mysqlx.ScalarSInt, SInt: v}},
+		}}}
+	}
+	c.send(mysqlx.ClientCrudFind, &mysqlx.Find{Collection: collection, DataModel: mysqlx.DataModelDocument, Criteria: vGreater(1)})
+	columns, rows, _ := c.recvResult()
+	require.Len(t, columns, 1)
+	require.Equal(t, uint32(mysqlx.ColumnBytes), columns[0].Type)
+	require.Equal(t, uint32(mysqlx.ContentTypeJSON), columns[0].ContentType)
+	require.Equal(t, [][][]byte{{[]byte(fmt.Sprintf(`{"_id": "%s", "v": 2}`, generatedID) + "\x00")}}, rows)
+
+	c.send(mysqlx.ClientCrudUpdate, &mysqlx.Update{Collection: collection, DataModel: mysqlx.DataModelDocument, Criteria: vGreater(1),
+		Operations: []*mysqlx.UpdateOperation{{
+			Source:    &mysqlx.ColumnIdentifier{DocumentPath: []*mysqlx.DocumentPathItem{{Type: mysqlx.PathMember, Value: "v"}}},
+			Operation: mysqlx.UpdateItemSet,
+			Value:     &mysqlx.Expr{Type: mysqlx.ExprLiteral, Literal: &mysqlx.Scalar{Type: mysqlx.ScalarSInt, SInt: 3}},
+		}},
+	})
+	_, _, states = c.recvResult()
+	require.Equal(t, uint64(1), states[mysqlx.StateRowsAffected][0].UInt)
+	c.send(mysqlx.ClientCrudDelete, &mysqlx.Delete{Collection: collection, DataModel: mysqlx.DataModelDocument, Criteria: vGreater(2)})
+	_, _, states = c.recvResult()
+	require.Equal(t, uint64(1), states[mysqlx.StateRowsAffected][0].UInt)
+	rows, _ = c.exec("select json_extract(doc, '$.v') from test.c")
+	require.Equal(t, [][][]byte{{[]byte("1\x00")}}, rows)
+
+	// The messages fail after an error in the expectation block of no_error.
+	c.send(mysqlx.ClientExpectOpen, &mysqlx.ExpectOpen{Op: mysqlx.ExpectCtxEmpty, Conds: []*mysqlx.ExpectCondition{{Key: 2, Value: []byte("99.1")}}})
+	c.recvError(mysqlx.ErrXExpectFieldExists)
+	c.send(mysqlx.ClientExpectClose, nil)
+	c.recv(mysqlx.ServerOk, nil)
+	c.send(mysqlx.ClientExpectOpen, &mysqlx.ExpectOpen{Op: mysqlx.ExpectCtxEmpty, Conds: []*mysqlx.ExpectCondition{{Key: 1}}})
+	c.recv(mysqlx.ServerOk, nil)
+	c.send(mysqlx.ClientSQLStmtExecute, &mysqlx.StmtExecute{Stmt: []byte("select * from no_such_table")})
+	c.recvError(mysql.ErrNoSuchTable)
+	c.send(mysqlx.ClientSQLStmtExecute, &mysqlx.StmtExecute{Stmt: []byte("select 1")})
+	c.recvError(mysqlx.ErrXExpectFailed)
+	c.send(mysqlx.ClientExpectClose, nil)
+	c.recvError(mysqlx.ErrXExpectFailed)
+	c.send(mysqlx.ClientExpectClose, nil)
+	c.recvError(mysqlx.ErrXExpectNotOpen)
+
+	// Reset the session and authenticate again.
+	c.exec("set @a = 1")
+	c.send(mysqlx.ClientSessReset, &mysqlx.Reset{KeepOpen: true})
+	c.recv(mysqlx.ServerOk, nil)
+	rows, _ = c.exec("select @a, database()")
+	require.Equal(t, [][][]byte{{{}, []byte("test\x00")}}, rows)
+	c.send(mysqlx.ClientSessReset, &mysqlx.Reset{})
+	c.recv(mysqlx.ServerOk, nil)
+	require.NotContains(t, server.ShowProcessList(), connID)
+	c.send(mysqlx.ClientSQLStmtExecute, &mysqlx.StmtExecute{Stmt: []byte("select 1")})
+	c.recvError(mysqlx.ErrUnknownComError)
+	c.authMySQL41("", "root", "")
+	c.recv(mysqlx.ServerNotice, nil)
+	c.recv(mysqlx.ServerSessAuthenticateOk, nil)
+
+	c.send(mysqlx.ClientConClose, nil)
+	c.recv(mysqlx.ServerOk, nil)
+	_, _, err = mysqlx.ReadMessage(conn)
+	require.Error(t, err)
+}
+
+func TestXRowFields(t *testing.T) {
+	columns := []*ColumnInfo{
+		{Type: mysql.TypeLonglong, Flag: uint16(mysql.UnsignedFlag | mysql.PriKeyFlag)},
+		{Type: mysql.TypeDouble},
+		{Type: mysql.TypeNewDecimal, Decimal: 2},
+		{Type: mysql.TypeDate},
+		{Type: mysql.TypeDuration},
+		{Type: mysql.TypeBit},
+	}
+	metas := make([]*mysqlx.ColumnMetaData, len(columns))
+	fieldTypes := make([]*types.FieldType, len(columns))
+	for i, col := range columns {
+		metas[i] = xColumnMetaData(col)
+		fieldTypes[i] = types.NewFieldType(col.Type)
+	}
+	require.Equal(t, uint32(mysqlx.ColumnUInt), metas[0].Type)
+	require.Equal(t, uint32(xFlagPrimaryKey), metas[0].Flags)
+	require.Equal(t, uint32(mysqlx.ColumnDecimal), metas[2].Type)
+
+	chk := chunk.NewChunkWithCapacity(fieldTypes, 1)
+	chk.AppendUint64(0, 300)
+	chk.AppendFloat64(1, 0.5)
+	chk.AppendMyDecimal(2, types.NewDecFromStringForTest("-12.34"))
+	chk.AppendTime(3, types.NewTime(types.FromDate(2022, 12, 31, 0, 0, 0, 0), mysql.TypeDate, 0))
+	chk.AppendDuration(4, types.Duration{Duration: -(26*time.Hour + 3*time.Minute + 4*time.Second)})
+	chk.AppendBytes(5, []byte{1, 2})
+	fields := xRowFields(columns, metas, chk.GetRow(0))
+
+	double := make([]byte, 8)
+	binary.LittleEndian.PutUint64(double, 0x3fe0000000000000)
+	require.Equal(t, [][]byte{
+		{0xac, 0x02},
+		double,
+		{2, 0x12, 0x34, 0xd0},
+		{0xe6, 0x0f, 12, 31},
+		{1, 26, 3, 4, 0},
+		{0x82, 0x02},
+	}, fields)
+}
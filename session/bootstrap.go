@@ -91,6 +91,15 @@ const (
 		Create_Tablespace_Priv  ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_slave_priv	    	ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_client_priv		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		Resource_group			VARCHAR(64) NOT NULL DEFAULT '',
+		max_user_connections	INT UNSIGNED NOT NULL DEFAULT 0,
+		Password_reuse_history	SMALLINT UNSIGNED DEFAULT NULL,
+		Password_reuse_time		SMALLINT UNSIGNED DEFAULT NULL,
+		Password_expired		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		Password_lifetime		SMALLINT UNSIGNED DEFAULT NULL,
+		Failed_login_attempts	SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+		Password_lock_time		SMALLINT NOT NULL DEFAULT 0,
+		Password_last_changed	TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		PRIMARY KEY (Host, User));`
 	// CreateGlobalPrivTable is the SQL statement creates Global scope privilege table in system db.
 	CreateGlobalPrivTable = "CREATE TABLE IF NOT EXISTS mysql.global_priv (" +
@@ -331,6 +340,7 @@ const (
 		QUERY_COUNT bigint(64),
 		ROWS_SELECTED bigint(64),
 		LAST_USED_AT timestamp,
+		ROWS_WRITTEN bigint(64) NOT NULL DEFAULT 0,
 		PRIMARY KEY(TABLE_ID, INDEX_ID)
 	);`
 	// CreateGlobalGrantsTable stores dynamic privs
@@ -365,6 +375,94 @@ const (
 		oldReadLease bigint(20) NOT NULL DEFAULT 0,
 		PRIMARY KEY (tid)
 	);`
+	// CreateLoadDataProgressTable stores the progress of the batched LOAD DATA statements, which is used to resume an interrupted load.
+	CreateLoadDataProgressTable = `CREATE TABLE IF NOT EXISTS mysql.load_data_progress (
+		table_id BIGINT(64) NOT NULL,
+		path_digest VARBINARY(32) NOT NULL,
+		path VARCHAR(4096) NOT NULL,
+		checksum BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		line_count BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		record_count BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		skipped_count BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		warning_count BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (table_id, path_digest) CLUSTERED
+	);`
+	// CreateUserFunctionsTable stores the user-defined functions created by CREATE FUNCTION.
+	CreateUserFunctionsTable = `CREATE TABLE IF NOT EXISTS mysql.user_functions (
+		name VARCHAR(64) NOT NULL,
+		language VARCHAR(16) NOT NULL,
+		param_types TEXT NOT NULL,
+		return_type TEXT NOT NULL,
+		body LONGBLOB NOT NULL,
+		create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (name)
+	);`
+	// CreateStatsTableLockedTable stores the tables whose statistics are locked by LOCK STATS.
+	CreateStatsTableLockedTable = `CREATE TABLE IF NOT EXISTS mysql.stats_table_locked (
+		table_id BIGINT(64) NOT NULL,
+		lock_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (table_id)
+	);`
+	// CreateAnalyzeJobsTable stores the analyze jobs and their progress.
+	CreateAnalyzeJobsTable = `CREATE TABLE IF NOT EXISTS mysql.analyze_jobs (
+		id BIGINT(64) UNSIGNED NOT NULL AUTO_INCREMENT,
+		update_time DATETIME ON UPDATE CURRENT_TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		table_schema CHAR(64) NOT NULL DEFAULT '',
+		table_name CHAR(64) NOT NULL DEFAULT '',
+		partition_name CHAR(64) NOT NULL DEFAULT '',
+		job_info TEXT NOT NULL,
+		processed_rows BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		total_rows BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		phase CHAR(16) NOT NULL DEFAULT '',
+		start_time DATETIME,
+		end_time DATETIME,
+		state ENUM('pending', 'running', 'finished', 'failed') NOT NULL,
+		fail_reason TEXT,
+		instance VARCHAR(512) NOT NULL,
+		process_id BIGINT(64) UNSIGNED,
+		PRIMARY KEY (id),
+		KEY (update_time)
+	);`
+
+	// CreateRunawayWatchTable stores the runaway queries, which exceed the QUERY_LIMIT of their resource groups.
+	CreateRunawayWatchTable = `CREATE TABLE IF NOT EXISTS mysql.runaway_watch (
+		resource_group_name VARCHAR(64) NOT NULL,
+		user VARCHAR(32) NOT NULL DEFAULT '',
+		host VARCHAR(255) NOT NULL DEFAULT '',
+		sql_digest VARCHAR(64) NOT NULL DEFAULT '',
+		sql_text TEXT,
+		exec_elapsed_ms BIGINT(64) UNSIGNED NOT NULL,
+		action VARCHAR(12) NOT NULL,
+		start_time DATETIME(6) NOT NULL,
+		watch_time DATETIME(6) NOT NULL,
+		KEY (resource_group_name),
+		KEY (watch_time)
+	);`
+	// CreateProxiesPrivTable stores the PROXY privileges, which allow the users to act as other users.
+	CreateProxiesPrivTable = `CREATE TABLE IF NOT EXISTS mysql.proxies_priv (
+		Host CHAR(255) NOT NULL DEFAULT '',
+		User CHAR(32) NOT NULL DEFAULT '',
+		Proxied_host CHAR(255) NOT NULL DEFAULT '',
+		Proxied_user CHAR(32) NOT NULL DEFAULT '',
+		With_grant ENUM('N','Y') NOT NULL DEFAULT 'N',
+		PRIMARY KEY (Host, User, Proxied_host, Proxied_user)
+	);`
+	// CreatePasswordHistoryTable stores the previous passwords of the users, which can't be reused.
+	CreatePasswordHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.password_history (
+		Host CHAR(255) NOT NULL DEFAULT '',
+		User CHAR(32) NOT NULL DEFAULT '',
+		Password_timestamp TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		Password TEXT,
+		PRIMARY KEY (Host, User, Password_timestamp)
+	);`
+	// CreateInspectionThresholdsTable stores the thresholds of the inspection items, which override the default ones.
+	CreateInspectionThresholdsTable = `CREATE TABLE IF NOT EXISTS mysql.inspection_thresholds (
+		rule VARCHAR(64) NOT NULL,
+		item VARCHAR(64) NOT NULL,
+		threshold DOUBLE NOT NULL,
+		PRIMARY KEY (rule, item)
+	);`
 )
 
 // bootstrap initiates system DB for a store.
@@ -541,11 +639,33 @@ const (
 	// version80 fixes the issue https://github.com/pingcap/tidb/issues/25422.
 	// If the TiDB upgrading from the 4.x to a newer version, we keep the tidb_analyze_version to 1.
 	version80 = 80
+	// version81 adds the mysql.load_data_progress table
+	version81 = 81
+	// version82 adds the mysql.user_functions table
+	version82 = 82
+	// version83 adds the mysql.stats_table_locked table
+	version83 = 83
+	// version84 adds the mysql.analyze_jobs table
+	version84 = 84
+	// version85 adds the Resource_group column to mysql.user
+	version85 = 85
+	// version86 adds the mysql.runaway_watch table
+	version86 = 86
+	// version87 adds the max_user_connections column to mysql.user
+	version87 = 87
+	// version88 adds the mysql.proxies_priv table
+	version88 = 88
+	// version89 adds the password management columns to mysql.user and the mysql.password_history table
+	version89 = 89
+	// version90 adds the mysql.inspection_thresholds table
+	version90 = 90
+	// version91 adds the ROWS_WRITTEN column to mysql.schema_index_usage
+	version91 = 91
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version80
+var currentBootstrapVersion int64 = version91
 
 var (
 	bootstrapVersion = []func(Session, int64){
@@ -629,6 +749,17 @@ var (
 		upgradeToVer78,
 		upgradeToVer79,
 		upgradeToVer80,
+		upgradeToVer81,
+		upgradeToVer82,
+		upgradeToVer83,
+		upgradeToVer84,
+		upgradeToVer85,
+		upgradeToVer86,
+		upgradeToVer87,
+		upgradeToVer88,
+		upgradeToVer89,
+		upgradeToVer90,
+		upgradeToVer91,
 	}
 )
 
@@ -1655,6 +1786,90 @@ func upgradeToVer80(s Session, ver int64) {
 		mysql.SystemDB, mysql.GlobalVariablesTable, variable.TiDBAnalyzeVersion, 1)
 }
 
+func upgradeToVer81(s Session, ver int64) {
+	if ver >= version81 {
+		return
+	}
+	doReentrantDDL(s, CreateLoadDataProgressTable)
+}
+
+func upgradeToVer82(s Session, ver int64) {
+	if ver >= version82 {
+		return
+	}
+	doReentrantDDL(s, CreateUserFunctionsTable)
+}
+
+func upgradeToVer83(s Session, ver int64) {
+	if ver >= version83 {
+		return
+	}
+	doReentrantDDL(s, CreateStatsTableLockedTable)
+}
+
+func upgradeToVer84(s Session, ver int64) {
+	if ver >= version84 {
+		return
+	}
+	doReentrantDDL(s, CreateAnalyzeJobsTable)
+}
+
+func upgradeToVer85(s Session, ver int64) {
+	if ver >= version85 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Resource_group` VARCHAR(64) NOT NULL DEFAULT ''", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer86(s Session, ver int64) {
+	if ver >= version86 {
+		return
+	}
+	doReentrantDDL(s, CreateRunawayWatchTable)
+}
+
+func upgradeToVer87(s Session, ver int64) {
+	if ver >= version87 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `max_user_connections` INT UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer88(s Session, ver int64) {
+	if ver >= version88 {
+		return
+	}
+	doReentrantDDL(s, CreateProxiesPrivTable)
+}
+
+func upgradeToVer89(s Session, ver int64) {
+	if ver >= version89 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_history` SMALLINT UNSIGNED DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_time` SMALLINT UNSIGNED DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_expired` ENUM('N','Y') NOT NULL DEFAULT 'N'", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_lifetime` SMALLINT UNSIGNED DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Failed_login_attempts` SMALLINT UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_lock_time` SMALLINT NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_last_changed` TIMESTAMP DEFAULT CURRENT_TIMESTAMP", infoschema.ErrColumnExists)
+	doReentrantDDL(s, CreatePasswordHistoryTable)
+}
+
+func upgradeToVer90(s Session, ver int64) {
+	if ver >= version90 {
+		return
+	}
+	doReentrantDDL(s, CreateInspectionThresholdsTable)
+}
+
+func upgradeToVer91(s Session, ver int64) {
+	if ver >= version91 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.schema_index_usage ADD COLUMN `ROWS_WRITTEN` bigint(64) NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -1739,6 +1954,22 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateColumnStatsUsageTable)
 	// Create table_cache_meta table.
 	mustExecute(s, CreateTableCacheMetaTable)
+	// Create load_data_progress table.
+	mustExecute(s, CreateLoadDataProgressTable)
+	// Create user_functions table.
+	mustExecute(s, CreateUserFunctionsTable)
+	// Create stats_table_locked table.
+	mustExecute(s, CreateStatsTableLockedTable)
+	// Create analyze_jobs table.
+	mustExecute(s, CreateAnalyzeJobsTable)
+	// Create runaway_watch table.
+	mustExecute(s, CreateRunawayWatchTable)
+	// Create proxies_priv table.
+	mustExecute(s, CreateProxiesPrivTable)
+	// Create password_history table.
+	mustExecute(s, CreatePasswordHistoryTable)
+	// Create inspection_thresholds table.
+	mustExecute(s, CreateInspectionThresholdsTable)
 }
 
 // doDMLWorks executes DML statements in bootstrap stage.
@@ -1755,10 +1986,10 @@ func doDMLWorks(s Session) {
 			logutil.BgLogger().Fatal("failed to read current user. unable to secure bootstrap.", zap.Error(err))
 		}
 		mustExecute(s, `INSERT HIGH_PRIORITY INTO mysql.user VALUES
-		("localhost", "root", %?, "auth_socket", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`, u.Username)
+		("localhost", "root", %?, "auth_socket", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "", 0, NULL, NULL, "N", NULL, 0, 0, CURRENT_TIMESTAMP())`, u.Username)
 	} else {
 		mustExecute(s, `INSERT HIGH_PRIORITY INTO mysql.user VALUES
-		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`)
+		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "", 0, NULL, NULL, "N", NULL, 0, 0, CURRENT_TIMESTAMP())`)
 	}
 
 	// Init global system variables table.
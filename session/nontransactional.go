@@ -0,0 +1,409 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/opcode"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// nonTransactionalJob is a part of the non-transactional DML, it processes the rows whose
+// shard column values are in [start, end]. Every job is executed in its own transaction.
+type nonTransactionalJob struct {
+	jobID   int
+	jobSize int
+	start   types.Datum
+	end     types.Datum
+}
+
+// nonTransactionalTarget is the table which is modified by the non-transactional DML.
+type nonTransactionalTarget struct {
+	tableRefs   *ast.TableRefsClause
+	where       ast.ExprNode
+	shardColumn *ast.ColumnName
+	columnInfo  *model.ColumnInfo
+}
+
+// HandleNonTransactionalDML splits the DML into many small jobs by the values of the shard column.
+// The rows to be modified are read at first, then they are split into jobs of about stmt.Limit rows,
+// the same shard column values are always in the same job. After that, the jobs are executed one
+// by one in auto-commit mode. It stops if any job fails, and the jobs done are not rolled back.
+func HandleNonTransactionalDML(ctx context.Context, stmt *ast.NonTransactionalDMLStmt, se Session) (sqlexec.RecordSet, error) {
+	if err := checkNonTransactionalDML(stmt, se); err != nil {
+		return nil, err
+	}
+	target, err := resolveNonTransactionalTarget(stmt, se)
+	if err != nil {
+		return nil, err
+	}
+	selectStmt, selectSQL, err := buildShardSelectStmt(target)
+	if err != nil {
+		return nil, err
+	}
+	maxChunkSize := se.GetSessionVars().MaxChunkSize
+	if stmt.DryRun == ast.DryRunQuery {
+		return buildNonTransactionalDryRunResult("query statement", []string{selectSQL}, maxChunkSize), nil
+	}
+
+	jobs, err := splitNonTransactionalJobs(ctx, se, selectStmt, target, stmt.Limit)
+	if err != nil {
+		return nil, err
+	}
+	// The where clause is replaced by the jobs, recover it after all.
+	defer setDMLWhere(stmt.DMLStmt, target.where)
+	if stmt.DryRun == ast.DryRunSplitDml {
+		sqls := make([]string, 0, 2)
+		for i, job := range jobs {
+			if i != 0 && i != len(jobs)-1 {
+				continue
+			}
+			setDMLWhere(stmt.DMLStmt, buildJobCondition(target, &job))
+			sql, err := restoreNode(stmt.DMLStmt)
+			if err != nil {
+				return nil, err
+			}
+			sqls = append(sqls, sql)
+		}
+		return buildNonTransactionalDryRunResult("split statement examples", sqls, maxChunkSize), nil
+	}
+
+	for i := range jobs {
+		if err := doNonTransactionalJob(ctx, se, stmt.DMLStmt, target, &jobs[i], len(jobs)); err != nil {
+			return nil, err
+		}
+	}
+	return &sqlexec.SimpleRecordSet{
+		ResultFields: []*ast.ResultField{
+			buildNonTransactionalResultField("number of jobs", mysql.TypeLonglong),
+			buildNonTransactionalResultField("job status", mysql.TypeVarchar),
+		},
+		Rows:         [][]interface{}{{len(jobs), "all succeeded"}},
+		MaxChunkSize: maxChunkSize,
+	}, nil
+}
+
+func checkNonTransactionalDML(stmt *ast.NonTransactionalDMLStmt, se Session) error {
+	sessVars := se.GetSessionVars()
+	if !sessVars.IsAutocommit() || sessVars.InTxn() {
+		return ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("it can only run in auto-commit mode")
+	}
+	if sessVars.SnapshotTS != 0 {
+		return ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("it can't run with tidb_snapshot")
+	}
+	if sessVars.BatchInsert || sessVars.BatchDelete || sessVars.BatchCommit {
+		return ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("it can't run with the batch DML variables")
+	}
+	if stmt.Limit == 0 {
+		return ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("the batch size must be positive")
+	}
+	switch x := stmt.DMLStmt.(type) {
+	case *ast.DeleteStmt:
+		if x.IsMultiTable {
+			return ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("multiple-table DELETE")
+		}
+		if x.Order != nil || x.Limit != nil {
+			return ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("ORDER BY or LIMIT clause")
+		}
+	case *ast.UpdateStmt:
+		if x.TableRefs.TableRefs.Right != nil {
+			return ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("multiple-table UPDATE")
+		}
+		if x.Order != nil || x.Limit != nil {
+			return ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("ORDER BY or LIMIT clause")
+		}
+	default:
+		return ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("only DELETE and UPDATE can be split")
+	}
+	return nil
+}
+
+// resolveNonTransactionalTarget finds the target table and the shard column. If the shard column
+// isn't specified, the handle column is used.
+func resolveNonTransactionalTarget(stmt *ast.NonTransactionalDMLStmt, se Session) (*nonTransactionalTarget, error) {
+	target := &nonTransactionalTarget{}
+	var assignments []*ast.Assignment
+	switch x := stmt.DMLStmt.(type) {
+	case *ast.DeleteStmt:
+		target.tableRefs, target.where = x.TableRefs, x.Where
+	case *ast.UpdateStmt:
+		target.tableRefs, target.where, assignments = x.TableRefs, x.Where, x.List
+	}
+	source, ok := target.tableRefs.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return nil, ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("the target must be a table")
+	}
+	tableName, ok := source.Source.(*ast.TableName)
+	if !ok {
+		return nil, ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("the target must be a table")
+	}
+	schema := tableName.Schema
+	if schema.L == "" {
+		schema = model.NewCIStr(se.GetSessionVars().CurrentDB)
+		// The table name is qualified, so the statements of the jobs are shown the same as executed.
+		tableName.Schema = schema
+	}
+	is := se.GetInfoSchema().(infoschema.InfoSchema)
+	tbl, err := is.TableByName(schema, tableName.Name)
+	if err != nil {
+		return nil, err
+	}
+	tblInfo := tbl.Meta()
+
+	if stmt.ShardColumn == nil {
+		switch {
+		case tblInfo.PKIsHandle:
+			target.columnInfo = tblInfo.GetPkColInfo()
+		case tblInfo.IsCommonHandle:
+			pk := tables.FindPrimaryIndex(tblInfo)
+			if len(pk.Columns) != 1 {
+				return nil, ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("the shard column must be specified for the table with composite clustered index")
+			}
+			target.columnInfo = tblInfo.Columns[pk.Columns[0].Offset]
+		default:
+			target.columnInfo = model.NewExtraHandleColInfo()
+		}
+		target.shardColumn = &ast.ColumnName{Name: target.columnInfo.Name}
+	} else {
+		target.shardColumn = stmt.ShardColumn
+		tableAsName := source.AsName
+		if tableAsName.L == "" {
+			tableAsName = tableName.Name
+		}
+		name := stmt.ShardColumn
+		if (name.Schema.L != "" && name.Schema.L != schema.L) || (name.Table.L != "" && name.Table.L != tableAsName.L) {
+			return nil, plannercore.ErrUnknownColumn.GenWithStackByArgs(name.OrigColName(), "field list")
+		}
+		if name.Name.L == model.ExtraHandleName.L && !tblInfo.PKIsHandle && !tblInfo.IsCommonHandle {
+			target.columnInfo = model.NewExtraHandleColInfo()
+		} else {
+			target.columnInfo = model.FindColumnInfo(tblInfo.Cols(), name.Name.L)
+		}
+		if target.columnInfo == nil {
+			return nil, plannercore.ErrUnknownColumn.GenWithStackByArgs(name.OrigColName(), "field list")
+		}
+	}
+	switch target.columnInfo.Tp {
+	case mysql.TypeJSON, mysql.TypeEnum, mysql.TypeSet, mysql.TypeBit:
+		return nil, ErrNonTransactionalDMLUnsupported.GenWithStackByArgs(fmt.Sprintf("the type of the shard column %s", target.columnInfo.Name.O))
+	}
+	// The rows may be moved across the jobs if the shard column is updated.
+	for _, assignment := range assignments {
+		if assignment.Column.Name.L == target.columnInfo.Name.L {
+			return nil, ErrNonTransactionalDMLUnsupported.GenWithStackByArgs("the shard column can't be updated")
+		}
+	}
+	return target, nil
+}
+
+// buildShardSelectStmt builds the statement which reads the shard column values of the rows to be modified.
+func buildShardSelectStmt(target *nonTransactionalTarget) (*ast.SelectStmt, string, error) {
+	selectStmt := &ast.SelectStmt{
+		SelectStmtOpts: &ast.SelectStmtOpts{SQLCache: true},
+		Kind:           ast.SelectStmtKindSelect,
+		Fields: &ast.FieldList{Fields: []*ast.SelectField{
+			{Expr: &ast.ColumnNameExpr{Name: target.shardColumn}},
+		}},
+		From:  target.tableRefs,
+		Where: target.where,
+		OrderBy: &ast.OrderByClause{Items: []*ast.ByItem{
+			{Expr: &ast.ColumnNameExpr{Name: target.shardColumn}},
+		}},
+	}
+	sql, err := restoreNode(selectStmt)
+	if err != nil {
+		return nil, "", err
+	}
+	selectStmt.SetText(sql)
+	return selectStmt, sql, nil
+}
+
+// splitNonTransactionalJobs reads the shard column values in order and splits them into jobs.
+// Only the boundaries of the jobs are kept.
+func splitNonTransactionalJobs(ctx context.Context, se Session, selectStmt *ast.SelectStmt, target *nonTransactionalTarget, batchSize uint64) (jobs []nonTransactionalJob, err error) {
+	rs, err := se.ExecuteStmt(ctx, selectStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rs.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	sc := se.GetSessionVars().StmtCtx
+	ft := &rs.Fields()[0].Column.FieldType
+	collator := collate.GetCollator(ft.Collate)
+	var current *nonTransactionalJob
+	chk := rs.NewChunk(nil)
+	for {
+		if err = rs.Next(ctx, chk); err != nil {
+			return nil, err
+		}
+		if chk.NumRows() == 0 {
+			break
+		}
+		for i := 0; i < chk.NumRows(); i++ {
+			value := chk.GetRow(i).GetDatum(0, ft)
+			if current != nil {
+				cmp, err := value.Compare(sc, &current.end, collator)
+				if err != nil {
+					return nil, err
+				}
+				if cmp == 0 || uint64(current.jobSize) < batchSize {
+					value.Copy(&current.end)
+					current.jobSize++
+					continue
+				}
+			}
+			jobs = append(jobs, nonTransactionalJob{jobID: len(jobs) + 1, jobSize: 1})
+			current = &jobs[len(jobs)-1]
+			value.Copy(&current.start)
+			value.Copy(&current.end)
+		}
+		chk = chunk.Renew(chk, se.GetSessionVars().MaxChunkSize)
+	}
+	return jobs, nil
+}
+
+// buildJobCondition builds the condition which selects the rows of the job. The NULL values
+// are sorted at first, so only the first job may contain them.
+func buildJobCondition(target *nonTransactionalTarget, job *nonTransactionalJob) ast.ExprNode {
+	column := &ast.ColumnNameExpr{Name: target.shardColumn}
+	var cond ast.ExprNode
+	switch {
+	case job.start.IsNull() && job.end.IsNull():
+		cond = &ast.IsNullExpr{Expr: column}
+	case job.start.IsNull():
+		cond = &ast.ParenthesesExpr{Expr: &ast.BinaryOperationExpr{
+			Op: opcode.LogicOr,
+			L:  &ast.IsNullExpr{Expr: column},
+			R:  &ast.BinaryOperationExpr{Op: opcode.LE, L: column, R: buildShardValueExpr(target, job.end)},
+		}}
+	default:
+		cond = &ast.BetweenExpr{Expr: column, Left: buildShardValueExpr(target, job.start), Right: buildShardValueExpr(target, job.end)}
+	}
+	if target.where == nil {
+		return cond
+	}
+	return &ast.BinaryOperationExpr{Op: opcode.LogicAnd, L: cond, R: &ast.ParenthesesExpr{Expr: target.where}}
+}
+
+func buildShardValueExpr(target *nonTransactionalTarget, value types.Datum) ast.ValueExpr {
+	return ast.NewValueExpr(value.GetValue(), target.columnInfo.Charset, target.columnInfo.Collate)
+}
+
+func doNonTransactionalJob(ctx context.Context, se Session, dml ast.DMLNode, target *nonTransactionalTarget, job *nonTransactionalJob, jobCount int) error {
+	jobFailure := func(err error) error {
+		return ErrNonTransactionalJobFailure.GenWithStackByArgs(job.jobID, jobCount, datumString(job.start), datumString(job.end), err.Error())
+	}
+	if atomic.LoadUint32(&se.GetSessionVars().Killed) == 1 {
+		return jobFailure(executor.ErrQueryInterrupted)
+	}
+	setDMLWhere(dml, buildJobCondition(target, job))
+	sql, err := restoreNode(dml)
+	if err != nil {
+		return jobFailure(err)
+	}
+	// The job number is shown in the processlist and the slow log to tell the progress.
+	dml.SetText(fmt.Sprintf("/* job %d/%d */ %s", job.jobID, jobCount, sql))
+	start := time.Now()
+	rs, err := se.ExecuteStmt(ctx, dml)
+	if rs != nil {
+		terr := rs.Close()
+		if err == nil {
+			err = terr
+		}
+	}
+	if err != nil {
+		logutil.Logger(ctx).Warn("non-transactional DML job failed",
+			zap.Int("jobID", job.jobID), zap.Int("jobCount", jobCount), zap.Int("jobSize", job.jobSize), zap.Error(err))
+		return jobFailure(err)
+	}
+	logutil.Logger(ctx).Info("non-transactional DML job finished",
+		zap.Int("jobID", job.jobID), zap.Int("jobCount", jobCount), zap.Int("jobSize", job.jobSize),
+		zap.Uint64("affectedRows", se.AffectedRows()), zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
+func setDMLWhere(dml ast.DMLNode, where ast.ExprNode) {
+	switch x := dml.(type) {
+	case *ast.DeleteStmt:
+		x.Where = where
+	case *ast.UpdateStmt:
+		x.Where = where
+	}
+}
+
+func restoreNode(node ast.Node) (string, error) {
+	var sb strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return "", errors.Trace(err)
+	}
+	return sb.String(), nil
+}
+
+func datumString(d types.Datum) string {
+	if d.IsNull() {
+		return "NULL"
+	}
+	s, err := d.ToString()
+	if err != nil {
+		return d.String()
+	}
+	return s
+}
+
+func buildNonTransactionalResultField(name string, tp byte) *ast.ResultField {
+	ft := types.NewFieldType(tp)
+	if tp == mysql.TypeVarchar {
+		ft.Flen = mysql.MaxBlobWidth
+		ft.Charset, ft.Collate = mysql.DefaultCharset, mysql.DefaultCollationName
+	}
+	return &ast.ResultField{
+		Column:       &model.ColumnInfo{FieldType: *ft},
+		ColumnAsName: model.NewCIStr(name),
+	}
+}
+
+func buildNonTransactionalDryRunResult(name string, sqls []string, maxChunkSize int) sqlexec.RecordSet {
+	rows := make([][]interface{}, 0, len(sqls))
+	for _, sql := range sqls {
+		rows = append(rows, []interface{}{sql})
+	}
+	return &sqlexec.SimpleRecordSet{
+		ResultFields: []*ast.ResultField{buildNonTransactionalResultField(name, mysql.TypeVarchar)},
+		Rows:         rows,
+		MaxChunkSize: maxChunkSize,
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonTransactionalDelete(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int, c varchar(10), key(b))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, '%d')", i, i%10, i))
+	}
+
+	tk.MustQuery("batch limit 10 dry run query delete from t where b < 5").Check(testkit.Rows(
+		"SELECT `a` FROM `test`.`t` WHERE `b`<5 ORDER BY `a`"))
+	tk.MustQuery("batch on b limit 10 dry run delete from t where b < 5").Check(testkit.Rows(
+		"DELETE FROM `test`.`t` WHERE `b` BETWEEN 0 AND 0 AND (`b`<5)",
+		"DELETE FROM `test`.`t` WHERE `b` BETWEEN 4 AND 4 AND (`b`<5)"))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("100"))
+
+	tk.MustQuery("batch limit 7 delete from t where b < 5").Check(testkit.Rows("8 all succeeded"))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("50"))
+	// The rows with the same shard column value are always in the same job.
+	tk.MustQuery("batch on b limit 3 delete from t").Check(testkit.Rows("5 all succeeded"))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("0"))
+	tk.MustQuery("batch limit 3 delete from t").Check(testkit.Rows("0 all succeeded"))
+
+	// The table without integer primary key is split by _tidb_rowid.
+	tk.MustExec("create table t1 (a varchar(10), b int)")
+	tk.MustExec("insert into t1 values ('a', 1), ('b', null), (null, 3), ('c', 4), (null, 5)")
+	tk.MustQuery("batch limit 2 dry run query delete from t1").Check(testkit.Rows(
+		"SELECT `_tidb_rowid` FROM `test`.`t1` ORDER BY `_tidb_rowid`"))
+	tk.MustQuery("batch on a limit 1 dry run delete from t1 where b > 1").Check(testkit.Rows(
+		"DELETE FROM `test`.`t1` WHERE `a` IS NULL AND (`b`>1)",
+		"DELETE FROM `test`.`t1` WHERE `a` BETWEEN _UTF8MB4'c' AND _UTF8MB4'c' AND (`b`>1)"))
+	tk.MustQuery("batch on a limit 1 delete from t1 where b > 1").Check(testkit.Rows("2 all succeeded"))
+	tk.MustQuery("select * from t1 order by a").Check(testkit.Rows("a 1", "b <nil>"))
+	tk.MustQuery("batch on test.t1.b limit 1 delete from t1").Check(testkit.Rows("2 all succeeded"))
+	tk.MustQuery("select count(*) from t1").Check(testkit.Rows("0"))
+}
+
+func TestNonTransactionalUpdate(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a varchar(10) primary key clustered, b int)")
+	tk.MustExec("insert into t values ('a', 1), ('b', 2), ('c', 3), ('d', 4), ('e', 5)")
+	tk.MustQuery("batch limit 2 dry run update t set b = b + 10 where b > 1").Check(testkit.Rows(
+		"UPDATE `test`.`t` SET `b`=`b`+10 WHERE `a` BETWEEN _UTF8MB4'b' AND _UTF8MB4'c' AND (`b`>1)",
+		"UPDATE `test`.`t` SET `b`=`b`+10 WHERE `a` BETWEEN _UTF8MB4'd' AND _UTF8MB4'e' AND (`b`>1)"))
+	tk.MustQuery("batch limit 2 update t set b = b + 10 where b > 1").Check(testkit.Rows("2 all succeeded"))
+	tk.MustQuery("select * from t").Check(testkit.Rows("a 1", "b 12", "c 13", "d 14", "e 15"))
+	tk.MustQuery("batch on a limit 1 update t as x set x.b = x.b + 1").Check(testkit.Rows("5 all succeeded"))
+	tk.MustQuery("select * from t").Check(testkit.Rows("a 2", "b 13", "c 14", "d 15", "e 16"))
+	tk.MustGetErrMsg("batch on b limit 1 update t set b = 1", "[session:8246]Non-transactional DML is unsupported: the shard column can't be updated")
+}
+
+func TestNonTransactionalDMLUnsupported(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int, j json)")
+	tk.MustExec("create table t1 (a int, b int, primary key(a, b) clustered)")
+
+	tk.MustExec("begin")
+	tk.MustGetErrMsg("batch limit 1 delete from t", "[session:8246]Non-transactional DML is unsupported: it can only run in auto-commit mode")
+	tk.MustExec("rollback")
+	tk.MustExec("set autocommit = 0")
+	tk.MustGetErrMsg("batch limit 1 delete from t", "[session:8246]Non-transactional DML is unsupported: it can only run in auto-commit mode")
+	tk.MustExec("set autocommit = 1")
+
+	tk.MustGetErrMsg("batch limit 0 delete from t", "[session:8246]Non-transactional DML is unsupported: the batch size must be positive")
+	tk.MustGetErrMsg("batch limit 1 delete t, t1 from t, t1 where t.a = t1.a", "[session:8246]Non-transactional DML is unsupported: multiple-table DELETE")
+	tk.MustGetErrMsg("batch limit 1 update t, t1 set t.b = 1 where t.a = t1.a", "[session:8246]Non-transactional DML is unsupported: multiple-table UPDATE")
+	tk.MustGetErrMsg("batch limit 1 delete from t order by a", "[session:8246]Non-transactional DML is unsupported: ORDER BY or LIMIT clause")
+	tk.MustGetErrMsg("batch limit 1 update t set b = 1 limit 1", "[session:8246]Non-transactional DML is unsupported: ORDER BY or LIMIT clause")
+	tk.MustGetErrMsg("batch on j limit 1 delete from t", "[session:8246]Non-transactional DML is unsupported: the type of the shard column j")
+	tk.MustGetErrMsg("batch on c limit 1 delete from t", "[planner:1054]Unknown column 'c' in 'field list'")
+	tk.MustGetErrMsg("batch on t1.a limit 1 delete from t", "[planner:1054]Unknown column 't1.a' in 'field list'")
+	tk.MustGetErrMsg("batch limit 1 delete from t1", "[session:8246]Non-transactional DML is unsupported: the shard column must be specified for the table with composite clustered index")
+	tk.MustQuery("batch on a limit 1 delete from t1").Check(testkit.Rows("0 all succeeded"))
+
+	tk.MustExec("set @@tidb_batch_delete = 1")
+	err := tk.ExecToErr("batch limit 1 delete from t")
+	require.EqualError(t, err, "[session:8246]Non-transactional DML is unsupported: it can't run with the batch DML variables")
+}
@@ -571,6 +571,39 @@ func (s *testPessimisticSuite) TestSelectForUpdateNoWait(c *C) {
 	tk2.MustExec("commit")
 }
 
+func (s *testPessimisticSuite) TestSelectForUpdateSkipLocked(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+
+	tk.MustExec("drop table if exists tk")
+	tk.MustExec("create table tk (c1 int primary key, c2 int, key(c2))")
+	tk.MustExec("insert into tk values(1,1),(2,2),(3,3),(4,4),(5,5)")
+
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("select * from tk where c1 in (2, 4) for update")
+	tk2.MustExec("begin pessimistic")
+	tk2.MustQuery("select * from tk use index(c2) where c2 > 1 order by c2 limit 1 for update skip locked").Check(testkit.Rows("3 3"))
+	// Only the returned rows are locked with the limit.
+	tk.MustExec("select * from tk where c1 = 5 for update nowait")
+	tk2.MustQuery("select * from tk for update skip locked").Check(testkit.Rows("1 1", "3 3"))
+	// The point get doesn't lock the row itself when skipping the locked rows.
+	tk2.MustQuery("select * from tk where c1 = 2 for update skip locked").Check(testkit.Rows())
+	tk2.MustQuery("select * from tk where c1 in (1, 2) for update skip locked").Check(testkit.Rows("1 1"))
+	// The rows locked by the transaction itself are not skipped.
+	tk2.MustQuery("select * from tk where c1 = 1 for update skip locked").Check(testkit.Rows("1 1"))
+	_, err := tk.Exec("select * from tk where c1 = 3 for update nowait")
+	c.Check(err, NotNil)
+	tk.MustExec("commit")
+	tk2.MustQuery("select * from tk for update skip locked").Check(testkit.Rows("1 1", "2 2", "3 3", "4 4", "5 5"))
+	tk2.MustExec("commit")
+
+	// The rows are not locked out of the transactions.
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("select * from tk where c1 = 1 for update")
+	tk2.MustQuery("select * from tk where c1 <= 2 for update skip locked").Check(testkit.Rows("1 1", "2 2"))
+	tk.MustExec("commit")
+}
+
 func (s *testPessimisticSuite) TestAsyncRollBackNoWait(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
 	tk2 := testkit.NewTestKitWithInit(c, s.store)
@@ -1441,6 +1474,66 @@ func (s *testPessimisticSuite) TestRCIndexMerge(c *C) {
 	)
 }
 
+func (s *testPessimisticSuite) TestRCReadCheckTS(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (id int primary key, v int, k int, key(k))")
+	tk.MustExec("create table t2 (id int primary key, v int)")
+	tk.MustExec("set tx_isolation = 'READ-COMMITTED'")
+	defer tk.MustExec("set tidb_rc_read_check_ts = 0")
+
+	for _, checkTS := range []string{"0", "1"} {
+		tk.MustExec("set tidb_rc_read_check_ts = " + checkTS)
+		tk2.MustExec("delete from t1")
+		tk2.MustExec("delete from t2")
+		tk2.MustExec("insert into t1 values (1, 1, 1), (2, 2, 2)")
+		tk2.MustExec("insert into t2 values (1, 1)")
+
+		tk.MustExec("begin pessimistic")
+		// The table reader, the index reader and the index lookup read the latest committed data.
+		tk.MustQuery("select * from t1 order by id").Check(testkit.Rows("1 1 1", "2 2 2"))
+		tk2.MustExec("update t1 set v = 10 where id = 1")
+		tk.MustQuery("select * from t1 order by id").Check(testkit.Rows("1 10 1", "2 2 2"))
+		tk2.MustExec("update t1 set k = 20 where id = 2")
+		tk.MustQuery("select k from t1 use index(k) where k > 0 order by k").Check(testkit.Rows("1", "20"))
+		tk2.MustExec("update t1 set v = 11 where id = 1")
+		tk.MustQuery("select v from t1 use index(k) where k = 1").Check(testkit.Rows("11"))
+		// The phantom rows are visible.
+		tk2.MustExec("insert into t1 values (3, 3, 3)")
+		tk.MustQuery("select count(*) from t1").Check(testkit.Rows("3"))
+		tk2.MustExec("delete from t1 where id = 3")
+		tk.MustQuery("select count(*) from t1").Check(testkit.Rows("2"))
+		// The point gets and the subqueries read the latest committed data too.
+		tk2.MustExec("update t1 set v = 12 where id = 1")
+		tk.MustQuery("select t1.v, t2.v from t1 join t2 on t1.v > t2.v where t1.id = 1").Check(testkit.Rows("12 1"))
+		tk2.MustExec("update t2 set v = 2 where id = 1")
+		tk.MustQuery("select v from t1 where v > (select v from t2 where id = 1) order by v").Check(testkit.Rows("12"))
+		// The own changes are visible.
+		tk.MustExec("update t1 set v = 13 where id = 1")
+		tk2.MustExec("update t1 set v = 22 where id = 2")
+		tk.MustQuery("select v from t1 order by id").Check(testkit.Rows("13", "22"))
+		tk.MustExec("commit")
+		tk.MustQuery("select v from t1 order by id").Check(testkit.Rows("13", "22"))
+	}
+
+	// The last timestamp is reused if no newer version is met.
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select v from t1 order by id").Check(testkit.Rows("13", "22"))
+	txnCtx := tk.Se.GetSessionVars().TxnCtx
+	forUpdateTS := txnCtx.GetForUpdateTS()
+	tk.MustQuery("select v from t1 order by id").Check(testkit.Rows("13", "22"))
+	c.Assert(txnCtx.GetForUpdateTS(), Equals, forUpdateTS)
+	// A new timestamp is fetched if the statement meets a newer version, or it contains a point get.
+	tk2.MustExec("update t1 set v = 14 where id = 1")
+	tk.MustQuery("select v from t1 order by id").Check(testkit.Rows("14", "22"))
+	c.Assert(txnCtx.GetForUpdateTS(), Greater, forUpdateTS)
+	forUpdateTS = txnCtx.GetForUpdateTS()
+	tk.MustQuery("select v from t1 where id = 1").Check(testkit.Rows("14"))
+	c.Assert(txnCtx.GetForUpdateTS(), Greater, forUpdateTS)
+	tk.MustExec("commit")
+}
+
 func (s *testPessimisticSuite) TestGenerateColPointGet(c *C) {
 	atomic.StoreUint64(&transaction.ManagedLockTTL, 3000)
 	defer atomic.StoreUint64(&transaction.ManagedLockTTL, 300)
@@ -0,0 +1,159 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavepoint(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("create table t2 (a int primary key, b int, c int, key(b), unique key(c))")
+
+	for _, txnMode := range []string{"optimistic", "pessimistic"} {
+		tk.MustExec("truncate table t")
+		tk.MustExec("truncate table t2")
+		tk.MustExec("begin " + txnMode)
+		tk.MustExec("insert into t values (1, 1)")
+		tk.MustExec("savepoint s1")
+		tk.MustExec("insert into t values (2, 2)")
+		tk.MustExec("savepoint s2")
+		tk.MustExec("insert into t values (3, 3)")
+		tk.MustExec("update t set b = 10 where a = 1")
+		tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 10", "2 2", "3 3"))
+		// The point get reads the changes after the savepoints.
+		tk.MustQuery("select * from t where a = 3").Check(testkit.Rows("3 3"))
+		tk.MustExec("rollback to savepoint s2")
+		tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1", "2 2"))
+		tk.MustQuery("select * from t where a = 3").Check(testkit.Rows())
+		// The savepoint is kept after rolling back to it.
+		tk.MustExec("insert into t values (4, 4)")
+		tk.MustExec("rollback to s2")
+		tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1", "2 2"))
+		tk.MustExec("rollback to s1")
+		tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1"))
+		// The savepoints after the one rolled back to are removed.
+		tk.MustGetErrMsg("rollback to s2", "[executor:1305]SAVEPOINT s2 does not exist")
+		tk.MustExec("insert into t values (5, 5)")
+		tk.MustExec("release savepoint s1")
+		tk.MustGetErrMsg("rollback to s1", "[executor:1305]SAVEPOINT s1 does not exist")
+		tk.MustGetErrMsg("release savepoint s1", "[executor:1305]SAVEPOINT s1 does not exist")
+		tk.MustExec("commit")
+		tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1", "5 5"))
+
+		// The changes after the savepoints are committed even if nothing is written before them.
+		tk.MustExec("begin " + txnMode)
+		tk.MustExec("savepoint s1")
+		tk.MustExec("savepoint s2")
+		tk.MustExec("delete from t where a = 1")
+		tk.MustExec("commit")
+		tk.MustQuery("select * from t order by a").Check(testkit.Rows("5 5"))
+
+		// A savepoint replaces the previous one with the same name, and the names are case-insensitive.
+		tk.MustExec("begin " + txnMode)
+		tk.MustExec("savepoint s")
+		tk.MustExec("insert into t values (6, 6)")
+		tk.MustExec("savepoint S")
+		tk.MustExec("insert into t values (7, 7)")
+		tk.MustExec("rollback to s")
+		tk.MustExec("commit")
+		tk.MustQuery("select * from t order by a").Check(testkit.Rows("5 5", "6 6"))
+
+		// Rolling back the transaction discards the changes before and after the savepoints.
+		tk.MustExec("begin " + txnMode)
+		tk.MustExec("insert into t values (8, 8)")
+		tk.MustExec("savepoint s1")
+		tk.MustExec("insert into t values (9, 9)")
+		tk.MustExec("rollback")
+		tk.MustQuery("select * from t order by a").Check(testkit.Rows("5 5", "6 6"))
+		tk.MustGetErrMsg("rollback to s1", "[executor:1305]SAVEPOINT s1 does not exist")
+
+		// The committed rows modified after the savepoint are restored, and so are the indexes.
+		tk.MustExec("insert into t2 values (5, 5, 5), (6, 6, 6)")
+		tk.MustExec("begin " + txnMode)
+		tk.MustExec("savepoint s1")
+		tk.MustExec("delete from t2 where a = 5")
+		tk.MustExec("update t2 set b = 60, c = 5 where a = 6")
+		tk.MustExec("insert into t2 values (7, 7, 7)")
+		tk.MustQuery("select * from t2 order by a").Check(testkit.Rows("6 60 5", "7 7 7"))
+		tk.MustExec("rollback to s1")
+		tk.MustQuery("select * from t2 order by a").Check(testkit.Rows("5 5 5", "6 6 6"))
+		tk.MustQuery("select * from t2 use index(b) where b > 0 order by b").Check(testkit.Rows("5 5 5", "6 6 6"))
+		tk.MustQuery("select * from t2 where c = 7").Check(testkit.Rows())
+		// The unique key written after the savepoint can be written again.
+		tk.MustExec("insert into t2 values (8, 8, 7)")
+		tk.MustExec("commit")
+		tk.MustQuery("select * from t2 order by a").Check(testkit.Rows("5 5 5", "6 6 6", "8 8 7"))
+		tk.MustExec("admin check table t2")
+	}
+
+	// The savepoint is useless if the statement is committed at once.
+	tk.MustExec("savepoint s1")
+	tk.MustGetErrMsg("rollback to s1", "[executor:1305]SAVEPOINT s1 does not exist")
+	tk.MustExec("set autocommit = 0")
+	tk.MustExec("insert into t values (10, 10)")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert into t values (11, 11)")
+	tk.MustExec("rollback to s1")
+	tk.MustExec("commit")
+	tk.MustExec("set autocommit = 1")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("5 5", "6 6", "10 10"))
+}
+
+func TestSavepointTxnContext(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tbl, err := tk.Session().GetInfoSchema().(infoschema.InfoSchema).TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tableID := tbl.Meta().ID
+
+	// The modify count of the table is restored when rolling back to the savepoint.
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("insert into t values (3, 3)")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert into t values (4, 4), (5, 5)")
+	txnCtx := tk.Session().GetSessionVars().TxnCtx
+	require.Equal(t, int64(3), txnCtx.TableDeltaMap[tableID].Count)
+	tk.MustExec("rollback to s1")
+	require.Equal(t, int64(1), txnCtx.TableDeltaMap[tableID].Count)
+	require.Len(t, txnCtx.Savepoints, 1)
+
+	// Like MySQL, the pessimistic locks acquired after the savepoint are kept until the transaction ends.
+	tk.MustExec("select * from t where a = 1 for update")
+	tk.MustExec("rollback to s1")
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	tk2.MustExec("begin pessimistic")
+	require.Error(t, tk2.ExecToErr("select * from t where a = 1 for update nowait"))
+	tk2.MustExec("rollback")
+	tk.MustExec("commit")
+	require.Len(t, tk.Session().GetSessionVars().TxnCtx.Savepoints, 0)
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1", "2 2", "3 3"))
+}
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"runtime/pprof"
 	"runtime/trace"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -59,6 +60,7 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
@@ -72,6 +74,7 @@ import (
 	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
@@ -163,6 +166,9 @@ type Session interface {
 	SetDiskFullOpt(level kvrpcpb.DiskFullOpt)
 	GetDiskFullOpt() kvrpcpb.DiskFullOpt
 	ClearDiskFullOpt()
+
+	// SetSessionStatesHandler sets the handler to migrate the session states kept outside the session.
+	SetSessionStatesHandler(handler sessionstates.Handler)
 }
 
 var _ Session = (*session)(nil)
@@ -230,6 +236,9 @@ type session struct {
 	builtinFunctionUsage telemetry.BuiltinFunctionsUsage
 	// allowed when tikv disk full happened.
 	diskFullOpt kvrpcpb.DiskFullOpt
+
+	// sessionStatesHandler encodes and decodes the session states kept by the server.
+	sessionStatesHandler sessionstates.Handler
 }
 
 var parserPool = &sync.Pool{New: func() interface{} { return parser.New() }}
@@ -421,6 +430,19 @@ func (s *session) StoreIndexUsage(tblID int64, idxID int64, rowsSelected int64)
 	s.idxUsageCollector.Update(tblID, idxID, &handle.IndexUsageInformation{QueryCount: 1, RowsSelected: rowsSelected})
 }
 
+// UpdateColStatsUsage updates the column stats usage.
+func (s *session) UpdateColStatsUsage(predicateColumns []model.TableColumnID) {
+	if s.statsCollector == nil {
+		return
+	}
+	t := time.Now()
+	colMap := make(map[model.TableColumnID]time.Time, len(predicateColumns))
+	for _, col := range predicateColumns {
+		colMap[col] = t
+	}
+	s.statsCollector.UpdateColStatsUsage(colMap)
+}
+
 // FieldList returns fields list of a table.
 func (s *session) FieldList(tableName string) ([]*ast.ResultField, error) {
 	is := s.GetInfoSchema().(infoschema.InfoSchema)
@@ -546,8 +568,13 @@ func (s *session) doCommit(ctx context.Context) error {
 	if sessVars.EnableAmendPessimisticTxn {
 		s.txn.SetOption(kv.SchemaAmender, NewSchemaAmenderForTikvTxn(s))
 	}
-	s.txn.SetOption(kv.EnableAsyncCommit, sessVars.EnableAsyncCommit)
-	s.txn.SetOption(kv.Enable1PC, sessVars.Enable1PC)
+	asyncCommit, onePC := sessVars.EnableAsyncCommit, sessVars.Enable1PC
+	if (asyncCommit || onePC) && !domain.GetDomain(s).AsyncCommitSupported() {
+		// Fall back to the normal 2PC if any store doesn't support async commit and one-phase commit.
+		asyncCommit, onePC = false, false
+	}
+	s.txn.SetOption(kv.EnableAsyncCommit, asyncCommit)
+	s.txn.SetOption(kv.Enable1PC, onePC)
 	s.txn.SetOption(kv.ResourceGroupTagger, sessVars.StmtCtx.GetResourceGroupTagger())
 	if sessVars.StmtCtx.KvExecCounter != nil {
 		// Bind an interceptor for client-go to count the number of SQL executions of each TiKV.
@@ -849,6 +876,13 @@ func (s *session) doCommitWithRetry(ctx context.Context) error {
 			}
 		}
 	}
+	if s.idxUsageCollector != nil && mapper != nil {
+		for _, item := range mapper {
+			for idxID, rowsWritten := range item.IndexWrites {
+				s.idxUsageCollector.Update(item.LogicalTableID, idxID, &handle.IndexUsageInformation{RowsWritten: rowsWritten})
+			}
+		}
+	}
 	return nil
 }
 
@@ -965,7 +999,7 @@ func (s *session) checkTxnAborted(stmt sqlexec.Statement) error {
 	if _, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.CommitStmt); ok {
 		return nil
 	}
-	if _, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.RollbackStmt); ok {
+	if x, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.RollbackStmt); ok && x.SavepointName == "" {
 		return nil
 	}
 	return err
@@ -1015,7 +1049,7 @@ func (s *session) retry(ctx context.Context, maxCnt uint) (err error) {
 				// We do not have to log the query every time.
 				// We print the queries at the first try only.
 				sql := sqlForLog(st.GetTextToLog())
-				if !sessVars.EnableRedactLog {
+				if !sessVars.ShouldRedactLog() {
 					sql += sessVars.PreparedParams.String()
 				}
 				logutil.Logger(ctx).Warn("retrying",
@@ -1316,21 +1350,24 @@ func (s *session) SetProcessInfo(sql string, t time.Time, command byte, maxExecu
 		p = explain.TargetPlan
 	}
 	pi := util.ProcessInfo{
-		ID:               s.sessionVars.ConnectionID,
-		Port:             s.sessionVars.Port,
-		DB:               s.sessionVars.CurrentDB,
-		Command:          command,
-		Plan:             p,
-		PlanExplainRows:  plannercore.GetExplainRowsForPlan(p),
-		RuntimeStatsColl: s.sessionVars.StmtCtx.RuntimeStatsColl,
-		Time:             t,
-		State:            s.Status(),
-		Info:             sql,
-		CurTxnStartTS:    curTxnStartTS,
-		StmtCtx:          s.sessionVars.StmtCtx,
-		StatsInfo:        plannercore.GetStatsInfo,
-		MaxExecutionTime: maxExecutionTime,
-		RedactSQL:        s.sessionVars.EnableRedactLog,
+		ID:                s.sessionVars.ConnectionID,
+		Port:              s.sessionVars.Port,
+		DB:                s.sessionVars.CurrentDB,
+		Command:           command,
+		Plan:              p,
+		PlanExplainRows:   plannercore.GetExplainRowsForPlan(p),
+		RuntimeStatsColl:  s.sessionVars.StmtCtx.RuntimeStatsColl,
+		Time:              t,
+		State:             s.Status(),
+		Info:              sql,
+		CurTxnStartTS:     curTxnStartTS,
+		StmtCtx:           s.sessionVars.StmtCtx,
+		StatsInfo:         plannercore.GetStatsInfo,
+		MaxExecutionTime:  maxExecutionTime,
+		GCLifeTime:        s.sessionVars.GCLifeTime,
+		ResourceGroupName: s.sessionVars.ResourceGroupName,
+		ConnectionAttrs:   s.sessionVars.ConnectionAttrs,
+		RedactSQL:         s.sessionVars.ShouldRedactLog(),
 	}
 	oldPi := s.ShowProcess()
 	if p == nil {
@@ -1443,7 +1480,7 @@ func (s *session) Parse(ctx context.Context, sql string) ([]ast.StmtNode, error)
 		// Only print log message when this SQL is from the user.
 		// Mute the warning for internal SQLs.
 		if !s.sessionVars.InRestrictedSQL {
-			if s.sessionVars.EnableRedactLog {
+			if s.sessionVars.ShouldRedactLog() {
 				logutil.Logger(ctx).Debug("parse SQL failed", zap.Error(err), zap.String("SQL", sql))
 			} else {
 				logutil.Logger(ctx).Warn("parse SQL failed", zap.Error(err), zap.String("SQL", sql))
@@ -1500,7 +1537,7 @@ func (s *session) ParseWithParams(ctx context.Context, sql string, args ...inter
 		// Only print log message when this SQL is from the user.
 		// Mute the warning for internal SQLs.
 		if !s.sessionVars.InRestrictedSQL {
-			if s.sessionVars.EnableRedactLog {
+			if s.sessionVars.ShouldRedactLog() {
 				logutil.Logger(ctx).Debug("parse SQL failed", zap.Error(err), zap.String("SQL", sql))
 			} else {
 				logutil.Logger(ctx).Warn("parse SQL failed", zap.Error(err), zap.String("SQL", sql))
@@ -1636,6 +1673,15 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 		ctx = opentracing.ContextWithSpan(ctx, span1)
 	}
 
+	if s.sessionVars.InSandboxMode && !s.sessionVars.InRestrictedSQL && !isAllowedInSandboxMode(stmtNode) {
+		return nil, ErrMustChangePassword.GenWithStackByArgs()
+	}
+
+	// The non-transactional DML is split into many statements, each of them is executed by ExecuteStmt.
+	if stmt, ok := stmtNode.(*ast.NonTransactionalDMLStmt); ok {
+		return HandleNonTransactionalDML(ctx, stmt, s)
+	}
+
 	if err := s.PrepareTxnCtx(ctx); err != nil {
 		return nil, err
 	}
@@ -1677,11 +1723,17 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	stmt, err := compiler.Compile(ctx, stmtNode)
 	if err != nil {
 		s.rollbackOnError(ctx)
+		// The statements denied by the privilege check fail here, which should be audited.
+		executor.LogAuditEvent(s, stmtNode, err)
 
 		// Only print log message when this SQL is from the user.
 		// Mute the warning for internal SQLs.
 		if !s.sessionVars.InRestrictedSQL {
-			logutil.Logger(ctx).Warn("compile SQL failed", zap.Error(err), zap.String("SQL", stmtNode.Text()))
+			sql := stmtNode.Text()
+			if s.sessionVars.ShouldRedactLog() {
+				sql = parser.Normalize(sql)
+			}
+			logutil.Logger(ctx).Warn("compile SQL failed", zap.Error(err), zap.String("SQL", sql))
 		}
 		return nil, err
 	}
@@ -1719,6 +1771,16 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	return recordSet, nil
 }
 
+// isAllowedInSandboxMode returns whether the statement can be executed before the expired password is changed.
+// USE is allowed as well, so the clients can still connect with a default database.
+func isAllowedInSandboxMode(stmtNode ast.StmtNode) bool {
+	switch stmtNode.(type) {
+	case *ast.SetPwdStmt, *ast.AlterUserStmt, *ast.SetStmt, *ast.UseStmt:
+		return true
+	}
+	return false
+}
+
 func (s *session) validateStatementReadOnlyInStaleness(stmtNode ast.StmtNode) error {
 	vars := s.GetSessionVars()
 	if !vars.TxnCtx.IsStaleness && vars.TxnReadTS.PeakTxnReadTS() == 0 {
@@ -1911,6 +1973,10 @@ func (s *session) rollbackOnError(ctx context.Context) {
 
 // PrepareStmt is used for executing prepare statement in binary protocol
 func (s *session) PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error) {
+	if s.sessionVars.InSandboxMode {
+		err = ErrMustChangePassword.GenWithStackByArgs()
+		return
+	}
 	if s.sessionVars.TxnCtx.InfoSchema == nil {
 		// We don't need to create a transaction for prepare statement, just get information schema will do.
 		s.sessionVars.TxnCtx.InfoSchema = domain.GetDomain(s).InfoSchema()
@@ -2388,11 +2454,13 @@ func (s *session) Auth(user *auth.UserIdentity, authentication []byte, salt []by
 	if err != nil {
 		return false
 	}
-	if pm.ConnectionVerification(authUser.Username, authUser.Hostname, authentication, salt, s.sessionVars.TLSConnectionState) {
+	if success, passwordExpired := pm.ConnectionVerification(authUser.Username, authUser.Hostname, authentication, salt, s.sessionVars.TLSConnectionState, s.sessionVars.AuthConn); success {
 		user.AuthUsername = authUser.Username
 		user.AuthHostname = authUser.Hostname
 		s.sessionVars.User = user
-		s.sessionVars.ActiveRoles = pm.GetDefaultRoles(user.AuthUsername, user.AuthHostname)
+		s.sessionVars.InSandboxMode = passwordExpired
+		s.sessionVars.ActiveRoles = pm.GetLoginRoles(user.AuthUsername, user.AuthHostname)
+		s.sessionVars.ResourceGroupName = pm.GetUserResourceGroup(user.AuthUsername, user.AuthHostname)
 		return true
 	}
 	return false
@@ -2428,7 +2496,8 @@ func (s *session) AuthWithoutVerification(user *auth.UserIdentity) bool {
 		user.AuthUsername = authUser.Username
 		user.AuthHostname = authUser.Hostname
 		s.sessionVars.User = user
-		s.sessionVars.ActiveRoles = pm.GetDefaultRoles(user.AuthUsername, user.AuthHostname)
+		s.sessionVars.ActiveRoles = pm.GetLoginRoles(user.AuthUsername, user.AuthHostname)
+		s.sessionVars.ResourceGroupName = pm.GetUserResourceGroup(user.AuthUsername, user.AuthHostname)
 		return true
 	}
 	return false
@@ -2488,6 +2557,7 @@ func CreateSessionWithOpt(store kv.Storage, opt *Opt) (Session, error) {
 		Handle: do.PrivilegeHandle(),
 	}
 	privilege.BindPrivilegeManager(s, pm)
+	expression.BindUserDefinedFunctions(s, do.UserDefinedFunctions())
 
 	sessionBindHandle := bindinfo.NewSessionBindHandle(parser.New())
 	s.SetValue(bindinfo.SessionBindInfoKeyType, sessionBindHandle)
@@ -2691,7 +2761,18 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 		return nil, err
 	}
 
+	se8, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	err = dom.LoadUserFunctionsLoop(se8)
+	if err != nil {
+		return nil, err
+	}
+
 	dom.PlanReplayerLoop()
+	dom.PlanReplayerCaptureLoop(executor.DumpPlanReplayerCapture)
+	dom.RunawayRecordLoop()
 
 	if raw, ok := store.(kv.EtcdBackend); ok {
 		err = raw.StartGCWorker()
@@ -2928,8 +3009,9 @@ func (s *session) PrepareTSFuture(ctx context.Context) {
 		// Prepare the transaction future if the transaction is invalid (at the beginning of the transaction).
 		txnFuture := s.getTxnFuture(ctx)
 		s.txn.changeInvalidToPending(txnFuture)
-	} else if s.txn.Valid() && s.GetSessionVars().IsPessimisticReadConsistency() {
+	} else if s.txn.Valid() && s.GetSessionVars().IsPessimisticReadConsistency() && !s.GetSessionVars().StmtCtx.RCCheckTS {
 		// Prepare the statement future if the transaction is valid in RC transactions.
+		// The statement checking the read timestamp reuses the last one, so no future is needed.
 		s.GetSessionVars().TxnCtx.SetStmtFutureForRC(s.getTxnFuture(ctx).future)
 	}
 }
@@ -3009,11 +3091,15 @@ func logStmt(execStmt *executor.ExecStmt, s *session) {
 				zap.String("secure text", ss.SecureText()),
 				zap.Stringer("user", user))
 		} else {
+			sql := stmt.Text()
+			if vars.ShouldRedactLog() {
+				sql = parser.Normalize(sql)
+			}
 			logutil.BgLogger().Info("CRUCIAL OPERATION",
 				zap.Uint64("conn", vars.ConnectionID),
 				zap.Int64("schemaVersion", schemaVersion),
 				zap.String("cur_db", vars.CurrentDB),
-				zap.String("sql", stmt.Text()),
+				zap.String("sql", sql),
 				zap.Stringer("user", user))
 		}
 	default:
@@ -3032,7 +3118,7 @@ func logGeneralQuery(execStmt *executor.ExecStmt, s *session, isPrepared bool) {
 		}
 
 		query = executor.QueryReplacer.Replace(query)
-		if !vars.EnableRedactLog {
+		if !vars.ShouldRedactLog() {
 			query += vars.PreparedParams.String()
 		}
 		logutil.BgLogger().Info("GENERAL_LOG",
@@ -3229,6 +3315,86 @@ func (s *session) GetBuiltinFunctionUsage() map[string]uint32 {
 	return s.builtinFunctionUsage
 }
 
+// SetSessionStatesHandler implements the Session.SetSessionStatesHandler interface.
+func (s *session) SetSessionStatesHandler(handler sessionstates.Handler) {
+	s.sessionStatesHandler = handler
+}
+
+// EncodeSessionStates implements sessionctx.Context EncodeSessionStates interface.
+func (s *session) EncodeSessionStates(ctx context.Context, sessionStates *sessionstates.SessionStates) error {
+	// The active transactions, the table locks, the local temporary tables and the historical reads are bound to
+	// the server or the timestamp, so the session can't be migrated with them. The session token only
+	// authenticates the login user, so a session acting as another user can't be migrated either.
+	if s.sessionVars.InTxn() {
+		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has an active transaction")
+	}
+	if s.HasLockedTables() {
+		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has locked tables")
+	}
+	if tables, ok := s.sessionVars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables); ok && tables.Count() > 0 {
+		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has local temporary tables")
+	}
+	if s.sessionVars.SnapshotTS != 0 || s.sessionVars.TxnReadTS.PeakTxnReadTS() != 0 {
+		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has a historical read timestamp")
+	}
+	if s.sessionVars.ProxyUser != nil {
+		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session acts as another user")
+	}
+
+	if err := s.sessionVars.EncodeSessionStates(ctx, sessionStates); err != nil {
+		return err
+	}
+	stmtNames := make(map[uint32]string, len(s.sessionVars.PreparedStmtNameToID))
+	for name, id := range s.sessionVars.PreparedStmtNameToID {
+		stmtNames[id] = name
+	}
+	sessionStates.PreparedStmts = make(map[uint32]*sessionstates.PreparedStmtInfo, len(s.sessionVars.PreparedStmts))
+	for id, stmt := range s.sessionVars.PreparedStmts {
+		preparedObj, ok := stmt.(*plannercore.CachedPrepareStmt)
+		if !ok {
+			return errors.Errorf("invalid CachedPrepareStmt type")
+		}
+		sessionStates.PreparedStmts[id] = &sessionstates.PreparedStmtInfo{
+			Name:     stmtNames[id],
+			StmtText: preparedObj.StmtText,
+			StmtDB:   preparedObj.StmtDB,
+		}
+	}
+	if s.sessionStatesHandler != nil {
+		return s.sessionStatesHandler.EncodeSessionStates(ctx, sessionStates)
+	}
+	return nil
+}
+
+// DecodeSessionStates implements sessionctx.Context DecodeSessionStates interface.
+func (s *session) DecodeSessionStates(ctx context.Context, sessionStates *sessionstates.SessionStates) error {
+	// The system variables are restored first because they may affect how the statements are prepared, such as
+	// sql_mode.
+	if err := s.sessionVars.DecodeSessionStates(ctx, sessionStates); err != nil {
+		return err
+	}
+	currentDB := s.sessionVars.CurrentDB
+	ids := make([]uint32, 0, len(sessionStates.PreparedStmts))
+	for id := range sessionStates.PreparedStmts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		info := sessionStates.PreparedStmts[id]
+		s.sessionVars.CurrentDB = info.StmtDB
+		err := executor.NewRestoredPrepareExec(s, info.StmtText, id, info.Name).Next(ctx, nil)
+		if err != nil {
+			s.sessionVars.CurrentDB = currentDB
+			return err
+		}
+	}
+	s.sessionVars.CurrentDB = currentDB
+	if s.sessionStatesHandler != nil {
+		return s.sessionStatesHandler.DecodeSessionStates(ctx, sessionStates)
+	}
+	return nil
+}
+
 func (s *session) getSnapshotInterceptor() kv.SnapshotInterceptor {
 	return temptable.SessionSnapshotInterceptor(s)
 }
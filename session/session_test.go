@@ -3985,6 +3985,36 @@ func (s *testSessionSuite3) TestSetVarHint(c *C) {
 	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
 	tk.MustQuery("SELECT @@windowing_use_high_precision;").Check(testkit.Rows("1"))
 
+	// The variables with SetSession functions take effect for the statement and are restored after it.
+	tk.MustExec("SET @@tidb_mem_quota_query = 1073741824")
+	tk.MustQuery("SELECT /*+ SET_VAR(tidb_mem_quota_query=1024) */ @@tidb_mem_quota_query;").Check(testkit.Rows("1024"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.MemTracker.GetBytesLimit(), Equals, int64(1024))
+	tk.MustQuery("SELECT @@tidb_mem_quota_query;").Check(testkit.Rows("1073741824"))
+	c.Assert(tk.Se.GetSessionVars().MemQuotaQuery, Equals, int64(1073741824))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.MemTracker.GetBytesLimit(), Equals, int64(1073741824))
+
+	tk.MustExec("SET @@tidb_executor_concurrency = 5")
+	tk.MustQuery("SELECT /*+ SET_VAR(tidb_executor_concurrency=2) SET_VAR(tidb_distsql_scan_concurrency=3) */ @@tidb_executor_concurrency, @@tidb_distsql_scan_concurrency;").Check(testkit.Rows("2 3"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
+	c.Assert(tk.Se.GetSessionVars().ExecutorConcurrency, Equals, 2)
+	c.Assert(tk.Se.GetSessionVars().DistSQLScanConcurrency(), Equals, 3)
+	tk.MustQuery("SELECT @@tidb_executor_concurrency, @@tidb_distsql_scan_concurrency;").Check(testkit.Rows("5 15"))
+	c.Assert(tk.Se.GetSessionVars().ExecutorConcurrency, Equals, 5)
+	c.Assert(tk.Se.GetSessionVars().DistSQLScanConcurrency(), Equals, 15)
+
+	tk.MustExec("SET @@tidb_isolation_read_engines = 'tikv,tidb'")
+	tk.MustQuery("SELECT /*+ SET_VAR(tidb_isolation_read_engines='tidb') */ @@tidb_isolation_read_engines;").Check(testkit.Rows("tidb"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
+	c.Assert(tk.Se.GetSessionVars().IsolationReadEngines, HasLen, 1)
+	tk.MustQuery("SELECT @@tidb_isolation_read_engines;").Check(testkit.Rows("tikv,tidb"))
+	c.Assert(tk.Se.GetSessionVars().IsolationReadEngines, HasLen, 2)
+
+	// The variables which are not hint-safe can't be set by SET_VAR hint.
+	tk.MustExec("SELECT /*+ SET_VAR(tidb_snapshot = '2020-01-01 00:00:00') */ 1;")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings()[0].Err.Error(), Equals, "[planner:3637]Variable 'tidb_snapshot' cannot be set using SET_VAR hint.")
+
 	tk.MustExec("SELECT /*+ SET_VAR(sql_safe_updates = 1) SET_VAR(max_heap_table_size = 1G) */ 1;")
 	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
 
@@ -4940,7 +4970,7 @@ func (s *testStatisticsSuite) TestNewCollationStatsWithPrefixIndex(c *C) {
 		"test t  ia3 1 7 16 5 \x00B\x00B\x00B \x00B\x00B\x00B 0",
 	))
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(testkit.Rows(
-		"test t  a 0 \x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00C 2",
+		"test t  a 0 \x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00C 2 0.125 1.875",
 	))
 	tk.MustQuery("select is_index, hist_id, distinct_count, null_count, stats_ver, correlation from mysql.stats_histograms").Sort().Check(testkit.Rows(
 		"0 1 15 0 1 0.8411764705882353",
@@ -4957,57 +4987,57 @@ func (s *testStatisticsSuite) TestNewCollationStatsWithPrefixIndex(c *C) {
 	c.Assert(h.LoadNeededHistograms(), IsNil)
 	tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't'").Sort().Check(testkit.Rows())
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(testkit.Rows(
-		"test t  a 0 \x00A 1",
-		"test t  a 0 \x00A\x00A 1",
-		"test t  a 0 \x00A\x00A\x00A 1",
-		"test t  a 0 \x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00C 2",
-		"test t  a 0 \x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00B\x00B\x00B\x00B\x00B 1",
-		"test t  a 0 \x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00C\x00C\x00C 1",
-		"test t  a 0 \x00A\x00B 1",
-		"test t  a 0 \x00B 1",
-		"test t  a 0 \x00B\x00A 1",
-		"test t  a 0 \x00B\x00B 1",
-		"test t  a 0 \x00B\x00B\x00B 1",
-		"test t  a 0 \x00B\x00B\x00B\x00B 1",
-		"test t  a 0 \x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00R\x00R\x00R 1",
-		"test t  a 0 \x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00R 1",
-		"test t  a 0 \x00B\x00B\x00B\x00B\x00B\x00D\x00D\x00D\x00D\x00D\x00D\x00D 1",
-		"test t  ia 1 \x00A 1",
-		"test t  ia 1 \x00A\x00A 1",
-		"test t  ia 1 \x00A\x00A\x00A 1",
-		"test t  ia 1 \x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00C 2",
-		"test t  ia 1 \x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00B\x00B\x00B\x00B\x00B 1",
-		"test t  ia 1 \x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00C\x00C\x00C 1",
-		"test t  ia 1 \x00A\x00B 1",
-		"test t  ia 1 \x00B 1",
-		"test t  ia 1 \x00B\x00A 1",
-		"test t  ia 1 \x00B\x00B 1",
-		"test t  ia 1 \x00B\x00B\x00B 1",
-		"test t  ia 1 \x00B\x00B\x00B\x00B 1",
-		"test t  ia 1 \x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00R\x00R\x00R 1",
-		"test t  ia 1 \x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00R 1",
-		"test t  ia 1 \x00B\x00B\x00B\x00B\x00B\x00D\x00D\x00D\x00D\x00D\x00D\x00D 1",
-		"test t  ia10 1 \x00A 1",
-		"test t  ia10 1 \x00A\x00A 1",
-		"test t  ia10 1 \x00A\x00A\x00A 1",
-		"test t  ia10 1 \x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A 2",
-		"test t  ia10 1 \x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00B\x00B\x00B 2",
-		"test t  ia10 1 \x00A\x00B 1",
-		"test t  ia10 1 \x00B 1",
-		"test t  ia10 1 \x00B\x00A 1",
-		"test t  ia10 1 \x00B\x00B 1",
-		"test t  ia10 1 \x00B\x00B\x00B 1",
-		"test t  ia10 1 \x00B\x00B\x00B\x00B 1",
-		"test t  ia10 1 \x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B 2",
-		"test t  ia10 1 \x00B\x00B\x00B\x00B\x00B\x00D\x00D\x00D\x00D\x00D 1",
-		"test t  ia3 1 \x00A 1",
-		"test t  ia3 1 \x00A\x00A 1",
-		"test t  ia3 1 \x00A\x00A\x00A 5",
-		"test t  ia3 1 \x00A\x00B 1",
-		"test t  ia3 1 \x00B 1",
-		"test t  ia3 1 \x00B\x00A 1",
-		"test t  ia3 1 \x00B\x00B 1",
-		"test t  ia3 1 \x00B\x00B\x00B 5",
+		"test t  a 0 \x00A 1 1 1.875",
+		"test t  a 0 \x00A\x00A 1 1 1.875",
+		"test t  a 0 \x00A\x00A\x00A 1 1 1.875",
+		"test t  a 0 \x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00C 2 1 1.875",
+		"test t  a 0 \x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00B\x00B\x00B\x00B\x00B 1 1 1.875",
+		"test t  a 0 \x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00C\x00C\x00C 1 1 1.875",
+		"test t  a 0 \x00A\x00B 1 1 1.875",
+		"test t  a 0 \x00B 1 1 1.875",
+		"test t  a 0 \x00B\x00A 1 1 1.875",
+		"test t  a 0 \x00B\x00B 1 1 1.875",
+		"test t  a 0 \x00B\x00B\x00B 1 1 1.875",
+		"test t  a 0 \x00B\x00B\x00B\x00B 1 1 1.875",
+		"test t  a 0 \x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00R\x00R\x00R 1 1 1.875",
+		"test t  a 0 \x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00R 1 1 1.875",
+		"test t  a 0 \x00B\x00B\x00B\x00B\x00B\x00D\x00D\x00D\x00D\x00D\x00D\x00D 1 1 1.875",
+		"test t  ia 1 \x00A 1 1 1.875",
+		"test t  ia 1 \x00A\x00A 1 1 1.875",
+		"test t  ia 1 \x00A\x00A\x00A 1 1 1.875",
+		"test t  ia 1 \x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00C 2 1 1.875",
+		"test t  ia 1 \x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00B\x00B\x00B\x00B\x00B 1 1 1.875",
+		"test t  ia 1 \x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00C\x00C\x00C 1 1 1.875",
+		"test t  ia 1 \x00A\x00B 1 1 1.875",
+		"test t  ia 1 \x00B 1 1 1.875",
+		"test t  ia 1 \x00B\x00A 1 1 1.875",
+		"test t  ia 1 \x00B\x00B 1 1 1.875",
+		"test t  ia 1 \x00B\x00B\x00B 1 1 1.875",
+		"test t  ia 1 \x00B\x00B\x00B\x00B 1 1 1.875",
+		"test t  ia 1 \x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00R\x00R\x00R 1 1 1.875",
+		"test t  ia 1 \x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00R 1 1 1.875",
+		"test t  ia 1 \x00B\x00B\x00B\x00B\x00B\x00D\x00D\x00D\x00D\x00D\x00D\x00D 1 1 1.875",
+		"test t  ia10 1 \x00A 1 1 1.625",
+		"test t  ia10 1 \x00A\x00A 1 1 1.625",
+		"test t  ia10 1 \x00A\x00A\x00A 1 1 1.625",
+		"test t  ia10 1 \x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A\x00A 2 1 1.625",
+		"test t  ia10 1 \x00A\x00A\x00A\x00A\x00A\x00B\x00B\x00B\x00B\x00B 2 1 1.625",
+		"test t  ia10 1 \x00A\x00B 1 1 1.625",
+		"test t  ia10 1 \x00B 1 1 1.625",
+		"test t  ia10 1 \x00B\x00A 1 1 1.625",
+		"test t  ia10 1 \x00B\x00B 1 1 1.625",
+		"test t  ia10 1 \x00B\x00B\x00B 1 1 1.625",
+		"test t  ia10 1 \x00B\x00B\x00B\x00B 1 1 1.625",
+		"test t  ia10 1 \x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B\x00B 2 1 1.625",
+		"test t  ia10 1 \x00B\x00B\x00B\x00B\x00B\x00D\x00D\x00D\x00D\x00D 1 1 1.625",
+		"test t  ia3 1 \x00A 1 1 2.5",
+		"test t  ia3 1 \x00A\x00A 1 1 2.5",
+		"test t  ia3 1 \x00A\x00A\x00A 5 1 2.5",
+		"test t  ia3 1 \x00A\x00B 1 1 2.5",
+		"test t  ia3 1 \x00B 1 1 2.5",
+		"test t  ia3 1 \x00B\x00A 1 1 2.5",
+		"test t  ia3 1 \x00B\x00B 1 1 2.5",
+		"test t  ia3 1 \x00B\x00B\x00B 5 1 2.5",
 	))
 	tk.MustQuery("select is_index, hist_id, distinct_count, null_count, stats_ver, correlation from mysql.stats_histograms").Sort().Check(testkit.Rows(
 		"0 1 15 0 2 0.8411764705882353",
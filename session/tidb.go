@@ -370,5 +370,8 @@ func ResultSetToStringSlice(ctx context.Context, s Session, rs sqlexec.RecordSet
 
 // Session errors.
 var (
-	ErrForUpdateCantRetry = dbterror.ClassSession.NewStd(errno.ErrForUpdateCantRetry)
+	ErrForUpdateCantRetry             = dbterror.ClassSession.NewStd(errno.ErrForUpdateCantRetry)
+	ErrNonTransactionalDMLUnsupported = dbterror.ClassSession.NewStd(errno.ErrNonTransactionalDMLUnsupported)
+	ErrNonTransactionalJobFailure     = dbterror.ClassSession.NewStd(errno.ErrNonTransactionalJobFailure)
+	ErrMustChangePassword             = dbterror.ClassSession.NewStd(errno.ErrMustChangePassword)
 )
@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sli"
@@ -271,6 +272,7 @@ func (txn *LazyTxn) changeToInvalid() {
 
 	txn.mu.Lock()
 	defer txn.mu.Unlock()
+	txninfo.Recorder.OnTrxEnd(&txn.mu.TxnInfo)
 	txn.mu.TxnInfo = txninfo.TxnInfo{}
 }
 
@@ -539,6 +541,7 @@ func (s *session) StmtCommit() {
 	}()
 
 	st := &s.txn
+	s.recordSavepointValues()
 	st.flushStmtBuf()
 
 	// Need to flush binlog.
@@ -548,6 +551,57 @@ func (s *session) StmtCommit() {
 	}
 }
 
+// recordSavepointValues records the values before the statement of the keys modified by it, if they are modified for
+// the first time after the latest savepoint. Rolling back to the savepoint restores these values.
+func (s *session) recordSavepointValues() {
+	txnCtx := s.sessionVars.TxnCtx
+	st := &s.txn
+	if len(txnCtx.Savepoints) == 0 || st.stagingHandle == kv.InvalidStagingHandle {
+		return
+	}
+	ctx := context.Background()
+	buf := st.GetMemBuffer()
+	var keys []kv.Key
+	needRecord := false
+	visited := make(map[string]struct{})
+	buf.InspectStage(st.stagingHandle, func(key kv.Key, _ kv.KeyFlags, _ []byte) {
+		if _, ok := visited[string(key)]; ok {
+			return
+		}
+		visited[string(key)] = struct{}{}
+		keys = append(keys, key.Clone())
+		needRecord = needRecord || txnCtx.NeedSavepointValue(key)
+	})
+	if !needRecord {
+		return
+	}
+	// Discard the changes of the statement to read the values before it, and then apply the changes again.
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		val, err := buf.Get(ctx, key)
+		terror.Log(err)
+		values[i] = append([]byte{}, val...)
+	}
+	st.cleanupStmtBuf()
+	for _, key := range keys {
+		if !txnCtx.NeedSavepointValue(key) {
+			continue
+		}
+		val, err := buf.Get(ctx, key)
+		txnCtx.RecordSavepointValue(key, variable.SavepointValue{Value: append([]byte{}, val...), Exists: err == nil})
+	}
+	st.initStmtBuf()
+	for i, key := range keys {
+		var err error
+		if len(values[i]) == 0 {
+			err = buf.Delete(key)
+		} else {
+			err = buf.Set(key, values[i])
+		}
+		terror.Log(err)
+	}
+}
+
 // StmtRollback implements the sessionctx.Context interface.
 func (s *session) StmtRollback() {
 	s.txn.cleanup()
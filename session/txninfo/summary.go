@@ -0,0 +1,177 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txninfo
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+const (
+	// SummaryDigestStr is the column name of the TRX_SUMMARY table's Digest column.
+	SummaryDigestStr = "DIGEST"
+	// SummaryAllSQLDigestsStr is the column name of the TRX_SUMMARY table's AllSQLDigests column.
+	SummaryAllSQLDigestsStr = "ALL_SQL_DIGESTS"
+)
+
+// trxSummaries is a LRU of the SQL digests lists of the finished transactions, the transactions executing the same
+// statements are merged into one entry.
+type trxSummaries struct {
+	capacity uint
+	// cache is the LRU list, the front is the most recently finished one. Its elements are *trxSummary.
+	cache *list.List
+	elems map[uint64]*list.Element
+}
+
+type trxSummary struct {
+	digest        uint64
+	allSQLDigests []string
+}
+
+func newTrxSummaries(capacity uint) *trxSummaries {
+	return &trxSummaries{
+		capacity: capacity,
+		cache:    list.New(),
+		elems:    make(map[uint64]*list.Element),
+	}
+}
+
+func (s *trxSummaries) onTrxEnd(allSQLDigests []string) {
+	if s.capacity == 0 {
+		return
+	}
+	digest := digestOfSQLDigests(allSQLDigests)
+	if elem, ok := s.elems[digest]; ok {
+		s.cache.MoveToFront(elem)
+		return
+	}
+	s.elems[digest] = s.cache.PushFront(&trxSummary{digest: digest, allSQLDigests: allSQLDigests})
+	for uint(s.cache.Len()) > s.capacity {
+		s.evictOldest()
+	}
+}
+
+func (s *trxSummaries) evictOldest() {
+	elem := s.cache.Back()
+	s.cache.Remove(elem)
+	delete(s.elems, elem.Value.(*trxSummary).digest)
+}
+
+func (s *trxSummaries) resize(capacity uint) {
+	s.capacity = capacity
+	for uint(s.cache.Len()) > s.capacity {
+		s.evictOldest()
+	}
+}
+
+func (s *trxSummaries) dumpTrxSummary() [][]types.Datum {
+	rows := make([][]types.Datum, 0, s.cache.Len())
+	for elem := s.cache.Front(); elem != nil; elem = elem.Next() {
+		summary := elem.Value.(*trxSummary)
+		allSQLDigests, err := json.Marshal(summary.allSQLDigests)
+		if err != nil {
+			logutil.BgLogger().Warn("Failed to marshal sql digests list as json", zap.Uint64("digest", summary.digest))
+			continue
+		}
+		rows = append(rows, []types.Datum{
+			types.NewDatum(fmt.Sprintf("%016x", summary.digest)),
+			types.NewDatum(string(allSQLDigests)),
+		})
+	}
+	return rows
+}
+
+func digestOfSQLDigests(allSQLDigests []string) uint64 {
+	h := fnv.New64a()
+	for _, digest := range allSQLDigests {
+		// The digests are hex strings, so they can be separated by any non-hex character.
+		_, _ = h.Write([]byte(digest))
+		_, _ = h.Write([]byte{','})
+	}
+	return h.Sum64()
+}
+
+// TrxHistoryRecorder records the statements executed by the finished transactions, which are shown in the
+// TRX_SUMMARY table. All its public APIs are thread safe.
+type TrxHistoryRecorder struct {
+	mu          sync.Mutex
+	minDuration time.Duration
+	summaries   *trxSummaries
+}
+
+// NewTrxHistoryRecorder creates a TrxHistoryRecorder.
+func NewTrxHistoryRecorder(summariesCap uint) *TrxHistoryRecorder {
+	return &TrxHistoryRecorder{
+		summaries: newTrxSummaries(summariesCap),
+	}
+}
+
+// Recorder is the global TrxHistoryRecorder.
+// The real capacity should be initialized with `ResizeSummaries` in `setGlobalVars` in tidb-server/main.go.
+var Recorder = NewTrxHistoryRecorder(0)
+
+// OnTrxEnd records the transaction when it's committed or rolled back. The transactions which execute no statement
+// or last shorter than the min duration are ignored.
+func (recorder *TrxHistoryRecorder) OnTrxEnd(info *TxnInfo) {
+	if info.StartTS == 0 || len(info.AllSQLDigests) == 0 {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.summaries.capacity == 0 {
+		return
+	}
+	if recorder.minDuration > 0 && time.Since(oracle.GetTimeFromTS(info.StartTS)) < recorder.minDuration {
+		return
+	}
+	recorder.summaries.onTrxEnd(info.AllSQLDigests)
+}
+
+// DumpTrxSummary dumps the rows of the TRX_SUMMARY table.
+func (recorder *TrxHistoryRecorder) DumpTrxSummary() [][]types.Datum {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return recorder.summaries.dumpTrxSummary()
+}
+
+// ResizeSummaries changes the max count of the transaction summaries.
+func (recorder *TrxHistoryRecorder) ResizeSummaries(capacity uint) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.summaries.resize(capacity)
+}
+
+// SetMinDuration sets the min duration of the transactions to be recorded.
+func (recorder *TrxHistoryRecorder) SetMinDuration(minDuration time.Duration) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.minDuration = minDuration
+}
+
+// Clean removes all the recorded transactions.
+func (recorder *TrxHistoryRecorder) Clean() {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.summaries = newTrxSummaries(recorder.summaries.capacity)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/owner"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/kvcache"
@@ -131,11 +132,17 @@ type Context interface {
 	PrepareTSFuture(ctx context.Context)
 	// StoreIndexUsage stores the index usage information.
 	StoreIndexUsage(tblID int64, idxID int64, rowsSelected int64)
+	// UpdateColStatsUsage updates the column stats usage.
+	UpdateColStatsUsage(predicateColumns []model.TableColumnID)
 	// GetTxnWriteThroughputSLI returns the TxnWriteThroughputSLI.
 	GetTxnWriteThroughputSLI() *sli.TxnWriteThroughputSLI
 	// GetBuiltinFunctionUsage returns the BuiltinFunctionUsage of current Context, which is not thread safe.
 	// Use primitive map type to prevent circular import. Should convert it to telemetry.BuiltinFunctionUsage before using.
 	GetBuiltinFunctionUsage() map[string]uint32
+	// EncodeSessionStates encodes the states of the session so that they can be restored on another server.
+	EncodeSessionStates(ctx context.Context, sessionStates *sessionstates.SessionStates) error
+	// DecodeSessionStates restores the states encoded by EncodeSessionStates into the session.
+	DecodeSessionStates(ctx context.Context, sessionStates *sessionstates.SessionStates) error
 }
 
 type basicCtxType int
@@ -0,0 +1,62 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionstates
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/errno"
+	ptypes "github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/dbterror"
+)
+
+// ErrCannotMigrateSession indicates the session is in a state that can't be migrated to another server.
+var ErrCannotMigrateSession = dbterror.ClassSession.NewStd(errno.ErrCannotMigrateSession)
+
+// PreparedStmtInfo contains the information about a prepared statement, which is re-prepared by its text when the
+// session is restored.
+type PreparedStmtInfo struct {
+	// Name is the name of the statement prepared by the `PREPARE` statement, it's empty for the statements prepared
+	// by the binary protocol.
+	Name     string `json:"name,omitempty"`
+	StmtText string `json:"text"`
+	StmtDB   string `json:"db,omitempty"`
+	// ParamTypes and BoundParams are the states of the statements prepared by the binary protocol.
+	ParamTypes  []byte   `json:"types,omitempty"`
+	BoundParams [][]byte `json:"bound-params,omitempty"`
+}
+
+// SessionStates contains all the states in the session that should be migrated when the session is moved to another
+// server. It's shown by `SHOW SESSION_STATES` and restored by `SET SESSION_STATES`.
+type SessionStates struct {
+	UserVars       map[string]*types.Datum      `json:"user-var-values,omitempty"`
+	UserVarTypes   map[string]*ptypes.FieldType `json:"user-var-types,omitempty"`
+	SystemVars     map[string]string            `json:"sys-vars,omitempty"`
+	PreparedStmts  map[uint32]*PreparedStmtInfo `json:"prepared-stmts,omitempty"`
+	PreparedStmtID uint32                       `json:"prepared-stmt-id,omitempty"`
+	CurrentDB      string                       `json:"current-db,omitempty"`
+	// ResourceGroupName is the resource group set by `SET RESOURCE GROUP`.
+	ResourceGroupName string `json:"resource-group,omitempty"`
+}
+
+// Handler encodes and decodes the session states which are not kept in the session, such as the states of the
+// statements prepared by the binary protocol, which are kept by the server.
+type Handler interface {
+	// EncodeSessionStates is called after the session encodes its own states.
+	EncodeSessionStates(ctx context.Context, sessionStates *SessionStates) error
+	// DecodeSessionStates is called after the session decodes its own states.
+	DecodeSessionStates(ctx context.Context, sessionStates *SessionStates) error
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionstates_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func showSessionStates(t *testing.T, tk *testkit.TestKit) string {
+	rows := tk.MustQuery("show session_states").Rows()
+	require.Len(t, rows, 1)
+	// The session token is NULL because the signing certificate isn't configured.
+	require.Equal(t, "<nil>", rows[0][1])
+	return rows[0][0].(string)
+}
+
+func checkCannotMigrate(t *testing.T, tk *testkit.TestKit) {
+	err := tk.QueryToErr("show session_states")
+	require.True(t, terror.ErrorEqual(err, sessionstates.ErrCannotMigrateSession), "%v", err)
+}
+
+func TestMigrateSessionStates(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	tk1.MustExec("create table t(a int primary key, b varchar(10))")
+	tk1.MustExec("insert into t values(1, 'a'), (2, 'b')")
+	tk1.MustExec("set @i = 1, @s = 'str', @d = 1.5, @n = null")
+	tk1.MustExec("set @@sql_mode = 'ANSI_QUOTES'")
+	tk1.MustExec("set @@div_precision_increment = 8")
+	tk1.MustExec("prepare stmt1 from 'select b from t where a = ?'")
+	tk1.MustExec("use mysql")
+	states := showSessionStates(t, tk1)
+
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec(fmt.Sprintf("set session_states '%s'", states))
+	tk2.MustQuery("select database()").Check(testkit.Rows("mysql"))
+	tk2.MustQuery("select @i, @s, @d, @n").Check(testkit.Rows("1 str 1.5 <nil>"))
+	tk2.MustQuery("select @@sql_mode, @@div_precision_increment").Check(testkit.Rows("ANSI_QUOTES 8"))
+	// The prepared statement is re-prepared in its original database.
+	tk2.MustQuery("execute stmt1 using @i").Check(testkit.Rows("a"))
+	tk2.MustExec("prepare stmt2 from 'select 1'")
+	tk2.MustQuery("execute stmt2").Check(testkit.Rows("1"))
+	tk2.MustExec("deallocate prepare stmt1")
+	tk2.MustGetErrCode("execute stmt1 using @i", errno.ErrPreparedStmtNotFound)
+
+	// The unchanged variables are not migrated.
+	tk3 := testkit.NewTestKit(t, store)
+	require.NotContains(t, showSessionStates(t, tk3), "div_precision_increment")
+	require.Contains(t, states, "div_precision_increment")
+}
+
+func TestCannotMigrateSession(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int)")
+
+	tk.MustExec("begin")
+	checkCannotMigrate(t, tk)
+	tk.MustExec("rollback")
+	showSessionStates(t, tk)
+
+	tk.MustExec("create temporary table tmp(a int)")
+	checkCannotMigrate(t, tk)
+	tk.MustExec("drop table tmp")
+	showSessionStates(t, tk)
+
+	require.Error(t, tk.ExecToErr("set session_states 'invalid'"))
+}
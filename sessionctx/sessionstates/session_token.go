@@ -0,0 +1,158 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionstates
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+)
+
+// tokenLifetime is the time before the session token expires, the session should be migrated within it.
+const tokenLifetime = 15 * time.Minute
+
+// SessionToken is used to authenticate the user on the new server when the session is migrated. Because the user
+// password isn't known by the proxy, the token is signed by the certificate shared by all the servers in the cluster
+// instead.
+type SessionToken struct {
+	Username   string    `json:"username"`
+	SignTime   time.Time `json:"sign-time"`
+	ExpireTime time.Time `json:"expire-time"`
+	Signature  []byte    `json:"signature,omitempty"`
+}
+
+// content returns the signed content of the token. The username is put at last so that the content is unambiguous.
+func (token *SessionToken) content() []byte {
+	return []byte(fmt.Sprintf("%d|%d|%s", token.SignTime.UnixNano(), token.ExpireTime.UnixNano(), token.Username))
+}
+
+type signingCert struct {
+	sync.Mutex
+	certPath string
+	keyPath  string
+	cert     *x509.Certificate
+	key      crypto.Signer
+}
+
+var globalSigningCert signingCert
+
+// load returns the signing certificate in the config, it's reloaded once the paths in the config are changed.
+func (sc *signingCert) load() (*x509.Certificate, crypto.Signer, error) {
+	security := config.GetGlobalConfig().Security
+	sc.Lock()
+	defer sc.Unlock()
+	if sc.cert != nil && sc.certPath == security.SessionTokenSigningCert && sc.keyPath == security.SessionTokenSigningKey {
+		return sc.cert, sc.key, nil
+	}
+	if len(security.SessionTokenSigningCert) == 0 || len(security.SessionTokenSigningKey) == 0 {
+		return nil, nil, errors.New("the session token signing certificate is not configured")
+	}
+	tlsCert, err := tls.LoadX509KeyPair(security.SessionTokenSigningCert, security.SessionTokenSigningKey)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	cert, err := x509.ParseCertificate(tlsCert.Certificate[0])
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	key, ok := tlsCert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, errors.New("the session token signing key can't be used to sign")
+	}
+	sc.certPath, sc.keyPath = security.SessionTokenSigningCert, security.SessionTokenSigningKey
+	sc.cert, sc.key = cert, key
+	return cert, key, nil
+}
+
+// signatureAlgorithm returns the algorithm to sign the content with the key of the certificate.
+func signatureAlgorithm(cert *x509.Certificate) (x509.SignatureAlgorithm, crypto.Hash, error) {
+	switch cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return x509.SHA256WithRSA, crypto.SHA256, nil
+	case *ecdsa.PublicKey:
+		return x509.ECDSAWithSHA256, crypto.SHA256, nil
+	case ed25519.PublicKey:
+		// Ed25519 signs the content directly instead of its digest.
+		return x509.PureEd25519, crypto.Hash(0), nil
+	default:
+		return x509.UnknownSignatureAlgorithm, 0, errors.Errorf("unsupported public key algorithm %s", cert.PublicKeyAlgorithm)
+	}
+}
+
+// CreateSessionToken creates a token for the user, which is valid on all the servers sharing the signing certificate.
+func CreateSessionToken(username string) (*SessionToken, error) {
+	cert, key, err := globalSigningCert.load()
+	if err != nil {
+		return nil, err
+	}
+	_, hash, err := signatureAlgorithm(cert)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	token := &SessionToken{
+		Username:   username,
+		SignTime:   now,
+		ExpireTime: now.Add(tokenLifetime),
+	}
+	digest := token.content()
+	if hash != 0 {
+		sum := sha256.Sum256(digest)
+		digest = sum[:]
+	}
+	if token.Signature, err = key.Sign(rand.Reader, digest, hash); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return token, nil
+}
+
+// ValidateSessionToken checks that the token is signed by the signing certificate, unexpired and belongs to the user.
+func ValidateSessionToken(tokenBytes []byte, username string) error {
+	var token SessionToken
+	if err := json.Unmarshal(tokenBytes, &token); err != nil {
+		return errors.Trace(err)
+	}
+	if token.Username != username {
+		return errors.Errorf("the session token belongs to user '%s'", token.Username)
+	}
+	if time.Now().After(token.ExpireTime) {
+		return errors.Errorf("the session token expired at %s", token.ExpireTime)
+	}
+	// The sign time isn't compared with the local time because the clocks of the servers may be skewed.
+	if token.ExpireTime.Sub(token.SignTime) > tokenLifetime {
+		return errors.New("the session token has an invalid lifetime")
+	}
+	cert, _, err := globalSigningCert.load()
+	if err != nil {
+		return err
+	}
+	algo, _, err := signatureAlgorithm(cert)
+	if err != nil {
+		return err
+	}
+	return errors.Trace(cert.CheckSignature(algo, token.content(), token.Signature))
+}
@@ -0,0 +1,106 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionstates
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/stretchr/testify/require"
+)
+
+func createSigningCert(t *testing.T, dir string) (certPath, keyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "tidb"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certPath, keyPath = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), 0600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0600))
+	return
+}
+
+func TestSessionToken(t *testing.T) {
+	_, err := CreateSessionToken("root")
+	require.Error(t, err)
+
+	certPath, keyPath := createSigningCert(t, t.TempDir())
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Security.SessionTokenSigningCert = certPath
+		conf.Security.SessionTokenSigningKey = keyPath
+	})
+
+	token, err := CreateSessionToken("root")
+	require.NoError(t, err)
+	tokenBytes, err := json.Marshal(token)
+	require.NoError(t, err)
+	require.NoError(t, ValidateSessionToken(tokenBytes, "root"))
+	require.Error(t, ValidateSessionToken(tokenBytes, "u1"))
+	require.Error(t, ValidateSessionToken([]byte("invalid"), "root"))
+
+	// The forged tokens are rejected.
+	forged := *token
+	forged.Username = "u1"
+	tokenBytes, err = json.Marshal(&forged)
+	require.NoError(t, err)
+	require.Error(t, ValidateSessionToken(tokenBytes, "u1"))
+	forged = *token
+	forged.ExpireTime = forged.ExpireTime.Add(time.Minute)
+	tokenBytes, err = json.Marshal(&forged)
+	require.NoError(t, err)
+	require.Error(t, ValidateSessionToken(tokenBytes, "root"))
+
+	// The expired tokens are rejected.
+	expired := *token
+	expired.SignTime = time.Now().Add(-2 * tokenLifetime)
+	expired.ExpireTime = expired.SignTime.Add(tokenLifetime)
+	tokenBytes, err = json.Marshal(&expired)
+	require.NoError(t, err)
+	err = ValidateSessionToken(tokenBytes, "root")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expired")
+
+	// The tokens signed by another certificate are rejected.
+	certPath2, keyPath2 := createSigningCert(t, t.TempDir())
+	token, err = CreateSessionToken("root")
+	require.NoError(t, err)
+	tokenBytes, err = json.Marshal(token)
+	require.NoError(t, err)
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Security.SessionTokenSigningCert = certPath2
+		conf.Security.SessionTokenSigningKey = keyPath2
+	})
+	require.Error(t, ValidateSessionToken(tokenBytes, "root"))
+}
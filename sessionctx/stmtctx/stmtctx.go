@@ -22,6 +22,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -59,6 +60,13 @@ type SQLWarn struct {
 	Err   error
 }
 
+// DMLError relates a row rejected by a DML statement and the reason.
+type DMLError struct {
+	// Row is the values of the rejected row.
+	Row string
+	Err error
+}
+
 // StatementContext contains variables for a statement.
 // It should be reset before executing a statement.
 type StatementContext struct {
@@ -90,10 +98,17 @@ type StatementContext struct {
 	IgnoreNoPartition      bool
 	SkipPlanCache          bool
 	IgnoreExplainIDSuffix  bool
+	// CollectDMLErrors indicates whether to collect the rows rejected by the DML statement, see AppendDMLError.
+	CollectDMLErrors bool
+	// DMLErrorsMaxSize is the max bytes of the rows rejected by the DML statement to collect.
+	DMLErrorsMaxSize int64
 	// If the select statement was like 'select * from t as of timestamp ...' or in a stale read transaction
 	// or is affected by the tidb_read_staleness session variable, then the statement will be makred as isStaleness
 	// in stmtCtx
 	IsStaleness bool
+	// RCCheckTS indicates the read-only statement in the RC transaction reads data with the last fetched timestamp
+	// instead of a new one, and the storage reports a write conflict if any newer version is met.
+	RCCheckTS bool
 	// mu struct holds variables that change during execution.
 	mu struct {
 		sync.Mutex
@@ -121,9 +136,14 @@ type StatementContext struct {
 
 		message        string
 		warnings       []SQLWarn
+		dmlErrors      []DMLError
 		errorCount     uint16
 		execDetails    execdetails.ExecDetails
 		allExecDetails []*execdetails.ExecDetails
+
+		dmlErrorsSize int64
+		// dmlErrorsFull indicates that some rejected rows aren't collected because of DMLErrorsMaxSize.
+		dmlErrorsFull bool
 	}
 	// PrevAffectedRows is the affected-rows value(DDL is 0, DML is the number of affected rows).
 	PrevAffectedRows int64
@@ -137,6 +157,13 @@ type StatementContext struct {
 	BaseRowID int64
 	MaxRowID  int64
 
+	// RunawayCooldown is set by the runaway query watcher when the statement exceeds the QUERY_LIMIT of its resource
+	// group with the COOLDOWN action.
+	RunawayCooldown atomic2.Bool
+	// MaxExecTimeExceeded is set when the statement is killed because it exceeds max_execution_time, so the
+	// interruption is reported as ErrMaxExecTimeExceeded instead of ErrQueryInterrupted.
+	MaxExecTimeExceeded atomic2.Bool
+
 	// Copied from SessionVars.TimeZone.
 	TimeZone         *time.Location
 	Priority         mysql.PriorityEnum
@@ -466,6 +493,42 @@ func (sc *StatementContext) GetWarnings() []SQLWarn {
 	return warns
 }
 
+// AppendDMLError appends a row rejected by the DML statement if CollectDMLErrors is set. Unlike
+// the warnings, the rejected rows are kept with their values and aren't limited by max_error_count,
+// but by DMLErrorsMaxSize bytes which are also consumed by the MemTracker of the statement. A warning
+// is appended when the first row isn't collected because of the limit.
+func (sc *StatementContext) AppendDMLError(row string, err error) {
+	if !sc.CollectDMLErrors {
+		return
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	size := int64(len(row) + len(err.Error()))
+	if sc.mu.dmlErrorsSize+size > sc.DMLErrorsMaxSize || len(sc.mu.dmlErrors) >= math.MaxUint16 {
+		if !sc.mu.dmlErrorsFull {
+			sc.mu.dmlErrorsFull = true
+			if len(sc.mu.warnings) < math.MaxUint16 {
+				sc.mu.warnings = append(sc.mu.warnings, SQLWarn{WarnLevelWarning, errors.Errorf("the rejected rows exceed %d bytes, the rest of them are not collected", sc.DMLErrorsMaxSize)})
+			}
+		}
+		return
+	}
+	sc.mu.dmlErrors = append(sc.mu.dmlErrors, DMLError{Row: row, Err: err})
+	sc.mu.dmlErrorsSize += size
+	if sc.MemTracker != nil {
+		sc.MemTracker.Consume(size)
+	}
+}
+
+// GetDMLErrors gets the rows rejected by the DML statement.
+func (sc *StatementContext) GetDMLErrors() []DMLError {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	errs := make([]DMLError, len(sc.mu.dmlErrors))
+	copy(errs, sc.mu.dmlErrors)
+	return errs
+}
+
 // TruncateWarnings truncates warnings begin from start and returns the truncated warnings.
 func (sc *StatementContext) TruncateWarnings(start int) []SQLWarn {
 	sc.mu.Lock()
@@ -591,6 +654,9 @@ func (sc *StatementContext) resetMuForRetry() {
 	sc.mu.message = ""
 	sc.mu.errorCount = 0
 	sc.mu.warnings = nil
+	sc.mu.dmlErrors = nil
+	sc.mu.dmlErrorsSize = 0
+	sc.mu.dmlErrorsFull = false
 	sc.mu.execDetails = execdetails.ExecDetails{}
 	sc.mu.allExecDetails = make([]*execdetails.ExecDetails, 0, 4)
 }
@@ -614,6 +680,7 @@ func (sc *StatementContext) MergeExecDetails(details *execdetails.ExecDetails, c
 		sc.mu.execDetails.CopTime += details.CopTime
 		sc.mu.execDetails.BackoffTime += details.BackoffTime
 		sc.mu.execDetails.RequestCount++
+		sc.mu.execDetails.RUConsumed += details.RUConsumed
 		sc.MergeScanDetail(details.ScanDetail)
 		sc.MergeTimeDetail(details.TimeDetail)
 		sc.mu.allExecDetails = append(sc.mu.allExecDetails, details)
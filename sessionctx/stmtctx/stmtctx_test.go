@@ -15,12 +15,14 @@
 package stmtctx_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/util"
 )
@@ -90,3 +92,28 @@ func TestStatementContextPushDownFLags(t *testing.T) {
 		require.Equal(t, tt.out, got)
 	}
 }
+
+func TestDMLErrorsMaxSize(t *testing.T) {
+	sc := &stmtctx.StatementContext{CollectDMLErrors: true, DMLErrorsMaxSize: 100}
+	sc.InitMemTracker(memory.LabelForSQLText, -1)
+	err := errors.New("0123456789")
+	row := "0123456789012345678901234567890123456789"
+	for i := 0; i < 3; i++ {
+		sc.AppendDMLError(row, err)
+	}
+	// Only the first 2 rows are collected within 100 bytes, the bytes are consumed by the MemTracker.
+	require.Len(t, sc.GetDMLErrors(), 2)
+	require.Equal(t, int64(100), sc.MemTracker.BytesConsumed())
+	warnings := sc.GetWarnings()
+	require.Len(t, warnings, 1)
+	require.EqualError(t, warnings[0].Err, "the rejected rows exceed 100 bytes, the rest of them are not collected")
+
+	// The warning is appended only once.
+	sc.AppendDMLError(row, err)
+	require.Len(t, sc.GetDMLErrors(), 2)
+	require.Len(t, sc.GetWarnings(), 1)
+
+	sc.ResetForRetry()
+	sc.AppendDMLError(row, err)
+	require.Len(t, sc.GetDMLErrors(), 1)
+}
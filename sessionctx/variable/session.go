@@ -16,6 +16,7 @@ package variable
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/binary"
 	"fmt"
@@ -42,6 +43,9 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	ptypes "github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tidb/privilege/conn"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -183,6 +187,28 @@ type TransactionContext struct {
 
 	// CachedTables is not nil if the transaction write on cached table.
 	CachedTables map[int64]interface{}
+
+	// Savepoints contains the savepoints of the transaction, in the order they are set.
+	Savepoints []SavepointRecord
+}
+
+// SavepointRecord is the record of a savepoint in the transaction.
+type SavepointRecord struct {
+	// Name is the name of the savepoint. It's empty if the savepoint is replaced by a later one with the same name.
+	Name string
+	// memBufferUndo keeps the values in the memory buffer when the savepoint is set, of the keys modified after it
+	// and before the next savepoint.
+	memBufferUndo map[string]SavepointValue
+	// tableDeltaMap and pessimisticLockCache are the snapshots of the transaction context when the savepoint is set.
+	tableDeltaMap        map[int64]TableDelta
+	pessimisticLockCache map[string][]byte
+}
+
+// SavepointValue is the value of a key in the memory buffer when a savepoint is set.
+type SavepointValue struct {
+	Value []byte
+	// Exists indicates whether the key has a value in the memory buffer. The value is empty if the key is deleted.
+	Exists bool
 }
 
 // GetShard returns the shard prefix for the next `count` rowids.
@@ -249,6 +275,23 @@ func (tc *TransactionContext) UpdateDeltaForTable(physicalTableID int64, delta i
 	tc.TableDeltaMap[physicalTableID] = item
 }
 
+// UpdateIndexWritesForTable increases the number of the rows written to the index of some table.
+func (tc *TransactionContext) UpdateIndexWritesForTable(physicalTableID int64, tableID int64, indexID int64) {
+	tc.tdmLock.Lock()
+	defer tc.tdmLock.Unlock()
+	if tc.TableDeltaMap == nil {
+		tc.TableDeltaMap = make(map[int64]TableDelta)
+	}
+	item := tc.TableDeltaMap[physicalTableID]
+	if item.IndexWrites == nil {
+		item.IndexWrites = make(map[int64]int64)
+	}
+	item.IndexWrites[indexID]++
+	item.TableID = physicalTableID
+	item.LogicalTableID = tableID
+	tc.TableDeltaMap[physicalTableID] = item
+}
+
 // GetKeyInPessimisticLockCache gets a key in pessimistic lock cache.
 func (tc *TransactionContext) GetKeyInPessimisticLockCache(key kv.Key) (val []byte, ok bool) {
 	if tc.pessimisticLockCache == nil {
@@ -269,6 +312,123 @@ func (tc *TransactionContext) SetPessimisticLockCache(key kv.Key, val []byte) {
 	tc.pessimisticLockCache[string(key)] = val
 }
 
+// AddSavepoint records a savepoint with the name, a previous savepoint with the same name is replaced.
+func (tc *TransactionContext) AddSavepoint(name string) {
+	for i := range tc.Savepoints {
+		if strings.EqualFold(tc.Savepoints[i].Name, name) {
+			tc.Savepoints[i].Name = ""
+		}
+	}
+	tc.tdmLock.Lock()
+	tableDeltaMap := copyTableDeltaMap(tc.TableDeltaMap)
+	tc.tdmLock.Unlock()
+	var pessimisticLockCache map[string][]byte
+	if tc.pessimisticLockCache != nil {
+		pessimisticLockCache = make(map[string][]byte, len(tc.pessimisticLockCache))
+		for key, val := range tc.pessimisticLockCache {
+			pessimisticLockCache[key] = val
+		}
+	}
+	tc.Savepoints = append(tc.Savepoints, SavepointRecord{
+		Name:                 name,
+		memBufferUndo:        make(map[string]SavepointValue),
+		tableDeltaMap:        tableDeltaMap,
+		pessimisticLockCache: pessimisticLockCache,
+	})
+}
+
+// FindSavepoint returns the index of the savepoint with the name, or -1 if it doesn't exist.
+func (tc *TransactionContext) FindSavepoint(name string) int {
+	for i := len(tc.Savepoints) - 1; i >= 0; i-- {
+		if strings.EqualFold(tc.Savepoints[i].Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// NeedSavepointValue checks whether the value of the key at the latest savepoint needs to be recorded before the
+// key is modified.
+func (tc *TransactionContext) NeedSavepointValue(key kv.Key) bool {
+	if len(tc.Savepoints) == 0 {
+		return false
+	}
+	_, ok := tc.Savepoints[len(tc.Savepoints)-1].memBufferUndo[string(key)]
+	return !ok
+}
+
+// RecordSavepointValue records the value of the key in the memory buffer at the latest savepoint.
+func (tc *TransactionContext) RecordSavepointValue(key kv.Key, val SavepointValue) {
+	tc.Savepoints[len(tc.Savepoints)-1].memBufferUndo[string(key)] = val
+}
+
+// SavepointValuesSince returns the values in the memory buffer at the savepoint at idx, of the keys modified after it.
+func (tc *TransactionContext) SavepointValuesSince(idx int) map[string]SavepointValue {
+	values := make(map[string]SavepointValue)
+	for i := len(tc.Savepoints) - 1; i >= idx; i-- {
+		for key, val := range tc.Savepoints[i].memBufferUndo {
+			values[key] = val
+		}
+	}
+	return values
+}
+
+// RollbackToSavepoint restores the transaction context to the savepoint at idx, and removes the savepoints after it.
+// The values in the memory buffer should be restored by the caller.
+func (tc *TransactionContext) RollbackToSavepoint(idx int) {
+	sp := &tc.Savepoints[idx]
+	sp.memBufferUndo = make(map[string]SavepointValue)
+	tc.tdmLock.Lock()
+	tc.TableDeltaMap = copyTableDeltaMap(sp.tableDeltaMap)
+	tc.tdmLock.Unlock()
+	tc.pessimisticLockCache = nil
+	for key, val := range sp.pessimisticLockCache {
+		tc.SetPessimisticLockCache(kv.Key(key), val)
+	}
+	tc.Savepoints = tc.Savepoints[:idx+1]
+}
+
+// ReleaseSavepoint removes the savepoint at idx and the savepoints after it. The values recorded by them are merged
+// into the previous savepoint.
+func (tc *TransactionContext) ReleaseSavepoint(idx int) {
+	if idx > 0 {
+		prev := tc.Savepoints[idx-1].memBufferUndo
+		for i := idx; i < len(tc.Savepoints); i++ {
+			for key, val := range tc.Savepoints[i].memBufferUndo {
+				if _, ok := prev[key]; !ok {
+					prev[key] = val
+				}
+			}
+		}
+	}
+	tc.Savepoints = tc.Savepoints[:idx]
+}
+
+func copyTableDeltaMap(m map[int64]TableDelta) map[int64]TableDelta {
+	if m == nil {
+		return nil
+	}
+	res := make(map[int64]TableDelta, len(m))
+	for id, delta := range m {
+		if delta.ColSize != nil {
+			colSize := make(map[int64]int64, len(delta.ColSize))
+			for colID, size := range delta.ColSize {
+				colSize[colID] = size
+			}
+			delta.ColSize = colSize
+		}
+		if delta.IndexWrites != nil {
+			indexWrites := make(map[int64]int64, len(delta.IndexWrites))
+			for idxID, count := range delta.IndexWrites {
+				indexWrites[idxID] = count
+			}
+			delta.IndexWrites = indexWrites
+		}
+		res[id] = delta
+	}
+	return res
+}
+
 // Cleanup clears up transaction info that no longer use.
 func (tc *TransactionContext) Cleanup() {
 	// tc.InfoSchema = nil; we cannot do it now, because some operation like handleFieldList depend on this.
@@ -279,6 +439,7 @@ func (tc *TransactionContext) Cleanup() {
 	tc.tdmLock.Unlock()
 	tc.pessimisticLockCache = nil
 	tc.IsStaleness = false
+	tc.Savepoints = nil
 }
 
 // ClearDelta clears the delta map.
@@ -455,6 +616,9 @@ type SessionVars struct {
 	// stmtVars variables are temporarily set by SET_VAR hint
 	// It only take effect for the duration of a single statement
 	stmtVars map[string]string
+	// stmtVarsRestore stores the session values of the variables applied by SET_VAR hint,
+	// they are restored when the statement vars are cleared.
+	stmtVarsRestore map[string]string
 	// SysWarningCount is the system variable "warning_count", because it is on the hot path, so we extract it from the systems
 	SysWarningCount int
 	// SysErrorCount is the system variable "error_count", because it is on the hot path, so we extract it from the systems
@@ -470,6 +634,10 @@ type SessionVars struct {
 	// ActiveRoles stores active roles for current user
 	ActiveRoles []*auth.RoleIdentity
 
+	// InSandboxMode indicates that the password of current user is expired, only SET PASSWORD and ALTER USER
+	// can be executed until it's changed.
+	InSandboxMode bool
+
 	RetryInfo *RetryInfo
 	//  TxnCtx Should be reset on transaction finished.
 	TxnCtx *TransactionContext
@@ -502,6 +670,10 @@ type SessionVars struct {
 	// TLSConnectionState is the TLS connection state (nil if not using TLS).
 	TLSConnectionState *tls.ConnectionState
 
+	// AuthConn is the connection with the client during the authentication, it's only set
+	// for the authentication plugins which exchange extra data with the client.
+	AuthConn conn.AuthConn
+
 	// ConnectionID is the connection id of the current session.
 	ConnectionID uint64
 
@@ -514,6 +686,10 @@ type SessionVars struct {
 	// User is the user identity with which the session login.
 	User *auth.UserIdentity
 
+	// ProxyUser is the login user when the session acts as User by SET SESSION AUTHORIZATION,
+	// it's nil if the session is not proxied.
+	ProxyUser *auth.UserIdentity
+
 	// Port is the port of the connected socket
 	Port string
 
@@ -766,12 +942,19 @@ type SessionVars struct {
 	// See https://dev.mysql.com/doc/refman/5.7/en/server-system-variables.html#sysvar_max_execution_time
 	MaxExecutionTime uint64
 
+	// EnableDMLMaxExecutionTime indicates whether MaxExecutionTime also limits the DML statements, see
+	// TiDBEnableDMLMaxExecutionTime.
+	EnableDMLMaxExecutionTime bool
+
 	// Killed is a flag to indicate that this query is killed.
 	Killed uint32
 
 	// ConnectionInfo indicates current connection info used by current session, only be lazy assigned by plugin.
 	ConnectionInfo *ConnectionInfo
 
+	// ConnectionAttrs is the connection attributes sent by the client in the handshake.
+	ConnectionAttrs map[string]string
+
 	// NoopFuncsMode allows OFF/ON/WARN values as 0/1/2.
 	NoopFuncsMode int
 
@@ -895,6 +1078,9 @@ type SessionVars struct {
 	// LastQueryInfo keeps track the info of last query.
 	LastQueryInfo QueryInfo
 
+	// ResourceGroupName is the resource group which the session is bound to, empty means the default group.
+	ResourceGroupName string
+
 	// PartitionPruneMode indicates how and when to prune partitions.
 	PartitionPruneMode atomic2.String
 
@@ -916,6 +1102,13 @@ type SessionVars struct {
 	// AnalyzeVersion indicates how TiDB collect and use analyzed statistics.
 	AnalyzeVersion int
 
+	// AnalyzeIndexTopNNum indicates the TopN size of the index statistics. 0 means the indexes use the same TopN size as
+	// the columns.
+	AnalyzeIndexTopNNum int
+
+	// MemQuotaAnalyze defines the memory quota for the samples collected by ANALYZE, -1 means no limit.
+	MemQuotaAnalyze int64
+
 	// EnableIndexMergeJoin indicates whether to enable index merge join.
 	EnableIndexMergeJoin bool
 
@@ -936,6 +1129,9 @@ type SessionVars struct {
 	// The temporary table size threshold, which is different from MySQL. See https://github.com/pingcap/tidb/issues/28691.
 	TMPTableSize int64
 
+	// TmpStorageQuotaQuery is the quota of the temporary storage which a query can use to spill data.
+	TmpStorageQuotaQuery int64
+
 	// EnableStableResultMode if stabilize query results.
 	EnableStableResultMode bool
 
@@ -973,6 +1169,37 @@ type SessionVars struct {
 	// EnablePaging indicates whether enable paging in coprocessor requests.
 	EnablePaging bool
 
+	// EnableSortedShuffle indicates whether to shuffle the sorted child of StreamAgg and Window by range.
+	EnableSortedShuffle bool
+
+	// LoadDataResume indicates whether LOAD DATA resumes from the progress recorded by a previous interrupted load.
+	LoadDataResume bool
+
+	// CollectDMLErrors indicates whether to collect the rows rejected by the DML statements.
+	CollectDMLErrors bool
+
+	// CollectDMLErrorsMaxSize is the max bytes of the rows rejected by a DML statement to collect.
+	CollectDMLErrorsMaxSize int64
+
+	// FastCheckTable indicates whether ADMIN CHECK TABLE compares the checksums of the table and index data.
+	FastCheckTable bool
+
+	// RcReadCheckTS indicates whether the read-only statements in the RC transactions reuse the last fetched
+	// timestamp, see TiDBRCReadCheckTS.
+	RcReadCheckTS bool
+
+	// IdleTransactionTimeout is the max seconds the session can be idle in a transaction, see TiDBIdleTransactionTimeout.
+	IdleTransactionTimeout int
+
+	// EnableExternalTSRead indicates whether the reads use the timestamp of TiDBExternalTS, see TiDBEnableExternalTSRead.
+	EnableExternalTSRead bool
+
+	// GCLifeTime is the GC life time required by the session, see TiDBSessionGCLifeTime.
+	GCLifeTime time.Duration
+
+	// LastDMLErrors are the rows rejected by the last DML statement which collected them.
+	LastDMLErrors []stmtctx.DMLError
+
 	// StmtStats is used to count various indicators of each SQL in this session
 	// at each point in time. These data will be periodically taken away by the
 	// background goroutine. The background goroutine will continue to aggregate
@@ -1042,6 +1269,12 @@ func (s *SessionVars) UseDynamicPartitionPrune() bool {
 	return PartitionPruneMode(s.PartitionPruneMode.Load()) == Dynamic
 }
 
+// ShouldRedactLog returns whether the literals should be removed from the logs of the session, it's true if
+// tidb_redact_log is on for the session or tidb_log_desensitization is on.
+func (s *SessionVars) ShouldRedactLog() bool {
+	return s.EnableRedactLog || LogDesensitization.Load()
+}
+
 // BuildParserConfig generate parser.ParserConfig for initial parser
 func (s *SessionVars) BuildParserConfig() parser.ParserConfig {
 	return parser.ParserConfig{
@@ -1109,10 +1342,12 @@ type ConnectionInfo struct {
 	Host              string
 	ClientIP          string
 	ClientPort        string
+	ProxyAddr         string
 	ServerID          int
 	ServerPort        int
 	Duration          float64
 	User              string
+	ProxyUser         string
 	ServerOSLoginUser string
 	OSVersion         string
 	ClientVersion     string
@@ -1193,6 +1428,8 @@ func NewSessionVars() *SessionVars {
 		AllowAutoRandExplicitInsert: DefTiDBAllowAutoRandExplicitInsert,
 		EnableClusteredIndex:        DefTiDBEnableClusteredIndex,
 		EnableParallelApply:         DefTiDBEnableParallelApply,
+		EnableSortedShuffle:         DefTiDBEnableSortedShuffle,
+		CollectDMLErrorsMaxSize:     DefTiDBCollectDMLErrorsMaxSize,
 		ShardAllocateStep:           DefTiDBShardAllocateStep,
 		EnableChangeMultiSchema:     DefTiDBChangeMultiSchema,
 		EnablePointGetCache:         DefTiDBPointGetCache,
@@ -1205,10 +1442,13 @@ func NewSessionVars() *SessionVars {
 		Enable1PC:                   DefTiDBEnable1PC,
 		GuaranteeLinearizability:    DefTiDBGuaranteeLinearizability,
 		AnalyzeVersion:              DefTiDBAnalyzeVersion,
+		AnalyzeIndexTopNNum:         DefTiDBAnalyzeIndexTopNNum,
+		MemQuotaAnalyze:             DefTiDBMemQuotaAnalyze,
 		EnableIndexMergeJoin:        DefTiDBEnableIndexMergeJoin,
 		AllowFallbackToTiKV:         make(map[kv.StoreType]struct{}),
 		CTEMaxRecursionDepth:        DefCTEMaxRecursionDepth,
 		TMPTableSize:                DefTiDBTmpTableMaxSize,
+		TmpStorageQuotaQuery:        DefTiDBTmpStorageQuotaQuery,
 		MPPStoreLastFailTime:        make(map[string]time.Time),
 		MPPStoreFailTTL:             DefTiDBMPPStoreFailTTL,
 		EnablePlacementChecks:       DefEnablePlacementCheck,
@@ -1582,14 +1822,109 @@ func (s *SessionVars) WithdrawAllPreparedStmt() {
 	metrics.PreparedStmtGauge.Set(float64(afterMinus))
 }
 
-// SetStmtVar sets the value of a system variable temporarily
+// EncodeSessionStates saves the user variables, the system variables and the current DB of the session.
+func (s *SessionVars) EncodeSessionStates(ctx context.Context, sessionStates *sessionstates.SessionStates) (err error) {
+	s.UsersLock.RLock()
+	sessionStates.UserVars = make(map[string]*types.Datum, len(s.Users))
+	for name, userVar := range s.Users {
+		sessionStates.UserVars[name] = userVar.Clone()
+	}
+	sessionStates.UserVarTypes = make(map[string]*ptypes.FieldType, len(s.UserVarTypes))
+	for name, userVarType := range s.UserVarTypes {
+		sessionStates.UserVarTypes[name] = userVarType.Clone()
+	}
+	s.UsersLock.RUnlock()
+
+	// Only the system variables whose values are different from the global values are saved, the others are loaded
+	// from the global values on the new server. The variables computed on the fly are not saved.
+	sessionStates.SystemVars = make(map[string]string)
+	for _, sv := range GetSysVars() {
+		if !sv.HasSessionScope() || sv.ReadOnly || sv.GetSession != nil {
+			continue
+		}
+		val, ok := s.systems[sv.Name]
+		if !ok {
+			continue
+		}
+		globalVal := sv.Value
+		if sv.HasGlobalScope() {
+			if globalVal, err = s.GlobalVarsAccessor.GetGlobalSysVar(sv.Name); err != nil {
+				return err
+			}
+		}
+		if val != globalVal {
+			sessionStates.SystemVars[sv.Name] = val
+		}
+	}
+
+	sessionStates.PreparedStmtID = s.preparedStmtID
+	sessionStates.CurrentDB = s.CurrentDB
+	sessionStates.ResourceGroupName = s.ResourceGroupName
+	return nil
+}
+
+// DecodeSessionStates restores the states saved by EncodeSessionStates.
+func (s *SessionVars) DecodeSessionStates(ctx context.Context, sessionStates *sessionstates.SessionStates) error {
+	s.UsersLock.Lock()
+	for name, userVar := range sessionStates.UserVars {
+		s.Users[name] = *userVar.Clone()
+	}
+	for name, userVarType := range sessionStates.UserVarTypes {
+		s.UserVarTypes[name] = userVarType.Clone()
+	}
+	s.UsersLock.Unlock()
+
+	names := make([]string, 0, len(sessionStates.SystemVars))
+	for name := range sessionStates.SystemVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := SetSessionSystemVar(s, name, sessionStates.SystemVars[name]); err != nil {
+			return err
+		}
+	}
+
+	if s.preparedStmtID < sessionStates.PreparedStmtID {
+		s.preparedStmtID = sessionStates.PreparedStmtID
+	}
+	s.CurrentDB = sessionStates.CurrentDB
+	s.ResourceGroupName = sessionStates.ResourceGroupName
+	return nil
+}
+
+// SetStmtVar sets the value of a system variable temporarily.
+// If the variable has a SetSession function, the value is also applied to the session,
+// and the session value is restored by ClearStmtVars.
 func (s *SessionVars) SetStmtVar(name string, val string) error {
+	if sv := GetSysVar(name); sv != nil && sv.SetSession != nil {
+		if _, ok := s.stmtVarsRestore[name]; !ok {
+			oldVal, ok := s.systems[sv.Name]
+			if !ok {
+				// The variables with skipInit are not in systems until they are set.
+				oldVal = sv.Value
+			}
+			if s.stmtVarsRestore == nil {
+				s.stmtVarsRestore = make(map[string]string)
+			}
+			s.stmtVarsRestore[name] = oldVal
+		}
+		if err := sv.SetSession(s, val); err != nil {
+			return err
+		}
+	}
 	s.stmtVars[name] = val
 	return nil
 }
 
 // ClearStmtVars clear temporarily system variables.
 func (s *SessionVars) ClearStmtVars() {
+	for name, val := range s.stmtVarsRestore {
+		terror.Log(GetSysVar(name).SetSession(s, val))
+	}
+	if len(s.stmtVarsRestore) > 0 {
+		s.stmtVarsRestore = nil
+	}
 	s.stmtVars = make(map[string]string)
 }
 
@@ -1679,6 +2014,10 @@ type TableDelta struct {
 	ColSize  map[int64]int64
 	InitTime time.Time // InitTime is the time that this delta is generated.
 	TableID  int64
+	// IndexWrites is the number of the index rows written, keyed by the index ID. The index usage of a partition is
+	// collected to its partitioned table, so LogicalTableID is the ID of the partitioned table for a partition.
+	IndexWrites    map[int64]int64
+	LogicalTableID int64
 }
 
 // ConcurrencyUnset means the value the of the concurrency related variable is unset.
@@ -1991,6 +2330,8 @@ const (
 	SlowLogMemMax = "Mem_max"
 	// SlowLogDiskMax is the nax number bytes of disk used in this statement.
 	SlowLogDiskMax = "Disk_max"
+	// SlowLogRequestUnit is the request units consumed by the coprocessor requests of this statement.
+	SlowLogRequestUnit = "Request_unit"
 	// SlowLogPrepared is used to indicate whether this sql execute in prepare.
 	SlowLogPrepared = "Prepared"
 	// SlowLogPlanFromCache is used to indicate whether this plan is from plan cache.
@@ -2070,6 +2411,7 @@ type SlowQueryLogItems struct {
 	ResultRows        int64
 	IsExplicitTxn     bool
 	IsWriteCacheTable bool
+	RUConsumed        float64
 }
 
 // SlowLogFormat uses for formatting slow log.
@@ -2094,6 +2436,9 @@ type SlowQueryLogItems struct {
 // # Prev_stmt: begin;
 // select * from t_slim;
 func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
+	if SlowLogFormatValue.Load() == SlowLogFormatJSON {
+		return s.slowLogJSONFormat(logItems)
+	}
 	var buf bytes.Buffer
 
 	writeSlowLogItem(&buf, SlowLogTxnStartTSStr, strconv.FormatUint(logItems.TxnTS, 10))
@@ -2149,24 +2494,7 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 		writeSlowLogItem(&buf, SlowLogDigestStr, logItems.Digest)
 	}
 	if len(logItems.StatsInfos) > 0 {
-		buf.WriteString(SlowLogRowPrefixStr + SlowLogStatsInfoStr + SlowLogSpaceMarkStr)
-		firstComma := false
-		vStr := ""
-		for k, v := range logItems.StatsInfos {
-			if v == 0 {
-				vStr = "pseudo"
-			} else {
-				vStr = strconv.FormatUint(v, 10)
-
-			}
-			if firstComma {
-				buf.WriteString("," + k + ":" + vStr)
-			} else {
-				buf.WriteString(k + ":" + vStr)
-				firstComma = true
-			}
-		}
-		buf.WriteString("\n")
+		writeSlowLogItem(&buf, SlowLogStatsInfoStr, formatSlowLogStatsInfos(logItems.StatsInfos))
 	}
 	if logItems.CopTasks != nil {
 		writeSlowLogItem(&buf, SlowLogNumCopTasksStr, strconv.FormatInt(int64(logItems.CopTasks.NumCopTasks), 10))
@@ -2223,6 +2551,9 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 	if logItems.DiskMax > 0 {
 		writeSlowLogItem(&buf, SlowLogDiskMax, strconv.FormatInt(logItems.DiskMax, 10))
 	}
+	if logItems.RUConsumed > 0 {
+		writeSlowLogItem(&buf, SlowLogRequestUnit, strconv.FormatFloat(logItems.RUConsumed, 'f', -1, 64))
+	}
 
 	writeSlowLogItem(&buf, SlowLogPrepared, strconv.FormatBool(logItems.Prepared))
 	writeSlowLogItem(&buf, SlowLogPlanFromCache, strconv.FormatBool(logItems.PlanFromCache))
@@ -2238,6 +2569,9 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 	if s.StmtCtx.WaitLockLeaseTime > 0 {
 		writeSlowLogItem(&buf, SlowLogIsWriteCacheTable, strconv.FormatBool(logItems.IsWriteCacheTable))
 	}
+	s.forEachRegisteredSlowLogField(logItems, func(name string, value interface{}) {
+		writeSlowLogItem(&buf, name, fmt.Sprint(value))
+	})
 	if len(logItems.Plan) != 0 {
 		writeSlowLogItem(&buf, SlowLogPlan, logItems.Plan)
 	}
@@ -2261,6 +2595,22 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 	return buf.String()
 }
 
+// formatSlowLogStatsInfos formats the stats infos in the form of: "t1:1,t2:pseudo"
+func formatSlowLogStatsInfos(statsInfos map[string]uint64) string {
+	var buf strings.Builder
+	for k, v := range statsInfos {
+		vStr := "pseudo"
+		if v != 0 {
+			vStr = strconv.FormatUint(v, 10)
+		}
+		if buf.Len() > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(k + ":" + vStr)
+	}
+	return buf.String()
+}
+
 // writeSlowLogItem writes a slow log item in the form of: "# ${key}:${value}"
 func writeSlowLogItem(buf *bytes.Buffer, key, value string) {
 	buf.WriteString(SlowLogRowPrefixStr + key + SlowLogSpaceMarkStr + value + "\n")
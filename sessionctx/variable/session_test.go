@@ -15,6 +15,7 @@
 package variable_test
 
 import (
+	"encoding/json"
 	"sync"
 	"testing"
 	"time"
@@ -283,6 +284,63 @@ func TestSlowLogFormat(t *testing.T) {
 	require.False(t, seVar.CurrentDBChanged)
 }
 
+func TestSlowLogJSONFormat(t *testing.T) {
+	ctx := mock.NewContext()
+	seVar := ctx.GetSessionVars()
+	seVar.User = &auth.UserIdentity{Username: "root", Hostname: "192.168.0.1"}
+	seVar.ConnectionInfo = &variable.ConnectionInfo{ClientIP: "192.168.0.1"}
+	seVar.ConnectionID = 1
+	seVar.CurrentDB = "test"
+
+	variable.SlowLogFormatValue.Store(variable.SlowLogFormatJSON)
+	defer variable.SlowLogFormatValue.Store(variable.DefTiDBSlowLogFormat)
+	require.NoError(t, variable.RegisterSlowLogField("Tenant", func(s *variable.SessionVars, logItems *variable.SlowQueryLogItems) (interface{}, bool) {
+		return "t1", true
+	}))
+	defer variable.UnregisterSlowLogField("Tenant")
+
+	sql := "select * from t"
+	logItems := &variable.SlowQueryLogItems{
+		TxnTS:     406649736972468225,
+		SQL:       sql,
+		TimeTotal: time.Second,
+		ExecDetail: execdetails.ExecDetails{
+			BackoffTime:  time.Millisecond,
+			RequestCount: 2,
+			RUConsumed:   1.5,
+		},
+		StatsInfos: map[string]uint64{"t1": 0},
+		MemMax:     2333,
+		DiskMax:    6666,
+		RUConsumed: 1.5,
+		Succ:       true,
+	}
+	logString := seVar.SlowLogFormat(logItems)
+	fields := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal([]byte(logString), &fields))
+	require.Equal(t, 406649736972468225.0, fields["Txn_start_ts"])
+	require.Equal(t, "root", fields["User"])
+	require.Equal(t, "192.168.0.1", fields["Host"])
+	require.Equal(t, 1.0, fields["Query_time"])
+	require.Equal(t, 0.001, fields["Backoff_time"])
+	require.Equal(t, 2.0, fields["Request_count"])
+	require.Equal(t, 1.5, fields["Request_unit"])
+	require.Equal(t, 6666.0, fields["Disk_max"])
+	require.Equal(t, "t1:pseudo", fields["Stats"])
+	require.Equal(t, true, fields["Succ"])
+	require.Equal(t, "t1", fields["Tenant"])
+	require.Equal(t, sql+";", fields["Query"])
+
+	// The registered fields are written in the text format as well.
+	variable.SlowLogFormatValue.Store(variable.SlowLogFormatText)
+	logString = seVar.SlowLogFormat(logItems)
+	require.Contains(t, logString, "\n# Tenant: t1\n")
+	require.Contains(t, logString, "\n# Request_unit: 1.5\n")
+
+	require.Error(t, variable.RegisterSlowLogField("Tenant", nil))
+	require.Error(t, variable.RegisterSlowLogField(variable.SlowLogQuerySQLStr, nil))
+}
+
 func TestIsolationRead(t *testing.T) {
 	defer config.RestoreFunc()()
 	config.UpdateGlobal(func(conf *config.Config) {
@@ -0,0 +1,256 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/pingcap/errors"
+)
+
+// SlowLogJSONStartPrefixStr is the prefix of the slow log entries in the JSON format, the time is always the first field.
+const SlowLogJSONStartPrefixStr = `{"` + SlowLogTimeStr + `":"`
+
+// SlowLogFieldFunc returns the value of a slow log field registered by RegisterSlowLogField, the field is omitted
+// if `ok` is false. The value is written by fmt in the text format and by encoding/json in the JSON format.
+type SlowLogFieldFunc func(s *SessionVars, logItems *SlowQueryLogItems) (value interface{}, ok bool)
+
+type slowLogField struct {
+	name string
+	fn   SlowLogFieldFunc
+}
+
+var slowLogFields struct {
+	sync.RWMutex
+	fields []slowLogField
+}
+
+// RegisterSlowLogField registers a field of the slow log, so that the subsystems can attach their fields to the slow
+// log without editing the writer. The registered fields are written in the order of registration, following the
+// built-in fields. The name should be in the form of the built-in fields like `Foo_bar`, and it can't be registered
+// twice.
+func RegisterSlowLogField(name string, fn SlowLogFieldFunc) error {
+	if name == "" || name == SlowLogTimeStr || name == SlowLogQuerySQLStr {
+		return errors.Errorf("invalid slow log field name '%s'", name)
+	}
+	slowLogFields.Lock()
+	defer slowLogFields.Unlock()
+	for _, f := range slowLogFields.fields {
+		if f.name == name {
+			return errors.Errorf("slow log field '%s' is already registered", name)
+		}
+	}
+	slowLogFields.fields = append(slowLogFields.fields, slowLogField{name: name, fn: fn})
+	return nil
+}
+
+// UnregisterSlowLogField removes a field registered by RegisterSlowLogField.
+func UnregisterSlowLogField(name string) {
+	slowLogFields.Lock()
+	defer slowLogFields.Unlock()
+	for i, f := range slowLogFields.fields {
+		if f.name == name {
+			slowLogFields.fields = append(slowLogFields.fields[:i], slowLogFields.fields[i+1:]...)
+			return
+		}
+	}
+}
+
+// forEachRegisteredSlowLogField calls `fn` for the registered fields which have values.
+func (s *SessionVars) forEachRegisteredSlowLogField(logItems *SlowQueryLogItems, fn func(name string, value interface{})) {
+	slowLogFields.RLock()
+	defer slowLogFields.RUnlock()
+	for _, f := range slowLogFields.fields {
+		if value, ok := f.fn(s, logItems); ok {
+			fn(f.name, value)
+		}
+	}
+}
+
+// slowLogJSONWriter writes the fields of a slow log entry into a JSON object in order.
+type slowLogJSONWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *slowLogJSONWriter) writeKey(key string) {
+	if w.buf.Len() == 0 {
+		w.buf.WriteByte('{')
+	} else {
+		w.buf.WriteByte(',')
+	}
+	w.writeJSON(key)
+	w.buf.WriteByte(':')
+}
+
+func (w *slowLogJSONWriter) writeJSON(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	w.buf.Write(data)
+}
+
+// writeString writes a string field.
+func (w *slowLogJSONWriter) writeString(key, value string) {
+	w.writeKey(key)
+	w.writeJSON(value)
+}
+
+// writeValue writes a field in any type.
+func (w *slowLogJSONWriter) writeValue(key string, value interface{}) {
+	w.writeKey(key)
+	w.writeJSON(value)
+}
+
+// writeRaw writes a field whose value is a number or a boolean in the text form.
+func (w *slowLogJSONWriter) writeRaw(key, value string) {
+	w.writeKey(key)
+	w.buf.WriteString(value)
+}
+
+func (w *slowLogJSONWriter) writeSeconds(key string, seconds float64) {
+	w.writeRaw(key, strconv.FormatFloat(seconds, 'f', -1, 64))
+}
+
+func (w *slowLogJSONWriter) String() string {
+	w.buf.WriteByte('}')
+	return w.buf.String()
+}
+
+// slowLogJSONFormat formats the slow log in the JSON format. The field names are the same as the text format, except
+// that the user and the host are written separately like the columns of the SLOW_QUERY table, and the backoff
+// details of the cop tasks are written as separate fields. The time is written by the slow log encoder as the first
+// field.
+func (s *SessionVars) slowLogJSONFormat(logItems *SlowQueryLogItems) string {
+	w := &slowLogJSONWriter{}
+	w.writeRaw(SlowLogTxnStartTSStr, strconv.FormatUint(logItems.TxnTS, 10))
+	if s.User != nil {
+		w.writeString(SlowLogUserStr, s.User.Username)
+		w.writeString(SlowLogHostStr, s.User.Hostname)
+	}
+	if s.ConnectionID != 0 {
+		w.writeRaw(SlowLogConnIDStr, strconv.FormatUint(s.ConnectionID, 10))
+	}
+	if logItems.ExecRetryCount > 0 {
+		w.writeSeconds(SlowLogExecRetryTime, logItems.ExecRetryTime.Seconds())
+		w.writeRaw(SlowLogExecRetryCount, strconv.Itoa(int(logItems.ExecRetryCount)))
+	}
+	w.writeSeconds(SlowLogQueryTimeStr, logItems.TimeTotal.Seconds())
+	w.writeSeconds(SlowLogParseTimeStr, logItems.TimeParse.Seconds())
+	w.writeSeconds(SlowLogCompileTimeStr, logItems.TimeCompile.Seconds())
+	w.writeSeconds(SlowLogRewriteTimeStr, logItems.RewriteInfo.DurationRewrite.Seconds())
+	if logItems.RewriteInfo.PreprocessSubQueries > 0 {
+		w.writeRaw(SlowLogPreprocSubQueriesStr, strconv.Itoa(logItems.RewriteInfo.PreprocessSubQueries))
+		w.writeSeconds(SlowLogPreProcSubQueryTimeStr, logItems.RewriteInfo.DurationPreprocessSubQuery.Seconds())
+	}
+	w.writeSeconds(SlowLogOptimizeTimeStr, logItems.TimeOptimize.Seconds())
+	w.writeSeconds(SlowLogWaitTSTimeStr, logItems.TimeWaitTS.Seconds())
+	for _, f := range logItems.ExecDetail.Fields() {
+		if _, err := strconv.ParseFloat(f.Value, 64); err == nil {
+			w.writeRaw(f.Name, f.Value)
+		} else {
+			w.writeString(f.Name, f.Value)
+		}
+	}
+	if len(s.CurrentDB) > 0 {
+		w.writeString(SlowLogDBStr, s.CurrentDB)
+	}
+	if len(logItems.IndexNames) > 0 {
+		w.writeString(SlowLogIndexNamesStr, logItems.IndexNames)
+	}
+	w.writeRaw(SlowLogIsInternalStr, strconv.FormatBool(s.InRestrictedSQL))
+	if len(logItems.Digest) > 0 {
+		w.writeString(SlowLogDigestStr, logItems.Digest)
+	}
+	if len(logItems.StatsInfos) > 0 {
+		w.writeString(SlowLogStatsInfoStr, formatSlowLogStatsInfos(logItems.StatsInfos))
+	}
+	if logItems.CopTasks != nil {
+		w.writeRaw(SlowLogNumCopTasksStr, strconv.Itoa(logItems.CopTasks.NumCopTasks))
+		if logItems.CopTasks.NumCopTasks > 0 {
+			w.writeSeconds(SlowLogCopProcAvg, logItems.CopTasks.AvgProcessTime.Seconds())
+			if logItems.CopTasks.NumCopTasks > 1 {
+				w.writeSeconds(SlowLogCopProcP90, logItems.CopTasks.P90ProcessTime.Seconds())
+				w.writeSeconds(SlowLogCopProcMax, logItems.CopTasks.MaxProcessTime.Seconds())
+			}
+			w.writeString(SlowLogCopProcAddr, logItems.CopTasks.MaxProcessAddress)
+			w.writeSeconds(SlowLogCopWaitAvg, logItems.CopTasks.AvgWaitTime.Seconds())
+			if logItems.CopTasks.NumCopTasks > 1 {
+				w.writeSeconds(SlowLogCopWaitP90, logItems.CopTasks.P90WaitTime.Seconds())
+				w.writeSeconds(SlowLogCopWaitMax, logItems.CopTasks.MaxWaitTime.Seconds())
+			}
+			w.writeString(SlowLogCopWaitAddr, logItems.CopTasks.MaxWaitAddress)
+			backoffs := make([]string, 0, len(logItems.CopTasks.TotBackoffTimes))
+			for backoff := range logItems.CopTasks.TotBackoffTimes {
+				backoffs = append(backoffs, backoff)
+			}
+			sort.Strings(backoffs)
+			for _, backoff := range backoffs {
+				backoffPrefix := SlowLogCopBackoffPrefix + backoff + "_"
+				w.writeRaw(backoffPrefix+"total_times", strconv.Itoa(logItems.CopTasks.TotBackoffTimes[backoff]))
+				w.writeSeconds(backoffPrefix+"total_time", logItems.CopTasks.TotBackoffTime[backoff].Seconds())
+				if logItems.CopTasks.NumCopTasks > 1 {
+					w.writeSeconds(backoffPrefix+"max_time", logItems.CopTasks.MaxBackoffTime[backoff].Seconds())
+					w.writeString(backoffPrefix+"max_addr", logItems.CopTasks.MaxBackoffAddress[backoff])
+					w.writeSeconds(backoffPrefix+"avg_time", logItems.CopTasks.AvgBackoffTime[backoff].Seconds())
+					w.writeSeconds(backoffPrefix+"p90_time", logItems.CopTasks.P90BackoffTime[backoff].Seconds())
+				}
+			}
+		}
+	}
+	// The memory, the disk and the request units are always written in the JSON format, so that the consumers can
+	// rely on them.
+	w.writeRaw(SlowLogMemMax, strconv.FormatInt(logItems.MemMax, 10))
+	w.writeRaw(SlowLogDiskMax, strconv.FormatInt(logItems.DiskMax, 10))
+	w.writeRaw(SlowLogRequestUnit, strconv.FormatFloat(logItems.RUConsumed, 'f', -1, 64))
+	w.writeRaw(SlowLogPrepared, strconv.FormatBool(logItems.Prepared))
+	w.writeRaw(SlowLogPlanFromCache, strconv.FormatBool(logItems.PlanFromCache))
+	w.writeRaw(SlowLogPlanFromBinding, strconv.FormatBool(logItems.PlanFromBinding))
+	w.writeRaw(SlowLogHasMoreResults, strconv.FormatBool(logItems.HasMoreResults))
+	w.writeSeconds(SlowLogKVTotal, logItems.KVTotal.Seconds())
+	w.writeSeconds(SlowLogPDTotal, logItems.PDTotal.Seconds())
+	w.writeSeconds(SlowLogBackoffTotal, logItems.BackoffTotal.Seconds())
+	w.writeSeconds(SlowLogWriteSQLRespTotal, logItems.WriteSQLRespTotal.Seconds())
+	w.writeRaw(SlowLogResultRows, strconv.FormatInt(logItems.ResultRows, 10))
+	w.writeRaw(SlowLogSucc, strconv.FormatBool(logItems.Succ))
+	w.writeRaw(SlowLogIsExplicitTxn, strconv.FormatBool(logItems.IsExplicitTxn))
+	if s.StmtCtx.WaitLockLeaseTime > 0 {
+		w.writeRaw(SlowLogIsWriteCacheTable, strconv.FormatBool(logItems.IsWriteCacheTable))
+	}
+	s.forEachRegisteredSlowLogField(logItems, func(name string, value interface{}) {
+		w.writeValue(name, value)
+	})
+	if len(logItems.Plan) != 0 {
+		w.writeString(SlowLogPlan, logItems.Plan)
+	}
+	w.writeString(SlowLogPlanDigest, logItems.PlanDigest)
+	if logItems.PrevStmt != "" {
+		w.writeString(SlowLogPrevStmt, logItems.PrevStmt)
+	}
+	// The DB is written as a field, so `use DB` isn't needed.
+	s.CurrentDBChanged = false
+
+	sql := logItems.SQL
+	if len(sql) == 0 || sql[len(sql)-1] != ';' {
+		sql += ";"
+	}
+	w.writeString(SlowLogQuerySQLStr, sql)
+	return w.String()
+}
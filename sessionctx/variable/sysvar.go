@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/privilege/privileges/ldap"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/collate"
@@ -252,6 +253,192 @@ var defaultSysVars = []*SysVar{
 	{Scope: ScopeNone, Name: "ssl_cert", Value: ""},
 	{Scope: ScopeNone, Name: "ssl_key", Value: ""},
 	{Scope: ScopeGlobal, Name: InitConnect, Value: ""},
+	{Scope: ScopeGlobal, Name: MaxUserConnections, Value: strconv.FormatUint(uint64(DefMaxUserConnections), 10), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint32, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatUint(uint64(MaxUserConnectionsValue.Load()), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		limit, err := strconv.ParseUint(val, 10, 32)
+		if err != nil {
+			return err
+		}
+		MaxUserConnectionsValue.Store(uint32(limit))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: MandatoryRoles, Value: "", Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		if _, err := ParseRoleList(normalizedValue); err != nil {
+			return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(MandatoryRoles, originalValue)
+		}
+		return normalizedValue, nil
+	}, GetGlobal: func(s *SessionVars) (string, error) {
+		return MandatoryRolesValue.Load(), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		MandatoryRolesValue.Store(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: ActivateAllRolesOnLogin, Value: BoolToOnOff(DefActivateAllRolesOnLogin), Type: TypeBool, GetGlobal: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(ActivateAllRolesOnLoginValue.Load()), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		ActivateAllRolesOnLoginValue.Store(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleServerHost, Value: "", SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSimpleAuthImpl.SetLDAPServerHost(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleServerPort, Value: strconv.Itoa(DefAuthenticationLDAPSimpleServerPort), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxUint16, SetGlobal: func(s *SessionVars, val string) error {
+		port, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ldap.LDAPSimpleAuthImpl.SetLDAPServerPort(port)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleTLS, Value: BoolToOnOff(DefAuthenticationLDAPSimpleTLS), Type: TypeBool, SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSimpleAuthImpl.SetEnableTLS(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleCAPath, Value: "", SetGlobal: func(s *SessionVars, val string) error {
+		return ldap.LDAPSimpleAuthImpl.SetCAPath(val)
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleBindBaseDN, Value: "", SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSimpleAuthImpl.SetBindBaseDN(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleBindRootDN, Value: "", SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSimpleAuthImpl.SetBindRootDN(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleBindRootPWD, Value: "", SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSimpleAuthImpl.SetBindRootPW(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleUserSearchAttr, Value: DefAuthenticationLDAPSimpleUserSearchAttr, SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSimpleAuthImpl.SetSearchAttr(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleGroupSearchAttr, Value: DefAuthenticationLDAPSimpleGroupSearchAttr, SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSimpleAuthImpl.SetGroupSearchAttr(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleGroupRoleMapping, Value: "", Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		if _, err := ldap.ParseGroupRoleMapping(normalizedValue); err != nil {
+			return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(AuthenticationLDAPSimpleGroupRoleMapping, originalValue)
+		}
+		return normalizedValue, nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		return ldap.LDAPSimpleAuthImpl.SetGroupRoleMapping(val)
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleInitPoolSize, Value: strconv.Itoa(DefAuthenticationLDAPSimpleInitPoolSize), Type: TypeUnsigned, MinValue: 1, MaxValue: 32767, SetGlobal: func(s *SessionVars, val string) error {
+		size, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ldap.LDAPSimpleAuthImpl.SetInitCapacity(size)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleMaxPoolSize, Value: strconv.Itoa(DefAuthenticationLDAPSimpleMaxPoolSize), Type: TypeUnsigned, MinValue: 1, MaxValue: 32767, SetGlobal: func(s *SessionVars, val string) error {
+		size, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ldap.LDAPSimpleAuthImpl.SetMaxCapacity(size)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSimpleBindCacheTTL, Value: strconv.Itoa(DefAuthenticationLDAPSimpleBindCacheTTL), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, SetGlobal: func(s *SessionVars, val string) error {
+		ttl, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ldap.LDAPSimpleAuthImpl.SetBindCacheTTL(time.Duration(ttl) * time.Second)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLServerHost, Value: "", SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSASLAuthImpl.SetLDAPServerHost(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLServerPort, Value: strconv.Itoa(DefAuthenticationLDAPSASLServerPort), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxUint16, SetGlobal: func(s *SessionVars, val string) error {
+		port, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ldap.LDAPSASLAuthImpl.SetLDAPServerPort(port)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLTLS, Value: BoolToOnOff(DefAuthenticationLDAPSASLTLS), Type: TypeBool, SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSASLAuthImpl.SetEnableTLS(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLCAPath, Value: "", SetGlobal: func(s *SessionVars, val string) error {
+		return ldap.LDAPSASLAuthImpl.SetCAPath(val)
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLBindBaseDN, Value: "", SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSASLAuthImpl.SetBindBaseDN(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLBindRootDN, Value: "", SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSASLAuthImpl.SetBindRootDN(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLBindRootPWD, Value: "", SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSASLAuthImpl.SetBindRootPW(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLUserSearchAttr, Value: DefAuthenticationLDAPSASLUserSearchAttr, SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSASLAuthImpl.SetSearchAttr(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLGroupSearchAttr, Value: DefAuthenticationLDAPSASLGroupSearchAttr, SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSASLAuthImpl.SetGroupSearchAttr(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLGroupRoleMapping, Value: "", Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		if _, err := ldap.ParseGroupRoleMapping(normalizedValue); err != nil {
+			return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(AuthenticationLDAPSASLGroupRoleMapping, originalValue)
+		}
+		return normalizedValue, nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		return ldap.LDAPSASLAuthImpl.SetGroupRoleMapping(val)
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLInitPoolSize, Value: strconv.Itoa(DefAuthenticationLDAPSASLInitPoolSize), Type: TypeUnsigned, MinValue: 1, MaxValue: 32767, SetGlobal: func(s *SessionVars, val string) error {
+		size, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ldap.LDAPSASLAuthImpl.SetInitCapacity(size)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLMaxPoolSize, Value: strconv.Itoa(DefAuthenticationLDAPSASLMaxPoolSize), Type: TypeUnsigned, MinValue: 1, MaxValue: 32767, SetGlobal: func(s *SessionVars, val string) error {
+		size, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ldap.LDAPSASLAuthImpl.SetMaxCapacity(size)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLAuthMethodName, Value: DefAuthenticationLDAPSASLAuthMethodName, Type: TypeEnum, PossibleValues: []string{"SCRAM-SHA-1", "SCRAM-SHA-256", "GSSAPI"}, SetGlobal: func(s *SessionVars, val string) error {
+		ldap.LDAPSASLAuthImpl.SetSASLAuthMethod(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: DefaultPasswordLifetime, Value: strconv.Itoa(DefDefaultPasswordLifetime), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint16, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatUint(uint64(DefaultPasswordLifetimeValue.Load()), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		days, err := strconv.ParseUint(val, 10, 16)
+		if err != nil {
+			return err
+		}
+		DefaultPasswordLifetimeValue.Store(uint32(days))
+		return nil
+	}},
+	{Scope: ScopeNone, Name: DisconnectOnExpiredPassword, Value: On, Type: TypeBool},
+	{Scope: ScopeGlobal, Name: PasswordHistory, Value: strconv.Itoa(DefPasswordHistory), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint32},
+	{Scope: ScopeGlobal, Name: PasswordReuseInterval, Value: strconv.Itoa(DefPasswordReuseInterval), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint32},
+	{Scope: ScopeGlobal, Name: ValidatePasswordEnable, Value: BoolToOnOff(DefValidatePasswordEnable), Type: TypeBool},
+	{Scope: ScopeGlobal, Name: ValidatePasswordPolicy, Value: DefValidatePasswordPolicy, Type: TypeEnum, PossibleValues: []string{"LOW", "MEDIUM", "STRONG"}},
+	{Scope: ScopeGlobal, Name: ValidatePasswordLength, Value: strconv.Itoa(DefValidatePasswordLength), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32},
+	{Scope: ScopeGlobal, Name: ValidatePasswordMixedCaseCount, Value: strconv.Itoa(DefValidatePasswordMixedCaseCount), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32},
+	{Scope: ScopeGlobal, Name: ValidatePasswordNumberCount, Value: strconv.Itoa(DefValidatePasswordNumberCount), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32},
+	{Scope: ScopeGlobal, Name: ValidatePasswordSpecialCharCount, Value: strconv.Itoa(DefValidatePasswordSpecialCharCount), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32},
+	{Scope: ScopeGlobal, Name: ValidatePasswordCheckUserName, Value: BoolToOnOff(DefValidatePasswordCheckUserName), Type: TypeBool},
+	{Scope: ScopeGlobal, Name: ValidatePasswordDictionary, Value: ""},
 
 	/* TiDB specific variables */
 	{Scope: ScopeGlobal, Name: TiDBTSOClientBatchMaxWaitTime, Value: strconv.FormatFloat(DefTiDBTSOClientBatchMaxWaitTime, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: 10,
@@ -310,7 +497,7 @@ var defaultSysVars = []*SysVar{
 	{Scope: ScopeSession, Name: TiDBReadStaleness, Value: "", Hidden: false, SetSession: func(s *SessionVars, val string) error {
 		return setReadStaleness(s, val)
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAllowMPPExecution, Type: TypeBool, Value: BoolToOnOff(DefTiDBAllowMPPExecution), SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAllowMPPExecution, Type: TypeBool, Value: BoolToOnOff(DefTiDBAllowMPPExecution), IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.allowMPPExecution = TiDBOptOn(val)
 		return nil
 	}},
@@ -350,7 +537,7 @@ var defaultSysVars = []*SysVar{
 		s.AllowAggPushDown = TiDBOptOn(val)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptBCJ, Value: BoolToOnOff(DefOptBCJ), Type: TypeBool, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptBCJ, Value: BoolToOnOff(DefOptBCJ), Type: TypeBool, IsHintUpdatable: true, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		if TiDBOptOn(normalizedValue) && vars.AllowBatchCop == 0 {
 			return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBOptBCJ, "'true' while tidb_allow_batch_cop is 0, please active batch cop at first.")
 		}
@@ -379,16 +566,17 @@ var defaultSysVars = []*SysVar{
 	{Scope: ScopeGlobal, Name: TiDBAutoAnalyzeRatio, Value: strconv.FormatFloat(DefAutoAnalyzeRatio, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64},
 	{Scope: ScopeGlobal, Name: TiDBAutoAnalyzeStartTime, Value: DefAutoAnalyzeStartTime, Type: TypeTime},
 	{Scope: ScopeGlobal, Name: TiDBAutoAnalyzeEndTime, Value: DefAutoAnalyzeEndTime, Type: TypeTime},
+	{Scope: ScopeGlobal, Name: TiDBAutoAnalyzeConcurrency, Value: strconv.Itoa(DefAutoAnalyzeConcurrency), Type: TypeUnsigned, MinValue: 1, MaxValue: MaxConfigurableConcurrency},
 	{Scope: ScopeSession, Name: TiDBChecksumTableConcurrency, skipInit: true, Value: strconv.Itoa(DefChecksumTableConcurrency)},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBExecutorConcurrency, Value: strconv.Itoa(DefExecutorConcurrency), Type: TypeUnsigned, MinValue: 1, MaxValue: MaxConfigurableConcurrency, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBExecutorConcurrency, Value: strconv.Itoa(DefExecutorConcurrency), Type: TypeUnsigned, MinValue: 1, MaxValue: MaxConfigurableConcurrency, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.ExecutorConcurrency = tidbOptPositiveInt32(val, DefExecutorConcurrency)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBDistSQLScanConcurrency, Value: strconv.Itoa(DefDistSQLScanConcurrency), Type: TypeUnsigned, MinValue: 1, MaxValue: MaxConfigurableConcurrency, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBDistSQLScanConcurrency, Value: strconv.Itoa(DefDistSQLScanConcurrency), Type: TypeUnsigned, MinValue: 1, MaxValue: MaxConfigurableConcurrency, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.distSQLScanConcurrency = tidbOptPositiveInt32(val, DefDistSQLScanConcurrency)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptInSubqToJoinAndAgg, Value: BoolToOnOff(DefOptInSubqToJoinAndAgg), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptInSubqToJoinAndAgg, Value: BoolToOnOff(DefOptInSubqToJoinAndAgg), Type: TypeBool, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.SetAllowInSubqToJoinAndAgg(TiDBOptOn(val))
 		return nil
 	}},
@@ -397,23 +585,23 @@ var defaultSysVars = []*SysVar{
 		return nil
 	}},
 	{
-		Scope: ScopeGlobal | ScopeSession, Name: TiDBOptLimitPushDownThreshold, Value: strconv.Itoa(DefOptLimitPushDownThreshold), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+		Scope: ScopeGlobal | ScopeSession, Name: TiDBOptLimitPushDownThreshold, Value: strconv.Itoa(DefOptLimitPushDownThreshold), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 			s.LimitPushDownThreshold = tidbOptInt64(val, DefOptLimitPushDownThreshold)
 			return nil
 		}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCorrelationThreshold, Value: strconv.FormatFloat(DefOptCorrelationThreshold, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: 1, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCorrelationThreshold, Value: strconv.FormatFloat(DefOptCorrelationThreshold, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: 1, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.CorrelationThreshold = tidbOptFloat64(val, DefOptCorrelationThreshold)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEnableCorrelationAdjustment, Value: BoolToOnOff(DefOptEnableCorrelationAdjustment), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEnableCorrelationAdjustment, Value: BoolToOnOff(DefOptEnableCorrelationAdjustment), Type: TypeBool, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.EnableCorrelationAdjustment = TiDBOptOn(val)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCorrelationExpFactor, Value: strconv.Itoa(DefOptCorrelationExpFactor), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCorrelationExpFactor, Value: strconv.Itoa(DefOptCorrelationExpFactor), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.CorrelationExpFactor = int(tidbOptInt64(val, DefOptCorrelationExpFactor))
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCPUFactor, Value: strconv.FormatFloat(DefOptCPUFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCPUFactor, Value: strconv.FormatFloat(DefOptCPUFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.CPUFactor = tidbOptFloat64(val, DefOptCPUFactor)
 		return nil
 	}},
@@ -421,61 +609,61 @@ var defaultSysVars = []*SysVar{
 		s.CopTiFlashConcurrencyFactor = tidbOptFloat64(val, DefOptTiFlashConcurrencyFactor)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCopCPUFactor, Value: strconv.FormatFloat(DefOptCopCPUFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCopCPUFactor, Value: strconv.FormatFloat(DefOptCopCPUFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.CopCPUFactor = tidbOptFloat64(val, DefOptCopCPUFactor)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptNetworkFactor, Value: strconv.FormatFloat(DefOptNetworkFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptNetworkFactor, Value: strconv.FormatFloat(DefOptNetworkFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.networkFactor = tidbOptFloat64(val, DefOptNetworkFactor)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptScanFactor, Value: strconv.FormatFloat(DefOptScanFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptScanFactor, Value: strconv.FormatFloat(DefOptScanFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.scanFactor = tidbOptFloat64(val, DefOptScanFactor)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptDescScanFactor, Value: strconv.FormatFloat(DefOptDescScanFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptDescScanFactor, Value: strconv.FormatFloat(DefOptDescScanFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.descScanFactor = tidbOptFloat64(val, DefOptDescScanFactor)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptSeekFactor, Value: strconv.FormatFloat(DefOptSeekFactor, 'f', -1, 64), skipInit: true, Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptSeekFactor, Value: strconv.FormatFloat(DefOptSeekFactor, 'f', -1, 64), skipInit: true, Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.seekFactor = tidbOptFloat64(val, DefOptSeekFactor)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptMemoryFactor, Value: strconv.FormatFloat(DefOptMemoryFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptMemoryFactor, Value: strconv.FormatFloat(DefOptMemoryFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.MemoryFactor = tidbOptFloat64(val, DefOptMemoryFactor)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptDiskFactor, Value: strconv.FormatFloat(DefOptDiskFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptDiskFactor, Value: strconv.FormatFloat(DefOptDiskFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.DiskFactor = tidbOptFloat64(val, DefOptDiskFactor)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptConcurrencyFactor, Value: strconv.FormatFloat(DefOptConcurrencyFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptConcurrencyFactor, Value: strconv.FormatFloat(DefOptConcurrencyFactor, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.ConcurrencyFactor = tidbOptFloat64(val, DefOptConcurrencyFactor)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexJoinBatchSize, Value: strconv.Itoa(DefIndexJoinBatchSize), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexJoinBatchSize, Value: strconv.Itoa(DefIndexJoinBatchSize), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxInt32, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.IndexJoinBatchSize = tidbOptPositiveInt32(val, DefIndexJoinBatchSize)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexLookupSize, Value: strconv.Itoa(DefIndexLookupSize), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexLookupSize, Value: strconv.Itoa(DefIndexLookupSize), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxInt32, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.IndexLookupSize = tidbOptPositiveInt32(val, DefIndexLookupSize)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexLookupConcurrency, Value: strconv.Itoa(DefIndexLookupConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexLookupConcurrency, Value: strconv.Itoa(DefIndexLookupConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.indexLookupConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
 		return nil
 	}, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		appendDeprecationWarning(vars, TiDBIndexLookupConcurrency, TiDBExecutorConcurrency)
 		return normalizedValue, nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexLookupJoinConcurrency, Value: strconv.Itoa(DefIndexLookupJoinConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexLookupJoinConcurrency, Value: strconv.Itoa(DefIndexLookupJoinConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.indexLookupJoinConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
 		return nil
 	}, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		appendDeprecationWarning(vars, TiDBIndexLookupJoinConcurrency, TiDBExecutorConcurrency)
 		return normalizedValue, nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexSerialScanConcurrency, Value: strconv.Itoa(DefIndexSerialScanConcurrency), Type: TypeUnsigned, MinValue: 1, MaxValue: MaxConfigurableConcurrency, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexSerialScanConcurrency, Value: strconv.Itoa(DefIndexSerialScanConcurrency), Type: TypeUnsigned, MinValue: 1, MaxValue: MaxConfigurableConcurrency, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.indexSerialScanConcurrency = tidbOptPositiveInt32(val, DefIndexSerialScanConcurrency)
 		return nil
 	}},
@@ -516,7 +704,13 @@ var defaultSysVars = []*SysVar{
 		}
 		return string(info), nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMaxChunkSize, Value: strconv.Itoa(DefMaxChunkSize), Type: TypeUnsigned, MinValue: maxChunkSizeLowerBound, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeSession, Name: TiDBResourceGroup, Value: "default", ReadOnly: true, skipInit: true, GetSession: func(s *SessionVars) (string, error) {
+		if s.ResourceGroupName == "" {
+			return "default", nil
+		}
+		return s.ResourceGroupName, nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMaxChunkSize, Value: strconv.Itoa(DefMaxChunkSize), Type: TypeUnsigned, MinValue: maxChunkSizeLowerBound, MaxValue: math.MaxInt32, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.MaxChunkSize = tidbOptPositiveInt32(val, DefMaxChunkSize)
 		return nil
 	}},
@@ -529,7 +723,7 @@ var defaultSysVars = []*SysVar{
 		s.AllowBatchCop = int(tidbOptInt64(val, DefTiDBAllowBatchCop))
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBInitChunkSize, Value: strconv.Itoa(DefInitChunkSize), Type: TypeUnsigned, MinValue: 1, MaxValue: initChunkSizeUpperBound, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBInitChunkSize, Value: strconv.Itoa(DefInitChunkSize), Type: TypeUnsigned, MinValue: 1, MaxValue: initChunkSizeUpperBound, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.InitChunkSize = tidbOptPositiveInt32(val, DefInitChunkSize)
 		return nil
 	}},
@@ -537,11 +731,11 @@ var defaultSysVars = []*SysVar{
 		s.SetEnableCascadesPlanner(TiDBOptOn(val))
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableIndexMerge, Value: Off, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableIndexMerge, Value: Off, Type: TypeBool, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.SetEnableIndexMerge(TiDBOptOn(val))
 		return nil
 	}},
-	{Scope: ScopeSession, Name: TiDBMemQuotaQuery, Value: strconv.FormatInt(config.GetGlobalConfig().MemQuotaQuery, 10), skipInit: true, Type: TypeInt, MinValue: -1, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeSession, Name: TiDBMemQuotaQuery, Value: strconv.FormatInt(config.GetGlobalConfig().MemQuotaQuery, 10), skipInit: true, Type: TypeInt, MinValue: -1, MaxValue: math.MaxInt64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.MemQuotaQuery = tidbOptInt64(val, config.GetGlobalConfig().MemQuotaQuery)
 		return nil
 	}},
@@ -611,42 +805,42 @@ var defaultSysVars = []*SysVar{
 		s.EnableListTablePartition = TiDBOptOn(val)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBHashJoinConcurrency, Value: strconv.Itoa(DefTiDBHashJoinConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBHashJoinConcurrency, Value: strconv.Itoa(DefTiDBHashJoinConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.hashJoinConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
 		return nil
 	}, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		appendDeprecationWarning(vars, TiDBHashJoinConcurrency, TiDBExecutorConcurrency)
 		return normalizedValue, nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBProjectionConcurrency, Value: strconv.Itoa(DefTiDBProjectionConcurrency), Type: TypeInt, MinValue: -1, MaxValue: MaxConfigurableConcurrency, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBProjectionConcurrency, Value: strconv.Itoa(DefTiDBProjectionConcurrency), Type: TypeInt, MinValue: -1, MaxValue: MaxConfigurableConcurrency, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.projectionConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
 		return nil
 	}, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		appendDeprecationWarning(vars, TiDBProjectionConcurrency, TiDBExecutorConcurrency)
 		return normalizedValue, nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBHashAggPartialConcurrency, Value: strconv.Itoa(DefTiDBHashAggPartialConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBHashAggPartialConcurrency, Value: strconv.Itoa(DefTiDBHashAggPartialConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.hashAggPartialConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
 		return nil
 	}, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		appendDeprecationWarning(vars, TiDBHashAggPartialConcurrency, TiDBExecutorConcurrency)
 		return normalizedValue, nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBHashAggFinalConcurrency, Value: strconv.Itoa(DefTiDBHashAggFinalConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBHashAggFinalConcurrency, Value: strconv.Itoa(DefTiDBHashAggFinalConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.hashAggFinalConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
 		return nil
 	}, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		appendDeprecationWarning(vars, TiDBHashAggFinalConcurrency, TiDBExecutorConcurrency)
 		return normalizedValue, nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBWindowConcurrency, Value: strconv.Itoa(DefTiDBWindowConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBWindowConcurrency, Value: strconv.Itoa(DefTiDBWindowConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.windowConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
 		return nil
 	}, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		appendDeprecationWarning(vars, TiDBWindowConcurrency, TiDBExecutorConcurrency)
 		return normalizedValue, nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMergeJoinConcurrency, Value: strconv.Itoa(DefTiDBMergeJoinConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMergeJoinConcurrency, Value: strconv.Itoa(DefTiDBMergeJoinConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.mergeJoinConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
 		return nil
 	}, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
@@ -654,21 +848,25 @@ var defaultSysVars = []*SysVar{
 		return normalizedValue, nil
 	}},
 
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBStreamAggConcurrency, Value: strconv.Itoa(DefTiDBStreamAggConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBStreamAggConcurrency, Value: strconv.Itoa(DefTiDBStreamAggConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.streamAggConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
 		return nil
 	}, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		appendDeprecationWarning(vars, TiDBStreamAggConcurrency, TiDBExecutorConcurrency)
 		return normalizedValue, nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableParallelApply, Value: BoolToOnOff(DefTiDBEnableParallelApply), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableParallelApply, Value: BoolToOnOff(DefTiDBEnableParallelApply), Type: TypeBool, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.EnableParallelApply = TiDBOptOn(val)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMemQuotaApplyCache, Value: strconv.Itoa(DefTiDBMemQuotaApplyCache), Type: TypeUnsigned, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMemQuotaApplyCache, Value: strconv.Itoa(DefTiDBMemQuotaApplyCache), Type: TypeUnsigned, MaxValue: math.MaxInt64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.MemQuotaApplyCache = tidbOptInt64(val, DefTiDBMemQuotaApplyCache)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMemQuotaAnalyze, Value: strconv.Itoa(DefTiDBMemQuotaAnalyze), Type: TypeInt, MinValue: -1, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.MemQuotaAnalyze = tidbOptInt64(val, DefTiDBMemQuotaAnalyze)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBBackoffLockFast, Value: strconv.Itoa(tikvstore.DefBackoffLockFast), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
 		s.KVVars.BackoffLockFast = tidbOptPositiveInt32(val, tikvstore.DefBackoffLockFast)
 		return nil
@@ -996,7 +1194,7 @@ var defaultSysVars = []*SysVar{
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskMaxTime, Value: strconv.Itoa(DefTiDBEvolvePlanTaskMaxTime), Type: TypeInt, MinValue: -1, MaxValue: math.MaxInt64},
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskStartTime, Value: DefTiDBEvolvePlanTaskStartTime, Type: TypeTime},
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskEndTime, Value: DefTiDBEvolvePlanTaskEndTime, Type: TypeTime},
-	{Scope: ScopeSession, Name: TiDBIsolationReadEngines, Value: strings.Join(config.GetGlobalConfig().IsolationRead.Engines, ","), Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+	{Scope: ScopeSession, Name: TiDBIsolationReadEngines, Value: strings.Join(config.GetGlobalConfig().IsolationRead.Engines, ","), IsHintUpdatable: true, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		engines := strings.Split(normalizedValue, ",")
 		var formatVal string
 		for i, engine := range engines {
@@ -1069,7 +1267,7 @@ var defaultSysVars = []*SysVar{
 	}, GetSession: func(s *SessionVars) (string, error) {
 		return strconv.FormatUint(atomic.LoadUint64(&config.GetGlobalConfig().Log.QueryLogMaxLen), 10), nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: CTEMaxRecursionDepth, Value: strconv.Itoa(DefCTEMaxRecursionDepth), Type: TypeInt, MinValue: 0, MaxValue: 4294967295, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: CTEMaxRecursionDepth, Value: strconv.Itoa(DefCTEMaxRecursionDepth), Type: TypeInt, MinValue: 0, MaxValue: 4294967295, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
 		s.CTEMaxRecursionDepth = tidbOptInt(val, DefCTEMaxRecursionDepth)
 		return nil
 	}},
@@ -1128,7 +1326,41 @@ var defaultSysVars = []*SysVar{
 	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBRedactLog, Value: BoolToOnOff(DefTiDBRedactLog), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableRedactLog = TiDBOptOn(val)
-		errors.RedactLogEnabled.Store(s.EnableRedactLog)
+		errors.RedactLogEnabled.Store(s.ShouldRedactLog())
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBLogDesensitization, Value: BoolToOnOff(DefTiDBLogDesensitization), Type: TypeBool, GetGlobal: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(LogDesensitization.Load()), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		LogDesensitization.Store(TiDBOptOn(val))
+		// The redaction of the errors is restored by tidb_redact_log after it's turned off.
+		if LogDesensitization.Load() {
+			errors.RedactLogEnabled.Store(true)
+		}
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBSlowLogFormat, Value: DefTiDBSlowLogFormat, Type: TypeEnum, PossibleValues: []string{SlowLogFormatText, SlowLogFormatJSON}, GetGlobal: func(s *SessionVars) (string, error) {
+		return SlowLogFormatValue.Load(), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		SlowLogFormatValue.Store(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBEnablePlanReplayerCapture, Value: BoolToOnOff(DefTiDBEnablePlanReplayerCapture), Type: TypeBool, GetGlobal: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(EnablePlanReplayerCapture.Load()), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		EnablePlanReplayerCapture.Store(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBPlanReplayerCaptureThreshold, Value: strconv.Itoa(DefTiDBPlanReplayerCaptureThreshold), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatInt(PlanReplayerCaptureThreshold.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		PlanReplayerCaptureThreshold.Store(tidbOptInt64(val, DefTiDBPlanReplayerCaptureThreshold))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBPlanReplayerCaptureMaxFiles, Value: strconv.Itoa(DefTiDBPlanReplayerCaptureMaxFiles), Type: TypeInt, MinValue: 1, MaxValue: 10000, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatInt(PlanReplayerCaptureMaxFiles.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		PlanReplayerCaptureMaxFiles.Store(tidbOptInt64(val, DefTiDBPlanReplayerCaptureMaxFiles))
 		return nil
 	}},
 	{Scope: ScopeGlobal, Name: TiDBRestrictedReadOnly, Value: BoolToOnOff(DefTiDBRestrictedReadOnly), Type: TypeBool},
@@ -1173,6 +1405,10 @@ var defaultSysVars = []*SysVar{
 		s.AnalyzeVersion = tidbOptPositiveInt32(val, DefTiDBAnalyzeVersion)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeIndexTopNNum, Value: strconv.Itoa(DefTiDBAnalyzeIndexTopNNum), Type: TypeUnsigned, MinValue: 0, MaxValue: 1024, SetSession: func(s *SessionVars, val string) error {
+		s.AnalyzeIndexTopNNum = tidbOptPositiveInt32(val, DefTiDBAnalyzeIndexTopNNum)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableIndexMergeJoin, Value: BoolToOnOff(DefTiDBEnableIndexMergeJoin), Hidden: true, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableIndexMergeJoin = TiDBOptOn(val)
 		return nil
@@ -1239,11 +1475,53 @@ var defaultSysVars = []*SysVar{
 	}, SetGlobal: func(s *SessionVars, val string) error {
 		return setTiDBTableValue(s, "tikv_gc_scan_lock_mode", val, "Mode of scanning locks, \"physical\" or \"legacy\"")
 	}},
+	{Scope: ScopeGlobal, Name: TiDBServerMemoryLimit, Value: strconv.Itoa(DefTiDBServerMemoryLimit), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint64, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatUint(ServerMemoryLimit.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		limit, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			return err
+		}
+		ServerMemoryLimit.Store(limit)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBConnEgressBytesPerSec, Value: strconv.Itoa(DefTiDBConnEgressBytesPerSec), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt64, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatUint(ConnEgressBytesPerSec.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		limit, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			return err
+		}
+		ConnEgressBytesPerSec.Store(limit)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBStatsCacheMemQuota, Value: strconv.Itoa(DefTiDBStatsCacheMemQuota), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatInt(StatsCacheMemQuota.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		StatsCacheMemQuota.Store(tidbOptInt64(val, DefTiDBStatsCacheMemQuota))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBEnableColumnTracking, Value: BoolToOnOff(DefTiDBEnableColumnTracking), Type: TypeBool, GetGlobal: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(EnableColumnTracking.Load()), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		EnableColumnTracking.Store(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBEnableHistogramCorrection, Value: BoolToOnOff(DefTiDBEnableHistogramCorrection), Type: TypeBool, GetGlobal: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(EnableHistogramCorrection.Load()), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		EnableHistogramCorrection.Store(TiDBOptOn(val))
+		return nil
+	}},
 	// It's different from tmp_table_size or max_heap_table_size. See https://github.com/pingcap/tidb/issues/28691.
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBTmpTableMaxSize, Value: strconv.Itoa(DefTiDBTmpTableMaxSize), Type: TypeUnsigned, MinValue: 1 << 20, MaxValue: 1 << 37, SetSession: func(s *SessionVars, val string) error {
 		s.TMPTableSize = tidbOptInt64(val, DefTiDBTmpTableMaxSize)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBTmpStorageQuotaQuery, Value: strconv.Itoa(DefTiDBTmpStorageQuotaQuery), Type: TypeInt, MinValue: -1, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.TmpStorageQuotaQuery = tidbOptInt64(val, DefTiDBTmpStorageQuotaQuery)
+		return nil
+	}},
 	// variable for top SQL feature.
 	{Scope: ScopeGlobal, Name: TiDBEnableTopSQL, Value: BoolToOnOff(DefTiDBTopSQLEnable), Type: TypeBool, Hidden: true, AllowEmpty: true, GetGlobal: func(s *SessionVars) (string, error) {
 		return BoolToOnOff(TopSQLVariable.Enable.Load()), nil
@@ -1325,6 +1603,62 @@ var defaultSysVars = []*SysVar{
 		s.EnablePaging = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableSortedShuffle, Value: BoolToOnOff(DefTiDBEnableSortedShuffle), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableSortedShuffle = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBLoadDataResume, Value: BoolToOnOff(DefTiDBLoadDataResume), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.LoadDataResume = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBCollectDMLErrors, Value: BoolToOnOff(DefTiDBCollectDMLErrors), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.CollectDMLErrors = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBCollectDMLErrorsMaxSize, Value: strconv.Itoa(DefTiDBCollectDMLErrorsMaxSize), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.CollectDMLErrorsMaxSize = tidbOptInt64(val, DefTiDBCollectDMLErrorsMaxSize)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableFastTableCheck, Value: BoolToOnOff(DefTiDBEnableFastTableCheck), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.FastCheckTable = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBRCReadCheckTS, Value: BoolToOnOff(DefTiDBRCReadCheckTS), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.RcReadCheckTS = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIdleTransactionTimeout, Value: strconv.Itoa(DefTiDBIdleTransactionTimeout), Type: TypeUnsigned, MinValue: 0, MaxValue: secondsPerYear, SetSession: func(s *SessionVars, val string) error {
+		s.IdleTransactionTimeout = tidbOptInt(val, DefTiDBIdleTransactionTimeout)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBExternalTS, Value: strconv.Itoa(DefTiDBExternalTS), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint64, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		// The external timestamp can't go backwards, otherwise the reads may see the data which has been changed.
+		original, err := vars.GlobalVarsAccessor.GetGlobalSysVar(TiDBExternalTS)
+		if err != nil {
+			return normalizedValue, err
+		}
+		originalTS, _ := strconv.ParseUint(original, 10, 64)
+		if ts, _ := strconv.ParseUint(normalizedValue, 10, 64); ts < originalTS {
+			return original, ErrWrongValueForVar.GenWithStackByArgs(TiDBExternalTS, originalValue)
+		}
+		return normalizedValue, nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableExternalTSRead, Value: BoolToOnOff(DefTiDBEnableExternalTSRead), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableExternalTSRead = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableDMLMaxExecutionTime, Value: BoolToOnOff(DefTiDBEnableDMLMaxExecutionTime), Type: TypeBool, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
+		s.EnableDMLMaxExecutionTime = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBSessionGCLifeTime, Value: time.Duration(DefTiDBSessionGCLifeTime).String(), Type: TypeDuration, MinValue: 0, MaxValue: uint64(time.Hour * 24 * 365), SetSession: func(s *SessionVars, val string) error {
+		lifeTime, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		s.GCLifeTime = lifeTime
+		return nil
+	}},
 }
 
 // FeedbackProbability points to the FeedbackProbability in statistics package.
@@ -1397,6 +1731,86 @@ const (
 	MaxSpRecursionDepth = "max_sp_recursion_depth"
 	// MaxUserConnections is the name for 'max_user_connections' system variable.
 	MaxUserConnections = "max_user_connections"
+	// MandatoryRoles is the name for 'mandatory_roles' system variable.
+	MandatoryRoles = "mandatory_roles"
+	// ActivateAllRolesOnLogin is the name for 'activate_all_roles_on_login' system variable.
+	ActivateAllRolesOnLogin = "activate_all_roles_on_login"
+	// AuthenticationLDAPSimpleServerHost is the name for 'authentication_ldap_simple_server_host' system variable.
+	AuthenticationLDAPSimpleServerHost = "authentication_ldap_simple_server_host"
+	// AuthenticationLDAPSimpleServerPort is the name for 'authentication_ldap_simple_server_port' system variable.
+	AuthenticationLDAPSimpleServerPort = "authentication_ldap_simple_server_port"
+	// AuthenticationLDAPSimpleTLS is the name for 'authentication_ldap_simple_tls' system variable.
+	AuthenticationLDAPSimpleTLS = "authentication_ldap_simple_tls"
+	// AuthenticationLDAPSimpleCAPath is the name for 'authentication_ldap_simple_ca_path' system variable.
+	AuthenticationLDAPSimpleCAPath = "authentication_ldap_simple_ca_path"
+	// AuthenticationLDAPSimpleBindBaseDN is the name for 'authentication_ldap_simple_bind_base_dn' system variable.
+	AuthenticationLDAPSimpleBindBaseDN = "authentication_ldap_simple_bind_base_dn"
+	// AuthenticationLDAPSimpleBindRootDN is the name for 'authentication_ldap_simple_bind_root_dn' system variable.
+	AuthenticationLDAPSimpleBindRootDN = "authentication_ldap_simple_bind_root_dn"
+	// AuthenticationLDAPSimpleBindRootPWD is the name for 'authentication_ldap_simple_bind_root_pwd' system variable.
+	AuthenticationLDAPSimpleBindRootPWD = "authentication_ldap_simple_bind_root_pwd"
+	// AuthenticationLDAPSimpleUserSearchAttr is the name for 'authentication_ldap_simple_user_search_attr' system variable.
+	AuthenticationLDAPSimpleUserSearchAttr = "authentication_ldap_simple_user_search_attr"
+	// AuthenticationLDAPSimpleGroupSearchAttr is the name for 'authentication_ldap_simple_group_search_attr' system variable.
+	AuthenticationLDAPSimpleGroupSearchAttr = "authentication_ldap_simple_group_search_attr"
+	// AuthenticationLDAPSimpleGroupRoleMapping is the name for 'authentication_ldap_simple_group_role_mapping' system variable.
+	AuthenticationLDAPSimpleGroupRoleMapping = "authentication_ldap_simple_group_role_mapping"
+	// AuthenticationLDAPSimpleInitPoolSize is the name for 'authentication_ldap_simple_init_pool_size' system variable.
+	AuthenticationLDAPSimpleInitPoolSize = "authentication_ldap_simple_init_pool_size"
+	// AuthenticationLDAPSimpleMaxPoolSize is the name for 'authentication_ldap_simple_max_pool_size' system variable.
+	AuthenticationLDAPSimpleMaxPoolSize = "authentication_ldap_simple_max_pool_size"
+	// AuthenticationLDAPSimpleBindCacheTTL is the name for 'authentication_ldap_simple_bind_cache_ttl' system variable.
+	AuthenticationLDAPSimpleBindCacheTTL = "authentication_ldap_simple_bind_cache_ttl"
+	// AuthenticationLDAPSASLServerHost is the name for 'authentication_ldap_sasl_server_host' system variable.
+	AuthenticationLDAPSASLServerHost = "authentication_ldap_sasl_server_host"
+	// AuthenticationLDAPSASLServerPort is the name for 'authentication_ldap_sasl_server_port' system variable.
+	AuthenticationLDAPSASLServerPort = "authentication_ldap_sasl_server_port"
+	// AuthenticationLDAPSASLTLS is the name for 'authentication_ldap_sasl_tls' system variable.
+	AuthenticationLDAPSASLTLS = "authentication_ldap_sasl_tls"
+	// AuthenticationLDAPSASLCAPath is the name for 'authentication_ldap_sasl_ca_path' system variable.
+	AuthenticationLDAPSASLCAPath = "authentication_ldap_sasl_ca_path"
+	// AuthenticationLDAPSASLBindBaseDN is the name for 'authentication_ldap_sasl_bind_base_dn' system variable.
+	AuthenticationLDAPSASLBindBaseDN = "authentication_ldap_sasl_bind_base_dn"
+	// AuthenticationLDAPSASLBindRootDN is the name for 'authentication_ldap_sasl_bind_root_dn' system variable.
+	AuthenticationLDAPSASLBindRootDN = "authentication_ldap_sasl_bind_root_dn"
+	// AuthenticationLDAPSASLBindRootPWD is the name for 'authentication_ldap_sasl_bind_root_pwd' system variable.
+	AuthenticationLDAPSASLBindRootPWD = "authentication_ldap_sasl_bind_root_pwd"
+	// AuthenticationLDAPSASLUserSearchAttr is the name for 'authentication_ldap_sasl_user_search_attr' system variable.
+	AuthenticationLDAPSASLUserSearchAttr = "authentication_ldap_sasl_user_search_attr"
+	// AuthenticationLDAPSASLGroupSearchAttr is the name for 'authentication_ldap_sasl_group_search_attr' system variable.
+	AuthenticationLDAPSASLGroupSearchAttr = "authentication_ldap_sasl_group_search_attr"
+	// AuthenticationLDAPSASLGroupRoleMapping is the name for 'authentication_ldap_sasl_group_role_mapping' system variable.
+	AuthenticationLDAPSASLGroupRoleMapping = "authentication_ldap_sasl_group_role_mapping"
+	// AuthenticationLDAPSASLInitPoolSize is the name for 'authentication_ldap_sasl_init_pool_size' system variable.
+	AuthenticationLDAPSASLInitPoolSize = "authentication_ldap_sasl_init_pool_size"
+	// AuthenticationLDAPSASLMaxPoolSize is the name for 'authentication_ldap_sasl_max_pool_size' system variable.
+	AuthenticationLDAPSASLMaxPoolSize = "authentication_ldap_sasl_max_pool_size"
+	// AuthenticationLDAPSASLAuthMethodName is the name for 'authentication_ldap_sasl_auth_method_name' system variable.
+	AuthenticationLDAPSASLAuthMethodName = "authentication_ldap_sasl_auth_method_name"
+	// DefaultPasswordLifetime is the name for 'default_password_lifetime' system variable.
+	DefaultPasswordLifetime = "default_password_lifetime"
+	// DisconnectOnExpiredPassword is the name for 'disconnect_on_expired_password' system variable.
+	DisconnectOnExpiredPassword = "disconnect_on_expired_password"
+	// PasswordHistory is the name for 'password_history' system variable.
+	PasswordHistory = "password_history"
+	// PasswordReuseInterval is the name for 'password_reuse_interval' system variable.
+	PasswordReuseInterval = "password_reuse_interval"
+	// ValidatePasswordEnable is the name for 'validate_password.enable' system variable.
+	ValidatePasswordEnable = "validate_password.enable"
+	// ValidatePasswordPolicy is the name for 'validate_password.policy' system variable.
+	ValidatePasswordPolicy = "validate_password.policy"
+	// ValidatePasswordLength is the name for 'validate_password.length' system variable.
+	ValidatePasswordLength = "validate_password.length"
+	// ValidatePasswordMixedCaseCount is the name for 'validate_password.mixed_case_count' system variable.
+	ValidatePasswordMixedCaseCount = "validate_password.mixed_case_count"
+	// ValidatePasswordNumberCount is the name for 'validate_password.number_count' system variable.
+	ValidatePasswordNumberCount = "validate_password.number_count"
+	// ValidatePasswordSpecialCharCount is the name for 'validate_password.special_char_count' system variable.
+	ValidatePasswordSpecialCharCount = "validate_password.special_char_count"
+	// ValidatePasswordCheckUserName is the name for 'validate_password.check_user_name' system variable.
+	ValidatePasswordCheckUserName = "validate_password.check_user_name"
+	// ValidatePasswordDictionary is the name for 'validate_password.dictionary' system variable.
+	ValidatePasswordDictionary = "validate_password.dictionary"
 	// OfflineMode is the name for 'offline_mode' system variable.
 	OfflineMode = "offline_mode"
 	// InteractiveTimeout is the name for 'interactive_timeout' system variable.
@@ -1443,10 +1857,6 @@ const (
 	BlockEncryptionMode = "block_encryption_mode"
 	// WaitTimeout is the name for 'wait_timeout' system variable.
 	WaitTimeout = "wait_timeout"
-	// ValidatePasswordNumberCount is the name of 'validate_password_number_count' system variable.
-	ValidatePasswordNumberCount = "validate_password_number_count"
-	// ValidatePasswordLength is the name of 'validate_password_length' system variable.
-	ValidatePasswordLength = "validate_password_length"
 	// Version is the name of 'version' system variable.
 	Version = "version"
 	// VersionComment is the name of 'version_comment' system variable.
@@ -1467,8 +1877,6 @@ const (
 	BinlogOrderCommits = "binlog_order_commits"
 	// MasterVerifyChecksum is the name for 'master_verify_checksum' system variable.
 	MasterVerifyChecksum = "master_verify_checksum"
-	// ValidatePasswordCheckUserName is the name for 'validate_password_check_user_name' system variable.
-	ValidatePasswordCheckUserName = "validate_password_check_user_name"
 	// SuperReadOnly is the name for 'super_read_only' system variable.
 	SuperReadOnly = "super_read_only"
 	// SQLNotes is the name for 'sql_notes' system variable.
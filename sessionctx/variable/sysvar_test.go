@@ -525,6 +525,18 @@ func TestSkipInit(t *testing.T) {
 	require.True(t, sv.SkipInit())
 }
 
+// TestHintUpdatableVars checks that the variables in the SET_VAR hint whitelist can be safely set for a statement.
+func TestHintUpdatableVars(t *testing.T) {
+	for _, sv := range GetSysVars() {
+		if !sv.IsHintUpdatable {
+			continue
+		}
+		require.True(t, sv.HasSessionScope(), sv.Name)
+		require.Nil(t, sv.GetSession, sv.Name)
+		require.Len(t, sv.Aliases, 0, sv.Name)
+	}
+}
+
 // TestIsNoop is used by the documentation to auto-generate docs for real sysvars.
 func TestIsNoop(t *testing.T) {
 	sv := GetSysVar(TiDBMultiStatementMode)
@@ -70,6 +70,9 @@ const (
 	TiDBAutoAnalyzeStartTime = "tidb_auto_analyze_start_time"
 	TiDBAutoAnalyzeEndTime   = "tidb_auto_analyze_end_time"
 
+	// tidb_auto_analyze_concurrency is the max number of tables or partitions analyzed by auto analyze at the same time.
+	TiDBAutoAnalyzeConcurrency = "tidb_auto_analyze_concurrency"
+
 	// tidb_checksum_table_concurrency is used to speed up the ADMIN CHECKSUM TABLE
 	// statement, when a table has multiple indices, those indices can be
 	// scanned concurrently, with the cost of higher system performance impact.
@@ -85,6 +88,10 @@ const (
 	// TiDBLastTxnInfo is used to get the last query info within the current session.
 	TiDBLastQueryInfo = "tidb_last_query_info"
 
+	// TiDBResourceGroup is used to get the resource group which the current session is bound to.
+	// It is read-only, use `SET RESOURCE GROUP` to change it.
+	TiDBResourceGroup = "tidb_resource_group"
+
 	// tidb_config is a read-only variable that shows the config of the current server.
 	TiDBConfig = "tidb_config"
 
@@ -110,6 +117,8 @@ const (
 	// "tidb_mem_quota_query":				control the memory quota of a query.
 	TiDBMemQuotaQuery      = "tidb_mem_quota_query" // Bytes.
 	TiDBMemQuotaApplyCache = "tidb_mem_quota_apply_cache"
+	// TiDBMemQuotaAnalyze is the memory quota of the samples held by ANALYZE, -1 means no limit.
+	TiDBMemQuotaAnalyze = "tidb_mem_quota_analyze"
 	// TODO: remove them below sometime, it should have only one Quota(TiDBMemQuotaQuery).
 	TiDBMemQuotaHashJoin          = "tidb_mem_quota_hashjoin"          // Bytes.
 	TiDBMemQuotaMergeJoin         = "tidb_mem_quota_mergejoin"         // Bytes.
@@ -556,6 +565,10 @@ const (
 	// TiDBAnalyzeVersion indicates the how tidb collects the analyzed statistics and how use to it.
 	TiDBAnalyzeVersion = "tidb_analyze_version"
 
+	// TiDBAnalyzeIndexTopNNum indicates the TopN size of the index statistics collected by ANALYZE. The skewed join keys
+	// are usually indexed, so a larger TopN for the indexes helps to estimate the joins on them.
+	TiDBAnalyzeIndexTopNNum = "tidb_analyze_index_topn_num"
+
 	// TiDBEnableIndexMergeJoin indicates whether to enable index merge join.
 	TiDBEnableIndexMergeJoin = "tidb_enable_index_merge_join"
 
@@ -603,6 +616,29 @@ const (
 
 	// TiDBTmpTableMaxSize indicates the max memory size of temporary tables.
 	TiDBTmpTableMaxSize = "tidb_tmp_table_max_size"
+
+	// TiDBTmpStorageQuotaQuery indicates the quota of the temporary storage which a query can use to spill data.
+	TiDBTmpStorageQuotaQuery = "tidb_tmp_storage_quota_query"
+
+	// TiDBEnableSortedShuffle indicates whether to run StreamAgg and Window in parallel by shuffling
+	// their already sorted child by range.
+	TiDBEnableSortedShuffle = "tidb_enable_sorted_shuffle"
+
+	// TiDBLoadDataResume indicates whether LOAD DATA skips the lines which have been committed by
+	// a previous interrupted load of the same file into the same table.
+	TiDBLoadDataResume = "tidb_load_data_resume"
+
+	// TiDBCollectDMLErrors indicates whether to collect the rows rejected by INSERT IGNORE, UPDATE IGNORE
+	// and LOAD DATA with the reasons, which are shown by SHOW LAST DML ERRORS.
+	TiDBCollectDMLErrors = "tidb_collect_dml_errors"
+
+	// TiDBCollectDMLErrorsMaxSize is the max bytes of the rows rejected by a DML statement to collect
+	// with tidb_collect_dml_errors.
+	TiDBCollectDMLErrorsMaxSize = "tidb_collect_dml_errors_max_size"
+
+	// TiDBEnableFastTableCheck indicates whether ADMIN CHECK TABLE compares the checksums of the table and
+	// index data computed by the coprocessor instead of looking up the rows one by one.
+	TiDBEnableFastTableCheck = "tidb_enable_fast_table_check"
 )
 
 // TiDB vars that have only global scope
@@ -622,6 +658,53 @@ const (
 	TiDBEnableEnhancedSecurity = "tidb_enable_enhanced_security"
 	// TiDBEnableHistoricalStats enables the historical statistics feature (default off)
 	TiDBEnableHistoricalStats = "tidb_enable_historical_stats"
+	// TiDBServerMemoryLimit indicates the memory limit of the tidb-server instance. When the memory usage
+	// exceeds it, the statement consuming the most memory is cancelled. 0 means no limit.
+	TiDBServerMemoryLimit = "tidb_server_memory_limit"
+	// TiDBStatsCacheMemQuota indicates the memory quota of the stats cache. When the stats cache exceeds it,
+	// the column statistics of the least recently used tables are evicted. 0 means no limit.
+	TiDBStatsCacheMemQuota = "tidb_stats_cache_mem_quota"
+	// TiDBEnableColumnTracking enables collecting predicate columns, which are used by `ANALYZE TABLE t PREDICATE COLUMNS`.
+	TiDBEnableColumnTracking = "tidb_enable_column_tracking"
+	// TiDBEnableHistogramCorrection enables correcting the row count estimations of the histogram buckets by the actual
+	// row counts of the executed scans.
+	TiDBEnableHistogramCorrection = "tidb_enable_histogram_correction"
+	// TiDBRCReadCheckTS indicates whether the read-only statements in the RC transactions reuse the last fetched
+	// timestamp, and retry with a new one only if a newer version is met.
+	TiDBRCReadCheckTS = "tidb_rc_read_check_ts"
+	// TiDBIdleTransactionTimeout is the max seconds a session can be idle in a transaction, the connection is closed
+	// and the transaction is rolled back when it's exceeded. 0 means no limit.
+	TiDBIdleTransactionTimeout = "tidb_idle_transaction_timeout"
+	// TiDBExternalTS is the timestamp which the data replicated from the upstream cluster is consistent at, it's
+	// used by the reads when TiDBEnableExternalTSRead is on.
+	TiDBExternalTS = "tidb_external_ts"
+	// TiDBEnableExternalTSRead indicates whether the reads use the timestamp of TiDBExternalTS.
+	TiDBEnableExternalTSRead = "tidb_enable_external_ts_read"
+	// TiDBSessionGCLifeTime is the GC life time required by the session, the GC safe point is held back to keep the
+	// versions within it while the session is alive. 0 means no requirement.
+	TiDBSessionGCLifeTime = "tidb_session_gc_life_time"
+	// TiDBConnEgressBytesPerSec is the max bytes per second of the result sets sent to a connection, the writes
+	// are throttled when it's exceeded. 0 means no limit.
+	TiDBConnEgressBytesPerSec = "tidb_conn_egress_bytes_per_sec"
+	// TiDBEnableDMLMaxExecutionTime indicates whether max_execution_time also limits the INSERT, REPLACE, UPDATE and
+	// DELETE statements, it only limits the SELECT statements by default.
+	TiDBEnableDMLMaxExecutionTime = "tidb_enable_dml_max_execution_time"
+	// TiDBLogDesensitization indicates whether the literals are replaced with placeholders in all the logs of all the
+	// sessions, including the slow log, the general log, the audit log and the processlist shown to the other users.
+	// Unlike TiDBRedactLog, it can't be turned off by a session.
+	TiDBLogDesensitization = "tidb_log_desensitization"
+	// TiDBSlowLogFormat is the format of the slow log, 'text' or 'json'. In the 'json' format, every slow log entry
+	// is a JSON object in one line.
+	TiDBSlowLogFormat = "tidb_slow_log_format"
+	// TiDBEnablePlanReplayerCapture indicates whether the slow statements and the statements whose plans deviate from
+	// the baselines are captured into the plan replayer zips automatically.
+	TiDBEnablePlanReplayerCapture = "tidb_enable_plan_replayer_capture"
+	// TiDBPlanReplayerCaptureThreshold is the latency threshold in milliseconds above which the statements are
+	// captured, 0 means the statements are captured only when their plans change.
+	TiDBPlanReplayerCaptureThreshold = "tidb_plan_replayer_capture_threshold"
+	// TiDBPlanReplayerCaptureMaxFiles is the max number of the captured plan replayer zips kept in every tidb-server,
+	// the oldest ones are removed first.
+	TiDBPlanReplayerCaptureMaxFiles = "tidb_plan_replayer_capture_max_files"
 )
 
 // TiDB intentional limits
@@ -646,6 +729,7 @@ const (
 	DefAutoAnalyzeRatio                   = 0.5
 	DefAutoAnalyzeStartTime               = "00:00 +0000"
 	DefAutoAnalyzeEndTime                 = "23:59 +0000"
+	DefAutoAnalyzeConcurrency             = 1
 	DefAutoIncrementIncrement             = 1
 	DefAutoIncrementOffset                = 1
 	DefChecksumTableConcurrency           = 4
@@ -682,6 +766,7 @@ const (
 	DefMaxPreparedStmtCount               = -1
 	DefWaitTimeout                        = 28800
 	DefTiDBMemQuotaApplyCache             = 32 << 20 // 32MB.
+	DefTiDBMemQuotaAnalyze                = -1
 	DefTiDBMemQuotaHashJoin               = 32 << 30 // 32GB.
 	DefTiDBMemQuotaMergeJoin              = 32 << 30 // 32GB.
 	DefTiDBMemQuotaSort                   = 32 << 30 // 32GB.
@@ -761,6 +846,7 @@ const (
 	DefTiDBEnable1PC                      = false
 	DefTiDBGuaranteeLinearizability       = true
 	DefTiDBAnalyzeVersion                 = 2
+	DefTiDBAnalyzeIndexTopNNum            = 0
 	DefTiDBEnableIndexMergeJoin           = false
 	DefTiDBTrackAggregateMemoryUsage      = true
 	DefTiDBEnableExchangePartition        = false
@@ -771,6 +857,7 @@ const (
 	DefTiDBTopSQLMaxCollect               = 5000
 	DefTiDBTopSQLReportIntervalSeconds    = 60
 	DefTiDBTmpTableMaxSize                = 64 << 20 // 64MB.
+	DefTiDBTmpStorageQuotaQuery           = -1
 	DefTiDBEnableLocalTxn                 = false
 	DefTiDBTSOClientBatchMaxWaitTime      = 0.0 // 0ms
 	DefTiDBEnableTSOFollowerProxy         = false
@@ -779,6 +866,69 @@ const (
 	DefTiDBRegardNULLAsPoint              = true
 	DefEnablePlacementCheck               = true
 	DefTimestamp                          = "0"
+	DefTiDBEnableSortedShuffle            = false
+	DefTiDBLoadDataResume                 = false
+	DefTiDBCollectDMLErrors               = false
+	DefTiDBCollectDMLErrorsMaxSize        = 1 << 20
+	DefTiDBEnableFastTableCheck           = false
+	DefTiDBServerMemoryLimit              = 0
+	DefTiDBStatsCacheMemQuota             = 0
+	DefTiDBEnableColumnTracking           = false
+	DefTiDBEnableHistogramCorrection      = false
+	DefTiDBRCReadCheckTS                  = false
+	DefTiDBIdleTransactionTimeout         = 0
+	DefTiDBExternalTS                     = 0
+	DefTiDBEnableExternalTSRead           = false
+	DefTiDBSessionGCLifeTime              = 0
+	DefMaxUserConnections                 = 0
+	DefTiDBConnEgressBytesPerSec          = 0
+	DefTiDBEnableDMLMaxExecutionTime      = false
+	DefActivateAllRolesOnLogin            = false
+	DefTiDBLogDesensitization             = false
+	DefTiDBSlowLogFormat                  = SlowLogFormatText
+	DefTiDBEnablePlanReplayerCapture      = false
+	DefTiDBPlanReplayerCaptureThreshold   = 3000
+	DefTiDBPlanReplayerCaptureMaxFiles    = 100
+)
+
+// The formats of the slow log.
+const (
+	// SlowLogFormatText is the MySQL compatible text format, every field is written in a line prefixed by "# ".
+	SlowLogFormatText = "text"
+	// SlowLogFormatJSON writes every slow log entry as a JSON object in one line.
+	SlowLogFormatJSON = "json"
+)
+
+// Default values of the LDAP authentication system variables.
+const (
+	DefAuthenticationLDAPSimpleServerPort      = 389
+	DefAuthenticationLDAPSimpleTLS             = false
+	DefAuthenticationLDAPSimpleUserSearchAttr  = "uid"
+	DefAuthenticationLDAPSimpleGroupSearchAttr = "cn"
+	DefAuthenticationLDAPSimpleInitPoolSize    = 10
+	DefAuthenticationLDAPSimpleMaxPoolSize     = 1000
+	DefAuthenticationLDAPSimpleBindCacheTTL    = 0
+	DefAuthenticationLDAPSASLServerPort        = 389
+	DefAuthenticationLDAPSASLTLS               = false
+	DefAuthenticationLDAPSASLUserSearchAttr    = "uid"
+	DefAuthenticationLDAPSASLGroupSearchAttr   = "cn"
+	DefAuthenticationLDAPSASLInitPoolSize      = 10
+	DefAuthenticationLDAPSASLMaxPoolSize       = 1000
+	DefAuthenticationLDAPSASLAuthMethodName    = "SCRAM-SHA-1"
+)
+
+// Default values of the password management system variables.
+const (
+	DefDefaultPasswordLifetime          = 0
+	DefPasswordHistory                  = 0
+	DefPasswordReuseInterval            = 0
+	DefValidatePasswordEnable           = false
+	DefValidatePasswordPolicy           = "MEDIUM"
+	DefValidatePasswordLength           = 8
+	DefValidatePasswordMixedCaseCount   = 1
+	DefValidatePasswordNumberCount      = 1
+	DefValidatePasswordSpecialCharCount = 1
+	DefValidatePasswordCheckUserName    = true
 )
 
 // Process global variables.
@@ -810,10 +960,26 @@ var (
 		MaxCollect:            atomic.NewInt64(DefTiDBTopSQLMaxCollect),
 		ReportIntervalSeconds: atomic.NewInt64(DefTiDBTopSQLReportIntervalSeconds),
 	}
-	EnableLocalTxn          = atomic.NewBool(DefTiDBEnableLocalTxn)
-	MaxTSOBatchWaitInterval = atomic.NewFloat64(DefTiDBTSOClientBatchMaxWaitTime)
-	EnableTSOFollowerProxy  = atomic.NewBool(DefTiDBEnableTSOFollowerProxy)
-	RestrictedReadOnly      = atomic.NewBool(DefTiDBRestrictedReadOnly)
+	EnableLocalTxn            = atomic.NewBool(DefTiDBEnableLocalTxn)
+	MaxTSOBatchWaitInterval   = atomic.NewFloat64(DefTiDBTSOClientBatchMaxWaitTime)
+	EnableTSOFollowerProxy    = atomic.NewBool(DefTiDBEnableTSOFollowerProxy)
+	RestrictedReadOnly        = atomic.NewBool(DefTiDBRestrictedReadOnly)
+	ServerMemoryLimit         = atomic.NewUint64(DefTiDBServerMemoryLimit)
+	StatsCacheMemQuota        = atomic.NewInt64(DefTiDBStatsCacheMemQuota)
+	EnableColumnTracking      = atomic.NewBool(DefTiDBEnableColumnTracking)
+	EnableHistogramCorrection = atomic.NewBool(DefTiDBEnableHistogramCorrection)
+	MaxUserConnectionsValue   = atomic.NewUint32(DefMaxUserConnections)
+	ConnEgressBytesPerSec     = atomic.NewUint64(DefTiDBConnEgressBytesPerSec)
+	// MandatoryRolesValue is the roles granted to all the users, it's parsed by ParseRoleList.
+	MandatoryRolesValue          = atomic.NewString("")
+	ActivateAllRolesOnLoginValue = atomic.NewBool(DefActivateAllRolesOnLogin)
+	// DefaultPasswordLifetimeValue is the days after which the passwords expire, 0 means they never expire.
+	DefaultPasswordLifetimeValue = atomic.NewUint32(DefDefaultPasswordLifetime)
+	LogDesensitization           = atomic.NewBool(DefTiDBLogDesensitization)
+	SlowLogFormatValue           = atomic.NewString(DefTiDBSlowLogFormat)
+	EnablePlanReplayerCapture    = atomic.NewBool(DefTiDBEnablePlanReplayerCapture)
+	PlanReplayerCaptureThreshold = atomic.NewInt64(DefTiDBPlanReplayerCaptureThreshold)
+	PlanReplayerCaptureMaxFiles  = atomic.NewInt64(DefTiDBPlanReplayerCaptureMaxFiles)
 )
 
 // TopSQL is the variable for control top sql feature.
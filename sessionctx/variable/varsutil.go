@@ -25,6 +25,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
@@ -485,6 +486,35 @@ func (v *serverGlobalVariable) GetVal() string {
 	return v.globalVal
 }
 
+// ParseRoleList parses the roles separated by commas, such as `r1, 'r2'@'localhost'`. The host is '%' if it's omitted.
+func ParseRoleList(s string) ([]*auth.RoleIdentity, error) {
+	var roles []*auth.RoleIdentity
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, host := item, "%"
+		if i := strings.LastIndex(item, "@"); i >= 0 {
+			name, host = item[:i], item[i+1:]
+		}
+		name, host = unquoteRoleName(name), unquoteRoleName(host)
+		if name == "" || host == "" {
+			return nil, errors.Errorf("invalid role %s", item)
+		}
+		roles = append(roles, &auth.RoleIdentity{Username: name, Hostname: strings.ToLower(host)})
+	}
+	return roles, nil
+}
+
+func unquoteRoleName(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"' || s[0] == '`') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
 func collectAllowFuncName4ExpressionIndex() string {
 	var str []string
 	for funcName := range GAFunction4ExpressionIndex {
@@ -23,6 +23,7 @@ import (
 
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/stretchr/testify/require"
@@ -747,6 +748,28 @@ func TestHelperFuncs(t *testing.T) {
 	require.Equal(t, 5, tidbOptInt("bogus", 5))
 }
 
+func TestParseRoleList(t *testing.T) {
+	roles, err := ParseRoleList("")
+	require.NoError(t, err)
+	require.Len(t, roles, 0)
+	roles, err = ParseRoleList("r1, 'r2'@'LocalHost',`r3`@\"%\", ")
+	require.NoError(t, err)
+	require.Equal(t, []*auth.RoleIdentity{{Username: "r1", Hostname: "%"}, {Username: "r2", Hostname: "localhost"}, {Username: "r3", Hostname: "%"}}, roles)
+	_, err = ParseRoleList("r1@")
+	require.Error(t, err)
+	_, err = ParseRoleList("''@'%'")
+	require.Error(t, err)
+
+	vars := NewSessionVars()
+	mock := NewMockGlobalAccessor4Tests()
+	mock.SessionVars = vars
+	vars.GlobalVarsAccessor = mock
+	require.Error(t, mock.SetGlobalSysVar(MandatoryRoles, "r1@"))
+	require.NoError(t, mock.SetGlobalSysVar(MandatoryRoles, "r1,r2@localhost"))
+	require.Equal(t, "r1,r2@localhost", MandatoryRolesValue.Load())
+	require.NoError(t, mock.SetGlobalSysVar(MandatoryRoles, ""))
+}
+
 func TestStmtVars(t *testing.T) {
 	vars := NewSessionVars()
 	err := SetStmtVar(vars, "bogussysvar", "1")
@@ -755,4 +778,19 @@ func TestStmtVars(t *testing.T) {
 	require.Equal(t, "[variable:1232]Incorrect argument type to variable 'max_execution_time'", err.Error())
 	err = SetStmtVar(vars, MaxExecutionTime, "100")
 	require.NoError(t, err)
+
+	require.NoError(t, vars.SetSystemVar(TiDBIndexLookupSize, "100"))
+	require.NoError(t, SetStmtVar(vars, TiDBIndexLookupSize, "200"))
+	require.NoError(t, SetStmtVar(vars, TiDBMemQuotaQuery, "1024"))
+	require.Equal(t, 200, vars.IndexLookupSize)
+	require.Equal(t, int64(1024), vars.MemQuotaQuery)
+	val, err := GetSessionOrGlobalSystemVar(vars, TiDBIndexLookupSize)
+	require.NoError(t, err)
+	require.Equal(t, "200", val)
+	vars.ClearStmtVars()
+	require.Equal(t, 100, vars.IndexLookupSize)
+	require.Equal(t, config.GetGlobalConfig().MemQuotaQuery, vars.MemQuotaQuery)
+	val, err = GetSessionOrGlobalSystemVar(vars, TiDBIndexLookupSize)
+	require.NoError(t, err)
+	require.Equal(t, "100", val)
 }
@@ -17,88 +17,111 @@ package statistics
 import (
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// analyzeJobs records the analyze jobs running on the current TiDB instance. The history of the jobs is kept in
+// mysql.analyze_jobs, so only the running jobs are kept in memory to dump their progress and to kill them.
 type analyzeJobs struct {
 	sync.Mutex
-	jobs    map[*AnalyzeJob]struct{}
-	history []*AnalyzeJob
+	jobs map[*AnalyzeJob]struct{}
 }
 
-var analyzeStatus = analyzeJobs{jobs: make(map[*AnalyzeJob]struct{}), history: make([]*AnalyzeJob, 0, numMaxHistoryJobs)}
+var analyzeStatus = analyzeJobs{jobs: make(map[*AnalyzeJob]struct{})}
 
 // AnalyzeJob is used to represent the status of one analyze job.
 type AnalyzeJob struct {
 	sync.Mutex
+	// ID is the id of the job in mysql.analyze_jobs, it is 0 if the job is not persisted.
+	ID            uint64
 	DBName        string
 	TableName     string
 	PartitionName string
 	JobInfo       string
 	RowCount      int64
+	// EstimatedRows is the estimated count of the rows to be processed, which is used to calculate the remaining time.
+	EstimatedRows int64
 	StartTime     time.Time
 	EndTime       time.Time
 	State         string
+	Phase         string
+	FailReason    string
 	updateTime    time.Time
+	// dumpedRowCount and dumpedPhase are the progress dumped into mysql.analyze_jobs last time.
+	dumpedRowCount int64
+	dumpedPhase    string
+	killed         uint32
 }
 
 const (
-	pending  = "pending"
-	running  = "running"
-	finished = "finished"
-	failed   = "failed"
+	// AnalyzePending means the analyze job is waiting to be executed.
+	AnalyzePending = "pending"
+	// AnalyzeRunning means the analyze job is being executed.
+	AnalyzeRunning = "running"
+	// AnalyzeFinished means the analyze job is finished successfully.
+	AnalyzeFinished = "finished"
+	// AnalyzeFailed means the analyze job is failed or killed.
+	AnalyzeFailed = "failed"
+)
+
+const (
+	// AnalyzePhaseScanning means the analyze job is reading and sampling the data.
+	AnalyzePhaseScanning = "scanning"
+	// AnalyzePhaseBuilding means the analyze job is building the statistics from the collected data.
+	AnalyzePhaseBuilding = "building"
+	// AnalyzePhaseSaving means the analyze job is saving the statistics into the storage.
+	AnalyzePhaseSaving = "saving"
 )
 
 // AddNewAnalyzeJob adds new analyze job.
 func AddNewAnalyzeJob(job *AnalyzeJob) {
 	analyzeStatus.Lock()
 	job.updateTime = time.Now()
-	job.State = pending
+	job.State = AnalyzePending
 	analyzeStatus.jobs[job] = struct{}{}
 	analyzeStatus.Unlock()
 }
 
-const numMaxHistoryJobs = 20
-
-// MoveToHistory moves the analyze job to history.
-func MoveToHistory(job *AnalyzeJob) {
+// RemoveAnalyzeJob removes the analyze job from the running jobs of the current instance.
+func RemoveAnalyzeJob(job *AnalyzeJob) {
 	analyzeStatus.Lock()
 	delete(analyzeStatus.jobs, job)
-	analyzeStatus.history = append(analyzeStatus.history, job)
-	numJobs := len(analyzeStatus.history)
-	if numJobs > numMaxHistoryJobs {
-		analyzeStatus.history = analyzeStatus.history[numJobs-numMaxHistoryJobs:]
-	}
-	analyzeStatus.Unlock()
-}
-
-// ClearHistoryJobs clears all history jobs.
-func ClearHistoryJobs() {
-	analyzeStatus.Lock()
-	analyzeStatus.history = analyzeStatus.history[:0]
 	analyzeStatus.Unlock()
 }
 
-// GetAllAnalyzeJobs gets all analyze jobs.
+// GetAllAnalyzeJobs gets all analyze jobs running on the current instance.
 func GetAllAnalyzeJobs() []*AnalyzeJob {
 	analyzeStatus.Lock()
-	jobs := make([]*AnalyzeJob, 0, len(analyzeStatus.jobs)+len(analyzeStatus.history))
+	jobs := make([]*AnalyzeJob, 0, len(analyzeStatus.jobs))
 	for job := range analyzeStatus.jobs {
 		jobs = append(jobs, job)
 	}
-	jobs = append(jobs, analyzeStatus.history...)
 	analyzeStatus.Unlock()
 	sort.Slice(jobs, func(i int, j int) bool { return jobs[i].getUpdateTime().Before(jobs[j].getUpdateTime()) })
 	return jobs
 }
 
+// GetAnalyzeJob gets the analyze job running on the current instance by its id.
+func GetAnalyzeJob(id uint64) *AnalyzeJob {
+	analyzeStatus.Lock()
+	defer analyzeStatus.Unlock()
+	for job := range analyzeStatus.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
 // Start marks status of the analyze job as running and update the start time.
 func (job *AnalyzeJob) Start() {
 	if job == nil {
 		return
 	}
 	job.Mutex.Lock()
-	job.State = running
+	job.State = AnalyzeRunning
+	job.Phase = AnalyzePhaseScanning
 	now := time.Now()
 	job.StartTime = now
 	job.updateTime = now
@@ -116,22 +139,57 @@ func (job *AnalyzeJob) Update(rowCount int64) {
 	job.Mutex.Unlock()
 }
 
-// Finish update the status of analyze job to finished or failed according to `meetError`.
-func (job *AnalyzeJob) Finish(meetError bool) {
+// SetPhase updates the phase of the analyze job.
+func (job *AnalyzeJob) SetPhase(phase string) {
+	if job == nil {
+		return
+	}
+	job.Mutex.Lock()
+	job.Phase = phase
+	job.updateTime = time.Now()
+	job.Mutex.Unlock()
+}
+
+// Finish update the status of analyze job to finished or failed according to `err`.
+func (job *AnalyzeJob) Finish(err error) {
 	if job == nil {
 		return
 	}
 	job.Mutex.Lock()
-	if meetError {
-		job.State = failed
+	if err != nil {
+		job.State = AnalyzeFailed
+		job.FailReason = err.Error()
 	} else {
-		job.State = finished
+		job.State = AnalyzeFinished
 	}
 	job.updateTime = time.Now()
 	job.EndTime = job.updateTime
 	job.Mutex.Unlock()
 }
 
+// Kill marks the analyze job as killed, the job checks the mark and stops itself as soon as possible.
+func (job *AnalyzeJob) Kill() {
+	atomic.StoreUint32(&job.killed, 1)
+}
+
+// Killed returns whether the analyze job is killed.
+func (job *AnalyzeJob) Killed() bool {
+	if job == nil {
+		return false
+	}
+	return atomic.LoadUint32(&job.killed) == 1
+}
+
+// ChangedProgress returns the processed row count and the phase of the job, and whether they have changed since the
+// last call. It avoids dumping the same progress into mysql.analyze_jobs repeatedly.
+func (job *AnalyzeJob) ChangedProgress() (rowCount int64, phase string, changed bool) {
+	job.Mutex.Lock()
+	defer job.Mutex.Unlock()
+	changed = job.RowCount != job.dumpedRowCount || job.Phase != job.dumpedPhase
+	job.dumpedRowCount, job.dumpedPhase = job.RowCount, job.Phase
+	return job.RowCount, job.Phase, changed
+}
+
 func (job *AnalyzeJob) getUpdateTime() time.Time {
 	if job == nil {
 		return time.Time{}
@@ -15,26 +15,51 @@
 package statistics
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
-func TestMoveToHistory(t *testing.T) {
-	ClearHistoryJobs()
-	numJobs := numMaxHistoryJobs*2 + 1
+func TestAnalyzeJobs(t *testing.T) {
+	numJobs := 5
 	jobs := make([]*AnalyzeJob, 0, numJobs)
 	for i := 0; i < numJobs; i++ {
-		job := &AnalyzeJob{}
+		job := &AnalyzeJob{ID: uint64(i + 1)}
 		AddNewAnalyzeJob(job)
 		jobs = append(jobs, job)
 	}
-	MoveToHistory(jobs[0])
 	require.Len(t, GetAllAnalyzeJobs(), numJobs)
-	for i := 1; i < numJobs; i++ {
-		MoveToHistory(jobs[i])
+	require.Equal(t, AnalyzePending, jobs[0].State)
+	require.Same(t, jobs[2], GetAnalyzeJob(3))
+	require.Nil(t, GetAnalyzeJob(uint64(numJobs+1)))
+
+	job := jobs[0]
+	job.Start()
+	require.Equal(t, AnalyzeRunning, job.State)
+	job.Update(10)
+	rowCount, phase, changed := job.ChangedProgress()
+	require.Equal(t, int64(10), rowCount)
+	require.Equal(t, AnalyzePhaseScanning, phase)
+	require.True(t, changed)
+	_, _, changed = job.ChangedProgress()
+	require.False(t, changed)
+	job.SetPhase(AnalyzePhaseBuilding)
+	_, phase, changed = job.ChangedProgress()
+	require.Equal(t, AnalyzePhaseBuilding, phase)
+	require.True(t, changed)
+
+	require.False(t, job.Killed())
+	job.Kill()
+	require.True(t, job.Killed())
+	job.Finish(errors.New("killed"))
+	require.Equal(t, AnalyzeFailed, job.State)
+	require.Equal(t, "killed", job.FailReason)
+	jobs[1].Finish(nil)
+	require.Equal(t, AnalyzeFinished, jobs[1].State)
+
+	for _, job := range jobs {
+		RemoveAnalyzeJob(job)
 	}
-	require.Len(t, GetAllAnalyzeJobs(), numMaxHistoryJobs)
-	ClearHistoryJobs()
 	require.Len(t, GetAllAnalyzeJobs(), 0)
 }
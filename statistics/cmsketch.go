@@ -657,6 +657,35 @@ func (c *TopN) TotalCount() uint64 {
 	return total
 }
 
+// minCount returns the minimum count of the TopN items. Since the TopN keeps the most frequent values, a value not in the
+// TopN can't be more frequent than it.
+func (c *TopN) minCount() (uint64, bool) {
+	if c.Num() == 0 {
+		return 0, false
+	}
+	minCnt := c.TopN[0].Count
+	for _, t := range c.TopN[1:] {
+		if t.Count < minCnt {
+			minCnt = t.Count
+		}
+	}
+	return minCnt, true
+}
+
+// maxCount returns the maximum count of the TopN items.
+func (c *TopN) maxCount() uint64 {
+	if c == nil {
+		return 0
+	}
+	maxCnt := uint64(0)
+	for _, t := range c.TopN {
+		if t.Count > maxCnt {
+			maxCnt = t.Count
+		}
+	}
+	return maxCnt
+}
+
 // Equal checks whether the two TopN are equal.
 func (c *TopN) Equal(cc *TopN) bool {
 	if c.TotalCount() == 0 && cc.TotalCount() == 0 {
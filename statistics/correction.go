@@ -0,0 +1,302 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/ranger"
+)
+
+var (
+	// CorrectionHalfLife is the duration after which the effect of a bucket correction is halved.
+	CorrectionHalfLife = 10 * time.Minute
+	// CorrectionMinErrorRate is the minimum ratio between the actual and the estimated row count, or the reverse,
+	// for a correction to be recorded.
+	CorrectionMinErrorRate = 2.0
+	// CorrectionMinRowCount is the minimum actual or estimated row count for a correction to be recorded.
+	CorrectionMinRowCount = 10.0
+)
+
+const (
+	maxCorrectionRatio = 100.0
+	minCorrectionRatio = 1 / maxCorrectionRatio
+	// maxCorrectionItems bounds the number of the bucket corrections kept in memory.
+	maxCorrectionItems = 1 << 16
+	// maxCorrectionBuckets is the max number of the buckets that one observation can correct. A range overlapping more
+	// buckets usually has a bad estimation for other reasons, e.g. the table is modified a lot.
+	maxCorrectionBuckets = 32
+	// minCorrectionLogRatio is the threshold under which a decayed correction is regarded as expired.
+	minCorrectionLogRatio = 0.01
+)
+
+type bucketCorrectionKey struct {
+	physicalID int64
+	histID     int64
+	isIndex    bool
+	// version is the LastUpdateVersion of the histogram, the corrections are discarded once the histogram is rebuilt.
+	version   uint64
+	bucketIdx int
+}
+
+type bucketCorrection struct {
+	ratio      float64
+	updateTime time.Time
+}
+
+// effectiveRatio returns the ratio decayed towards 1 by the time elapsed since it is updated.
+func (c bucketCorrection) effectiveRatio(now time.Time) float64 {
+	elapsed := now.Sub(c.updateTime)
+	if elapsed <= 0 {
+		return c.ratio
+	}
+	decay := math.Pow(0.5, float64(elapsed)/float64(CorrectionHalfLife))
+	return math.Exp(math.Log(c.ratio) * decay)
+}
+
+// EstimateCorrections records the ratios between the actual and the estimated row counts of the histogram buckets,
+// which are observed from the executed scans. Unlike the query feedback, it never modifies the stored histograms, and
+// a correction decays as time goes by, so it stops affecting the estimation if it is not observed again.
+type EstimateCorrections struct {
+	sync.RWMutex
+	items map[bucketCorrectionKey]bucketCorrection
+}
+
+// NewEstimateCorrections creates an EstimateCorrections.
+func NewEstimateCorrections() *EstimateCorrections {
+	return &EstimateCorrections{items: make(map[bucketCorrectionKey]bucketCorrection)}
+}
+
+// GlobalEstimateCorrections stores the bucket corrections of the current TiDB instance.
+var GlobalEstimateCorrections = NewEstimateCorrections()
+
+// Len returns the number of the bucket corrections.
+func (c *EstimateCorrections) Len() int {
+	c.RLock()
+	defer c.RUnlock()
+	return len(c.items)
+}
+
+// Clear removes all the bucket corrections.
+func (c *EstimateCorrections) Clear() {
+	c.Lock()
+	c.items = make(map[bucketCorrectionKey]bucketCorrection)
+	c.Unlock()
+}
+
+// record multiplies the corrections of the buckets in [lBkt, rBkt] by the ratio.
+func (c *EstimateCorrections) record(key bucketCorrectionKey, lBkt, rBkt int, ratio float64, now time.Time) {
+	if rBkt-lBkt+1 > maxCorrectionBuckets {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	for i := lBkt; i <= rBkt; i++ {
+		key.bucketIdx = i
+		newRatio := ratio
+		item, ok := c.items[key]
+		if ok {
+			newRatio *= item.effectiveRatio(now)
+		} else if len(c.items) >= maxCorrectionItems {
+			c.evictExpired(now)
+			if len(c.items) >= maxCorrectionItems {
+				return
+			}
+		}
+		newRatio = math.Max(minCorrectionRatio, math.Min(maxCorrectionRatio, newRatio))
+		if math.Abs(math.Log(newRatio)) < minCorrectionLogRatio {
+			delete(c.items, key)
+			continue
+		}
+		c.items[key] = bucketCorrection{ratio: newRatio, updateTime: now}
+	}
+}
+
+// ratio returns the geometric mean of the corrections of the buckets in [lBkt, rBkt].
+func (c *EstimateCorrections) ratio(key bucketCorrectionKey, lBkt, rBkt int, now time.Time) float64 {
+	c.RLock()
+	defer c.RUnlock()
+	if len(c.items) == 0 {
+		return 1
+	}
+	logSum := 0.0
+	for i := lBkt; i <= rBkt; i++ {
+		key.bucketIdx = i
+		if item, ok := c.items[key]; ok {
+			logSum += math.Log(item.effectiveRatio(now))
+		}
+	}
+	return math.Exp(logSum / float64(rBkt-lBkt+1))
+}
+
+func (c *EstimateCorrections) evictExpired(now time.Time) {
+	for key, item := range c.items {
+		if math.Abs(math.Log(item.effectiveRatio(now))) < minCorrectionLogRatio {
+			delete(c.items, key)
+		}
+	}
+}
+
+// bucketIdx returns the index of the bucket which the value falls in.
+func (hg *Histogram) bucketIdx(value *types.Datum) int {
+	index, _ := hg.Bounds.LowerBound(0, value)
+	// Since we store the lower and upper bound together, dividing the index by 2 will get the bucket index.
+	if index/2 >= hg.Len() {
+		return hg.Len() - 1
+	}
+	return index / 2
+}
+
+// bucketSpan returns the index range of the buckets overlapped by the column ranges.
+func (c *Column) bucketSpan(ranges []*ranger.Range) (lBkt, rBkt int, ok bool) {
+	if c.Histogram.Len() == 0 || len(ranges) == 0 {
+		return 0, 0, false
+	}
+	lBkt, rBkt = c.Histogram.Len(), -1
+	for _, rg := range ranges {
+		lowVal := *rg.LowVal[0].Clone()
+		highVal := *rg.HighVal[0].Clone()
+		if lowVal.Kind() == types.KindString {
+			lowVal.SetBytes(collate.GetCollator(lowVal.Collation()).Key(lowVal.GetString()))
+		}
+		if highVal.Kind() == types.KindString {
+			highVal.SetBytes(collate.GetCollator(highVal.Collation()).Key(highVal.GetString()))
+		}
+		if idx := c.Histogram.bucketIdx(&lowVal); idx < lBkt {
+			lBkt = idx
+		}
+		if idx := c.Histogram.bucketIdx(&highVal); idx > rBkt {
+			rBkt = idx
+		}
+	}
+	return lBkt, rBkt, true
+}
+
+// bucketSpan returns the index range of the buckets overlapped by the index ranges.
+func (idx *Index) bucketSpan(sc *stmtctx.StatementContext, ranges []*ranger.Range) (lBkt, rBkt int, ok bool) {
+	if idx.Histogram.Len() == 0 || len(ranges) == 0 {
+		return 0, 0, false
+	}
+	lBkt, rBkt = idx.Histogram.Len(), -1
+	for _, rg := range ranges {
+		lb, err := codec.EncodeKey(sc, nil, rg.LowVal...)
+		if err != nil {
+			return 0, 0, false
+		}
+		rb, err := codec.EncodeKey(sc, nil, rg.HighVal...)
+		if err != nil {
+			return 0, 0, false
+		}
+		l, r := types.NewBytesDatum(lb), types.NewBytesDatum(rb)
+		if i := idx.Histogram.bucketIdx(&l); i < lBkt {
+			lBkt = i
+		}
+		if i := idx.Histogram.bucketIdx(&r); i > rBkt {
+			rBkt = i
+		}
+	}
+	return lBkt, rBkt, true
+}
+
+// correctRowCount multiplies the estimated row count by the corrections of the buckets in [lBkt, rBkt].
+func (coll *HistColl) correctRowCount(hg *Histogram, isIndex bool, lBkt, rBkt int, rowCount float64) float64 {
+	key := bucketCorrectionKey{physicalID: coll.PhysicalID, histID: hg.ID, isIndex: isIndex, version: hg.LastUpdateVersion}
+	ratio := GlobalEstimateCorrections.ratio(key, lBkt, rBkt, time.Now())
+	if ratio == 1 {
+		return rowCount
+	}
+	return math.Min(math.Max(rowCount, 1)*ratio, float64(coll.Count))
+}
+
+func (coll *HistColl) correctColumnRowCount(c *Column, ranges []*ranger.Range, rowCount float64) float64 {
+	if !variable.EnableHistogramCorrection.Load() {
+		return rowCount
+	}
+	lBkt, rBkt, ok := c.bucketSpan(ranges)
+	if !ok {
+		return rowCount
+	}
+	return coll.correctRowCount(&c.Histogram, false, lBkt, rBkt, rowCount)
+}
+
+func (coll *HistColl) correctIndexRowCount(sc *stmtctx.StatementContext, idx *Index, ranges []*ranger.Range, rowCount float64) float64 {
+	if !variable.EnableHistogramCorrection.Load() {
+		return rowCount
+	}
+	lBkt, rBkt, ok := idx.bucketSpan(sc, ranges)
+	if !ok {
+		return rowCount
+	}
+	return coll.correctRowCount(&idx.Histogram, true, lBkt, rBkt, rowCount)
+}
+
+// observeRowCount records the corrections of the buckets in [lBkt, rBkt] if the estimation is bad.
+func (coll *HistColl) observeRowCount(hg *Histogram, isIndex bool, lBkt, rBkt int, estimated, actual float64, now time.Time) {
+	if math.Max(estimated, actual) < CorrectionMinRowCount {
+		return
+	}
+	ratio := math.Max(actual, 1) / math.Max(estimated, 1)
+	if ratio < CorrectionMinErrorRate && 1/ratio < CorrectionMinErrorRate {
+		return
+	}
+	key := bucketCorrectionKey{physicalID: coll.PhysicalID, histID: hg.ID, isIndex: isIndex, version: hg.LastUpdateVersion}
+	GlobalEstimateCorrections.record(key, lBkt, rBkt, ratio, now)
+}
+
+// ObserveIntColumnRowCount compares the actual row count of the int column ranges with the estimated one, and records
+// the corrections of the buckets overlapped by the ranges if the estimation is bad.
+func (coll *HistColl) ObserveIntColumnRowCount(sctx sessionctx.Context, colID int64, intRanges []*ranger.Range, actual int64, now time.Time) error {
+	c, ok := coll.Columns[colID]
+	if !ok || c.IsInvalid(sctx, coll.Pseudo) {
+		return nil
+	}
+	lBkt, rBkt, ok := c.bucketSpan(intRanges)
+	if !ok {
+		return nil
+	}
+	estimated, err := coll.GetRowCountByIntColumnRanges(sctx, colID, intRanges)
+	if err != nil {
+		return err
+	}
+	coll.observeRowCount(&c.Histogram, false, lBkt, rBkt, estimated, float64(actual), now)
+	return nil
+}
+
+// ObserveIndexRowCount compares the actual row count of the index ranges with the estimated one, and records
+// the corrections of the buckets overlapped by the ranges if the estimation is bad.
+func (coll *HistColl) ObserveIndexRowCount(sctx sessionctx.Context, idxID int64, indexRanges []*ranger.Range, actual int64, now time.Time) error {
+	idx, ok := coll.Indices[idxID]
+	if !ok || idx.IsInvalid(coll.Pseudo) {
+		return nil
+	}
+	lBkt, rBkt, ok := idx.bucketSpan(sctx.GetSessionVars().StmtCtx, indexRanges)
+	if !ok {
+		return nil
+	}
+	estimated, err := coll.GetRowCountByIndexRanges(sctx, idxID, indexRanges)
+	if err != nil {
+		return err
+	}
+	coll.observeRowCount(&idx.Histogram, true, lBkt, rBkt, estimated, float64(actual), now)
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// AnalyzeJobsRetention is the duration for which the finished analyze jobs are kept in mysql.analyze_jobs.
+var AnalyzeJobsRetention = 7 * 24 * time.Hour
+
+// InsertAnalyzeJob inserts the analyze job into mysql.analyze_jobs and sets the id of the job.
+func (h *Handle) InsertAnalyzeJob(job *statistics.AnalyzeJob, instance string, procID uint64) (err error) {
+	job.Lock()
+	defer job.Unlock()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := context.Background()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(ctx, "insert into mysql.analyze_jobs (table_schema, table_name, partition_name, job_info, total_rows, state, instance, process_id) values (%?, %?, %?, %?, %?, %?, %?, %?)",
+		job.DBName, job.TableName, job.PartitionName, job.JobInfo, job.EstimatedRows, statistics.AnalyzePending, instance, procID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rs, err := exec.ExecuteInternal(ctx, "select last_insert_id()")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer terror.Call(rs.Close)
+	rows, err := sqlexec.DrainRecordSet(ctx, rs, 1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	job.ID = rows[0].GetUint64(0)
+	return nil
+}
+
+// StartAnalyzeJob records the start of the analyze job in mysql.analyze_jobs.
+func (h *Handle) StartAnalyzeJob(job *statistics.AnalyzeJob) error {
+	if job == nil || job.ID == 0 {
+		return nil
+	}
+	job.Lock()
+	startTime, phase := job.StartTime, job.Phase
+	job.Unlock()
+	_, _, err := h.execRestrictedSQL(context.Background(), "update mysql.analyze_jobs set start_time = %?, state = %?, phase = %? where id = %?",
+		startTime, statistics.AnalyzeRunning, phase, job.ID)
+	return errors.Trace(err)
+}
+
+// FinishAnalyzeJob records the final state of the analyze job in mysql.analyze_jobs.
+func (h *Handle) FinishAnalyzeJob(job *statistics.AnalyzeJob) error {
+	if job == nil || job.ID == 0 {
+		return nil
+	}
+	rowCount, phase, _ := job.ChangedProgress()
+	job.Lock()
+	endTime, state, failReason := job.EndTime, job.State, job.FailReason
+	job.Unlock()
+	var reason interface{}
+	if failReason != "" {
+		reason = failReason
+	}
+	_, _, err := h.execRestrictedSQL(context.Background(), "update mysql.analyze_jobs set processed_rows = %?, phase = %?, end_time = %?, state = %?, fail_reason = %? where id = %?",
+		rowCount, phase, endTime, state, reason, job.ID)
+	return errors.Trace(err)
+}
+
+// DumpAnalyzeJobProgress dumps the progress of the analyze jobs running on the current instance into mysql.analyze_jobs.
+func (h *Handle) DumpAnalyzeJobProgress() error {
+	for _, job := range statistics.GetAllAnalyzeJobs() {
+		if job.ID == 0 {
+			continue
+		}
+		rowCount, phase, changed := job.ChangedProgress()
+		if !changed {
+			continue
+		}
+		_, _, err := h.execRestrictedSQL(context.Background(), "update mysql.analyze_jobs set processed_rows = %?, phase = %? where id = %? and state = %?",
+			rowCount, phase, job.ID, statistics.AnalyzeRunning)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// DeleteAnalyzeJobs deletes the analyze jobs which are not updated since the given time.
+func (h *Handle) DeleteAnalyzeJobs(updateTime time.Time) error {
+	_, _, err := h.execRestrictedSQL(context.Background(), "delete from mysql.analyze_jobs where update_time < %?", updateTime)
+	return errors.Trace(err)
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"math"
+	"time"
+
+	"github.com/pingcap/tidb/statistics"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+const (
+	// changeRatioWeight, analyzeIntervalWeight and sizeWeight are the weights of the factors
+	// that decide the priority of an auto analyze job.
+	changeRatioWeight     = 0.6
+	analyzeIntervalWeight = 0.3
+	sizeWeight            = 0.1
+	// newIndexWeight is the extra weight of the jobs analyzing the newly added indexes, because
+	// the optimizer can't estimate well on the indexes without any stats.
+	newIndexWeight = 2
+)
+
+// autoAnalyzeJob is a table or a partition that needs to be analyzed by auto analyze.
+type autoAnalyzeJob struct {
+	// tableID is the ID of the table, the partitions of the same table share the same tableID.
+	tableID  int64
+	sql      string
+	params   []interface{}
+	statsVer int
+	reason   string
+	// weight is the priority of the job, the job with the larger weight is analyzed first.
+	weight float64
+}
+
+// autoAnalyzeQueue is the priority queue of the auto analyze jobs, it implements heap.Interface.
+type autoAnalyzeQueue []*autoAnalyzeJob
+
+func (q autoAnalyzeQueue) Len() int { return len(q) }
+
+func (q autoAnalyzeQueue) Less(i, j int) bool { return q[i].weight > q[j].weight }
+
+func (q autoAnalyzeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *autoAnalyzeQueue) Push(x interface{}) {
+	*q = append(*q, x.(*autoAnalyzeJob))
+}
+
+func (q *autoAnalyzeQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	job := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return job
+}
+
+// lastAnalyzeTime returns the time when the table was analyzed last time, it returns
+// false if the table has never been analyzed.
+func lastAnalyzeTime(tbl *statistics.Table) (time.Time, bool) {
+	var version uint64
+	for _, col := range tbl.Columns {
+		if col.Count > 0 && col.LastUpdateVersion > version {
+			version = col.LastUpdateVersion
+		}
+	}
+	for _, idx := range tbl.Indices {
+		if idx.Histogram.Len() > 0 && idx.LastUpdateVersion > version {
+			version = idx.LastUpdateVersion
+		}
+	}
+	if version == 0 {
+		return time.Time{}, false
+	}
+	return oracle.GetTimeFromTS(version), true
+}
+
+// calcAutoAnalyzeWeight calculates the priority of analyzing the table. The stale tables come first:
+// the weight grows with the ratio of the modified rows and the time since the last analyze.
+// The small tables are slightly preferred because they are cheap to analyze.
+func calcAutoAnalyzeWeight(tbl *statistics.Table, now time.Time) float64 {
+	tblCnt := float64(tbl.Count)
+	if histCnt := tbl.GetColRowCount(); histCnt > 0 {
+		tblCnt = histCnt
+	}
+	// The table that has never been analyzed is regarded as totally changed.
+	changeRatio := 1.0
+	if tblCnt > 0 {
+		changeRatio = float64(tbl.ModifyCount) / tblCnt
+	}
+	lastAnalyze, analyzed := lastAnalyzeTime(tbl)
+	if !analyzed {
+		changeRatio = math.Max(changeRatio, 1)
+		lastAnalyze = oracle.GetTimeFromTS(tbl.Version)
+	}
+	interval := math.Max(now.Sub(lastAnalyze).Hours(), 0)
+	return changeRatioWeight*math.Log10(1+changeRatio) +
+		analyzeIntervalWeight*math.Log10(1+interval) +
+		sizeWeight/(1+math.Log10(1+math.Max(tblCnt, 0)))
+}
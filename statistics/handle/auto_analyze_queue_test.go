@@ -0,0 +1,59 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"container/heap"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/statistics"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestAutoAnalyzeQueue(t *testing.T) {
+	queue := &autoAnalyzeQueue{}
+	for i, weight := range []float64{0.3, 1.5, 0.1, 2.2, 0.7} {
+		heap.Push(queue, &autoAnalyzeJob{tableID: int64(i), weight: weight})
+	}
+	var ids []int64
+	for queue.Len() > 0 {
+		ids = append(ids, heap.Pop(queue).(*autoAnalyzeJob).tableID)
+	}
+	require.Equal(t, []int64{3, 1, 4, 0, 2}, ids)
+}
+
+func TestCalcAutoAnalyzeWeight(t *testing.T) {
+	now := time.Now()
+	newTable := func(count, modifyCount int64, lastModified time.Time) *statistics.Table {
+		return &statistics.Table{
+			HistColl: statistics.HistColl{Count: count, ModifyCount: modifyCount},
+			Version:  oracle.GoTimeToTS(lastModified),
+		}
+	}
+	// The tables modified long ago are analyzed first.
+	require.Greater(t,
+		calcAutoAnalyzeWeight(newTable(1000, 1000, now.Add(-24*time.Hour)), now),
+		calcAutoAnalyzeWeight(newTable(1000, 1000, now.Add(-time.Hour)), now))
+	// The small tables are analyzed first.
+	require.Greater(t,
+		calcAutoAnalyzeWeight(newTable(1000, 1000, now), now),
+		calcAutoAnalyzeWeight(newTable(1000000, 1000000, now), now))
+	// The tables with more modifications are analyzed first.
+	require.Greater(t,
+		calcAutoAnalyzeWeight(newTable(1000, 3000, now), now),
+		calcAutoAnalyzeWeight(newTable(1000, 1000, now), now))
+}
@@ -72,6 +72,9 @@ func (h *Handle) gcTableStats(is infoschema.InfoSchema, physicalID int64) error
 	h.mu.Unlock()
 	if !ok {
 		logutil.BgLogger().Info("remove stats in GC due to dropped table", zap.Int64("table_id", physicalID))
+		if _, _, err = h.execRestrictedSQL(ctx, "delete from mysql.stats_table_locked where table_id = %?", physicalID); err != nil {
+			return errors.Trace(err)
+		}
 		return errors.Trace(h.DeleteTableStatsFromKV([]int64{physicalID}))
 	}
 	tblInfo := tbl.Meta()
@@ -89,6 +89,8 @@ type Handle struct {
 		sync.Mutex
 		atomic.Value
 		memTracker *memory.Tracker
+		// lru decides which tables' column stats are evicted when the cache exceeds tidb_stats_cache_mem_quota.
+		lru *statsCacheLRU
 	}
 
 	pool sessionPool
@@ -108,6 +110,11 @@ type Handle struct {
 		sync.Mutex
 		data *statistics.QueryFeedbackMap
 	}
+	// colMap contains all the column stats usage information from collectors when we dump them to KV.
+	colMap struct {
+		sync.Mutex
+		data colStatsUsageMap
+	}
 
 	lease atomic2.Duration
 
@@ -170,6 +177,7 @@ func (h *Handle) Clear() {
 	h.statsCache.Lock()
 	h.statsCache.Store(statsCache{tables: make(map[int64]*statistics.Table)})
 	h.statsCache.memTracker = memory.NewTracker(memory.LabelForStatsCache, -1)
+	h.statsCache.lru = newStatsCacheLRU()
 	h.statsCache.Unlock()
 	for len(h.ddlEventCh) > 0 {
 		<-h.ddlEventCh
@@ -185,6 +193,9 @@ func (h *Handle) Clear() {
 	h.globalMap.Lock()
 	h.globalMap.data = make(tableDeltaMap)
 	h.globalMap.Unlock()
+	h.colMap.Lock()
+	h.colMap.data = make(colStatsUsageMap)
+	h.colMap.Unlock()
 	h.mu.rateMap = make(errorRateDeltaMap)
 	h.mu.Unlock()
 }
@@ -204,11 +215,13 @@ func NewHandle(ctx sessionctx.Context, lease time.Duration, pool sessionPool) (*
 	}
 	handle.lease.Store(lease)
 	handle.statsCache.memTracker = memory.NewTracker(memory.LabelForStatsCache, -1)
+	handle.statsCache.lru = newStatsCacheLRU()
 	handle.mu.ctx = ctx
 	handle.mu.rateMap = make(errorRateDeltaMap)
 	handle.statsCache.Store(statsCache{tables: make(map[int64]*statistics.Table)})
 	handle.globalMap.data = make(tableDeltaMap)
 	handle.feedback.data = statistics.NewQueryFeedbackMap()
+	handle.colMap.data = make(colStatsUsageMap)
 	err := handle.RefreshVars()
 	if err != nil {
 		return nil, err
@@ -444,6 +457,9 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 			}
 		}
 
+		// The partition-level histograms are changed by merging TopN, so we collect their NDV and correlation first.
+		maxPartitionNDV, correlation := partitionNDVAndCorrelation(allHg[i], allTopN[i])
+
 		// Merge topN. We need to merge TopN before merging the histogram.
 		// Because after merging TopN, some numbers will be left.
 		// These remaining topN numbers will be used as a separate bucket for later histogram merging.
@@ -472,14 +488,41 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 
 		// update the NDV
 		globalStatsNDV := globalStats.Fms[i].NDV()
+		// The global NDV can't be less than the NDV of any partition, the FMSketch may underestimate it
+		// when the sketches of the partitions are sampled with different masks or some of them are missing.
+		if globalStatsNDV < maxPartitionNDV {
+			globalStatsNDV = maxPartitionNDV
+		}
 		if globalStatsNDV > globalStats.Count {
 			globalStatsNDV = globalStats.Count
 		}
 		globalStats.Hg[i].NDV = globalStatsNDV
+
+		if isIndex == 0 {
+			globalStats.Hg[i].Correlation = correlation
+		}
 	}
 	return
 }
 
+// partitionNDVAndCorrelation returns the max NDV of the partitions, which is the lower bound of the global-level NDV,
+// and the global-level correlation estimated by the average of the partition-level ones weighted by the row count.
+func partitionNDVAndCorrelation(hists []*statistics.Histogram, topNs []*statistics.TopN) (maxNDV int64, correlation float64) {
+	var totCount float64
+	for j, hg := range hists {
+		if hg.NDV > maxNDV {
+			maxNDV = hg.NDV
+		}
+		cnt := hg.TotalRowCount() + float64(topNs[j].TotalCount())
+		totCount += cnt
+		correlation += hg.Correlation * cnt
+	}
+	if totCount > 0 {
+		correlation /= totCount
+	}
+	return maxNDV, correlation
+}
+
 func (h *Handle) getTableByPhysicalID(is infoschema.InfoSchema, physicalID int64) (table.Table, bool) {
 	if is.SchemaMetaVersion() != h.mu.schemaVersion {
 		h.mu.schemaVersion = is.SchemaMetaVersion()
@@ -529,6 +572,7 @@ func (h *Handle) GetPartitionStats(tblInfo *model.TableInfo, pid int64) *statist
 		h.updateStatsCache(statsCache.update([]*statistics.Table{tbl}, nil, statsCache.version))
 		return tbl
 	}
+	h.statsCache.lru.access(pid)
 	return tbl
 }
 
@@ -539,6 +583,9 @@ func (h *Handle) updateStatsCache(newCache statsCache) (updated bool) {
 	h.statsCache.Lock()
 	oldCache := h.statsCache.Load().(statsCache)
 	if oldCache.version < newCache.version || (oldCache.version == newCache.version && oldCache.minorVersion < newCache.minorVersion) {
+		if quota := variable.StatsCacheMemQuota.Load(); quota > 0 && newCache.memUsage > quota {
+			newCache.evict(h.statsCache.lru.evictionOrder(newCache.tables), quota)
+		}
 		h.statsCache.memTracker.Consume(newCache.memUsage - oldCache.memUsage)
 		h.statsCache.Store(newCache)
 		updated = true
@@ -661,6 +708,9 @@ func (h *Handle) LoadNeededHistograms() (err error) {
 		}
 		tbl = tbl.Copy()
 		tbl.Columns[c.ID] = colHist
+		// The internal SQLs above may access other tables, so mark the table as the most recently used one
+		// to avoid evicting the column stats just loaded.
+		h.statsCache.lru.access(col.TableID)
 		if h.updateStatsCache(oldCache.update([]*statistics.Table{tbl}, nil, oldCache.version)) {
 			statistics.HistogramNeededColumns.Delete(col)
 		}
@@ -693,6 +743,9 @@ func (h *Handle) FlushStats() {
 	if err := h.DumpStatsFeedbackToKV(); err != nil {
 		logutil.BgLogger().Error("[stats] dump stats feedback fail", zap.Error(err))
 	}
+	if err := h.DumpColStatsUsageToKV(); err != nil {
+		logutil.BgLogger().Error("[stats] dump column stats usage fail", zap.Error(err))
+	}
 }
 
 func (h *Handle) cmSketchAndTopNFromStorage(reader *statsReader, tblID int64, isIndex, histID int64) (_ *statistics.CMSketch, _ *statistics.TopN, err error) {
@@ -1821,6 +1874,22 @@ func (h *Handle) LoadColumnStatsUsage() (map[model.TableColumnID]colStatsUsage,
 	return colStatsMap, nil
 }
 
+// GetPredicateColumns returns IDs of predicate columns, which are the columns whose stats are used(needed) when generating query plans.
+func (h *Handle) GetPredicateColumns(tableID int64) ([]int64, error) {
+	rows, _, err := h.execRestrictedSQL(context.Background(), "SELECT column_id FROM mysql.column_stats_usage WHERE table_id = %? AND last_used_at IS NOT NULL", tableID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	columnIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		if row.IsNull(0) {
+			continue
+		}
+		columnIDs = append(columnIDs, row.GetInt64(0))
+	}
+	return columnIDs, nil
+}
+
 // CollectColumnsInExtendedStats returns IDs of the columns involved in extended stats.
 func (h *Handle) CollectColumnsInExtendedStats(tableID int64) ([]int64, error) {
 	ctx := context.Background()
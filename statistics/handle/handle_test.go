@@ -634,6 +634,62 @@ func (s *testStatsSuite) TestLoadStats(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *testStatsSuite) TestStatsCacheMemQuota(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	testKit := testkit.NewTestKit(c, s.store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t1(a int, b int, key idx(b))")
+	testKit.MustExec("create table t2(a int, b int, key idx(b))")
+	for i := 0; i < 10; i++ {
+		testKit.MustExec(fmt.Sprintf("insert into t1 values (%d,%d)", i, i))
+		testKit.MustExec(fmt.Sprintf("insert into t2 values (%d,%d)", i, i))
+	}
+	// The values are all put into the buckets with no TopN.
+	testKit.MustExec("analyze table t1, t2 with 0 topn")
+
+	h := s.do.StatsHandle()
+	is := s.do.InfoSchema()
+	tbl1, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	c.Assert(err, IsNil)
+	tbl2, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t2"))
+	c.Assert(err, IsNil)
+	tableInfo1, tableInfo2 := tbl1.Meta(), tbl2.Meta()
+	colID := tableInfo1.Columns[0].ID
+	memConsumed := h.GetMemConsumed()
+
+	testKit.MustExec("set @@global.tidb_stats_cache_mem_quota = 1")
+	defer testKit.MustExec("set @@global.tidb_stats_cache_mem_quota = 0")
+	h.Clear()
+	c.Assert(h.Update(is), IsNil)
+	// The column stats of both tables are evicted, while the index stats are kept.
+	c.Assert(h.GetMemConsumed(), Less, memConsumed)
+	c.Assert(h.GetTableStats(tableInfo2).Columns[colID].Len(), Equals, 0)
+	stat1 := h.GetTableStats(tableInfo1)
+	c.Assert(stat1.Columns[colID].Len(), Equals, 0)
+	c.Assert(stat1.Indices[tableInfo1.Indices[0].ID].Len(), Greater, 0)
+
+	// The evicted column stats are loaded again when they are needed, and t1 is not evicted because it's
+	// the most recently used table.
+	_, err = stat1.ColumnEqualRowCount(testKit.Se, types.NewIntDatum(1), colID)
+	c.Assert(err, IsNil)
+	c.Assert(h.LoadNeededHistograms(), IsNil)
+	c.Assert(h.GetTableStats(tableInfo1).Columns[colID].Len(), Greater, 0)
+
+	// Loading the column stats of t2 evicts the ones of t1, which was used less recently.
+	stat2 := h.GetTableStats(tableInfo2)
+	_, err = stat2.ColumnEqualRowCount(testKit.Se, types.NewIntDatum(1), colID)
+	c.Assert(err, IsNil)
+	c.Assert(h.LoadNeededHistograms(), IsNil)
+	c.Assert(h.GetTableStats(tableInfo2).Columns[colID].Len(), Greater, 0)
+	c.Assert(h.GetTableStats(tableInfo1).Columns[colID].Len(), Equals, 0)
+
+	testKit.MustExec("set @@global.tidb_stats_cache_mem_quota = 0")
+	h.Clear()
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(h.GetTableStats(tableInfo1).Columns[colID].Len(), Greater, 0)
+	c.Assert(h.GetTableStats(tableInfo2).Columns[colID].Len(), Greater, 0)
+}
+
 func newStoreWithBootstrap() (kv.Storage, *domain.Domain, error) {
 	store, err := mockstore.NewMockStore()
 	if err != nil {
@@ -1107,9 +1163,9 @@ partition by range (a) (
 	tk.MustQuery("select modify_count, count from mysql.stats_meta order by table_id asc").Check(
 		testkit.Rows("0 18", "0 8", "0 10")) // global row-count = sum(partition row-count)
 
-	// distinct, null_count, tot_col_size should be the sum of their values in partition-stats, and correlation should be 0
+	// distinct, null_count, tot_col_size should be the sum of their values in partition-stats, and correlation should be merged from partition-stats
 	tk.MustQuery("select distinct_count, null_count, tot_col_size, correlation=0 from mysql.stats_histograms where is_index=0 order by table_id asc").Check(
-		testkit.Rows("15 1 17 1", "6 1 7 0", "9 0 10 0"))
+		testkit.Rows("15 1 17 0", "6 1 7 0", "9 0 10 0"))
 	tk.MustQuery("select distinct_count, null_count, tot_col_size, correlation=0 from mysql.stats_histograms where is_index=1 order by table_id asc").Check(
 		testkit.Rows("15 1 0 1", "6 1 6 1", "9 0 10 1"))
 
@@ -1150,20 +1206,20 @@ func (s *testStatsSuite) TestGlobalStatsData2(c *C) {
 		"0 11")) // p1
 
 	tk.MustQuery("show stats_topn where table_name='tint' and is_index=0").Check(testkit.Rows(
-		"test tint global c 0 5 3",
-		"test tint global c 0 16 4",
-		"test tint p0 c 0 4 2",
-		"test tint p0 c 0 5 3",
-		"test tint p1 c 0 16 4",
-		"test tint p1 c 0 17 2"))
+		"test tint global c 0 5 3 0.3684210526315789 2.5263157894736845",
+		"test tint global c 0 16 4 0.3684210526315789 2.5263157894736845",
+		"test tint p0 c 0 4 2 0.625 1.875",
+		"test tint p0 c 0 5 3 0.625 1.875",
+		"test tint p1 c 0 16 4 0.5454545454545454 2.5454545454545454",
+		"test tint p1 c 0 17 2 0.5454545454545454 2.5454545454545454"))
 
 	tk.MustQuery("show stats_topn where table_name='tint' and is_index=1").Check(testkit.Rows(
-		"test tint global c 1 5 3",
-		"test tint global c 1 16 4",
-		"test tint p0 c 1 4 2",
-		"test tint p0 c 1 5 3",
-		"test tint p1 c 1 16 4",
-		"test tint p1 c 1 17 2"))
+		"test tint global c 1 5 3 0.3684210526315789 2.5263157894736845",
+		"test tint global c 1 16 4 0.3684210526315789 2.5263157894736845",
+		"test tint p0 c 1 4 2 0.625 1.875",
+		"test tint p0 c 1 5 3 0.625 1.875",
+		"test tint p1 c 1 16 4 0.5454545454545454 2.5454545454545454",
+		"test tint p1 c 1 17 2 0.5454545454545454 2.5454545454545454"))
 
 	tk.MustQuery("show stats_buckets where is_index=0").Check(testkit.Rows(
 		// db, tbl, part, col, isIdx, bucketID, count, repeat, lower, upper, ndv
@@ -1209,20 +1265,20 @@ func (s *testStatsSuite) TestGlobalStatsData2(c *C) {
 	c.Assert(rs[2][5].(string), Equals, "11") // p1.count
 
 	tk.MustQuery("show stats_topn where table_name='tdouble' and is_index=0 and column_name='c'").Check(testkit.Rows(
-		`test tdouble global c 0 5 3`,
-		`test tdouble global c 0 16 4`,
-		`test tdouble p0 c 0 4 2`,
-		`test tdouble p0 c 0 5 3`,
-		`test tdouble p1 c 0 16 4`,
-		`test tdouble p1 c 0 17 2`))
+		`test tdouble global c 0 5 3 0.3684210526315789 2.5263157894736845`,
+		`test tdouble global c 0 16 4 0.3684210526315789 2.5263157894736845`,
+		`test tdouble p0 c 0 4 2 0.625 1.875`,
+		`test tdouble p0 c 0 5 3 0.625 1.875`,
+		`test tdouble p1 c 0 16 4 0.5454545454545454 2.5454545454545454`,
+		`test tdouble p1 c 0 17 2 0.5454545454545454 2.5454545454545454`))
 
 	tk.MustQuery("show stats_topn where table_name='tdouble' and is_index=1 and column_name='c'").Check(testkit.Rows(
-		`test tdouble global c 1 5 3`,
-		`test tdouble global c 1 16 4`,
-		`test tdouble p0 c 1 4 2`,
-		`test tdouble p0 c 1 5 3`,
-		`test tdouble p1 c 1 16 4`,
-		`test tdouble p1 c 1 17 2`))
+		`test tdouble global c 1 5 3 0.3684210526315789 2.5263157894736845`,
+		`test tdouble global c 1 16 4 0.3684210526315789 2.5263157894736845`,
+		`test tdouble p0 c 1 4 2 0.625 1.875`,
+		`test tdouble p0 c 1 5 3 0.625 1.875`,
+		`test tdouble p1 c 1 16 4 0.5454545454545454 2.5454545454545454`,
+		`test tdouble p1 c 1 17 2 0.5454545454545454 2.5454545454545454`))
 
 	tk.MustQuery("show stats_buckets where table_name='tdouble' and is_index=0 and column_name='c'").Check(testkit.Rows(
 		// db, tbl, part, col, isIdx, bucketID, count, repeat, lower, upper, ndv
@@ -1274,20 +1330,20 @@ func (s *testStatsSuite) TestGlobalStatsData2(c *C) {
 	c.Assert(rs[2][5].(string), Equals, "11") // p1.count
 
 	tk.MustQuery("show stats_topn where table_name='tdecimal' and is_index=0 and column_name='c'").Check(testkit.Rows(
-		`test tdecimal global c 0 5.00 3`,
-		`test tdecimal global c 0 16.00 4`,
-		`test tdecimal p0 c 0 4.00 2`,
-		`test tdecimal p0 c 0 5.00 3`,
-		`test tdecimal p1 c 0 16.00 4`,
-		`test tdecimal p1 c 0 17.00 2`))
+		`test tdecimal global c 0 5.00 3 0.3684210526315789 2.5263157894736845`,
+		`test tdecimal global c 0 16.00 4 0.3684210526315789 2.5263157894736845`,
+		`test tdecimal p0 c 0 4.00 2 0.625 1.875`,
+		`test tdecimal p0 c 0 5.00 3 0.625 1.875`,
+		`test tdecimal p1 c 0 16.00 4 0.5454545454545454 2.5454545454545454`,
+		`test tdecimal p1 c 0 17.00 2 0.5454545454545454 2.5454545454545454`))
 
 	tk.MustQuery("show stats_topn where table_name='tdecimal' and is_index=1 and column_name='c'").Check(testkit.Rows(
-		`test tdecimal global c 1 5.00 3`,
-		`test tdecimal global c 1 16.00 4`,
-		`test tdecimal p0 c 1 4.00 2`,
-		`test tdecimal p0 c 1 5.00 3`,
-		`test tdecimal p1 c 1 16.00 4`,
-		`test tdecimal p1 c 1 17.00 2`))
+		`test tdecimal global c 1 5.00 3 0.3684210526315789 2.5263157894736845`,
+		`test tdecimal global c 1 16.00 4 0.3684210526315789 2.5263157894736845`,
+		`test tdecimal p0 c 1 4.00 2 0.625 1.875`,
+		`test tdecimal p0 c 1 5.00 3 0.625 1.875`,
+		`test tdecimal p1 c 1 16.00 4 0.5454545454545454 2.5454545454545454`,
+		`test tdecimal p1 c 1 17.00 2 0.5454545454545454 2.5454545454545454`))
 
 	tk.MustQuery("show stats_buckets where table_name='tdecimal' and is_index=0 and column_name='c'").Check(testkit.Rows(
 		// db, tbl, part, col, isIdx, bucketID, count, repeat, lower, upper, ndv
@@ -1339,20 +1395,20 @@ func (s *testStatsSuite) TestGlobalStatsData2(c *C) {
 	c.Assert(rs[2][5].(string), Equals, "11") // p1.count
 
 	tk.MustQuery("show stats_topn where table_name='tdatetime' and is_index=0 and column_name='c'").Check(testkit.Rows(
-		`test tdatetime global c 0 2000-01-05 00:00:00 3`,
-		`test tdatetime global c 0 2000-01-16 00:00:00 4`,
-		`test tdatetime p0 c 0 2000-01-04 00:00:00 2`,
-		`test tdatetime p0 c 0 2000-01-05 00:00:00 3`,
-		`test tdatetime p1 c 0 2000-01-16 00:00:00 4`,
-		`test tdatetime p1 c 0 2000-01-17 00:00:00 2`))
+		`test tdatetime global c 0 2000-01-05 00:00:00 3 0.3684210526315789 2.5263157894736845`,
+		`test tdatetime global c 0 2000-01-16 00:00:00 4 0.3684210526315789 2.5263157894736845`,
+		`test tdatetime p0 c 0 2000-01-04 00:00:00 2 0.625 1.875`,
+		`test tdatetime p0 c 0 2000-01-05 00:00:00 3 0.625 1.875`,
+		`test tdatetime p1 c 0 2000-01-16 00:00:00 4 0.5454545454545454 2.5454545454545454`,
+		`test tdatetime p1 c 0 2000-01-17 00:00:00 2 0.5454545454545454 2.5454545454545454`))
 
 	tk.MustQuery("show stats_topn where table_name='tdatetime' and is_index=1 and column_name='c'").Check(testkit.Rows(
-		`test tdatetime global c 1 2000-01-05 00:00:00 3`,
-		`test tdatetime global c 1 2000-01-16 00:00:00 4`,
-		`test tdatetime p0 c 1 2000-01-04 00:00:00 2`,
-		`test tdatetime p0 c 1 2000-01-05 00:00:00 3`,
-		`test tdatetime p1 c 1 2000-01-16 00:00:00 4`,
-		`test tdatetime p1 c 1 2000-01-17 00:00:00 2`))
+		`test tdatetime global c 1 2000-01-05 00:00:00 3 0.3684210526315789 2.5263157894736845`,
+		`test tdatetime global c 1 2000-01-16 00:00:00 4 0.3684210526315789 2.5263157894736845`,
+		`test tdatetime p0 c 1 2000-01-04 00:00:00 2 0.625 1.875`,
+		`test tdatetime p0 c 1 2000-01-05 00:00:00 3 0.625 1.875`,
+		`test tdatetime p1 c 1 2000-01-16 00:00:00 4 0.5454545454545454 2.5454545454545454`,
+		`test tdatetime p1 c 1 2000-01-17 00:00:00 2 0.5454545454545454 2.5454545454545454`))
 
 	tk.MustQuery("show stats_buckets where table_name='tdatetime' and is_index=0 and column_name='c'").Check(testkit.Rows(
 		// db, tbl, part, col, isIdx, bucketID, count, repeat, lower, upper, ndv
@@ -1404,20 +1460,20 @@ func (s *testStatsSuite) TestGlobalStatsData2(c *C) {
 	c.Assert(rs[2][5].(string), Equals, "11") // p1.count
 
 	tk.MustQuery("show stats_topn where table_name='tstring' and is_index=0 and column_name='c'").Check(testkit.Rows(
-		`test tstring global c 0 a5 3`,
-		`test tstring global c 0 b16 4`,
-		`test tstring p0 c 0 a4 2`,
-		`test tstring p0 c 0 a5 3`,
-		`test tstring p1 c 0 b16 4`,
-		`test tstring p1 c 0 b17 2`))
+		`test tstring global c 0 a5 3 0.3684210526315789 2.5263157894736845`,
+		`test tstring global c 0 b16 4 0.3684210526315789 2.5263157894736845`,
+		`test tstring p0 c 0 a4 2 0.625 1.875`,
+		`test tstring p0 c 0 a5 3 0.625 1.875`,
+		`test tstring p1 c 0 b16 4 0.5454545454545454 2.5454545454545454`,
+		`test tstring p1 c 0 b17 2 0.5454545454545454 2.5454545454545454`))
 
 	tk.MustQuery("show stats_topn where table_name='tstring' and is_index=1 and column_name='c'").Check(testkit.Rows(
-		`test tstring global c 1 a5 3`,
-		`test tstring global c 1 b16 4`,
-		`test tstring p0 c 1 a4 2`,
-		`test tstring p0 c 1 a5 3`,
-		`test tstring p1 c 1 b16 4`,
-		`test tstring p1 c 1 b17 2`))
+		`test tstring global c 1 a5 3 0.3684210526315789 2.5263157894736845`,
+		`test tstring global c 1 b16 4 0.3684210526315789 2.5263157894736845`,
+		`test tstring p0 c 1 a4 2 0.625 1.875`,
+		`test tstring p0 c 1 a5 3 0.625 1.875`,
+		`test tstring p1 c 1 b16 4 0.5454545454545454 2.5454545454545454`,
+		`test tstring p1 c 1 b17 2 0.5454545454545454 2.5454545454545454`))
 
 	tk.MustQuery("show stats_buckets where table_name='tstring' and is_index=0 and column_name='c'").Check(testkit.Rows(
 		// db, tbl, part, col, isIdx, bucketID, count, repeat, lower, upper, ndv
@@ -1475,12 +1531,12 @@ func (s *testStatsSuite) TestGlobalStatsData3(c *C) {
 	c.Assert(rs[2][5].(string), Equals, "8")
 
 	tk.MustQuery("show stats_topn where table_name='tintint' and is_index=1").Check(testkit.Rows(
-		"test tintint global a 1 (3, 1) 3",
-		"test tintint global a 1 (13, 2) 3",
-		"test tintint p0 a 1 (2, 3) 2",
-		"test tintint p0 a 1 (3, 1) 3",
-		"test tintint p1 a 1 (13, 1) 2",
-		"test tintint p1 a 1 (13, 2) 3"))
+		"test tintint global a 1 (3, 1) 3 0.35294117647058826 1.9411764705882353",
+		"test tintint global a 1 (13, 2) 3 0.35294117647058826 1.9411764705882353",
+		"test tintint p0 a 1 (2, 3) 2 0.5555555555555556 2",
+		"test tintint p0 a 1 (3, 1) 3 0.5555555555555556 2",
+		"test tintint p1 a 1 (13, 1) 2 0.625 1.875",
+		"test tintint p1 a 1 (13, 2) 3 0.625 1.875"))
 
 	tk.MustQuery("show stats_buckets where table_name='tintint' and is_index=1").Check(testkit.Rows(
 		"test tintint global a 1 0 6 2 (1, 1) (2, 3) 0",    // (2, 3) is popped into it
@@ -1509,12 +1565,12 @@ func (s *testStatsSuite) TestGlobalStatsData3(c *C) {
 	c.Assert(rs[2][5].(string), Equals, "8")
 
 	tk.MustQuery("show stats_topn where table_name='tintstr' and is_index=1").Check(testkit.Rows(
-		"test tintstr global a 1 (3, 1) 3",
-		"test tintstr global a 1 (13, 2) 3",
-		"test tintstr p0 a 1 (2, 3) 2",
-		"test tintstr p0 a 1 (3, 1) 3",
-		"test tintstr p1 a 1 (13, 1) 2",
-		"test tintstr p1 a 1 (13, 2) 3"))
+		"test tintstr global a 1 (3, 1) 3 0.35294117647058826 1.9411764705882353",
+		"test tintstr global a 1 (13, 2) 3 0.35294117647058826 1.9411764705882353",
+		"test tintstr p0 a 1 (2, 3) 2 0.5555555555555556 2",
+		"test tintstr p0 a 1 (3, 1) 3 0.5555555555555556 2",
+		"test tintstr p1 a 1 (13, 1) 2 0.625 1.875",
+		"test tintstr p1 a 1 (13, 2) 3 0.625 1.875"))
 
 	tk.MustQuery("show stats_buckets where table_name='tintstr' and is_index=1").Check(testkit.Rows(
 		"test tintstr global a 1 0 6 2 (1, 1) (2, 3) 0",    // (2, 3) is popped into it
@@ -1543,12 +1599,12 @@ func (s *testStatsSuite) TestGlobalStatsData3(c *C) {
 	c.Assert(rs[2][5].(string), Equals, "8")
 
 	tk.MustQuery("show stats_topn where table_name='tintdouble' and is_index=1").Check(testkit.Rows(
-		"test tintdouble global a 1 (3, 1) 3",
-		"test tintdouble global a 1 (13, 2) 3",
-		"test tintdouble p0 a 1 (2, 3) 2",
-		"test tintdouble p0 a 1 (3, 1) 3",
-		"test tintdouble p1 a 1 (13, 1) 2",
-		"test tintdouble p1 a 1 (13, 2) 3"))
+		"test tintdouble global a 1 (3, 1) 3 0.35294117647058826 1.9411764705882353",
+		"test tintdouble global a 1 (13, 2) 3 0.35294117647058826 1.9411764705882353",
+		"test tintdouble p0 a 1 (2, 3) 2 0.5555555555555556 2",
+		"test tintdouble p0 a 1 (3, 1) 3 0.5555555555555556 2",
+		"test tintdouble p1 a 1 (13, 1) 2 0.625 1.875",
+		"test tintdouble p1 a 1 (13, 2) 3 0.625 1.875"))
 
 	tk.MustQuery("show stats_buckets where table_name='tintdouble' and is_index=1").Check(testkit.Rows(
 		"test tintdouble global a 1 0 6 2 (1, 1) (2, 3) 0",    // (2, 3) is popped into it
@@ -1577,12 +1633,12 @@ func (s *testStatsSuite) TestGlobalStatsData3(c *C) {
 	c.Assert(rs[2][5].(string), Equals, "8")
 
 	tk.MustQuery("show stats_topn where table_name='tdoubledecimal' and is_index=1").Check(testkit.Rows(
-		"test tdoubledecimal global a 1 (3, 1.00) 3",
-		"test tdoubledecimal global a 1 (13, 2.00) 3",
-		"test tdoubledecimal p0 a 1 (2, 3.00) 2",
-		"test tdoubledecimal p0 a 1 (3, 1.00) 3",
-		"test tdoubledecimal p1 a 1 (13, 1.00) 2",
-		"test tdoubledecimal p1 a 1 (13, 2.00) 3"))
+		"test tdoubledecimal global a 1 (3, 1.00) 3 0.35294117647058826 1.9411764705882353",
+		"test tdoubledecimal global a 1 (13, 2.00) 3 0.35294117647058826 1.9411764705882353",
+		"test tdoubledecimal p0 a 1 (2, 3.00) 2 0.5555555555555556 2",
+		"test tdoubledecimal p0 a 1 (3, 1.00) 3 0.5555555555555556 2",
+		"test tdoubledecimal p1 a 1 (13, 1.00) 2 0.625 1.875",
+		"test tdoubledecimal p1 a 1 (13, 2.00) 3 0.625 1.875"))
 
 	tk.MustQuery("show stats_buckets where table_name='tdoubledecimal' and is_index=1").Check(testkit.Rows(
 		"test tdoubledecimal global a 1 0 6 2 (1, 1.00) (2, 3.00) 0",    // (2, 3) is popped into it
@@ -1611,12 +1667,12 @@ func (s *testStatsSuite) TestGlobalStatsData3(c *C) {
 	c.Assert(rs[2][5].(string), Equals, "8")
 
 	tk.MustQuery("show stats_topn where table_name='tstrdt' and is_index=1").Check(testkit.Rows(
-		"test tstrdt global a 1 (3, 2000-01-01 00:00:00) 3",
-		"test tstrdt global a 1 (13, 2000-01-02 00:00:00) 3",
-		"test tstrdt p0 a 1 (2, 2000-01-03 00:00:00) 2",
-		"test tstrdt p0 a 1 (3, 2000-01-01 00:00:00) 3",
-		"test tstrdt p1 a 1 (13, 2000-01-01 00:00:00) 2",
-		"test tstrdt p1 a 1 (13, 2000-01-02 00:00:00) 3"))
+		"test tstrdt global a 1 (3, 2000-01-01 00:00:00) 3 0.35294117647058826 1.9411764705882353",
+		"test tstrdt global a 1 (13, 2000-01-02 00:00:00) 3 0.35294117647058826 1.9411764705882353",
+		"test tstrdt p0 a 1 (2, 2000-01-03 00:00:00) 2 0.5555555555555556 2",
+		"test tstrdt p0 a 1 (3, 2000-01-01 00:00:00) 3 0.5555555555555556 2",
+		"test tstrdt p1 a 1 (13, 2000-01-01 00:00:00) 2 0.625 1.875",
+		"test tstrdt p1 a 1 (13, 2000-01-02 00:00:00) 3 0.625 1.875"))
 
 	tk.MustQuery("show stats_buckets where table_name='tstrdt' and is_index=1").Check(testkit.Rows(
 		"test tstrdt global a 1 0 6 2 (1, 2000-01-01 00:00:00) (2, 2000-01-03 00:00:00) 0",    // (2, 3) is popped into it
@@ -1699,6 +1755,32 @@ partition by range (a) (
 	c.Assert(globalStats.Count, Equals, int64(7))
 }
 
+func (s *testStatsSuite) TestGlobalStatsNDVAndCorrelation(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode='dynamic'")
+	tk.MustExec("set @@session.tidb_analyze_version=2")
+	tk.MustExec("create table t (a int, b int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	tk.MustExec("insert into t values (1, 4), (2, 3), (3, 2), (4, 1), (11, 4), (12, 3), (13, 2), (14, 1)")
+	tk.MustExec("analyze table t with 0 topn")
+
+	h := s.do.StatsHandle()
+	is := s.do.InfoSchema()
+	c.Assert(h.Update(is), IsNil)
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo := tbl.Meta()
+	globalStats := h.GetTableStats(tableInfo)
+	colA := globalStats.Columns[tableInfo.Columns[0].ID]
+	colB := globalStats.Columns[tableInfo.Columns[1].ID]
+	c.Assert(colA.Histogram.NDV, Equals, int64(8))
+	c.Assert(colB.Histogram.NDV, Equals, int64(4))
+	// The global-level correlation is merged from the partition-level ones.
+	c.Assert(colA.Histogram.Correlation, Equals, float64(1))
+	c.Assert(colB.Histogram.Correlation, Equals, float64(-1))
+}
+
 func (s *testSerialStatsSuite) TestDDLPartition4GlobalStats(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	tk := testkit.NewTestKit(c, s.store)
@@ -1778,24 +1860,24 @@ func (s *testStatsSuite) TestMergeGlobalTopN(c *C) {
 	tk.MustExec("analyze table t with 2 topn;")
 	// The top2 values in partition p0 are 1(count = 4) and 3(count = 3).
 	tk.MustQuery("show stats_topn where table_name = 't' and column_name = 'b' and partition_name = 'p0';").Check(testkit.Rows(
-		("test t p0 b 0 1 4"),
-		("test t p0 b 0 3 3"),
-		("test t p0 b 1 1 4"),
-		("test t p0 b 1 3 3")))
+		("test t p0 b 0 1 4 0.7777777777777778 1.3333333333333333"),
+		("test t p0 b 0 3 3 0.7777777777777778 1.3333333333333333"),
+		("test t p0 b 1 1 4 0.7777777777777778 1.3333333333333333"),
+		("test t p0 b 1 3 3 0.7777777777777778 1.3333333333333333")))
 	// The top2 values in partition p1 are 11(count = 3) and 12(count = 3).
 	tk.MustQuery("show stats_topn where table_name = 't' and column_name = 'b' and partition_name = 'p1';").Check(testkit.Rows(
-		("test t p1 b 0 11 3"),
-		("test t p1 b 0 12 3"),
-		("test t p1 b 1 11 3"),
-		("test t p1 b 1 12 3")))
+		("test t p1 b 0 11 3 0.75 1.125"),
+		("test t p1 b 0 12 3 0.75 1.125"),
+		("test t p1 b 1 11 3 0.75 1.125"),
+		("test t p1 b 1 12 3 0.75 1.125")))
 	// The top2 values in global are 1(count = 4) and 3(count = 5).
 	// Notice: The value 3 does not appear in the topN structure of partition one.
 	// But we can still use the histogram to calculate its accurate value.
 	tk.MustQuery("show stats_topn where table_name = 't' and column_name = 'b' and partition_name = 'global';").Check(testkit.Rows(
-		("test t global b 0 1 4"),
-		("test t global b 0 3 5"),
-		("test t global b 1 1 4"),
-		("test t global b 1 3 5")))
+		("test t global b 0 1 4 0.47368421052631576 1.3157894736842106"),
+		("test t global b 0 3 5 0.47368421052631576 1.3157894736842106"),
+		("test t global b 1 1 4 0.5294117647058824 1.4705882352941178"),
+		("test t global b 1 3 5 0.5294117647058824 1.4705882352941178")))
 }
 
 func (s *testStatsSuite) TestExtendedStatsDefaultSwitch(c *C) {
@@ -2245,8 +2327,10 @@ func (s *statsSerialSuite) TestIndexUsageInformation(c *C) {
 	tk.MustQuery("select a from t_idx where a=1")
 	err = do.StatsHandle().DumpIndexUsageToKV()
 	c.Assert(err, IsNil)
+	// The insert writes idx_b, which has no reads.
 	tk.MustQuery(querySQL).Check(testkit.Rows(
 		"test t_idx idx_a 3 2",
+		"test t_idx idx_b 0 0",
 	))
 	tk.MustQuery("select b from t_idx where b=0")
 	tk.MustQuery("select b from t_idx where b=0")
@@ -2284,6 +2368,41 @@ func (s *statsSerialSuite) TestGCIndexUsageInformation(c *C) {
 	tk.MustQuery(querySQL).Check(testkit.Rows("0"))
 }
 
+func (s *statsSerialSuite) TestIndexUsageReadsAndWrites(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	session.SetIndexUsageSyncLease(1)
+	defer session.SetIndexUsageSyncLease(0)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t_idx(a int, b int, c int, index idx_a(a), index idx_b(b), index idx_c(c))")
+	tk.MustExec("insert into t_idx values(1, 1, 1), (2, 2, 2), (3, 3, 3)")
+	tk.MustExec("update t_idx set b = 4 where a = 1")
+	tk.MustExec("delete from t_idx where a = 3")
+	// The writes of the rolled back transactions are not collected.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t_idx values(5, 5, 5)")
+	tk.MustExec("rollback")
+	tk.MustQuery("select b from t_idx use index(idx_b) where b > 1").Check(testkit.Rows("2", "4"))
+	tk.MustQuery("select * from t_idx use index(idx_a) where a < 3").Check(testkit.Rows("1 4 1", "2 2 2"))
+
+	err := s.do.StatsHandle().DumpIndexUsageToKV()
+	c.Assert(err, IsNil)
+	// The update and delete read idx_a, and the update rewrites the row of idx_b.
+	tk.MustQuery("select index_name, query_count, rows_selected, rows_written, last_used_at is null from information_schema.tidb_index_usage where table_name = 't_idx'").Check(testkit.Rows(
+		"idx_a 3 4 4 0",
+		"idx_b 1 2 5 0",
+		"idx_c 0 0 4 1",
+	))
+	tk.MustExec("insert into t_idx values(6, 6, 6)")
+	err = s.do.StatsHandle().DumpIndexUsageToKV()
+	c.Assert(err, IsNil)
+	tk.MustQuery("select index_name, query_count, rows_written, last_used_at is null from information_schema.tidb_index_usage where table_name = 't_idx'").Check(testkit.Rows(
+		"idx_a 3 5 0",
+		"idx_b 1 6 0",
+		"idx_c 0 5 1",
+	))
+}
+
 func (s *statsSerialSuite) TestFeedbackWithGlobalStats(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	testKit := testkit.NewTestKit(c, s.store)
@@ -3134,10 +3253,10 @@ func (s *testStatsSuite) TestAnalyzeColumnsWithPrimaryKey(c *C) {
 		testkit.Rows("0 9"))
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(
 		// db, tbl, part, col, is_idx, value, count
-		testkit.Rows("test t  a 0 1 2",
-			"test t  a 0 2 2",
-			"test t  c 0 1 1",
-			"test t  c 0 2 1"))
+		testkit.Rows("test t  a 0 1 2 0.5 1.5",
+			"test t  a 0 2 2 0.5 1.5",
+			"test t  c 0 1 1 0.2222222222222222 1",
+			"test t  c 0 2 1 0.2222222222222222 1"))
 	tk.MustQuery(fmt.Sprintf("select is_index, hist_id, distinct_count, null_count, tot_col_size, stats_ver, truncate(correlation,2) from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
 		testkit.Rows("0 1 6 1 8 2 1",
 			"0 2 0 0 8 0 0", // column b is not analyzed
@@ -3181,14 +3300,14 @@ func (s *testStatsSuite) TestAnalyzeColumnsWithIndex(c *C) {
 		testkit.Rows("0 9"))
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(
 		// db, tbl, part, col, is_idx, value, count
-		testkit.Rows("test t  b 0 1 3",
-			"test t  b 0 4 3",
-			"test t  c 0 6 4",
-			"test t  c 0 7 2",
-			"test t  d 0 1 3",
-			"test t  d 0 5 3",
-			"test t  idx_b_d 1 (1, 1) 3",
-			"test t  idx_b_d 1 (4, 5) 2"))
+		testkit.Rows("test t  b 0 1 3 0.6666666666666666 1.6666666666666665",
+			"test t  b 0 4 3 0.6666666666666666 1.6666666666666665",
+			"test t  c 0 6 4 0.75 2",
+			"test t  c 0 7 2 0.75 2",
+			"test t  d 0 1 3 0.6666666666666666 1.6666666666666665",
+			"test t  d 0 5 3 0.6666666666666666 1.6666666666666665",
+			"test t  idx_b_d 1 (1, 1) 3 0.5555555555555556 2",
+			"test t  idx_b_d 1 (4, 5) 2 0.5555555555555556 2"))
 	tk.MustQuery(fmt.Sprintf("select is_index, hist_id, distinct_count, null_count, tot_col_size, stats_ver, truncate(correlation,2) from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
 		testkit.Rows("0 1 0 0 9 0 0", // column a is not analyzed
 			"0 2 5 0 9 2 1",
@@ -3236,14 +3355,14 @@ func (s *testStatsSuite) TestAnalyzeColumnsWithClusteredIndex(c *C) {
 		testkit.Rows("0 9"))
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(
 		// db, tbl, part, col, is_idx, value, count
-		testkit.Rows("test t  PRIMARY 1 (1, 1) 1",
-			"test t  PRIMARY 1 (2, 2) 1",
-			"test t  b 0 1 1",
-			"test t  b 0 2 1",
-			"test t  c 0 6 4",
-			"test t  c 0 7 2",
-			"test t  d 0 1 1",
-			"test t  d 0 2 1"))
+		testkit.Rows("test t  PRIMARY 1 (1, 1) 1 0.2222222222222222 1",
+			"test t  PRIMARY 1 (2, 2) 1 0.2222222222222222 1",
+			"test t  b 0 1 1 0.2222222222222222 1",
+			"test t  b 0 2 1 0.2222222222222222 1",
+			"test t  c 0 6 4 0.75 2",
+			"test t  c 0 7 2 0.75 2",
+			"test t  d 0 1 1 0.2222222222222222 1",
+			"test t  d 0 2 1 0.2222222222222222 1"))
 	tk.MustQuery(fmt.Sprintf("select is_index, hist_id, distinct_count, null_count, tot_col_size, stats_ver, truncate(correlation,2) from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
 		testkit.Rows("0 1 0 0 9 0 0", // column a is not analyzed
 			"0 2 9 0 9 2 1",
@@ -3272,6 +3391,8 @@ func (s *testStatsSuite) TestAnalyzeColumnsError(c *C) {
 	tk.MustExec("set @@tidb_analyze_version = 1")
 	err := tk.ExecToErr("analyze table t columns a")
 	c.Assert(err.Error(), Equals, "Only the analyze version 2 supports analyzing the specified columns")
+	err = tk.ExecToErr("analyze table t predicate columns")
+	c.Assert(err.Error(), Equals, "Only the analyze version 2 supports analyzing predicate columns")
 
 	// invalid column
 	tk.MustExec("set @@tidb_analyze_version = 2")
@@ -3322,27 +3443,27 @@ func (s *testStatsSuite) TestAnalyzeColumnsWithDynamicPartitionTable(c *C) {
 
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't' and is_index = 0").Sort().Check(
 		// db, tbl, part, col, is_idx, value, count
-		testkit.Rows("test t global a 0 16 4",
-			"test t global a 0 5 3",
-			"test t global c 0 1 3",
-			"test t global c 0 14 3",
-			"test t p0 a 0 4 2",
-			"test t p0 a 0 5 3",
-			"test t p0 c 0 1 3",
-			"test t p0 c 0 2 2",
-			"test t p1 a 0 16 4",
-			"test t p1 a 0 17 2",
-			"test t p1 c 0 13 2",
-			"test t p1 c 0 14 3"))
+		testkit.Rows("test t global a 0 16 4 0.3684210526315789 2.5263157894736845",
+			"test t global a 0 5 3 0.3684210526315789 2.5263157894736845",
+			"test t global c 0 1 3 0.3 2.1",
+			"test t global c 0 14 3 0.3 2.1",
+			"test t p0 a 0 4 2 0.625 1.875",
+			"test t p0 a 0 5 3 0.625 1.875",
+			"test t p0 c 0 1 3 0.5555555555555556 2",
+			"test t p0 c 0 2 2 0.5555555555555556 2",
+			"test t p1 a 0 16 4 0.5454545454545454 2.5454545454545454",
+			"test t p1 a 0 17 2 0.5454545454545454 2.5454545454545454",
+			"test t p1 c 0 13 2 0.45454545454545453 2.1818181818181817",
+			"test t p1 c 0 14 3 0.45454545454545453 2.1818181818181817"))
 
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't' and is_index = 1").Sort().Check(
 		// db, tbl, part, col, is_idx, value, count
-		testkit.Rows("test t global idx 1 1 3",
-			"test t global idx 1 14 3",
-			"test t p0 idx 1 1 3",
-			"test t p0 idx 1 2 2",
-			"test t p1 idx 1 13 2",
-			"test t p1 idx 1 14 3"))
+		testkit.Rows("test t global idx 1 1 3 0.3 2.1",
+			"test t global idx 1 14 3 0.3 2.1",
+			"test t p0 idx 1 1 3 0.5555555555555556 2",
+			"test t p0 idx 1 2 2 0.5555555555555556 2",
+			"test t p1 idx 1 13 2 0.45454545454545453 2.1818181818181817",
+			"test t p1 idx 1 14 3 0.45454545454545453 2.1818181818181817"))
 
 	tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't' and is_index = 0").Sort().Check(
 		// db, tbl, part, col, is_index, bucket_id, count, repeats, lower, upper, ndv
@@ -3369,8 +3490,8 @@ func (s *testStatsSuite) TestAnalyzeColumnsWithDynamicPartitionTable(c *C) {
 			"test t p1 idx 1 1 6 1 11 12 0"))
 
 	tk.MustQuery("select table_id, is_index, hist_id, distinct_count, null_count, tot_col_size, stats_ver, truncate(correlation,2) from mysql.stats_histograms order by table_id, is_index, hist_id asc").Check(
-		testkit.Rows(fmt.Sprintf("%d 0 1 12 1 19 2 0", tblID), // global, a
-			fmt.Sprintf("%d 0 3 14 0 20 2 0", tblID), // global, c
+		testkit.Rows(fmt.Sprintf("%d 0 1 12 1 19 2 1", tblID), // global, a
+			fmt.Sprintf("%d 0 3 14 0 20 2 1", tblID), // global, c
 			fmt.Sprintf("%d 1 1 14 0 0 2 0", tblID),  // global, idx
 			fmt.Sprintf("%d 0 1 5 1 8 2 1", p0ID),    // p0, a
 			fmt.Sprintf("%d 0 2 0 0 8 0 0", p0ID),    // p0, b, not analyzed
@@ -3421,21 +3542,21 @@ func (s *testStatsSuite) TestAnalyzeColumnsWithStaticPartitionTable(c *C) {
 
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't' and is_index = 0").Sort().Check(
 		// db, tbl, part, col, is_idx, value, count
-		testkit.Rows("test t p0 a 0 4 2",
-			"test t p0 a 0 5 3",
-			"test t p0 c 0 1 3",
-			"test t p0 c 0 2 2",
-			"test t p1 a 0 16 4",
-			"test t p1 a 0 17 2",
-			"test t p1 c 0 13 2",
-			"test t p1 c 0 14 3"))
+		testkit.Rows("test t p0 a 0 4 2 0.625 1.875",
+			"test t p0 a 0 5 3 0.625 1.875",
+			"test t p0 c 0 1 3 0.5555555555555556 2",
+			"test t p0 c 0 2 2 0.5555555555555556 2",
+			"test t p1 a 0 16 4 0.5454545454545454 2.5454545454545454",
+			"test t p1 a 0 17 2 0.5454545454545454 2.5454545454545454",
+			"test t p1 c 0 13 2 0.45454545454545453 2.1818181818181817",
+			"test t p1 c 0 14 3 0.45454545454545453 2.1818181818181817"))
 
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't' and is_index = 1").Sort().Check(
 		// db, tbl, part, col, is_idx, value, count
-		testkit.Rows("test t p0 idx 1 1 3",
-			"test t p0 idx 1 2 2",
-			"test t p1 idx 1 13 2",
-			"test t p1 idx 1 14 3"))
+		testkit.Rows("test t p0 idx 1 1 3 0.5555555555555556 2",
+			"test t p0 idx 1 2 2 0.5555555555555556 2",
+			"test t p1 idx 1 13 2 0.45454545454545453 2.1818181818181817",
+			"test t p1 idx 1 14 3 0.45454545454545453 2.1818181818181817"))
 
 	tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't' and is_index = 0").Sort().Check(
 		// db, tbl, part, col, is_index, bucket_id, count, repeats, lower, upper, ndv
@@ -3498,10 +3619,10 @@ func (s *testStatsSuite) TestAnalyzeColumnsWithExtendedStats(c *C) {
 		testkit.Rows("0 5"))
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(
 		// db, tbl, part, col, is_idx, value, count
-		testkit.Rows("test t  b 0 1 1",
-			"test t  b 0 2 1",
-			"test t  c 0 1 1",
-			"test t  c 0 2 1"))
+		testkit.Rows("test t  b 0 1 1 0.4 1",
+			"test t  b 0 2 1 0.4 1",
+			"test t  c 0 1 1 0.4 1",
+			"test t  c 0 2 1 0.4 1"))
 	tk.MustQuery(fmt.Sprintf("select is_index, hist_id, distinct_count, null_count, tot_col_size, stats_ver, truncate(correlation,2) from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
 		testkit.Rows("0 1 0 0 5 0 0", // column a is not analyzed
 			"0 2 5 0 5 2 1",
@@ -3547,10 +3668,10 @@ func (s *testStatsSuite) TestAnalyzeColumnsWithVirtualColumnIndex(c *C) {
 		testkit.Rows("0 9"))
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(
 		// db, tbl, part, col, is_idx, value, count
-		testkit.Rows("test t  b 0 4 2",
-			"test t  b 0 5 3",
-			"test t  idx 1 5 2",
-			"test t  idx 1 6 3"))
+		testkit.Rows("test t  b 0 4 2 0.625 1.875",
+			"test t  b 0 5 3 0.625 1.875",
+			"test t  idx 1 5 2 0.625 1.875",
+			"test t  idx 1 6 3 0.625 1.875"))
 	tk.MustQuery(fmt.Sprintf("select is_index, hist_id, distinct_count, null_count, stats_ver, truncate(correlation,2) from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
 		testkit.Rows("0 1 0 0 0 0", // column a is not analyzed
 			"0 2 5 1 2 1",
@@ -3584,10 +3705,10 @@ func (s *testStatsSuite) TestAnalyzeColumnsAfterAnalyzeAll(c *C) {
 		testkit.Rows("0 6"))
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(
 		// db, tbl, part, col, is_idx, value, count
-		testkit.Rows("test t  a 0 1 2",
-			"test t  a 0 2 2",
-			"test t  b 0 1 2",
-			"test t  b 0 2 2"))
+		testkit.Rows("test t  a 0 1 2 0.6666666666666666 1.3333333333333333",
+			"test t  a 0 2 2 0.6666666666666666 1.3333333333333333",
+			"test t  b 0 1 2 0.6666666666666666 1.3333333333333333",
+			"test t  b 0 2 2 0.6666666666666666 1.3333333333333333"))
 	tk.MustQuery(fmt.Sprintf("select is_index, hist_id, distinct_count, null_count, tot_col_size, stats_ver, truncate(correlation,2) from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
 		testkit.Rows("0 1 4 0 6 2 1",
 			"0 2 4 0 6 2 1"))
@@ -3605,10 +3726,10 @@ func (s *testStatsSuite) TestAnalyzeColumnsAfterAnalyzeAll(c *C) {
 		testkit.Rows("0 8"))
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(
 		// db, tbl, part, col, is_idx, value, count
-		testkit.Rows("test t  a 0 1 2",
-			"test t  a 0 2 2",
-			"test t  b 0 1 3",
-			"test t  b 0 2 2"))
+		testkit.Rows("test t  a 0 1 2 0.6666666666666666 1.3333333333333333",
+			"test t  a 0 2 2 0.6666666666666666 1.3333333333333333",
+			"test t  b 0 1 3 0.625 1.875",
+			"test t  b 0 2 2 0.625 1.875"))
 	tk.MustQuery(fmt.Sprintf("select is_index, hist_id, distinct_count, null_count, tot_col_size, stats_ver, truncate(correlation,2) from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
 		testkit.Rows("0 1 4 0 8 2 1", // tot_col_size of column a is updated to 8 by DumpStatsDeltaToKV
 			"0 2 5 0 8 2 0.76"))
@@ -3619,3 +3740,108 @@ func (s *testStatsSuite) TestAnalyzeColumnsAfterAnalyzeAll(c *C) {
 			"test t  b 0 1 3 1 6 6 0"))
 	tk.MustQuery(fmt.Sprintf("select hist_id from mysql.stats_histograms where version = (select version from mysql.stats_meta where table_id = %d)", tblID)).Check(testkit.Rows("2"))
 }
+
+func (s *testStatsSuite) TestCollectPredicateColumnsFromExecution(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	originalVal := tk.MustQuery("select @@tidb_enable_column_tracking").Rows()[0][0].(string)
+	defer func() {
+		tk.MustExec(fmt.Sprintf("set global tidb_enable_column_tracking = %v", originalVal))
+	}()
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int, b int, c int)")
+	tk.MustExec("create table t2 (a int, b int, c int)")
+	h := s.do.StatsHandle()
+
+	// The predicate columns are not collected when tidb_enable_column_tracking is off.
+	tk.MustExec("set global tidb_enable_column_tracking = 0")
+	tk.MustQuery("select * from t1 where a > 1").Check(testkit.Rows())
+	c.Assert(h.DumpColStatsUsageToKV(), IsNil)
+	tk.MustQuery("show column_stats_usage where db_name = 'test' and last_used_at is not null").Check(testkit.Rows())
+
+	tk.MustExec("set global tidb_enable_column_tracking = 1")
+	tk.MustQuery("select * from t1 join t2 on t1.b = t2.b where t1.a > 1").Check(testkit.Rows())
+	c.Assert(h.DumpColStatsUsageToKV(), IsNil)
+	rows := tk.MustQuery("show column_stats_usage where db_name = 'test' and last_used_at is not null").Sort().Rows()
+	c.Assert(len(rows), Equals, 3)
+	c.Assert(rows[0][1], Equals, "t1")
+	c.Assert(rows[0][3], Equals, "a")
+	c.Assert(rows[1][1], Equals, "t1")
+	c.Assert(rows[1][3], Equals, "b")
+	c.Assert(rows[2][1], Equals, "t2")
+	c.Assert(rows[2][3], Equals, "b")
+}
+
+func (s *testStatsSuite) TestAnalyzePredicateColumns(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	originalVal := tk.MustQuery("select @@tidb_enable_column_tracking").Rows()[0][0].(string)
+	defer func() {
+		tk.MustExec(fmt.Sprintf("set global tidb_enable_column_tracking = %v", originalVal))
+	}()
+	tk.MustExec("set global tidb_enable_column_tracking = 1")
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("create table t (a int, b int, c int, d int, index idx_c(c))")
+	tk.MustExec("insert into t values (1,1,1,1), (2,2,2,2), (3,3,3,3)")
+	h := s.do.StatsHandle()
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+
+	is := s.do.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tblID := tbl.Meta().ID
+
+	// Only the indexed columns are analyzed if no predicate column has been collected.
+	tk.MustExec("analyze table t predicate columns")
+	tk.MustQuery("show warnings").Sort().Check(testkit.Rows(
+		"Note 1105 Analyze use auto adjusted sample rate 1.000000 for table test.t.",
+		"Warning 1105 No predicate column has been collected yet for table test.t so only indexes and the columns composing the indexes will be analyzed.",
+	))
+	tk.MustQuery(fmt.Sprintf("select is_index, hist_id, stats_ver from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
+		testkit.Rows("0 1 0", "0 2 0", "0 3 2", "0 4 0", "1 1 2"))
+
+	tk.MustQuery("select * from t where b > 1 and d < 3").Check(testkit.Rows("2 2 2 2"))
+	c.Assert(h.DumpColStatsUsageToKV(), IsNil)
+	tk.MustExec("analyze table t predicate columns")
+	tk.MustQuery("show warnings").Sort().Check(testkit.Rows(
+		"Note 1105 Analyze use auto adjusted sample rate 1.000000 for table test.t.",
+	))
+	tk.MustQuery(fmt.Sprintf("select is_index, hist_id, stats_ver from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
+		testkit.Rows("0 1 0", "0 2 2", "0 3 2", "0 4 2", "1 1 2"))
+}
+
+func (s *testStatsSuite) TestAnalyzeJobsPersistence(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("delete from mysql.analyze_jobs")
+	h := s.do.StatsHandle()
+	job := &statistics.AnalyzeJob{DBName: "test", TableName: "t", PartitionName: "p0", JobInfo: "analyze table", EstimatedRows: 10}
+	c.Assert(h.InsertAnalyzeJob(job, "127.0.0.1:4000", 3), IsNil)
+	c.Assert(job.ID, Greater, uint64(0))
+	tk.MustQuery(fmt.Sprintf("select table_schema, table_name, partition_name, job_info, total_rows, state, instance, process_id from mysql.analyze_jobs where id = %d", job.ID)).Check(
+		testkit.Rows("test t p0 analyze table 10 pending 127.0.0.1:4000 3"))
+
+	statistics.AddNewAnalyzeJob(job)
+	defer statistics.RemoveAnalyzeJob(job)
+	job.Start()
+	c.Assert(h.StartAnalyzeJob(job), IsNil)
+	job.Update(5)
+	c.Assert(h.DumpAnalyzeJobProgress(), IsNil)
+	tk.MustQuery(fmt.Sprintf("select processed_rows, phase, state, start_time is not null from mysql.analyze_jobs where id = %d", job.ID)).Check(
+		testkit.Rows("5 scanning running 1"))
+
+	job.Update(5)
+	job.SetPhase(statistics.AnalyzePhaseSaving)
+	job.Finish(nil)
+	c.Assert(h.FinishAnalyzeJob(job), IsNil)
+	tk.MustQuery(fmt.Sprintf("select processed_rows, phase, state, end_time is not null, fail_reason from mysql.analyze_jobs where id = %d", job.ID)).Check(
+		testkit.Rows("10 saving finished 1 <nil>"))
+
+	c.Assert(h.DeleteAnalyzeJobs(time.Now().Add(-time.Hour)), IsNil)
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(testkit.Rows("1"))
+	c.Assert(h.DeleteAnalyzeJobs(time.Now().Add(time.Hour)), IsNil)
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(testkit.Rows("0"))
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// LockTables locks the statistics of the tables, so that auto analyze and ANALYZE skip them.
+// It returns the IDs of the tables whose statistics have been locked already.
+func (h *Handle) LockTables(tableIDs []int64) (skipped []int64, err error) {
+	return h.updateLockedTables(tableIDs, true)
+}
+
+// UnlockTables unlocks the statistics of the tables.
+// It returns the IDs of the tables whose statistics are not locked.
+func (h *Handle) UnlockTables(tableIDs []int64) (skipped []int64, err error) {
+	return h.updateLockedTables(tableIDs, false)
+}
+
+func (h *Handle) updateLockedTables(tableIDs []int64, lock bool) (skipped []int64, err error) {
+	lockedTables, err := h.GetLockedTables()
+	if err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := context.Background()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(ctx, "begin")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		err = finishTransaction(ctx, exec, err)
+	}()
+	for _, id := range tableIDs {
+		if _, locked := lockedTables[id]; locked == lock {
+			skipped = append(skipped, id)
+			continue
+		}
+		if lock {
+			_, err = exec.ExecuteInternal(ctx, "insert ignore into mysql.stats_table_locked (table_id) values (%?)", id)
+		} else {
+			_, err = exec.ExecuteInternal(ctx, "delete from mysql.stats_table_locked where table_id = %?", id)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return skipped, nil
+}
+
+// GetLockedTables returns the IDs of the tables whose statistics are locked.
+func (h *Handle) GetLockedTables() (map[int64]struct{}, error) {
+	rows, _, err := h.execRestrictedSQL(context.Background(), "select table_id from mysql.stats_table_locked")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	lockedTables := make(map[int64]struct{}, len(rows))
+	for _, row := range rows {
+		lockedTables[row.GetInt64(0)] = struct{}{}
+	}
+	return lockedTables, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/pingcap/tidb/statistics"
+)
+
+// statsCacheLRU records the order in which the tables of the stats cache are accessed. When the stats cache
+// exceeds tidb_stats_cache_mem_quota, the column stats of the least recently used tables are evicted first.
+type statsCacheLRU struct {
+	sync.Mutex
+	// list holds the physical IDs of the tables, the front is the most recently used one.
+	list  *list.List
+	elems map[int64]*list.Element
+}
+
+func newStatsCacheLRU() *statsCacheLRU {
+	return &statsCacheLRU{
+		list:  list.New(),
+		elems: make(map[int64]*list.Element),
+	}
+}
+
+// access marks the table as the most recently used one.
+func (l *statsCacheLRU) access(physicalID int64) {
+	l.Lock()
+	defer l.Unlock()
+	if elem, ok := l.elems[physicalID]; ok {
+		l.list.MoveToFront(elem)
+		return
+	}
+	l.elems[physicalID] = l.list.PushFront(physicalID)
+}
+
+// evictionOrder returns the physical IDs of the cached tables in the order of eviction. The tables that have
+// never been accessed come first, then the others from the least recently used one. The most recently used
+// table is never evicted, otherwise the column stats just loaded for the planner may be evicted at once.
+// The tables that are no longer cached are removed from the lru.
+func (l *statsCacheLRU) evictionOrder(tables map[int64]*statistics.Table) []int64 {
+	l.Lock()
+	defer l.Unlock()
+	ids := make([]int64, 0, len(tables))
+	for id := range tables {
+		if _, ok := l.elems[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	for elem := l.list.Back(); elem != nil && elem != l.list.Front(); {
+		prev := elem.Prev()
+		id := elem.Value.(int64)
+		if _, ok := tables[id]; ok {
+			ids = append(ids, id)
+		} else {
+			l.list.Remove(elem)
+			delete(l.elems, id)
+		}
+		elem = prev
+	}
+	return ids
+}
+
+// evictColumnStats returns a copy of the table whose column stats are evicted, except the handle columns,
+// which are always loaded. The evicted columns only keep the meta data, and their histograms, CMSketch,
+// TopN and FMSketch are loaded again by `LoadNeededHistograms` when the planner needs them.
+func evictColumnStats(tbl *statistics.Table) (*statistics.Table, bool) {
+	var newTbl *statistics.Table
+	for id, col := range tbl.Columns {
+		if col.IsHandle || (col.Len() == 0 && col.TopN.Num() == 0) {
+			continue
+		}
+		if newTbl == nil {
+			newTbl = tbl.Copy()
+		}
+		evicted := &statistics.Column{
+			PhysicalID: col.PhysicalID,
+			Histogram:  *statistics.NewHistogram(col.ID, col.Histogram.NDV, col.NullCount, col.LastUpdateVersion, col.Tp, 0, col.TotColSize),
+			Info:       col.Info,
+			Count:      col.Count,
+			ErrorRate:  col.ErrorRate,
+			IsHandle:   col.IsHandle,
+			Flag:       col.Flag,
+			StatsVer:   col.StatsVer,
+		}
+		col.LastAnalyzePos.Copy(&evicted.LastAnalyzePos)
+		evicted.Histogram.Correlation = col.Histogram.Correlation
+		newTbl.Columns[id] = evicted
+	}
+	return newTbl, newTbl != nil
+}
+
+// evict evicts the column stats of the tables in the given order until the memory usage of
+// the cache doesn't exceed the quota. The cache must be a copy that is not published yet.
+func (sc *statsCache) evict(order []int64, quota int64) {
+	for _, id := range order {
+		if sc.memUsage <= quota {
+			return
+		}
+		tbl := sc.tables[id]
+		newTbl, ok := evictColumnStats(tbl)
+		if !ok {
+			continue
+		}
+		sc.memUsage += newTbl.MemoryUsage() - tbl.MemoryUsage()
+		sc.tables[id] = newTbl
+	}
+}
@@ -16,9 +16,11 @@ package handle
 
 import (
 	"bytes"
+	"container/heap"
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -37,6 +39,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/collate"
@@ -133,13 +136,26 @@ func (m errorRateDeltaMap) clear(tableID int64, histID int64, isIndex bool) {
 	m[tableID] = item
 }
 
-func merge(s *SessionStatsCollector, deltaMap tableDeltaMap, rateMap errorRateDeltaMap, feedback *statistics.QueryFeedbackMap) {
+// colStatsUsageMap maps the predicate columns to the time when they are used last time.
+type colStatsUsageMap map[model.TableColumnID]time.Time
+
+func (m colStatsUsageMap) merge(other colStatsUsageMap) {
+	for id, t := range other {
+		if mt, ok := m[id]; !ok || mt.Before(t) {
+			m[id] = t
+		}
+	}
+}
+
+func merge(s *SessionStatsCollector, deltaMap tableDeltaMap, rateMap errorRateDeltaMap, feedback *statistics.QueryFeedbackMap, colMap colStatsUsageMap) {
 	deltaMap.merge(s.mapper)
 	s.mapper = make(tableDeltaMap)
 	rateMap.merge(s.rateMap)
 	s.rateMap = make(errorRateDeltaMap)
 	feedback.Merge(s.feedback)
 	s.feedback = statistics.NewQueryFeedbackMap()
+	colMap.merge(s.colMap)
+	s.colMap = make(colStatsUsageMap)
 }
 
 // SessionStatsCollector is a list item that holds the delta mapper. If you want to write or read mapper, you must lock it.
@@ -149,6 +165,7 @@ type SessionStatsCollector struct {
 	mapper   tableDeltaMap
 	feedback *statistics.QueryFeedbackMap
 	rateMap  errorRateDeltaMap
+	colMap   colStatsUsageMap
 	next     *SessionStatsCollector
 	// deleted is set to true when a session is closed. Every time we sweep the list, we will remove the useless collector.
 	deleted bool
@@ -168,6 +185,13 @@ func (s *SessionStatsCollector) Update(id int64, delta int64, count int64, colSi
 	s.mapper.update(id, delta, count, colSize)
 }
 
+// UpdateColStatsUsage updates the last time when the column stats are used(visited).
+func (s *SessionStatsCollector) UpdateColStatsUsage(colMap colStatsUsageMap) {
+	s.Lock()
+	defer s.Unlock()
+	s.colMap.merge(colMap)
+}
+
 var (
 	// MinLogScanCount is the minimum scan count for a feedback to be logged.
 	MinLogScanCount = atomic.NewInt64(1000)
@@ -213,6 +237,7 @@ func (h *Handle) NewSessionStatsCollector() *SessionStatsCollector {
 		rateMap:  make(errorRateDeltaMap),
 		next:     h.listHead.next,
 		feedback: statistics.NewQueryFeedbackMap(),
+		colMap:   make(colStatsUsageMap),
 	}
 	h.listHead.next = newCollector
 	return newCollector
@@ -222,7 +247,10 @@ func (h *Handle) NewSessionStatsCollector() *SessionStatsCollector {
 type IndexUsageInformation struct {
 	QueryCount   int64
 	RowsSelected int64
-	LastUsedAt   string
+	// RowsWritten is the number of the index rows written by the committed transactions.
+	RowsWritten int64
+	// LastUsedAt is the last time the index is read, it's empty if the index is only written.
+	LastUsedAt string
 }
 
 // GlobalIndexID is the key type for indexUsageMap.
@@ -246,6 +274,7 @@ func (m indexUsageMap) updateByKey(id GlobalIndexID, value *IndexUsageInformatio
 	item := m[id]
 	item.QueryCount += value.QueryCount
 	item.RowsSelected += value.RowsSelected
+	item.RowsWritten += value.RowsWritten
 	if item.LastUsedAt < value.LastUsedAt {
 		item.LastUsedAt = value.LastUsedAt
 	}
@@ -266,7 +295,10 @@ func (m indexUsageMap) merge(destMap indexUsageMap) {
 
 // Update updates the mapper in SessionIndexUsageCollector.
 func (s *SessionIndexUsageCollector) Update(tableID int64, indexID int64, value *IndexUsageInformation) {
-	value.LastUsedAt = time.Now().Format(types.TimeFSPFormat)
+	// Writing an index doesn't mean it's used, so only the reads update the last used time.
+	if value.QueryCount > 0 {
+		value.LastUsedAt = time.Now().Format(types.TimeFSPFormat)
+	}
 	s.Lock()
 	defer s.Unlock()
 	s.mapper.update(tableID, indexID, value)
@@ -321,8 +353,16 @@ func (h *Handle) DumpIndexUsageToKV() error {
 	ctx := context.Background()
 	mapper := h.sweepIdxUsageList()
 	for id, value := range mapper {
-		const sql = `insert into mysql.SCHEMA_INDEX_USAGE values (%?, %?, %?, %?, %?) on duplicate key update query_count=query_count+%?, rows_selected=rows_selected+%?, last_used_at=greatest(last_used_at, %?)`
-		_, _, err := h.execRestrictedSQL(ctx, sql, id.TableID, id.IndexID, value.QueryCount, value.RowsSelected, value.LastUsedAt, value.QueryCount, value.RowsSelected, value.LastUsedAt)
+		var lastUsedAt interface{}
+		if value.LastUsedAt != "" {
+			lastUsedAt = value.LastUsedAt
+		}
+		// The last_used_at is NULL if the index has never been read, `greatest` returns NULL if any argument is NULL.
+		const sql = `insert into mysql.SCHEMA_INDEX_USAGE (table_id, index_id, query_count, rows_selected, last_used_at, rows_written) values (%?, %?, %?, %?, %?, %?)
+			on duplicate key update query_count=query_count+%?, rows_selected=rows_selected+%?, rows_written=rows_written+%?,
+			last_used_at=greatest(ifnull(last_used_at, %?), ifnull(%?, last_used_at))`
+		_, _, err := h.execRestrictedSQL(ctx, sql, id.TableID, id.IndexID, value.QueryCount, value.RowsSelected, lastUsedAt, value.RowsWritten,
+			value.QueryCount, value.RowsSelected, value.RowsWritten, lastUsedAt, lastUsedAt)
 		if err != nil {
 			return err
 		}
@@ -384,12 +424,13 @@ func (h *Handle) sweepList() {
 	deltaMap := make(tableDeltaMap)
 	errorRateMap := make(errorRateDeltaMap)
 	feedback := statistics.NewQueryFeedbackMap()
+	colMap := make(colStatsUsageMap)
 	prev := h.listHead
 	prev.Lock()
 	for curr := prev.next; curr != nil; curr = curr.next {
 		curr.Lock()
-		// Merge the session stats into deltaMap, errorRateMap and feedback respectively.
-		merge(curr, deltaMap, errorRateMap, feedback)
+		// Merge the session stats into deltaMap, errorRateMap, feedback and colMap respectively.
+		merge(curr, deltaMap, errorRateMap, feedback, colMap)
 		if curr.deleted {
 			prev.next = curr.next
 			// Since the session is already closed, we can safely unlock it here.
@@ -411,6 +452,9 @@ func (h *Handle) sweepList() {
 	h.feedback.data.Merge(feedback)
 	h.feedback.data.SiftFeedbacks()
 	h.feedback.Unlock()
+	h.colMap.Lock()
+	h.colMap.data.merge(colMap)
+	h.colMap.Unlock()
 }
 
 // DumpStatsDeltaToKV sweeps the whole list and updates the global map, then we dumps every table that held in map to KV.
@@ -453,6 +497,65 @@ func (h *Handle) DumpStatsDeltaToKV(mode dumpMode) error {
 	return nil
 }
 
+// batchInsertSize is the batch size used by internal SQL to insert values to some system table.
+const batchInsertSize = 10
+
+// DumpColStatsUsageToKV sweeps the whole list, updates the column stats usage map and dumps it to KV.
+func (h *Handle) DumpColStatsUsageToKV() error {
+	if !variable.EnableColumnTracking.Load() {
+		return nil
+	}
+	h.sweepList()
+	h.colMap.Lock()
+	colMap := h.colMap.data
+	h.colMap.data = make(colStatsUsageMap)
+	h.colMap.Unlock()
+	defer func() {
+		h.colMap.Lock()
+		h.colMap.data.merge(colMap)
+		h.colMap.Unlock()
+	}()
+	type pair struct {
+		tblColID   model.TableColumnID
+		lastUsedAt string
+	}
+	pairs := make([]pair, 0, len(colMap))
+	for id, t := range colMap {
+		pairs = append(pairs, pair{tblColID: id, lastUsedAt: t.UTC().Format(types.TimeFormat)})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].tblColID.TableID == pairs[j].tblColID.TableID {
+			return pairs[i].tblColID.ColumnID < pairs[j].tblColID.ColumnID
+		}
+		return pairs[i].tblColID.TableID < pairs[j].tblColID.TableID
+	})
+	// Use batch insert to reduce cost.
+	for i := 0; i < len(pairs); i += batchInsertSize {
+		end := i + batchInsertSize
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+		sql := new(strings.Builder)
+		sqlexec.MustFormatSQL(sql, "INSERT INTO mysql.column_stats_usage (table_id, column_id, last_used_at) VALUES ")
+		for j := i; j < end; j++ {
+			// Since we will use some session from session pool to execute the insert statement, we pass in UTC time here and covert it
+			// to the session's time zone when executing the insert statement. In this way we can make the stored time right.
+			sqlexec.MustFormatSQL(sql, "(%?, %?, CONVERT_TZ(%?, '+00:00', @@TIME_ZONE))", pairs[j].tblColID.TableID, pairs[j].tblColID.ColumnID, pairs[j].lastUsedAt)
+			if j < end-1 {
+				sqlexec.MustFormatSQL(sql, ",")
+			}
+		}
+		sqlexec.MustFormatSQL(sql, " ON DUPLICATE KEY UPDATE last_used_at = CASE WHEN last_used_at IS NULL THEN VALUES(last_used_at) ELSE GREATEST(last_used_at, VALUES(last_used_at)) END")
+		if _, _, err := h.execRestrictedSQL(context.Background(), sql.String()); err != nil {
+			return errors.Trace(err)
+		}
+		for j := i; j < end; j++ {
+			delete(colMap, pairs[j].tblColID)
+		}
+	}
+	return nil
+}
+
 // dumpTableStatDeltaToKV dumps a single delta with some table to KV and updates the version.
 func (h *Handle) dumpTableStatCountToKV(id int64, delta variable.TableDelta) (updated bool, err error) {
 	if delta.Count == 0 {
@@ -900,8 +1003,8 @@ func NeedAnalyzeTable(tbl *statistics.Table, limit time.Duration, autoAnalyzeRat
 
 func (h *Handle) getAutoAnalyzeParameters() map[string]string {
 	ctx := context.Background()
-	sql := "select variable_name, variable_value from mysql.global_variables where variable_name in (%?, %?, %?)"
-	rows, _, err := h.execRestrictedSQL(ctx, sql, variable.TiDBAutoAnalyzeRatio, variable.TiDBAutoAnalyzeStartTime, variable.TiDBAutoAnalyzeEndTime)
+	sql := "select variable_name, variable_value from mysql.global_variables where variable_name in (%?, %?, %?, %?)"
+	rows, _, err := h.execRestrictedSQL(ctx, sql, variable.TiDBAutoAnalyzeRatio, variable.TiDBAutoAnalyzeStartTime, variable.TiDBAutoAnalyzeEndTime, variable.TiDBAutoAnalyzeConcurrency)
 	if err != nil {
 		return map[string]string{}
 	}
@@ -920,6 +1023,14 @@ func parseAutoAnalyzeRatio(ratio string) float64 {
 	return math.Max(autoAnalyzeRatio, 0)
 }
 
+func parseAutoAnalyzeConcurrency(concurrency string) int {
+	c, err := strconv.Atoi(concurrency)
+	if err != nil || c < 1 {
+		return variable.DefAutoAnalyzeConcurrency
+	}
+	return c
+}
+
 func parseAnalyzePeriod(start, end string) (time.Time, time.Time, error) {
 	if start == "" {
 		start = variable.DefAutoAnalyzeStartTime
@@ -936,15 +1047,17 @@ func parseAnalyzePeriod(start, end string) (time.Time, time.Time, error) {
 }
 
 // HandleAutoAnalyze analyzes the newly created table or index.
+// The tables and partitions that need to be analyzed are put into a priority queue ordered by
+// their staleness, and at most `tidb_auto_analyze_concurrency` of them are analyzed in one round.
 func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
 	err := h.UpdateSessionVar()
 	if err != nil {
 		logutil.BgLogger().Error("[stats] update analyze version for auto analyze session failed", zap.Error(err))
 		return false
 	}
-	dbs := is.AllSchemaNames()
 	parameters := h.getAutoAnalyzeParameters()
 	autoAnalyzeRatio := parseAutoAnalyzeRatio(parameters[variable.TiDBAutoAnalyzeRatio])
+	concurrency := parseAutoAnalyzeConcurrency(parameters[variable.TiDBAutoAnalyzeConcurrency])
 	start, end, err := parseAnalyzePeriod(parameters[variable.TiDBAutoAnalyzeStartTime], parameters[variable.TiDBAutoAnalyzeEndTime])
 	if err != nil {
 		logutil.BgLogger().Error("[stats] parse auto analyze period failed", zap.Error(err))
@@ -953,136 +1066,121 @@ func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
 	if !timeutil.WithinDayTimePeriod(start, end, time.Now()) {
 		return false
 	}
+	lockedTables, err := h.GetLockedTables()
+	if err != nil {
+		logutil.BgLogger().Error("[stats] get the tables with locked stats failed", zap.Error(err))
+		return false
+	}
+	queue := h.buildAutoAnalyzeQueue(is, autoAnalyzeRatio, lockedTables)
+	return h.runAutoAnalyzeJobs(queue, concurrency)
+}
+
+// buildAutoAnalyzeQueue collects the tables and partitions that need to be analyzed.
+// The partitions are analyzed independently no matter which partition prune mode is used.
+// The tables whose stats are locked are skipped.
+func (h *Handle) buildAutoAnalyzeQueue(is infoschema.InfoSchema, ratio float64, lockedTables map[int64]struct{}) *autoAnalyzeQueue {
+	queue := &autoAnalyzeQueue{}
+	now := time.Now()
 	pruneMode := h.CurrentPruneMode()
-	for _, db := range dbs {
+	for _, db := range is.AllSchemaNames() {
 		tbls := is.SchemaTables(model.NewCIStr(db))
 		for _, tbl := range tbls {
 			tblInfo := tbl.Meta()
 			if tblInfo.IsView() {
 				continue
 			}
+			if _, ok := lockedTables[tblInfo.ID]; ok {
+				continue
+			}
 			pi := tblInfo.GetPartitionInfo()
 			if pi == nil {
 				statsTbl := h.GetTableStats(tblInfo)
-				sql := "analyze table %n.%n"
-				analyzed := h.autoAnalyzeTable(tblInfo, statsTbl, start, end, autoAnalyzeRatio, sql, db, tblInfo.Name.O)
-				if analyzed {
-					// analyze one table at a time to let it get the freshest parameters.
-					// others will be analyzed next round which is just 3s later.
-					return true
+				if job := h.buildAutoAnalyzeJob(tblInfo, statsTbl, nil, ratio, now, "analyze table %n.%n", db, tblInfo.Name.O); job != nil {
+					heap.Push(queue, job)
 				}
 				continue
 			}
+			var globalStatsTbl *statistics.Table
 			if pruneMode == variable.Dynamic {
-				analyzed := h.autoAnalyzePartitionTable(tblInfo, pi, db, start, end, autoAnalyzeRatio)
-				if analyzed {
-					return true
-				}
-				continue
+				globalStatsTbl = h.GetTableStats(tblInfo)
 			}
 			for _, def := range pi.Definitions {
-				sql := "analyze table %n.%n partition %n"
 				statsTbl := h.GetPartitionStats(tblInfo, def.ID)
-				analyzed := h.autoAnalyzeTable(tblInfo, statsTbl, start, end, autoAnalyzeRatio, sql, db, tblInfo.Name.O, def.Name.O)
-				if analyzed {
-					return true
+				if job := h.buildAutoAnalyzeJob(tblInfo, statsTbl, globalStatsTbl, ratio, now, "analyze table %n.%n partition %n", db, tblInfo.Name.O, def.Name.O); job != nil {
+					heap.Push(queue, job)
 				}
 			}
 		}
 	}
-	return false
+	return queue
 }
 
-func (h *Handle) autoAnalyzeTable(tblInfo *model.TableInfo, statsTbl *statistics.Table, start, end time.Time, ratio float64, sql string, params ...interface{}) bool {
+// buildAutoAnalyzeJob returns the job to analyze the table or partition, it returns nil if there is no need to analyze it.
+// globalStatsTbl is the stats of the partitioned table in dynamic prune mode, it is used to decide the stats version.
+func (h *Handle) buildAutoAnalyzeJob(tblInfo *model.TableInfo, statsTbl, globalStatsTbl *statistics.Table, ratio float64, now time.Time, sql string, params ...interface{}) *autoAnalyzeJob {
 	if statsTbl.Pseudo || statsTbl.Count < AutoAnalyzeMinCnt {
-		return false
+		return nil
+	}
+	tableStatsVer := h.mu.ctx.GetSessionVars().AnalyzeVersion
+	statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
+	if globalStatsTbl != nil {
+		statistics.CheckAnalyzeVerOnTable(globalStatsTbl, &tableStatsVer)
 	}
 	if needAnalyze, reason := NeedAnalyzeTable(statsTbl, 20*h.Lease(), ratio); needAnalyze {
-		escaped, err := sqlexec.EscapeSQL(sql, params...)
-		if err != nil {
-			return false
+		return &autoAnalyzeJob{
+			tableID:  tblInfo.ID,
+			sql:      sql,
+			params:   params,
+			statsVer: tableStatsVer,
+			reason:   reason,
+			weight:   calcAutoAnalyzeWeight(statsTbl, now),
 		}
-		logutil.BgLogger().Info("[stats] auto analyze triggered", zap.String("sql", escaped), zap.String("reason", reason))
-		tableStatsVer := h.mu.ctx.GetSessionVars().AnalyzeVersion
-		statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-		h.execAutoAnalyze(tableStatsVer, sql, params...)
-		return true
 	}
 	for _, idx := range tblInfo.Indices {
 		if _, ok := statsTbl.Indices[idx.ID]; !ok && idx.State == model.StatePublic {
-			sqlWithIdx := sql + " index %n"
-			paramsWithIdx := append(params, idx.Name.O)
-			escaped, err := sqlexec.EscapeSQL(sqlWithIdx, paramsWithIdx...)
-			if err != nil {
-				return false
+			return &autoAnalyzeJob{
+				tableID:  tblInfo.ID,
+				sql:      sql + " index %n",
+				params:   append(params, idx.Name.O),
+				statsVer: tableStatsVer,
+				reason:   "index unanalyzed",
+				weight:   calcAutoAnalyzeWeight(statsTbl, now) + newIndexWeight,
 			}
-			logutil.BgLogger().Info("[stats] auto analyze for unanalyzed", zap.String("sql", escaped))
-			tableStatsVer := h.mu.ctx.GetSessionVars().AnalyzeVersion
-			statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-			h.execAutoAnalyze(tableStatsVer, sqlWithIdx, paramsWithIdx...)
-			return true
 		}
 	}
-	return false
+	return nil
 }
 
-func (h *Handle) autoAnalyzePartitionTable(tblInfo *model.TableInfo, pi *model.PartitionInfo, db string, start, end time.Time, ratio float64) bool {
-	tableStatsVer := h.mu.ctx.GetSessionVars().AnalyzeVersion
-	partitionNames := make([]interface{}, 0, len(pi.Definitions))
-	for _, def := range pi.Definitions {
-		partitionStatsTbl := h.GetPartitionStats(tblInfo, def.ID)
-		if partitionStatsTbl.Pseudo || partitionStatsTbl.Count < AutoAnalyzeMinCnt {
+// runAutoAnalyzeJobs analyzes the jobs with the highest priorities concurrently. The partitions of the same
+// table are not analyzed at the same time, and the others will be analyzed in the next rounds, so every
+// round gets the freshest parameters.
+func (h *Handle) runAutoAnalyzeJobs(queue *autoAnalyzeQueue, concurrency int) bool {
+	jobs := make([]*autoAnalyzeJob, 0, concurrency)
+	analyzingTables := make(map[int64]struct{}, concurrency)
+	for queue.Len() > 0 && len(jobs) < concurrency {
+		job := heap.Pop(queue).(*autoAnalyzeJob)
+		if _, ok := analyzingTables[job.tableID]; ok {
 			continue
 		}
-		if needAnalyze, _ := NeedAnalyzeTable(partitionStatsTbl, 20*h.Lease(), ratio); needAnalyze {
-			partitionNames = append(partitionNames, def.Name.O)
-			statistics.CheckAnalyzeVerOnTable(partitionStatsTbl, &tableStatsVer)
-		}
-	}
-	getSQL := func(prefix, suffix string, numPartitions int) string {
-		var sqlBuilder strings.Builder
-		sqlBuilder.WriteString(prefix)
-		for i := 0; i < numPartitions; i++ {
-			if i != 0 {
-				sqlBuilder.WriteString(",")
-			}
-			sqlBuilder.WriteString(" %n")
-		}
-		sqlBuilder.WriteString(suffix)
-		return sqlBuilder.String()
-	}
-	if len(partitionNames) > 0 {
-		logutil.BgLogger().Info("[stats] auto analyze triggered")
-		sql := getSQL("analyze table %n.%n partition", "", len(partitionNames))
-		params := append([]interface{}{db, tblInfo.Name.O}, partitionNames...)
-		statsTbl := h.GetTableStats(tblInfo)
-		statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-		h.execAutoAnalyze(tableStatsVer, sql, params...)
-		return true
+		analyzingTables[job.tableID] = struct{}{}
+		jobs = append(jobs, job)
 	}
-	for _, idx := range tblInfo.Indices {
-		if idx.State != model.StatePublic {
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		escaped, err := sqlexec.EscapeSQL(job.sql, job.params...)
+		if err != nil {
 			continue
 		}
-		for _, def := range pi.Definitions {
-			partitionStatsTbl := h.GetPartitionStats(tblInfo, def.ID)
-			if _, ok := partitionStatsTbl.Indices[idx.ID]; !ok {
-				partitionNames = append(partitionNames, def.Name.O)
-				statistics.CheckAnalyzeVerOnTable(partitionStatsTbl, &tableStatsVer)
-			}
-		}
-		if len(partitionNames) > 0 {
-			logutil.BgLogger().Info("[stats] auto analyze for unanalyzed")
-			sql := getSQL("analyze table %n.%n partition", " index %n", len(partitionNames))
-			params := append([]interface{}{db, tblInfo.Name.O}, partitionNames...)
-			params = append(params, idx.Name.O)
-			statsTbl := h.GetTableStats(tblInfo)
-			statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-			h.execAutoAnalyze(tableStatsVer, sql, params...)
-			return true
-		}
+		logutil.BgLogger().Info("[stats] auto analyze triggered", zap.String("sql", escaped), zap.String("reason", job.reason), zap.Float64("weight", job.weight))
+		wg.Add(1)
+		go func(job *autoAnalyzeJob) {
+			defer wg.Done()
+			defer util.Recover(metrics.LabelAnalyze, "autoAnalyze", nil, false)
+			h.execAutoAnalyze(job.statsVer, job.sql, job.params...)
+		}(job)
 	}
-	return false
+	wg.Wait()
+	return len(jobs) > 0
 }
 
 var execOptionForAnalyze = map[int]sqlexec.OptionFuncAlias{
@@ -2336,3 +2336,103 @@ func (s *testSerialStatsSuite) TestAutoAnalyzeRatio(c *C) {
 	c.Assert(h.Update(is), IsNil)
 	c.Assert(h.HandleAutoAnalyze(s.do.InfoSchema()), IsTrue)
 }
+
+func (s *testSerialStatsSuite) TestAutoAnalyzePriorityQueue(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	oriRatio := tk.MustQuery("select @@tidb_auto_analyze_ratio").Rows()[0][0].(string)
+	handle.AutoAnalyzeMinCnt = 0
+	tk.MustExec("set global tidb_auto_analyze_ratio = 0.5")
+	defer func() {
+		handle.AutoAnalyzeMinCnt = 1000
+		tk.MustExec(fmt.Sprintf("set global tidb_auto_analyze_ratio = %v", oriRatio))
+		tk.MustExec("set global tidb_auto_analyze_concurrency = default")
+	}()
+	testkit.WithPruneMode(tk, variable.Static, func() {
+		h := s.do.StatsHandle()
+		tk.MustExec("use test")
+		tk.MustExec("create table t1 (a int)")
+		tk.MustExec("create table t2 (a int)")
+		tk.MustExec("create table t3 (a int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+		for i := 0; i < 3; i++ {
+			c.Assert(h.HandleDDLEvent(<-h.DDLEventCh()), IsNil)
+		}
+		tk.MustExec("insert into t1 values (1)" + strings.Repeat(", (1)", 9))
+		tk.MustExec("insert into t2 values (1)" + strings.Repeat(", (1)", 9))
+		tk.MustExec("insert into t3 values (1)" + strings.Repeat(", (1)", 9) + strings.Repeat(", (11)", 10))
+		c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+		tk.MustExec("analyze table t1, t2, t3")
+		is := s.do.InfoSchema()
+		getModifyCount := func(tblName string) int64 {
+			tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr(tblName))
+			c.Assert(err, IsNil)
+			return h.GetTableStats(tbl.Meta()).ModifyCount
+		}
+		getPartitionModifyCounts := func() []int64 {
+			tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t3"))
+			c.Assert(err, IsNil)
+			counts := make([]int64, 0, 2)
+			for _, def := range tbl.Meta().Partition.Definitions {
+				counts = append(counts, h.GetPartitionStats(tbl.Meta(), def.ID).ModifyCount)
+			}
+			return counts
+		}
+
+		// t2 is staler than t1, so it is analyzed first.
+		tk.MustExec("insert into t1 values (1)" + strings.Repeat(", (1)", 9))
+		tk.MustExec("insert into t2 values (1)" + strings.Repeat(", (1)", 29))
+		c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+		c.Assert(h.Update(is), IsNil)
+		c.Assert(h.HandleAutoAnalyze(is), IsTrue)
+		c.Assert(h.Update(is), IsNil)
+		c.Assert(getModifyCount("t1"), Equals, int64(10))
+		c.Assert(getModifyCount("t2"), Equals, int64(0))
+		c.Assert(h.HandleAutoAnalyze(is), IsTrue)
+		c.Assert(h.Update(is), IsNil)
+		c.Assert(getModifyCount("t1"), Equals, int64(0))
+		c.Assert(h.HandleAutoAnalyze(is), IsFalse)
+
+		// The tables are analyzed concurrently, but the partitions of the same table are not.
+		tk.MustExec("set global tidb_auto_analyze_concurrency = 4")
+		tk.MustExec("insert into t1 values (1)" + strings.Repeat(", (1)", 19))
+		tk.MustExec("insert into t3 values (1)" + strings.Repeat(", (1)", 9) + strings.Repeat(", (11)", 9))
+		c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+		c.Assert(h.Update(is), IsNil)
+		c.Assert(h.HandleAutoAnalyze(is), IsTrue)
+		c.Assert(h.Update(is), IsNil)
+		c.Assert(getModifyCount("t1"), Equals, int64(0))
+		c.Assert(getPartitionModifyCounts(), DeepEquals, []int64{0, 9})
+		c.Assert(h.HandleAutoAnalyze(is), IsTrue)
+		c.Assert(h.Update(is), IsNil)
+		c.Assert(getPartitionModifyCounts(), DeepEquals, []int64{0, 0})
+		c.Assert(h.HandleAutoAnalyze(is), IsFalse)
+	})
+}
+
+func (s *testSerialStatsSuite) TestAutoAnalyzeSkipLockedTables(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	oriRatio := tk.MustQuery("select @@tidb_auto_analyze_ratio").Rows()[0][0].(string)
+	handle.AutoAnalyzeMinCnt = 0
+	tk.MustExec("set global tidb_auto_analyze_ratio = 0.5")
+	defer func() {
+		handle.AutoAnalyzeMinCnt = 1000
+		tk.MustExec(fmt.Sprintf("set global tidb_auto_analyze_ratio = %v", oriRatio))
+	}()
+	h := s.do.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	c.Assert(h.HandleDDLEvent(<-h.DDLEventCh()), IsNil)
+	tk.MustExec("insert into t values (1)" + strings.Repeat(", (1)", 9))
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	tk.MustExec("analyze table t")
+	tk.MustExec("insert into t values (1)" + strings.Repeat(", (1)", 9))
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	is := s.do.InfoSchema()
+	c.Assert(h.Update(is), IsNil)
+
+	tk.MustExec("lock stats t")
+	c.Assert(h.HandleAutoAnalyze(is), IsFalse)
+	tk.MustExec("unlock stats t")
+	c.Assert(h.HandleAutoAnalyze(is), IsTrue)
+}
@@ -1112,17 +1112,47 @@ func (c *Column) equalRowCount(sctx sessionctx.Context, val types.Datum, encoded
 			return float64(rowcount), nil
 		}
 	}
-	// 2. try to find this value in bucket.Repeat(the last value in every bucket)
-	histCnt, matched := c.Histogram.equalRowCount(val, true)
-	if matched {
-		return histCnt, nil
-	}
-	// 3. use uniform distribution assumption for the rest (even when this value is not covered by the range of stats)
-	histNDV := float64(c.Histogram.NDV - int64(c.TopN.Num()))
-	if histNDV <= 0 {
-		return 0, nil
+	// 2. estimate it by the histogram
+	return outOfTopNEqualRowCount(&c.Histogram, c.TopN, val), nil
+}
+
+// TopNSkewMetrics returns the metrics which describe how skewed the values are, including the fraction of the non-null
+// rows covered by the TopN, and the ratio between the row count of the most frequent value and the average row count
+// of all the values.
+func TopNSkewMetrics(hg *Histogram, topN *TopN, statsVer int) (coverage, skewRatio float64) {
+	notNullCount := hg.notNullCount()
+	if statsVer >= Version2 {
+		// The rows of the TopN values are excluded from the histogram for the stats version 2.
+		notNullCount += float64(topN.TotalCount())
 	}
-	return c.Histogram.notNullCount() / histNDV, nil
+	if notNullCount <= 0 || hg.NDV <= 0 {
+		return 0, 0
+	}
+	coverage = math.Min(float64(topN.TotalCount())/notNullCount, 1)
+	skewRatio = float64(topN.maxCount()) / (notNullCount / float64(hg.NDV))
+	return coverage, skewRatio
+}
+
+// outOfTopNEqualRowCount estimates the row count of the value which is not in the TopN for the stats version 2, where
+// the rows of the TopN values are excluded from the histogram.
+func outOfTopNEqualRowCount(hg *Histogram, topN *TopN, val types.Datum) float64 {
+	// 1. try to find this value in bucket.Repeat(the last value in every bucket)
+	count, matched := hg.equalRowCount(val, true)
+	if !matched {
+		// 2. use uniform distribution assumption for the rest (even when this value is not covered by the range of stats),
+		// i.e. the remaining depth of the histogram averaged by the NDV outside the TopN.
+		histNDV := float64(hg.NDV - int64(topN.Num()))
+		if histNDV <= 0 {
+			return 0
+		}
+		count = hg.notNullCount() / histNDV
+	}
+	// The bucket NDV is estimated by the samples and may be much smaller than the real one, which makes the estimation
+	// of a skewed bucket larger than the values kept in the TopN.
+	if minCnt, ok := topN.minCount(); ok {
+		count = math.Min(count, float64(minCnt))
+	}
+	return count
 }
 
 // GetColumnRowCount estimates the row count by a slice of Range.
@@ -1306,17 +1336,8 @@ func (idx *Index) equalRowCount(b []byte, realtimeRowCount int64) float64 {
 	if found {
 		return float64(count)
 	}
-	// 2. try to find this value in bucket.Repeat(the last value in every bucket)
-	histCnt, matched := idx.Histogram.equalRowCount(val, true)
-	if matched {
-		return histCnt
-	}
-	// 3. use uniform distribution assumption for the rest (even when this value is not covered by the range of stats)
-	histNDV := float64(idx.Histogram.NDV - int64(idx.TopN.Num()))
-	if histNDV <= 0 {
-		return 0
-	}
-	return idx.Histogram.notNullCount() / histNDV
+	// 2. estimate it by the histogram
+	return outOfTopNEqualRowCount(&idx.Histogram, idx.TopN, val)
 }
 
 // QueryBytes is used to query the count of specified bytes.
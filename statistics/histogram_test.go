@@ -467,3 +467,43 @@ func TestMergeBucketNDV(t *testing.T) {
 		require.Equal(t, res.disjointNDV, tt.result.disjointNDV)
 	}
 }
+
+func TestOutOfTopNEqualRowCount(t *testing.T) {
+	sc := mock.NewContext().GetSessionVars().StmtCtx
+	tp := types.NewFieldType(mysql.TypeLonglong)
+	// The NDV of the first bucket is underestimated, so its average row count is larger than the values in the TopN.
+	hg := NewHistogram(1, 12, 0, 0, tp, 2, 0)
+	lower, upper := types.NewIntDatum(1), types.NewIntDatum(10)
+	hg.AppendBucketWithNDV(&lower, &upper, 30, 2, 2)
+	lower, upper = types.NewIntDatum(20), types.NewIntDatum(30)
+	hg.AppendBucketWithNDV(&lower, &upper, 40, 5, 5)
+	topN := NewTopN(2)
+	for _, item := range []struct {
+		val   int64
+		count uint64
+	}{{100, 20}, {200, 15}} {
+		data, err := codec.EncodeKey(sc, nil, types.NewIntDatum(item.val))
+		require.NoError(t, err)
+		topN.AppendTopN(data, item.count)
+	}
+
+	tests := []struct {
+		val   int64
+		count float64
+	}{
+		{5, 15},    // capped by the minimum count of the TopN
+		{10, 2},    // the upper bound of the bucket
+		{25, 1.25}, // (40 - 30 - 5) / (5 - 1)
+		{15, 4},    // not covered by the buckets: 40 / (12 - 2)
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.count, outOfTopNEqualRowCount(hg, topN, types.NewIntDatum(tt.val)), "value %d", tt.val)
+	}
+
+	coverage, skewRatio := TopNSkewMetrics(hg, topN, Version2)
+	require.InDelta(t, 35.0/75.0, coverage, 1e-9)
+	require.InDelta(t, 20/(75.0/12), skewRatio, 1e-9)
+	coverage, skewRatio = TopNSkewMetrics(hg, topN, Version1)
+	require.InDelta(t, 35.0/40.0, coverage, 1e-9)
+	require.InDelta(t, 20/(40.0/12), skewRatio, 1e-9)
+}
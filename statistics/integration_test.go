@@ -20,6 +20,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/domain"
@@ -175,8 +176,8 @@ func TestIncAnalyzeOnVer2(t *testing.T) {
 	))
 	// Two val in topn.
 	tk.MustQuery("show stats_topn where table_name = 't' and column_name = 'idx'").Check(testkit.Rows(
-		"test t  idx 1 2 3",
-		"test t  idx 1 4 4",
+		"test t  idx 1 2 3 0.7 1.6",
+		"test t  idx 1 4 4 0.7 1.6",
 	))
 }
 
@@ -471,3 +472,73 @@ func hasPseudoStats(rows [][]interface{}) bool {
 	}
 	return false
 }
+
+func TestHistogramCorrection(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	statistics.GlobalEstimateCorrections.Clear()
+	defer func() {
+		tk.MustExec("set global tidb_enable_histogram_correction = 0")
+		statistics.GlobalEstimateCorrections.Clear()
+	}()
+	h := dom.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set @@tidb_enable_pseudo_for_outdated_stats = 0")
+	tk.MustExec("create table t (a int, b int, index idx(a))")
+	values := make([]string, 0, 100)
+	for i := 1; i <= 100; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("analyze table t with 0 topn, 16 buckets")
+	// The rows inserted after analyze make the estimation of the range [10, 20] bad.
+	tk.MustExec("insert into t values (15, 15)" + strings.Repeat(", (15, 15)", 199))
+	require.NoError(t, h.DumpStatsDeltaToKV(handle.DumpAll))
+	require.NoError(t, h.Update(dom.InfoSchema()))
+
+	estRows := func() float64 {
+		rows := tk.MustQuery("explain format = 'brief' select * from t use index(idx) where a between 10 and 20").Rows()
+		for _, row := range rows {
+			if strings.Contains(row[0].(string), "IndexRangeScan") {
+				est, err := strconv.ParseFloat(row[1].(string), 64)
+				require.NoError(t, err)
+				return est
+			}
+		}
+		require.FailNow(t, "no index range scan in the plan")
+		return 0
+	}
+	originalEst := estRows()
+	require.Less(t, originalEst, 100.0)
+
+	// Nothing is recorded when the correction is disabled.
+	tk.MustQuery("select count(*) from t use index(idx) where a between 10 and 20").Check(testkit.Rows("211"))
+	require.Equal(t, 0, statistics.GlobalEstimateCorrections.Len())
+	require.Equal(t, originalEst, estRows())
+
+	tk.MustExec("set global tidb_enable_histogram_correction = 1")
+	tk.MustQuery("select count(*) from t use index(idx) where a between 10 and 20").Check(testkit.Rows("211"))
+	require.Greater(t, statistics.GlobalEstimateCorrections.Len(), 0)
+	require.InDelta(t, 211, estRows(), 1)
+
+	// The estimation is good now, so the correction stays the same.
+	tk.MustQuery("select count(*) from t use index(idx) where a between 10 and 20").Check(testkit.Rows("211"))
+	require.InDelta(t, 211, estRows(), 1)
+
+	// The statements which may stop scanning early are ignored.
+	statistics.GlobalEstimateCorrections.Clear()
+	tk.MustQuery("select * from t use index(idx) where a between 10 and 20 limit 1").Check(testkit.Rows("10 10"))
+	require.Equal(t, 0, statistics.GlobalEstimateCorrections.Len())
+
+	// The correction decays as time goes by.
+	tk.MustQuery("select count(*) from t use index(idx) where a between 10 and 20").Check(testkit.Rows("211"))
+	require.InDelta(t, 211, estRows(), 1)
+	originalHalfLife := statistics.CorrectionHalfLife
+	statistics.CorrectionHalfLife = time.Nanosecond
+	defer func() {
+		statistics.CorrectionHalfLife = originalHalfLife
+	}()
+	require.Equal(t, originalEst, estRows())
+}
@@ -18,6 +18,7 @@ import (
 	"container/heap"
 	"context"
 	"math/rand"
+	"unsafe"
 
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -43,6 +44,8 @@ type baseCollector struct {
 	FMSketches []*FMSketch
 	TotalSizes []int64
 	Count      int64
+	// MemSize is the estimated memory usage of the samples.
+	MemSize int64
 }
 
 // ReservoirRowSampleCollector collects the samples from the source and organize the samples by row.
@@ -65,6 +68,14 @@ type ReservoirRowSampleItem struct {
 	Handle  kv.Handle
 }
 
+// EmptyReservoirSampleItemSize is the size of an empty ReservoirRowSampleItem.
+const EmptyReservoirSampleItemSize = int64(unsafe.Sizeof(ReservoirRowSampleItem{}))
+
+// MemUsage returns the estimated memory usage of the sample item.
+func (i *ReservoirRowSampleItem) MemUsage() int64 {
+	return EmptyReservoirSampleItemSize + types.EstimatedMemUsage(i.Columns, 1)
+}
+
 // WeightedRowSampleHeap implements the Heap interface.
 type WeightedRowSampleHeap []*ReservoirRowSampleItem
 
@@ -267,10 +278,12 @@ func (s *baseCollector) FromProto(pbCollector *tipb.RowSampleCollector) {
 			data = append(data, types.NewBytesDatum(b))
 		}
 		// Directly copy the weight.
-		s.Samples = append(s.Samples, &ReservoirRowSampleItem{
+		sample := &ReservoirRowSampleItem{
 			Columns: data,
 			Weight:  pbSample.Weight,
-		})
+		}
+		s.Samples = append(s.Samples, sample)
+		s.MemSize += sample.MemUsage()
 	}
 }
 
@@ -282,12 +295,14 @@ func (s *ReservoirRowSampleCollector) Base() *baseCollector {
 func (s *ReservoirRowSampleCollector) sampleZippedRow(sample *ReservoirRowSampleItem) {
 	if len(s.Samples) < s.MaxSampleSize {
 		s.Samples = append(s.Samples, sample)
+		s.MemSize += sample.MemUsage()
 		if len(s.Samples) == s.MaxSampleSize {
 			heap.Init(&s.Samples)
 		}
 		return
 	}
 	if s.Samples[0].Weight < sample.Weight {
+		s.MemSize += sample.MemUsage() - s.Samples[0].MemUsage()
 		s.Samples[0] = sample
 		heap.Fix(&s.Samples, 0)
 	}
@@ -296,20 +311,24 @@ func (s *ReservoirRowSampleCollector) sampleZippedRow(sample *ReservoirRowSample
 func (s *ReservoirRowSampleCollector) sampleRow(row []types.Datum, rng *rand.Rand) {
 	weight := rng.Int63()
 	if len(s.Samples) < s.MaxSampleSize {
-		s.Samples = append(s.Samples, &ReservoirRowSampleItem{
+		sample := &ReservoirRowSampleItem{
 			Columns: row,
 			Weight:  weight,
-		})
+		}
+		s.Samples = append(s.Samples, sample)
+		s.MemSize += sample.MemUsage()
 		if len(s.Samples) == s.MaxSampleSize {
 			heap.Init(&s.Samples)
 		}
 		return
 	}
 	if s.Samples[0].Weight < weight {
-		s.Samples[0] = &ReservoirRowSampleItem{
+		sample := &ReservoirRowSampleItem{
 			Columns: row,
 			Weight:  weight,
 		}
+		s.MemSize += sample.MemUsage() - s.Samples[0].MemUsage()
+		s.Samples[0] = sample
 		heap.Fix(&s.Samples, 0)
 	}
 }
@@ -386,10 +405,12 @@ func (s *BernoulliRowSampleCollector) sampleRow(row []types.Datum, rng *rand.Ran
 	if rng.Float64() > s.SampleRate {
 		return
 	}
-	s.baseCollector.Samples = append(s.baseCollector.Samples, &ReservoirRowSampleItem{
+	sample := &ReservoirRowSampleItem{
 		Columns: row,
 		Weight:  0,
-	})
+	}
+	s.baseCollector.Samples = append(s.baseCollector.Samples, sample)
+	s.baseCollector.MemSize += sample.MemUsage()
 }
 
 // MergeCollector merges the collectors to a final one.
@@ -405,6 +426,7 @@ func (s *BernoulliRowSampleCollector) MergeCollector(subCollector RowSampleColle
 		s.TotalSizes[i] += subCollector.Base().TotalSizes[i]
 	}
 	s.baseCollector.Samples = append(s.baseCollector.Samples, subCollector.Base().Samples...)
+	s.baseCollector.MemSize += subCollector.Base().MemSize
 }
 
 // Base implements the interface RowSampleCollector.
@@ -193,6 +193,48 @@ func TestBuildStatsOnRowSample(t *testing.T) {
 		"num: 196 lower_bound: 805 upper_bound: 1000 repeats: 1 ndv: 0", hist.ToString(0))
 }
 
+func TestRowSampleCollectorMemSize(t *testing.T) {
+	rowNum := 100
+	batch := 5
+	sc := mock.NewContext().GetSessionVars().StmtCtx
+	sampleMemSize := func(samples WeightedRowSampleHeap) (sum int64) {
+		for _, sample := range samples {
+			sum += sample.MemUsage()
+		}
+		return sum
+	}
+	for _, sampleRate := range []float64{0, 0.5} {
+		sets := recordSetForDistributedSamplingTest(rowNum, batch)
+		rootRowCollector := NewRowSampleCollector(10, sampleRate, 1)
+		rootRowCollector.Base().FMSketches = append(rootRowCollector.Base().FMSketches, NewFMSketch(1000))
+		for i := 0; i < batch; i++ {
+			builder := &RowSampleBuilder{
+				Sc:              sc,
+				RecordSet:       sets[i],
+				ColsFieldType:   []*types.FieldType{types.NewFieldType(mysql.TypeLonglong)},
+				Collators:       make([]collate.Collator, 1),
+				ColGroups:       nil,
+				MaxSampleSize:   10,
+				SampleRate:      sampleRate,
+				MaxFMSketchSize: 1000,
+				Rng:             rand.New(rand.NewSource(time.Now().UnixNano())),
+			}
+			collector, err := builder.Collect()
+			require.NoError(t, err)
+			require.Equal(t, sampleMemSize(collector.Base().Samples), collector.Base().MemSize)
+
+			// The size of the collector decoded from the proto is estimated by the decoded samples.
+			pbCollector := NewRowSampleCollector(10, sampleRate, 1)
+			pbCollector.Base().FromProto(collector.Base().ToProto())
+			require.Equal(t, sampleMemSize(pbCollector.Base().Samples), pbCollector.Base().MemSize)
+
+			rootRowCollector.MergeCollector(pbCollector)
+			require.Equal(t, sampleMemSize(rootRowCollector.Base().Samples), rootRowCollector.Base().MemSize)
+			require.Nil(t, sets[i].Close())
+		}
+	}
+}
+
 type testSampleSuite struct {
 	count int
 	rs    sqlexec.RecordSet
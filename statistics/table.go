@@ -285,6 +285,21 @@ func (t *Table) IsOutdated() bool {
 	return false
 }
 
+// GetStatsHealthy calculates stats healthy if the table stats is not pseudo.
+// If the table stats is pseudo, it returns false.
+func (t *Table) GetStatsHealthy() (int64, bool) {
+	if t.Pseudo {
+		return 0, false
+	}
+	var healthy int64
+	if t.ModifyCount < t.Count {
+		healthy = int64((1.0 - float64(t.ModifyCount)/float64(t.Count)) * 100.0)
+	} else if t.ModifyCount == 0 {
+		healthy = 100
+	}
+	return healthy, true
+}
+
 // ColumnGreaterRowCount estimates the row count where the column greater than value.
 func (t *Table) ColumnGreaterRowCount(sctx sessionctx.Context, value types.Datum, colID int64) float64 {
 	c, ok := t.Columns[colID]
@@ -360,6 +375,9 @@ func (coll *HistColl) GetRowCountByIntColumnRanges(sctx sessionctx.Context, colI
 		return result, nil
 	}
 	result, err := c.GetColumnRowCount(sctx, intRanges, coll.Count, true)
+	if err == nil {
+		result = coll.correctColumnRowCount(c, intRanges, result)
+	}
 	if sc.EnableOptimizerCETrace {
 		CETraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, intRanges, "Column Stats", uint64(result))
 	}
@@ -378,6 +396,9 @@ func (coll *HistColl) GetRowCountByColumnRanges(sctx sessionctx.Context, colID i
 		return result, err
 	}
 	result, err := c.GetColumnRowCount(sctx, colRanges, coll.Count, false)
+	if err == nil {
+		result = coll.correctColumnRowCount(c, colRanges, result)
+	}
 	if sc.EnableOptimizerCETrace {
 		CETraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, colRanges, "Column Stats", uint64(result))
 	}
@@ -412,6 +433,9 @@ func (coll *HistColl) GetRowCountByIndexRanges(sctx sessionctx.Context, idxID in
 	} else {
 		result, err = idx.GetRowCount(sctx, coll, indexRanges, coll.Count)
 	}
+	if err == nil {
+		result = coll.correctIndexRowCount(sc, idx, indexRanges, result)
+	}
 	if sc.EnableOptimizerCETrace {
 		CETraceRange(sctx, coll.PhysicalID, colNames, indexRanges, "Index Stats", uint64(result))
 	}
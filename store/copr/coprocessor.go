@@ -27,6 +27,7 @@ import (
 
 	"github.com/cznic/mathutil"
 	"github.com/gogo/protobuf/proto"
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/coprocessor"
@@ -43,6 +44,7 @@ import (
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/paging"
+	"github.com/pingcap/tidb/util/resourcegroup"
 	"github.com/pingcap/tidb/util/trxevents"
 	"github.com/pingcap/tipb/go-tipb"
 	"github.com/tikv/client-go/v2/metrics"
@@ -660,6 +662,13 @@ func (worker *copIteratorWorker) handleTask(ctx context.Context, task *copTask,
 			worker.sendToRespCh(resp, respCh, false)
 		}
 	}()
+	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
+		span1 := span.Tracer().StartSpan("copIteratorWorker.handleTask", opentracing.ChildOf(span.Context()))
+		span1.SetTag("region_id", task.region.GetID())
+		span1.SetTag("store_addr", task.storeAddr)
+		defer span1.Finish()
+		ctx = opentracing.ContextWithSpan(ctx, span1)
+	}
 	remainTasks := []*copTask{task}
 	backoffermap := make(map[uint64]*Backoffer)
 	for len(remainTasks) > 0 {
@@ -728,9 +737,13 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 		}
 	}
 
+	priority := worker.req.Priority
+	if worker.req.RunawayCooldown != nil && worker.req.RunawayCooldown.Load() {
+		priority = kv.PriorityLow
+	}
 	req := tikvrpc.NewReplicaReadRequest(task.cmdType, &copReq, options.GetTiKVReplicaReadType(worker.req.ReplicaRead), &worker.replicaReadSeed, kvrpcpb.Context{
 		IsolationLevel: isolationLevelToPB(worker.req.IsolationLevel),
-		Priority:       priorityToPB(worker.req.Priority),
+		Priority:       priorityToPB(priority),
 		NotFillCache:   worker.req.NotFillCache,
 		RecordTimeStat: true,
 		RecordScanStat: true,
@@ -740,6 +753,9 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 		worker.req.ResourceGroupTagger(req)
 	}
 	req.StoreTp = getEndPointType(task.storeType)
+	if err := resourcegroup.GlobalController.OnRequest(bo.GetCtx(), worker.req.ResourceGroupName); err != nil {
+		return nil, errors.Trace(err)
+	}
 	startTime := time.Now()
 	if worker.kvclient.Stats == nil {
 		worker.kvclient.Stats = make(map[tikvrpc.CmdType]*tikv.RPCRuntimeStats)
@@ -776,6 +792,8 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 		return worker.handleCopStreamResult(bo, rpcCtx, resp.Resp.(*tikvrpc.CopStreamResponse), task, ch, costTime)
 	}
 
+	resourcegroup.GlobalController.OnResponse(worker.req.ResourceGroupName, len(resp.Resp.(*coprocessor.Response).Data))
+
 	if worker.req.Paging {
 		return worker.handleCopPagingResult(bo, rpcCtx, &copResponse{pbResp: resp.Resp.(*coprocessor.Response)}, task, ch, costTime)
 	}
@@ -972,6 +990,10 @@ func (worker *copIteratorWorker) handleCopResponse(bo *Backoffer, rpcCtx *tikv.R
 			zap.Uint64("regionID", task.region.GetID()),
 			zap.String("storeAddr", task.storeAddr),
 			zap.Error(err))
+		if worker.req.IsolationLevel == kv.RCCheckTS && strings.Contains(otherErr, "write conflict") {
+			// A newer version than the read timestamp is met, the statement should be retried with a new timestamp.
+			return nil, kv.ErrWriteConflict.FastGen("%s", otherErr)
+		}
 		return nil, errors.Trace(err)
 	}
 	// When the request is using streaming API, the `Range` is not nil.
@@ -1020,6 +1042,7 @@ func (worker *copIteratorWorker) handleCopResponse(bo *Backoffer, rpcCtx *tikv.R
 	}
 	resp.detail.ScanDetail = sd
 	resp.detail.TimeDetail = td
+	resp.detail.RUConsumed = resourcegroup.RequestRU(len(resp.pbResp.Data))
 	if resp.pbResp.IsCacheHit {
 		if cacheValue == nil {
 			return nil, errors.New("Internal error: received illegal TiKV response")
@@ -1311,10 +1334,15 @@ func priorityToPB(pri int) kvrpcpb.CommandPri {
 	}
 }
 
+// isolationLevelRCCheckTS is the RCCheckTS isolation level of the coprocessor requests, it's not defined by kvproto yet.
+const isolationLevelRCCheckTS kvrpcpb.IsolationLevel = 2
+
 func isolationLevelToPB(level kv.IsoLevel) kvrpcpb.IsolationLevel {
 	switch level {
 	case kv.RC:
 		return kvrpcpb.IsolationLevel_RC
+	case kv.RCCheckTS:
+		return isolationLevelRCCheckTS
 	case kv.SI:
 		return kvrpcpb.IsolationLevel_SI
 	default:
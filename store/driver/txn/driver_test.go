@@ -16,6 +16,7 @@ package txn
 import (
 	"testing"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/pingcap/tidb/kv"
 	"github.com/stretchr/testify/require"
@@ -59,3 +60,13 @@ func TestWriteConflictPrettyFormat(t *testing.T) {
 		kv.TxnRetryableMark
 	require.EqualError(t, newWriteConflictError(conflict), expectedStr)
 }
+
+func TestPessimisticLockNotFoundErr(t *testing.T) {
+	err := errors.New("tikv aborts txn: Error(Txn(Error(Mvcc(Error(PessimisticLockNotFound { start_ts: 408090278408224772, key: [116, 128] })))))")
+	err = extractKeyErr(err)
+	require.True(t, kv.ErrTxnRetryable.Equal(err))
+	require.True(t, kv.IsTxnRetryableError(err))
+
+	err = extractKeyErr(errors.New("tikv aborts txn: some other error"))
+	require.False(t, kv.ErrTxnRetryable.Equal(err))
+}
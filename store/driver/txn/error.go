@@ -150,9 +150,20 @@ func extractKeyErr(err error) error {
 		notFoundDetail := prettyLockNotFoundKey(e.Retryable)
 		return kv.ErrTxnRetryable.GenWithStackByArgs(e.Retryable + " " + notFoundDetail)
 	}
+	if isPessimisticLockNotFound(err) {
+		return kv.ErrTxnRetryable.GenWithStackByArgs(err.Error())
+	}
 	return derr.ToTiDBErr(err)
 }
 
+// isPessimisticLockNotFound checks whether the prewrite fails because the pessimistic lock is lost. The pessimistic
+// locks kept in the memory of TiKV (pessimistic-txn.in-memory) may be dropped when the region leader is transferred or
+// the region is merged, then the transaction is aborted and can be retried from the beginning.
+func isPessimisticLockNotFound(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "PessimisticLockNotFound") || strings.Contains(msg, "pessimistic lock not found")
+}
+
 func newWriteConflictError(conflict *kvrpcpb.WriteConflict) error {
 	if conflict == nil {
 		return kv.ErrWriteConflict
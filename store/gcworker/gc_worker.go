@@ -38,6 +38,7 @@ import (
 	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/domain/infosync"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/metrics"
@@ -563,6 +564,15 @@ func (w *GCWorker) calcNewSafePoint(ctx context.Context, now time.Time) (*time.T
 		return nil, 0, err
 	}
 	metrics.GCConfigGauge.WithLabelValues(gcLifeTimeKey).Set(lifeTime.Seconds())
+	// The tables whose GC life time is longer than the global one hold the safe point back.
+	if tableLifeTime, tableName := w.loadMaxTableGCLifeTime(ctx); tableLifeTime > *lifeTime {
+		logutil.Logger(ctx).Info("[gc worker] gc life time is extended by a table",
+			zap.String("uuid", w.uuid),
+			zap.String("table", tableName),
+			zap.Duration("globalLifeTime", *lifeTime),
+			zap.Duration("tableLifeTime", tableLifeTime))
+		*lifeTime = tableLifeTime
+	}
 
 	lastSafePoint, err := w.loadTime(gcSafePointKey)
 	if err != nil {
@@ -592,6 +602,37 @@ func (w *GCWorker) calcNewSafePoint(ctx context.Context, now time.Time) (*time.T
 	return &safePoint, safePointValue, nil
 }
 
+// loadMaxTableGCLifeTime returns the longest GC life time set on the tables and the name of the table, the
+// duration is 0 if no table sets it.
+func (w *GCWorker) loadMaxTableGCLifeTime(ctx context.Context) (time.Duration, string) {
+	se := createSession(w.store)
+	defer se.Close()
+	is, ok := se.GetInfoSchema().(infoschema.InfoSchema)
+	if !ok {
+		return 0, ""
+	}
+	var maxLifeTime time.Duration
+	var tableName string
+	for _, db := range is.AllSchemas() {
+		for _, tbl := range db.Tables {
+			if tbl.GCLifeTime == "" {
+				continue
+			}
+			lifeTime, err := time.ParseDuration(tbl.GCLifeTime)
+			if err != nil {
+				logutil.Logger(ctx).Warn("[gc worker] parse gc life time of table failed",
+					zap.String("table", db.Name.O+"."+tbl.Name.O), zap.String("gcLifeTime", tbl.GCLifeTime), zap.Error(err))
+				continue
+			}
+			if lifeTime > maxLifeTime {
+				maxLifeTime = lifeTime
+				tableName = db.Name.O + "." + tbl.Name.O
+			}
+		}
+	}
+	return maxLifeTime, tableName
+}
+
 // setGCWorkerServiceSafePoint sets the given safePoint as TiDB's service safePoint to PD, and returns the current minimal
 // service safePoint among all services.
 func (w *GCWorker) setGCWorkerServiceSafePoint(ctx context.Context, safePoint uint64) (uint64, error) {
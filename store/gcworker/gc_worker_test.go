@@ -416,6 +416,42 @@ func TestPrepareGC(t *testing.T) {
 	require.Equal(t, uint64(0), safepoint)
 }
 
+func TestTableGCLifeTime(t *testing.T) {
+	s, clean := createGCWorkerSuite(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int) gc_life_time = '2h'")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("alter table t2 gc_life_time = '3h'")
+	lifeTime, table := s.gcWorker.loadMaxTableGCLifeTime(context.Background())
+	require.Equal(t, 3*time.Hour, lifeTime)
+	require.Equal(t, "test.t2", table)
+
+	// The tables whose GC life time is longer than the global one hold the safe point back.
+	s.oracle.AddOffset(time.Hour * 4)
+	now, err := s.gcWorker.getOracleTime()
+	require.NoError(t, err)
+	ok, _, err := s.gcWorker.prepare()
+	require.NoError(t, err)
+	require.True(t, ok)
+	safePoint, err := s.gcWorker.loadTime(gcSafePointKey)
+	require.NoError(t, err)
+	timeEqual(t, safePoint.Add(time.Hour*3), now, 2*time.Second)
+
+	tk.MustExec("alter table t2 gc_life_time = ''")
+	s.oracle.AddOffset(time.Hour)
+	now, err = s.gcWorker.getOracleTime()
+	require.NoError(t, err)
+	ok, _, err = s.gcWorker.prepare()
+	require.NoError(t, err)
+	require.True(t, ok)
+	safePoint, err = s.gcWorker.loadTime(gcSafePointKey)
+	require.NoError(t, err)
+	timeEqual(t, safePoint.Add(time.Hour*2), now, 2*time.Second)
+}
+
 func TestStatusVars(t *testing.T) {
 	s, clean := createGCWorkerSuite(t)
 	defer clean()
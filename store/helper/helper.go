@@ -846,6 +846,79 @@ func (h *Helper) GetStoresStat() (*StoresStat, error) {
 	return &storesStat, err
 }
 
+// The APIs of the encryption at rest on the status server of the stores.
+const (
+	storeRotateMasterKeyPath  = "/encryption/master-key/rotate"
+	storeEncryptionStatusPath = "/encryption/status"
+)
+
+// StoreStateTombstone is the state name of the removed stores.
+const StoreStateTombstone = "Tombstone"
+
+// EncryptionStatus is the status of the encryption at rest of a store.
+type EncryptionStatus struct {
+	// Method is the method to encrypt the data files, it's "plaintext" if the encryption is disabled.
+	Method        string `json:"method"`
+	MasterKeyType string `json:"master_key_type"`
+	MasterKeyID   string `json:"master_key_id"`
+	// RotationState is the state of the last master key rotation, such as "rotating", "done" and "failed".
+	RotationState string `json:"rotation_state"`
+	// LastRotationTimestamp is the unix timestamp when the last rotation finished, 0 means never.
+	LastRotationTimestamp int64  `json:"last_rotation_timestamp"`
+	Error                 string `json:"error"`
+}
+
+// RotatePDMasterKey asks PD to rotate the master key of its encrypted metadata.
+func (h *Helper) RotatePDMasterKey() error {
+	pdAddrs, err := h.GetPDAddr()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return requestStatusServer(http.MethodPost, fmt.Sprintf("%s://%s%s", util.InternalHTTPSchema(), pdAddrs[0], pdapi.RotateMasterKey), nil)
+}
+
+// RotateStoreMasterKey asks the store to rotate its master key, the data keys are re-encrypted with the new
+// master key in the background. The progress is reported by GetStoreEncryptionStatus.
+func RotateStoreMasterKey(statusAddress string) error {
+	return requestStatusServer(http.MethodPost, fmt.Sprintf("%s://%s%s", util.InternalHTTPSchema(), statusAddress, storeRotateMasterKeyPath), nil)
+}
+
+// GetStoreEncryptionStatus gets the encryption status of the store.
+func GetStoreEncryptionStatus(statusAddress string) (*EncryptionStatus, error) {
+	status := &EncryptionStatus{}
+	err := requestStatusServer(http.MethodGet, fmt.Sprintf("%s://%s%s", util.InternalHTTPSchema(), statusAddress, storeEncryptionStatusPath), status)
+	return status, err
+}
+
+// requestStatusServer sends the request to the status server of PD or the stores, and decodes the response body
+// into res if it isn't nil.
+func requestStatusServer(method, url string, res interface{}) error {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := util.InternalHTTPClient().Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err = resp.Body.Close(); err != nil {
+			logutil.BgLogger().Error("close body failed", zap.Error(err))
+		}
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Errorf("request %s failed: %s %s", url, resp.Status, strings.TrimSpace(string(message)))
+	}
+	if res == nil {
+		return nil
+	}
+	return errors.Trace(json.NewDecoder(resp.Body).Decode(res))
+}
+
 // GetPDAddr return the PD Address.
 func (h *Helper) GetPDAddr() ([]string, error) {
 	etcd, ok := h.Store.(kv.EtcdBackend)
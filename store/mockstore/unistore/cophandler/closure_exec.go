@@ -21,7 +21,9 @@ import (
 	"sort"
 	"time"
 
+	"github.com/pingcap/badger"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/coprocessor"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
@@ -484,6 +486,9 @@ type closureExecutor struct {
 	counts []int64
 	ndvs   []int64
 	curNdv int64
+
+	// pagingRange is the range scanned by a paging request, it's nil for the other requests.
+	pagingRange *coprocessor.KeyRange
 }
 
 func pbChunkToChunk(pbChk tipb.Chunk, chk *chunk.Chunk, fieldTypes []*types.FieldType) error {
@@ -551,8 +556,27 @@ func (e *closureExecutor) execute() ([]tipb.Chunk, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if e.isolationLevel == isolationLevelRCCheckTS {
+		err = e.checkRangeNewerVersion()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	dbReader := e.dbReader
+	var processor closureProcessor = e.processor
+	var pagingProc *pagingProcessor
+	if e.pagingSize > 0 {
+		pagingProc = &pagingProcessor{closureProcessor: e.processor}
+		processor = pagingProc
+	}
 	for i, ran := range e.kvRanges {
+		scanLimit := math.MaxInt64
+		if pagingProc != nil {
+			if pagingProc.scanned >= int(e.pagingSize) {
+				break
+			}
+			scanLimit = int(e.pagingSize) - pagingProc.scanned
+		}
 		e.curNdv = 0
 		if e.isPointGetRange(ran) {
 			val, err := dbReader.Get(ran.StartKey, e.startTS)
@@ -566,16 +590,16 @@ func (e *closureExecutor) execute() ([]tipb.Chunk, error) {
 				e.counts[i]++
 				e.ndvs[i] = 1
 			}
-			err = e.processor.Process(ran.StartKey, val)
+			err = processor.Process(ran.StartKey, val)
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
 		} else {
 			oldCnt := e.rowCount
 			if e.scanCtx.desc {
-				err = dbReader.ReverseScan(ran.StartKey, ran.EndKey, math.MaxInt64, e.startTS, e.processor)
+				err = dbReader.ReverseScan(ran.StartKey, ran.EndKey, scanLimit, e.startTS, processor)
 			} else {
-				err = dbReader.Scan(ran.StartKey, ran.EndKey, math.MaxInt64, e.startTS, e.processor)
+				err = dbReader.Scan(ran.StartKey, ran.EndKey, scanLimit, e.startTS, processor)
 			}
 			delta := int64(e.rowCount - oldCnt)
 			if e.counts != nil {
@@ -590,10 +614,50 @@ func (e *closureExecutor) execute() ([]tipb.Chunk, error) {
 			break
 		}
 	}
+	if pagingProc != nil {
+		// The ranges may be not drained if the paging size is reached. If the limit is reached,
+		// the rest rows are not needed, so they are taken as drained too.
+		drained := pagingProc.scanned < int(e.pagingSize) || e.rowCount == e.limit
+		e.pagingRange = pagingProc.scannedRange(e.kvRanges, e.scanCtx.desc, drained)
+	}
 	err = e.processor.Finish()
 	return e.oldChunks, err
 }
 
+// pagingProcessor counts the scanned keys of a paging request and remembers the last one,
+// so the next page can be started from it.
+type pagingProcessor struct {
+	closureProcessor
+	scanned int
+	lastKey []byte
+}
+
+// Process implements the dbreader.ScanProcessor interface.
+func (p *pagingProcessor) Process(key, value []byte) error {
+	p.scanned++
+	p.lastKey = append(p.lastKey[:0], key...)
+	return p.closureProcessor.Process(key, value)
+}
+
+// scannedRange returns the range which has been scanned. If the ranges are drained,
+// the whole ranges are returned. The ranges are in reverse order for desc scan.
+func (p *pagingProcessor) scannedRange(ranges []kv.KeyRange, desc bool, drained bool) *coprocessor.KeyRange {
+	first, last := ranges[0], ranges[len(ranges)-1]
+	if desc {
+		first, last = last, first
+	}
+	scanned := &coprocessor.KeyRange{Start: first.StartKey, End: last.EndKey}
+	if drained {
+		return scanned
+	}
+	if desc {
+		scanned.Start = p.lastKey
+	} else {
+		scanned.End = kv.Key(p.lastKey).Next()
+	}
+	return scanned
+}
+
 func (e *closureExecutor) isPointGetRange(ran kv.KeyRange) bool {
 	if len(e.primaryCols) > 0 {
 		return false
@@ -629,6 +693,38 @@ func (e *closureExecutor) checkRangeLockForRange(ran kv.KeyRange) error {
 	return nil
 }
 
+// isolationLevelRCCheckTS is the RCCheckTS isolation level, which is not defined by kvproto yet. The data is read like
+// SI, but a write conflict is reported if any version newer than the start ts is met.
+const isolationLevelRCCheckTS kvrpcpb.IsolationLevel = 2
+
+func (e *closureExecutor) checkRangeNewerVersion() error {
+	txn := e.dbReader.GetTxn()
+	txn.SetReadTS(math.MaxUint64)
+	defer txn.SetReadTS(e.startTS)
+	for _, ran := range e.kvRanges {
+		err := e.checkRangeNewerVersionForRange(txn, ran)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *closureExecutor) checkRangeNewerVersionForRange(txn *badger.Txn, ran kv.KeyRange) error {
+	it := dbreader.NewIterator(txn, false, ran.StartKey, ran.EndKey)
+	defer it.Close()
+	for it.Seek(ran.StartKey); it.Valid(); it.Next() {
+		item := it.Item()
+		if exceedEndKey(item.Key(), ran.EndKey) {
+			break
+		}
+		if item.Version() > e.startTS {
+			return &ErrConflict{Key: item.KeyCopy(nil), StartTS: e.startTS, ConflictTS: item.Version()}
+		}
+	}
+	return nil
+}
+
 type countStarProcessor struct {
 	skipVal
 	*closureExecutor
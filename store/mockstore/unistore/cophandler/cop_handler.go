@@ -83,6 +83,10 @@ type dagContext struct {
 	dagReq        *tipb.DAGRequest
 	keyRanges     []*coprocessor.KeyRange
 	startTS       uint64
+	// isolationLevel is the isolation level of the request, see isolationLevelRCCheckTS.
+	isolationLevel kvrpcpb.IsolationLevel
+	// pagingSize is the max number of the keys scanned by a paging request, 0 for unlimited.
+	pagingSize uint64
 }
 
 // handleCopDAGRequest handles coprocessor DAG request.
@@ -118,7 +122,9 @@ func handleCopDAGRequest(dbReader *dbreader.DBReader, lockStore *lockstore.MemSt
 		return buildResp(nil, nil, nil, dagReq, err, dagCtx.sc.GetWarnings(), time.Since(startTime))
 	}
 	chunks, err := closureExec.execute()
-	return buildResp(chunks, closureExec, closureExec.ndvs, dagReq, err, dagCtx.sc.GetWarnings(), time.Since(startTime))
+	resp = buildResp(chunks, closureExec, closureExec.ndvs, dagReq, err, dagCtx.sc.GetWarnings(), time.Since(startTime))
+	resp.Range = closureExec.pagingRange
+	return resp
 }
 
 func buildDAG(reader *dbreader.DBReader, lockStore *lockstore.MemStore, req *coprocessor.Request) (*dagContext, *tipb.DAGRequest, error) {
@@ -137,13 +143,15 @@ func buildDAG(reader *dbreader.DBReader, lockStore *lockstore.MemStore, req *cop
 	sc := flagsToStatementContext(dagReq.Flags)
 	sc.TimeZone = time.FixedZone("UTC", int(dagReq.TimeZoneOffset))
 	ctx := &dagContext{
-		evalContext:   &evalContext{sc: sc},
-		dbReader:      reader,
-		lockStore:     lockStore,
-		dagReq:        dagReq,
-		keyRanges:     req.Ranges,
-		startTS:       req.StartTs,
-		resolvedLocks: req.Context.ResolvedLocks,
+		evalContext:    &evalContext{sc: sc},
+		dbReader:       reader,
+		lockStore:      lockStore,
+		dagReq:         dagReq,
+		keyRanges:      req.Ranges,
+		startTS:        req.StartTs,
+		resolvedLocks:  req.Context.ResolvedLocks,
+		isolationLevel: req.Context.IsolationLevel,
+		pagingSize:     req.PagingSize,
 	}
 	return ctx, dagReq, err
 }
@@ -287,8 +295,24 @@ func (e *ErrLocked) Error() string {
 	return fmt.Sprintf("key is locked, key: %q, Type: %v, primary: %q, startTS: %v", e.Key, e.LockType, e.Primary, e.StartTS)
 }
 
+// ErrConflict is returned when a version newer than the start ts is met by the request with the RCCheckTS isolation level.
+type ErrConflict struct {
+	Key        []byte
+	StartTS    uint64
+	ConflictTS uint64
+}
+
+// Error formats the conflict to a string.
+func (e *ErrConflict) Error() string {
+	return fmt.Sprintf("write conflict, key: %q, startTS: %v, conflictCommitTS: %v", e.Key, e.StartTS, e.ConflictTS)
+}
+
 func buildResp(chunks []tipb.Chunk, closureExecutor *closureExecutor, ndvs []int64, dagReq *tipb.DAGRequest, err error, warnings []stmtctx.SQLWarn, dur time.Duration) *coprocessor.Response {
 	resp := &coprocessor.Response{}
+	if conflict, ok := errors.Cause(err).(*ErrConflict); ok {
+		resp.OtherError = conflict.Error()
+		return resp
+	}
 	var counts []int64
 	if closureExecutor != nil {
 		counts = closureExecutor.counts
@@ -297,6 +297,10 @@ func CastValue(ctx sessionctx.Context, val types.Datum, col *model.ColumnInfo, r
 	if returnErr && err != nil {
 		return casted, err
 	}
+	if err != nil && col.Tp == mysql.TypeTiDBVectorFloat32 {
+		// An invalid vector can't be truncated to a valid one, so the error is always returned.
+		return casted, err
+	}
 	if err != nil && types.ErrTruncated.Equal(err) && col.Tp != mysql.TypeSet && col.Tp != mysql.TypeEnum {
 		str, err1 := val.ToString()
 		if err1 != nil {
@@ -680,6 +684,13 @@ func GetZeroValue(col *model.ColumnInfo) types.Datum {
 		}
 	case mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		d.SetString("", col.Collate)
+	case mysql.TypeTiDBVectorFloat32:
+		// The zero value of a vector is the zero vector with the dimension of the column.
+		dims := 0
+		if col.Flen > 0 {
+			dims = col.Flen
+		}
+		d.SetString(make(types.VectorFloat32, dims).String(), col.Collate)
 	case mysql.TypeDuration:
 		d.SetMysqlDuration(types.ZeroDuration)
 	case mysql.TypeDate:
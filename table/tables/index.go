@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/rowcodec"
 )
 
@@ -144,6 +145,19 @@ func (c *index) GenIndexKey(sc *stmtctx.StatementContext, indexedValues []types.
 // If the index is unique and there is an existing entry with the same key,
 // Create will return the existing entry's handle as the first return value, ErrKeyExists as the second return value.
 func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
+	if !c.idxInfo.MVIndex {
+		return c.create(sctx, txn, indexedValues, h, handleRestoreData, opts...)
+	}
+	// A multi-valued index is never unique, so there is no existing handle to return.
+	for _, vals := range mvIndexEntries(indexedValues) {
+		if _, err := c.create(sctx, txn, vals, h, handleRestoreData, opts...); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (c *index) create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
 	if c.Meta().Unique {
 		txn.CacheTableInfo(c.phyTblID, c.tblInfo)
 	}
@@ -243,6 +257,18 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 
 // Delete removes the entry for handle h and indexedValues from KV index.
 func (c *index) Delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
+	if !c.idxInfo.MVIndex {
+		return c.delete(sc, txn, indexedValues, h)
+	}
+	for _, vals := range mvIndexEntries(indexedValues) {
+		if err := c.delete(sc, txn, vals, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *index) delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
 	key, distinct, err := c.GenIndexKey(sc, indexedValues, h, nil)
 	if err != nil {
 		return err
@@ -255,6 +281,34 @@ func (c *index) Delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexed
 	return err
 }
 
+// mvIndexEntries expands the indexed JSON array of a multi-valued index, every
+// element of the array makes up an index entry. The array has been converted
+// to the element type by `CAST(... AS ... ARRAY)`, so only integers and strings
+// are expected here. A NULL array makes up no entry.
+func mvIndexEntries(indexedValues []types.Datum) [][]types.Datum {
+	if indexedValues[0].IsNull() {
+		return nil
+	}
+	array := indexedValues[0].GetMysqlJSON()
+	elemCount := array.GetElemCount()
+	entries := make([][]types.Datum, 0, elemCount)
+	for i := 0; i < elemCount; i++ {
+		vals := make([]types.Datum, len(indexedValues))
+		copy(vals, indexedValues)
+		elem := array.ArrayGetElem(i)
+		switch elem.TypeCode {
+		case json.TypeCodeInt64:
+			vals[0] = types.NewIntDatum(elem.GetInt64())
+		case json.TypeCodeUint64:
+			vals[0] = types.NewUintDatum(elem.GetUint64())
+		default:
+			vals[0] = types.NewStringDatum(string(elem.GetString()))
+		}
+		entries = append(entries, vals)
+	}
+	return entries
+}
+
 // Drop removes the KV index from store.
 func (c *index) Drop(txn kv.Transaction) error {
 	it, err := txn.Iter(c.prefix, c.prefix.PrefixNext())
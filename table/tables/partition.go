@@ -1215,6 +1215,16 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 	// The old and new data locate in different partitions.
 	// Remove record from old partition and add record to new partition.
 	if from != to {
+		if t.meta.HasGlobalIndex() {
+			// The entries of a global index are shared by all the partitions, adding the new record first would
+			// conflict with the entries of the old record, so the old record is removed first here.
+			err = t.GetPartition(from).RemoveRecord(ctx, h, currData)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			_, err = t.GetPartition(to).AddRecord(ctx, newData)
+			return errors.Trace(err)
+		}
 		_, err = t.GetPartition(to).AddRecord(ctx, newData)
 		if err != nil {
 			return errors.Trace(err)
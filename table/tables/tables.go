@@ -504,10 +504,20 @@ func (t *TableCommon) rebuildIndices(ctx sessionctx.Context, txn kv.Transaction,
 		if err := t.buildIndexForRow(ctx, h, newVs, newData, idx, txn, untouched, opts...); err != nil {
 			return err
 		}
+		if !untouched {
+			t.updateIndexWrites(ctx, idx)
+		}
 	}
 	return nil
 }
 
+// updateIndexWrites records a row written to the index in the transaction, which is collected into the index usage.
+func (t *TableCommon) updateIndexWrites(sctx sessionctx.Context, idx table.Index) {
+	if txnCtx := sctx.GetSessionVars().TxnCtx; txnCtx != nil {
+		txnCtx.UpdateIndexWritesForTable(t.physicalTableID, t.meta.ID, idx.Meta().ID)
+	}
+}
+
 // adjustRowValuesBuf adjust writeBufs.AddRowValues length, AddRowValues stores the inserting values that is used
 // by tablecodec.EncodeRow, the encoded row format is `id1, colval, id2, colval`, so the correct length is rowLen * 2. If
 // the inserting row has null value, AddRecord will skip it, so the rowLen will be different, so we need to adjust it.
@@ -920,6 +930,7 @@ func (t *TableCommon) addIndices(sctx sessionctx.Context, recordID kv.Handle, r
 			}
 			return nil, err
 		}
+		t.updateIndexWrites(sctx, v)
 	}
 	// save the buffer, multi rows insert can use it.
 	writeBufs.IndexValsBuf = indexVals
@@ -1233,6 +1244,7 @@ func (t *TableCommon) removeRowIndices(ctx sessionctx.Context, h kv.Handle, rec
 			}
 			return err
 		}
+		t.updateIndexWrites(ctx, v)
 	}
 	return nil
 }
@@ -578,7 +578,7 @@ func Unflatten(datum types.Datum, ft *types.FieldType, loc *time.Location) (type
 		datum.SetFloat32(float32(datum.GetFloat64()))
 		return datum, nil
 	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString, mysql.TypeTinyBlob,
-		mysql.TypeMediumBlob, mysql.TypeBlob, mysql.TypeLongBlob:
+		mysql.TypeMediumBlob, mysql.TypeBlob, mysql.TypeLongBlob, mysql.TypeTiDBVectorFloat32:
 		datum.SetString(datum.GetString(), ft.Collate)
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeYear, mysql.TypeInt24,
 		mysql.TypeLong, mysql.TypeLonglong, mysql.TypeDouble:
@@ -1369,6 +1369,21 @@ func DecodeHandleInUniqueIndexValue(data []byte, isCommonHandle bool) (kv.Handle
 	return h, nil
 }
 
+// DecodePartitionIDInGlobalIndexValue decodes the partition ID of the row from the value of a global index.
+func DecodePartitionIDInGlobalIndexValue(data []byte) (int64, error) {
+	var segs IndexValueSegments
+	if getIndexVersion(data) == 1 {
+		segs = SplitIndexValueForClusteredIndexVersion1(data)
+	} else {
+		segs = SplitIndexValue(data)
+	}
+	if segs.PartitionID == nil {
+		return 0, errors.New("partition ID not found in the global index value")
+	}
+	_, pid, err := codec.DecodeInt(segs.PartitionID)
+	return pid, errors.Trace(err)
+}
+
 func encodePartitionID(idxVal []byte, partitionID int64) []byte {
 	idxVal = append(idxVal, PartitionIDFlag)
 	idxVal = codec.EncodeInt(idxVal, partitionID)
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -580,3 +581,26 @@ func TestUntouchedIndexKValue(t *testing.T) {
 	untouchedIndexValue := []byte{0, 0, 0, 0, 0, 0, 0, 1, 49}
 	require.True(t, IsUntouchedIndexKValue(untouchedIndexKey, untouchedIndexValue))
 }
+
+func TestDecodePartitionIDInGlobalIndexValue(t *testing.T) {
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	tblInfo := &model.TableInfo{ID: 1, Columns: []*model.ColumnInfo{{ID: 1, Offset: 0, FieldType: *types.NewFieldType(mysql.TypeLonglong)}}}
+	idxInfo := &model.IndexInfo{ID: 1, Unique: true, Global: true, Columns: []*model.IndexColumn{{Offset: 0}}}
+	indexedValues := types.MakeDatums(1)
+	for _, untouched := range []bool{false, true} {
+		val, err := GenIndexValuePortal(sc, tblInfo, idxInfo, false, true, untouched, indexedValues, kv.IntHandle(10), 100, nil)
+		require.NoError(t, err)
+		h, err := DecodeHandleInUniqueIndexValue(val, false)
+		require.NoError(t, err)
+		require.Equal(t, int64(10), h.IntValue())
+		pid, err := DecodePartitionIDInGlobalIndexValue(val)
+		require.NoError(t, err)
+		require.Equal(t, int64(100), pid)
+	}
+
+	idxInfo.Global = false
+	val, err := GenIndexValuePortal(sc, tblInfo, idxInfo, false, true, false, indexedValues, kv.IntHandle(10), 100, nil)
+	require.NoError(t, err)
+	_, err = DecodePartitionIDInGlobalIndexValue(val)
+	require.Error(t, err)
+}
@@ -46,6 +46,7 @@ import (
 	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/server"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
@@ -53,6 +54,7 @@ import (
 	"github.com/pingcap/tidb/store/driver"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/audit"
 	"github.com/pingcap/tidb/util/deadlockhistory"
 	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/domainutil"
@@ -62,10 +64,12 @@ import (
 	"github.com/pingcap/tidb/util/printer"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/signal"
+	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/pingcap/tidb/util/sys/linux"
 	storageSys "github.com/pingcap/tidb/util/sys/storage"
 	"github.com/pingcap/tidb/util/systimemon"
 	"github.com/pingcap/tidb/util/topsql"
+	"github.com/pingcap/tidb/util/tracing"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/push"
 	"github.com/tikv/client-go/v2/tikv"
@@ -190,6 +194,8 @@ func main() {
 	setGlobalVars()
 	setCPUAffinity()
 	setupLog()
+	setupAudit()
+	setupStmtSummaryPersistence()
 	setupTracing() // Should before createServer and after setup config.
 	printInfo()
 	setupBinlogClient()
@@ -209,6 +215,11 @@ func main() {
 		cleanup(svr, storage, dom, graceful)
 		close(exited)
 	})
+	signal.SetupReloadSignalHandler(func() {
+		if err := svr.ReloadTLS(); err != nil {
+			log.Warn("reload TLS certificates failed", zap.Error(err))
+		}
+	})
 	topsql.SetupTopSQL()
 	terror.MustNil(svr.Run())
 	<-exited
@@ -577,6 +588,7 @@ func setGlobalVars() {
 	variable.SetSysVar(variable.TiDBMemQuotaQuery, strconv.FormatInt(cfg.MemQuotaQuery, 10))
 	variable.SetSysVar(variable.LowerCaseTableNames, strconv.Itoa(cfg.LowerCaseTableNames))
 	variable.SetSysVar(variable.LogBin, variable.BoolToOnOff(cfg.Binlog.Enable))
+	variable.SetSysVar(variable.DisconnectOnExpiredPassword, variable.BoolToOnOff(cfg.Security.DisconnectOnExpiredPassword))
 	variable.SetSysVar(variable.Port, fmt.Sprintf("%d", cfg.Port))
 	cfg.Socket = strings.Replace(cfg.Socket, "{Port}", fmt.Sprintf("%d", cfg.Port), 1)
 	variable.SetSysVar(variable.Socket, cfg.Socket)
@@ -631,6 +643,8 @@ func setGlobalVars() {
 	tikv.SetStoreLivenessTimeout(t)
 	parsertypes.TiDBStrictIntegerDisplayWidth = cfg.DeprecateIntegerDisplayWidth
 	deadlockhistory.GlobalDeadlockHistory.Resize(cfg.PessimisticTxn.DeadlockHistoryCapacity)
+	txninfo.Recorder.ResizeSummaries(cfg.TrxSummary.TransactionSummaryCapacity)
+	txninfo.Recorder.SetMinDuration(time.Duration(cfg.TrxSummary.TransactionSummaryMinDuration) * time.Millisecond)
 }
 
 func setupLog() {
@@ -642,6 +656,48 @@ func setupLog() {
 	util.InternalHTTPClient()
 }
 
+func setupAudit() {
+	cfg := config.GetGlobalConfig()
+	if !cfg.Audit.Enable {
+		return
+	}
+	var sink audit.Sink
+	var err error
+	switch cfg.Audit.Sink {
+	case config.AuditSinkFile:
+		sink, err = audit.NewFileSink(cfg.Audit.File.FileLogConfig)
+	case config.AuditSinkSyslog:
+		sink, err = audit.NewSyslogSink(cfg.Audit.SyslogNetwork, cfg.Audit.SyslogAddress)
+	case config.AuditSinkGRPC:
+		clusterSecurity := cfg.Security.ClusterSecurity()
+		tlsConfig, tlsErr := clusterSecurity.ToTLSConfig()
+		terror.MustNil(tlsErr)
+		sink, err = audit.NewGRPCSink(cfg.Audit.GRPCAddress, tlsConfig)
+	}
+	if err != nil {
+		log.Fatal("failed to create the audit log sink", zap.String("sink", cfg.Audit.Sink), zap.Error(err))
+	}
+	rules := make([]audit.Rule, 0, len(cfg.Audit.Rules))
+	for _, rule := range cfg.Audit.Rules {
+		rules = append(rules, audit.Rule{Users: rule.Users, DBs: rule.DBs, Classes: rule.Classes})
+	}
+	logger, err := audit.NewLogger(sink, rules, cfg.Audit.Redact)
+	if err != nil {
+		log.Fatal("invalid audit log rules", zap.Error(err))
+	}
+	audit.SetGlobalLogger(logger)
+}
+
+func setupStmtSummaryPersistence() {
+	cfg := config.GetGlobalConfig()
+	if !cfg.StmtSummary.EnablePersistent {
+		return
+	}
+	if err := stmtsummary.StmtSummaryByDigestMap.SetupPersistence(cfg.StmtSummary.File.FileLogConfig); err != nil {
+		log.Fatal("failed to set up the statement summary persistence", zap.Error(err))
+	}
+}
+
 func printInfo() {
 	// Make sure the TiDB info is always printed.
 	level := log.GetLevel()
@@ -687,8 +743,21 @@ func setupMetrics() {
 	pushMetric(cfg.Status.MetricsAddr, time.Duration(cfg.Status.MetricsInterval)*time.Second)
 }
 
+// otlpExporter exports the spans when `opentracing.reporter.otlp-endpoint` is set.
+var otlpExporter *tracing.OTLPExporter
+
 func setupTracing() {
 	cfg := config.GetGlobalConfig()
+	if cfg.OpenTracing.Enable && cfg.OpenTracing.Reporter.OTLPEndpoint != "" {
+		sampleRate := 1.0
+		switch cfg.OpenTracing.Sampler.Type {
+		case "const", "probabilistic":
+			sampleRate = cfg.OpenTracing.Sampler.Param
+		}
+		otlpExporter = tracing.NewOTLPExporter(cfg.OpenTracing.Reporter.OTLPEndpoint, "TiDB", cfg.OpenTracing.Reporter.BufferFlushInterval)
+		opentracing.SetGlobalTracer(tracing.NewOTLPTracer(otlpExporter, sampleRate))
+		return
+	}
 	tracingCfg := cfg.OpenTracing.ToTracingConfig()
 	tracingCfg.ServiceName = "TiDB"
 	tracer, _, err := tracingCfg.NewTracer()
@@ -713,9 +782,16 @@ func cleanup(svr *server.Server, storage kv.Storage, dom *domain.Domain, gracefu
 		svr.TryGracefulDown()
 	}
 	plugin.Shutdown(context.Background())
+	if logger := audit.SetGlobalLogger(nil); logger != nil {
+		terror.Log(logger.Close())
+	}
+	stmtsummary.StmtSummaryByDigestMap.ClosePersistence()
 	closeDomainAndStorage(storage, dom)
 	disk.CleanUp()
 	topsql.Close()
+	if otlpExporter != nil {
+		otlpExporter.Close()
+	}
 }
 
 func stringToList(repairString string) []string {
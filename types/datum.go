@@ -15,6 +15,7 @@
 package types
 
 import (
+	gjson "encoding/json"
 	"fmt"
 	"math"
 	"sort"
@@ -420,6 +421,67 @@ func (d Datum) String() string {
 	return fmt.Sprintf("%v %v", t, v)
 }
 
+// jsonDatum is the JSON representation of Datum, the time and decimal values are kept in their own fields.
+type jsonDatum struct {
+	K         byte   `json:"k"`
+	Decimal   uint16 `json:"decimal,omitempty"`
+	Length    uint32 `json:"length,omitempty"`
+	I         int64  `json:"i,omitempty"`
+	Collation string `json:"collation,omitempty"`
+	B         []byte `json:"b,omitempty"`
+	Time      uint64 `json:"time,omitempty"`
+	MyDecimal string `json:"mydecimal,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. It's used to migrate the user variables along with the session.
+func (d Datum) MarshalJSON() ([]byte, error) {
+	jd := &jsonDatum{
+		K:         d.k,
+		Decimal:   d.decimal,
+		Length:    d.length,
+		I:         d.i,
+		Collation: d.collation,
+		B:         d.b,
+	}
+	switch d.k {
+	case KindMysqlTime:
+		jd.Time = uint64(d.GetMysqlTime().coreTime)
+	case KindMysqlDecimal:
+		jd.MyDecimal = d.GetMysqlDecimal().String()
+	case KindInterface:
+		return nil, errors.Errorf("unsupported datum kind %d to marshal", d.k)
+	}
+	return gjson.Marshal(jd)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *Datum) UnmarshalJSON(data []byte) error {
+	var jd jsonDatum
+	if err := gjson.Unmarshal(data, &jd); err != nil {
+		return errors.Trace(err)
+	}
+	d.k = jd.K
+	d.decimal = jd.Decimal
+	d.length = jd.Length
+	d.i = jd.I
+	d.collation = jd.Collation
+	d.b = jd.B
+	d.x = nil
+	switch jd.K {
+	case KindMysqlTime:
+		d.x = Time{coreTime: coreTime(jd.Time)}
+	case KindMysqlDecimal:
+		dec := new(MyDecimal)
+		if err := dec.FromString([]byte(jd.MyDecimal)); err != nil {
+			return errors.Trace(err)
+		}
+		d.x = dec
+	case KindInterface:
+		return errors.Errorf("unsupported datum kind %d to unmarshal", jd.K)
+	}
+	return nil
+}
+
 // GetValue gets the value of the datum of any kind.
 func (d *Datum) GetValue() interface{} {
 	switch d.k {
@@ -855,6 +917,8 @@ func (d *Datum) ConvertTo(sc *stmtctx.StatementContext, target *FieldType) (Datu
 		return d.convertToMysqlSet(sc, target)
 	case mysql.TypeJSON:
 		return d.convertToMysqlJSON(sc, target)
+	case mysql.TypeTiDBVectorFloat32:
+		return d.convertToVectorFloat32(sc, target)
 	case mysql.TypeNull:
 		return Datum{}, nil
 	default:
@@ -1586,6 +1650,23 @@ func (d *Datum) convertToMysqlJSON(sc *stmtctx.StatementContext, target *FieldTy
 	return ret, errors.Trace(err)
 }
 
+func (d *Datum) convertToVectorFloat32(sc *stmtctx.StatementContext, target *FieldType) (ret Datum, err error) {
+	switch d.k {
+	case KindString, KindBytes:
+		var v VectorFloat32
+		if v, err = ParseVectorFloat32(d.GetString()); err != nil {
+			return ret, err
+		}
+		if err = v.CheckDimsFitColumn(target.Flen); err != nil {
+			return ret, err
+		}
+		ret.SetString(v.String(), target.Collate)
+		return ret, nil
+	default:
+		return invalidConv(d, target.Tp)
+	}
+}
+
 // ToBool converts to a bool.
 // We will use 1 for true, and 0 for false.
 func (d *Datum) ToBool(sc *stmtctx.StatementContext) (int64, error) {
@@ -335,6 +335,38 @@ func TestCloneDatum(t *testing.T) {
 	}
 }
 
+func TestMarshalDatum(t *testing.T) {
+	tests := []Datum{
+		{},
+		NewIntDatum(-72),
+		NewUintDatum(math.MaxUint64),
+		NewFloat64Datum(1.5),
+		NewCollationStringDatum("abcd", "utf8mb4_general_ci"),
+		NewBytesDatum([]byte("abcd")),
+		NewDecimalDatum(newMyDecimal("123.4500", t)),
+		NewDurationDatum(Duration{Duration: time.Hour + time.Millisecond, Fsp: 3}),
+		NewTimeDatum(NewTime(FromGoTime(time.Date(2022, 1, 2, 3, 4, 5, 6000, time.UTC)), mysql.TypeTimestamp, 6)),
+		NewJSONDatum(json.CreateBinary("abc")),
+		NewMysqlEnumDatum(Enum{Name: "a", Value: 1}),
+	}
+
+	sc := new(stmtctx.StatementContext)
+	for _, tt := range tests {
+		data, err := tt.MarshalJSON()
+		require.NoError(t, err)
+		var d Datum
+		require.NoError(t, d.UnmarshalJSON(data))
+		require.Equal(t, tt.Kind(), d.Kind())
+		require.Equal(t, tt.Collation(), d.Collation())
+		res, err := tt.Compare(sc, &d, collate.GetBinaryCollator())
+		require.NoError(t, err)
+		require.Equal(t, 0, res, tt.String())
+	}
+
+	_, err := NewDatum([]int{1}).MarshalJSON()
+	require.Error(t, err)
+}
+
 func newTypeWithFlag(tp byte, flag uint) *FieldType {
 	t := NewFieldType(tp)
 	t.Flag |= flag
@@ -84,6 +84,10 @@ var (
 	ErrWrongValue = dbterror.ClassTypes.NewStdErr(mysql.ErrTruncatedWrongValue, mysql.MySQLErrName[mysql.ErrWrongValue])
 	// ErrWrongValueForType is returned when the input value is in wrong format for function.
 	ErrWrongValueForType = dbterror.ClassTypes.NewStdErr(mysql.ErrWrongValueForType, mysql.MySQLErrName[mysql.ErrWrongValueForType])
+	// ErrVectorDimensionMismatch is returned when the dimensions of vectors don't match.
+	ErrVectorDimensionMismatch = dbterror.ClassTypes.NewStd(mysql.ErrVectorDimensionMismatch)
+	// ErrVectorDimensionOutOfRange is returned when the dimension of a VECTOR column is out of range.
+	ErrVectorDimensionOutOfRange = dbterror.ClassTypes.NewStd(mysql.ErrVectorDimensionOutOfRange)
 	// ErrPartitionStatsMissing is returned when the partition-level stats is missing and the build global-level stats fails.
 	// Put this error here is to prevent `import cycle not allowed`.
 	ErrPartitionStatsMissing = dbterror.ClassTypes.NewStd(mysql.ErrPartitionStatsMissing)
@@ -46,6 +46,8 @@ func TestError(t *testing.T) {
 		ErrTruncatedWrongVal,
 		ErrInvalidWeekModeFormat,
 		ErrWrongValue,
+		ErrVectorDimensionMismatch,
+		ErrVectorDimensionOutOfRange,
 	}
 
 	for _, err := range kvErrs {
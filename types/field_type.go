@@ -347,6 +347,14 @@ func DefaultCharsetForType(tp byte) (string, string) {
 // The result field type of the case expression is the merged type of the two when clause.
 // See https://github.com/mysql/mysql-server/blob/8.0/sql/field.cc#L1042
 func MergeFieldType(a byte, b byte) byte {
+	// The VECTOR type is a TiDB extension that isn't in the merge rules of MySQL.
+	// It is merged as a vector only with itself or NULL, and as a LONGBLOB otherwise.
+	if a == mysql.TypeTiDBVectorFloat32 || b == mysql.TypeTiDBVectorFloat32 {
+		if a == b || a == mysql.TypeNull || b == mysql.TypeNull {
+			return mysql.TypeTiDBVectorFloat32
+		}
+		return mysql.TypeLongBlob
+	}
 	ia := getFieldTypeIndex(a)
 	ib := getFieldTypeIndex(b)
 	return fieldTypeMergeRules[ia][ib]
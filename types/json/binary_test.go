@@ -92,6 +92,29 @@ func TestBinaryJSONExtract(t *testing.T) {
 	}
 }
 
+func TestBinaryJSONExtractAll(t *testing.T) {
+	bj := mustParseBinaryFromString(t, `{"a": [1, [2, 3], {"b": 4}], "c": "d"}`)
+	var tests = []struct {
+		pathExprString string
+		expected       []string
+	}{
+		{"$.a", []string{`[1, [2, 3], {"b": 4}]`}},
+		{"$.a[*]", []string{"1", "[2, 3]", `{"b": 4}`}},
+		{"$.a[1]", []string{"[2, 3]"}},
+		{"$.*", []string{`[1, [2, 3], {"b": 4}]`, `"d"`}},
+		{"$.x", nil},
+	}
+	for _, test := range tests {
+		pe, err := ParseJSONPathExpr(test.pathExprString)
+		require.NoError(t, err)
+		var result []string
+		for _, v := range bj.ExtractAll(pe) {
+			result = append(result, v.String())
+		}
+		require.Equal(t, test.expected, result, test.pathExprString)
+	}
+}
+
 func TestBinaryJSONType(t *testing.T) {
 	var tests = []struct {
 		in  string
@@ -0,0 +1,122 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxVectorDimension is the max dimension of the VECTOR type.
+const MaxVectorDimension = 16383
+
+// VectorFloat32 is the value of the VECTOR type, whose elements are float32.
+// It is stored in its text form, such as '[1,2.5,3]'.
+type VectorFloat32 []float32
+
+// ParseVectorFloat32 parses a vector from its text form, such as '[1, 2.5, 3]'.
+func ParseVectorFloat32(s string) (VectorFloat32, error) {
+	str := strings.TrimSpace(s)
+	if len(str) < 2 || str[0] != '[' || str[len(str)-1] != ']' {
+		return nil, ErrTruncatedWrongVal.GenWithStackByArgs("vector", s)
+	}
+	str = strings.TrimSpace(str[1 : len(str)-1])
+	if len(str) == 0 {
+		return VectorFloat32{}, nil
+	}
+	elems := strings.Split(str, ",")
+	if len(elems) > MaxVectorDimension {
+		return nil, ErrTruncatedWrongVal.GenWithStackByArgs("vector", s)
+	}
+	v := make(VectorFloat32, 0, len(elems))
+	for _, elem := range elems {
+		f, err := strconv.ParseFloat(strings.TrimSpace(elem), 32)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, ErrTruncatedWrongVal.GenWithStackByArgs("vector", s)
+		}
+		v = append(v, float32(f))
+	}
+	return v, nil
+}
+
+// Len returns the dimension of the vector.
+func (v VectorFloat32) Len() int {
+	return len(v)
+}
+
+// String returns the canonical text form of the vector.
+func (v VectorFloat32) String() string {
+	buf := make([]byte, 0, 2+len(v)*8)
+	buf = append(buf, '[')
+	for i, f := range v {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = strconv.AppendFloat(buf, float64(f), 'g', -1, 32)
+	}
+	buf = append(buf, ']')
+	return string(buf)
+}
+
+// CheckDimsFitColumn checks whether the vector fits a VECTOR column with the dimension flen.
+// A VECTOR column without a dimension accepts vectors of any dimension.
+func (v VectorFloat32) CheckDimsFitColumn(flen int) error {
+	if flen != UnspecifiedLength && len(v) != flen {
+		return ErrVectorDimensionMismatch.GenWithStackByArgs(flen, len(v))
+	}
+	return nil
+}
+
+func (v VectorFloat32) checkDims(b VectorFloat32) error {
+	if len(v) != len(b) {
+		return ErrVectorDimensionMismatch.GenWithStackByArgs(len(v), len(b))
+	}
+	return nil
+}
+
+// L2Distance returns the euclidean distance between two vectors.
+func (v VectorFloat32) L2Distance(b VectorFloat32) (float64, error) {
+	if err := v.checkDims(b); err != nil {
+		return 0, err
+	}
+	var sum float64
+	for i := range v {
+		diff := float64(v[i]) - float64(b[i])
+		sum += diff * diff
+	}
+	return math.Sqrt(sum), nil
+}
+
+// CosineDistance returns the cosine distance between two vectors, which is 1 - cosine similarity.
+// isNull is true if any of the vectors is a zero vector, because the cosine distance is undefined.
+func (v VectorFloat32) CosineDistance(b VectorFloat32) (distance float64, isNull bool, err error) {
+	if err := v.checkDims(b); err != nil {
+		return 0, false, err
+	}
+	var dot, normV, normB float64
+	for i := range v {
+		dot += float64(v[i]) * float64(b[i])
+		normV += float64(v[i]) * float64(v[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normV == 0 || normB == 0 {
+		return 0, true, nil
+	}
+	similarity := dot / (math.Sqrt(normV) * math.Sqrt(normB))
+	// Clamp the similarity to [-1, 1] to tolerate the error of floating point arithmetic.
+	similarity = math.Max(-1, math.Min(1, similarity))
+	return 1 - similarity, false, nil
+}
@@ -0,0 +1,287 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// The classes of the audit events.
+const (
+	// ClassConnection is the connections, the disconnections and the failed logins.
+	ClassConnection = "connection"
+	// ClassPrivilege is the statements which change the users, the roles or the privileges.
+	ClassPrivilege = "privilege"
+	// ClassDDL is the DDL statements.
+	ClassDDL = "ddl"
+	// ClassDML is the statements which read or write the tables.
+	ClassDML = "dml"
+	// ClassOther is the rest statements, such as SET and SHOW.
+	ClassOther = "other"
+)
+
+// DefaultClasses are the classes recorded when there is no rule.
+var DefaultClasses = []string{ClassConnection, ClassPrivilege, ClassDDL}
+
+// The events of ClassConnection, the events of the other classes are the statement types.
+const (
+	EventConnect    = "Connect"
+	EventDisconnect = "Disconnect"
+	EventChangeUser = "ChangeUser"
+	// EventReject is a failed login or a connection rejected before the login.
+	EventReject = "Reject"
+)
+
+// eventChanSize is the max count of the events waiting to be written, the connections are blocked when it's
+// exceeded so that no event is lost.
+const eventChanSize = 4096
+
+// Event is an audit event.
+type Event struct {
+	Time   time.Time `json:"time"`
+	ConnID uint64    `json:"conn_id"`
+	// Seq is the sequence number of the events of a connection, starting from 1. The events of a connection are
+	// written in the order of Seq.
+	Seq   uint64 `json:"seq"`
+	Class string `json:"class"`
+	Event string `json:"event"`
+	User  string `json:"user"`
+	Host  string `json:"host"`
+	// DB is the current database.
+	DB string `json:"db,omitempty"`
+	// Tables are the accessed tables in the form of db.table.
+	Tables []string `json:"tables,omitempty"`
+	SQL    string   `json:"sql,omitempty"`
+	// ErrCode is the MySQL error code returned to the client, 0 means success.
+	ErrCode uint16 `json:"err_code,omitempty"`
+	// Err is the error message, it's omitted if the statements are redacted because it may contain the literals.
+	Err string `json:"err,omitempty"`
+}
+
+// dbs returns the databases of the event.
+func (e *Event) dbs() []string {
+	dbs := make([]string, 0, len(e.Tables)+1)
+	if e.DB != "" {
+		dbs = append(dbs, e.DB)
+	}
+	for _, table := range e.Tables {
+		if idx := strings.IndexByte(table, '.'); idx > 0 {
+			dbs = append(dbs, table[:idx])
+		}
+	}
+	return dbs
+}
+
+// Rule selects the events to be recorded, an event matches the rule if it matches all the non-empty fields.
+type Rule struct {
+	// Users are the user names, "%" matches all the users.
+	Users []string
+	// DBs are the database names, an event matches it if its current database or any accessed table is in it.
+	DBs []string
+	// Classes are the event classes.
+	Classes []string
+}
+
+// Validate checks the classes of the rule.
+func (r *Rule) Validate() error {
+	for i, class := range r.Classes {
+		class = strings.ToLower(class)
+		switch class {
+		case ClassConnection, ClassPrivilege, ClassDDL, ClassDML, ClassOther:
+			r.Classes[i] = class
+		default:
+			return errors.Errorf("unknown audit event class '%s'", class)
+		}
+	}
+	return nil
+}
+
+func (r *Rule) match(class, user string, dbs []string) bool {
+	if len(r.Classes) > 0 && !containsFold(r.Classes, class) {
+		return false
+	}
+	if len(r.Users) > 0 && !containsFold(r.Users, "%") && !containsFold(r.Users, user) {
+		return false
+	}
+	if len(r.DBs) == 0 {
+		return true
+	}
+	for _, db := range dbs {
+		if containsFold(r.DBs, db) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Logger filters the audit events and writes them to the sink in the background. The events are written in
+// the order they are logged, so the events of a connection keep their order.
+type Logger struct {
+	sink  Sink
+	rules []Rule
+	// redact replaces the literals in the SQL with "?", the SQL is always redacted if tidb_log_desensitization is on.
+	redact  bool
+	eventCh chan *Event
+	wg      sync.WaitGroup
+
+	mu sync.Mutex
+	// seqs is the sequence number of the last event of each connection.
+	seqs   map[uint64]uint64
+	closed bool
+}
+
+// NewLogger creates a Logger and starts writing to the sink. The events of DefaultClasses are recorded if there
+// is no rule.
+func NewLogger(sink Sink, rules []Rule, redact bool) (*Logger, error) {
+	if len(rules) == 0 {
+		rules = []Rule{{Classes: DefaultClasses}}
+	}
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+	l := &Logger{
+		sink:    sink,
+		rules:   rules,
+		redact:  redact,
+		eventCh: make(chan *Event, eventChanSize),
+		seqs:    make(map[uint64]uint64),
+	}
+	l.wg.Add(1)
+	go l.run()
+	return l, nil
+}
+
+func (l *Logger) run() {
+	defer l.wg.Done()
+	f, canFlush := l.sink.(flusher)
+	for e := range l.eventCh {
+		if err := l.sink.Write(e); err != nil {
+			logutil.BgLogger().Warn("write audit event failed", zap.Uint64("conn", e.ConnID), zap.Uint64("seq", e.Seq), zap.Error(err))
+		}
+		// Flush once all the pending events are written.
+		if canFlush && len(l.eventCh) == 0 {
+			if err := f.Flush(); err != nil {
+				logutil.BgLogger().Warn("flush audit events failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Match reports whether an event should be recorded, so that the caller can skip building it.
+func (l *Logger) Match(class, user string, dbs []string) bool {
+	for i := range l.rules {
+		if l.rules[i].match(class, user, dbs) {
+			return true
+		}
+	}
+	return false
+}
+
+// Log records the event if it matches any rule. It's blocked if the sink is too slow.
+func (l *Logger) Log(e *Event) {
+	matched := l.Match(e.Class, e.User, e.dbs())
+	closeConn := e.Event == EventDisconnect || e.Event == EventReject
+	if !matched && !closeConn {
+		return
+	}
+	if matched {
+		if l.redact || variable.LogDesensitization.Load() {
+			e.SQL = parser.Normalize(e.SQL)
+			e.Err = ""
+		}
+		if e.Time.IsZero() {
+			e.Time = time.Now()
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if matched && !l.closed {
+		l.seqs[e.ConnID]++
+		e.Seq = l.seqs[e.ConnID]
+		// Sending under the lock keeps the order of Seq, and Close never closes the channel being sent to.
+		l.eventCh <- e
+	}
+	// The connection won't log any event, even if the event isn't recorded.
+	if closeConn {
+		delete(l.seqs, e.ConnID)
+	}
+}
+
+// Close stops the logger after the pending events are written, and closes the sink.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.eventCh)
+	l.mu.Unlock()
+	l.wg.Wait()
+	return l.sink.Close()
+}
+
+// ClassOfStmt returns the event class of the statement.
+func ClassOfStmt(stmt ast.StmtNode) string {
+	switch stmt.(type) {
+	case *ast.CreateUserStmt, *ast.AlterUserStmt, *ast.DropUserStmt, *ast.RenameUserStmt, *ast.SetPwdStmt,
+		*ast.GrantStmt, *ast.RevokeStmt, *ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.GrantProxyStmt,
+		*ast.RevokeProxyStmt, *ast.SetDefaultRoleStmt:
+		return ClassPrivilege
+	case ast.DDLNode:
+		return ClassDDL
+	case *ast.SelectStmt, *ast.SetOprStmt, *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt, *ast.LoadDataStmt,
+		*ast.NonTransactionalDMLStmt:
+		return ClassDML
+	}
+	return ClassOther
+}
+
+var globalLogger atomic.Value
+
+// SetGlobalLogger sets the logger which records the audit events of the server, nil disables the audit log.
+// It returns the previous logger, which should be closed by the caller.
+func SetGlobalLogger(l *Logger) *Logger {
+	old := GetGlobalLogger()
+	globalLogger.Store(l)
+	return old
+}
+
+// GetGlobalLogger returns the logger of the server, it returns nil if the audit log is disabled.
+func GetGlobalLogger() *Logger {
+	l, _ := globalLogger.Load().(*Logger)
+	return l
+}
@@ -0,0 +1,263 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	_ "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type memorySink struct {
+	sync.Mutex
+	events []*Event
+	closed bool
+}
+
+func (s *memorySink) Write(e *Event) error {
+	s.Lock()
+	defer s.Unlock()
+	s.events = append(s.events, e)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestRules(t *testing.T) {
+	_, err := NewLogger(&memorySink{}, []Rule{{Classes: []string{"unknown"}}}, false)
+	require.Error(t, err)
+
+	l, err := NewLogger(&memorySink{}, nil, false)
+	require.NoError(t, err)
+	require.True(t, l.Match(ClassConnection, "u1", nil))
+	require.True(t, l.Match(ClassPrivilege, "u1", nil))
+	require.True(t, l.Match(ClassDDL, "u1", []string{"test"}))
+	require.False(t, l.Match(ClassDML, "u1", []string{"test"}))
+	require.False(t, l.Match(ClassOther, "u1", nil))
+	require.NoError(t, l.Close())
+
+	rules := []Rule{
+		{Users: []string{"u1"}, Classes: []string{"DML"}},
+		{Users: []string{"%"}, DBs: []string{"Sales"}},
+	}
+	l, err = NewLogger(&memorySink{}, rules, false)
+	require.NoError(t, err)
+	require.True(t, l.Match(ClassDML, "U1", nil))
+	require.False(t, l.Match(ClassDDL, "u1", nil))
+	require.False(t, l.Match(ClassDML, "u2", []string{"test"}))
+	require.True(t, l.Match(ClassDML, "u2", []string{"test", "sales"}))
+	require.True(t, l.Match(ClassOther, "u2", []string{"sales"}))
+	require.False(t, l.Match(ClassConnection, "u2", nil))
+	require.NoError(t, l.Close())
+}
+
+func TestClassOfStmt(t *testing.T) {
+	cases := map[string]string{
+		"create user u1":                  ClassPrivilege,
+		"grant select on test.* to u1":    ClassPrivilege,
+		"set password for u1 = 'abc'":     ClassPrivilege,
+		"create table t (a int)":          ClassDDL,
+		"drop database test":              ClassDDL,
+		"select * from t":                 ClassDML,
+		"select 1 union select 2":         ClassDML,
+		"insert into t values (1)":        ClassDML,
+		"delete from t":                   ClassDML,
+		"set @@tidb_mem_quota_query = 10": ClassOther,
+		"show tables":                     ClassOther,
+	}
+	p := parser.New()
+	for sql, class := range cases {
+		stmt, err := p.ParseOneStmt(sql, "", "")
+		require.NoError(t, err)
+		require.Equal(t, class, ClassOfStmt(stmt), sql)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	sink := &memorySink{}
+	l, err := NewLogger(sink, []Rule{{Classes: []string{ClassConnection, ClassDML}}}, true)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for conn := uint64(1); conn <= 4; conn++ {
+		wg.Add(1)
+		go func(conn uint64) {
+			defer wg.Done()
+			l.Log(&Event{ConnID: conn, Class: ClassConnection, Event: EventConnect, User: "u1"})
+			for i := 0; i < 100; i++ {
+				l.Log(&Event{ConnID: conn, Class: ClassDML, Event: "Insert", User: "u1",
+					SQL: "insert into t values (1, 'secret')", Err: "Duplicate entry 'secret'"})
+				l.Log(&Event{ConnID: conn, Class: ClassDDL, Event: "CreateTable", User: "u1"})
+			}
+			l.Log(&Event{ConnID: conn, Class: ClassConnection, Event: EventDisconnect, User: "u1"})
+		}(conn)
+	}
+	wg.Wait()
+	require.NoError(t, l.Close())
+	require.True(t, sink.closed)
+	require.Empty(t, l.seqs)
+
+	require.Len(t, sink.events, 4*102)
+	lastSeq := make(map[uint64]uint64)
+	for _, e := range sink.events {
+		require.Equal(t, lastSeq[e.ConnID]+1, e.Seq)
+		lastSeq[e.ConnID] = e.Seq
+		require.False(t, e.Time.IsZero())
+		switch e.Seq {
+		case 1:
+			require.Equal(t, EventConnect, e.Event)
+		case 102:
+			require.Equal(t, EventDisconnect, e.Event)
+		default:
+			require.Equal(t, "insert into `t` values ( ... )", e.SQL)
+			require.Empty(t, e.Err)
+		}
+	}
+
+	// The events after closing are dropped.
+	l.Log(&Event{ConnID: 1, Class: ClassConnection, Event: EventConnect})
+	require.Len(t, sink.events, 4*102)
+	require.NoError(t, l.Close())
+}
+
+func TestSeqOfUnmatchedDisconnect(t *testing.T) {
+	sink := &memorySink{}
+	l, err := NewLogger(sink, []Rule{{Users: []string{"u1"}}}, false)
+	require.NoError(t, err)
+	l.Log(&Event{ConnID: 1, Class: ClassDML, User: "u1", SQL: "select 'a'", Err: "error"})
+	// The user changes, the disconnection isn't recorded but the connection is forgotten.
+	l.Log(&Event{ConnID: 1, Class: ClassConnection, Event: EventDisconnect, User: "u2"})
+	require.Empty(t, l.seqs)
+	require.NoError(t, l.Close())
+	require.Len(t, sink.events, 1)
+	require.Equal(t, "select 'a'", sink.events[0].SQL)
+	require.Equal(t, "error", sink.events[0].Err)
+}
+
+func TestLogDesensitization(t *testing.T) {
+	variable.LogDesensitization.Store(true)
+	defer variable.LogDesensitization.Store(variable.DefTiDBLogDesensitization)
+
+	sink := &memorySink{}
+	l, err := NewLogger(sink, []Rule{{Classes: []string{ClassDML}}}, false)
+	require.NoError(t, err)
+	l.Log(&Event{ConnID: 1, Class: ClassDML, User: "u1", SQL: "select 'secret'", Err: "error"})
+	require.NoError(t, l.Close())
+	require.Len(t, sink.events, 1)
+	require.Equal(t, "select ?", sink.events[0].SQL)
+	require.Empty(t, sink.events[0].Err)
+}
+
+func TestFileSink(t *testing.T) {
+	_, err := NewFileSink(log.FileLogConfig{})
+	require.Error(t, err)
+
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileSink(log.FileLogConfig{Filename: filename})
+	require.NoError(t, err)
+	l, err := NewLogger(sink, nil, false)
+	require.NoError(t, err)
+	l.Log(&Event{ConnID: 1, Class: ClassConnection, Event: EventConnect, User: "u1", Host: "localhost"})
+	l.Log(&Event{ConnID: 1, Class: ClassDDL, Event: "CreateTable", User: "u1", DB: "test",
+		Tables: []string{"test.t"}, SQL: "create table t (a int)"})
+	l.Log(&Event{ConnID: 2, Class: ClassConnection, Event: EventReject, User: "u2", ErrCode: 1045})
+	require.NoError(t, l.Close())
+
+	f, err := os.Open(filename)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+	var events []*Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		e := &Event{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), e))
+		events = append(events, e)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, events, 3)
+	require.Equal(t, "localhost", events[0].Host)
+	require.Equal(t, []string{"test.t"}, events[1].Tables)
+	require.Equal(t, uint64(2), events[1].Seq)
+	require.Equal(t, uint16(1045), events[2].ErrCode)
+	require.Equal(t, uint64(1), events[2].Seq)
+}
+
+type mockAuditLogServer struct {
+	sync.Mutex
+	events []*Event
+}
+
+func (s *mockAuditLogServer) Write(_ context.Context, req *WriteRequest) (*WriteResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+	s.events = append(s.events, req.Events...)
+	return &WriteResponse{}, nil
+}
+
+func TestGRPCSink(t *testing.T) {
+	_, err := NewGRPCSink("", nil)
+	require.Error(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	receiver := &mockAuditLogServer{}
+	RegisterAuditLogServer(server, receiver)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = server.Serve(listener)
+	}()
+	defer func() {
+		server.Stop()
+		wg.Wait()
+	}()
+
+	sink, err := NewGRPCSink(listener.Addr().String(), nil)
+	require.NoError(t, err)
+	l, err := NewLogger(sink, []Rule{{Classes: []string{ClassDML}}}, true)
+	require.NoError(t, err)
+	count := grpcMaxBatchCount + 10
+	for i := 0; i < count; i++ {
+		l.Log(&Event{ConnID: 1, Class: ClassDML, Event: "Select", User: "u1", SQL: "select * from t where a = 1"})
+	}
+	require.NoError(t, l.Close())
+
+	require.Len(t, receiver.events, count)
+	for i, e := range receiver.events {
+		require.Equal(t, uint64(i+1), e.Seq)
+		require.Equal(t, "select * from `t` where `a` = ?", e.SQL)
+	}
+}
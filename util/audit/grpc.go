@@ -0,0 +1,143 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding"
+)
+
+// The audit events are sent to the gRPC receiver with the JSON codec, so that the receivers in any language can
+// decode them without the protobuf definition. The receiver implements:
+//
+//	service AuditLog { rpc Write(WriteRequest) returns (WriteResponse); }
+//
+// and accepts the content subtype "json".
+const (
+	grpcCodecName     = "json"
+	grpcServiceName   = "tidb.audit.AuditLog"
+	grpcWriteMethod   = "/" + grpcServiceName + "/Write"
+	grpcSendTimeout   = 10 * time.Second
+	grpcMaxBatchCount = 256
+)
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return grpcCodecName
+}
+
+// WriteRequest is a batch of the events sent to the receiver, the events of a connection are in the order of Seq.
+type WriteRequest struct {
+	Events []*Event `json:"events"`
+}
+
+// WriteResponse is the response of the receiver.
+type WriteResponse struct{}
+
+// AuditLogServer is the gRPC receiver of the audit events.
+type AuditLogServer interface {
+	Write(ctx context.Context, req *WriteRequest) (*WriteResponse, error)
+}
+
+// RegisterAuditLogServer registers the receiver to the gRPC server.
+func RegisterAuditLogServer(s *grpc.Server, srv AuditLogServer) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: grpcServiceName,
+		HandlerType: (*AuditLogServer)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Write",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := &WriteRequest{}
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				return srv.(AuditLogServer).Write(ctx, req)
+			},
+		}},
+	}, srv)
+}
+
+// GRPCSink sends the events to the gRPC receiver in batches.
+type GRPCSink struct {
+	conn  *grpc.ClientConn
+	batch []*Event
+}
+
+// NewGRPCSink creates a GRPCSink of the receiver, the connection is insecure if tlsConfig is nil.
+func NewGRPCSink(addr string, tlsConfig *tls.Config) (*GRPCSink, error) {
+	if addr == "" {
+		return nil, errors.New("the audit log receiver is not specified")
+	}
+	opt := grpc.WithInsecure()
+	if tlsConfig != nil {
+		opt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+	conn, err := grpc.Dial(addr, opt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &GRPCSink{conn: conn}, nil
+}
+
+// Write implements the Sink interface.
+func (s *GRPCSink) Write(e *Event) error {
+	s.batch = append(s.batch, e)
+	if len(s.batch) >= grpcMaxBatchCount {
+		return s.Flush()
+	}
+	return nil
+}
+
+// Flush sends the buffered events to the receiver, the events are dropped if it fails.
+func (s *GRPCSink) Flush() error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+	req := &WriteRequest{Events: s.batch}
+	s.batch = nil
+	ctx, cancel := context.WithTimeout(context.Background(), grpcSendTimeout)
+	defer cancel()
+	err := s.conn.Invoke(ctx, grpcWriteMethod, req, &WriteResponse{}, grpc.CallContentSubtype(grpcCodecName))
+	return errors.Annotatef(err, "send %d audit events", len(req.Events))
+}
+
+// Close implements the Sink interface.
+func (s *GRPCSink) Close() error {
+	err := s.Flush()
+	if closeErr := s.conn.Close(); err == nil {
+		err = errors.Trace(closeErr)
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/zap/zapcore"
+)
+
+// Sink stores the audit events.
+type Sink interface {
+	Write(e *Event) error
+	Close() error
+}
+
+// flusher is implemented by the sinks which buffer the events, Flush is called when there is no pending event.
+type flusher interface {
+	Flush() error
+}
+
+// FileSink writes the events as JSON lines into a file, which is rotated like the log files of TiDB.
+type FileSink struct {
+	syncer zapcore.WriteSyncer
+}
+
+// NewFileSink creates a FileSink with the file config.
+func NewFileSink(cfg log.FileLogConfig) (*FileSink, error) {
+	if cfg.Filename == "" {
+		return nil, errors.New("the audit log file is not specified")
+	}
+	_, prop, err := log.InitLogger(&log.Config{File: cfg})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &FileSink{syncer: prop.Syncer}, nil
+}
+
+// Write implements the Sink interface.
+func (s *FileSink) Write(e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = s.syncer.Write(append(data, '\n'))
+	return errors.Trace(err)
+}
+
+// Flush syncs the written events to the file.
+func (s *FileSink) Flush() error {
+	return errors.Trace(s.syncer.Sync())
+}
+
+// Close implements the Sink interface.
+func (s *FileSink) Close() error {
+	return s.Flush()
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package audit
+
+import (
+	"encoding/json"
+	"log/syslog"
+
+	"github.com/pingcap/errors"
+)
+
+// syslogTag is the tag of the audit events in the syslog.
+const syslogTag = "tidb-audit"
+
+// SyslogSink writes the events as JSON messages to the syslog with the AUTH facility.
+type SyslogSink struct {
+	w *syslog.Writer
+}
+
+// NewSyslogSink connects to the syslog server, the local syslog server is used if network and addr are empty.
+func NewSyslogSink(network, addr string) (*SyslogSink, error) {
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_AUTH, syslogTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &SyslogSink{w: w}, nil
+}
+
+// Write implements the Sink interface.
+func (s *SyslogSink) Write(e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(s.w.Info(string(data)))
+}
+
+// Close implements the Sink interface.
+func (s *SyslogSink) Close() error {
+	return errors.Trace(s.w.Close())
+}
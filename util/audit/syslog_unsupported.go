@@ -0,0 +1,40 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows || plan9
+// +build windows plan9
+
+package audit
+
+import (
+	"github.com/pingcap/errors"
+)
+
+// SyslogSink is not supported on this platform.
+type SyslogSink struct{}
+
+// NewSyslogSink returns an error because the syslog is not supported on this platform.
+func NewSyslogSink(network, addr string) (*SyslogSink, error) {
+	return nil, errors.New("the syslog audit sink is not supported on this platform")
+}
+
+// Write implements the Sink interface.
+func (s *SyslogSink) Write(e *Event) error {
+	return nil
+}
+
+// Close implements the Sink interface.
+func (s *SyslogSink) Close() error {
+	return nil
+}
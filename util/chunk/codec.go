@@ -283,10 +283,9 @@ func (c *Decoder) RemainedRows() int {
 	return c.remainedRows
 }
 
-// ReuseIntermChk swaps `Decoder.intermChk` with `chk` directly when `Decoder.intermChk.NumRows()` is no less
-// than `chk.requiredRows * factor` where `factor` is 0.8 now. This can avoid the overhead of appending the
-// data from `Decoder.intermChk` to `chk`. Moreover, the column.offsets needs to be further adjusted
-// according to column.offset[0].
+// ReuseIntermChk swaps `Decoder.intermChk` with `chk` directly, so the columns of `chk` reference the decoded
+// byte slice without copying. This can avoid the overhead of appending the data from `Decoder.intermChk` to `chk`.
+// Moreover, the column.offsets needs to be further adjusted according to column.offset[0].
 func (c *Decoder) ReuseIntermChk(chk *Chunk) {
 	for i, col := range c.intermChk.columns {
 		col.length = c.remainedRows
@@ -73,6 +73,7 @@ func (l *ListInDisk) initDiskFile() (err error) {
 	if err != nil {
 		return errors2.Trace(err)
 	}
+	disk.RegisterSpillFile(l.disk.Name(), l.diskTracker)
 	var underlying io.WriteCloser = l.disk
 	if config.GetGlobalConfig().Security.SpilledFileEncryptionMethod != config.SpilledFileEncryptionMethodPlaintext {
 		// The possible values of SpilledFileEncryptionMethod are "plaintext", "aes128-ctr"
@@ -149,7 +150,7 @@ func (l *ListInDisk) Add(chk *Chunk) (err error) {
 	l.offsets = append(l.offsets, chk2.getOffsetsOfRows())
 	l.diskTracker.Consume(n)
 	l.numRowsInDisk += chk.NumRows()
-	return
+	return disk.CheckQuota(l.diskTracker)
 }
 
 // GetChunk gets a Chunk from the ListInDisk by chkIdx.
@@ -203,6 +204,7 @@ func (l *ListInDisk) Close() error {
 		l.diskTracker.Consume(-l.diskTracker.BytesConsumed())
 		terror.Call(l.disk.Close)
 		terror.Log(os.Remove(l.disk.Name()))
+		disk.UnregisterSpillFile(l.disk.Name())
 	}
 	return nil
 }
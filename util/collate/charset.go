@@ -85,8 +85,12 @@ func init() {
 	experimentalCollation = make(map[string]Collator)
 	experimentalCharsetInfo = append(experimentalCharsetInfo,
 		&charset.Charset{Name: charset.CharsetGBK, DefaultCollation: "gbk_chinese_ci", Collations: make(map[string]*charset.Collation), Desc: "Chinese Internal Code Specification", Maxlen: 2},
+		&charset.Charset{Name: charset.CharsetGB18030, DefaultCollation: "gb18030_chinese_ci", Collations: make(map[string]*charset.Collation), Desc: "China National Standard GB18030", Maxlen: 4},
 	)
 	e, _ := charset.Lookup(charset.CharsetGBK)
 	experimentalCollation[charset.CollationGBKBin] = &gbkBinCollator{e.NewEncoder()}
 	experimentalCollation["gbk_chinese_ci"] = &gbkChineseCICollator{}
+	e, _ = charset.Lookup(charset.CharsetGB18030)
+	experimentalCollation[charset.CollationGB18030Bin] = &gb18030BinCollator{gbkBinCollator{e.NewEncoder()}}
+	experimentalCollation["gb18030_chinese_ci"] = &gb18030ChineseCICollator{}
 }
@@ -336,6 +336,12 @@ func IsBinCollation(collate string) bool {
 		collate == charset.CollationUTF8 || collate == charset.CollationUTF8MB4
 }
 
+// IsTiKVSupportedCollation returns if the collation is supported by the coprocessor of TiKV, that is,
+// the expressions in the collation are evaluated in the same way as in TiDB.
+func IsTiKVSupportedCollation(collate string) bool {
+	return collate == charset.CollationBin || IsBinCollation(collate) || IsCICollation(collate)
+}
+
 // CollationToProto converts collation from string to int32(used by protocol).
 func CollationToProto(c string) int32 {
 	if coll, err := charset.GetCollationByName(c); err == nil {
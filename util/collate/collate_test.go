@@ -110,6 +110,36 @@ func TestUTF8CollatorKey(t *testing.T) {
 	testKeyTable(t, collations, tests)
 }
 
+func TestGB18030Collator(t *testing.T) {
+	SetCharsetFeatEnabledForTest(true)
+	defer SetCharsetFeatEnabledForTest(false)
+	collations := []string{"gbk_bin", "gbk_chinese_ci", "gb18030_bin", "gb18030_chinese_ci"}
+	compareTests := []compareTable{
+		{"a", "A", []int{1, 0, 1, 0}},
+		{"a", "a ", []int{0, 0, 0, 0}},
+		{"啊", "吧", []int{-1, -1, -1, -1}},
+		{"中文", "汉字", []int{1, 1, 1, 1}},
+		{"😜", "😃", []int{0, 0, 1, 1}},
+		{"À", "à", []int{-1, -1, -1, 0}},
+		{"À", "Á", []int{0, 0, -1, 1}},
+		{"z", "À", []int{1, 1, -1, -1}},
+		{"啊", "😃", []int{1, 1, 1, -1}},
+	}
+	testCompareTable(t, collations, compareTests)
+
+	keyTests := []keyTable{
+		{"a", [][]byte{{0x61}, {0x41}, {0x61}, {0x41}}},
+		{"中文", [][]byte{{0xD6, 0xD0, 0xCE, 0xC4}, {0xD3, 0x21, 0xC1, 0xAD}, {0xD6, 0xD0, 0xCE, 0xC4}, {0xD3, 0x21, 0xC1, 0xAD}}},
+		{"À😃 ", [][]byte{
+			{0x3F, 0x3F},
+			{0x3F, 0x3F},
+			{0x81, 0x30, 0x86, 0x38, 0x94, 0x39, 0xFC, 0x39},
+			{0x82, 0xB3, 0xFF, 0x01, 0xF6, 0x03},
+		}},
+	}
+	testKeyTable(t, collations, keyTests)
+}
+
 func TestSetNewCollateEnabled(t *testing.T) {
 	defer SetNewCollationEnabledForTest(false)
 
@@ -179,4 +209,6 @@ func TestGetCollator(t *testing.T) {
 	defer SetCharsetFeatEnabledForTest(false)
 	require.IsType(t, &gbkBinCollator{}, GetCollator("gbk_bin"))
 	require.IsType(t, &gbkBinCollator{}, GetCollatorByID(87))
+	require.IsType(t, &gb18030BinCollator{}, GetCollator("gb18030_bin"))
+	require.IsType(t, &gb18030ChineseCICollator{}, GetCollatorByID(248))
 }
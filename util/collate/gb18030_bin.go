@@ -0,0 +1,31 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collate
+
+// gb18030BinCollator is collator for gb18030_bin. Like gbk_bin, the strings are compared by
+// their encoded bytes, but every unicode character can be encoded in gb18030.
+type gb18030BinCollator struct {
+	gbkBinCollator
+}
+
+// Pattern implements Collator interface.
+func (g *gb18030BinCollator) Pattern() WildcardPattern {
+	return &gb18030BinPattern{}
+}
+
+// use binPattern directly, they are totally same.
+type gb18030BinPattern struct {
+	binPattern
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collate
+
+import (
+	"unicode"
+
+	"github.com/pingcap/tidb/util/stringutil"
+)
+
+// gb18030ChineseCICollator is collator for gb18030_chinese_ci. The characters in GBK are sorted as
+// gbk_chinese_ci, the other characters are sorted after them by their upper case code points unless
+// their lower cases are in GBK.
+type gb18030ChineseCICollator struct {
+}
+
+// Compare implements Collator interface.
+func (g *gb18030ChineseCICollator) Compare(a, b string) int {
+	a = truncateTailingSpace(a)
+	b = truncateTailingSpace(b)
+
+	r1, r2 := rune(0), rune(0)
+	ai, bi := 0, 0
+	for ai < len(a) && bi < len(b) {
+		r1, ai = decodeRune(a, ai)
+		r2, bi = decodeRune(b, bi)
+
+		cmp := int64(gb18030ChineseCISortKey(r1)) - int64(gb18030ChineseCISortKey(r2))
+		if cmp != 0 {
+			return sign(int(cmp))
+		}
+	}
+	return sign((len(a) - ai) - (len(b) - bi))
+}
+
+// Key implements Collator interface.
+func (g *gb18030ChineseCICollator) Key(str string) []byte {
+	str = truncateTailingSpace(str)
+	buf := make([]byte, 0, len(str)*2)
+	i := 0
+	r := rune(0)
+	for i < len(str) {
+		r, i = decodeRune(str, i)
+		u32 := gb18030ChineseCISortKey(r)
+		if u32 > 0xFFFF {
+			buf = append(buf, byte(u32>>24), byte(u32>>16))
+		}
+		if u32 > 0xFF {
+			buf = append(buf, byte(u32>>8))
+		}
+		buf = append(buf, byte(u32))
+	}
+	return buf
+}
+
+// Pattern implements Collator interface.
+func (g *gb18030ChineseCICollator) Pattern() WildcardPattern {
+	return &gb18030ChineseCIPattern{}
+}
+
+type gb18030ChineseCIPattern struct {
+	patChars []rune
+	patTypes []byte
+}
+
+// Compile implements WildcardPattern interface.
+func (p *gb18030ChineseCIPattern) Compile(patternStr string, escape byte) {
+	p.patChars, p.patTypes = stringutil.CompilePatternInner(patternStr, escape)
+}
+
+// DoMatch implements WildcardPattern interface.
+func (p *gb18030ChineseCIPattern) DoMatch(str string) bool {
+	return stringutil.DoMatchInner(str, p.patChars, p.patTypes, func(a, b rune) bool {
+		return gb18030ChineseCISortKey(a) == gb18030ChineseCISortKey(b)
+	})
+}
+
+// gb18030NonGBKWeightPrefix is the prefix of the weights of the characters not in GBK, it's greater than
+// the first byte of all the weights of gbk_chinese_ci.
+const gb18030NonGBKWeightPrefix = 0xFF000000
+
+func gb18030ChineseCISortKey(r rune) uint32 {
+	if w, ok := gbkWeight(r); ok {
+		return w
+	}
+	// The lower case of a character may be in GBK, such as 'À' and 'à'.
+	if w, ok := gbkWeight(unicode.ToLower(r)); ok {
+		return w
+	}
+	return gb18030NonGBKWeightPrefix | uint32(unicode.ToUpper(r))
+}
+
+// gbkWeight returns the weight of gbk_chinese_ci, ok is false if the character isn't in GBK.
+func gbkWeight(r rune) (w uint32, ok bool) {
+	if r > 0xFFFF {
+		return 0, false
+	}
+	// The characters not in GBK are weighted '?' by gbk_chinese_ci.
+	w = uint32(gbkChineseCISortKeyTable[r])
+	return w, w != 0x3F || r == '?'
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// SpillFile is a live temporary file which holds the data spilled by a query.
+type SpillFile struct {
+	Path       string
+	CreateTime time.Time
+	// Tracker tracks the disk usage of the file, it's attached to the disk tracker of the owning query.
+	Tracker *Tracker
+}
+
+var spillFiles sync.Map // path -> *SpillFile
+
+// RegisterSpillFile registers a spill file which is created in the temporary storage directory.
+func RegisterSpillFile(path string, tracker *Tracker) {
+	spillFiles.Store(path, &SpillFile{Path: path, CreateTime: time.Now(), Tracker: tracker})
+}
+
+// UnregisterSpillFile unregisters a spill file when it's removed.
+func UnregisterSpillFile(path string) {
+	spillFiles.Delete(path)
+}
+
+// GetSpillFiles returns all the live spill files ordered by their creation time.
+func GetSpillFiles() []*SpillFile {
+	files := make([]*SpillFile, 0)
+	spillFiles.Range(func(_, value interface{}) bool {
+		files = append(files, value.(*SpillFile))
+		return true
+	})
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].CreateTime.Before(files[j].CreateTime)
+	})
+	return files
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpillFiles(t *testing.T) {
+	stmtTracker := NewTracker(memory.LabelForSQLText, 100)
+	tracker1 := NewTracker(memory.LabelForChunkListInDisk, -1)
+	tracker1.AttachTo(stmtTracker)
+	tracker2 := NewTracker(memory.LabelForChunkListInDisk, -1)
+	tracker2.AttachTo(stmtTracker)
+
+	RegisterSpillFile("file1", tracker1)
+	RegisterSpillFile("file2", tracker2)
+	files := GetSpillFiles()
+	require.Len(t, files, 2)
+	require.Equal(t, "file1", files[0].Path)
+	require.Equal(t, tracker1, files[0].Tracker)
+	require.Equal(t, "file2", files[1].Path)
+	require.True(t, files[1].Tracker.HasAncestor(stmtTracker))
+
+	tracker1.Consume(60)
+	require.NoError(t, CheckQuota(tracker1))
+	tracker2.Consume(60)
+	err := CheckQuota(tracker1)
+	require.True(t, terror.ErrorEqual(err, ErrTmpStorageQuotaExceeded))
+	require.EqualError(t, err, "[util:8248]Out Of Temporary Storage Quota! The query holds 120B temporary storage, exceeds the quota 100B")
+
+	UnregisterSpillFile("file1")
+	UnregisterSpillFile("file2")
+	require.Len(t, GetSpillFiles(), 0)
+}
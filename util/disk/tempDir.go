@@ -86,19 +86,25 @@ func InitializeTempDir() error {
 		return err
 	}
 
-	// If it exists others files except lock file, creates another goroutine to clean them.
-	if len(subDirs) > 2 {
+	// The files left by the last running instance, e.g. the spill files which were not
+	// removed because of a crash, are cleaned by another goroutine.
+	leftovers := make([]string, 0, len(subDirs))
+	for _, subDir := range subDirs {
+		// Do not remove the lock file and the record directory.
+		switch subDir.Name() {
+		case lockFile, recordDir:
+			continue
+		}
+		leftovers = append(leftovers, filepath.Join(tempDir, subDir.Name()))
+	}
+	if len(leftovers) > 0 {
+		log.Info("Remove the temporary files left by the last running instance", zap.Int("count", len(leftovers)))
 		go func() {
-			for _, subDir := range subDirs {
-				// Do not remove the lock file.
-				switch subDir.Name() {
-				case lockFile, recordDir:
-					continue
-				}
-				err := os.RemoveAll(filepath.Join(tempDir, subDir.Name()))
+			for _, leftover := range leftovers {
+				err := os.RemoveAll(leftover)
 				if err != nil {
 					log.Warn("Remove temporary file error",
-						zap.String("tempStorageSubDir", filepath.Join(tempDir, subDir.Name())), zap.Error(err))
+						zap.String("tempStorageSubDir", leftover), zap.Error(err))
 				}
 			}
 		}()
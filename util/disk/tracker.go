@@ -15,6 +15,8 @@
 package disk
 
 import (
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/memory"
 )
 
@@ -28,3 +30,16 @@ var NewTracker = memory.NewTracker
 
 // NewGlobalTrcaker creates a global disk tracker.
 var NewGlobalTrcaker = memory.NewGlobalTracker
+
+// ErrTmpStorageQuotaExceeded is returned when the temporary storage used by a query exceeds its quota.
+var ErrTmpStorageQuotaExceeded = dbterror.ClassUtil.NewStd(errno.ErrTmpStorageQuotaExceeded)
+
+// CheckQuota returns ErrTmpStorageQuotaExceeded if any tracker on the path from
+// the tracker to the root exceeds its quota. The global tracker is not checked,
+// it's handled by its own action.
+func CheckQuota(t *Tracker) error {
+	if exceeded := t.ExceededAncestor(); exceeded != nil {
+		return ErrTmpStorageQuotaExceeded.GenWithStackByArgs(exceeded.BytesConsumed(), exceeded.GetBytesLimit())
+	}
+	return nil
+}
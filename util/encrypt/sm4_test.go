@@ -0,0 +1,84 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encrypt
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSM4Cipher(t *testing.T) {
+	// The example in GB/T 32907-2016.
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	cb, err := NewSM4Cipher(key)
+	require.NoError(t, err)
+	require.Equal(t, SM4BlockSize, cb.BlockSize())
+
+	block := make([]byte, SM4BlockSize)
+	cb.Encrypt(block, key)
+	require.Equal(t, "681edf34d206965e86b3e94f536e4246", hex.EncodeToString(block))
+	cb.Decrypt(block, block)
+	require.Equal(t, key, block)
+
+	// Encrypt the plain text 1000000 times.
+	copy(block, key)
+	for i := 0; i < 1000000; i++ {
+		cb.Encrypt(block, block)
+	}
+	require.Equal(t, "595298c7c6fd271f0402f804c33d3f66", hex.EncodeToString(block))
+
+	_, err = NewSM4Cipher(key[:15])
+	require.Error(t, err)
+}
+
+func TestSM4Modes(t *testing.T) {
+	key := []byte("1234567890123456")
+	iv := []byte("abcdefghijklmnop")
+	tests := []struct {
+		encrypt func(str, key, iv []byte) ([]byte, error)
+		decrypt func(str, key, iv []byte) ([]byte, error)
+		padded  bool
+	}{
+		{
+			func(str, key, _ []byte) ([]byte, error) { return SM4EncryptWithECB(str, key) },
+			func(str, key, _ []byte) ([]byte, error) { return SM4DecryptWithECB(str, key) },
+			true,
+		},
+		{SM4EncryptWithCBC, SM4DecryptWithCBC, true},
+		{SM4EncryptWithOFB, SM4DecryptWithOFB, false},
+		{SM4EncryptWithCFB, SM4DecryptWithCFB, false},
+	}
+	for _, tt := range tests {
+		for _, str := range []string{"", "pingcap", "1234567890123456", "pingcap-tidb-pingcap-tidb"} {
+			crypted, err := tt.encrypt([]byte(str), key, iv)
+			require.NoError(t, err)
+			if tt.padded {
+				require.Equal(t, (len(str)/SM4BlockSize+1)*SM4BlockSize, len(crypted))
+			} else {
+				require.Equal(t, len(str), len(crypted))
+			}
+			plain, err := tt.decrypt(crypted, key, iv)
+			require.NoError(t, err)
+			require.Equal(t, str, string(plain))
+		}
+	}
+
+	_, err := SM4DecryptWithECB([]byte("0123456789"), key)
+	require.Error(t, err)
+	_, err = SM4EncryptWithCBC([]byte("pingcap"), key[:8], iv)
+	require.Error(t, err)
+}
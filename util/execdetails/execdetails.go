@@ -43,6 +43,8 @@ type ExecDetails struct {
 	LockKeysDetail   *util.LockKeysDetails
 	ScanDetail       *util.ScanDetail
 	TimeDetail       util.TimeDetail
+	// RUConsumed is the request units consumed by the coprocessor requests.
+	RUConsumed float64
 }
 
 type stmtExecDetailKeyType struct{}
@@ -110,94 +112,110 @@ const (
 
 // String implements the fmt.Stringer interface.
 func (d ExecDetails) String() string {
-	parts := make([]string, 0, 8)
+	fields := d.Fields()
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, f.Name+": "+f.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ExecDetailField is a field of ExecDetails in the slow log.
+type ExecDetailField struct {
+	Name  string
+	Value string
+}
+
+// Fields returns the non-zero fields of ExecDetails in the order of the slow log.
+func (d ExecDetails) Fields() []ExecDetailField {
+	fields := make([]ExecDetailField, 0, 8)
 	if d.CopTime > 0 {
-		parts = append(parts, CopTimeStr+": "+strconv.FormatFloat(d.CopTime.Seconds(), 'f', -1, 64))
+		fields = append(fields, ExecDetailField{CopTimeStr, strconv.FormatFloat(d.CopTime.Seconds(), 'f', -1, 64)})
 	}
 	if d.TimeDetail.ProcessTime > 0 {
-		parts = append(parts, ProcessTimeStr+": "+strconv.FormatFloat(d.TimeDetail.ProcessTime.Seconds(), 'f', -1, 64))
+		fields = append(fields, ExecDetailField{ProcessTimeStr, strconv.FormatFloat(d.TimeDetail.ProcessTime.Seconds(), 'f', -1, 64)})
 	}
 	if d.TimeDetail.WaitTime > 0 {
-		parts = append(parts, WaitTimeStr+": "+strconv.FormatFloat(d.TimeDetail.WaitTime.Seconds(), 'f', -1, 64))
+		fields = append(fields, ExecDetailField{WaitTimeStr, strconv.FormatFloat(d.TimeDetail.WaitTime.Seconds(), 'f', -1, 64)})
 	}
 	if d.BackoffTime > 0 {
-		parts = append(parts, BackoffTimeStr+": "+strconv.FormatFloat(d.BackoffTime.Seconds(), 'f', -1, 64))
+		fields = append(fields, ExecDetailField{BackoffTimeStr, strconv.FormatFloat(d.BackoffTime.Seconds(), 'f', -1, 64)})
 	}
 	if d.LockKeysDuration > 0 {
-		parts = append(parts, LockKeysTimeStr+": "+strconv.FormatFloat(d.LockKeysDuration.Seconds(), 'f', -1, 64))
+		fields = append(fields, ExecDetailField{LockKeysTimeStr, strconv.FormatFloat(d.LockKeysDuration.Seconds(), 'f', -1, 64)})
 	}
 	if d.RequestCount > 0 {
-		parts = append(parts, RequestCountStr+": "+strconv.FormatInt(int64(d.RequestCount), 10))
+		fields = append(fields, ExecDetailField{RequestCountStr, strconv.FormatInt(int64(d.RequestCount), 10)})
 	}
 	commitDetails := d.CommitDetail
 	if commitDetails != nil {
 		if commitDetails.PrewriteTime > 0 {
-			parts = append(parts, PreWriteTimeStr+": "+strconv.FormatFloat(commitDetails.PrewriteTime.Seconds(), 'f', -1, 64))
+			fields = append(fields, ExecDetailField{PreWriteTimeStr, strconv.FormatFloat(commitDetails.PrewriteTime.Seconds(), 'f', -1, 64)})
 		}
 		if commitDetails.WaitPrewriteBinlogTime > 0 {
-			parts = append(parts, WaitPrewriteBinlogTimeStr+": "+strconv.FormatFloat(commitDetails.WaitPrewriteBinlogTime.Seconds(), 'f', -1, 64))
+			fields = append(fields, ExecDetailField{WaitPrewriteBinlogTimeStr, strconv.FormatFloat(commitDetails.WaitPrewriteBinlogTime.Seconds(), 'f', -1, 64)})
 		}
 		if commitDetails.CommitTime > 0 {
-			parts = append(parts, CommitTimeStr+": "+strconv.FormatFloat(commitDetails.CommitTime.Seconds(), 'f', -1, 64))
+			fields = append(fields, ExecDetailField{CommitTimeStr, strconv.FormatFloat(commitDetails.CommitTime.Seconds(), 'f', -1, 64)})
 		}
 		if commitDetails.GetCommitTsTime > 0 {
-			parts = append(parts, GetCommitTSTimeStr+": "+strconv.FormatFloat(commitDetails.GetCommitTsTime.Seconds(), 'f', -1, 64))
+			fields = append(fields, ExecDetailField{GetCommitTSTimeStr, strconv.FormatFloat(commitDetails.GetCommitTsTime.Seconds(), 'f', -1, 64)})
 		}
 		commitDetails.Mu.Lock()
 		commitBackoffTime := commitDetails.Mu.CommitBackoffTime
 		if commitBackoffTime > 0 {
-			parts = append(parts, CommitBackoffTimeStr+": "+strconv.FormatFloat(time.Duration(commitBackoffTime).Seconds(), 'f', -1, 64))
+			fields = append(fields, ExecDetailField{CommitBackoffTimeStr, strconv.FormatFloat(time.Duration(commitBackoffTime).Seconds(), 'f', -1, 64)})
 		}
 		if len(commitDetails.Mu.BackoffTypes) > 0 {
-			parts = append(parts, BackoffTypesStr+": "+fmt.Sprintf("%v", commitDetails.Mu.BackoffTypes))
+			fields = append(fields, ExecDetailField{BackoffTypesStr, fmt.Sprintf("%v", commitDetails.Mu.BackoffTypes)})
 		}
 		commitDetails.Mu.Unlock()
 		resolveLockTime := atomic.LoadInt64(&commitDetails.ResolveLockTime)
 		if resolveLockTime > 0 {
-			parts = append(parts, ResolveLockTimeStr+": "+strconv.FormatFloat(time.Duration(resolveLockTime).Seconds(), 'f', -1, 64))
+			fields = append(fields, ExecDetailField{ResolveLockTimeStr, strconv.FormatFloat(time.Duration(resolveLockTime).Seconds(), 'f', -1, 64)})
 		}
 		if commitDetails.LocalLatchTime > 0 {
-			parts = append(parts, LocalLatchWaitTimeStr+": "+strconv.FormatFloat(commitDetails.LocalLatchTime.Seconds(), 'f', -1, 64))
+			fields = append(fields, ExecDetailField{LocalLatchWaitTimeStr, strconv.FormatFloat(commitDetails.LocalLatchTime.Seconds(), 'f', -1, 64)})
 		}
 		if commitDetails.WriteKeys > 0 {
-			parts = append(parts, WriteKeysStr+": "+strconv.FormatInt(int64(commitDetails.WriteKeys), 10))
+			fields = append(fields, ExecDetailField{WriteKeysStr, strconv.FormatInt(int64(commitDetails.WriteKeys), 10)})
 		}
 		if commitDetails.WriteSize > 0 {
-			parts = append(parts, WriteSizeStr+": "+strconv.FormatInt(int64(commitDetails.WriteSize), 10))
+			fields = append(fields, ExecDetailField{WriteSizeStr, strconv.FormatInt(int64(commitDetails.WriteSize), 10)})
 		}
 		prewriteRegionNum := atomic.LoadInt32(&commitDetails.PrewriteRegionNum)
 		if prewriteRegionNum > 0 {
-			parts = append(parts, PrewriteRegionStr+": "+strconv.FormatInt(int64(prewriteRegionNum), 10))
+			fields = append(fields, ExecDetailField{PrewriteRegionStr, strconv.FormatInt(int64(prewriteRegionNum), 10)})
 		}
 		if commitDetails.TxnRetry > 0 {
-			parts = append(parts, TxnRetryStr+": "+strconv.FormatInt(int64(commitDetails.TxnRetry), 10))
+			fields = append(fields, ExecDetailField{TxnRetryStr, strconv.FormatInt(int64(commitDetails.TxnRetry), 10)})
 		}
 	}
 	scanDetail := d.ScanDetail
 	if scanDetail != nil {
 		if scanDetail.ProcessedKeys > 0 {
-			parts = append(parts, ProcessKeysStr+": "+strconv.FormatInt(scanDetail.ProcessedKeys, 10))
+			fields = append(fields, ExecDetailField{ProcessKeysStr, strconv.FormatInt(scanDetail.ProcessedKeys, 10)})
 		}
 		if scanDetail.TotalKeys > 0 {
-			parts = append(parts, TotalKeysStr+": "+strconv.FormatInt(scanDetail.TotalKeys, 10))
+			fields = append(fields, ExecDetailField{TotalKeysStr, strconv.FormatInt(scanDetail.TotalKeys, 10)})
 		}
 		if scanDetail.RocksdbDeleteSkippedCount > 0 {
-			parts = append(parts, RocksdbDeleteSkippedCountStr+": "+strconv.FormatUint(scanDetail.RocksdbDeleteSkippedCount, 10))
+			fields = append(fields, ExecDetailField{RocksdbDeleteSkippedCountStr, strconv.FormatUint(scanDetail.RocksdbDeleteSkippedCount, 10)})
 		}
 		if scanDetail.RocksdbKeySkippedCount > 0 {
-			parts = append(parts, RocksdbKeySkippedCountStr+": "+strconv.FormatUint(scanDetail.RocksdbKeySkippedCount, 10))
+			fields = append(fields, ExecDetailField{RocksdbKeySkippedCountStr, strconv.FormatUint(scanDetail.RocksdbKeySkippedCount, 10)})
 		}
 		if scanDetail.RocksdbBlockCacheHitCount > 0 {
-			parts = append(parts, RocksdbBlockCacheHitCountStr+": "+strconv.FormatUint(scanDetail.RocksdbBlockCacheHitCount, 10))
+			fields = append(fields, ExecDetailField{RocksdbBlockCacheHitCountStr, strconv.FormatUint(scanDetail.RocksdbBlockCacheHitCount, 10)})
 		}
 		if scanDetail.RocksdbBlockReadCount > 0 {
-			parts = append(parts, RocksdbBlockReadCountStr+": "+strconv.FormatUint(scanDetail.RocksdbBlockReadCount, 10))
+			fields = append(fields, ExecDetailField{RocksdbBlockReadCountStr, strconv.FormatUint(scanDetail.RocksdbBlockReadCount, 10)})
 		}
 		if scanDetail.RocksdbBlockReadByte > 0 {
-			parts = append(parts, RocksdbBlockReadByteStr+": "+strconv.FormatUint(scanDetail.RocksdbBlockReadByte, 10))
+			fields = append(fields, ExecDetailField{RocksdbBlockReadByteStr, strconv.FormatUint(scanDetail.RocksdbBlockReadByte, 10)})
 		}
 	}
-	return strings.Join(parts, " ")
+	return fields
 }
 
 // ToZapFields wraps the ExecDetails as zap.Fields.
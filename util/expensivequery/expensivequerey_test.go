@@ -18,9 +18,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/resourcegroup"
 	"github.com/pingcap/tidb/util/testbridge"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/goleak"
@@ -72,3 +74,52 @@ func TestLogFormat(t *testing.T) {
 	logFields = genLogFields(costTime, info)
 	assert.Equal(t, "select * from table where `a` > ?", logFields[6].String)
 }
+
+func TestCheckRunawayQuery(t *testing.T) {
+	resourcegroup.GlobalController.Update([]*model.ResourceGroupInfo{
+		{Name: model.NewCIStr("rg_kill"), ResourceGroupSettings: &model.ResourceGroupSettings{ExecElapsedTimeMs: 1000, RunawayAction: model.RunawayActionKill}},
+		{Name: model.NewCIStr("rg_cooldown"), ResourceGroupSettings: &model.ResourceGroupSettings{ExecElapsedTimeMs: 1000, RunawayAction: model.RunawayActionCooldown}},
+		{Name: model.NewCIStr("rg_unlimited"), ResourceGroupSettings: &model.ResourceGroupSettings{}},
+	})
+	defer resourcegroup.GlobalController.Update(nil)
+
+	newInfo := func(id uint64, group string) *util.ProcessInfo {
+		info := newProcessInfoWithMem(id, "select * from t where a = 1", 0)
+		info.ResourceGroupName = group
+		info.Digest = "digest"
+		info.RedactSQL = true
+		return info
+	}
+	sm := &mockSessionManager{ps: map[uint64]*util.ProcessInfo{
+		1: newInfo(1, "rg_kill"),
+		2: newInfo(2, "rg_cooldown"),
+		3: newInfo(3, "rg_unlimited"),
+	}}
+
+	// The queries within the limit are left alone.
+	for _, info := range sm.ps {
+		checkRunawayQuery(sm, info, 500*time.Millisecond)
+		assert.False(t, info.ExceedRunawayLimit)
+	}
+	assert.Empty(t, sm.killed)
+
+	for _, info := range sm.ps {
+		checkRunawayQuery(sm, info, 2*time.Second)
+	}
+	assert.Equal(t, []uint64{1}, sm.killed)
+	assert.True(t, sm.ps[1].ExceedRunawayLimit)
+	assert.True(t, sm.ps[2].ExceedRunawayLimit)
+	assert.True(t, sm.ps[2].StmtCtx.RunawayCooldown.Load())
+	assert.False(t, sm.ps[3].ExceedRunawayLimit)
+
+	records := make(map[string]*resourcegroup.RunawayRecord)
+	for i := 0; i < 2; i++ {
+		record := <-resourcegroup.GlobalController.RunawayRecords()
+		records[record.ResourceGroupName] = record
+	}
+	assert.Equal(t, model.RunawayActionKill, records["rg_kill"].Action)
+	assert.Equal(t, model.RunawayActionCooldown, records["rg_cooldown"].Action)
+	assert.Equal(t, 2*time.Second, records["rg_kill"].ExecElapsed)
+	assert.Equal(t, "digest", records["rg_kill"].SQLDigest)
+	assert.Equal(t, "select * from `t` where `a` = ?", records["rg_kill"].SQLText)
+}
@@ -23,9 +23,11 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/resourcegroup"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -57,6 +59,7 @@ func (eqh *Handle) Run() {
 	defer ticker.Stop()
 	sm := eqh.sm.Load().(util.SessionManager)
 	record := &memoryUsageAlarm{}
+	killer := &serverMemoryLimitKiller{}
 	for {
 		select {
 		case <-ticker.C:
@@ -71,8 +74,8 @@ func (eqh *Handle) Run() {
 					info.ExceedExpensiveTimeThresh = true
 				}
 
-				if info.MaxExecutionTime > 0 && costTime > time.Duration(info.MaxExecutionTime)*time.Millisecond {
-					sm.Kill(info.ID, true)
+				if !info.ExceedRunawayLimit && len(info.ResourceGroupName) > 0 {
+					checkRunawayQuery(sm, info, costTime)
 				}
 			}
 			threshold = atomic.LoadUint64(&variable.ExpensiveQueryTimeThreshold)
@@ -81,12 +84,47 @@ func (eqh *Handle) Run() {
 			if record.err == nil {
 				record.alarm4ExcessiveMemUsage(sm)
 			}
+			killer.killIfNeeded(sm, variable.ServerMemoryLimit.Load())
 		case <-eqh.exitCh:
 			return
 		}
 	}
 }
 
+// checkRunawayQuery takes the QUERY_LIMIT action of the resource group on the query and records it if the query
+// runs longer than the limit.
+func checkRunawayQuery(sm util.SessionManager, info *util.ProcessInfo, costTime time.Duration) {
+	execElapsed, action := resourcegroup.GlobalController.QueryLimit(info.ResourceGroupName)
+	if execElapsed == 0 || costTime <= execElapsed {
+		return
+	}
+	info.ExceedRunawayLimit = true
+	switch action {
+	case model.RunawayActionKill:
+		sm.Kill(info.ID, true)
+	case model.RunawayActionCooldown:
+		info.StmtCtx.RunawayCooldown.Store(true)
+	}
+	sql := info.Info
+	if info.RedactSQL {
+		sql = parser.Normalize(sql)
+	}
+	resourcegroup.GlobalController.RecordRunaway(&resourcegroup.RunawayRecord{
+		ResourceGroupName: info.ResourceGroupName,
+		User:              info.User,
+		Host:              info.Host,
+		SQLDigest:         info.Digest,
+		SQLText:           sql,
+		ExecElapsed:       costTime,
+		Action:            action,
+		StartTime:         info.Time,
+		WatchTime:         time.Now(),
+	})
+	logutil.BgLogger().Warn("runaway_query", zap.String("resource group", info.ResourceGroupName),
+		zap.Stringer("action", action), zap.Duration("cost time", costTime), zap.Uint64("conn_id", info.ID),
+		zap.String("sql digest", info.Digest))
+}
+
 // LogOnQueryExceedMemQuota prints a log when memory usage of connID is out of memory quota.
 func (eqh *Handle) LogOnQueryExceedMemQuota(connID uint64) {
 	if log.GetLevel() > zapcore.WarnLevel {
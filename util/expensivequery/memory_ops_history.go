@@ -0,0 +1,85 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expensivequery
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/util"
+)
+
+const (
+	// MemoryOpsRecord is the operation that records the running SQLs and the heap profile when the memory usage
+	// exceeds tidb_memory_usage_alarm_ratio.
+	MemoryOpsRecord = "record"
+	// MemoryOpsKill is the operation that cancels the statement consuming the most memory when the memory usage
+	// exceeds tidb_server_memory_limit.
+	MemoryOpsKill = "kill"
+
+	// memoryOpsHistoryCapacity is the max number of the kept memory operations, the oldest one is dropped first.
+	memoryOpsHistoryCapacity = 50
+)
+
+// MemoryOps is an operation taken by the tidb-server when its memory usage is too high.
+type MemoryOps struct {
+	Time time.Time `json:"time"`
+	Ops  string    `json:"ops"`
+	// MemoryLimit is the threshold of the memory usage which triggers the operation.
+	MemoryLimit   uint64 `json:"memory_limit"`
+	MemoryCurrent uint64 `json:"memory_current"`
+	// The following fields describe the cancelled statement, they're empty if no statement is cancelled.
+	ConnID    uint64 `json:"conn_id,omitempty"`
+	Mem       int64  `json:"mem,omitempty"`
+	User      string `json:"user,omitempty"`
+	Host      string `json:"host,omitempty"`
+	DB        string `json:"db,omitempty"`
+	SQLDigest string `json:"sql_digest,omitempty"`
+	SQLText   string `json:"sql_text,omitempty"`
+}
+
+var memoryOpsHistory struct {
+	sync.Mutex
+	ops []MemoryOps
+}
+
+// setStatement sets the statement cancelled by the operation.
+func (ops *MemoryOps) setStatement(info *util.ProcessInfo, mem int64) {
+	ops.ConnID = info.ID
+	ops.Mem = mem
+	ops.User, ops.Host, ops.DB = info.User, info.Host, info.DB
+	ops.SQLDigest = info.Digest
+	ops.SQLText = info.Info
+	if info.RedactSQL {
+		ops.SQLText = parser.Normalize(ops.SQLText)
+	}
+}
+
+func recordMemoryOps(ops MemoryOps) {
+	memoryOpsHistory.Lock()
+	defer memoryOpsHistory.Unlock()
+	if len(memoryOpsHistory.ops) >= memoryOpsHistoryCapacity {
+		memoryOpsHistory.ops = append(memoryOpsHistory.ops[:0:0], memoryOpsHistory.ops[1:]...)
+	}
+	memoryOpsHistory.ops = append(memoryOpsHistory.ops, ops)
+}
+
+// GetMemoryOpsHistory returns the recent operations taken when the memory usage is too high, in time order.
+func GetMemoryOpsHistory() []MemoryOps {
+	memoryOpsHistory.Lock()
+	defer memoryOpsHistory.Unlock()
+	return append([]MemoryOps(nil), memoryOpsHistory.ops...)
+}
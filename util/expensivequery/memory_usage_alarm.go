@@ -138,6 +138,12 @@ func (record *memoryUsageAlarm) doRecord(memUsage uint64, instanceMemoryUsage ui
 	fields = append(fields, zap.Any("record path", record.tmpDir))
 
 	logutil.BgLogger().Warn("tidb-server has the risk of OOM. Running SQLs and heap profile will be recorded in record path", fields...)
+	recordMemoryOps(MemoryOps{
+		Time:          record.lastCheckTime,
+		Ops:           MemoryOpsRecord,
+		MemoryLimit:   uint64(float64(record.serverMemoryQuota) * record.memoryUsageAlarmRatio),
+		MemoryCurrent: memUsage,
+	})
 
 	if record.err = disk.CheckAndCreateDir(record.tmpDir); record.err != nil {
 		return
@@ -0,0 +1,104 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expensivequery
+
+import (
+	"runtime/metrics"
+	"time"
+
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
+)
+
+// killingTimeout is how long the killer waits for a cancelled statement to exit
+// before it picks another victim.
+const killingTimeout = 30 * time.Second
+
+// serverMemoryLimitKiller cancels the statement which consumes the most memory when
+// the memory usage of the tidb-server exceeds tidb_server_memory_limit.
+type serverMemoryLimitKiller struct {
+	// The statement being cancelled is identified by its connection and start time.
+	// No other statement is cancelled until it exits or killingTimeout is reached.
+	isKilling    bool
+	connID       uint64
+	sqlStartTime time.Time
+	killTime     time.Time
+}
+
+// instanceMemoryUsage returns the memory usage of the tidb-server, it's a variable for testing.
+// It's the same as HeapInuse of runtime.MemStats, but it's read from runtime/metrics, which doesn't
+// stop the world like runtime.ReadMemStats, so it's cheap enough to be checked on every tick.
+var instanceMemoryUsage = func() uint64 {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/memory/classes/heap/unused:bytes"},
+	}
+	metrics.Read(samples)
+	var usage uint64
+	for _, sample := range samples {
+		if sample.Value.Kind() == metrics.KindUint64 {
+			usage += sample.Value.Uint64()
+		}
+	}
+	return usage
+}
+
+func (k *serverMemoryLimitKiller) killIfNeeded(sm util.SessionManager, limit uint64) {
+	if k.isKilling {
+		if info, ok := sm.GetProcessInfo(k.connID); ok && info.Time.Equal(k.sqlStartTime) && time.Since(k.killTime) < killingTimeout {
+			return
+		}
+		k.isKilling = false
+	}
+	if limit == 0 {
+		return
+	}
+	memoryUsage := instanceMemoryUsage()
+	if memoryUsage <= limit {
+		return
+	}
+
+	var victim *util.ProcessInfo
+	var victimMem int64
+	for _, info := range sm.ShowProcessList() {
+		if len(info.Info) == 0 || info.StmtCtx == nil || info.StmtCtx.MemTracker == nil {
+			continue
+		}
+		if consumed := info.StmtCtx.MemTracker.BytesConsumed(); consumed > victimMem {
+			victim, victimMem = info, consumed
+		}
+	}
+	if victim == nil {
+		return
+	}
+
+	k.killTime = time.Now()
+	fields := make([]zap.Field, 0, 24)
+	fields = append(fields, zap.Uint64("tidb_server_memory_limit", limit))
+	fields = append(fields, zap.Uint64("tidb-server memory usage", memoryUsage))
+	fields = append(fields, zap.String("mem_consumed", memory.FormatBytes(victimMem)))
+	fields = append(fields, genLogFields(k.killTime.Sub(victim.Time), victim)...)
+	logutil.BgLogger().Warn("tidb-server memory usage exceeds tidb_server_memory_limit, cancel the statement consuming the most memory", fields...)
+
+	k.isKilling = true
+	k.connID = victim.ID
+	k.sqlStartTime = victim.Time
+	sm.Kill(victim.ID, true)
+	ops := MemoryOps{Time: k.killTime, Ops: MemoryOpsKill, MemoryLimit: limit, MemoryCurrent: memoryUsage}
+	ops.setStatement(victim, victimMem)
+	recordMemoryOps(ops)
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expensivequery
+
+import (
+	"crypto/tls"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/session/txninfo"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSessionManager struct {
+	util.SessionManager
+	ps     map[uint64]*util.ProcessInfo
+	killed []uint64
+}
+
+func (msm *mockSessionManager) ShowProcessList() map[uint64]*util.ProcessInfo {
+	return msm.ps
+}
+
+func (msm *mockSessionManager) ShowTxnList() []*txninfo.TxnInfo {
+	return nil
+}
+
+func (msm *mockSessionManager) GetProcessInfo(id uint64) (*util.ProcessInfo, bool) {
+	info, ok := msm.ps[id]
+	return info, ok
+}
+
+func (msm *mockSessionManager) Kill(connectionID uint64, query bool) {
+	msm.killed = append(msm.killed, connectionID)
+}
+
+func (msm *mockSessionManager) UpdateTLSConfig(*tls.Config) {}
+
+func newProcessInfoWithMem(id uint64, sql string, consumed int64) *util.ProcessInfo {
+	mem := memory.NewTracker(0, -1)
+	mem.Consume(consumed)
+	return &util.ProcessInfo{
+		ID:   id,
+		Info: sql,
+		Time: time.Now(),
+		StatsInfo: func(interface{}) map[string]uint64 {
+			return nil
+		},
+		StmtCtx: &stmtctx.StatementContext{
+			MemTracker: mem,
+		},
+	}
+}
+
+func TestServerMemoryLimitKiller(t *testing.T) {
+	usage := uint64(100)
+	origin := instanceMemoryUsage
+	instanceMemoryUsage = func() uint64 { return usage }
+	defer func() { instanceMemoryUsage = origin }()
+
+	memoryOpsHistory.ops = nil
+	sm := &mockSessionManager{ps: map[uint64]*util.ProcessInfo{
+		1: newProcessInfoWithMem(1, "select 1", 10),
+		2: newProcessInfoWithMem(2, "select 2", 30),
+		3: newProcessInfoWithMem(3, "", 50),
+	}}
+	killer := &serverMemoryLimitKiller{}
+
+	// No limit or the limit is not exceeded.
+	killer.killIfNeeded(sm, 0)
+	killer.killIfNeeded(sm, 100)
+	assert.Empty(t, sm.killed)
+
+	// The idle connection is skipped even if its tracker keeps some memory.
+	killer.killIfNeeded(sm, 90)
+	assert.Equal(t, []uint64{2}, sm.killed)
+
+	// Don't kill another statement while the victim is exiting.
+	killer.killIfNeeded(sm, 90)
+	assert.Equal(t, []uint64{2}, sm.killed)
+
+	// The victim exits, pick the next one.
+	delete(sm.ps, 2)
+	killer.killIfNeeded(sm, 90)
+	assert.Equal(t, []uint64{2, 1}, sm.killed)
+
+	// The victim runs a new statement and the memory usage drops.
+	sm.ps[1] = newProcessInfoWithMem(1, "select 1", 10)
+	usage = 50
+	killer.killIfNeeded(sm, 90)
+	assert.Equal(t, []uint64{2, 1}, sm.killed)
+	assert.False(t, killer.isKilling)
+
+	// The kills are recorded in the memory operation history.
+	history := GetMemoryOpsHistory()
+	assert.Len(t, history, 2)
+	for i, connID := range sm.killed {
+		assert.Equal(t, MemoryOpsKill, history[i].Ops)
+		assert.Equal(t, connID, history[i].ConnID)
+		assert.Equal(t, uint64(90), history[i].MemoryLimit)
+		assert.Equal(t, uint64(100), history[i].MemoryCurrent)
+	}
+	assert.Equal(t, "select 2", history[0].SQLText)
+	assert.Equal(t, int64(30), history[0].Mem)
+}
+
+func TestMemoryOpsHistory(t *testing.T) {
+	memoryOpsHistory.ops = nil
+	for i := 0; i < memoryOpsHistoryCapacity+10; i++ {
+		recordMemoryOps(MemoryOps{Ops: MemoryOpsRecord, MemoryCurrent: uint64(i)})
+	}
+	// The oldest operations are dropped.
+	history := GetMemoryOpsHistory()
+	assert.Len(t, history, memoryOpsHistoryCapacity)
+	assert.Equal(t, uint64(10), history[0].MemoryCurrent)
+	assert.Equal(t, uint64(memoryOpsHistoryCapacity+9), history[len(history)-1].MemoryCurrent)
+}
+
+func TestInstanceMemoryUsage(t *testing.T) {
+	stats := &runtime.MemStats{}
+	runtime.ReadMemStats(stats)
+	usage := instanceMemoryUsage()
+	assert.Greater(t, usage, uint64(0))
+	assert.InEpsilon(t, stats.HeapInuse, usage, 0.1)
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/pingcap/log"
 	"github.com/stretchr/testify/require"
@@ -143,6 +144,20 @@ func TestSlowQueryLoggerCreation(t *testing.T) {
 	require.Equal(t, fileConf.FileLogConfig, slowQueryConf.File)
 }
 
+func TestSlowLogEncoder(t *testing.T) {
+	entry := zapcore.Entry{Time: time.Date(2019, 4, 28, 15, 24, 4, 309074000, time.FixedZone("", 8*3600))}
+	enc := &slowLogEncoder{}
+	entry.Message = "# Txn_start_ts: 1\nselect 1;"
+	b, err := enc.EncodeEntry(entry, nil)
+	require.NoError(t, err)
+	require.Equal(t, "# Time: 2019-04-28T15:24:04.309074+08:00\n# Txn_start_ts: 1\nselect 1;\n", b.String())
+
+	entry.Message = `{"Txn_start_ts":1,"Query":"select 1;"}`
+	b, err = enc.EncodeEntry(entry, nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"Time":"2019-04-28T15:24:04.309074+08:00","Txn_start_ts":1,"Query":"select 1;"}`+"\n", b.String())
+}
+
 func TestGlobalLoggerReplace(t *testing.T) {
 	fileCfg := FileLogConfig{log.FileLogConfig{Filename: "zap_log", MaxDays: 0, MaxSize: 4096}}
 	conf := NewLogConfig("info", DefaultLogFormat, "", fileCfg, false)
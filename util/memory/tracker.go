@@ -149,6 +149,27 @@ func (t *Tracker) CheckExceed() bool {
 	return atomic.LoadInt64(&t.bytesConsumed) >= t.bytesHardLimit && t.bytesHardLimit > 0
 }
 
+// ExceededAncestor returns the nearest non-global tracker which exceeds its bytes limit
+// on the path from this tracker to the root, and nil if there is no such tracker.
+func (t *Tracker) ExceededAncestor() *Tracker {
+	for tracker := t; tracker != nil; tracker = tracker.getParent() {
+		if !tracker.isGlobal && tracker.CheckExceed() {
+			return tracker
+		}
+	}
+	return nil
+}
+
+// HasAncestor checks whether the ancestor is on the path from this tracker to the root.
+func (t *Tracker) HasAncestor(ancestor *Tracker) bool {
+	for tracker := t; tracker != nil; tracker = tracker.getParent() {
+		if tracker == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
 // SetActionOnExceed sets the action when memory usage exceeds bytesHardLimit.
 func (t *Tracker) SetActionOnExceed(a ActionOnExceed) {
 	t.actionMuForHardLimit.Lock()
@@ -556,4 +577,6 @@ const (
 	LabelForIndexJoinInnerWorker int = -20
 	// LabelForIndexJoinOuterWorker represents the label of IndexJoin OuterWorker
 	LabelForIndexJoinOuterWorker int = -21
+	// LabelForAnalyzeMemory represents the label of the memory of the analyze samples
+	LabelForAnalyzeMemory int = -22
 )
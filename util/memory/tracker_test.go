@@ -178,6 +178,31 @@ func TestDetach(t *testing.T) {
 	require.Nil(t, child.getParent())
 }
 
+func TestExceededAncestor(t *testing.T) {
+	global := NewGlobalTracker(1, 10)
+	root := NewTracker(2, 100)
+	parent := NewTracker(3, 50)
+	child := NewTracker(4, -1)
+	root.AttachToGlobalTracker(global)
+	parent.AttachTo(root)
+	child.AttachTo(parent)
+	require.True(t, child.HasAncestor(child))
+	require.True(t, child.HasAncestor(root))
+	require.True(t, child.HasAncestor(global))
+	require.False(t, root.HasAncestor(child))
+
+	// The global tracker is never returned.
+	child.Consume(20)
+	require.Nil(t, child.ExceededAncestor())
+	child.Consume(40)
+	require.Equal(t, parent, child.ExceededAncestor())
+	parent.SetBytesLimit(-1)
+	require.Nil(t, child.ExceededAncestor())
+	child.Consume(40)
+	require.Equal(t, root, child.ExceededAncestor())
+	require.Nil(t, NewTracker(5, -1).ExceededAncestor())
+}
+
 func TestReplaceChild(t *testing.T) {
 	oldChild := NewTracker(1, -1)
 	oldChild.Consume(100)
@@ -303,12 +303,15 @@ const (
 	DNS = SANType("DNS")
 	// IP indicates ip info in SAN.
 	IP = SANType("IP")
+	// EmailAddress indicates email info in SAN.
+	EmailAddress = SANType("EMAIL")
 )
 
 var supportSAN = map[SANType]struct{}{
-	URI: {},
-	DNS: {},
-	IP:  {},
+	URI:          {},
+	DNS:          {},
+	IP:           {},
+	EmailAddress: {},
 }
 
 // ParseAndCheckSAN parses and check SAN str.
@@ -422,6 +425,10 @@ func ColumnToProto(c *model.ColumnInfo) *tipb.ColumnInfo {
 		Elems:     c.Elems,
 	}
 	pc.Tp = int32(c.FieldType.Tp)
+	// The storage layer doesn't know the VECTOR type, it reads vectors as plain bytes.
+	if c.FieldType.Tp == mysql.TypeTiDBVectorFloat32 {
+		pc.Tp = int32(mysql.TypeLongBlob)
+	}
 	return pc
 }
 
@@ -474,22 +481,15 @@ func LoadTLSCertificates(ca, key, cert string, autoTLS bool, rsaKeySize int) (tl
 	}
 
 	requireTLS := config.GetGlobalConfig().Security.RequireSecureTransport
-	var minTLSVersion uint16 = tls.VersionTLS11
-	switch tlsver := config.GetGlobalConfig().Security.MinTLSVersion; tlsver {
-	case "TLSv1.0":
-		minTLSVersion = tls.VersionTLS10
-	case "TLSv1.1":
-		minTLSVersion = tls.VersionTLS11
-	case "TLSv1.2":
-		minTLSVersion = tls.VersionTLS12
-	case "TLSv1.3":
-		minTLSVersion = tls.VersionTLS13
-	case "":
-	default:
+	tlsver := config.GetGlobalConfig().Security.MinTLSVersion
+	minTLSVersion, err := ParseTLSVersion(tlsver)
+	if err != nil {
 		logutil.BgLogger().Warn(
 			"Invalid TLS version, using default instead",
 			zap.String("tls-version", tlsver),
 		)
+		minTLSVersion = tls.VersionTLS11
+		err = nil
 	}
 	if minTLSVersion < tls.VersionTLS12 {
 		logutil.BgLogger().Warn(
@@ -521,18 +521,14 @@ func LoadTLSCertificates(ca, key, cert string, autoTLS bool, rsaKeySize int) (tl
 		}
 	}
 
-	// This excludes ciphers listed in tls.InsecureCipherSuites() and can be used to filter out more
-	var cipherSuites []uint16
-	var cipherNames []string
-	for _, sc := range tls.CipherSuites() {
-		switch sc.ID {
-		case tls.TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA, tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA:
-			logutil.BgLogger().Info("Disabling weak cipherSuite", zap.String("cipherSuite", sc.Name))
-		default:
-			cipherNames = append(cipherNames, sc.Name)
-			cipherSuites = append(cipherSuites, sc.ID)
-		}
-
+	cipherSuites, err := ParseTLSCipherSuites(config.GetGlobalConfig().Security.TLSCipherSuites)
+	if err != nil {
+		logutil.BgLogger().Warn("load TLS cipher suites failed", zap.Error(err))
+		return
+	}
+	cipherNames := make([]string, 0, len(cipherSuites))
+	for _, id := range cipherSuites {
+		cipherNames = append(cipherNames, tls.CipherSuiteName(id))
 	}
 	logutil.BgLogger().Info("Enabled ciphersuites", zap.Strings("cipherNames", cipherNames))
 
@@ -547,6 +543,54 @@ func LoadTLSCertificates(ca, key, cert string, autoTLS bool, rsaKeySize int) (tl
 	return
 }
 
+// ParseTLSVersion parses the minimum TLS version like "TLSv1.2". An empty string means the default version TLSv1.1.
+func ParseTLSVersion(tlsver string) (uint16, error) {
+	switch tlsver {
+	case "TLSv1.0":
+		return tls.VersionTLS10, nil
+	case "TLSv1.1", "":
+		return tls.VersionTLS11, nil
+	case "TLSv1.2":
+		return tls.VersionTLS12, nil
+	case "TLSv1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, errors.Errorf("invalid TLS version %s", tlsver)
+}
+
+// ParseTLSCipherSuites parses the names of the cipher suites used by TLSv1.2 and earlier versions.
+// If no name is given, all the secure cipher suites except the weak 3DES ones are returned.
+// The cipher suites of TLSv1.3 are not configurable.
+func ParseTLSCipherSuites(names []string) ([]uint16, error) {
+	var cipherSuites []uint16
+	if len(names) == 0 {
+		// This excludes ciphers listed in tls.InsecureCipherSuites() and can be used to filter out more
+		for _, sc := range tls.CipherSuites() {
+			switch sc.ID {
+			case tls.TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA, tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA:
+			default:
+				cipherSuites = append(cipherSuites, sc.ID)
+			}
+		}
+		return cipherSuites, nil
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, sc := range tls.CipherSuites() {
+			if sc.Name == name || tlsCipherString[sc.ID] == name {
+				cipherSuites = append(cipherSuites, sc.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("unsupported or insecure TLS cipher suite %s", name)
+		}
+	}
+	return cipherSuites, nil
+}
+
 // IsTLSExpiredError checks error is caused by TLS expired.
 func IsTLSExpiredError(err error) bool {
 	err = errors.Cause(err)
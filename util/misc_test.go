@@ -16,6 +16,7 @@ package util
 
 import (
 	"bytes"
+	"crypto/tls"
 	"crypto/x509/pkix"
 	"testing"
 	"time"
@@ -91,6 +92,37 @@ func TestX509NameParseMatch(t *testing.T) {
 	assert.Equal(t, result, X509NameOnline(check))
 }
 
+func TestParseTLSPolicy(t *testing.T) {
+	version, err := ParseTLSVersion("")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS11), version)
+	version, err = ParseTLSVersion("TLSv1.3")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), version)
+	_, err = ParseTLSVersion("SSLv3")
+	assert.Error(t, err)
+
+	cipherSuites, err := ParseTLSCipherSuites(nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, cipherSuites, tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA)
+	// Both the Go names and the OpenSSL names are supported.
+	cipherSuites, err = ParseTLSCipherSuites([]string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", " ECDHE-ECDSA-AES256-GCM-SHA384"})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}, cipherSuites)
+	_, err = ParseTLSCipherSuites([]string{"TLS_RSA_WITH_RC4_128_SHA"})
+	assert.Error(t, err)
+}
+
+func TestParseAndCheckSAN(t *testing.T) {
+	sans, err := ParseAndCheckSAN("URI:spiffe://example.com/a, DNS:example.com,email:a@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"spiffe://example.com/a"}, sans[URI])
+	assert.Equal(t, []string{"example.com"}, sans[DNS])
+	assert.Equal(t, []string{"a@example.com"}, sans[EmailAddress])
+	_, err = ParseAndCheckSAN("UID:1")
+	assert.Error(t, err)
+}
+
 func TestBasicFuncGetStack(t *testing.T) {
 	b := GetStack()
 	assert.Less(t, len(b), 4096)
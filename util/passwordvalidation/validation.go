@@ -0,0 +1,172 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package passwordvalidation
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/sessionctx/variable"
+)
+
+// The policies of `validate_password.policy`, each policy includes the checks of the previous one.
+const (
+	// PolicyLow only checks the length of the password.
+	PolicyLow = "LOW"
+	// PolicyMedium also checks the numeric, lowercase, uppercase and special characters.
+	PolicyMedium = "MEDIUM"
+	// PolicyStrong also checks the substrings of the password against the dictionary.
+	PolicyStrong = "STRONG"
+)
+
+// minDictionaryWordLength is the minimum length of the substrings checked against the dictionary.
+const minDictionaryWordLength = 4
+
+// Options is the rules of the validate_password component.
+type Options struct {
+	Policy           string
+	Length           int
+	MixedCaseCount   int
+	NumberCount      int
+	SpecialCharCount int
+	CheckUserName    bool
+	// Dictionary is the lowercase words which must not be a substring of the password.
+	Dictionary []string
+}
+
+// LoadOptions reads the rules from the `validate_password.*` system variables,
+// it returns nil if the validation is disabled.
+func LoadOptions(globalVars variable.GlobalVarAccessor) (*Options, error) {
+	enable, err := globalVars.GetGlobalSysVar(variable.ValidatePasswordEnable)
+	if err != nil {
+		return nil, err
+	}
+	if !variable.TiDBOptOn(enable) {
+		return nil, nil
+	}
+
+	opts := &Options{}
+	if opts.Policy, err = globalVars.GetGlobalSysVar(variable.ValidatePasswordPolicy); err != nil {
+		return nil, err
+	}
+	for name, value := range map[string]*int{
+		variable.ValidatePasswordLength:           &opts.Length,
+		variable.ValidatePasswordMixedCaseCount:   &opts.MixedCaseCount,
+		variable.ValidatePasswordNumberCount:      &opts.NumberCount,
+		variable.ValidatePasswordSpecialCharCount: &opts.SpecialCharCount,
+	} {
+		val, err := globalVars.GetGlobalSysVar(name)
+		if err != nil {
+			return nil, err
+		}
+		if *value, err = strconv.Atoi(val); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	checkUserName, err := globalVars.GetGlobalSysVar(variable.ValidatePasswordCheckUserName)
+	if err != nil {
+		return nil, err
+	}
+	opts.CheckUserName = variable.TiDBOptOn(checkUserName)
+	dictionary, err := globalVars.GetGlobalSysVar(variable.ValidatePasswordDictionary)
+	if err != nil {
+		return nil, err
+	}
+	opts.Dictionary = ParseDictionary(dictionary)
+	return opts, nil
+}
+
+// ParseDictionary parses the `validate_password.dictionary`, the words are separated by semicolons.
+func ParseDictionary(dictionary string) []string {
+	var words []string
+	for _, word := range strings.Split(dictionary, ";") {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if utf8.RuneCountInString(word) >= minDictionaryWordLength {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// MinLength returns the effective minimum length of the passwords, which is never less than
+// the number of the characters required by the other rules.
+func (opts *Options) MinLength() int {
+	minLength := opts.NumberCount + opts.SpecialCharCount + 2*opts.MixedCaseCount
+	if opts.Length > minLength {
+		return opts.Length
+	}
+	return minLength
+}
+
+// Validate returns an error describing the first rule the password of the user doesn't satisfy.
+func (opts *Options) Validate(userName, pwd string) error {
+	lowerPwd := strings.ToLower(pwd)
+	if opts.CheckUserName && userName != "" {
+		lowerUserName := strings.ToLower(userName)
+		if lowerPwd == lowerUserName || lowerPwd == reverse(lowerUserName) {
+			return errors.New("the password can't be the user name or the user name in reverse")
+		}
+	}
+	if minLength := opts.MinLength(); utf8.RuneCountInString(pwd) < minLength {
+		return errors.Errorf("the password must contain at least %d characters", minLength)
+	}
+	if opts.Policy == PolicyLow {
+		return nil
+	}
+
+	var lowerCount, upperCount, numberCount, specialCount int
+	for _, r := range pwd {
+		switch {
+		case unicode.IsLower(r):
+			lowerCount++
+		case unicode.IsUpper(r):
+			upperCount++
+		case unicode.IsDigit(r):
+			numberCount++
+		case !unicode.IsLetter(r):
+			specialCount++
+		}
+	}
+	if lowerCount < opts.MixedCaseCount || upperCount < opts.MixedCaseCount {
+		return errors.Errorf("the password must contain at least %d lowercase and %d uppercase characters", opts.MixedCaseCount, opts.MixedCaseCount)
+	}
+	if numberCount < opts.NumberCount {
+		return errors.Errorf("the password must contain at least %d numeric characters", opts.NumberCount)
+	}
+	if specialCount < opts.SpecialCharCount {
+		return errors.Errorf("the password must contain at least %d special characters", opts.SpecialCharCount)
+	}
+	if opts.Policy != PolicyStrong {
+		return nil
+	}
+
+	for _, word := range opts.Dictionary {
+		if strings.Contains(lowerPwd, word) {
+			return errors.New("the password contains a word in the dictionary")
+		}
+	}
+	return nil
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package passwordvalidation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	opts := &Options{
+		Policy:           PolicyMedium,
+		Length:           8,
+		MixedCaseCount:   1,
+		NumberCount:      1,
+		SpecialCharCount: 1,
+		CheckUserName:    true,
+		Dictionary:       ParseDictionary("password; abc ;Secret"),
+	}
+	require.Equal(t, []string{"password", "secret"}, opts.Dictionary)
+
+	tests := []struct {
+		policy string
+		pwd    string
+		errMsg string
+	}{
+		{PolicyLow, "Alice", "the password can't be the user name or the user name in reverse"},
+		{PolicyLow, "ecila", "the password can't be the user name or the user name in reverse"},
+		{PolicyLow, "abc", "the password must contain at least 8 characters"},
+		{PolicyLow, "abcdefgh", ""},
+		{PolicyMedium, "abcdefgh", "the password must contain at least 1 lowercase and 1 uppercase characters"},
+		{PolicyMedium, "Abcdefgh", "the password must contain at least 1 numeric characters"},
+		{PolicyMedium, "Abcdefg1", "the password must contain at least 1 special characters"},
+		{PolicyMedium, "Abcdef1!", ""},
+		{PolicyMedium, "MyPassword1!", ""},
+		{PolicyStrong, "MyPassword1!", "the password contains a word in the dictionary"},
+		{PolicyStrong, "MySECRET1!", "the password contains a word in the dictionary"},
+		{PolicyStrong, "MyAbcdef1!", ""},
+	}
+	for _, test := range tests {
+		opts.Policy = test.policy
+		err := opts.Validate("alice", test.pwd)
+		if test.errMsg == "" {
+			require.NoError(t, err, test.pwd)
+		} else {
+			require.EqualError(t, err, test.errMsg, test.pwd)
+		}
+	}
+
+	opts.CheckUserName = false
+	opts.Policy = PolicyLow
+	opts.Length = 4
+	require.NoError(t, opts.Validate("alice12", "alice12"))
+	// The minimum length covers the characters required by the other rules.
+	opts.MixedCaseCount = 2
+	require.Equal(t, 6, opts.MinLength())
+	require.EqualError(t, opts.Validate("u", "abcde"), "the password must contain at least 6 characters")
+}
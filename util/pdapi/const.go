@@ -24,4 +24,6 @@ const (
 	Stores     = "/pd/api/v1/stores"
 	Status     = "/pd/api/v1/status"
 	Config     = "/pd/api/v1/config"
+
+	RotateMasterKey = "/pd/api/v1/encryption/master-key/rotate"
 )
@@ -78,6 +78,7 @@ func TestPlanIDChanged(t *testing.T) {
 		{typeCTE, 50},
 		{typeCTEDefinition, 51},
 		{typeCTETable, 52},
+		{typeJSONTable, 53},
 	}
 
 	for _, testcase := range testCases {
@@ -45,13 +45,22 @@ type ProcessInfo struct {
 	CurTxnStartTS    uint64
 	StmtCtx          *stmtctx.StatementContext
 	StatsInfo        func(interface{}) map[string]uint64
-	// MaxExecutionTime is the timeout for select statement, in milliseconds.
-	// If the query takes too long, kill it.
+	// MaxExecutionTime is the timeout for select statement, in milliseconds. The statement is killed by the timer of
+	// the statement when it takes too long.
 	MaxExecutionTime uint64
+	// GCLifeTime is the GC life time required by the session, the GC safe point is held back to keep the versions
+	// within it.
+	GCLifeTime time.Duration
+	// ResourceGroupName is the resource group of the session, the query is a runaway query if it exceeds the
+	// QUERY_LIMIT of the group.
+	ResourceGroupName string
+	// ConnectionAttrs is the connection attributes sent by the client in the handshake.
+	ConnectionAttrs map[string]string
 
 	State                     uint16
 	Command                   byte
 	ExceedExpensiveTimeThresh bool
+	ExceedRunawayLimit        bool
 	RedactSQL                 bool
 }
 
@@ -96,6 +105,9 @@ func (pi *ProcessInfo) txnStartTs(tz *time.Location) (txnStart string) {
 	return
 }
 
+// ProgramNameAttr is the connection attribute which identifies the client program, it's shown in the processlist.
+const ProgramNameAttr = "program_name"
+
 // ToRow returns []interface{} for the row data of
 // "SELECT * FROM INFORMATION_SCHEMA.PROCESSLIST".
 func (pi *ProcessInfo) ToRow(tz *time.Location) []interface{} {
@@ -109,7 +121,7 @@ func (pi *ProcessInfo) ToRow(tz *time.Location) []interface{} {
 			diskConsumed = pi.StmtCtx.DiskTracker.BytesConsumed()
 		}
 	}
-	return append(pi.ToRowForShow(true), pi.Digest, bytesConsumed, diskConsumed, pi.txnStartTs(tz))
+	return append(pi.ToRowForShow(true), pi.Digest, bytesConsumed, diskConsumed, pi.txnStartTs(tz), pi.ConnectionAttrs[ProgramNameAttr])
 }
 
 // ascServerStatus is a slice of all defined server status in ascending order.
@@ -0,0 +1,443 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxyprotocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Ref: https://www.haproxy.org/download/2.3/doc/proxy-protocol.txt .
+const (
+	v1MaxHeaderLen = 107
+	v2HeaderLen    = 16
+
+	v2CmdLocal = 0x00
+	v2CmdProxy = 0x01
+
+	v2AddrLenInet  = 12
+	v2AddrLenInet6 = 36
+	v2AddrLenUnix  = 216
+)
+
+// The types of the TLVs in the PROXY protocol v2 header.
+const (
+	TLVTypeALPN      byte = 0x01
+	TLVTypeAuthority byte = 0x02
+	TLVTypeCRC32C    byte = 0x03
+	TLVTypeNoop      byte = 0x04
+	TLVTypeUniqueID  byte = 0x05
+	TLVTypeSSL       byte = 0x20
+	TLVTypeNetNS     byte = 0x30
+)
+
+var (
+	// ErrProxyProtocolV1HeaderInvalid means the PROXY protocol v1 header is invalid.
+	ErrProxyProtocolV1HeaderInvalid = errors.New("PROXY Protocol v1 header is invalid")
+	// ErrProxyProtocolV2HeaderInvalid means the PROXY protocol v2 header is invalid.
+	ErrProxyProtocolV2HeaderInvalid = errors.New("PROXY Protocol v2 header is invalid")
+	// ErrHeaderReadTimeout means the PROXY protocol header is not received in time.
+	ErrHeaderReadTimeout = errors.New("Header read timeout")
+
+	v2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+	v1Signature = []byte("PROXY")
+
+	_ net.Conn     = &Conn{}
+	_ net.Listener = &Listener{}
+)
+
+// IsProxyProtocolError returns whether the error is caused by an invalid PROXY protocol header.
+// The listener keeps accepting the other connections after such errors.
+func IsProxyProtocolError(err error) bool {
+	return err == ErrProxyProtocolV1HeaderInvalid ||
+		err == ErrProxyProtocolV2HeaderInvalid ||
+		err == ErrHeaderReadTimeout
+}
+
+// TLV is a type-length-value vector carried by the PROXY protocol v2 header.
+type TLV struct {
+	Type  byte
+	Value []byte
+}
+
+// Networks is the allowlist of the proxies which are trusted to send the PROXY protocol header.
+type Networks struct {
+	str      string
+	allowAll bool
+	nets     []*net.IPNet
+}
+
+// ParseNetworks parses the allowed IPs or CIDRs separated by `,`, and `*` means any address is allowed.
+func ParseNetworks(networks string) (*Networks, error) {
+	n := &Networks{str: networks}
+	if strings.TrimSpace(networks) == "*" {
+		n.allowAll = true
+		return n, nil
+	}
+	for _, item := range strings.Split(networks, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid PROXY protocol network %q", item)
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			item = fmt.Sprintf("%s/%d", item, bits)
+		}
+		_, ipNet, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, err
+		}
+		n.nets = append(n.nets, ipNet)
+	}
+	return n, nil
+}
+
+// String returns the networks in the same form as they are parsed.
+func (n *Networks) String() string {
+	return n.str
+}
+
+// Contains returns whether the address is in the networks. Only the TCP addresses can be allowed
+// unless any address is allowed.
+func (n *Networks) Contains(addr net.Addr) bool {
+	if n.allowAll {
+		return true
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, ipNet := range n.nets {
+		if ipNet.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+type connErr struct {
+	conn net.Conn
+	err  error
+}
+
+// Listener reads the PROXY protocol header from the connections of the allowed networks, and returns
+// the connections with the real client addresses. The connections from the other networks are returned
+// as they are.
+type Listener struct {
+	listener      net.Listener
+	networks      atomic.Value // *Networks
+	headerTimeout time.Duration
+	acceptQueue   chan connErr
+	done          chan struct{}
+	closeOnce     sync.Once
+}
+
+// NewListener creates a PROXY protocol listener on the base listener.
+// The allowed networks can be changed by SetNetworks after it's created.
+func NewListener(listener net.Listener, networks string, headerTimeout time.Duration) (*Listener, error) {
+	n, err := ParseNetworks(networks)
+	if err != nil {
+		return nil, err
+	}
+	l := &Listener{
+		listener:      listener,
+		headerTimeout: headerTimeout,
+		acceptQueue:   make(chan connErr, 1),
+		done:          make(chan struct{}),
+	}
+	l.networks.Store(n)
+	go l.acceptLoop()
+	return l, nil
+}
+
+// Networks returns the allowed networks.
+func (l *Listener) Networks() *Networks {
+	return l.networks.Load().(*Networks)
+}
+
+// SetNetworks changes the allowed networks, it takes effect on the new connections.
+func (l *Listener) SetNetworks(networks string) error {
+	n, err := ParseNetworks(networks)
+	if err != nil {
+		return err
+	}
+	l.networks.Store(n)
+	return nil
+}
+
+func (l *Listener) acceptLoop() {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			l.push(connErr{conn, err})
+			return
+		}
+		if !l.Networks().Contains(conn.RemoteAddr()) {
+			l.push(connErr{conn, nil})
+			continue
+		}
+		// Read the header in another goroutine so that a slow client does not block the others.
+		go func() {
+			ppConn, err := newConn(conn, l.headerTimeout)
+			if err != nil {
+				_ = conn.Close()
+				l.push(connErr{nil, err})
+				return
+			}
+			l.push(connErr{ppConn, nil})
+		}()
+	}
+}
+
+func (l *Listener) push(ce connErr) {
+	select {
+	case l.acceptQueue <- ce:
+	case <-l.done:
+		if ce.conn != nil {
+			_ = ce.conn.Close()
+		}
+	}
+}
+
+// Accept waits for and returns the next connection.
+// If the PROXY protocol header is invalid, an error satisfying IsProxyProtocolError is returned.
+func (l *Listener) Accept() (net.Conn, error) {
+	select {
+	case ce := <-l.acceptQueue:
+		return ce.conn, ce.err
+	case <-l.done:
+		return nil, &net.OpError{Op: "accept", Net: l.Addr().Network(), Addr: l.Addr(), Err: errors.New("use of closed network connection")}
+	}
+}
+
+// Close closes the listener.
+func (l *Listener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+	return l.listener.Close()
+}
+
+// Addr returns the listener's network address.
+func (l *Listener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// Conn is a connection accepted through PROXY protocol.
+type Conn struct {
+	net.Conn
+	clientAddr net.Addr
+	tlvs       []TLV
+}
+
+func newConn(conn net.Conn, headerTimeout time.Duration) (*Conn, error) {
+	c := &Conn{Conn: conn, clientAddr: conn.RemoteAddr()}
+	// All the header data should be read in headerTimeout.
+	if err := conn.SetReadDeadline(time.Now().Add(headerTimeout)); err != nil {
+		return nil, err
+	}
+	if err := c.readHeader(); err != nil {
+		return nil, err
+	}
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// readHeader reads the header exactly, since the client waits for the handshake of the server
+// and nothing is sent after the header.
+func (c *Conn) readHeader() error {
+	buf := make([]byte, len(v1Signature), v2HeaderLen)
+	if err := c.readFull(buf); err != nil {
+		return err
+	}
+	if bytes.Equal(buf, v1Signature) {
+		return c.readV1Header(buf)
+	}
+	if bytes.Equal(buf, v2Signature[:len(v1Signature)]) {
+		buf = buf[:v2HeaderLen]
+		if err := c.readFull(buf[len(v1Signature):]); err != nil {
+			return err
+		}
+		return c.readV2Header(buf)
+	}
+	return ErrProxyProtocolV1HeaderInvalid
+}
+
+func (c *Conn) readFull(buf []byte) error {
+	if _, err := io.ReadFull(c.Conn, buf); err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return ErrHeaderReadTimeout
+		}
+		return ErrProxyProtocolV1HeaderInvalid
+	}
+	return nil
+}
+
+func (c *Conn) readV1Header(buf []byte) error {
+	b := make([]byte, 1)
+	for {
+		if len(buf) >= v1MaxHeaderLen {
+			return ErrProxyProtocolV1HeaderInvalid
+		}
+		if err := c.readFull(b); err != nil {
+			return err
+		}
+		buf = append(buf, b[0])
+		if b[0] == '\n' {
+			break
+		}
+	}
+	if buf[len(buf)-2] != '\r' {
+		return ErrProxyProtocolV1HeaderInvalid
+	}
+	parts := strings.Split(string(buf[:len(buf)-2]), " ")
+	if len(parts) < 2 {
+		return ErrProxyProtocolV1HeaderInvalid
+	}
+	switch parts[1] {
+	case "UNKNOWN":
+		// Keep the address of the connection.
+		return nil
+	case "TCP4", "TCP6":
+	default:
+		return ErrProxyProtocolV1HeaderInvalid
+	}
+	if len(parts) != 6 {
+		return ErrProxyProtocolV1HeaderInvalid
+	}
+	ip := net.ParseIP(parts[2])
+	port, err := strconv.ParseUint(parts[4], 10, 16)
+	if ip == nil || err != nil || (parts[1] == "TCP4") != (ip.To4() != nil) {
+		return ErrProxyProtocolV1HeaderInvalid
+	}
+	c.clientAddr = &net.TCPAddr{IP: ip, Port: int(port)}
+	return nil
+}
+
+func (c *Conn) readV2Header(header []byte) error {
+	if !bytes.Equal(header[:len(v2Signature)], v2Signature) || header[12]>>4 != 0x2 {
+		return ErrProxyProtocolV2HeaderInvalid
+	}
+	cmd, family := header[12]&0x0F, header[13]
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if err := c.readFull(payload); err != nil {
+		if err == ErrProxyProtocolV1HeaderInvalid {
+			return ErrProxyProtocolV2HeaderInvalid
+		}
+		return err
+	}
+	switch cmd {
+	case v2CmdLocal:
+		// The connection is established by the proxy itself, e.g. health checks, keep the address of the connection.
+		return nil
+	case v2CmdProxy:
+	default:
+		return ErrProxyProtocolV2HeaderInvalid
+	}
+
+	var addrLen int
+	switch family >> 4 {
+	case 0x0:
+		// AF_UNSPEC, keep the address of the connection.
+	case 0x1:
+		addrLen = v2AddrLenInet
+	case 0x2:
+		addrLen = v2AddrLenInet6
+	case 0x3:
+		addrLen = v2AddrLenUnix
+	default:
+		return ErrProxyProtocolV2HeaderInvalid
+	}
+	if len(payload) < addrLen {
+		return ErrProxyProtocolV2HeaderInvalid
+	}
+	switch addrLen {
+	case v2AddrLenInet:
+		c.clientAddr = &net.TCPAddr{IP: net.IP(payload[0:4]), Port: int(binary.BigEndian.Uint16(payload[8:10]))}
+	case v2AddrLenInet6:
+		c.clientAddr = &net.TCPAddr{IP: net.IP(payload[0:16]), Port: int(binary.BigEndian.Uint16(payload[32:34]))}
+	case v2AddrLenUnix:
+		c.clientAddr = &net.UnixAddr{Name: string(bytes.TrimRight(payload[0:108], "\x00")), Net: "unix"}
+	}
+
+	tlvs, err := parseTLVs(payload[addrLen:])
+	if err != nil {
+		return err
+	}
+	c.tlvs = tlvs
+	return nil
+}
+
+func parseTLVs(data []byte) ([]TLV, error) {
+	var tlvs []TLV
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, ErrProxyProtocolV2HeaderInvalid
+		}
+		tp, length := data[0], int(binary.BigEndian.Uint16(data[1:3]))
+		if len(data) < 3+length {
+			return nil, ErrProxyProtocolV2HeaderInvalid
+		}
+		if tp != TLVTypeNoop {
+			tlvs = append(tlvs, TLV{Type: tp, Value: data[3 : 3+length]})
+		}
+		data = data[3+length:]
+	}
+	return tlvs, nil
+}
+
+// RemoteAddr returns the address of the client behind the proxy.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.clientAddr
+}
+
+// ProxyAddr returns the address of the proxy.
+func (c *Conn) ProxyAddr() net.Addr {
+	return c.Conn.RemoteAddr()
+}
+
+// TLVs returns the TLVs in the PROXY protocol v2 header.
+func (c *Conn) TLVs() []TLV {
+	return c.tlvs
+}
+
+// TLV returns the value of the TLV with the type, the second return value is false if it doesn't exist.
+func (c *Conn) TLV(tp byte) ([]byte, bool) {
+	for _, tlv := range c.tlvs {
+		if tlv.Type == tp {
+			return tlv.Value, true
+		}
+	}
+	return nil, false
+}
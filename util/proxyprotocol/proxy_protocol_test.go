@@ -0,0 +1,196 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxyprotocol
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestListener(t *testing.T, networks string, headerTimeout time.Duration) *Listener {
+	base, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	l, err := NewListener(base, networks, headerTimeout)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, l.Close())
+	})
+	return l
+}
+
+// dialAndAccept sends the data from a new client connection and returns the accepted connection.
+func dialAndAccept(t *testing.T, l *Listener, data []byte) (net.Conn, net.Conn, error) {
+	client, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	if len(data) > 0 {
+		_, err = client.Write(data)
+		require.NoError(t, err)
+	}
+	conn, err := l.Accept()
+	if conn != nil {
+		t.Cleanup(func() {
+			_ = conn.Close()
+		})
+	}
+	return client, conn, err
+}
+
+func buildV2Header(cmd byte, family byte, addrs []byte, tlvs []TLV) []byte {
+	payload := append([]byte{}, addrs...)
+	for _, tlv := range tlvs {
+		payload = append(payload, tlv.Type, 0, 0)
+		binary.BigEndian.PutUint16(payload[len(payload)-2:], uint16(len(tlv.Value)))
+		payload = append(payload, tlv.Value...)
+	}
+	header := append([]byte{}, v2Signature...)
+	header = append(header, 0x20|cmd, family, 0, 0)
+	binary.BigEndian.PutUint16(header[14:16], uint16(len(payload)))
+	return append(header, payload...)
+}
+
+func TestParseNetworks(t *testing.T) {
+	n, err := ParseNetworks("*")
+	require.NoError(t, err)
+	require.True(t, n.Contains(&net.UnixAddr{Name: "/tmp/tidb.sock", Net: "unix"}))
+
+	n, err = ParseNetworks("192.168.1.0/24, 10.0.0.1,::1")
+	require.NoError(t, err)
+	require.Equal(t, "192.168.1.0/24, 10.0.0.1,::1", n.String())
+	require.True(t, n.Contains(&net.TCPAddr{IP: net.ParseIP("192.168.1.100")}))
+	require.True(t, n.Contains(&net.TCPAddr{IP: net.ParseIP("10.0.0.1")}))
+	require.False(t, n.Contains(&net.TCPAddr{IP: net.ParseIP("10.0.0.2")}))
+	require.True(t, n.Contains(&net.TCPAddr{IP: net.ParseIP("::1")}))
+	require.False(t, n.Contains(&net.UnixAddr{Name: "/tmp/tidb.sock", Net: "unix"}))
+
+	_, err = ParseNetworks("192.168.1.0/33")
+	require.Error(t, err)
+	_, err = ParseNetworks("localhost")
+	require.Error(t, err)
+}
+
+func TestV1Header(t *testing.T) {
+	l := newTestListener(t, "127.0.0.1", time.Second)
+	_, conn, err := dialAndAccept(t, l, []byte("PROXY TCP4 192.168.1.1 10.0.0.1 5000 4000\r\nhello"))
+	require.NoError(t, err)
+	require.Equal(t, "192.168.1.1:5000", conn.RemoteAddr().String())
+	require.Equal(t, "127.0.0.1", conn.(*Conn).ProxyAddr().(*net.TCPAddr).IP.String())
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(buf))
+
+	_, conn, err = dialAndAccept(t, l, []byte("PROXY TCP6 2001:db8::1 2001:db8::2 5000 4000\r\n"))
+	require.NoError(t, err)
+	require.Equal(t, "[2001:db8::1]:5000", conn.RemoteAddr().String())
+
+	client, conn, err := dialAndAccept(t, l, []byte("PROXY UNKNOWN\r\n"))
+	require.NoError(t, err)
+	require.Equal(t, client.LocalAddr().String(), conn.RemoteAddr().String())
+
+	_, _, err = dialAndAccept(t, l, []byte("PROXY TCP4 2001:db8::1 10.0.0.1 5000 4000\r\n"))
+	require.Equal(t, ErrProxyProtocolV1HeaderInvalid, err)
+}
+
+func TestV2Header(t *testing.T) {
+	l := newTestListener(t, "127.0.0.0/8", time.Second)
+
+	addrs := make([]byte, v2AddrLenInet)
+	copy(addrs[0:4], net.ParseIP("192.168.1.1").To4())
+	copy(addrs[4:8], net.ParseIP("10.0.0.1").To4())
+	binary.BigEndian.PutUint16(addrs[8:10], 5000)
+	binary.BigEndian.PutUint16(addrs[10:12], 4000)
+	tlvs := []TLV{
+		{Type: TLVTypeAuthority, Value: []byte("tidb.example.com")},
+		{Type: TLVTypeNoop, Value: make([]byte, 300)},
+		{Type: TLVTypeUniqueID, Value: []byte("conn-1")},
+	}
+	// The header with TLVs can be longer than the v1 header.
+	header := buildV2Header(v2CmdProxy, 0x11, addrs, tlvs)
+	require.Greater(t, len(header), v1MaxHeaderLen)
+	_, conn, err := dialAndAccept(t, l, append(header, "hello"...))
+	require.NoError(t, err)
+	require.Equal(t, "192.168.1.1:5000", conn.RemoteAddr().String())
+	ppConn := conn.(*Conn)
+	require.Len(t, ppConn.TLVs(), 2)
+	authority, ok := ppConn.TLV(TLVTypeAuthority)
+	require.True(t, ok)
+	require.Equal(t, "tidb.example.com", string(authority))
+	_, ok = ppConn.TLV(TLVTypeSSL)
+	require.False(t, ok)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(buf))
+
+	addrs = make([]byte, v2AddrLenInet6)
+	copy(addrs[0:16], net.ParseIP("2001:db8::1"))
+	copy(addrs[16:32], net.ParseIP("2001:db8::2"))
+	binary.BigEndian.PutUint16(addrs[32:34], 5000)
+	binary.BigEndian.PutUint16(addrs[34:36], 4000)
+	_, conn, err = dialAndAccept(t, l, buildV2Header(v2CmdProxy, 0x21, addrs, nil))
+	require.NoError(t, err)
+	require.Equal(t, "[2001:db8::1]:5000", conn.RemoteAddr().String())
+
+	// The LOCAL command keeps the address of the connection.
+	client, conn, err := dialAndAccept(t, l, buildV2Header(v2CmdLocal, 0x00, nil, nil))
+	require.NoError(t, err)
+	require.Equal(t, client.LocalAddr().String(), conn.RemoteAddr().String())
+
+	// The TLV exceeds the header.
+	header = buildV2Header(v2CmdProxy, 0x11, make([]byte, v2AddrLenInet), []TLV{{Type: TLVTypeAuthority, Value: []byte("a")}})
+	binary.BigEndian.PutUint16(header[v2HeaderLen+v2AddrLenInet+1:], 10)
+	_, _, err = dialAndAccept(t, l, header)
+	require.Equal(t, ErrProxyProtocolV2HeaderInvalid, err)
+}
+
+func TestInvalidHeader(t *testing.T) {
+	l := newTestListener(t, "*", 100*time.Millisecond)
+	_, _, err := dialAndAccept(t, l, []byte("GET / HTTP/1.1\r\n"))
+	require.True(t, IsProxyProtocolError(err))
+
+	_, _, err = dialAndAccept(t, l, nil)
+	require.Equal(t, ErrHeaderReadTimeout, err)
+
+	// The listener keeps accepting connections after the errors.
+	_, conn, err := dialAndAccept(t, l, []byte("PROXY TCP4 192.168.1.1 10.0.0.1 5000 4000\r\n"))
+	require.NoError(t, err)
+	require.Equal(t, "192.168.1.1:5000", conn.RemoteAddr().String())
+}
+
+func TestSetNetworks(t *testing.T) {
+	l := newTestListener(t, "10.0.0.0/8", time.Second)
+	// The connections out of the allowed networks are accepted without reading the header.
+	client, conn, err := dialAndAccept(t, l, nil)
+	require.NoError(t, err)
+	require.Equal(t, client.LocalAddr().String(), conn.RemoteAddr().String())
+	_, ok := conn.(*Conn)
+	require.False(t, ok)
+
+	require.Error(t, l.SetNetworks("10.0.0.0/8,abc"))
+	require.Equal(t, "10.0.0.0/8", l.Networks().String())
+	require.NoError(t, l.SetNetworks("10.0.0.0/8,127.0.0.1"))
+	require.Equal(t, "10.0.0.0/8,127.0.0.1", l.Networks().String())
+	_, conn, err = dialAndAccept(t, l, []byte("PROXY TCP4 192.168.1.1 10.0.0.1 5000 4000\r\n"))
+	require.NoError(t, err)
+	require.Equal(t, "192.168.1.1:5000", conn.RemoteAddr().String())
+}
@@ -0,0 +1,267 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegroup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// RequestBaseRU is the request units consumed by every coprocessor request.
+	RequestBaseRU = 1
+	// ResponseBytesPerRU is the size of the response data which consumes one request unit.
+	ResponseBytesPerRU = 64 * 1024
+	// runawayRecordChanSize is the max count of the runaway records waiting to be persisted, the following records
+	// are dropped when it's exceeded.
+	runawayRecordChanSize = 1024
+)
+
+// TokenBucket limits the request units consumed per second. The bucket holds at most one second of tokens, and it's
+// allowed to go into debt, in which case the following requests wait until the debt is paid off.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a full TokenBucket which is refilled with rate tokens per second.
+func NewTokenBucket(rate uint64) *TokenBucket {
+	return &TokenBucket{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   time.Now(),
+	}
+}
+
+func (b *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.rate {
+			b.tokens = b.rate
+		}
+		b.last = now
+	}
+}
+
+// reserve consumes n tokens and returns how long the caller should wait before the tokens are available.
+func (b *TokenBucket) reserve(n float64, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Wait consumes n tokens and blocks until they are available or the context is done.
+func (b *TokenBucket) Wait(ctx context.Context, n float64) error {
+	d := b.reserve(n, time.Now())
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Consume consumes n tokens without waiting, the debt is paid by the following requests.
+func (b *TokenBucket) Consume(n float64) {
+	b.reserve(n, time.Now())
+}
+
+func (b *TokenBucket) setRate(rate uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	b.rate = float64(rate)
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+}
+
+type group struct {
+	settings model.ResourceGroupSettings
+	bucket   *TokenBucket
+	// egress limits the size of the results sent to the clients, it's nil if the group has no EGRESS_BYTES_PER_SEC.
+	egress *TokenBucket
+}
+
+// Controller throttles the requests of the resource groups according to their RU_PER_SEC quotas.
+// The requests of the default group, the unknown groups, the BURSTABLE groups and the groups without quotas are
+// never throttled.
+type Controller struct {
+	mu     sync.RWMutex
+	groups map[string]*group
+
+	runawayRecordCh chan *RunawayRecord
+}
+
+// NewController creates a Controller.
+func NewController() *Controller {
+	return &Controller{
+		groups:          make(map[string]*group),
+		runawayRecordCh: make(chan *RunawayRecord, runawayRecordChanSize),
+	}
+}
+
+// GlobalController is the Controller of the TiDB instance, it's kept up to date with the info schema by the domain.
+var GlobalController = NewController()
+
+// Update replaces the resource groups of the Controller. The token buckets of the existing groups are kept so
+// altering or reloading the groups doesn't reset their debts.
+func (c *Controller) Update(infos []*model.ResourceGroupInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	groups := make(map[string]*group, len(infos))
+	for _, info := range infos {
+		g, ok := c.groups[info.Name.L]
+		if !ok {
+			g = &group{bucket: NewTokenBucket(info.RURate)}
+		} else if g.settings.RURate != info.RURate {
+			g.bucket.setRate(info.RURate)
+		}
+		switch {
+		case info.EgressBytesPerSec == 0:
+			g.egress = nil
+		case g.egress == nil:
+			g.egress = NewTokenBucket(info.EgressBytesPerSec)
+		case g.settings.EgressBytesPerSec != info.EgressBytesPerSec:
+			g.egress.setRate(info.EgressBytesPerSec)
+		}
+		g.settings = *info.ResourceGroupSettings
+		groups[info.Name.L] = g
+	}
+	c.groups = groups
+}
+
+func (c *Controller) throttledBucket(name string) *TokenBucket {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	g, ok := c.groups[name]
+	if !ok || g.settings.Burstable || g.settings.RURate == 0 {
+		return nil
+	}
+	return g.bucket
+}
+
+// Priority returns the priority of the resource group, it returns ResourceGroupPriorityMedium for the default
+// and the unknown groups.
+func (c *Controller) Priority(name string) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if g, ok := c.groups[name]; ok {
+		return g.settings.Priority
+	}
+	return model.ResourceGroupPriorityMedium
+}
+
+// OnRequest is called before sending a request of the resource group, it blocks until the group has enough quota.
+func (c *Controller) OnRequest(ctx context.Context, name string) error {
+	if name == "" {
+		return nil
+	}
+	if bucket := c.throttledBucket(name); bucket != nil {
+		return bucket.Wait(ctx, RequestBaseRU)
+	}
+	return nil
+}
+
+// RequestRU returns the request units consumed by a coprocessor request whose response has `respBytes` bytes,
+// which are charged by OnRequest and OnResponse.
+func RequestRU(respBytes int) float64 {
+	if respBytes < ResponseBytesPerRU {
+		return RequestBaseRU
+	}
+	return RequestBaseRU + float64(respBytes)/ResponseBytesPerRU
+}
+
+// OnResponse is called after receiving the response of a request of the resource group, it charges the group for
+// the response size.
+func (c *Controller) OnResponse(name string, respBytes int) {
+	if name == "" || respBytes < ResponseBytesPerRU {
+		return
+	}
+	if bucket := c.throttledBucket(name); bucket != nil {
+		bucket.Consume(float64(respBytes) / ResponseBytesPerRU)
+	}
+}
+
+// EgressBucket returns the token bucket which limits the bytes sent to the clients by the connections of the
+// resource group per second, it returns nil if the group isn't limited. The bucket is shared by all the connections
+// of the group on this TiDB instance.
+func (c *Controller) EgressBucket(name string) *TokenBucket {
+	if name == "" {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if g, ok := c.groups[name]; ok {
+		return g.egress
+	}
+	return nil
+}
+
+// QueryLimit returns the QUERY_LIMIT of the resource group, the execution time limit is 0 if the group has no
+// QUERY_LIMIT.
+func (c *Controller) QueryLimit(name string) (time.Duration, model.RunawayActionType) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if g, ok := c.groups[name]; ok && g.settings.ExecElapsedTimeMs > 0 {
+		return time.Duration(g.settings.ExecElapsedTimeMs) * time.Millisecond, g.settings.RunawayAction
+	}
+	return 0, model.RunawayActionNone
+}
+
+// RunawayRecord is a runaway query, which exceeds the QUERY_LIMIT of its resource group.
+type RunawayRecord struct {
+	ResourceGroupName string
+	User              string
+	Host              string
+	SQLDigest         string
+	SQLText           string
+	ExecElapsed       time.Duration
+	Action            model.RunawayActionType
+	StartTime         time.Time
+	WatchTime         time.Time
+}
+
+// RecordRunaway records the runaway query without blocking, it's persisted by the consumer of RunawayRecords.
+func (c *Controller) RecordRunaway(record *RunawayRecord) {
+	select {
+	case c.runawayRecordCh <- record:
+	default:
+		logutil.BgLogger().Warn("too many runaway records, drop the record",
+			zap.String("resource group", record.ResourceGroupName), zap.String("sql digest", record.SQLDigest))
+	}
+}
+
+// RunawayRecords returns the channel of the runaway records to be persisted.
+func (c *Controller) RunawayRecords() <-chan *RunawayRecord {
+	return c.runawayRecordCh
+}
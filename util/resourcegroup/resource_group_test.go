@@ -0,0 +1,117 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegroup
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := NewTokenBucket(10)
+	b.last = now
+	// The bucket is full at first.
+	require.Equal(t, time.Duration(0), b.reserve(10, now))
+	// It goes into debt, the debt is paid off at the rate.
+	require.Equal(t, 500*time.Millisecond, b.reserve(5, now))
+	require.Equal(t, time.Duration(0), b.reserve(5, now.Add(time.Second)))
+	// The bucket holds at most one second of tokens.
+	require.Equal(t, 100*time.Millisecond, b.reserve(11, now.Add(time.Hour)))
+
+	b.setRate(1000)
+	require.Equal(t, 1000.0, b.rate)
+}
+
+func TestController(t *testing.T) {
+	c := NewController()
+	limited := &model.ResourceGroupInfo{Name: model.NewCIStr("limited"), ResourceGroupSettings: &model.ResourceGroupSettings{RURate: 1, Priority: model.ResourceGroupPriorityHigh}}
+	burstable := &model.ResourceGroupInfo{Name: model.NewCIStr("burstable"), ResourceGroupSettings: &model.ResourceGroupSettings{RURate: 1, Burstable: true}}
+	c.Update([]*model.ResourceGroupInfo{limited, burstable})
+	require.Equal(t, model.ResourceGroupPriorityHigh, c.Priority("limited"))
+	require.Equal(t, model.ResourceGroupPriorityMedium, c.Priority("unknown"))
+
+	ctx := context.Background()
+	require.NoError(t, c.OnRequest(ctx, "limited"))
+	// The quota of the group is used up, so the request is throttled until the context is done.
+	ctx2, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, c.OnRequest(ctx2, "limited"), context.DeadlineExceeded)
+
+	// The burstable, unknown and default groups are never throttled.
+	for i := 0; i < 10; i++ {
+		require.NoError(t, c.OnRequest(ctx2, "burstable"))
+		require.NoError(t, c.OnRequest(ctx2, "unknown"))
+		require.NoError(t, c.OnRequest(ctx2, ""))
+	}
+
+	// Updating the groups keeps the debt of the existing groups.
+	bucket := c.throttledBucket("limited")
+	c.Update([]*model.ResourceGroupInfo{limited})
+	require.Same(t, bucket, c.throttledBucket("limited"))
+	require.Nil(t, c.throttledBucket("burstable"))
+}
+
+func TestRunawayRecords(t *testing.T) {
+	c := NewController()
+	limited := &model.ResourceGroupInfo{Name: model.NewCIStr("limited"), ResourceGroupSettings: &model.ResourceGroupSettings{ExecElapsedTimeMs: 1500, RunawayAction: model.RunawayActionCooldown}}
+	unlimited := &model.ResourceGroupInfo{Name: model.NewCIStr("unlimited"), ResourceGroupSettings: &model.ResourceGroupSettings{RunawayAction: model.RunawayActionKill}}
+	c.Update([]*model.ResourceGroupInfo{limited, unlimited})
+
+	execElapsed, action := c.QueryLimit("limited")
+	require.Equal(t, 1500*time.Millisecond, execElapsed)
+	require.Equal(t, model.RunawayActionCooldown, action)
+	for _, name := range []string{"unlimited", "unknown", ""} {
+		execElapsed, action = c.QueryLimit(name)
+		require.Equal(t, time.Duration(0), execElapsed)
+		require.Equal(t, model.RunawayActionNone, action)
+	}
+
+	// The records are dropped rather than blocking the watcher when nobody consumes them.
+	for i := 0; i < runawayRecordChanSize+1; i++ {
+		c.RecordRunaway(&RunawayRecord{ResourceGroupName: "limited", SQLDigest: strconv.Itoa(i)})
+	}
+	require.Len(t, c.RunawayRecords(), runawayRecordChanSize)
+	require.Equal(t, "0", (<-c.RunawayRecords()).SQLDigest)
+}
+
+func TestEgressBucket(t *testing.T) {
+	c := NewController()
+	limited := &model.ResourceGroupInfo{Name: model.NewCIStr("limited"), ResourceGroupSettings: &model.ResourceGroupSettings{EgressBytesPerSec: 1024}}
+	unlimited := &model.ResourceGroupInfo{Name: model.NewCIStr("unlimited"), ResourceGroupSettings: &model.ResourceGroupSettings{RURate: 1}}
+	c.Update([]*model.ResourceGroupInfo{limited, unlimited})
+	bucket := c.EgressBucket("limited")
+	require.NotNil(t, bucket)
+	require.Equal(t, 1024.0, bucket.rate)
+	require.Nil(t, c.EgressBucket("unlimited"))
+	require.Nil(t, c.EgressBucket("unknown"))
+	require.Nil(t, c.EgressBucket(""))
+
+	// Altering the limit keeps the bucket, and removing the limit drops it.
+	altered := limited.Clone()
+	altered.EgressBytesPerSec = 2048
+	c.Update([]*model.ResourceGroupInfo{altered})
+	require.Same(t, bucket, c.EgressBucket("limited"))
+	require.Equal(t, 2048.0, bucket.rate)
+	altered = altered.Clone()
+	altered.EgressBytesPerSec = 0
+	c.Update([]*model.ResourceGroupInfo{altered})
+	require.Nil(t, c.EgressBucket("limited"))
+}
@@ -39,6 +39,7 @@ const (
 	clusterLog            = "cluster_log"
 	clusterSystemInfo     = "cluster_systeminfo"
 	inspectionResult      = "inspection_result"
+	inspectionReport      = "inspection_report"
 	inspectionRules       = "inspection_rules"
 	inspectionSummary     = "inspection_summary"
 	metricsSummary        = "metrics_summary"
@@ -110,7 +111,7 @@ func IsInvisibleTable(dbLowerName, tblLowerName string) bool {
 	case informationSchema:
 		switch tblLowerName {
 		case clusterConfig, clusterHardware, clusterLoad, clusterLog, clusterSystemInfo, inspectionResult,
-			inspectionRules, inspectionSummary, metricsSummary, metricsSummaryByLabel, metricsTables, tidbHotRegions:
+			inspectionReport, inspectionRules, inspectionSummary, metricsSummary, metricsSummaryByLabel, metricsTables, tidbHotRegions:
 			return true
 		}
 	case performanceSchema:
@@ -153,7 +154,9 @@ func IsInvisibleSysVar(varNameInLower string) bool {
 		variable.TiDBSlowLogThreshold,
 		variable.TiDBEnableCollectExecutionInfo,
 		variable.TiDBMemoryUsageAlarmRatio,
+		variable.TiDBServerMemoryLimit,
 		variable.TiDBRedactLog,
+		variable.TiDBLogDesensitization,
 		variable.TiDBRestrictedReadOnly,
 		variable.TiDBSlowLogMasking:
 		return true
@@ -45,7 +45,6 @@ func SetupSignalHandler(shutdownFunc func(bool)) {
 
 	closeSignalChan := make(chan os.Signal, 1)
 	signal.Notify(closeSignalChan,
-		syscall.SIGHUP,
 		syscall.SIGINT,
 		syscall.SIGTERM,
 		syscall.SIGQUIT)
@@ -56,3 +55,15 @@ func SetupSignalHandler(shutdownFunc func(bool)) {
 		shutdownFunc(sig == syscall.SIGQUIT)
 	}()
 }
+
+// SetupReloadSignalHandler setup signal handler to reload the TLS certificates for TiDB Server by SIGHUP
+func SetupReloadSignalHandler(reloadFunc func()) {
+	reloadSignalChan := make(chan os.Signal, 1)
+	signal.Notify(reloadSignalChan, syscall.SIGHUP)
+	go func() {
+		for sig := range reloadSignalChan {
+			logutil.BgLogger().Info("got signal to reload", zap.Stringer("signal", sig))
+			reloadFunc()
+		}
+	}()
+}
@@ -17,3 +17,7 @@ package signal
 // SetupSignalHandler setup signal handler for TiDB Server
 func SetupSignalHandler(shutdownFunc func(bool)) {
 }
+
+// SetupReloadSignalHandler setup signal handler to reload the TLS certificates for TiDB Server by SIGHUP
+func SetupReloadSignalHandler(reloadFunc func()) {
+}
@@ -41,3 +41,7 @@ func SetupSignalHandler(shutdownFunc func(bool)) {
 		shutdownFunc(sig == syscall.SIGQUIT)
 	}()
 }
+
+// SetupReloadSignalHandler setup signal handler to reload the TLS certificates for TiDB Server by SIGHUP
+func SetupReloadSignalHandler(reloadFunc func()) {
+}
@@ -90,6 +90,10 @@ func (ssbde *stmtSummaryByDigestEvicted) AddEvicted(evictedKey *stmtSummaryByDig
 		func() {
 			evictedElement.Lock()
 			defer evictedElement.Unlock()
+			// the persisted element is read from the file, it shouldn't be counted again.
+			if evictedElement.persisted {
+				return
+			}
 			// no record in ssbde.history, direct insert
 			if ssbde.history.Len() == 0 && historySize != 0 {
 
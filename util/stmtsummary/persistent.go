@@ -0,0 +1,343 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// persistInterval is how often the expired summaries are written into the file.
+const persistInterval = time.Minute
+
+// persistedSummary is a summary of one interval written into the file as a JSON line.
+type persistedSummary struct {
+	// Columns are the values of the columns in the string form, the NULL values are omitted.
+	Columns map[string]string `json:"columns"`
+	// AuthUsers are the users who are allowed to read the summary without the PROCESS privilege.
+	AuthUsers []string `json:"auth_users,omitempty"`
+	// Evicted means the summary of the evicted statements, it's not filtered by the digests or the users.
+	Evicted bool `json:"evicted,omitempty"`
+}
+
+// stmtSummaryPersister writes the expired summaries into a file in the background, so that
+// STATEMENTS_SUMMARY_HISTORY survives the restarts. The file is rotated like the log files of TiDB,
+// and its max-days and max-backups are the retention of the persisted summaries.
+type stmtSummaryPersister struct {
+	filename string
+	syncer   zapcore.WriteSyncer
+	// mu is held while writing the summaries and while reading the history, so that a summary is read either
+	// from the file or from the memory.
+	mu     sync.Mutex
+	exitCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// SetupPersistence starts persisting the history summaries into the file.
+func (ssMap *stmtSummaryByDigestMap) SetupPersistence(cfg log.FileLogConfig) error {
+	if cfg.Filename == "" {
+		return errors.New("the statement summary file is not specified")
+	}
+	_, prop, err := log.InitLogger(&log.Config{File: cfg})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	p := &stmtSummaryPersister{
+		filename: cfg.Filename,
+		syncer:   prop.Syncer,
+		exitCh:   make(chan struct{}),
+	}
+	ssMap.Lock()
+	if ssMap.persister != nil {
+		ssMap.Unlock()
+		return errors.New("the statement summary persistence is already set up")
+	}
+	ssMap.persister = p
+	ssMap.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer util.Recover("stmtsummary", "persist", nil, false)
+		ticker := time.NewTicker(persistInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ssMap.persist(p, false)
+			case <-p.exitCh:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// ClosePersistence stops persisting, the summaries of the current interval are also written so that
+// they are not lost after the restart.
+func (ssMap *stmtSummaryByDigestMap) ClosePersistence() {
+	ssMap.Lock()
+	p := ssMap.persister
+	ssMap.persister = nil
+	ssMap.Unlock()
+	if p == nil {
+		return
+	}
+	close(p.exitCh)
+	p.wg.Wait()
+	ssMap.persist(p, true)
+}
+
+func (ssMap *stmtSummaryByDigestMap) getPersister() *stmtSummaryPersister {
+	ssMap.Lock()
+	defer ssMap.Unlock()
+	return ssMap.persister
+}
+
+// persist writes the expired summaries which are not written yet, and all the summaries if `all` is true.
+func (ssMap *stmtSummaryByDigestMap) persist(p *stmtSummaryPersister, all bool) {
+	ssMap.Lock()
+	values := ssMap.summaryMap.Values()
+	other := ssMap.other
+	ssMap.Unlock()
+
+	now := time.Now().Unix()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		ssbd.Lock()
+		ssElements := make([]*stmtSummaryByDigestElement, 0, ssbd.history.Len())
+		if ssbd.initialized {
+			for listElement := ssbd.history.Front(); listElement != nil; listElement = listElement.Next() {
+				ssElements = append(ssElements, listElement.Value.(*stmtSummaryByDigestElement))
+			}
+		}
+		ssbd.Unlock()
+		for _, ssElement := range ssElements {
+			p.persistElement(ssElement, ssbd, false, now, all)
+		}
+	}
+
+	other.Lock()
+	seElements := make([]*stmtSummaryByDigestEvictedElement, 0, other.history.Len())
+	for listElement := other.history.Front(); listElement != nil; listElement = listElement.Next() {
+		seElements = append(seElements, listElement.Value.(*stmtSummaryByDigestEvictedElement))
+	}
+	other.Unlock()
+	ssbd := new(stmtSummaryByDigest)
+	for _, seElement := range seElements {
+		p.persistElement(seElement.otherSummary, ssbd, true, now, all)
+	}
+
+	if err := p.syncer.Sync(); err != nil {
+		logutil.BgLogger().Warn("sync the statement summary file failed", zap.Error(err))
+	}
+}
+
+func (p *stmtSummaryPersister) persistElement(ssElement *stmtSummaryByDigestElement, ssbd *stmtSummaryByDigest, evicted bool, now int64, all bool) {
+	ssElement.Lock()
+	defer ssElement.Unlock()
+	if ssElement.persisted || (!all && ssElement.endTime > now) {
+		return
+	}
+	// The current interval is cut by the shutdown.
+	if ssElement.endTime > now {
+		ssElement.endTime = now
+	}
+	summary := persistedSummary{
+		Columns: make(map[string]string, len(columnValueFactoryMap)),
+		Evicted: evicted,
+	}
+	for name, factory := range columnValueFactoryMap {
+		v := factory(ssElement, ssbd)
+		if v == nil {
+			continue
+		}
+		d := types.NewDatum(v)
+		str, err := d.ToString()
+		if err != nil {
+			logutil.BgLogger().Warn("convert the statement summary to string failed", zap.String("column", name), zap.Error(err))
+			continue
+		}
+		summary.Columns[name] = str
+	}
+	if !evicted {
+		summary.AuthUsers = make([]string, 0, len(ssElement.authUsers))
+		for user := range ssElement.authUsers {
+			summary.AuthUsers = append(summary.AuthUsers, user)
+		}
+		sort.Strings(summary.AuthUsers)
+	}
+	data, err := json.Marshal(&summary)
+	if err == nil {
+		_, err = p.syncer.Write(append(data, '\n'))
+	}
+	if err != nil {
+		logutil.BgLogger().Warn("write the statement summary file failed", zap.Error(err))
+		return
+	}
+	ssElement.persisted = true
+}
+
+// isPersisted reports whether the element is written into the file, then it's read from the file.
+func (ssElement *stmtSummaryByDigestElement) isPersisted() bool {
+	ssElement.Lock()
+	defer ssElement.Unlock()
+	return ssElement.persisted
+}
+
+// getPersistedFiles returns the file and its rotated backups, the older files go first.
+func (p *stmtSummaryPersister) getPersistedFiles() ([]string, error) {
+	dir := filepath.Dir(p.filename)
+	base := filepath.Base(p.filename)
+	ext := filepath.Ext(base)
+	// The backups are named as `<name>-<time>.<ext>` by the rotation, and the time sorts as the string.
+	prefix := strings.TrimSuffix(base, ext) + "-"
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	files := make([]string, 0, len(entries))
+	current := false
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		if name == base {
+			current = true
+		} else if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, ext) {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(files)
+	if current {
+		files = append(files, p.filename)
+	}
+	return files, nil
+}
+
+// getPersistedRows reads the summaries in the files.
+func (ssr *stmtSummaryReader) getPersistedRows(p *stmtSummaryPersister) [][]types.Datum {
+	files, err := p.getPersistedFiles()
+	if err != nil {
+		logutil.BgLogger().Warn("list the statement summary files failed", zap.Error(err))
+		return nil
+	}
+	var rows [][]types.Datum
+	for _, file := range files {
+		fileRows, err := ssr.readPersistedFile(file)
+		if err != nil {
+			// The file may be removed by the rotation.
+			logutil.BgLogger().Warn("read the statement summary file failed", zap.String("file", file), zap.Error(err))
+		}
+		rows = append(rows, fileRows...)
+	}
+	return rows
+}
+
+func (ssr *stmtSummaryReader) readPersistedFile(file string) ([][]types.Datum, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		terror.Log(f.Close())
+	}()
+
+	var rows [][]types.Datum
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			var summary persistedSummary
+			if jsonErr := json.Unmarshal(line, &summary); jsonErr != nil {
+				logutil.BgLogger().Warn("invalid statement summary in the file", zap.String("file", file), zap.Error(jsonErr))
+			} else if row := ssr.getPersistedRow(&summary); row != nil {
+				rows = append(rows, row)
+			}
+		}
+		// The last line without '\n' is incomplete, which is written partially when TiDB exits.
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, errors.Trace(err)
+		}
+	}
+}
+
+func (ssr *stmtSummaryReader) getPersistedRow(summary *persistedSummary) []types.Datum {
+	if summary.Evicted {
+		if ssr.checker != nil {
+			return nil
+		}
+	} else {
+		if ssr.checker != nil && !ssr.checker.isDigestValid(summary.Columns[DigestStr]) {
+			return nil
+		}
+		if ssr.user != nil && !ssr.hasProcessPriv {
+			isAuthed := false
+			for _, user := range summary.AuthUsers {
+				if user == ssr.user.Username {
+					isAuthed = true
+					break
+				}
+			}
+			if !isAuthed {
+				return nil
+			}
+		}
+	}
+
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	datums := make([]types.Datum, len(ssr.columns))
+	for i, col := range ssr.columns {
+		if col.Name.O == util.ClusterTableInstanceColumnName {
+			datums[i] = types.NewDatum(ssr.instanceAddr)
+			continue
+		}
+		str, ok := summary.Columns[col.Name.O]
+		if !ok {
+			continue
+		}
+		d := types.NewStringDatum(str)
+		d, err := d.ConvertTo(sc, &col.FieldType)
+		if err != nil {
+			logutil.BgLogger().Warn("convert the persisted statement summary failed", zap.String("column", col.Name.O), zap.Error(err))
+			continue
+		}
+		datums[i] = d
+	}
+	return datums
+}
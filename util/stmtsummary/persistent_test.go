@@ -0,0 +1,164 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	tidbutil "github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/set"
+	"github.com/stretchr/testify/require"
+)
+
+func newPersistentReaderForTest(ssMap *stmtSummaryByDigestMap) *stmtSummaryReader {
+	columns := []struct {
+		name string
+		tp   byte
+		flag uint
+	}{
+		{SummaryBeginTimeStr, mysql.TypeTimestamp, 0},
+		{DigestStr, mysql.TypeVarchar, 0},
+		{SampleUserStr, mysql.TypeVarchar, 0},
+		{ExecCountStr, mysql.TypeLonglong, mysql.UnsignedFlag},
+		{AvgLatencyStr, mysql.TypeLonglong, mysql.UnsignedFlag},
+		{AvgWaitTimeStr, mysql.TypeLonglong, mysql.UnsignedFlag},
+		{BackoffTypesStr, mysql.TypeVarchar, 0},
+		{FirstSeenStr, mysql.TypeTimestamp, 0},
+		{PlanInCacheStr, mysql.TypeTiny, 0},
+		{QuerySampleTextStr, mysql.TypeBlob, 0},
+		{PrevSampleTextStr, mysql.TypeBlob, 0},
+		{tidbutil.ClusterTableInstanceColumnName, mysql.TypeVarchar, 0},
+	}
+	cols := make([]*model.ColumnInfo, len(columns))
+	for i, col := range columns {
+		ft := types.NewFieldType(col.tp)
+		ft.Flag = col.flag
+		ft.Flen = types.UnspecifiedLength
+		cols[i] = &model.ColumnInfo{
+			ID:        int64(i),
+			Name:      model.NewCIStr(col.name),
+			Offset:    i,
+			FieldType: *ft,
+		}
+	}
+	reader := NewStmtSummaryReader(nil, true, cols, "127.0.0.1:10080")
+	reader.ssMap = ssMap
+	return reader
+}
+
+func historyRowsForTest(t *testing.T, reader *stmtSummaryReader) []string {
+	datums := reader.GetStmtSummaryHistoryRows()
+	rows := make([]string, 0, len(datums))
+	for _, row := range datums {
+		strs := make([]string, 0, len(row))
+		for _, d := range row {
+			if d.IsNull() {
+				strs = append(strs, "<nil>")
+				continue
+			}
+			str, err := d.ToString()
+			require.NoError(t, err)
+			strs = append(strs, str)
+		}
+		rows = append(rows, strings.Join(strs, "|"))
+	}
+	sort.Strings(rows)
+	return rows
+}
+
+func TestPersistence(t *testing.T) {
+	ssMap := newStmtSummaryByDigestMap()
+	filename := filepath.Join(t.TempDir(), "tidb-statements.log")
+	require.Error(t, ssMap.SetupPersistence(log.FileLogConfig{}))
+	require.NoError(t, ssMap.SetupPersistence(log.FileLogConfig{Filename: filename}))
+	require.Error(t, ssMap.SetupPersistence(log.FileLogConfig{Filename: filename}))
+	defer ssMap.ClosePersistence()
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	ssMap.AddStatement(stmtExecInfo1)
+	ssMap.AddStatement(stmtExecInfo1)
+	stmtExecInfo2 := generateAnyExecInfo()
+	stmtExecInfo2.Digest = "digest2"
+	stmtExecInfo2.User = "user2"
+	ssMap.AddStatement(stmtExecInfo2)
+
+	// The summary of digest1 expires.
+	value, ok := ssMap.summaryMap.Get(&stmtSummaryByDigestKey{
+		schemaName: stmtExecInfo1.SchemaName,
+		digest:     stmtExecInfo1.Digest,
+		planDigest: stmtExecInfo1.PlanDigest,
+	})
+	require.True(t, ok)
+	ssElement1 := value.(*stmtSummaryByDigest).history.Back().Value.(*stmtSummaryByDigestElement)
+	ssElement1.endTime = time.Now().Unix() - 1
+
+	reader := newPersistentReaderForTest(ssMap)
+	expected := historyRowsForTest(t, reader)
+	require.Len(t, expected, 2)
+
+	ssMap.persist(ssMap.getPersister(), false)
+	require.True(t, ssElement1.isPersisted())
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(content), "\n"))
+	// The persisted summary is read from the file.
+	require.Equal(t, expected, historyRowsForTest(t, reader))
+	// It's not persisted twice.
+	ssMap.persist(ssMap.getPersister(), false)
+	content, err = os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(content), "\n"))
+
+	// The persisted summaries are filtered by the users and the digests.
+	reader.hasProcessPriv = false
+	reader.user = &auth.UserIdentity{Username: "user"}
+	require.Len(t, historyRowsForTest(t, reader), 1)
+	reader.user = &auth.UserIdentity{Username: "bad_user"}
+	require.Len(t, historyRowsForTest(t, reader), 0)
+	reader.hasProcessPriv = true
+	reader.SetChecker(NewStmtSummaryChecker(set.NewStringSet("digest")))
+	require.Len(t, historyRowsForTest(t, reader), 1)
+	reader.SetChecker(nil)
+
+	// The current summaries are persisted when it's closed, and they are read after the restart.
+	ssMap.ClosePersistence()
+	content, err = os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(string(content), "\n"))
+	ssMap.Clear()
+	require.Len(t, historyRowsForTest(t, reader), 0)
+	require.NoError(t, ssMap.SetupPersistence(log.FileLogConfig{Filename: filename}))
+	require.Equal(t, expected, historyRowsForTest(t, reader))
+
+	// The rotated files are read, and the incomplete line is skipped.
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(filename), "tidb-statements-2022-01-01T00-00-00.000.log"), content, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(filename), "tidb-slow.log"), content, 0600))
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"columns":{"DIGEST":"dig`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.Len(t, historyRowsForTest(t, reader), 4)
+}
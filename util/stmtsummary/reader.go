@@ -107,6 +107,12 @@ func (ssr *stmtSummaryReader) GetStmtSummaryHistoryRows() [][]types.Datum {
 
 	historySize := ssMap.historySize()
 	rows := make([][]types.Datum, 0, len(values)*historySize)
+	// The persisted summaries are read from the files, and they are skipped in the memory.
+	if persister := ssMap.getPersister(); persister != nil {
+		persister.mu.Lock()
+		defer persister.mu.Unlock()
+		rows = append(rows, ssr.getPersistedRows(persister)...)
+	}
 	for _, value := range values {
 		ssbd := value.(*stmtSummaryByDigest)
 		if ssr.checker != nil && !ssr.checker.isDigestValid(ssbd.digest) {
@@ -164,6 +170,9 @@ func (ssr *stmtSummaryReader) getStmtByDigestHistoryRow(ssbd *stmtSummaryByDiges
 
 	rows := make([][]types.Datum, 0, len(ssElements))
 	for _, ssElement := range ssElements {
+		if ssElement.isPersisted() {
+			continue
+		}
 		isAuthed := true
 		if ssr.user != nil && !ssr.hasProcessPriv {
 			_, isAuthed = ssElement.authUsers[ssr.user.Username]
@@ -200,6 +209,9 @@ func (ssr *stmtSummaryReader) getStmtEvictedOtherHistoryRow(ssbde *stmtSummaryBy
 
 	ssbd := new(stmtSummaryByDigest)
 	for _, seElement := range seElements {
+		if seElement.otherSummary.isPersisted() {
+			continue
+		}
 		rows = append(rows, ssr.getStmtByDigestElementRow(seElement.otherSummary, ssbd))
 	}
 	return rows
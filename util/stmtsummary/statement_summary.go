@@ -75,6 +75,9 @@ type stmtSummaryByDigestMap struct {
 
 	// other stores summary of evicted data.
 	other *stmtSummaryByDigestEvicted
+
+	// persister writes the history summaries into the file, it's nil if the persistence is disabled.
+	persister *stmtSummaryPersister
 }
 
 // StmtSummaryByDigestMap is a global map containing all statement summaries.
@@ -201,6 +204,8 @@ type stmtSummaryByDigestElement struct {
 	// pessimistic execution retry information.
 	execRetryCount uint
 	execRetryTime  time.Duration
+	// persisted means the summary is written into the file, it's read from the file since then.
+	persisted bool
 }
 
 // StmtExecInfo records execution information of each statement.
@@ -529,7 +534,8 @@ func (ssbd *stmtSummaryByDigest) add(sei *StmtExecInfo, beginTime int64, interva
 		isElementNew := true
 		if ssbd.history.Len() > 0 {
 			lastElement := ssbd.history.Back().Value.(*stmtSummaryByDigestElement)
-			if lastElement.beginTime >= beginTime {
+			// A persisted element isn't changed anymore, which happens when the interval is enlarged.
+			if lastElement.beginTime >= beginTime && !lastElement.isPersisted() {
 				ssElement = lastElement
 				isElementNew = false
 			} else {
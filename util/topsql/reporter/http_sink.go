@@ -0,0 +1,262 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tipb/go-tipb"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// HTTPDataSink posts the data as JSON to the HTTP receiver configured by `[top-sql] http-receiver-url`.
+// Besides the data sent by SingleTargetDataSink, it sends the CPU time of TiKV of each store, which is not
+// supported by the gRPC protocol.
+type HTTPDataSink struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	client     *http.Client
+	sendTaskCh chan sendTask
+
+	registered *atomic.Bool
+	registerer DataSinkRegisterer
+}
+
+// NewHTTPDataSink returns a new HTTPDataSink.
+func NewHTTPDataSink(registerer DataSinkRegisterer) *HTTPDataSink {
+	ctx, cancel := context.WithCancel(context.Background())
+	dataSink := &HTTPDataSink{
+		ctx:    ctx,
+		cancel: cancel,
+
+		client:     &http.Client{},
+		sendTaskCh: make(chan sendTask, 1),
+
+		registered: atomic.NewBool(false),
+		registerer: registerer,
+	}
+
+	if url := config.GetGlobalConfig().TopSQL.HTTPReceiverURL; url != "" {
+		if err := registerer.Register(dataSink); err != nil {
+			logutil.BgLogger().Warn("failed to register http datasink", zap.Error(err))
+			return nil
+		}
+		dataSink.registered.Store(true)
+	}
+
+	go dataSink.run()
+	return dataSink
+}
+
+func (ds *HTTPDataSink) run() {
+	defer func() {
+		if r := recover(); r != nil {
+			logutil.BgLogger().Error("panic in HTTPDataSink", zap.Reflect("r", r), zap.Stack("stack trace"))
+		}
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		var url string
+		select {
+		case <-ds.ctx.Done():
+			return
+		case task := <-ds.sendTaskCh:
+			url = config.GetGlobalConfig().TopSQL.HTTPReceiverURL
+			ds.doSend(url, task)
+		case <-ticker.C:
+			url = config.GetGlobalConfig().TopSQL.HTTPReceiverURL
+		}
+
+		if err := ds.trySwitchRegistration(url); err != nil {
+			return
+		}
+	}
+}
+
+func (ds *HTTPDataSink) trySwitchRegistration(url string) error {
+	// deregister if `url` is empty and registered before
+	if url == "" && ds.registered.Load() {
+		ds.registerer.Deregister(ds)
+		ds.registered.Store(false)
+		return nil
+	}
+
+	// register if `url` is not empty and not registered before
+	if url != "" && !ds.registered.Load() {
+		if err := ds.registerer.Register(ds); err != nil {
+			logutil.BgLogger().Warn("failed to register the http datasink", zap.Error(err))
+			return err
+		}
+		ds.registered.Store(true)
+	}
+	return nil
+}
+
+var _ DataSink = &HTTPDataSink{}
+
+// TrySend implements the DataSink interface.
+func (ds *HTTPDataSink) TrySend(data *ReportData, deadline time.Time) error {
+	select {
+	case ds.sendTaskCh <- sendTask{data: data, deadline: deadline}:
+		return nil
+	case <-ds.ctx.Done():
+		return ds.ctx.Err()
+	default:
+		ignoreReportChannelFullCounter.Inc()
+		return errors.New("the channel of http dataSink is full")
+	}
+}
+
+// OnReporterClosing implements the DataSink interface.
+func (ds *HTTPDataSink) OnReporterClosing() {
+	ds.cancel()
+}
+
+// Close stops the HTTPDataSink.
+func (ds *HTTPDataSink) Close() {
+	ds.cancel()
+
+	if ds.registered.Load() {
+		ds.registerer.Deregister(ds)
+		ds.registered.Store(false)
+	}
+}
+
+func (ds *HTTPDataSink) doSend(url string, task sendTask) {
+	if url == "" {
+		return
+	}
+
+	var err error
+	start := time.Now()
+	defer func() {
+		if err != nil {
+			logutil.BgLogger().Warn("[top-sql] http data sink failed to send data to receiver", zap.String("url", url), zap.Error(err))
+			reportAllDurationFailedHistogram.Observe(time.Since(start).Seconds())
+		} else {
+			reportAllDurationSuccHistogram.Observe(time.Since(start).Seconds())
+		}
+	}()
+
+	body, err := json.Marshal(newHTTPReportData(task.data))
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithDeadline(ds.ctx, task.deadline)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := ds.client.Do(req)
+	if err != nil {
+		return
+	}
+	if err = resp.Body.Close(); err != nil {
+		return
+	}
+	if resp.StatusCode/100 != 2 {
+		err = fmt.Errorf("the receiver responds %s", resp.Status)
+	}
+}
+
+// httpReportData is the JSON form of ReportData, the digests are encoded in hex.
+type httpReportData struct {
+	// Instance is the address of the TiDB.
+	Instance         string              `json:"instance"`
+	CPUTimeRecords   []httpCPUTimeRecord `json:"cpu_time_records"`
+	KvCPUTimeRecords []httpCPUTimeRecord `json:"kv_cpu_time_records"`
+	SQLMetas         []httpSQLMeta       `json:"sql_metas"`
+	PlanMetas        []httpPlanMeta      `json:"plan_metas"`
+}
+
+type httpCPUTimeRecord struct {
+	// Store is the address of the TiKV, it's empty for the CPU time of TiDB.
+	Store      string `json:"store,omitempty"`
+	SQLDigest  string `json:"sql_digest"`
+	PlanDigest string `json:"plan_digest"`
+	// TimestampSec and CPUTimeMs are zipped together, they are the CPU time in each second.
+	TimestampSec []uint64 `json:"timestamp_sec"`
+	CPUTimeMs    []uint32 `json:"cpu_time_ms"`
+}
+
+type httpSQLMeta struct {
+	SQLDigest     string `json:"sql_digest"`
+	NormalizedSQL string `json:"normalized_sql"`
+	IsInternal    bool   `json:"is_internal"`
+}
+
+type httpPlanMeta struct {
+	PlanDigest     string `json:"plan_digest"`
+	NormalizedPlan string `json:"normalized_plan"`
+}
+
+func newHTTPCPUTimeRecord(store string, record *tipb.CPUTimeRecord) httpCPUTimeRecord {
+	return httpCPUTimeRecord{
+		Store:        store,
+		SQLDigest:    hex.EncodeToString(record.SqlDigest),
+		PlanDigest:   hex.EncodeToString(record.PlanDigest),
+		TimestampSec: record.RecordListTimestampSec,
+		CPUTimeMs:    record.RecordListCpuTimeMs,
+	}
+}
+
+func newHTTPReportData(data *ReportData) *httpReportData {
+	cfg := config.GetGlobalConfig()
+	res := &httpReportData{
+		Instance:         net.JoinHostPort(cfg.AdvertiseAddress, strconv.Itoa(int(cfg.Port))),
+		CPUTimeRecords:   make([]httpCPUTimeRecord, 0, len(data.CPUTimeRecords)),
+		KvCPUTimeRecords: make([]httpCPUTimeRecord, 0, len(data.KvCPUTimeRecords)),
+		SQLMetas:         make([]httpSQLMeta, 0, len(data.SQLMetas)),
+		PlanMetas:        make([]httpPlanMeta, 0, len(data.PlanMetas)),
+	}
+	for i := range data.CPUTimeRecords {
+		res.CPUTimeRecords = append(res.CPUTimeRecords, newHTTPCPUTimeRecord("", &data.CPUTimeRecords[i]))
+	}
+	for i := range data.KvCPUTimeRecords {
+		record := &data.KvCPUTimeRecords[i]
+		res.KvCPUTimeRecords = append(res.KvCPUTimeRecords, newHTTPCPUTimeRecord(record.Store, &record.CPUTimeRecord))
+	}
+	for _, meta := range data.SQLMetas {
+		res.SQLMetas = append(res.SQLMetas, httpSQLMeta{
+			SQLDigest:     hex.EncodeToString(meta.SqlDigest),
+			NormalizedSQL: meta.NormalizedSql,
+			IsInternal:    meta.IsInternalSql,
+		})
+	}
+	for _, meta := range data.PlanMetas {
+		res.PlanMetas = append(res.PlanMetas, httpPlanMeta{
+			PlanDigest:     hex.EncodeToString(meta.PlanDigest),
+			NormalizedPlan: meta.NormalizedPlan,
+		})
+	}
+	return res
+}
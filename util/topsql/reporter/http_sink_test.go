@@ -0,0 +1,104 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tipb/go-tipb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPDataSink(t *testing.T) {
+	ch := make(chan *httpReportData, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		data := &httpReportData{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(data))
+		ch <- data
+	}))
+	defer server.Close()
+
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TopSQL.HTTPReceiverURL = ""
+	})
+	tsr := NewRemoteTopSQLReporter(mockPlanBinaryDecoderFunc)
+	defer tsr.Close()
+	ds := NewHTTPDataSink(tsr)
+	defer ds.Close()
+	require.False(t, ds.registered.Load())
+
+	// The data sink is registered after the URL is configured.
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TopSQL.HTTPReceiverURL = server.URL
+	})
+	defer config.UpdateGlobal(func(conf *config.Config) {
+		conf.TopSQL.HTTPReceiverURL = ""
+	})
+	require.Eventually(t, ds.registered.Load, 5*time.Second, 100*time.Millisecond)
+
+	require.NoError(t, ds.TrySend(&ReportData{
+		CPUTimeRecords: []tipb.CPUTimeRecord{{
+			SqlDigest:              []byte{0x01},
+			PlanDigest:             []byte{0x02},
+			RecordListTimestampSec: []uint64{1},
+			RecordListCpuTimeMs:    []uint32{10},
+		}},
+		KvCPUTimeRecords: []KvCPUTimeRecord{{
+			Store: "store1",
+			CPUTimeRecord: tipb.CPUTimeRecord{
+				SqlDigest:              []byte{0x01},
+				PlanDigest:             []byte{0x02},
+				RecordListTimestampSec: []uint64{1},
+				RecordListCpuTimeMs:    []uint32{20},
+			},
+		}},
+		SQLMetas:  []tipb.SQLMeta{{SqlDigest: []byte{0x01}, NormalizedSql: "select ?"}},
+		PlanMetas: []tipb.PlanMeta{{PlanDigest: []byte{0x02}, NormalizedPlan: "TableReader"}},
+	}, time.Now().Add(10*time.Second)))
+
+	var data *httpReportData
+	select {
+	case data = <-ch:
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "the receiver doesn't receive the data")
+	}
+	require.Equal(t, []httpCPUTimeRecord{{
+		SQLDigest:    "01",
+		PlanDigest:   "02",
+		TimestampSec: []uint64{1},
+		CPUTimeMs:    []uint32{10},
+	}}, data.CPUTimeRecords)
+	require.Equal(t, []httpCPUTimeRecord{{
+		Store:        "store1",
+		SQLDigest:    "01",
+		PlanDigest:   "02",
+		TimestampSec: []uint64{1},
+		CPUTimeMs:    []uint32{20},
+	}}, data.KvCPUTimeRecords)
+	require.Equal(t, []httpSQLMeta{{SQLDigest: "01", NormalizedSQL: "select ?"}}, data.SQLMetas)
+	require.Equal(t, []httpPlanMeta{{PlanDigest: "02", NormalizedPlan: "TableReader"}}, data.PlanMetas)
+
+	// The data sink is deregistered after the URL is removed.
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TopSQL.HTTPReceiverURL = ""
+	})
+	require.Eventually(t, func() bool { return !ds.registered.Load() }, 5*time.Second, 100*time.Millisecond)
+}
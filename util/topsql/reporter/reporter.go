@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/topsql/stmtstats"
 	"github.com/pingcap/tidb/util/topsql/tracecpu"
 	"github.com/pingcap/tipb/go-tipb"
 	"github.com/wangjohn/quickselect"
@@ -45,6 +46,7 @@ const (
 )
 
 var _ TopSQLReporter = &RemoteTopSQLReporter{}
+var _ stmtstats.Collector = &RemoteTopSQLReporter{}
 
 // TopSQLReporter collects Top SQL metrics.
 type TopSQLReporter interface {
@@ -141,6 +143,7 @@ type RemoteTopSQLReporter struct {
 	planMapLength     atomic2.Int64
 
 	collectCPUDataChan      chan cpuData
+	collectStmtStatsChan    chan []stmtstats.StatementStatsRecord
 	reportCollectedDataChan chan collectedData
 
 	// calling decodePlan this can take a while, so should not block critical paths
@@ -166,6 +169,7 @@ func NewRemoteTopSQLReporter(decodePlan planBinaryDecodeFunc) *RemoteTopSQLRepor
 		dataSinks: make(map[DataSink]struct{}, 10),
 
 		collectCPUDataChan:      make(chan cpuData, 1),
+		collectStmtStatsChan:    make(chan []stmtstats.StatementStatsRecord, 1),
 		reportCollectedDataChan: make(chan collectedData, 1),
 		decodePlan:              decodePlan,
 	}
@@ -182,6 +186,7 @@ var (
 	ignoreExceedSQLCounter              = metrics.TopSQLIgnoredCounter.WithLabelValues("ignore_exceed_sql")
 	ignoreExceedPlanCounter             = metrics.TopSQLIgnoredCounter.WithLabelValues("ignore_exceed_plan")
 	ignoreCollectChannelFullCounter     = metrics.TopSQLIgnoredCounter.WithLabelValues("ignore_collect_channel_full")
+	ignoreCollectStmtChannelFullCounter = metrics.TopSQLIgnoredCounter.WithLabelValues("ignore_collect_stmt_channel_full")
 	ignoreReportChannelFullCounter      = metrics.TopSQLIgnoredCounter.WithLabelValues("ignore_report_channel_full")
 	reportAllDurationSuccHistogram      = metrics.TopSQLReportDurationHistogram.WithLabelValues("all", metrics.LblOK)
 	reportAllDurationFailedHistogram    = metrics.TopSQLReportDurationHistogram.WithLabelValues("all", metrics.LblError)
@@ -291,6 +296,21 @@ func (tsr *RemoteTopSQLReporter) Collect(timestamp uint64, records []tracecpu.SQ
 	}
 }
 
+// CollectStmtStatsRecords receives the statement stats records, the CPU time of TiKV in them is collected for each
+// store. WARN: It will drop the records if the processing is not in time.
+// This function is thread-safe and efficient.
+func (tsr *RemoteTopSQLReporter) CollectStmtStatsRecords(records []stmtstats.StatementStatsRecord) {
+	if len(records) == 0 {
+		return
+	}
+	select {
+	case tsr.collectStmtStatsChan <- records:
+	default:
+		// ignore if chan blocked
+		ignoreCollectStmtChannelFullCounter.Inc()
+	}
+}
+
 // Close uses to close and release the reporter resource.
 func (tsr *RemoteTopSQLReporter) Close() {
 	tsr.cancel()
@@ -385,6 +405,7 @@ func (tsr *RemoteTopSQLReporter) collectWorker() {
 	defer util.Recover("top-sql", "collectWorker", nil, false)
 
 	collectedData := make(map[string]*dataPoints)
+	collectedKvData := make(map[string]map[string]*dataPoints)
 	currentReportInterval := variable.TopSQLVariable.ReportIntervalSeconds.Load()
 	reportTicker := time.NewTicker(time.Second * time.Duration(currentReportInterval))
 	for {
@@ -392,8 +413,10 @@ func (tsr *RemoteTopSQLReporter) collectWorker() {
 		case data := <-tsr.collectCPUDataChan:
 			// On receiving data to collect: Write to local data array, and retain records with most CPU time.
 			tsr.doCollect(collectedData, data.timestamp, data.records)
+		case records := <-tsr.collectStmtStatsChan:
+			tsr.doCollectKvCPUTime(collectedKvData, records)
 		case <-reportTicker.C:
-			tsr.takeDataAndSendToReportChan(&collectedData)
+			tsr.takeDataAndSendToReportChan(&collectedData, &collectedKvData)
 			// Update `reportTicker` if report interval changed.
 			if newInterval := variable.TopSQLVariable.ReportIntervalSeconds.Load(); newInterval != currentReportInterval {
 				currentReportInterval = newInterval
@@ -484,16 +507,47 @@ func (tsr *RemoteTopSQLReporter) doCollect(
 	addEvictedCPUTime(collectTarget, timestamp, totalEvictedCPUTime)
 }
 
+// doCollectKvCPUTime collects the CPU time of TiKV of each store like doCollect, the top N records of each round
+// are kept for each store.
+func (tsr *RemoteTopSQLReporter) doCollectKvCPUTime(
+	collectTarget map[string]map[string]*dataPoints, records []stmtstats.StatementStatsRecord) {
+	for _, record := range records {
+		storeRecords := make(map[string][]tracecpu.SQLCPUTimeRecord)
+		for digest, item := range record.Data {
+			for store, ms := range item.KvStatsItem.KvCPUTimeMs {
+				if ms == 0 {
+					continue
+				}
+				storeRecords[store] = append(storeRecords[store], tracecpu.SQLCPUTimeRecord{
+					SQLDigest:  []byte(digest.SQLDigest),
+					PlanDigest: []byte(digest.PlanDigest),
+					CPUTimeMs:  uint32(ms),
+				})
+			}
+		}
+		for store, cpuRecords := range storeRecords {
+			target, ok := collectTarget[store]
+			if !ok {
+				target = make(map[string]*dataPoints)
+				collectTarget[store] = target
+			}
+			tsr.doCollect(target, uint64(record.Timestamp), cpuRecords)
+		}
+	}
+}
+
 // takeDataAndSendToReportChan takes collected data and then send to the report channel for reporting.
-func (tsr *RemoteTopSQLReporter) takeDataAndSendToReportChan(collectedDataPtr *map[string]*dataPoints) {
+func (tsr *RemoteTopSQLReporter) takeDataAndSendToReportChan(collectedDataPtr *map[string]*dataPoints, collectedKvDataPtr *map[string]map[string]*dataPoints) {
 	data := collectedData{
 		records:           *collectedDataPtr,
+		kvRecords:         *collectedKvDataPtr,
 		normalizedSQLMap:  tsr.normalizedSQLMap.Load().(*sync.Map),
 		normalizedPlanMap: tsr.normalizedPlanMap.Load().(*sync.Map),
 	}
 
 	// Reset data for next report.
 	*collectedDataPtr = make(map[string]*dataPoints)
+	*collectedKvDataPtr = make(map[string]map[string]*dataPoints)
 	tsr.normalizedSQLMap.Store(&sync.Map{})
 	tsr.normalizedPlanMap.Store(&sync.Map{})
 	tsr.sqlMapLength.Store(0)
@@ -509,7 +563,9 @@ func (tsr *RemoteTopSQLReporter) takeDataAndSendToReportChan(collectedDataPtr *m
 }
 
 type collectedData struct {
-	records           map[string]*dataPoints
+	records map[string]*dataPoints
+	// kvRecords are the records of the CPU time of TiKV, which are grouped by the stores.
+	kvRecords         map[string]map[string]*dataPoints
 	normalizedSQLMap  *sync.Map
 	normalizedPlanMap *sync.Map
 }
@@ -518,12 +574,21 @@ type collectedData struct {
 type ReportData struct {
 	// CPUTimeRecords contains the topN collected records and the `others` record which aggregation all records that is out of Top N.
 	CPUTimeRecords []tipb.CPUTimeRecord
-	SQLMetas       []tipb.SQLMeta
-	PlanMetas      []tipb.PlanMeta
+	// KvCPUTimeRecords contains the topN records and the `others` record of the CPU time of TiKV for each store.
+	KvCPUTimeRecords []KvCPUTimeRecord
+	SQLMetas         []tipb.SQLMeta
+	PlanMetas        []tipb.PlanMeta
+}
+
+// KvCPUTimeRecord is the CPU time of a SQL plan on a TiKV store, which is the process time of the requests
+// reported by TiKV.
+type KvCPUTimeRecord struct {
+	Store string
+	tipb.CPUTimeRecord
 }
 
 func (d *ReportData) hasData() bool {
-	return len(d.CPUTimeRecords) != 0 || len(d.SQLMetas) != 0 || len(d.PlanMetas) != 0
+	return len(d.CPUTimeRecords) != 0 || len(d.KvCPUTimeRecords) != 0 || len(d.SQLMetas) != 0 || len(d.PlanMetas) != 0
 }
 
 // reportWorker sends data to the gRPC endpoint from the `reportCollectedDataChan` one by one.
@@ -548,17 +613,37 @@ func (tsr *RemoteTopSQLReporter) reportWorker() {
 // getReportData gets ReportData from the collectedData.
 // This function will calculate the topN collected records and the `others` record which aggregation all records that is out of Top N.
 func (tsr *RemoteTopSQLReporter) getReportData(collected collectedData) *ReportData {
-	records := getTopNFromCollected(collected)
-	return tsr.buildReportData(records, collected.normalizedSQLMap, collected.normalizedPlanMap)
+	records := getTopNFromCollected(collected.records)
+	data := tsr.buildReportData(records, collected.normalizedSQLMap, collected.normalizedPlanMap)
+
+	stores := make([]string, 0, len(collected.kvRecords))
+	for store := range collected.kvRecords {
+		stores = append(stores, store)
+	}
+	sort.Strings(stores)
+	for _, store := range stores {
+		for _, record := range getTopNFromCollected(collected.kvRecords[store]) {
+			data.KvCPUTimeRecords = append(data.KvCPUTimeRecords, KvCPUTimeRecord{
+				Store: store,
+				CPUTimeRecord: tipb.CPUTimeRecord{
+					RecordListTimestampSec: record.TimestampList,
+					RecordListCpuTimeMs:    record.CPUTimeMsList,
+					SqlDigest:              record.SQLDigest,
+					PlanDigest:             record.PlanDigest,
+				},
+			})
+		}
+	}
+	return data
 }
 
-func getTopNFromCollected(collected collectedData) (records []*dataPoints) {
+func getTopNFromCollected(collected map[string]*dataPoints) (records []*dataPoints) {
 	// Fetch TopN dataPoints.
-	others := collected.records[keyOthers]
-	delete(collected.records, keyOthers)
+	others := collected[keyOthers]
+	delete(collected, keyOthers)
 
-	records = make([]*dataPoints, 0, len(collected.records))
-	for _, v := range collected.records {
+	records = make([]*dataPoints, 0, len(collected))
+	for _, v := range collected {
 		records = append(records, v)
 	}
 
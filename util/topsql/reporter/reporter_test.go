@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/topsql/reporter/mock"
+	"github.com/pingcap/tidb/util/topsql/stmtstats"
 	"github.com/pingcap/tidb/util/topsql/tracecpu"
 	"github.com/pingcap/tipb/go-tipb"
 	"github.com/stretchr/testify/require"
@@ -546,6 +547,52 @@ func TestMultipleDataSinks(t *testing.T) {
 	}
 }
 
+func TestCollectKvCPUTime(t *testing.T) {
+	variable.TopSQLVariable.MaxStatementCount.Store(1)
+	variable.TopSQLVariable.ReportIntervalSeconds.Store(1)
+
+	tsr := NewRemoteTopSQLReporter(mockPlanBinaryDecoderFunc)
+	defer tsr.Close()
+	ch := make(chan *ReportData, 1)
+	require.NoError(t, tsr.Register(newMockDataSink(ch)))
+
+	newItem := func(cpuTimeMs map[string]uint64) *stmtstats.StatementStatsItem {
+		item := stmtstats.NewStatementStatsItem()
+		item.KvStatsItem.KvCPUTimeMs = cpuTimeMs
+		return item
+	}
+	tsr.CollectStmtStatsRecords([]stmtstats.StatementStatsRecord{{
+		Timestamp: 1,
+		Data: stmtstats.StatementStatsMap{
+			{SQLDigest: "sqlDigest1", PlanDigest: "planDigest1"}: newItem(map[string]uint64{"store1": 10, "store2": 5}),
+			{SQLDigest: "sqlDigest2", PlanDigest: "planDigest2"}: newItem(map[string]uint64{"store1": 20}),
+		},
+	}})
+
+	// Only the top 1 records of each store are kept, the others are aggregated.
+	d := <-ch
+	require.NotNil(t, d)
+	require.Equal(t, []KvCPUTimeRecord{
+		{Store: "store1", CPUTimeRecord: tipb.CPUTimeRecord{
+			SqlDigest:              []byte("sqlDigest2"),
+			PlanDigest:             []byte("planDigest2"),
+			RecordListTimestampSec: []uint64{1},
+			RecordListCpuTimeMs:    []uint32{20},
+		}},
+		{Store: "store1", CPUTimeRecord: tipb.CPUTimeRecord{
+			RecordListTimestampSec: []uint64{1},
+			RecordListCpuTimeMs:    []uint32{10},
+		}},
+		{Store: "store2", CPUTimeRecord: tipb.CPUTimeRecord{
+			SqlDigest:              []byte("sqlDigest1"),
+			PlanDigest:             []byte("planDigest1"),
+			RecordListTimestampSec: []uint64{1},
+			RecordListCpuTimeMs:    []uint32{5},
+		}},
+	}, d.KvCPUTimeRecords)
+	require.Empty(t, d.CPUTimeRecords)
+}
+
 func BenchmarkTopSQL_CollectAndIncrementFrequency(b *testing.B) {
 	tsr, _ := initializeCache(maxSQLNum, 120, ":23333")
 	for i := 0; i < b.N; i++ {
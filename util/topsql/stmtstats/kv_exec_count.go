@@ -17,6 +17,7 @@ package stmtstats
 import (
 	"sync"
 
+	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/tikv/client-go/v2/tikvrpc"
 	"github.com/tikv/client-go/v2/tikvrpc/interceptor"
 )
@@ -51,7 +52,11 @@ func (c *KvExecCounter) RPCInterceptor() interceptor.RPCInterceptor {
 	return func(next interceptor.RPCInterceptorFunc) interceptor.RPCInterceptorFunc {
 		return func(target string, req *tikvrpc.Request) (*tikvrpc.Response, error) {
 			c.mark(target)
-			return next(target, req)
+			resp, err := next(target, req)
+			if ms := kvProcessTimeMs(resp); ms > 0 {
+				c.stats.addKvCPUTime([]byte(c.digest.SQLDigest), []byte(c.digest.PlanDigest), target, ms)
+			}
+			return resp, err
 		}
 	}
 }
@@ -71,3 +76,21 @@ func (c *KvExecCounter) mark(target string) {
 		c.stats.addKvExecCount([]byte(c.digest.SQLDigest), []byte(c.digest.PlanDigest), target, 1)
 	}
 }
+
+// kvProcessTimeMs returns the process time reported by TiKV in the response, which is the time TiKV spends on
+// the CPU to handle the request. It returns 0 if the response doesn't contain the execution details.
+func kvProcessTimeMs(resp *tikvrpc.Response) uint64 {
+	if resp == nil {
+		return 0
+	}
+	r, ok := resp.Resp.(interface {
+		GetExecDetailsV2() *kvrpcpb.ExecDetailsV2
+	})
+	if !ok {
+		return 0
+	}
+	if ms := r.GetExecDetailsV2().GetTimeDetail().GetProcessWallTimeMs(); ms > 0 {
+		return uint64(ms)
+	}
+	return 0
+}
@@ -17,6 +17,8 @@ package stmtstats
 import (
 	"testing"
 
+	"github.com/pingcap/kvproto/pkg/coprocessor"
+	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/stretchr/testify/assert"
 	"github.com/tikv/client-go/v2/tikvrpc"
 )
@@ -41,3 +43,35 @@ func TestKvExecCounter(t *testing.T) {
 	assert.NotNil(t, stats.data[SQLPlanDigest{SQLDigest: "SQL-1"}])
 	assert.Equal(t, uint64(1), stats.data[SQLPlanDigest{SQLDigest: "SQL-1"}].KvStatsItem.KvExecCount["TIKV-1"])
 }
+
+func TestKvCPUTime(t *testing.T) {
+	stats := CreateStatementStats()
+	counter := stats.CreateKvExecCounter([]byte("SQL-1"), []byte("PLAN-1"))
+	interceptor := counter.RPCInterceptor()
+	execDetails := &kvrpcpb.ExecDetailsV2{TimeDetail: &kvrpcpb.TimeDetail{ProcessWallTimeMs: 10}}
+	responses := []interface{}{
+		&coprocessor.Response{ExecDetailsV2: execDetails},
+		&kvrpcpb.GetResponse{ExecDetailsV2: execDetails},
+		&kvrpcpb.BatchGetResponse{ExecDetailsV2: execDetails},
+		// The responses without the execution details are ignored.
+		&kvrpcpb.GetResponse{},
+		&kvrpcpb.PrewriteResponse{},
+	}
+	for _, resp := range responses {
+		_, _ = interceptor(func(target string, req *tikvrpc.Request) (*tikvrpc.Response, error) {
+			return &tikvrpc.Response{Resp: resp}, nil
+		})("TIKV-1", nil)
+	}
+	_, _ = interceptor(func(target string, req *tikvrpc.Request) (*tikvrpc.Response, error) {
+		return &tikvrpc.Response{Resp: &coprocessor.Response{ExecDetailsV2: execDetails}}, nil
+	})("TIKV-2", nil)
+	_, _ = interceptor(func(target string, req *tikvrpc.Request) (*tikvrpc.Response, error) {
+		return nil, nil
+	})("TIKV-2", nil)
+
+	item := stats.data[SQLPlanDigest{SQLDigest: "SQL-1", PlanDigest: "PLAN-1"}]
+	assert.NotNil(t, item)
+	assert.Equal(t, uint64(30), item.KvStatsItem.KvCPUTimeMs["TIKV-1"])
+	assert.Equal(t, uint64(10), item.KvStatsItem.KvCPUTimeMs["TIKV-2"])
+	assert.Equal(t, uint64(1), item.KvStatsItem.KvExecCount["TIKV-2"])
+}
@@ -93,6 +93,15 @@ func (s *StatementStats) addKvExecCount(sqlDigest, planDigest []byte, target str
 	item.KvStatsItem.KvExecCount[target] += n
 }
 
+// addKvCPUTime is used to add the CPU time of TiKV of a certain SQLPlanDigest for a certain target.
+// addKvCPUTime is thread-safe.
+func (s *StatementStats) addKvCPUTime(sqlDigest, planDigest []byte, target string, ms uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.GetOrCreateStatementStatsItem(sqlDigest, planDigest)
+	item.KvStatsItem.KvCPUTimeMs[target] += ms
+}
+
 // Take takes out all existing StatementStatsMap data from StatementStats.
 // Take is thread-safe.
 func (s *StatementStats) Take() StatementStatsMap {
@@ -192,12 +201,17 @@ func (i *StatementStatsItem) Merge(other *StatementStatsItem) {
 type KvStatementStatsItem struct {
 	// KvExecCount represents the number of SQL executions of TiKV.
 	KvExecCount map[string]uint64
+
+	// KvCPUTimeMs represents the CPU time of TiKV in milliseconds, which is the
+	// process time of the requests reported by each TiKV.
+	KvCPUTimeMs map[string]uint64
 }
 
 // NewKvStatementStatsItem creates an empty KvStatementStatsItem.
 func NewKvStatementStatsItem() KvStatementStatsItem {
 	return KvStatementStatsItem{
 		KvExecCount: map[string]uint64{},
+		KvCPUTimeMs: map[string]uint64{},
 	}
 }
 
@@ -216,4 +230,11 @@ func (i *KvStatementStatsItem) Merge(other KvStatementStatsItem) {
 			i.KvExecCount[target] += count
 		}
 	}
+	if i.KvCPUTimeMs == nil {
+		i.KvCPUTimeMs = other.KvCPUTimeMs
+	} else {
+		for target, ms := range other.KvCPUTimeMs {
+			i.KvCPUTimeMs[target] += ms
+		}
+	}
 }
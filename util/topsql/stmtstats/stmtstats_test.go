@@ -65,12 +65,19 @@ func TestKvStatementStatsItem_Merge(t *testing.T) {
 			"127.0.0.1:10001": 1,
 			"127.0.0.1:10002": 2,
 		},
+		KvCPUTimeMs: map[string]uint64{
+			"127.0.0.1:10001": 10,
+		},
 	}
 	item2 := KvStatementStatsItem{
 		KvExecCount: map[string]uint64{
 			"127.0.0.1:10002": 2,
 			"127.0.0.1:10003": 3,
 		},
+		KvCPUTimeMs: map[string]uint64{
+			"127.0.0.1:10001": 20,
+			"127.0.0.1:10003": 30,
+		},
 	}
 	assert.Len(t, item1.KvExecCount, 2)
 	assert.Len(t, item2.KvExecCount, 2)
@@ -80,6 +87,9 @@ func TestKvStatementStatsItem_Merge(t *testing.T) {
 	assert.Equal(t, uint64(1), item1.KvExecCount["127.0.0.1:10001"])
 	assert.Equal(t, uint64(3), item1.KvExecCount["127.0.0.1:10003"])
 	assert.Equal(t, uint64(3), item1.KvExecCount["127.0.0.1:10003"])
+	assert.Len(t, item1.KvCPUTimeMs, 2)
+	assert.Equal(t, uint64(30), item1.KvCPUTimeMs["127.0.0.1:10001"])
+	assert.Equal(t, uint64(30), item1.KvCPUTimeMs["127.0.0.1:10003"])
 }
 
 func TestStatementsStatsItem_Merge(t *testing.T) {
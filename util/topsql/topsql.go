@@ -42,17 +42,20 @@ const (
 var (
 	globalTopSQLReport   *reporter.RemoteTopSQLReporter
 	singleTargetDataSink *reporter.SingleTargetDataSink
+	httpDataSink         *reporter.HTTPDataSink
 )
 
 // SetupTopSQL sets up the top-sql worker.
 func SetupTopSQL() {
 	remoteReporter := reporter.NewRemoteTopSQLReporter(plancodec.DecodeNormalizedPlan)
 	singleTargetDataSink = reporter.NewSingleTargetDataSink(remoteReporter)
+	httpDataSink = reporter.NewHTTPDataSink(remoteReporter)
 
 	globalTopSQLReport = remoteReporter
 
 	tracecpu.GlobalSQLCPUProfiler.SetCollector(remoteReporter)
 	tracecpu.GlobalSQLCPUProfiler.Run()
+	stmtstats.RegisterCollector(remoteReporter)
 	stmtstats.SetupAggregator()
 }
 
@@ -69,7 +72,11 @@ func Close() {
 	if singleTargetDataSink != nil {
 		singleTargetDataSink.Close()
 	}
+	if httpDataSink != nil {
+		httpDataSink.Close()
+	}
 	if globalTopSQLReport != nil {
+		stmtstats.UnregisterCollector(globalTopSQLReport)
 		globalTopSQLReport.Close()
 	}
 	stmtstats.CloseAggregator()
@@ -0,0 +1,304 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+// Resources:
+// - https://opentelemetry.io/docs/reference/specification/protocol/otlp/
+// - https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/trace/v1/trace.proto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/opentracing/basictracer-go"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// The OTLP/JSON form of ExportTraceServiceRequest. The 64 bits integers are encoded as strings,
+// and the IDs are encoded in hex.
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// otlpSpanKindInternal is SPAN_KIND_INTERNAL, all the spans of TiDB are internal operations.
+const otlpSpanKindInternal = 1
+
+func newOTLPKeyValue(key string, value interface{}) otlpKeyValue {
+	kv := otlpKeyValue{Key: key}
+	switch v := value.(type) {
+	case string:
+		kv.Value.StringValue = &v
+	case bool:
+		kv.Value.BoolValue = &v
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		s := fmt.Sprintf("%d", v)
+		kv.Value.IntValue = &s
+	case uint64:
+		// The integers of OTLP are int64.
+		s := strconv.FormatUint(v, 10)
+		if v > math.MaxInt64 {
+			kv.Value.StringValue = &s
+		} else {
+			kv.Value.IntValue = &s
+		}
+	case float32:
+		f := float64(v)
+		kv.Value.DoubleValue = &f
+	case float64:
+		kv.Value.DoubleValue = &v
+	default:
+		s := fmt.Sprint(v)
+		kv.Value.StringValue = &s
+	}
+	return kv
+}
+
+func newOTLPSpan(sp *basictracer.RawSpan) otlpSpan {
+	span := otlpSpan{
+		// The trace ID of OTLP has 16 bytes, the higher 8 bytes are left zero.
+		TraceID:           fmt.Sprintf("%032x", sp.Context.TraceID),
+		SpanID:            fmt.Sprintf("%016x", sp.Context.SpanID),
+		Name:              sp.Operation,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(sp.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(sp.Start.Add(sp.Duration).UnixNano(), 10),
+	}
+	if sp.ParentSpanID != 0 {
+		span.ParentSpanID = fmt.Sprintf("%016x", sp.ParentSpanID)
+	}
+	for k, v := range sp.Tags {
+		span.Attributes = append(span.Attributes, newOTLPKeyValue(k, v))
+	}
+	for _, l := range sp.Logs {
+		event := otlpEvent{
+			TimeUnixNano: strconv.FormatInt(l.Timestamp.UnixNano(), 10),
+			Name:         "log",
+		}
+		for _, f := range l.Fields {
+			// The `event` field is the name of the log, see opentracing.Span.LogEvent.
+			if f.Key() == "event" {
+				event.Name = fmt.Sprint(f.Value())
+				continue
+			}
+			event.Attributes = append(event.Attributes, newOTLPKeyValue(f.Key(), f.Value()))
+		}
+		span.Events = append(span.Events, event)
+	}
+	return span
+}
+
+// EncodeOTLP encodes the spans into the OTLP/JSON form of the ExportTraceServiceRequest, which can be
+// received by the OTLP/HTTP collectors like Jaeger, Tempo and the OpenTelemetry Collector.
+func EncodeOTLP(serviceName string, spans []basictracer.RawSpan) ([]byte, error) {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for i := range spans {
+		otlpSpans = append(otlpSpans, newOTLPSpan(&spans[i]))
+	}
+	traces := otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{newOTLPKeyValue("service.name", serviceName)}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "github.com/pingcap/tidb"},
+			Spans: otlpSpans,
+		}},
+	}}}
+	data, err := json.Marshal(&traces)
+	return data, errors.Trace(err)
+}
+
+// ExportOTLP posts the spans encoded by EncodeOTLP to the OTLP/HTTP endpoint, e.g. `http://127.0.0.1:4318/v1/traces`.
+func ExportOTLP(ctx context.Context, endpoint string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = resp.Body.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("the OTLP endpoint responds %s", resp.Status)
+	}
+	return nil
+}
+
+const (
+	otlpExporterQueueSize    = 4096
+	otlpExporterBatchSize    = 512
+	otlpExporterTimeout      = 10 * time.Second
+	defaultOTLPFlushInterval = time.Second
+)
+
+// OTLPExporter is a basictracer.SpanRecorder which exports the spans to an OTLP/HTTP endpoint in batches.
+// The spans are dropped if the queue is full.
+type OTLPExporter struct {
+	endpoint    string
+	serviceName string
+	interval    time.Duration
+
+	spanCh chan basictracer.RawSpan
+	exitCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewOTLPExporter creates an OTLPExporter, the spans are exported every `interval` or when a batch is full.
+func NewOTLPExporter(endpoint, serviceName string, interval time.Duration) *OTLPExporter {
+	if interval <= 0 {
+		interval = defaultOTLPFlushInterval
+	}
+	e := &OTLPExporter{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+		interval:    interval,
+		spanCh:      make(chan basictracer.RawSpan, otlpExporterQueueSize),
+		exitCh:      make(chan struct{}),
+	}
+	e.wg.Add(1)
+	go e.run()
+	return e
+}
+
+// NewOTLPTracer returns a tracer whose spans are exported by the OTLPExporter, the traces are sampled with
+// the probability `sampleRate`.
+func NewOTLPTracer(e *OTLPExporter, sampleRate float64) opentracing.Tracer {
+	opts := basictracer.DefaultOptions()
+	opts.Recorder = e
+	opts.ShouldSample = func(traceID uint64) bool {
+		if sampleRate >= 1 {
+			return true
+		}
+		return float64(traceID) < sampleRate*math.MaxUint64
+	}
+	opts.TrimUnsampledSpans = true
+	return basictracer.NewWithOptions(opts)
+}
+
+// RecordSpan implements basictracer.SpanRecorder.
+func (e *OTLPExporter) RecordSpan(sp basictracer.RawSpan) {
+	if !sp.Context.Sampled {
+		return
+	}
+	select {
+	case e.spanCh <- sp:
+	default:
+	}
+}
+
+// Close exports the remaining spans and stops the OTLPExporter.
+func (e *OTLPExporter) Close() {
+	close(e.exitCh)
+	e.wg.Wait()
+}
+
+func (e *OTLPExporter) run() {
+	defer e.wg.Done()
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	batch := make([]basictracer.RawSpan, 0, otlpExporterBatchSize)
+	for {
+		select {
+		case sp := <-e.spanCh:
+			batch = append(batch, sp)
+			if len(batch) < otlpExporterBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case <-e.exitCh:
+			for len(e.spanCh) > 0 {
+				batch = append(batch, <-e.spanCh)
+			}
+			e.export(batch)
+			return
+		}
+		e.export(batch)
+		batch = batch[:0]
+	}
+}
+
+func (e *OTLPExporter) export(spans []basictracer.RawSpan) {
+	if len(spans) == 0 {
+		return
+	}
+	data, err := EncodeOTLP(e.serviceName, spans)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), otlpExporterTimeout)
+		err = ExportOTLP(ctx, e.endpoint, data)
+		cancel()
+	}
+	if err != nil {
+		logutil.BgLogger().Warn("export the spans to the OTLP endpoint failed", zap.String("endpoint", e.endpoint), zap.Int("spans", len(spans)), zap.Error(err))
+	}
+}
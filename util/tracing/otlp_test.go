@@ -0,0 +1,108 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opentracing/basictracer-go"
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeOTLP(t *testing.T) {
+	start := time.Unix(1, 0)
+	spans := []basictracer.RawSpan{
+		{
+			Context:   basictracer.SpanContext{TraceID: 1, SpanID: 2},
+			Operation: "root",
+			Start:     start,
+			Duration:  time.Second,
+			Tags:      opentracing.Tags{"region_id": uint64(3)},
+		},
+		{
+			Context:      basictracer.SpanContext{TraceID: 1, SpanID: 4},
+			ParentSpanID: 2,
+			Operation:    "child",
+			Start:        start,
+			Duration:     time.Millisecond,
+			Logs: []opentracing.LogRecord{{
+				Timestamp: start,
+				Fields:    nil,
+			}},
+		},
+	}
+	data, err := EncodeOTLP("TiDB", spans)
+	require.NoError(t, err)
+
+	traces := otlpTraces{}
+	require.NoError(t, json.Unmarshal(data, &traces))
+	require.Len(t, traces.ResourceSpans, 1)
+	require.Equal(t, "service.name", traces.ResourceSpans[0].Resource.Attributes[0].Key)
+	require.Equal(t, "TiDB", *traces.ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+	otlpSpans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, otlpSpans, 2)
+
+	require.Equal(t, "00000000000000000000000000000001", otlpSpans[0].TraceID)
+	require.Equal(t, "0000000000000002", otlpSpans[0].SpanID)
+	require.Equal(t, "", otlpSpans[0].ParentSpanID)
+	require.Equal(t, "root", otlpSpans[0].Name)
+	require.Equal(t, "1000000000", otlpSpans[0].StartTimeUnixNano)
+	require.Equal(t, "2000000000", otlpSpans[0].EndTimeUnixNano)
+	require.Equal(t, "region_id", otlpSpans[0].Attributes[0].Key)
+	require.Equal(t, "3", *otlpSpans[0].Attributes[0].Value.IntValue)
+
+	require.Equal(t, "0000000000000002", otlpSpans[1].ParentSpanID)
+	require.Equal(t, "1001000000", otlpSpans[1].EndTimeUnixNano)
+	require.Len(t, otlpSpans[1].Events, 1)
+	require.Equal(t, "log", otlpSpans[1].Events[0].Name)
+}
+
+func TestOTLPExporter(t *testing.T) {
+	ch := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		ch <- data
+	}))
+	defer server.Close()
+
+	exporter := NewOTLPExporter(server.URL, "TiDB", time.Hour)
+	tracer := NewOTLPTracer(exporter, 1)
+	span := tracer.StartSpan("root")
+	tracer.StartSpan("child", opentracing.ChildOf(span.Context())).Finish()
+	span.Finish()
+	// The remaining spans are exported when it's closed.
+	exporter.Close()
+
+	traces := otlpTraces{}
+	require.NoError(t, json.Unmarshal(<-ch, &traces))
+	otlpSpans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, otlpSpans, 2)
+	require.Equal(t, "child", otlpSpans[0].Name)
+	require.Equal(t, "root", otlpSpans[1].Name)
+	require.Equal(t, otlpSpans[1].SpanID, otlpSpans[0].ParentSpanID)
+
+	// The unsampled spans are not exported.
+	exporter = NewOTLPExporter(server.URL, "TiDB", time.Hour)
+	NewOTLPTracer(exporter, 0).StartSpan("root").Finish()
+	exporter.Close()
+	require.Len(t, ch, 0)
+}
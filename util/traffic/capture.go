@@ -0,0 +1,153 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+const (
+	// recordChanSize is the max count of the records waiting to be written, the following records are dropped
+	// when it's exceeded so that capturing never blocks the connections.
+	recordChanSize = 4096
+	// flushInterval is the interval of flushing the buffered records to the sink.
+	flushInterval = time.Second
+)
+
+type flusher interface {
+	Flush() error
+}
+
+// CaptureStatus reports the progress of capturing.
+type CaptureStatus struct {
+	Capturing bool      `json:"capturing"`
+	Dir       string    `json:"dir"`
+	Start     time.Time `json:"start"`
+	// Deadline is zero if the capture runs until it's stopped.
+	Deadline time.Time `json:"deadline"`
+	Captured uint64    `json:"captured"`
+	// Dropped is the count of the records dropped because the sink is too slow.
+	Dropped uint64 `json:"dropped"`
+	// Err is the error which stops the capture.
+	Err string `json:"err,omitempty"`
+}
+
+// Capturer writes the captured records to the sink in the background.
+type Capturer struct {
+	sink     Sink
+	recordCh chan *Record
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+
+	captured atomic.Uint64
+	dropped  atomic.Uint64
+
+	mu     sync.Mutex
+	status CaptureStatus
+}
+
+// NewCapturer creates a Capturer and starts writing to the sink. dir is only used for reporting.
+func NewCapturer(sink Sink, dir string, deadline time.Time) *Capturer {
+	c := &Capturer{
+		sink:     sink,
+		recordCh: make(chan *Record, recordChanSize),
+		status:   CaptureStatus{Capturing: true, Dir: dir, Start: time.Now(), Deadline: deadline},
+	}
+	c.wg.Add(1)
+	go c.run()
+	return c
+}
+
+func (c *Capturer) run() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	f, canFlush := c.sink.(flusher)
+	for {
+		select {
+		case r, ok := <-c.recordCh:
+			if !ok {
+				c.finish(c.sink.Close())
+				return
+			}
+			if err := c.sink.Write(r); err != nil {
+				c.fail(err)
+				return
+			}
+		case <-ticker.C:
+			if canFlush {
+				if err := f.Flush(); err != nil {
+					c.fail(err)
+					return
+				}
+			}
+		}
+	}
+}
+
+// fail stops capturing after an error of the sink, the following records are dropped.
+func (c *Capturer) fail(err error) {
+	logutil.BgLogger().Warn("capture traffic failed", zap.Error(err))
+	c.finish(err)
+	if err = c.sink.Close(); err != nil {
+		logutil.BgLogger().Warn("close the traffic sink failed", zap.Error(err))
+	}
+	for range c.recordCh {
+		c.captured.Dec()
+		c.dropped.Inc()
+	}
+}
+
+func (c *Capturer) finish(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status.Capturing = false
+	if err != nil && c.status.Err == "" {
+		c.status.Err = err.Error()
+	}
+}
+
+// Capture captures the record without blocking, the record is dropped if the sink is too slow.
+func (c *Capturer) Capture(r *Record) {
+	select {
+	case c.recordCh <- r:
+		c.captured.Inc()
+	default:
+		c.dropped.Inc()
+	}
+}
+
+// Stop stops capturing and waits for the captured records to be written. It must be called after the last Capture.
+func (c *Capturer) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.recordCh)
+		c.wg.Wait()
+	})
+}
+
+// Status returns the status of the capture.
+func (c *Capturer) Status() CaptureStatus {
+	c.mu.Lock()
+	status := c.status
+	c.mu.Unlock()
+	status.Captured = c.captured.Load()
+	status.Dropped = c.dropped.Load()
+	return status
+}
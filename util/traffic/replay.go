@@ -0,0 +1,186 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// replayChanSize is the max count of the records waiting to be replayed by a connection.
+const replayChanSize = 1024
+
+// ReplayStats summarizes the result of a replay.
+type ReplayStats struct {
+	Replayed uint64 `json:"replayed"`
+	// Failed is the count of the records which fail in the replay.
+	Failed uint64 `json:"failed"`
+	// Mismatched is the count of the records which fail in the replay but succeed in the capture, or vice versa.
+	Mismatched uint64 `json:"mismatched"`
+	// CapturedDuration and ReplayedDuration are the total execution time of the records in the capture and the replay.
+	CapturedDuration time.Duration `json:"captured_duration"`
+	ReplayedDuration time.Duration `json:"replayed_duration"`
+}
+
+// Replayer replays the captured records against a database, for example, a test cluster of a new version.
+type Replayer struct {
+	db *sql.DB
+	// speed is the multiple of the captured speed, the records are replayed as fast as possible if it's not positive.
+	speed float64
+	// onResult is called with the record and the error of its replay, it may be called concurrently.
+	onResult func(r *Record, err error)
+
+	mu    sync.Mutex
+	stats ReplayStats
+}
+
+// NewReplayer creates a Replayer. onResult is optional.
+func NewReplayer(db *sql.DB, speed float64, onResult func(r *Record, err error)) *Replayer {
+	return &Replayer{db: db, speed: speed, onResult: onResult}
+}
+
+// Replay replays the records of the reader. The records of each captured connection are replayed in order in a
+// dedicated connection, and the intervals between the records are kept according to the speed.
+func (rp *Replayer) Replay(ctx context.Context, reader *Reader) (ReplayStats, error) {
+	var wg sync.WaitGroup
+	conns := make(map[uint64]chan *Record)
+	err := rp.dispatch(ctx, reader, conns, &wg)
+	// The stats are complete only after all the connections finish.
+	for _, ch := range conns {
+		close(ch)
+	}
+	wg.Wait()
+	return rp.Stats(), err
+}
+
+// dispatch sends the records to the connections at the captured intervals.
+func (rp *Replayer) dispatch(ctx context.Context, reader *Reader, conns map[uint64]chan *Record, wg *sync.WaitGroup) error {
+	var firstStart, replayStart time.Time
+	for {
+		r, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if replayStart.IsZero() {
+			firstStart, replayStart = r.StartTime, time.Now()
+		}
+		if rp.speed > 0 {
+			due := replayStart.Add(time.Duration(float64(r.StartTime.Sub(firstStart)) / rp.speed))
+			if wait := time.Until(due); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+		ch, ok := conns[r.ConnID]
+		if !ok {
+			ch = make(chan *Record, replayChanSize)
+			conns[r.ConnID] = ch
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rp.replayConn(ctx, ch)
+			}()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- r:
+		}
+	}
+}
+
+// replayConn replays the records of a captured connection.
+func (rp *Replayer) replayConn(ctx context.Context, ch <-chan *Record) {
+	conn, connErr := rp.db.Conn(ctx)
+	if connErr == nil {
+		defer func() {
+			// The connection isn't reused by the others because its session state is changed by the records.
+			_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+			_ = conn.Close()
+		}()
+	}
+	var curDB string
+	for r := range ch {
+		if connErr != nil {
+			rp.finish(r, 0, connErr)
+			continue
+		}
+		start := time.Now()
+		var err error
+		if r.DB != "" && r.DB != curDB && r.Cmd != CmdInitDB {
+			if _, err = conn.ExecContext(ctx, "USE "+quoteName(r.DB)); err == nil {
+				curDB = r.DB
+			}
+		}
+		if err == nil {
+			switch r.Cmd {
+			case CmdInitDB:
+				if _, err = conn.ExecContext(ctx, "USE "+quoteName(r.SQL)); err == nil {
+					curDB = r.SQL
+				}
+			case CmdExecute:
+				args := make([]interface{}, len(r.Params))
+				for i, param := range r.Params {
+					if param != nil {
+						args[i] = param
+					}
+				}
+				_, err = conn.ExecContext(ctx, r.SQL, args...)
+			default:
+				_, err = conn.ExecContext(ctx, r.SQL)
+			}
+		}
+		rp.finish(r, time.Since(start), err)
+	}
+}
+
+func (rp *Replayer) finish(r *Record, d time.Duration, err error) {
+	rp.mu.Lock()
+	rp.stats.Replayed++
+	if err != nil {
+		rp.stats.Failed++
+	}
+	if (err != nil) != (r.Err != "") {
+		rp.stats.Mismatched++
+	}
+	rp.stats.CapturedDuration += r.Duration
+	rp.stats.ReplayedDuration += d
+	rp.mu.Unlock()
+	if rp.onResult != nil {
+		rp.onResult(r, err)
+	}
+}
+
+// Stats returns the statistics of the replay.
+func (rp *Replayer) Stats() ReplayStats {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	return rp.stats
+}
+
+func quoteName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
@@ -0,0 +1,237 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/terror"
+)
+
+// The commands of the captured records.
+const (
+	// CmdQuery is a statement of the text protocol.
+	CmdQuery = "Query"
+	// CmdExecute is an execution of a prepared statement of the binary protocol, the parameters are captured.
+	CmdExecute = "Execute"
+	// CmdInitDB changes the current database of the connection.
+	CmdInitDB = "InitDB"
+)
+
+const (
+	captureFileName = "traffic.log"
+	// rotatedFileTimeFormat keeps the rotated files in the order of time when they are sorted by names.
+	rotatedFileTimeFormat = "2006-01-02T15-04-05.000"
+)
+
+// Record is a captured command of a client connection.
+type Record struct {
+	ConnID uint64 `json:"conn_id"`
+	// StartTime is when the server starts to handle the command, the replayer keeps the intervals between them.
+	StartTime time.Time     `json:"start_time"`
+	Duration  time.Duration `json:"duration"`
+	User      string        `json:"user"`
+	// DB is the current database when the command is received.
+	DB  string `json:"db"`
+	Cmd string `json:"cmd"`
+	SQL string `json:"sql"`
+	// Params are the parameters of CmdExecute, a NULL is nil.
+	Params [][]byte `json:"params,omitempty"`
+	// Err is the error returned to the client, it's compared with the result of the replay.
+	Err string `json:"err,omitempty"`
+}
+
+// Sink stores the captured records.
+type Sink interface {
+	Write(r *Record) error
+	Close() error
+}
+
+// FileSink writes the records as JSON lines into the files in a directory. The file is rotated when it exceeds
+// maxSize bytes, and only the latest maxFiles rotated files are kept.
+type FileSink struct {
+	dir      string
+	maxSize  int64
+	maxFiles int
+
+	file *os.File
+	w    *bufio.Writer
+	size int64
+}
+
+// NewFileSink creates a FileSink in dir, the directory is created if it doesn't exist. maxSize and maxFiles are
+// unlimited if they are not positive.
+func NewFileSink(dir string, maxSize int64, maxFiles int) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Trace(err)
+	}
+	s := &FileSink{dir: dir, maxSize: maxSize, maxFiles: maxFiles}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	// The captured SQL may contain sensitive data, so the files are only readable by the owner.
+	file, err := os.OpenFile(filepath.Join(s.dir, captureFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		terror.Log(file.Close())
+		return errors.Trace(err)
+	}
+	s.file, s.w, s.size = file, bufio.NewWriter(file), stat.Size()
+	return nil
+}
+
+// Write implements the Sink interface.
+func (s *FileSink) Write(r *Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data = append(data, '\n')
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(data)) > s.maxSize {
+		if err = s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.w.Write(data)
+	s.size += int64(n)
+	return errors.Trace(err)
+}
+
+// rotate renames the current file with the time suffix and removes the oldest rotated files.
+func (s *FileSink) rotate() error {
+	if err := s.closeFile(); err != nil {
+		return err
+	}
+	rotated := "traffic-" + time.Now().Format(rotatedFileTimeFormat) + ".log"
+	if err := os.Rename(filepath.Join(s.dir, captureFileName), filepath.Join(s.dir, rotated)); err != nil {
+		return errors.Trace(err)
+	}
+	if s.maxFiles > 0 {
+		files, err := rotatedFiles(s.dir)
+		if err != nil {
+			return err
+		}
+		for len(files) > s.maxFiles {
+			if err = os.Remove(files[0]); err != nil {
+				return errors.Trace(err)
+			}
+			files = files[1:]
+		}
+	}
+	return s.open()
+}
+
+func (s *FileSink) closeFile() error {
+	if err := s.w.Flush(); err != nil {
+		terror.Log(s.file.Close())
+		return errors.Trace(err)
+	}
+	return errors.Trace(s.file.Close())
+}
+
+// Flush flushes the buffered records to the file.
+func (s *FileSink) Flush() error {
+	return errors.Trace(s.w.Flush())
+}
+
+// Close implements the Sink interface.
+func (s *FileSink) Close() error {
+	return s.closeFile()
+}
+
+// rotatedFiles returns the rotated files in the directory from the oldest to the latest.
+func rotatedFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "traffic-*.log"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Reader reads the records captured by FileSink in a directory, from the oldest rotated file to the current file.
+type Reader struct {
+	files []string
+	file  *os.File
+	dec   *json.Decoder
+}
+
+// NewReader creates a Reader of the capture directory.
+func NewReader(dir string) (*Reader, error) {
+	files, err := rotatedFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(filepath.Join(dir, captureFileName)); err == nil {
+		files = append(files, filepath.Join(dir, captureFileName))
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Trace(err)
+	}
+	if len(files) == 0 {
+		return nil, errors.Errorf("no captured traffic in %s", dir)
+	}
+	return &Reader{files: files}, nil
+}
+
+// Next returns the next record, it returns io.EOF after the last record.
+func (r *Reader) Next() (*Record, error) {
+	for {
+		if r.dec == nil {
+			if len(r.files) == 0 {
+				return nil, io.EOF
+			}
+			file, err := os.Open(r.files[0])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			r.files = r.files[1:]
+			r.file, r.dec = file, json.NewDecoder(bufio.NewReader(file))
+		}
+		var record Record
+		err := r.dec.Decode(&record)
+		if err == nil {
+			return &record, nil
+		}
+		if err != io.EOF {
+			return nil, errors.Annotatef(err, "read %s", r.file.Name())
+		}
+		if err = r.file.Close(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		r.file, r.dec = nil, nil
+	}
+}
+
+// Close closes the file being read.
+func (r *Reader) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	return errors.Trace(r.file.Close())
+}
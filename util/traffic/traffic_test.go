@@ -0,0 +1,144 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, dir string) []*Record {
+	reader, err := NewReader(dir)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, reader.Close())
+	}()
+	var records []*Record
+	for {
+		r, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+		require.NoError(t, err)
+		records = append(records, r)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewReader(dir)
+	require.Error(t, err)
+
+	sink, err := NewFileSink(dir, 0, 0)
+	require.NoError(t, err)
+	records := []*Record{
+		{ConnID: 1, StartTime: time.Now(), Cmd: CmdQuery, SQL: "select 1"},
+		{ConnID: 1, Cmd: CmdExecute, SQL: "select ?, ?", Params: [][]byte{[]byte("a"), nil}, Err: "error"},
+		{ConnID: 2, Cmd: CmdInitDB, SQL: "test"},
+	}
+	for _, r := range records {
+		require.NoError(t, sink.Write(r))
+	}
+	require.NoError(t, sink.Close())
+	stat, err := os.Stat(filepath.Join(dir, captureFileName))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+
+	read := readAll(t, dir)
+	require.Len(t, read, len(records))
+	for i, r := range read {
+		require.Equal(t, records[i].SQL, r.SQL)
+		require.Equal(t, records[i].Params, r.Params)
+		require.Equal(t, records[i].Err, r.Err)
+		require.True(t, records[i].StartTime.Equal(r.StartTime))
+	}
+}
+
+func TestFileSinkRotate(t *testing.T) {
+	dir := t.TempDir()
+	// Every record exceeds the max size, so each one is in its own file.
+	sink, err := NewFileSink(dir, 1, 2)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, sink.Write(&Record{ConnID: 1, Cmd: CmdQuery, SQL: "select " + strconv.Itoa(i)}))
+		// Keep the names of the rotated files different.
+		time.Sleep(2 * time.Millisecond)
+	}
+	require.NoError(t, sink.Close())
+	files, err := rotatedFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	// The oldest records are removed.
+	read := readAll(t, dir)
+	require.Len(t, read, 3)
+	for i, r := range read {
+		require.Equal(t, "select "+strconv.Itoa(i+2), r.SQL)
+	}
+}
+
+type blockingSink struct {
+	ch chan struct{}
+}
+
+func (s *blockingSink) Write(*Record) error {
+	<-s.ch
+	return nil
+}
+
+func (s *blockingSink) Close() error {
+	return nil
+}
+
+func TestCapturer(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewFileSink(dir, 0, 0)
+	require.NoError(t, err)
+	deadline := time.Now().Add(time.Hour)
+	c := NewCapturer(sink, dir, deadline)
+	for i := 0; i < 10; i++ {
+		c.Capture(&Record{ConnID: 1, Cmd: CmdQuery, SQL: "select 1"})
+	}
+	status := c.Status()
+	require.True(t, status.Capturing)
+	require.Equal(t, dir, status.Dir)
+	require.Equal(t, deadline, status.Deadline)
+	c.Stop()
+	c.Stop()
+	status = c.Status()
+	require.False(t, status.Capturing)
+	require.Equal(t, uint64(10), status.Captured)
+	require.Equal(t, uint64(0), status.Dropped)
+	require.Empty(t, status.Err)
+	require.Len(t, readAll(t, dir), 10)
+
+	// The records are dropped instead of blocking the connections if the sink is too slow.
+	blocking := &blockingSink{ch: make(chan struct{})}
+	c = NewCapturer(blocking, dir, time.Time{})
+	for i := 0; i < recordChanSize+10; i++ {
+		c.Capture(&Record{})
+	}
+	status = c.Status()
+	require.Greater(t, status.Dropped, uint64(0))
+	require.Equal(t, uint64(recordChanSize+10), status.Captured+status.Dropped)
+	close(blocking.ch)
+	c.Stop()
+}